@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
+	"github.com/yandas/backend/internal/backup"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/database"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/scheduler"
+	"github.com/yandas/backend/pkg/envelope"
+)
+
+const usage = `Usage: backup <command> [args]
+
+Commands:
+  run             take a snapshot now
+  serve           take a snapshot whenever BACKUP_INTERVAL has passed since the last one
+  list            list the latest backups
+  restore [flags] DUMP
+                  replace the database with DUMP, a path or the file name of a backup
+                  in BACKUP_DIR or remote storage
+
+Restore flags:
+  -confirm NAME        the target database's name, required
+  -force-production    allow restoring when GIN_MODE is release
+  -allow-unrecorded    restore a dump with no backup record to check its checksum against
+  -skip-safety-backup  do not back up the current database first`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+	command, args := os.Args[1], os.Args[2:]
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+	cfg := config.Load()
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	masterKey, err := envelope.NewMasterKey(cfg.MessageKeyProvider, cfg.MessageKeyID, cfg.MessageMasterKey)
+	if err != nil {
+		log.Fatalf("Failed to load message master key: %v", err)
+	}
+	repos := repository.NewRepositories(db, repository.NewMessageKeys(masterKey))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	remote, err := backup.NewRemote(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up backup storage: %v", err)
+	}
+	runner, err := backup.NewRunner(cfg, repos, remote)
+	if err != nil {
+		log.Fatalf("Failed to set up backups: %v", err)
+	}
+
+	switch command {
+	case "run":
+		record, err := runner.Run(ctx)
+		if err != nil {
+			log.Fatalf("Backup failed: %v", err)
+		}
+		fmt.Printf("Backed up %s to %s (%d bytes, sha256 %s)\n", runner.Database(), record.Location, record.SizeBytes, record.Checksum)
+	case "serve":
+		runner.RunIfDue()
+		jobs := scheduler.New()
+		jobs.Every("database_backup", backup.CheckInterval, runner.RunIfDue)
+		jobs.Start()
+		<-ctx.Done()
+		log.Println("Stopping, waiting for a running backup to finish...")
+		jobs.Stop()
+	case "list":
+		backups, err := repos.Backup.List(20)
+		if err != nil {
+			log.Fatalf("Failed to list backups: %v", err)
+		}
+		for _, b := range backups {
+			fmt.Printf("%s  %-9s %-32s %12d  %s\n", b.StartedAt.Format("2006-01-02 15:04:05"), b.Status, b.FileName, b.SizeBytes, b.Location)
+		}
+	case "restore":
+		restore(ctx, runner, args)
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+}
+
+func restore(ctx context.Context, runner *backup.Runner, args []string) {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	opts := backup.RestoreOptions{}
+	flags.StringVar(&opts.Confirm, "confirm", "", "the target database's name")
+	flags.BoolVar(&opts.AllowProduction, "force-production", false, "allow restoring when GIN_MODE is release")
+	flags.BoolVar(&opts.AllowUnrecorded, "allow-unrecorded", false, "restore a dump with no backup record")
+	flags.BoolVar(&opts.SkipSafetyBackup, "skip-safety-backup", false, "do not back up the current database first")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		log.Fatal("restore needs one dump, e.g. backup restore -confirm yandas yandas-20261017T033000Z.dump")
+	}
+	opts.Source = flags.Arg(0)
+
+	log.Printf("Restoring %s into %s", opts.Source, runner.Database())
+	if err := runner.Restore(ctx, opts); err != nil {
+		log.Fatalf("Restore failed: %v", err)
+	}
+	fmt.Printf("Restored %s into %s\n", opts.Source, runner.Database())
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/joho/godotenv"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/database"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/envelope"
+)
+
+// encrypt_messages seals chat messages stored in plaintext, from before
+// encryption at rest was turned on, with the master key from the environment.
+// It can be stopped and rerun at any time: every run picks up the messages
+// still left in plaintext.
+func main() {
+	batch := flag.Int("batch", 500, "messages encrypted per round")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+	cfg := config.Load()
+
+	masterKey, err := envelope.NewMasterKey(cfg.MessageKeyProvider, cfg.MessageKeyID, cfg.MessageMasterKey)
+	if err != nil {
+		log.Fatalf("Failed to load message master key: %v", err)
+	}
+	if masterKey == nil {
+		log.Fatal("MESSAGE_KEY_PROVIDER is none; configure a master key first")
+	}
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	repos := repository.NewRepositories(db, repository.NewMessageKeys(masterKey))
+
+	total := 0
+	for {
+		n, err := repos.Message.EncryptPlaintext(*batch)
+		if err != nil {
+			log.Fatalf("Stopped after %d messages: %v", total, err)
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+		log.Printf("Encrypted %d messages", total)
+	}
+	log.Printf("Done: %d messages encrypted", total)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/joho/godotenv"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/database"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// documentColumns are the encrypted document URLs of a yandaş profile
+var documentColumns = []string{
+	"kimlik_on_url", "kimlik_arka_url", "ehliyet_on_url", "ehliyet_arka_url", "adli_sicil_pdf_url", "selfie_url",
+}
+
+// encrypt_pii writes every encrypted column again with the first key of
+// PII_KEYS and recomputes the phone blind indexes. Run it after turning
+// encryption on, after putting a new key in front of PII_KEYS (the old key
+// can be dropped once it finishes) and after changing PII_INDEX_KEY. Rows are
+// only read and written back, so it is safe to rerun.
+func main() {
+	batch := flag.Int("batch", 500, "rows rewritten per round")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+	cfg := config.Load()
+
+	// Connect loads the keys
+	db, err := database.Connect(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	n, err := rewriteUsers(db, *batch)
+	if err != nil {
+		log.Fatalf("Stopped after %d users: %v", n, err)
+	}
+	log.Printf("Rewrote %d users", n)
+
+	n, err = rewriteProfiles(db, *batch)
+	if err != nil {
+		log.Fatalf("Stopped after %d yandaş profiles: %v", n, err)
+	}
+	log.Printf("Rewrote %d yandaş profiles", n)
+
+	n, err = rewriteSMSDeliveries(db, *batch)
+	if err != nil {
+		log.Fatalf("Stopped after %d SMS deliveries: %v", n, err)
+	}
+	log.Printf("Rewrote %d SMS deliveries", n)
+}
+
+// The rewrites go through the models, so the encrypted serializer seals the
+// values and the BeforeSave hooks recompute the blind indexes
+
+func rewriteUsers(db *gorm.DB, batch int) (int, error) {
+	var users []models.User
+	total := 0
+	err := db.Unscoped().Select("id", "phone").Where("phone IS NOT NULL").
+		FindInBatches(&users, batch, func(_ *gorm.DB, _ int) error {
+			for i := range users {
+				err := db.Unscoped().Model(&users[i]).Select("phone", "phone_hash").Omit("updated_at").Updates(&users[i]).Error
+				if err != nil {
+					return err
+				}
+			}
+			total += len(users)
+			return nil
+		}).Error
+	return total, err
+}
+
+func rewriteProfiles(db *gorm.DB, batch int) (int, error) {
+	var profiles []models.YandasProfile
+	total := 0
+	err := db.Select(append([]string{"id"}, documentColumns...)).
+		FindInBatches(&profiles, batch, func(_ *gorm.DB, _ int) error {
+			for i := range profiles {
+				if err := db.Model(&profiles[i]).Select(documentColumns).Updates(&profiles[i]).Error; err != nil {
+					return err
+				}
+			}
+			total += len(profiles)
+			return nil
+		}).Error
+	return total, err
+}
+
+func rewriteSMSDeliveries(db *gorm.DB, batch int) (int, error) {
+	var deliveries []models.SMSDelivery
+	total := 0
+	err := db.Select("id", "phone").
+		FindInBatches(&deliveries, batch, func(_ *gorm.DB, _ int) error {
+			for i := range deliveries {
+				err := db.Model(&deliveries[i]).Select("phone", "phone_hash").Omit("updated_at").Updates(&deliveries[i]).Error
+				if err != nil {
+					return err
+				}
+			}
+			total += len(deliveries)
+			return nil
+		}).Error
+	return total, err
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Client sends requests to the API and records their latency
+type Client struct {
+	base  string
+	http  *http.Client
+	stats *Stats
+
+	wsConnected atomic.Int64
+	wsFailed    atomic.Int64
+	wsDropped   atomic.Int64
+	wsEvents    atomic.Int64
+}
+
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+}
+
+// do sends a JSON request and decodes the response data into out; endpoint
+// names the route in the report so IDs in the path don't split it
+func (c *Client) do(ctx context.Context, endpoint, method, path, token string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+"/api/v1"+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Platform", "loadgen")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	start := time.Now()
+	res, err := c.http.Do(req)
+	if err != nil {
+		// Requests cut short by the end of the run aren't failures of the API
+		if ctx.Err() == nil {
+			c.stats.Record(endpoint, time.Since(start), 0)
+		}
+		return err
+	}
+	defer res.Body.Close()
+	raw, err := io.ReadAll(res.Body)
+	c.stats.Record(endpoint, time.Since(start), res.StatusCode)
+	if err != nil {
+		return err
+	}
+
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return fmt.Errorf("%s: unexpected %d response", endpoint, res.StatusCode)
+	}
+	if res.StatusCode >= 400 {
+		return fmt.Errorf("%s: %d %s", endpoint, res.StatusCode, env.Error)
+	}
+	if out != nil {
+		return json.Unmarshal(env.Data, out)
+	}
+	return nil
+}
+
+// listen holds a WebSocket connection open until ctx ends, counting the
+// events pushed by the hub
+func (c *Client) listen(ctx context.Context, token string) {
+	url := strings.Replace(c.base, "http", "ws", 1) + "/api/v1/ws?token=" + token
+
+	start := time.Now()
+	conn, res, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	status := 0
+	if res != nil {
+		status = res.StatusCode
+	}
+	if err != nil {
+		if status < 400 {
+			// The handshake response was not an upgrade
+			status = 0
+		}
+		if ctx.Err() == nil {
+			c.stats.Record("WS /ws", time.Since(start), status)
+			c.wsFailed.Add(1)
+		}
+		return
+	}
+	c.stats.Record("WS /ws", time.Since(start), status)
+	c.wsConnected.Add(1)
+
+	go func() {
+		<-ctx.Done()
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		conn.Close()
+	}()
+
+	// Reading also answers the hub's pings
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			if ctx.Err() == nil {
+				c.wsDropped.Add(1)
+			}
+			return
+		}
+		c.wsEvents.Add(1)
+	}
+}
@@ -0,0 +1,109 @@
+// Command loadgen replays a realistic traffic mix against a running API to
+// size the WebSocket hub and the database pool. Every virtual user registers
+// its own account, so point it at a staging environment, never production.
+//
+//	go run ./cmd/loadgen -target https://staging.yandas.app -users 200 -duration 5m
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type options struct {
+	target     string
+	users      int
+	rampUp     time.Duration
+	duration   time.Duration
+	think      time.Duration
+	orderRatio float64
+	chatRatio  float64
+	ws         bool
+	password   string
+	queries    []string
+}
+
+func main() {
+	var opts options
+	var queries string
+	flag.StringVar(&opts.target, "target", "http://localhost:8080", "base URL of the API")
+	flag.IntVar(&opts.users, "users", 50, "number of concurrent virtual users")
+	flag.DurationVar(&opts.rampUp, "ramp-up", 30*time.Second, "time over which virtual users are started")
+	flag.DurationVar(&opts.duration, "duration", 2*time.Minute, "how long to generate load after ramp-up starts")
+	flag.DurationVar(&opts.think, "think", time.Second, "mean pause between a user's actions")
+	flag.Float64Var(&opts.orderRatio, "order-ratio", 0.1, "share of iterations that create an order")
+	flag.Float64Var(&opts.chatRatio, "chat-ratio", 0.3, "share of iterations that send a chat message")
+	flag.BoolVar(&opts.ws, "ws", true, "keep a WebSocket connection open per virtual user")
+	flag.StringVar(&opts.password, "password", "Loadgen1234!", "password of the registered accounts")
+	flag.StringVar(&queries, "queries", "vekil,kurye,sürücü,temizlik,ankara,istanbul", "comma separated search terms")
+	flag.Parse()
+
+	opts.target = strings.TrimRight(opts.target, "/")
+	opts.queries = strings.Split(queries, ",")
+	if opts.users < 1 {
+		log.Fatal("-users must be at least 1")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	stats := NewStats()
+	client := &Client{
+		base:  opts.target,
+		http:  &http.Client{Timeout: 30 * time.Second, Transport: &http.Transport{MaxIdleConnsPerHost: opts.users}},
+		stats: stats,
+	}
+
+	targets, err := discoverTargets(ctx, client)
+	if err != nil {
+		log.Fatalf("Failed to load yandaş listing: %v", err)
+	}
+	if len(targets) == 0 {
+		log.Println("No yandaş with active services found; chat and order steps are skipped")
+	}
+
+	run := time.Now().Format("20060102150405")
+	log.Printf("Starting %d virtual users against %s for %s", opts.users, opts.target, opts.duration)
+
+	started := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < opts.users; i++ {
+		delay := time.Duration(int64(opts.rampUp) * int64(i) / int64(opts.users))
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			user := &virtualUser{
+				opts:    &opts,
+				client:  client,
+				targets: targets,
+				rnd:     rand.New(rand.NewSource(time.Now().UnixNano() + int64(n))),
+				email:   fmt.Sprintf("loadgen+%s-%d@example.com", run, n),
+			}
+			user.run(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	elapsed := time.Since(started)
+	fmt.Printf("\n%d virtual users, %s\n\n", opts.users, elapsed.Round(time.Second))
+	stats.Report(os.Stdout, elapsed)
+	fmt.Printf("\nWebSocket: %d connected, %d failed, %d dropped early, %d events received\n",
+		client.wsConnected.Load(), client.wsFailed.Load(), client.wsDropped.Load(), client.wsEvents.Load())
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// target is a yandaş the virtual users chat with and book
+type target struct {
+	YandasID  string
+	UserID    string
+	ServiceID string
+}
+
+// discoverTargets picks bookable yandaş from the public listing
+func discoverTargets(ctx context.Context, c *Client) ([]target, error) {
+	var listing []struct {
+		ID     string `json:"id"`
+		UserID string `json:"user_id"`
+	}
+	if err := c.do(ctx, "GET /yandas", http.MethodGet, "/yandas?limit=50", "", nil, &listing); err != nil {
+		return nil, err
+	}
+
+	var targets []target
+	for _, y := range listing {
+		var services []struct {
+			ID       string `json:"id"`
+			IsActive bool   `json:"is_active"`
+		}
+		if err := c.do(ctx, "GET /yandas/:id/services", http.MethodGet, "/yandas/"+y.ID+"/services", "", nil, &services); err != nil {
+			continue
+		}
+		for _, s := range services {
+			if s.IsActive {
+				targets = append(targets, target{YandasID: y.ID, UserID: y.UserID, ServiceID: s.ID})
+				break
+			}
+		}
+	}
+	return targets, nil
+}
+
+type virtualUser struct {
+	opts    *options
+	client  *Client
+	targets []target
+	rnd     *rand.Rand
+	email   string
+
+	token         string
+	conversations map[string]string // yandaş user ID -> conversation ID
+}
+
+func (u *virtualUser) run(ctx context.Context) {
+	if err := u.register(ctx); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("%s: %v", u.email, err)
+		}
+		return
+	}
+	if u.opts.ws {
+		go u.client.listen(ctx, u.token)
+	}
+
+	u.conversations = make(map[string]string)
+	for ctx.Err() == nil {
+		u.iterate(ctx)
+		u.pause(ctx)
+	}
+}
+
+func (u *virtualUser) register(ctx context.Context) error {
+	var data struct {
+		Tokens struct {
+			AccessToken string `json:"access_token"`
+		} `json:"tokens"`
+	}
+	err := u.client.do(ctx, "POST /auth/register", http.MethodPost, "/auth/register", "", map[string]string{
+		"email":     u.email,
+		"password":  u.opts.password,
+		"full_name": "Loadgen Kullanıcı",
+	}, &data)
+	if err != nil {
+		return err
+	}
+	u.token = data.Tokens.AccessToken
+	return nil
+}
+
+// iterate performs one visit: browse, then sometimes chat or book
+func (u *virtualUser) iterate(ctx context.Context) {
+	query := u.opts.queries[u.rnd.Intn(len(u.opts.queries))]
+	u.client.do(ctx, "GET /search", http.MethodGet, "/search?q="+url.QueryEscape(query), "", nil, nil)
+	u.client.do(ctx, "GET /categories", http.MethodGet, "/categories", "", nil, nil)
+
+	if len(u.targets) == 0 {
+		return
+	}
+	t := u.targets[u.rnd.Intn(len(u.targets))]
+	u.client.do(ctx, "GET /yandas/:id", http.MethodGet, "/yandas/"+t.YandasID, "", nil, nil)
+
+	if u.rnd.Float64() < u.opts.chatRatio {
+		u.chat(ctx, t)
+	}
+	if u.rnd.Float64() < u.opts.orderRatio {
+		u.order(ctx, t)
+	}
+}
+
+func (u *virtualUser) chat(ctx context.Context, t target) {
+	convID, ok := u.conversations[t.UserID]
+	if !ok {
+		var conv struct {
+			ID string `json:"id"`
+		}
+		err := u.client.do(ctx, "POST /chat/conversations/start", http.MethodPost, "/chat/conversations/start", u.token,
+			map[string]string{"yandas_user_id": t.UserID}, &conv)
+		if err != nil {
+			return
+		}
+		convID = conv.ID
+		u.conversations[t.UserID] = convID
+	}
+
+	u.client.do(ctx, "POST /chat/conversations/:id/messages", http.MethodPost, "/chat/conversations/"+convID+"/messages", u.token,
+		map[string]string{"content": fmt.Sprintf("Merhaba, yük testi mesajı %d", u.rnd.Intn(1000))}, nil)
+	u.client.do(ctx, "GET /chat/conversations/:id/messages", http.MethodGet, "/chat/conversations/"+convID+"/messages", u.token, nil, nil)
+}
+
+func (u *virtualUser) order(ctx context.Context, t target) {
+	var order struct {
+		ID string `json:"id"`
+	}
+	err := u.client.do(ctx, "POST /orders", http.MethodPost, "/orders", u.token, map[string]interface{}{
+		"yandas_id":        t.YandasID,
+		"service_id":       t.ServiceID,
+		"location_address": "Yük testi adresi",
+		"customer_notes":   "loadgen",
+	}, &order)
+	if err != nil {
+		return
+	}
+	u.client.do(ctx, "GET /orders", http.MethodGet, "/orders", u.token, nil, nil)
+	// Cancel right away so test orders don't pile up on real yandaş
+	u.client.do(ctx, "POST /orders/:id/cancel", http.MethodPost, "/orders/"+order.ID+"/cancel", u.token,
+		map[string]string{"reason": "loadgen"}, nil)
+}
+
+// pause waits for a jittered think time between 0.5x and 1.5x the mean
+func (u *virtualUser) pause(ctx context.Context) {
+	if u.opts.think <= 0 {
+		return
+	}
+	d := u.opts.think/2 + time.Duration(u.rnd.Int63n(int64(u.opts.think)))
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Stats collects per-endpoint latencies across all virtual users
+type Stats struct {
+	mu        sync.Mutex
+	endpoints map[string]*endpointStats
+}
+
+type endpointStats struct {
+	latencies []time.Duration
+	errors    int
+	statuses  map[int]int
+}
+
+// NewStats returns an empty collector
+func NewStats() *Stats {
+	return &Stats{endpoints: make(map[string]*endpointStats)}
+}
+
+// Record stores one request; status 0 means the request never got a response
+func (s *Stats) Record(endpoint string, latency time.Duration, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.endpoints[endpoint]
+	if !ok {
+		e = &endpointStats{statuses: make(map[int]int)}
+		s.endpoints[endpoint] = e
+	}
+	e.latencies = append(e.latencies, latency)
+	e.statuses[status]++
+	if status == 0 || status >= 400 {
+		e.errors++
+	}
+}
+
+// Report writes one line per endpoint with request counts and latency percentiles
+func (s *Stats) Report(w io.Writer, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.endpoints))
+	for name := range s.endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "endpoint\trequests\terrors\trps\tp50\tp95\tp99\tmax\tstatuses")
+	for _, name := range names {
+		e := s.endpoints[name]
+		sorted := append([]time.Duration(nil), e.latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t%s\n",
+			name, len(sorted), e.errors, float64(len(sorted))/elapsed.Seconds(),
+			round(percentile(sorted, 50)), round(percentile(sorted, 95)), round(percentile(sorted, 99)),
+			round(percentile(sorted, 100)), formatStatuses(e.statuses))
+	}
+	tw.Flush()
+}
+
+// percentile uses the nearest-rank method on sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func round(d time.Duration) time.Duration {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
+
+func formatStatuses(statuses map[int]int) string {
+	codes := make([]int, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	out := ""
+	for i, code := range codes {
+		if i > 0 {
+			out += " "
+		}
+		label := fmt.Sprint(code)
+		if code == 0 {
+			label = "net"
+		}
+		out += fmt.Sprintf("%s:%d", label, statuses[code])
+	}
+	return out
+}
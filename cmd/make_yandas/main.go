@@ -28,7 +28,7 @@ func main() {
 
 	user.Role = "yandas"
 	db.Save(&user)
-	fmt.Printf("✅ %s (%s) kullanıcısının rolü 'yandas' olarak güncellendi!\n", user.FullName, user.Email)
+	fmt.Printf("✅ %s (%s) kullanıcısının rolü 'yandas' olarak güncellendi!\n", user.FullName, *user.Email)
 
 	// Yandaş profili kontrol et, hizmet ekle
 	var profile models.YandasProfile
@@ -50,12 +50,12 @@ func main() {
 				CategoryID:      cat.ID,
 				Title:           cat.Name + " Hizmeti",
 				Description:     &desc,
-				BasePrice:       150.0,
+				BasePrice:       15000,
 				DurationMinutes: &dur,
 				IsActive:        true,
 			}
 			db.Create(&service)
-			fmt.Printf("  ✅ Hizmet eklendi: %s (%.0f TL)\n", service.Title, service.BasePrice)
+			fmt.Printf("  ✅ Hizmet eklendi: %s (%s TL)\n", service.Title, service.BasePrice)
 		}
 	} else {
 		fmt.Printf("  ℹ️  Zaten %d hizmet mevcut\n", serviceCount)
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/database"
+	"github.com/yandas/backend/migrations"
+)
+
+const usage = `Usage: migrate <command> [args]
+
+Commands:
+  up [N]         apply all pending migrations, or the next N
+  down [N]       roll back the latest migration, or the latest N
+  status         list migrations and whether they are applied
+  force VERSION  mark migrations up to VERSION as applied without running them
+  create NAME    add an empty up/down pair to ./migrations
+  schema         print the DDL for the current models (no database needed)`
+
+var namePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+	command, args := os.Args[1], os.Args[2:]
+
+	switch command {
+	case "create":
+		if len(args) != 1 || !namePattern.MatchString(args[0]) {
+			log.Fatal("create needs a snake_case name, e.g. migrate create add_order_indexes")
+		}
+		create(args[0])
+		return
+	case "schema":
+		statements, err := database.SchemaSQL(database.Models()...)
+		if err != nil {
+			log.Fatalf("Failed to render schema: %v", err)
+		}
+		for _, stmt := range statements {
+			fmt.Printf("%s;\n", stmt)
+		}
+		return
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+	cfg := config.Load()
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	switch command {
+	case "up":
+		done, err := migrator.Up(steps(args))
+		report("Applied", done)
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+	case "down":
+		done, err := migrator.Down(steps(args))
+		report("Rolled back", done)
+		if err != nil {
+			log.Fatalf("Rollback failed: %v", err)
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatalf("Failed to read status: %v", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%06d  %-40s %s\n", s.Version, s.Name, state)
+		}
+	case "force":
+		if len(args) != 1 {
+			log.Fatal("force needs a version")
+		}
+		version, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid version %q", args[0])
+		}
+		if err := migrator.Force(version); err != nil {
+			log.Fatalf("Force failed: %v", err)
+		}
+		fmt.Printf("Schema marked at version %d\n", version)
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+}
+
+func steps(args []string) int {
+	if len(args) == 0 {
+		return 0
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 {
+		log.Fatalf("Invalid step count %q", args[0])
+	}
+	return n
+}
+
+func report(verb string, done []database.Migration) {
+	if len(done) == 0 {
+		fmt.Println("Nothing to do")
+	}
+	for _, m := range done {
+		fmt.Printf("%s %06d_%s\n", verb, m.Version, m.Name)
+	}
+}
+
+// create writes the next numbered migration pair, reading existing files from disk
+func create(name string) {
+	dir := "migrations"
+	existing, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var next uint64 = 1
+	for _, file := range existing {
+		prefix, _, _ := strings.Cut(filepath.Base(file), "_")
+		if v, err := strconv.ParseUint(prefix, 10, 64); err == nil && v >= next {
+			next = v + 1
+		}
+	}
+
+	for _, direction := range []string{"up", "down"} {
+		file := filepath.Join(dir, fmt.Sprintf("%06d_%s.%s.sql", next, name, direction))
+		if err := os.WriteFile(file, []byte("-- "+name+" ("+direction+")\n"), 0o644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Created", file)
+	}
+}
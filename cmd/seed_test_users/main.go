@@ -6,9 +6,10 @@ import (
 	"os"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/joho/godotenv"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/testutil"
+	"github.com/yandas/backend/pkg/currency"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -41,69 +42,48 @@ func main() {
 
 	log.Println("✅ All user data deleted!")
 
-	// Hash password
-	password := "Test1234!"
-	hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	hashStr := string(hash)
-
-	// Create User 1 - Hakan
-	user1ID := uuid.New()
-	email1 := "hakan@test.com"
-	phone1 := "5551112233"
-	db.Exec(`INSERT INTO users (id, email, phone, password_hash, full_name, role, is_verified, is_active, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		user1ID, email1, phone1, hashStr, "Hakan Aydın", "yandas", true, true, time.Now(), time.Now())
-
-	// Create User 2 - Fevzi
-	user2ID := uuid.New()
-	email2 := "fevzi@test.com"
-	phone2 := "5554445566"
-	db.Exec(`INSERT INTO users (id, email, phone, password_hash, full_name, role, is_verified, is_active, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		user2ID, email2, phone2, hashStr, "Fevzi Acar", "yandas", true, true, time.Now(), time.Now())
-
-	log.Printf("✅ Created user 1: Hakan Aydın (ID: %s)", user1ID)
-	log.Printf("✅ Created user 2: Fevzi Acar (ID: %s)", user2ID)
-
-	// Create YandasProfile for both
-	now := time.Now()
-	profile1ID := uuid.New()
-	bio1 := "Profesyonel yandaş hizmetleri. Güvenilir ve hızlı."
-	cities1 := "{İstanbul,Ankara}"
-	db.Exec(`INSERT INTO yandas_profiles (id, user_id, bio, approval_status, approved_at, rating_avg, total_jobs, is_available, service_cities, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?::text[], ?)`,
-		profile1ID, user1ID, bio1, "approved", now, 4.8, 50, true, cities1, now)
-
-	profile2ID := uuid.New()
-	bio2 := "Deneyimli yandaş. Her işte yanındayım."
-	cities2 := "{İstanbul,İzmir}"
-	db.Exec(`INSERT INTO yandas_profiles (id, user_id, bio, approval_status, approved_at, rating_avg, total_jobs, is_available, service_cities, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?::text[], ?)`,
-		profile2ID, user2ID, bio2, "approved", now, 4.9, 75, true, cities2, now)
+	factory := testutil.NewFactory(db)
+	password := testutil.DefaultPassword
 
+	hakan, err := createYandas(factory, "hakan@test.com", "5551112233", "Hakan Aydın",
+		"Profesyonel yandaş hizmetleri. Güvenilir ve hızlı.", []string{"İstanbul", "Ankara"}, 4.8, 50)
+	if err != nil {
+		log.Fatalf("Failed to create Hakan: %v", err)
+	}
+	fevzi, err := createYandas(factory, "fevzi@test.com", "5554445566", "Fevzi Acar",
+		"Deneyimli yandaş. Her işte yanındayım.", []string{"İstanbul", "İzmir"}, 4.9, 75)
+	if err != nil {
+		log.Fatalf("Failed to create Fevzi: %v", err)
+	}
+	email1, email2 := *hakan.User.Email, *fevzi.User.Email
+
+	log.Printf("✅ Created user 1: Hakan Aydın (ID: %s)", hakan.UserID)
+	log.Printf("✅ Created user 2: Fevzi Acar (ID: %s)", fevzi.UserID)
 	log.Println("✅ YandasProfiles created with 'approved' status!")
 
 	// Get all sub-categories (leaf categories with a parent_id)
-	type Cat struct {
-		ID   uuid.UUID
-		Name string
-	}
-	var subCats []Cat
-	db.Raw("SELECT id, name FROM categories WHERE parent_id IS NOT NULL ORDER BY sort_order").Scan(&subCats)
+	var subCats []models.Category
+	db.Where("parent_id IS NOT NULL").Order("sort_order").Find(&subCats)
 
 	log.Printf("📋 Found %d sub-categories to assign as services", len(subCats))
 
 	// Create YandasService for each sub-category for both profiles
 	for _, cat := range subCats {
-		// For Hakan
-		db.Exec(`INSERT INTO yandas_services (id, yandas_id, category_id, title, description, base_price, currency, duration_minutes, is_active, created_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-			uuid.New(), profile1ID, cat.ID, cat.Name+" Hizmeti", cat.Name+" için profesyonel hizmet", 500.00, "TRY", 60, true, now)
-
-		// For Fevzi
-		db.Exec(`INSERT INTO yandas_services (id, yandas_id, category_id, title, description, base_price, currency, duration_minutes, is_active, created_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-			uuid.New(), profile2ID, cat.ID, cat.Name+" Hizmeti", cat.Name+" için profesyonel hizmet", 450.00, "TRY", 60, true, now)
+		for _, offer := range []struct {
+			profile *models.YandasProfile
+			price   float64
+		}{{hakan, 500}, {fevzi, 450}} {
+			_, err := factory.Service(offer.profile, func(s *models.YandasService) {
+				description := cat.Name + " için profesyonel hizmet"
+				s.CategoryID = cat.ID
+				s.Title = cat.Name + " Hizmeti"
+				s.Description = &description
+				s.BasePrice = currency.FromFloat(offer.price)
+			})
+			if err != nil {
+				log.Fatalf("Failed to create service: %v", err)
+			}
+		}
 	}
 
 	log.Printf("✅ Created %d services for each yandaş (%d total)", len(subCats), len(subCats)*2)
@@ -125,6 +105,34 @@ func main() {
 	fmt.Println("╚══════════════════════════════════════════════════════════╝")
 }
 
+func createYandas(factory *testutil.Factory, email, phone, name, bio string, cities []string, rating float64, jobs int) (*models.YandasProfile, error) {
+	user, err := factory.User(func(u *models.User) {
+		u.Email = &email
+		u.Phone = &phone
+		u.FullName = name
+		u.Role = "yandas"
+	})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	profile := &models.YandasProfile{
+		UserID:         user.ID,
+		Bio:            &bio,
+		ApprovalStatus: "approved",
+		ApprovedAt:     &now,
+		RatingAvg:      rating,
+		TotalJobs:      jobs,
+		IsAvailable:    true,
+		ServiceCities:  cities,
+	}
+	if err := factory.DB.Create(profile).Error; err != nil {
+		return nil, err
+	}
+	profile.User = *user
+	return profile, nil
+}
+
 func getEnv(key, fallback string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/database"
+	"github.com/yandas/backend/internal/queue"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/services"
+	"github.com/yandas/backend/pkg/envelope"
+)
+
+// Worker executes background jobs enqueued by the API (emails, SMS, push
+// notifications, receipts, document screening).
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	cfg := config.Load()
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	redisClient, err := database.ConnectRedis(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+
+	masterKey, err := envelope.NewMasterKey(cfg.MessageKeyProvider, cfg.MessageKeyID, cfg.MessageMasterKey)
+	if err != nil {
+		log.Fatalf("Failed to load message master key: %v", err)
+	}
+	repos := repository.NewRepositories(db, repository.NewMessageKeys(masterKey))
+	svcs := services.NewServices(repos, cfg, redisClient)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	queue.NewWorker(redisClient, svcs.JobHandlers, cfg.WorkerConcurrency).Run(ctx)
+}
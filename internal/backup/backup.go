@@ -0,0 +1,307 @@
+// Package backup takes pg_dump snapshots of the database, keeps them in the
+// backup directory and optionally in S3, and restores them
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/database"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/storage"
+	"gorm.io/gorm"
+)
+
+// Backup statuses
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+const (
+	// CheckInterval is how often the serve loop checks whether a backup is due;
+	// checking rather than waiting out BackupInterval keeps restarts from
+	// postponing backups
+	CheckInterval = 15 * time.Minute
+	// dumpTimeout bounds one pg_dump run
+	dumpTimeout = 2 * time.Hour
+	dumpPrefix  = "yandas-"
+	dumpSuffix  = ".dump"
+)
+
+// Runner takes and restores backups
+type Runner struct {
+	repos      *repository.Repositories
+	remote     storage.Store // nil keeps dumps in the backup directory only
+	dir        string
+	keep       int
+	interval   time.Duration
+	pgDump     string
+	pgRestore  string
+	env        []string
+	database   string
+	production bool
+	now        func() time.Time
+}
+
+// NewRunner creates a runner for the configured database. remote is the
+// store dumps are uploaded to, or nil to keep them local.
+func NewRunner(cfg *config.Config, repos *repository.Repositories, remote storage.Store) (*Runner, error) {
+	env, dbName, err := pgEnv(database.DSN(cfg))
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(cfg.BackupDir, 0o750); err != nil {
+		return nil, err
+	}
+	keep := cfg.BackupKeep
+	if keep < 1 {
+		keep = 1
+	}
+	return &Runner{
+		repos:      repos,
+		remote:     remote,
+		dir:        cfg.BackupDir,
+		keep:       keep,
+		interval:   cfg.BackupInterval,
+		pgDump:     cfg.PGDumpPath,
+		pgRestore:  cfg.PGRestorePath,
+		env:        env,
+		database:   dbName,
+		production: cfg.GinMode == "release",
+		now:        time.Now,
+	}, nil
+}
+
+// NewRemote returns the store configured by BACKUP_STORAGE, or nil when
+// dumps stay in the backup directory
+func NewRemote(ctx context.Context, cfg *config.Config) (storage.Store, error) {
+	if cfg.BackupStorage == "" || cfg.BackupStorage == storage.StoreLocal {
+		return nil, nil
+	}
+	return storage.NewStore(ctx, cfg.BackupStorage, storage.Options{
+		Bucket:    cfg.BackupS3Bucket,
+		Region:    cfg.S3Region,
+		Prefix:    cfg.BackupS3Prefix,
+		AccessKey: cfg.S3AccessKey,
+		SecretKey: cfg.S3SecretKey,
+	})
+}
+
+// Database is the name of the database backed up and restored
+func (r *Runner) Database() string { return r.database }
+
+// Run takes a snapshot and records its outcome. pg_dump reads the whole
+// database in one repeatable-read transaction, so the snapshot is consistent
+// while the API keeps writing.
+func (r *Runner) Run(ctx context.Context) (*models.DatabaseBackup, error) {
+	return r.run(ctx, true)
+}
+
+// run takes a snapshot, pruning old dumps after it when prune is set
+func (r *Runner) run(ctx context.Context, prune bool) (*models.DatabaseBackup, error) {
+	started := r.now().UTC()
+	record := &models.DatabaseBackup{
+		FileName:  dumpPrefix + started.Format("20060102T150405Z") + dumpSuffix,
+		Storage:   storage.StoreLocal,
+		Status:    StatusRunning,
+		StartedAt: started,
+	}
+	if r.remote != nil {
+		record.Storage = r.remote.Name()
+	}
+	if err := r.repos.Backup.Create(record); err != nil {
+		return nil, fmt.Errorf("failed to record backup: %w", err)
+	}
+
+	err := r.snapshot(ctx, record)
+	finished := r.now()
+	record.FinishedAt = &finished
+	record.Status = StatusSucceeded
+	if err != nil {
+		record.Status = StatusFailed
+		message := err.Error()
+		record.Error = &message
+	}
+	if updateErr := r.repos.Backup.Update(record); updateErr != nil {
+		log.Printf("[BACKUP] failed to record outcome of %s: %v", record.FileName, updateErr)
+	}
+	if err != nil {
+		return record, err
+	}
+
+	if prune {
+		r.prune(ctx)
+	}
+	return record, nil
+}
+
+// RunIfDue takes a snapshot when the last successful one is older than the
+// backup interval; the serve loop calls it every CheckInterval
+func (r *Runner) RunIfDue() {
+	latest, err := r.repos.Backup.LatestSucceeded()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("[BACKUP] failed to read last backup: %v", err)
+		return
+	}
+	if err == nil && r.now().Sub(latest.StartedAt) < r.interval {
+		return
+	}
+
+	record, err := r.Run(context.Background())
+	if err != nil {
+		log.Printf("[BACKUP] backup failed: %v", err)
+		return
+	}
+	log.Printf("[BACKUP] %s done, %d bytes, stored in %s", record.FileName, record.SizeBytes, record.Location)
+}
+
+// snapshot dumps to a partial file, checks pg_restore can read it, then
+// moves it into place and uploads it
+func (r *Runner) snapshot(ctx context.Context, record *models.DatabaseBackup) error {
+	ctx, cancel := context.WithTimeout(ctx, dumpTimeout)
+	defer cancel()
+
+	path := filepath.Join(r.dir, record.FileName)
+	partial := path + ".partial"
+	defer os.Remove(partial)
+
+	if err := r.exec(ctx, r.pgDump, "--format=custom", "--no-owner", "--no-privileges", "--file="+partial); err != nil {
+		return fmt.Errorf("pg_dump: %w", err)
+	}
+	if err := r.verify(ctx, partial); err != nil {
+		return err
+	}
+	if err := os.Rename(partial, path); err != nil {
+		return err
+	}
+
+	size, checksum, err := fileChecksum(path)
+	if err != nil {
+		return err
+	}
+	record.SizeBytes, record.Checksum, record.Location = size, checksum, path
+
+	if r.remote == nil {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := r.remote.Put(ctx, record.FileName, f); err != nil {
+		return fmt.Errorf("upload to %s: %w", r.remote.Name(), err)
+	}
+	record.Location = r.remote.URL(record.FileName)
+	return nil
+}
+
+// verify lists the archive's contents, which fails on a truncated or corrupt dump
+func (r *Runner) verify(ctx context.Context, path string) error {
+	if err := r.exec(ctx, r.pgRestore, "--list", path); err != nil {
+		return fmt.Errorf("dump is not readable by pg_restore: %w", err)
+	}
+	return nil
+}
+
+// exec runs a PostgreSQL client tool with the connection in its environment.
+// Output is discarded unless the tool fails, when its stderr becomes the error.
+func (r *Runner) exec(ctx context.Context, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), r.env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// prune keeps the newest dumps in the backup directory and in remote storage
+func (r *Runner) prune(ctx context.Context) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		log.Printf("[BACKUP] failed to list %s: %v", r.dir, err)
+		return
+	}
+	var local []string
+	for _, e := range entries {
+		if !e.IsDir() && isDump(e.Name()) {
+			local = append(local, e.Name())
+		}
+	}
+	for _, name := range expired(local, r.keep) {
+		if err := os.Remove(filepath.Join(r.dir, name)); err != nil {
+			log.Printf("[BACKUP] failed to remove %s: %v", name, err)
+		}
+	}
+
+	if r.remote == nil {
+		return
+	}
+	objects, err := r.remote.List(ctx, dumpPrefix)
+	if err != nil {
+		log.Printf("[BACKUP] failed to list %s backups: %v", r.remote.Name(), err)
+		return
+	}
+	var remote []string
+	for _, obj := range objects {
+		if isDump(obj.Key) {
+			remote = append(remote, obj.Key)
+		}
+	}
+	for _, key := range expired(remote, r.keep) {
+		if err := r.remote.Delete(ctx, key); err != nil {
+			log.Printf("[BACKUP] failed to delete %s from %s: %v", key, r.remote.Name(), err)
+		}
+	}
+}
+
+func isDump(name string) bool {
+	return strings.HasPrefix(name, dumpPrefix) && strings.HasSuffix(name, dumpSuffix)
+}
+
+// expired returns the dumps beyond the newest keep. Dump names embed their
+// UTC start time, so they sort oldest first.
+func expired(names []string, keep int) []string {
+	if len(names) <= keep {
+		return nil
+	}
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	return sorted[:len(sorted)-keep]
+}
+
+// fileChecksum returns a file's size and hex SHA-256
+func fileChecksum(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,95 @@
+package backup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// pgEnvVars maps connection string keys to the libpq environment variables
+// pg_dump and pg_restore read
+var pgEnvVars = map[string]string{
+	"host":            "PGHOST",
+	"port":            "PGPORT",
+	"user":            "PGUSER",
+	"password":        "PGPASSWORD",
+	"dbname":          "PGDATABASE",
+	"sslmode":         "PGSSLMODE",
+	"sslrootcert":     "PGSSLROOTCERT",
+	"sslcert":         "PGSSLCERT",
+	"sslkey":          "PGSSLKEY",
+	"connect_timeout": "PGCONNECT_TIMEOUT",
+}
+
+// pgEnv turns a postgres:// URL or key=value connection string into libpq
+// environment variables, so the password never appears on a command line.
+// It also returns the database name.
+func pgEnv(dsn string) ([]string, string, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		converted, err := pq.ParseURL(dsn)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid database URL: %w", err)
+		}
+		dsn = converted
+	}
+	params, err := parseConnString(dsn)
+	if err != nil {
+		return nil, "", err
+	}
+	if params["dbname"] == "" {
+		return nil, "", fmt.Errorf("connection string names no database")
+	}
+
+	var env []string
+	for key, value := range params {
+		if name, ok := pgEnvVars[key]; ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env, params["dbname"], nil
+}
+
+// parseConnString splits a libpq key=value connection string, where values
+// may be single-quoted with backslash escapes
+func parseConnString(s string) (map[string]string, error) {
+	params := map[string]string{}
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("invalid connection string near %q", s)
+		}
+		key := strings.TrimSpace(s[:eq])
+		s = strings.TrimLeft(s[eq+1:], " ")
+
+		var value strings.Builder
+		if strings.HasPrefix(s, "'") {
+			i, closed := 1, false
+			for ; i < len(s); i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+					value.WriteByte(s[i])
+					continue
+				}
+				if s[i] == '\'' {
+					closed = true
+					break
+				}
+				value.WriteByte(s[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated quote in value of %s", key)
+			}
+			s = s[i+1:]
+		} else {
+			end := strings.IndexAny(s, " \t\n")
+			if end < 0 {
+				end = len(s)
+			}
+			value.WriteString(s[:end])
+			s = s[end:]
+		}
+		params[key] = value.String()
+	}
+	return params, nil
+}
@@ -0,0 +1,147 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"gorm.io/gorm"
+)
+
+// Restore safety check failures
+var (
+	ErrConfirmMismatch  = errors.New("confirmation does not match the target database")
+	ErrProduction       = errors.New("refusing to restore into a production database without -force-production")
+	ErrChecksumMismatch = errors.New("dump checksum does not match the recorded backup")
+	ErrDumpNotFound     = errors.New("dump not found in the backup directory or remote storage")
+	ErrUnrecordedBackup = errors.New("dump has no backup record to check it against; pass -allow-unrecorded to restore it anyway")
+	ErrSafetyBackup     = errors.New("safety backup of the current database failed")
+)
+
+// RestoreOptions are the checks a restore must pass
+type RestoreOptions struct {
+	// Source is a dump path, or the file name of a backup in the backup
+	// directory or remote storage
+	Source string
+	// Confirm must repeat the target database's name
+	Confirm string
+	// AllowProduction is needed when GIN_MODE is release
+	AllowProduction bool
+	// AllowUnrecorded restores a dump with no backup record, so no checksum to check
+	AllowUnrecorded bool
+	// SkipSafetyBackup skips the snapshot of the current database taken first
+	SkipSafetyBackup bool
+}
+
+// Restore replaces the database's contents with a dump after checking the
+// operator named the right database, the dump matches its recorded checksum
+// and pg_restore can read it. Unless skipped, the current database is backed
+// up first; its record is replaced along with the rest of the database, so
+// restoring it later needs AllowUnrecorded. The restore runs in a single
+// transaction, so a failure leaves the database as it was.
+func (r *Runner) Restore(ctx context.Context, opts RestoreOptions) error {
+	if opts.Confirm != r.database {
+		return fmt.Errorf("%w: restoring into %q", ErrConfirmMismatch, r.database)
+	}
+	if r.production && !opts.AllowProduction {
+		return ErrProduction
+	}
+
+	path, cleanup, err := r.fetch(ctx, opts.Source)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	name := filepath.Base(path)
+	record, err := r.repos.Backup.GetByFileName(name)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound) || err == nil && record.Checksum == "":
+		if !opts.AllowUnrecorded {
+			return ErrUnrecordedBackup
+		}
+		log.Printf("[BACKUP] %s has no recorded checksum, restoring unchecked", name)
+	case err != nil:
+		return fmt.Errorf("failed to read backup record: %w", err)
+	default:
+		_, checksum, err := fileChecksum(path)
+		if err != nil {
+			return err
+		}
+		if checksum != record.Checksum {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, name)
+		}
+	}
+
+	if err := r.verify(ctx, path); err != nil {
+		return err
+	}
+
+	if !opts.SkipSafetyBackup {
+		// Not pruned, which could remove the dump about to be restored
+		safety, err := r.run(ctx, false)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrSafetyBackup, err)
+		}
+		log.Printf("[BACKUP] current database saved to %s before restoring", safety.Location)
+	}
+
+	err = r.exec(ctx, r.pgRestore, "--clean", "--if-exists", "--no-owner", "--no-privileges",
+		"--single-transaction", "--exit-on-error", "--dbname="+r.database, path)
+	if err != nil {
+		return fmt.Errorf("pg_restore: %w", err)
+	}
+	return nil
+}
+
+// fetch finds the dump to restore: an existing path, a file in the backup
+// directory, or an object downloaded from remote storage. cleanup removes a
+// downloaded copy.
+func (r *Runner) fetch(ctx context.Context, source string) (string, func(), error) {
+	noop := func() {}
+	if source == "" {
+		return "", noop, ErrDumpNotFound
+	}
+	for _, path := range []string{source, filepath.Join(r.dir, filepath.Base(source))} {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, noop, nil
+		}
+	}
+	if r.remote == nil {
+		return "", noop, ErrDumpNotFound
+	}
+
+	name := filepath.Base(source)
+	obj, err := r.remote.Get(ctx, name)
+	if err != nil {
+		return "", noop, fmt.Errorf("%w: %v", ErrDumpNotFound, err)
+	}
+	defer obj.Close()
+
+	// Keep the file name so the record lookup finds it
+	dir, err := os.MkdirTemp(r.dir, ".restore-")
+	if err != nil {
+		return "", noop, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	if _, err := io.Copy(f, obj); err != nil {
+		f.Close()
+		cleanup()
+		return "", noop, fmt.Errorf("download from %s: %w", r.remote.Name(), err)
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	return path, cleanup, nil
+}
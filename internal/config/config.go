@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -20,14 +21,33 @@ type Config struct {
 	DBPassword  string
 	DBName      string
 
+	// Read replicas; listing and search queries go here, writes stay on the primary
+	DBReplicaURLs []string
+
 	// Redis
 	RedisURL string
 
+	// Background jobs
+	WorkerConcurrency int
+
 	// JWT
 	JWTSecret        string
 	JWTAccessExpiry  time.Duration
 	JWTRefreshExpiry time.Duration
 
+	// Master key for chat messages encrypted at rest (none stores them in
+	// plaintext). Encrypted messages can't be searched: with a key set,
+	// conversation search only matches participant names and reports
+	// content_search: false.
+	MessageKeyProvider string
+	MessageKeyID       string
+	MessageMasterKey   string
+
+	// Keys for personal data in database columns, "id:base64" newest first
+	// (none stores plaintext), and the key of their lookup hashes
+	PIIKeys     string
+	PIIIndexKey string
+
 	// Storage
 	StorageType string
 	StoragePath string
@@ -35,9 +55,60 @@ type Config struct {
 	S3Region    string
 	S3AccessKey string
 	S3SecretKey string
+	// Signs the short-lived links identity documents are served through
+	DocumentLinkSecret string
+
+	// Database backups taken by cmd/backup: dumps are written to BackupDir and,
+	// with BackupStorage s3, uploaded under BackupS3Prefix in BackupS3Bucket.
+	// The newest BackupKeep dumps are kept in each place.
+	BackupDir      string
+	BackupStorage  string
+	BackupS3Bucket string
+	BackupS3Prefix string
+	BackupKeep     int
+	BackupInterval time.Duration
+	PGDumpPath     string
+	PGRestorePath  string
 
 	// FCM
 	FCMServerKey string
+	// VoIP pushes for incoming calls on iOS (none until an APNs client is added)
+	APNSVoIPProvider string
+
+	// OCR pre-screening of application documents
+	OCRProvider   string
+	TesseractPath string
+	TesseractLang string
+
+	// Selfie and ID liveness check of applicants (none disables it)
+	KYCProvider string
+
+	// Routing engine for order ETAs (straight-line estimates when unset)
+	RoutingProvider string
+	RoutingURL      string
+
+	// Exchange rates for totalling reports in the base currency (none disables fetching)
+	ExchangeRateProvider string
+	ExchangeRateURL      string
+
+	// Voice note transcoding in chat
+	MediaTranscoder string
+	FFmpegPath      string
+
+	// Commission taken on completed orders (0.15 = 15%), unless a category or plan overrides it
+	CommissionRate float64
+
+	// Receipts and e-Arşiv invoicing
+	VATRate          float64
+	EInvoiceProvider string
+	CompanyName      string
+	CompanyTaxNumber string
+
+	// Maintenance mode at startup; admins can also switch it at runtime
+	MaintenanceMode bool
+
+	// New yandaş services wait for admin approval before they are listed
+	ServiceApprovalRequired bool
 
 	// Rate Limiting
 	RateLimitRequests int
@@ -52,6 +123,11 @@ type Config struct {
 	WebURL string
 	APIURL string
 
+	// Browser origins allowed to call the API (local frontends are added outside release mode)
+	CORSAllowedOrigins []string
+	// How long browsers keep to HTTPS after an HTTPS response (0 disables HSTS)
+	HSTSMaxAge time.Duration
+
 	// RevenueCat
 	RevenueCatAPIKey string
 
@@ -59,6 +135,14 @@ type Config struct {
 	TwilioAccountSID string
 	TwilioAuthToken  string
 	TwilioVerifySID  string
+	TwilioFromNumber string
+
+	// SMS providers, tried in order when one fails
+	SMSProvider          string
+	SMSFallbackProviders []string
+	NetgsmUserCode       string
+	NetgsmPassword       string
+	NetgsmHeader         string
 
 	// SMTP Email
 	SMTPHost     string
@@ -68,9 +152,27 @@ type Config struct {
 	SMTPFrom     string
 	SMTPFromName string
 
+	// Email provider; SMTPFrom and SMTPFromName are the sender for every provider
+	EmailProvider      string
+	EmailWebhookToken  string
+	SESRegion          string
+	SendGridAPIKey     string
+	SendGridWebhookKey string
+
 	// Agora
 	AgoraAppID          string
 	AgoraAppCertificate string
+	CallRingTimeout     time.Duration
+
+	// Agora Cloud Recording
+	AgoraRecordingEnabled   bool
+	AgoraCustomerID         string
+	AgoraCustomerSecret     string
+	AgoraRecordingVendor    int
+	AgoraRecordingRegion    int
+	AgoraRecordingBucket    string
+	AgoraRecordingAccessKey string
+	AgoraRecordingSecretKey string
 }
 
 // Load reads configuration from environment variables
@@ -88,24 +190,85 @@ func Load() *Config {
 		DBPassword:  getEnv("DB_PASSWORD", "password"),
 		DBName:      getEnv("DB_NAME", "yandas"),
 
+		DBReplicaURLs: getEnvList("DB_REPLICA_URLS"),
+
 		// Redis
 		RedisURL: getEnv("REDIS_URL", "redis://localhost:6379"),
 
+		// Background jobs
+		WorkerConcurrency: getEnvInt("WORKER_CONCURRENCY", 4),
+
 		// JWT
 		JWTSecret:        getEnv("JWT_SECRET", "default-secret-change-in-production"),
 		JWTAccessExpiry:  parseDuration(getEnv("JWT_ACCESS_EXPIRY", "24h")),
 		JWTRefreshExpiry: parseDuration(getEnv("JWT_REFRESH_EXPIRY", "720h")),
 
+		// Message encryption
+		MessageKeyProvider: getEnv("MESSAGE_KEY_PROVIDER", "none"),
+		MessageKeyID:       getEnv("MESSAGE_KEY_ID", "local-1"),
+		MessageMasterKey:   getEnv("MESSAGE_MASTER_KEY", ""),
+
+		// Personal data encryption
+		PIIKeys:     getEnv("PII_KEYS", ""),
+		PIIIndexKey: getEnv("PII_INDEX_KEY", "default-index-key-change-in-production"),
+
 		// Storage
-		StorageType: getEnv("STORAGE_TYPE", "local"),
-		StoragePath: getEnv("STORAGE_PATH", "./uploads"),
-		S3Bucket:    getEnv("S3_BUCKET", ""),
-		S3Region:    getEnv("S3_REGION", ""),
-		S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
-		S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+		StorageType:        getEnv("STORAGE_TYPE", "local"),
+		StoragePath:        getEnv("STORAGE_PATH", "./uploads"),
+		S3Bucket:           getEnv("S3_BUCKET", ""),
+		S3Region:           getEnv("S3_REGION", ""),
+		S3AccessKey:        getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:        getEnv("S3_SECRET_KEY", ""),
+		DocumentLinkSecret: getEnv("DOCUMENT_LINK_SECRET", "default-document-secret-change-in-production"),
+
+		// Database backups
+		BackupDir:      getEnv("BACKUP_DIR", "./backups"),
+		BackupStorage:  getEnv("BACKUP_STORAGE", "local"),
+		BackupS3Bucket: getEnv("BACKUP_S3_BUCKET", getEnv("S3_BUCKET", "")),
+		BackupS3Prefix: getEnv("BACKUP_S3_PREFIX", "db-backups"),
+		BackupKeep:     getEnvInt("BACKUP_KEEP", 7),
+		BackupInterval: parseDuration(getEnv("BACKUP_INTERVAL", "24h")),
+		PGDumpPath:     getEnv("PG_DUMP_PATH", "pg_dump"),
+		PGRestorePath:  getEnv("PG_RESTORE_PATH", "pg_restore"),
 
 		// FCM
-		FCMServerKey: getEnv("FCM_SERVER_KEY", ""),
+		FCMServerKey:     getEnv("FCM_SERVER_KEY", ""),
+		APNSVoIPProvider: getEnv("APNS_VOIP_PROVIDER", "none"),
+
+		// Commission
+		CommissionRate: getEnvFloat("COMMISSION_RATE", 0.15),
+
+		// Receipts
+		VATRate:          getEnvFloat("VAT_RATE", 0.20),
+		EInvoiceProvider: getEnv("EINVOICE_PROVIDER", "none"),
+		CompanyName:      getEnv("COMPANY_NAME", "Yandaş"),
+		CompanyTaxNumber: getEnv("COMPANY_TAX_NUMBER", ""),
+
+		// OCR
+		OCRProvider:   getEnv("OCR_PROVIDER", "none"),
+		TesseractPath: getEnv("TESSERACT_PATH", "tesseract"),
+		TesseractLang: getEnv("TESSERACT_LANG", "tur"),
+
+		// Identity verification
+		KYCProvider: getEnv("KYC_PROVIDER", "none"),
+
+		// Routing
+		RoutingProvider: getEnv("ROUTING_PROVIDER", "straight"),
+		RoutingURL:      getEnv("ROUTING_URL", ""),
+
+		// Exchange rates
+		ExchangeRateProvider: getEnv("EXCHANGE_RATE_PROVIDER", "none"),
+		ExchangeRateURL:      getEnv("EXCHANGE_RATE_URL", ""),
+
+		// Media
+		MediaTranscoder: getEnv("MEDIA_TRANSCODER", "none"),
+		FFmpegPath:      getEnv("FFMPEG_PATH", "ffmpeg"),
+
+		// Maintenance
+		MaintenanceMode: getEnvBool("MAINTENANCE_MODE", false),
+
+		// Service moderation
+		ServiceApprovalRequired: getEnvBool("SERVICE_APPROVAL_REQUIRED", false),
 
 		// Rate Limiting
 		RateLimitRequests: getEnvInt("RATE_LIMIT_REQUESTS", 100),
@@ -120,6 +283,10 @@ func Load() *Config {
 		WebURL: getEnv("WEB_URL", "https://yandas.app"),
 		APIURL: getEnv("API_URL", "https://api.yandas.app"),
 
+		// Browser security
+		CORSAllowedOrigins: getEnvList("CORS_ALLOWED_ORIGINS", "https://yandas.app", "https://www.yandas.app", "https://admin.yandas.app"),
+		HSTSMaxAge:         parseDuration(getEnv("HSTS_MAX_AGE", "8760h")),
+
 		// RevenueCat
 		RevenueCatAPIKey: getEnv("REVENUECAT_API_KEY", ""),
 
@@ -127,6 +294,14 @@ func Load() *Config {
 		TwilioAccountSID: getEnv("TWILIO_ACCOUNT_SID", ""),
 		TwilioAuthToken:  getEnv("TWILIO_AUTH_TOKEN", ""),
 		TwilioVerifySID:  getEnv("TWILIO_VERIFY_SERVICE_SID", ""),
+		TwilioFromNumber: getEnv("TWILIO_FROM_NUMBER", ""),
+
+		// SMS providers
+		SMSProvider:          getEnv("SMS_PROVIDER", "twilio"),
+		SMSFallbackProviders: getEnvList("SMS_FALLBACK_PROVIDERS"),
+		NetgsmUserCode:       getEnv("NETGSM_USERCODE", ""),
+		NetgsmPassword:       getEnv("NETGSM_PASSWORD", ""),
+		NetgsmHeader:         getEnv("NETGSM_HEADER", ""),
 
 		// SMTP Email
 		SMTPHost:     getEnv("SMTP_HOST", "mail.ubasoft.net"),
@@ -136,9 +311,27 @@ func Load() *Config {
 		SMTPFrom:     getEnv("SMTP_FROM", "yandas@ubasoft.net"),
 		SMTPFromName: getEnv("SMTP_FROM_NAME", "YANDAŞ"),
 
+		// Email provider
+		EmailProvider:      getEnv("EMAIL_PROVIDER", "smtp"),
+		EmailWebhookToken:  getEnv("EMAIL_WEBHOOK_TOKEN", ""),
+		SESRegion:          getEnv("SES_REGION", "eu-central-1"),
+		SendGridAPIKey:     getEnv("SENDGRID_API_KEY", ""),
+		SendGridWebhookKey: getEnv("SENDGRID_WEBHOOK_VERIFICATION_KEY", ""),
+
 		// Agora
 		AgoraAppID:          getEnv("AGORA_APP_ID", ""),
 		AgoraAppCertificate: getEnv("AGORA_APP_CERTIFICATE", ""),
+		CallRingTimeout:     parseDuration(getEnv("CALL_RING_TIMEOUT", "45s")),
+
+		// Agora Cloud Recording
+		AgoraRecordingEnabled:   getEnvBool("AGORA_RECORDING_ENABLED", false),
+		AgoraCustomerID:         getEnv("AGORA_CUSTOMER_ID", ""),
+		AgoraCustomerSecret:     getEnv("AGORA_CUSTOMER_SECRET", ""),
+		AgoraRecordingVendor:    getEnvInt("AGORA_RECORDING_VENDOR", 1),
+		AgoraRecordingRegion:    getEnvInt("AGORA_RECORDING_REGION", 0),
+		AgoraRecordingBucket:    getEnv("AGORA_RECORDING_BUCKET", ""),
+		AgoraRecordingAccessKey: getEnv("AGORA_RECORDING_ACCESS_KEY", ""),
+		AgoraRecordingSecretKey: getEnv("AGORA_RECORDING_SECRET_KEY", ""),
 	}
 }
 
@@ -158,6 +351,39 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvList splits a comma-separated variable, skipping empty entries. An
+// unset or empty variable gives defaultValues.
+func getEnvList(key string, defaultValues ...string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	if len(list) == 0 {
+		return defaultValues
+	}
+	return list
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
+
 func parseDuration(s string) time.Duration {
 	d, err := time.ParseDuration(s)
 	if err != nil {
@@ -5,32 +5,43 @@ import (
 	"log"
 
 	"github.com/yandas/backend/internal/config"
-	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/migrations"
+	"github.com/yandas/backend/pkg/crypto"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
-// Connect establishes a connection to the PostgreSQL database
-func Connect(cfg *config.Config) (*gorm.DB, error) {
-	var dsn string
+// DSN returns the connection string of the primary. Individual DB_ env vars
+// win over DATABASE_URL when DB_HOST points somewhere other than localhost.
+func DSN(cfg *config.Config) string {
 	// Prefer individual DB_ env vars (more reliable with Coolify)
-	if cfg.DBHost != "" && cfg.DBHost != "localhost" {
-		dsn = fmt.Sprintf(
+	if cfg.DBHost != "" && cfg.DBHost != "localhost" || cfg.DatabaseURL == "" {
+		return fmt.Sprintf(
 			"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 			cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName,
 		)
+	}
+	return cfg.DatabaseURL
+}
+
+// Connect establishes a connection to the PostgreSQL database
+func Connect(cfg *config.Config) (*gorm.DB, error) {
+	dsn := DSN(cfg)
+	if cfg.DBHost != "" && cfg.DBHost != "localhost" {
 		log.Printf("Using individual DB env vars: host=%s dbname=%s", cfg.DBHost, cfg.DBName)
 	} else if cfg.DatabaseURL != "" {
-		dsn = cfg.DatabaseURL
 		log.Printf("Using DATABASE_URL")
-	} else {
-		dsn = fmt.Sprintf(
-			"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-			cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName,
-		)
 	}
 
+	// Encrypted columns are sealed and opened with the configured keys
+	keyring, err := crypto.ParseKeyring(cfg.PIIKeys, cfg.PIIIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PII keys: %w", err)
+	}
+	crypto.Use(keyring)
+
 	logLevel := logger.Silent
 	if cfg.GinMode == "debug" {
 		logLevel = logger.Info
@@ -53,41 +64,54 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 	sqlDB.SetMaxIdleConns(10)
 	sqlDB.SetMaxOpenConns(100)
 
+	if err := useReplicas(db, cfg.DBReplicaURLs); err != nil {
+		return nil, fmt.Errorf("failed to configure read replicas: %w", err)
+	}
+
 	log.Println("✅ Database connected successfully")
 	return db, nil
 }
 
-// Migrate runs database migrations
+// useReplicas routes reads outside transactions to the replicas. Writes,
+// transactions, locking reads and queries pinned with dbresolver.Write use the
+// primary (see Repositories.OnPrimary).
+func useReplicas(db *gorm.DB, urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	replicas := make([]gorm.Dialector, 0, len(urls))
+	for _, url := range urls {
+		replicas = append(replicas, postgres.Open(url))
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}).
+		SetMaxIdleConns(10).
+		SetMaxOpenConns(100)
+	if err := db.Use(resolver); err != nil {
+		return err
+	}
+
+	log.Printf("Using %d read replica(s)", len(urls))
+	return nil
+}
+
+// Migrate applies pending SQL migrations from /migrations
 func Migrate(db *gorm.DB) error {
 	log.Println("🔄 Running database migrations...")
 
-	// Enable UUID extension
-	db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"")
-	db.Exec("CREATE EXTENSION IF NOT EXISTS \"pgcrypto\"")
-
-	// Auto-migrate all models
-	err := db.AutoMigrate(
-		&models.User{},
-		&models.YandasProfile{},
-		&models.Category{},
-		&models.YandasService{},
-		&models.Order{},
-		&models.Review{},
-		&models.Conversation{},
-		&models.Message{},
-		&models.Subscription{},
-		&models.DeviceToken{},
-		&models.AuditLog{},
-		&models.Notification{},
-		&models.SupportTicket{},
-		&models.SupportMessage{},
-		&models.Favorite{},
-		&models.CallLog{},
-	)
+	migrator, err := NewMigrator(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(0)
 	if err != nil {
 		return fmt.Errorf("migration failed: %w", err)
 	}
 
-	log.Println("✅ Database migrations completed")
+	log.Printf("✅ Database migrations completed (%d applied)", len(applied))
 	return nil
 }
@@ -0,0 +1,274 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// Serialises migration runs across API instances and cmd/migrate
+const migrationLockID = 7_341_902
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var ErrNoMigrations = errors.New("no migrations found")
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// schemaMigration is the row recorded for each applied migration
+type schemaMigration struct {
+	Version   uint64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+// LoadMigrations reads <version>_<name>.up.sql / .down.sql pairs from fsys, ordered by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		body, err := fs.ReadFile(fsys, path.Join(".", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		if m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+		}
+		list = append(list, *m)
+	}
+	if len(list) == 0 {
+		return nil, ErrNoMigrations
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// Migrator applies and rolls back versioned SQL migrations
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	list, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	// Schema state must never be read from a lagging replica
+	return &Migrator{db: db.Clauses(dbresolver.Write).Session(&gorm.Session{}), migrations: list}, nil
+}
+
+func (m *Migrator) applied() (map[uint64]schemaMigration, error) {
+	if err := m.db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, err
+	}
+	var rows []schemaMigration
+	if err := m.db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[uint64]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// adoptLegacySchema marks the baseline as applied on databases that were
+// created by AutoMigrate before migrations existed
+func (m *Migrator) adoptLegacySchema(applied map[uint64]schemaMigration) error {
+	if len(applied) > 0 || !m.db.Migrator().HasTable("users") {
+		return nil
+	}
+	baseline := schemaMigration{Version: m.migrations[0].Version, Name: m.migrations[0].Name, AppliedAt: time.Now()}
+	if err := m.db.Create(&baseline).Error; err != nil {
+		return err
+	}
+	applied[baseline.Version] = baseline
+	return nil
+}
+
+// Up applies up to steps pending migrations in order; steps <= 0 applies all of them
+func (m *Migrator) Up(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.adoptLegacySchema(applied); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if steps > 0 && len(done) == steps {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		ran, err := m.run(migration, true)
+		if err != nil {
+			return done, fmt.Errorf("%d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if ran {
+			done = append(done, migration)
+		}
+	}
+	return done, nil
+}
+
+// Down rolls back the latest steps applied migrations; steps <= 0 rolls back one
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		ran, err := m.run(migration, false)
+		if err != nil {
+			return done, fmt.Errorf("%d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if ran {
+			done = append(done, migration)
+		}
+	}
+	return done, nil
+}
+
+// run executes one direction of a migration in a transaction. It reports false
+// when another process got there first.
+func (m *Migrator) run(migration Migration, up bool) (bool, error) {
+	ran := false
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&schemaMigration{}).Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if (count > 0) == up {
+			return nil
+		}
+
+		if up {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+			ran = true
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		}
+		if err := tx.Exec(migration.Down).Error; err != nil {
+			return err
+		}
+		ran = true
+		return tx.Delete(&schemaMigration{}, "version = ?", migration.Version).Error
+	})
+	return ran, err
+}
+
+// Force records every migration up to version as applied and the rest as
+// pending, without running any SQL. Use it to repair a failed manual change.
+func (m *Migrator) Force(version uint64) error {
+	if _, err := m.applied(); err != nil {
+		return err
+	}
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("version > ?", version).Delete(&schemaMigration{}).Error; err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			row := schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+			if err := tx.Where(schemaMigration{Version: migration.Version}).FirstOrCreate(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Status lists every known migration with when it was applied
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
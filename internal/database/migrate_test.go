@@ -0,0 +1,74 @@
+package database
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/yandas/backend/migrations"
+)
+
+func TestLoadMigrationsOrdersPairs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000010_add_index.up.sql":   {Data: []byte("CREATE INDEX x ON t (a);")},
+		"000010_add_index.down.sql": {Data: []byte("DROP INDEX x;")},
+		"000002_second.up.sql":      {Data: []byte("ALTER TABLE t ADD b int;")},
+		"000002_second.down.sql":    {Data: []byte("ALTER TABLE t DROP b;")},
+		"README.md":                 {Data: []byte("ignored")},
+	}
+
+	list, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Version != 2 || list[1].Version != 10 {
+		t.Fatalf("expected versions 2 and 10 in order, got %+v", list)
+	}
+	if list[1].Name != "add_index" || list[1].Down != "DROP INDEX x;" {
+		t.Errorf("unexpected migration %+v", list[1])
+	}
+}
+
+func TestLoadMigrationsRejectsIncompletePairs(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"missing down": {"000001_a.up.sql": {Data: []byte("SELECT 1;")}},
+		"name clash": {
+			"000001_a.up.sql":   {Data: []byte("SELECT 1;")},
+			"000001_b.down.sql": {Data: []byte("SELECT 1;")},
+		},
+		"empty": {},
+	}
+	for name, fsys := range cases {
+		if _, err := LoadMigrations(fsys); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// Every table and index declared on the models must be created by some migration
+func TestMigrationsCoverModels(t *testing.T) {
+	list, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statements, err := SchemaSQL(Models()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var all strings.Builder
+	for _, m := range list {
+		all.WriteString(m.Up)
+	}
+	objectName := regexp.MustCompile(`^CREATE (?:TABLE|(?:UNIQUE )?INDEX IF NOT EXISTS) ("[a-z0-9_]+")`)
+	for _, stmt := range statements {
+		match := objectName.FindStringSubmatch(stmt)
+		if match == nil {
+			continue
+		}
+		if !strings.Contains(all.String(), match[1]) {
+			t.Errorf("no migration creates %s", match[1])
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Models lists every table the application uses. Keep it in sync with new
+// migrations; cmd/migrate schema renders it so a migration can be checked
+// against the models.
+func Models() []interface{} {
+	return []interface{}{
+		&models.User{},
+		&models.Address{},
+		&models.YandasProfile{},
+		&models.DocumentScreening{},
+		&models.DocumentRenewal{},
+		&models.Category{},
+		&models.CategoryDemand{},
+		&models.CategoryDocumentRule{},
+		&models.YandasQualityScore{},
+		&models.ApplicationQuestion{},
+		&models.ApplicationAnswer{},
+		&models.YandasService{},
+		&models.ServiceOption{},
+		&models.ServicePriceTier{},
+		&models.Order{},
+		&models.OrderLineItem{},
+		&models.OrderAdjustment{},
+		&models.OrderStatusHistory{},
+		&models.RecurringOrder{},
+		&models.JobRequest{},
+		&models.Bid{},
+		&models.AutoAssignment{},
+		&models.Receipt{},
+		&models.PayoutEntry{},
+		&models.Payout{},
+		&models.ExchangeRate{},
+		&models.Review{},
+		&models.ReviewVote{},
+		&models.Conversation{},
+		&models.Message{},
+		&models.ArchivedMessage{},
+		&models.Subscription{},
+		&models.DeviceToken{},
+		&models.Session{},
+		&models.ContactChange{},
+		&models.Setting{},
+		&models.AuditLog{},
+		&models.AlertRule{},
+		&models.AlertEvent{},
+		&models.MetricCounter{},
+		&models.WebhookEndpoint{},
+		&models.WebhookDelivery{},
+		&models.DomainEvent{},
+		&models.OutboxMessage{},
+		&models.SMSDelivery{},
+		&models.Role{},
+		&models.UserRole{},
+		&models.Announcement{},
+		&models.AnnouncementDismissal{},
+		&models.LegalDocument{},
+		&models.UserConsent{},
+		&models.Notification{},
+		&models.NotificationPreference{},
+		&models.SupportTicket{},
+		&models.SupportMessage{},
+		&models.CannedResponse{},
+		&models.Favorite{},
+		&models.FavoriteActivity{},
+		&models.ProfileView{},
+		&models.YandasDailyStat{},
+		&models.AnalyticsEvent{},
+		&models.CallLog{},
+		&models.CallParticipant{},
+		&models.DatabaseBackup{},
+		&models.AdminActivity{},
+	}
+}
+
+// statementRecorder collects the SQL gorm would run in dry-run mode
+type statementRecorder struct {
+	logger.Interface
+	statements []string
+}
+
+func (r *statementRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.statements = append(r.statements, sql)
+}
+
+// offlineConn fails every query so rendering the schema never touches a real database
+type offlineConn struct{}
+
+var errOffline = errors.New("schema rendering runs without a database")
+
+func (offlineConn) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errOffline
+}
+
+func (offlineConn) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errOffline
+}
+
+func (offlineConn) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errOffline
+}
+
+func (offlineConn) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+// SchemaSQL renders the CREATE statements for tables against an empty database,
+// in dependency order. It generated the baseline migration.
+func SchemaSQL(tables ...interface{}) ([]string, error) {
+	recorder := &statementRecorder{Interface: logger.Discard}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: offlineConn{}}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               recorder,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, ok := db.Migrator().(postgres.Migrator)
+	if !ok {
+		return nil, fmt.Errorf("unexpected migrator %T", db.Migrator())
+	}
+	for _, table := range migrator.ReorderModels(tables, true) {
+		if err := migrator.CreateTable(table); err != nil {
+			return nil, err
+		}
+	}
+
+	statements := make([]string, 0, len(recorder.statements))
+	for _, stmt := range recorder.statements {
+		statements = append(statements, strings.TrimSuffix(stmt, ";"))
+	}
+	return statements, nil
+}
@@ -1,12 +1,20 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
 	"github.com/yandas/backend/internal/services"
+	"github.com/yandas/backend/pkg/export"
 )
 
 type AdminHandler struct {
@@ -22,17 +30,94 @@ func (h *AdminHandler) Dashboard(c *gin.Context) {
 	c.JSON(http.StatusOK, SuccessResponse(stats))
 }
 
+// Activity returns the recent platform activity feed, newest first; type
+// narrows it to order_created, application_submitted, ticket_created or
+// payment_webhook. New entries stream live to the admin:events WebSocket room.
+func (h *AdminHandler) Activity(c *gin.Context) {
+	page, limit := getPagination(c)
+	activities, total, err := h.svcs.Activity.List(c.Query("type"), page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(activities, PaginationMeta(page, limit, total)))
+}
+
+// ListUsers searches users; filters are q (email, phone or name), role, verified,
+// active, created_from/created_to (YYYY-MM-DD), city, subscription (active,
+// cancelled, expired, none) and sort (newest, oldest, name, email)
 func (h *AdminHandler) ListUsers(c *gin.Context) {
 	page, limit := getPagination(c)
-	users, total, _ := h.svcs.Admin.ListUsers(page, limit, c.Query("role"))
+	filter := repository.UserFilter{
+		Query:        strings.TrimSpace(c.Query("q")),
+		Role:         c.Query("role"),
+		City:         c.Query("city"),
+		Subscription: c.Query("subscription"),
+		Sort:         c.Query("sort"),
+	}
+
+	var err error
+	if filter.Verified, err = optionalBoolQuery(c, "verified"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.Active, err = optionalBoolQuery(c, "active"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.CreatedFrom, filter.CreatedTo, err = dateRangeQuery(c, "created_from", "created_to"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+
+	users, total, err := h.svcs.Admin.ListUsers(filter, page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponseWithMeta(users, PaginationMeta(page, limit, total)))
 }
 
+// dateRangeQuery parses YYYY-MM-DD query parameters into a half-open range
+// that includes the whole of the to day; absent bounds are nil
+func dateRangeQuery(c *gin.Context, fromKey, toKey string) (from, to *time.Time, err error) {
+	if v := c.Query(fromKey); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, nil, errors.New("invalid " + fromKey + " date")
+		}
+		from = &t
+	}
+	if v := c.Query(toKey); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, nil, errors.New("invalid " + toKey + " date")
+		}
+		end := t.AddDate(0, 0, 1)
+		to = &end
+	}
+	return from, to, nil
+}
+
+func (h *AdminHandler) BulkUserStatus(c *gin.Context) {
+	var input services.BulkUserStatusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	result, err := h.svcs.Admin.BulkSetUserStatus(&input, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(result))
+}
+
 func (h *AdminHandler) GetUser(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
 	user, err := h.svcs.Admin.GetUser(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(user))
@@ -42,13 +127,20 @@ func (h *AdminHandler) UpdateUser(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
 	var updates map[string]interface{}
 	c.ShouldBindJSON(&updates)
-	user, _ := h.svcs.Admin.UpdateUser(id, updates)
+	user, err := h.svcs.Admin.UpdateUser(id, updates)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(user))
 }
 
 func (h *AdminHandler) DeleteUser(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
-	h.svcs.Admin.DeleteUser(id)
+	if err := h.svcs.Admin.DeleteUser(id); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Deleted"}))
 }
 
@@ -61,42 +153,309 @@ func (h *AdminHandler) ListApplications(c *gin.Context) {
 
 func (h *AdminHandler) GetApplication(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
-	app, _ := h.svcs.Admin.GetApplication(id)
+	app, err := h.svcs.Admin.GetApplication(id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(app))
 }
 
+// ApplicationDocument serves an identity document through a signed link from
+// GetApplication. The link is the credential, so the route needs no token and
+// the file is never cached.
+func (h *AdminHandler) ApplicationDocument(c *gin.Context) {
+	file, err := h.svcs.Documents.Open(c.Param("name"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "private, no-store")
+	c.File(file)
+}
+
+// ScreenApplication re-runs OCR pre-screening on an application's kimlik
+func (h *AdminHandler) ScreenApplication(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	screening, err := h.svcs.Screening.ScreenApplication(id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(screening))
+}
+
+// VerifyApplicationIdentity re-runs the selfie and ID check on an application
+func (h *AdminHandler) VerifyApplicationIdentity(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	outcome, err := h.svcs.KYC.VerifyApplication(id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(outcome))
+}
+
 func (h *AdminHandler) ApproveApplication(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
-	h.svcs.Admin.ApproveApplication(id, getUserID(c))
+	if err := h.svcs.Admin.ApproveApplication(id, getUserID(c)); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Approved"}))
 }
 
+func (h *AdminHandler) BulkApproveApplications(c *gin.Context) {
+	var input services.BulkApproveInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(h.svcs.Admin.BulkApproveApplications(&input, getUserID(c))))
+}
+
 func (h *AdminHandler) RejectApplication(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
 	var input struct {
 		Reason string `json:"reason"`
 	}
 	c.ShouldBindJSON(&input)
-	h.svcs.Admin.RejectApplication(id, getUserID(c), input.Reason)
+	if err := h.svcs.Admin.RejectApplication(id, getUserID(c), input.Reason); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Rejected"}))
 }
 
+// ListOrders filters orders by status, created_from/created_to and
+// scheduled_from/scheduled_to (YYYY-MM-DD), city, category, customer_id,
+// yandas_id, min_amount/max_amount and sort (newest, oldest, amount_desc,
+// amount_asc, scheduled); meta.total_revenue sums the completed ones in TRY and
+// meta.revenue breaks them down per currency in minor units
 func (h *AdminHandler) ListOrders(c *gin.Context) {
 	page, limit := getPagination(c)
-	orders, total, _ := h.svcs.Admin.ListOrders(page, limit, c.Query("status"))
-	c.JSON(http.StatusOK, SuccessResponseWithMeta(orders, PaginationMeta(page, limit, total)))
+	filter := repository.OrderFilter{
+		Status:       c.Query("status"),
+		City:         c.Query("city"),
+		CategorySlug: c.Query("category"),
+		Sort:         c.Query("sort"),
+	}
+
+	var err error
+	if filter.CreatedFrom, filter.CreatedTo, err = dateRangeQuery(c, "created_from", "created_to"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.ScheduledFrom, filter.ScheduledTo, err = dateRangeQuery(c, "scheduled_from", "scheduled_to"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.CustomerID, err = optionalUUIDQuery(c, "customer_id"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.YandasID, err = optionalUUIDQuery(c, "yandas_id"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.MinAmount, err = optionalMoneyQuery(c, "min_amount"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.MaxAmount, err = optionalMoneyQuery(c, "max_amount"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+
+	orders, total, revenue, err := h.svcs.Admin.ListOrders(filter, page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	meta := PaginationMeta(page, limit, total)
+	meta.TotalRevenue = &revenue.Total
+	meta.Revenue = revenue
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(orders, meta))
+}
+
+// optionalUUIDQuery parses an ID query parameter; absent means nil
+func optionalUUIDQuery(c *gin.Context, key string) (*uuid.UUID, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return nil, errors.New("invalid " + key)
+	}
+	return &id, nil
 }
 
 func (h *AdminHandler) GetOrder(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
-	order, _ := h.svcs.Admin.GetOrder(id)
+	order, err := h.svcs.Admin.GetOrder(id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(order))
 }
 
+// ListReviews lists reviews, hidden ones included; filters are q (comment or
+// reply), reviewee_id, reviewer_id, min_rating/max_rating, hidden,
+// created_from/created_to (YYYY-MM-DD) and sort (newest, oldest, rating_asc, rating_desc)
+func (h *AdminHandler) ListReviews(c *gin.Context) {
+	page, limit := getPagination(c)
+	filter := repository.ReviewFilter{
+		Query: strings.TrimSpace(c.Query("q")),
+		Sort:  c.Query("sort"),
+	}
+
+	var err error
+	if filter.RevieweeID, err = optionalUUIDQuery(c, "reviewee_id"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.ReviewerID, err = optionalUUIDQuery(c, "reviewer_id"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.MinRating, err = optionalIntQuery(c, "min_rating"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.MaxRating, err = optionalIntQuery(c, "max_rating"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.Hidden, err = optionalBoolQuery(c, "hidden"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.CreatedFrom, filter.CreatedTo, err = dateRangeQuery(c, "created_from", "created_to"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+
+	reviews, total, err := h.svcs.Admin.ListReviews(filter, page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(reviews, PaginationMeta(page, limit, total)))
+}
+
+func (h *AdminHandler) HideReview(c *gin.Context) {
+	h.setReviewHidden(c, true)
+}
+
+func (h *AdminHandler) UnhideReview(c *gin.Context) {
+	h.setReviewHidden(c, false)
+}
+
+func (h *AdminHandler) setReviewHidden(c *gin.Context, hidden bool) {
+	id, _ := uuid.Parse(c.Param("id"))
+	if err := h.svcs.Admin.SetReviewHidden(id, hidden, getUserID(c)); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"is_hidden": hidden}))
+}
+
+// ListServices returns services by status (draft, pending, active,
+// rejected), oldest first; pending is the moderation queue and the default
+func (h *AdminHandler) ListServices(c *gin.Context) {
+	page, limit := getPagination(c)
+	status := c.DefaultQuery("status", services.ServiceStatusPending)
+	list, total, err := h.svcs.Admin.ListServices(status, page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(list, PaginationMeta(page, limit, total)))
+}
+
+func (h *AdminHandler) ApproveService(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid service ID"))
+		return
+	}
+	svc, err := h.svcs.Admin.ApproveService(id, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(svc))
+}
+
+func (h *AdminHandler) RejectService(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid service ID"))
+		return
+	}
+	var input struct {
+		Reason string `json:"reason" binding:"required,max=500"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	svc, err := h.svcs.Admin.RejectService(id, getUserID(c), input.Reason)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(svc))
+}
+
+func (h *AdminHandler) ListDocumentRenewals(c *gin.Context) {
+	page, limit := getPagination(c)
+	status := c.DefaultQuery("status", services.RenewalLapsed)
+	list, total, err := h.svcs.Admin.ListDocumentRenewals(status, page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(list, PaginationMeta(page, limit, total)))
+}
+
+func (h *AdminHandler) WaiveDocumentRenewal(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid document renewal ID"))
+		return
+	}
+	var input services.WaiveRenewalInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	renewal, err := h.svcs.Admin.WaiveDocumentRenewal(id, getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(renewal))
+}
+
+func (h *AdminHandler) DeleteReview(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	if err := h.svcs.Admin.DeleteReview(id, getUserID(c)); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Deleted"}))
+}
+
 func (h *AdminHandler) CreateCategory(c *gin.Context) {
 	var cat models.Category
 	c.ShouldBindJSON(&cat)
-	h.svcs.Admin.CreateCategory(&cat)
+	if err := h.svcs.Admin.CreateCategory(&cat); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusCreated, SuccessResponse(cat))
 }
 
@@ -105,89 +464,999 @@ func (h *AdminHandler) UpdateCategory(c *gin.Context) {
 	var cat models.Category
 	c.ShouldBindJSON(&cat)
 	cat.ID = id
-	h.svcs.Admin.UpdateCategory(&cat)
+	if err := h.svcs.Admin.UpdateCategory(&cat); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(cat))
 }
 
 func (h *AdminHandler) DeleteCategory(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
-	h.svcs.Admin.DeleteCategory(id)
+	if err := h.svcs.Admin.DeleteCategory(id); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Deleted"}))
 }
 
-func (h *AdminHandler) AnalyticsOverview(c *gin.Context) {
-	stats, _ := h.svcs.Admin.GetDashboard()
-	c.JSON(http.StatusOK, SuccessResponse(stats))
-}
-
-func (h *AdminHandler) AnalyticsRevenue(c *gin.Context) {
-	c.JSON(http.StatusOK, SuccessResponse(gin.H{"revenue": 0}))
-}
-
-func (h *AdminHandler) AnalyticsUsers(c *gin.Context) {
-	c.JSON(http.StatusOK, SuccessResponse(gin.H{"users": 0}))
-}
-
-func (h *AdminHandler) AuditLogs(c *gin.Context) {
-	page, limit := getPagination(c)
-	logs, total, _ := h.svcs.Admin.GetAuditLogs(page, limit, nil, "")
-	c.JSON(http.StatusOK, SuccessResponseWithMeta(logs, PaginationMeta(page, limit, total)))
+// ListApplicationQuestions returns a category's application questions,
+// inactive ones included
+func (h *AdminHandler) ListApplicationQuestions(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	questions, err := h.svcs.Onboarding.Questions(id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(questions))
 }
 
-// Support Ticket handlers
-
-func (h *AdminHandler) ListSupportTickets(c *gin.Context) {
-	page, limit := getPagination(c)
-	status := c.Query("status")
-	priority := c.Query("priority")
-	tickets, total, _ := h.svcs.Admin.ListSupportTickets(page, limit, status, priority)
-	c.JSON(http.StatusOK, SuccessResponseWithMeta(tickets, PaginationMeta(page, limit, total)))
+func (h *AdminHandler) CreateApplicationQuestion(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	var input services.ApplicationQuestionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	question, err := h.svcs.Onboarding.CreateQuestion(id, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(question))
 }
 
-func (h *AdminHandler) GetSupportTicket(c *gin.Context) {
+func (h *AdminHandler) UpdateApplicationQuestion(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
-	ticket, err := h.svcs.Admin.GetSupportTicket(id)
+	var input services.ApplicationQuestionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	question, err := h.svcs.Onboarding.UpdateQuestion(id, &input)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, SuccessResponse(ticket))
+	c.JSON(http.StatusOK, SuccessResponse(question))
 }
 
-func (h *AdminHandler) UpdateSupportTicket(c *gin.Context) {
+func (h *AdminHandler) DeleteApplicationQuestion(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
-	var updates struct {
-		Status     string `json:"status"`
-		Priority   string `json:"priority"`
-		AssignedTo string `json:"assigned_to"`
+	if err := h.svcs.Onboarding.DeleteQuestion(id); err != nil {
+		serviceError(c, err)
+		return
 	}
-	c.ShouldBindJSON(&updates)
-	ticket, err := h.svcs.Admin.UpdateSupportTicket(id, updates.Status, updates.Priority, updates.AssignedTo)
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Deleted"}))
+}
+
+// GetDocumentRules returns the documents a category requires of applicants
+func (h *AdminHandler) GetDocumentRules(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	rules, err := h.svcs.Onboarding.DocumentRules(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, SuccessResponse(ticket))
+	c.JSON(http.StatusOK, SuccessResponse(rules))
 }
 
-func (h *AdminHandler) ReplySupportTicket(c *gin.Context) {
+// SetDocumentRules replaces the documents a category requires of applicants
+func (h *AdminHandler) SetDocumentRules(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
-	var input struct {
-		Content string `json:"content" binding:"required"`
-	}
+	var input services.DocumentRulesInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse("Content is required"))
+		bindError(c, err)
 		return
 	}
-	message, err := h.svcs.Admin.ReplySupportTicket(id, getUserID(c), input.Content)
+	rules, err := h.svcs.Onboarding.SetDocumentRules(id, &input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, SuccessResponse(message))
+	c.JSON(http.StatusOK, SuccessResponse(rules))
 }
 
-func (h *AdminHandler) GetSupportStats(c *gin.Context) {
-	stats, _ := h.svcs.Admin.GetSupportStats()
-	c.JSON(http.StatusOK, SuccessResponse(stats))
+// ImportCategories creates and updates categories from an uploaded CSV or JSON
+// file. With dry_run=true it only reports the diff against the stored categories.
+func (h *AdminHandler) ImportCategories(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("csv or json file required"))
+		return
+	}
+	format := c.Query("format")
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file.Filename)), ".")
+	}
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+
+	f, err := file.Open()
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	defer f.Close()
+
+	rows, err := services.ParseCategoryImport(format, f)
+	if err == nil {
+		var result *services.CategoryImportResult
+		result, err = h.svcs.Admin.ImportCategories(rows, dryRun, getUserID(c))
+		if err == nil {
+			c.JSON(http.StatusOK, SuccessResponse(result))
+			return
+		}
+	}
+
+	var invalid *services.CategoryImportError
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusUnprocessableEntity, Response{Success: false, Error: err.Error(), Data: invalid.Rows})
+		return
+	}
+	serviceError(c, err)
+}
+
+func (h *AdminHandler) AnalyticsOverview(c *gin.Context) {
+	stats, _ := h.svcs.Admin.GetDashboard()
+	c.JSON(http.StatusOK, SuccessResponse(stats))
+}
+
+func (h *AdminHandler) AnalyticsRevenue(c *gin.Context) {
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"revenue": 0}))
+}
+
+func (h *AdminHandler) AnalyticsUsers(c *gin.Context) {
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"users": 0}))
+}
+
+// Cohorts groups customers by registration month with their retention,
+// average order value and LTV; from/to are YYYY-MM and default to the last 12 months
+func (h *AdminHandler) Cohorts(c *gin.Context) {
+	from, to, ok := cohortRange(c)
+	if !ok {
+		return
+	}
+	report, err := h.svcs.Admin.Cohorts(from, to)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(report))
+}
+
+// ExportCohorts streams the cohort report as csv or xlsx
+func (h *AdminHandler) ExportCohorts(c *gin.Context) {
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		c.JSON(http.StatusBadRequest, ErrorResponse("format must be csv or xlsx"))
+		return
+	}
+	from, to, ok := cohortRange(c)
+	if !ok {
+		return
+	}
+	// Build the report before the attachment headers go out
+	report, err := h.svcs.Admin.Cohorts(from, to)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	writeExport(c, "cohorts", format, func(w export.Writer) error {
+		return h.svcs.Admin.ExportCohorts(report, w, getUserID(c))
+	})
+}
+
+// cohortRange parses the from/to months, answering 400 when one is malformed
+func cohortRange(c *gin.Context) (from, to time.Time, ok bool) {
+	to = time.Now()
+	from = time.Date(to.Year(), to.Month()-11, 1, 0, 0, 0, 0, time.Local)
+	for _, param := range []struct {
+		key   string
+		month *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		if v := c.Query(param.key); v != "" {
+			t, err := time.ParseInLocation("2006-01", v, time.Local)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse("invalid "+param.key+" month"))
+				return from, to, false
+			}
+			*param.month = t
+		}
+	}
+	return from, to, true
+}
+
+// Funnel reports the search to profile view to order started funnel for the
+// from/to date range; platform (ios, android, web) narrows it to one app
+func (h *AdminHandler) Funnel(c *gin.Context) {
+	from, to, ok := dateRange(c)
+	if !ok {
+		return
+	}
+	platform := c.Query("platform")
+	if platform != "" && platform != "ios" && platform != "android" && platform != "web" {
+		c.JSON(http.StatusBadRequest, ErrorResponse("platform must be ios, android or web"))
+		return
+	}
+	report, err := h.svcs.Tracking.Funnel(from, to, platform)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(report))
+}
+
+func (h *AdminHandler) AuditLogs(c *gin.Context) {
+	page, limit := getPagination(c)
+	logs, total, _ := h.svcs.Admin.GetAuditLogs(page, limit, nil, "")
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(logs, PaginationMeta(page, limit, total)))
+}
+
+func (h *AdminHandler) GetCallRecording(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	recording, err := h.svcs.Admin.GetCallRecording(id, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(recording))
+}
+
+// CallQuality reports call feedback by region and platform for the from/to date range
+func (h *AdminHandler) CallQuality(c *gin.Context) {
+	from, to, ok := dateRange(c)
+	if !ok {
+		return
+	}
+	rows, err := h.svcs.Call.QualityReport(from, to)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{
+		"from": from.Format("2006-01-02"),
+		"to":   to.AddDate(0, 0, -1).Format("2006-01-02"),
+		"rows": rows,
+	}))
+}
+
+// Leaderboard lists yandaşlar by quality score; filters are city, category
+// (slug), min_orders, min_score, max_score, available and sort (score,
+// score_asc, rating, completion, response, disputes, cancellations, orders)
+func (h *AdminHandler) Leaderboard(c *gin.Context) {
+	page, limit := getPagination(c)
+	filter := repository.QualityFilter{
+		City:         c.Query("city"),
+		CategorySlug: c.Query("category"),
+		Sort:         c.Query("sort"),
+	}
+
+	minOrders, err := optionalIntQuery(c, "min_orders")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if minOrders != nil {
+		filter.MinOrders = *minOrders
+	}
+	if filter.MinScore, err = optionalFloatQuery(c, "min_score"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.MaxScore, err = optionalFloatQuery(c, "max_score"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	if filter.Available, err = optionalBoolQuery(c, "available"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+
+	entries, total, err := h.svcs.Admin.Leaderboard(filter, page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(entries, PaginationMeta(page, limit, total)))
+}
+
+// Retention handlers
+
+// RetentionReport previews what the retention policies would remove now, without removing anything
+func (h *AdminHandler) RetentionReport(c *gin.Context) {
+	c.JSON(http.StatusOK, SuccessResponse(h.svcs.Retention.Run(true)))
+}
+
+// RunRetention applies the retention policies now; dry_run=true only reports
+func (h *AdminHandler) RunRetention(c *gin.Context) {
+	dryRun, err := optionalBoolQuery(c, "dry_run")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+	report := h.svcs.Retention.RunNow(getUserID(c), dryRun != nil && *dryRun)
+	c.JSON(http.StatusOK, SuccessResponse(report))
+}
+
+// Role handlers
+
+func (h *AdminHandler) MyPermissions(c *gin.Context) {
+	permissions, err := h.svcs.Permission.UserPermissions(getUserID(c), c.GetString("role"))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"permissions": permissions}))
+}
+
+func (h *AdminHandler) ListRoles(c *gin.Context) {
+	roles, err := h.svcs.Permission.ListRoles()
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(roles))
+}
+
+func (h *AdminHandler) GetUserRoles(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	roles, err := h.svcs.Permission.GetUserRoles(id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(roles))
+}
+
+func (h *AdminHandler) AssignUserRoles(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	var input services.AssignRolesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	roles, err := h.svcs.Permission.AssignRoles(id, &input, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(roles))
+}
+
+// Export handlers
+
+func (h *AdminHandler) ExportUsers(c *gin.Context) {
+	h.export(c, services.ExportUsers)
+}
+
+func (h *AdminHandler) ExportOrders(c *gin.Context) {
+	h.export(c, services.ExportOrders)
+}
+
+func (h *AdminHandler) ExportApplications(c *gin.Context) {
+	h.export(c, services.ExportApplications)
+}
+
+// export streams a dataset as csv or xlsx; filters are from/to (YYYY-MM-DD), status, role and city
+func (h *AdminHandler) export(c *gin.Context, dataset string) {
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		c.JSON(http.StatusBadRequest, ErrorResponse("format must be csv or xlsx"))
+		return
+	}
+
+	filter := repository.ExportFilter{
+		Status: c.Query("status"),
+		Role:   c.Query("role"),
+		City:   c.Query("city"),
+	}
+	var err error
+	if filter.From, filter.To, err = dateRangeQuery(c, "from", "to"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		return
+	}
+
+	writeExport(c, dataset, format, func(w export.Writer) error {
+		return h.svcs.Admin.Export(dataset, filter, w, getUserID(c))
+	})
+}
+
+// writeExport sends the attachment headers and lets write fill the file
+func writeExport(c *gin.Context, name, format string, write func(w export.Writer) error) {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	w, err := export.NewWriter(format, c.Writer)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	// Headers are already sent, so a failure here can only truncate the file
+	if err := write(w); err != nil {
+		c.Error(err)
+	}
+}
+
+// ReviewConversation returns a page of a conversation's messages when a dispute or support ticket concerns it
+func (h *AdminHandler) ReviewConversation(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	page, limit := getPagination(c)
+	review, total, err := h.svcs.Admin.ReviewConversation(id, getUserID(c), page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(review, PaginationMeta(page, limit, total)))
+}
+
+// ExportConversationTranscript streams a flagged conversation's transcript as csv or xlsx
+func (h *AdminHandler) ExportConversationTranscript(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		c.JSON(http.StatusBadRequest, ErrorResponse("format must be csv or xlsx"))
+		return
+	}
+	// Check access before the attachment headers go out
+	if _, err := h.svcs.Admin.ConversationFlags(id); err != nil {
+		serviceError(c, err)
+		return
+	}
+	writeExport(c, "conversation-"+id.String(), format, func(w export.Writer) error {
+		return h.svcs.Admin.ExportConversationTranscript(id, getUserID(c), w)
+	})
+}
+
+// Report handlers
+
+// ReportOrders streams orders with their commission split as csv or xlsx;
+// filters are from/to (YYYY-MM-DD, last 30 days by default), status and city
+func (h *AdminHandler) ReportOrders(c *gin.Context) {
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		c.JSON(http.StatusBadRequest, ErrorResponse("format must be csv or xlsx"))
+		return
+	}
+	from, to, ok := dateRange(c)
+	if !ok {
+		return
+	}
+	filter := repository.ExportFilter{
+		From:   &from,
+		To:     &to,
+		Status: c.Query("status"),
+		City:   c.Query("city"),
+	}
+
+	writeExport(c, "order-report", format, func(w export.Writer) error {
+		return h.svcs.Admin.OrderReport(filter, w, getUserID(c))
+	})
+}
+
+// AccountingReport totals a month's completed orders by category and city;
+// month is YYYY-MM and defaults to the current month
+func (h *AdminHandler) AccountingReport(c *gin.Context) {
+	month := time.Now()
+	if v := c.Query("month"); v != "" {
+		t, err := time.ParseInLocation("2006-01", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse("invalid month"))
+			return
+		}
+		month = t
+	}
+
+	summary, err := h.svcs.Admin.MonthlyAccounting(month)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(summary))
+}
+
+// Payout handlers
+
+func (h *AdminHandler) PayoutBalances(c *gin.Context) {
+	balances, err := h.svcs.Admin.PayoutBalances()
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(balances))
+}
+
+func (h *AdminHandler) ListPayouts(c *gin.Context) {
+	page, limit := getPagination(c)
+	var yandasID *uuid.UUID
+	if id, err := uuid.Parse(c.Query("yandas_id")); err == nil {
+		yandasID = &id
+	}
+	payouts, total, err := h.svcs.Admin.ListPayouts(page, limit, c.Query("status"), yandasID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(payouts, PaginationMeta(page, limit, total)))
+}
+
+func (h *AdminHandler) GetPayout(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	payout, err := h.svcs.Admin.GetPayout(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse("payout not found"))
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(payout))
+}
+
+func (h *AdminHandler) CreatePayout(c *gin.Context) {
+	var input services.CreatePayoutInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	payout, err := h.svcs.Admin.CreatePayout(&input, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(payout))
+}
+
+func (h *AdminHandler) MarkPayoutTransferred(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	var input services.MarkPayoutTransferredInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	payout, err := h.svcs.Admin.MarkPayoutTransferred(id, &input, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(payout))
+}
+
+// Monitoring handlers
+
+func (h *AdminHandler) CurrentMetrics(c *gin.Context) {
+	window, _ := strconv.Atoi(c.DefaultQuery("window", "60"))
+	if window < 1 || window > 10080 {
+		window = 60
+	}
+	metrics, err := h.svcs.Monitoring.CurrentMetrics(window)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"window_minutes": window, "metrics": metrics}))
+}
+
+func (h *AdminHandler) ListAlertRules(c *gin.Context) {
+	rules, err := h.svcs.Monitoring.ListRules()
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(rules))
+}
+
+func (h *AdminHandler) CreateAlertRule(c *gin.Context) {
+	var input services.AlertRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	rule, err := h.svcs.Monitoring.CreateRule(&input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(rule))
+}
+
+func (h *AdminHandler) UpdateAlertRule(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	var input services.AlertRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	rule, err := h.svcs.Monitoring.UpdateRule(id, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(rule))
+}
+
+func (h *AdminHandler) DeleteAlertRule(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	if err := h.svcs.Monitoring.DeleteRule(id); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Deleted"}))
+}
+
+func (h *AdminHandler) ListAlertEvents(c *gin.Context) {
+	page, limit := getPagination(c)
+	var ruleID *uuid.UUID
+	if id, err := uuid.Parse(c.Query("rule_id")); err == nil {
+		ruleID = &id
+	}
+	events, total, err := h.svcs.Monitoring.ListEvents(page, limit, ruleID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(events, PaginationMeta(page, limit, total)))
+}
+
+// Webhook handlers
+
+func (h *AdminHandler) ListWebhooks(c *gin.Context) {
+	endpoints, err := h.svcs.Webhook.ListEndpoints()
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"endpoints": endpoints, "events": services.WebhookEvents}))
+}
+
+func (h *AdminHandler) CreateWebhook(c *gin.Context) {
+	var input services.WebhookEndpointInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	endpoint, err := h.svcs.Webhook.CreateEndpoint(&input, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(endpoint))
+}
+
+func (h *AdminHandler) UpdateWebhook(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	var input services.WebhookEndpointInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	endpoint, err := h.svcs.Webhook.UpdateEndpoint(id, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(endpoint))
+}
+
+func (h *AdminHandler) DeleteWebhook(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	if err := h.svcs.Webhook.DeleteEndpoint(id); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Deleted"}))
+}
+
+func (h *AdminHandler) ListWebhookDeliveries(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	page, limit := getPagination(c)
+	deliveries, total, err := h.svcs.Webhook.ListDeliveries(id, page, limit, c.Query("status"))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(deliveries, PaginationMeta(page, limit, total)))
+}
+
+func (h *AdminHandler) RetryWebhookDelivery(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	delivery, err := h.svcs.Webhook.RetryDelivery(id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(delivery))
+}
+
+// ListSMSDeliveries returns logged SMS send attempts and their delivery status
+func (h *AdminHandler) ListSMSDeliveries(c *gin.Context) {
+	page, limit := getPagination(c)
+	deliveries, total, err := h.svcs.SMS.Deliveries(page, limit, c.Query("phone"), c.Query("status"))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(deliveries, PaginationMeta(page, limit, total)))
+}
+
+// Request capture handlers
+
+func (h *AdminHandler) ListCaptureRules(c *gin.Context) {
+	rules, err := h.svcs.Capture.ListCaptureRules()
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(rules))
+}
+
+// StartCapture records sampled requests of a user or endpoint for a limited time
+func (h *AdminHandler) StartCapture(c *gin.Context) {
+	var input services.CaptureRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	rule, err := h.svcs.Capture.StartCapture(&input, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(rule))
+}
+
+func (h *AdminHandler) StopCapture(c *gin.Context) {
+	if err := h.svcs.Capture.StopCapture(c.Param("id"), getUserID(c)); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Stopped"}))
+}
+
+// ListCapturedRequests returns captured requests, newest first, filtered by
+// rule_id, user_id or a path prefix
+func (h *AdminHandler) ListCapturedRequests(c *gin.Context) {
+	page, limit := getPagination(c)
+	filter := services.CaptureFilter{
+		RuleID: c.Query("rule_id"),
+		UserID: c.Query("user_id"),
+		Path:   c.Query("path"),
+	}
+	exchanges, total, err := h.svcs.Capture.ListCaptured(filter, page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(exchanges, PaginationMeta(page, limit, total)))
+}
+
+// Settings handlers
+
+func (h *AdminHandler) ListSettings(c *gin.Context) {
+	settings, err := h.svcs.Settings.List()
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(settings))
+}
+
+// UpdateSettings changes several settings at once; nothing is stored unless every value is valid
+func (h *AdminHandler) UpdateSettings(c *gin.Context) {
+	var input services.UpdateSettingsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	settings, err := h.svcs.Settings.Update(&input, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(settings))
+}
+
+// Support Ticket handlers
+
+func (h *AdminHandler) ListSupportTickets(c *gin.Context) {
+	page, limit := getPagination(c)
+	status := c.Query("status")
+	priority := c.Query("priority")
+	tickets, total, _ := h.svcs.Admin.ListSupportTickets(page, limit, status, priority)
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(tickets, PaginationMeta(page, limit, total)))
+}
+
+func (h *AdminHandler) GetSupportTicket(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	ticket, err := h.svcs.Admin.GetSupportTicket(id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(ticket))
+}
+
+func (h *AdminHandler) UpdateSupportTicket(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	var updates struct {
+		Status     string `json:"status"`
+		Priority   string `json:"priority"`
+		AssignedTo string `json:"assigned_to"`
+	}
+	c.ShouldBindJSON(&updates)
+	ticket, err := h.svcs.Admin.UpdateSupportTicket(id, updates.Status, updates.Priority, updates.AssignedTo)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(ticket))
+}
+
+func (h *AdminHandler) ReplySupportTicket(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	var input services.SupportReplyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	message, err := h.svcs.Admin.ReplySupportTicket(id, getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(message))
+}
+
+func (h *AdminHandler) GetSupportStats(c *gin.Context) {
+	stats, _ := h.svcs.Admin.GetSupportStats()
+	c.JSON(http.StatusOK, SuccessResponse(stats))
+}
+
+// Legal document handlers
+
+func (h *AdminHandler) ListLegalDocuments(c *gin.Context) {
+	page, limit := getPagination(c)
+	documents, total, err := h.svcs.Legal.History(c.Query("kind"), c.Query("locale"), page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(documents, PaginationMeta(page, limit, total)))
+}
+
+func (h *AdminHandler) GetLegalDocument(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	document, err := h.svcs.Legal.GetByID(id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(document))
+}
+
+// PublishLegalDocument publishes a new version of a document. Unless it is a
+// translation or marked as not requiring consent, every user has to accept it
+// before going on.
+func (h *AdminHandler) PublishLegalDocument(c *gin.Context) {
+	var input services.LegalDocumentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	document, err := h.svcs.Legal.Publish(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(document))
+}
+
+// GetUserConsents returns the document versions a user accepted, with when
+// and from which IP
+func (h *AdminHandler) GetUserConsents(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	consents, err := h.svcs.Consent.History(id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(consents))
+}
+
+// Announcement handlers
+
+func (h *AdminHandler) ListAnnouncements(c *gin.Context) {
+	page, limit := getPagination(c)
+	announcements, total, err := h.svcs.Announcement.List(page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(announcements, PaginationMeta(page, limit, total)))
+}
+
+func (h *AdminHandler) CreateAnnouncement(c *gin.Context) {
+	var input services.AnnouncementInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	announcement, err := h.svcs.Announcement.Create(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(announcement))
+}
+
+func (h *AdminHandler) UpdateAnnouncement(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	var input services.AnnouncementInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	announcement, err := h.svcs.Announcement.Update(id, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(announcement))
+}
+
+func (h *AdminHandler) DeleteAnnouncement(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	if err := h.svcs.Announcement.Delete(id); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Deleted"}))
+}
+
+// Canned response handlers
+
+func (h *AdminHandler) ListCannedResponses(c *gin.Context) {
+	responses, err := h.svcs.Admin.ListCannedResponses(c.Query("category"))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(responses))
+}
+
+func (h *AdminHandler) CreateCannedResponse(c *gin.Context) {
+	var input services.CannedResponseInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	response, err := h.svcs.Admin.CreateCannedResponse(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(response))
+}
+
+func (h *AdminHandler) UpdateCannedResponse(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	var input services.CannedResponseInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	response, err := h.svcs.Admin.UpdateCannedResponse(id, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(response))
+}
+
+func (h *AdminHandler) DeleteCannedResponse(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	if err := h.svcs.Admin.DeleteCannedResponse(id); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Deleted"}))
 }
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/services"
+)
+
+// AnnouncementHandler serves announcement banners to the apps
+type AnnouncementHandler struct {
+	svcs *services.Services
+}
+
+// NewAnnouncementHandler creates a new announcement handler
+func NewAnnouncementHandler(svcs *services.Services) *AnnouncementHandler {
+	return &AnnouncementHandler{svcs: svcs}
+}
+
+// Active returns the announcements the current user should see
+func (h *AnnouncementHandler) Active(c *gin.Context) {
+	announcements, err := h.svcs.Announcement.Active(getUserID(c), c.GetString("role"))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(announcements))
+}
+
+// Dismiss hides an announcement for the current user
+func (h *AnnouncementHandler) Dismiss(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid announcement ID"))
+		return
+	}
+
+	if err := h.svcs.Announcement.Dismiss(id, getUserID(c)); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Dismissed"}))
+}
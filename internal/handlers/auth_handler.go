@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yandas/backend/internal/services"
+	"github.com/yandas/backend/pkg/mail"
+	"github.com/yandas/backend/pkg/sms"
 )
 
 // AuthHandler handles authentication endpoints
@@ -16,6 +20,28 @@ func NewAuthHandler(svcs *services.Services) *AuthHandler {
 	return &AuthHandler{svcs: svcs}
 }
 
+// otpLimitError answers 429 with the retry-after time when err is a refused OTP
+// send or check, and reports whether it did
+func otpLimitError(c *gin.Context, err error) bool {
+	var limitErr *services.OTPLimitError
+	if !errors.As(err, &limitErr) {
+		return false
+	}
+	seconds := limitErr.RetrySeconds()
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, Response{
+		Success: false,
+		Error:   err.Error(),
+		Data:    gin.H{"code": limitErr.Reason, "retry_after": seconds},
+	})
+	return true
+}
+
+// clientInfo captures the device details recorded on the session
+func clientInfo(c *gin.Context) services.ClientInfo {
+	return services.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
 // Register godoc
 // @Summary Register a new user
 // @Tags Auth
@@ -28,7 +54,7 @@ func NewAuthHandler(svcs *services.Services) *AuthHandler {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var input services.RegisterInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 
@@ -36,10 +62,11 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	if input.Platform == "" {
 		input.Platform = "unknown"
 	}
+	input.Client = clientInfo(c)
 
 	user, tokens, err := h.svcs.Auth.Register(&input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -62,7 +89,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var input services.LoginInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 
@@ -70,10 +97,11 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	if input.Platform == "" {
 		input.Platform = "unknown"
 	}
+	input.Client = clientInfo(c)
 
 	user, tokens, err := h.svcs.Auth.Login(&input)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -81,6 +109,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		"user":               user,
 		"tokens":             tokens,
 		"needs_verification": !user.IsVerified,
+		"needs_consent":      h.svcs.Consent.NeedsConsent(user.ID),
 	}))
 }
 
@@ -98,14 +127,14 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		RefreshToken string `json:"refresh_token" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 
 	platform := c.GetHeader("X-Platform")
-	tokens, err := h.svcs.Auth.RefreshToken(input.RefreshToken, platform)
+	tokens, err := h.svcs.Auth.RefreshToken(input.RefreshToken, platform, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -125,7 +154,7 @@ func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 		Email string `json:"email" binding:"required,email"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 
@@ -152,12 +181,12 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 		NewPassword string `json:"new_password" binding:"required,min=6"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 
 	if err := h.svcs.Auth.ResetPassword(input.Token, input.NewPassword); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -174,19 +203,20 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 // @Param body body map[string]string true "Phone and OTP"
 // @Success 200 {object} Response
 // @Failure 400 {object} Response
+// @Failure 429 {object} Response
 // @Router /auth/verify-phone [post]
 func (h *AuthHandler) VerifyPhone(c *gin.Context) {
 	var input struct {
-		Phone string `json:"phone" binding:"required"`
+		Phone string `json:"phone" binding:"required,tr_phone"`
 		OTP   string `json:"otp" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 
 	if err := h.svcs.Auth.VerifyOTP(input.Phone, input.OTP); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -202,17 +232,21 @@ func (h *AuthHandler) VerifyPhone(c *gin.Context) {
 // @Produce json
 // @Param body body map[string]string true "Phone"
 // @Success 200 {object} Response
+// @Failure 429 {object} Response
 // @Router /auth/resend-otp [post]
 func (h *AuthHandler) ResendOTP(c *gin.Context) {
 	var input struct {
-		Phone string `json:"phone" binding:"required"`
+		Phone string `json:"phone" binding:"required,tr_phone"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 
-	h.svcs.Auth.SendOTP(input.Phone)
+	// Delivery failures are not reported, only refused resends
+	if err := h.svcs.Auth.SendOTP(input.Phone); otpLimitError(c, err) {
+		return
+	}
 
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{
 		"message": "OTP sent",
@@ -232,16 +266,16 @@ func (h *AuthHandler) VerifyAccount(c *gin.Context) {
 	var input struct {
 		Email    string `json:"email" binding:"required"`
 		EmailOTP string `json:"email_otp" binding:"required"`
-		Phone    string `json:"phone"`
+		Phone    string `json:"phone" binding:"omitempty,tr_phone"`
 		PhoneOTP string `json:"phone_otp"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 
 	if err := h.svcs.Auth.VerifyAccount(input.Email, input.EmailOTP, input.Phone, input.PhoneOTP); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -264,12 +298,12 @@ func (h *AuthHandler) ResendEmailOTP(c *gin.Context) {
 		Email string `json:"email" binding:"required,email"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 
 	if err := h.svcs.Auth.ResendEmailOTP(input.Email); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -277,3 +311,29 @@ func (h *AuthHandler) ResendEmailOTP(c *gin.Context) {
 		"message": "Doğrulama kodu e-postanıza gönderildi",
 	}))
 }
+
+// SMSStatus records a delivery report posted by an SMS provider
+func (h *AuthHandler) SMSStatus(c *gin.Context) {
+	err := h.svcs.SMS.HandleStatus(c.Param("provider"), c.Request)
+	switch {
+	case errors.Is(err, sms.ErrInvalidCallback):
+		c.JSON(http.StatusForbidden, ErrorResponse(err.Error()))
+	case err != nil:
+		serviceError(c, err)
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// EmailEvents records bounces and complaints posted by the email provider
+func (h *AuthHandler) EmailEvents(c *gin.Context) {
+	err := h.svcs.Email.HandleEvents(c.Param("provider"), c.Request)
+	switch {
+	case errors.Is(err, mail.ErrInvalidWebhook):
+		c.JSON(http.StatusForbidden, ErrorResponse(err.Error()))
+	case err != nil:
+		serviceError(c, err)
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
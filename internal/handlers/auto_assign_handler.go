@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/services"
+)
+
+// AutoAssignHandler serves urgent orders matched to the nearest available yandaş
+type AutoAssignHandler struct {
+	svcs *services.Services
+}
+
+// NewAutoAssignHandler creates a new auto-assign handler
+func NewAutoAssignHandler(svcs *services.Services) *AutoAssignHandler {
+	return &AutoAssignHandler{svcs: svcs}
+}
+
+// Start begins searching for the nearest yandaş to take an urgent order
+func (h *AutoAssignHandler) Start(c *gin.Context) {
+	var input services.AutoAssignInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	assignment, err := h.svcs.AutoAssign.Start(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(assignment))
+}
+
+// Get returns the search's progress; once assigned it carries the order ID
+func (h *AutoAssignHandler) Get(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid assignment ID"))
+		return
+	}
+
+	assignment, err := h.svcs.AutoAssign.Get(id, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(assignment))
+}
+
+// Cancel stops the search
+func (h *AutoAssignHandler) Cancel(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid assignment ID"))
+		return
+	}
+
+	if err := h.svcs.AutoAssign.Cancel(id, getUserID(c)); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Search cancelled"}))
+}
+
+// Accept takes the offer held by the current yandaş
+func (h *AutoAssignHandler) Accept(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid assignment ID"))
+		return
+	}
+
+	order, err := h.svcs.AutoAssign.Accept(getUserID(c), id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(order))
+}
+
+// Decline passes the offer on to the next candidate
+func (h *AutoAssignHandler) Decline(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid assignment ID"))
+		return
+	}
+
+	if err := h.svcs.AutoAssign.Decline(getUserID(c), id); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Offer declined"}))
+}
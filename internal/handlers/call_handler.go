@@ -8,6 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/yandas/backend/internal/config"
 	"github.com/yandas/backend/internal/models"
 	"github.com/yandas/backend/internal/services"
@@ -16,15 +17,31 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	// recorderUID is the Agora uid the cloud recorder joins the channel with
+	recorderUID = 9000
+	// callTokenTTL is how long an RTC token is valid, in seconds; long calls refresh it
+	callTokenTTL = 3600
+)
+
 type CallHandler struct {
-	svcs  *services.Services
-	wsHub *websocket.Hub
-	cfg   *config.Config
-	db    *gorm.DB
+	svcs     *services.Services
+	wsHub    *websocket.Hub
+	cfg      *config.Config
+	db       *gorm.DB
+	recorder *agora.RecordingClient
 }
 
 func NewCallHandler(svcs *services.Services, wsHub *websocket.Hub, cfg *config.Config, db *gorm.DB) *CallHandler {
-	return &CallHandler{svcs: svcs, wsHub: wsHub, cfg: cfg, db: db}
+	recorder := agora.NewRecordingClient(cfg.AgoraAppID, cfg.AgoraCustomerID, cfg.AgoraCustomerSecret, agora.StorageConfig{
+		Vendor:         cfg.AgoraRecordingVendor,
+		Region:         cfg.AgoraRecordingRegion,
+		Bucket:         cfg.AgoraRecordingBucket,
+		AccessKey:      cfg.AgoraRecordingAccessKey,
+		SecretKey:      cfg.AgoraRecordingSecretKey,
+		FileNamePrefix: []string{"calls"},
+	})
+	return &CallHandler{svcs: svcs, wsHub: wsHub, cfg: cfg, db: db, recorder: recorder}
 }
 
 // InitiateCall starts a new call
@@ -35,10 +52,11 @@ func (h *CallHandler) InitiateCall(c *gin.Context) {
 	var input struct {
 		ReceiverID string `json:"receiver_id" binding:"required"`
 		CallType   string `json:"call_type" binding:"required"` // "audio" or "video"
+		Record     bool   `json:"record"`                       // caller requests recording and consents to it
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
 		log.Printf("[CALL] InitiateCall: bind error: %v", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse("receiver_id and call_type are required"))
+		bindError(c, err)
 		return
 	}
 
@@ -64,8 +82,8 @@ func (h *CallHandler) InitiateCall(c *gin.Context) {
 		h.cfg.AgoraAppID,
 		h.cfg.AgoraAppCertificate,
 		channelName,
-		1,    // caller UID
-		3600, // 1 hour expiry
+		1, // caller UID
+		callTokenTTL,
 	)
 	if err != nil {
 		log.Printf("[CALL] InitiateCall: token generation error: %v", err)
@@ -73,14 +91,21 @@ func (h *CallHandler) InitiateCall(c *gin.Context) {
 		return
 	}
 
+	// Marked missed by the scheduler if nobody picks up in time
+	ringExpiresAt := time.Now().Add(h.cfg.CallRingTimeout)
+
 	// Create call log
 	callLog := &models.CallLog{
-		ID:        uuid.New(),
-		CallerID:  callerID,
-		CalleeID:  receiverID,
-		CallType:  input.CallType,
-		Status:    "ringing",
-		ChannelID: &channelName,
+		ID:                 uuid.New(),
+		CallerID:           callerID,
+		CalleeID:           receiverID,
+		CallType:           input.CallType,
+		Status:             "ringing",
+		ChannelID:          &channelName,
+		RingExpiresAt:      &ringExpiresAt,
+		RecordingRequested: input.Record,
+		CallerConsent:      input.Record,
+		RecordingStatus:    "none",
 	}
 
 	if err := h.db.Create(callLog).Error; err != nil {
@@ -103,9 +128,14 @@ func (h *CallHandler) InitiateCall(c *gin.Context) {
 		"caller_avatar": caller.AvatarURL,
 		"call_type":     input.CallType,
 		"channel_name":  channelName,
+		// Receiver must send record_consent on answer for recording to start
+		"recording_requested": input.Record,
 	})
 	log.Printf("[CALL] InitiateCall: incoming_call broadcast DONE")
 
+	// Ring backgrounded apps too
+	go h.svcs.Call.PushIncomingCall(callLog, &caller, h.cfg.CallRingTimeout)
+
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{
 		"call_id":      callLog.ID.String(),
 		"channel_name": channelName,
@@ -120,6 +150,17 @@ func (h *CallHandler) AnswerCall(c *gin.Context) {
 	callID, _ := uuid.Parse(c.Param("id"))
 	userID := getUserID(c)
 
+	var input struct {
+		RecordConsent bool `json:"record_consent"`
+	}
+	// The body is optional; without one the callee doesn't consent to recording
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			bindError(c, err)
+			return
+		}
+	}
+
 	var callLog models.CallLog
 	if err := h.db.First(&callLog, "id = ? AND callee_id = ?", callID, userID).Error; err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse("call not found"))
@@ -137,19 +178,31 @@ func (h *CallHandler) AnswerCall(c *gin.Context) {
 		h.cfg.AgoraAppCertificate,
 		*callLog.ChannelID,
 		2, // callee UID
-		3600,
+		callTokenTTL,
 	)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse("failed to generate token"))
 		return
 	}
 
+	// Start cloud recording only when it was requested and both parties consented
+	record := callLog.RecordingRequested && callLog.CallerConsent && input.RecordConsent
+
 	// Update call status
 	now := time.Now()
-	h.db.Model(&callLog).Updates(map[string]interface{}{
-		"status":      "answered",
-		"answered_at": now,
-	})
+	updates := map[string]interface{}{
+		"status":         "answered",
+		"answered_at":    now,
+		"callee_consent": input.RecordConsent,
+	}
+	if record {
+		updates["recording_status"] = "starting"
+	}
+	h.db.Model(&callLog).Updates(updates)
+
+	if record {
+		go h.startRecording(callLog.ID, *callLog.ChannelID)
+	}
 
 	// Notify caller that call was answered
 	h.wsHub.BroadcastToUser(callLog.CallerID.String(), "call_answered", map[string]interface{}{
@@ -187,6 +240,8 @@ func (h *CallHandler) RejectCall(c *gin.Context) {
 		"call_id": callLog.ID.String(),
 	})
 
+	go h.postCallSummary(callLog.ID)
+
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Call rejected"}))
 }
 
@@ -213,16 +268,320 @@ func (h *CallHandler) EndCall(c *gin.Context) {
 		"duration": duration,
 	})
 
+	// A recording still starting is stopped by startRecording as soon as it begins
+	stopping := h.db.Model(&models.CallLog{}).
+		Where("id = ? AND recording_status IN ?", callLog.ID, []string{"starting", "recording"}).
+		Update("recording_status", "stopping")
+	if stopping.Error == nil && stopping.RowsAffected > 0 {
+		go h.stopStartedRecording(callLog.ID)
+	}
+
 	// Notify the other party
 	otherUserID := callLog.CallerID.String()
 	if callLog.CallerID == userID {
 		otherUserID = callLog.CalleeID.String()
 	}
 
-	h.wsHub.BroadcastToUser(otherUserID, "call_ended", map[string]interface{}{
+	ended := map[string]interface{}{
 		"call_id":  callLog.ID.String(),
 		"duration": duration,
-	})
+	}
+	h.wsHub.BroadcastToUser(otherUserID, "call_ended", ended)
+
+	// Mediators in the call hang up with it
+	participants, _ := h.svcs.Call.Participants(callLog.ID)
+	for _, participant := range participants {
+		h.wsHub.BroadcastToUser(participant.UserID.String(), "call_ended", ended)
+	}
+
+	go h.postCallSummary(callLog.ID)
 
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Call ended", "duration": duration}))
 }
+
+// RefreshToken issues a fresh RTC token for the same channel and uid so calls can outlast the token expiry
+func (h *CallHandler) RefreshToken(c *gin.Context) {
+	callID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid call ID"))
+		return
+	}
+	userID := getUserID(c)
+
+	var callLog models.CallLog
+	if err := h.db.First(&callLog, "id = ?", callID).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse("call not found"))
+		return
+	}
+
+	// Same uids as InitiateCall, AnswerCall and JoinCall hand out
+	var uid uint32
+	switch userID {
+	case callLog.CallerID:
+		uid = 1
+	case callLog.CalleeID:
+		uid = 2
+	default:
+		participant, err := h.svcs.Call.Participant(callID, userID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, ErrorResponse("call not found"))
+			return
+		}
+		uid = uint32(participant.UID)
+	}
+
+	if callLog.Status != "answered" || callLog.ChannelID == nil {
+		c.JSON(http.StatusConflict, ErrorResponse("call is not active"))
+		return
+	}
+
+	token, err := agora.GenerateRTCToken(h.cfg.AgoraAppID, h.cfg.AgoraAppCertificate, *callLog.ChannelID, uid, callTokenTTL)
+	if err != nil {
+		log.Printf("[CALL] RefreshToken: token generation error for call=%s: %v", callID.String(), err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse("failed to generate call token"))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{
+		"call_id":      callLog.ID.String(),
+		"channel_name": *callLog.ChannelID,
+		"token":        token,
+		"uid":          uid,
+		"app_id":       h.cfg.AgoraAppID,
+		"expires_at":   time.Now().Add(callTokenTTL * time.Second),
+	}))
+}
+
+// Delivered lets the callee's app report that it started ringing, and whether push or WebSocket got there
+func (h *CallHandler) Delivered(c *gin.Context) {
+	callID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid call ID"))
+		return
+	}
+	var input struct {
+		Via string `json:"via" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	if err := h.svcs.Call.MarkDelivered(callID, getUserID(c), input.Via); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Delivery recorded"}))
+}
+
+// ActiveCalls lists ongoing calls staff can join
+func (h *CallHandler) ActiveCalls(c *gin.Context) {
+	page, limit := getPagination(c)
+	calls, total, err := h.svcs.Call.ActiveCalls(page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(calls, PaginationMeta(page, limit, total)))
+}
+
+// JoinCall lets a support agent or admin join an ongoing call to mediate a dispute
+func (h *CallHandler) JoinCall(c *gin.Context) {
+	callID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid call ID"))
+		return
+	}
+	userID := getUserID(c)
+
+	callLog, participant, err := h.svcs.Call.JoinCall(callID, userID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	token, err := agora.GenerateRTCToken(h.cfg.AgoraAppID, h.cfg.AgoraAppCertificate, *callLog.ChannelID, uint32(participant.UID), callTokenTTL)
+	if err != nil {
+		log.Printf("[CALL] JoinCall: token generation error for call=%s: %v", callID.String(), err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse("failed to generate call token"))
+		return
+	}
+
+	var user models.User
+	h.db.First(&user, "id = ?", userID)
+	h.notifyCallParties(callLog, userID, "call_participant_joined", map[string]interface{}{
+		"call_id": callLog.ID.String(),
+		"user_id": userID.String(),
+		"name":    user.FullName,
+		"uid":     participant.UID,
+		"role":    participant.Role,
+	})
+
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{
+		"call_id":      callLog.ID.String(),
+		"channel_name": *callLog.ChannelID,
+		"token":        token,
+		"uid":          participant.UID,
+		"app_id":       h.cfg.AgoraAppID,
+	}))
+}
+
+// LeaveCall takes a mediator out of a call
+func (h *CallHandler) LeaveCall(c *gin.Context) {
+	callID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid call ID"))
+		return
+	}
+	userID := getUserID(c)
+
+	callLog, err := h.svcs.Call.LeaveCall(callID, userID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	h.notifyCallParties(callLog, userID, "call_participant_left", map[string]interface{}{
+		"call_id": callLog.ID.String(),
+		"user_id": userID.String(),
+	})
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Left the call"}))
+}
+
+// notifyCallParties sends an event to the caller, the callee and the other participants still in the call
+func (h *CallHandler) notifyCallParties(callLog *models.CallLog, except uuid.UUID, event string, payload map[string]interface{}) {
+	recipients := []uuid.UUID{callLog.CallerID, callLog.CalleeID}
+	participants, _ := h.svcs.Call.Participants(callLog.ID)
+	for _, participant := range participants {
+		recipients = append(recipients, participant.UserID)
+	}
+	for _, id := range recipients {
+		if id != except {
+			h.wsHub.BroadcastToUser(id.String(), event, payload)
+		}
+	}
+}
+
+// Feedback stores the current user's quality rating of an ended call
+func (h *CallHandler) Feedback(c *gin.Context) {
+	callID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid call ID"))
+		return
+	}
+	var input services.CallFeedbackInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	if err := h.svcs.Call.SubmitFeedback(callID, getUserID(c), &input); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Feedback received"}))
+}
+
+// History returns the user's call history
+func (h *CallHandler) History(c *gin.Context) {
+	page, limit := getPagination(c)
+	filter := c.Query("filter")
+	if filter != "" && filter != "missed" && filter != "answered" {
+		c.JSON(http.StatusBadRequest, ErrorResponse("filter must be 'missed' or 'answered'"))
+		return
+	}
+
+	calls, total, err := h.svcs.Call.History(getUserID(c), page, limit, filter)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(calls, PaginationMeta(page, limit, total)))
+}
+
+// postCallSummary writes the call outcome into the participants' conversation
+func (h *CallHandler) postCallSummary(callID uuid.UUID) {
+	if _, err := h.svcs.Call.PostCallSummary(callID); err != nil {
+		log.Printf("[CALL] Call summary error for call=%s: %v", callID.String(), err)
+	}
+}
+
+// startRecording starts Agora cloud recording for an answered call. The call
+// can end while Agora is still starting it: EndCall then moves the recording
+// from starting to stopping, and it is stopped here as soon as it begins.
+func (h *CallHandler) startRecording(callID uuid.UUID, channelName string) {
+	if !h.cfg.AgoraRecordingEnabled || !h.recorder.Enabled() {
+		log.Printf("[CALL] Recording requested for call=%s but cloud recording is not configured", callID.String())
+		h.abandonRecording(callID, "none")
+		return
+	}
+
+	token, err := agora.GenerateRTCToken(h.cfg.AgoraAppID, h.cfg.AgoraAppCertificate, channelName, recorderUID, callTokenTTL)
+	if err != nil {
+		log.Printf("[CALL] Recording token error for call=%s: %v", callID.String(), err)
+		h.abandonRecording(callID, "failed")
+		return
+	}
+
+	session, err := h.recorder.Start(channelName, recorderUID, token)
+	if err != nil {
+		log.Printf("[CALL] Recording start error for call=%s: %v", callID.String(), err)
+		h.abandonRecording(callID, "failed")
+		return
+	}
+
+	started := h.db.Model(&models.CallLog{}).Where("id = ? AND recording_status = ?", callID, "starting").Updates(map[string]interface{}{
+		"recording_status":      "recording",
+		"recording_resource_id": session.ResourceID,
+		"recording_s_id":        session.SID,
+	})
+	if started.Error != nil || started.RowsAffected == 0 {
+		// Either the call ended meanwhile or the session can't be saved for EndCall to stop
+		log.Printf("[CALL] Recording of call=%s can't continue, stopping it: %v", callID.String(), started.Error)
+		h.stopRecording(callID, channelName, *session)
+		return
+	}
+	log.Printf("[CALL] Recording started for call=%s sid=%s", callID.String(), session.SID)
+}
+
+// abandonRecording records that a recording never started
+func (h *CallHandler) abandonRecording(callID uuid.UUID, status string) {
+	h.db.Model(&models.CallLog{}).
+		Where("id = ? AND recording_status IN ?", callID, []string{"starting", "stopping"}).
+		Update("recording_status", status)
+}
+
+// stopStartedRecording stops the recording of a call that has ended. A
+// recording that hadn't begun yet has no session and is left to startRecording.
+func (h *CallHandler) stopStartedRecording(callID uuid.UUID) {
+	var callLog models.CallLog
+	if err := h.db.First(&callLog, "id = ?", callID).Error; err != nil {
+		log.Printf("[CALL] Recording stop error for call=%s: %v", callID.String(), err)
+		return
+	}
+	if callLog.RecordingStatus != "stopping" || callLog.RecordingResourceID == nil || callLog.RecordingSID == nil || callLog.ChannelID == nil {
+		return
+	}
+	h.stopRecording(callID, *callLog.ChannelID, agora.RecordingSession{
+		ResourceID: *callLog.RecordingResourceID,
+		SID:        *callLog.RecordingSID,
+	})
+}
+
+// stopRecording stops cloud recording and stores the uploaded file references
+func (h *CallHandler) stopRecording(callID uuid.UUID, channelName string, session agora.RecordingSession) {
+	files, err := h.recorder.Stop(channelName, recorderUID, session)
+	if err != nil {
+		log.Printf("[CALL] Recording stop error for call=%s: %v", callID.String(), err)
+		h.db.Model(&models.CallLog{}).Where("id = ?", callID).Update("recording_status", "failed")
+		return
+	}
+
+	h.db.Model(&models.CallLog{}).Where("id = ?", callID).Updates(map[string]interface{}{
+		"recording_status":      "stopped",
+		"recording_resource_id": session.ResourceID,
+		"recording_s_id":        session.SID,
+		"recording_files":       pq.StringArray(files),
+	})
+	log.Printf("[CALL] Recording stopped for call=%s files=%d", callID.String(), len(files))
+}
@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/services"
 	"github.com/yandas/backend/internal/websocket"
+	"github.com/yandas/backend/pkg/media"
 )
 
 type ChatHandler struct {
@@ -28,15 +31,41 @@ func (h *ChatHandler) GetConversation(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
 	conv, err := h.svcs.Chat.GetConversation(getUserID(c), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(conv))
 }
 
+// SearchConversations finds the user's conversations by message content and
+// participant name. When messages are encrypted at rest their content can't be
+// searched; meta.content_search is false so clients can say only names matched.
+func (h *ChatHandler) SearchConversations(c *gin.Context) {
+	page, limit := getPagination(c)
+	results, total, err := h.svcs.Chat.SearchConversations(getUserID(c), c.Query("q"), page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	meta := PaginationMeta(page, limit, total)
+	contentSearch := h.svcs.Chat.ContentSearchable()
+	meta.ContentSearch = &contentSearch
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(results, meta))
+}
+
 func (h *ChatHandler) GetMessages(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
 	page, limit := getPagination(c)
+	// A cursor from a search result opens the conversation at the matched message
+	if cursor := c.Query("cursor"); cursor != "" {
+		msgs, next, err := h.svcs.Chat.GetMessagesFrom(getUserID(c), id, cursor, limit)
+		if err != nil {
+			serviceError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, SuccessResponseWithMeta(msgs, &Meta{Limit: limit, NextCursor: next}))
+		return
+	}
 	msgs, total, _ := h.svcs.Chat.GetMessages(getUserID(c), id, page, limit)
 	c.JSON(http.StatusOK, SuccessResponseWithMeta(msgs, PaginationMeta(page, limit, total)))
 }
@@ -45,22 +74,23 @@ func (h *ChatHandler) SendMessage(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
 	var input services.SendMessageInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 	msg, err := h.svcs.Chat.SendMessage(getUserID(c), id, &input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
-	// Broadcast via WebSocket
-	h.wsHub.BroadcastToConversation(id.String(), msg)
 	c.JSON(http.StatusCreated, SuccessResponse(msg))
 }
 
 func (h *ChatHandler) MarkAsRead(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
-	h.svcs.Chat.MarkAsRead(getUserID(c), id)
+	if err := h.svcs.Chat.MarkAsRead(getUserID(c), id); err != nil {
+		serviceError(c, err)
+		return
+	}
 
 	// Broadcast read receipt via WebSocket
 	h.wsHub.BroadcastToConversation(id.String(), map[string]interface{}{
@@ -92,22 +122,107 @@ func (h *ChatHandler) SendImageMessage(c *gin.Context) {
 
 	msg, err := h.svcs.Chat.SendMessage(userID, convID, input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
-	// Broadcast via WebSocket
-	h.wsHub.BroadcastToConversation(convID.String(), msg)
 	c.JSON(http.StatusCreated, SuccessResponse(msg))
 }
 
+// SendAudioMessage handles a voice note upload with its duration in seconds
+func (h *ChatHandler) SendAudioMessage(c *gin.Context) {
+	convID, _ := uuid.Parse(c.Param("id"))
+	userID := getUserID(c)
+
+	file, err := c.FormFile("audio")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("audio file required"))
+		return
+	}
+	duration, err := strconv.Atoi(c.PostForm("duration"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("duration in seconds required"))
+		return
+	}
+
+	msg, err := h.svcs.Chat.SendAudioMessage(userID, convID, file, duration)
+	switch {
+	case errors.Is(err, media.ErrAudioTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse(err.Error()))
+		return
+	case errors.Is(err, services.ErrAudioProcessing):
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse(err.Error()))
+		return
+	case err != nil:
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse(msg))
+}
+
+// SendLocationMessage shares a location, optionally live for a number of minutes
+func (h *ChatHandler) SendLocationMessage(c *gin.Context) {
+	convID, _ := uuid.Parse(c.Param("id"))
+	var input services.LocationMessageInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	msg, err := h.svcs.Chat.SendLocationMessage(getUserID(c), convID, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse(msg))
+}
+
+// UpdateLiveLocation moves a live location the current user is sharing
+func (h *ChatHandler) UpdateLiveLocation(c *gin.Context) {
+	convID, _ := uuid.Parse(c.Param("id"))
+	messageID, err := uuid.Parse(c.Param("messageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid message ID"))
+		return
+	}
+	var input services.LiveLocationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	if err := h.svcs.Chat.UpdateLiveLocation(getUserID(c), convID, messageID, &input); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Location updated"}))
+}
+
+// StopLiveLocation ends a live location the current user is sharing
+func (h *ChatHandler) StopLiveLocation(c *gin.Context) {
+	convID, _ := uuid.Parse(c.Param("id"))
+	messageID, err := uuid.Parse(c.Param("messageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid message ID"))
+		return
+	}
+
+	if err := h.svcs.Chat.StopLiveLocation(getUserID(c), convID, messageID); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Live location stopped"}))
+}
+
 // StartConversation starts a new chat conversation with a yandaş
 func (h *ChatHandler) StartConversation(c *gin.Context) {
 	var input struct {
 		YandasUserID string `json:"yandas_user_id" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse("yandas_user_id is required"))
+		bindError(c, err)
 		return
 	}
 
@@ -119,7 +234,7 @@ func (h *ChatHandler) StartConversation(c *gin.Context) {
 
 	conv, err := h.svcs.Chat.StartConversation(getUserID(c), yandasUserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -135,23 +250,18 @@ func NewSubscriptionHandler(svcs *services.Services) *SubscriptionHandler {
 }
 
 func (h *SubscriptionHandler) Get(c *gin.Context) {
-	sub, err := h.svcs.Subscription.Get(getUserID(c))
-	if err != nil {
-		c.JSON(http.StatusOK, SuccessResponse(nil))
-		return
-	}
-	c.JSON(http.StatusOK, SuccessResponse(sub))
+	c.JSON(http.StatusOK, SuccessResponse(h.svcs.Subscription.Status(getUserID(c))))
 }
 
 func (h *SubscriptionHandler) Verify(c *gin.Context) {
 	var input services.VerifyInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 	sub, err := h.svcs.Subscription.Verify(getUserID(c), &input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(sub))
@@ -179,11 +289,17 @@ func (h *NotificationHandler) List(c *gin.Context) {
 
 func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
-	h.svcs.Notification.MarkAsRead(id)
+	if err := h.svcs.Notification.MarkAsRead(id); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Marked"}))
 }
 
 func (h *NotificationHandler) MarkAllAsRead(c *gin.Context) {
-	h.svcs.Notification.MarkAllAsRead(getUserID(c))
+	if err := h.svcs.Notification.MarkAllAsRead(getUserID(c)); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "All marked"}))
 }
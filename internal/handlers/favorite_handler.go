@@ -28,7 +28,7 @@ func (h *FavoriteHandler) Toggle(c *gin.Context) {
 
 	added, err := h.svcs.Favorite.Toggle(getUserID(c), yandasID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -42,13 +42,25 @@ func (h *FavoriteHandler) List(c *gin.Context) {
 	page, limit := getPagination(c)
 	favs, total, err := h.svcs.Favorite.List(getUserID(c), page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, SuccessResponseWithMeta(favs, PaginationMeta(page, limit, total)))
 }
 
+// Feed returns recent activity of the user's favorited yandaşlar
+func (h *FavoriteHandler) Feed(c *gin.Context) {
+	page, limit := getPagination(c)
+	activities, total, err := h.svcs.Favorite.Feed(getUserID(c), page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(activities, PaginationMeta(page, limit, total)))
+}
+
 // Check checks if a yandaş is favorited
 func (h *FavoriteHandler) Check(c *gin.Context) {
 	yandasID, err := uuid.Parse(c.Param("id"))
@@ -67,7 +79,7 @@ func (h *FavoriteHandler) Check(c *gin.Context) {
 func (h *FavoriteHandler) IDs(c *gin.Context) {
 	ids, err := h.svcs.Favorite.GetFavoriteIDs(getUserID(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
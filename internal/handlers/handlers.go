@@ -1,9 +1,17 @@
 package handlers
 
 import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
 	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/repository"
 	"github.com/yandas/backend/internal/services"
+	"github.com/yandas/backend/internal/validation"
 	"github.com/yandas/backend/internal/websocket"
+	"github.com/yandas/backend/pkg/currency"
 	"gorm.io/gorm"
 )
 
@@ -23,10 +31,17 @@ type Handlers struct {
 	Favorite     *FavoriteHandler
 	Support      *SupportHandler
 	Search       *SearchHandler
+	Announcement *AnnouncementHandler
+	JobRequest   *JobRequestHandler
+	AutoAssign   *AutoAssignHandler
+	SEO          *SEOHandler
+	Tracking     *TrackingHandler
+	Health       *HealthHandler
 }
 
 // NewHandlers creates all handlers
 func NewHandlers(svcs *services.Services, cfg *config.Config, wsHub *websocket.Hub, db *gorm.DB) *Handlers {
+	validation.Register()
 	return &Handlers{
 		Auth:         NewAuthHandler(svcs),
 		User:         NewUserHandler(svcs),
@@ -38,19 +53,27 @@ func NewHandlers(svcs *services.Services, cfg *config.Config, wsHub *websocket.H
 		Subscription: NewSubscriptionHandler(svcs),
 		Notification: NewNotificationHandler(svcs),
 		Admin:        NewAdminHandler(svcs),
-		Legal:        NewLegalHandler(cfg),
+		Legal:        NewLegalHandler(svcs, cfg),
 		Favorite:     NewFavoriteHandler(svcs),
 		Support:      NewSupportHandler(svcs),
 		Search:       NewSearchHandler(svcs),
+		Announcement: NewAnnouncementHandler(svcs),
+		JobRequest:   NewJobRequestHandler(svcs),
+		AutoAssign:   NewAutoAssignHandler(svcs),
+		SEO:          NewSEOHandler(svcs),
+		Tracking:     NewTrackingHandler(svcs),
+		Health:       NewHealthHandler(svcs),
 	}
 }
 
 // Response helpers
 type Response struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
-	Meta    *Meta       `json:"meta,omitempty"`
+	Success bool                    `json:"success"`
+	Data    interface{}             `json:"data,omitempty"`
+	Error   string                  `json:"error,omitempty"`
+	Code    string                  `json:"code,omitempty"`
+	Errors  []validation.FieldError `json:"errors,omitempty"`
+	Meta    *Meta                   `json:"meta,omitempty"`
 }
 
 type Meta struct {
@@ -58,6 +81,12 @@ type Meta struct {
 	Limit      int   `json:"limit"`
 	Total      int64 `json:"total"`
 	TotalPages int64 `json:"total_pages"`
+
+	NextCursor    string `json:"next_cursor,omitempty"`    // messages opened at a cursor only
+	ContentSearch *bool  `json:"content_search,omitempty"` // conversation search only: false when message content is encrypted and not searched
+
+	TotalRevenue *currency.Money          `json:"total_revenue,omitempty"` // admin order list only, in the base currency
+	Revenue      *services.RevenueSummary `json:"revenue,omitempty"`       // admin order list only
 }
 
 func SuccessResponse(data interface{}) Response {
@@ -72,6 +101,57 @@ func ErrorResponse(err string) Response {
 	return Response{Success: false, Error: err}
 }
 
+// ValidationErrorResponse describes a request that failed binding, field by field
+func ValidationErrorResponse(err error) Response {
+	errs := validation.Errors(err)
+	return Response{Success: false, Error: validation.Message(errs), Code: validation.CodeValidationFailed, Errors: errs}
+}
+
+// bindError answers a request whose body or query failed to bind
+func bindError(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, ValidationErrorResponse(err))
+}
+
+// serviceError answers a request whose service call failed. Domain errors get
+// the status of their kind and keep their code; anything else is logged and
+// reported as an internal error so its details don't reach the client.
+func serviceError(c *gin.Context, err error) {
+	if otpLimitError(c, err) {
+		return
+	}
+
+	var domainErr *services.DomainError
+	switch {
+	case errors.As(err, &domainErr):
+		c.JSON(domainStatus(domainErr.Kind), Response{Success: false, Error: err.Error(), Code: domainErr.Code})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, Response{Success: false, Error: "not found", Code: "not_found"})
+	case errors.Is(err, repository.ErrStaleVersion):
+		// The client edited an old copy; it should refetch and reapply its change
+		c.JSON(http.StatusConflict, Response{Success: false, Error: err.Error(), Code: "stale_version"})
+	default:
+		log.Printf("%s %s: %v", c.Request.Method, c.FullPath(), err)
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Error: "internal server error", Code: "internal_error"})
+	}
+}
+
+// domainStatus maps a domain error kind to its HTTP status
+func domainStatus(kind error) int {
+	switch kind {
+	case services.ErrNotFound:
+		return http.StatusNotFound
+	case services.ErrUnauthenticated:
+		return http.StatusUnauthorized
+	case services.ErrUnauthorized:
+		return http.StatusForbidden
+	case services.ErrConflict:
+		return http.StatusConflict
+	case services.ErrUnavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusBadRequest
+}
+
 func PaginationMeta(page, limit int, total int64) *Meta {
 	totalPages := total / int64(limit)
 	if total%int64(limit) > 0 {
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yandas/backend/internal/services"
+)
+
+// HealthHandler serves the public liveness check and the admin health dashboard
+type HealthHandler struct {
+	svcs *services.Services
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(svcs *services.Services) *HealthHandler {
+	return &HealthHandler{svcs: svcs}
+}
+
+// Health is the minimal public check for load balancers; it answers 503
+// when the database is unreachable and reveals nothing else
+func (h *HealthHandler) Health(c *gin.Context) {
+	if !h.svcs.Health.Live(c.Request.Context()) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": services.HealthDown, "version": services.Version})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": services.HealthOK, "version": services.Version})
+}
+
+// Report returns the status of every dependency for the admin dashboard
+func (h *HealthHandler) Report(c *gin.Context) {
+	c.JSON(http.StatusOK, SuccessResponse(h.svcs.Health.Report(c.Request.Context())))
+}
+
+// Backups reports when the database was last backed up, with the latest runs
+func (h *HealthHandler) Backups(c *gin.Context) {
+	health, err := h.svcs.Health.Backups()
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(health))
+}
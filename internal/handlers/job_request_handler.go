@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/services"
+)
+
+// JobRequestHandler serves open job requests and the bids on them
+type JobRequestHandler struct {
+	svcs *services.Services
+}
+
+// NewJobRequestHandler creates a new job request handler
+func NewJobRequestHandler(svcs *services.Services) *JobRequestHandler {
+	return &JobRequestHandler{svcs: svcs}
+}
+
+// Create posts a job request for matching yandaşlar to bid on
+func (h *JobRequestHandler) Create(c *gin.Context) {
+	var input services.JobRequestInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	request, err := h.svcs.JobRequest.Create(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(request))
+}
+
+// List returns the current user's job requests
+func (h *JobRequestHandler) List(c *gin.Context) {
+	page, limit := getPagination(c)
+	requests, total, err := h.svcs.JobRequest.ListMine(getUserID(c), page, limit, c.Query("status"))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(requests, PaginationMeta(page, limit, total)))
+}
+
+// Get returns a job request with the bids the current user may see
+func (h *JobRequestHandler) Get(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid job request ID"))
+		return
+	}
+
+	request, err := h.svcs.JobRequest.Get(id, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(request))
+}
+
+// Cancel withdraws an open job request
+func (h *JobRequestHandler) Cancel(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid job request ID"))
+		return
+	}
+
+	if err := h.svcs.JobRequest.Cancel(id, getUserID(c)); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Job request cancelled"}))
+}
+
+// AcceptBid accepts a bid, turning it into an order
+func (h *JobRequestHandler) AcceptBid(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid job request ID"))
+		return
+	}
+	bidID, err := uuid.Parse(c.Param("bidId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid bid ID"))
+		return
+	}
+
+	order, err := h.svcs.JobRequest.AcceptBid(id, bidID, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(order))
+}
+
+// ListOpen returns open job requests the current yandaş can bid on
+func (h *JobRequestHandler) ListOpen(c *gin.Context) {
+	page, limit := getPagination(c)
+	requests, total, err := h.svcs.JobRequest.ListOpen(getUserID(c), page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(requests, PaginationMeta(page, limit, total)))
+}
+
+// PlaceBid bids on a job request, or revises the current yandaş's bid on it
+func (h *JobRequestHandler) PlaceBid(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid job request ID"))
+		return
+	}
+
+	var input services.BidInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	bid, err := h.svcs.JobRequest.PlaceBid(getUserID(c), id, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(bid))
+}
+
+// ListMyBids returns the current yandaş's bids
+func (h *JobRequestHandler) ListMyBids(c *gin.Context) {
+	page, limit := getPagination(c)
+	bids, total, err := h.svcs.JobRequest.ListMyBids(getUserID(c), page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(bids, PaginationMeta(page, limit, total)))
+}
+
+// WithdrawBid pulls back a pending bid
+func (h *JobRequestHandler) WithdrawBid(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid bid ID"))
+		return
+	}
+
+	if err := h.svcs.JobRequest.WithdrawBid(getUserID(c), id); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Bid withdrawn"}))
+}
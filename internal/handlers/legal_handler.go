@@ -2,114 +2,124 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/services"
 )
 
 type LegalHandler struct {
-	cfg *config.Config
+	svcs *services.Services
+	cfg  *config.Config
 }
 
-func NewLegalHandler(cfg *config.Config) *LegalHandler {
-	return &LegalHandler{cfg: cfg}
+func NewLegalHandler(svcs *services.Services, cfg *config.Config) *LegalHandler {
+	return &LegalHandler{svcs: svcs, cfg: cfg}
 }
 
-func (h *LegalHandler) PrivacyPolicy(c *gin.Context) {
+// document serves the current version of a kind in the requested locale
+func (h *LegalHandler) document(c *gin.Context, kind string) {
+	document, err := h.svcs.Legal.Document(kind, legalLocale(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"title": "Gizlilik Politikası",
-		"content": `
-# Gizlilik Politikası
-
-Son güncelleme: Şubat 2026
-
-## 1. Giriş
-Yandaş ("biz", "bizim") olarak gizliliğinize saygı duyuyoruz.
-
-## 2. Toplanan Veriler
-- Kimlik bilgileri (ad, e-posta, telefon)
-- Konum verileri (hizmet sağlanması için)
-- Ödeme bilgileri (işlem güvenliği için)
-
-## 3. Verilerin Kullanımı
-- Hizmet sağlanması
-- İletişim
-- Güvenlik
-
-## 4. Veri Güvenliği
-Verileriniz şifrelenerek saklanır.
-
-## 5. Haklarınız
-KVKK kapsamında verilerinize erişim, düzeltme ve silme hakkına sahipsiniz.
-
-## 6. İletişim
-privacy@yandas.app
-`,
+		"id":           document.ID,
+		"title":        document.Title,
+		"content":      document.Content,
+		"locale":       document.Locale,
+		"version":      document.Version,
+		"published_at": document.PublishedAt,
 	})
 }
 
-func (h *LegalHandler) TermsOfService(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"title": "Kullanım Koşulları",
-		"content": `
-# Kullanım Koşulları
-
-Son güncelleme: Şubat 2026
+// legalLocale picks the locale from the locale query parameter, then the
+// first supported language of the Accept-Language header, falling back to
+// Turkish
+func legalLocale(c *gin.Context) string {
+	tags := append([]string{c.Query("locale")}, strings.Split(c.GetHeader("Accept-Language"), ",")...)
+	for _, part := range tags {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		base := strings.SplitN(tag, "-", 2)[0]
+		for _, locale := range services.LegalLocales {
+			if base == locale {
+				return locale
+			}
+		}
+	}
+	return services.DefaultLegalLocale
+}
 
-## 1. Kabul
-Bu uygulamayı kullanarak bu koşulları kabul etmiş olursunuz.
+// Current returns the documents registration must accept
+func (h *LegalHandler) Current(c *gin.Context) {
+	documents, err := h.svcs.Consent.Required()
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	if documents == nil {
+		documents = []models.LegalDocument{}
+	}
+	c.JSON(http.StatusOK, SuccessResponse(documents))
+}
 
-## 2. Hizmet Tanımı
-Yandaş, vekalet ve yerinden hizmet platformudur.
+// PendingConsents returns the required documents the user has not accepted
+func (h *LegalHandler) PendingConsents(c *gin.Context) {
+	pending, err := h.svcs.Consent.Pending(getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	if pending == nil {
+		pending = []models.LegalDocument{}
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{
+		"needs_consent": len(pending) > 0,
+		"documents":     pending,
+	}))
+}
 
-## 3. Kullanıcı Sorumlulukları
-- Doğru bilgi sağlamak
-- Yasalara uymak
-- Diğer kullanıcılara saygı göstermek
+// AcceptConsents records the user accepting required documents
+func (h *LegalHandler) AcceptConsents(c *gin.Context) {
+	var input services.AcceptConsentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	input.Client = clientInfo(c)
+
+	pending, err := h.svcs.Consent.Accept(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{
+		"needs_consent": len(pending) > 0,
+		"documents":     pending,
+	}))
+}
 
-## 4. Yandaş Sorumlulukları
-- Onaylı belgeler sunmak
-- Profesyonel hizmet vermek
-- Müşteri güvenliğini sağlamak
+// ConsentHistory returns every document version the user accepted
+func (h *LegalHandler) ConsentHistory(c *gin.Context) {
+	consents, err := h.svcs.Consent.History(getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(consents))
+}
 
-## 5. Ödeme
-Ödemeler platform dışında yapılır.
+func (h *LegalHandler) PrivacyPolicy(c *gin.Context) {
+	h.document(c, services.LegalPrivacy)
+}
 
-## 6. İletişim
-legal@yandas.app
-`,
-	})
+func (h *LegalHandler) TermsOfService(c *gin.Context) {
+	h.document(c, services.LegalTerms)
 }
 
 func (h *LegalHandler) KVKK(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"title": "KVKK Aydınlatma Metni",
-		"content": `
-# KVKK Aydınlatma Metni
-
-6698 sayılı Kişisel Verilerin Korunması Kanunu kapsamında:
-
-## Veri Sorumlusu
-Yandaş Teknoloji A.Ş.
-
-## Toplanan Veriler
-- Kimlik bilgileri
-- İletişim bilgileri
-- Konum verileri
-
-## İşleme Amaçları
-- Sözleşmenin ifası
-- Yasal yükümlülükler
-
-## Haklarınız
-- Bilgi alma
-- Erişim
-- Düzeltme
-- Silme
-- İtiraz
-
-## İletişim
-kvkk@yandas.app
-`,
-	})
+	h.document(c, services.LegalKVKK)
 }
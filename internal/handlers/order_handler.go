@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
 	"github.com/yandas/backend/internal/services"
 )
 
@@ -19,12 +21,12 @@ func NewOrderHandler(svcs *services.Services) *OrderHandler {
 func (h *OrderHandler) Create(c *gin.Context) {
 	var input services.CreateOrderInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 	order, err := h.svcs.Order.Create(getUserID(c), &input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, SuccessResponse(order))
@@ -36,42 +38,227 @@ func (h *OrderHandler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, SuccessResponseWithMeta(orders, PaginationMeta(page, limit, total)))
 }
 
+// Rebook creates a new order pre-filled from a past one
+func (h *OrderHandler) Rebook(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	var input services.RebookInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			bindError(c, err)
+			return
+		}
+	}
+	order, err := h.svcs.Order.Rebook(id, getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(order))
+}
+
+// Recurring order handlers
+
+func (h *OrderHandler) CreateRecurring(c *gin.Context) {
+	var input services.RecurringOrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	recurring, err := h.svcs.Order.CreateRecurring(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(recurring))
+}
+
+func (h *OrderHandler) ListRecurring(c *gin.Context) {
+	page, limit := getPagination(c)
+	series, total, err := h.svcs.Order.ListRecurring(getUserID(c), page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(series, PaginationMeta(page, limit, total)))
+}
+
+func (h *OrderHandler) GetRecurring(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	recurring, err := h.svcs.Order.GetRecurring(id, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(recurring))
+}
+
+func (h *OrderHandler) PauseRecurring(c *gin.Context) {
+	h.updateRecurring(c, h.svcs.Order.PauseRecurring)
+}
+
+func (h *OrderHandler) ResumeRecurring(c *gin.Context) {
+	h.updateRecurring(c, h.svcs.Order.ResumeRecurring)
+}
+
+func (h *OrderHandler) CancelRecurring(c *gin.Context) {
+	h.updateRecurring(c, h.svcs.Order.CancelRecurring)
+}
+
+func (h *OrderHandler) updateRecurring(c *gin.Context, action func(id, customerID uuid.UUID) (*models.RecurringOrder, error)) {
+	id, _ := uuid.Parse(c.Param("id"))
+	recurring, err := action(id, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(recurring))
+}
+
+// Receipt downloads the PDF receipt of a completed order for either party
+func (h *OrderHandler) Receipt(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	userID := getUserID(c)
+	order, err := h.svcs.Order.Get(id, userID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	receipt, err := h.svcs.Receipt.Ensure(order)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	body := h.svcs.Receipt.Render(receipt, order, order.CustomerID != userID)
+	c.Header("Content-Disposition", `attachment; filename="`+receipt.ReceiptNumber+`.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", body)
+}
+
 func (h *OrderHandler) Get(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
 	order, err := h.svcs.Order.Get(id, getUserID(c))
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(order))
 }
 
+// Timeline returns the order's events for the tracking screen
+func (h *OrderHandler) Timeline(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	events, err := h.svcs.Order.Timeline(id, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(events))
+}
+
 func (h *OrderHandler) Cancel(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
 	var input struct{ Reason string `json:"reason"` }
 	c.ShouldBindJSON(&input)
 	if err := h.svcs.Order.Cancel(id, getUserID(c), input.Reason); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Cancelled"}))
 }
 
+// ConfirmCompletion lets the customer confirm an order the yandaş marked done
+func (h *OrderHandler) ConfirmCompletion(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	if err := h.svcs.Yandas.ConfirmCompletion(getUserID(c), id); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Completed"}))
+}
+
+// ListAdjustments returns the amount changes proposed for an order
+func (h *OrderHandler) ListAdjustments(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	adjustments, err := h.svcs.Order.ListAdjustments(id, getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(adjustments))
+}
+
+// ApproveAdjustment accepts the yandaş's proposed amount for the order
+func (h *OrderHandler) ApproveAdjustment(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	adjustmentID, err := uuid.Parse(c.Param("adjustmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid adjustment ID"))
+		return
+	}
+	order, err := h.svcs.Yandas.ApproveAdjustment(getUserID(c), id, adjustmentID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(order))
+}
+
+// RejectAdjustment turns down the yandaş's proposed amount for the order
+func (h *OrderHandler) RejectAdjustment(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	adjustmentID, err := uuid.Parse(c.Param("adjustmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid adjustment ID"))
+		return
+	}
+	if err := h.svcs.Yandas.RejectAdjustment(getUserID(c), id, adjustmentID); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Rejected"}))
+}
+
 func (h *OrderHandler) Review(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
 	var input services.ReviewInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 	review, err := h.svcs.Order.Review(id, getUserID(c), &input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, SuccessResponse(review))
 }
 
+func (h *OrderHandler) MarkReviewHelpful(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid review id"))
+		return
+	}
+	if err := h.svcs.Order.MarkReviewHelpful(id, getUserID(c)); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Marked helpful"}))
+}
+
+func (h *OrderHandler) UnmarkReviewHelpful(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid review id"))
+		return
+	}
+	if err := h.svcs.Order.UnmarkReviewHelpful(id, getUserID(c)); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Vote removed"}))
+}
+
 type CategoryHandler struct {
 	svcs *services.Services
 }
@@ -80,7 +267,49 @@ func NewCategoryHandler(svcs *services.Services) *CategoryHandler {
 	return &CategoryHandler{svcs: svcs}
 }
 
+// Currencies lists the accepted currencies with their latest rate against TRY
+func (h *CategoryHandler) Currencies(c *gin.Context) {
+	currencies, err := h.svcs.Currency.List()
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(currencies))
+}
+
 func (h *CategoryHandler) List(c *gin.Context) {
-	categories, _ := h.svcs.Category.List()
+	categories, _ := h.svcs.Category.List(c.Query("city"))
 	c.JSON(http.StatusOK, SuccessResponse(categories))
 }
+
+// Demand shows yandaşlar where open orders outnumber available yandaşlar; city is optional
+func (h *CategoryHandler) Demand(c *gin.Context) {
+	demand, err := h.svcs.Category.Demand(c.Query("city"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse("failed to load demand"))
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(demand))
+}
+
+// ApplicationForm returns the documents and questions of the yandaş application
+// for the categories in category_ids
+func (h *CategoryHandler) ApplicationForm(c *gin.Context) {
+	var categoryIDs []uuid.UUID
+	for _, value := range c.QueryArray("category_ids") {
+		for _, raw := range strings.Split(value, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(raw))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse("invalid category id"))
+				return
+			}
+			categoryIDs = append(categoryIDs, id)
+		}
+	}
+	form, err := h.svcs.Onboarding.Form(categoryIDs)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(form))
+}
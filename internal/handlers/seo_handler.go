@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yandas/backend/internal/services"
+)
+
+// SEOHandler serves sitemap and link preview data to the web frontend
+type SEOHandler struct {
+	svcs *services.Services
+}
+
+// NewSEOHandler creates a new SEO handler
+func NewSEOHandler(svcs *services.Services) *SEOHandler {
+	return &SEOHandler{svcs: svcs}
+}
+
+// Sitemap lists public category and yandaş profile pages with their lastmod
+func (h *SEOHandler) Sitemap(c *gin.Context) {
+	page, limit := getPagination(c)
+	pages, total, err := h.svcs.SEO.Sitemap(page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(pages, PaginationMeta(page, limit, total)))
+}
+
+// ProfileOpenGraph returns the OpenGraph tags of a yandaş profile; :id is
+// the profile ID or its slug
+func (h *SEOHandler) ProfileOpenGraph(c *gin.Context) {
+	og, err := h.svcs.SEO.ProfileOpenGraph(c.Param("id"))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(og))
+}
@@ -22,13 +22,13 @@ func NewSupportHandler(svcs *services.Services) *SupportHandler {
 func (h *SupportHandler) CreateTicket(c *gin.Context) {
 	var input services.CreateTicketInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 
 	ticket, err := h.svcs.Support.CreateTicket(getUserID(c), &input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -40,7 +40,7 @@ func (h *SupportHandler) ListTickets(c *gin.Context) {
 	page, limit := getPagination(c)
 	tickets, total, err := h.svcs.Support.ListUserTickets(getUserID(c), page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -57,7 +57,7 @@ func (h *SupportHandler) GetTicket(c *gin.Context) {
 
 	ticket, err := h.svcs.Support.GetUserTicket(getUserID(c), ticketID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -76,13 +76,13 @@ func (h *SupportHandler) ReplyTicket(c *gin.Context) {
 		Content string `json:"content" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 
 	msg, err := h.svcs.Support.ReplyTicket(getUserID(c), ticketID, input.Content)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -110,7 +110,7 @@ func (h *SearchHandler) SearchYandas(c *gin.Context) {
 	page, limit := getPagination(c)
 	profiles, total, err := h.svcs.Yandas.Search(query, page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/services"
+)
+
+// TrackingHandler handles product analytics events from the apps
+type TrackingHandler struct {
+	svcs *services.Services
+}
+
+// NewTrackingHandler creates a new tracking handler
+func NewTrackingHandler(svcs *services.Services) *TrackingHandler {
+	return &TrackingHandler{svcs: svcs}
+}
+
+// Track stores a batch of events; signed-in requests are attributed to the user too
+func (h *TrackingHandler) Track(c *gin.Context) {
+	var input services.TrackEventsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	var userID *uuid.UUID
+	if id, err := uuid.Parse(c.GetString("user_id")); err == nil {
+		userID = &id
+	}
+	accepted, err := h.svcs.Tracking.Track(userID, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, SuccessResponse(gin.H{"accepted": accepted}))
+}
@@ -34,7 +34,7 @@ func getPagination(c *gin.Context) (int, int) {
 func (h *UserHandler) GetProfile(c *gin.Context) {
 	user, err := h.svcs.User.GetProfile(getUserID(c))
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(user))
@@ -43,12 +43,12 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	var input services.UpdateProfileInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 	user, err := h.svcs.User.UpdateProfile(getUserID(c), &input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(user))
@@ -63,26 +63,98 @@ func (h *UserHandler) UpdateAvatar(c *gin.Context) {
 	userID := getUserID(c)
 	dst := "./uploads/avatars/" + userID.String() + "_" + file.Filename
 	c.SaveUploadedFile(file, dst)
-	h.svcs.User.UpdateAvatar(userID, dst)
+	if err := h.svcs.User.UpdateAvatar(userID, dst); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"avatar_url": dst}))
 }
 
 func (h *UserHandler) ChangePassword(c *gin.Context) {
 	var input services.ChangePasswordInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 	if err := h.svcs.User.ChangePassword(getUserID(c), &input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Password changed"}))
 }
 
+func (h *UserHandler) RequestEmailChange(c *gin.Context) {
+	var input services.EmailChangeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	change, err := h.svcs.Auth.RequestEmailChange(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{
+		"pending_email": change.NewValue,
+		"expires_at":    change.ExpiresAt,
+	}))
+}
+
+func (h *UserHandler) ConfirmEmailChange(c *gin.Context) {
+	var input struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	user, tokens, err := h.svcs.Auth.ConfirmEmailChange(getUserID(c), input.Code, c.GetHeader("X-Platform"), clientInfo(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{
+		"user":   user,
+		"tokens": tokens,
+	}))
+}
+
+func (h *UserHandler) RequestPhoneChange(c *gin.Context) {
+	var input services.PhoneChangeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	change, err := h.svcs.Auth.RequestPhoneChange(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{
+		"pending_phone": change.NewValue,
+		"expires_at":    change.ExpiresAt,
+	}))
+}
+
+func (h *UserHandler) ConfirmPhoneChange(c *gin.Context) {
+	var input struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	user, err := h.svcs.Auth.ConfirmPhoneChange(getUserID(c), input.Code)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(user))
+}
+
 func (h *UserHandler) DeleteAccount(c *gin.Context) {
 	if err := h.svcs.User.DeleteAccount(getUserID(c)); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Account deleted"}))
@@ -94,9 +166,135 @@ func (h *UserHandler) RegisterDeviceToken(c *gin.Context) {
 		Platform string `json:"platform" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
+		return
+	}
+	if err := h.svcs.User.RegisterDeviceToken(getUserID(c), getSessionID(c), input.Token, input.Platform); err != nil {
+		serviceError(c, err)
 		return
 	}
-	h.svcs.User.RegisterDeviceToken(getUserID(c), input.Token, input.Platform)
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Token registered"}))
 }
+
+// getSessionID returns the session of the current access token, or nil for tokens issued before sessions
+func getSessionID(c *gin.Context) *uuid.UUID {
+	id, err := uuid.Parse(c.GetString("session_id"))
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+func (h *UserHandler) ListDevices(c *gin.Context) {
+	sessions, err := h.svcs.Auth.ListSessions(getUserID(c), c.GetString("session_id"))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(sessions))
+}
+
+func (h *UserHandler) RevokeDevice(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid session id"))
+		return
+	}
+	if err := h.svcs.Auth.RevokeSession(getUserID(c), sessionID); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Device signed out"}))
+}
+
+func (h *UserHandler) GetNotificationPreferences(c *gin.Context) {
+	prefs, err := h.svcs.Notification.GetPreferences(getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(prefs))
+}
+
+func (h *UserHandler) UpdateNotificationPreferences(c *gin.Context) {
+	var input services.UpdatePreferencesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	prefs, err := h.svcs.Notification.UpdatePreferences(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(prefs))
+}
+
+func (h *UserHandler) ListAddresses(c *gin.Context) {
+	addresses, err := h.svcs.User.ListAddresses(getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(addresses))
+}
+
+func (h *UserHandler) CreateAddress(c *gin.Context) {
+	var input services.AddressInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	address, err := h.svcs.User.CreateAddress(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(address))
+}
+
+func (h *UserHandler) UpdateAddress(c *gin.Context) {
+	addressID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid address id"))
+		return
+	}
+	var input services.AddressInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	address, err := h.svcs.User.UpdateAddress(getUserID(c), addressID, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(address))
+}
+
+func (h *UserHandler) SetDefaultAddress(c *gin.Context) {
+	addressID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid address id"))
+		return
+	}
+	address, err := h.svcs.User.SetDefaultAddress(getUserID(c), addressID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(address))
+}
+
+func (h *UserHandler) DeleteAddress(c *gin.Context) {
+	addressID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid address id"))
+		return
+	}
+	if err := h.svcs.User.DeleteAddress(getUserID(c), addressID); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Address deleted"}))
+}
@@ -1,15 +1,24 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
 	"github.com/yandas/backend/internal/services"
+	"github.com/yandas/backend/pkg/currency"
+	"github.com/yandas/backend/pkg/kyc"
 )
 
 type YandasHandler struct {
@@ -27,27 +36,145 @@ func (h *YandasHandler) ListPublic(c *gin.Context) {
 
 	yandas, total, err := h.svcs.Yandas.ListPublic(page, limit, category, city)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponseWithMeta(yandas, PaginationMeta(page, limit, total)))
 }
 
+// GetPublic returns a yandaş profile; with lat/lng its services carry the
+// distance and travel fee to that location
+// GetPublic returns a yandaş profile; :id is the profile ID or its slug
 func (h *YandasHandler) GetPublic(c *gin.Context) {
-	id, _ := uuid.Parse(c.Param("id"))
-	yandas, err := h.svcs.Yandas.GetPublic(id)
+	yandas, err := h.svcs.Yandas.GetPublic(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse(err.Error()))
+		serviceError(c, err)
+		return
+	}
+	if !h.estimateTravel(c, yandas.ID, yandas.Services) {
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(yandas))
 }
 
+// estimateTravel annotates services when the request has lat/lng, replying
+// with an error and returning false when they are invalid
+func (h *YandasHandler) estimateTravel(c *gin.Context, yandasID uuid.UUID, services []models.YandasService) bool {
+	if c.Query("lat") == "" && c.Query("lng") == "" {
+		return true
+	}
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid lat/lng"))
+		return false
+	}
+	if err := h.svcs.Yandas.EstimateTravel(yandasID, services, lat, lng); err != nil {
+		serviceError(c, err)
+		return false
+	}
+	return true
+}
+
+// ListServices browses the service catalogue; filters are category, city,
+// min_price/max_price, min_duration/max_duration (minutes) and sort
+// (rating, price_asc, price_desc)
+func (h *YandasHandler) ListServices(c *gin.Context) {
+	page, limit := getPagination(c)
+	filter := repository.ServiceFilter{
+		CategorySlug: c.Query("category"),
+		City:         c.Query("city"),
+		Sort:         c.Query("sort"),
+	}
+
+	var err error
+	if filter.MinPrice, err = optionalMoneyQuery(c, "min_price"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid min_price"))
+		return
+	}
+	if filter.MaxPrice, err = optionalMoneyQuery(c, "max_price"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid max_price"))
+		return
+	}
+	if filter.MinDuration, err = optionalIntQuery(c, "min_duration"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid min_duration"))
+		return
+	}
+	if filter.MaxDuration, err = optionalIntQuery(c, "max_duration"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid max_duration"))
+		return
+	}
+
+	items, total, err := h.svcs.Yandas.ListServices(filter, page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(items, PaginationMeta(page, limit, total)))
+}
+
+// optionalMoneyQuery parses a non-negative amount query parameter such as
+// 150.50; absent means nil
+func optionalMoneyQuery(c *gin.Context, key string) (*currency.Money, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	m, err := currency.ParseMoney(v)
+	if err != nil || m < 0 {
+		return nil, errors.New("invalid " + key)
+	}
+	return &m, nil
+}
+
+// optionalIntQuery parses a non-negative integer query parameter; absent means nil
+func optionalIntQuery(c *gin.Context, key string) (*int, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return nil, errors.New("invalid " + key)
+	}
+	return &n, nil
+}
+
+// optionalFloatQuery parses a non-negative number query parameter; absent means nil
+func optionalFloatQuery(c *gin.Context, key string) (*float64, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, errors.New("invalid " + key)
+	}
+	return &f, nil
+}
+
+// optionalBoolQuery parses a true/false query parameter; absent means nil
+func optionalBoolQuery(c *gin.Context, key string) (*bool, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, errors.New("invalid " + key)
+	}
+	return &b, nil
+}
+
+// GetServices lists a yandaş's services; lat/lng add the travel estimate
 func (h *YandasHandler) GetServices(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
 	services, err := h.svcs.Yandas.GetServices(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse(err.Error()))
+		serviceError(c, err)
+		return
+	}
+	if !h.estimateTravel(c, id, services) {
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(services))
@@ -56,10 +183,46 @@ func (h *YandasHandler) GetServices(c *gin.Context) {
 func (h *YandasHandler) GetReviews(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
 	page, limit := getPagination(c)
-	reviews, total, _ := h.svcs.Yandas.GetReviews(id, page, limit)
+	sort := c.DefaultQuery("sort", "recent")
+	if sort != "recent" && sort != "helpful" {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid sort"))
+		return
+	}
+	reviews, total, _ := h.svcs.Yandas.GetReviews(id, page, limit, sort)
 	c.JSON(http.StatusOK, SuccessResponseWithMeta(reviews, PaginationMeta(page, limit, total)))
 }
 
+// GetReviewStats returns the star distribution, criteria averages and monthly
+// trend of a yandaş's reviews
+func (h *YandasHandler) GetReviewStats(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	stats, err := h.svcs.ReviewStats.ForYandas(id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(stats))
+}
+
+func (h *YandasHandler) ReplyReview(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid review id"))
+		return
+	}
+	var input services.ReplyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	review, err := h.svcs.Yandas.ReplyToReview(getUserID(c), id, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(review))
+}
+
 // saveUploadedFile saves an uploaded file and returns the URL path
 func saveUploadedFile(c *gin.Context, fieldName string, userID uuid.UUID) (string, error) {
 	file, err := c.FormFile(fieldName)
@@ -73,9 +236,10 @@ func saveUploadedFile(c *gin.Context, fieldName string, userID uuid.UUID) (strin
 		return "", err
 	}
 
-	// Generate unique filename
+	// Generate unique filename; answers[<id>] fields become answers_<id>
 	ext := filepath.Ext(file.Filename)
-	filename := fmt.Sprintf("%s_%s_%d%s", userID.String(), fieldName, time.Now().UnixNano(), ext)
+	label := strings.NewReplacer("[", "_", "]", "").Replace(fieldName)
+	filename := fmt.Sprintf("%s_%s_%d%s", userID.String(), label, time.Now().UnixNano(), ext)
 	filePath := filepath.Join(uploadDir, filename)
 
 	// Save the file
@@ -98,7 +262,7 @@ func (h *YandasHandler) Apply(c *gin.Context) {
 	if contentType == "application/json" {
 		// JSON request (backward compatible)
 		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+			bindError(c, err)
 			return
 		}
 	} else {
@@ -136,16 +300,63 @@ func (h *YandasHandler) Apply(c *gin.Context) {
 		if adliSicilPDFURL, err := saveUploadedFile(c, "adli_sicil_pdf", userID); err == nil {
 			input.AdliSicilPDFURL = adliSicilPDFURL
 		}
+		if selfieURL, err := saveUploadedFile(c, "selfie", userID); err == nil {
+			input.SelfieURL = selfieURL
+		}
+
+		input.Answers = formAnswers(c, userID)
 	}
 
 	profile, err := h.svcs.Yandas.Apply(userID, &input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, SuccessResponse(profile))
 }
 
+// formAnswers reads the answers to the application questions, sent as
+// answers[<question id>] fields, or as files for file questions
+func formAnswers(c *gin.Context, userID uuid.UUID) []services.ApplicationAnswerInput {
+	var answers []services.ApplicationAnswerInput
+	for key, value := range c.PostFormMap("answers") {
+		if id, err := uuid.Parse(key); err == nil {
+			answers = append(answers, services.ApplicationAnswerInput{QuestionID: id, Value: value})
+		}
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return answers
+	}
+	for field := range form.File {
+		if !strings.HasPrefix(field, "answers[") || !strings.HasSuffix(field, "]") {
+			continue
+		}
+		id, err := uuid.Parse(field[len("answers[") : len(field)-1])
+		if err != nil {
+			continue
+		}
+		if url, err := saveUploadedFile(c, field, userID); err == nil {
+			answers = append(answers, services.ApplicationAnswerInput{QuestionID: id, Value: url})
+		}
+	}
+	return answers
+}
+
+// KYCResult records an identity check result posted by the KYC provider
+func (h *YandasHandler) KYCResult(c *gin.Context) {
+	err := h.svcs.KYC.HandleResult(c.Param("provider"), c.Request)
+	switch {
+	case errors.Is(err, kyc.ErrInvalidWebhook):
+		c.JSON(http.StatusForbidden, ErrorResponse(err.Error()))
+	case err != nil:
+		serviceError(c, err)
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
 func (h *YandasHandler) ApplicationStatus(c *gin.Context) {
 	profile, err := h.svcs.Yandas.GetApplicationStatus(getUserID(c))
 	if err != nil {
@@ -158,12 +369,12 @@ func (h *YandasHandler) ApplicationStatus(c *gin.Context) {
 func (h *YandasHandler) UpdateProfile(c *gin.Context) {
 	var input services.UpdateYandasProfileInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 	profile, err := h.svcs.Yandas.UpdateProfile(getUserID(c), &input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(profile))
@@ -174,41 +385,216 @@ func (h *YandasHandler) UpdateAvailability(c *gin.Context) {
 		Available bool `json:"available"`
 	}
 	c.ShouldBindJSON(&input)
-	h.svcs.Yandas.UpdateAvailability(getUserID(c), input.Available)
+	if err := h.svcs.Yandas.UpdateAvailability(getUserID(c), input.Available); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"available": input.Available}))
 }
 
+// UpdateBookingPreferences sets the minimum order value, instant booking and lead time
+func (h *YandasHandler) UpdateBookingPreferences(c *gin.Context) {
+	var input services.BookingPreferencesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	profile, err := h.svcs.Yandas.UpdateBookingPreferences(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(profile))
+}
+
+// SetVacation plans a vacation, or moves the end of the current one
+func (h *YandasHandler) SetVacation(c *gin.Context) {
+	var input services.VacationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	profile, err := h.svcs.Yandas.SetVacation(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(profile))
+}
+
+// EndVacation cancels a planned vacation or ends the current one early
+func (h *YandasHandler) EndVacation(c *gin.Context) {
+	profile, err := h.svcs.Yandas.EndVacation(getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(profile))
+}
+
+// ListDocumentRenewals returns the documents the yandaş has been asked to renew
+func (h *YandasHandler) ListDocumentRenewals(c *gin.Context) {
+	renewals, err := h.svcs.Yandas.ListDocumentRenewals(getUserID(c))
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(renewals))
+}
+
+// RenewDocument uploads a fresh copy of a document that went stale
+func (h *YandasHandler) RenewDocument(c *gin.Context) {
+	document := c.Param("document")
+	if !services.RenewableDocument(document) {
+		serviceError(c, services.ErrDocumentNotRenewable)
+		return
+	}
+	userID := getUserID(c)
+	url, err := saveUploadedFile(c, document, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("document file required"))
+		return
+	}
+	profile, err := h.svcs.Yandas.RenewDocument(userID, document, url)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(profile))
+}
+
+// UploadApplicationDocument adds a document to the user's pending
+// application, sent as a file under the document's name
+func (h *YandasHandler) UploadApplicationDocument(c *gin.Context) {
+	document := c.Param("document")
+	if !slices.Contains(services.ApplicationDocuments, document) {
+		c.JSON(http.StatusBadRequest, ErrorResponse("unknown document"))
+		return
+	}
+	userID := getUserID(c)
+	url, err := saveUploadedFile(c, document, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("document file required"))
+		return
+	}
+	status, err := h.svcs.Yandas.UploadApplicationDocument(userID, document, url)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(status))
+}
+
+// UpdateServiceArea replaces the base location, service radius and travel fee
+func (h *YandasHandler) UpdateServiceArea(c *gin.Context) {
+	var input services.ServiceAreaInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	profile, err := h.svcs.Yandas.UpdateServiceArea(getUserID(c), &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{
+		"base_latitude":     profile.BaseLatitude,
+		"base_longitude":    profile.BaseLongitude,
+		"radius_km":         profile.ServiceRadiusKm,
+		"travel_fee_per_km": profile.TravelFeePerKm,
+	}))
+}
+
 func (h *YandasHandler) UpdateLocation(c *gin.Context) {
 	var input struct {
 		Lat float64 `json:"latitude"`
 		Lng float64 `json:"longitude"`
 	}
 	c.ShouldBindJSON(&input)
-	h.svcs.Yandas.UpdateLocation(getUserID(c), input.Lat, input.Lng)
+	if err := h.svcs.Yandas.UpdateLocation(getUserID(c), input.Lat, input.Lng); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Location updated"}))
 }
 
 func (h *YandasHandler) CreateService(c *gin.Context) {
 	var input services.ServiceInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		bindError(c, err)
 		return
 	}
 	svc, err := h.svcs.Yandas.CreateService(getUserID(c), &input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, SuccessResponse(svc))
 }
 
+func (h *YandasHandler) AddServiceOption(c *gin.Context) {
+	serviceID, _ := uuid.Parse(c.Param("id"))
+	var input services.ServiceOptionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	option, err := h.svcs.Yandas.AddServiceOption(getUserID(c), serviceID, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(option))
+}
+
+func (h *YandasHandler) UpdateServiceOption(c *gin.Context) {
+	serviceID, _ := uuid.Parse(c.Param("id"))
+	optionID, _ := uuid.Parse(c.Param("optionId"))
+	var input services.ServiceOptionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	option, err := h.svcs.Yandas.UpdateServiceOption(getUserID(c), serviceID, optionID, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(option))
+}
+
+func (h *YandasHandler) DeleteServiceOption(c *gin.Context) {
+	serviceID, _ := uuid.Parse(c.Param("id"))
+	optionID, _ := uuid.Parse(c.Param("optionId"))
+	if err := h.svcs.Yandas.DeleteServiceOption(getUserID(c), serviceID, optionID); err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Deleted"}))
+}
+
+func (h *YandasHandler) SetPriceTiers(c *gin.Context) {
+	serviceID, _ := uuid.Parse(c.Param("id"))
+	var input services.SetPriceTiersInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	tiers, err := h.svcs.Yandas.SetPriceTiers(getUserID(c), serviceID, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(tiers))
+}
+
 func (h *YandasHandler) UpdateService(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
 	var input services.ServiceInput
 	c.ShouldBindJSON(&input)
 	svc, err := h.svcs.Yandas.UpdateService(getUserID(c), id, &input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(svc))
@@ -216,20 +602,69 @@ func (h *YandasHandler) UpdateService(c *gin.Context) {
 
 func (h *YandasHandler) DeleteService(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
-	h.svcs.Yandas.DeleteService(getUserID(c), id)
+	if err := h.svcs.Yandas.DeleteService(getUserID(c), id); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Deleted"}))
 }
 
+// ArchiveService hides a service from customers until it is unarchived
+func (h *YandasHandler) ArchiveService(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid service ID"))
+		return
+	}
+	svc, err := h.svcs.Yandas.ArchiveService(getUserID(c), id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(svc))
+}
+
+// PublishService publishes a draft or resubmits a rejected service
+func (h *YandasHandler) PublishService(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid service ID"))
+		return
+	}
+	svc, err := h.svcs.Yandas.PublishService(getUserID(c), id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(svc))
+}
+
+// UnarchiveService lists an archived service again
+func (h *YandasHandler) UnarchiveService(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse("invalid service ID"))
+		return
+	}
+	svc, err := h.svcs.Yandas.UnarchiveService(getUserID(c), id)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(svc))
+}
+
+// GetMyServices lists the yandaş's own services in every status;
+// include_inactive=true adds the archived ones
 func (h *YandasHandler) GetMyServices(c *gin.Context) {
-	userID := getUserID(c)
-	profile, err := h.svcs.Yandas.GetApplicationStatus(userID)
+	includeInactive, err := optionalBoolQuery(c, "include_inactive")
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse("Yandaş profile not found"))
+		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 		return
 	}
-	services, err := h.svcs.Yandas.GetServices(profile.ID)
+	services, err := h.svcs.Yandas.GetMyServices(getUserID(c), includeInactive != nil && *includeInactive)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse(err.Error()))
+		serviceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, SuccessResponse(services))
@@ -243,11 +678,12 @@ func (h *YandasHandler) GetOrders(c *gin.Context) {
 
 func (h *YandasHandler) AcceptOrder(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
-	if err := h.svcs.Yandas.AcceptOrder(getUserID(c), id); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	order, err := h.svcs.Yandas.AcceptOrder(getUserID(c), id)
+	if err != nil {
+		serviceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Accepted"}))
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Accepted", "order_id": order.ID, "conversation_id": order.ConversationID}))
 }
 
 func (h *YandasHandler) RejectOrder(c *gin.Context) {
@@ -256,13 +692,54 @@ func (h *YandasHandler) RejectOrder(c *gin.Context) {
 		Reason string `json:"reason"`
 	}
 	c.ShouldBindJSON(&input)
-	h.svcs.Yandas.RejectOrder(getUserID(c), id, input.Reason)
+	if err := h.svcs.Yandas.RejectOrder(getUserID(c), id, input.Reason); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Rejected"}))
 }
 
+// OnMyWay reports that the yandaş set off for an accepted order; the body may
+// carry their current latitude and longitude for the arrival estimate
+func (h *YandasHandler) OnMyWay(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	var input services.OnMyWayInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			bindError(c, err)
+			return
+		}
+	}
+	eta, err := h.svcs.Yandas.OnMyWay(getUserID(c), id, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "On the way", "eta": eta}))
+}
+
+// ProposeAdjustment asks the customer to approve a new amount for the order
+func (h *YandasHandler) ProposeAdjustment(c *gin.Context) {
+	id, _ := uuid.Parse(c.Param("id"))
+	var input services.AdjustmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+	adjustment, err := h.svcs.Yandas.ProposeAdjustment(getUserID(c), id, &input)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SuccessResponse(adjustment))
+}
+
 func (h *YandasHandler) StartOrder(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("id"))
-	h.svcs.Yandas.StartOrder(getUserID(c), id)
+	if err := h.svcs.Yandas.StartOrder(getUserID(c), id); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Started"}))
 }
 
@@ -272,10 +749,115 @@ func (h *YandasHandler) CompleteOrder(c *gin.Context) {
 		Notes string `json:"notes"`
 	}
 	c.ShouldBindJSON(&input)
-	h.svcs.Yandas.CompleteOrder(getUserID(c), id, input.Notes)
+	if err := h.svcs.Yandas.CompleteOrder(getUserID(c), id, input.Notes); err != nil {
+		serviceError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, SuccessResponse(gin.H{"message": "Completed"}))
 }
 
+// GetCalendar returns bookings grouped by day; from/to are YYYY-MM-DD and default to the next 30 days
+func (h *YandasHandler) GetCalendar(c *gin.Context) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := from.AddDate(0, 0, 30)
+
+	if v := c.Query("from"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse("invalid from date"))
+			return
+		}
+		from = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse("invalid to date"))
+			return
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	if !to.After(from) || to.Sub(from) > 92*24*time.Hour {
+		c.JSON(http.StatusBadRequest, ErrorResponse("date range must be between 1 and 92 days"))
+		return
+	}
+
+	days, err := h.svcs.Yandas.GetCalendar(getUserID(c), from, to)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(days))
+}
+
+// Analytics returns the yandaş's daily profile views and conversions; from/to
+// are YYYY-MM-DD, inclusive, and default to the last 30 days up to yesterday
+func (h *YandasHandler) Analytics(c *gin.Context) {
+	from, to, ok := dateRange(c)
+	if !ok {
+		return
+	}
+
+	analytics, err := h.svcs.Yandas.Analytics(getUserID(c), from, to)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse(analytics))
+}
+
+// dateRange reads the inclusive from/to query dates, defaulting to the last 30 days.
+// It answers 400 and returns false when they are invalid.
+func dateRange(c *gin.Context) (time.Time, time.Time, bool) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := to.AddDate(0, 0, -30)
+
+	if v := c.Query("from"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse("invalid from date"))
+			return from, to, false
+		}
+		from = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse("invalid to date"))
+			return from, to, false
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	if !to.After(from) || to.Sub(from) > 366*24*time.Hour {
+		c.JSON(http.StatusBadRequest, ErrorResponse("date range must be between 1 and 366 days"))
+		return from, to, false
+	}
+	return from, to, true
+}
+
+// GetEarnings returns unpaid balances and a page of the earnings ledger
+func (h *YandasHandler) GetEarnings(c *gin.Context) {
+	page, limit := getPagination(c)
+	earnings, total, err := h.svcs.Yandas.GetEarnings(getUserID(c), page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(earnings, PaginationMeta(page, limit, total)))
+}
+
+func (h *YandasHandler) GetPayouts(c *gin.Context) {
+	page, limit := getPagination(c)
+	payouts, total, err := h.svcs.Yandas.GetPayouts(getUserID(c), page, limit)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponseWithMeta(payouts, PaginationMeta(page, limit, total)))
+}
+
 func (h *YandasHandler) GetStats(c *gin.Context) {
 	stats, _ := h.svcs.Yandas.GetStats(getUserID(c))
 	c.JSON(http.StatusOK, SuccessResponse(stats))
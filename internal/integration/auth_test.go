@@ -0,0 +1,149 @@
+//go:build integration
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/testutil"
+	"github.com/yandas/backend/pkg/auth"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	app := env.NewApp(t)
+
+	res := app.Do(http.MethodPost, "/api/v1/auth/register", "", map[string]string{
+		"email":     "yeni@test.com",
+		"phone":     "5550001122",
+		"password":  testutil.DefaultPassword,
+		"full_name": "Yeni Kullanıcı",
+	})
+	if res.Status != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", res.Status, res.Error)
+	}
+
+	res = app.Do(http.MethodPost, "/api/v1/auth/register", "", map[string]string{
+		"email":     "yeni@test.com",
+		"password":  testutil.DefaultPassword,
+		"full_name": "Tekrar",
+	})
+	if res.Status != http.StatusConflict {
+		t.Errorf("expected a duplicate email to be rejected, got %d", res.Status)
+	}
+
+	token := app.Login("yeni@test.com")
+	res = app.Do(http.MethodGet, "/api/v1/user/me", token, nil)
+	if res.Status != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.Status, res.Error)
+	}
+	var me models.User
+	res.Decode(t, &me)
+	if me.FullName != "Yeni Kullanıcı" || me.Role != "customer" || me.IsVerified {
+		t.Errorf("unexpected profile %+v", me)
+	}
+}
+
+func TestLoginRejectsBadCredentials(t *testing.T) {
+	app := env.NewApp(t)
+	user, err := app.Factory.User()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := app.Do(http.MethodPost, "/api/v1/auth/login", "", map[string]string{
+		"email":    *user.Email,
+		"password": "yanlis-sifre",
+	})
+	if res.Status != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", res.Status)
+	}
+
+	if res := app.Do(http.MethodGet, "/api/v1/user/me", "", nil); res.Status != http.StatusUnauthorized {
+		t.Errorf("expected protected routes to require a token, got %d", res.Status)
+	}
+}
+
+func TestRefreshRejectsBadTokens(t *testing.T) {
+	app := env.NewApp(t)
+	user, err := app.Factory.User()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := app.Do(http.MethodPost, "/api/v1/auth/login", "", map[string]string{
+		"email":    *user.Email,
+		"password": testutil.DefaultPassword,
+	})
+	var login struct {
+		Tokens auth.TokenPair `json:"tokens"`
+	}
+	res.Decode(t, &login)
+
+	expired, err := auth.GenerateTokenPair(auth.Claims{UserID: user.ID.String()}, env.Config.JWTSecret, -time.Minute, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refresh := login.Tokens.RefreshToken
+	tampered := refresh[:len(refresh)-4] + "AAAA"
+	if tampered == refresh {
+		tampered = refresh[:len(refresh)-4] + "BBBB"
+	}
+
+	for name, token := range map[string]string{"expired": expired.RefreshToken, "tampered": tampered} {
+		res := app.Do(http.MethodPost, "/api/v1/auth/refresh", "", map[string]string{"refresh_token": token})
+		if res.Status != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401, got %d: %s", name, res.Status, res.Error)
+		}
+	}
+}
+
+func TestDeactivatedUserLosesAccess(t *testing.T) {
+	app := env.NewApp(t)
+	user, err := app.Factory.User()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := app.Login(*user.Email)
+
+	if _, err := app.Services.Admin.UpdateUser(user.ID, map[string]interface{}{"is_active": false}); err != nil {
+		t.Fatal(err)
+	}
+	if res := app.Do(http.MethodGet, "/api/v1/user/me", token, nil); res.Status != http.StatusUnauthorized {
+		t.Errorf("expected a revoked token to be rejected, got %d", res.Status)
+	}
+}
+
+func TestStaleProfileUpdateConflicts(t *testing.T) {
+	app := env.NewApp(t)
+	user, err := app.Factory.User()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := app.Login(*user.Email)
+
+	var me models.User
+	app.Do(http.MethodGet, "/api/v1/user/me", token, nil).Decode(t, &me)
+
+	res := app.Do(http.MethodPut, "/api/v1/user/me", token, map[string]interface{}{"full_name": "İlk Değişiklik", "version": me.Version})
+	if res.Status != http.StatusOK {
+		t.Fatalf("expected the first edit to succeed, got %d: %s", res.Status, res.Error)
+	}
+	var updated models.User
+	res.Decode(t, &updated)
+	if updated.Version != me.Version+1 {
+		t.Errorf("expected the version to move to %d, got %d", me.Version+1, updated.Version)
+	}
+
+	// A second device still holding the old copy must not overwrite the first edit
+	res = app.Do(http.MethodPut, "/api/v1/user/me", token, map[string]interface{}{"full_name": "Eski Kopya", "version": me.Version})
+	if res.Status != http.StatusConflict {
+		t.Errorf("expected a stale edit to conflict, got %d", res.Status)
+	}
+	app.Do(http.MethodGet, "/api/v1/user/me", token, nil).Decode(t, &me)
+	if me.FullName != "İlk Değişiklik" {
+		t.Errorf("expected the first edit to survive, got %q", me.FullName)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build integration
+
+// Package integration exercises the HTTP API end to end against real Postgres
+// and Redis containers: go test -tags integration ./internal/integration/...
+package integration
+
+import (
+	"os"
+	"testing"
+
+	"github.com/yandas/backend/internal/testutil"
+)
+
+var env *testutil.Environment
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.Run(m, &env))
+}
@@ -0,0 +1,183 @@
+//go:build integration
+
+package integration
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/services"
+)
+
+func TestOrderLifecycle(t *testing.T) {
+	app := env.NewApp(t)
+	customer, err := app.Factory.User()
+	if err != nil {
+		t.Fatal(err)
+	}
+	yandas, err := app.Factory.Yandas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	service, err := app.Factory.Service(yandas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	customerToken := app.Login(*customer.Email)
+	yandasToken := app.Login(*yandas.User.Email)
+
+	res := app.Do(http.MethodPost, "/api/v1/orders", customerToken, map[string]interface{}{
+		"yandas_id":        yandas.ID,
+		"service_id":       service.ID,
+		"location_address": "Kadıköy, İstanbul",
+	})
+	if res.Status != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", res.Status, res.Error)
+	}
+	var order models.Order
+	res.Decode(t, &order)
+	if order.Status != "pending" || order.AgreedPrice != service.BasePrice || order.OrderNumber == "" {
+		t.Fatalf("unexpected order %+v", order)
+	}
+
+	for _, step := range []string{"accept", "start", "complete"} {
+		res := app.Do(http.MethodPost, "/api/v1/yandas/orders/"+order.ID.String()+"/"+step, yandasToken, nil)
+		if res.Status != http.StatusOK {
+			t.Fatalf("%s failed with %d: %s", step, res.Status, res.Error)
+		}
+	}
+
+	res = app.Do(http.MethodPost, "/api/v1/yandas/orders/"+order.ID.String()+"/start", yandasToken, nil)
+	if res.Status != http.StatusConflict {
+		t.Errorf("expected starting a completed order to conflict, got %d", res.Status)
+	}
+
+	res = app.Do(http.MethodGet, "/api/v1/orders/"+order.ID.String(), customerToken, nil)
+	if res.Status != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.Status, res.Error)
+	}
+	res.Decode(t, &order)
+	if order.Status != "completed" || order.PlatformFee == nil || order.NetEarnings == nil {
+		t.Errorf("expected a completed order with its commission split, got %+v", order)
+	}
+
+	res = app.Do(http.MethodGet, "/api/v1/orders/"+order.ID.String(), yandasToken, nil)
+	if res.Status != http.StatusOK {
+		t.Errorf("expected the yandaş to see the order, got %d", res.Status)
+	}
+}
+
+func TestOrderAccess(t *testing.T) {
+	app := env.NewApp(t)
+	customer, err := app.Factory.User()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stranger, err := app.Factory.User()
+	if err != nil {
+		t.Fatal(err)
+	}
+	yandas, err := app.Factory.Yandas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	service, err := app.Factory.Service(yandas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err := app.Factory.Order(customer, service)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strangerToken := app.Login(*stranger.Email)
+	if res := app.Do(http.MethodGet, "/api/v1/orders/"+order.ID.String(), strangerToken, nil); res.Status == http.StatusOK {
+		t.Error("expected another customer not to see the order")
+	}
+	if res := app.Do(http.MethodPost, "/api/v1/orders/"+order.ID.String()+"/cancel", strangerToken, nil); res.Status == http.StatusOK {
+		t.Error("expected another customer not to cancel the order")
+	}
+
+	customerToken := app.Login(*customer.Email)
+	res := app.Do(http.MethodPost, "/api/v1/orders/"+order.ID.String()+"/cancel", customerToken, map[string]string{"reason": "Plan değişti"})
+	if res.Status != http.StatusOK {
+		t.Fatalf("expected the customer to cancel, got %d: %s", res.Status, res.Error)
+	}
+
+	pending, err := app.Factory.Order(customer, service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unavailable, err := app.Factory.Yandas(func(p *models.YandasProfile) { p.ApprovalStatus = "pending" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	unavailableToken := app.Login(*unavailable.User.Email)
+	if res := app.Do(http.MethodPost, "/api/v1/yandas/orders/"+pending.ID.String()+"/accept", unavailableToken, nil); res.Status == http.StatusOK {
+		t.Error("expected another yandaş not to accept the order")
+	}
+}
+
+func TestConcurrentOrderTransitions(t *testing.T) {
+	app := env.NewApp(t)
+	customer, err := app.Factory.User()
+	if err != nil {
+		t.Fatal(err)
+	}
+	yandas, err := app.Factory.Yandas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	service, err := app.Factory.Service(yandas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err := app.Factory.Order(customer, service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	yandasToken := app.Login(*yandas.User.Email)
+
+	// A double-tapped accept: only one may record the transition
+	const requests = 4
+	statuses := make(chan int, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses <- app.Do(http.MethodPost, "/api/v1/yandas/orders/"+order.ID.String()+"/accept", yandasToken, nil).Status
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	won, lost := 0, 0
+	for status := range statuses {
+		switch status {
+		case http.StatusOK:
+			won++
+		case http.StatusConflict:
+			lost++
+		}
+	}
+	if won != 1 || lost != requests-1 {
+		t.Errorf("expected one accept to win and the rest to conflict, got %d won and %d conflicted", won, lost)
+	}
+
+	timeline, err := app.Services.Order.Timeline(order.ID, customer.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accepted := 0
+	for _, event := range timeline {
+		if event.Type == services.OrderEventAccepted {
+			accepted++
+		}
+	}
+	if accepted != 1 {
+		t.Errorf("expected the acceptance to be recorded once, got %d", accepted)
+	}
+}
@@ -5,12 +5,20 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/config"
 	"github.com/yandas/backend/pkg/auth"
 )
 
-// AuthRequired middleware validates JWT token
-func AuthRequired(cfg *config.Config) gin.HandlerFunc {
+// TokenChecker reports whether a validly signed token has since been invalidated
+type TokenChecker interface {
+	IsSessionRevoked(sessionID string) bool
+	IsTokenStale(userID string, tokenVersion int) bool
+}
+
+// AuthRequired middleware validates JWT token and rejects tokens of revoked sessions
+// or tokens issued before the user's role or status changed
+func AuthRequired(cfg *config.Config, tokens TokenChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var tokenString string
 
@@ -37,9 +45,9 @@ func AuthRequired(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		// Validate token
+		// Validate token; refresh tokens are only good for POST /auth/refresh
 		claims, err := auth.ValidateToken(tokenString, cfg.JWTSecret)
-		if err != nil {
+		if err != nil || !claims.IsAccess() {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
 				"error":   "Invalid or expired token",
@@ -48,15 +56,59 @@ func AuthRequired(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		if tokens.IsSessionRevoked(claims.SessionID) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Session has been revoked",
+			})
+			c.Abort()
+			return
+		}
+
+		// Role and status claims are only trusted while the token version is current;
+		// clients get fresh claims by refreshing
+		if tokens.IsTokenStale(claims.UserID, claims.TokenVersion) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Token is outdated, please refresh",
+			})
+			c.Abort()
+			return
+		}
+
 		// Set user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
 		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
 
 		c.Next()
 	}
 }
 
+// AuthOptional sets the user info like AuthRequired when a valid bearer token
+// is sent, and lets the request through signed out otherwise
+func AuthOptional(cfg *config.Config, tokens TokenChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.Next()
+			return
+		}
+		claims, err := auth.ValidateToken(parts[1], cfg.JWTSecret)
+		if err != nil || !claims.IsAccess() || tokens.IsSessionRevoked(claims.SessionID) || tokens.IsTokenStale(claims.UserID, claims.TokenVersion) {
+			c.Next()
+			return
+		}
+
+		c.Set("user_id", claims.UserID)
+		c.Set("email", claims.Email)
+		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
+		c.Next()
+	}
+}
+
 // AdminRequired middleware checks if user is admin
 func AdminRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -73,6 +125,32 @@ func AdminRequired() gin.HandlerFunc {
 	}
 }
 
+// PermissionChecker resolves whether a user's roles grant a permission
+type PermissionChecker interface {
+	HasPermission(userID uuid.UUID, permission string) bool
+}
+
+// PermissionRequired middleware checks that the user holds a permission. Admins hold every permission.
+func PermissionRequired(checker PermissionChecker, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if role, _ := c.Get("role"); role == "admin" {
+			c.Next()
+			return
+		}
+
+		userID, err := uuid.Parse(c.GetString("user_id"))
+		if err != nil || !checker.HasPermission(userID, permission) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Permission required: " + permission,
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 // YandasRequired middleware checks if user is approved yandaş
 func YandasRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
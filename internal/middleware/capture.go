@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yandas/backend/pkg/httpcapture"
+)
+
+// RequestCapturer decides which requests admins are capturing and stores them
+type RequestCapturer interface {
+	Capturing() bool
+	Match(userID, path string) (ruleID string, ok bool)
+	Record(exchange *httpcapture.Exchange)
+}
+
+// captureWriter keeps a copy of the start of the response body
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	w.keep(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.keep([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *captureWriter) keep(data []byte) {
+	if room := httpcapture.MaxBodySize + 1 - w.body.Len(); room > 0 {
+		if len(data) > room {
+			data = data[:room]
+		}
+		w.body.Write(data)
+	}
+}
+
+// RequestCapture records requests matching an active capture rule with
+// their responses. Bodies are only buffered while a rule is active; the user
+// is known once authentication has run, so matching happens afterwards.
+func RequestCapture(capturer RequestCapturer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !capturer.Capturing() || c.IsWebsocket() {
+			c.Next()
+			return
+		}
+
+		var requestBody []byte
+		if c.Request.Body != nil {
+			// Keep one byte more than is recorded so an oversized body is noticed
+			requestBody, _ = io.ReadAll(io.LimitReader(c.Request.Body, httpcapture.MaxBodySize+1))
+			c.Request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(requestBody), c.Request.Body), c.Request.Body}
+		}
+		writer := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		start := time.Now()
+
+		c.Next()
+
+		userID := c.GetString("user_id")
+		ruleID, ok := capturer.Match(userID, c.Request.URL.Path)
+		if !ok {
+			return
+		}
+		capturer.Record(&httpcapture.Exchange{
+			RuleID:          ruleID,
+			UserID:          userID,
+			Method:          c.Request.Method,
+			Path:            c.Request.URL.Path,
+			Query:           httpcapture.Query(c.Request.URL.RawQuery),
+			ClientIP:        c.ClientIP(),
+			Status:          writer.Status(),
+			LatencyMS:       float64(time.Since(start).Microseconds()) / 1000,
+			RequestHeaders:  httpcapture.Headers(c.Request.Header),
+			RequestBody:     httpcapture.Body(c.ContentType(), requestBody),
+			ResponseHeaders: httpcapture.Headers(writer.Header()),
+			ResponseBody:    httpcapture.Body(writer.Header().Get("Content-Type"), writer.body.Bytes()),
+			CapturedAt:      start,
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ConsentChecker reports whether a user still has to accept the current
+// terms and policies
+type ConsentChecker interface {
+	NeedsConsent(userID uuid.UUID) bool
+}
+
+// consentExempt lists the routes, by method and route as registered, a user
+// can use before accepting: reading and accepting the documents, their own
+// profile, and deleting the account instead of accepting
+var consentExempt = map[string]bool{
+	"GET /api/v1/consents":         true,
+	"GET /api/v1/consents/pending": true,
+	"POST /api/v1/consents":        true,
+	"GET /api/v1/user/me":          true,
+	"DELETE /api/v1/user/me":       true,
+}
+
+// ConsentRequired answers 403 with code needs_consent while the signed-in user
+// has not accepted a current document. It runs after AuthRequired.
+func ConsentRequired(checker ConsentChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if consentExempt[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		userID, err := uuid.Parse(c.GetString("user_id"))
+		if err != nil || !checker.NeedsConsent(userID) {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "The updated terms and policies must be accepted",
+			"code":    "needs_consent",
+		})
+	}
+}
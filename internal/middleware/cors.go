@@ -5,23 +5,29 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/yandas/backend/internal/config"
 )
 
-// CORS returns a configured CORS middleware
-func CORS() gin.HandlerFunc {
+// devOrigins are the local frontends, allowed outside release mode only
+var devOrigins = []string{
+	"http://localhost:3000",
+	"http://localhost:3001",
+	"http://localhost:8080",
+	"http://127.0.0.1:3000",
+	"http://127.0.0.1:3001",
+	"http://127.0.0.1:8080",
+}
+
+// CORS returns a CORS middleware allowing the configured origins. Outside
+// release mode the local frontends are allowed as well.
+func CORS(cfg *config.Config) gin.HandlerFunc {
+	origins := append([]string{}, cfg.CORSAllowedOrigins...)
+	if cfg.GinMode != gin.ReleaseMode {
+		origins = append(origins, devOrigins...)
+	}
+
 	return cors.New(cors.Config{
-		AllowOrigins: []string{
-			"http://localhost:3000",
-			"http://localhost:3001",
-			"http://localhost:8080",
-			"http://127.0.0.1:3000",
-			"http://127.0.0.1:3001",
-			"http://127.0.0.1:8080",
-			"https://yandas.app",
-			"https://www.yandas.app",
-			"https://admin.yandas.app",
-			"https://api.yandas.app",
-		},
+		AllowOrigins: origins,
 		AllowMethods: []string{
 			"GET",
 			"POST",
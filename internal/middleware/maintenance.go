@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceChecker reports whether maintenance mode is on and when it is expected to end
+type MaintenanceChecker interface {
+	MaintenanceActive() (active bool, endsAt *time.Time)
+}
+
+// maintenanceAllowed lists path prefixes served during maintenance: health
+// checks, the admin panel and the logins it needs, provider callbacks that
+// must not be lost, and the WebSocket so clients hear when it ends
+var maintenanceAllowed = []string{
+	"/health",
+	"/api/v1/admin",
+	"/api/v1/auth/login",
+	"/api/v1/auth/refresh",
+	"/api/v1/sms/status/",
+	"/api/v1/email/events/",
+	"/api/v1/subscription/webhook",
+	"/api/v1/ws",
+}
+
+var maintenanceMessages = map[string]string{
+	"tr": "Yandaş şu anda bakımda. Lütfen kısa bir süre sonra tekrar deneyin.",
+	"en": "Yandaş is down for maintenance. Please try again shortly.",
+}
+
+// Maintenance answers every request outside the allowlist with a 503 while
+// maintenance mode is on
+func Maintenance(checker MaintenanceChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		for _, prefix := range maintenanceAllowed {
+			if strings.HasPrefix(path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
+		active, endsAt := checker.MaintenanceActive()
+		if !active {
+			c.Next()
+			return
+		}
+
+		if endsAt != nil {
+			if wait := time.Until(*endsAt); wait > 0 {
+				c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"error":   maintenanceMessages[language(c.GetHeader("Accept-Language"))],
+			"code":    "maintenance",
+			"ends_at": endsAt,
+		})
+	}
+}
+
+// language picks the first supported language of an Accept-Language header,
+// falling back to Turkish
+func language(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		base := strings.SplitN(tag, "-", 2)[0]
+		if _, ok := maintenanceMessages[base]; ok {
+			return base
+		}
+	}
+	return "tr"
+}
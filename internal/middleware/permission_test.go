@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// grants is a PermissionChecker holding each user's permissions
+type grants map[uuid.UUID][]string
+
+func (g grants) HasPermission(userID uuid.UUID, permission string) bool {
+	for _, p := range g[userID] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPermissionRequired(t *testing.T) {
+	support, moderator := uuid.New(), uuid.New()
+	checker := grants{support: {"support.manage"}, moderator: {"reviews.moderate"}}
+
+	tests := []struct {
+		name   string
+		userID string
+		role   string
+		want   int
+	}{
+		{"admin holds every permission", uuid.NewString(), "admin", http.StatusOK},
+		{"granted", support.String(), "customer", http.StatusOK},
+		{"missing the permission", moderator.String(), "customer", http.StatusForbidden},
+		{"no roles", uuid.NewString(), "yandas", http.StatusForbidden},
+		{"unauthenticated", "", "", http.StatusForbidden},
+		{"malformed user id", "not-a-uuid", "customer", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.userID != "" {
+					c.Set("user_id", tt.userID)
+				}
+				if tt.role != "" {
+					c.Set("role", tt.role)
+				}
+			})
+			router.GET("/admin/tickets", PermissionRequired(checker, "support.manage"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/tickets", nil))
+			if w.Code != tt.want {
+				t.Errorf("got %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
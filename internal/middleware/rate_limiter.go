@@ -8,11 +8,15 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
-	"github.com/yandas/backend/internal/config"
 )
 
+// RateLimits supplies the current per-IP request limit, which admins can change at runtime
+type RateLimits interface {
+	RateLimit() (requests int, window time.Duration)
+}
+
 // RateLimiter middleware limits request rate per IP
-func RateLimiter(cfg *config.Config, redisClient *redis.Client) gin.HandlerFunc {
+func RateLimiter(limits RateLimits, redisClient *redis.Client) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if redisClient == nil {
 			// Skip rate limiting if Redis is not available
@@ -20,6 +24,7 @@ func RateLimiter(cfg *config.Config, redisClient *redis.Client) gin.HandlerFunc
 			return
 		}
 
+		limit, window := limits.RateLimit()
 		ip := c.ClientIP()
 		key := fmt.Sprintf("rate_limit:%s", ip)
 
@@ -32,7 +37,7 @@ func RateLimiter(cfg *config.Config, redisClient *redis.Client) gin.HandlerFunc
 			return
 		}
 
-		if count >= cfg.RateLimitRequests {
+		if count >= limit {
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"success": false,
 				"error":   "Too many requests. Please try again later.",
@@ -45,13 +50,13 @@ func RateLimiter(cfg *config.Config, redisClient *redis.Client) gin.HandlerFunc
 		pipe := redisClient.Pipeline()
 		pipe.Incr(ctx, key)
 		if count == 0 {
-			pipe.Expire(ctx, key, time.Duration(cfg.RateLimitWindow)*time.Second)
+			pipe.Expire(ctx, key, window)
 		}
 		pipe.Exec(ctx)
 
 		// Set rate limit headers
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", cfg.RateLimitRequests))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", cfg.RateLimitRequests-count-1))
+		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", limit))
+		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", limit-count-1))
 
 		c.Next()
 	}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxAge   time.Duration
+		tls      bool
+		proto    string
+		wantHSTS string
+	}{
+		{name: "plain HTTP", maxAge: time.Hour},
+		{name: "TLS", maxAge: time.Hour, tls: true, wantHSTS: "max-age=3600; includeSubDomains"},
+		{name: "behind a TLS proxy", maxAge: time.Hour, proto: "https", wantHSTS: "max-age=3600; includeSubDomains"},
+		{name: "HSTS off", tls: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(SecurityHeaders(tt.maxAge))
+			router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			// Error responses carry the headers as well
+			for _, target := range []string{"/ok", "/missing"} {
+				req := httptest.NewRequest(http.MethodGet, target, nil)
+				if tt.tls {
+					req.TLS = &tls.ConnectionState{}
+				}
+				if tt.proto != "" {
+					req.Header.Set("X-Forwarded-Proto", tt.proto)
+				}
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				want := map[string]string{
+					"X-Content-Type-Options":    "nosniff",
+					"X-Frame-Options":           "DENY",
+					"Content-Security-Policy":   "frame-ancestors 'none'",
+					"Referrer-Policy":           "no-referrer",
+					"Strict-Transport-Security": tt.wantHSTS,
+				}
+				for header, value := range want {
+					if got := w.Header().Get(header); got != value {
+						t.Errorf("%s %s: got %q, want %q", target, header, got, value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHidePrefix(t *testing.T) {
+	router := gin.New()
+	router.Use(HidePrefix("/uploads/documents/"))
+	router.NoRoute(func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/uploads/documents/id.pdf", http.StatusNotFound},
+		{"/uploads/documents", http.StatusNotFound},
+		{"//uploads/documents/id.pdf", http.StatusNotFound},
+		{"/uploads//documents/id.pdf", http.StatusNotFound},
+		{"/uploads/./documents/id.pdf", http.StatusNotFound},
+		{"/uploads/x/../documents/id.pdf", http.StatusNotFound},
+		{"/uploads/avatars/a.png", http.StatusOK},
+		{"/uploads/documents-public/a.png", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.URL.Path = tt.path
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.path, w.Code, tt.want)
+		}
+	}
+}
+
+func TestRequireJSON(t *testing.T) {
+	router := gin.New()
+	router.Use(RequireJSON())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/v1/orders", ok)
+	router.HEAD("/api/v1/orders", ok)
+	router.POST("/api/v1/orders", ok)
+	router.POST("/api/v1/user/me/avatar", ok)
+	router.POST("/api/v1/sms/status/:provider", ok)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		want        int
+	}{
+		{"GET without a body", http.MethodGet, "/api/v1/orders", "", "", http.StatusOK},
+		{"HEAD without a body", http.MethodHead, "/api/v1/orders", "", "", http.StatusOK},
+		{"JSON", http.MethodPost, "/api/v1/orders", "application/json", `{}`, http.StatusOK},
+		{"JSON with charset", http.MethodPost, "/api/v1/orders", "application/json; charset=utf-8", `{}`, http.StatusOK},
+		{"empty POST", http.MethodPost, "/api/v1/orders", "", "", http.StatusOK},
+		{"form", http.MethodPost, "/api/v1/orders", "application/x-www-form-urlencoded", "a=1", http.StatusUnsupportedMediaType},
+		{"text/plain", http.MethodPost, "/api/v1/orders", "text/plain", `{}`, http.StatusUnsupportedMediaType},
+		{"no content type", http.MethodPost, "/api/v1/orders", "", `{}`, http.StatusUnsupportedMediaType},
+		{"multipart upload", http.MethodPost, "/api/v1/user/me/avatar", "multipart/form-data; boundary=x", "--x--", http.StatusOK},
+		{"JSON upload route", http.MethodPost, "/api/v1/user/me/avatar", "application/json", `{}`, http.StatusOK},
+		{"form on an upload route", http.MethodPost, "/api/v1/user/me/avatar", "text/plain", "a", http.StatusUnsupportedMediaType},
+		{"multipart elsewhere", http.MethodPost, "/api/v1/orders", "multipart/form-data; boundary=x", "--x--", http.StatusUnsupportedMediaType},
+		{"provider callback", http.MethodPost, "/api/v1/sms/status/netgsm", "application/x-www-form-urlencoded", "a=1", http.StatusOK},
+	}
+	for _, tt := range tests {
+		var body io.Reader
+		if tt.body != "" {
+			body = strings.NewReader(tt.body)
+		}
+		req := httptest.NewRequest(tt.method, tt.path, body)
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+// A page of 20 yandaşlar with a typical catalogue, rendered the way the list
+// endpoint used to (full profiles with user and services) and as list items.
+const benchPageSize = 20
+
+func benchProfiles() []YandasProfile {
+	email, phone, avatar := "yandas@example.com", "+905551112233", "/uploads/avatars/a.jpg"
+	bio := "Ankara'da 8 yıllık deneyimle taşıma, montaj ve ev işleri."
+	profiles := make([]YandasProfile, benchPageSize)
+	for i := range profiles {
+		id := uuid.New()
+		services := make([]YandasService, 6)
+		for j := range services {
+			desc := "Hizmet açıklaması, kapsam ve koşullar burada yer alır."
+			services[j] = YandasService{
+				ID:          uuid.New(),
+				YandasID:    id,
+				Title:       fmt.Sprintf("Hizmet %d", j),
+				Description: &desc,
+				BasePrice:   currency.Money(25000 + j*5000),
+				Currency:    "TRY",
+				Includes:    []string{"Malzeme", "Ulaşım", "Temizlik"},
+				IsActive:    true,
+				Category:    &Category{ID: uuid.New(), Name: "Ev Hizmetleri", Slug: "ev-hizmetleri"},
+			}
+		}
+		profiles[i] = YandasProfile{
+			ID:            id,
+			UserID:        uuid.New(),
+			Bio:           &bio,
+			RatingAvg:     4.7,
+			TotalJobs:     120,
+			IsAvailable:   true,
+			ServiceCities: []string{"Ankara", "Eskişehir"},
+			User:          User{FullName: "Ayşe Yılmaz", Email: &email, Phone: &phone, AvatarURL: &avatar},
+			Services:      services,
+		}
+	}
+	return profiles
+}
+
+func benchListItems(profiles []YandasProfile) []YandasListItem {
+	items := make([]YandasListItem, len(profiles))
+	for i, p := range profiles {
+		price := p.Services[0].BasePrice
+		items[i] = YandasListItem{
+			ID:            p.ID,
+			UserID:        p.UserID,
+			FullName:      p.User.FullName,
+			AvatarURL:     p.User.AvatarURL,
+			RatingAvg:     p.RatingAvg,
+			TotalJobs:     p.TotalJobs,
+			IsAvailable:   p.IsAvailable,
+			ServiceCities: p.ServiceCities,
+			StartingPrice: &price,
+		}
+	}
+	return items
+}
+
+func benchmarkEncode(b *testing.B, page interface{}) {
+	b.ReportAllocs()
+	var size int
+	for i := 0; i < b.N; i++ {
+		data, err := json.Marshal(page)
+		if err != nil {
+			b.Fatal(err)
+		}
+		size = len(data)
+	}
+	b.ReportMetric(float64(size), "bytes/page")
+}
+
+func BenchmarkYandasListPayload(b *testing.B) {
+	profiles := benchProfiles()
+	b.Run("full_profiles", func(b *testing.B) { benchmarkEncode(b, profiles) })
+	b.Run("list_items", func(b *testing.B) { benchmarkEncode(b, benchListItems(profiles)) })
+}
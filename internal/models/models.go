@@ -5,6 +5,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"github.com/yandas/backend/pkg/crypto"
+	"github.com/yandas/backend/pkg/currency"
 	"gorm.io/gorm"
 )
 
@@ -12,71 +14,249 @@ import (
 type User struct {
 	ID           uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	Email        *string        `gorm:"uniqueIndex;size:255" json:"email,omitempty"`
-	Phone        *string        `gorm:"uniqueIndex;size:20" json:"phone,omitempty"`
+	Phone        *string        `gorm:"serializer:encrypted;size:255" json:"phone,omitempty"`
+	PhoneHash    *string        `gorm:"uniqueIndex;size:64" json:"-"` // blind index of Phone, for lookups
 	PasswordHash string         `gorm:"size:255;not null" json:"-"`
 	FullName     string         `gorm:"size:255;not null" json:"full_name"`
 	AvatarURL    *string        `gorm:"type:text" json:"avatar_url,omitempty"`
 	Role         string         `gorm:"size:20;default:customer" json:"role"` // customer, yandas, admin
 	IsVerified   bool           `gorm:"default:false" json:"is_verified"`
 	IsActive     bool           `gorm:"default:true" json:"is_active"`
+	TokenVersion int            `gorm:"not null;default:0" json:"-"`       // bumped on role/status changes to invalidate issued tokens
+	Version      int            `gorm:"not null;default:1" json:"version"` // bumped on every update; stale writes are refused
 	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// Set when the email address hard-bounced or reported spam; nothing is emailed to it until it changes
+	EmailUndeliverableAt     *time.Time `json:"email_undeliverable_at,omitempty"`
+	EmailUndeliverableReason *string    `gorm:"size:255" json:"email_undeliverable_reason,omitempty"`
+
 	// Relations
 	YandasProfile *YandasProfile `gorm:"foreignKey:UserID" json:"yandas_profile,omitempty"`
 	DeviceTokens  []DeviceToken  `gorm:"foreignKey:UserID" json:"-"`
 	Subscription  *Subscription  `gorm:"foreignKey:UserID" json:"subscription,omitempty"`
 }
 
+// BeforeSave keeps the phone's blind index in step with the phone
+func (u *User) BeforeSave(*gorm.DB) error {
+	u.PhoneHash = crypto.BlindIndex(u.Phone)
+	return nil
+}
+
+// Address represents a saved customer location
+type Address struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Label       string    `gorm:"size:50;not null" json:"label"` // e.g. Ev, İş
+	AddressText string    `gorm:"type:text;not null" json:"address_text"`
+	Latitude    *float64  `gorm:"type:decimal(10,8)" json:"latitude,omitempty"`
+	Longitude   *float64  `gorm:"type:decimal(11,8)" json:"longitude,omitempty"`
+	City        string    `gorm:"size:100" json:"city"`
+	IsDefault   bool      `gorm:"default:false" json:"is_default"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
 // YandasProfile contains extended data for Yandaş users
 type YandasProfile struct {
 	ID                uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	UserID            uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"`
+	Slug              *string   `gorm:"size:120;uniqueIndex" json:"slug,omitempty"` // public profile URL, set on application and never changed
 	Bio               *string   `gorm:"type:text" json:"bio,omitempty"`
 	InstagramHandle   *string   `gorm:"size:100" json:"instagram_handle,omitempty"`
 	InstagramVerified bool      `gorm:"default:false" json:"instagram_verified"`
 	// Document URLs - Front and Back for ID and License
-	KimlikOnURL     *string `gorm:"type:text" json:"-"` // ID Card Front
-	KimlikArkaURL   *string `gorm:"type:text" json:"-"` // ID Card Back
-	EhliyetOnURL    *string `gorm:"type:text" json:"-"` // Driver's License Front
-	EhliyetArkaURL  *string `gorm:"type:text" json:"-"` // Driver's License Back
-	AdliSicilPDFURL *string `gorm:"type:text" json:"-"` // Criminal Record PDF
+	KimlikOnURL     *string `gorm:"serializer:encrypted;type:text" json:"-"` // ID Card Front
+	KimlikArkaURL   *string `gorm:"serializer:encrypted;type:text" json:"-"` // ID Card Back
+	EhliyetOnURL    *string `gorm:"serializer:encrypted;type:text" json:"-"` // Driver's License Front
+	EhliyetArkaURL  *string `gorm:"serializer:encrypted;type:text" json:"-"` // Driver's License Back
+	AdliSicilPDFURL *string `gorm:"serializer:encrypted;type:text" json:"-"` // Criminal Record PDF
+	SelfieURL       *string `gorm:"serializer:encrypted;type:text" json:"-"` // Selfie for the identity check
 	// Verification status for each document
-	KimlikOnVerified    bool           `gorm:"default:false" json:"kimlik_on_verified"`
-	KimlikArkaVerified  bool           `gorm:"default:false" json:"kimlik_arka_verified"`
-	EhliyetOnVerified   bool           `gorm:"default:false" json:"ehliyet_on_verified"`
-	EhliyetArkaVerified bool           `gorm:"default:false" json:"ehliyet_arka_verified"`
-	AdliSicilVerified   bool           `gorm:"default:false" json:"adli_sicil_verified"`
-	ApprovalStatus      string         `gorm:"size:20;default:pending" json:"approval_status"` // pending, approved, rejected
-	ApprovedBy          *uuid.UUID     `gorm:"type:uuid" json:"-"`
-	ApprovedAt          *time.Time     `json:"approved_at,omitempty"`
-	RejectionReason     *string        `gorm:"type:text" json:"rejection_reason,omitempty"`
-	RatingAvg           float64        `gorm:"type:decimal(3,2);default:0" json:"rating_avg"`
-	TotalJobs           int            `gorm:"default:0" json:"total_jobs"`
-	IsAvailable         bool           `gorm:"default:false" json:"is_available"`
-	Latitude            *float64       `gorm:"type:decimal(10,8)" json:"latitude,omitempty"`
-	Longitude           *float64       `gorm:"type:decimal(11,8)" json:"longitude,omitempty"`
-	ServiceCities       pq.StringArray `gorm:"type:text[]" json:"service_cities"`
-	CreatedAt           time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	KimlikOnVerified    bool            `gorm:"default:false" json:"kimlik_on_verified"`
+	KimlikArkaVerified  bool            `gorm:"default:false" json:"kimlik_arka_verified"`
+	EhliyetOnVerified   bool            `gorm:"default:false" json:"ehliyet_on_verified"`
+	EhliyetArkaVerified bool            `gorm:"default:false" json:"ehliyet_arka_verified"`
+	AdliSicilVerified   bool            `gorm:"default:false" json:"adli_sicil_verified"`
+	AdliSicilUploadedAt *time.Time      `json:"adli_sicil_uploaded_at,omitempty"` // criminal records go stale and are renewed yearly
+	KYCProvider         *string         `gorm:"size:30" json:"-"`                 // selfie and ID liveness check, shown to admins only
+	KYCReference        *string         `gorm:"size:100;index" json:"-"`          // the check's ID on the provider side
+	KYCStatus           *string         `gorm:"size:20" json:"-"`                 // pending, verified, rejected, skipped, failed
+	KYCScore            *float64        `gorm:"type:decimal(5,4)" json:"-"`       // face match confidence from 0 to 1
+	KYCReason           *string         `gorm:"type:text" json:"-"`
+	KYCCheckedAt        *time.Time      `json:"-"`
+	ApprovalStatus      string          `gorm:"size:20;default:pending" json:"approval_status"` // pending, approved, rejected
+	ApprovedBy          *uuid.UUID      `gorm:"type:uuid" json:"-"`
+	ApprovedAt          *time.Time      `json:"approved_at,omitempty"`
+	RejectionReason     *string         `gorm:"type:text" json:"rejection_reason,omitempty"`
+	RatingAvg           float64         `gorm:"type:decimal(3,2);default:0" json:"rating_avg"`
+	TotalJobs           int             `gorm:"default:0" json:"total_jobs"`
+	IsAvailable         bool            `gorm:"default:false" json:"is_available"`
+	VacationFrom        *time.Time      `json:"vacation_from,omitempty"` // a planned absence; listings show the yandaş back on VacationUntil
+	VacationUntil       *time.Time      `json:"vacation_until,omitempty"`
+	VacationPolicy      *string         `gorm:"size:20" json:"vacation_policy,omitempty"` // reject, reschedule: what happens to pending orders
+	VacationStartedAt   *time.Time      `json:"vacation_started_at,omitempty"`            // set when the vacation began and availability was turned off
+	Latitude            *float64        `gorm:"type:decimal(10,8);index:idx_yandas_profiles_location,priority:1" json:"latitude,omitempty"`
+	Longitude           *float64        `gorm:"type:decimal(11,8);index:idx_yandas_profiles_location,priority:2" json:"longitude,omitempty"`
+	ServiceCities       pq.StringArray  `gorm:"type:text[];index:idx_yandas_profiles_service_cities,type:gin" json:"service_cities"`
+	BaseLatitude        *float64        `gorm:"type:decimal(10,8)" json:"-"` // centre of the service area, kept private
+	BaseLongitude       *float64        `gorm:"type:decimal(11,8)" json:"-"`
+	ServiceRadiusKm     *float64        `gorm:"type:decimal(6,2)" json:"service_radius_km,omitempty"` // orders farther from the base are refused
+	TravelFeePerKm      *currency.Money `gorm:"type:bigint" json:"travel_fee_per_km,omitempty"`       // charged on the distance from the base
+	MinOrderValue       *currency.Money `gorm:"type:bigint" json:"min_order_value,omitempty"`         // orders totalling less are refused
+	MinOrderCurrency    *string         `gorm:"size:3" json:"min_order_currency,omitempty"`           // currency of MinOrderValue; TRY when unset
+	InstantBooking      bool            `gorm:"default:false" json:"instant_booking"`                 // orders are accepted without the yandaş's approval
+	LeadTimeMinutes     int             `gorm:"default:0" json:"lead_time_minutes"`                   // notice an order needs; 0 takes orders for right away
+	Version             int             `gorm:"not null;default:1" json:"version"`                    // bumped on every update; stale writes are refused
+	CategoryIDs         pq.StringArray  `gorm:"type:text[]" json:"category_ids,omitempty"`            // categories applied for; their forms were answered
+	CreatedAt           time.Time       `gorm:"autoCreateTime" json:"created_at"`
 
 	// Relations
-	User     User            `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Services []YandasService `gorm:"foreignKey:YandasID" json:"services,omitempty"`
+	User      User               `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Services  []YandasService    `gorm:"foreignKey:YandasID" json:"services,omitempty"`
+	Screening *DocumentScreening `gorm:"foreignKey:YandasProfileID" json:"screening,omitempty"` // admin views only
+}
+
+// YandasListItem is the lightweight projection of a yandaş used by listing and
+// search results; the detail endpoint returns the full profile with services
+type YandasListItem struct {
+	ID            uuid.UUID       `json:"id"`
+	UserID        uuid.UUID       `json:"user_id"`
+	Slug          *string         `json:"slug,omitempty"`
+	FullName      string          `json:"full_name"`
+	AvatarURL     *string         `json:"avatar_url,omitempty"`
+	RatingAvg     float64         `json:"rating_avg"`
+	TotalJobs     int             `json:"total_jobs"`
+	IsAvailable   bool            `json:"is_available"`
+	BackOn        *time.Time      `json:"back_on,omitempty"` // end of the vacation the yandaş is on
+	ServiceCities pq.StringArray  `gorm:"type:text[]" json:"service_cities"`
+	StartingPrice *currency.Money `json:"starting_price,omitempty"` // lowest active service base price
+}
+
+// DocumentScreening holds the OCR pre-screen result for a yandaş application
+type DocumentScreening struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	YandasProfileID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"yandas_profile_id"`
+	Provider        string    `gorm:"size:30;not null" json:"provider"`
+	Status          string    `gorm:"size:20;not null" json:"status"` // passed, mismatch, unreadable, skipped, failed
+	ExtractedName   *string   `gorm:"size:255" json:"extracted_name,omitempty"`
+	IDNumberMasked  *string   `gorm:"size:20" json:"id_number_masked,omitempty"`
+	IDNumberValid   bool      `gorm:"not null" json:"id_number_valid"`
+	NameMatch       bool      `gorm:"not null" json:"name_match"`
+	Error           *string   `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// DocumentRenewal asks a yandaş to upload a fresh copy of a document that
+// has become too old. A renewal not made by the deadline lapses and takes the
+// yandaş offline until the document is renewed or an admin waives it.
+type DocumentRenewal struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	YandasProfileID uuid.UUID  `gorm:"type:uuid;not null;index" json:"yandas_profile_id"`
+	Document        string     `gorm:"size:30;not null" json:"document"`                                                      // adli_sicil
+	Status          string     `gorm:"size:20;not null;index:idx_document_renewals_status_deadline,priority:1" json:"status"` // requested, lapsed, renewed, waived
+	Deadline        time.Time  `gorm:"not null;index:idx_document_renewals_status_deadline,priority:2" json:"deadline"`
+	RenewedAt       *time.Time `json:"renewed_at,omitempty"`
+	LapsedAt        *time.Time `json:"lapsed_at,omitempty"`
+	WaivedBy        *uuid.UUID `gorm:"type:uuid" json:"waived_by,omitempty"`
+	WaivedUntil     *time.Time `json:"waived_until,omitempty"` // no new renewal is requested before this
+	WaiverReason    *string    `gorm:"type:text" json:"waiver_reason,omitempty"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	YandasProfile *YandasProfile `gorm:"foreignKey:YandasProfileID" json:"yandas_profile,omitempty"`
 }
 
 // Category represents service categories
 type Category struct {
-	ID            uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	ParentID      *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"`
-	Name          string     `gorm:"size:100;not null" json:"name"`
-	NameEN        *string    `gorm:"size:100" json:"name_en,omitempty"`
-	Slug          string     `gorm:"size:100;uniqueIndex;not null" json:"slug"`
-	Icon          *string    `gorm:"size:50" json:"icon,omitempty"`
-	Description   *string    `gorm:"type:text" json:"description,omitempty"`
-	IsActive      bool       `gorm:"default:true" json:"is_active"`
-	SortOrder     int        `gorm:"default:0" json:"sort_order"`
-	SubCategories []Category `gorm:"foreignKey:ParentID" json:"sub_categories,omitempty"`
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ParentID       *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	Name           string     `gorm:"size:100;not null" json:"name"`
+	NameEN         *string    `gorm:"size:100" json:"name_en,omitempty"`
+	Slug           string     `gorm:"size:100;uniqueIndex;not null" json:"slug"`
+	Icon           *string    `gorm:"size:50" json:"icon,omitempty"`
+	Description    *string    `gorm:"type:text" json:"description,omitempty"`
+	IsActive       bool       `gorm:"default:true" json:"is_active"`
+	SortOrder      int        `gorm:"default:0" json:"sort_order"`
+	CommissionRate *float64   `gorm:"type:decimal(5,4)" json:"commission_rate,omitempty"` // overrides the global rate when set
+	HighDemand     bool       `gorm:"-" json:"high_demand,omitempty"`                     // set when listing for a city
+	SubCategories  []Category `gorm:"foreignKey:ParentID" json:"sub_categories,omitempty"`
+}
+
+// YandasQualityScore is a yandaş's composite quality score over recent orders,
+// combining rating, completion, response time, disputes and cancellations.
+// The whole table is recomputed on a schedule.
+type YandasQualityScore struct {
+	YandasID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"yandas_id"`
+	RatingAvg        float64   `gorm:"type:decimal(3,2);not null;default:0" json:"rating_avg"`
+	ReviewCount      int64     `gorm:"not null;default:0" json:"review_count"`
+	OrderCount       int64     `gorm:"not null;default:0" json:"order_count"` // orders created in the scoring window
+	EndedCount       int64     `gorm:"not null;default:0" json:"ended_count"` // completed, cancelled or disputed
+	CompletedCount   int64     `gorm:"not null;default:0" json:"completed_count"`
+	CancelledCount   int64     `gorm:"not null;default:0" json:"cancelled_count"`            // cancelled by the yandaş
+	DisputedCount    int64     `gorm:"not null;default:0" json:"disputed_count"`             // disputed or taken to support
+	ResponseMinutes  *float64  `gorm:"type:decimal(10,2)" json:"response_minutes,omitempty"` // median time to accept an order
+	CompletionRate   float64   `gorm:"type:decimal(5,4);not null;default:0" json:"completion_rate"`
+	CancellationRate float64   `gorm:"type:decimal(5,4);not null;default:0" json:"cancellation_rate"`
+	DisputeRate      float64   `gorm:"type:decimal(5,4);not null;default:0" json:"dispute_rate"`
+	Score            float64   `gorm:"type:decimal(5,2);not null;default:0;index" json:"score"` // 0 to 100
+	ComputedAt       time.Time `gorm:"not null" json:"computed_at"`
+}
+
+// CategoryDocumentRule makes a document mandatory for applicants to a
+// category, e.g. the driving licence for driving categories
+type CategoryDocumentRule struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CategoryID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_category_document_rules_category_document,priority:1" json:"category_id"`
+	Document   string    `gorm:"size:30;not null;uniqueIndex:idx_category_document_rules_category_document,priority:2" json:"document"` // kimlik_on, kimlik_arka, ehliyet_on, ehliyet_arka, adli_sicil_pdf, selfie
+	Hint       *string   `gorm:"size:300" json:"hint,omitempty"`                                                                        // shown to applicants missing the document
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ApplicationQuestion is a question a category asks in the yandaş application
+// form, set up by admins
+type ApplicationQuestion struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CategoryID uuid.UUID      `gorm:"type:uuid;not null;index" json:"category_id"`
+	Label      string         `gorm:"size:200;not null" json:"label"`
+	HelpText   *string        `gorm:"size:500" json:"help_text,omitempty"`
+	Type       string         `gorm:"size:20;not null" json:"type"`         // text, number, yes_no, choice, file
+	Options    pq.StringArray `gorm:"type:text[]" json:"options,omitempty"` // the answers a choice question accepts
+	IsRequired bool           `gorm:"not null;default:false" json:"is_required"`
+	SortOrder  int            `gorm:"default:0" json:"sort_order"`
+	IsActive   bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ApplicationAnswer is an applicant's answer to an application question. The
+// question is copied so later edits don't change what the applicant answered.
+type ApplicationAnswer struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	YandasProfileID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_application_answers_profile_question,priority:1" json:"yandas_profile_id"`
+	QuestionID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_application_answers_profile_question,priority:2" json:"question_id"`
+	CategoryID      uuid.UUID `gorm:"type:uuid;not null" json:"category_id"`
+	Question        string    `gorm:"size:200;not null" json:"question"`
+	Type            string    `gorm:"size:20;not null" json:"type"`
+	Value           string    `gorm:"serializer:encrypted;type:text;not null" json:"value"` // an upload URL for file questions
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// CategoryDemand compares open orders with available yandaşlar for a category
+// in a city. The whole table is recomputed on a schedule.
+type CategoryDemand struct {
+	City            string    `gorm:"size:100;primaryKey" json:"city"`
+	CategoryID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"category_id"`
+	OpenOrders      int64     `gorm:"not null;default:0" json:"open_orders"`
+	AvailableYandas int64     `gorm:"not null;default:0" json:"available_yandas"`
+	Ratio           float64   `gorm:"type:decimal(8,2);not null;default:0" json:"ratio"` // open orders per available yandaş
+	Level           string    `gorm:"size:10;not null" json:"level"`                     // low, medium, high
+	ComputedAt      time.Time `gorm:"not null" json:"computed_at"`
+
+	// Relations
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
 }
 
 // YandasService represents a service/package offered by a Yandaş
@@ -86,63 +266,348 @@ type YandasService struct {
 	CategoryID      uuid.UUID      `gorm:"type:uuid" json:"category_id"`
 	Title           string         `gorm:"size:255;not null" json:"title"`
 	Description     *string        `gorm:"type:text" json:"description,omitempty"`
-	BasePrice       float64        `gorm:"type:decimal(10,2);not null" json:"base_price"`
+	BasePrice       currency.Money `gorm:"type:bigint;not null" json:"base_price"`
 	Currency        string         `gorm:"size:3;default:TRY" json:"currency"`
 	DurationMinutes *int           `json:"duration_minutes,omitempty"`
 	Includes        pq.StringArray `gorm:"type:text[]" json:"includes,omitempty"`
 	IsActive        bool           `gorm:"default:true" json:"is_active"`
+	Status          string         `gorm:"size:20;not null;default:active;index" json:"status"` // draft, pending, active, rejected; only active ones are listed
+	RejectionReason *string        `gorm:"type:text" json:"rejection_reason,omitempty"`
+	ArchivedAt      *time.Time     `json:"archived_at,omitempty"`             // hidden by the yandaş; can be unarchived
+	DeletedAt       *time.Time     `gorm:"index" json:"-"`                    // removed by the yandaş; past orders still load it
+	Version         int            `gorm:"not null;default:1" json:"version"` // bumped on every update; stale writes are refused
 	CreatedAt       time.Time      `gorm:"autoCreateTime" json:"created_at"`
 
+	// Set when listing for a customer location
+	DistanceKm  *float64        `gorm:"-" json:"distance_km,omitempty"`
+	TravelFee   *currency.Money `gorm:"-" json:"travel_fee,omitempty"`
+	OutsideArea bool            `gorm:"-" json:"outside_service_area,omitempty"`
+
 	// Relations
-	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Category   *Category          `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Options    []ServiceOption    `gorm:"foreignKey:ServiceID" json:"options,omitempty"`
+	PriceTiers []ServicePriceTier `gorm:"foreignKey:ServiceID" json:"price_tiers,omitempty"`
+}
+
+// ServiceListItem is a catalogue entry: a service with its category and the
+// yandaş offering it
+type ServiceListItem struct {
+	ID              uuid.UUID      `json:"id"`
+	Title           string         `json:"title"`
+	BasePrice       currency.Money `json:"base_price"`
+	Currency        string         `json:"currency"`
+	DurationMinutes *int           `json:"duration_minutes,omitempty"`
+	CategoryID      uuid.UUID      `json:"category_id"`
+	CategoryName    string         `json:"category_name"`
+	CategorySlug    string         `json:"category_slug"`
+	YandasID        uuid.UUID      `json:"yandas_id"`
+	YandasName      string         `json:"yandas_name"`
+	YandasAvatarURL *string        `json:"yandas_avatar_url,omitempty"`
+	YandasRating    float64        `json:"yandas_rating"`
+	YandasTotalJobs int            `json:"yandas_total_jobs"`
+}
+
+// ServiceOption is a priced add-on a customer can select with a service
+type ServiceOption struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ServiceID uuid.UUID      `gorm:"type:uuid;not null;index" json:"service_id"`
+	Name      string         `gorm:"size:255;not null" json:"name"`
+	Price     currency.Money `gorm:"type:bigint;not null" json:"price"`
+	IsActive  bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ServicePriceTier adds a surcharge when an order's duration or distance falls within a band
+type ServicePriceTier struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ServiceID uuid.UUID      `gorm:"type:uuid;not null;index" json:"service_id"`
+	Basis     string         `gorm:"size:20;not null" json:"basis"` // duration (minutes), distance (km)
+	UpTo      float64        `gorm:"type:decimal(10,2);not null" json:"up_to"`
+	Surcharge currency.Money `gorm:"type:bigint;not null" json:"surcharge"`
 }
 
 // Order represents a booking/order
 type Order struct {
 	ID                 uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	OrderNumber        string         `gorm:"size:20;uniqueIndex;not null" json:"order_number"`
-	CustomerID         uuid.UUID      `gorm:"type:uuid;not null" json:"customer_id"`
-	YandasID           uuid.UUID      `gorm:"type:uuid;not null" json:"yandas_id"`
+	CustomerID         uuid.UUID      `gorm:"type:uuid;not null;index:idx_orders_customer_status,priority:1" json:"customer_id"`
+	YandasID           uuid.UUID      `gorm:"type:uuid;not null;index:idx_orders_yandas_status_created,priority:1" json:"yandas_id"`
 	ServiceID          uuid.UUID      `gorm:"type:uuid" json:"service_id"`
-	Status             string         `gorm:"size:30;default:pending" json:"status"` // pending, accepted, in_progress, completed, cancelled, disputed
-	AgreedPrice        float64        `gorm:"type:decimal(10,2);not null" json:"agreed_price"`
+	Status             string         `gorm:"size:30;default:pending;index:idx_orders_customer_status,priority:2;index:idx_orders_yandas_status_created,priority:2" json:"status"` // pending, accepted, in_progress, pending_confirmation, completed, cancelled, disputed
+	AgreedPrice        currency.Money `gorm:"type:bigint;not null" json:"agreed_price"`
 	Currency           string         `gorm:"size:3;default:TRY" json:"currency"`
 	LocationAddress    *string        `gorm:"type:text" json:"location_address,omitempty"`
 	Latitude           *float64       `gorm:"type:decimal(10,8)" json:"latitude,omitempty"`
 	Longitude          *float64       `gorm:"type:decimal(11,8)" json:"longitude,omitempty"`
 	ScheduledAt        *time.Time     `json:"scheduled_at,omitempty"`
+	EnRouteAt          *time.Time     `json:"en_route_at,omitempty"` // the accepted order's yandaş set off
 	StartedAt          *time.Time     `json:"started_at,omitempty"`
 	CompletedAt        *time.Time     `json:"completed_at,omitempty"`
 	CustomerNotes      *string        `gorm:"type:text" json:"customer_notes,omitempty"`
 	YandasNotes        *string        `gorm:"type:text" json:"yandas_notes,omitempty"`
 	CancellationReason *string        `gorm:"type:text" json:"cancellation_reason,omitempty"`
 	CancelledBy        *uuid.UUID     `gorm:"type:uuid" json:"cancelled_by,omitempty"`
-	CreatedAt          time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt          time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
+	ConversationID     *uuid.UUID     `gorm:"type:uuid" json:"conversation_id,omitempty"` // opened with the customer when the order was accepted
+	// Agreed price before the first approved adjustment; nil if never adjusted
+	OriginalPrice *currency.Money `gorm:"type:bigint" json:"original_price,omitempty"`
+	// When the yandaş marked the order done, if completions need the customer's confirmation
+	CompletionRequestedAt *time.Time `json:"completion_requested_at,omitempty"`
+	// Estimated arrival of the yandaş while the order is accepted or in progress
+	EtaAt         *time.Time `json:"eta_at,omitempty"`
+	EtaDistanceKm *float64   `gorm:"type:decimal(8,2)" json:"eta_distance_km,omitempty"`
+	EtaUpdatedAt  *time.Time `json:"eta_updated_at,omitempty"`
+	// Commission split, set when the order is completed
+	CommissionRate *float64        `gorm:"type:decimal(5,4)" json:"commission_rate,omitempty"`
+	PlatformFee    *currency.Money `gorm:"type:bigint" json:"platform_fee,omitempty"`
+	NetEarnings    *currency.Money `gorm:"type:bigint" json:"net_earnings,omitempty"`
+	CreatedAt      time.Time       `gorm:"autoCreateTime;index:idx_orders_yandas_status_created,priority:3" json:"created_at"`
+	UpdatedAt      time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt      gorm.DeletedAt  `gorm:"index" json:"-"`
+
+	// Relations
+	Customer  *User           `gorm:"foreignKey:CustomerID" json:"customer,omitempty"`
+	Yandas    *YandasProfile  `gorm:"foreignKey:YandasID" json:"yandas,omitempty"`
+	Service   *YandasService  `gorm:"foreignKey:ServiceID" json:"service,omitempty"`
+	Review    *Review         `gorm:"foreignKey:OrderID" json:"review,omitempty"`
+	LineItems []OrderLineItem `gorm:"foreignKey:OrderID" json:"line_items,omitempty"`
+}
+
+// RecurringOrder is a customer's repeating booking; the scheduler creates an order for each occurrence
+type RecurringOrder struct {
+	ID              uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CustomerID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"customer_id"`
+	YandasID        uuid.UUID      `gorm:"type:uuid;not null" json:"yandas_id"`
+	ServiceID       uuid.UUID      `gorm:"type:uuid;not null" json:"service_id"`
+	OptionIDs       pq.StringArray `gorm:"type:text[]" json:"option_ids"`
+	DurationMinutes *int           `json:"duration_minutes,omitempty"`
+	AddressID       *uuid.UUID     `gorm:"type:uuid" json:"address_id,omitempty"`
+	LocationAddress *string        `gorm:"type:text" json:"location_address,omitempty"`
+	Latitude        *float64       `gorm:"type:decimal(10,8)" json:"latitude,omitempty"`
+	Longitude       *float64       `gorm:"type:decimal(11,8)" json:"longitude,omitempty"`
+	CustomerNotes   *string        `gorm:"type:text" json:"customer_notes,omitempty"`
+	Frequency       string         `gorm:"size:20;not null" json:"frequency"` // weekly, biweekly, monthly
+	NextRunAt       time.Time      `gorm:"not null;index" json:"next_run_at"` // scheduled time of the next occurrence
+	EndsAt          *time.Time     `json:"ends_at,omitempty"`
+	MaxOccurrences  *int           `json:"max_occurrences,omitempty"`
+	OccurrenceCount int            `gorm:"not null" json:"occurrence_count"`
+	Status          string         `gorm:"size:20;default:active;index" json:"status"` // active, paused, cancelled, completed
+	LastOrderID     *uuid.UUID     `gorm:"type:uuid" json:"last_order_id,omitempty"`
+	LastError       *string        `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt       time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Relations
-	Customer *User          `gorm:"foreignKey:CustomerID" json:"customer,omitempty"`
-	Yandas   *YandasProfile `gorm:"foreignKey:YandasID" json:"yandas,omitempty"`
-	Service  *YandasService `gorm:"foreignKey:ServiceID" json:"service,omitempty"`
-	Review   *Review        `gorm:"foreignKey:OrderID" json:"review,omitempty"`
+	Yandas  *YandasProfile `gorm:"foreignKey:YandasID" json:"yandas,omitempty"`
+	Service *YandasService `gorm:"foreignKey:ServiceID" json:"service,omitempty"`
+}
+
+// JobRequest is a job a customer posts without picking a yandaş. Matching
+// yandaşlar bid on it and the accepted bid becomes an order.
+type JobRequest struct {
+	ID              uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CustomerID      uuid.UUID       `gorm:"type:uuid;not null;index" json:"customer_id"`
+	CategoryID      uuid.UUID       `gorm:"type:uuid;not null;index:idx_job_requests_open,priority:3" json:"category_id"`
+	City            string          `gorm:"size:100;not null;index:idx_job_requests_open,priority:2" json:"city"`
+	Title           string          `gorm:"size:255;not null" json:"title"`
+	Description     *string         `gorm:"type:text" json:"description,omitempty"`
+	BudgetMin       *currency.Money `gorm:"type:bigint" json:"budget_min,omitempty"`
+	BudgetMax       *currency.Money `gorm:"type:bigint" json:"budget_max,omitempty"`
+	Currency        string          `gorm:"size:3;default:TRY" json:"currency"`
+	ScheduledAt     *time.Time      `json:"scheduled_at,omitempty"`
+	LocationAddress *string         `gorm:"type:text" json:"location_address,omitempty"`
+	Latitude        *float64        `gorm:"type:decimal(10,8)" json:"latitude,omitempty"`
+	Longitude       *float64        `gorm:"type:decimal(11,8)" json:"longitude,omitempty"`
+	Status          string          `gorm:"size:20;default:open;index:idx_job_requests_open,priority:1" json:"status"` // open, accepted, cancelled, expired
+	ExpiresAt       time.Time       `gorm:"not null" json:"expires_at"`
+	AcceptedBidID   *uuid.UUID      `gorm:"type:uuid" json:"accepted_bid_id,omitempty"`
+	OrderID         *uuid.UUID      `gorm:"type:uuid" json:"order_id,omitempty"`
+	BidCount        int             `gorm:"->;-:migration" json:"bid_count"` // pending bids, set by list queries
+	CreatedAt       time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	Customer *User     `gorm:"foreignKey:CustomerID" json:"customer,omitempty"`
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Bids     []Bid     `gorm:"foreignKey:JobRequestID" json:"bids,omitempty"`
+}
+
+// Bid is a yandaş's offer on a job request, priced for one of their services
+type Bid struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	JobRequestID uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_bids_request_yandas" json:"job_request_id"`
+	YandasID     uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_bids_request_yandas;index" json:"yandas_id"`
+	ServiceID    uuid.UUID      `gorm:"type:uuid;not null" json:"service_id"`
+	Amount       currency.Money `gorm:"type:bigint;not null" json:"amount"`
+	Message      *string        `gorm:"type:text" json:"message,omitempty"`
+	Status       string         `gorm:"size:20;default:pending" json:"status"` // pending, accepted, rejected, withdrawn
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	JobRequest *JobRequest    `gorm:"foreignKey:JobRequestID" json:"job_request,omitempty"`
+	Yandas     *YandasProfile `gorm:"foreignKey:YandasID" json:"yandas,omitempty"`
+	Service    *YandasService `gorm:"foreignKey:ServiceID" json:"service,omitempty"`
+}
+
+// AutoAssignment is an urgent order looking for the nearest available yandaş.
+// The job is offered to one candidate at a time; the first to accept gets the order.
+type AutoAssignment struct {
+	ID              uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CustomerID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"customer_id"`
+	CategoryID      uuid.UUID      `gorm:"type:uuid;not null" json:"category_id"`
+	LocationAddress *string        `gorm:"type:text" json:"location_address,omitempty"`
+	Latitude        float64        `gorm:"type:decimal(10,8);not null" json:"latitude"`
+	Longitude       float64        `gorm:"type:decimal(11,8);not null" json:"longitude"`
+	CustomerNotes   *string        `gorm:"type:text" json:"customer_notes,omitempty"`
+	Status          string         `gorm:"size:20;default:searching;index:idx_auto_assignments_offers,priority:1" json:"status"` // searching, assigned, failed, cancelled
+	CandidateID     *uuid.UUID     `gorm:"type:uuid" json:"candidate_id,omitempty"`                                              // yandaş profile holding the current offer
+	ServiceID       *uuid.UUID     `gorm:"type:uuid" json:"service_id,omitempty"`                                                // candidate's service the order would use
+	OfferExpiresAt  *time.Time     `gorm:"index:idx_auto_assignments_offers,priority:2" json:"offer_expires_at,omitempty"`
+	Attempts        int            `gorm:"not null" json:"attempts"`
+	OfferedTo       pq.StringArray `gorm:"type:text[]" json:"-"` // profiles already offered the job
+	OrderID         *uuid.UUID     `gorm:"type:uuid" json:"order_id,omitempty"`
+	CreatedAt       time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
+
+// OrderLineItem is one priced component of an order total
+type OrderLineItem struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	OrderID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"order_id"`
+	Kind        string         `gorm:"size:20;not null" json:"kind"` // base, option, tier, travel, adjustment
+	OptionID    *uuid.UUID     `gorm:"type:uuid" json:"option_id,omitempty"`
+	Description string         `gorm:"size:255;not null" json:"description"`
+	Amount      currency.Money `gorm:"type:bigint;not null" json:"amount"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// OrderAdjustment is a yandaş's proposal to change an order's amount when the
+// final scope differs from what was agreed; a lower amount is a partial refund.
+// The order's price only changes once the customer approves it.
+type OrderAdjustment struct {
+	ID             uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	OrderID        uuid.UUID      `gorm:"type:uuid;not null;index" json:"order_id"`
+	ProposedBy     uuid.UUID      `gorm:"type:uuid;not null" json:"proposed_by"`
+	PreviousAmount currency.Money `gorm:"type:bigint;not null" json:"previous_amount"`
+	Amount         currency.Money `gorm:"type:bigint;not null" json:"amount"`
+	Reason         string         `gorm:"type:text;not null" json:"reason"`
+	Status         string         `gorm:"size:20;not null;default:pending" json:"status"` // pending, approved, rejected, withdrawn
+	RespondedAt    *time.Time     `json:"responded_at,omitempty"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// OrderStatusHistory records each status change of an order, plus the yandaş's
+// location while the order is in progress
+type OrderStatusHistory struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	OrderID   uuid.UUID  `gorm:"type:uuid;not null;index:idx_order_status_history_order_created,priority:1" json:"order_id"`
+	Event     string     `gorm:"size:30;not null" json:"event"`  // created, accepted, en_route, started, location, completion_requested, completed, cancelled
+	Status    string     `gorm:"size:30;not null" json:"status"` // order status after the event
+	ActorID   *uuid.UUID `gorm:"type:uuid" json:"actor_id,omitempty"`
+	Note      *string    `gorm:"type:text" json:"note,omitempty"`
+	Latitude  *float64   `gorm:"type:decimal(10,8)" json:"latitude,omitempty"`
+	Longitude *float64   `gorm:"type:decimal(11,8)" json:"longitude,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime;index:idx_order_status_history_order_created,priority:2" json:"created_at"`
+}
+
+func (OrderStatusHistory) TableName() string { return "order_status_history" }
+
+// Receipt is the VAT breakdown issued for a completed order
+type Receipt struct {
+	ID               uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	OrderID          uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex" json:"order_id"`
+	ReceiptNumber    string         `gorm:"size:30;uniqueIndex;not null" json:"receipt_number"`
+	Currency         string         `gorm:"size:3;default:TRY" json:"currency"`
+	Subtotal         currency.Money `gorm:"type:bigint;not null" json:"subtotal"` // excluding VAT
+	VATRate          float64        `gorm:"type:decimal(5,4);not null" json:"vat_rate"`
+	VATAmount        currency.Money `gorm:"type:bigint;not null" json:"vat_amount"`
+	Total            currency.Money `gorm:"type:bigint;not null" json:"total"`
+	EInvoiceProvider *string        `gorm:"size:50" json:"einvoice_provider,omitempty"`
+	EInvoiceStatus   string         `gorm:"size:20;default:not_sent" json:"einvoice_status"` // not_sent, issued, failed
+	EInvoiceUUID     *string        `gorm:"size:64" json:"einvoice_uuid,omitempty"`          // ETTN
+	EInvoiceError    *string        `gorm:"type:text" json:"-"`
+	IssuedAt         time.Time      `gorm:"not null" json:"issued_at"`
+	UpdatedAt        time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PayoutEntry records a yandaş's earnings from one completed order
+type PayoutEntry struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	YandasID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"yandas_id"`
+	OrderID     uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex" json:"order_id"`
+	PayoutID    *uuid.UUID     `gorm:"type:uuid;index" json:"payout_id,omitempty"` // nil until included in a payout
+	GrossAmount currency.Money `gorm:"type:bigint;not null" json:"gross_amount"`
+	PlatformFee currency.Money `gorm:"type:bigint;not null" json:"platform_fee"`
+	NetAmount   currency.Money `gorm:"type:bigint;not null" json:"net_amount"`
+	Currency    string         `gorm:"size:3;default:TRY" json:"currency"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// Payout is a transfer of accumulated net earnings to a yandaş
+type Payout struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	YandasID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"yandas_id"`
+	Amount        currency.Money `gorm:"type:bigint;not null" json:"amount"`
+	Currency      string         `gorm:"size:3;default:TRY" json:"currency"`
+	Status        string         `gorm:"size:20;default:pending" json:"status"` // pending, transferred
+	Reference     *string        `gorm:"size:100" json:"reference,omitempty"`   // bank transfer reference
+	Notes         *string        `gorm:"type:text" json:"notes,omitempty"`
+	CreatedBy     uuid.UUID      `gorm:"type:uuid;not null" json:"created_by"`
+	TransferredBy *uuid.UUID     `gorm:"type:uuid" json:"transferred_by,omitempty"`
+	TransferredAt *time.Time     `json:"transferred_at,omitempty"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relations
+	Yandas  *YandasProfile `gorm:"foreignKey:YandasID" json:"yandas,omitempty"`
+	Entries []PayoutEntry  `gorm:"foreignKey:PayoutID" json:"entries,omitempty"`
+}
+
+// ExchangeRate is how much of the base currency (TRY) one unit of a currency
+// bought on a day; reports use it to total amounts in different currencies
+type ExchangeRate struct {
+	Currency  string    `gorm:"size:3;primaryKey" json:"currency"`
+	Date      time.Time `gorm:"type:date;primaryKey" json:"date"`
+	Rate      float64   `gorm:"type:decimal(18,6);not null" json:"rate"`
+	Source    string    `gorm:"size:20;not null" json:"source"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 // Review represents a rating/review for an order
 type Review struct {
-	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	OrderID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"order_id"`
-	ReviewerID  uuid.UUID `gorm:"type:uuid;not null" json:"reviewer_id"`
-	RevieweeID  uuid.UUID `gorm:"type:uuid;not null" json:"reviewee_id"`
-	Rating      int       `gorm:"not null;check:rating >= 1 AND rating <= 5" json:"rating"`
-	Comment     *string   `gorm:"type:text" json:"comment,omitempty"`
-	IsAnonymous bool      `gorm:"default:false" json:"is_anonymous"`
-	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	OrderID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"order_id"`
+	ReviewerID   uuid.UUID  `gorm:"type:uuid;not null" json:"reviewer_id"`
+	RevieweeID   uuid.UUID  `gorm:"type:uuid;not null" json:"reviewee_id"`
+	Rating       int        `gorm:"not null;check:rating >= 1 AND rating <= 5" json:"rating"`
+	Comment      *string    `gorm:"type:text" json:"comment,omitempty"`
+	IsAnonymous  bool       `gorm:"default:false" json:"is_anonymous"`
+	Reply        *string    `gorm:"type:text" json:"reply,omitempty"` // public reply by the reviewed yandaş
+	RepliedAt    *time.Time `json:"replied_at,omitempty"`
+	HelpfulCount int        `gorm:"default:0" json:"helpful_count"`
+	IsHidden     bool       `gorm:"not null;default:false" json:"is_hidden"` // hidden by a moderator
+	HiddenAt     *time.Time `json:"hidden_at,omitempty"`
+	HiddenBy     *uuid.UUID `gorm:"type:uuid" json:"hidden_by,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Optional per-criterion ratings, 1-5 like Rating
+	PunctualityRating   *int `gorm:"check:punctuality_rating >= 1 AND punctuality_rating <= 5" json:"punctuality_rating,omitempty"`
+	CommunicationRating *int `gorm:"check:communication_rating >= 1 AND communication_rating <= 5" json:"communication_rating,omitempty"`
+	QualityRating       *int `gorm:"check:quality_rating >= 1 AND quality_rating <= 5" json:"quality_rating,omitempty"`
 
 	// Relations
 	Reviewer *User `gorm:"foreignKey:ReviewerID" json:"reviewer,omitempty"`
 }
 
+// ReviewVote records a user marking a review helpful
+type ReviewVote struct {
+	ReviewID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"review_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
 // Conversation represents a chat conversation
 type Conversation struct {
 	ID            uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
@@ -150,6 +615,8 @@ type Conversation struct {
 	CustomerID    uuid.UUID  `gorm:"type:uuid;not null" json:"customer_id"`
 	YandasID      uuid.UUID  `gorm:"type:uuid;not null" json:"yandas_id"`
 	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
+	DataKey       []byte     `gorm:"type:bytea" json:"-"` // message data key, wrapped by the master key
+	DataKeyID     *string    `gorm:"size:100" json:"-"`   // master key that wrapped DataKey
 	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
 
 	// Relations
@@ -160,18 +627,49 @@ type Conversation struct {
 
 // Message represents a chat message
 type Message struct {
-	ID             uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	ConversationID uuid.UUID `gorm:"type:uuid;not null" json:"conversation_id"`
-	SenderID       uuid.UUID `gorm:"type:uuid;not null" json:"sender_id"`
-	Content        string    `gorm:"type:text;not null" json:"content"`
-	MessageType    string    `gorm:"size:20;default:text" json:"message_type"` // text, image, location, system
-	IsRead         bool      `gorm:"default:false" json:"is_read"`
-	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConversationID  uuid.UUID  `gorm:"type:uuid;not null;index:idx_messages_conversation_created,priority:1" json:"conversation_id"`
+	SenderID        uuid.UUID  `gorm:"type:uuid;not null" json:"sender_id"`
+	Content         string     `gorm:"type:text;not null" json:"content"`
+	MessageType     string     `gorm:"size:20;default:text" json:"message_type"` // text, image, audio, location, system
+	MediaURL        *string    `gorm:"size:500" json:"media_url,omitempty"`      // playback URL of audio messages
+	DurationSeconds *int       `json:"duration_seconds,omitempty"`
+	Latitude        *float64   `gorm:"type:decimal(10,8)" json:"latitude,omitempty"`
+	Longitude       *float64   `gorm:"type:decimal(11,8)" json:"longitude,omitempty"`
+	LocationLabel   *string    `gorm:"size:255" json:"location_label,omitempty"`
+	LiveUntil       *time.Time `gorm:"index" json:"live_until,omitempty"` // set while a location is shared live
+	LiveEndedAt     *time.Time `json:"live_ended_at,omitempty"`
+	IsRead          bool       `gorm:"default:false" json:"is_read"`
+	Encrypted       bool       `gorm:"not null;default:false" json:"-"` // content is stored sealed with the conversation's data key
+	CreatedAt       time.Time  `gorm:"autoCreateTime;index:idx_messages_conversation_created,priority:2;index" json:"created_at"`
 
 	// Relations
 	Sender *User `gorm:"foreignKey:SenderID" json:"sender,omitempty"`
 }
 
+// ArchivedMessage is a message moved to cold storage by the retention job.
+// Its columns follow Message; add new message columns to both.
+type ArchivedMessage struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	ConversationID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"conversation_id"`
+	SenderID        uuid.UUID  `gorm:"type:uuid;not null" json:"sender_id"`
+	Content         string     `gorm:"type:text;not null" json:"content"`
+	MessageType     string     `gorm:"size:20" json:"message_type"`
+	MediaURL        *string    `gorm:"size:500" json:"media_url,omitempty"`
+	DurationSeconds *int       `json:"duration_seconds,omitempty"`
+	Latitude        *float64   `gorm:"type:decimal(10,8)" json:"latitude,omitempty"`
+	Longitude       *float64   `gorm:"type:decimal(11,8)" json:"longitude,omitempty"`
+	LocationLabel   *string    `gorm:"size:255" json:"location_label,omitempty"`
+	LiveUntil       *time.Time `json:"live_until,omitempty"`
+	LiveEndedAt     *time.Time `json:"live_ended_at,omitempty"`
+	IsRead          bool       `json:"is_read"`
+	Encrypted       bool       `gorm:"not null;default:false" json:"-"`
+	CreatedAt       time.Time  `gorm:"not null" json:"created_at"`
+	ArchivedAt      time.Time  `gorm:"not null" json:"archived_at"`
+}
+
+func (ArchivedMessage) TableName() string { return "messages_archive" }
+
 // Subscription represents a premium subscription
 type Subscription struct {
 	ID                     uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
@@ -188,12 +686,195 @@ type Subscription struct {
 
 // DeviceToken represents a push notification token
 type DeviceToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	Token     string     `gorm:"type:text;not null" json:"token"`
+	Platform  string     `gorm:"size:10;not null" json:"platform"` // ios, android, web, ios_voip
+	SessionID *uuid.UUID `gorm:"type:uuid;index" json:"session_id,omitempty"`
+	IsActive  bool       `gorm:"default:true" json:"is_active"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ContactChange holds a pending email or phone change until the new address is verified
+type ContactChange struct {
 	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
-	Token     string    `gorm:"type:text;not null" json:"token"`
-	Platform  string    `gorm:"size:10;not null" json:"platform"` // ios, android, web
-	IsActive  bool      `gorm:"default:true" json:"is_active"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_contact_change_user_channel" json:"user_id"`
+	Channel   string    `gorm:"size:10;not null;uniqueIndex:idx_contact_change_user_channel" json:"channel"` // email, phone
+	NewValue  string    `gorm:"size:255;not null" json:"new_value"`
+	CodeHash  string    `gorm:"size:64" json:"-"`
+	Attempts  int       `gorm:"default:0" json:"-"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Session is a signed-in device, backed by the refresh token issued to it
+type Session struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID           uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Platform         string     `gorm:"size:20" json:"platform"`
+	UserAgent        string     `gorm:"size:255" json:"user_agent"`
+	IPAddress        string     `gorm:"size:45" json:"ip_address"`
+	RefreshTokenHash string     `gorm:"size:64;not null" json:"-"`
+	LastSeenAt       time.Time  `json:"last_seen_at"`
+	ExpiresAt        time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	Current bool `gorm:"-" json:"current"`
+}
+
+// Role groups permissions that can be granted to staff users
+type Role struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Name        string         `gorm:"size:50;uniqueIndex;not null" json:"name"` // support_agent, moderator, finance
+	Description string         `gorm:"type:text" json:"description"`
+	Permissions pq.StringArray `gorm:"type:text[]" json:"permissions"`
+	IsSystem    bool           `gorm:"not null" json:"is_system"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// UserRole assigns a role to a user
+type UserRole struct {
+	UserID    uuid.UUID  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	RoleID    uuid.UUID  `gorm:"type:uuid;primaryKey" json:"role_id"`
+	GrantedBy *uuid.UUID `gorm:"type:uuid" json:"granted_by,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relations
+	Role *Role `gorm:"foreignKey:RoleID" json:"role,omitempty"`
+}
+
+// WebhookEndpoint is an admin-registered receiver for outbound events
+type WebhookEndpoint struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	URL         string         `gorm:"type:text;not null" json:"url"`
+	Secret      string         `gorm:"size:100;not null" json:"-"`
+	Events      pq.StringArray `gorm:"type:text[]" json:"events"` // order.created, order.completed, application.approved, review.created
+	Description *string        `gorm:"type:text" json:"description,omitempty"`
+	IsActive    bool           `gorm:"default:true" json:"is_active"`
+	CreatedBy   uuid.UUID      `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// WebhookDelivery records one event sent to one endpoint, including retries
+type WebhookDelivery struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	EndpointID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"endpoint_id"`
+	Event          string     `gorm:"size:50;not null" json:"event"`
+	Payload        string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status         string     `gorm:"size:20;default:pending;index" json:"status"` // pending, succeeded, failed
+	Attempts       int        `gorm:"default:0" json:"attempts"`
+	NextAttemptAt  *time.Time `gorm:"index" json:"next_attempt_at,omitempty"`
+	LastStatusCode *int       `json:"last_status_code,omitempty"`
+	LastError      *string    `gorm:"type:text" json:"last_error,omitempty"`
+	LastResponse   *string    `gorm:"type:text" json:"last_response,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// DomainEvent is a domain event in the outbox. It is written in the same
+// transaction as the change it describes and relayed to its subscribers
+// afterwards, so side effects survive a crash or restart in between.
+type DomainEvent struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Type          string     `gorm:"size:50;not null;index" json:"type"`
+	AggregateID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"aggregate_id"`
+	Payload       string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status        string     `gorm:"size:20;not null;default:pending;index" json:"status"` // pending, published
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	NextAttemptAt *time.Time `gorm:"index" json:"next_attempt_at,omitempty"`
+	LastError     *string    `gorm:"type:text" json:"last_error,omitempty"`
+	PublishedAt   *time.Time `json:"published_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// OutboxMessage is a WebSocket broadcast or push notification written in the
+// transaction that caused it and delivered after it commits
+type OutboxMessage struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Channel       string     `gorm:"size:10;not null" json:"channel"` // ws, push
+	Target        string     `gorm:"size:100;not null" json:"target"` // WebSocket room, or the user ID of a push
+	Type          string     `gorm:"size:50;not null" json:"type"`
+	Payload       string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status        string     `gorm:"size:20;not null;default:pending;index" json:"status"` // pending, delivered, failed
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	NextAttemptAt *time.Time `gorm:"index" json:"next_attempt_at,omitempty"`
+	LastError     *string    `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// SMSDelivery is one attempt to send a text message through a provider. A
+// message that fails over gets one row per provider tried, in Attempt order.
+type SMSDelivery struct {
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Phone             string    `gorm:"serializer:encrypted;size:255;not null" json:"phone"`
+	PhoneHash         string    `gorm:"size:64;index" json:"-"`          // blind index of Phone, for filtering
+	Purpose           string    `gorm:"size:20;not null" json:"purpose"` // otp, text
+	Provider          string    `gorm:"size:20;not null;index:idx_sms_deliveries_provider_message,priority:1" json:"provider"`
+	ProviderMessageID *string   `gorm:"size:64;index:idx_sms_deliveries_provider_message,priority:2" json:"provider_message_id,omitempty"`
+	Attempt           int       `gorm:"not null;default:1" json:"attempt"`
+	Status            string    `gorm:"size:20;not null;index" json:"status"` // sent, delivered, undelivered, failed
+	ErrorCode         *string   `gorm:"size:20" json:"error_code,omitempty"`
+	Error             *string   `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt         time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// BeforeSave keeps the phone's blind index in step with the phone
+func (d *SMSDelivery) BeforeSave(*gorm.DB) error {
+	d.PhoneHash = crypto.Active().BlindIndex(d.Phone)
+	return nil
+}
+
+// AlertRule watches a business metric and alerts admins when it crosses a threshold
+type AlertRule struct {
+	ID              uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Name            string         `gorm:"size:100;not null" json:"name"`
+	Metric          string         `gorm:"size:50;not null;index" json:"metric"`
+	Condition       string         `gorm:"size:10;not null" json:"condition"` // above, below
+	Threshold       int64          `gorm:"not null" json:"threshold"`
+	WindowMinutes   int            `gorm:"not null" json:"window_minutes"`
+	CooldownMinutes int            `gorm:"not null" json:"cooldown_minutes"`
+	Channels        pq.StringArray `gorm:"type:text[]" json:"channels"` // email, notification
+	IsActive        bool           `gorm:"not null" json:"is_active"`
+	LastTriggeredAt *time.Time     `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AlertEvent records a rule firing
+type AlertEvent struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	RuleID    uuid.UUID `gorm:"type:uuid;not null;index" json:"rule_id"`
+	Metric    string    `gorm:"size:50;not null" json:"metric"`
+	Value     int64     `gorm:"not null" json:"value"`
+	Threshold int64     `gorm:"not null" json:"threshold"`
+	Condition string    `gorm:"size:10;not null" json:"condition"`
+	Message   string    `gorm:"type:text;not null" json:"message"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+
+	// Relations
+	Rule *AlertRule `gorm:"foreignKey:RuleID" json:"rule,omitempty"`
+}
+
+// MetricCounter counts occurrences of a metric per minute
+type MetricCounter struct {
+	Metric string    `gorm:"size:50;primaryKey" json:"metric"`
+	Bucket time.Time `gorm:"primaryKey;index" json:"bucket"`
+	Count  int64     `gorm:"not null" json:"count"`
+}
+
+// Setting overrides an operational setting at runtime. Keys without a row use
+// their default; Value is stored as text and parsed according to Type.
+type Setting struct {
+	Key       string     `gorm:"primaryKey;size:100" json:"key"`
+	Type      string     `gorm:"size:20;not null" json:"type"` // float, int, bool, duration
+	Value     string     `gorm:"type:text;not null" json:"value"`
+	UpdatedBy *uuid.UUID `gorm:"type:uuid" json:"updated_by,omitempty"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 // AuditLog represents admin action logs
@@ -206,22 +887,87 @@ type AuditLog struct {
 	OldValues  *string    `gorm:"type:jsonb" json:"old_values,omitempty"`
 	NewValues  *string    `gorm:"type:jsonb" json:"new_values,omitempty"`
 	IPAddress  *string    `gorm:"size:45" json:"ip_address,omitempty"`
-	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime;index" json:"created_at"`
 
 	// Relations
 	Admin *User `gorm:"foreignKey:AdminID" json:"admin,omitempty"`
 }
 
+// Announcement is a banner shown in the apps, e.g. a maintenance notice or campaign
+type Announcement struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Title       string     `gorm:"size:200;not null" json:"title"`
+	Body        string     `gorm:"type:text;not null" json:"body"`
+	Audience    string     `gorm:"size:20;default:all" json:"audience"`  // all, customer, yandas
+	Severity    string     `gorm:"size:20;default:info" json:"severity"` // info, warning, critical
+	LinkURL     *string    `gorm:"size:500" json:"link_url,omitempty"`
+	Dismissible bool       `gorm:"not null" json:"dismissible"`
+	IsActive    bool       `gorm:"not null;index:idx_announcements_active_window,priority:1" json:"is_active"`
+	StartsAt    time.Time  `gorm:"not null;index:idx_announcements_active_window,priority:2" json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"` // nil keeps it up until deactivated
+	CreatedBy   uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AnnouncementDismissal records that a user closed an announcement
+type AnnouncementDismissal struct {
+	AnnouncementID uuid.UUID `gorm:"type:uuid;primaryKey" json:"announcement_id"`
+	UserID         uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// LegalDocument is a published version of a legal text in one locale.
+// Versions are never edited: a change publishes the next one, so the history
+// stays. Users must accept the latest Turkish version that requires consent.
+type LegalDocument struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Kind            string    `gorm:"size:20;not null;uniqueIndex:idx_legal_documents_kind_locale_version,priority:1" json:"kind"` // terms, privacy, kvkk
+	Locale          string    `gorm:"size:10;not null;default:tr;uniqueIndex:idx_legal_documents_kind_locale_version,priority:2" json:"locale"`
+	Version         int       `gorm:"not null;uniqueIndex:idx_legal_documents_kind_locale_version,priority:3" json:"version"` // counted per kind and locale
+	Title           string    `gorm:"size:200;not null" json:"title"`
+	Content         string    `gorm:"type:text;not null" json:"content"`             // markdown
+	RequiresConsent bool      `gorm:"not null;default:true" json:"requires_consent"` // false for corrections users need not accept again
+	PublishedBy     uuid.UUID `gorm:"type:uuid;not null" json:"published_by"`
+	PublishedAt     time.Time `gorm:"autoCreateTime" json:"published_at"`
+}
+
+// UserConsent records a user accepting a version of a legal document
+type UserConsent struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_user_consents_user_document,priority:1" json:"user_id"`
+	DocumentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_user_consents_user_document,priority:2" json:"document_id"`
+	IPAddress  string    `gorm:"size:45" json:"ip_address"`
+	UserAgent  string    `gorm:"size:255" json:"user_agent"`
+	AcceptedAt time.Time `gorm:"autoCreateTime" json:"accepted_at"`
+
+	// Relations
+	Document *LegalDocument `gorm:"foreignKey:DocumentID" json:"document,omitempty"`
+}
+
 // Notification represents in-app notifications
 type Notification struct {
 	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index:idx_notifications_user_read,priority:1" json:"user_id"`
 	Title     string    `gorm:"size:255;not null" json:"title"`
 	Body      string    `gorm:"type:text;not null" json:"body"`
-	Type      string    `gorm:"size:50" json:"type"` // order, chat, system, promotion
+	Type      string    `gorm:"size:50" json:"type"` // order, chat, call, favorite, system, promotion
 	Data      *string   `gorm:"type:jsonb" json:"data,omitempty"`
-	IsRead    bool      `gorm:"default:false" json:"is_read"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	IsRead    bool      `gorm:"default:false;index:idx_notifications_user_read,priority:2" json:"is_read"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index:idx_notifications_unread_created,where:is_read = false" json:"created_at"`
+}
+
+// NotificationPreference stores a user's opt-in state per notification type and channel
+type NotificationPreference struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"-"`
+	UserID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_notification_prefs_user_type" json:"-"`
+	Type          string    `gorm:"size:50;not null;uniqueIndex:idx_notification_prefs_user_type" json:"type"` // order, chat, call, favorite, promotion, system
+	PushEnabled   bool      `gorm:"not null" json:"push"`
+	EmailEnabled  bool      `gorm:"not null" json:"email"`
+	SMSEnabled    bool      `gorm:"not null" json:"sms"`
+	BatchEnabled  bool      `gorm:"not null" json:"batch"`  // pushes arriving close together are summarised in one
+	DigestEnabled bool      `gorm:"not null" json:"digest"` // unread ones go into the daily email digest for inactive users
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 // SupportTicket represents a support request
@@ -236,8 +982,16 @@ type SupportTicket struct {
 	Status      string     `gorm:"size:20;default:open" json:"status"`      // open, pending, in_progress, resolved, closed
 	OrderID     *uuid.UUID `gorm:"type:uuid" json:"order_id,omitempty"`
 	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
-	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	// SLA deadlines follow the priority; breaching one escalates the ticket
+	FirstResponseDueAt    *time.Time `gorm:"index" json:"first_response_due_at,omitempty"`
+	ResolutionDueAt       *time.Time `gorm:"index" json:"resolution_due_at,omitempty"`
+	FirstRespondedAt      *time.Time `json:"first_responded_at,omitempty"`
+	FirstResponseBreached bool       `gorm:"default:false" json:"first_response_breached"`
+	ResolutionBreached    bool       `gorm:"default:false" json:"resolution_breached"`
+	EscalationLevel       int        `gorm:"default:0" json:"escalation_level"`
+	EscalatedAt           *time.Time `json:"escalated_at,omitempty"`
+	CreatedAt             time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt             time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Relations
 	User     *User            `gorm:"foreignKey:UserID" json:"user,omitempty"`
@@ -247,22 +1001,35 @@ type SupportTicket struct {
 
 // SupportMessage represents a message in a support conversation
 type SupportMessage struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	TicketID  uuid.UUID `gorm:"type:uuid;not null" json:"ticket_id"`
-	SenderID  uuid.UUID `gorm:"type:uuid;not null" json:"sender_id"`
-	Content   string    `gorm:"type:text;not null" json:"content"`
-	IsAdmin   bool      `gorm:"default:false" json:"is_admin"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TicketID   uuid.UUID `gorm:"type:uuid;not null" json:"ticket_id"`
+	SenderID   uuid.UUID `gorm:"type:uuid;not null" json:"sender_id"`
+	Content    string    `gorm:"type:text;not null" json:"content"`
+	IsAdmin    bool      `gorm:"default:false" json:"is_admin"`
+	IsInternal bool      `gorm:"default:false" json:"is_internal"` // agent-only note, hidden from the ticket owner
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
 
 	// Relations
 	Sender *User `gorm:"foreignKey:SenderID" json:"sender,omitempty"`
 }
 
+// CannedResponse is a reply template support agents can insert into ticket replies
+type CannedResponse struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Title      string    `gorm:"size:100;not null" json:"title"`
+	Content    string    `gorm:"type:text;not null" json:"content"`       // may contain {name} and {subject}
+	Category   *string   `gorm:"size:50;index" json:"category,omitempty"` // ticket category it suits; nil for any
+	CreatedBy  uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	UsageCount int       `gorm:"default:0" json:"usage_count"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
 // Favorite represents a user's bookmarked Yandaş
 type Favorite struct {
 	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
-	YandasID  uuid.UUID `gorm:"type:uuid;not null" json:"yandas_id"`
+	YandasID  uuid.UUID `gorm:"type:uuid;not null;index" json:"yandas_id"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 
 	// Relations
@@ -270,21 +1037,145 @@ type Favorite struct {
 	Yandas *YandasProfile `gorm:"foreignKey:YandasID" json:"yandas,omitempty"`
 }
 
+// FavoriteActivity is something a yandaş did that users who favorited them hear about
+type FavoriteActivity struct {
+	ID        uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	YandasID  uuid.UUID       `gorm:"type:uuid;not null;index:idx_favorite_activities_yandas_created,priority:1" json:"yandas_id"`
+	Type      string          `gorm:"size:20;not null" json:"type"` // online, new_service, price_drop
+	ServiceID *uuid.UUID      `gorm:"type:uuid" json:"service_id,omitempty"`
+	Title     string          `gorm:"size:255" json:"title,omitempty"` // service title for service activities
+	OldPrice  *currency.Money `gorm:"type:bigint" json:"old_price,omitempty"`
+	NewPrice  *currency.Money `gorm:"type:bigint" json:"new_price,omitempty"`
+	Currency  string          `gorm:"size:3;default:TRY" json:"currency,omitempty"` // of the prices
+	CreatedAt time.Time       `gorm:"autoCreateTime;index:idx_favorite_activities_yandas_created,priority:2" json:"created_at"`
+
+	// Relations
+	Yandas *YandasProfile `gorm:"foreignKey:YandasID" json:"yandas,omitempty"`
+}
+
+// ProfileView is one view of a yandaş's public profile, kept until the nightly rollup has counted it
+type ProfileView struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	YandasID  uuid.UUID `gorm:"type:uuid;not null;index:idx_profile_views_yandas_created,priority:1" json:"yandas_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index:idx_profile_views_yandas_created,priority:2;index" json:"created_at"`
+}
+
+// YandasDailyStat is a yandaş's funnel for one day, aggregated nightly
+type YandasDailyStat struct {
+	YandasID           uuid.UUID      `gorm:"type:uuid;primaryKey" json:"-"`
+	Date               time.Time      `gorm:"type:date;primaryKey" json:"date"`
+	ProfileViews       int64          `gorm:"not null;default:0" json:"profile_views"`
+	FavoriteAdds       int64          `gorm:"not null;default:0" json:"favorite_adds"`
+	ConversationStarts int64          `gorm:"not null;default:0" json:"conversation_starts"`
+	OrdersCreated      int64          `gorm:"not null;default:0" json:"orders_created"`
+	OrdersCompleted    int64          `gorm:"not null;default:0" json:"orders_completed"`
+	Revenue            currency.Money `gorm:"type:bigint;not null;default:0" json:"revenue"` // in the base currency
+	UpdatedAt          time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AnalyticsEvent is one product analytics event sent by the apps. Actor is the
+// signed-in user's ID, or the app's anonymous install ID before sign-in, so a
+// funnel can follow one person across its steps.
+type AnalyticsEvent struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Name       string     `gorm:"size:40;not null;index:idx_analytics_events_name_occurred,priority:1" json:"name"` // screen_view, search, profile_view, order_started
+	Actor      string     `gorm:"size:64;not null;index:idx_analytics_events_actor_occurred,priority:1" json:"actor"`
+	UserID     *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	SessionID  string     `gorm:"size:64" json:"session_id,omitempty"`
+	Platform   string     `gorm:"size:20" json:"platform,omitempty"` // ios, android, web
+	AppVersion string     `gorm:"size:20" json:"app_version,omitempty"`
+	Screen     string     `gorm:"size:100" json:"screen,omitempty"`
+	YandasID   *uuid.UUID `gorm:"type:uuid" json:"yandas_id,omitempty"`
+	Properties *string    `gorm:"type:jsonb" json:"properties,omitempty"`
+	OccurredAt time.Time  `gorm:"not null;index:idx_analytics_events_name_occurred,priority:2;index:idx_analytics_events_actor_occurred,priority:2" json:"occurred_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
 // CallLog represents a voice/video call record
 type CallLog struct {
 	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	CallerID   uuid.UUID  `gorm:"type:uuid;not null" json:"caller_id"`
 	CalleeID   uuid.UUID  `gorm:"type:uuid;not null" json:"callee_id"`
 	OrderID    *uuid.UUID `gorm:"type:uuid" json:"order_id,omitempty"`
-	CallType   string     `gorm:"size:20;not null" json:"call_type"` // voice, video
-	Status     string     `gorm:"size:20;not null" json:"status"`    // initiated, ringing, answered, ended, missed, declined
-	Duration   int        `gorm:"default:0" json:"duration"`         // seconds
+	CallType   string     `gorm:"size:20;not null" json:"call_type"`                                     // voice, video
+	Status     string     `gorm:"size:20;not null;index:idx_call_logs_ringing,priority:1" json:"status"` // initiated, ringing, answered, ended, missed, declined
+	Duration   int        `gorm:"default:0" json:"duration"`                                             // seconds
 	ChannelID  *string    `gorm:"size:255" json:"channel_id,omitempty"`
 	StartedAt  time.Time  `gorm:"autoCreateTime" json:"started_at"`
 	AnsweredAt *time.Time `json:"answered_at,omitempty"`
 	EndedAt    *time.Time `json:"ended_at,omitempty"`
+	// When a call nobody picked up is marked missed
+	RingExpiresAt *time.Time `gorm:"index:idx_call_logs_ringing,priority:2" json:"ring_expires_at,omitempty"`
+	// Cloud recording (dispute evidence) - only started when both parties consent
+	RecordingRequested  bool           `gorm:"default:false" json:"recording_requested"`
+	CallerConsent       bool           `gorm:"default:false" json:"caller_consent"`
+	CalleeConsent       bool           `gorm:"default:false" json:"callee_consent"`
+	RecordingStatus     string         `gorm:"size:20;default:none" json:"recording_status"` // none, starting, recording, stopping, stopped, failed
+	RecordingResourceID *string        `gorm:"type:text" json:"-"`
+	RecordingSID        *string        `gorm:"size:255" json:"-"`
+	RecordingFiles      pq.StringArray `gorm:"type:text[]" json:"-"`
+	// Quality feedback each party can leave once the call has ended
+	CallerRating   *int           `json:"caller_rating,omitempty"`
+	CallerIssues   pq.StringArray `gorm:"type:text[]" json:"caller_issues,omitempty"` // echo, drop, no_audio, no_video, lag, noise
+	CallerPlatform *string        `gorm:"size:20" json:"caller_platform,omitempty"`
+	CallerRegion   *string        `gorm:"size:50" json:"caller_region,omitempty"`
+	CalleeRating   *int           `json:"callee_rating,omitempty"`
+	CalleeIssues   pq.StringArray `gorm:"type:text[]" json:"callee_issues,omitempty"`
+	CalleePlatform *string        `gorm:"size:20" json:"callee_platform,omitempty"`
+	CalleeRegion   *string        `gorm:"size:50" json:"callee_region,omitempty"`
+	// Incoming call push to the callee's devices, and when a device started ringing
+	PushStatus   string     `gorm:"size:20;default:none" json:"push_status"` // none, sent, failed, no_devices, disabled
+	PushSentAt   *time.Time `json:"push_sent_at,omitempty"`
+	PushError    *string    `gorm:"type:text" json:"-"`
+	DeliveredAt  *time.Time `json:"delivered_at,omitempty"`
+	DeliveredVia *string    `gorm:"size:20" json:"delivered_via,omitempty"` // push, websocket
+
+	// Relations
+	Caller       *User             `gorm:"foreignKey:CallerID" json:"caller,omitempty"`
+	Callee       *User             `gorm:"foreignKey:CalleeID" json:"callee,omitempty"`
+	Participants []CallParticipant `gorm:"foreignKey:CallID" json:"participants,omitempty"`
+}
+
+// CallParticipant is someone in a call besides its caller and callee, such as
+// a support agent mediating a dispute. UID is their Agora uid in the channel.
+type CallParticipant struct {
+	ID       uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CallID   uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_call_participants_call_user,priority:1;uniqueIndex:idx_call_participants_call_uid,priority:1" json:"call_id"`
+	UserID   uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_call_participants_call_user,priority:2" json:"user_id"`
+	UID      int        `gorm:"column:uid;not null;uniqueIndex:idx_call_participants_call_uid,priority:2" json:"uid"`
+	Role     string     `gorm:"size:20;not null" json:"role"` // mediator
+	JoinedAt time.Time  `gorm:"not null" json:"joined_at"`
+	LeftAt   *time.Time `json:"left_at,omitempty"`
 
 	// Relations
-	Caller *User `gorm:"foreignKey:CallerID" json:"caller,omitempty"`
-	Callee *User `gorm:"foreignKey:CalleeID" json:"callee,omitempty"`
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// DatabaseBackup is one pg_dump snapshot taken by cmd/backup. Location is the
+// dump's path in the backup directory or its s3:// URL, and Checksum its hex
+// SHA-256, checked again before a restore.
+type DatabaseBackup struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	FileName   string     `gorm:"size:255;not null" json:"file_name"`
+	Storage    string     `gorm:"size:20;not null" json:"storage"` // local, s3
+	Location   string     `gorm:"size:500" json:"location,omitempty"`
+	SizeBytes  int64      `gorm:"not null;default:0" json:"size_bytes"`
+	Checksum   string     `gorm:"size:64" json:"checksum,omitempty"`
+	Status     string     `gorm:"size:20;not null;index:idx_database_backups_status_started,priority:1" json:"status"` // running, succeeded, failed
+	Error      *string    `gorm:"type:text" json:"error,omitempty"`
+	StartedAt  time.Time  `gorm:"not null;index:idx_database_backups_status_started,priority:2" json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// AdminActivity is one entry of the admin activity feed, written with the
+// change it describes and streamed live to the admin:events WebSocket room.
+// SubjectID is the order, yandaş profile, support ticket or subscription.
+type AdminActivity struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Type      string     `gorm:"size:40;not null;index:idx_admin_activities_type_created,priority:1" json:"type"` // order_created, application_submitted, ticket_created, payment_webhook
+	SubjectID uuid.UUID  `gorm:"type:uuid;not null" json:"subject_id"`
+	UserID    *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	Summary   string     `gorm:"size:255;not null" json:"summary"`
+	Data      *string    `gorm:"type:jsonb" json:"data,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime;index;index:idx_admin_activities_type_created,priority:2" json:"created_at"`
 }
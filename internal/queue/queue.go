@@ -0,0 +1,185 @@
+// Package queue is a small Redis-backed job queue with typed handlers, retries
+// with exponential backoff and a dead-letter list. Jobs are enqueued by the API
+// and executed by cmd/worker. Without Redis, jobs run in-process instead.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	keyPending   = "queue:pending"
+	keyScheduled = "queue:scheduled"
+	keyInflight  = "queue:inflight"
+	keyDead      = "queue:dead"
+
+	// DefaultMaxAttempts is how often a job runs before it is dead-lettered.
+	DefaultMaxAttempts = 5
+	// Dead-lettered jobs beyond this many are dropped, oldest first.
+	deadLetterLimit = 1000
+
+	baseBackoff = 10 * time.Second
+	maxBackoff  = 10 * time.Minute
+)
+
+// Job is a unit of work as stored in Redis
+type Job struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempt     int             `json:"attempt"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	EnqueuedAt  time.Time       `json:"enqueued_at"`
+	RunAt       *time.Time      `json:"run_at,omitempty"`
+}
+
+// HandlerFunc executes one job payload. Returning an error schedules a retry.
+type HandlerFunc func(ctx context.Context, payload json.RawMessage) error
+
+// Mux maps job types to handlers
+type Mux struct {
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+}
+
+func NewMux() *Mux {
+	return &Mux{handlers: make(map[string]HandlerFunc)}
+}
+
+// Handle registers the handler for a job type
+func (m *Mux) Handle(jobType string, h HandlerFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[jobType] = h
+}
+
+// HandleJSON registers a handler that receives the payload decoded into T
+func HandleJSON[T any](m *Mux, jobType string, fn func(ctx context.Context, payload T) error) {
+	m.Handle(jobType, func(ctx context.Context, raw json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return fmt.Errorf("decode %s payload: %w", jobType, err)
+		}
+		return fn(ctx, payload)
+	})
+}
+
+func (m *Mux) handler(jobType string) (HandlerFunc, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	h, ok := m.handlers[jobType]
+	return h, ok
+}
+
+// run executes a job, turning panics into errors
+func (m *Mux) run(ctx context.Context, job *Job) (err error) {
+	h, ok := m.handler(job.Type)
+	if !ok {
+		return fmt.Errorf("no handler for job type %q", job.Type)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h(ctx, job.Payload)
+}
+
+// Queue enqueues jobs
+type Queue struct {
+	redis *redis.Client
+	mux   *Mux
+}
+
+// New creates a queue. With a nil Redis client jobs run in a goroutine of the
+// calling process using mux, which keeps local development working without Redis.
+func New(client *redis.Client, mux *Mux) *Queue {
+	return &Queue{redis: client, mux: mux}
+}
+
+// Option customises an enqueued job
+type Option func(*Job)
+
+// MaxAttempts overrides how often the job is tried before it is dead-lettered
+func MaxAttempts(n int) Option {
+	return func(j *Job) { j.MaxAttempts = n }
+}
+
+// Delay holds the job back for d before a worker may run it
+func Delay(d time.Duration) Option {
+	return func(j *Job) {
+		runAt := time.Now().Add(d)
+		j.RunAt = &runAt
+	}
+}
+
+// Enqueue adds a job of the given type. A nil queue drops the job.
+func (q *Queue) Enqueue(jobType string, payload interface{}, opts ...Option) error {
+	if q == nil {
+		return nil
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	job := &Job{
+		ID:          uuid.New().String(),
+		Type:        jobType,
+		Payload:     raw,
+		MaxAttempts: DefaultMaxAttempts,
+		EnqueuedAt:  time.Now(),
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	if q.redis == nil {
+		run := func() {
+			if err := q.mux.run(context.Background(), job); err != nil {
+				log.Printf("[QUEUE] inline job %s (%s) failed: %v", job.ID, job.Type, err)
+			}
+		}
+		if job.RunAt != nil {
+			time.AfterFunc(time.Until(*job.RunAt), run)
+		} else {
+			go run()
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	if job.RunAt != nil {
+		err = q.redis.ZAdd(context.Background(), keyScheduled, redis.Z{Score: float64(job.RunAt.Unix()), Member: data}).Err()
+	} else {
+		err = q.redis.LPush(context.Background(), keyPending, data).Err()
+	}
+	if err != nil {
+		log.Printf("[QUEUE] enqueue %s failed: %v", jobType, err)
+		return err
+	}
+	return nil
+}
+
+// backoff is the delay before retrying a job that failed for the attempt-th time
+func backoff(attempt int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	return d
+}
@@ -0,0 +1,135 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  10 * time.Second,
+		2:  20 * time.Second,
+		3:  40 * time.Second,
+		7:  maxBackoff,
+		20: maxBackoff,
+	}
+	for attempt, want := range cases {
+		if got := backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
+
+func TestInlineEnqueueRunsHandler(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+	done := make(chan string, 1)
+	mux := NewMux()
+	HandleJSON(mux, "greet", func(_ context.Context, p payload) error {
+		done <- p.Name
+		return nil
+	})
+
+	if err := New(nil, mux).Enqueue("greet", payload{Name: "Ayşe"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case name := <-done:
+		if name != "Ayşe" {
+			t.Errorf("expected decoded payload, got %q", name)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler did not run")
+	}
+}
+
+func TestMuxRunReportsFailures(t *testing.T) {
+	mux := NewMux()
+	mux.Handle("panics", func(context.Context, json.RawMessage) error { panic("boom") })
+
+	if err := mux.run(context.Background(), &Job{Type: "panics"}); err == nil {
+		t.Error("expected a panicking handler to be reported as a failure")
+	}
+	if err := mux.run(context.Background(), &Job{Type: "unknown"}); err == nil {
+		t.Error("expected an unknown job type to fail")
+	}
+
+	var q *Queue
+	if err := q.Enqueue("anything", nil); err != nil {
+		t.Errorf("expected a nil queue to drop jobs, got %v", err)
+	}
+}
+
+func TestDelayedEnqueueIsScheduled(t *testing.T) {
+	mr := miniredis.RunT(t)
+	q := New(redis.NewClient(&redis.Options{Addr: mr.Addr()}), NewMux())
+
+	before := time.Now()
+	if err := q.Enqueue("greet", nil, Delay(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := mr.List(keyPending); len(pending) != 0 {
+		t.Fatalf("delayed job was queued for immediate run: %v", pending)
+	}
+	members, err := mr.ZMembers(keyScheduled)
+	if err != nil || len(members) != 1 {
+		t.Fatalf("expected one scheduled job, got %v (%v)", members, err)
+	}
+	score, _ := mr.ZScore(keyScheduled, members[0])
+	if runAt := time.Unix(int64(score), 0); runAt.Before(before.Add(59 * time.Second)) {
+		t.Errorf("job scheduled too early: %s", runAt)
+	}
+}
+
+func TestReclaimExpiredCountsAttempts(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	w := NewWorker(client, NewMux(), 1)
+	ctx := context.Background()
+
+	claim := func(job Job) {
+		data, _ := json.Marshal(job)
+		if err := client.ZAdd(ctx, keyInflight, redis.Z{Score: float64(time.Now().Add(-time.Second).Unix()), Member: data}).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	claim(Job{ID: "retry", Type: "greet", Attempt: 0, MaxAttempts: 2})
+	claim(Job{ID: "dead", Type: "greet", Attempt: 1, MaxAttempts: 2})
+
+	now := time.Now()
+	if err := w.reclaimExpired(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if inflight, _ := client.ZCard(ctx, keyInflight).Result(); inflight != 0 {
+		t.Fatalf("expected the expired claims to be taken back, %d left", inflight)
+	}
+
+	scheduled, _ := mr.ZMembers(keyScheduled)
+	if len(scheduled) != 1 {
+		t.Fatalf("expected one job scheduled for a retry, got %v", scheduled)
+	}
+	var retry Job
+	json.Unmarshal([]byte(scheduled[0]), &retry)
+	if retry.ID != "retry" || retry.Attempt != 1 || retry.LastError == "" {
+		t.Errorf("expected the timed out run to count as an attempt, got %+v", retry)
+	}
+	if score, _ := mr.ZScore(keyScheduled, scheduled[0]); int64(score) != now.Add(backoff(1)).Unix() {
+		t.Errorf("expected the retry after the backoff, got %v", score)
+	}
+
+	dead, _ := mr.List(keyDead)
+	if len(dead) != 1 {
+		t.Fatalf("expected one dead-lettered job, got %v", dead)
+	}
+	var deadJob Job
+	json.Unmarshal([]byte(dead[0]), &deadJob)
+	if deadJob.ID != "dead" || deadJob.Attempt != 2 {
+		t.Errorf("expected the job out of attempts to be dead-lettered, got %+v", deadJob)
+	}
+}
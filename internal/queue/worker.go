@@ -0,0 +1,208 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// A claimed job that isn't finished within this long is handed to another worker.
+	visibilityTimeout = 5 * time.Minute
+	// How often due retries and expired claims are moved back to the pending list.
+	promoteInterval = time.Second
+	// How long an idle worker waits before polling the pending list again.
+	idleWait = time.Second
+)
+
+// claimScript pops the oldest pending job and records it as in flight until ARGV[1]
+var claimScript = redis.NewScript(`
+local job = redis.call('RPOP', KEYS[1])
+if job then
+	redis.call('ZADD', KEYS[2], ARGV[1], job)
+end
+return job
+`)
+
+// promoteScript moves members of the sorted set KEYS[1] scored up to ARGV[1] onto the pending list KEYS[2]
+var promoteScript = redis.NewScript(`
+local jobs = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, 100)
+for _, job in ipairs(jobs) do
+	redis.call('ZREM', KEYS[1], job)
+	redis.call('LPUSH', KEYS[2], job)
+end
+return #jobs
+`)
+
+// reclaimScript takes the expired claim ARGV[1] out of the in-flight set
+// KEYS[1] and adds ARGV[2] in its place: to the sorted set KEYS[2] scored
+// ARGV[3], or, when ARGV[3] is empty, to the list KEYS[2] trimmed to ARGV[4]
+// entries. It does nothing if another worker reclaimed the job first.
+var reclaimScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+if ARGV[3] == '' then
+	redis.call('LPUSH', KEYS[2], ARGV[2])
+	redis.call('LTRIM', KEYS[2], 0, tonumber(ARGV[4]) - 1)
+else
+	redis.call('ZADD', KEYS[2], ARGV[3], ARGV[2])
+end
+return 1
+`)
+
+// Worker executes queued jobs
+type Worker struct {
+	redis       *redis.Client
+	mux         *Mux
+	concurrency int
+}
+
+// NewWorker creates a worker running up to concurrency jobs at once
+func NewWorker(client *redis.Client, mux *Mux, concurrency int) *Worker {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Worker{redis: client, mux: mux, concurrency: concurrency}
+}
+
+// Run processes jobs until ctx is cancelled, then waits for running jobs to finish
+func (w *Worker) Run(ctx context.Context) {
+	log.Printf("[QUEUE] worker started with concurrency %d", w.concurrency)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.promoteLoop(ctx)
+	}()
+	for i := 0; i < w.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.processLoop(ctx)
+		}()
+	}
+
+	wg.Wait()
+	log.Printf("[QUEUE] worker stopped")
+}
+
+func (w *Worker) promoteLoop(ctx context.Context) {
+	ticker := time.NewTicker(promoteInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now()
+			if err := promoteScript.Run(ctx, w.redis, []string{keyScheduled, keyPending}, now.Unix()).Err(); err != nil && ctx.Err() == nil {
+				log.Printf("[QUEUE] promote %s failed: %v", keyScheduled, err)
+			}
+			if err := w.reclaimExpired(ctx, now); err != nil && ctx.Err() == nil {
+				log.Printf("[QUEUE] reclaim %s failed: %v", keyInflight, err)
+			}
+		}
+	}
+}
+
+// reclaimExpired takes back the jobs whose worker didn't finish them within the
+// visibility timeout. The lost run counts as a failed attempt, so a job that
+// keeps crashing or hanging its worker is dead-lettered like any other.
+func (w *Worker) reclaimExpired(ctx context.Context, now time.Time) error {
+	expired, err := w.redis.ZRangeByScore(ctx, keyInflight, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: 100,
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, data := range expired {
+		var job Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			log.Printf("[QUEUE] dropping malformed job: %v", err)
+			w.redis.ZRem(ctx, keyInflight, data)
+			continue
+		}
+
+		job.Attempt++
+		job.LastError = "not finished within the visibility timeout"
+		next, _ := json.Marshal(job)
+		if job.Attempt >= job.MaxAttempts {
+			err = reclaimScript.Run(ctx, w.redis, []string{keyInflight, keyDead}, data, next, "", deadLetterLimit).Err()
+			log.Printf("[QUEUE] job %s (%s) dead-lettered after %d attempts: %s", job.ID, job.Type, job.Attempt, job.LastError)
+		} else {
+			runAt := now.Add(backoff(job.Attempt))
+			err = reclaimScript.Run(ctx, w.redis, []string{keyInflight, keyScheduled}, data, next, runAt.Unix()).Err()
+			log.Printf("[QUEUE] job %s (%s) attempt %d timed out, retrying at %s", job.ID, job.Type, job.Attempt, runAt.Format(time.RFC3339))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Worker) processLoop(ctx context.Context) {
+	for ctx.Err() == nil {
+		deadline := time.Now().Add(visibilityTimeout).Unix()
+		data, err := claimScript.Run(ctx, w.redis, []string{keyPending, keyInflight}, deadline).Text()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) && ctx.Err() == nil {
+				log.Printf("[QUEUE] claim failed: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(idleWait):
+			}
+			continue
+		}
+		w.process(data)
+	}
+}
+
+// process runs one claimed job. It uses its own context so a shutdown lets the job finish.
+func (w *Worker) process(data string) {
+	ctx, cancel := context.WithTimeout(context.Background(), visibilityTimeout)
+	defer cancel()
+
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		log.Printf("[QUEUE] dropping malformed job: %v", err)
+		w.redis.ZRem(ctx, keyInflight, data)
+		return
+	}
+
+	job.Attempt++
+	runErr := w.mux.run(ctx, &job)
+
+	pipe := w.redis.TxPipeline()
+	pipe.ZRem(ctx, keyInflight, data)
+	switch {
+	case runErr == nil:
+	case job.Attempt >= job.MaxAttempts:
+		job.LastError = runErr.Error()
+		dead, _ := json.Marshal(job)
+		pipe.LPush(ctx, keyDead, dead)
+		pipe.LTrim(ctx, keyDead, 0, deadLetterLimit-1)
+		log.Printf("[QUEUE] job %s (%s) dead-lettered after %d attempts: %v", job.ID, job.Type, job.Attempt, runErr)
+	default:
+		job.LastError = runErr.Error()
+		retry, _ := json.Marshal(job)
+		runAt := time.Now().Add(backoff(job.Attempt))
+		pipe.ZAdd(ctx, keyScheduled, redis.Z{Score: float64(runAt.Unix()), Member: retry})
+		log.Printf("[QUEUE] job %s (%s) attempt %d failed, retrying at %s: %v", job.ID, job.Type, job.Attempt, runAt.Format(time.RFC3339), runErr)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[QUEUE] finishing job %s failed: %v", job.ID, err)
+	}
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// activityRepository handles the admin activity feed
+type activityRepository struct {
+	db *gorm.DB
+}
+
+func NewActivityRepository(db *gorm.DB) ActivityRepository {
+	return &activityRepository{db: db}
+}
+
+func (r *activityRepository) Create(activity *models.AdminActivity) error {
+	return r.db.Create(activity).Error
+}
+
+// List returns the feed newest first, optionally of one activity type
+func (r *activityRepository) List(activityType string, page, limit int) ([]models.AdminActivity, int64, error) {
+	var activities []models.AdminActivity
+	var total int64
+
+	query := r.db.Model(&models.AdminActivity{})
+	if activityType != "" {
+		query = query.Where("type = ?", activityType)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&activities).Error
+	return activities, total, err
+}
+
+// Prune deletes activity from before the given time
+func (r *activityRepository) Prune(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.AdminActivity{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// addressRepository handles saved address operations
+type addressRepository struct {
+	db *gorm.DB
+}
+
+func NewAddressRepository(db *gorm.DB) AddressRepository {
+	return &addressRepository{db: db}
+}
+
+func (r *addressRepository) Create(address *models.Address) error {
+	return r.db.Create(address).Error
+}
+
+func (r *addressRepository) GetByID(id uuid.UUID) (*models.Address, error) {
+	var address models.Address
+	err := r.db.First(&address, "id = ?", id).Error
+	return &address, err
+}
+
+func (r *addressRepository) ListByUser(userID uuid.UUID) ([]models.Address, error) {
+	var addresses []models.Address
+	err := r.db.Where("user_id = ?", userID).
+		Order("is_default DESC, created_at DESC").
+		Find(&addresses).Error
+	return addresses, err
+}
+
+func (r *addressRepository) CountByUser(userID uuid.UUID) int64 {
+	var count int64
+	r.db.Model(&models.Address{}).Where("user_id = ?", userID).Count(&count)
+	return count
+}
+
+func (r *addressRepository) Update(address *models.Address) error {
+	return r.db.Save(address).Error
+}
+
+func (r *addressRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Address{}, "id = ?", id).Error
+}
+
+// SetDefault marks one address as the user's default and clears the flag on the others
+func (r *addressRepository) SetDefault(userID, addressID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Address{}).
+			Where("user_id = ? AND id != ?", userID, addressID).
+			Update("is_default", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Address{}).
+			Where("user_id = ? AND id = ?", userID, addressID).
+			Update("is_default", true).Error
+	})
+}
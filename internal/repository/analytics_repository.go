@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+	"gorm.io/gorm"
+)
+
+// FunnelStep is how many actors reached one step of a funnel
+type FunnelStep struct {
+	Step   int    `json:"-"`
+	Name   string `json:"name"`
+	Actors int64  `json:"actors"`
+}
+
+// CohortRow counts the customers who registered in one month. A customer is
+// eligible for a retention window once their first order is that old, and
+// retained when their next order came within it. Cancelled orders never count.
+type CohortRow struct {
+	Month      string         `json:"month"` // YYYY-MM
+	Users      int64          `json:"users"`
+	Customers  int64          `json:"customers"` // placed at least one order
+	Eligible30 int64          `json:"eligible_30"`
+	Retained30 int64          `json:"retained_30"`
+	Eligible60 int64          `json:"eligible_60"`
+	Retained60 int64          `json:"retained_60"`
+	Eligible90 int64          `json:"eligible_90"`
+	Retained90 int64          `json:"retained_90"`
+	Completed  int64          `json:"completed_orders"`
+	Revenue    currency.Money `json:"revenue"` // completed orders, in the base currency
+}
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+func (r *analyticsRepository) RecordProfileView(yandasID uuid.UUID) error {
+	return r.db.Create(&models.ProfileView{YandasID: yandasID}).Error
+}
+
+// RollupDay recounts every yandaş's funnel for the day spanning [from, to) and
+// replaces that day's stats, so running it again for the same day is safe.
+// Revenue is totalled in the base currency.
+func (r *analyticsRepository) RollupDay(from, to time.Time) error {
+	date := from.Format("2006-01-02")
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("date = ?", date).Delete(&models.YandasDailyStat{}).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+			INSERT INTO yandas_daily_stats
+				(yandas_id, date, profile_views, favorite_adds, conversation_starts, orders_created, orders_completed, revenue, updated_at)
+			SELECT yandas_id, ?, SUM(views), SUM(favorites), SUM(conversations), SUM(created), SUM(completed), SUM(revenue), ?
+			FROM (
+				SELECT yandas_id, COUNT(*) AS views, 0 AS favorites, 0 AS conversations, 0 AS created, 0 AS completed, 0 AS revenue
+				FROM profile_views WHERE created_at >= ? AND created_at < ? GROUP BY yandas_id
+				UNION ALL
+				SELECT yandas_id, 0, COUNT(*), 0, 0, 0, 0
+				FROM favorites WHERE created_at >= ? AND created_at < ? GROUP BY yandas_id
+				UNION ALL
+				SELECT p.id, 0, 0, COUNT(*), 0, 0, 0
+				FROM conversations c JOIN yandas_profiles p ON p.user_id = c.yandas_id
+				WHERE c.created_at >= ? AND c.created_at < ? GROUP BY p.id
+				UNION ALL
+				SELECT yandas_id, 0, 0, 0, COUNT(*), 0, 0
+				FROM orders WHERE created_at >= ? AND created_at < ? AND deleted_at IS NULL GROUP BY yandas_id
+				UNION ALL
+				SELECT yandas_id, 0, 0, 0, 0, COUNT(*), SUM(`+inBaseCurrency("agreed_price")+`)
+				FROM orders WHERE status = 'completed' AND completed_at >= ? AND completed_at < ? AND deleted_at IS NULL GROUP BY yandas_id
+			) counts
+			GROUP BY yandas_id`,
+			date, time.Now(),
+			from, to, from, to, from, to, from, to, from, to,
+		).Error
+	})
+}
+
+// ListDaily returns a yandaş's stats for the days in [from, to), oldest first
+func (r *analyticsRepository) ListDaily(yandasID uuid.UUID, from, to time.Time) ([]models.YandasDailyStat, error) {
+	var stats []models.YandasDailyStat
+	err := r.db.
+		Where("yandas_id = ? AND date >= ? AND date < ?", yandasID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("date ASC").
+		Find(&stats).Error
+	return stats, err
+}
+
+// PruneProfileViews deletes raw views older than before and returns how many were removed
+func (r *analyticsRepository) PruneProfileViews(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.ProfileView{})
+	return result.RowsAffected, result.Error
+}
+
+// Cohorts groups the customer accounts registered in [from, to) by month,
+// oldest first; asOf decides which retention windows have passed
+func (r *analyticsRepository) Cohorts(from, to, asOf time.Time) ([]CohortRow, error) {
+	var rows []CohortRow
+	err := r.db.Raw(`
+		WITH cohort AS (
+			SELECT id, date_trunc('month', created_at) AS month FROM users
+			WHERE role = 'customer' AND deleted_at IS NULL AND created_at >= ? AND created_at < ?
+		), placed AS (
+			SELECT orders.customer_id, orders.created_at FROM orders
+			JOIN cohort ON cohort.id = orders.customer_id
+			WHERE orders.deleted_at IS NULL AND orders.status <> 'cancelled'
+		), firsts AS (
+			SELECT customer_id, MIN(created_at) AS first_at FROM placed GROUP BY customer_id
+		), repeats AS (
+			SELECT firsts.customer_id, firsts.first_at, MIN(placed.created_at) AS next_at
+			FROM firsts LEFT JOIN placed ON placed.customer_id = firsts.customer_id AND placed.created_at > firsts.first_at
+			GROUP BY firsts.customer_id, firsts.first_at
+		), spent AS (
+			SELECT orders.customer_id, COUNT(*) AS completed, SUM(`+inBaseCurrency("orders.agreed_price")+`) AS revenue
+			FROM orders JOIN cohort ON cohort.id = orders.customer_id
+			WHERE orders.deleted_at IS NULL AND orders.status = 'completed'
+			GROUP BY orders.customer_id
+		)
+		SELECT to_char(cohort.month, 'YYYY-MM') AS month,
+			COUNT(*) AS users,
+			COUNT(repeats.customer_id) AS customers,
+			COUNT(*) FILTER (WHERE repeats.first_at <= ?::timestamptz - INTERVAL '30 days') AS eligible30,
+			COUNT(*) FILTER (WHERE repeats.first_at <= ?::timestamptz - INTERVAL '30 days' AND repeats.next_at <= repeats.first_at + INTERVAL '30 days') AS retained30,
+			COUNT(*) FILTER (WHERE repeats.first_at <= ?::timestamptz - INTERVAL '60 days') AS eligible60,
+			COUNT(*) FILTER (WHERE repeats.first_at <= ?::timestamptz - INTERVAL '60 days' AND repeats.next_at <= repeats.first_at + INTERVAL '60 days') AS retained60,
+			COUNT(*) FILTER (WHERE repeats.first_at <= ?::timestamptz - INTERVAL '90 days') AS eligible90,
+			COUNT(*) FILTER (WHERE repeats.first_at <= ?::timestamptz - INTERVAL '90 days' AND repeats.next_at <= repeats.first_at + INTERVAL '90 days') AS retained90,
+			COALESCE(SUM(spent.completed), 0) AS completed,
+			COALESCE(SUM(spent.revenue), 0) AS revenue
+		FROM cohort
+		LEFT JOIN repeats ON repeats.customer_id = cohort.id
+		LEFT JOIN spent ON spent.customer_id = cohort.id
+		GROUP BY cohort.month
+		ORDER BY cohort.month`,
+		from, to, asOf, asOf, asOf, asOf, asOf, asOf,
+	).Scan(&rows).Error
+	return rows, err
+}
+
+// RecordEvents stores a batch of product analytics events
+func (r *analyticsRepository) RecordEvents(events []models.AnalyticsEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(events, 100).Error
+}
+
+// Funnel counts the actors who did each of steps, in order, with their first
+// step in [from, to). A step counts from the actor's first event of it after
+// the previous step; later steps may fall after to. Platform narrows the
+// events when set.
+func (r *analyticsRepository) Funnel(steps []string, from, to time.Time, platform string) ([]FunnelStep, error) {
+	if len(steps) == 0 {
+		return nil, nil
+	}
+	platformCond := ""
+	if platform != "" {
+		platformCond = " AND e.platform = @platform"
+	}
+
+	var ctes, counts []string
+	for i := range steps {
+		name := fmt.Sprintf("s%d", i+1)
+		cte := fmt.Sprintf("%s AS (SELECT e.actor, MIN(e.occurred_at) AS at FROM analytics_events e", name)
+		if i == 0 {
+			cte += fmt.Sprintf(" WHERE e.name = @step%d AND e.occurred_at >= @from AND e.occurred_at < @to", i+1)
+		} else {
+			cte += fmt.Sprintf(" JOIN s%d prev ON prev.actor = e.actor AND e.occurred_at >= prev.at WHERE e.name = @step%d", i, i+1)
+		}
+		ctes = append(ctes, cte+platformCond+" GROUP BY e.actor)")
+		counts = append(counts, fmt.Sprintf("SELECT %d AS step, COUNT(*) AS actors FROM %s", i+1, name))
+	}
+
+	args := map[string]interface{}{"from": from, "to": to, "platform": platform}
+	for i, step := range steps {
+		args[fmt.Sprintf("step%d", i+1)] = step
+	}
+	var rows []FunnelStep
+	err := r.db.Raw("WITH "+strings.Join(ctes, ", ")+" "+strings.Join(counts, " UNION ALL ")+" ORDER BY step", args).
+		Scan(&rows).Error
+	for i := range rows {
+		if rows[i].Step >= 1 && rows[i].Step <= len(steps) {
+			rows[i].Name = steps[rows[i].Step-1]
+		}
+	}
+	return rows, err
+}
+
+// PruneEvents deletes analytics events received before before and returns how many were removed
+func (r *analyticsRepository) PruneEvents(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.AnalyticsEvent{})
+	return result.RowsAffected, result.Error
+}
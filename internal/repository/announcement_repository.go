@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type announcementRepository struct {
+	db *gorm.DB
+}
+
+func NewAnnouncementRepository(db *gorm.DB) AnnouncementRepository {
+	return &announcementRepository{db: db}
+}
+
+func (r *announcementRepository) Create(announcement *models.Announcement) error {
+	return r.db.Create(announcement).Error
+}
+
+func (r *announcementRepository) GetByID(id uuid.UUID) (*models.Announcement, error) {
+	var announcement models.Announcement
+	err := r.db.First(&announcement, "id = ?", id).Error
+	return &announcement, err
+}
+
+func (r *announcementRepository) Update(announcement *models.Announcement) error {
+	return r.db.Save(announcement).Error
+}
+
+// Delete removes the announcement together with its dismissals
+func (r *announcementRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("announcement_id = ?", id).Delete(&models.AnnouncementDismissal{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Announcement{}, "id = ?", id).Error
+	})
+}
+
+func (r *announcementRepository) List(page, limit int) ([]models.Announcement, int64, error) {
+	var announcements []models.Announcement
+	var total int64
+
+	query := r.db.Model(&models.Announcement{})
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Order("starts_at DESC").Offset(offset).Limit(limit).Find(&announcements).Error
+	return announcements, total, err
+}
+
+// ListActive returns announcements live at now for the given audiences that
+// userID hasn't dismissed, most severe first
+func (r *announcementRepository) ListActive(userID uuid.UUID, audiences []string, now time.Time) ([]models.Announcement, error) {
+	var announcements []models.Announcement
+	err := r.db.
+		Where("is_active = ? AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", true, now, now).
+		Where("audience IN ?", audiences).
+		Where("NOT EXISTS (SELECT 1 FROM announcement_dismissals d WHERE d.announcement_id = announcements.id AND d.user_id = ?)", userID).
+		Order("CASE severity WHEN 'critical' THEN 0 WHEN 'warning' THEN 1 ELSE 2 END").
+		Order("starts_at DESC").
+		Find(&announcements).Error
+	return announcements, err
+}
+
+// Dismiss records the dismissal; dismissing twice is a no-op
+func (r *announcementRepository) Dismiss(announcementID, userID uuid.UUID) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.AnnouncementDismissal{AnnouncementID: announcementID, UserID: userID}).Error
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// applicationFormRepository handles the per-category application questions
+// and applicants' answers
+type applicationFormRepository struct {
+	db *gorm.DB
+}
+
+func NewApplicationFormRepository(db *gorm.DB) ApplicationFormRepository {
+	return &applicationFormRepository{db: db}
+}
+
+// ListQuestions returns the questions of the categories in form order
+func (r *applicationFormRepository) ListQuestions(categoryIDs []uuid.UUID, activeOnly bool) ([]models.ApplicationQuestion, error) {
+	var questions []models.ApplicationQuestion
+	if len(categoryIDs) == 0 {
+		return questions, nil
+	}
+	query := r.db.Where("category_id IN ?", categoryIDs)
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("sort_order ASC, created_at ASC").Find(&questions).Error
+	return questions, err
+}
+
+func (r *applicationFormRepository) GetQuestion(id uuid.UUID) (*models.ApplicationQuestion, error) {
+	var question models.ApplicationQuestion
+	err := r.db.First(&question, "id = ?", id).Error
+	return &question, err
+}
+
+func (r *applicationFormRepository) CreateQuestion(question *models.ApplicationQuestion) error {
+	return r.db.Create(question).Error
+}
+
+func (r *applicationFormRepository) UpdateQuestion(question *models.ApplicationQuestion) error {
+	return r.db.Save(question).Error
+}
+
+// DeleteQuestion removes a question from the form; answers already given keep
+// their copy of it
+func (r *applicationFormRepository) DeleteQuestion(id uuid.UUID) error {
+	return r.db.Delete(&models.ApplicationQuestion{}, "id = ?", id).Error
+}
+
+func (r *applicationFormRepository) CreateAnswers(answers []models.ApplicationAnswer) error {
+	if len(answers) == 0 {
+		return nil
+	}
+	return r.db.Create(&answers).Error
+}
+
+func (r *applicationFormRepository) ListAnswers(profileID uuid.UUID) ([]models.ApplicationAnswer, error) {
+	var answers []models.ApplicationAnswer
+	err := r.db.Where("yandas_profile_id = ?", profileID).Order("created_at ASC").Find(&answers).Error
+	return answers, err
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// AssignmentCandidate is an available yandaş near an urgent order, with the
+// cheapest of their active services in its category
+type AssignmentCandidate struct {
+	YandasID   uuid.UUID
+	UserID     uuid.UUID
+	ServiceID  uuid.UUID
+	DistanceKm float64
+}
+
+type assignmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAssignmentRepository(db *gorm.DB) AssignmentRepository {
+	return &assignmentRepository{db: db}
+}
+
+func (r *assignmentRepository) Create(assignment *models.AutoAssignment) error {
+	return r.db.Create(assignment).Error
+}
+
+func (r *assignmentRepository) GetByID(id uuid.UUID) (*models.AutoAssignment, error) {
+	var assignment models.AutoAssignment
+	err := r.db.Preload("Category").First(&assignment, "id = ?", id).Error
+	return &assignment, err
+}
+
+// Transition saves the assignment's state only if it is still searching and no
+// other offer was made since it was loaded. Every offer bumps Attempts, so
+// expectAttempts is the value the caller read.
+func (r *assignmentRepository) Transition(assignment *models.AutoAssignment, expectAttempts int) (bool, error) {
+	result := r.db.Model(&models.AutoAssignment{}).
+		Where("id = ? AND status = ? AND attempts = ?", assignment.ID, "searching", expectAttempts).
+		Updates(map[string]interface{}{
+			"status":           assignment.Status,
+			"candidate_id":     assignment.CandidateID,
+			"service_id":       assignment.ServiceID,
+			"offer_expires_at": assignment.OfferExpiresAt,
+			"attempts":         assignment.Attempts,
+			"offered_to":       assignment.OfferedTo,
+			"order_id":         assignment.OrderID,
+			"updated_at":       time.Now(),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// ListExpiredOffers returns searching assignments whose current offer ran out
+func (r *assignmentRepository) ListExpiredOffers(now time.Time, limit int) ([]models.AutoAssignment, error) {
+	var assignments []models.AutoAssignment
+	err := r.db.
+		Where("status = ? AND offer_expires_at <= ?", "searching", now).
+		Order("offer_expires_at ASC").
+		Limit(limit).
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// NearestCandidates returns approved, available yandaşlar within radiusKm of a
+// point offering an active service in the category, nearest first. A bounding
+// box on the location index narrows the search before distances are computed.
+func (r *assignmentRepository) NearestCandidates(categoryID, customerID uuid.UUID, lat, lng, radiusKm float64, exclude []string, limit int) ([]AssignmentCandidate, error) {
+	dLat := radiusKm / 111.0
+	dLng := radiusKm / (111.0 * math.Max(math.Cos(lat*math.Pi/180), 0.01))
+
+	var candidates []AssignmentCandidate
+	err := r.db.Raw(`
+		SELECT * FROM (
+			SELECT DISTINCT ON (p.id) p.id AS yandas_id, p.user_id, s.id AS service_id,
+				6371 * 2 * ASIN(SQRT(
+					POWER(SIN(RADIANS(p.latitude - ?) / 2), 2) +
+					COS(RADIANS(?)) * COS(RADIANS(p.latitude)) * POWER(SIN(RADIANS(p.longitude - ?) / 2), 2)
+				)) AS distance_km
+			FROM yandas_profiles p
+			JOIN yandas_services s ON s.yandas_id = p.id AND s.is_active AND s.status = 'active' AND s.category_id = ?
+			WHERE p.approval_status = 'approved' AND p.is_available AND p.user_id <> ?
+				AND p.latitude BETWEEN ? AND ? AND p.longitude BETWEEN ? AND ?
+				AND NOT (p.id::text = ANY(?))
+			ORDER BY p.id, s.base_price ASC
+		) candidates
+		WHERE distance_km <= ?
+		ORDER BY distance_km ASC
+		LIMIT ?`,
+		lat, lat, lng, categoryID, customerID,
+		lat-dLat, lat+dLat, lng-dLng, lng+dLng,
+		pq.StringArray(exclude), radiusKm, limit,
+	).Scan(&candidates).Error
+	return candidates, err
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// backupRepository records the database backups taken by cmd/backup
+type backupRepository struct {
+	db *gorm.DB
+}
+
+func NewBackupRepository(db *gorm.DB) BackupRepository {
+	return &backupRepository{db: db}
+}
+
+func (r *backupRepository) Create(backup *models.DatabaseBackup) error {
+	return r.db.Create(backup).Error
+}
+
+func (r *backupRepository) Update(backup *models.DatabaseBackup) error {
+	return r.db.Save(backup).Error
+}
+
+// LatestSucceeded returns the newest backup that completed
+func (r *backupRepository) LatestSucceeded() (*models.DatabaseBackup, error) {
+	var backup models.DatabaseBackup
+	err := r.db.Where("status = ?", "succeeded").Order("started_at DESC").First(&backup).Error
+	return &backup, err
+}
+
+// GetByFileName returns the newest backup of a dump file
+func (r *backupRepository) GetByFileName(name string) (*models.DatabaseBackup, error) {
+	var backup models.DatabaseBackup
+	err := r.db.Where("file_name = ?", name).Order("started_at DESC").First(&backup).Error
+	return &backup, err
+}
+
+// List returns the latest backups, newest first
+func (r *backupRepository) List(limit int) ([]models.DatabaseBackup, error) {
+	var backups []models.DatabaseBackup
+	err := r.db.Order("started_at DESC").Limit(limit).Find(&backups).Error
+	return backups, err
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// CallFeedback is one party's quality report of a call
+type CallFeedback struct {
+	Rating   int
+	Issues   []string
+	Platform string
+	Region   string
+}
+
+// CallQualityRow aggregates call feedback of one region and platform
+type CallQualityRow struct {
+	Region    string  `json:"region"`
+	Platform  string  `json:"platform"`
+	Responses int64   `json:"responses"`
+	AvgRating float64 `json:"avg_rating"`
+	Poor      int64   `json:"poor"` // rated 2 or lower
+	Echo      int64   `json:"echo"`
+	Drop      int64   `json:"drop"`
+	NoAudio   int64   `json:"no_audio"`
+	NoVideo   int64   `json:"no_video"`
+}
+
+// callLogRepository handles call log operations
+type callLogRepository struct {
+	db *gorm.DB
+}
+
+func NewCallLogRepository(db *gorm.DB) CallLogRepository {
+	return &callLogRepository{db: db}
+}
+
+func (r *callLogRepository) GetByID(id uuid.UUID) (*models.CallLog, error) {
+	var callLog models.CallLog
+	err := r.db.
+		Preload("Caller").
+		Preload("Callee").
+		First(&callLog, "id = ?", id).Error
+	return &callLog, err
+}
+
+// ListByUser returns calls the user took part in. filter can be "missed" or "answered".
+func (r *callLogRepository) ListByUser(userID uuid.UUID, page, limit int, filter string) ([]models.CallLog, int64, error) {
+	var calls []models.CallLog
+	var total int64
+
+	query := r.db.Model(&models.CallLog{}).Where("caller_id = ? OR callee_id = ?", userID, userID)
+	switch filter {
+	case "missed":
+		query = query.Where("status = ?", "missed")
+	case "answered":
+		query = query.Where("answered_at IS NOT NULL")
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.
+		Preload("Caller").
+		Preload("Callee").
+		Offset(offset).
+		Limit(limit).
+		Order("started_at DESC").
+		Find(&calls).Error
+
+	return calls, total, err
+}
+
+// MarkMissed flips a call that is still ringing to missed. It reports false when
+// the call was answered, rejected or ended in the meantime.
+func (r *callLogRepository) MarkMissed(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&models.CallLog{}).
+		Where("id = ? AND status = ?", id, "ringing").
+		Updates(map[string]interface{}{
+			"status":   "missed",
+			"ended_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListRingExpired returns the calls still ringing past their ring deadline,
+// the longest overdue first
+func (r *callLogRepository) ListRingExpired(now time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&models.CallLog{}).
+		Where("status = ? AND ring_expires_at <= ?", "ringing", now).
+		Order("ring_expires_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// SetFeedback stores a party's feedback on an ended call. party is "caller" or
+// "callee"; it reports false when that party already left feedback.
+func (r *callLogRepository) SetFeedback(id uuid.UUID, party string, feedback CallFeedback) (bool, error) {
+	if party != "caller" && party != "callee" {
+		return false, fmt.Errorf("unknown call party %q", party)
+	}
+	result := r.db.Model(&models.CallLog{}).
+		Where("id = ? AND status = ? AND "+party+"_rating IS NULL", id, "ended").
+		Updates(map[string]interface{}{
+			party + "_rating":   feedback.Rating,
+			party + "_issues":   pq.StringArray(feedback.Issues),
+			party + "_platform": feedback.Platform,
+			party + "_region":   feedback.Region,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// QualityReport aggregates the feedback on calls started in [from, to) by region and platform, most responses first
+func (r *callLogRepository) QualityReport(from, to time.Time) ([]CallQualityRow, error) {
+	var rows []CallQualityRow
+	err := r.db.Raw(`
+		WITH feedback AS (
+			SELECT caller_rating AS rating, caller_issues AS issues, caller_platform AS platform, caller_region AS region
+			FROM call_logs WHERE caller_rating IS NOT NULL AND started_at >= ? AND started_at < ?
+			UNION ALL
+			SELECT callee_rating, callee_issues, callee_platform, callee_region
+			FROM call_logs WHERE callee_rating IS NOT NULL AND started_at >= ? AND started_at < ?
+		)
+		SELECT COALESCE(NULLIF(region, ''), 'unknown') AS region,
+			COALESCE(NULLIF(platform, ''), 'unknown') AS platform,
+			COUNT(*) AS responses,
+			ROUND(AVG(rating)::numeric, 2) AS avg_rating,
+			COUNT(*) FILTER (WHERE rating <= 2) AS poor,
+			COUNT(*) FILTER (WHERE 'echo' = ANY(issues)) AS echo,
+			COUNT(*) FILTER (WHERE 'drop' = ANY(issues)) AS "drop",
+			COUNT(*) FILTER (WHERE 'no_audio' = ANY(issues)) AS no_audio,
+			COUNT(*) FILTER (WHERE 'no_video' = ANY(issues)) AS no_video
+		FROM feedback
+		GROUP BY 1, 2
+		ORDER BY responses DESC, region, platform`, from, to, from, to).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// ListActive returns answered calls that have not ended yet, newest first
+func (r *callLogRepository) ListActive(page, limit int) ([]models.CallLog, int64, error) {
+	var calls []models.CallLog
+	var total int64
+
+	query := r.db.Model(&models.CallLog{}).Where("status = ?", "answered")
+	query.Count(&total)
+
+	err := query.
+		Preload("Caller").
+		Preload("Callee").
+		Preload("Participants", "left_at IS NULL").
+		Order("answered_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&calls).Error
+	return calls, total, err
+}
+
+// AddParticipant puts a user into a call with the next free Agora uid after the
+// caller's 1 and callee's 2. A participant who left and rejoins keeps their uid.
+func (r *callLogRepository) AddParticipant(callID, userID uuid.UUID, role string) (*models.CallParticipant, error) {
+	var participant models.CallParticipant
+	err := r.db.Raw(`
+		INSERT INTO call_participants (call_id, user_id, uid, role, joined_at)
+		SELECT ?, ?, COALESCE(MAX(uid), 2) + 1, ?, ? FROM call_participants WHERE call_id = ?
+		ON CONFLICT (call_id, user_id) DO UPDATE SET left_at = NULL, joined_at = EXCLUDED.joined_at
+		RETURNING *`, callID, userID, role, time.Now(), callID).
+		Scan(&participant).Error
+	return &participant, err
+}
+
+// GetParticipant returns a user's participation in a call while they are in it
+func (r *callLogRepository) GetParticipant(callID, userID uuid.UUID) (*models.CallParticipant, error) {
+	var participant models.CallParticipant
+	err := r.db.Where("call_id = ? AND user_id = ? AND left_at IS NULL", callID, userID).First(&participant).Error
+	return &participant, err
+}
+
+// ListParticipants returns the participants still in a call
+func (r *callLogRepository) ListParticipants(callID uuid.UUID) ([]models.CallParticipant, error) {
+	var participants []models.CallParticipant
+	err := r.db.Where("call_id = ? AND left_at IS NULL", callID).Order("uid").Find(&participants).Error
+	return participants, err
+}
+
+// LeaveParticipant marks a participant as gone; it reports false when they were not in the call
+func (r *callLogRepository) LeaveParticipant(callID, userID uuid.UUID) (bool, error) {
+	result := r.db.Model(&models.CallParticipant{}).
+		Where("call_id = ? AND user_id = ? AND left_at IS NULL", callID, userID).
+		Update("left_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// SetPushResult records how the incoming call push to the callee went
+func (r *callLogRepository) SetPushResult(id uuid.UUID, status string, pushErr *string) error {
+	updates := map[string]interface{}{"push_status": status, "push_error": pushErr}
+	if status == "sent" {
+		updates["push_sent_at"] = time.Now()
+	}
+	return r.db.Model(&models.CallLog{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// MarkDelivered records when the callee's first device started ringing; later reports are ignored
+func (r *callLogRepository) MarkDelivered(id, calleeID uuid.UUID, via string) (bool, error) {
+	result := r.db.Model(&models.CallLog{}).
+		Where("id = ? AND callee_id = ? AND delivered_at IS NULL", id, calleeID).
+		Updates(map[string]interface{}{"delivered_at": time.Now(), "delivered_via": via})
+	return result.RowsAffected == 1, result.Error
+}
@@ -1,21 +1,23 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/models"
 	"gorm.io/gorm"
 )
 
-// CategoryRepository handles category operations
-type CategoryRepository struct {
+// categoryRepository handles category operations
+type categoryRepository struct {
 	db *gorm.DB
 }
 
-func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
-	return &CategoryRepository{db: db}
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
 }
 
-func (r *CategoryRepository) List() ([]models.Category, error) {
+func (r *categoryRepository) List() ([]models.Category, error) {
 	var categories []models.Category
 	err := r.db.Where("is_active = ? AND parent_id IS NULL", true).
 		Preload("SubCategories", "is_active = ?", true).
@@ -23,59 +25,180 @@ func (r *CategoryRepository) List() ([]models.Category, error) {
 	return categories, err
 }
 
-func (r *CategoryRepository) GetByID(id uuid.UUID) (*models.Category, error) {
+// ListAll returns every category, inactive ones and subcategories included, flat
+func (r *categoryRepository) ListAll() ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.Order("sort_order ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) GetByID(id uuid.UUID) (*models.Category, error) {
 	var category models.Category
 	err := r.db.First(&category, "id = ?", id).Error
 	return &category, err
 }
 
-func (r *CategoryRepository) GetBySlug(slug string) (*models.Category, error) {
+func (r *categoryRepository) GetBySlug(slug string) (*models.Category, error) {
 	var category models.Category
 	err := r.db.First(&category, "slug = ?", slug).Error
 	return &category, err
 }
 
-func (r *CategoryRepository) Create(category *models.Category) error {
+func (r *categoryRepository) Create(category *models.Category) error {
 	return r.db.Create(category).Error
 }
 
-func (r *CategoryRepository) Update(category *models.Category) error {
+func (r *categoryRepository) Update(category *models.Category) error {
 	return r.db.Save(category).Error
 }
 
-func (r *CategoryRepository) Delete(id uuid.UUID) error {
+func (r *categoryRepository) Delete(id uuid.UUID) error {
 	return r.db.Model(&models.Category{}).Where("id = ?", id).Update("is_active", false).Error
 }
 
-// ServiceRepository handles yandaş service operations
-type ServiceRepository struct {
+// serviceRepository handles yandaş service operations
+type serviceRepository struct {
 	db *gorm.DB
 }
 
-func NewServiceRepository(db *gorm.DB) *ServiceRepository {
-	return &ServiceRepository{db: db}
+func NewServiceRepository(db *gorm.DB) ServiceRepository {
+	return &serviceRepository{db: db}
 }
 
-func (r *ServiceRepository) Create(service *models.YandasService) error {
+func (r *serviceRepository) Create(service *models.YandasService) error {
 	return r.db.Create(service).Error
 }
 
-func (r *ServiceRepository) GetByID(id uuid.UUID) (*models.YandasService, error) {
+func (r *serviceRepository) GetByID(id uuid.UUID) (*models.YandasService, error) {
 	var service models.YandasService
-	err := r.db.Preload("Category").First(&service, "id = ?", id).Error
+	err := r.withPricing(r.db.Preload("Category")).First(&service, "id = ?", id).Error
 	return &service, err
 }
 
-func (r *ServiceRepository) GetByYandasID(yandasID uuid.UUID) ([]models.YandasService, error) {
+// GetByYandasID returns a yandaş's listed services: active and not archived
+func (r *serviceRepository) GetByYandasID(yandasID uuid.UUID) ([]models.YandasService, error) {
 	var services []models.YandasService
-	err := r.db.Preload("Category").Where("yandas_id = ? AND is_active = ?", yandasID, true).Find(&services).Error
+	err := r.withPricing(r.db.Preload("Category")).
+		Where("yandas_id = ? AND is_active = ? AND status = ?", yandasID, true, "active").
+		Find(&services).Error
 	return services, err
 }
 
-func (r *ServiceRepository) Update(service *models.YandasService) error {
-	return r.db.Save(service).Error
+// ListByYandas returns a yandaş's own services in every status, or with
+// includeArchived also the archived ones, newest first. Deleted services are
+// never listed.
+func (r *serviceRepository) ListByYandas(yandasID uuid.UUID, includeArchived bool) ([]models.YandasService, error) {
+	var services []models.YandasService
+	query := r.withPricing(r.db.Preload("Category")).Where("yandas_id = ? AND deleted_at IS NULL", yandasID)
+	if !includeArchived {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("created_at DESC").Find(&services).Error
+	return services, err
+}
+
+// ListByStatus returns services in a publication status for moderation,
+// oldest first so the queue is worked in order
+func (r *serviceRepository) ListByStatus(status string, page, limit int) ([]models.YandasService, int64, error) {
+	var services []models.YandasService
+	var total int64
+
+	query := r.db.Model(&models.YandasService{}).Where("status = ? AND deleted_at IS NULL", status)
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := r.withPricing(query.Preload("Category")).
+		Order("created_at ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&services).Error
+
+	return services, total, err
+}
+
+// ListPublic returns active services of approved, available yandaşlar in active categories
+func (r *serviceRepository) ListPublic(filter ServiceFilter, page, limit int) ([]models.ServiceListItem, int64, error) {
+	var items []models.ServiceListItem
+	var total int64
+
+	query := filter.apply(r.db.Model(&models.YandasService{}).
+		Joins("JOIN yandas_profiles ON yandas_profiles.id = yandas_services.yandas_id").
+		Joins("JOIN users ON users.id = yandas_profiles.user_id AND users.deleted_at IS NULL").
+		Joins("JOIN categories ON categories.id = yandas_services.category_id").
+		Where("yandas_services.is_active = ? AND yandas_services.status = ?", true, "active").
+		Where("categories.is_active = ?", true).
+		Where("yandas_profiles.approval_status = ?", "approved").
+		Where("yandas_profiles.is_available = ?", true))
+
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.
+		Select(`yandas_services.id, yandas_services.title, yandas_services.base_price, yandas_services.currency,
+			yandas_services.duration_minutes, categories.id AS category_id, categories.name AS category_name,
+			categories.slug AS category_slug, yandas_profiles.id AS yandas_id, users.full_name AS yandas_name,
+			users.avatar_url AS yandas_avatar_url, yandas_profiles.rating_avg AS yandas_rating,
+			yandas_profiles.total_jobs AS yandas_total_jobs`).
+		Order(filter.order()).
+		Offset(offset).
+		Limit(limit).
+		Scan(&items).Error
+
+	return items, total, err
+}
+
+// withPricing preloads active add-ons and price tiers in ascending band order
+func (r *serviceRepository) withPricing(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("Options", "is_active = ?", true).
+		Preload("PriceTiers", func(db *gorm.DB) *gorm.DB {
+			return db.Order("basis ASC, up_to ASC")
+		})
+}
+
+func (r *serviceRepository) Update(service *models.YandasService) error {
+	return saveVersioned(r.db, service, &service.Version)
+}
+
+func (r *serviceRepository) CreateOption(option *models.ServiceOption) error {
+	return r.db.Create(option).Error
+}
+
+func (r *serviceRepository) GetOption(id uuid.UUID) (*models.ServiceOption, error) {
+	var option models.ServiceOption
+	err := r.db.First(&option, "id = ?", id).Error
+	return &option, err
+}
+
+func (r *serviceRepository) UpdateOption(option *models.ServiceOption) error {
+	return r.db.Save(option).Error
+}
+
+// ReplacePriceTiers swaps a service's tiers for the given set
+func (r *serviceRepository) ReplacePriceTiers(serviceID uuid.UUID, tiers []models.ServicePriceTier) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("service_id = ?", serviceID).Delete(&models.ServicePriceTier{}).Error; err != nil {
+			return err
+		}
+		if len(tiers) == 0 {
+			return nil
+		}
+		return tx.Create(&tiers).Error
+	})
+}
+
+// Delete hides a service for good; the row stays for the orders that reference it
+func (r *serviceRepository) Delete(id uuid.UUID) error {
+	return r.db.Model(&models.YandasService{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"is_active": false, "deleted_at": time.Now()}).Error
 }
 
-func (r *ServiceRepository) Delete(id uuid.UUID) error {
-	return r.db.Model(&models.YandasService{}).Where("id = ?", id).Update("is_active", false).Error
+// CountActiveByYandasID counts the services that take up a plan slot: listed
+// ones and ones awaiting approval
+func (r *serviceRepository) CountActiveByYandasID(yandasID uuid.UUID) int64 {
+	var count int64
+	r.db.Model(&models.YandasService{}).
+		Where("yandas_id = ? AND is_active = ? AND status IN ?", yandasID, true, []string{"active", "pending"}).
+		Count(&count)
+	return count
 }
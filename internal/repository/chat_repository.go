@@ -6,22 +6,23 @@ import (
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 )
 
-// ConversationRepository handles conversation operations
-type ConversationRepository struct {
+// conversationRepository handles conversation operations
+type conversationRepository struct {
 	db *gorm.DB
 }
 
-func NewConversationRepository(db *gorm.DB) *ConversationRepository {
-	return &ConversationRepository{db: db}
+func NewConversationRepository(db *gorm.DB) ConversationRepository {
+	return &conversationRepository{db: db}
 }
 
-func (r *ConversationRepository) Create(conv *models.Conversation) error {
+func (r *conversationRepository) Create(conv *models.Conversation) error {
 	return r.db.Create(conv).Error
 }
 
-func (r *ConversationRepository) GetByID(id uuid.UUID) (*models.Conversation, error) {
+func (r *conversationRepository) GetByID(id uuid.UUID) (*models.Conversation, error) {
 	var conv models.Conversation
 	err := r.db.
 		Preload("Customer").
@@ -30,7 +31,7 @@ func (r *ConversationRepository) GetByID(id uuid.UUID) (*models.Conversation, er
 	return &conv, err
 }
 
-func (r *ConversationRepository) GetByParticipants(customerID, yandasID uuid.UUID) (*models.Conversation, error) {
+func (r *conversationRepository) GetByParticipants(customerID, yandasID uuid.UUID) (*models.Conversation, error) {
 	var conv models.Conversation
 	err := r.db.
 		Where("customer_id = ? AND yandas_id = ?", customerID, yandasID).
@@ -38,7 +39,17 @@ func (r *ConversationRepository) GetByParticipants(customerID, yandasID uuid.UUI
 	return &conv, err
 }
 
-func (r *ConversationRepository) GetOrCreate(customerID, yandasID uuid.UUID, orderID *uuid.UUID) (*models.Conversation, error) {
+// FindBetween returns the conversation between two users regardless of which side is the customer
+func (r *conversationRepository) FindBetween(userA, userB uuid.UUID) (*models.Conversation, error) {
+	var conv models.Conversation
+	err := r.db.
+		Where("(customer_id = ? AND yandas_id = ?) OR (customer_id = ? AND yandas_id = ?)", userA, userB, userB, userA).
+		Order("last_message_at DESC NULLS LAST").
+		First(&conv).Error
+	return &conv, err
+}
+
+func (r *conversationRepository) GetOrCreate(customerID, yandasID uuid.UUID, orderID *uuid.UUID) (*models.Conversation, error) {
 	conv, err := r.GetByParticipants(customerID, yandasID)
 	if err == nil {
 		// Re-fetch with preloads
@@ -63,7 +74,14 @@ func (r *ConversationRepository) GetOrCreate(customerID, yandasID uuid.UUID, ord
 	return r.GetByID(conv.ID)
 }
 
-func (r *ConversationRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.Conversation, int64, error) {
+// LinkOrder points a conversation at the order it is currently about
+func (r *conversationRepository) LinkOrder(id, orderID uuid.UUID) error {
+	return r.db.Model(&models.Conversation{}).
+		Where("id = ?", id).
+		Update("order_id", orderID).Error
+}
+
+func (r *conversationRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.Conversation, int64, error) {
 	var convs []models.Conversation
 	var total int64
 
@@ -84,26 +102,168 @@ func (r *ConversationRepository) ListByUser(userID uuid.UUID, page, limit int) (
 	return convs, total, err
 }
 
-func (r *ConversationRepository) UpdateLastMessage(id uuid.UUID) error {
+// ConversationMatch is a conversation found by search: through its latest
+// message matching the query, or only through the other participant's name,
+// in which case the message fields are nil. The snippet marks matched words
+// with <mark> tags.
+type ConversationMatch struct {
+	Conversation models.Conversation
+	MessageID    *uuid.UUID
+	MessageAt    *time.Time
+	Snippet      *string
+}
+
+// conversationMatchesQuery finds a user's conversations whose text messages
+// match a full-text query or whose other participant's name contains a
+// substring. Encrypted messages cannot be matched in the database, so with
+// encryption at rest only names are searched (see ContentSearchable); the
+// partial idx_messages_content_search index covers plaintext messages only. Arguments: query, user ID twice, user ID and name pattern twice.
+const conversationMatchesQuery = `
+	WITH q AS (SELECT websearch_to_tsquery('simple', ?) AS query),
+	mine AS (
+		SELECT id, customer_id, yandas_id, last_message_at FROM conversations
+		WHERE customer_id = ? OR yandas_id = ?
+	),
+	hits AS (
+		SELECT DISTINCT ON (m.conversation_id) m.conversation_id, m.id AS message_id, m.created_at AS message_at,
+			ts_headline('simple', m.content, q.query, 'StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=8, MaxFragments=1') AS snippet
+		FROM messages m JOIN mine ON mine.id = m.conversation_id, q
+		WHERE m.message_type = 'text' AND NOT m.encrypted AND to_tsvector('simple', m.content) @@ q.query
+		ORDER BY m.conversation_id, m.created_at DESC, m.id DESC
+	),
+	matches AS (
+		SELECT mine.id, hits.message_id, hits.message_at, hits.snippet,
+			COALESCE(hits.message_at, mine.last_message_at) AS sort_at
+		FROM mine
+		LEFT JOIN hits ON hits.conversation_id = mine.id
+		JOIN users customer ON customer.id = mine.customer_id
+		JOIN users yandas ON yandas.id = mine.yandas_id
+		WHERE hits.conversation_id IS NOT NULL
+			OR (mine.customer_id = ? AND yandas.full_name ILIKE ?)
+			OR (mine.yandas_id = ? AND customer.full_name ILIKE ?)
+	)`
+
+// Search returns a page of the user's conversations matching a query, the
+// most recently matched first
+func (r *conversationRepository) Search(userID uuid.UUID, query string, page, limit int) ([]ConversationMatch, int64, error) {
+	pattern := "%" + query + "%"
+	args := []interface{}{query, userID, userID, userID, pattern, userID, pattern}
+
+	var total int64
+	if err := r.db.Raw(conversationMatchesQuery+` SELECT COUNT(*) FROM matches`, args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		ID        uuid.UUID
+		MessageID *uuid.UUID
+		MessageAt *time.Time
+		Snippet   *string
+	}
+	offset := (page - 1) * limit
+	err := r.db.Raw(conversationMatchesQuery+`
+		SELECT id, message_id, message_at, snippet FROM matches
+		ORDER BY sort_at DESC NULLS LAST, id
+		LIMIT ? OFFSET ?`, append(args, limit, offset)...).Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, total, err
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	var convs []models.Conversation
+	if err := r.db.Preload("Customer").Preload("Yandas").Where("id IN ?", ids).Find(&convs).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uuid.UUID]models.Conversation, len(convs))
+	for _, conv := range convs {
+		byID[conv.ID] = conv
+	}
+
+	matches := make([]ConversationMatch, 0, len(rows))
+	for _, row := range rows {
+		conv, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		matches = append(matches, ConversationMatch{Conversation: conv, MessageID: row.MessageID, MessageAt: row.MessageAt, Snippet: row.Snippet})
+	}
+	return matches, total, nil
+}
+
+// ConversationFlags are the reasons staff may read a conversation: orders
+// between its participants that are disputed, and support tickets still open
+// about those orders
+type ConversationFlags struct {
+	DisputedOrders []uuid.UUID `json:"disputed_orders"`
+	SupportTickets []uuid.UUID `json:"support_tickets"`
+}
+
+// Flagged reports whether anything puts the conversation under review
+func (f *ConversationFlags) Flagged() bool {
+	return len(f.DisputedOrders) > 0 || len(f.SupportTickets) > 0
+}
+
+// participantOrders selects the IDs of orders between a conversation's
+// customer and yandaş user
+const participantOrders = `SELECT orders.id FROM orders
+	JOIN yandas_profiles ON yandas_profiles.id = orders.yandas_id
+	WHERE orders.customer_id = ? AND yandas_profiles.user_id = ? AND orders.deleted_at IS NULL`
+
+// GetFlags returns why a conversation may be reviewed by staff
+func (r *conversationRepository) GetFlags(conv *models.Conversation) (*ConversationFlags, error) {
+	flags := &ConversationFlags{DisputedOrders: []uuid.UUID{}, SupportTickets: []uuid.UUID{}}
+	err := r.db.Raw(participantOrders+` AND orders.status = 'disputed'`, conv.CustomerID, conv.YandasID).
+		Scan(&flags.DisputedOrders).Error
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Raw(`SELECT id FROM support_tickets
+		WHERE order_id IN (`+participantOrders+`) AND status NOT IN ('resolved', 'closed')`, conv.CustomerID, conv.YandasID).
+		Scan(&flags.SupportTickets).Error
+	if err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+func (r *conversationRepository) UpdateLastMessage(id uuid.UUID) error {
 	return r.db.Model(&models.Conversation{}).
 		Where("id = ?", id).
 		Update("last_message_at", time.Now()).Error
 }
 
-// MessageRepository handles message operations
-type MessageRepository struct {
-	db *gorm.DB
+// messageRepository handles message operations. With keys set, content is
+// encrypted on the way in and decrypted on the way out.
+type messageRepository struct {
+	db   *gorm.DB
+	keys *MessageKeys
 }
 
-func NewMessageRepository(db *gorm.DB) *MessageRepository {
-	return &MessageRepository{db: db}
+func NewMessageRepository(db *gorm.DB, keys *MessageKeys) MessageRepository {
+	return &messageRepository{db: db, keys: keys}
 }
 
-func (r *MessageRepository) Create(msg *models.Message) error {
-	return r.db.Create(msg).Error
+// Create stores the message, sealing its content when encryption is on. msg
+// keeps the plaintext, so callers can broadcast it as is.
+func (r *messageRepository) Create(msg *models.Message) error {
+	if r.keys == nil {
+		return r.db.Create(msg).Error
+	}
+	sealed, err := r.keys.seal(r.db, msg.ConversationID, msg.Content)
+	if err != nil {
+		return err
+	}
+	content := msg.Content
+	msg.Content, msg.Encrypted = sealed, true
+	err = r.db.Create(msg).Error
+	msg.Content = content
+	return err
 }
 
-func (r *MessageRepository) GetByConversation(conversationID uuid.UUID, page, limit int) ([]models.Message, int64, error) {
+func (r *messageRepository) GetByConversation(conversationID uuid.UUID, page, limit int) ([]models.Message, int64, error) {
 	var messages []models.Message
 	var total int64
 
@@ -117,17 +277,68 @@ func (r *MessageRepository) GetByConversation(conversationID uuid.UUID, page, li
 		Limit(limit).
 		Order("created_at DESC").
 		Find(&messages).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return messages, total, r.keys.open(r.db, messages)
+}
+
+// ListUpTo returns a conversation's messages sent at or before the given one,
+// newest first; (createdAt, id) is the position of that message
+func (r *messageRepository) ListUpTo(conversationID uuid.UUID, createdAt time.Time, id uuid.UUID, limit int) ([]models.Message, error) {
+	var messages []models.Message
+	err := r.db.
+		Preload("Sender").
+		Where("conversation_id = ?", conversationID).
+		Where("(created_at, id) <= (?, ?)", createdAt, id).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, r.keys.open(r.db, messages)
+}
 
-	return messages, total, err
+// StreamByConversation passes a conversation's messages to fn in batches,
+// oldest first
+func (r *messageRepository) StreamByConversation(conversationID uuid.UUID, fn func(messages []models.Message) error) error {
+	var lastAt time.Time
+	var lastID uuid.UUID
+	for {
+		var batch []models.Message
+		query := r.db.Preload("Sender").Where("conversation_id = ?", conversationID)
+		if lastID != uuid.Nil {
+			query = query.Where("(created_at, id) > (?, ?)", lastAt, lastID)
+		}
+		if err := query.Order("created_at, id").Limit(exportBatchSize).Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := r.keys.open(r.db, batch); err != nil {
+			return err
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < exportBatchSize {
+			return nil
+		}
+		last := batch[len(batch)-1]
+		lastAt, lastID = last.CreatedAt, last.ID
+	}
 }
 
-func (r *MessageRepository) MarkAsRead(conversationID, userID uuid.UUID) error {
+func (r *messageRepository) MarkAsRead(conversationID, userID uuid.UUID) error {
 	return r.db.Model(&models.Message{}).
 		Where("conversation_id = ? AND sender_id != ? AND is_read = ?", conversationID, userID, false).
 		Update("is_read", true).Error
 }
 
-func (r *MessageRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
+func (r *messageRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
 	var count int64
 	err := r.db.Model(&models.Message{}).
 		Joins("JOIN conversations ON conversations.id = messages.conversation_id").
@@ -136,3 +347,88 @@ func (r *MessageRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
 		Count(&count).Error
 	return count, err
 }
+
+// CountBetween counts a conversation's messages sent in [from, to) and returns when the latest of them was sent
+func (r *messageRepository) CountBetween(conversationID uuid.UUID, from, to time.Time) (int64, *time.Time, error) {
+	var result struct {
+		Count  int64
+		LastAt *time.Time
+	}
+	err := r.db.Model(&models.Message{}).
+		Select("COUNT(*) AS count, MAX(created_at) AS last_at").
+		Where("conversation_id = ? AND created_at >= ? AND created_at < ?", conversationID, from, to).
+		Scan(&result).Error
+	return result.Count, result.LastAt, err
+}
+
+// UpdateLiveLocation moves a live location message while its sender is still sharing it
+func (r *messageRepository) UpdateLiveLocation(id, conversationID, senderID uuid.UUID, latitude, longitude float64, now time.Time) (bool, error) {
+	result := r.db.Model(&models.Message{}).
+		Where("id = ? AND conversation_id = ? AND sender_id = ?", id, conversationID, senderID).
+		Where("message_type = ? AND live_ended_at IS NULL AND live_until > ?", "location", now).
+		Updates(map[string]interface{}{"latitude": latitude, "longitude": longitude})
+	return result.RowsAffected == 1, result.Error
+}
+
+// EndLiveLocation stops a live location share early
+func (r *messageRepository) EndLiveLocation(id, conversationID, senderID uuid.UUID, now time.Time) (bool, error) {
+	result := r.db.Model(&models.Message{}).
+		Where("id = ? AND conversation_id = ? AND sender_id = ?", id, conversationID, senderID).
+		Where("message_type = ? AND live_ended_at IS NULL AND live_until > ?", "location", now).
+		Update("live_ended_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ExpireLiveLocations ends the live shares whose time ran out and returns them
+func (r *messageRepository) ExpireLiveLocations(now time.Time) ([]models.Message, error) {
+	var messages []models.Message
+	err := r.db.Raw(`
+		UPDATE messages SET live_ended_at = live_until
+		WHERE live_ended_at IS NULL AND live_until <= ?
+		RETURNING *`, now).
+		Scan(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, r.keys.open(r.db, messages)
+}
+
+// ContentSearchable reports whether new messages are stored in plaintext, so
+// conversation search can match their content. Sealed messages can't be
+// matched in the database.
+func (r *messageRepository) ContentSearchable() bool {
+	return r.keys == nil
+}
+
+// EncryptPlaintext seals up to limit messages that are still stored in
+// plaintext and returns how many it sealed; zero means none are left
+func (r *messageRepository) EncryptPlaintext(limit int) (int, error) {
+	if r.keys == nil {
+		return 0, ErrMessageEncryptionOff
+	}
+
+	var batch []models.Message
+	err := r.db.Clauses(dbresolver.Write).
+		Select("id", "conversation_id", "content").
+		Where("encrypted = ?", false).
+		Order("id").
+		Limit(limit).
+		Find(&batch).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, msg := range batch {
+		sealed, err := r.keys.seal(r.db, msg.ConversationID, msg.Content)
+		if err != nil {
+			return 0, err
+		}
+		err = r.db.Model(&models.Message{}).
+			Where("id = ? AND encrypted = ?", msg.ID, false).
+			Updates(map[string]interface{}{"content": sealed, "encrypted": true}).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(batch), nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type consentRepository struct {
+	db *gorm.DB
+}
+
+func NewConsentRepository(db *gorm.DB) ConsentRepository {
+	return &consentRepository{db: db}
+}
+
+// AcceptedIDs returns which of documentIDs the user accepted
+func (r *consentRepository) AcceptedIDs(userID uuid.UUID, documentIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if len(documentIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.UserConsent{}).
+		Where("user_id = ? AND document_id IN ?", userID, documentIDs).
+		Pluck("document_id", &ids).Error
+	return ids, err
+}
+
+// Accept records the acceptances; accepting a version twice keeps the first
+func (r *consentRepository) Accept(consents []models.UserConsent) error {
+	if len(consents) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&consents).Error
+}
+
+// ListByUser returns every version the user accepted, newest first
+func (r *consentRepository) ListByUser(userID uuid.UUID) ([]models.UserConsent, error) {
+	var consents []models.UserConsent
+	err := r.db.Preload("Document").
+		Where("user_id = ?", userID).
+		Order("accepted_at DESC").
+		Find(&consents).Error
+	return consents, err
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type demandRepository struct {
+	db *gorm.DB
+}
+
+func NewDemandRepository(db *gorm.DB) DemandRepository {
+	return &demandRepository{db: db}
+}
+
+// Count open orders and available yandaşlar per city and category. An order's
+// city is its customer's default address, falling back to the yandaş's first
+// service city; a yandaş counts in every city they serve.
+const demandCountsQuery = `
+	WITH demand AS (
+		SELECT COALESCE(
+				(SELECT NULLIF(a.city, '') FROM addresses a WHERE a.user_id = o.customer_id AND a.is_default LIMIT 1),
+				p.service_cities[1]
+			) AS city,
+			s.category_id, COUNT(*) AS open_orders
+		FROM orders o
+		JOIN yandas_services s ON s.id = o.service_id
+		JOIN yandas_profiles p ON p.id = o.yandas_id
+		WHERE o.status IN ('pending', 'accepted') AND o.deleted_at IS NULL
+		GROUP BY 1, 2
+	), supply AS (
+		SELECT c.city, s.category_id, COUNT(DISTINCT p.id) AS available_yandas
+		FROM yandas_profiles p
+		CROSS JOIN LATERAL unnest(p.service_cities) AS c(city)
+		JOIN yandas_services s ON s.yandas_id = p.id AND s.is_active AND s.status = 'active'
+		WHERE p.approval_status = 'approved' AND p.is_available
+		GROUP BY 1, 2
+	)
+	SELECT COALESCE(d.city, s.city) AS city,
+		COALESCE(d.category_id, s.category_id) AS category_id,
+		COALESCE(d.open_orders, 0) AS open_orders,
+		COALESCE(s.available_yandas, 0) AS available_yandas
+	FROM demand d
+	FULL OUTER JOIN supply s ON s.city = d.city AND s.category_id = d.category_id
+	WHERE COALESCE(d.city, s.city) IS NOT NULL AND COALESCE(d.category_id, s.category_id) IS NOT NULL`
+
+// Counts returns the current open orders and available yandaşlar; Ratio, Level
+// and ComputedAt are left for the caller
+func (r *demandRepository) Counts() ([]models.CategoryDemand, error) {
+	var rows []models.CategoryDemand
+	err := r.db.Raw(demandCountsQuery).Scan(&rows).Error
+	return rows, err
+}
+
+// Replace swaps the stored demand for rows in one transaction
+func (r *demandRepository) Replace(rows []models.CategoryDemand) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.CategoryDemand{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Omit("Category").CreateInBatches(rows, 500).Error
+	})
+}
+
+// ListByCity returns a city's demand, busiest first; an empty city returns every city
+func (r *demandRepository) ListByCity(city string) ([]models.CategoryDemand, error) {
+	var rows []models.CategoryDemand
+	query := r.db.Preload("Category")
+	if city != "" {
+		query = query.Where("LOWER(city) = LOWER(?)", city)
+	}
+	err := query.Order("ratio DESC").Order("open_orders DESC").Find(&rows).Error
+	return rows, err
+}
+
+// CategoryIDsAtLevel returns the categories whose demand in city is at level
+func (r *demandRepository) CategoryIDsAtLevel(city, level string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&models.CategoryDemand{}).
+		Where("LOWER(city) = LOWER(?) AND level = ?", city, level).
+		Pluck("category_id", &ids).Error
+	return ids, err
+}
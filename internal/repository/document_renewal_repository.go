@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// Open renewals are the ones still waiting for the yandaş
+var openRenewalStatuses = []string{"requested", "lapsed"}
+
+// renewableDocuments maps the documents that go stale to the profile column
+// holding their upload time
+var renewableDocuments = map[string]string{
+	"adli_sicil": "adli_sicil_uploaded_at",
+}
+
+// documentRenewalRepository handles requests to renew stale yandaş documents
+type documentRenewalRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentRenewalRepository(db *gorm.DB) DocumentRenewalRepository {
+	return &documentRenewalRepository{db: db}
+}
+
+func (r *documentRenewalRepository) Create(renewal *models.DocumentRenewal) error {
+	return r.db.Create(renewal).Error
+}
+
+func (r *documentRenewalRepository) GetByID(id uuid.UUID) (*models.DocumentRenewal, error) {
+	var renewal models.DocumentRenewal
+	err := r.db.Preload("YandasProfile").First(&renewal, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &renewal, nil
+}
+
+func (r *documentRenewalRepository) Update(renewal *models.DocumentRenewal) error {
+	return r.db.Omit("YandasProfile").Save(renewal).Error
+}
+
+// GetOpen returns the requested or lapsed renewal of a profile's document
+func (r *documentRenewalRepository) GetOpen(profileID uuid.UUID, document string) (*models.DocumentRenewal, error) {
+	var renewal models.DocumentRenewal
+	err := r.db.Where("yandas_profile_id = ? AND document = ? AND status IN ?", profileID, document, openRenewalStatuses).
+		First(&renewal).Error
+	if err != nil {
+		return nil, err
+	}
+	return &renewal, nil
+}
+
+// ListOpenByProfile returns the renewals a yandaş still has to make, earliest deadline first
+func (r *documentRenewalRepository) ListOpenByProfile(profileID uuid.UUID) ([]models.DocumentRenewal, error) {
+	var renewals []models.DocumentRenewal
+	err := r.db.Where("yandas_profile_id = ? AND status IN ?", profileID, openRenewalStatuses).
+		Order("deadline ASC").
+		Find(&renewals).Error
+	return renewals, err
+}
+
+// ListPastDeadline returns requested renewals whose deadline passed before now
+func (r *documentRenewalRepository) ListPastDeadline(now time.Time, limit int) ([]models.DocumentRenewal, error) {
+	var renewals []models.DocumentRenewal
+	err := r.db.Preload("YandasProfile").
+		Where("status = 'requested' AND deadline < ?", now).
+		Order("deadline ASC").
+		Limit(limit).
+		Find(&renewals).Error
+	return renewals, err
+}
+
+// List returns renewals in a status with the yandaş, earliest deadline first
+func (r *documentRenewalRepository) List(status string, page, limit int) ([]models.DocumentRenewal, int64, error) {
+	var renewals []models.DocumentRenewal
+	var total int64
+
+	query := r.db.Model(&models.DocumentRenewal{}).Where("status = ?", status)
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Preload("YandasProfile.User").
+		Order("deadline ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&renewals).Error
+
+	return renewals, total, err
+}
+
+// StaleProfiles returns approved profiles whose document was uploaded before
+// uploadedBefore and that have neither an open renewal for it nor a waiver in force
+func (r *documentRenewalRepository) StaleProfiles(document string, uploadedBefore time.Time, limit int) ([]models.YandasProfile, error) {
+	column, ok := renewableDocuments[document]
+	if !ok {
+		return nil, fmt.Errorf("document %q does not expire", document)
+	}
+	var profiles []models.YandasProfile
+	err := r.db.Where("approval_status = 'approved' AND "+column+" < ?", uploadedBefore).
+		Where(`NOT EXISTS (SELECT 1 FROM document_renewals
+			WHERE document_renewals.yandas_profile_id = yandas_profiles.id AND document_renewals.document = ?
+			AND (document_renewals.status IN ? OR (document_renewals.status = 'waived' AND document_renewals.waived_until > ?)))`,
+			document, openRenewalStatuses, time.Now()).
+		Order(column + " ASC").
+		Limit(limit).
+		Find(&profiles).Error
+	return profiles, err
+}
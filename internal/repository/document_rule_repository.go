@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// documentRuleRepository handles the documents each category requires
+type documentRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentRuleRepository(db *gorm.DB) DocumentRuleRepository {
+	return &documentRuleRepository{db: db}
+}
+
+func (r *documentRuleRepository) ListByCategories(categoryIDs []uuid.UUID) ([]models.CategoryDocumentRule, error) {
+	var rules []models.CategoryDocumentRule
+	if len(categoryIDs) == 0 {
+		return rules, nil
+	}
+	err := r.db.Where("category_id IN ?", categoryIDs).Order("created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+// Replace swaps a category's rules for rules
+func (r *documentRuleRepository) Replace(categoryID uuid.UUID, rules []models.CategoryDocumentRule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", categoryID).Delete(&models.CategoryDocumentRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(&rules).Error
+	})
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// domainEventRepository handles the domain event outbox
+type domainEventRepository struct {
+	db *gorm.DB
+}
+
+func NewDomainEventRepository(db *gorm.DB) DomainEventRepository {
+	return &domainEventRepository{db: db}
+}
+
+func (r *domainEventRepository) Create(event *models.DomainEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *domainEventRepository) GetByID(id uuid.UUID) (*models.DomainEvent, error) {
+	var event models.DomainEvent
+	err := r.db.First(&event, "id = ?", id).Error
+	return &event, err
+}
+
+// ClaimDue leases up to limit pending events whose next attempt is due, oldest
+// first. Leased rows get their next attempt pushed out by lease so other
+// instances skip them.
+func (r *domainEventRepository) ClaimDue(limit int, lease time.Duration) ([]models.DomainEvent, error) {
+	var events []models.DomainEvent
+	err := r.db.Raw(`
+		UPDATE domain_events SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM domain_events
+			WHERE status = 'pending' AND next_attempt_at <= ?
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, time.Now().Add(lease), time.Now(), limit).
+		Scan(&events).Error
+	return events, err
+}
+
+func (r *domainEventRepository) Update(event *models.DomainEvent) error {
+	return r.db.Save(event).Error
+}
+
+// PrunePublished deletes events relayed before the given time
+func (r *domainEventRepository) PrunePublished(before time.Time) error {
+	return r.db.Where("status = 'published' AND published_at < ?", before).Delete(&models.DomainEvent{}).Error
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CurrencyTotal is a sum of order amounts in one currency, and the same sum
+// converted to the base currency
+type CurrencyTotal struct {
+	Currency   string         `json:"currency"`
+	Amount     currency.Money `json:"amount"`
+	BaseAmount currency.Money `json:"base_amount"`
+}
+
+// inBaseCurrency converts an orders money column to the base currency at the
+// rate nearest the order's completion (or creation) day, rounded to whole
+// minor units. Amounts already in the base currency, or in a currency no rate
+// was ever fetched for, count at par.
+func inBaseCurrency(column string) string {
+	return fmt.Sprintf(`ROUND(%s * COALESCE((
+		SELECT exchange_rates.rate FROM exchange_rates
+		WHERE exchange_rates.currency = orders.currency
+		ORDER BY ABS(exchange_rates.date - COALESCE(orders.completed_at, orders.created_at)::date)
+		LIMIT 1), 1))::bigint`, column)
+}
+
+// exchangeRateRepository stores the daily rates used for reporting
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+// Save stores rates, replacing those already fetched for the same currency and day
+func (r *exchangeRateRepository) Save(rates []models.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "updated_at"}),
+	}).Create(&rates).Error
+}
+
+// Latest returns the most recent rate of each currency
+func (r *exchangeRateRepository) Latest() ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	err := r.db.Raw(`SELECT DISTINCT ON (currency) * FROM exchange_rates ORDER BY currency, date DESC`).Scan(&rates).Error
+	return rates, err
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// exportBatchSize is how many rows admin exports load per query
+const exportBatchSize = 500
+
+// ExportFilter narrows admin exports; zero values are ignored
+type ExportFilter struct {
+	From   *time.Time // inclusive
+	To     *time.Time // exclusive
+	Status string
+	Role   string
+	City   string
+}
+
+// applyDateRange limits query to rows whose column falls inside the filter's range
+func (f ExportFilter) applyDateRange(query *gorm.DB, column string) *gorm.DB {
+	if f.From != nil {
+		query = query.Where(column+" >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where(column+" < ?", *f.To)
+	}
+	return query
+}
@@ -1,35 +1,37 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/models"
 	"gorm.io/gorm"
 )
 
-// FavoriteRepository handles favorite operations
-type FavoriteRepository struct {
+// favoriteRepository handles favorite operations
+type favoriteRepository struct {
 	db *gorm.DB
 }
 
-func NewFavoriteRepository(db *gorm.DB) *FavoriteRepository {
-	return &FavoriteRepository{db: db}
+func NewFavoriteRepository(db *gorm.DB) FavoriteRepository {
+	return &favoriteRepository{db: db}
 }
 
-func (r *FavoriteRepository) Create(fav *models.Favorite) error {
+func (r *favoriteRepository) Create(fav *models.Favorite) error {
 	return r.db.Create(fav).Error
 }
 
-func (r *FavoriteRepository) Delete(userID, yandasID uuid.UUID) error {
+func (r *favoriteRepository) Delete(userID, yandasID uuid.UUID) error {
 	return r.db.Where("user_id = ? AND yandas_id = ?", userID, yandasID).Delete(&models.Favorite{}).Error
 }
 
-func (r *FavoriteRepository) Exists(userID, yandasID uuid.UUID) bool {
+func (r *favoriteRepository) Exists(userID, yandasID uuid.UUID) bool {
 	var count int64
 	r.db.Model(&models.Favorite{}).Where("user_id = ? AND yandas_id = ?", userID, yandasID).Count(&count)
 	return count > 0
 }
 
-func (r *FavoriteRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.Favorite, int64, error) {
+func (r *favoriteRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.Favorite, int64, error) {
 	var favs []models.Favorite
 	var total int64
 
@@ -49,7 +51,53 @@ func (r *FavoriteRepository) ListByUser(userID uuid.UUID, page, limit int) ([]mo
 	return favs, total, err
 }
 
-func (r *FavoriteRepository) GetYandasIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+// GetUserIDsByYandas returns the users who favorited a yandaş
+func (r *favoriteRepository) GetUserIDsByYandas(yandasID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&models.Favorite{}).Where("yandas_id = ?", yandasID).Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+func (r *favoriteRepository) CreateActivity(activity *models.FavoriteActivity) error {
+	return r.db.Create(activity).Error
+}
+
+func (r *favoriteRepository) GetActivity(id uuid.UUID) (*models.FavoriteActivity, error) {
+	var activity models.FavoriteActivity
+	err := r.db.Preload("Yandas.User").First(&activity, "id = ?", id).Error
+	return &activity, err
+}
+
+// LastActivity returns the yandaş's most recent activity of a type
+func (r *favoriteRepository) LastActivity(yandasID uuid.UUID, activityType string) (*models.FavoriteActivity, error) {
+	var activity models.FavoriteActivity
+	err := r.db.Where("yandas_id = ? AND type = ?", yandasID, activityType).
+		Order("created_at DESC").First(&activity).Error
+	return &activity, err
+}
+
+// ListFeed returns activity since the given time from yandaşlar the user favorited, newest first
+func (r *favoriteRepository) ListFeed(userID uuid.UUID, since time.Time, page, limit int) ([]models.FavoriteActivity, int64, error) {
+	var activities []models.FavoriteActivity
+	var total int64
+
+	query := r.db.Model(&models.FavoriteActivity{}).
+		Where("yandas_id IN (SELECT yandas_id FROM favorites WHERE user_id = ?)", userID).
+		Where("created_at >= ?", since)
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.
+		Preload("Yandas.User").
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&activities).Error
+
+	return activities, total, err
+}
+
+func (r *favoriteRepository) GetYandasIDs(userID uuid.UUID) ([]uuid.UUID, error) {
 	var ids []uuid.UUID
 	err := r.db.Model(&models.Favorite{}).Where("user_id = ?", userID).Pluck("yandas_id", &ids).Error
 	return ids, err
@@ -0,0 +1,563 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/database"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=interfaces.go -destination=mocks/mock_repositories.go -package=mocks
+
+// UserRepository defines user data access
+type UserRepository interface {
+	Create(user *models.User) error
+	GetByID(id uuid.UUID) (*models.User, error)
+	Lock(id uuid.UUID) error
+	GetByEmail(email string) (*models.User, error)
+	GetByPhone(phone string) (*models.User, error)
+	Update(user *models.User) error
+	Delete(id uuid.UUID) error
+	HardDelete(id uuid.UUID) error
+	List(filter UserFilter, page, limit int) ([]models.User, int64, error)
+	ExistsByEmail(email string) bool
+	ExistsByPhone(phone string) bool
+	SetActiveBulk(ids []uuid.UUID, active bool) (int64, error)
+	StreamForExport(filter ExportFilter, fn func(users []models.User) error) error
+	MarkEmailUndeliverable(email, reason string) (bool, error)
+	IsEmailUndeliverable(email string) bool
+}
+
+// YandasProfileRepository defines yandaş profile data access
+type YandasProfileRepository interface {
+	Create(profile *models.YandasProfile) error
+	GetByID(id uuid.UUID) (*models.YandasProfile, error)
+	GetByUserID(userID uuid.UUID) (*models.YandasProfile, error)
+	GetPublic(id uuid.UUID) (*models.YandasProfile, error)
+	GetPublicBySlug(slug string) (*models.YandasProfile, error)
+	SlugExists(slug string) (bool, error)
+	Update(profile *models.YandasProfile) error
+	ListPublic(page, limit int, categorySlug, city string, byQuality bool) ([]models.YandasListItem, int64, error)
+	ListPendingApplications(page, limit int) ([]models.YandasProfile, int64, error)
+	ListAllApplications(page, limit int, status string) ([]models.YandasProfile, int64, error)
+	UpdateAvailability(id uuid.UUID, available bool) error
+	SetVacation(id uuid.UUID, from, until time.Time, policy string) error
+	StartVacation(id uuid.UUID, at time.Time) (bool, error)
+	ClearVacation(id uuid.UUID) error
+	ListVacationsDue(now time.Time, limit int) ([]models.YandasProfile, error)
+	UpdateLocation(id uuid.UUID, lat, lng float64) error
+	UpdateRating(id uuid.UUID) error
+	GetByKYCReference(provider, reference string) (*models.YandasProfile, error)
+	UpdateKYC(profile *models.YandasProfile) error
+	Search(query string, page, limit int) ([]models.YandasListItem, int64, error)
+	StreamApplicationsForExport(filter ExportFilter, fn func(profiles []models.YandasProfile) error) error
+}
+
+// CategoryRepository defines category data access
+type CategoryRepository interface {
+	List() ([]models.Category, error)
+	ListAll() ([]models.Category, error)
+	GetByID(id uuid.UUID) (*models.Category, error)
+	GetBySlug(slug string) (*models.Category, error)
+	Create(category *models.Category) error
+	Update(category *models.Category) error
+	Delete(id uuid.UUID) error
+}
+
+// ServiceRepository defines service data access
+type ServiceRepository interface {
+	Create(service *models.YandasService) error
+	GetByID(id uuid.UUID) (*models.YandasService, error)
+	GetByYandasID(yandasID uuid.UUID) ([]models.YandasService, error)
+	ListByYandas(yandasID uuid.UUID, includeArchived bool) ([]models.YandasService, error)
+	ListByStatus(status string, page, limit int) ([]models.YandasService, int64, error)
+	Update(service *models.YandasService) error
+	Delete(id uuid.UUID) error
+	CountActiveByYandasID(yandasID uuid.UUID) int64
+	ListPublic(filter ServiceFilter, page, limit int) ([]models.ServiceListItem, int64, error)
+	CreateOption(option *models.ServiceOption) error
+	GetOption(id uuid.UUID) (*models.ServiceOption, error)
+	UpdateOption(option *models.ServiceOption) error
+	ReplacePriceTiers(serviceID uuid.UUID, tiers []models.ServicePriceTier) error
+}
+
+// OrderRepository defines order data access
+type OrderRepository interface {
+	Create(order *models.Order) error
+	GetByID(id uuid.UUID) (*models.Order, error)
+	GetByIDForUpdate(id uuid.UUID) (*models.Order, error)
+	GetByOrderNumber(orderNumber string) (*models.Order, error)
+	Update(order *models.Order) error
+	ListByCustomer(customerID uuid.UUID, page, limit int, status string) ([]models.Order, int64, error)
+	ListByYandas(yandasID uuid.UUID, page, limit int, status string) ([]models.Order, int64, error)
+	ListAll(filter OrderFilter, page, limit int) ([]models.Order, int64, error)
+	SumRevenue(filter OrderFilter) ([]CurrencyTotal, error)
+	ListScheduledByYandas(yandasID uuid.UUID, from, to time.Time, statuses []string) ([]models.Order, error)
+	UpdateStatus(id uuid.UUID, status string) error
+	UpdateETA(id uuid.UUID, etaAt time.Time, distanceKm float64) error
+	SetConversation(id, conversationID uuid.UUID) error
+	MarkEnRoute(id uuid.UUID) (bool, error)
+	ListAwaitingConfirmation(requestedBefore time.Time, limit int) ([]models.Order, error)
+	CountActiveByService(serviceID uuid.UUID) (int64, error)
+	AddLineItem(item *models.OrderLineItem) error
+	GetStats(yandasID uuid.UUID) (map[string]interface{}, error)
+	StreamForExport(filter ExportFilter, fn func(orders []models.Order) error) error
+	AccountingSummary(from, to time.Time) ([]AccountingRow, error)
+}
+
+// OrderAdjustmentRepository defines order amount adjustment data access
+type OrderAdjustmentRepository interface {
+	Create(adjustment *models.OrderAdjustment) error
+	GetByID(id uuid.UUID) (*models.OrderAdjustment, error)
+	ListByOrder(orderID uuid.UUID) ([]models.OrderAdjustment, error)
+	WithdrawPending(orderID uuid.UUID) error
+	Resolve(id uuid.UUID, status string) (bool, error)
+}
+
+// OrderHistoryRepository defines order status history data access
+type OrderHistoryRepository interface {
+	Record(entry *models.OrderStatusHistory) error
+	ListByOrder(orderID uuid.UUID) ([]models.OrderStatusHistory, error)
+	LastEvent(orderID uuid.UUID, event string) (*models.OrderStatusHistory, error)
+}
+
+// JobRequestRepository defines open job request and bid data access
+type JobRequestRepository interface {
+	Create(request *models.JobRequest) error
+	GetByID(id uuid.UUID) (*models.JobRequest, error)
+	Update(request *models.JobRequest) error
+	ListByCustomer(customerID uuid.UUID, page, limit int, status string) ([]models.JobRequest, int64, error)
+	ListOpenForYandas(yandasID uuid.UUID, now time.Time, page, limit int) ([]models.JobRequest, int64, error)
+	MatchingYandasUserIDs(request *models.JobRequest, limit int) ([]uuid.UUID, error)
+	Close(id uuid.UUID, status string, acceptedBidID, orderID *uuid.UUID) (bool, error)
+	ExpireOpen(now time.Time) ([]models.JobRequest, error)
+	CreateBid(bid *models.Bid) error
+	GetBid(id uuid.UUID) (*models.Bid, error)
+	GetBidByYandas(requestID, yandasID uuid.UUID) (*models.Bid, error)
+	UpdateBid(bid *models.Bid) error
+	ListBids(requestID uuid.UUID) ([]models.Bid, error)
+	ListBidsByYandas(yandasID uuid.UUID, page, limit int) ([]models.Bid, int64, error)
+	RejectPendingBids(requestID uuid.UUID, keepID *uuid.UUID) ([]uuid.UUID, error)
+}
+
+// AssignmentRepository defines urgent order auto-assignment data access
+type AssignmentRepository interface {
+	Create(assignment *models.AutoAssignment) error
+	GetByID(id uuid.UUID) (*models.AutoAssignment, error)
+	Transition(assignment *models.AutoAssignment, expectAttempts int) (bool, error)
+	ListExpiredOffers(now time.Time, limit int) ([]models.AutoAssignment, error)
+	NearestCandidates(categoryID, customerID uuid.UUID, lat, lng, radiusKm float64, exclude []string, limit int) ([]AssignmentCandidate, error)
+}
+
+// RecurringOrderRepository defines recurring order data access
+type RecurringOrderRepository interface {
+	Create(recurring *models.RecurringOrder) error
+	GetByID(id uuid.UUID) (*models.RecurringOrder, error)
+	ListByCustomer(customerID uuid.UUID, page, limit int) ([]models.RecurringOrder, int64, error)
+	Update(recurring *models.RecurringOrder) error
+	ListDue(before time.Time, limit int) ([]models.RecurringOrder, error)
+	ClaimOccurrence(id uuid.UUID, scheduledFor, next time.Time) (bool, error)
+}
+
+// ReviewRepository defines review data access
+type ReviewRepository interface {
+	Create(review *models.Review) error
+	GetByOrderID(orderID uuid.UUID) (*models.Review, error)
+	GetByID(id uuid.UUID) (*models.Review, error)
+	ListByReviewee(revieweeID uuid.UUID, page, limit int, sort string) ([]models.Review, int64, error)
+	ListAll(filter ReviewFilter, page, limit int) ([]models.Review, int64, error)
+	SetHidden(id uuid.UUID, hidden bool, by uuid.UUID) error
+	Delete(id uuid.UUID) error
+	SetReply(id uuid.UUID, reply string) (bool, error)
+	AddHelpfulVote(reviewID, userID uuid.UUID) error
+	RemoveHelpfulVote(reviewID, userID uuid.UUID) error
+	ExistsByOrderID(orderID uuid.UUID) bool
+	Stats(revieweeID uuid.UUID, trendFrom time.Time) (*ReviewStats, error)
+}
+
+// ConversationRepository defines conversation data access
+type ConversationRepository interface {
+	Create(conv *models.Conversation) error
+	GetByID(id uuid.UUID) (*models.Conversation, error)
+	GetByParticipants(customerID, yandasID uuid.UUID) (*models.Conversation, error)
+	FindBetween(userA, userB uuid.UUID) (*models.Conversation, error)
+	GetOrCreate(customerID, yandasID uuid.UUID, orderID *uuid.UUID) (*models.Conversation, error)
+	LinkOrder(id, orderID uuid.UUID) error
+	ListByUser(userID uuid.UUID, page, limit int) ([]models.Conversation, int64, error)
+	Search(userID uuid.UUID, query string, page, limit int) ([]ConversationMatch, int64, error)
+	GetFlags(conv *models.Conversation) (*ConversationFlags, error)
+	UpdateLastMessage(id uuid.UUID) error
+}
+
+// MessageRepository defines message data access
+type MessageRepository interface {
+	Create(msg *models.Message) error
+	GetByConversation(conversationID uuid.UUID, page, limit int) ([]models.Message, int64, error)
+	ListUpTo(conversationID uuid.UUID, createdAt time.Time, id uuid.UUID, limit int) ([]models.Message, error)
+	StreamByConversation(conversationID uuid.UUID, fn func(messages []models.Message) error) error
+	MarkAsRead(conversationID, userID uuid.UUID) error
+	GetUnreadCount(userID uuid.UUID) (int64, error)
+	CountBetween(conversationID uuid.UUID, from, to time.Time) (int64, *time.Time, error)
+	UpdateLiveLocation(id, conversationID, senderID uuid.UUID, latitude, longitude float64, now time.Time) (bool, error)
+	EndLiveLocation(id, conversationID, senderID uuid.UUID, now time.Time) (bool, error)
+	ExpireLiveLocations(now time.Time) ([]models.Message, error)
+	EncryptPlaintext(limit int) (int, error)
+	ContentSearchable() bool
+}
+
+// SubscriptionRepository defines subscription data access
+type SubscriptionRepository interface {
+	Create(sub *models.Subscription) error
+	GetByUserID(userID uuid.UUID) (*models.Subscription, error)
+	GetByProviderID(providerID string) (*models.Subscription, error)
+	Update(sub *models.Subscription) error
+	Cancel(id uuid.UUID) error
+}
+
+// DeviceTokenRepository defines device token data access
+type DeviceTokenRepository interface {
+	Create(token *models.DeviceToken) error
+	GetByUserID(userID uuid.UUID) ([]models.DeviceToken, error)
+	Deactivate(token string) error
+	DeactivateAllForUser(userID uuid.UUID) error
+	DeactivateForSession(sessionID uuid.UUID) error
+}
+
+// SessionRepository defines data access for signed-in devices
+type SessionRepository interface {
+	Create(session *models.Session) error
+	GetByID(id uuid.UUID) (*models.Session, error)
+	ListActiveForUser(userID uuid.UUID) ([]models.Session, error)
+	Rotate(id uuid.UUID, oldHash, newHash, ip string, expiresAt time.Time) (bool, error)
+	Revoke(id uuid.UUID) error
+	RevokeAllForUser(userID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// ContactChangeRepository defines data access for pending email/phone changes
+type ContactChangeRepository interface {
+	Save(change *models.ContactChange) error
+	Get(userID uuid.UUID, channel string) (*models.ContactChange, error)
+	IncrementAttempts(id uuid.UUID) error
+	Delete(id uuid.UUID) error
+}
+
+// AuditLogRepository defines audit log data access
+type AuditLogRepository interface {
+	Create(log *models.AuditLog) error
+	List(page, limit int, adminID *uuid.UUID, action string) ([]models.AuditLog, int64, error)
+}
+
+// NotificationRepository defines notification data access
+type NotificationRepository interface {
+	Create(notif *models.Notification) error
+	ListByUser(userID uuid.UUID, page, limit int) ([]models.Notification, int64, error)
+	MarkAsRead(id uuid.UUID) error
+	MarkAllAsRead(userID uuid.UUID) error
+	GetUnreadCount(userID uuid.UUID) (int64, error)
+	DigestRecipients(since, inactiveSince time.Time) ([]uuid.UUID, error)
+	ListUnreadSince(userID uuid.UUID, since time.Time, limit int) ([]models.Notification, error)
+}
+
+// SupportRepository defines support data access
+type SupportRepository interface {
+	ListTickets(page, limit int, status, priority string) ([]models.SupportTicket, int64, error)
+	GetTicket(id uuid.UUID) (*models.SupportTicket, error)
+	GetTicketForOwner(id uuid.UUID) (*models.SupportTicket, error)
+	CreateTicket(ticket *models.SupportTicket) error
+	UpdateTicket(ticket *models.SupportTicket) error
+	CreateMessage(message *models.SupportMessage) error
+	GetTicketMessages(ticketID uuid.UUID) ([]models.SupportMessage, error)
+	GetStats() (map[string]int64, error)
+	ListByUser(userID uuid.UUID, page, limit int) ([]models.SupportTicket, int64, error)
+	ListOverdueTickets(now time.Time, limit int) ([]models.SupportTicket, error)
+	EscalateTicket(ticket *models.SupportTicket, fromLevel int) (bool, error)
+	ListCannedResponses(category string) ([]models.CannedResponse, error)
+	GetCannedResponse(id uuid.UUID) (*models.CannedResponse, error)
+	CreateCannedResponse(response *models.CannedResponse) error
+	UpdateCannedResponse(response *models.CannedResponse) error
+	DeleteCannedResponse(id uuid.UUID) error
+	IncrementCannedResponseUsage(id uuid.UUID) error
+}
+
+// AnnouncementRepository defines announcement data access
+type AnnouncementRepository interface {
+	Create(announcement *models.Announcement) error
+	GetByID(id uuid.UUID) (*models.Announcement, error)
+	Update(announcement *models.Announcement) error
+	Delete(id uuid.UUID) error
+	List(page, limit int) ([]models.Announcement, int64, error)
+	ListActive(userID uuid.UUID, audiences []string, now time.Time) ([]models.Announcement, error)
+	Dismiss(announcementID, userID uuid.UUID) error
+}
+
+// DocumentRuleRepository defines category document rule data access
+type DocumentRuleRepository interface {
+	ListByCategories(categoryIDs []uuid.UUID) ([]models.CategoryDocumentRule, error)
+	Replace(categoryID uuid.UUID, rules []models.CategoryDocumentRule) error
+}
+
+// ApplicationFormRepository defines application question and answer data access
+type ApplicationFormRepository interface {
+	ListQuestions(categoryIDs []uuid.UUID, activeOnly bool) ([]models.ApplicationQuestion, error)
+	GetQuestion(id uuid.UUID) (*models.ApplicationQuestion, error)
+	CreateQuestion(question *models.ApplicationQuestion) error
+	UpdateQuestion(question *models.ApplicationQuestion) error
+	DeleteQuestion(id uuid.UUID) error
+	CreateAnswers(answers []models.ApplicationAnswer) error
+	ListAnswers(profileID uuid.UUID) ([]models.ApplicationAnswer, error)
+}
+
+// LegalDocumentRepository defines legal document data access
+type LegalDocumentRepository interface {
+	Publish(document *models.LegalDocument) error
+	GetByID(id uuid.UUID) (*models.LegalDocument, error)
+	List(kind, locale string, page, limit int) ([]models.LegalDocument, int64, error)
+	Latest(kind, locale string) (*models.LegalDocument, error)
+	Kinds(locale string) ([]string, error)
+	Required(locale string) ([]models.LegalDocument, error)
+}
+
+// ConsentRepository defines user consent data access
+type ConsentRepository interface {
+	AcceptedIDs(userID uuid.UUID, documentIDs []uuid.UUID) ([]uuid.UUID, error)
+	Accept(consents []models.UserConsent) error
+	ListByUser(userID uuid.UUID) ([]models.UserConsent, error)
+}
+
+// FavoriteRepository defines favorite data access
+type FavoriteRepository interface {
+	Create(fav *models.Favorite) error
+	Delete(userID, yandasID uuid.UUID) error
+	Exists(userID, yandasID uuid.UUID) bool
+	ListByUser(userID uuid.UUID, page, limit int) ([]models.Favorite, int64, error)
+	GetYandasIDs(userID uuid.UUID) ([]uuid.UUID, error)
+	GetUserIDsByYandas(yandasID uuid.UUID) ([]uuid.UUID, error)
+	CreateActivity(activity *models.FavoriteActivity) error
+	GetActivity(id uuid.UUID) (*models.FavoriteActivity, error)
+	LastActivity(yandasID uuid.UUID, activityType string) (*models.FavoriteActivity, error)
+	ListFeed(userID uuid.UUID, since time.Time, page, limit int) ([]models.FavoriteActivity, int64, error)
+}
+
+// NotificationPreferenceRepository defines notification preference data access
+type NotificationPreferenceRepository interface {
+	ListByUser(userID uuid.UUID) ([]models.NotificationPreference, error)
+	GetByUserAndType(userID uuid.UUID, notifType string) (*models.NotificationPreference, error)
+	Upsert(pref *models.NotificationPreference) error
+}
+
+// CallLogRepository defines call log data access
+type CallLogRepository interface {
+	GetByID(id uuid.UUID) (*models.CallLog, error)
+	ListByUser(userID uuid.UUID, page, limit int, filter string) ([]models.CallLog, int64, error)
+	MarkMissed(id uuid.UUID) (bool, error)
+	ListRingExpired(now time.Time, limit int) ([]uuid.UUID, error)
+	SetFeedback(id uuid.UUID, party string, feedback CallFeedback) (bool, error)
+	QualityReport(from, to time.Time) ([]CallQualityRow, error)
+	ListActive(page, limit int) ([]models.CallLog, int64, error)
+	AddParticipant(callID, userID uuid.UUID, role string) (*models.CallParticipant, error)
+	GetParticipant(callID, userID uuid.UUID) (*models.CallParticipant, error)
+	ListParticipants(callID uuid.UUID) ([]models.CallParticipant, error)
+	LeaveParticipant(callID, userID uuid.UUID) (bool, error)
+	SetPushResult(id uuid.UUID, status string, pushErr *string) error
+	MarkDelivered(id, calleeID uuid.UUID, via string) (bool, error)
+}
+
+// AddressRepository defines address data access
+type AddressRepository interface {
+	Create(address *models.Address) error
+	GetByID(id uuid.UUID) (*models.Address, error)
+	ListByUser(userID uuid.UUID) ([]models.Address, error)
+	CountByUser(userID uuid.UUID) int64
+	Update(address *models.Address) error
+	Delete(id uuid.UUID) error
+	SetDefault(userID, addressID uuid.UUID) error
+}
+
+// RoleRepository defines role and permission data access
+type RoleRepository interface {
+	List() ([]models.Role, error)
+	UpsertSystemRole(role *models.Role) error
+	GetByNames(names []string) ([]models.Role, error)
+	ListByUser(userID uuid.UUID) ([]models.Role, error)
+	ReplaceUserRoles(userID uuid.UUID, roleIDs []uuid.UUID, grantedBy uuid.UUID) error
+	UserHasPermission(userID uuid.UUID, permission string) bool
+}
+
+// DocumentScreeningRepository defines document screening data access
+type DocumentScreeningRepository interface {
+	GetByProfileID(profileID uuid.UUID) (*models.DocumentScreening, error)
+	Upsert(screening *models.DocumentScreening) error
+}
+
+// DocumentRenewalRepository defines stale document renewal data access
+type DocumentRenewalRepository interface {
+	Create(renewal *models.DocumentRenewal) error
+	GetByID(id uuid.UUID) (*models.DocumentRenewal, error)
+	Update(renewal *models.DocumentRenewal) error
+	GetOpen(profileID uuid.UUID, document string) (*models.DocumentRenewal, error)
+	ListOpenByProfile(profileID uuid.UUID) ([]models.DocumentRenewal, error)
+	ListPastDeadline(now time.Time, limit int) ([]models.DocumentRenewal, error)
+	List(status string, page, limit int) ([]models.DocumentRenewal, int64, error)
+	StaleProfiles(document string, uploadedBefore time.Time, limit int) ([]models.YandasProfile, error)
+}
+
+// ReceiptRepository defines order receipt data access
+type ReceiptRepository interface {
+	GetByOrderID(orderID uuid.UUID) (*models.Receipt, error)
+	Create(receipt *models.Receipt) error
+	Update(receipt *models.Receipt) error
+}
+
+// PayoutRepository defines the earnings ledger and payout data access
+type PayoutRepository interface {
+	CreateEntry(entry *models.PayoutEntry) error
+	ListEntries(yandasID uuid.UUID, page, limit int) ([]models.PayoutEntry, int64, error)
+	UnpaidBalances(yandasID *uuid.UUID) ([]PayoutBalance, error)
+	PaidTotal(yandasID uuid.UUID, currencyCode string) (currency.Money, error)
+	AttachUnpaidEntries(payoutID, yandasID uuid.UUID, currencyCode string) (currency.Money, error)
+	Create(payout *models.Payout) error
+	GetByID(id uuid.UUID) (*models.Payout, error)
+	Update(payout *models.Payout) error
+	List(page, limit int, status string, yandasID *uuid.UUID) ([]models.Payout, int64, error)
+}
+
+// MonitoringRepository defines metric counters and alert rule data access
+type MonitoringRepository interface {
+	IncrementMetric(metric string, bucket time.Time) error
+	SumMetric(metric string, since time.Time) (int64, error)
+	PruneMetrics(before time.Time) error
+	ListRules() ([]models.AlertRule, error)
+	ListActiveRules() ([]models.AlertRule, error)
+	CountRules() int64
+	GetRule(id uuid.UUID) (*models.AlertRule, error)
+	CreateRule(rule *models.AlertRule) error
+	UpdateRule(rule *models.AlertRule) error
+	DeleteRule(id uuid.UUID) error
+	CreateEvent(event *models.AlertEvent) error
+	ListEvents(page, limit int, ruleID *uuid.UUID) ([]models.AlertEvent, int64, error)
+}
+
+// WebhookRepository defines webhook endpoint and delivery data access
+type WebhookRepository interface {
+	CreateEndpoint(endpoint *models.WebhookEndpoint) error
+	GetEndpoint(id uuid.UUID) (*models.WebhookEndpoint, error)
+	UpdateEndpoint(endpoint *models.WebhookEndpoint) error
+	DeleteEndpoint(id uuid.UUID) error
+	ListEndpoints() ([]models.WebhookEndpoint, error)
+	ListActiveEndpointsForEvent(event string) ([]models.WebhookEndpoint, error)
+	CreateDelivery(delivery *models.WebhookDelivery) error
+	GetDelivery(id uuid.UUID) (*models.WebhookDelivery, error)
+	UpdateDelivery(delivery *models.WebhookDelivery) error
+	ListDeliveries(endpointID uuid.UUID, page, limit int, status string) ([]models.WebhookDelivery, int64, error)
+	ClaimDueDeliveries(limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+}
+
+// SMSDeliveryRepository defines SMS delivery log data access
+type SMSDeliveryRepository interface {
+	Create(delivery *models.SMSDelivery) error
+	UpdateStatus(provider, messageID, status string, errorCode, errMsg *string) (bool, error)
+	List(page, limit int, phone, status string) ([]models.SMSDelivery, int64, error)
+}
+
+// AnalyticsRepository defines yandaş profile view, daily funnel, customer cohort and product event data access
+type AnalyticsRepository interface {
+	RecordProfileView(yandasID uuid.UUID) error
+	RollupDay(from, to time.Time) error
+	ListDaily(yandasID uuid.UUID, from, to time.Time) ([]models.YandasDailyStat, error)
+	PruneProfileViews(before time.Time) (int64, error)
+	Cohorts(from, to, asOf time.Time) ([]CohortRow, error)
+	RecordEvents(events []models.AnalyticsEvent) error
+	Funnel(steps []string, from, to time.Time, platform string) ([]FunnelStep, error)
+	PruneEvents(before time.Time) (int64, error)
+}
+
+// ExchangeRateRepository defines exchange rate data access
+type ExchangeRateRepository interface {
+	Save(rates []models.ExchangeRate) error
+	Latest() ([]models.ExchangeRate, error)
+}
+
+// QualityRepository defines yandaş quality score data access
+type QualityRepository interface {
+	Metrics(since time.Time) ([]models.YandasQualityScore, error)
+	Replace(rows []models.YandasQualityScore) error
+	Leaderboard(filter QualityFilter, page, limit int) ([]LeaderboardEntry, int64, error)
+}
+
+// RetentionRepository defines the data access of the retention jobs
+type RetentionRepository interface {
+	SoftDeletedUsers(before time.Time) ([]uuid.UUID, error)
+	CountMessages(before time.Time) (int64, error)
+	ArchiveMessages(before time.Time, limit int) (int64, error)
+	CountAuditLogs(before time.Time) (int64, error)
+	DeleteAuditLogs(before time.Time, limit int) (int64, error)
+	CountCallLogs(before time.Time) (int64, error)
+	DeleteCallLogs(before time.Time, limit int) (int64, error)
+}
+
+// BackupRepository defines database backup record data access
+type BackupRepository interface {
+	Create(backup *models.DatabaseBackup) error
+	Update(backup *models.DatabaseBackup) error
+	LatestSucceeded() (*models.DatabaseBackup, error)
+	GetByFileName(name string) (*models.DatabaseBackup, error)
+	List(limit int) ([]models.DatabaseBackup, error)
+}
+
+// ActivityRepository defines admin activity feed data access
+type ActivityRepository interface {
+	Create(activity *models.AdminActivity) error
+	List(activityType string, page, limit int) ([]models.AdminActivity, int64, error)
+	Prune(before time.Time) (int64, error)
+}
+
+// DemandRepository defines per-city category demand data access
+type DemandRepository interface {
+	Counts() ([]models.CategoryDemand, error)
+	Replace(rows []models.CategoryDemand) error
+	ListByCity(city string) ([]models.CategoryDemand, error)
+	CategoryIDsAtLevel(city, level string) ([]uuid.UUID, error)
+}
+
+// SitemapRepository defines public page listing for search engines
+type SitemapRepository interface {
+	List(page, limit int) ([]SitemapEntry, int64, error)
+}
+
+// DomainEventRepository defines domain event outbox data access
+type DomainEventRepository interface {
+	Create(event *models.DomainEvent) error
+	GetByID(id uuid.UUID) (*models.DomainEvent, error)
+	ClaimDue(limit int, lease time.Duration) ([]models.DomainEvent, error)
+	Update(event *models.DomainEvent) error
+	PrunePublished(before time.Time) error
+}
+
+// OutboxRepository defines WebSocket and push outbox data access
+type OutboxRepository interface {
+	Create(message *models.OutboxMessage) error
+	ClaimDue(limit int, lease time.Duration) ([]models.OutboxMessage, error)
+	Update(message *models.OutboxMessage) error
+	PruneDelivered(before time.Time) error
+}
+
+// SystemRepository defines database health checks
+type SystemRepository interface {
+	Ping(ctx context.Context) error
+	MigrationStatus() ([]database.MigrationStatus, error)
+}
+
+// SettingRepository defines runtime setting override data access
+type SettingRepository interface {
+	List() ([]models.Setting, error)
+	Upsert(setting *models.Setting) error
+	Delete(key string) error
+}
+
+// UnitOfWork runs a function against repositories bound to a single database transaction
+type UnitOfWork interface {
+	Do(fn func(tx *Repositories) error) error
+}
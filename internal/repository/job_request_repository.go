@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type jobRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRequestRepository(db *gorm.DB) JobRequestRepository {
+	return &jobRequestRepository{db: db}
+}
+
+func (r *jobRequestRepository) Create(request *models.JobRequest) error {
+	return r.db.Create(request).Error
+}
+
+func (r *jobRequestRepository) GetByID(id uuid.UUID) (*models.JobRequest, error) {
+	var request models.JobRequest
+	err := r.db.Preload("Category").First(&request, "id = ?", id).Error
+	return &request, err
+}
+
+func (r *jobRequestRepository) Update(request *models.JobRequest) error {
+	return r.db.Omit("Customer", "Category", "Bids").Save(request).Error
+}
+
+func (r *jobRequestRepository) ListByCustomer(customerID uuid.UUID, page, limit int, status string) ([]models.JobRequest, int64, error) {
+	var requests []models.JobRequest
+	var total int64
+
+	query := r.db.Model(&models.JobRequest{}).Where("customer_id = ?", customerID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.
+		Select("job_requests.*, (SELECT COUNT(*) FROM bids WHERE bids.job_request_id = job_requests.id AND bids.status = 'pending') AS bid_count").
+		Preload("Category").
+		Order("created_at DESC").Offset(offset).Limit(limit).
+		Find(&requests).Error
+	return requests, total, err
+}
+
+// ListOpenForYandas returns open, unexpired requests in the yandaş's service
+// cities for categories they offer an active service in, newest first
+func (r *jobRequestRepository) ListOpenForYandas(yandasID uuid.UUID, now time.Time, page, limit int) ([]models.JobRequest, int64, error) {
+	var requests []models.JobRequest
+	var total int64
+
+	query := r.db.Model(&models.JobRequest{}).
+		Where("job_requests.status = ? AND job_requests.expires_at > ?", "open", now).
+		Where(`EXISTS (
+			SELECT 1 FROM yandas_profiles p JOIN yandas_services s ON s.yandas_id = p.id AND s.is_active AND s.status = 'active'
+			WHERE p.id = ? AND s.category_id = job_requests.category_id AND job_requests.city = ANY(p.service_cities))`, yandasID)
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.
+		Select("job_requests.*, (SELECT COUNT(*) FROM bids WHERE bids.job_request_id = job_requests.id AND bids.status = 'pending') AS bid_count").
+		Preload("Category").
+		Order("job_requests.created_at DESC").Offset(offset).Limit(limit).
+		Find(&requests).Error
+	return requests, total, err
+}
+
+// MatchingYandasUserIDs returns the users behind approved, available yandaşlar
+// serving the request's city with an active service in its category
+func (r *jobRequestRepository) MatchingYandasUserIDs(request *models.JobRequest, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&models.YandasProfile{}).
+		Where("approval_status = ? AND is_available = ?", "approved", true).
+		Where("? = ANY(service_cities)", request.City).
+		Where("EXISTS (SELECT 1 FROM yandas_services s WHERE s.yandas_id = yandas_profiles.id AND s.category_id = ? AND s.is_active AND s.status = 'active')", request.CategoryID).
+		Where("user_id <> ?", request.CustomerID).
+		Order("rating_avg DESC").
+		Limit(limit).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// Close moves an open request to status and reports whether it was still open,
+// so only one of two racing accepts or cancels wins
+func (r *jobRequestRepository) Close(id uuid.UUID, status string, acceptedBidID, orderID *uuid.UUID) (bool, error) {
+	result := r.db.Model(&models.JobRequest{}).
+		Where("id = ? AND status = ?", id, "open").
+		Updates(map[string]interface{}{
+			"status":          status,
+			"accepted_bid_id": acceptedBidID,
+			"order_id":        orderID,
+			"updated_at":      time.Now(),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// ExpireOpen marks open requests past their expiry as expired and returns them
+func (r *jobRequestRepository) ExpireOpen(now time.Time) ([]models.JobRequest, error) {
+	var requests []models.JobRequest
+	err := r.db.Raw(`
+		UPDATE job_requests SET status = 'expired', updated_at = ?
+		WHERE status = 'open' AND expires_at <= ?
+		RETURNING *`, now, now).
+		Scan(&requests).Error
+	return requests, err
+}
+
+func (r *jobRequestRepository) CreateBid(bid *models.Bid) error {
+	return r.db.Create(bid).Error
+}
+
+func (r *jobRequestRepository) GetBid(id uuid.UUID) (*models.Bid, error) {
+	var bid models.Bid
+	err := r.db.Preload("Yandas.User").Preload("Service").First(&bid, "id = ?", id).Error
+	return &bid, err
+}
+
+func (r *jobRequestRepository) GetBidByYandas(requestID, yandasID uuid.UUID) (*models.Bid, error) {
+	var bid models.Bid
+	err := r.db.Where("job_request_id = ? AND yandas_id = ?", requestID, yandasID).First(&bid).Error
+	return &bid, err
+}
+
+func (r *jobRequestRepository) UpdateBid(bid *models.Bid) error {
+	return r.db.Omit("JobRequest", "Yandas", "Service").Save(bid).Error
+}
+
+// ListBids returns a request's bids, cheapest first
+func (r *jobRequestRepository) ListBids(requestID uuid.UUID) ([]models.Bid, error) {
+	var bids []models.Bid
+	err := r.db.
+		Preload("Yandas.User").
+		Preload("Service").
+		Where("job_request_id = ?", requestID).
+		Order("CASE status WHEN 'accepted' THEN 0 WHEN 'pending' THEN 1 ELSE 2 END").
+		Order("amount ASC").
+		Find(&bids).Error
+	return bids, err
+}
+
+func (r *jobRequestRepository) ListBidsByYandas(yandasID uuid.UUID, page, limit int) ([]models.Bid, int64, error) {
+	var bids []models.Bid
+	var total int64
+
+	query := r.db.Model(&models.Bid{}).Where("yandas_id = ?", yandasID)
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.
+		Preload("JobRequest.Category").
+		Preload("Service").
+		Order("created_at DESC").Offset(offset).Limit(limit).
+		Find(&bids).Error
+	return bids, total, err
+}
+
+// RejectPendingBids rejects the request's pending bids other than keepID and
+// returns the users behind the rejected bids
+func (r *jobRequestRepository) RejectPendingBids(requestID uuid.UUID, keepID *uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	query := `
+		UPDATE bids SET status = 'rejected', updated_at = ?
+		WHERE job_request_id = ? AND status = 'pending'`
+	args := []interface{}{time.Now(), requestID}
+	if keepID != nil {
+		query += " AND id <> ?"
+		args = append(args, *keepID)
+	}
+	err := r.db.Raw(query+" RETURNING (SELECT user_id FROM yandas_profiles WHERE yandas_profiles.id = bids.yandas_id)", args...).
+		Scan(&userIDs).Error
+	return userIDs, err
+}
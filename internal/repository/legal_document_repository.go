@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type legalDocumentRepository struct {
+	db *gorm.DB
+}
+
+func NewLegalDocumentRepository(db *gorm.DB) LegalDocumentRepository {
+	return &legalDocumentRepository{db: db}
+}
+
+// Publish stores document as the next version of its kind and locale. Two
+// publishes racing each other fail on the unique version index.
+func (r *legalDocumentRepository) Publish(document *models.LegalDocument) error {
+	return r.db.Raw(`INSERT INTO legal_documents (kind, locale, version, title, content, requires_consent, published_by, published_at)
+		SELECT ?, ?, COALESCE(MAX(version), 0) + 1, ?, ?, ?, ?, NOW() FROM legal_documents WHERE kind = ? AND locale = ?
+		RETURNING *`,
+		document.Kind, document.Locale, document.Title, document.Content, document.RequiresConsent, document.PublishedBy,
+		document.Kind, document.Locale).
+		Scan(document).Error
+}
+
+func (r *legalDocumentRepository) GetByID(id uuid.UUID) (*models.LegalDocument, error) {
+	var document models.LegalDocument
+	err := r.db.First(&document, "id = ?", id).Error
+	return &document, err
+}
+
+// List returns published versions, newest first, filtered by kind and locale
+// when given
+func (r *legalDocumentRepository) List(kind, locale string, page, limit int) ([]models.LegalDocument, int64, error) {
+	var documents []models.LegalDocument
+	var total int64
+
+	query := r.db.Model(&models.LegalDocument{})
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if locale != "" {
+		query = query.Where("locale = ?", locale)
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Order("published_at DESC").Offset(offset).Limit(limit).Find(&documents).Error
+	return documents, total, err
+}
+
+// Latest returns the newest version of a kind in a locale
+func (r *legalDocumentRepository) Latest(kind, locale string) (*models.LegalDocument, error) {
+	var document models.LegalDocument
+	err := r.db.Where("kind = ? AND locale = ?", kind, locale).Order("version DESC").First(&document).Error
+	return &document, err
+}
+
+// Kinds returns the kinds published in a locale
+func (r *legalDocumentRepository) Kinds(locale string) ([]string, error) {
+	var kinds []string
+	err := r.db.Model(&models.LegalDocument{}).Where("locale = ?", locale).Distinct().Pluck("kind", &kinds).Error
+	return kinds, err
+}
+
+// Required returns, for each kind, the newest version in locale that requires
+// consent
+func (r *legalDocumentRepository) Required(locale string) ([]models.LegalDocument, error) {
+	var documents []models.LegalDocument
+	err := r.db.Raw(`SELECT DISTINCT ON (kind) * FROM legal_documents
+		WHERE locale = ? AND requires_consent
+		ORDER BY kind, version DESC`, locale).
+		Scan(&documents).Error
+	return documents, err
+}
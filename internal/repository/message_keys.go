@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/envelope"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// ErrMessageEncryptionOff is returned when encrypted messages are read, or
+// plaintext ones are to be encrypted, without a master key configured
+var ErrMessageEncryptionOff = errors.New("message encryption is not configured")
+
+// maxCachedDataKeys bounds how many unwrapped data keys stay in memory
+const maxCachedDataKeys = 10000
+
+// MessageKeys encrypts message content at rest. Every conversation gets its
+// own data key, created with its first encrypted message and stored on the
+// conversation wrapped by the master key. Unwrapped keys are cached, so the
+// master key (possibly a KMS call) is only needed once per conversation.
+type MessageKeys struct {
+	master envelope.MasterKey
+
+	mu    sync.Mutex
+	cache map[uuid.UUID][]byte
+}
+
+// NewMessageKeys returns message encryption under the master key, or nil,
+// which stores messages in plaintext, when master is nil
+func NewMessageKeys(master envelope.MasterKey) *MessageKeys {
+	if master == nil {
+		return nil
+	}
+	return &MessageKeys{master: master, cache: make(map[uuid.UUID][]byte)}
+}
+
+// seal encrypts a message's content for storage. The conversation ID is bound
+// into the ciphertext, so content copied to another conversation won't open.
+func (k *MessageKeys) seal(db *gorm.DB, conversationID uuid.UUID, content string) (string, error) {
+	key, err := k.dataKey(db, conversationID, true)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := envelope.Seal(key, []byte(content), conversationID[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// open decrypts the content of encrypted messages in place. It is safe to call
+// on a nil *MessageKeys as long as none of the messages is encrypted.
+func (k *MessageKeys) open(db *gorm.DB, messages []models.Message) error {
+	for i := range messages {
+		msg := &messages[i]
+		if !msg.Encrypted {
+			continue
+		}
+		if k == nil {
+			return ErrMessageEncryptionOff
+		}
+		key, err := k.dataKey(db, msg.ConversationID, false)
+		if err != nil {
+			return err
+		}
+		sealed, err := base64.StdEncoding.DecodeString(msg.Content)
+		if err != nil {
+			return envelope.ErrDecrypt
+		}
+		content, err := envelope.Open(key, sealed, msg.ConversationID[:])
+		if err != nil {
+			return err
+		}
+		msg.Content = string(content)
+	}
+	return nil
+}
+
+// dataKey returns a conversation's unwrapped data key, creating it first when
+// create is set and the conversation has none yet
+func (k *MessageKeys) dataKey(db *gorm.DB, conversationID uuid.UUID, create bool) ([]byte, error) {
+	k.mu.Lock()
+	key, ok := k.cache[conversationID]
+	k.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	// The key is read from the primary: a replica may not have it yet
+	db = db.Clauses(dbresolver.Write)
+	conv, err := loadDataKey(db, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conv.DataKey == nil {
+		if !create {
+			return nil, envelope.ErrDecrypt
+		}
+		if conv, err = k.createDataKey(db, conversationID); err != nil {
+			return nil, err
+		}
+	}
+
+	if conv.DataKeyID == nil || *conv.DataKeyID != k.master.ID() {
+		return nil, envelope.ErrUnknownMasterKey
+	}
+	key, err = k.master.Unwrap(context.Background(), conv.DataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// A key read inside a transaction may have been created by it and
+	// vanish with a rollback, so only committed keys are cached
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return key, nil
+	}
+	k.mu.Lock()
+	if len(k.cache) >= maxCachedDataKeys {
+		k.cache = make(map[uuid.UUID][]byte)
+	}
+	k.cache[conversationID] = key
+	k.mu.Unlock()
+	return key, nil
+}
+
+// createDataKey stores a new wrapped data key on the conversation. When two
+// writers race, the first key stored wins and the other reads it back.
+func (k *MessageKeys) createDataKey(db *gorm.DB, conversationID uuid.UUID) (*models.Conversation, error) {
+	key, err := envelope.GenerateDataKey()
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := k.master.Wrap(context.Background(), key)
+	if err != nil {
+		return nil, err
+	}
+	keyID := k.master.ID()
+	err = db.Model(&models.Conversation{}).
+		Where("id = ? AND data_key IS NULL", conversationID).
+		Updates(map[string]interface{}{"data_key": wrapped, "data_key_id": keyID}).Error
+	if err != nil {
+		return nil, err
+	}
+	return loadDataKey(db, conversationID)
+}
+
+func loadDataKey(db *gorm.DB, conversationID uuid.UUID) (*models.Conversation, error) {
+	var conv models.Conversation
+	err := db.Select("id", "data_key", "data_key_id").First(&conv, "id = ?", conversationID).Error
+	return &conv, err
+}
@@ -0,0 +1,6311 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interfaces.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	database "github.com/yandas/backend/internal/database"
+	models "github.com/yandas/backend/internal/models"
+	repository "github.com/yandas/backend/internal/repository"
+	currency "github.com/yandas/backend/pkg/currency"
+)
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUserRepository) Create(user *models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserRepositoryMockRecorder) Create(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepository)(nil).Create), user)
+}
+
+// Delete mocks base method.
+func (m *MockUserRepository) Delete(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepository)(nil).Delete), id)
+}
+
+// ExistsByEmail mocks base method.
+func (m *MockUserRepository) ExistsByEmail(email string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsByEmail", email)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ExistsByEmail indicates an expected call of ExistsByEmail.
+func (mr *MockUserRepositoryMockRecorder) ExistsByEmail(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByEmail", reflect.TypeOf((*MockUserRepository)(nil).ExistsByEmail), email)
+}
+
+// ExistsByPhone mocks base method.
+func (m *MockUserRepository) ExistsByPhone(phone string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsByPhone", phone)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ExistsByPhone indicates an expected call of ExistsByPhone.
+func (mr *MockUserRepositoryMockRecorder) ExistsByPhone(phone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByPhone", reflect.TypeOf((*MockUserRepository)(nil).ExistsByPhone), phone)
+}
+
+// GetByEmail mocks base method.
+func (m *MockUserRepository) GetByEmail(email string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByEmail", email)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByEmail indicates an expected call of GetByEmail.
+func (mr *MockUserRepositoryMockRecorder) GetByEmail(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockUserRepository)(nil).GetByEmail), email)
+}
+
+// GetByID mocks base method.
+func (m *MockUserRepository) GetByID(id uuid.UUID) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockUserRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepository)(nil).GetByID), id)
+}
+
+// GetByPhone mocks base method.
+func (m *MockUserRepository) GetByPhone(phone string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPhone", phone)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPhone indicates an expected call of GetByPhone.
+func (mr *MockUserRepositoryMockRecorder) GetByPhone(phone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPhone", reflect.TypeOf((*MockUserRepository)(nil).GetByPhone), phone)
+}
+
+// HardDelete mocks base method.
+func (m *MockUserRepository) HardDelete(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HardDelete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HardDelete indicates an expected call of HardDelete.
+func (mr *MockUserRepositoryMockRecorder) HardDelete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HardDelete", reflect.TypeOf((*MockUserRepository)(nil).HardDelete), id)
+}
+
+// IsEmailUndeliverable mocks base method.
+func (m *MockUserRepository) IsEmailUndeliverable(email string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEmailUndeliverable", email)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsEmailUndeliverable indicates an expected call of IsEmailUndeliverable.
+func (mr *MockUserRepositoryMockRecorder) IsEmailUndeliverable(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEmailUndeliverable", reflect.TypeOf((*MockUserRepository)(nil).IsEmailUndeliverable), email)
+}
+
+// List mocks base method.
+func (m *MockUserRepository) List(filter repository.UserFilter, page, limit int) ([]models.User, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", filter, page, limit)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockUserRepositoryMockRecorder) List(filter, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepository)(nil).List), filter, page, limit)
+}
+
+// Lock mocks base method.
+func (m *MockUserRepository) Lock(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockUserRepositoryMockRecorder) Lock(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockUserRepository)(nil).Lock), id)
+}
+
+// MarkEmailUndeliverable mocks base method.
+func (m *MockUserRepository) MarkEmailUndeliverable(email, reason string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailUndeliverable", email, reason)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkEmailUndeliverable indicates an expected call of MarkEmailUndeliverable.
+func (mr *MockUserRepositoryMockRecorder) MarkEmailUndeliverable(email, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailUndeliverable", reflect.TypeOf((*MockUserRepository)(nil).MarkEmailUndeliverable), email, reason)
+}
+
+// SetActiveBulk mocks base method.
+func (m *MockUserRepository) SetActiveBulk(ids []uuid.UUID, active bool) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetActiveBulk", ids, active)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetActiveBulk indicates an expected call of SetActiveBulk.
+func (mr *MockUserRepositoryMockRecorder) SetActiveBulk(ids, active interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetActiveBulk", reflect.TypeOf((*MockUserRepository)(nil).SetActiveBulk), ids, active)
+}
+
+// StreamForExport mocks base method.
+func (m *MockUserRepository) StreamForExport(filter repository.ExportFilter, fn func([]models.User) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamForExport", filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamForExport indicates an expected call of StreamForExport.
+func (mr *MockUserRepositoryMockRecorder) StreamForExport(filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamForExport", reflect.TypeOf((*MockUserRepository)(nil).StreamForExport), filter, fn)
+}
+
+// Update mocks base method.
+func (m *MockUserRepository) Update(user *models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockUserRepositoryMockRecorder) Update(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepository)(nil).Update), user)
+}
+
+// MockYandasProfileRepository is a mock of YandasProfileRepository interface.
+type MockYandasProfileRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockYandasProfileRepositoryMockRecorder
+}
+
+// MockYandasProfileRepositoryMockRecorder is the mock recorder for MockYandasProfileRepository.
+type MockYandasProfileRepositoryMockRecorder struct {
+	mock *MockYandasProfileRepository
+}
+
+// NewMockYandasProfileRepository creates a new mock instance.
+func NewMockYandasProfileRepository(ctrl *gomock.Controller) *MockYandasProfileRepository {
+	mock := &MockYandasProfileRepository{ctrl: ctrl}
+	mock.recorder = &MockYandasProfileRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockYandasProfileRepository) EXPECT() *MockYandasProfileRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClearVacation mocks base method.
+func (m *MockYandasProfileRepository) ClearVacation(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearVacation", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearVacation indicates an expected call of ClearVacation.
+func (mr *MockYandasProfileRepositoryMockRecorder) ClearVacation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearVacation", reflect.TypeOf((*MockYandasProfileRepository)(nil).ClearVacation), id)
+}
+
+// Create mocks base method.
+func (m *MockYandasProfileRepository) Create(profile *models.YandasProfile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", profile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockYandasProfileRepositoryMockRecorder) Create(profile interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockYandasProfileRepository)(nil).Create), profile)
+}
+
+// GetByID mocks base method.
+func (m *MockYandasProfileRepository) GetByID(id uuid.UUID) (*models.YandasProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.YandasProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockYandasProfileRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockYandasProfileRepository)(nil).GetByID), id)
+}
+
+// GetByKYCReference mocks base method.
+func (m *MockYandasProfileRepository) GetByKYCReference(provider, reference string) (*models.YandasProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByKYCReference", provider, reference)
+	ret0, _ := ret[0].(*models.YandasProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByKYCReference indicates an expected call of GetByKYCReference.
+func (mr *MockYandasProfileRepositoryMockRecorder) GetByKYCReference(provider, reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByKYCReference", reflect.TypeOf((*MockYandasProfileRepository)(nil).GetByKYCReference), provider, reference)
+}
+
+// GetByUserID mocks base method.
+func (m *MockYandasProfileRepository) GetByUserID(userID uuid.UUID) (*models.YandasProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", userID)
+	ret0, _ := ret[0].(*models.YandasProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockYandasProfileRepositoryMockRecorder) GetByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockYandasProfileRepository)(nil).GetByUserID), userID)
+}
+
+// GetPublic mocks base method.
+func (m *MockYandasProfileRepository) GetPublic(id uuid.UUID) (*models.YandasProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublic", id)
+	ret0, _ := ret[0].(*models.YandasProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublic indicates an expected call of GetPublic.
+func (mr *MockYandasProfileRepositoryMockRecorder) GetPublic(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublic", reflect.TypeOf((*MockYandasProfileRepository)(nil).GetPublic), id)
+}
+
+// GetPublicBySlug mocks base method.
+func (m *MockYandasProfileRepository) GetPublicBySlug(slug string) (*models.YandasProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicBySlug", slug)
+	ret0, _ := ret[0].(*models.YandasProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicBySlug indicates an expected call of GetPublicBySlug.
+func (mr *MockYandasProfileRepositoryMockRecorder) GetPublicBySlug(slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicBySlug", reflect.TypeOf((*MockYandasProfileRepository)(nil).GetPublicBySlug), slug)
+}
+
+// ListAllApplications mocks base method.
+func (m *MockYandasProfileRepository) ListAllApplications(page, limit int, status string) ([]models.YandasProfile, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllApplications", page, limit, status)
+	ret0, _ := ret[0].([]models.YandasProfile)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAllApplications indicates an expected call of ListAllApplications.
+func (mr *MockYandasProfileRepositoryMockRecorder) ListAllApplications(page, limit, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllApplications", reflect.TypeOf((*MockYandasProfileRepository)(nil).ListAllApplications), page, limit, status)
+}
+
+// ListPendingApplications mocks base method.
+func (m *MockYandasProfileRepository) ListPendingApplications(page, limit int) ([]models.YandasProfile, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingApplications", page, limit)
+	ret0, _ := ret[0].([]models.YandasProfile)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPendingApplications indicates an expected call of ListPendingApplications.
+func (mr *MockYandasProfileRepositoryMockRecorder) ListPendingApplications(page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingApplications", reflect.TypeOf((*MockYandasProfileRepository)(nil).ListPendingApplications), page, limit)
+}
+
+// ListPublic mocks base method.
+func (m *MockYandasProfileRepository) ListPublic(page, limit int, categorySlug, city string, byQuality bool) ([]models.YandasListItem, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPublic", page, limit, categorySlug, city, byQuality)
+	ret0, _ := ret[0].([]models.YandasListItem)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPublic indicates an expected call of ListPublic.
+func (mr *MockYandasProfileRepositoryMockRecorder) ListPublic(page, limit, categorySlug, city, byQuality interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublic", reflect.TypeOf((*MockYandasProfileRepository)(nil).ListPublic), page, limit, categorySlug, city, byQuality)
+}
+
+// ListVacationsDue mocks base method.
+func (m *MockYandasProfileRepository) ListVacationsDue(now time.Time, limit int) ([]models.YandasProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVacationsDue", now, limit)
+	ret0, _ := ret[0].([]models.YandasProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVacationsDue indicates an expected call of ListVacationsDue.
+func (mr *MockYandasProfileRepositoryMockRecorder) ListVacationsDue(now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVacationsDue", reflect.TypeOf((*MockYandasProfileRepository)(nil).ListVacationsDue), now, limit)
+}
+
+// Search mocks base method.
+func (m *MockYandasProfileRepository) Search(query string, page, limit int) ([]models.YandasListItem, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", query, page, limit)
+	ret0, _ := ret[0].([]models.YandasListItem)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockYandasProfileRepositoryMockRecorder) Search(query, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockYandasProfileRepository)(nil).Search), query, page, limit)
+}
+
+// SetVacation mocks base method.
+func (m *MockYandasProfileRepository) SetVacation(id uuid.UUID, from, until time.Time, policy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVacation", id, from, until, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetVacation indicates an expected call of SetVacation.
+func (mr *MockYandasProfileRepositoryMockRecorder) SetVacation(id, from, until, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVacation", reflect.TypeOf((*MockYandasProfileRepository)(nil).SetVacation), id, from, until, policy)
+}
+
+// SlugExists mocks base method.
+func (m *MockYandasProfileRepository) SlugExists(slug string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SlugExists", slug)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SlugExists indicates an expected call of SlugExists.
+func (mr *MockYandasProfileRepositoryMockRecorder) SlugExists(slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SlugExists", reflect.TypeOf((*MockYandasProfileRepository)(nil).SlugExists), slug)
+}
+
+// StartVacation mocks base method.
+func (m *MockYandasProfileRepository) StartVacation(id uuid.UUID, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartVacation", id, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartVacation indicates an expected call of StartVacation.
+func (mr *MockYandasProfileRepositoryMockRecorder) StartVacation(id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartVacation", reflect.TypeOf((*MockYandasProfileRepository)(nil).StartVacation), id, at)
+}
+
+// StreamApplicationsForExport mocks base method.
+func (m *MockYandasProfileRepository) StreamApplicationsForExport(filter repository.ExportFilter, fn func([]models.YandasProfile) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamApplicationsForExport", filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamApplicationsForExport indicates an expected call of StreamApplicationsForExport.
+func (mr *MockYandasProfileRepositoryMockRecorder) StreamApplicationsForExport(filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamApplicationsForExport", reflect.TypeOf((*MockYandasProfileRepository)(nil).StreamApplicationsForExport), filter, fn)
+}
+
+// Update mocks base method.
+func (m *MockYandasProfileRepository) Update(profile *models.YandasProfile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", profile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockYandasProfileRepositoryMockRecorder) Update(profile interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockYandasProfileRepository)(nil).Update), profile)
+}
+
+// UpdateAvailability mocks base method.
+func (m *MockYandasProfileRepository) UpdateAvailability(id uuid.UUID, available bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAvailability", id, available)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAvailability indicates an expected call of UpdateAvailability.
+func (mr *MockYandasProfileRepositoryMockRecorder) UpdateAvailability(id, available interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAvailability", reflect.TypeOf((*MockYandasProfileRepository)(nil).UpdateAvailability), id, available)
+}
+
+// UpdateKYC mocks base method.
+func (m *MockYandasProfileRepository) UpdateKYC(profile *models.YandasProfile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateKYC", profile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateKYC indicates an expected call of UpdateKYC.
+func (mr *MockYandasProfileRepositoryMockRecorder) UpdateKYC(profile interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKYC", reflect.TypeOf((*MockYandasProfileRepository)(nil).UpdateKYC), profile)
+}
+
+// UpdateLocation mocks base method.
+func (m *MockYandasProfileRepository) UpdateLocation(id uuid.UUID, lat, lng float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLocation", id, lat, lng)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLocation indicates an expected call of UpdateLocation.
+func (mr *MockYandasProfileRepositoryMockRecorder) UpdateLocation(id, lat, lng interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLocation", reflect.TypeOf((*MockYandasProfileRepository)(nil).UpdateLocation), id, lat, lng)
+}
+
+// UpdateRating mocks base method.
+func (m *MockYandasProfileRepository) UpdateRating(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRating", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRating indicates an expected call of UpdateRating.
+func (mr *MockYandasProfileRepositoryMockRecorder) UpdateRating(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRating", reflect.TypeOf((*MockYandasProfileRepository)(nil).UpdateRating), id)
+}
+
+// MockCategoryRepository is a mock of CategoryRepository interface.
+type MockCategoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryRepositoryMockRecorder
+}
+
+// MockCategoryRepositoryMockRecorder is the mock recorder for MockCategoryRepository.
+type MockCategoryRepositoryMockRecorder struct {
+	mock *MockCategoryRepository
+}
+
+// NewMockCategoryRepository creates a new mock instance.
+func NewMockCategoryRepository(ctrl *gomock.Controller) *MockCategoryRepository {
+	mock := &MockCategoryRepository{ctrl: ctrl}
+	mock.recorder = &MockCategoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryRepository) EXPECT() *MockCategoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCategoryRepository) Create(category *models.Category) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCategoryRepositoryMockRecorder) Create(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCategoryRepository)(nil).Create), category)
+}
+
+// Delete mocks base method.
+func (m *MockCategoryRepository) Delete(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCategoryRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCategoryRepository)(nil).Delete), id)
+}
+
+// GetByID mocks base method.
+func (m *MockCategoryRepository) GetByID(id uuid.UUID) (*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCategoryRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCategoryRepository)(nil).GetByID), id)
+}
+
+// GetBySlug mocks base method.
+func (m *MockCategoryRepository) GetBySlug(slug string) (*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySlug", slug)
+	ret0, _ := ret[0].(*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySlug indicates an expected call of GetBySlug.
+func (mr *MockCategoryRepositoryMockRecorder) GetBySlug(slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySlug", reflect.TypeOf((*MockCategoryRepository)(nil).GetBySlug), slug)
+}
+
+// List mocks base method.
+func (m *MockCategoryRepository) List() ([]models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCategoryRepositoryMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCategoryRepository)(nil).List))
+}
+
+// ListAll mocks base method.
+func (m *MockCategoryRepository) ListAll() ([]models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll")
+	ret0, _ := ret[0].([]models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockCategoryRepositoryMockRecorder) ListAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockCategoryRepository)(nil).ListAll))
+}
+
+// Update mocks base method.
+func (m *MockCategoryRepository) Update(category *models.Category) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCategoryRepositoryMockRecorder) Update(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCategoryRepository)(nil).Update), category)
+}
+
+// MockServiceRepository is a mock of ServiceRepository interface.
+type MockServiceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceRepositoryMockRecorder
+}
+
+// MockServiceRepositoryMockRecorder is the mock recorder for MockServiceRepository.
+type MockServiceRepositoryMockRecorder struct {
+	mock *MockServiceRepository
+}
+
+// NewMockServiceRepository creates a new mock instance.
+func NewMockServiceRepository(ctrl *gomock.Controller) *MockServiceRepository {
+	mock := &MockServiceRepository{ctrl: ctrl}
+	mock.recorder = &MockServiceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceRepository) EXPECT() *MockServiceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountActiveByYandasID mocks base method.
+func (m *MockServiceRepository) CountActiveByYandasID(yandasID uuid.UUID) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveByYandasID", yandasID)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// CountActiveByYandasID indicates an expected call of CountActiveByYandasID.
+func (mr *MockServiceRepositoryMockRecorder) CountActiveByYandasID(yandasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveByYandasID", reflect.TypeOf((*MockServiceRepository)(nil).CountActiveByYandasID), yandasID)
+}
+
+// Create mocks base method.
+func (m *MockServiceRepository) Create(service *models.YandasService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", service)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockServiceRepositoryMockRecorder) Create(service interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockServiceRepository)(nil).Create), service)
+}
+
+// CreateOption mocks base method.
+func (m *MockServiceRepository) CreateOption(option *models.ServiceOption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOption", option)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOption indicates an expected call of CreateOption.
+func (mr *MockServiceRepositoryMockRecorder) CreateOption(option interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOption", reflect.TypeOf((*MockServiceRepository)(nil).CreateOption), option)
+}
+
+// Delete mocks base method.
+func (m *MockServiceRepository) Delete(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockServiceRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockServiceRepository)(nil).Delete), id)
+}
+
+// GetByID mocks base method.
+func (m *MockServiceRepository) GetByID(id uuid.UUID) (*models.YandasService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.YandasService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockServiceRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockServiceRepository)(nil).GetByID), id)
+}
+
+// GetByYandasID mocks base method.
+func (m *MockServiceRepository) GetByYandasID(yandasID uuid.UUID) ([]models.YandasService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByYandasID", yandasID)
+	ret0, _ := ret[0].([]models.YandasService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByYandasID indicates an expected call of GetByYandasID.
+func (mr *MockServiceRepositoryMockRecorder) GetByYandasID(yandasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByYandasID", reflect.TypeOf((*MockServiceRepository)(nil).GetByYandasID), yandasID)
+}
+
+// GetOption mocks base method.
+func (m *MockServiceRepository) GetOption(id uuid.UUID) (*models.ServiceOption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOption", id)
+	ret0, _ := ret[0].(*models.ServiceOption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOption indicates an expected call of GetOption.
+func (mr *MockServiceRepositoryMockRecorder) GetOption(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOption", reflect.TypeOf((*MockServiceRepository)(nil).GetOption), id)
+}
+
+// ListByStatus mocks base method.
+func (m *MockServiceRepository) ListByStatus(status string, page, limit int) ([]models.YandasService, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByStatus", status, page, limit)
+	ret0, _ := ret[0].([]models.YandasService)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByStatus indicates an expected call of ListByStatus.
+func (mr *MockServiceRepositoryMockRecorder) ListByStatus(status, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatus", reflect.TypeOf((*MockServiceRepository)(nil).ListByStatus), status, page, limit)
+}
+
+// ListByYandas mocks base method.
+func (m *MockServiceRepository) ListByYandas(yandasID uuid.UUID, includeArchived bool) ([]models.YandasService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByYandas", yandasID, includeArchived)
+	ret0, _ := ret[0].([]models.YandasService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByYandas indicates an expected call of ListByYandas.
+func (mr *MockServiceRepositoryMockRecorder) ListByYandas(yandasID, includeArchived interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByYandas", reflect.TypeOf((*MockServiceRepository)(nil).ListByYandas), yandasID, includeArchived)
+}
+
+// ListPublic mocks base method.
+func (m *MockServiceRepository) ListPublic(filter repository.ServiceFilter, page, limit int) ([]models.ServiceListItem, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPublic", filter, page, limit)
+	ret0, _ := ret[0].([]models.ServiceListItem)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPublic indicates an expected call of ListPublic.
+func (mr *MockServiceRepositoryMockRecorder) ListPublic(filter, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublic", reflect.TypeOf((*MockServiceRepository)(nil).ListPublic), filter, page, limit)
+}
+
+// ReplacePriceTiers mocks base method.
+func (m *MockServiceRepository) ReplacePriceTiers(serviceID uuid.UUID, tiers []models.ServicePriceTier) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplacePriceTiers", serviceID, tiers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplacePriceTiers indicates an expected call of ReplacePriceTiers.
+func (mr *MockServiceRepositoryMockRecorder) ReplacePriceTiers(serviceID, tiers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplacePriceTiers", reflect.TypeOf((*MockServiceRepository)(nil).ReplacePriceTiers), serviceID, tiers)
+}
+
+// Update mocks base method.
+func (m *MockServiceRepository) Update(service *models.YandasService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", service)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockServiceRepositoryMockRecorder) Update(service interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockServiceRepository)(nil).Update), service)
+}
+
+// UpdateOption mocks base method.
+func (m *MockServiceRepository) UpdateOption(option *models.ServiceOption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOption", option)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOption indicates an expected call of UpdateOption.
+func (mr *MockServiceRepositoryMockRecorder) UpdateOption(option interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOption", reflect.TypeOf((*MockServiceRepository)(nil).UpdateOption), option)
+}
+
+// MockOrderRepository is a mock of OrderRepository interface.
+type MockOrderRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderRepositoryMockRecorder
+}
+
+// MockOrderRepositoryMockRecorder is the mock recorder for MockOrderRepository.
+type MockOrderRepositoryMockRecorder struct {
+	mock *MockOrderRepository
+}
+
+// NewMockOrderRepository creates a new mock instance.
+func NewMockOrderRepository(ctrl *gomock.Controller) *MockOrderRepository {
+	mock := &MockOrderRepository{ctrl: ctrl}
+	mock.recorder = &MockOrderRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AccountingSummary mocks base method.
+func (m *MockOrderRepository) AccountingSummary(from, to time.Time) ([]repository.AccountingRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountingSummary", from, to)
+	ret0, _ := ret[0].([]repository.AccountingRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountingSummary indicates an expected call of AccountingSummary.
+func (mr *MockOrderRepositoryMockRecorder) AccountingSummary(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountingSummary", reflect.TypeOf((*MockOrderRepository)(nil).AccountingSummary), from, to)
+}
+
+// AddLineItem mocks base method.
+func (m *MockOrderRepository) AddLineItem(item *models.OrderLineItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLineItem", item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddLineItem indicates an expected call of AddLineItem.
+func (mr *MockOrderRepositoryMockRecorder) AddLineItem(item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLineItem", reflect.TypeOf((*MockOrderRepository)(nil).AddLineItem), item)
+}
+
+// CountActiveByService mocks base method.
+func (m *MockOrderRepository) CountActiveByService(serviceID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveByService", serviceID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActiveByService indicates an expected call of CountActiveByService.
+func (mr *MockOrderRepositoryMockRecorder) CountActiveByService(serviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveByService", reflect.TypeOf((*MockOrderRepository)(nil).CountActiveByService), serviceID)
+}
+
+// Create mocks base method.
+func (m *MockOrderRepository) Create(order *models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOrderRepositoryMockRecorder) Create(order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderRepository)(nil).Create), order)
+}
+
+// GetByID mocks base method.
+func (m *MockOrderRepository) GetByID(id uuid.UUID) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockOrderRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockOrderRepository)(nil).GetByID), id)
+}
+
+// GetByIDForUpdate mocks base method.
+func (m *MockOrderRepository) GetByIDForUpdate(id uuid.UUID) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDForUpdate", id)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDForUpdate indicates an expected call of GetByIDForUpdate.
+func (mr *MockOrderRepositoryMockRecorder) GetByIDForUpdate(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDForUpdate", reflect.TypeOf((*MockOrderRepository)(nil).GetByIDForUpdate), id)
+}
+
+// GetByOrderNumber mocks base method.
+func (m *MockOrderRepository) GetByOrderNumber(orderNumber string) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrderNumber", orderNumber)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOrderNumber indicates an expected call of GetByOrderNumber.
+func (mr *MockOrderRepositoryMockRecorder) GetByOrderNumber(orderNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrderNumber", reflect.TypeOf((*MockOrderRepository)(nil).GetByOrderNumber), orderNumber)
+}
+
+// GetStats mocks base method.
+func (m *MockOrderRepository) GetStats(yandasID uuid.UUID) (map[string]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", yandasID)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockOrderRepositoryMockRecorder) GetStats(yandasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockOrderRepository)(nil).GetStats), yandasID)
+}
+
+// ListAll mocks base method.
+func (m *MockOrderRepository) ListAll(filter repository.OrderFilter, page, limit int) ([]models.Order, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll", filter, page, limit)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockOrderRepositoryMockRecorder) ListAll(filter, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockOrderRepository)(nil).ListAll), filter, page, limit)
+}
+
+// ListAwaitingConfirmation mocks base method.
+func (m *MockOrderRepository) ListAwaitingConfirmation(requestedBefore time.Time, limit int) ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAwaitingConfirmation", requestedBefore, limit)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAwaitingConfirmation indicates an expected call of ListAwaitingConfirmation.
+func (mr *MockOrderRepositoryMockRecorder) ListAwaitingConfirmation(requestedBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAwaitingConfirmation", reflect.TypeOf((*MockOrderRepository)(nil).ListAwaitingConfirmation), requestedBefore, limit)
+}
+
+// ListByCustomer mocks base method.
+func (m *MockOrderRepository) ListByCustomer(customerID uuid.UUID, page, limit int, status string) ([]models.Order, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCustomer", customerID, page, limit, status)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByCustomer indicates an expected call of ListByCustomer.
+func (mr *MockOrderRepositoryMockRecorder) ListByCustomer(customerID, page, limit, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCustomer", reflect.TypeOf((*MockOrderRepository)(nil).ListByCustomer), customerID, page, limit, status)
+}
+
+// ListByYandas mocks base method.
+func (m *MockOrderRepository) ListByYandas(yandasID uuid.UUID, page, limit int, status string) ([]models.Order, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByYandas", yandasID, page, limit, status)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByYandas indicates an expected call of ListByYandas.
+func (mr *MockOrderRepositoryMockRecorder) ListByYandas(yandasID, page, limit, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByYandas", reflect.TypeOf((*MockOrderRepository)(nil).ListByYandas), yandasID, page, limit, status)
+}
+
+// ListScheduledByYandas mocks base method.
+func (m *MockOrderRepository) ListScheduledByYandas(yandasID uuid.UUID, from, to time.Time, statuses []string) ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledByYandas", yandasID, from, to, statuses)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledByYandas indicates an expected call of ListScheduledByYandas.
+func (mr *MockOrderRepositoryMockRecorder) ListScheduledByYandas(yandasID, from, to, statuses interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledByYandas", reflect.TypeOf((*MockOrderRepository)(nil).ListScheduledByYandas), yandasID, from, to, statuses)
+}
+
+// MarkEnRoute mocks base method.
+func (m *MockOrderRepository) MarkEnRoute(id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEnRoute", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkEnRoute indicates an expected call of MarkEnRoute.
+func (mr *MockOrderRepositoryMockRecorder) MarkEnRoute(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEnRoute", reflect.TypeOf((*MockOrderRepository)(nil).MarkEnRoute), id)
+}
+
+// SetConversation mocks base method.
+func (m *MockOrderRepository) SetConversation(id, conversationID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetConversation", id, conversationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetConversation indicates an expected call of SetConversation.
+func (mr *MockOrderRepositoryMockRecorder) SetConversation(id, conversationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConversation", reflect.TypeOf((*MockOrderRepository)(nil).SetConversation), id, conversationID)
+}
+
+// StreamForExport mocks base method.
+func (m *MockOrderRepository) StreamForExport(filter repository.ExportFilter, fn func([]models.Order) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamForExport", filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamForExport indicates an expected call of StreamForExport.
+func (mr *MockOrderRepositoryMockRecorder) StreamForExport(filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamForExport", reflect.TypeOf((*MockOrderRepository)(nil).StreamForExport), filter, fn)
+}
+
+// SumRevenue mocks base method.
+func (m *MockOrderRepository) SumRevenue(filter repository.OrderFilter) ([]repository.CurrencyTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumRevenue", filter)
+	ret0, _ := ret[0].([]repository.CurrencyTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumRevenue indicates an expected call of SumRevenue.
+func (mr *MockOrderRepositoryMockRecorder) SumRevenue(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumRevenue", reflect.TypeOf((*MockOrderRepository)(nil).SumRevenue), filter)
+}
+
+// Update mocks base method.
+func (m *MockOrderRepository) Update(order *models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockOrderRepositoryMockRecorder) Update(order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockOrderRepository)(nil).Update), order)
+}
+
+// UpdateETA mocks base method.
+func (m *MockOrderRepository) UpdateETA(id uuid.UUID, etaAt time.Time, distanceKm float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateETA", id, etaAt, distanceKm)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateETA indicates an expected call of UpdateETA.
+func (mr *MockOrderRepositoryMockRecorder) UpdateETA(id, etaAt, distanceKm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateETA", reflect.TypeOf((*MockOrderRepository)(nil).UpdateETA), id, etaAt, distanceKm)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockOrderRepository) UpdateStatus(id uuid.UUID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockOrderRepositoryMockRecorder) UpdateStatus(id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockOrderRepository)(nil).UpdateStatus), id, status)
+}
+
+// MockOrderAdjustmentRepository is a mock of OrderAdjustmentRepository interface.
+type MockOrderAdjustmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderAdjustmentRepositoryMockRecorder
+}
+
+// MockOrderAdjustmentRepositoryMockRecorder is the mock recorder for MockOrderAdjustmentRepository.
+type MockOrderAdjustmentRepositoryMockRecorder struct {
+	mock *MockOrderAdjustmentRepository
+}
+
+// NewMockOrderAdjustmentRepository creates a new mock instance.
+func NewMockOrderAdjustmentRepository(ctrl *gomock.Controller) *MockOrderAdjustmentRepository {
+	mock := &MockOrderAdjustmentRepository{ctrl: ctrl}
+	mock.recorder = &MockOrderAdjustmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderAdjustmentRepository) EXPECT() *MockOrderAdjustmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOrderAdjustmentRepository) Create(adjustment *models.OrderAdjustment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", adjustment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOrderAdjustmentRepositoryMockRecorder) Create(adjustment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderAdjustmentRepository)(nil).Create), adjustment)
+}
+
+// GetByID mocks base method.
+func (m *MockOrderAdjustmentRepository) GetByID(id uuid.UUID) (*models.OrderAdjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.OrderAdjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockOrderAdjustmentRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockOrderAdjustmentRepository)(nil).GetByID), id)
+}
+
+// ListByOrder mocks base method.
+func (m *MockOrderAdjustmentRepository) ListByOrder(orderID uuid.UUID) ([]models.OrderAdjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByOrder", orderID)
+	ret0, _ := ret[0].([]models.OrderAdjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByOrder indicates an expected call of ListByOrder.
+func (mr *MockOrderAdjustmentRepositoryMockRecorder) ListByOrder(orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByOrder", reflect.TypeOf((*MockOrderAdjustmentRepository)(nil).ListByOrder), orderID)
+}
+
+// Resolve mocks base method.
+func (m *MockOrderAdjustmentRepository) Resolve(id uuid.UUID, status string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", id, status)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockOrderAdjustmentRepositoryMockRecorder) Resolve(id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockOrderAdjustmentRepository)(nil).Resolve), id, status)
+}
+
+// WithdrawPending mocks base method.
+func (m *MockOrderAdjustmentRepository) WithdrawPending(orderID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawPending", orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithdrawPending indicates an expected call of WithdrawPending.
+func (mr *MockOrderAdjustmentRepositoryMockRecorder) WithdrawPending(orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawPending", reflect.TypeOf((*MockOrderAdjustmentRepository)(nil).WithdrawPending), orderID)
+}
+
+// MockOrderHistoryRepository is a mock of OrderHistoryRepository interface.
+type MockOrderHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderHistoryRepositoryMockRecorder
+}
+
+// MockOrderHistoryRepositoryMockRecorder is the mock recorder for MockOrderHistoryRepository.
+type MockOrderHistoryRepositoryMockRecorder struct {
+	mock *MockOrderHistoryRepository
+}
+
+// NewMockOrderHistoryRepository creates a new mock instance.
+func NewMockOrderHistoryRepository(ctrl *gomock.Controller) *MockOrderHistoryRepository {
+	mock := &MockOrderHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockOrderHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderHistoryRepository) EXPECT() *MockOrderHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// LastEvent mocks base method.
+func (m *MockOrderHistoryRepository) LastEvent(orderID uuid.UUID, event string) (*models.OrderStatusHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastEvent", orderID, event)
+	ret0, _ := ret[0].(*models.OrderStatusHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastEvent indicates an expected call of LastEvent.
+func (mr *MockOrderHistoryRepositoryMockRecorder) LastEvent(orderID, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastEvent", reflect.TypeOf((*MockOrderHistoryRepository)(nil).LastEvent), orderID, event)
+}
+
+// ListByOrder mocks base method.
+func (m *MockOrderHistoryRepository) ListByOrder(orderID uuid.UUID) ([]models.OrderStatusHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByOrder", orderID)
+	ret0, _ := ret[0].([]models.OrderStatusHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByOrder indicates an expected call of ListByOrder.
+func (mr *MockOrderHistoryRepositoryMockRecorder) ListByOrder(orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByOrder", reflect.TypeOf((*MockOrderHistoryRepository)(nil).ListByOrder), orderID)
+}
+
+// Record mocks base method.
+func (m *MockOrderHistoryRepository) Record(entry *models.OrderStatusHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockOrderHistoryRepositoryMockRecorder) Record(entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockOrderHistoryRepository)(nil).Record), entry)
+}
+
+// MockJobRequestRepository is a mock of JobRequestRepository interface.
+type MockJobRequestRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobRequestRepositoryMockRecorder
+}
+
+// MockJobRequestRepositoryMockRecorder is the mock recorder for MockJobRequestRepository.
+type MockJobRequestRepositoryMockRecorder struct {
+	mock *MockJobRequestRepository
+}
+
+// NewMockJobRequestRepository creates a new mock instance.
+func NewMockJobRequestRepository(ctrl *gomock.Controller) *MockJobRequestRepository {
+	mock := &MockJobRequestRepository{ctrl: ctrl}
+	mock.recorder = &MockJobRequestRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobRequestRepository) EXPECT() *MockJobRequestRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockJobRequestRepository) Close(id uuid.UUID, status string, acceptedBidID, orderID *uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", id, status, acceptedBidID, orderID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockJobRequestRepositoryMockRecorder) Close(id, status, acceptedBidID, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockJobRequestRepository)(nil).Close), id, status, acceptedBidID, orderID)
+}
+
+// Create mocks base method.
+func (m *MockJobRequestRepository) Create(request *models.JobRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockJobRequestRepositoryMockRecorder) Create(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockJobRequestRepository)(nil).Create), request)
+}
+
+// CreateBid mocks base method.
+func (m *MockJobRequestRepository) CreateBid(bid *models.Bid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBid", bid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBid indicates an expected call of CreateBid.
+func (mr *MockJobRequestRepositoryMockRecorder) CreateBid(bid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBid", reflect.TypeOf((*MockJobRequestRepository)(nil).CreateBid), bid)
+}
+
+// ExpireOpen mocks base method.
+func (m *MockJobRequestRepository) ExpireOpen(now time.Time) ([]models.JobRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireOpen", now)
+	ret0, _ := ret[0].([]models.JobRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireOpen indicates an expected call of ExpireOpen.
+func (mr *MockJobRequestRepositoryMockRecorder) ExpireOpen(now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireOpen", reflect.TypeOf((*MockJobRequestRepository)(nil).ExpireOpen), now)
+}
+
+// GetBid mocks base method.
+func (m *MockJobRequestRepository) GetBid(id uuid.UUID) (*models.Bid, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBid", id)
+	ret0, _ := ret[0].(*models.Bid)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBid indicates an expected call of GetBid.
+func (mr *MockJobRequestRepositoryMockRecorder) GetBid(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBid", reflect.TypeOf((*MockJobRequestRepository)(nil).GetBid), id)
+}
+
+// GetBidByYandas mocks base method.
+func (m *MockJobRequestRepository) GetBidByYandas(requestID, yandasID uuid.UUID) (*models.Bid, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBidByYandas", requestID, yandasID)
+	ret0, _ := ret[0].(*models.Bid)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBidByYandas indicates an expected call of GetBidByYandas.
+func (mr *MockJobRequestRepositoryMockRecorder) GetBidByYandas(requestID, yandasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBidByYandas", reflect.TypeOf((*MockJobRequestRepository)(nil).GetBidByYandas), requestID, yandasID)
+}
+
+// GetByID mocks base method.
+func (m *MockJobRequestRepository) GetByID(id uuid.UUID) (*models.JobRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.JobRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockJobRequestRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockJobRequestRepository)(nil).GetByID), id)
+}
+
+// ListBids mocks base method.
+func (m *MockJobRequestRepository) ListBids(requestID uuid.UUID) ([]models.Bid, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBids", requestID)
+	ret0, _ := ret[0].([]models.Bid)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBids indicates an expected call of ListBids.
+func (mr *MockJobRequestRepositoryMockRecorder) ListBids(requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBids", reflect.TypeOf((*MockJobRequestRepository)(nil).ListBids), requestID)
+}
+
+// ListBidsByYandas mocks base method.
+func (m *MockJobRequestRepository) ListBidsByYandas(yandasID uuid.UUID, page, limit int) ([]models.Bid, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBidsByYandas", yandasID, page, limit)
+	ret0, _ := ret[0].([]models.Bid)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListBidsByYandas indicates an expected call of ListBidsByYandas.
+func (mr *MockJobRequestRepositoryMockRecorder) ListBidsByYandas(yandasID, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBidsByYandas", reflect.TypeOf((*MockJobRequestRepository)(nil).ListBidsByYandas), yandasID, page, limit)
+}
+
+// ListByCustomer mocks base method.
+func (m *MockJobRequestRepository) ListByCustomer(customerID uuid.UUID, page, limit int, status string) ([]models.JobRequest, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCustomer", customerID, page, limit, status)
+	ret0, _ := ret[0].([]models.JobRequest)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByCustomer indicates an expected call of ListByCustomer.
+func (mr *MockJobRequestRepositoryMockRecorder) ListByCustomer(customerID, page, limit, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCustomer", reflect.TypeOf((*MockJobRequestRepository)(nil).ListByCustomer), customerID, page, limit, status)
+}
+
+// ListOpenForYandas mocks base method.
+func (m *MockJobRequestRepository) ListOpenForYandas(yandasID uuid.UUID, now time.Time, page, limit int) ([]models.JobRequest, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenForYandas", yandasID, now, page, limit)
+	ret0, _ := ret[0].([]models.JobRequest)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListOpenForYandas indicates an expected call of ListOpenForYandas.
+func (mr *MockJobRequestRepositoryMockRecorder) ListOpenForYandas(yandasID, now, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenForYandas", reflect.TypeOf((*MockJobRequestRepository)(nil).ListOpenForYandas), yandasID, now, page, limit)
+}
+
+// MatchingYandasUserIDs mocks base method.
+func (m *MockJobRequestRepository) MatchingYandasUserIDs(request *models.JobRequest, limit int) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchingYandasUserIDs", request, limit)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchingYandasUserIDs indicates an expected call of MatchingYandasUserIDs.
+func (mr *MockJobRequestRepositoryMockRecorder) MatchingYandasUserIDs(request, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchingYandasUserIDs", reflect.TypeOf((*MockJobRequestRepository)(nil).MatchingYandasUserIDs), request, limit)
+}
+
+// RejectPendingBids mocks base method.
+func (m *MockJobRequestRepository) RejectPendingBids(requestID uuid.UUID, keepID *uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectPendingBids", requestID, keepID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectPendingBids indicates an expected call of RejectPendingBids.
+func (mr *MockJobRequestRepositoryMockRecorder) RejectPendingBids(requestID, keepID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectPendingBids", reflect.TypeOf((*MockJobRequestRepository)(nil).RejectPendingBids), requestID, keepID)
+}
+
+// Update mocks base method.
+func (m *MockJobRequestRepository) Update(request *models.JobRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockJobRequestRepositoryMockRecorder) Update(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockJobRequestRepository)(nil).Update), request)
+}
+
+// UpdateBid mocks base method.
+func (m *MockJobRequestRepository) UpdateBid(bid *models.Bid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBid", bid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBid indicates an expected call of UpdateBid.
+func (mr *MockJobRequestRepositoryMockRecorder) UpdateBid(bid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBid", reflect.TypeOf((*MockJobRequestRepository)(nil).UpdateBid), bid)
+}
+
+// MockAssignmentRepository is a mock of AssignmentRepository interface.
+type MockAssignmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssignmentRepositoryMockRecorder
+}
+
+// MockAssignmentRepositoryMockRecorder is the mock recorder for MockAssignmentRepository.
+type MockAssignmentRepositoryMockRecorder struct {
+	mock *MockAssignmentRepository
+}
+
+// NewMockAssignmentRepository creates a new mock instance.
+func NewMockAssignmentRepository(ctrl *gomock.Controller) *MockAssignmentRepository {
+	mock := &MockAssignmentRepository{ctrl: ctrl}
+	mock.recorder = &MockAssignmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAssignmentRepository) EXPECT() *MockAssignmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAssignmentRepository) Create(assignment *models.AutoAssignment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", assignment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAssignmentRepositoryMockRecorder) Create(assignment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAssignmentRepository)(nil).Create), assignment)
+}
+
+// GetByID mocks base method.
+func (m *MockAssignmentRepository) GetByID(id uuid.UUID) (*models.AutoAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.AutoAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAssignmentRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAssignmentRepository)(nil).GetByID), id)
+}
+
+// ListExpiredOffers mocks base method.
+func (m *MockAssignmentRepository) ListExpiredOffers(now time.Time, limit int) ([]models.AutoAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredOffers", now, limit)
+	ret0, _ := ret[0].([]models.AutoAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredOffers indicates an expected call of ListExpiredOffers.
+func (mr *MockAssignmentRepositoryMockRecorder) ListExpiredOffers(now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredOffers", reflect.TypeOf((*MockAssignmentRepository)(nil).ListExpiredOffers), now, limit)
+}
+
+// NearestCandidates mocks base method.
+func (m *MockAssignmentRepository) NearestCandidates(categoryID, customerID uuid.UUID, lat, lng, radiusKm float64, exclude []string, limit int) ([]repository.AssignmentCandidate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NearestCandidates", categoryID, customerID, lat, lng, radiusKm, exclude, limit)
+	ret0, _ := ret[0].([]repository.AssignmentCandidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NearestCandidates indicates an expected call of NearestCandidates.
+func (mr *MockAssignmentRepositoryMockRecorder) NearestCandidates(categoryID, customerID, lat, lng, radiusKm, exclude, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NearestCandidates", reflect.TypeOf((*MockAssignmentRepository)(nil).NearestCandidates), categoryID, customerID, lat, lng, radiusKm, exclude, limit)
+}
+
+// Transition mocks base method.
+func (m *MockAssignmentRepository) Transition(assignment *models.AutoAssignment, expectAttempts int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transition", assignment, expectAttempts)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transition indicates an expected call of Transition.
+func (mr *MockAssignmentRepositoryMockRecorder) Transition(assignment, expectAttempts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transition", reflect.TypeOf((*MockAssignmentRepository)(nil).Transition), assignment, expectAttempts)
+}
+
+// MockRecurringOrderRepository is a mock of RecurringOrderRepository interface.
+type MockRecurringOrderRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecurringOrderRepositoryMockRecorder
+}
+
+// MockRecurringOrderRepositoryMockRecorder is the mock recorder for MockRecurringOrderRepository.
+type MockRecurringOrderRepositoryMockRecorder struct {
+	mock *MockRecurringOrderRepository
+}
+
+// NewMockRecurringOrderRepository creates a new mock instance.
+func NewMockRecurringOrderRepository(ctrl *gomock.Controller) *MockRecurringOrderRepository {
+	mock := &MockRecurringOrderRepository{ctrl: ctrl}
+	mock.recorder = &MockRecurringOrderRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecurringOrderRepository) EXPECT() *MockRecurringOrderRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimOccurrence mocks base method.
+func (m *MockRecurringOrderRepository) ClaimOccurrence(id uuid.UUID, scheduledFor, next time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOccurrence", id, scheduledFor, next)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOccurrence indicates an expected call of ClaimOccurrence.
+func (mr *MockRecurringOrderRepositoryMockRecorder) ClaimOccurrence(id, scheduledFor, next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOccurrence", reflect.TypeOf((*MockRecurringOrderRepository)(nil).ClaimOccurrence), id, scheduledFor, next)
+}
+
+// Create mocks base method.
+func (m *MockRecurringOrderRepository) Create(recurring *models.RecurringOrder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", recurring)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRecurringOrderRepositoryMockRecorder) Create(recurring interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRecurringOrderRepository)(nil).Create), recurring)
+}
+
+// GetByID mocks base method.
+func (m *MockRecurringOrderRepository) GetByID(id uuid.UUID) (*models.RecurringOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.RecurringOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRecurringOrderRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRecurringOrderRepository)(nil).GetByID), id)
+}
+
+// ListByCustomer mocks base method.
+func (m *MockRecurringOrderRepository) ListByCustomer(customerID uuid.UUID, page, limit int) ([]models.RecurringOrder, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCustomer", customerID, page, limit)
+	ret0, _ := ret[0].([]models.RecurringOrder)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByCustomer indicates an expected call of ListByCustomer.
+func (mr *MockRecurringOrderRepositoryMockRecorder) ListByCustomer(customerID, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCustomer", reflect.TypeOf((*MockRecurringOrderRepository)(nil).ListByCustomer), customerID, page, limit)
+}
+
+// ListDue mocks base method.
+func (m *MockRecurringOrderRepository) ListDue(before time.Time, limit int) ([]models.RecurringOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", before, limit)
+	ret0, _ := ret[0].([]models.RecurringOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockRecurringOrderRepositoryMockRecorder) ListDue(before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockRecurringOrderRepository)(nil).ListDue), before, limit)
+}
+
+// Update mocks base method.
+func (m *MockRecurringOrderRepository) Update(recurring *models.RecurringOrder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", recurring)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRecurringOrderRepositoryMockRecorder) Update(recurring interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRecurringOrderRepository)(nil).Update), recurring)
+}
+
+// MockReviewRepository is a mock of ReviewRepository interface.
+type MockReviewRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewRepositoryMockRecorder
+}
+
+// MockReviewRepositoryMockRecorder is the mock recorder for MockReviewRepository.
+type MockReviewRepositoryMockRecorder struct {
+	mock *MockReviewRepository
+}
+
+// NewMockReviewRepository creates a new mock instance.
+func NewMockReviewRepository(ctrl *gomock.Controller) *MockReviewRepository {
+	mock := &MockReviewRepository{ctrl: ctrl}
+	mock.recorder = &MockReviewRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewRepository) EXPECT() *MockReviewRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddHelpfulVote mocks base method.
+func (m *MockReviewRepository) AddHelpfulVote(reviewID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddHelpfulVote", reviewID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddHelpfulVote indicates an expected call of AddHelpfulVote.
+func (mr *MockReviewRepositoryMockRecorder) AddHelpfulVote(reviewID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHelpfulVote", reflect.TypeOf((*MockReviewRepository)(nil).AddHelpfulVote), reviewID, userID)
+}
+
+// Create mocks base method.
+func (m *MockReviewRepository) Create(review *models.Review) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReviewRepositoryMockRecorder) Create(review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReviewRepository)(nil).Create), review)
+}
+
+// Delete mocks base method.
+func (m *MockReviewRepository) Delete(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockReviewRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReviewRepository)(nil).Delete), id)
+}
+
+// ExistsByOrderID mocks base method.
+func (m *MockReviewRepository) ExistsByOrderID(orderID uuid.UUID) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsByOrderID", orderID)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ExistsByOrderID indicates an expected call of ExistsByOrderID.
+func (mr *MockReviewRepositoryMockRecorder) ExistsByOrderID(orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByOrderID", reflect.TypeOf((*MockReviewRepository)(nil).ExistsByOrderID), orderID)
+}
+
+// GetByID mocks base method.
+func (m *MockReviewRepository) GetByID(id uuid.UUID) (*models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockReviewRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockReviewRepository)(nil).GetByID), id)
+}
+
+// GetByOrderID mocks base method.
+func (m *MockReviewRepository) GetByOrderID(orderID uuid.UUID) (*models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrderID", orderID)
+	ret0, _ := ret[0].(*models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOrderID indicates an expected call of GetByOrderID.
+func (mr *MockReviewRepositoryMockRecorder) GetByOrderID(orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrderID", reflect.TypeOf((*MockReviewRepository)(nil).GetByOrderID), orderID)
+}
+
+// ListAll mocks base method.
+func (m *MockReviewRepository) ListAll(filter repository.ReviewFilter, page, limit int) ([]models.Review, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll", filter, page, limit)
+	ret0, _ := ret[0].([]models.Review)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockReviewRepositoryMockRecorder) ListAll(filter, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockReviewRepository)(nil).ListAll), filter, page, limit)
+}
+
+// ListByReviewee mocks base method.
+func (m *MockReviewRepository) ListByReviewee(revieweeID uuid.UUID, page, limit int, sort string) ([]models.Review, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByReviewee", revieweeID, page, limit, sort)
+	ret0, _ := ret[0].([]models.Review)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByReviewee indicates an expected call of ListByReviewee.
+func (mr *MockReviewRepositoryMockRecorder) ListByReviewee(revieweeID, page, limit, sort interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByReviewee", reflect.TypeOf((*MockReviewRepository)(nil).ListByReviewee), revieweeID, page, limit, sort)
+}
+
+// RemoveHelpfulVote mocks base method.
+func (m *MockReviewRepository) RemoveHelpfulVote(reviewID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveHelpfulVote", reviewID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveHelpfulVote indicates an expected call of RemoveHelpfulVote.
+func (mr *MockReviewRepositoryMockRecorder) RemoveHelpfulVote(reviewID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveHelpfulVote", reflect.TypeOf((*MockReviewRepository)(nil).RemoveHelpfulVote), reviewID, userID)
+}
+
+// SetHidden mocks base method.
+func (m *MockReviewRepository) SetHidden(id uuid.UUID, hidden bool, by uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHidden", id, hidden, by)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHidden indicates an expected call of SetHidden.
+func (mr *MockReviewRepositoryMockRecorder) SetHidden(id, hidden, by interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHidden", reflect.TypeOf((*MockReviewRepository)(nil).SetHidden), id, hidden, by)
+}
+
+// SetReply mocks base method.
+func (m *MockReviewRepository) SetReply(id uuid.UUID, reply string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReply", id, reply)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetReply indicates an expected call of SetReply.
+func (mr *MockReviewRepositoryMockRecorder) SetReply(id, reply interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReply", reflect.TypeOf((*MockReviewRepository)(nil).SetReply), id, reply)
+}
+
+// Stats mocks base method.
+func (m *MockReviewRepository) Stats(revieweeID uuid.UUID, trendFrom time.Time) (*repository.ReviewStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", revieweeID, trendFrom)
+	ret0, _ := ret[0].(*repository.ReviewStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockReviewRepositoryMockRecorder) Stats(revieweeID, trendFrom interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockReviewRepository)(nil).Stats), revieweeID, trendFrom)
+}
+
+// MockConversationRepository is a mock of ConversationRepository interface.
+type MockConversationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConversationRepositoryMockRecorder
+}
+
+// MockConversationRepositoryMockRecorder is the mock recorder for MockConversationRepository.
+type MockConversationRepositoryMockRecorder struct {
+	mock *MockConversationRepository
+}
+
+// NewMockConversationRepository creates a new mock instance.
+func NewMockConversationRepository(ctrl *gomock.Controller) *MockConversationRepository {
+	mock := &MockConversationRepository{ctrl: ctrl}
+	mock.recorder = &MockConversationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConversationRepository) EXPECT() *MockConversationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockConversationRepository) Create(conv *models.Conversation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", conv)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockConversationRepositoryMockRecorder) Create(conv interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockConversationRepository)(nil).Create), conv)
+}
+
+// FindBetween mocks base method.
+func (m *MockConversationRepository) FindBetween(userA, userB uuid.UUID) (*models.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBetween", userA, userB)
+	ret0, _ := ret[0].(*models.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBetween indicates an expected call of FindBetween.
+func (mr *MockConversationRepositoryMockRecorder) FindBetween(userA, userB interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBetween", reflect.TypeOf((*MockConversationRepository)(nil).FindBetween), userA, userB)
+}
+
+// GetByID mocks base method.
+func (m *MockConversationRepository) GetByID(id uuid.UUID) (*models.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockConversationRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockConversationRepository)(nil).GetByID), id)
+}
+
+// GetByParticipants mocks base method.
+func (m *MockConversationRepository) GetByParticipants(customerID, yandasID uuid.UUID) (*models.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByParticipants", customerID, yandasID)
+	ret0, _ := ret[0].(*models.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByParticipants indicates an expected call of GetByParticipants.
+func (mr *MockConversationRepositoryMockRecorder) GetByParticipants(customerID, yandasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByParticipants", reflect.TypeOf((*MockConversationRepository)(nil).GetByParticipants), customerID, yandasID)
+}
+
+// GetFlags mocks base method.
+func (m *MockConversationRepository) GetFlags(conv *models.Conversation) (*repository.ConversationFlags, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFlags", conv)
+	ret0, _ := ret[0].(*repository.ConversationFlags)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFlags indicates an expected call of GetFlags.
+func (mr *MockConversationRepositoryMockRecorder) GetFlags(conv interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlags", reflect.TypeOf((*MockConversationRepository)(nil).GetFlags), conv)
+}
+
+// GetOrCreate mocks base method.
+func (m *MockConversationRepository) GetOrCreate(customerID, yandasID uuid.UUID, orderID *uuid.UUID) (*models.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrCreate", customerID, yandasID, orderID)
+	ret0, _ := ret[0].(*models.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrCreate indicates an expected call of GetOrCreate.
+func (mr *MockConversationRepositoryMockRecorder) GetOrCreate(customerID, yandasID, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreate", reflect.TypeOf((*MockConversationRepository)(nil).GetOrCreate), customerID, yandasID, orderID)
+}
+
+// LinkOrder mocks base method.
+func (m *MockConversationRepository) LinkOrder(id, orderID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkOrder", id, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LinkOrder indicates an expected call of LinkOrder.
+func (mr *MockConversationRepositoryMockRecorder) LinkOrder(id, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkOrder", reflect.TypeOf((*MockConversationRepository)(nil).LinkOrder), id, orderID)
+}
+
+// ListByUser mocks base method.
+func (m *MockConversationRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.Conversation, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", userID, page, limit)
+	ret0, _ := ret[0].([]models.Conversation)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockConversationRepositoryMockRecorder) ListByUser(userID, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockConversationRepository)(nil).ListByUser), userID, page, limit)
+}
+
+// Search mocks base method.
+func (m *MockConversationRepository) Search(userID uuid.UUID, query string, page, limit int) ([]repository.ConversationMatch, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", userID, query, page, limit)
+	ret0, _ := ret[0].([]repository.ConversationMatch)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockConversationRepositoryMockRecorder) Search(userID, query, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockConversationRepository)(nil).Search), userID, query, page, limit)
+}
+
+// UpdateLastMessage mocks base method.
+func (m *MockConversationRepository) UpdateLastMessage(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastMessage", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastMessage indicates an expected call of UpdateLastMessage.
+func (mr *MockConversationRepositoryMockRecorder) UpdateLastMessage(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastMessage", reflect.TypeOf((*MockConversationRepository)(nil).UpdateLastMessage), id)
+}
+
+// MockMessageRepository is a mock of MessageRepository interface.
+type MockMessageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageRepositoryMockRecorder
+}
+
+// MockMessageRepositoryMockRecorder is the mock recorder for MockMessageRepository.
+type MockMessageRepositoryMockRecorder struct {
+	mock *MockMessageRepository
+}
+
+// NewMockMessageRepository creates a new mock instance.
+func NewMockMessageRepository(ctrl *gomock.Controller) *MockMessageRepository {
+	mock := &MockMessageRepository{ctrl: ctrl}
+	mock.recorder = &MockMessageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageRepository) EXPECT() *MockMessageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ContentSearchable mocks base method.
+func (m *MockMessageRepository) ContentSearchable() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContentSearchable")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ContentSearchable indicates an expected call of ContentSearchable.
+func (mr *MockMessageRepositoryMockRecorder) ContentSearchable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContentSearchable", reflect.TypeOf((*MockMessageRepository)(nil).ContentSearchable))
+}
+
+// CountBetween mocks base method.
+func (m *MockMessageRepository) CountBetween(conversationID uuid.UUID, from, to time.Time) (int64, *time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBetween", conversationID, from, to)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CountBetween indicates an expected call of CountBetween.
+func (mr *MockMessageRepositoryMockRecorder) CountBetween(conversationID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBetween", reflect.TypeOf((*MockMessageRepository)(nil).CountBetween), conversationID, from, to)
+}
+
+// Create mocks base method.
+func (m *MockMessageRepository) Create(msg *models.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockMessageRepositoryMockRecorder) Create(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMessageRepository)(nil).Create), msg)
+}
+
+// EncryptPlaintext mocks base method.
+func (m *MockMessageRepository) EncryptPlaintext(limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptPlaintext", limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EncryptPlaintext indicates an expected call of EncryptPlaintext.
+func (mr *MockMessageRepositoryMockRecorder) EncryptPlaintext(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptPlaintext", reflect.TypeOf((*MockMessageRepository)(nil).EncryptPlaintext), limit)
+}
+
+// EndLiveLocation mocks base method.
+func (m *MockMessageRepository) EndLiveLocation(id, conversationID, senderID uuid.UUID, now time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EndLiveLocation", id, conversationID, senderID, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EndLiveLocation indicates an expected call of EndLiveLocation.
+func (mr *MockMessageRepositoryMockRecorder) EndLiveLocation(id, conversationID, senderID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndLiveLocation", reflect.TypeOf((*MockMessageRepository)(nil).EndLiveLocation), id, conversationID, senderID, now)
+}
+
+// ExpireLiveLocations mocks base method.
+func (m *MockMessageRepository) ExpireLiveLocations(now time.Time) ([]models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireLiveLocations", now)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireLiveLocations indicates an expected call of ExpireLiveLocations.
+func (mr *MockMessageRepositoryMockRecorder) ExpireLiveLocations(now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireLiveLocations", reflect.TypeOf((*MockMessageRepository)(nil).ExpireLiveLocations), now)
+}
+
+// GetByConversation mocks base method.
+func (m *MockMessageRepository) GetByConversation(conversationID uuid.UUID, page, limit int) ([]models.Message, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByConversation", conversationID, page, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetByConversation indicates an expected call of GetByConversation.
+func (mr *MockMessageRepositoryMockRecorder) GetByConversation(conversationID, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByConversation", reflect.TypeOf((*MockMessageRepository)(nil).GetByConversation), conversationID, page, limit)
+}
+
+// GetUnreadCount mocks base method.
+func (m *MockMessageRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnreadCount", userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnreadCount indicates an expected call of GetUnreadCount.
+func (mr *MockMessageRepositoryMockRecorder) GetUnreadCount(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnreadCount", reflect.TypeOf((*MockMessageRepository)(nil).GetUnreadCount), userID)
+}
+
+// ListUpTo mocks base method.
+func (m *MockMessageRepository) ListUpTo(conversationID uuid.UUID, createdAt time.Time, id uuid.UUID, limit int) ([]models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUpTo", conversationID, createdAt, id, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUpTo indicates an expected call of ListUpTo.
+func (mr *MockMessageRepositoryMockRecorder) ListUpTo(conversationID, createdAt, id, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUpTo", reflect.TypeOf((*MockMessageRepository)(nil).ListUpTo), conversationID, createdAt, id, limit)
+}
+
+// MarkAsRead mocks base method.
+func (m *MockMessageRepository) MarkAsRead(conversationID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAsRead", conversationID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAsRead indicates an expected call of MarkAsRead.
+func (mr *MockMessageRepositoryMockRecorder) MarkAsRead(conversationID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsRead", reflect.TypeOf((*MockMessageRepository)(nil).MarkAsRead), conversationID, userID)
+}
+
+// StreamByConversation mocks base method.
+func (m *MockMessageRepository) StreamByConversation(conversationID uuid.UUID, fn func([]models.Message) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamByConversation", conversationID, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamByConversation indicates an expected call of StreamByConversation.
+func (mr *MockMessageRepositoryMockRecorder) StreamByConversation(conversationID, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamByConversation", reflect.TypeOf((*MockMessageRepository)(nil).StreamByConversation), conversationID, fn)
+}
+
+// UpdateLiveLocation mocks base method.
+func (m *MockMessageRepository) UpdateLiveLocation(id, conversationID, senderID uuid.UUID, latitude, longitude float64, now time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLiveLocation", id, conversationID, senderID, latitude, longitude, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLiveLocation indicates an expected call of UpdateLiveLocation.
+func (mr *MockMessageRepositoryMockRecorder) UpdateLiveLocation(id, conversationID, senderID, latitude, longitude, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLiveLocation", reflect.TypeOf((*MockMessageRepository)(nil).UpdateLiveLocation), id, conversationID, senderID, latitude, longitude, now)
+}
+
+// MockSubscriptionRepository is a mock of SubscriptionRepository interface.
+type MockSubscriptionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionRepositoryMockRecorder
+}
+
+// MockSubscriptionRepositoryMockRecorder is the mock recorder for MockSubscriptionRepository.
+type MockSubscriptionRepositoryMockRecorder struct {
+	mock *MockSubscriptionRepository
+}
+
+// NewMockSubscriptionRepository creates a new mock instance.
+func NewMockSubscriptionRepository(ctrl *gomock.Controller) *MockSubscriptionRepository {
+	mock := &MockSubscriptionRepository{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptionRepository) EXPECT() *MockSubscriptionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockSubscriptionRepository) Cancel(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockSubscriptionRepositoryMockRecorder) Cancel(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockSubscriptionRepository)(nil).Cancel), id)
+}
+
+// Create mocks base method.
+func (m *MockSubscriptionRepository) Create(sub *models.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", sub)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSubscriptionRepositoryMockRecorder) Create(sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSubscriptionRepository)(nil).Create), sub)
+}
+
+// GetByProviderID mocks base method.
+func (m *MockSubscriptionRepository) GetByProviderID(providerID string) (*models.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByProviderID", providerID)
+	ret0, _ := ret[0].(*models.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByProviderID indicates an expected call of GetByProviderID.
+func (mr *MockSubscriptionRepositoryMockRecorder) GetByProviderID(providerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProviderID", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetByProviderID), providerID)
+}
+
+// GetByUserID mocks base method.
+func (m *MockSubscriptionRepository) GetByUserID(userID uuid.UUID) (*models.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", userID)
+	ret0, _ := ret[0].(*models.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockSubscriptionRepositoryMockRecorder) GetByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetByUserID), userID)
+}
+
+// Update mocks base method.
+func (m *MockSubscriptionRepository) Update(sub *models.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", sub)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSubscriptionRepositoryMockRecorder) Update(sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSubscriptionRepository)(nil).Update), sub)
+}
+
+// MockDeviceTokenRepository is a mock of DeviceTokenRepository interface.
+type MockDeviceTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceTokenRepositoryMockRecorder
+}
+
+// MockDeviceTokenRepositoryMockRecorder is the mock recorder for MockDeviceTokenRepository.
+type MockDeviceTokenRepositoryMockRecorder struct {
+	mock *MockDeviceTokenRepository
+}
+
+// NewMockDeviceTokenRepository creates a new mock instance.
+func NewMockDeviceTokenRepository(ctrl *gomock.Controller) *MockDeviceTokenRepository {
+	mock := &MockDeviceTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockDeviceTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceTokenRepository) EXPECT() *MockDeviceTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDeviceTokenRepository) Create(token *models.DeviceToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeviceTokenRepositoryMockRecorder) Create(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeviceTokenRepository)(nil).Create), token)
+}
+
+// Deactivate mocks base method.
+func (m *MockDeviceTokenRepository) Deactivate(token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deactivate", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Deactivate indicates an expected call of Deactivate.
+func (mr *MockDeviceTokenRepositoryMockRecorder) Deactivate(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deactivate", reflect.TypeOf((*MockDeviceTokenRepository)(nil).Deactivate), token)
+}
+
+// DeactivateAllForUser mocks base method.
+func (m *MockDeviceTokenRepository) DeactivateAllForUser(userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateAllForUser", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateAllForUser indicates an expected call of DeactivateAllForUser.
+func (mr *MockDeviceTokenRepositoryMockRecorder) DeactivateAllForUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateAllForUser", reflect.TypeOf((*MockDeviceTokenRepository)(nil).DeactivateAllForUser), userID)
+}
+
+// DeactivateForSession mocks base method.
+func (m *MockDeviceTokenRepository) DeactivateForSession(sessionID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateForSession", sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateForSession indicates an expected call of DeactivateForSession.
+func (mr *MockDeviceTokenRepositoryMockRecorder) DeactivateForSession(sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateForSession", reflect.TypeOf((*MockDeviceTokenRepository)(nil).DeactivateForSession), sessionID)
+}
+
+// GetByUserID mocks base method.
+func (m *MockDeviceTokenRepository) GetByUserID(userID uuid.UUID) ([]models.DeviceToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", userID)
+	ret0, _ := ret[0].([]models.DeviceToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockDeviceTokenRepositoryMockRecorder) GetByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockDeviceTokenRepository)(nil).GetByUserID), userID)
+}
+
+// MockSessionRepository is a mock of SessionRepository interface.
+type MockSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionRepositoryMockRecorder
+}
+
+// MockSessionRepositoryMockRecorder is the mock recorder for MockSessionRepository.
+type MockSessionRepositoryMockRecorder struct {
+	mock *MockSessionRepository
+}
+
+// NewMockSessionRepository creates a new mock instance.
+func NewMockSessionRepository(ctrl *gomock.Controller) *MockSessionRepository {
+	mock := &MockSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionRepository) EXPECT() *MockSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSessionRepository) Create(session *models.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSessionRepositoryMockRecorder) Create(session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionRepository)(nil).Create), session)
+}
+
+// GetByID mocks base method.
+func (m *MockSessionRepository) GetByID(id uuid.UUID) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockSessionRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSessionRepository)(nil).GetByID), id)
+}
+
+// ListActiveForUser mocks base method.
+func (m *MockSessionRepository) ListActiveForUser(userID uuid.UUID) ([]models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveForUser", userID)
+	ret0, _ := ret[0].([]models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveForUser indicates an expected call of ListActiveForUser.
+func (mr *MockSessionRepositoryMockRecorder) ListActiveForUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveForUser", reflect.TypeOf((*MockSessionRepository)(nil).ListActiveForUser), userID)
+}
+
+// Revoke mocks base method.
+func (m *MockSessionRepository) Revoke(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockSessionRepositoryMockRecorder) Revoke(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockSessionRepository)(nil).Revoke), id)
+}
+
+// RevokeAllForUser mocks base method.
+func (m *MockSessionRepository) RevokeAllForUser(userID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllForUser", userID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAllForUser indicates an expected call of RevokeAllForUser.
+func (mr *MockSessionRepositoryMockRecorder) RevokeAllForUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllForUser", reflect.TypeOf((*MockSessionRepository)(nil).RevokeAllForUser), userID)
+}
+
+// Rotate mocks base method.
+func (m *MockSessionRepository) Rotate(id uuid.UUID, oldHash, newHash, ip string, expiresAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", id, oldHash, newHash, ip, expiresAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockSessionRepositoryMockRecorder) Rotate(id, oldHash, newHash, ip, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockSessionRepository)(nil).Rotate), id, oldHash, newHash, ip, expiresAt)
+}
+
+// MockContactChangeRepository is a mock of ContactChangeRepository interface.
+type MockContactChangeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockContactChangeRepositoryMockRecorder
+}
+
+// MockContactChangeRepositoryMockRecorder is the mock recorder for MockContactChangeRepository.
+type MockContactChangeRepositoryMockRecorder struct {
+	mock *MockContactChangeRepository
+}
+
+// NewMockContactChangeRepository creates a new mock instance.
+func NewMockContactChangeRepository(ctrl *gomock.Controller) *MockContactChangeRepository {
+	mock := &MockContactChangeRepository{ctrl: ctrl}
+	mock.recorder = &MockContactChangeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContactChangeRepository) EXPECT() *MockContactChangeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockContactChangeRepository) Delete(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockContactChangeRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockContactChangeRepository)(nil).Delete), id)
+}
+
+// Get mocks base method.
+func (m *MockContactChangeRepository) Get(userID uuid.UUID, channel string) (*models.ContactChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", userID, channel)
+	ret0, _ := ret[0].(*models.ContactChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockContactChangeRepositoryMockRecorder) Get(userID, channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockContactChangeRepository)(nil).Get), userID, channel)
+}
+
+// IncrementAttempts mocks base method.
+func (m *MockContactChangeRepository) IncrementAttempts(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementAttempts", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementAttempts indicates an expected call of IncrementAttempts.
+func (mr *MockContactChangeRepositoryMockRecorder) IncrementAttempts(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementAttempts", reflect.TypeOf((*MockContactChangeRepository)(nil).IncrementAttempts), id)
+}
+
+// Save mocks base method.
+func (m *MockContactChangeRepository) Save(change *models.ContactChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockContactChangeRepositoryMockRecorder) Save(change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockContactChangeRepository)(nil).Save), change)
+}
+
+// MockAuditLogRepository is a mock of AuditLogRepository interface.
+type MockAuditLogRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditLogRepositoryMockRecorder
+}
+
+// MockAuditLogRepositoryMockRecorder is the mock recorder for MockAuditLogRepository.
+type MockAuditLogRepositoryMockRecorder struct {
+	mock *MockAuditLogRepository
+}
+
+// NewMockAuditLogRepository creates a new mock instance.
+func NewMockAuditLogRepository(ctrl *gomock.Controller) *MockAuditLogRepository {
+	mock := &MockAuditLogRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditLogRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditLogRepository) EXPECT() *MockAuditLogRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditLogRepository) Create(log *models.AuditLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", log)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditLogRepositoryMockRecorder) Create(log interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditLogRepository)(nil).Create), log)
+}
+
+// List mocks base method.
+func (m *MockAuditLogRepository) List(page, limit int, adminID *uuid.UUID, action string) ([]models.AuditLog, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", page, limit, adminID, action)
+	ret0, _ := ret[0].([]models.AuditLog)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockAuditLogRepositoryMockRecorder) List(page, limit, adminID, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAuditLogRepository)(nil).List), page, limit, adminID, action)
+}
+
+// MockNotificationRepository is a mock of NotificationRepository interface.
+type MockNotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationRepositoryMockRecorder
+}
+
+// MockNotificationRepositoryMockRecorder is the mock recorder for MockNotificationRepository.
+type MockNotificationRepositoryMockRecorder struct {
+	mock *MockNotificationRepository
+}
+
+// NewMockNotificationRepository creates a new mock instance.
+func NewMockNotificationRepository(ctrl *gomock.Controller) *MockNotificationRepository {
+	mock := &MockNotificationRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationRepository) EXPECT() *MockNotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockNotificationRepository) Create(notif *models.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", notif)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockNotificationRepositoryMockRecorder) Create(notif interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNotificationRepository)(nil).Create), notif)
+}
+
+// DigestRecipients mocks base method.
+func (m *MockNotificationRepository) DigestRecipients(since, inactiveSince time.Time) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DigestRecipients", since, inactiveSince)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DigestRecipients indicates an expected call of DigestRecipients.
+func (mr *MockNotificationRepositoryMockRecorder) DigestRecipients(since, inactiveSince interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DigestRecipients", reflect.TypeOf((*MockNotificationRepository)(nil).DigestRecipients), since, inactiveSince)
+}
+
+// GetUnreadCount mocks base method.
+func (m *MockNotificationRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnreadCount", userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnreadCount indicates an expected call of GetUnreadCount.
+func (mr *MockNotificationRepositoryMockRecorder) GetUnreadCount(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnreadCount", reflect.TypeOf((*MockNotificationRepository)(nil).GetUnreadCount), userID)
+}
+
+// ListByUser mocks base method.
+func (m *MockNotificationRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.Notification, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", userID, page, limit)
+	ret0, _ := ret[0].([]models.Notification)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockNotificationRepositoryMockRecorder) ListByUser(userID, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockNotificationRepository)(nil).ListByUser), userID, page, limit)
+}
+
+// ListUnreadSince mocks base method.
+func (m *MockNotificationRepository) ListUnreadSince(userID uuid.UUID, since time.Time, limit int) ([]models.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnreadSince", userID, since, limit)
+	ret0, _ := ret[0].([]models.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnreadSince indicates an expected call of ListUnreadSince.
+func (mr *MockNotificationRepositoryMockRecorder) ListUnreadSince(userID, since, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnreadSince", reflect.TypeOf((*MockNotificationRepository)(nil).ListUnreadSince), userID, since, limit)
+}
+
+// MarkAllAsRead mocks base method.
+func (m *MockNotificationRepository) MarkAllAsRead(userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllAsRead", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAllAsRead indicates an expected call of MarkAllAsRead.
+func (mr *MockNotificationRepositoryMockRecorder) MarkAllAsRead(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllAsRead", reflect.TypeOf((*MockNotificationRepository)(nil).MarkAllAsRead), userID)
+}
+
+// MarkAsRead mocks base method.
+func (m *MockNotificationRepository) MarkAsRead(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAsRead", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAsRead indicates an expected call of MarkAsRead.
+func (mr *MockNotificationRepositoryMockRecorder) MarkAsRead(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsRead", reflect.TypeOf((*MockNotificationRepository)(nil).MarkAsRead), id)
+}
+
+// MockSupportRepository is a mock of SupportRepository interface.
+type MockSupportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSupportRepositoryMockRecorder
+}
+
+// MockSupportRepositoryMockRecorder is the mock recorder for MockSupportRepository.
+type MockSupportRepositoryMockRecorder struct {
+	mock *MockSupportRepository
+}
+
+// NewMockSupportRepository creates a new mock instance.
+func NewMockSupportRepository(ctrl *gomock.Controller) *MockSupportRepository {
+	mock := &MockSupportRepository{ctrl: ctrl}
+	mock.recorder = &MockSupportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSupportRepository) EXPECT() *MockSupportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateCannedResponse mocks base method.
+func (m *MockSupportRepository) CreateCannedResponse(response *models.CannedResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCannedResponse", response)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCannedResponse indicates an expected call of CreateCannedResponse.
+func (mr *MockSupportRepositoryMockRecorder) CreateCannedResponse(response interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCannedResponse", reflect.TypeOf((*MockSupportRepository)(nil).CreateCannedResponse), response)
+}
+
+// CreateMessage mocks base method.
+func (m *MockSupportRepository) CreateMessage(message *models.SupportMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessage", message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockSupportRepositoryMockRecorder) CreateMessage(message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockSupportRepository)(nil).CreateMessage), message)
+}
+
+// CreateTicket mocks base method.
+func (m *MockSupportRepository) CreateTicket(ticket *models.SupportTicket) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTicket", ticket)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTicket indicates an expected call of CreateTicket.
+func (mr *MockSupportRepositoryMockRecorder) CreateTicket(ticket interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTicket", reflect.TypeOf((*MockSupportRepository)(nil).CreateTicket), ticket)
+}
+
+// DeleteCannedResponse mocks base method.
+func (m *MockSupportRepository) DeleteCannedResponse(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCannedResponse", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCannedResponse indicates an expected call of DeleteCannedResponse.
+func (mr *MockSupportRepositoryMockRecorder) DeleteCannedResponse(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCannedResponse", reflect.TypeOf((*MockSupportRepository)(nil).DeleteCannedResponse), id)
+}
+
+// EscalateTicket mocks base method.
+func (m *MockSupportRepository) EscalateTicket(ticket *models.SupportTicket, fromLevel int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EscalateTicket", ticket, fromLevel)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EscalateTicket indicates an expected call of EscalateTicket.
+func (mr *MockSupportRepositoryMockRecorder) EscalateTicket(ticket, fromLevel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateTicket", reflect.TypeOf((*MockSupportRepository)(nil).EscalateTicket), ticket, fromLevel)
+}
+
+// GetCannedResponse mocks base method.
+func (m *MockSupportRepository) GetCannedResponse(id uuid.UUID) (*models.CannedResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCannedResponse", id)
+	ret0, _ := ret[0].(*models.CannedResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCannedResponse indicates an expected call of GetCannedResponse.
+func (mr *MockSupportRepositoryMockRecorder) GetCannedResponse(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCannedResponse", reflect.TypeOf((*MockSupportRepository)(nil).GetCannedResponse), id)
+}
+
+// GetStats mocks base method.
+func (m *MockSupportRepository) GetStats() (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats")
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockSupportRepositoryMockRecorder) GetStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockSupportRepository)(nil).GetStats))
+}
+
+// GetTicket mocks base method.
+func (m *MockSupportRepository) GetTicket(id uuid.UUID) (*models.SupportTicket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicket", id)
+	ret0, _ := ret[0].(*models.SupportTicket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicket indicates an expected call of GetTicket.
+func (mr *MockSupportRepositoryMockRecorder) GetTicket(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicket", reflect.TypeOf((*MockSupportRepository)(nil).GetTicket), id)
+}
+
+// GetTicketForOwner mocks base method.
+func (m *MockSupportRepository) GetTicketForOwner(id uuid.UUID) (*models.SupportTicket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicketForOwner", id)
+	ret0, _ := ret[0].(*models.SupportTicket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicketForOwner indicates an expected call of GetTicketForOwner.
+func (mr *MockSupportRepositoryMockRecorder) GetTicketForOwner(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketForOwner", reflect.TypeOf((*MockSupportRepository)(nil).GetTicketForOwner), id)
+}
+
+// GetTicketMessages mocks base method.
+func (m *MockSupportRepository) GetTicketMessages(ticketID uuid.UUID) ([]models.SupportMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicketMessages", ticketID)
+	ret0, _ := ret[0].([]models.SupportMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicketMessages indicates an expected call of GetTicketMessages.
+func (mr *MockSupportRepositoryMockRecorder) GetTicketMessages(ticketID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketMessages", reflect.TypeOf((*MockSupportRepository)(nil).GetTicketMessages), ticketID)
+}
+
+// IncrementCannedResponseUsage mocks base method.
+func (m *MockSupportRepository) IncrementCannedResponseUsage(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementCannedResponseUsage", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementCannedResponseUsage indicates an expected call of IncrementCannedResponseUsage.
+func (mr *MockSupportRepositoryMockRecorder) IncrementCannedResponseUsage(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementCannedResponseUsage", reflect.TypeOf((*MockSupportRepository)(nil).IncrementCannedResponseUsage), id)
+}
+
+// ListByUser mocks base method.
+func (m *MockSupportRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.SupportTicket, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", userID, page, limit)
+	ret0, _ := ret[0].([]models.SupportTicket)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockSupportRepositoryMockRecorder) ListByUser(userID, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockSupportRepository)(nil).ListByUser), userID, page, limit)
+}
+
+// ListCannedResponses mocks base method.
+func (m *MockSupportRepository) ListCannedResponses(category string) ([]models.CannedResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCannedResponses", category)
+	ret0, _ := ret[0].([]models.CannedResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCannedResponses indicates an expected call of ListCannedResponses.
+func (mr *MockSupportRepositoryMockRecorder) ListCannedResponses(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCannedResponses", reflect.TypeOf((*MockSupportRepository)(nil).ListCannedResponses), category)
+}
+
+// ListOverdueTickets mocks base method.
+func (m *MockSupportRepository) ListOverdueTickets(now time.Time, limit int) ([]models.SupportTicket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverdueTickets", now, limit)
+	ret0, _ := ret[0].([]models.SupportTicket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverdueTickets indicates an expected call of ListOverdueTickets.
+func (mr *MockSupportRepositoryMockRecorder) ListOverdueTickets(now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverdueTickets", reflect.TypeOf((*MockSupportRepository)(nil).ListOverdueTickets), now, limit)
+}
+
+// ListTickets mocks base method.
+func (m *MockSupportRepository) ListTickets(page, limit int, status, priority string) ([]models.SupportTicket, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTickets", page, limit, status, priority)
+	ret0, _ := ret[0].([]models.SupportTicket)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTickets indicates an expected call of ListTickets.
+func (mr *MockSupportRepositoryMockRecorder) ListTickets(page, limit, status, priority interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTickets", reflect.TypeOf((*MockSupportRepository)(nil).ListTickets), page, limit, status, priority)
+}
+
+// UpdateCannedResponse mocks base method.
+func (m *MockSupportRepository) UpdateCannedResponse(response *models.CannedResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCannedResponse", response)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCannedResponse indicates an expected call of UpdateCannedResponse.
+func (mr *MockSupportRepositoryMockRecorder) UpdateCannedResponse(response interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCannedResponse", reflect.TypeOf((*MockSupportRepository)(nil).UpdateCannedResponse), response)
+}
+
+// UpdateTicket mocks base method.
+func (m *MockSupportRepository) UpdateTicket(ticket *models.SupportTicket) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTicket", ticket)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTicket indicates an expected call of UpdateTicket.
+func (mr *MockSupportRepositoryMockRecorder) UpdateTicket(ticket interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTicket", reflect.TypeOf((*MockSupportRepository)(nil).UpdateTicket), ticket)
+}
+
+// MockAnnouncementRepository is a mock of AnnouncementRepository interface.
+type MockAnnouncementRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnnouncementRepositoryMockRecorder
+}
+
+// MockAnnouncementRepositoryMockRecorder is the mock recorder for MockAnnouncementRepository.
+type MockAnnouncementRepositoryMockRecorder struct {
+	mock *MockAnnouncementRepository
+}
+
+// NewMockAnnouncementRepository creates a new mock instance.
+func NewMockAnnouncementRepository(ctrl *gomock.Controller) *MockAnnouncementRepository {
+	mock := &MockAnnouncementRepository{ctrl: ctrl}
+	mock.recorder = &MockAnnouncementRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnnouncementRepository) EXPECT() *MockAnnouncementRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAnnouncementRepository) Create(announcement *models.Announcement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", announcement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAnnouncementRepositoryMockRecorder) Create(announcement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAnnouncementRepository)(nil).Create), announcement)
+}
+
+// Delete mocks base method.
+func (m *MockAnnouncementRepository) Delete(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAnnouncementRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAnnouncementRepository)(nil).Delete), id)
+}
+
+// Dismiss mocks base method.
+func (m *MockAnnouncementRepository) Dismiss(announcementID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dismiss", announcementID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Dismiss indicates an expected call of Dismiss.
+func (mr *MockAnnouncementRepositoryMockRecorder) Dismiss(announcementID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dismiss", reflect.TypeOf((*MockAnnouncementRepository)(nil).Dismiss), announcementID, userID)
+}
+
+// GetByID mocks base method.
+func (m *MockAnnouncementRepository) GetByID(id uuid.UUID) (*models.Announcement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Announcement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAnnouncementRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAnnouncementRepository)(nil).GetByID), id)
+}
+
+// List mocks base method.
+func (m *MockAnnouncementRepository) List(page, limit int) ([]models.Announcement, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", page, limit)
+	ret0, _ := ret[0].([]models.Announcement)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockAnnouncementRepositoryMockRecorder) List(page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAnnouncementRepository)(nil).List), page, limit)
+}
+
+// ListActive mocks base method.
+func (m *MockAnnouncementRepository) ListActive(userID uuid.UUID, audiences []string, now time.Time) ([]models.Announcement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActive", userID, audiences, now)
+	ret0, _ := ret[0].([]models.Announcement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActive indicates an expected call of ListActive.
+func (mr *MockAnnouncementRepositoryMockRecorder) ListActive(userID, audiences, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActive", reflect.TypeOf((*MockAnnouncementRepository)(nil).ListActive), userID, audiences, now)
+}
+
+// Update mocks base method.
+func (m *MockAnnouncementRepository) Update(announcement *models.Announcement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", announcement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAnnouncementRepositoryMockRecorder) Update(announcement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAnnouncementRepository)(nil).Update), announcement)
+}
+
+// MockDocumentRuleRepository is a mock of DocumentRuleRepository interface.
+type MockDocumentRuleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDocumentRuleRepositoryMockRecorder
+}
+
+// MockDocumentRuleRepositoryMockRecorder is the mock recorder for MockDocumentRuleRepository.
+type MockDocumentRuleRepositoryMockRecorder struct {
+	mock *MockDocumentRuleRepository
+}
+
+// NewMockDocumentRuleRepository creates a new mock instance.
+func NewMockDocumentRuleRepository(ctrl *gomock.Controller) *MockDocumentRuleRepository {
+	mock := &MockDocumentRuleRepository{ctrl: ctrl}
+	mock.recorder = &MockDocumentRuleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDocumentRuleRepository) EXPECT() *MockDocumentRuleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListByCategories mocks base method.
+func (m *MockDocumentRuleRepository) ListByCategories(categoryIDs []uuid.UUID) ([]models.CategoryDocumentRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCategories", categoryIDs)
+	ret0, _ := ret[0].([]models.CategoryDocumentRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByCategories indicates an expected call of ListByCategories.
+func (mr *MockDocumentRuleRepositoryMockRecorder) ListByCategories(categoryIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCategories", reflect.TypeOf((*MockDocumentRuleRepository)(nil).ListByCategories), categoryIDs)
+}
+
+// Replace mocks base method.
+func (m *MockDocumentRuleRepository) Replace(categoryID uuid.UUID, rules []models.CategoryDocumentRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", categoryID, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockDocumentRuleRepositoryMockRecorder) Replace(categoryID, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockDocumentRuleRepository)(nil).Replace), categoryID, rules)
+}
+
+// MockApplicationFormRepository is a mock of ApplicationFormRepository interface.
+type MockApplicationFormRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockApplicationFormRepositoryMockRecorder
+}
+
+// MockApplicationFormRepositoryMockRecorder is the mock recorder for MockApplicationFormRepository.
+type MockApplicationFormRepositoryMockRecorder struct {
+	mock *MockApplicationFormRepository
+}
+
+// NewMockApplicationFormRepository creates a new mock instance.
+func NewMockApplicationFormRepository(ctrl *gomock.Controller) *MockApplicationFormRepository {
+	mock := &MockApplicationFormRepository{ctrl: ctrl}
+	mock.recorder = &MockApplicationFormRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApplicationFormRepository) EXPECT() *MockApplicationFormRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAnswers mocks base method.
+func (m *MockApplicationFormRepository) CreateAnswers(answers []models.ApplicationAnswer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAnswers", answers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAnswers indicates an expected call of CreateAnswers.
+func (mr *MockApplicationFormRepositoryMockRecorder) CreateAnswers(answers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAnswers", reflect.TypeOf((*MockApplicationFormRepository)(nil).CreateAnswers), answers)
+}
+
+// CreateQuestion mocks base method.
+func (m *MockApplicationFormRepository) CreateQuestion(question *models.ApplicationQuestion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateQuestion", question)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateQuestion indicates an expected call of CreateQuestion.
+func (mr *MockApplicationFormRepositoryMockRecorder) CreateQuestion(question interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateQuestion", reflect.TypeOf((*MockApplicationFormRepository)(nil).CreateQuestion), question)
+}
+
+// DeleteQuestion mocks base method.
+func (m *MockApplicationFormRepository) DeleteQuestion(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteQuestion", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteQuestion indicates an expected call of DeleteQuestion.
+func (mr *MockApplicationFormRepositoryMockRecorder) DeleteQuestion(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQuestion", reflect.TypeOf((*MockApplicationFormRepository)(nil).DeleteQuestion), id)
+}
+
+// GetQuestion mocks base method.
+func (m *MockApplicationFormRepository) GetQuestion(id uuid.UUID) (*models.ApplicationQuestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuestion", id)
+	ret0, _ := ret[0].(*models.ApplicationQuestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuestion indicates an expected call of GetQuestion.
+func (mr *MockApplicationFormRepositoryMockRecorder) GetQuestion(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuestion", reflect.TypeOf((*MockApplicationFormRepository)(nil).GetQuestion), id)
+}
+
+// ListAnswers mocks base method.
+func (m *MockApplicationFormRepository) ListAnswers(profileID uuid.UUID) ([]models.ApplicationAnswer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAnswers", profileID)
+	ret0, _ := ret[0].([]models.ApplicationAnswer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAnswers indicates an expected call of ListAnswers.
+func (mr *MockApplicationFormRepositoryMockRecorder) ListAnswers(profileID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAnswers", reflect.TypeOf((*MockApplicationFormRepository)(nil).ListAnswers), profileID)
+}
+
+// ListQuestions mocks base method.
+func (m *MockApplicationFormRepository) ListQuestions(categoryIDs []uuid.UUID, activeOnly bool) ([]models.ApplicationQuestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQuestions", categoryIDs, activeOnly)
+	ret0, _ := ret[0].([]models.ApplicationQuestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQuestions indicates an expected call of ListQuestions.
+func (mr *MockApplicationFormRepositoryMockRecorder) ListQuestions(categoryIDs, activeOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQuestions", reflect.TypeOf((*MockApplicationFormRepository)(nil).ListQuestions), categoryIDs, activeOnly)
+}
+
+// UpdateQuestion mocks base method.
+func (m *MockApplicationFormRepository) UpdateQuestion(question *models.ApplicationQuestion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateQuestion", question)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateQuestion indicates an expected call of UpdateQuestion.
+func (mr *MockApplicationFormRepositoryMockRecorder) UpdateQuestion(question interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQuestion", reflect.TypeOf((*MockApplicationFormRepository)(nil).UpdateQuestion), question)
+}
+
+// MockLegalDocumentRepository is a mock of LegalDocumentRepository interface.
+type MockLegalDocumentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLegalDocumentRepositoryMockRecorder
+}
+
+// MockLegalDocumentRepositoryMockRecorder is the mock recorder for MockLegalDocumentRepository.
+type MockLegalDocumentRepositoryMockRecorder struct {
+	mock *MockLegalDocumentRepository
+}
+
+// NewMockLegalDocumentRepository creates a new mock instance.
+func NewMockLegalDocumentRepository(ctrl *gomock.Controller) *MockLegalDocumentRepository {
+	mock := &MockLegalDocumentRepository{ctrl: ctrl}
+	mock.recorder = &MockLegalDocumentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLegalDocumentRepository) EXPECT() *MockLegalDocumentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockLegalDocumentRepository) GetByID(id uuid.UUID) (*models.LegalDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.LegalDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockLegalDocumentRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockLegalDocumentRepository)(nil).GetByID), id)
+}
+
+// Kinds mocks base method.
+func (m *MockLegalDocumentRepository) Kinds(locale string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Kinds", locale)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Kinds indicates an expected call of Kinds.
+func (mr *MockLegalDocumentRepositoryMockRecorder) Kinds(locale interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Kinds", reflect.TypeOf((*MockLegalDocumentRepository)(nil).Kinds), locale)
+}
+
+// Latest mocks base method.
+func (m *MockLegalDocumentRepository) Latest(kind, locale string) (*models.LegalDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Latest", kind, locale)
+	ret0, _ := ret[0].(*models.LegalDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Latest indicates an expected call of Latest.
+func (mr *MockLegalDocumentRepositoryMockRecorder) Latest(kind, locale interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Latest", reflect.TypeOf((*MockLegalDocumentRepository)(nil).Latest), kind, locale)
+}
+
+// List mocks base method.
+func (m *MockLegalDocumentRepository) List(kind, locale string, page, limit int) ([]models.LegalDocument, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", kind, locale, page, limit)
+	ret0, _ := ret[0].([]models.LegalDocument)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockLegalDocumentRepositoryMockRecorder) List(kind, locale, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockLegalDocumentRepository)(nil).List), kind, locale, page, limit)
+}
+
+// Publish mocks base method.
+func (m *MockLegalDocumentRepository) Publish(document *models.LegalDocument) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", document)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockLegalDocumentRepositoryMockRecorder) Publish(document interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockLegalDocumentRepository)(nil).Publish), document)
+}
+
+// Required mocks base method.
+func (m *MockLegalDocumentRepository) Required(locale string) ([]models.LegalDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Required", locale)
+	ret0, _ := ret[0].([]models.LegalDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Required indicates an expected call of Required.
+func (mr *MockLegalDocumentRepositoryMockRecorder) Required(locale interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Required", reflect.TypeOf((*MockLegalDocumentRepository)(nil).Required), locale)
+}
+
+// MockConsentRepository is a mock of ConsentRepository interface.
+type MockConsentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsentRepositoryMockRecorder
+}
+
+// MockConsentRepositoryMockRecorder is the mock recorder for MockConsentRepository.
+type MockConsentRepositoryMockRecorder struct {
+	mock *MockConsentRepository
+}
+
+// NewMockConsentRepository creates a new mock instance.
+func NewMockConsentRepository(ctrl *gomock.Controller) *MockConsentRepository {
+	mock := &MockConsentRepository{ctrl: ctrl}
+	mock.recorder = &MockConsentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsentRepository) EXPECT() *MockConsentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Accept mocks base method.
+func (m *MockConsentRepository) Accept(consents []models.UserConsent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accept", consents)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Accept indicates an expected call of Accept.
+func (mr *MockConsentRepositoryMockRecorder) Accept(consents interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockConsentRepository)(nil).Accept), consents)
+}
+
+// AcceptedIDs mocks base method.
+func (m *MockConsentRepository) AcceptedIDs(userID uuid.UUID, documentIDs []uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptedIDs", userID, documentIDs)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptedIDs indicates an expected call of AcceptedIDs.
+func (mr *MockConsentRepositoryMockRecorder) AcceptedIDs(userID, documentIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptedIDs", reflect.TypeOf((*MockConsentRepository)(nil).AcceptedIDs), userID, documentIDs)
+}
+
+// ListByUser mocks base method.
+func (m *MockConsentRepository) ListByUser(userID uuid.UUID) ([]models.UserConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", userID)
+	ret0, _ := ret[0].([]models.UserConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockConsentRepositoryMockRecorder) ListByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockConsentRepository)(nil).ListByUser), userID)
+}
+
+// MockFavoriteRepository is a mock of FavoriteRepository interface.
+type MockFavoriteRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFavoriteRepositoryMockRecorder
+}
+
+// MockFavoriteRepositoryMockRecorder is the mock recorder for MockFavoriteRepository.
+type MockFavoriteRepositoryMockRecorder struct {
+	mock *MockFavoriteRepository
+}
+
+// NewMockFavoriteRepository creates a new mock instance.
+func NewMockFavoriteRepository(ctrl *gomock.Controller) *MockFavoriteRepository {
+	mock := &MockFavoriteRepository{ctrl: ctrl}
+	mock.recorder = &MockFavoriteRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFavoriteRepository) EXPECT() *MockFavoriteRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFavoriteRepository) Create(fav *models.Favorite) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", fav)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFavoriteRepositoryMockRecorder) Create(fav interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFavoriteRepository)(nil).Create), fav)
+}
+
+// CreateActivity mocks base method.
+func (m *MockFavoriteRepository) CreateActivity(activity *models.FavoriteActivity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateActivity", activity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateActivity indicates an expected call of CreateActivity.
+func (mr *MockFavoriteRepositoryMockRecorder) CreateActivity(activity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateActivity", reflect.TypeOf((*MockFavoriteRepository)(nil).CreateActivity), activity)
+}
+
+// Delete mocks base method.
+func (m *MockFavoriteRepository) Delete(userID, yandasID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userID, yandasID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFavoriteRepositoryMockRecorder) Delete(userID, yandasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFavoriteRepository)(nil).Delete), userID, yandasID)
+}
+
+// Exists mocks base method.
+func (m *MockFavoriteRepository) Exists(userID, yandasID uuid.UUID) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", userID, yandasID)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockFavoriteRepositoryMockRecorder) Exists(userID, yandasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockFavoriteRepository)(nil).Exists), userID, yandasID)
+}
+
+// GetActivity mocks base method.
+func (m *MockFavoriteRepository) GetActivity(id uuid.UUID) (*models.FavoriteActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActivity", id)
+	ret0, _ := ret[0].(*models.FavoriteActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActivity indicates an expected call of GetActivity.
+func (mr *MockFavoriteRepositoryMockRecorder) GetActivity(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivity", reflect.TypeOf((*MockFavoriteRepository)(nil).GetActivity), id)
+}
+
+// GetUserIDsByYandas mocks base method.
+func (m *MockFavoriteRepository) GetUserIDsByYandas(yandasID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIDsByYandas", yandasID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIDsByYandas indicates an expected call of GetUserIDsByYandas.
+func (mr *MockFavoriteRepositoryMockRecorder) GetUserIDsByYandas(yandasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIDsByYandas", reflect.TypeOf((*MockFavoriteRepository)(nil).GetUserIDsByYandas), yandasID)
+}
+
+// GetYandasIDs mocks base method.
+func (m *MockFavoriteRepository) GetYandasIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetYandasIDs", userID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetYandasIDs indicates an expected call of GetYandasIDs.
+func (mr *MockFavoriteRepositoryMockRecorder) GetYandasIDs(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetYandasIDs", reflect.TypeOf((*MockFavoriteRepository)(nil).GetYandasIDs), userID)
+}
+
+// LastActivity mocks base method.
+func (m *MockFavoriteRepository) LastActivity(yandasID uuid.UUID, activityType string) (*models.FavoriteActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastActivity", yandasID, activityType)
+	ret0, _ := ret[0].(*models.FavoriteActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastActivity indicates an expected call of LastActivity.
+func (mr *MockFavoriteRepositoryMockRecorder) LastActivity(yandasID, activityType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastActivity", reflect.TypeOf((*MockFavoriteRepository)(nil).LastActivity), yandasID, activityType)
+}
+
+// ListByUser mocks base method.
+func (m *MockFavoriteRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.Favorite, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", userID, page, limit)
+	ret0, _ := ret[0].([]models.Favorite)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockFavoriteRepositoryMockRecorder) ListByUser(userID, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockFavoriteRepository)(nil).ListByUser), userID, page, limit)
+}
+
+// ListFeed mocks base method.
+func (m *MockFavoriteRepository) ListFeed(userID uuid.UUID, since time.Time, page, limit int) ([]models.FavoriteActivity, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFeed", userID, since, page, limit)
+	ret0, _ := ret[0].([]models.FavoriteActivity)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListFeed indicates an expected call of ListFeed.
+func (mr *MockFavoriteRepositoryMockRecorder) ListFeed(userID, since, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeed", reflect.TypeOf((*MockFavoriteRepository)(nil).ListFeed), userID, since, page, limit)
+}
+
+// MockNotificationPreferenceRepository is a mock of NotificationPreferenceRepository interface.
+type MockNotificationPreferenceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationPreferenceRepositoryMockRecorder
+}
+
+// MockNotificationPreferenceRepositoryMockRecorder is the mock recorder for MockNotificationPreferenceRepository.
+type MockNotificationPreferenceRepositoryMockRecorder struct {
+	mock *MockNotificationPreferenceRepository
+}
+
+// NewMockNotificationPreferenceRepository creates a new mock instance.
+func NewMockNotificationPreferenceRepository(ctrl *gomock.Controller) *MockNotificationPreferenceRepository {
+	mock := &MockNotificationPreferenceRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationPreferenceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationPreferenceRepository) EXPECT() *MockNotificationPreferenceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByUserAndType mocks base method.
+func (m *MockNotificationPreferenceRepository) GetByUserAndType(userID uuid.UUID, notifType string) (*models.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserAndType", userID, notifType)
+	ret0, _ := ret[0].(*models.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserAndType indicates an expected call of GetByUserAndType.
+func (mr *MockNotificationPreferenceRepositoryMockRecorder) GetByUserAndType(userID, notifType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserAndType", reflect.TypeOf((*MockNotificationPreferenceRepository)(nil).GetByUserAndType), userID, notifType)
+}
+
+// ListByUser mocks base method.
+func (m *MockNotificationPreferenceRepository) ListByUser(userID uuid.UUID) ([]models.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", userID)
+	ret0, _ := ret[0].([]models.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockNotificationPreferenceRepositoryMockRecorder) ListByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockNotificationPreferenceRepository)(nil).ListByUser), userID)
+}
+
+// Upsert mocks base method.
+func (m *MockNotificationPreferenceRepository) Upsert(pref *models.NotificationPreference) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", pref)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockNotificationPreferenceRepositoryMockRecorder) Upsert(pref interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockNotificationPreferenceRepository)(nil).Upsert), pref)
+}
+
+// MockCallLogRepository is a mock of CallLogRepository interface.
+type MockCallLogRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCallLogRepositoryMockRecorder
+}
+
+// MockCallLogRepositoryMockRecorder is the mock recorder for MockCallLogRepository.
+type MockCallLogRepositoryMockRecorder struct {
+	mock *MockCallLogRepository
+}
+
+// NewMockCallLogRepository creates a new mock instance.
+func NewMockCallLogRepository(ctrl *gomock.Controller) *MockCallLogRepository {
+	mock := &MockCallLogRepository{ctrl: ctrl}
+	mock.recorder = &MockCallLogRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCallLogRepository) EXPECT() *MockCallLogRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddParticipant mocks base method.
+func (m *MockCallLogRepository) AddParticipant(callID, userID uuid.UUID, role string) (*models.CallParticipant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddParticipant", callID, userID, role)
+	ret0, _ := ret[0].(*models.CallParticipant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddParticipant indicates an expected call of AddParticipant.
+func (mr *MockCallLogRepositoryMockRecorder) AddParticipant(callID, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddParticipant", reflect.TypeOf((*MockCallLogRepository)(nil).AddParticipant), callID, userID, role)
+}
+
+// GetByID mocks base method.
+func (m *MockCallLogRepository) GetByID(id uuid.UUID) (*models.CallLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.CallLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCallLogRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCallLogRepository)(nil).GetByID), id)
+}
+
+// GetParticipant mocks base method.
+func (m *MockCallLogRepository) GetParticipant(callID, userID uuid.UUID) (*models.CallParticipant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParticipant", callID, userID)
+	ret0, _ := ret[0].(*models.CallParticipant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParticipant indicates an expected call of GetParticipant.
+func (mr *MockCallLogRepositoryMockRecorder) GetParticipant(callID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParticipant", reflect.TypeOf((*MockCallLogRepository)(nil).GetParticipant), callID, userID)
+}
+
+// LeaveParticipant mocks base method.
+func (m *MockCallLogRepository) LeaveParticipant(callID, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaveParticipant", callID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LeaveParticipant indicates an expected call of LeaveParticipant.
+func (mr *MockCallLogRepositoryMockRecorder) LeaveParticipant(callID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveParticipant", reflect.TypeOf((*MockCallLogRepository)(nil).LeaveParticipant), callID, userID)
+}
+
+// ListActive mocks base method.
+func (m *MockCallLogRepository) ListActive(page, limit int) ([]models.CallLog, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActive", page, limit)
+	ret0, _ := ret[0].([]models.CallLog)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListActive indicates an expected call of ListActive.
+func (mr *MockCallLogRepositoryMockRecorder) ListActive(page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActive", reflect.TypeOf((*MockCallLogRepository)(nil).ListActive), page, limit)
+}
+
+// ListByUser mocks base method.
+func (m *MockCallLogRepository) ListByUser(userID uuid.UUID, page, limit int, filter string) ([]models.CallLog, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", userID, page, limit, filter)
+	ret0, _ := ret[0].([]models.CallLog)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockCallLogRepositoryMockRecorder) ListByUser(userID, page, limit, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockCallLogRepository)(nil).ListByUser), userID, page, limit, filter)
+}
+
+// ListParticipants mocks base method.
+func (m *MockCallLogRepository) ListParticipants(callID uuid.UUID) ([]models.CallParticipant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListParticipants", callID)
+	ret0, _ := ret[0].([]models.CallParticipant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListParticipants indicates an expected call of ListParticipants.
+func (mr *MockCallLogRepositoryMockRecorder) ListParticipants(callID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListParticipants", reflect.TypeOf((*MockCallLogRepository)(nil).ListParticipants), callID)
+}
+
+// ListRingExpired mocks base method.
+func (m *MockCallLogRepository) ListRingExpired(now time.Time, limit int) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRingExpired", now, limit)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRingExpired indicates an expected call of ListRingExpired.
+func (mr *MockCallLogRepositoryMockRecorder) ListRingExpired(now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRingExpired", reflect.TypeOf((*MockCallLogRepository)(nil).ListRingExpired), now, limit)
+}
+
+// MarkDelivered mocks base method.
+func (m *MockCallLogRepository) MarkDelivered(id, calleeID uuid.UUID, via string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDelivered", id, calleeID, via)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkDelivered indicates an expected call of MarkDelivered.
+func (mr *MockCallLogRepositoryMockRecorder) MarkDelivered(id, calleeID, via interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDelivered", reflect.TypeOf((*MockCallLogRepository)(nil).MarkDelivered), id, calleeID, via)
+}
+
+// MarkMissed mocks base method.
+func (m *MockCallLogRepository) MarkMissed(id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkMissed", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkMissed indicates an expected call of MarkMissed.
+func (mr *MockCallLogRepositoryMockRecorder) MarkMissed(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMissed", reflect.TypeOf((*MockCallLogRepository)(nil).MarkMissed), id)
+}
+
+// QualityReport mocks base method.
+func (m *MockCallLogRepository) QualityReport(from, to time.Time) ([]repository.CallQualityRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QualityReport", from, to)
+	ret0, _ := ret[0].([]repository.CallQualityRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QualityReport indicates an expected call of QualityReport.
+func (mr *MockCallLogRepositoryMockRecorder) QualityReport(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QualityReport", reflect.TypeOf((*MockCallLogRepository)(nil).QualityReport), from, to)
+}
+
+// SetFeedback mocks base method.
+func (m *MockCallLogRepository) SetFeedback(id uuid.UUID, party string, feedback repository.CallFeedback) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFeedback", id, party, feedback)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetFeedback indicates an expected call of SetFeedback.
+func (mr *MockCallLogRepositoryMockRecorder) SetFeedback(id, party, feedback interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFeedback", reflect.TypeOf((*MockCallLogRepository)(nil).SetFeedback), id, party, feedback)
+}
+
+// SetPushResult mocks base method.
+func (m *MockCallLogRepository) SetPushResult(id uuid.UUID, status string, pushErr *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPushResult", id, status, pushErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPushResult indicates an expected call of SetPushResult.
+func (mr *MockCallLogRepositoryMockRecorder) SetPushResult(id, status, pushErr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPushResult", reflect.TypeOf((*MockCallLogRepository)(nil).SetPushResult), id, status, pushErr)
+}
+
+// MockAddressRepository is a mock of AddressRepository interface.
+type MockAddressRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddressRepositoryMockRecorder
+}
+
+// MockAddressRepositoryMockRecorder is the mock recorder for MockAddressRepository.
+type MockAddressRepositoryMockRecorder struct {
+	mock *MockAddressRepository
+}
+
+// NewMockAddressRepository creates a new mock instance.
+func NewMockAddressRepository(ctrl *gomock.Controller) *MockAddressRepository {
+	mock := &MockAddressRepository{ctrl: ctrl}
+	mock.recorder = &MockAddressRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddressRepository) EXPECT() *MockAddressRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountByUser mocks base method.
+func (m *MockAddressRepository) CountByUser(userID uuid.UUID) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByUser", userID)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// CountByUser indicates an expected call of CountByUser.
+func (mr *MockAddressRepositoryMockRecorder) CountByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByUser", reflect.TypeOf((*MockAddressRepository)(nil).CountByUser), userID)
+}
+
+// Create mocks base method.
+func (m *MockAddressRepository) Create(address *models.Address) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAddressRepositoryMockRecorder) Create(address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAddressRepository)(nil).Create), address)
+}
+
+// Delete mocks base method.
+func (m *MockAddressRepository) Delete(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAddressRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAddressRepository)(nil).Delete), id)
+}
+
+// GetByID mocks base method.
+func (m *MockAddressRepository) GetByID(id uuid.UUID) (*models.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAddressRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAddressRepository)(nil).GetByID), id)
+}
+
+// ListByUser mocks base method.
+func (m *MockAddressRepository) ListByUser(userID uuid.UUID) ([]models.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", userID)
+	ret0, _ := ret[0].([]models.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockAddressRepositoryMockRecorder) ListByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockAddressRepository)(nil).ListByUser), userID)
+}
+
+// SetDefault mocks base method.
+func (m *MockAddressRepository) SetDefault(userID, addressID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefault", userID, addressID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDefault indicates an expected call of SetDefault.
+func (mr *MockAddressRepositoryMockRecorder) SetDefault(userID, addressID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefault", reflect.TypeOf((*MockAddressRepository)(nil).SetDefault), userID, addressID)
+}
+
+// Update mocks base method.
+func (m *MockAddressRepository) Update(address *models.Address) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAddressRepositoryMockRecorder) Update(address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAddressRepository)(nil).Update), address)
+}
+
+// MockRoleRepository is a mock of RoleRepository interface.
+type MockRoleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleRepositoryMockRecorder
+}
+
+// MockRoleRepositoryMockRecorder is the mock recorder for MockRoleRepository.
+type MockRoleRepositoryMockRecorder struct {
+	mock *MockRoleRepository
+}
+
+// NewMockRoleRepository creates a new mock instance.
+func NewMockRoleRepository(ctrl *gomock.Controller) *MockRoleRepository {
+	mock := &MockRoleRepository{ctrl: ctrl}
+	mock.recorder = &MockRoleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleRepository) EXPECT() *MockRoleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByNames mocks base method.
+func (m *MockRoleRepository) GetByNames(names []string) ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByNames", names)
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByNames indicates an expected call of GetByNames.
+func (mr *MockRoleRepositoryMockRecorder) GetByNames(names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByNames", reflect.TypeOf((*MockRoleRepository)(nil).GetByNames), names)
+}
+
+// List mocks base method.
+func (m *MockRoleRepository) List() ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRoleRepositoryMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRoleRepository)(nil).List))
+}
+
+// ListByUser mocks base method.
+func (m *MockRoleRepository) ListByUser(userID uuid.UUID) ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", userID)
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockRoleRepositoryMockRecorder) ListByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockRoleRepository)(nil).ListByUser), userID)
+}
+
+// ReplaceUserRoles mocks base method.
+func (m *MockRoleRepository) ReplaceUserRoles(userID uuid.UUID, roleIDs []uuid.UUID, grantedBy uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceUserRoles", userID, roleIDs, grantedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceUserRoles indicates an expected call of ReplaceUserRoles.
+func (mr *MockRoleRepositoryMockRecorder) ReplaceUserRoles(userID, roleIDs, grantedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceUserRoles", reflect.TypeOf((*MockRoleRepository)(nil).ReplaceUserRoles), userID, roleIDs, grantedBy)
+}
+
+// UpsertSystemRole mocks base method.
+func (m *MockRoleRepository) UpsertSystemRole(role *models.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertSystemRole", role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertSystemRole indicates an expected call of UpsertSystemRole.
+func (mr *MockRoleRepositoryMockRecorder) UpsertSystemRole(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertSystemRole", reflect.TypeOf((*MockRoleRepository)(nil).UpsertSystemRole), role)
+}
+
+// UserHasPermission mocks base method.
+func (m *MockRoleRepository) UserHasPermission(userID uuid.UUID, permission string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserHasPermission", userID, permission)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// UserHasPermission indicates an expected call of UserHasPermission.
+func (mr *MockRoleRepositoryMockRecorder) UserHasPermission(userID, permission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserHasPermission", reflect.TypeOf((*MockRoleRepository)(nil).UserHasPermission), userID, permission)
+}
+
+// MockDocumentScreeningRepository is a mock of DocumentScreeningRepository interface.
+type MockDocumentScreeningRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDocumentScreeningRepositoryMockRecorder
+}
+
+// MockDocumentScreeningRepositoryMockRecorder is the mock recorder for MockDocumentScreeningRepository.
+type MockDocumentScreeningRepositoryMockRecorder struct {
+	mock *MockDocumentScreeningRepository
+}
+
+// NewMockDocumentScreeningRepository creates a new mock instance.
+func NewMockDocumentScreeningRepository(ctrl *gomock.Controller) *MockDocumentScreeningRepository {
+	mock := &MockDocumentScreeningRepository{ctrl: ctrl}
+	mock.recorder = &MockDocumentScreeningRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDocumentScreeningRepository) EXPECT() *MockDocumentScreeningRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByProfileID mocks base method.
+func (m *MockDocumentScreeningRepository) GetByProfileID(profileID uuid.UUID) (*models.DocumentScreening, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByProfileID", profileID)
+	ret0, _ := ret[0].(*models.DocumentScreening)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByProfileID indicates an expected call of GetByProfileID.
+func (mr *MockDocumentScreeningRepositoryMockRecorder) GetByProfileID(profileID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProfileID", reflect.TypeOf((*MockDocumentScreeningRepository)(nil).GetByProfileID), profileID)
+}
+
+// Upsert mocks base method.
+func (m *MockDocumentScreeningRepository) Upsert(screening *models.DocumentScreening) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", screening)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockDocumentScreeningRepositoryMockRecorder) Upsert(screening interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockDocumentScreeningRepository)(nil).Upsert), screening)
+}
+
+// MockDocumentRenewalRepository is a mock of DocumentRenewalRepository interface.
+type MockDocumentRenewalRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDocumentRenewalRepositoryMockRecorder
+}
+
+// MockDocumentRenewalRepositoryMockRecorder is the mock recorder for MockDocumentRenewalRepository.
+type MockDocumentRenewalRepositoryMockRecorder struct {
+	mock *MockDocumentRenewalRepository
+}
+
+// NewMockDocumentRenewalRepository creates a new mock instance.
+func NewMockDocumentRenewalRepository(ctrl *gomock.Controller) *MockDocumentRenewalRepository {
+	mock := &MockDocumentRenewalRepository{ctrl: ctrl}
+	mock.recorder = &MockDocumentRenewalRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDocumentRenewalRepository) EXPECT() *MockDocumentRenewalRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDocumentRenewalRepository) Create(renewal *models.DocumentRenewal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", renewal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDocumentRenewalRepositoryMockRecorder) Create(renewal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDocumentRenewalRepository)(nil).Create), renewal)
+}
+
+// GetByID mocks base method.
+func (m *MockDocumentRenewalRepository) GetByID(id uuid.UUID) (*models.DocumentRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.DocumentRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDocumentRenewalRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDocumentRenewalRepository)(nil).GetByID), id)
+}
+
+// GetOpen mocks base method.
+func (m *MockDocumentRenewalRepository) GetOpen(profileID uuid.UUID, document string) (*models.DocumentRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpen", profileID, document)
+	ret0, _ := ret[0].(*models.DocumentRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpen indicates an expected call of GetOpen.
+func (mr *MockDocumentRenewalRepositoryMockRecorder) GetOpen(profileID, document interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpen", reflect.TypeOf((*MockDocumentRenewalRepository)(nil).GetOpen), profileID, document)
+}
+
+// List mocks base method.
+func (m *MockDocumentRenewalRepository) List(status string, page, limit int) ([]models.DocumentRenewal, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", status, page, limit)
+	ret0, _ := ret[0].([]models.DocumentRenewal)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockDocumentRenewalRepositoryMockRecorder) List(status, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDocumentRenewalRepository)(nil).List), status, page, limit)
+}
+
+// ListOpenByProfile mocks base method.
+func (m *MockDocumentRenewalRepository) ListOpenByProfile(profileID uuid.UUID) ([]models.DocumentRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenByProfile", profileID)
+	ret0, _ := ret[0].([]models.DocumentRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenByProfile indicates an expected call of ListOpenByProfile.
+func (mr *MockDocumentRenewalRepositoryMockRecorder) ListOpenByProfile(profileID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenByProfile", reflect.TypeOf((*MockDocumentRenewalRepository)(nil).ListOpenByProfile), profileID)
+}
+
+// ListPastDeadline mocks base method.
+func (m *MockDocumentRenewalRepository) ListPastDeadline(now time.Time, limit int) ([]models.DocumentRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPastDeadline", now, limit)
+	ret0, _ := ret[0].([]models.DocumentRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPastDeadline indicates an expected call of ListPastDeadline.
+func (mr *MockDocumentRenewalRepositoryMockRecorder) ListPastDeadline(now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPastDeadline", reflect.TypeOf((*MockDocumentRenewalRepository)(nil).ListPastDeadline), now, limit)
+}
+
+// StaleProfiles mocks base method.
+func (m *MockDocumentRenewalRepository) StaleProfiles(document string, uploadedBefore time.Time, limit int) ([]models.YandasProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StaleProfiles", document, uploadedBefore, limit)
+	ret0, _ := ret[0].([]models.YandasProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StaleProfiles indicates an expected call of StaleProfiles.
+func (mr *MockDocumentRenewalRepositoryMockRecorder) StaleProfiles(document, uploadedBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StaleProfiles", reflect.TypeOf((*MockDocumentRenewalRepository)(nil).StaleProfiles), document, uploadedBefore, limit)
+}
+
+// Update mocks base method.
+func (m *MockDocumentRenewalRepository) Update(renewal *models.DocumentRenewal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", renewal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDocumentRenewalRepositoryMockRecorder) Update(renewal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDocumentRenewalRepository)(nil).Update), renewal)
+}
+
+// MockReceiptRepository is a mock of ReceiptRepository interface.
+type MockReceiptRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReceiptRepositoryMockRecorder
+}
+
+// MockReceiptRepositoryMockRecorder is the mock recorder for MockReceiptRepository.
+type MockReceiptRepositoryMockRecorder struct {
+	mock *MockReceiptRepository
+}
+
+// NewMockReceiptRepository creates a new mock instance.
+func NewMockReceiptRepository(ctrl *gomock.Controller) *MockReceiptRepository {
+	mock := &MockReceiptRepository{ctrl: ctrl}
+	mock.recorder = &MockReceiptRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReceiptRepository) EXPECT() *MockReceiptRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockReceiptRepository) Create(receipt *models.Receipt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", receipt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReceiptRepositoryMockRecorder) Create(receipt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReceiptRepository)(nil).Create), receipt)
+}
+
+// GetByOrderID mocks base method.
+func (m *MockReceiptRepository) GetByOrderID(orderID uuid.UUID) (*models.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrderID", orderID)
+	ret0, _ := ret[0].(*models.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOrderID indicates an expected call of GetByOrderID.
+func (mr *MockReceiptRepositoryMockRecorder) GetByOrderID(orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrderID", reflect.TypeOf((*MockReceiptRepository)(nil).GetByOrderID), orderID)
+}
+
+// Update mocks base method.
+func (m *MockReceiptRepository) Update(receipt *models.Receipt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", receipt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockReceiptRepositoryMockRecorder) Update(receipt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockReceiptRepository)(nil).Update), receipt)
+}
+
+// MockPayoutRepository is a mock of PayoutRepository interface.
+type MockPayoutRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPayoutRepositoryMockRecorder
+}
+
+// MockPayoutRepositoryMockRecorder is the mock recorder for MockPayoutRepository.
+type MockPayoutRepositoryMockRecorder struct {
+	mock *MockPayoutRepository
+}
+
+// NewMockPayoutRepository creates a new mock instance.
+func NewMockPayoutRepository(ctrl *gomock.Controller) *MockPayoutRepository {
+	mock := &MockPayoutRepository{ctrl: ctrl}
+	mock.recorder = &MockPayoutRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPayoutRepository) EXPECT() *MockPayoutRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AttachUnpaidEntries mocks base method.
+func (m *MockPayoutRepository) AttachUnpaidEntries(payoutID, yandasID uuid.UUID, currencyCode string) (currency.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachUnpaidEntries", payoutID, yandasID, currencyCode)
+	ret0, _ := ret[0].(currency.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachUnpaidEntries indicates an expected call of AttachUnpaidEntries.
+func (mr *MockPayoutRepositoryMockRecorder) AttachUnpaidEntries(payoutID, yandasID, currencyCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachUnpaidEntries", reflect.TypeOf((*MockPayoutRepository)(nil).AttachUnpaidEntries), payoutID, yandasID, currencyCode)
+}
+
+// Create mocks base method.
+func (m *MockPayoutRepository) Create(payout *models.Payout) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", payout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPayoutRepositoryMockRecorder) Create(payout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPayoutRepository)(nil).Create), payout)
+}
+
+// CreateEntry mocks base method.
+func (m *MockPayoutRepository) CreateEntry(entry *models.PayoutEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntry", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEntry indicates an expected call of CreateEntry.
+func (mr *MockPayoutRepositoryMockRecorder) CreateEntry(entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockPayoutRepository)(nil).CreateEntry), entry)
+}
+
+// GetByID mocks base method.
+func (m *MockPayoutRepository) GetByID(id uuid.UUID) (*models.Payout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Payout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPayoutRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPayoutRepository)(nil).GetByID), id)
+}
+
+// List mocks base method.
+func (m *MockPayoutRepository) List(page, limit int, status string, yandasID *uuid.UUID) ([]models.Payout, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", page, limit, status, yandasID)
+	ret0, _ := ret[0].([]models.Payout)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockPayoutRepositoryMockRecorder) List(page, limit, status, yandasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPayoutRepository)(nil).List), page, limit, status, yandasID)
+}
+
+// ListEntries mocks base method.
+func (m *MockPayoutRepository) ListEntries(yandasID uuid.UUID, page, limit int) ([]models.PayoutEntry, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", yandasID, page, limit)
+	ret0, _ := ret[0].([]models.PayoutEntry)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockPayoutRepositoryMockRecorder) ListEntries(yandasID, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockPayoutRepository)(nil).ListEntries), yandasID, page, limit)
+}
+
+// PaidTotal mocks base method.
+func (m *MockPayoutRepository) PaidTotal(yandasID uuid.UUID, currencyCode string) (currency.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PaidTotal", yandasID, currencyCode)
+	ret0, _ := ret[0].(currency.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PaidTotal indicates an expected call of PaidTotal.
+func (mr *MockPayoutRepositoryMockRecorder) PaidTotal(yandasID, currencyCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PaidTotal", reflect.TypeOf((*MockPayoutRepository)(nil).PaidTotal), yandasID, currencyCode)
+}
+
+// UnpaidBalances mocks base method.
+func (m *MockPayoutRepository) UnpaidBalances(yandasID *uuid.UUID) ([]repository.PayoutBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpaidBalances", yandasID)
+	ret0, _ := ret[0].([]repository.PayoutBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnpaidBalances indicates an expected call of UnpaidBalances.
+func (mr *MockPayoutRepositoryMockRecorder) UnpaidBalances(yandasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpaidBalances", reflect.TypeOf((*MockPayoutRepository)(nil).UnpaidBalances), yandasID)
+}
+
+// Update mocks base method.
+func (m *MockPayoutRepository) Update(payout *models.Payout) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", payout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPayoutRepositoryMockRecorder) Update(payout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPayoutRepository)(nil).Update), payout)
+}
+
+// MockMonitoringRepository is a mock of MonitoringRepository interface.
+type MockMonitoringRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMonitoringRepositoryMockRecorder
+}
+
+// MockMonitoringRepositoryMockRecorder is the mock recorder for MockMonitoringRepository.
+type MockMonitoringRepositoryMockRecorder struct {
+	mock *MockMonitoringRepository
+}
+
+// NewMockMonitoringRepository creates a new mock instance.
+func NewMockMonitoringRepository(ctrl *gomock.Controller) *MockMonitoringRepository {
+	mock := &MockMonitoringRepository{ctrl: ctrl}
+	mock.recorder = &MockMonitoringRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMonitoringRepository) EXPECT() *MockMonitoringRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountRules mocks base method.
+func (m *MockMonitoringRepository) CountRules() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRules")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// CountRules indicates an expected call of CountRules.
+func (mr *MockMonitoringRepositoryMockRecorder) CountRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRules", reflect.TypeOf((*MockMonitoringRepository)(nil).CountRules))
+}
+
+// CreateEvent mocks base method.
+func (m *MockMonitoringRepository) CreateEvent(event *models.AlertEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEvent indicates an expected call of CreateEvent.
+func (mr *MockMonitoringRepositoryMockRecorder) CreateEvent(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockMonitoringRepository)(nil).CreateEvent), event)
+}
+
+// CreateRule mocks base method.
+func (m *MockMonitoringRepository) CreateRule(rule *models.AlertRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRule", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRule indicates an expected call of CreateRule.
+func (mr *MockMonitoringRepositoryMockRecorder) CreateRule(rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRule", reflect.TypeOf((*MockMonitoringRepository)(nil).CreateRule), rule)
+}
+
+// DeleteRule mocks base method.
+func (m *MockMonitoringRepository) DeleteRule(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRule", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRule indicates an expected call of DeleteRule.
+func (mr *MockMonitoringRepositoryMockRecorder) DeleteRule(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRule", reflect.TypeOf((*MockMonitoringRepository)(nil).DeleteRule), id)
+}
+
+// GetRule mocks base method.
+func (m *MockMonitoringRepository) GetRule(id uuid.UUID) (*models.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRule", id)
+	ret0, _ := ret[0].(*models.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRule indicates an expected call of GetRule.
+func (mr *MockMonitoringRepositoryMockRecorder) GetRule(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRule", reflect.TypeOf((*MockMonitoringRepository)(nil).GetRule), id)
+}
+
+// IncrementMetric mocks base method.
+func (m *MockMonitoringRepository) IncrementMetric(metric string, bucket time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementMetric", metric, bucket)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementMetric indicates an expected call of IncrementMetric.
+func (mr *MockMonitoringRepositoryMockRecorder) IncrementMetric(metric, bucket interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementMetric", reflect.TypeOf((*MockMonitoringRepository)(nil).IncrementMetric), metric, bucket)
+}
+
+// ListActiveRules mocks base method.
+func (m *MockMonitoringRepository) ListActiveRules() ([]models.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveRules")
+	ret0, _ := ret[0].([]models.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveRules indicates an expected call of ListActiveRules.
+func (mr *MockMonitoringRepositoryMockRecorder) ListActiveRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveRules", reflect.TypeOf((*MockMonitoringRepository)(nil).ListActiveRules))
+}
+
+// ListEvents mocks base method.
+func (m *MockMonitoringRepository) ListEvents(page, limit int, ruleID *uuid.UUID) ([]models.AlertEvent, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", page, limit, ruleID)
+	ret0, _ := ret[0].([]models.AlertEvent)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockMonitoringRepositoryMockRecorder) ListEvents(page, limit, ruleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockMonitoringRepository)(nil).ListEvents), page, limit, ruleID)
+}
+
+// ListRules mocks base method.
+func (m *MockMonitoringRepository) ListRules() ([]models.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRules")
+	ret0, _ := ret[0].([]models.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRules indicates an expected call of ListRules.
+func (mr *MockMonitoringRepositoryMockRecorder) ListRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRules", reflect.TypeOf((*MockMonitoringRepository)(nil).ListRules))
+}
+
+// PruneMetrics mocks base method.
+func (m *MockMonitoringRepository) PruneMetrics(before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneMetrics", before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneMetrics indicates an expected call of PruneMetrics.
+func (mr *MockMonitoringRepositoryMockRecorder) PruneMetrics(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneMetrics", reflect.TypeOf((*MockMonitoringRepository)(nil).PruneMetrics), before)
+}
+
+// SumMetric mocks base method.
+func (m *MockMonitoringRepository) SumMetric(metric string, since time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumMetric", metric, since)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumMetric indicates an expected call of SumMetric.
+func (mr *MockMonitoringRepositoryMockRecorder) SumMetric(metric, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumMetric", reflect.TypeOf((*MockMonitoringRepository)(nil).SumMetric), metric, since)
+}
+
+// UpdateRule mocks base method.
+func (m *MockMonitoringRepository) UpdateRule(rule *models.AlertRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRule", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRule indicates an expected call of UpdateRule.
+func (mr *MockMonitoringRepositoryMockRecorder) UpdateRule(rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRule", reflect.TypeOf((*MockMonitoringRepository)(nil).UpdateRule), rule)
+}
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueDeliveries mocks base method.
+func (m *MockWebhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueDeliveries", limit, lease)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueDeliveries indicates an expected call of ClaimDueDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) ClaimDueDeliveries(limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).ClaimDueDeliveries), limit, lease)
+}
+
+// CreateDelivery mocks base method.
+func (m *MockWebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDelivery indicates an expected call of CreateDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) CreateDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).CreateDelivery), delivery)
+}
+
+// CreateEndpoint mocks base method.
+func (m *MockWebhookRepository) CreateEndpoint(endpoint *models.WebhookEndpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEndpoint", endpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEndpoint indicates an expected call of CreateEndpoint.
+func (mr *MockWebhookRepositoryMockRecorder) CreateEndpoint(endpoint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEndpoint", reflect.TypeOf((*MockWebhookRepository)(nil).CreateEndpoint), endpoint)
+}
+
+// DeleteEndpoint mocks base method.
+func (m *MockWebhookRepository) DeleteEndpoint(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEndpoint", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEndpoint indicates an expected call of DeleteEndpoint.
+func (mr *MockWebhookRepositoryMockRecorder) DeleteEndpoint(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEndpoint", reflect.TypeOf((*MockWebhookRepository)(nil).DeleteEndpoint), id)
+}
+
+// GetDelivery mocks base method.
+func (m *MockWebhookRepository) GetDelivery(id uuid.UUID) (*models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelivery", id)
+	ret0, _ := ret[0].(*models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelivery indicates an expected call of GetDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) GetDelivery(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).GetDelivery), id)
+}
+
+// GetEndpoint mocks base method.
+func (m *MockWebhookRepository) GetEndpoint(id uuid.UUID) (*models.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEndpoint", id)
+	ret0, _ := ret[0].(*models.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEndpoint indicates an expected call of GetEndpoint.
+func (mr *MockWebhookRepositoryMockRecorder) GetEndpoint(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEndpoint", reflect.TypeOf((*MockWebhookRepository)(nil).GetEndpoint), id)
+}
+
+// ListActiveEndpointsForEvent mocks base method.
+func (m *MockWebhookRepository) ListActiveEndpointsForEvent(event string) ([]models.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveEndpointsForEvent", event)
+	ret0, _ := ret[0].([]models.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveEndpointsForEvent indicates an expected call of ListActiveEndpointsForEvent.
+func (mr *MockWebhookRepositoryMockRecorder) ListActiveEndpointsForEvent(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveEndpointsForEvent", reflect.TypeOf((*MockWebhookRepository)(nil).ListActiveEndpointsForEvent), event)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhookRepository) ListDeliveries(endpointID uuid.UUID, page, limit int, status string) ([]models.WebhookDelivery, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", endpointID, page, limit, status)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) ListDeliveries(endpointID, page, limit, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).ListDeliveries), endpointID, page, limit, status)
+}
+
+// ListEndpoints mocks base method.
+func (m *MockWebhookRepository) ListEndpoints() ([]models.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEndpoints")
+	ret0, _ := ret[0].([]models.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEndpoints indicates an expected call of ListEndpoints.
+func (mr *MockWebhookRepositoryMockRecorder) ListEndpoints() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEndpoints", reflect.TypeOf((*MockWebhookRepository)(nil).ListEndpoints))
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockWebhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) UpdateDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).UpdateDelivery), delivery)
+}
+
+// UpdateEndpoint mocks base method.
+func (m *MockWebhookRepository) UpdateEndpoint(endpoint *models.WebhookEndpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEndpoint", endpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEndpoint indicates an expected call of UpdateEndpoint.
+func (mr *MockWebhookRepositoryMockRecorder) UpdateEndpoint(endpoint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEndpoint", reflect.TypeOf((*MockWebhookRepository)(nil).UpdateEndpoint), endpoint)
+}
+
+// MockSMSDeliveryRepository is a mock of SMSDeliveryRepository interface.
+type MockSMSDeliveryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSMSDeliveryRepositoryMockRecorder
+}
+
+// MockSMSDeliveryRepositoryMockRecorder is the mock recorder for MockSMSDeliveryRepository.
+type MockSMSDeliveryRepositoryMockRecorder struct {
+	mock *MockSMSDeliveryRepository
+}
+
+// NewMockSMSDeliveryRepository creates a new mock instance.
+func NewMockSMSDeliveryRepository(ctrl *gomock.Controller) *MockSMSDeliveryRepository {
+	mock := &MockSMSDeliveryRepository{ctrl: ctrl}
+	mock.recorder = &MockSMSDeliveryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSMSDeliveryRepository) EXPECT() *MockSMSDeliveryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSMSDeliveryRepository) Create(delivery *models.SMSDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSMSDeliveryRepositoryMockRecorder) Create(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSMSDeliveryRepository)(nil).Create), delivery)
+}
+
+// List mocks base method.
+func (m *MockSMSDeliveryRepository) List(page, limit int, phone, status string) ([]models.SMSDelivery, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", page, limit, phone, status)
+	ret0, _ := ret[0].([]models.SMSDelivery)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockSMSDeliveryRepositoryMockRecorder) List(page, limit, phone, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSMSDeliveryRepository)(nil).List), page, limit, phone, status)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockSMSDeliveryRepository) UpdateStatus(provider, messageID, status string, errorCode, errMsg *string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", provider, messageID, status, errorCode, errMsg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockSMSDeliveryRepositoryMockRecorder) UpdateStatus(provider, messageID, status, errorCode, errMsg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockSMSDeliveryRepository)(nil).UpdateStatus), provider, messageID, status, errorCode, errMsg)
+}
+
+// MockAnalyticsRepository is a mock of AnalyticsRepository interface.
+type MockAnalyticsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnalyticsRepositoryMockRecorder
+}
+
+// MockAnalyticsRepositoryMockRecorder is the mock recorder for MockAnalyticsRepository.
+type MockAnalyticsRepositoryMockRecorder struct {
+	mock *MockAnalyticsRepository
+}
+
+// NewMockAnalyticsRepository creates a new mock instance.
+func NewMockAnalyticsRepository(ctrl *gomock.Controller) *MockAnalyticsRepository {
+	mock := &MockAnalyticsRepository{ctrl: ctrl}
+	mock.recorder = &MockAnalyticsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnalyticsRepository) EXPECT() *MockAnalyticsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Cohorts mocks base method.
+func (m *MockAnalyticsRepository) Cohorts(from, to, asOf time.Time) ([]repository.CohortRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cohorts", from, to, asOf)
+	ret0, _ := ret[0].([]repository.CohortRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cohorts indicates an expected call of Cohorts.
+func (mr *MockAnalyticsRepositoryMockRecorder) Cohorts(from, to, asOf interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cohorts", reflect.TypeOf((*MockAnalyticsRepository)(nil).Cohorts), from, to, asOf)
+}
+
+// Funnel mocks base method.
+func (m *MockAnalyticsRepository) Funnel(steps []string, from, to time.Time, platform string) ([]repository.FunnelStep, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Funnel", steps, from, to, platform)
+	ret0, _ := ret[0].([]repository.FunnelStep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Funnel indicates an expected call of Funnel.
+func (mr *MockAnalyticsRepositoryMockRecorder) Funnel(steps, from, to, platform interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Funnel", reflect.TypeOf((*MockAnalyticsRepository)(nil).Funnel), steps, from, to, platform)
+}
+
+// ListDaily mocks base method.
+func (m *MockAnalyticsRepository) ListDaily(yandasID uuid.UUID, from, to time.Time) ([]models.YandasDailyStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDaily", yandasID, from, to)
+	ret0, _ := ret[0].([]models.YandasDailyStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDaily indicates an expected call of ListDaily.
+func (mr *MockAnalyticsRepositoryMockRecorder) ListDaily(yandasID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDaily", reflect.TypeOf((*MockAnalyticsRepository)(nil).ListDaily), yandasID, from, to)
+}
+
+// PruneEvents mocks base method.
+func (m *MockAnalyticsRepository) PruneEvents(before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneEvents", before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneEvents indicates an expected call of PruneEvents.
+func (mr *MockAnalyticsRepositoryMockRecorder) PruneEvents(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneEvents", reflect.TypeOf((*MockAnalyticsRepository)(nil).PruneEvents), before)
+}
+
+// PruneProfileViews mocks base method.
+func (m *MockAnalyticsRepository) PruneProfileViews(before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneProfileViews", before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneProfileViews indicates an expected call of PruneProfileViews.
+func (mr *MockAnalyticsRepositoryMockRecorder) PruneProfileViews(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneProfileViews", reflect.TypeOf((*MockAnalyticsRepository)(nil).PruneProfileViews), before)
+}
+
+// RecordEvents mocks base method.
+func (m *MockAnalyticsRepository) RecordEvents(events []models.AnalyticsEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordEvents", events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordEvents indicates an expected call of RecordEvents.
+func (mr *MockAnalyticsRepositoryMockRecorder) RecordEvents(events interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordEvents", reflect.TypeOf((*MockAnalyticsRepository)(nil).RecordEvents), events)
+}
+
+// RecordProfileView mocks base method.
+func (m *MockAnalyticsRepository) RecordProfileView(yandasID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordProfileView", yandasID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordProfileView indicates an expected call of RecordProfileView.
+func (mr *MockAnalyticsRepositoryMockRecorder) RecordProfileView(yandasID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordProfileView", reflect.TypeOf((*MockAnalyticsRepository)(nil).RecordProfileView), yandasID)
+}
+
+// RollupDay mocks base method.
+func (m *MockAnalyticsRepository) RollupDay(from, to time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupDay", from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollupDay indicates an expected call of RollupDay.
+func (mr *MockAnalyticsRepositoryMockRecorder) RollupDay(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupDay", reflect.TypeOf((*MockAnalyticsRepository)(nil).RollupDay), from, to)
+}
+
+// MockExchangeRateRepository is a mock of ExchangeRateRepository interface.
+type MockExchangeRateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeRateRepositoryMockRecorder
+}
+
+// MockExchangeRateRepositoryMockRecorder is the mock recorder for MockExchangeRateRepository.
+type MockExchangeRateRepositoryMockRecorder struct {
+	mock *MockExchangeRateRepository
+}
+
+// NewMockExchangeRateRepository creates a new mock instance.
+func NewMockExchangeRateRepository(ctrl *gomock.Controller) *MockExchangeRateRepository {
+	mock := &MockExchangeRateRepository{ctrl: ctrl}
+	mock.recorder = &MockExchangeRateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeRateRepository) EXPECT() *MockExchangeRateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Latest mocks base method.
+func (m *MockExchangeRateRepository) Latest() ([]models.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Latest")
+	ret0, _ := ret[0].([]models.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Latest indicates an expected call of Latest.
+func (mr *MockExchangeRateRepositoryMockRecorder) Latest() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Latest", reflect.TypeOf((*MockExchangeRateRepository)(nil).Latest))
+}
+
+// Save mocks base method.
+func (m *MockExchangeRateRepository) Save(rates []models.ExchangeRate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", rates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockExchangeRateRepositoryMockRecorder) Save(rates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockExchangeRateRepository)(nil).Save), rates)
+}
+
+// MockQualityRepository is a mock of QualityRepository interface.
+type MockQualityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQualityRepositoryMockRecorder
+}
+
+// MockQualityRepositoryMockRecorder is the mock recorder for MockQualityRepository.
+type MockQualityRepositoryMockRecorder struct {
+	mock *MockQualityRepository
+}
+
+// NewMockQualityRepository creates a new mock instance.
+func NewMockQualityRepository(ctrl *gomock.Controller) *MockQualityRepository {
+	mock := &MockQualityRepository{ctrl: ctrl}
+	mock.recorder = &MockQualityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQualityRepository) EXPECT() *MockQualityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Leaderboard mocks base method.
+func (m *MockQualityRepository) Leaderboard(filter repository.QualityFilter, page, limit int) ([]repository.LeaderboardEntry, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Leaderboard", filter, page, limit)
+	ret0, _ := ret[0].([]repository.LeaderboardEntry)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Leaderboard indicates an expected call of Leaderboard.
+func (mr *MockQualityRepositoryMockRecorder) Leaderboard(filter, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Leaderboard", reflect.TypeOf((*MockQualityRepository)(nil).Leaderboard), filter, page, limit)
+}
+
+// Metrics mocks base method.
+func (m *MockQualityRepository) Metrics(since time.Time) ([]models.YandasQualityScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Metrics", since)
+	ret0, _ := ret[0].([]models.YandasQualityScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Metrics indicates an expected call of Metrics.
+func (mr *MockQualityRepositoryMockRecorder) Metrics(since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metrics", reflect.TypeOf((*MockQualityRepository)(nil).Metrics), since)
+}
+
+// Replace mocks base method.
+func (m *MockQualityRepository) Replace(rows []models.YandasQualityScore) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", rows)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockQualityRepositoryMockRecorder) Replace(rows interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockQualityRepository)(nil).Replace), rows)
+}
+
+// MockRetentionRepository is a mock of RetentionRepository interface.
+type MockRetentionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetentionRepositoryMockRecorder
+}
+
+// MockRetentionRepositoryMockRecorder is the mock recorder for MockRetentionRepository.
+type MockRetentionRepositoryMockRecorder struct {
+	mock *MockRetentionRepository
+}
+
+// NewMockRetentionRepository creates a new mock instance.
+func NewMockRetentionRepository(ctrl *gomock.Controller) *MockRetentionRepository {
+	mock := &MockRetentionRepository{ctrl: ctrl}
+	mock.recorder = &MockRetentionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetentionRepository) EXPECT() *MockRetentionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ArchiveMessages mocks base method.
+func (m *MockRetentionRepository) ArchiveMessages(before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveMessages", before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveMessages indicates an expected call of ArchiveMessages.
+func (mr *MockRetentionRepositoryMockRecorder) ArchiveMessages(before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveMessages", reflect.TypeOf((*MockRetentionRepository)(nil).ArchiveMessages), before, limit)
+}
+
+// CountAuditLogs mocks base method.
+func (m *MockRetentionRepository) CountAuditLogs(before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAuditLogs", before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAuditLogs indicates an expected call of CountAuditLogs.
+func (mr *MockRetentionRepositoryMockRecorder) CountAuditLogs(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAuditLogs", reflect.TypeOf((*MockRetentionRepository)(nil).CountAuditLogs), before)
+}
+
+// CountCallLogs mocks base method.
+func (m *MockRetentionRepository) CountCallLogs(before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCallLogs", before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCallLogs indicates an expected call of CountCallLogs.
+func (mr *MockRetentionRepositoryMockRecorder) CountCallLogs(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCallLogs", reflect.TypeOf((*MockRetentionRepository)(nil).CountCallLogs), before)
+}
+
+// CountMessages mocks base method.
+func (m *MockRetentionRepository) CountMessages(before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountMessages", before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountMessages indicates an expected call of CountMessages.
+func (mr *MockRetentionRepositoryMockRecorder) CountMessages(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountMessages", reflect.TypeOf((*MockRetentionRepository)(nil).CountMessages), before)
+}
+
+// DeleteAuditLogs mocks base method.
+func (m *MockRetentionRepository) DeleteAuditLogs(before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuditLogs", before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAuditLogs indicates an expected call of DeleteAuditLogs.
+func (mr *MockRetentionRepositoryMockRecorder) DeleteAuditLogs(before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuditLogs", reflect.TypeOf((*MockRetentionRepository)(nil).DeleteAuditLogs), before, limit)
+}
+
+// DeleteCallLogs mocks base method.
+func (m *MockRetentionRepository) DeleteCallLogs(before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCallLogs", before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCallLogs indicates an expected call of DeleteCallLogs.
+func (mr *MockRetentionRepositoryMockRecorder) DeleteCallLogs(before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCallLogs", reflect.TypeOf((*MockRetentionRepository)(nil).DeleteCallLogs), before, limit)
+}
+
+// SoftDeletedUsers mocks base method.
+func (m *MockRetentionRepository) SoftDeletedUsers(before time.Time) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeletedUsers", before)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDeletedUsers indicates an expected call of SoftDeletedUsers.
+func (mr *MockRetentionRepositoryMockRecorder) SoftDeletedUsers(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeletedUsers", reflect.TypeOf((*MockRetentionRepository)(nil).SoftDeletedUsers), before)
+}
+
+// MockBackupRepository is a mock of BackupRepository interface.
+type MockBackupRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackupRepositoryMockRecorder
+}
+
+// MockBackupRepositoryMockRecorder is the mock recorder for MockBackupRepository.
+type MockBackupRepositoryMockRecorder struct {
+	mock *MockBackupRepository
+}
+
+// NewMockBackupRepository creates a new mock instance.
+func NewMockBackupRepository(ctrl *gomock.Controller) *MockBackupRepository {
+	mock := &MockBackupRepository{ctrl: ctrl}
+	mock.recorder = &MockBackupRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackupRepository) EXPECT() *MockBackupRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBackupRepository) Create(backup *models.DatabaseBackup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", backup)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBackupRepositoryMockRecorder) Create(backup interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBackupRepository)(nil).Create), backup)
+}
+
+// GetByFileName mocks base method.
+func (m *MockBackupRepository) GetByFileName(name string) (*models.DatabaseBackup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByFileName", name)
+	ret0, _ := ret[0].(*models.DatabaseBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByFileName indicates an expected call of GetByFileName.
+func (mr *MockBackupRepositoryMockRecorder) GetByFileName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByFileName", reflect.TypeOf((*MockBackupRepository)(nil).GetByFileName), name)
+}
+
+// LatestSucceeded mocks base method.
+func (m *MockBackupRepository) LatestSucceeded() (*models.DatabaseBackup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestSucceeded")
+	ret0, _ := ret[0].(*models.DatabaseBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestSucceeded indicates an expected call of LatestSucceeded.
+func (mr *MockBackupRepositoryMockRecorder) LatestSucceeded() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestSucceeded", reflect.TypeOf((*MockBackupRepository)(nil).LatestSucceeded))
+}
+
+// List mocks base method.
+func (m *MockBackupRepository) List(limit int) ([]models.DatabaseBackup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", limit)
+	ret0, _ := ret[0].([]models.DatabaseBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockBackupRepositoryMockRecorder) List(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBackupRepository)(nil).List), limit)
+}
+
+// Update mocks base method.
+func (m *MockBackupRepository) Update(backup *models.DatabaseBackup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", backup)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBackupRepositoryMockRecorder) Update(backup interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBackupRepository)(nil).Update), backup)
+}
+
+// MockActivityRepository is a mock of ActivityRepository interface.
+type MockActivityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockActivityRepositoryMockRecorder
+}
+
+// MockActivityRepositoryMockRecorder is the mock recorder for MockActivityRepository.
+type MockActivityRepositoryMockRecorder struct {
+	mock *MockActivityRepository
+}
+
+// NewMockActivityRepository creates a new mock instance.
+func NewMockActivityRepository(ctrl *gomock.Controller) *MockActivityRepository {
+	mock := &MockActivityRepository{ctrl: ctrl}
+	mock.recorder = &MockActivityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActivityRepository) EXPECT() *MockActivityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockActivityRepository) Create(activity *models.AdminActivity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", activity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockActivityRepositoryMockRecorder) Create(activity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockActivityRepository)(nil).Create), activity)
+}
+
+// List mocks base method.
+func (m *MockActivityRepository) List(activityType string, page, limit int) ([]models.AdminActivity, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", activityType, page, limit)
+	ret0, _ := ret[0].([]models.AdminActivity)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockActivityRepositoryMockRecorder) List(activityType, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockActivityRepository)(nil).List), activityType, page, limit)
+}
+
+// Prune mocks base method.
+func (m *MockActivityRepository) Prune(before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockActivityRepositoryMockRecorder) Prune(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockActivityRepository)(nil).Prune), before)
+}
+
+// MockDemandRepository is a mock of DemandRepository interface.
+type MockDemandRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDemandRepositoryMockRecorder
+}
+
+// MockDemandRepositoryMockRecorder is the mock recorder for MockDemandRepository.
+type MockDemandRepositoryMockRecorder struct {
+	mock *MockDemandRepository
+}
+
+// NewMockDemandRepository creates a new mock instance.
+func NewMockDemandRepository(ctrl *gomock.Controller) *MockDemandRepository {
+	mock := &MockDemandRepository{ctrl: ctrl}
+	mock.recorder = &MockDemandRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDemandRepository) EXPECT() *MockDemandRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CategoryIDsAtLevel mocks base method.
+func (m *MockDemandRepository) CategoryIDsAtLevel(city, level string) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CategoryIDsAtLevel", city, level)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CategoryIDsAtLevel indicates an expected call of CategoryIDsAtLevel.
+func (mr *MockDemandRepositoryMockRecorder) CategoryIDsAtLevel(city, level interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CategoryIDsAtLevel", reflect.TypeOf((*MockDemandRepository)(nil).CategoryIDsAtLevel), city, level)
+}
+
+// Counts mocks base method.
+func (m *MockDemandRepository) Counts() ([]models.CategoryDemand, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Counts")
+	ret0, _ := ret[0].([]models.CategoryDemand)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Counts indicates an expected call of Counts.
+func (mr *MockDemandRepositoryMockRecorder) Counts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Counts", reflect.TypeOf((*MockDemandRepository)(nil).Counts))
+}
+
+// ListByCity mocks base method.
+func (m *MockDemandRepository) ListByCity(city string) ([]models.CategoryDemand, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCity", city)
+	ret0, _ := ret[0].([]models.CategoryDemand)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByCity indicates an expected call of ListByCity.
+func (mr *MockDemandRepositoryMockRecorder) ListByCity(city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCity", reflect.TypeOf((*MockDemandRepository)(nil).ListByCity), city)
+}
+
+// Replace mocks base method.
+func (m *MockDemandRepository) Replace(rows []models.CategoryDemand) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", rows)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockDemandRepositoryMockRecorder) Replace(rows interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockDemandRepository)(nil).Replace), rows)
+}
+
+// MockSitemapRepository is a mock of SitemapRepository interface.
+type MockSitemapRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSitemapRepositoryMockRecorder
+}
+
+// MockSitemapRepositoryMockRecorder is the mock recorder for MockSitemapRepository.
+type MockSitemapRepositoryMockRecorder struct {
+	mock *MockSitemapRepository
+}
+
+// NewMockSitemapRepository creates a new mock instance.
+func NewMockSitemapRepository(ctrl *gomock.Controller) *MockSitemapRepository {
+	mock := &MockSitemapRepository{ctrl: ctrl}
+	mock.recorder = &MockSitemapRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSitemapRepository) EXPECT() *MockSitemapRepositoryMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockSitemapRepository) List(page, limit int) ([]repository.SitemapEntry, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", page, limit)
+	ret0, _ := ret[0].([]repository.SitemapEntry)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockSitemapRepositoryMockRecorder) List(page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSitemapRepository)(nil).List), page, limit)
+}
+
+// MockDomainEventRepository is a mock of DomainEventRepository interface.
+type MockDomainEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainEventRepositoryMockRecorder
+}
+
+// MockDomainEventRepositoryMockRecorder is the mock recorder for MockDomainEventRepository.
+type MockDomainEventRepositoryMockRecorder struct {
+	mock *MockDomainEventRepository
+}
+
+// NewMockDomainEventRepository creates a new mock instance.
+func NewMockDomainEventRepository(ctrl *gomock.Controller) *MockDomainEventRepository {
+	mock := &MockDomainEventRepository{ctrl: ctrl}
+	mock.recorder = &MockDomainEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainEventRepository) EXPECT() *MockDomainEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method.
+func (m *MockDomainEventRepository) ClaimDue(limit int, lease time.Duration) ([]models.DomainEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", limit, lease)
+	ret0, _ := ret[0].([]models.DomainEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockDomainEventRepositoryMockRecorder) ClaimDue(limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockDomainEventRepository)(nil).ClaimDue), limit, lease)
+}
+
+// Create mocks base method.
+func (m *MockDomainEventRepository) Create(event *models.DomainEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDomainEventRepositoryMockRecorder) Create(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDomainEventRepository)(nil).Create), event)
+}
+
+// GetByID mocks base method.
+func (m *MockDomainEventRepository) GetByID(id uuid.UUID) (*models.DomainEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.DomainEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDomainEventRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDomainEventRepository)(nil).GetByID), id)
+}
+
+// PrunePublished mocks base method.
+func (m *MockDomainEventRepository) PrunePublished(before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrunePublished", before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PrunePublished indicates an expected call of PrunePublished.
+func (mr *MockDomainEventRepositoryMockRecorder) PrunePublished(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrunePublished", reflect.TypeOf((*MockDomainEventRepository)(nil).PrunePublished), before)
+}
+
+// Update mocks base method.
+func (m *MockDomainEventRepository) Update(event *models.DomainEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDomainEventRepositoryMockRecorder) Update(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDomainEventRepository)(nil).Update), event)
+}
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method.
+func (m *MockOutboxRepository) ClaimDue(limit int, lease time.Duration) ([]models.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", limit, lease)
+	ret0, _ := ret[0].([]models.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockOutboxRepositoryMockRecorder) ClaimDue(limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockOutboxRepository)(nil).ClaimDue), limit, lease)
+}
+
+// Create mocks base method.
+func (m *MockOutboxRepository) Create(message *models.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOutboxRepositoryMockRecorder) Create(message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOutboxRepository)(nil).Create), message)
+}
+
+// PruneDelivered mocks base method.
+func (m *MockOutboxRepository) PruneDelivered(before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneDelivered", before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneDelivered indicates an expected call of PruneDelivered.
+func (mr *MockOutboxRepositoryMockRecorder) PruneDelivered(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneDelivered", reflect.TypeOf((*MockOutboxRepository)(nil).PruneDelivered), before)
+}
+
+// Update mocks base method.
+func (m *MockOutboxRepository) Update(message *models.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockOutboxRepositoryMockRecorder) Update(message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockOutboxRepository)(nil).Update), message)
+}
+
+// MockSystemRepository is a mock of SystemRepository interface.
+type MockSystemRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSystemRepositoryMockRecorder
+}
+
+// MockSystemRepositoryMockRecorder is the mock recorder for MockSystemRepository.
+type MockSystemRepositoryMockRecorder struct {
+	mock *MockSystemRepository
+}
+
+// NewMockSystemRepository creates a new mock instance.
+func NewMockSystemRepository(ctrl *gomock.Controller) *MockSystemRepository {
+	mock := &MockSystemRepository{ctrl: ctrl}
+	mock.recorder = &MockSystemRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSystemRepository) EXPECT() *MockSystemRepositoryMockRecorder {
+	return m.recorder
+}
+
+// MigrationStatus mocks base method.
+func (m *MockSystemRepository) MigrationStatus() ([]database.MigrationStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrationStatus")
+	ret0, _ := ret[0].([]database.MigrationStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MigrationStatus indicates an expected call of MigrationStatus.
+func (mr *MockSystemRepositoryMockRecorder) MigrationStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrationStatus", reflect.TypeOf((*MockSystemRepository)(nil).MigrationStatus))
+}
+
+// Ping mocks base method.
+func (m *MockSystemRepository) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockSystemRepositoryMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockSystemRepository)(nil).Ping), ctx)
+}
+
+// MockSettingRepository is a mock of SettingRepository interface.
+type MockSettingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSettingRepositoryMockRecorder
+}
+
+// MockSettingRepositoryMockRecorder is the mock recorder for MockSettingRepository.
+type MockSettingRepositoryMockRecorder struct {
+	mock *MockSettingRepository
+}
+
+// NewMockSettingRepository creates a new mock instance.
+func NewMockSettingRepository(ctrl *gomock.Controller) *MockSettingRepository {
+	mock := &MockSettingRepository{ctrl: ctrl}
+	mock.recorder = &MockSettingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSettingRepository) EXPECT() *MockSettingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockSettingRepository) Delete(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSettingRepositoryMockRecorder) Delete(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSettingRepository)(nil).Delete), key)
+}
+
+// List mocks base method.
+func (m *MockSettingRepository) List() ([]models.Setting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]models.Setting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSettingRepositoryMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSettingRepository)(nil).List))
+}
+
+// Upsert mocks base method.
+func (m *MockSettingRepository) Upsert(setting *models.Setting) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", setting)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockSettingRepositoryMockRecorder) Upsert(setting interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockSettingRepository)(nil).Upsert), setting)
+}
+
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockUnitOfWork) Do(fn func(*repository.Repositories) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockUnitOfWorkMockRecorder) Do(fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockUnitOfWork)(nil).Do), fn)
+}
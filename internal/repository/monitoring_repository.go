@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// monitoringRepository handles metric counters, alert rules and alert events
+type monitoringRepository struct {
+	db *gorm.DB
+}
+
+func NewMonitoringRepository(db *gorm.DB) MonitoringRepository {
+	return &monitoringRepository{db: db}
+}
+
+// IncrementMetric adds one to the metric's counter for the given minute bucket
+func (r *monitoringRepository) IncrementMetric(metric string, bucket time.Time) error {
+	counter := &models.MetricCounter{Metric: metric, Bucket: bucket, Count: 1}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "metric"}, {Name: "bucket"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("metric_counters.count + 1")}),
+	}).Create(counter).Error
+}
+
+// SumMetric totals a metric's counters from since until now
+func (r *monitoringRepository) SumMetric(metric string, since time.Time) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.MetricCounter{}).
+		Select("COALESCE(SUM(count), 0)").
+		Where("metric = ? AND bucket >= ?", metric, since).
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *monitoringRepository) PruneMetrics(before time.Time) error {
+	return r.db.Where("bucket < ?", before).Delete(&models.MetricCounter{}).Error
+}
+
+func (r *monitoringRepository) ListRules() ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	err := r.db.Order("created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *monitoringRepository) ListActiveRules() ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	err := r.db.Where("is_active = ?", true).Find(&rules).Error
+	return rules, err
+}
+
+func (r *monitoringRepository) CountRules() int64 {
+	var count int64
+	r.db.Model(&models.AlertRule{}).Count(&count)
+	return count
+}
+
+func (r *monitoringRepository) GetRule(id uuid.UUID) (*models.AlertRule, error) {
+	var rule models.AlertRule
+	err := r.db.First(&rule, "id = ?", id).Error
+	return &rule, err
+}
+
+func (r *monitoringRepository) CreateRule(rule *models.AlertRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *monitoringRepository) UpdateRule(rule *models.AlertRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *monitoringRepository) DeleteRule(id uuid.UUID) error {
+	return r.db.Delete(&models.AlertRule{}, "id = ?", id).Error
+}
+
+func (r *monitoringRepository) CreateEvent(event *models.AlertEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *monitoringRepository) ListEvents(page, limit int, ruleID *uuid.UUID) ([]models.AlertEvent, int64, error) {
+	var events []models.AlertEvent
+	var total int64
+
+	query := r.db.Model(&models.AlertEvent{})
+	if ruleID != nil {
+		query = query.Where("rule_id = ?", *ruleID)
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Preload("Rule").Order("created_at DESC").Offset(offset).Limit(limit).Find(&events).Error
+
+	return events, total, err
+}
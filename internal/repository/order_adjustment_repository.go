@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// orderAdjustmentRepository handles proposed changes to order amounts
+type orderAdjustmentRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderAdjustmentRepository(db *gorm.DB) OrderAdjustmentRepository {
+	return &orderAdjustmentRepository{db: db}
+}
+
+func (r *orderAdjustmentRepository) Create(adjustment *models.OrderAdjustment) error {
+	return r.db.Create(adjustment).Error
+}
+
+func (r *orderAdjustmentRepository) GetByID(id uuid.UUID) (*models.OrderAdjustment, error) {
+	var adjustment models.OrderAdjustment
+	err := r.db.First(&adjustment, "id = ?", id).Error
+	return &adjustment, err
+}
+
+// ListByOrder returns an order's adjustments oldest first
+func (r *orderAdjustmentRepository) ListByOrder(orderID uuid.UUID) ([]models.OrderAdjustment, error) {
+	var adjustments []models.OrderAdjustment
+	err := r.db.Where("order_id = ?", orderID).Order("created_at ASC").Find(&adjustments).Error
+	return adjustments, err
+}
+
+// WithdrawPending withdraws any adjustment still awaiting the customer
+func (r *orderAdjustmentRepository) WithdrawPending(orderID uuid.UUID) error {
+	return r.db.Model(&models.OrderAdjustment{}).
+		Where("order_id = ? AND status = ?", orderID, "pending").
+		Updates(map[string]interface{}{"status": "withdrawn", "responded_at": time.Now()}).Error
+}
+
+// Resolve moves a pending adjustment to status. It reports false when the
+// adjustment was no longer pending.
+func (r *orderAdjustmentRepository) Resolve(id uuid.UUID, status string) (bool, error) {
+	result := r.db.Model(&models.OrderAdjustment{}).
+		Where("id = ? AND status = ?", id, "pending").
+		Updates(map[string]interface{}{"status": status, "responded_at": time.Now()})
+	return result.RowsAffected > 0, result.Error
+}
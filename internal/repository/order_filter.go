@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/pkg/currency"
+	"gorm.io/gorm"
+)
+
+// Sort orders for the admin order list
+const (
+	OrderSortNewest     = "newest"
+	OrderSortOldest     = "oldest"
+	OrderSortAmountDesc = "amount_desc"
+	OrderSortAmountAsc  = "amount_asc"
+	OrderSortScheduled  = "scheduled"
+)
+
+// OrderFilter narrows the admin order list; zero values are ignored
+type OrderFilter struct {
+	Status        string
+	CreatedFrom   *time.Time // inclusive
+	CreatedTo     *time.Time // exclusive
+	ScheduledFrom *time.Time // inclusive
+	ScheduledTo   *time.Time // exclusive
+	City          string     // one of the yandaş's service cities
+	CategorySlug  string     // also matches services in the category's subcategories
+	CustomerID    *uuid.UUID
+	YandasID      *uuid.UUID // yandaş profile ID
+	MinAmount     *currency.Money
+	MaxAmount     *currency.Money
+	Sort          string
+}
+
+// apply adds the filter's conditions to a query over orders
+func (f OrderFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.Status != "" {
+		query = query.Where("orders.status = ?", f.Status)
+	}
+	if f.CreatedFrom != nil {
+		query = query.Where("orders.created_at >= ?", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		query = query.Where("orders.created_at < ?", *f.CreatedTo)
+	}
+	if f.ScheduledFrom != nil {
+		query = query.Where("orders.scheduled_at >= ?", *f.ScheduledFrom)
+	}
+	if f.ScheduledTo != nil {
+		query = query.Where("orders.scheduled_at < ?", *f.ScheduledTo)
+	}
+	if f.City != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM yandas_profiles WHERE yandas_profiles.id = orders.yandas_id AND ? = ANY(yandas_profiles.service_cities))", f.City)
+	}
+	if f.CategorySlug != "" {
+		query = query.Where(`EXISTS (SELECT 1 FROM yandas_services WHERE yandas_services.id = orders.service_id AND yandas_services.category_id IN (
+			SELECT id FROM categories WHERE slug = ?
+			UNION SELECT id FROM categories WHERE parent_id = (SELECT id FROM categories WHERE slug = ?)))`,
+			f.CategorySlug, f.CategorySlug)
+	}
+	if f.CustomerID != nil {
+		query = query.Where("orders.customer_id = ?", *f.CustomerID)
+	}
+	if f.YandasID != nil {
+		query = query.Where("orders.yandas_id = ?", *f.YandasID)
+	}
+	if f.MinAmount != nil {
+		query = query.Where("orders.agreed_price >= ?", *f.MinAmount)
+	}
+	if f.MaxAmount != nil {
+		query = query.Where("orders.agreed_price <= ?", *f.MaxAmount)
+	}
+	return query
+}
+
+// order returns the ORDER BY for the filter's sort, newest first by default
+func (f OrderFilter) order() string {
+	switch f.Sort {
+	case OrderSortOldest:
+		return "orders.created_at ASC"
+	case OrderSortAmountDesc:
+		return "orders.agreed_price DESC, orders.created_at DESC"
+	case OrderSortAmountAsc:
+		return "orders.agreed_price ASC, orders.created_at DESC"
+	case OrderSortScheduled:
+		return "orders.scheduled_at ASC NULLS LAST, orders.created_at DESC"
+	default:
+		return "orders.created_at DESC"
+	}
+}
@@ -6,36 +6,58 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// OrderRepository handles order operations
-type OrderRepository struct {
+// AccountingRow totals the completed orders of one category, city and currency
+type AccountingRow struct {
+	Category     string         `json:"category"`
+	City         string         `json:"city"`
+	Currency     string         `json:"currency"`
+	Orders       int64          `json:"orders"`
+	Gross        currency.Money `json:"gross"`
+	PlatformFees currency.Money `json:"platform_fees"`
+	NetEarnings  currency.Money `json:"net_earnings"`
+}
+
+// orderRepository handles order operations
+type orderRepository struct {
 	db *gorm.DB
 }
 
-func NewOrderRepository(db *gorm.DB) *OrderRepository {
-	return &OrderRepository{db: db}
+func NewOrderRepository(db *gorm.DB) OrderRepository {
+	return &orderRepository{db: db}
 }
 
-func (r *OrderRepository) Create(order *models.Order) error {
+func (r *orderRepository) Create(order *models.Order) error {
 	// Generate order number
 	order.OrderNumber = generateOrderNumber()
 	return r.db.Create(order).Error
 }
 
-func (r *OrderRepository) GetByID(id uuid.UUID) (*models.Order, error) {
+func (r *orderRepository) GetByID(id uuid.UUID) (*models.Order, error) {
 	var order models.Order
 	err := r.db.
 		Preload("Customer").
 		Preload("Yandas.User").
 		Preload("Service.Category").
 		Preload("Review").
+		Preload("LineItems").
 		First(&order, "id = ?", id).Error
 	return &order, err
 }
 
-func (r *OrderRepository) GetByOrderNumber(orderNumber string) (*models.Order, error) {
+// GetByIDForUpdate loads an order without associations and locks its row
+// until the surrounding transaction ends
+func (r *orderRepository) GetByIDForUpdate(id uuid.UUID) (*models.Order, error) {
+	var order models.Order
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", id).Error
+	return &order, err
+}
+
+func (r *orderRepository) GetByOrderNumber(orderNumber string) (*models.Order, error) {
 	var order models.Order
 	err := r.db.
 		Preload("Customer").
@@ -45,11 +67,11 @@ func (r *OrderRepository) GetByOrderNumber(orderNumber string) (*models.Order, e
 	return &order, err
 }
 
-func (r *OrderRepository) Update(order *models.Order) error {
+func (r *orderRepository) Update(order *models.Order) error {
 	return r.db.Save(order).Error
 }
 
-func (r *OrderRepository) ListByCustomer(customerID uuid.UUID, page, limit int, status string) ([]models.Order, int64, error) {
+func (r *orderRepository) ListByCustomer(customerID uuid.UUID, page, limit int, status string) ([]models.Order, int64, error) {
 	var orders []models.Order
 	var total int64
 
@@ -72,7 +94,7 @@ func (r *OrderRepository) ListByCustomer(customerID uuid.UUID, page, limit int,
 	return orders, total, err
 }
 
-func (r *OrderRepository) ListByYandas(yandasID uuid.UUID, page, limit int, status string) ([]models.Order, int64, error) {
+func (r *orderRepository) ListByYandas(yandasID uuid.UUID, page, limit int, status string) ([]models.Order, int64, error) {
 	var orders []models.Order
 	var total int64
 
@@ -95,14 +117,11 @@ func (r *OrderRepository) ListByYandas(yandasID uuid.UUID, page, limit int, stat
 	return orders, total, err
 }
 
-func (r *OrderRepository) ListAll(page, limit int, status string) ([]models.Order, int64, error) {
+func (r *orderRepository) ListAll(filter OrderFilter, page, limit int) ([]models.Order, int64, error) {
 	var orders []models.Order
 	var total int64
 
-	query := r.db.Model(&models.Order{})
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
+	query := filter.apply(r.db.Model(&models.Order{}))
 
 	query.Count(&total)
 
@@ -113,13 +132,60 @@ func (r *OrderRepository) ListAll(page, limit int, status string) ([]models.Orde
 		Preload("Service").
 		Offset(offset).
 		Limit(limit).
-		Order("created_at DESC").
+		Order(filter.order()).
 		Find(&orders).Error
 
 	return orders, total, err
 }
 
-func (r *OrderRepository) UpdateStatus(id uuid.UUID, status string) error {
+// SumRevenue totals the agreed price of the completed orders matching filter
+// per currency, largest first
+func (r *orderRepository) SumRevenue(filter OrderFilter) ([]CurrencyTotal, error) {
+	var totals []CurrencyTotal
+	err := filter.apply(r.db.Model(&models.Order{})).
+		Where("orders.status = ?", "completed").
+		Select("orders.currency, SUM(orders.agreed_price) AS amount, SUM(" + inBaseCurrency("orders.agreed_price") + ") AS base_amount").
+		Group("orders.currency").
+		Order("base_amount DESC").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// StreamForExport loads orders matching filter in batches and passes each batch to fn.
+// City is matched against the yandaş's service cities.
+func (r *orderRepository) StreamForExport(filter ExportFilter, fn func(orders []models.Order) error) error {
+	query := filter.applyDateRange(r.db.Model(&models.Order{}), "orders.created_at")
+	if filter.Status != "" {
+		query = query.Where("orders.status = ?", filter.Status)
+	}
+	if filter.City != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM yandas_profiles WHERE yandas_profiles.id = orders.yandas_id AND ? = ANY(yandas_profiles.service_cities))", filter.City)
+	}
+
+	var batch []models.Order
+	return query.
+		Preload("Customer").
+		Preload("Yandas.User").
+		Preload("Service.Category").
+		FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// ListScheduledByYandas returns the yandaş's orders in the given statuses scheduled within [from, to)
+func (r *orderRepository) ListScheduledByYandas(yandasID uuid.UUID, from, to time.Time, statuses []string) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.db.
+		Preload("Customer").
+		Preload("Service").
+		Where("yandas_id = ? AND status IN ?", yandasID, statuses).
+		Where("scheduled_at >= ? AND scheduled_at < ?", from, to).
+		Order("scheduled_at ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+func (r *orderRepository) UpdateStatus(id uuid.UUID, status string) error {
 	updates := map[string]interface{}{"status": status}
 
 	switch status {
@@ -132,12 +198,61 @@ func (r *OrderRepository) UpdateStatus(id uuid.UUID, status string) error {
 	return r.db.Model(&models.Order{}).Where("id = ?", id).Updates(updates).Error
 }
 
-func (r *OrderRepository) GetStats(yandasID uuid.UUID) (map[string]interface{}, error) {
+// UpdateETA stores the latest arrival estimate of an order without touching updated_at
+func (r *orderRepository) UpdateETA(id uuid.UUID, etaAt time.Time, distanceKm float64) error {
+	return r.db.Model(&models.Order{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"eta_at":          etaAt,
+		"eta_distance_km": distanceKm,
+		"eta_updated_at":  time.Now(),
+	}).Error
+}
+
+// SetConversation links an order to the conversation opened for it
+func (r *orderRepository) SetConversation(id, conversationID uuid.UUID) error {
+	return r.db.Model(&models.Order{}).Where("id = ?", id).Update("conversation_id", conversationID).Error
+}
+
+// MarkEnRoute records that the yandaş set off, only if that wasn't recorded yet
+func (r *orderRepository) MarkEnRoute(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&models.Order{}).
+		Where("id = ? AND en_route_at IS NULL", id).
+		Update("en_route_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListAwaitingConfirmation returns orders whose completion was requested
+// before requestedBefore and is still unconfirmed, oldest first
+func (r *orderRepository) ListAwaitingConfirmation(requestedBefore time.Time, limit int) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.db.
+		Where("status = ? AND completion_requested_at < ?", "pending_confirmation", requestedBefore).
+		Order("completion_requested_at ASC").
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
+
+// CountActiveByService counts the service's orders that are not yet
+// completed or cancelled, including disputed ones
+func (r *orderRepository) CountActiveByService(serviceID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Order{}).
+		Where("service_id = ? AND status NOT IN ?", serviceID, []string{"completed", "cancelled"}).
+		Count(&count).Error
+	return count, err
+}
+
+// AddLineItem appends a priced line to an existing order
+func (r *orderRepository) AddLineItem(item *models.OrderLineItem) error {
+	return r.db.Create(item).Error
+}
+
+func (r *orderRepository) GetStats(yandasID uuid.UUID) (map[string]interface{}, error) {
 	var stats struct {
-		TotalOrders     int64   `json:"total_orders"`
-		CompletedOrders int64   `json:"completed_orders"`
-		TotalRevenue    float64 `json:"total_revenue"`
-		AvgRating       float64 `json:"avg_rating"`
+		TotalOrders     int64          `json:"total_orders"`
+		CompletedOrders int64          `json:"completed_orders"`
+		TotalRevenue    currency.Money `json:"total_revenue"`
+		AvgRating       float64        `json:"avg_rating"`
 	}
 
 	r.db.Model(&models.Order{}).
@@ -150,59 +265,332 @@ func (r *OrderRepository) GetStats(yandasID uuid.UUID) (map[string]interface{},
 
 	r.db.Model(&models.Order{}).
 		Where("yandas_id = ? AND status = ?", yandasID, "completed").
-		Select("COALESCE(SUM(agreed_price), 0)").
+		Select("COALESCE(SUM(" + inBaseCurrency("agreed_price") + "), 0)").
 		Scan(&stats.TotalRevenue)
 
 	return map[string]interface{}{
 		"total_orders":     stats.TotalOrders,
 		"completed_orders": stats.CompletedOrders,
 		"total_revenue":    stats.TotalRevenue,
+		"currency":         currency.Base,
 	}, nil
 }
 
+// AccountingSummary totals the orders completed in [from, to) by top-level
+// category, the yandaş's primary service city and currency, largest first
+func (r *orderRepository) AccountingSummary(from, to time.Time) ([]AccountingRow, error) {
+	var rows []AccountingRow
+	err := r.db.Raw(`
+		SELECT COALESCE(parent.name, category.name, 'unknown') AS category,
+			COALESCE(NULLIF(yandas_profiles.service_cities[1], ''), 'unknown') AS city,
+			orders.currency,
+			COUNT(*) AS orders,
+			COALESCE(SUM(orders.agreed_price), 0) AS gross,
+			COALESCE(SUM(orders.platform_fee), 0) AS platform_fees,
+			COALESCE(SUM(orders.net_earnings), 0) AS net_earnings
+		FROM orders
+		LEFT JOIN yandas_services ON yandas_services.id = orders.service_id
+		LEFT JOIN categories category ON category.id = yandas_services.category_id
+		LEFT JOIN categories parent ON parent.id = category.parent_id
+		LEFT JOIN yandas_profiles ON yandas_profiles.id = orders.yandas_id
+		WHERE orders.deleted_at IS NULL AND orders.status = 'completed'
+			AND orders.completed_at >= ? AND orders.completed_at < ?
+		GROUP BY 1, 2, 3
+		ORDER BY gross DESC, category, city`, from, to).Scan(&rows).Error
+	return rows, err
+}
+
 func generateOrderNumber() string {
 	return fmt.Sprintf("YND%d%04d", time.Now().Unix()%100000, time.Now().Nanosecond()%10000)
 }
 
-// ReviewRepository handles review operations
-type ReviewRepository struct {
+// orderHistoryRepository handles order status history
+type orderHistoryRepository struct {
 	db *gorm.DB
 }
 
-func NewReviewRepository(db *gorm.DB) *ReviewRepository {
-	return &ReviewRepository{db: db}
+func NewOrderHistoryRepository(db *gorm.DB) OrderHistoryRepository {
+	return &orderHistoryRepository{db: db}
 }
 
-func (r *ReviewRepository) Create(review *models.Review) error {
+func (r *orderHistoryRepository) Record(entry *models.OrderStatusHistory) error {
+	return r.db.Create(entry).Error
+}
+
+// ListByOrder returns an order's history, oldest first
+func (r *orderHistoryRepository) ListByOrder(orderID uuid.UUID) ([]models.OrderStatusHistory, error) {
+	var entries []models.OrderStatusHistory
+	err := r.db.
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// LastEvent returns the most recent entry of the given event for an order
+func (r *orderHistoryRepository) LastEvent(orderID uuid.UUID, event string) (*models.OrderStatusHistory, error) {
+	var entry models.OrderStatusHistory
+	err := r.db.
+		Where("order_id = ? AND event = ?", orderID, event).
+		Order("created_at DESC").
+		First(&entry).Error
+	return &entry, err
+}
+
+// reviewRepository handles review operations
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+func (r *reviewRepository) Create(review *models.Review) error {
 	return r.db.Create(review).Error
 }
 
-func (r *ReviewRepository) GetByOrderID(orderID uuid.UUID) (*models.Review, error) {
+func (r *reviewRepository) GetByOrderID(orderID uuid.UUID) (*models.Review, error) {
 	var review models.Review
 	err := r.db.Preload("Reviewer").First(&review, "order_id = ?", orderID).Error
 	return &review, err
 }
 
-func (r *ReviewRepository) ListByReviewee(revieweeID uuid.UUID, page, limit int) ([]models.Review, int64, error) {
+func (r *reviewRepository) GetByID(id uuid.UUID) (*models.Review, error) {
+	var review models.Review
+	err := r.db.First(&review, "id = ?", id).Error
+	return &review, err
+}
+
+// ListByReviewee returns the visible reviews sorted by "helpful" or, by default, most recent first
+func (r *reviewRepository) ListByReviewee(revieweeID uuid.UUID, page, limit int, sort string) ([]models.Review, int64, error) {
 	var reviews []models.Review
 	var total int64
 
-	query := r.db.Model(&models.Review{}).Where("reviewee_id = ?", revieweeID)
+	query := r.db.Model(&models.Review{}).Where("reviewee_id = ? AND is_hidden = ?", revieweeID, false)
 	query.Count(&total)
 
+	order := "created_at DESC"
+	if sort == "helpful" {
+		order = "helpful_count DESC, created_at DESC"
+	}
+
 	offset := (page - 1) * limit
 	err := query.
 		Preload("Reviewer").
 		Offset(offset).
 		Limit(limit).
-		Order("created_at DESC").
+		Order(order).
+		Find(&reviews).Error
+
+	return reviews, total, err
+}
+
+// ListAll returns reviews matching filter, hidden ones included (admin view)
+func (r *reviewRepository) ListAll(filter ReviewFilter, page, limit int) ([]models.Review, int64, error) {
+	var reviews []models.Review
+	var total int64
+
+	query := filter.apply(r.db.Model(&models.Review{}))
+
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.
+		Preload("Reviewer").
+		Offset(offset).
+		Limit(limit).
+		Order(filter.order()).
 		Find(&reviews).Error
 
 	return reviews, total, err
 }
 
-func (r *ReviewRepository) ExistsByOrderID(orderID uuid.UUID) bool {
+// SetHidden hides a review from public listings and the rating average, or shows it again
+func (r *reviewRepository) SetHidden(id uuid.UUID, hidden bool, by uuid.UUID) error {
+	updates := map[string]interface{}{"is_hidden": false, "hidden_at": nil, "hidden_by": nil}
+	if hidden {
+		updates = map[string]interface{}{"is_hidden": true, "hidden_at": time.Now(), "hidden_by": by}
+	}
+	return r.db.Model(&models.Review{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// Delete removes a review along with its helpful votes
+func (r *reviewRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("review_id = ?", id).Delete(&models.ReviewVote{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Review{}, "id = ?", id).Error
+	})
+}
+
+// SetReply stores the reply only if the review has none yet
+func (r *reviewRepository) SetReply(id uuid.UUID, reply string) (bool, error) {
+	result := r.db.Model(&models.Review{}).
+		Where("id = ? AND reply IS NULL", id).
+		Updates(map[string]interface{}{"reply": reply, "replied_at": time.Now()})
+	return result.RowsAffected > 0, result.Error
+}
+
+// AddHelpfulVote records a vote and bumps the counter; repeat votes are ignored
+func (r *reviewRepository) AddHelpfulVote(reviewID, userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.ReviewVote{ReviewID: reviewID, UserID: userID})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&models.Review{}).Where("id = ?", reviewID).
+			UpdateColumn("helpful_count", gorm.Expr("helpful_count + 1")).Error
+	})
+}
+
+// RemoveHelpfulVote deletes a vote and decrements the counter if one existed
+func (r *reviewRepository) RemoveHelpfulVote(reviewID, userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("review_id = ? AND user_id = ?", reviewID, userID).Delete(&models.ReviewVote{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&models.Review{}).Where("id = ? AND helpful_count > 0", reviewID).
+			UpdateColumn("helpful_count", gorm.Expr("helpful_count - 1")).Error
+	})
+}
+
+func (r *reviewRepository) ExistsByOrderID(orderID uuid.UUID) bool {
 	var count int64
 	r.db.Model(&models.Review{}).Where("order_id = ?", orderID).Count(&count)
 	return count > 0
 }
+
+// ReviewStats aggregates the visible reviews about one user
+type ReviewStats struct {
+	Total         int64            `json:"total"`
+	Average       float64          `json:"average"`
+	Distribution  map[int]int64    `json:"distribution"`  // review count per star, 1-5
+	Punctuality   *float64         `json:"punctuality"`   // nil until some review rates it
+	Communication *float64         `json:"communication"` // nil until some review rates it
+	Quality       *float64         `json:"quality"`       // nil until some review rates it
+	Trend         []ReviewTrendRow `json:"trend"`
+}
+
+// ReviewTrendRow aggregates the reviews of one calendar month
+type ReviewTrendRow struct {
+	Month   string  `json:"month"` // YYYY-MM
+	Count   int64   `json:"count"`
+	Average float64 `json:"average"`
+}
+
+// Stats aggregates the visible reviews about revieweeID; the trend covers the
+// months since trendFrom that have reviews, oldest first
+func (r *reviewRepository) Stats(revieweeID uuid.UUID, trendFrom time.Time) (*ReviewStats, error) {
+	visible := r.db.Model(&models.Review{}).Where("reviewee_id = ? AND is_hidden = ?", revieweeID, false)
+
+	var stars []struct {
+		Rating int
+		Count  int64
+	}
+	if err := visible.Session(&gorm.Session{}).
+		Select("rating, COUNT(*) AS count").
+		Group("rating").
+		Scan(&stars).Error; err != nil {
+		return nil, err
+	}
+
+	stats := &ReviewStats{Distribution: map[int]int64{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}, Trend: []ReviewTrendRow{}}
+	var sum int64
+	for _, s := range stars {
+		stats.Distribution[s.Rating] = s.Count
+		stats.Total += s.Count
+		sum += int64(s.Rating) * s.Count
+	}
+	if stats.Total > 0 {
+		stats.Average = float64(sum) / float64(stats.Total)
+	}
+
+	var criteria struct {
+		Punctuality   *float64
+		Communication *float64
+		Quality       *float64
+	}
+	if err := visible.Session(&gorm.Session{}).
+		Select("AVG(punctuality_rating) AS punctuality, AVG(communication_rating) AS communication, AVG(quality_rating) AS quality").
+		Scan(&criteria).Error; err != nil {
+		return nil, err
+	}
+	stats.Punctuality, stats.Communication, stats.Quality = criteria.Punctuality, criteria.Communication, criteria.Quality
+
+	err := visible.Session(&gorm.Session{}).
+		Select("to_char(date_trunc('month', created_at), 'YYYY-MM') AS month, COUNT(*) AS count, AVG(rating) AS average").
+		Where("created_at >= ?", trendFrom).
+		Group("month").
+		Order("month").
+		Scan(&stats.Trend).Error
+	return stats, err
+}
+
+// recurringOrderRepository handles recurring order series
+type recurringOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewRecurringOrderRepository(db *gorm.DB) RecurringOrderRepository {
+	return &recurringOrderRepository{db: db}
+}
+
+func (r *recurringOrderRepository) Create(recurring *models.RecurringOrder) error {
+	return r.db.Create(recurring).Error
+}
+
+func (r *recurringOrderRepository) GetByID(id uuid.UUID) (*models.RecurringOrder, error) {
+	var recurring models.RecurringOrder
+	err := r.db.Preload("Yandas.User").Preload("Service").First(&recurring, "id = ?", id).Error
+	return &recurring, err
+}
+
+func (r *recurringOrderRepository) ListByCustomer(customerID uuid.UUID, page, limit int) ([]models.RecurringOrder, int64, error) {
+	var series []models.RecurringOrder
+	var total int64
+
+	query := r.db.Model(&models.RecurringOrder{}).Where("customer_id = ?", customerID)
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.
+		Preload("Yandas.User").
+		Preload("Service").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&series).Error
+
+	return series, total, err
+}
+
+func (r *recurringOrderRepository) Update(recurring *models.RecurringOrder) error {
+	return r.db.Omit(clause.Associations).Save(recurring).Error
+}
+
+// ListDue returns active series whose next occurrence is before the given time
+func (r *recurringOrderRepository) ListDue(before time.Time, limit int) ([]models.RecurringOrder, error) {
+	var series []models.RecurringOrder
+	err := r.db.
+		Where("status = ? AND next_run_at <= ?", "active", before).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&series).Error
+	return series, err
+}
+
+// ClaimOccurrence advances the series past scheduledFor. It reports false when another
+// worker already claimed that occurrence or the series is no longer active.
+func (r *recurringOrderRepository) ClaimOccurrence(id uuid.UUID, scheduledFor, next time.Time) (bool, error) {
+	result := r.db.Model(&models.RecurringOrder{}).
+		Where("id = ? AND status = ? AND next_run_at = ?", id, "active", scheduledFor).
+		Updates(map[string]interface{}{
+			"next_run_at":      next,
+			"occurrence_count": gorm.Expr("occurrence_count + 1"),
+		})
+	return result.RowsAffected == 1, result.Error
+}
@@ -1,99 +1,206 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// SubscriptionRepository handles subscription operations
-type SubscriptionRepository struct {
+// subscriptionRepository handles subscription operations
+type subscriptionRepository struct {
 	db *gorm.DB
 }
 
-func NewSubscriptionRepository(db *gorm.DB) *SubscriptionRepository {
-	return &SubscriptionRepository{db: db}
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepository {
+	return &subscriptionRepository{db: db}
 }
 
-func (r *SubscriptionRepository) Create(sub *models.Subscription) error {
+func (r *subscriptionRepository) Create(sub *models.Subscription) error {
 	return r.db.Create(sub).Error
 }
 
-func (r *SubscriptionRepository) GetByUserID(userID uuid.UUID) (*models.Subscription, error) {
+func (r *subscriptionRepository) GetByUserID(userID uuid.UUID) (*models.Subscription, error) {
 	var sub models.Subscription
 	err := r.db.Where("user_id = ? AND status = ?", userID, "active").First(&sub).Error
 	return &sub, err
 }
 
-func (r *SubscriptionRepository) GetByProviderID(providerID string) (*models.Subscription, error) {
+func (r *subscriptionRepository) GetByProviderID(providerID string) (*models.Subscription, error) {
 	var sub models.Subscription
 	err := r.db.First(&sub, "provider_subscription_id = ?", providerID).Error
 	return &sub, err
 }
 
-func (r *SubscriptionRepository) Update(sub *models.Subscription) error {
+func (r *subscriptionRepository) Update(sub *models.Subscription) error {
 	return r.db.Save(sub).Error
 }
 
-func (r *SubscriptionRepository) Cancel(id uuid.UUID) error {
+func (r *subscriptionRepository) Cancel(id uuid.UUID) error {
 	return r.db.Model(&models.Subscription{}).
 		Where("id = ?", id).
 		Update("status", "cancelled").Error
 }
 
-// DeviceTokenRepository handles device token operations
-type DeviceTokenRepository struct {
+// deviceTokenRepository handles device token operations
+type deviceTokenRepository struct {
 	db *gorm.DB
 }
 
-func NewDeviceTokenRepository(db *gorm.DB) *DeviceTokenRepository {
-	return &DeviceTokenRepository{db: db}
+func NewDeviceTokenRepository(db *gorm.DB) DeviceTokenRepository {
+	return &deviceTokenRepository{db: db}
 }
 
-func (r *DeviceTokenRepository) Create(token *models.DeviceToken) error {
+func (r *deviceTokenRepository) Create(token *models.DeviceToken) error {
 	// First try to find existing token
 	var existing models.DeviceToken
 	err := r.db.Where("user_id = ? AND token = ?", token.UserID, token.Token).First(&existing).Error
 	if err == nil {
 		// Token exists, update it
 		existing.IsActive = true
+		existing.SessionID = token.SessionID
 		return r.db.Save(&existing).Error
 	}
 	return r.db.Create(token).Error
 }
 
-func (r *DeviceTokenRepository) GetByUserID(userID uuid.UUID) ([]models.DeviceToken, error) {
+func (r *deviceTokenRepository) GetByUserID(userID uuid.UUID) ([]models.DeviceToken, error) {
 	var tokens []models.DeviceToken
 	err := r.db.Where("user_id = ? AND is_active = ?", userID, true).Find(&tokens).Error
 	return tokens, err
 }
 
-func (r *DeviceTokenRepository) Deactivate(token string) error {
+func (r *deviceTokenRepository) Deactivate(token string) error {
 	return r.db.Model(&models.DeviceToken{}).
 		Where("token = ?", token).
 		Update("is_active", false).Error
 }
 
-func (r *DeviceTokenRepository) DeactivateAllForUser(userID uuid.UUID) error {
+func (r *deviceTokenRepository) DeactivateAllForUser(userID uuid.UUID) error {
 	return r.db.Model(&models.DeviceToken{}).
 		Where("user_id = ?", userID).
 		Update("is_active", false).Error
 }
 
-// AuditLogRepository handles audit log operations
-type AuditLogRepository struct {
+func (r *deviceTokenRepository) DeactivateForSession(sessionID uuid.UUID) error {
+	return r.db.Model(&models.DeviceToken{}).
+		Where("session_id = ?", sessionID).
+		Update("is_active", false).Error
+}
+
+// sessionRepository handles signed-in device sessions
+type sessionRepository struct {
 	db *gorm.DB
 }
 
-func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
-	return &AuditLogRepository{db: db}
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(session *models.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *sessionRepository) GetByID(id uuid.UUID) (*models.Session, error) {
+	var session models.Session
+	err := r.db.First(&session, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) ListActiveForUser(userID uuid.UUID) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Rotate swaps the stored refresh token hash only if it still matches oldHash,
+// so a refresh token can be exchanged at most once.
+func (r *sessionRepository) Rotate(id uuid.UUID, oldHash, newHash, ip string, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", id, oldHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash": newHash,
+			"ip_address":         ip,
+			"last_seen_at":       time.Now(),
+			"expires_at":         expiresAt,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *sessionRepository) Revoke(id uuid.UUID) error {
+	return r.db.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAllForUser revokes every live session of a user and returns their ids
+func (r *sessionRepository) RevokeAllForUser(userID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Raw(`
+		UPDATE sessions SET revoked_at = ?
+		WHERE user_id = ? AND revoked_at IS NULL
+		RETURNING id`, time.Now(), userID).
+		Scan(&ids).Error
+	return ids, err
 }
 
-func (r *AuditLogRepository) Create(log *models.AuditLog) error {
+// contactChangeRepository handles pending email/phone changes
+type contactChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewContactChangeRepository(db *gorm.DB) ContactChangeRepository {
+	return &contactChangeRepository{db: db}
+}
+
+// Save replaces any earlier pending change on the same channel
+func (r *contactChangeRepository) Save(change *models.ContactChange) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "channel"}},
+		DoUpdates: clause.AssignmentColumns([]string{"new_value", "code_hash", "attempts", "expires_at", "created_at"}),
+	}).Create(change).Error
+}
+
+func (r *contactChangeRepository) Get(userID uuid.UUID, channel string) (*models.ContactChange, error) {
+	var change models.ContactChange
+	err := r.db.Where("user_id = ? AND channel = ?", userID, channel).First(&change).Error
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (r *contactChangeRepository) IncrementAttempts(id uuid.UUID) error {
+	return r.db.Model(&models.ContactChange{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+func (r *contactChangeRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.ContactChange{}, "id = ?", id).Error
+}
+
+// auditLogRepository handles audit log operations
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(log *models.AuditLog) error {
 	return r.db.Create(log).Error
 }
 
-func (r *AuditLogRepository) List(page, limit int, adminID *uuid.UUID, action string) ([]models.AuditLog, int64, error) {
+func (r *auditLogRepository) List(page, limit int, adminID *uuid.UUID, action string) ([]models.AuditLog, int64, error) {
 	var logs []models.AuditLog
 	var total int64
 
@@ -118,20 +225,20 @@ func (r *AuditLogRepository) List(page, limit int, adminID *uuid.UUID, action st
 	return logs, total, err
 }
 
-// NotificationRepository handles notification operations
-type NotificationRepository struct {
+// notificationRepository handles notification operations
+type notificationRepository struct {
 	db *gorm.DB
 }
 
-func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
-	return &NotificationRepository{db: db}
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
 }
 
-func (r *NotificationRepository) Create(notif *models.Notification) error {
+func (r *notificationRepository) Create(notif *models.Notification) error {
 	return r.db.Create(notif).Error
 }
 
-func (r *NotificationRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.Notification, int64, error) {
+func (r *notificationRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.Notification, int64, error) {
 	var notifs []models.Notification
 	var total int64
 
@@ -148,22 +255,125 @@ func (r *NotificationRepository) ListByUser(userID uuid.UUID, page, limit int) (
 	return notifs, total, err
 }
 
-func (r *NotificationRepository) MarkAsRead(id uuid.UUID) error {
+func (r *notificationRepository) MarkAsRead(id uuid.UUID) error {
 	return r.db.Model(&models.Notification{}).
 		Where("id = ?", id).
 		Update("is_read", true).Error
 }
 
-func (r *NotificationRepository) MarkAllAsRead(userID uuid.UUID) error {
+func (r *notificationRepository) MarkAllAsRead(userID uuid.UUID) error {
 	return r.db.Model(&models.Notification{}).
 		Where("user_id = ? AND is_read = ?", userID, false).
 		Update("is_read", true).Error
 }
 
-func (r *NotificationRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
+func (r *notificationRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
 	var count int64
 	err := r.db.Model(&models.Notification{}).
 		Where("user_id = ? AND is_read = ?", userID, false).
 		Count(&count).Error
 	return count, err
 }
+
+// DigestRecipients returns users with unread notifications created since
+// since who have an email address and have not used the app since inactiveSince
+func (r *notificationRepository) DigestRecipients(since, inactiveSince time.Time) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.Model(&models.Notification{}).
+		Distinct("notifications.user_id").
+		Joins("JOIN users ON users.id = notifications.user_id").
+		Where("notifications.is_read = ? AND notifications.created_at >= ?", false, since).
+		Where("users.is_active = ? AND users.deleted_at IS NULL AND users.email IS NOT NULL AND users.email_undeliverable_at IS NULL", true).
+		Where("NOT EXISTS (SELECT 1 FROM sessions WHERE sessions.user_id = notifications.user_id AND sessions.last_seen_at >= ?)", inactiveSince).
+		Pluck("notifications.user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// ListUnreadSince returns a user's unread notifications created since since, newest first
+func (r *notificationRepository) ListUnreadSince(userID uuid.UUID, since time.Time, limit int) ([]models.Notification, error) {
+	var notifs []models.Notification
+	err := r.db.Where("user_id = ? AND is_read = ? AND created_at >= ?", userID, false, since).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&notifs).Error
+	return notifs, err
+}
+
+// notificationPreferenceRepository handles notification preference operations
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+func (r *notificationPreferenceRepository) ListByUser(userID uuid.UUID) ([]models.NotificationPreference, error) {
+	var prefs []models.NotificationPreference
+	err := r.db.Where("user_id = ?", userID).Find(&prefs).Error
+	return prefs, err
+}
+
+func (r *notificationPreferenceRepository) GetByUserAndType(userID uuid.UUID, notifType string) (*models.NotificationPreference, error) {
+	var pref models.NotificationPreference
+	err := r.db.First(&pref, "user_id = ? AND type = ?", userID, notifType).Error
+	return &pref, err
+}
+
+// Upsert creates the preference row or overwrites the channel flags of an existing one
+func (r *notificationPreferenceRepository) Upsert(pref *models.NotificationPreference) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"push_enabled", "email_enabled", "sms_enabled", "batch_enabled", "digest_enabled", "updated_at"}),
+	}).Create(pref).Error
+}
+
+// documentScreeningRepository handles OCR pre-screen results
+type documentScreeningRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentScreeningRepository(db *gorm.DB) DocumentScreeningRepository {
+	return &documentScreeningRepository{db: db}
+}
+
+func (r *documentScreeningRepository) GetByProfileID(profileID uuid.UUID) (*models.DocumentScreening, error) {
+	var screening models.DocumentScreening
+	err := r.db.First(&screening, "yandas_profile_id = ?", profileID).Error
+	return &screening, err
+}
+
+// Upsert stores the latest screening for a profile, replacing any previous result
+func (r *documentScreeningRepository) Upsert(screening *models.DocumentScreening) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "yandas_profile_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"provider", "status", "extracted_name", "id_number_masked",
+			"id_number_valid", "name_match", "error", "updated_at",
+		}),
+	}).Create(screening).Error
+}
+
+// receiptRepository handles order receipts
+type receiptRepository struct {
+	db *gorm.DB
+}
+
+func NewReceiptRepository(db *gorm.DB) ReceiptRepository {
+	return &receiptRepository{db: db}
+}
+
+func (r *receiptRepository) GetByOrderID(orderID uuid.UUID) (*models.Receipt, error) {
+	var receipt models.Receipt
+	err := r.db.First(&receipt, "order_id = ?", orderID).Error
+	return &receipt, err
+}
+
+// Create stores a receipt; a concurrent insert for the same order is ignored
+func (r *receiptRepository) Create(receipt *models.Receipt) error {
+	return r.db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "order_id"}}, DoNothing: true}).Create(receipt).Error
+}
+
+func (r *receiptRepository) Update(receipt *models.Receipt) error {
+	return r.db.Save(receipt).Error
+}
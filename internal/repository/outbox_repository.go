@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// outboxRepository handles WebSocket and push messages awaiting delivery
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Create(message *models.OutboxMessage) error {
+	return r.db.Create(message).Error
+}
+
+// ClaimDue leases up to limit pending messages whose next attempt is due, oldest
+// first. Leased rows get their next attempt pushed out by lease so other
+// instances skip them.
+func (r *outboxRepository) ClaimDue(limit int, lease time.Duration) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+	err := r.db.Raw(`
+		UPDATE outbox_messages SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_messages
+			WHERE status = 'pending' AND next_attempt_at <= ?
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, time.Now().Add(lease), time.Now(), limit).
+		Scan(&messages).Error
+	return messages, err
+}
+
+func (r *outboxRepository) Update(message *models.OutboxMessage) error {
+	return r.db.Save(message).Error
+}
+
+// PruneDelivered deletes messages delivered before the given time
+func (r *outboxRepository) PruneDelivered(before time.Time) error {
+	return r.db.Where("status = 'delivered' AND delivered_at < ?", before).Delete(&models.OutboxMessage{}).Error
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+	"gorm.io/gorm"
+)
+
+// PayoutBalance is a yandaş's earnings not yet included in a payout, per currency
+type PayoutBalance struct {
+	YandasID uuid.UUID      `json:"yandas_id"`
+	Currency string         `json:"currency"`
+	Amount   currency.Money `json:"amount"`
+	Entries  int64          `json:"entries"`
+}
+
+// payoutRepository handles the earnings ledger and payouts
+type payoutRepository struct {
+	db *gorm.DB
+}
+
+func NewPayoutRepository(db *gorm.DB) PayoutRepository {
+	return &payoutRepository{db: db}
+}
+
+func (r *payoutRepository) CreateEntry(entry *models.PayoutEntry) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *payoutRepository) ListEntries(yandasID uuid.UUID, page, limit int) ([]models.PayoutEntry, int64, error) {
+	var entries []models.PayoutEntry
+	var total int64
+
+	query := r.db.Model(&models.PayoutEntry{}).Where("yandas_id = ?", yandasID)
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&entries).Error
+
+	return entries, total, err
+}
+
+// UnpaidBalances sums unpaid entries per yandaş and currency, largest first.
+// A nil yandasID returns balances for everyone.
+func (r *payoutRepository) UnpaidBalances(yandasID *uuid.UUID) ([]PayoutBalance, error) {
+	var balances []PayoutBalance
+	query := r.db.Model(&models.PayoutEntry{}).
+		Select("yandas_id, currency, SUM(net_amount) AS amount, COUNT(*) AS entries").
+		Where("payout_id IS NULL")
+	if yandasID != nil {
+		query = query.Where("yandas_id = ?", *yandasID)
+	}
+	err := query.Group("yandas_id, currency").Order("amount DESC").Scan(&balances).Error
+	return balances, err
+}
+
+// PaidTotal sums transferred payouts for a yandaş in a currency
+func (r *payoutRepository) PaidTotal(yandasID uuid.UUID, currencyCode string) (currency.Money, error) {
+	var total currency.Money
+	err := r.db.Model(&models.Payout{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("yandas_id = ? AND currency = ? AND status = ?", yandasID, currencyCode, "transferred").
+		Scan(&total).Error
+	return total, err
+}
+
+// AttachUnpaidEntries assigns all unpaid entries of a yandaş in a currency to a payout
+// and returns their net total. Run it in the same transaction that creates the payout.
+func (r *payoutRepository) AttachUnpaidEntries(payoutID, yandasID uuid.UUID, currencyCode string) (currency.Money, error) {
+	var amounts []currency.Money
+	err := r.db.Raw(`
+		UPDATE payout_entries SET payout_id = ?
+		WHERE payout_id IS NULL AND yandas_id = ? AND currency = ?
+		RETURNING net_amount`, payoutID, yandasID, currencyCode).
+		Scan(&amounts).Error
+
+	var total currency.Money
+	for _, a := range amounts {
+		total += a
+	}
+	return total, err
+}
+
+func (r *payoutRepository) Create(payout *models.Payout) error {
+	return r.db.Create(payout).Error
+}
+
+func (r *payoutRepository) GetByID(id uuid.UUID) (*models.Payout, error) {
+	var payout models.Payout
+	err := r.db.
+		Preload("Yandas.User").
+		Preload("Entries", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		First(&payout, "id = ?", id).Error
+	return &payout, err
+}
+
+func (r *payoutRepository) Update(payout *models.Payout) error {
+	return r.db.Omit("Yandas", "Entries").Save(payout).Error
+}
+
+func (r *payoutRepository) List(page, limit int, status string, yandasID *uuid.UUID) ([]models.Payout, int64, error) {
+	var payouts []models.Payout
+	var total int64
+
+	query := r.db.Model(&models.Payout{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if yandasID != nil {
+		query = query.Where("yandas_id = ?", *yandasID)
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Preload("Yandas.User").Order("created_at DESC").Offset(offset).Limit(limit).Find(&payouts).Error
+
+	return payouts, total, err
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// Sort orders for the admin yandaş leaderboard
+const (
+	QualitySortScore         = "score" // best first
+	QualitySortScoreAsc      = "score_asc"
+	QualitySortRating        = "rating"
+	QualitySortCompletion    = "completion"
+	QualitySortResponse      = "response" // fastest first
+	QualitySortDisputes      = "disputes" // most first
+	QualitySortCancellations = "cancellations"
+	QualitySortOrders        = "orders"
+)
+
+// QualityFilter narrows the admin yandaş leaderboard; zero values are ignored
+type QualityFilter struct {
+	City         string // one of the yandaş's service cities
+	CategorySlug string // also matches services in the category's subcategories
+	MinOrders    int    // orders in the scoring window
+	MinScore     *float64
+	MaxScore     *float64
+	Available    *bool
+	Sort         string
+}
+
+// apply adds the filter's conditions to a query over yandas_quality_scores
+// joined with yandas_profiles
+func (f QualityFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.City != "" {
+		query = query.Where("? = ANY(yandas_profiles.service_cities)", f.City)
+	}
+	if f.CategorySlug != "" {
+		query = query.Where(`EXISTS (SELECT 1 FROM yandas_services WHERE yandas_services.yandas_id = yandas_profiles.id AND yandas_services.category_id IN (
+			SELECT id FROM categories WHERE slug = ?
+			UNION SELECT id FROM categories WHERE parent_id = (SELECT id FROM categories WHERE slug = ?)))`,
+			f.CategorySlug, f.CategorySlug)
+	}
+	if f.MinOrders > 0 {
+		query = query.Where("yandas_quality_scores.order_count >= ?", f.MinOrders)
+	}
+	if f.MinScore != nil {
+		query = query.Where("yandas_quality_scores.score >= ?", *f.MinScore)
+	}
+	if f.MaxScore != nil {
+		query = query.Where("yandas_quality_scores.score <= ?", *f.MaxScore)
+	}
+	if f.Available != nil {
+		query = query.Where("yandas_profiles.is_available = ?", *f.Available)
+	}
+	return query
+}
+
+// order returns the ORDER BY for the filter's sort, best score first by default
+func (f QualityFilter) order() string {
+	switch f.Sort {
+	case QualitySortScoreAsc:
+		return "yandas_quality_scores.score ASC, yandas_quality_scores.order_count DESC"
+	case QualitySortRating:
+		return "yandas_quality_scores.rating_avg DESC, yandas_quality_scores.review_count DESC"
+	case QualitySortCompletion:
+		return "yandas_quality_scores.completion_rate DESC, yandas_quality_scores.score DESC"
+	case QualitySortResponse:
+		return "yandas_quality_scores.response_minutes ASC NULLS LAST, yandas_quality_scores.score DESC"
+	case QualitySortDisputes:
+		return "yandas_quality_scores.dispute_rate DESC, yandas_quality_scores.disputed_count DESC"
+	case QualitySortCancellations:
+		return "yandas_quality_scores.cancellation_rate DESC, yandas_quality_scores.cancelled_count DESC"
+	case QualitySortOrders:
+		return "yandas_quality_scores.order_count DESC, yandas_quality_scores.score DESC"
+	default:
+		return "yandas_quality_scores.score DESC, yandas_quality_scores.order_count DESC"
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type qualityRepository struct {
+	db *gorm.DB
+}
+
+func NewQualityRepository(db *gorm.DB) QualityRepository {
+	return &qualityRepository{db: db}
+}
+
+// LeaderboardEntry is a yandaş's quality score with who they are
+type LeaderboardEntry struct {
+	models.YandasQualityScore
+	FullName      string         `json:"full_name"`
+	Slug          *string        `json:"slug,omitempty"`
+	IsAvailable   bool           `json:"is_available"`
+	ServiceCities pq.StringArray `gorm:"type:text[]" json:"service_cities"`
+}
+
+// Count each approved yandaş's orders created since the window start by how
+// they ended, and the median minutes from creation to acceptance. An order
+// counts as disputed when it is, or when the customer opened an order ticket
+// about it; only cancellations by the yandaş count against them.
+const qualityMetricsQuery = `
+	WITH windowed AS (
+		SELECT o.yandas_id,
+			COUNT(*) AS order_count,
+			COUNT(*) FILTER (WHERE o.status IN ('completed', 'cancelled', 'disputed')) AS ended_count,
+			COUNT(*) FILTER (WHERE o.status = 'completed') AS completed_count,
+			COUNT(*) FILTER (WHERE o.status = 'cancelled' AND o.cancelled_by = p.user_id) AS cancelled_count,
+			COUNT(*) FILTER (WHERE o.status = 'disputed' OR EXISTS (
+				SELECT 1 FROM support_tickets t WHERE t.order_id = o.id AND t.category = 'order')) AS disputed_count,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM accepted.at - o.created_at) / 60) AS response_minutes
+		FROM orders o
+		JOIN yandas_profiles p ON p.id = o.yandas_id
+		LEFT JOIN LATERAL (
+			SELECT MIN(h.created_at) AS at FROM order_status_history h WHERE h.order_id = o.id AND h.event = 'accepted'
+		) accepted ON true
+		WHERE o.created_at >= ? AND o.deleted_at IS NULL
+		GROUP BY o.yandas_id
+	)
+	SELECT p.id AS yandas_id, p.rating_avg,
+		(SELECT COUNT(*) FROM reviews r WHERE r.reviewee_id = p.user_id AND NOT r.is_hidden) AS review_count,
+		COALESCE(w.order_count, 0) AS order_count,
+		COALESCE(w.ended_count, 0) AS ended_count,
+		COALESCE(w.completed_count, 0) AS completed_count,
+		COALESCE(w.cancelled_count, 0) AS cancelled_count,
+		COALESCE(w.disputed_count, 0) AS disputed_count,
+		w.response_minutes
+	FROM yandas_profiles p
+	LEFT JOIN windowed w ON w.yandas_id = p.id
+	WHERE p.approval_status = 'approved'`
+
+// Metrics returns the raw counts of every approved yandaş over orders created
+// since; rates, Score and ComputedAt are left for the caller
+func (r *qualityRepository) Metrics(since time.Time) ([]models.YandasQualityScore, error) {
+	var rows []models.YandasQualityScore
+	err := r.db.Raw(qualityMetricsQuery, since).Scan(&rows).Error
+	return rows, err
+}
+
+// Replace swaps the stored scores for rows in one transaction
+func (r *qualityRepository) Replace(rows []models.YandasQualityScore) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.YandasQualityScore{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 500).Error
+	})
+}
+
+// Leaderboard returns scored yandaşlar matching filter
+func (r *qualityRepository) Leaderboard(filter QualityFilter, page, limit int) ([]LeaderboardEntry, int64, error) {
+	var entries []LeaderboardEntry
+	var total int64
+
+	query := filter.apply(r.db.Model(&models.YandasQualityScore{}).
+		Joins("JOIN yandas_profiles ON yandas_profiles.id = yandas_quality_scores.yandas_id").
+		Joins("JOIN users ON users.id = yandas_profiles.user_id AND users.deleted_at IS NULL"))
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.
+		Select("yandas_quality_scores.*, users.full_name, yandas_profiles.slug, yandas_profiles.is_available, yandas_profiles.service_cities").
+		Order(filter.order()).
+		Offset(offset).
+		Limit(limit).
+		Scan(&entries).Error
+	return entries, total, err
+}
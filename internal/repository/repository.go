@@ -2,42 +2,136 @@ package repository
 
 import (
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 )
 
-// Repositories holds all repository instances
+// Repositories holds all repositories behind their interfaces
 type Repositories struct {
-	User          *UserRepository
-	YandasProfile *YandasProfileRepository
-	Category      *CategoryRepository
-	Service       *ServiceRepository
-	Order         *OrderRepository
-	Review        *ReviewRepository
-	Conversation  *ConversationRepository
-	Message       *MessageRepository
-	Subscription  *SubscriptionRepository
-	DeviceToken   *DeviceTokenRepository
-	AuditLog      *AuditLogRepository
-	Notification  *NotificationRepository
-	Support       *SupportRepository
-	Favorite      *FavoriteRepository
+	User                   UserRepository
+	YandasProfile          YandasProfileRepository
+	Category               CategoryRepository
+	ApplicationForm        ApplicationFormRepository
+	DocumentRule           DocumentRuleRepository
+	Service                ServiceRepository
+	Order                  OrderRepository
+	OrderHistory           OrderHistoryRepository
+	OrderAdjustment        OrderAdjustmentRepository
+	RecurringOrder         RecurringOrderRepository
+	JobRequest             JobRequestRepository
+	Assignment             AssignmentRepository
+	Review                 ReviewRepository
+	Conversation           ConversationRepository
+	Message                MessageRepository
+	Subscription           SubscriptionRepository
+	DeviceToken            DeviceTokenRepository
+	Session                SessionRepository
+	ContactChange          ContactChangeRepository
+	AuditLog               AuditLogRepository
+	Notification           NotificationRepository
+	Announcement           AnnouncementRepository
+	LegalDocument          LegalDocumentRepository
+	Consent                ConsentRepository
+	Support                SupportRepository
+	Favorite               FavoriteRepository
+	NotificationPreference NotificationPreferenceRepository
+	CallLog                CallLogRepository
+	Address                AddressRepository
+	Role                   RoleRepository
+	DocumentScreening      DocumentScreeningRepository
+	DocumentRenewal        DocumentRenewalRepository
+	Webhook                WebhookRepository
+	SMSDelivery            SMSDeliveryRepository
+	Payout                 PayoutRepository
+	ExchangeRate           ExchangeRateRepository
+	Receipt                ReceiptRepository
+	Monitoring             MonitoringRepository
+	Analytics              AnalyticsRepository
+	Demand                 DemandRepository
+	Quality                QualityRepository
+	Retention              RetentionRepository
+	Backup                 BackupRepository
+	Activity               ActivityRepository
+	Setting                SettingRepository
+	Sitemap                SitemapRepository
+	DomainEvent            DomainEventRepository
+	Outbox                 OutboxRepository
+	System                 SystemRepository
+	UnitOfWork             UnitOfWork
+
+	primary *Repositories
+}
+
+// NewRepositories creates all repositories. Reads go to a read replica when one
+// is configured; OnPrimary returns the same repositories pinned to the primary.
+// Message content is encrypted at rest with keys, or stored in plaintext when
+// keys is nil.
+func NewRepositories(db *gorm.DB, keys *MessageKeys) *Repositories {
+	repos := newRepositories(db, keys)
+	repos.primary = newRepositories(db.Clauses(dbresolver.Write).Session(&gorm.Session{}), keys)
+	return repos
+}
+
+// OnPrimary returns repositories whose reads go to the primary database. Use it
+// for read-modify-write paths and reads that must see a write made just before,
+// which a lagging replica may not have yet.
+func (r *Repositories) OnPrimary() *Repositories {
+	if r.primary == nil {
+		return r
+	}
+	return r.primary
 }
 
-// NewRepositories creates all repositories
-func NewRepositories(db *gorm.DB) *Repositories {
+func newRepositories(db *gorm.DB, keys *MessageKeys) *Repositories {
 	return &Repositories{
-		User:          NewUserRepository(db),
-		YandasProfile: NewYandasProfileRepository(db),
-		Category:      NewCategoryRepository(db),
-		Service:       NewServiceRepository(db),
-		Order:         NewOrderRepository(db),
-		Review:        NewReviewRepository(db),
-		Conversation:  NewConversationRepository(db),
-		Message:       NewMessageRepository(db),
-		Subscription:  NewSubscriptionRepository(db),
-		DeviceToken:   NewDeviceTokenRepository(db),
-		AuditLog:      NewAuditLogRepository(db),
-		Notification:  NewNotificationRepository(db),
-		Support:       NewSupportRepository(db),
-		Favorite:      NewFavoriteRepository(db),
+		User:                   NewUserRepository(db),
+		YandasProfile:          NewYandasProfileRepository(db),
+		Category:               NewCategoryRepository(db),
+		ApplicationForm:        NewApplicationFormRepository(db),
+		DocumentRule:           NewDocumentRuleRepository(db),
+		Service:                NewServiceRepository(db),
+		Order:                  NewOrderRepository(db),
+		OrderHistory:           NewOrderHistoryRepository(db),
+		OrderAdjustment:        NewOrderAdjustmentRepository(db),
+		RecurringOrder:         NewRecurringOrderRepository(db),
+		JobRequest:             NewJobRequestRepository(db),
+		Assignment:             NewAssignmentRepository(db),
+		Review:                 NewReviewRepository(db),
+		Conversation:           NewConversationRepository(db),
+		Message:                NewMessageRepository(db, keys),
+		Subscription:           NewSubscriptionRepository(db),
+		DeviceToken:            NewDeviceTokenRepository(db),
+		Session:                NewSessionRepository(db),
+		ContactChange:          NewContactChangeRepository(db),
+		AuditLog:               NewAuditLogRepository(db),
+		Notification:           NewNotificationRepository(db),
+		Announcement:           NewAnnouncementRepository(db),
+		LegalDocument:          NewLegalDocumentRepository(db),
+		Consent:                NewConsentRepository(db),
+		Support:                NewSupportRepository(db),
+		Favorite:               NewFavoriteRepository(db),
+		NotificationPreference: NewNotificationPreferenceRepository(db),
+		CallLog:                NewCallLogRepository(db),
+		Address:                NewAddressRepository(db),
+		Role:                   NewRoleRepository(db),
+		DocumentScreening:      NewDocumentScreeningRepository(db),
+		DocumentRenewal:        NewDocumentRenewalRepository(db),
+		Webhook:                NewWebhookRepository(db),
+		SMSDelivery:            NewSMSDeliveryRepository(db),
+		Payout:                 NewPayoutRepository(db),
+		ExchangeRate:           NewExchangeRateRepository(db),
+		Receipt:                NewReceiptRepository(db),
+		Monitoring:             NewMonitoringRepository(db),
+		Analytics:              NewAnalyticsRepository(db),
+		Demand:                 NewDemandRepository(db),
+		Quality:                NewQualityRepository(db),
+		Retention:              NewRetentionRepository(db),
+		Backup:                 NewBackupRepository(db),
+		Activity:               NewActivityRepository(db),
+		Setting:                NewSettingRepository(db),
+		Sitemap:                NewSitemapRepository(db),
+		DomainEvent:            NewDomainEventRepository(db),
+		Outbox:                 NewOutboxRepository(db),
+		System:                 NewSystemRepository(db),
+		UnitOfWork:             NewUnitOfWork(db, keys),
 	}
 }
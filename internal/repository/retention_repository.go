@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// messageColumns are the columns messages and messages_archive share
+const messageColumns = `id, conversation_id, sender_id, content, message_type, media_url, duration_seconds,
+	latitude, longitude, location_label, live_until, live_ended_at, is_read, encrypted, created_at`
+
+// retentionRepository finds and removes data past its retention window. The
+// removing methods work one batch at a time so no statement holds locks for long.
+type retentionRepository struct {
+	db *gorm.DB
+}
+
+func NewRetentionRepository(db *gorm.DB) RetentionRepository {
+	return &retentionRepository{db: db}
+}
+
+// SoftDeletedUsers returns the users soft-deleted before before
+func (r *retentionRepository) SoftDeletedUsers(before time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Unscoped().Model(&models.User{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("deleted_at").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// CountMessages counts the messages sent before before
+func (r *retentionRepository) CountMessages(before time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Message{}).Where("created_at < ?", before).Count(&count).Error
+	return count, err
+}
+
+// ArchiveMessages moves up to limit messages sent before before, oldest
+// first, to messages_archive and returns how many were moved
+func (r *retentionRepository) ArchiveMessages(before time.Time, limit int) (int64, error) {
+	result := r.db.Exec(`
+		WITH moved AS (
+			DELETE FROM messages WHERE id IN (
+				SELECT id FROM messages WHERE created_at < ? ORDER BY created_at LIMIT ?)
+			RETURNING `+messageColumns+`
+		)
+		INSERT INTO messages_archive (`+messageColumns+`, archived_at)
+		SELECT `+messageColumns+`, ? FROM moved`,
+		before, limit, time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// CountAuditLogs counts the audit log entries written before before
+func (r *retentionRepository) CountAuditLogs(before time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.AuditLog{}).Where("created_at < ?", before).Count(&count).Error
+	return count, err
+}
+
+// DeleteAuditLogs deletes up to limit audit log entries written before before
+func (r *retentionRepository) DeleteAuditLogs(before time.Time, limit int) (int64, error) {
+	result := r.db.Exec(`DELETE FROM audit_logs WHERE id IN (
+		SELECT id FROM audit_logs WHERE created_at < ? ORDER BY created_at LIMIT ?)`, before, limit)
+	return result.RowsAffected, result.Error
+}
+
+// expiredCalls selects calls started before the cutoff. Calls with a cloud
+// recording are dispute evidence and are kept.
+const expiredCalls = `SELECT id FROM call_logs
+	WHERE created_at < ? AND (recording_files IS NULL OR cardinality(recording_files) = 0)`
+
+// CountCallLogs counts the calls that DeleteCallLogs would remove
+func (r *retentionRepository) CountCallLogs(before time.Time) (int64, error) {
+	var count int64
+	err := r.db.Raw("SELECT COUNT(*) FROM ("+expiredCalls+") expired", before).Scan(&count).Error
+	return count, err
+}
+
+// DeleteCallLogs deletes up to limit calls started before before, with their
+// participants, and returns how many calls were removed
+func (r *retentionRepository) DeleteCallLogs(before time.Time, limit int) (int64, error) {
+	var removed int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Raw(expiredCalls+" ORDER BY created_at LIMIT ?", before, limit).Scan(&ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := tx.Where("call_id IN ?", ids).Delete(&models.CallParticipant{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id IN ?", ids).Delete(&models.CallLog{})
+		removed = result.RowsAffected
+		return result.Error
+	})
+	return removed, err
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Sort orders for the admin review list
+const (
+	ReviewSortNewest     = "newest"
+	ReviewSortOldest     = "oldest"
+	ReviewSortRatingAsc  = "rating_asc"
+	ReviewSortRatingDesc = "rating_desc"
+)
+
+// ReviewFilter narrows the admin review list; zero values are ignored
+type ReviewFilter struct {
+	Query       string     // substring of the comment or reply
+	RevieweeID  *uuid.UUID // reviewed user's ID
+	ReviewerID  *uuid.UUID
+	MinRating   *int
+	MaxRating   *int
+	Hidden      *bool
+	CreatedFrom *time.Time // inclusive
+	CreatedTo   *time.Time // exclusive
+	Sort        string
+}
+
+// apply adds the filter's conditions to a query over reviews
+func (f ReviewFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.Query != "" {
+		pattern := "%" + f.Query + "%"
+		query = query.Where("(reviews.comment ILIKE ? OR reviews.reply ILIKE ?)", pattern, pattern)
+	}
+	if f.RevieweeID != nil {
+		query = query.Where("reviews.reviewee_id = ?", *f.RevieweeID)
+	}
+	if f.ReviewerID != nil {
+		query = query.Where("reviews.reviewer_id = ?", *f.ReviewerID)
+	}
+	if f.MinRating != nil {
+		query = query.Where("reviews.rating >= ?", *f.MinRating)
+	}
+	if f.MaxRating != nil {
+		query = query.Where("reviews.rating <= ?", *f.MaxRating)
+	}
+	if f.Hidden != nil {
+		query = query.Where("reviews.is_hidden = ?", *f.Hidden)
+	}
+	if f.CreatedFrom != nil {
+		query = query.Where("reviews.created_at >= ?", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		query = query.Where("reviews.created_at < ?", *f.CreatedTo)
+	}
+	return query
+}
+
+// order returns the ORDER BY for the filter's sort, newest first by default
+func (f ReviewFilter) order() string {
+	switch f.Sort {
+	case ReviewSortOldest:
+		return "reviews.created_at ASC"
+	case ReviewSortRatingAsc:
+		return "reviews.rating ASC, reviews.created_at DESC"
+	case ReviewSortRatingDesc:
+		return "reviews.rating DESC, reviews.created_at DESC"
+	default:
+		return "reviews.created_at DESC"
+	}
+}
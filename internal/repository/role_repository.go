@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// roleRepository handles role and permission operations
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) List() ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Order("name ASC").Find(&roles).Error
+	return roles, err
+}
+
+// UpsertSystemRole creates a built-in role or refreshes its permissions
+func (r *roleRepository) UpsertSystemRole(role *models.Role) error {
+	role.IsSystem = true
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"description", "permissions", "is_system", "updated_at"}),
+	}).Create(role).Error
+}
+
+func (r *roleRepository) GetByNames(names []string) ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Where("name IN ?", names).Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) ListByUser(userID uuid.UUID) ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name ASC").
+		Find(&roles).Error
+	return roles, err
+}
+
+// ReplaceUserRoles swaps the user's role set for the given roles
+func (r *roleRepository) ReplaceUserRoles(userID uuid.UUID, roleIDs []uuid.UUID, grantedBy uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		for _, roleID := range roleIDs {
+			userRole := &models.UserRole{UserID: userID, RoleID: roleID, GrantedBy: &grantedBy}
+			if err := tx.Create(userRole).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UserHasPermission reports whether any of the user's roles grants the permission
+func (r *roleRepository) UserHasPermission(userID uuid.UUID, permission string) bool {
+	var count int64
+	r.db.Model(&models.Role{}).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ? AND ? = ANY(roles.permissions)", userID, permission).
+		Count(&count)
+	return count > 0
+}
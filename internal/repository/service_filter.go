@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"github.com/yandas/backend/pkg/currency"
+	"gorm.io/gorm"
+)
+
+// Sort orders for the public service catalogue
+const (
+	ServiceSortRating    = "rating"
+	ServiceSortPriceAsc  = "price_asc"
+	ServiceSortPriceDesc = "price_desc"
+)
+
+// ServiceFilter narrows the public service catalogue; zero values are ignored
+type ServiceFilter struct {
+	CategorySlug string // also matches services in the category's subcategories
+	City         string
+	MinPrice     *currency.Money
+	MaxPrice     *currency.Money
+	MinDuration  *int // minutes
+	MaxDuration  *int // minutes
+	Sort         string
+}
+
+// apply adds the filter's conditions to a query over yandas_services
+func (f ServiceFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.CategorySlug != "" {
+		query = query.Where(`yandas_services.category_id IN (
+			SELECT id FROM categories WHERE slug = ?
+			UNION SELECT id FROM categories WHERE parent_id = (SELECT id FROM categories WHERE slug = ?))`,
+			f.CategorySlug, f.CategorySlug)
+	}
+	if f.City != "" {
+		query = query.Where("yandas_profiles.service_cities @> ARRAY[?]::text[]", f.City)
+	}
+	if f.MinPrice != nil {
+		query = query.Where("yandas_services.base_price >= ?", *f.MinPrice)
+	}
+	if f.MaxPrice != nil {
+		query = query.Where("yandas_services.base_price <= ?", *f.MaxPrice)
+	}
+	if f.MinDuration != nil {
+		query = query.Where("yandas_services.duration_minutes >= ?", *f.MinDuration)
+	}
+	if f.MaxDuration != nil {
+		query = query.Where("yandas_services.duration_minutes <= ?", *f.MaxDuration)
+	}
+	return query
+}
+
+// order returns the ORDER BY for the filter's sort, best-rated first by default
+func (f ServiceFilter) order() string {
+	switch f.Sort {
+	case ServiceSortPriceAsc:
+		return "yandas_services.base_price ASC, yandas_profiles.rating_avg DESC"
+	case ServiceSortPriceDesc:
+		return "yandas_services.base_price DESC, yandas_profiles.rating_avg DESC"
+	default:
+		return "yandas_profiles.rating_avg DESC, yandas_profiles.total_jobs DESC, yandas_services.base_price ASC"
+	}
+}
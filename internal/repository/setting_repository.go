@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type settingRepository struct {
+	db *gorm.DB
+}
+
+func NewSettingRepository(db *gorm.DB) SettingRepository {
+	return &settingRepository{db: db}
+}
+
+func (r *settingRepository) List() ([]models.Setting, error) {
+	var settings []models.Setting
+	err := r.db.Order("key").Find(&settings).Error
+	return settings, err
+}
+
+// Upsert stores the setting, replacing any earlier override of the key
+func (r *settingRepository) Upsert(setting *models.Setting) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"type", "value", "updated_by", "updated_at"}),
+	}).Create(setting).Error
+}
+
+// Delete drops the override so the key falls back to its default
+func (r *settingRepository) Delete(key string) error {
+	return r.db.Delete(&models.Setting{}, "key = ?", key).Error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Sitemap entry types
+const (
+	SitemapYandas   = "yandas"
+	SitemapCategory = "category"
+)
+
+// SitemapEntry is a public page: an approved yandaş profile or an active
+// category. LastMod is when its content last changed, nil when unknown.
+type SitemapEntry struct {
+	Type    string     `json:"type"`
+	Slug    string     `json:"slug"`
+	LastMod *time.Time `json:"lastmod,omitempty"`
+}
+
+type sitemapRepository struct {
+	db *gorm.DB
+}
+
+func NewSitemapRepository(db *gorm.DB) SitemapRepository {
+	return &sitemapRepository{db: db}
+}
+
+// A profile changes when it is approved, lists a service or gets a visible
+// review; a category when one of its services is listed
+const sitemapEntriesQuery = `
+	WITH entries AS (
+		SELECT 'yandas' AS type, p.slug, GREATEST(p.approved_at,
+			(SELECT MAX(s.created_at) FROM yandas_services s
+				WHERE s.yandas_id = p.id AND s.is_active AND s.status = 'active'),
+			(SELECT MAX(r.created_at) FROM reviews r
+				WHERE r.reviewee_id = p.user_id AND NOT r.is_hidden)) AS last_mod
+		FROM yandas_profiles p
+		JOIN users u ON u.id = p.user_id AND u.deleted_at IS NULL
+		WHERE p.approval_status = 'approved' AND p.slug IS NOT NULL
+		UNION ALL
+		SELECT 'category', c.slug,
+			(SELECT MAX(s.created_at) FROM yandas_services s
+				WHERE s.category_id = c.id AND s.is_active AND s.status = 'active')
+		FROM categories c
+		WHERE c.is_active
+	)`
+
+// List returns a page of public pages, categories first, each type by slug
+func (r *sitemapRepository) List(page, limit int) ([]SitemapEntry, int64, error) {
+	var total int64
+	if err := r.db.Raw(sitemapEntriesQuery + ` SELECT COUNT(*) FROM entries`).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []SitemapEntry
+	offset := (page - 1) * limit
+	err := r.db.Raw(sitemapEntriesQuery+`
+		SELECT type, slug, last_mod FROM entries
+		ORDER BY type = 'yandas', slug
+		LIMIT ? OFFSET ?`, limit, offset).Scan(&entries).Error
+
+	return entries, total, err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/crypto"
+	"gorm.io/gorm"
+)
+
+type smsDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewSMSDeliveryRepository(db *gorm.DB) SMSDeliveryRepository {
+	return &smsDeliveryRepository{db: db}
+}
+
+func (r *smsDeliveryRepository) Create(delivery *models.SMSDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// UpdateStatus records a delivery report for a provider's message. Reports for
+// messages that were never logged, e.g. sent before logging started, are ignored.
+func (r *smsDeliveryRepository) UpdateStatus(provider, messageID, status string, errorCode, errMsg *string) (bool, error) {
+	result := r.db.Model(&models.SMSDelivery{}).
+		Where("provider = ? AND provider_message_id = ?", provider, messageID).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"error_code": errorCode,
+			"error":      errMsg,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *smsDeliveryRepository) List(page, limit int, phone, status string) ([]models.SMSDelivery, int64, error) {
+	var deliveries []models.SMSDelivery
+	var total int64
+
+	query := r.db.Model(&models.SMSDelivery{})
+	if phone != "" {
+		query = query.Where("phone_hash = ?", crypto.Active().BlindIndex(phone))
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&deliveries).Error
+
+	return deliveries, total, err
+}
@@ -1,20 +1,22 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/models"
 	"gorm.io/gorm"
 )
 
-type SupportRepository struct {
+type supportRepository struct {
 	db *gorm.DB
 }
 
-func NewSupportRepository(db *gorm.DB) *SupportRepository {
-	return &SupportRepository{db: db}
+func NewSupportRepository(db *gorm.DB) SupportRepository {
+	return &supportRepository{db: db}
 }
 
-func (r *SupportRepository) ListTickets(page, limit int, status, priority string) ([]models.SupportTicket, int64, error) {
+func (r *supportRepository) ListTickets(page, limit int, status, priority string) ([]models.SupportTicket, int64, error) {
 	var tickets []models.SupportTicket
 	var total int64
 
@@ -34,31 +36,43 @@ func (r *SupportRepository) ListTickets(page, limit int, status, priority string
 	return tickets, total, err
 }
 
-func (r *SupportRepository) GetTicket(id uuid.UUID) (*models.SupportTicket, error) {
+func (r *supportRepository) GetTicket(id uuid.UUID) (*models.SupportTicket, error) {
 	var ticket models.SupportTicket
 	err := r.db.Preload("User").Preload("Assignee").Preload("Messages.Sender").First(&ticket, "id = ?", id).Error
 	return &ticket, err
 }
 
-func (r *SupportRepository) CreateTicket(ticket *models.SupportTicket) error {
+// GetTicketForOwner returns a ticket as its owner sees it, without internal notes
+func (r *supportRepository) GetTicketForOwner(id uuid.UUID) (*models.SupportTicket, error) {
+	var ticket models.SupportTicket
+	err := r.db.
+		Preload("User").
+		Preload("Assignee").
+		Preload("Messages", "is_internal = ?", false).
+		Preload("Messages.Sender").
+		First(&ticket, "id = ?", id).Error
+	return &ticket, err
+}
+
+func (r *supportRepository) CreateTicket(ticket *models.SupportTicket) error {
 	return r.db.Create(ticket).Error
 }
 
-func (r *SupportRepository) UpdateTicket(ticket *models.SupportTicket) error {
+func (r *supportRepository) UpdateTicket(ticket *models.SupportTicket) error {
 	return r.db.Save(ticket).Error
 }
 
-func (r *SupportRepository) CreateMessage(message *models.SupportMessage) error {
+func (r *supportRepository) CreateMessage(message *models.SupportMessage) error {
 	return r.db.Create(message).Error
 }
 
-func (r *SupportRepository) GetTicketMessages(ticketID uuid.UUID) ([]models.SupportMessage, error) {
+func (r *supportRepository) GetTicketMessages(ticketID uuid.UUID) ([]models.SupportMessage, error) {
 	var messages []models.SupportMessage
 	err := r.db.Preload("Sender").Where("ticket_id = ?", ticketID).Order("created_at ASC").Find(&messages).Error
 	return messages, err
 }
 
-func (r *SupportRepository) GetStats() (map[string]int64, error) {
+func (r *supportRepository) GetStats() (map[string]int64, error) {
 	stats := make(map[string]int64)
 	var open, pending, resolved, urgent, total int64
 
@@ -74,10 +88,50 @@ func (r *SupportRepository) GetStats() (map[string]int64, error) {
 	stats["urgent"] = urgent
 	stats["total"] = total
 
+	var sla struct {
+		FirstResponseMet      int64
+		FirstResponseBreached int64
+		ResolutionMet         int64
+		ResolutionBreached    int64
+		Escalated             int64
+	}
+	err := r.db.Model(&models.SupportTicket{}).Select(`
+		COUNT(*) FILTER (WHERE first_responded_at IS NOT NULL AND NOT first_response_breached) AS first_response_met,
+		COUNT(*) FILTER (WHERE first_response_breached) AS first_response_breached,
+		COUNT(*) FILTER (WHERE resolved_at IS NOT NULL AND NOT resolution_breached) AS resolution_met,
+		COUNT(*) FILTER (WHERE resolution_breached) AS resolution_breached,
+		COUNT(*) FILTER (WHERE escalation_level > 0 AND status NOT IN ('resolved', 'closed')) AS escalated`).
+		Scan(&sla).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats["sla_first_response_met"] = sla.FirstResponseMet
+	stats["sla_first_response_breached"] = sla.FirstResponseBreached
+	stats["sla_resolution_met"] = sla.ResolutionMet
+	stats["sla_resolution_breached"] = sla.ResolutionBreached
+	stats["escalated"] = sla.Escalated
+
 	return stats, nil
 }
 
-func (r *SupportRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.SupportTicket, int64, error) {
+// ListOverdueTickets returns open tickets past a deadline: no first response by
+// first_response_due_at, or still unresolved at resolution_due_at. Tickets
+// waiting on the user only count against the first-response deadline.
+func (r *supportRepository) ListOverdueTickets(now time.Time, limit int) ([]models.SupportTicket, error) {
+	var tickets []models.SupportTicket
+	err := r.db.
+		Where("status NOT IN ?", []string{"resolved", "closed"}).
+		Where(r.db.
+			Where("first_responded_at IS NULL AND first_response_due_at < ?", now).
+			Or("status <> ? AND resolution_due_at < ?", "pending", now)).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&tickets).Error
+	return tickets, err
+}
+
+func (r *supportRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.SupportTicket, int64, error) {
 	var tickets []models.SupportTicket
 	var total int64
 
@@ -88,3 +142,48 @@ func (r *SupportRepository) ListByUser(userID uuid.UUID, page, limit int) ([]mod
 	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&tickets).Error
 	return tickets, total, err
 }
+
+// EscalateTicket saves an escalated ticket unless another process already
+// escalated it past fromLevel, in which case it reports false
+func (r *supportRepository) EscalateTicket(ticket *models.SupportTicket, fromLevel int) (bool, error) {
+	result := r.db.Model(ticket).
+		Where("escalation_level = ?", fromLevel).
+		Select("priority", "first_response_due_at", "resolution_due_at", "first_response_breached", "resolution_breached", "escalation_level", "escalated_at").
+		Updates(ticket)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ListCannedResponses returns templates for a ticket category plus those for any category
+func (r *supportRepository) ListCannedResponses(category string) ([]models.CannedResponse, error) {
+	var responses []models.CannedResponse
+	query := r.db.Model(&models.CannedResponse{})
+	if category != "" {
+		query = query.Where("category = ? OR category IS NULL", category)
+	}
+	err := query.Order("usage_count DESC, title ASC").Find(&responses).Error
+	return responses, err
+}
+
+func (r *supportRepository) GetCannedResponse(id uuid.UUID) (*models.CannedResponse, error) {
+	var response models.CannedResponse
+	err := r.db.First(&response, "id = ?", id).Error
+	return &response, err
+}
+
+func (r *supportRepository) CreateCannedResponse(response *models.CannedResponse) error {
+	return r.db.Create(response).Error
+}
+
+func (r *supportRepository) UpdateCannedResponse(response *models.CannedResponse) error {
+	return r.db.Save(response).Error
+}
+
+func (r *supportRepository) DeleteCannedResponse(id uuid.UUID) error {
+	return r.db.Delete(&models.CannedResponse{}, "id = ?", id).Error
+}
+
+func (r *supportRepository) IncrementCannedResponseUsage(id uuid.UUID) error {
+	return r.db.Model(&models.CannedResponse{}).
+		Where("id = ?", id).
+		UpdateColumn("usage_count", gorm.Expr("usage_count + 1")).Error
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yandas/backend/internal/database"
+	"github.com/yandas/backend/migrations"
+	"gorm.io/gorm"
+)
+
+// systemRepository reports on the database itself for health checks
+type systemRepository struct {
+	db *gorm.DB
+}
+
+func NewSystemRepository(db *gorm.DB) SystemRepository {
+	return &systemRepository{db: db}
+}
+
+// Ping runs a trivial query on the primary
+func (r *systemRepository) Ping(ctx context.Context) error {
+	return r.db.WithContext(ctx).Exec("SELECT 1").Error
+}
+
+// MigrationStatus lists the embedded migrations with when each was applied
+func (r *systemRepository) MigrationStatus() ([]database.MigrationStatus, error) {
+	migrator, err := database.NewMigrator(r.db, migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+	return migrator.Status()
+}
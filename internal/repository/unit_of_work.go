@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// unitOfWork wraps gorm transactions
+type unitOfWork struct {
+	db   *gorm.DB
+	keys *MessageKeys
+}
+
+// NewUnitOfWork creates a transaction runner for the given connection
+func NewUnitOfWork(db *gorm.DB, keys *MessageKeys) UnitOfWork {
+	return &unitOfWork{db: db, keys: keys}
+}
+
+// Do commits if fn returns nil and rolls back otherwise. Nested calls use savepoints.
+func (u *unitOfWork) Do(fn func(tx *Repositories) error) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx, u.keys))
+	})
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yandas/backend/pkg/crypto"
+	"gorm.io/gorm"
+)
+
+// Sort orders for the admin user list
+const (
+	UserSortNewest = "newest"
+	UserSortOldest = "oldest"
+	UserSortName   = "name"
+	UserSortEmail  = "email"
+)
+
+// Subscription states the admin user list can filter by
+const (
+	UserSubscriptionActive    = "active"    // currently entitled
+	UserSubscriptionCancelled = "cancelled" // cancelled and not re-subscribed
+	UserSubscriptionExpired   = "expired"   // expired and not re-subscribed
+	UserSubscriptionNone      = "none"      // never subscribed
+)
+
+// activeSubscription matches users with a subscription that is still running
+const activeSubscription = `EXISTS (SELECT 1 FROM subscriptions WHERE subscriptions.user_id = users.id
+	AND subscriptions.status = 'active'
+	AND (subscriptions.current_period_end IS NULL OR subscriptions.current_period_end > NOW()))`
+
+// UserFilter narrows the admin user list; zero values are ignored
+type UserFilter struct {
+	Query        string // substring of the email or full name, or the exact phone number
+	Role         string
+	Verified     *bool
+	Active       *bool
+	CreatedFrom  *time.Time // inclusive
+	CreatedTo    *time.Time // exclusive
+	City         string     // a yandaş service city
+	Subscription string
+	Sort         string
+}
+
+// apply adds the filter's conditions to a query over users
+func (f UserFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.Query != "" {
+		pattern := "%" + f.Query + "%"
+		query = query.Where("(users.email ILIKE ? OR users.full_name ILIKE ? OR users.phone_hash = ?)", pattern, pattern, crypto.Active().BlindIndex(f.Query))
+	}
+	if f.Role != "" {
+		query = query.Where("users.role = ?", f.Role)
+	}
+	if f.Verified != nil {
+		query = query.Where("users.is_verified = ?", *f.Verified)
+	}
+	if f.Active != nil {
+		query = query.Where("users.is_active = ?", *f.Active)
+	}
+	if f.CreatedFrom != nil {
+		query = query.Where("users.created_at >= ?", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		query = query.Where("users.created_at < ?", *f.CreatedTo)
+	}
+	if f.City != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM yandas_profiles WHERE yandas_profiles.user_id = users.id AND ? = ANY(yandas_profiles.service_cities))", f.City)
+	}
+	switch f.Subscription {
+	case UserSubscriptionActive:
+		query = query.Where(activeSubscription)
+	case UserSubscriptionCancelled, UserSubscriptionExpired:
+		query = query.Where("EXISTS (SELECT 1 FROM subscriptions WHERE subscriptions.user_id = users.id AND subscriptions.status = ?)", f.Subscription).
+			Where("NOT " + activeSubscription)
+	case UserSubscriptionNone:
+		query = query.Where("NOT EXISTS (SELECT 1 FROM subscriptions WHERE subscriptions.user_id = users.id)")
+	}
+	return query
+}
+
+// order returns the ORDER BY for the filter's sort, newest first by default
+func (f UserFilter) order() string {
+	switch f.Sort {
+	case UserSortOldest:
+		return "users.created_at ASC"
+	case UserSortName:
+		return "users.full_name ASC, users.created_at DESC"
+	case UserSortEmail:
+		return "users.email ASC NULLS LAST, users.created_at DESC"
+	default:
+		return "users.created_at DESC"
+	}
+}
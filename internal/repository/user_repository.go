@@ -1,28 +1,32 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/crypto"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// UserRepository handles user data operations
-type UserRepository struct {
+// userRepository handles user data operations
+type userRepository struct {
 	db *gorm.DB
 }
 
 // NewUserRepository creates a new user repository
-func NewUserRepository(db *gorm.DB) *UserRepository {
-	return &UserRepository{db: db}
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
 }
 
 // Create creates a new user
-func (r *UserRepository) Create(user *models.User) error {
+func (r *userRepository) Create(user *models.User) error {
 	return r.db.Create(user).Error
 }
 
 // GetByID finds a user by ID
-func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
+func (r *userRepository) GetByID(id uuid.UUID) (*models.User, error) {
 	var user models.User
 	err := r.db.Preload("YandasProfile").Preload("Subscription").First(&user, "id = ?", id).Error
 	if err != nil {
@@ -31,8 +35,15 @@ func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
 	return &user, nil
 }
 
+// Lock takes a row lock on the user that is held until the surrounding
+// transaction ends, serializing changes that hang off the user
+func (r *userRepository) Lock(id uuid.UUID) error {
+	var user models.User
+	return r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, "id = ?", id).Error
+}
+
 // GetByEmail finds a user by email
-func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	var user models.User
 	err := r.db.Preload("YandasProfile").First(&user, "email = ?", email).Error
 	if err != nil {
@@ -41,10 +52,17 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
+// byPhone matches users by the blind index of their phone. Rows written before
+// the index existed are matched on the plaintext column until cmd/encrypt_pii
+// has indexed them.
+func byPhone(query *gorm.DB, phone string) *gorm.DB {
+	return query.Where("users.phone_hash = ? OR (users.phone_hash IS NULL AND users.phone = ?)", crypto.Active().BlindIndex(phone), phone)
+}
+
 // GetByPhone finds a user by phone
-func (r *UserRepository) GetByPhone(phone string) (*models.User, error) {
+func (r *userRepository) GetByPhone(phone string) (*models.User, error) {
 	var user models.User
-	err := r.db.First(&user, "phone = ?", phone).Error
+	err := byPhone(r.db, phone).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -52,48 +70,106 @@ func (r *UserRepository) GetByPhone(phone string) (*models.User, error) {
 }
 
 // Update updates a user
-func (r *UserRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+func (r *userRepository) Update(user *models.User) error {
+	return saveVersioned(r.db, user, &user.Version)
 }
 
 // Delete soft-deletes a user
-func (r *UserRepository) Delete(id uuid.UUID) error {
+func (r *userRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.User{}, "id = ?", id).Error
 }
 
 // HardDelete permanently deletes a user (for GDPR compliance)
-func (r *UserRepository) HardDelete(id uuid.UUID) error {
+func (r *userRepository) HardDelete(id uuid.UUID) error {
 	return r.db.Unscoped().Delete(&models.User{}, "id = ?", id).Error
 }
 
 // List returns paginated users
-func (r *UserRepository) List(page, limit int, role string) ([]models.User, int64, error) {
+func (r *userRepository) List(filter UserFilter, page, limit int) ([]models.User, int64, error) {
 	var users []models.User
 	var total int64
 
-	query := r.db.Model(&models.User{})
-	if role != "" {
-		query = query.Where("role = ?", role)
-	}
+	query := filter.apply(r.db.Model(&models.User{}))
 
 	query.Count(&total)
 
 	offset := (page - 1) * limit
-	err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&users).Error
+	err := query.Offset(offset).Limit(limit).Order(filter.order()).Find(&users).Error
 
 	return users, total, err
 }
 
+// SetActiveBulk activates or deactivates many users at once, bumping the token
+// version of every user whose status actually changes
+func (r *userRepository) SetActiveBulk(ids []uuid.UUID, active bool) (int64, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id IN ? AND is_active <> ?", ids, active).
+		Updates(map[string]interface{}{
+			"is_active":     active,
+			"token_version": gorm.Expr("token_version + 1"),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// StreamForExport loads users matching filter in batches and passes each batch to fn.
+// Status is "active" or "inactive"; City matches a saved address or a yandaş service city.
+func (r *userRepository) StreamForExport(filter ExportFilter, fn func(users []models.User) error) error {
+	query := filter.applyDateRange(r.db.Model(&models.User{}), "created_at")
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	switch filter.Status {
+	case "active":
+		query = query.Where("is_active = ?", true)
+	case "inactive":
+		query = query.Where("is_active = ?", false)
+	}
+	if filter.City != "" {
+		query = query.Where(
+			"(EXISTS (SELECT 1 FROM addresses WHERE addresses.user_id = users.id AND addresses.city = ?) OR "+
+				"EXISTS (SELECT 1 FROM yandas_profiles WHERE yandas_profiles.user_id = users.id AND ? = ANY(yandas_profiles.service_cities)))",
+			filter.City, filter.City,
+		)
+	}
+
+	var batch []models.User
+	return query.FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
 // ExistsByEmail checks if email exists
-func (r *UserRepository) ExistsByEmail(email string) bool {
+func (r *userRepository) ExistsByEmail(email string) bool {
 	var count int64
 	r.db.Model(&models.User{}).Where("email = ?", email).Count(&count)
 	return count > 0
 }
 
+// MarkEmailUndeliverable flags the account using email after a hard bounce or
+// complaint. Addresses already flagged keep their first reason.
+func (r *userRepository) MarkEmailUndeliverable(email, reason string) (bool, error) {
+	if len(reason) > 255 {
+		reason = reason[:255]
+	}
+	result := r.db.Model(&models.User{}).
+		Where("LOWER(email) = LOWER(?) AND email_undeliverable_at IS NULL", email).
+		Updates(map[string]interface{}{
+			"email_undeliverable_at":     time.Now(),
+			"email_undeliverable_reason": reason,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// IsEmailUndeliverable checks if email belongs to an account flagged undeliverable
+func (r *userRepository) IsEmailUndeliverable(email string) bool {
+	var count int64
+	r.db.Model(&models.User{}).Where("LOWER(email) = LOWER(?) AND email_undeliverable_at IS NOT NULL", email).Count(&count)
+	return count > 0
+}
+
 // ExistsByPhone checks if phone exists
-func (r *UserRepository) ExistsByPhone(phone string) bool {
+func (r *userRepository) ExistsByPhone(phone string) bool {
 	var count int64
-	r.db.Model(&models.User{}).Where("phone = ?", phone).Count(&count)
+	byPhone(r.db.Model(&models.User{}), phone).Count(&count)
 	return count > 0
 }
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStaleVersion is returned when a versioned row changed after it was read
+var ErrStaleVersion = errors.New("record was changed since it was read")
+
+// saveVersioned writes every column of model, but only if its row still has
+// the version the model carries, and bumps that version. Associations are not
+// saved. On failure the model keeps its old version.
+func saveVersioned(db *gorm.DB, model interface{}, version *int) error {
+	read := *version
+	*version = read + 1
+	result := db.Model(model).
+		Where("version = ?", read).
+		Select("*").
+		Omit(clause.Associations, "created_at").
+		Updates(model)
+	if result.Error != nil {
+		*version = read
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		*version = read
+		return ErrStaleVersion
+	}
+	return nil
+}
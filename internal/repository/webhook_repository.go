@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// webhookRepository handles webhook endpoints and their delivery log
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) CreateEndpoint(endpoint *models.WebhookEndpoint) error {
+	return r.db.Create(endpoint).Error
+}
+
+func (r *webhookRepository) GetEndpoint(id uuid.UUID) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	err := r.db.First(&endpoint, "id = ?", id).Error
+	return &endpoint, err
+}
+
+func (r *webhookRepository) UpdateEndpoint(endpoint *models.WebhookEndpoint) error {
+	return r.db.Save(endpoint).Error
+}
+
+func (r *webhookRepository) DeleteEndpoint(id uuid.UUID) error {
+	return r.db.Delete(&models.WebhookEndpoint{}, "id = ?", id).Error
+}
+
+func (r *webhookRepository) ListEndpoints() ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	err := r.db.Order("created_at DESC").Find(&endpoints).Error
+	return endpoints, err
+}
+
+func (r *webhookRepository) ListActiveEndpointsForEvent(event string) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	err := r.db.Where("is_active = ? AND ? = ANY(events)", true, event).Find(&endpoints).Error
+	return endpoints, err
+}
+
+func (r *webhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r *webhookRepository) GetDelivery(id uuid.UUID) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.First(&delivery, "id = ?", id).Error
+	return &delivery, err
+}
+
+func (r *webhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Save(delivery).Error
+}
+
+func (r *webhookRepository) ListDeliveries(endpointID uuid.UUID, page, limit int, status string) ([]models.WebhookDelivery, int64, error) {
+	var deliveries []models.WebhookDelivery
+	var total int64
+
+	query := r.db.Model(&models.WebhookDelivery{}).Where("endpoint_id = ?", endpointID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&deliveries).Error
+
+	return deliveries, total, err
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries whose next attempt is due.
+// Leased rows get their next attempt pushed out by lease so other instances skip them.
+func (r *webhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, time.Now().Add(lease), time.Now(), limit).
+		Scan(&deliveries).Error
+	return deliveries, err
+}
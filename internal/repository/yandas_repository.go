@@ -1,28 +1,30 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/models"
 	"gorm.io/gorm"
 )
 
-// YandasProfileRepository handles yandaş profile operations
-type YandasProfileRepository struct {
+// yandasProfileRepository handles yandaş profile operations
+type yandasProfileRepository struct {
 	db *gorm.DB
 }
 
 // NewYandasProfileRepository creates a new repository
-func NewYandasProfileRepository(db *gorm.DB) *YandasProfileRepository {
-	return &YandasProfileRepository{db: db}
+func NewYandasProfileRepository(db *gorm.DB) YandasProfileRepository {
+	return &yandasProfileRepository{db: db}
 }
 
 // Create creates a new yandaş profile
-func (r *YandasProfileRepository) Create(profile *models.YandasProfile) error {
+func (r *yandasProfileRepository) Create(profile *models.YandasProfile) error {
 	return r.db.Create(profile).Error
 }
 
 // GetByID finds a profile by ID
-func (r *YandasProfileRepository) GetByID(id uuid.UUID) (*models.YandasProfile, error) {
+func (r *yandasProfileRepository) GetByID(id uuid.UUID) (*models.YandasProfile, error) {
 	var profile models.YandasProfile
 	err := r.db.Preload("User").Preload("Services").First(&profile, "id = ?", id).Error
 	if err != nil {
@@ -31,8 +33,35 @@ func (r *YandasProfileRepository) GetByID(id uuid.UUID) (*models.YandasProfile,
 	return &profile, nil
 }
 
+// GetPublic finds a profile with its user only; services are loaded separately
+func (r *yandasProfileRepository) GetPublic(id uuid.UUID) (*models.YandasProfile, error) {
+	var profile models.YandasProfile
+	err := r.db.Preload("User").First(&profile, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetPublicBySlug finds a profile by its URL slug, with its user only
+func (r *yandasProfileRepository) GetPublicBySlug(slug string) (*models.YandasProfile, error) {
+	var profile models.YandasProfile
+	err := r.db.Preload("User").First(&profile, "slug = ?", slug).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// SlugExists reports whether a profile already uses slug
+func (r *yandasProfileRepository) SlugExists(slug string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.YandasProfile{}).Where("slug = ?", slug).Count(&count).Error
+	return count > 0, err
+}
+
 // GetByUserID finds a profile by user ID
-func (r *YandasProfileRepository) GetByUserID(userID uuid.UUID) (*models.YandasProfile, error) {
+func (r *yandasProfileRepository) GetByUserID(userID uuid.UUID) (*models.YandasProfile, error) {
 	var profile models.YandasProfile
 	err := r.db.Preload("User").Preload("Services").First(&profile, "user_id = ?", userID).Error
 	if err != nil {
@@ -42,46 +71,70 @@ func (r *YandasProfileRepository) GetByUserID(userID uuid.UUID) (*models.YandasP
 }
 
 // Update updates a profile
-func (r *YandasProfileRepository) Update(profile *models.YandasProfile) error {
-	return r.db.Save(profile).Error
+func (r *yandasProfileRepository) Update(profile *models.YandasProfile) error {
+	return saveVersioned(r.db, profile, &profile.Version)
 }
 
-// ListPublic returns available and approved yandaşlar
-func (r *YandasProfileRepository) ListPublic(page, limit int, categorySlug, city string) ([]models.YandasProfile, int64, error) {
-	var profiles []models.YandasProfile
+// listItemColumns selects the YandasListItem projection. The users join is
+// expected on the query; the starting price is a correlated subquery so no
+// services are loaded.
+const listItemColumns = `yandas_profiles.id, yandas_profiles.user_id, yandas_profiles.slug, users.full_name, users.avatar_url,
+	yandas_profiles.rating_avg, yandas_profiles.total_jobs, yandas_profiles.is_available, yandas_profiles.service_cities,
+	CASE WHEN yandas_profiles.vacation_started_at IS NOT NULL THEN yandas_profiles.vacation_until END AS back_on,
+	(SELECT MIN(yandas_services.base_price) FROM yandas_services
+		WHERE yandas_services.yandas_id = yandas_profiles.id AND yandas_services.is_active AND yandas_services.status = 'active') AS starting_price`
+
+// ListPublic returns approved yandaşlar who are available or on vacation,
+// the available ones first; byQuality ranks them by their quality score
+// before rating
+func (r *yandasProfileRepository) ListPublic(page, limit int, categorySlug, city string, byQuality bool) ([]models.YandasListItem, int64, error) {
+	var items []models.YandasListItem
 	var total int64
 
 	query := r.db.Model(&models.YandasProfile{}).
 		Where("approval_status = ?", "approved").
-		Where("is_available = ?", true)
+		Where("is_available = ? OR vacation_started_at IS NOT NULL", true)
 
 	if city != "" {
-		query = query.Where("? = ANY(service_cities)", city)
+		query = query.Where("service_cities @> ARRAY[?]::text[]", city)
 	}
 
 	if categorySlug != "" {
-		query = query.Joins("JOIN yandas_services ON yandas_services.yandas_id = yandas_profiles.id").
-			Joins("JOIN categories ON categories.id = yandas_services.category_id").
-			Where("categories.slug = ?", categorySlug).
-			Distinct()
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM yandas_services
+			JOIN categories ON categories.id = yandas_services.category_id
+			WHERE yandas_services.yandas_id = yandas_profiles.id AND categories.slug = ?)`, categorySlug)
 	}
 
 	query.Count(&total)
 
+	// Yandaş with a current paid plan get priority listing (see services.PlanEntitlements)
+	priority := `EXISTS (
+		SELECT 1 FROM subscriptions
+		WHERE subscriptions.user_id = yandas_profiles.user_id AND subscriptions.status = 'active'
+		AND (subscriptions.current_period_end IS NULL OR subscriptions.current_period_end > NOW())) DESC`
+
 	offset := (page - 1) * limit
-	err := query.
-		Preload("User").
-		Preload("Services.Category").
+	query = query.
+		Select(listItemColumns).
+		Joins("JOIN users ON users.id = yandas_profiles.user_id AND users.deleted_at IS NULL").
 		Offset(offset).
 		Limit(limit).
-		Order("rating_avg DESC, total_jobs DESC").
-		Find(&profiles).Error
+		Order("yandas_profiles.is_available DESC").
+		Order(priority)
+	if byQuality {
+		query = query.Order(`(SELECT yandas_quality_scores.score FROM yandas_quality_scores
+			WHERE yandas_quality_scores.yandas_id = yandas_profiles.id) DESC NULLS LAST`)
+	}
+	err := query.
+		Order("yandas_profiles.rating_avg DESC, yandas_profiles.total_jobs DESC").
+		Scan(&items).Error
 
-	return profiles, total, err
+	return items, total, err
 }
 
 // ListPendingApplications returns pending yandaş applications
-func (r *YandasProfileRepository) ListPendingApplications(page, limit int) ([]models.YandasProfile, int64, error) {
+func (r *yandasProfileRepository) ListPendingApplications(page, limit int) ([]models.YandasProfile, int64, error) {
 	var profiles []models.YandasProfile
 	var total int64
 
@@ -91,6 +144,7 @@ func (r *YandasProfileRepository) ListPendingApplications(page, limit int) ([]mo
 	offset := (page - 1) * limit
 	err := query.
 		Preload("User").
+		Preload("Screening").
 		Offset(offset).
 		Limit(limit).
 		Order("created_at ASC").
@@ -100,7 +154,7 @@ func (r *YandasProfileRepository) ListPendingApplications(page, limit int) ([]mo
 }
 
 // ListAllApplications returns all yandaş applications (for admin)
-func (r *YandasProfileRepository) ListAllApplications(page, limit int, status string) ([]models.YandasProfile, int64, error) {
+func (r *yandasProfileRepository) ListAllApplications(page, limit int, status string) ([]models.YandasProfile, int64, error) {
 	var profiles []models.YandasProfile
 	var total int64
 
@@ -113,6 +167,7 @@ func (r *YandasProfileRepository) ListAllApplications(page, limit int, status st
 	offset := (page - 1) * limit
 	err := query.
 		Preload("User").
+		Preload("Screening").
 		Offset(offset).
 		Limit(limit).
 		Order("created_at DESC").
@@ -121,15 +176,94 @@ func (r *YandasProfileRepository) ListAllApplications(page, limit int, status st
 	return profiles, total, err
 }
 
+// StreamApplicationsForExport loads applications matching filter in batches and passes each batch to fn
+func (r *yandasProfileRepository) StreamApplicationsForExport(filter ExportFilter, fn func(profiles []models.YandasProfile) error) error {
+	query := filter.applyDateRange(r.db.Model(&models.YandasProfile{}), "created_at")
+	if filter.Status != "" {
+		query = query.Where("approval_status = ?", filter.Status)
+	}
+	if filter.City != "" {
+		query = query.Where("service_cities @> ARRAY[?]::text[]", filter.City)
+	}
+
+	var batch []models.YandasProfile
+	return query.
+		Preload("User").
+		Preload("Screening").
+		FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
 // UpdateAvailability updates yandaş availability status
-func (r *YandasProfileRepository) UpdateAvailability(id uuid.UUID, available bool) error {
+func (r *yandasProfileRepository) UpdateAvailability(id uuid.UUID, available bool) error {
 	return r.db.Model(&models.YandasProfile{}).
 		Where("id = ?", id).
 		Update("is_available", available).Error
 }
 
+// SetVacation plans a vacation, or changes the one already planned
+func (r *yandasProfileRepository) SetVacation(id uuid.UUID, from, until time.Time, policy string) error {
+	return r.db.Model(&models.YandasProfile{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"vacation_from": from, "vacation_until": until, "vacation_policy": policy}).Error
+}
+
+// StartVacation marks a planned vacation begun and takes the yandaş offline.
+// It reports false when the vacation had already begun or was cancelled.
+func (r *yandasProfileRepository) StartVacation(id uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.Model(&models.YandasProfile{}).
+		Where("id = ? AND vacation_from IS NOT NULL AND vacation_started_at IS NULL", id).
+		Updates(map[string]interface{}{"vacation_started_at": at, "is_available": false})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ClearVacation removes a planned or running vacation
+func (r *yandasProfileRepository) ClearVacation(id uuid.UUID) error {
+	return r.db.Model(&models.YandasProfile{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"vacation_from": nil, "vacation_until": nil, "vacation_policy": nil, "vacation_started_at": nil}).Error
+}
+
+// ListVacationsDue returns profiles whose vacation should begin or end by now
+func (r *yandasProfileRepository) ListVacationsDue(now time.Time, limit int) ([]models.YandasProfile, error) {
+	var profiles []models.YandasProfile
+	err := r.db.
+		Where("vacation_from IS NOT NULL").
+		Where("(vacation_started_at IS NULL AND vacation_from <= ?) OR vacation_until <= ?", now, now).
+		Order("vacation_from ASC").
+		Limit(limit).
+		Find(&profiles).Error
+	return profiles, err
+}
+
+// GetByKYCReference finds the profile whose identity check a provider knows by reference
+func (r *yandasProfileRepository) GetByKYCReference(provider, reference string) (*models.YandasProfile, error) {
+	var profile models.YandasProfile
+	err := r.db.First(&profile, "kyc_provider = ? AND kyc_reference = ?", provider, reference).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// UpdateKYC stores the identity check fields of a profile without touching
+// its version, so a result arriving mid-edit does not conflict with the yandaş
+func (r *yandasProfileRepository) UpdateKYC(profile *models.YandasProfile) error {
+	return r.db.Model(&models.YandasProfile{}).
+		Where("id = ?", profile.ID).
+		Updates(map[string]interface{}{
+			"kyc_provider":   profile.KYCProvider,
+			"kyc_reference":  profile.KYCReference,
+			"kyc_status":     profile.KYCStatus,
+			"kyc_score":      profile.KYCScore,
+			"kyc_reason":     profile.KYCReason,
+			"kyc_checked_at": profile.KYCCheckedAt,
+		}).Error
+}
+
 // UpdateLocation updates yandaş current location
-func (r *YandasProfileRepository) UpdateLocation(id uuid.UUID, lat, lng float64) error {
+func (r *yandasProfileRepository) UpdateLocation(id uuid.UUID, lat, lng float64) error {
 	return r.db.Model(&models.YandasProfile{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
@@ -139,12 +273,12 @@ func (r *YandasProfileRepository) UpdateLocation(id uuid.UUID, lat, lng float64)
 }
 
 // UpdateRating updates yandaş rating
-func (r *YandasProfileRepository) UpdateRating(id uuid.UUID) error {
-	// Calculate average rating from reviews
+func (r *yandasProfileRepository) UpdateRating(id uuid.UUID) error {
+	// Calculate average rating from reviews; hidden ones don't count
 	var avgRating float64
 	r.db.Model(&models.Review{}).
 		Select("COALESCE(AVG(rating), 0)").
-		Where("reviewee_id = (SELECT user_id FROM yandas_profiles WHERE id = ?)", id).
+		Where("reviewee_id = (SELECT user_id FROM yandas_profiles WHERE id = ?) AND is_hidden = ?", id, false).
 		Scan(&avgRating)
 
 	var totalJobs int64
@@ -161,8 +295,8 @@ func (r *YandasProfileRepository) UpdateRating(id uuid.UUID) error {
 }
 
 // Search searches yandaş profiles by name, bio, or service title
-func (r *YandasProfileRepository) Search(query string, page, limit int) ([]models.YandasProfile, int64, error) {
-	var profiles []models.YandasProfile
+func (r *yandasProfileRepository) Search(query string, page, limit int) ([]models.YandasListItem, int64, error) {
+	var items []models.YandasListItem
 	var total int64
 
 	searchQuery := "%" + query + "%"
@@ -175,12 +309,11 @@ func (r *YandasProfileRepository) Search(query string, page, limit int) ([]model
 
 	offset := (page - 1) * limit
 	err := dbQuery.
-		Preload("User").
-		Preload("Services.Category").
+		Select(listItemColumns).
 		Offset(offset).
 		Limit(limit).
-		Order("rating_avg DESC").
-		Find(&profiles).Error
+		Order("yandas_profiles.rating_avg DESC").
+		Scan(&items).Error
 
-	return profiles, total, err
+	return items, total, err
 }
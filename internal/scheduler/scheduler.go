@@ -0,0 +1,90 @@
+// Package scheduler runs periodic background jobs inside the API process
+package scheduler
+
+import (
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// job is a named function run whenever next says
+type job struct {
+	name string
+	next func(now time.Time) time.Time
+	run  func()
+}
+
+// Scheduler runs registered jobs, each on its own ticker. A slow job delays
+// only its own next run, and a panicking job is logged and retried next tick.
+type Scheduler struct {
+	jobs []job
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates an empty scheduler
+func New() *Scheduler {
+	return &Scheduler{stop: make(chan struct{})}
+}
+
+// Every registers fn to run every interval once the scheduler starts
+func (s *Scheduler) Every(name string, interval time.Duration, fn func()) {
+	next := func(now time.Time) time.Time { return now.Add(interval) }
+	s.jobs = append(s.jobs, job{name: name, next: next, run: fn})
+}
+
+// Daily registers fn to run once a day at hour:minute server time
+func (s *Scheduler) Daily(name string, hour, minute int, fn func()) {
+	s.jobs = append(s.jobs, job{name: name, next: func(now time.Time) time.Time { return nextDaily(now, hour, minute) }, run: fn})
+}
+
+// nextDaily returns the first hour:minute strictly after now
+func nextDaily(now time.Time, hour, minute int) time.Time {
+	at := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at
+}
+
+// Start launches all registered jobs
+func (s *Scheduler) Start() {
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(j)
+	}
+	log.Printf("[SCHEDULER] started %d jobs", len(s.jobs))
+}
+
+// Stop signals all jobs to exit and waits for running ones to finish
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(j job) {
+	defer s.wg.Done()
+
+	timer := time.NewTimer(time.Until(j.next(time.Now())))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-timer.C:
+			s.runOnce(j)
+			timer.Reset(time.Until(j.next(time.Now())))
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(j job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[SCHEDULER] job %s panicked: %v\n%s", j.name, r, debug.Stack())
+		}
+	}()
+	j.run()
+}
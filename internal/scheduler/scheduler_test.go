@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJobsRunAndSurvivePanics(t *testing.T) {
+	var runs, panics int32
+
+	s := New()
+	s.Every("count", 5*time.Millisecond, func() { atomic.AddInt32(&runs, 1) })
+	s.Every("panic", 5*time.Millisecond, func() {
+		atomic.AddInt32(&panics, 1)
+		panic("boom")
+	})
+	s.Start()
+	time.Sleep(40 * time.Millisecond)
+	s.Stop()
+
+	if atomic.LoadInt32(&runs) < 2 {
+		t.Errorf("expected repeated runs, got %d", runs)
+	}
+	if atomic.LoadInt32(&panics) < 2 {
+		t.Errorf("panicking job should keep being scheduled, got %d", panics)
+	}
+}
+
+func TestNextDaily(t *testing.T) {
+	loc := time.UTC
+	tests := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		{time.Date(2026, 4, 10, 1, 0, 0, 0, loc), time.Date(2026, 4, 10, 3, 30, 0, 0, loc)},
+		{time.Date(2026, 4, 10, 3, 30, 0, 0, loc), time.Date(2026, 4, 11, 3, 30, 0, 0, loc)},
+		{time.Date(2026, 4, 30, 23, 0, 0, 0, loc), time.Date(2026, 5, 1, 3, 30, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		if got := nextDaily(tt.now, 3, 30); !got.Equal(tt.want) {
+			t.Errorf("nextDaily(%v) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
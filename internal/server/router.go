@@ -0,0 +1,507 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/handlers"
+	"github.com/yandas/backend/internal/middleware"
+	"github.com/yandas/backend/internal/services"
+	"github.com/yandas/backend/internal/websocket"
+)
+
+// NewRouter registers the middleware and every API route. It is shared by
+// cmd/api and the integration tests so both serve the same routes.
+func NewRouter(cfg *config.Config, svcs *services.Services, h *handlers.Handlers, wsHub *websocket.Hub, redisClient *redis.Client) *gin.Engine {
+	router := gin.Default()
+
+	// Set max multipart memory for file uploads (50 MB)
+	router.MaxMultipartMemory = 50 << 20 // 50 MB
+
+	// Apply global middleware
+	router.Use(middleware.SecurityHeaders(cfg.HSTSMaxAge))
+	router.Use(middleware.CORS(cfg))
+	router.Use(middleware.RequireJSON())
+	router.Use(middleware.Maintenance(svcs.Settings))
+	router.Use(middleware.RateLimiter(svcs.Settings, redisClient))
+	router.Use(middleware.RequestLogger())
+	router.Use(middleware.RequestCapture(svcs.Capture))
+	router.Use(gin.Recovery())
+
+	// Health check
+	router.GET("/health", h.Health.Health)
+
+	// API v1 routes
+	v1 := router.Group("/api/v1")
+	{
+		// Public routes
+		auth := v1.Group("/auth")
+		{
+			auth.POST("/register", h.Auth.Register)
+			auth.POST("/login", h.Auth.Login)
+			auth.POST("/refresh", h.Auth.RefreshToken)
+			auth.POST("/forgot-password", h.Auth.ForgotPassword)
+			auth.POST("/reset-password", h.Auth.ResetPassword)
+			auth.POST("/verify-phone", h.Auth.VerifyPhone)
+			auth.POST("/resend-otp", h.Auth.ResendOTP)
+			auth.POST("/verify-account", h.Auth.VerifyAccount)
+			auth.POST("/resend-email-otp", h.Auth.ResendEmailOTP)
+		}
+
+		// Categories (public)
+		v1.GET("/categories", h.Category.List)
+		v1.GET("/categories/application-form", h.Category.ApplicationForm)
+		v1.GET("/currencies", h.Category.Currencies)
+
+		// Public Yandaş listing
+		v1.GET("/yandas", h.Yandas.ListPublic)
+		v1.GET("/yandas/:id", h.Yandas.GetPublic)
+		v1.GET("/yandas/:id/services", h.Yandas.GetServices)
+		v1.GET("/yandas/:id/reviews", h.Yandas.GetReviews)
+		v1.GET("/yandas/:id/reviews/stats", h.Yandas.GetReviewStats)
+		v1.GET("/yandas/:id/og", h.SEO.ProfileOpenGraph)
+
+		// SEO data for the web frontend (public)
+		v1.GET("/sitemap", h.SEO.Sitemap)
+
+		// Service catalogue (public)
+		v1.GET("/services", h.Yandas.ListServices)
+
+		// Search (public)
+		v1.GET("/search", h.Search.SearchYandas)
+
+		// Product analytics events from the apps, signed in or not
+		v1.POST("/events", middleware.AuthOptional(cfg, svcs.Auth), h.Tracking.Track)
+
+		// SMS delivery reports from providers
+		v1.POST("/sms/status/:provider", h.Auth.SMSStatus)
+
+		// Email bounces and complaints from providers
+		v1.POST("/email/events/:provider", h.Auth.EmailEvents)
+
+		// Identity check results from the KYC provider
+		v1.POST("/kyc/results/:provider", h.Yandas.KYCResult)
+
+		// Identity documents, through the signed links admins are given
+		v1.GET("/documents/:name", h.Admin.ApplicationDocument)
+
+		// Legal pages (public)
+		legal := v1.Group("/legal")
+		{
+			legal.GET("/privacy", h.Legal.PrivacyPolicy)
+			legal.GET("/terms", h.Legal.TermsOfService)
+			legal.GET("/kvkk", h.Legal.KVKK)
+			legal.GET("/current", h.Legal.Current)
+		}
+
+		// Protected routes
+		protected := v1.Group("")
+		protected.Use(middleware.AuthRequired(cfg, svcs.Auth), middleware.ConsentRequired(svcs.Consent))
+		{
+			// Terms and policies the user accepted or still has to accept
+			consents := protected.Group("/consents")
+			{
+				consents.GET("", h.Legal.ConsentHistory)
+				consents.GET("/pending", h.Legal.PendingConsents)
+				consents.POST("", h.Legal.AcceptConsents)
+			}
+
+			// User profile
+			user := protected.Group("/user")
+			{
+				user.GET("/me", h.User.GetProfile)
+				user.PUT("/me", h.User.UpdateProfile)
+				user.PUT("/me/avatar", h.User.UpdateAvatar)
+				user.PUT("/me/password", h.User.ChangePassword)
+				user.POST("/me/email", h.User.RequestEmailChange)
+				user.POST("/me/email/confirm", h.User.ConfirmEmailChange)
+				user.POST("/me/phone", h.User.RequestPhoneChange)
+				user.POST("/me/phone/confirm", h.User.ConfirmPhoneChange)
+				user.DELETE("/me", h.User.DeleteAccount)
+				user.POST("/me/device-token", h.User.RegisterDeviceToken)
+				user.GET("/me/devices", h.User.ListDevices)
+				user.DELETE("/me/devices/:id", h.User.RevokeDevice)
+				user.GET("/me/notification-preferences", h.User.GetNotificationPreferences)
+				user.PUT("/me/notification-preferences", h.User.UpdateNotificationPreferences)
+				user.GET("/me/addresses", h.User.ListAddresses)
+				user.POST("/me/addresses", h.User.CreateAddress)
+				user.PUT("/me/addresses/:id", h.User.UpdateAddress)
+				user.PUT("/me/addresses/:id/default", h.User.SetDefaultAddress)
+				user.DELETE("/me/addresses/:id", h.User.DeleteAddress)
+			}
+
+			// Yandaş application & management
+			yandas := protected.Group("/yandas")
+			{
+				yandas.POST("/apply", h.Yandas.Apply)
+				yandas.GET("/application-status", h.Yandas.ApplicationStatus)
+				yandas.POST("/application/documents/:document", h.Yandas.UploadApplicationDocument)
+				yandas.PUT("/profile", h.Yandas.UpdateProfile)
+				yandas.PUT("/availability", h.Yandas.UpdateAvailability)
+				yandas.PUT("/vacation", h.Yandas.SetVacation)
+				yandas.DELETE("/vacation", h.Yandas.EndVacation)
+				yandas.PUT("/location", h.Yandas.UpdateLocation)
+				yandas.PUT("/service-area", h.Yandas.UpdateServiceArea)
+				yandas.PUT("/booking-preferences", h.Yandas.UpdateBookingPreferences)
+				yandas.GET("/document-renewals", h.Yandas.ListDocumentRenewals)
+				yandas.POST("/documents/:document", h.Yandas.RenewDocument)
+
+				// Services management
+				yandas.POST("/services", h.Yandas.CreateService)
+				yandas.PUT("/services/:id", h.Yandas.UpdateService)
+				yandas.DELETE("/services/:id", h.Yandas.DeleteService)
+				yandas.POST("/services/:id/publish", h.Yandas.PublishService)
+				yandas.POST("/services/:id/archive", h.Yandas.ArchiveService)
+				yandas.POST("/services/:id/unarchive", h.Yandas.UnarchiveService)
+				yandas.POST("/services/:id/options", h.Yandas.AddServiceOption)
+				yandas.PUT("/services/:id/options/:optionId", h.Yandas.UpdateServiceOption)
+				yandas.DELETE("/services/:id/options/:optionId", h.Yandas.DeleteServiceOption)
+				yandas.PUT("/services/:id/price-tiers", h.Yandas.SetPriceTiers)
+				yandas.GET("/my-services", h.Yandas.GetMyServices)
+
+				// Incoming orders
+				yandas.GET("/orders", h.Yandas.GetOrders)
+				yandas.POST("/orders/:id/accept", h.Yandas.AcceptOrder)
+				yandas.POST("/orders/:id/reject", h.Yandas.RejectOrder)
+				yandas.POST("/orders/:id/on-my-way", h.Yandas.OnMyWay)
+				yandas.POST("/orders/:id/start", h.Yandas.StartOrder)
+				yandas.POST("/orders/:id/complete", h.Yandas.CompleteOrder)
+				yandas.POST("/orders/:id/adjustments", h.Yandas.ProposeAdjustment)
+				yandas.GET("/calendar", h.Yandas.GetCalendar)
+				yandas.GET("/analytics", h.Yandas.Analytics)
+
+				// Open job requests and bids
+				yandas.GET("/job-requests", h.JobRequest.ListOpen)
+				yandas.POST("/job-requests/:id/bids", h.JobRequest.PlaceBid)
+				yandas.GET("/bids", h.JobRequest.ListMyBids)
+				yandas.POST("/bids/:id/withdraw", h.JobRequest.WithdrawBid)
+
+				// Urgent order offers
+				yandas.POST("/assignments/:id/accept", h.AutoAssign.Accept)
+				yandas.POST("/assignments/:id/decline", h.AutoAssign.Decline)
+
+				// Reviews
+				yandas.POST("/reviews/:id/reply", h.Yandas.ReplyReview)
+
+				// Stats
+				yandas.GET("/stats", h.Yandas.GetStats)
+				yandas.GET("/earnings", h.Yandas.GetEarnings)
+				yandas.GET("/payouts", h.Yandas.GetPayouts)
+			}
+
+			// Orders (customer side)
+			orders := protected.Group("/orders")
+			{
+				orders.POST("", h.Order.Create)
+				orders.GET("", h.Order.List)
+				orders.POST("/recurring", h.Order.CreateRecurring)
+				orders.GET("/recurring", h.Order.ListRecurring)
+				orders.GET("/recurring/:id", h.Order.GetRecurring)
+				orders.POST("/recurring/:id/pause", h.Order.PauseRecurring)
+				orders.POST("/recurring/:id/resume", h.Order.ResumeRecurring)
+				orders.POST("/recurring/:id/cancel", h.Order.CancelRecurring)
+				orders.POST("/auto-assign", h.AutoAssign.Start)
+				orders.GET("/auto-assign/:id", h.AutoAssign.Get)
+				orders.POST("/auto-assign/:id/cancel", h.AutoAssign.Cancel)
+				orders.GET("/:id", h.Order.Get)
+				orders.GET("/:id/receipt", h.Order.Receipt)
+				orders.GET("/:id/timeline", h.Order.Timeline)
+				orders.POST("/:id/rebook", h.Order.Rebook)
+				orders.POST("/:id/cancel", h.Order.Cancel)
+				orders.POST("/:id/confirm-completion", h.Order.ConfirmCompletion)
+				orders.GET("/:id/adjustments", h.Order.ListAdjustments)
+				orders.POST("/:id/adjustments/:adjustmentId/approve", h.Order.ApproveAdjustment)
+				orders.POST("/:id/adjustments/:adjustmentId/reject", h.Order.RejectAdjustment)
+				orders.POST("/:id/review", h.Order.Review)
+			}
+
+			// Review helpfulness votes
+			reviews := protected.Group("/reviews")
+			{
+				reviews.POST("/:id/helpful", h.Order.MarkReviewHelpful)
+				reviews.DELETE("/:id/helpful", h.Order.UnmarkReviewHelpful)
+			}
+
+			// Chat
+			chat := protected.Group("/chat")
+			{
+				chat.GET("/conversations", h.Chat.ListConversations)
+				chat.POST("/conversations/start", h.Chat.StartConversation)
+				chat.GET("/conversations/search", h.Chat.SearchConversations)
+				chat.GET("/conversations/:id", h.Chat.GetConversation)
+				chat.GET("/conversations/:id/messages", h.Chat.GetMessages)
+				chat.POST("/conversations/:id/messages", h.Chat.SendMessage)
+				chat.POST("/conversations/:id/read", h.Chat.MarkAsRead)
+				chat.POST("/conversations/:id/image", h.Chat.SendImageMessage)
+				chat.POST("/conversations/:id/audio", h.Chat.SendAudioMessage)
+				chat.POST("/conversations/:id/location", h.Chat.SendLocationMessage)
+				chat.PUT("/conversations/:id/location/:messageId", h.Chat.UpdateLiveLocation)
+				chat.POST("/conversations/:id/location/:messageId/stop", h.Chat.StopLiveLocation)
+			}
+
+			// Calls (voice/video)
+			calls := protected.Group("/call")
+			{
+				calls.GET("/history", h.Call.History)
+				calls.POST("/initiate", h.Call.InitiateCall)
+				calls.POST("/:id/delivered", h.Call.Delivered)
+				calls.POST("/:id/answer", h.Call.AnswerCall)
+				calls.POST("/:id/reject", h.Call.RejectCall)
+				calls.POST("/:id/end", h.Call.EndCall)
+				calls.POST("/:id/refresh-token", h.Call.RefreshToken)
+				calls.POST("/:id/feedback", h.Call.Feedback)
+			}
+
+			// Favorites
+			favorites := protected.Group("/favorites")
+			{
+				favorites.GET("", h.Favorite.List)
+				favorites.GET("/ids", h.Favorite.IDs)
+				favorites.GET("/feed", h.Favorite.Feed)
+				favorites.POST("/:id/toggle", h.Favorite.Toggle)
+				favorites.GET("/:id/check", h.Favorite.Check)
+			}
+
+			// Open job requests (customer side)
+			jobRequests := protected.Group("/job-requests")
+			{
+				jobRequests.POST("", h.JobRequest.Create)
+				jobRequests.GET("", h.JobRequest.List)
+				jobRequests.GET("/:id", h.JobRequest.Get)
+				jobRequests.POST("/:id/cancel", h.JobRequest.Cancel)
+				jobRequests.POST("/:id/bids/:bidId/accept", h.JobRequest.AcceptBid)
+			}
+
+			// Where open orders outnumber available yandaşlar
+			protected.GET("/demand", middleware.YandasRequired(), h.Category.Demand)
+
+			// Announcement banners
+			announcements := protected.Group("/announcements")
+			{
+				announcements.GET("/active", h.Announcement.Active)
+				announcements.POST("/:id/dismiss", h.Announcement.Dismiss)
+			}
+
+			// Support tickets (user-facing)
+			support := protected.Group("/support")
+			{
+				support.POST("/tickets", h.Support.CreateTicket)
+				support.GET("/tickets", h.Support.ListTickets)
+				support.GET("/tickets/:id", h.Support.GetTicket)
+				support.POST("/tickets/:id/reply", h.Support.ReplyTicket)
+			}
+
+			// Subscriptions
+			subscription := protected.Group("/subscription")
+			{
+				subscription.GET("", h.Subscription.Get)
+				subscription.POST("/verify", h.Subscription.Verify)
+				subscription.POST("/webhook", h.Subscription.Webhook)
+			}
+
+			// Notifications
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", h.Notification.List)
+				notifications.POST("/:id/read", h.Notification.MarkAsRead)
+				notifications.POST("/read-all", h.Notification.MarkAllAsRead)
+			}
+		}
+
+		// Admin routes
+		admin := v1.Group("/admin")
+		admin.Use(middleware.AuthRequired(cfg, svcs.Auth))
+		{
+			perm := func(permission string) gin.HandlerFunc {
+				return middleware.PermissionRequired(svcs.Permission, permission)
+			}
+
+			// Current staff member's permissions
+			admin.GET("/me/permissions", h.Admin.MyPermissions)
+
+			// Dashboard
+			admin.GET("/dashboard", perm(services.PermissionDashboardView), h.Admin.Dashboard)
+			admin.GET("/activity", perm(services.PermissionDashboardView), h.Admin.Activity)
+
+			// User management
+			admin.GET("/users", perm(services.PermissionUsersView), h.Admin.ListUsers)
+			admin.GET("/users/export", perm(services.PermissionUsersView), h.Admin.ExportUsers)
+			admin.POST("/users/bulk-status", perm(services.PermissionUsersManage), h.Admin.BulkUserStatus)
+			admin.GET("/users/:id", perm(services.PermissionUsersView), h.Admin.GetUser)
+			admin.PUT("/users/:id", perm(services.PermissionUsersManage), h.Admin.UpdateUser)
+			admin.DELETE("/users/:id", perm(services.PermissionUsersManage), h.Admin.DeleteUser)
+			admin.GET("/users/:id/consents", perm(services.PermissionUsersView), h.Admin.GetUserConsents)
+
+			// Roles and permissions
+			admin.GET("/roles", perm(services.PermissionRolesManage), h.Admin.ListRoles)
+			admin.GET("/users/:id/roles", perm(services.PermissionRolesManage), h.Admin.GetUserRoles)
+			admin.PUT("/users/:id/roles", perm(services.PermissionRolesManage), h.Admin.AssignUserRoles)
+
+			// Yandaş applications
+			admin.GET("/applications", perm(services.PermissionApplicationsManage), h.Admin.ListApplications)
+			admin.GET("/applications/export", perm(services.PermissionApplicationsManage), h.Admin.ExportApplications)
+			admin.POST("/applications/bulk-approve", perm(services.PermissionApplicationsManage), h.Admin.BulkApproveApplications)
+			admin.GET("/applications/:id", perm(services.PermissionApplicationsManage), h.Admin.GetApplication)
+			admin.POST("/applications/:id/screen", perm(services.PermissionApplicationsManage), h.Admin.ScreenApplication)
+			admin.POST("/applications/:id/kyc", perm(services.PermissionApplicationsManage), h.Admin.VerifyApplicationIdentity)
+			admin.POST("/applications/:id/approve", perm(services.PermissionApplicationsManage), h.Admin.ApproveApplication)
+			admin.POST("/applications/:id/reject", perm(services.PermissionApplicationsManage), h.Admin.RejectApplication)
+
+			// Stale document renewals
+			admin.GET("/document-renewals", perm(services.PermissionApplicationsManage), h.Admin.ListDocumentRenewals)
+			admin.POST("/document-renewals/:id/waive", perm(services.PermissionApplicationsManage), h.Admin.WaiveDocumentRenewal)
+
+			// Orders
+			admin.GET("/orders", perm(services.PermissionOrdersView), h.Admin.ListOrders)
+			admin.GET("/orders/export", perm(services.PermissionOrdersView), h.Admin.ExportOrders)
+			admin.GET("/orders/:id", perm(services.PermissionOrdersView), h.Admin.GetOrder)
+
+			// Review moderation
+			admin.GET("/reviews", perm(services.PermissionReviewsModerate), h.Admin.ListReviews)
+			admin.POST("/reviews/:id/hide", perm(services.PermissionReviewsModerate), h.Admin.HideReview)
+			admin.POST("/reviews/:id/unhide", perm(services.PermissionReviewsModerate), h.Admin.UnhideReview)
+			admin.DELETE("/reviews/:id", perm(services.PermissionReviewsModerate), h.Admin.DeleteReview)
+
+			// Service moderation
+			admin.GET("/services", perm(services.PermissionContentModerate), h.Admin.ListServices)
+			admin.POST("/services/:id/approve", perm(services.PermissionContentModerate), h.Admin.ApproveService)
+			admin.POST("/services/:id/reject", perm(services.PermissionContentModerate), h.Admin.RejectService)
+
+			// Categories
+			admin.POST("/categories", perm(services.PermissionCategoriesManage), h.Admin.CreateCategory)
+			admin.POST("/categories/import", perm(services.PermissionCategoriesManage), h.Admin.ImportCategories)
+			admin.PUT("/categories/:id", perm(services.PermissionCategoriesManage), h.Admin.UpdateCategory)
+			admin.DELETE("/categories/:id", perm(services.PermissionCategoriesManage), h.Admin.DeleteCategory)
+			admin.GET("/categories/:id/document-rules", perm(services.PermissionCategoriesManage), h.Admin.GetDocumentRules)
+			admin.PUT("/categories/:id/document-rules", perm(services.PermissionCategoriesManage), h.Admin.SetDocumentRules)
+			admin.GET("/categories/:id/questions", perm(services.PermissionCategoriesManage), h.Admin.ListApplicationQuestions)
+			admin.POST("/categories/:id/questions", perm(services.PermissionCategoriesManage), h.Admin.CreateApplicationQuestion)
+			admin.PUT("/application-questions/:id", perm(services.PermissionCategoriesManage), h.Admin.UpdateApplicationQuestion)
+			admin.DELETE("/application-questions/:id", perm(services.PermissionCategoriesManage), h.Admin.DeleteApplicationQuestion)
+
+			// Analytics
+			admin.GET("/analytics/overview", perm(services.PermissionAnalyticsView), h.Admin.AnalyticsOverview)
+			admin.GET("/analytics/revenue", perm(services.PermissionAnalyticsView), h.Admin.AnalyticsRevenue)
+			admin.GET("/analytics/users", perm(services.PermissionAnalyticsView), h.Admin.AnalyticsUsers)
+			admin.GET("/analytics/cohorts", perm(services.PermissionAnalyticsView), h.Admin.Cohorts)
+			admin.GET("/analytics/cohorts/export", perm(services.PermissionAnalyticsView), h.Admin.ExportCohorts)
+			admin.GET("/analytics/funnel", perm(services.PermissionAnalyticsView), h.Admin.Funnel)
+			admin.GET("/yandas/leaderboard", perm(services.PermissionAnalyticsView), h.Admin.Leaderboard)
+
+			// Audit logs
+			admin.GET("/audit-logs", perm(services.PermissionAuditLogsView), h.Admin.AuditLogs)
+
+			// Call recordings (dispute evidence)
+			admin.GET("/calls/:id/recording", perm(services.PermissionCallRecordingsView), h.Admin.GetCallRecording)
+			admin.GET("/calls/quality", perm(services.PermissionAnalyticsView), h.Admin.CallQuality)
+
+			// Conversations under a dispute or support ticket
+			admin.GET("/conversations/:id/messages", perm(services.PermissionConversationsReview), h.Admin.ReviewConversation)
+			admin.GET("/conversations/:id/transcript", perm(services.PermissionConversationsReview), h.Admin.ExportConversationTranscript)
+
+			// Joining ongoing calls to mediate disputes
+			admin.GET("/calls/active", perm(services.PermissionSupportManage), h.Call.ActiveCalls)
+			admin.POST("/calls/:id/join", perm(services.PermissionSupportManage), h.Call.JoinCall)
+			admin.POST("/calls/:id/leave", perm(services.PermissionSupportManage), h.Call.LeaveCall)
+
+			// Finance reports
+			admin.GET("/reports/orders", perm(services.PermissionReportsView), h.Admin.ReportOrders)
+			admin.GET("/reports/accounting", perm(services.PermissionReportsView), h.Admin.AccountingReport)
+
+			// Payouts
+			payouts := admin.Group("/payouts", perm(services.PermissionPayoutsManage))
+			{
+				payouts.GET("", h.Admin.ListPayouts)
+				payouts.GET("/balances", h.Admin.PayoutBalances)
+				payouts.POST("", h.Admin.CreatePayout)
+				payouts.GET("/:id", h.Admin.GetPayout)
+				payouts.POST("/:id/transferred", h.Admin.MarkPayoutTransferred)
+			}
+
+			// Monitoring and alerts
+			monitoring := admin.Group("/monitoring", perm(services.PermissionMonitoringManage))
+			{
+				monitoring.GET("/health", h.Health.Report)
+				monitoring.GET("/backups", h.Health.Backups)
+				monitoring.GET("/metrics", h.Admin.CurrentMetrics)
+				monitoring.GET("/rules", h.Admin.ListAlertRules)
+				monitoring.POST("/rules", h.Admin.CreateAlertRule)
+				monitoring.PUT("/rules/:id", h.Admin.UpdateAlertRule)
+				monitoring.DELETE("/rules/:id", h.Admin.DeleteAlertRule)
+				monitoring.GET("/alerts", h.Admin.ListAlertEvents)
+				monitoring.GET("/sms", h.Admin.ListSMSDeliveries)
+			}
+
+			// Request capture for debugging app issues
+			debug := admin.Group("/debug", perm(services.PermissionDebugCapture))
+			{
+				debug.GET("/capture", h.Admin.ListCaptureRules)
+				debug.POST("/capture", h.Admin.StartCapture)
+				debug.DELETE("/capture/:id", h.Admin.StopCapture)
+				debug.GET("/requests", h.Admin.ListCapturedRequests)
+			}
+
+			// Runtime operational settings
+			admin.GET("/settings", perm(services.PermissionSettingsManage), h.Admin.ListSettings)
+			admin.PUT("/settings", perm(services.PermissionSettingsManage), h.Admin.UpdateSettings)
+
+			// Data retention: a dry-run report, or a run on demand
+			admin.GET("/retention", perm(services.PermissionSettingsManage), h.Admin.RetentionReport)
+			admin.POST("/retention/run", perm(services.PermissionSettingsManage), h.Admin.RunRetention)
+
+			// Outbound webhooks
+			webhooks := admin.Group("/webhooks", perm(services.PermissionWebhooksManage))
+			{
+				webhooks.GET("", h.Admin.ListWebhooks)
+				webhooks.POST("", h.Admin.CreateWebhook)
+				webhooks.PUT("/:id", h.Admin.UpdateWebhook)
+				webhooks.DELETE("/:id", h.Admin.DeleteWebhook)
+				webhooks.GET("/:id/deliveries", h.Admin.ListWebhookDeliveries)
+				webhooks.POST("/deliveries/:id/retry", h.Admin.RetryWebhookDelivery)
+			}
+
+			// Announcement banners
+			announcements := admin.Group("/announcements", perm(services.PermissionAnnouncementsManage))
+			{
+				announcements.GET("", h.Admin.ListAnnouncements)
+				announcements.POST("", h.Admin.CreateAnnouncement)
+				announcements.PUT("/:id", h.Admin.UpdateAnnouncement)
+				announcements.DELETE("/:id", h.Admin.DeleteAnnouncement)
+			}
+
+			// Versioned terms and policies in each locale
+			legalDocuments := admin.Group("/legal-documents", perm(services.PermissionLegalManage))
+			{
+				legalDocuments.GET("", h.Admin.ListLegalDocuments)
+				legalDocuments.POST("", h.Admin.PublishLegalDocument)
+				legalDocuments.GET("/:id", h.Admin.GetLegalDocument)
+			}
+
+			// Support tickets
+			support := admin.Group("/support", perm(services.PermissionSupportManage))
+			{
+				support.GET("/tickets", h.Admin.ListSupportTickets)
+				support.GET("/tickets/:id", h.Admin.GetSupportTicket)
+				support.PUT("/tickets/:id", h.Admin.UpdateSupportTicket)
+				support.POST("/tickets/:id/reply", h.Admin.ReplySupportTicket)
+				support.GET("/stats", h.Admin.GetSupportStats)
+				support.GET("/canned-responses", h.Admin.ListCannedResponses)
+				support.POST("/canned-responses", h.Admin.CreateCannedResponse)
+				support.PUT("/canned-responses/:id", h.Admin.UpdateCannedResponse)
+				support.DELETE("/canned-responses/:id", h.Admin.DeleteCannedResponse)
+			}
+		}
+
+		// WebSocket
+		v1.GET("/ws", middleware.AuthRequired(cfg, svcs.Auth), func(c *gin.Context) {
+			websocket.HandleConnection(wsHub, c)
+		})
+	}
+
+	// Static files for uploads. Identity documents are left out: they are
+	// served through signed links only.
+	uploads := router.Group("/uploads", middleware.HidePrefix("/uploads/documents/"))
+	uploads.Static("", "./uploads")
+
+	return router
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// ActivityRoom is the WebSocket room admin dashboards join for live activity
+const ActivityRoom = "admin:events"
+
+// Admin activity types
+const (
+	ActivityOrderCreated         = "order_created"
+	ActivityApplicationSubmitted = "application_submitted"
+	ActivityTicketCreated        = "ticket_created"
+	ActivityPaymentWebhook       = "payment_webhook"
+)
+
+// ActivityTypes are the activity types the feed can be filtered by
+var ActivityTypes = []string{ActivityOrderCreated, ActivityApplicationSubmitted, ActivityTicketCreated, ActivityPaymentWebhook}
+
+const (
+	// activityMessage is the WebSocket message type of a live activity entry
+	activityMessage = "activity"
+	// activityRetention is how long the feed keeps entries
+	activityRetention  = 90 * 24 * time.Hour
+	maxActivitySummary = 255
+)
+
+var ErrInvalidActivityType = validationError("invalid_activity_type", "type must be one of order_created, application_submitted, ticket_created, payment_webhook")
+
+// ActivityService keeps the admin activity feed and streams new entries to
+// the admin dashboards connected to ActivityRoom
+type ActivityService struct {
+	repos  *repository.Repositories
+	outbox *Outbox
+}
+
+func NewActivityService(repos *repository.Repositories, outbox *Outbox) *ActivityService {
+	return &ActivityService{repos: repos, outbox: outbox}
+}
+
+// Record adds an entry to the feed through tx and queues it for ActivityRoom,
+// so both commit or roll back with the change it describes. Callers flush
+// after commit. It is safe to call on a nil service.
+func (s *ActivityService) Record(tx *repository.Repositories, activityType string, subjectID uuid.UUID, userID *uuid.UUID, summary string, data map[string]interface{}) error {
+	if s == nil {
+		return nil
+	}
+	activity := &models.AdminActivity{
+		Type:      activityType,
+		SubjectID: subjectID,
+		UserID:    userID,
+		Summary:   truncateText(summary, maxActivitySummary),
+	}
+	if len(data) > 0 {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		value := string(encoded)
+		activity.Data = &value
+	}
+	if err := tx.Activity.Create(activity); err != nil {
+		return err
+	}
+	return s.outbox.BroadcastToAdmins(tx, activityMessage, activity)
+}
+
+// Flush wakes the outbox dispatcher after a transaction that called Record
+// commits. It is safe to call on a nil service.
+func (s *ActivityService) Flush() {
+	if s == nil {
+		return
+	}
+	s.outbox.Flush()
+}
+
+// List returns the feed newest first; activityType narrows it to one type when set
+func (s *ActivityService) List(activityType string, page, limit int) ([]models.AdminActivity, int64, error) {
+	if activityType != "" && !slices.Contains(ActivityTypes, activityType) {
+		return nil, 0, ErrInvalidActivityType
+	}
+	return s.repos.Activity.List(activityType, page, limit)
+}
+
+// Prune deletes entries past activityRetention; the scheduler calls it daily
+func (s *ActivityService) Prune() {
+	removed, err := s.repos.Activity.Prune(time.Now().Add(-activityRetention))
+	if err != nil {
+		log.Printf("[ACTIVITY] failed to prune the feed: %v", err)
+		return
+	}
+	if removed > 0 {
+		log.Printf("[ACTIVITY] pruned %d entries", removed)
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestActivityRecordStreamsToAdmins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	activities := mocks.NewMockActivityRepository(ctrl)
+	messages := mocks.NewMockOutboxRepository(ctrl)
+	repos := &repository.Repositories{Activity: activities, Outbox: messages}
+	outbox := NewOutbox(repos, nil)
+	svc := NewActivityService(repos, outbox)
+
+	orderID, customerID := uuid.New(), uuid.New()
+	var stored []models.OutboxMessage
+	activities.EXPECT().Create(gomock.Any()).DoAndReturn(func(a *models.AdminActivity) error {
+		if a.Type != ActivityOrderCreated || a.SubjectID != orderID || *a.UserID != customerID {
+			t.Errorf("unexpected activity %+v", a)
+		}
+		if a.Data == nil || *a.Data != `{"status":"pending"}` {
+			t.Errorf("unexpected data %v", a.Data)
+		}
+		a.ID = uuid.New()
+		return nil
+	})
+	messages.EXPECT().Create(gomock.Any()).DoAndReturn(func(m *models.OutboxMessage) error {
+		m.ID = uuid.New()
+		m.CreatedAt = time.Now()
+		stored = append(stored, *m)
+		return nil
+	})
+	summary := strings.Repeat("a", maxActivitySummary+10)
+	if err := svc.Record(repos, ActivityOrderCreated, orderID, &customerID, summary, map[string]interface{}{"status": "pending"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 || stored[0].Target != ActivityRoom || stored[0].Type != activityMessage {
+		t.Fatalf("expected one message for %s, got %+v", ActivityRoom, stored)
+	}
+
+	// The dispatcher delivers it to everyone in the room
+	rooms := &recordingRooms{}
+	outbox.SetBroadcaster(rooms)
+	messages.EXPECT().ClaimDue(outboxBatchSize, outboxLease).Return(stored, nil)
+	messages.EXPECT().Update(gomock.Any()).Return(nil)
+	outbox.dispatchDue()
+	if len(rooms.events) != 1 || !strings.HasPrefix(rooms.events[0], ActivityRoom+" activity ") {
+		t.Fatalf("expected the activity in %s, got %v", ActivityRoom, rooms.events)
+	}
+	if !strings.Contains(rooms.events[0], `"summary":"`+strings.Repeat("a", maxActivitySummary-1)+`…"`) {
+		t.Errorf("expected a truncated summary, got %s", rooms.events[0])
+	}
+}
+
+func TestActivityList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	activities := mocks.NewMockActivityRepository(ctrl)
+	svc := NewActivityService(&repository.Repositories{Activity: activities}, nil)
+
+	if _, _, err := svc.List("order_paid", 1, 20); !errors.Is(err, ErrInvalidActivityType) {
+		t.Errorf("expected ErrInvalidActivityType, got %v", err)
+	}
+	activities.EXPECT().List(ActivityTicketCreated, 2, 20).Return([]models.AdminActivity{{Type: ActivityTicketCreated}}, int64(21), nil)
+	if got, total, err := svc.List(ActivityTicketCreated, 2, 20); err != nil || len(got) != 1 || total != 21 {
+		t.Errorf("unexpected result %v %d %v", got, total, err)
+	}
+}
+
+func TestActivityNilService(t *testing.T) {
+	var svc *ActivityService
+	if err := svc.Record(&repository.Repositories{}, ActivityPaymentWebhook, uuid.New(), nil, "RevenueCat RENEWAL", nil); err != nil {
+		t.Fatal(err)
+	}
+	svc.Flush()
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/currency"
+	"github.com/yandas/backend/pkg/export"
+)
+
+// maxCohortMonths bounds one cohort report
+const maxCohortMonths = 36
+
+var ErrInvalidCohortRange = validationError("invalid_cohort_range", "cohort range must run forwards and span at most 36 months")
+
+// Cohort is one registration month with its retention rates, average order
+// value and lifetime value per registered customer. A rate is nil until some
+// customer's first order is old enough for its window.
+type Cohort struct {
+	repository.CohortRow
+	Retention30       *float64       `json:"retention_30"`
+	Retention60       *float64       `json:"retention_60"`
+	Retention90       *float64       `json:"retention_90"`
+	AverageOrderValue currency.Money `json:"average_order_value"`
+	LTV               currency.Money `json:"ltv"`
+}
+
+// CohortReport is the growth team's monthly customer cohorts; amounts are in Currency
+type CohortReport struct {
+	From     string   `json:"from"` // YYYY-MM
+	To       string   `json:"to"`   // YYYY-MM, inclusive
+	Currency string   `json:"currency"`
+	Cohorts  []Cohort `json:"cohorts"`
+}
+
+// Cohorts reports the customers registered in the calendar months from the
+// one containing from through the one containing to
+func (s *AdminService) Cohorts(from, to time.Time) (*CohortReport, error) {
+	start := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+	end := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, to.Location()).AddDate(0, 1, 0)
+	if !start.Before(end) || start.AddDate(0, maxCohortMonths, 0).Before(end) {
+		return nil, ErrInvalidCohortRange
+	}
+
+	rows, err := s.repos.Analytics.Cohorts(start, end, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	report := &CohortReport{
+		From:     start.Format("2006-01"),
+		To:       end.AddDate(0, -1, 0).Format("2006-01"),
+		Currency: currency.Base,
+		Cohorts:  make([]Cohort, 0, len(rows)),
+	}
+	for _, row := range rows {
+		report.Cohorts = append(report.Cohorts, cohortFromRow(row))
+	}
+	return report, nil
+}
+
+func cohortFromRow(row repository.CohortRow) Cohort {
+	cohort := Cohort{
+		CohortRow:   row,
+		Retention30: retention(row.Retained30, row.Eligible30),
+		Retention60: retention(row.Retained60, row.Eligible60),
+		Retention90: retention(row.Retained90, row.Eligible90),
+	}
+	if row.Completed > 0 {
+		cohort.AverageOrderValue = row.Revenue.Mul(1 / float64(row.Completed))
+	}
+	if row.Users > 0 {
+		cohort.LTV = row.Revenue.Mul(1 / float64(row.Users))
+	}
+	return cohort
+}
+
+// retention is retained out of eligible rounded to four places, nil without any eligible
+func retention(retained, eligible int64) *float64 {
+	if eligible == 0 {
+		return nil
+	}
+	rate := ratio(retained, eligible)
+	return &rate
+}
+
+// ExportCohorts writes a cohort report as one row per month
+func (s *AdminService) ExportCohorts(report *CohortReport, w export.Writer, adminID uuid.UUID) error {
+	header := []string{
+		"month", "users", "customers", "eligible_30", "retained_30", "retention_30", "eligible_60", "retained_60", "retention_60",
+		"eligible_90", "retained_90", "retention_90", "completed_orders", "currency", "revenue", "average_order_value", "ltv",
+	}
+	if err := w.WriteRow(header); err != nil {
+		return err
+	}
+	for _, c := range report.Cohorts {
+		row := []string{
+			c.Month, formatCount(c.Users), formatCount(c.Customers),
+			formatCount(c.Eligible30), formatCount(c.Retained30), formatRate(c.Retention30),
+			formatCount(c.Eligible60), formatCount(c.Retained60), formatRate(c.Retention60),
+			formatCount(c.Eligible90), formatCount(c.Retained90), formatRate(c.Retention90),
+			formatCount(c.Completed), report.Currency, c.Revenue.String(), c.AverageOrderValue.String(), c.LTV.String(),
+		}
+		if err := w.WriteRow(row); err != nil {
+			return err
+		}
+	}
+
+	s.logAction(adminID, "report_cohorts", "users", uuid.Nil, nil, map[string]interface{}{
+		"from": report.From,
+		"to":   report.To,
+	})
+
+	return w.Close()
+}
+
+// formatCount renders a count for an export
+func formatCount(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
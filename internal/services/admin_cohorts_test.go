@@ -0,0 +1,85 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/export"
+)
+
+func TestCohortsComputeRetentionAndLTV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	analytics := mocks.NewMockAnalyticsRepository(ctrl)
+	svc := NewAdminService(&repository.Repositories{Analytics: analytics}, nil, nil, nil, nil, nil, nil)
+
+	from := time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)
+	analytics.EXPECT().Cohorts(from, from.AddDate(0, 3, 0), gomock.Any()).Return([]repository.CohortRow{
+		{Month: "2026-07", Users: 40, Customers: 20, Eligible30: 20, Retained30: 5, Eligible60: 16, Retained60: 6, Completed: 30, Revenue: 450000},
+		{Month: "2026-09", Users: 10},
+	}, nil)
+
+	report, err := svc.Cohorts(time.Date(2026, time.July, 20, 0, 0, 0, 0, time.UTC), time.Date(2026, time.September, 3, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.From != "2026-07" || report.To != "2026-09" || len(report.Cohorts) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	july := report.Cohorts[0]
+	if july.Retention30 == nil || *july.Retention30 != 0.25 || july.Retention60 == nil || *july.Retention60 != 0.375 || july.Retention90 != nil {
+		t.Errorf("unexpected retention %v %v %v", july.Retention30, july.Retention60, july.Retention90)
+	}
+	if july.AverageOrderValue != 15000 || july.LTV != 11250 {
+		t.Errorf("expected 150.00 per order and 112.50 per customer, got %s and %s", july.AverageOrderValue, july.LTV)
+	}
+	if september := report.Cohorts[1]; september.AverageOrderValue != 0 || september.LTV != 0 || september.Retention30 != nil {
+		t.Errorf("expected an empty cohort to have no figures, got %+v", september)
+	}
+}
+
+func TestCohortsRejectInvalidRange(t *testing.T) {
+	svc := NewAdminService(&repository.Repositories{}, nil, nil, nil, nil, nil, nil)
+	now := time.Now()
+	for name, r := range map[string][2]time.Time{
+		"backwards": {now, now.AddDate(0, -2, 0)},
+		"too long":  {now.AddDate(-4, 0, 0), now},
+	} {
+		if _, err := svc.Cohorts(r[0], r[1]); !errors.Is(err, ErrInvalidCohortRange) {
+			t.Errorf("%s: expected ErrInvalidCohortRange, got %v", name, err)
+		}
+	}
+}
+
+func TestExportCohortsWritesOneRowPerMonth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	audit := mocks.NewMockAuditLogRepository(ctrl)
+	svc := NewAdminService(&repository.Repositories{AuditLog: audit}, nil, nil, nil, nil, nil, nil)
+	audit.EXPECT().Create(gomock.Any()).Return(nil)
+
+	report := &CohortReport{From: "2026-07", To: "2026-07", Currency: "TRY", Cohorts: []Cohort{
+		cohortFromRow(repository.CohortRow{Month: "2026-07", Users: 4, Customers: 2, Eligible30: 2, Retained30: 1, Completed: 3, Revenue: 30000}),
+	}}
+	var buf bytes.Buffer
+	w, err := export.NewWriter(export.FormatCSV, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ExportCohorts(report, w, uuid.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(strings.TrimPrefix(lines[0], "\ufeff"), "month,users,customers") {
+		t.Fatalf("unexpected csv %q", buf.String())
+	}
+	if want := "2026-07,4,2,2,1,0.5000,0,0,,0,0,,3,TRY,300.00,100.00,75.00"; strings.TrimSpace(lines[1]) != want {
+		t.Errorf("expected %q, got %q", want, lines[1])
+	}
+}
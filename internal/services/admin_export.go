@@ -0,0 +1,135 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/export"
+)
+
+// Admin export datasets
+const (
+	ExportUsers        = "users"
+	ExportOrders       = "orders"
+	ExportApplications = "applications"
+)
+
+// ErrUnknownExport is returned for datasets other than users, orders and applications
+var ErrUnknownExport = validationError("unknown_export", "unknown export dataset")
+
+// Export streams a dataset matching filter to w, writing a header row first
+func (s *AdminService) Export(dataset string, filter repository.ExportFilter, w export.Writer, adminID uuid.UUID) error {
+	var err error
+	switch dataset {
+	case ExportUsers:
+		err = s.exportUsers(filter, w)
+	case ExportOrders:
+		err = s.exportOrders(filter, w)
+	case ExportApplications:
+		err = s.exportApplications(filter, w)
+	default:
+		return ErrUnknownExport
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logAction(adminID, "export_"+dataset, dataset, uuid.Nil, nil, map[string]interface{}{
+		"from":   filter.From,
+		"to":     filter.To,
+		"status": filter.Status,
+		"role":   filter.Role,
+		"city":   filter.City,
+	})
+
+	return w.Close()
+}
+
+func (s *AdminService) exportUsers(filter repository.ExportFilter, w export.Writer) error {
+	if err := w.WriteRow([]string{"id", "full_name", "email", "phone", "role", "is_active", "is_verified", "created_at"}); err != nil {
+		return err
+	}
+	return s.repos.User.StreamForExport(filter, func(users []models.User) error {
+		for _, u := range users {
+			row := []string{
+				u.ID.String(), u.FullName, deref(u.Email), deref(u.Phone), u.Role,
+				strconv.FormatBool(u.IsActive), strconv.FormatBool(u.IsVerified), formatTime(&u.CreatedAt),
+			}
+			if err := w.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *AdminService) exportOrders(filter repository.ExportFilter, w export.Writer) error {
+	header := []string{"id", "order_number", "status", "customer", "yandas", "service", "agreed_price", "currency", "scheduled_at", "completed_at", "created_at"}
+	if err := w.WriteRow(header); err != nil {
+		return err
+	}
+	return s.repos.Order.StreamForExport(filter, func(orders []models.Order) error {
+		for _, o := range orders {
+			var customer, yandas, service string
+			if o.Customer != nil {
+				customer = o.Customer.FullName
+			}
+			if o.Yandas != nil {
+				yandas = o.Yandas.User.FullName
+			}
+			if o.Service != nil {
+				service = o.Service.Title
+			}
+			row := []string{
+				o.ID.String(), o.OrderNumber, o.Status, customer, yandas, service,
+				o.AgreedPrice.String(), o.Currency,
+				formatTime(o.ScheduledAt), formatTime(o.CompletedAt), formatTime(&o.CreatedAt),
+			}
+			if err := w.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *AdminService) exportApplications(filter repository.ExportFilter, w export.Writer) error {
+	header := []string{"id", "user_id", "full_name", "phone", "approval_status", "service_cities", "screening_status", "approved_at", "created_at"}
+	if err := w.WriteRow(header); err != nil {
+		return err
+	}
+	return s.repos.YandasProfile.StreamApplicationsForExport(filter, func(profiles []models.YandasProfile) error {
+		for _, p := range profiles {
+			var screening string
+			if p.Screening != nil {
+				screening = p.Screening.Status
+			}
+			row := []string{
+				p.ID.String(), p.UserID.String(), p.User.FullName, deref(p.User.Phone), p.ApprovalStatus,
+				strings.Join(p.ServiceCities, ";"), screening, formatTime(p.ApprovedAt), formatTime(&p.CreatedAt),
+			}
+			if err := w.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+func TestListUsersValidatesFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUserRepository(ctrl)
+	svc := NewAdminService(&repository.Repositories{User: users}, nil, nil, nil, nil, nil, nil)
+
+	now := time.Now()
+	for name, filter := range map[string]repository.UserFilter{
+		"sort":         {Sort: "rating"},
+		"subscription": {Subscription: "trial"},
+		"range":        {CreatedFrom: &now, CreatedTo: &now},
+	} {
+		if _, _, err := svc.ListUsers(filter, 1, 20); !errors.Is(err, ErrInvalidUserFilter) {
+			t.Errorf("%s: expected ErrInvalidUserFilter, got %v", name, err)
+		}
+	}
+
+	verified := true
+	filter := repository.UserFilter{Query: "ayşe", Verified: &verified, Subscription: repository.UserSubscriptionNone, Sort: repository.UserSortName}
+	users.EXPECT().List(filter, 2, 20).Return(nil, int64(0), nil)
+	if _, _, err := svc.ListUsers(filter, 2, 20); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListOrdersReturnsRevenue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orders := mocks.NewMockOrderRepository(ctrl)
+	svc := NewAdminService(&repository.Repositories{Order: orders}, nil, nil, nil, nil, nil, nil)
+
+	low, high := currency.Money(50000), currency.Money(10000)
+	if _, _, _, err := svc.ListOrders(repository.OrderFilter{MinAmount: &low, MaxAmount: &high}, 1, 20); !errors.Is(err, ErrInvalidOrderFilter) {
+		t.Errorf("expected an inverted amount range to be rejected, got %v", err)
+	}
+	if _, _, _, err := svc.ListOrders(repository.OrderFilter{Sort: "city"}, 1, 20); !errors.Is(err, ErrInvalidOrderFilter) {
+		t.Errorf("expected an unknown sort to be rejected, got %v", err)
+	}
+
+	filter := repository.OrderFilter{City: "İzmir", MinAmount: &high, Sort: repository.OrderSortAmountDesc}
+	orders.EXPECT().ListAll(filter, 1, 20).Return(nil, int64(42), nil)
+	orders.EXPECT().SumRevenue(filter).Return([]repository.CurrencyTotal{
+		{Currency: "TRY", Amount: 1250050, BaseAmount: 1250050},
+		{Currency: "EUR", Amount: 10010, BaseAmount: 486976},
+	}, nil)
+	_, total, revenue, err := svc.ListOrders(filter, 1, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 42 || revenue.Currency != "TRY" || revenue.Total != 1737026 {
+		t.Errorf("unexpected total %d and revenue %+v", total, revenue)
+	}
+	if len(revenue.ByCurrency) != 2 || revenue.ByCurrency[1] != (CurrencyAmount{Currency: "EUR", Amount: 10010}) {
+		t.Errorf("unexpected revenue per currency %+v", revenue.ByCurrency)
+	}
+}
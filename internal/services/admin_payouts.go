@@ -0,0 +1,108 @@
+package services
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+var (
+	ErrNothingToPay           = conflictError("nothing_to_pay", "no unpaid earnings for this yandaş")
+	ErrPayoutAlreadyCompleted = conflictError("payout_already_completed", "payout already transferred")
+)
+
+// CreatePayoutInput bundles a yandaş's unpaid earnings into a payout
+type CreatePayoutInput struct {
+	YandasID uuid.UUID `json:"yandas_id" binding:"required"`
+	Currency string    `json:"currency" binding:"omitempty,currency"`
+	Notes    string    `json:"notes"`
+}
+
+// MarkPayoutTransferredInput records the bank transfer for a payout
+type MarkPayoutTransferredInput struct {
+	Reference string `json:"reference" binding:"required"`
+}
+
+// PayoutBalances returns unpaid earnings per yandaş, largest first
+func (s *AdminService) PayoutBalances() ([]repository.PayoutBalance, error) {
+	return s.repos.Payout.UnpaidBalances(nil)
+}
+
+func (s *AdminService) ListPayouts(page, limit int, status string, yandasID *uuid.UUID) ([]models.Payout, int64, error) {
+	return s.repos.Payout.List(page, limit, status, yandasID)
+}
+
+func (s *AdminService) GetPayout(id uuid.UUID) (*models.Payout, error) {
+	return s.repos.Payout.GetByID(id)
+}
+
+// CreatePayout moves every unpaid ledger entry of the yandaş into a new pending payout
+func (s *AdminService) CreatePayout(input *CreatePayoutInput, adminID uuid.UUID) (*models.Payout, error) {
+	payout := &models.Payout{
+		YandasID:  input.YandasID,
+		Currency:  currencyCode(input.Currency),
+		Status:    "pending",
+		CreatedBy: adminID,
+	}
+	if input.Notes != "" {
+		payout.Notes = &input.Notes
+	}
+
+	err := s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.Payout.Create(payout); err != nil {
+			return err
+		}
+		amount, err := tx.Payout.AttachUnpaidEntries(payout.ID, input.YandasID, payout.Currency)
+		if err != nil {
+			return err
+		}
+		if amount <= 0 {
+			return ErrNothingToPay
+		}
+		payout.Amount = amount
+		return tx.Payout.Update(payout)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAction(adminID, "create_payout", "payout", payout.ID, nil, map[string]interface{}{
+		"yandas_id": payout.YandasID,
+		"amount":    payout.Amount,
+		"currency":  payout.Currency,
+	})
+
+	return s.repos.Payout.GetByID(payout.ID)
+}
+
+// MarkPayoutTransferred records that the money has been sent
+func (s *AdminService) MarkPayoutTransferred(id uuid.UUID, input *MarkPayoutTransferredInput, adminID uuid.UUID) (*models.Payout, error) {
+	payout, err := s.repos.Payout.GetByID(id)
+	if err != nil {
+		return nil, notFoundError("payout_not_found", "payout not found")
+	}
+	if payout.Status == "transferred" {
+		return nil, ErrPayoutAlreadyCompleted
+	}
+
+	now := time.Now()
+	payout.Status = "transferred"
+	payout.Reference = &input.Reference
+	payout.TransferredBy = &adminID
+	payout.TransferredAt = &now
+
+	if err := s.repos.Payout.Update(payout); err != nil {
+		return nil, err
+	}
+
+	s.logAction(adminID, "payout_transferred", "payout", payout.ID, map[string]interface{}{
+		"status": "pending",
+	}, map[string]interface{}{
+		"status":    "transferred",
+		"reference": input.Reference,
+	})
+
+	return payout, nil
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/currency"
+	"github.com/yandas/backend/pkg/export"
+)
+
+// OrderReport streams orders matching filter with their commission split for
+// accounting. Tips and refunds are not tracked on orders yet, so they have no columns.
+func (s *AdminService) OrderReport(filter repository.ExportFilter, w export.Writer, adminID uuid.UUID) error {
+	header := []string{
+		"order_number", "status", "created_at", "completed_at", "category", "city", "customer", "yandas", "service",
+		"currency", "agreed_price", "commission_rate", "platform_fee", "net_earnings", "cancellation_reason",
+	}
+	if err := w.WriteRow(header); err != nil {
+		return err
+	}
+	err := s.repos.Order.StreamForExport(filter, func(orders []models.Order) error {
+		for _, o := range orders {
+			var customer, yandas, city, service, category string
+			if o.Customer != nil {
+				customer = o.Customer.FullName
+			}
+			if o.Yandas != nil {
+				yandas = o.Yandas.User.FullName
+				if len(o.Yandas.ServiceCities) > 0 {
+					city = o.Yandas.ServiceCities[0]
+				}
+			}
+			if o.Service != nil {
+				service = o.Service.Title
+				if o.Service.Category != nil {
+					category = o.Service.Category.Name
+				}
+			}
+			row := []string{
+				o.OrderNumber, o.Status, formatTime(&o.CreatedAt), formatTime(o.CompletedAt), category, city, customer, yandas, service,
+				o.Currency, formatNumber(&o.AgreedPrice), formatRate(o.CommissionRate), formatNumber(o.PlatformFee), formatNumber(o.NetEarnings),
+				deref(o.CancellationReason),
+			}
+			if err := w.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logAction(adminID, "report_orders", "orders", uuid.Nil, nil, map[string]interface{}{
+		"from":   filter.From,
+		"to":     filter.To,
+		"status": filter.Status,
+		"city":   filter.City,
+	})
+
+	return w.Close()
+}
+
+// AccountingSummary is a month of completed orders grouped for the finance team
+type AccountingSummary struct {
+	Month  string                     `json:"month"` // YYYY-MM
+	Rows   []repository.AccountingRow `json:"rows"`
+	Totals []repository.AccountingRow `json:"totals"` // one per currency, without category or city
+}
+
+// MonthlyAccounting totals the orders completed in the calendar month containing month
+func (s *AdminService) MonthlyAccounting(month time.Time) (*AccountingSummary, error) {
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	rows, err := s.repos.Order.AccountingSummary(from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &AccountingSummary{Month: from.Format("2006-01"), Rows: rows, Totals: []repository.AccountingRow{}}
+	if summary.Rows == nil {
+		summary.Rows = []repository.AccountingRow{}
+	}
+	totals := map[string]int{}
+	for _, row := range rows {
+		i, ok := totals[row.Currency]
+		if !ok {
+			i = len(summary.Totals)
+			totals[row.Currency] = i
+			summary.Totals = append(summary.Totals, repository.AccountingRow{Currency: row.Currency})
+		}
+		total := &summary.Totals[i]
+		total.Orders += row.Orders
+		total.Gross += row.Gross
+		total.PlatformFees += row.PlatformFees
+		total.NetEarnings += row.NetEarnings
+	}
+	return summary, nil
+}
+
+// formatNumber renders an optional amount with two decimals
+func formatNumber(v *currency.Money) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+// formatRate renders an optional commission rate such as 0.1500
+func formatRate(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 4, 64)
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestMonthlyAccountingTotalsPerCurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orders := mocks.NewMockOrderRepository(ctrl)
+	svc := NewAdminService(&repository.Repositories{Order: orders}, nil, nil, nil, nil, nil, nil)
+
+	from := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	orders.EXPECT().AccountingSummary(from, from.AddDate(0, 1, 0)).Return([]repository.AccountingRow{
+		{Category: "Vekil Sürücü", City: "İstanbul", Currency: "TRY", Orders: 3, Gross: 1500, PlatformFees: 225, NetEarnings: 1275},
+		{Category: "Kurye", City: "Ankara", Currency: "TRY", Orders: 1, Gross: 200, PlatformFees: 30, NetEarnings: 170},
+		{Category: "Kurye", City: "İzmir", Currency: "EUR", Orders: 1, Gross: 40, PlatformFees: 6, NetEarnings: 34},
+	}, nil)
+
+	summary, err := svc.MonthlyAccounting(time.Date(2026, time.September, 17, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Month != "2026-09" || len(summary.Rows) != 3 || len(summary.Totals) != 2 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	try := summary.Totals[0]
+	if try.Currency != "TRY" || try.Orders != 4 || try.Gross != 1700 || try.PlatformFees != 255 || try.NetEarnings != 1445 {
+		t.Errorf("unexpected TRY totals %+v", try)
+	}
+}
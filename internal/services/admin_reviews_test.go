@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func newTestReviewModeration(t *testing.T) (*AdminService, *mocks.MockReviewRepository, *mocks.MockYandasProfileRepository, *mocks.MockAuditLogRepository) {
+	ctrl := gomock.NewController(t)
+	reviews := mocks.NewMockReviewRepository(ctrl)
+	profiles := mocks.NewMockYandasProfileRepository(ctrl)
+	audit := mocks.NewMockAuditLogRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{Review: reviews, YandasProfile: profiles, AuditLog: audit, UnitOfWork: uow}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	return NewAdminService(repos, nil, nil, nil, nil, nil, nil), reviews, profiles, audit
+}
+
+func TestSetReviewHiddenRecalculatesRating(t *testing.T) {
+	svc, reviews, profiles, audit := newTestReviewModeration(t)
+	adminID := uuid.New()
+	review := &models.Review{ID: uuid.New(), RevieweeID: uuid.New(), Rating: 1}
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: review.RevieweeID}
+
+	reviews.EXPECT().GetByID(review.ID).Return(review, nil)
+	reviews.EXPECT().SetHidden(review.ID, true, adminID).Return(nil)
+	profiles.EXPECT().GetByUserID(review.RevieweeID).Return(profile, nil)
+	profiles.EXPECT().UpdateRating(profile.ID).Return(nil)
+	audit.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *models.AuditLog) error {
+		if log.Action != "hide_review" || *log.EntityID != review.ID {
+			t.Errorf("unexpected audit log %+v", log)
+		}
+		return nil
+	})
+	if err := svc.SetReviewHidden(review.ID, true, adminID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hiding an already hidden review changes nothing
+	hidden := &models.Review{ID: review.ID, RevieweeID: review.RevieweeID, IsHidden: true}
+	reviews.EXPECT().GetByID(review.ID).Return(hidden, nil)
+	if err := svc.SetReviewHidden(review.ID, true, adminID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteReview(t *testing.T) {
+	svc, reviews, profiles, audit := newTestReviewModeration(t)
+	adminID := uuid.New()
+	review := &models.Review{ID: uuid.New(), RevieweeID: uuid.New(), Rating: 1}
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: review.RevieweeID}
+
+	reviews.EXPECT().GetByID(gomock.Any()).Return(nil, errors.New("record not found"))
+	if err := svc.DeleteReview(uuid.New(), adminID); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("expected ErrReviewNotFound, got %v", err)
+	}
+
+	reviews.EXPECT().GetByID(review.ID).Return(review, nil)
+	reviews.EXPECT().Delete(review.ID).Return(nil)
+	profiles.EXPECT().GetByUserID(review.RevieweeID).Return(profile, nil)
+	profiles.EXPECT().UpdateRating(profile.ID).Return(nil)
+	audit.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *models.AuditLog) error {
+		if log.Action != "delete_review" || log.OldValues == nil {
+			t.Errorf("expected the deleted review in the audit log, got %+v", log)
+		}
+		return nil
+	})
+	if err := svc.DeleteReview(review.ID, adminID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListReviewsValidatesFilter(t *testing.T) {
+	svc, reviews, _, _ := newTestReviewModeration(t)
+
+	low, high := 4, 2
+	if _, _, err := svc.ListReviews(repository.ReviewFilter{MinRating: &low, MaxRating: &high}, 1, 20); !errors.Is(err, ErrInvalidReviewFilter) {
+		t.Errorf("expected an inverted rating range to be rejected, got %v", err)
+	}
+	if _, _, err := svc.ListReviews(repository.ReviewFilter{Sort: "helpful"}, 1, 20); !errors.Is(err, ErrInvalidReviewFilter) {
+		t.Errorf("expected an unknown sort to be rejected, got %v", err)
+	}
+
+	hidden := true
+	filter := repository.ReviewFilter{Hidden: &hidden, Sort: repository.ReviewSortRatingAsc}
+	reviews.EXPECT().ListAll(filter, 1, 20).Return(nil, int64(0), nil)
+	if _, _, err := svc.ListReviews(filter, 1, 20); err != nil {
+		t.Fatal(err)
+	}
+}
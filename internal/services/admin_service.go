@@ -7,26 +7,33 @@ import (
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/models"
 	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/currency"
 )
 
 // AdminService handles admin operations
 type AdminService struct {
-	repos *repository.Repositories
+	repos         *repository.Repositories
+	webhooks      *WebhookService
+	tokenVersions *TokenVersionCache
+	ratings       *ReviewStatsService
+	favorites     *FavoriteService
+	notifications *NotificationService
+	documents     *DocumentLinks
 }
 
-func NewAdminService(repos *repository.Repositories) *AdminService {
-	return &AdminService{repos: repos}
+func NewAdminService(repos *repository.Repositories, webhooks *WebhookService, tokenVersions *TokenVersionCache, ratings *ReviewStatsService, favorites *FavoriteService, notifications *NotificationService, documents *DocumentLinks) *AdminService {
+	return &AdminService{repos: repos, webhooks: webhooks, tokenVersions: tokenVersions, ratings: ratings, favorites: favorites, notifications: notifications, documents: documents}
 }
 
 // DashboardStats represents dashboard statistics
 type DashboardStats struct {
-	TotalUsers          int64   `json:"total_users"`
-	TotalYandas         int64   `json:"total_yandas"`
-	PendingApplications int64   `json:"pending_applications"`
-	TotalOrders         int64   `json:"total_orders"`
-	CompletedOrders     int64   `json:"completed_orders"`
-	TotalRevenue        float64 `json:"total_revenue"`
-	ActiveSubscriptions int64   `json:"active_subscriptions"`
+	TotalUsers          int64          `json:"total_users"`
+	TotalYandas         int64          `json:"total_yandas"`
+	PendingApplications int64          `json:"pending_applications"`
+	TotalOrders         int64          `json:"total_orders"`
+	CompletedOrders     int64          `json:"completed_orders"`
+	TotalRevenue        currency.Money `json:"total_revenue"`
+	ActiveSubscriptions int64          `json:"active_subscriptions"`
 }
 
 // GetDashboard returns dashboard statistics
@@ -34,11 +41,11 @@ func (s *AdminService) GetDashboard() (*DashboardStats, error) {
 	stats := &DashboardStats{}
 
 	// This is simplified - in production you'd have dedicated count methods
-	_, total, _ := s.repos.User.List(1, 99999, "")
+	_, total, _ := s.repos.User.List(repository.UserFilter{}, 1, 1)
 	stats.TotalUsers = total
 
 	// Total yandaş
-	_, yandasTotal, _ := s.repos.User.List(1, 1, "yandas")
+	_, yandasTotal, _ := s.repos.User.List(repository.UserFilter{Role: "yandas"}, 1, 1)
 	stats.TotalYandas = yandasTotal
 
 	// Pending applications
@@ -46,18 +53,35 @@ func (s *AdminService) GetDashboard() (*DashboardStats, error) {
 	stats.PendingApplications = pendingTotal
 
 	// Orders
-	_, ordersTotal, _ := s.repos.Order.ListAll(1, 1, "")
+	_, ordersTotal, _ := s.repos.Order.ListAll(repository.OrderFilter{}, 1, 1)
 	stats.TotalOrders = ordersTotal
 
-	_, completedTotal, _ := s.repos.Order.ListAll(1, 1, "completed")
+	_, completedTotal, _ := s.repos.Order.ListAll(repository.OrderFilter{Status: "completed"}, 1, 1)
 	stats.CompletedOrders = completedTotal
 
 	return stats, nil
 }
 
-// ListUsers returns paginated users
-func (s *AdminService) ListUsers(page, limit int, role string) ([]models.User, int64, error) {
-	return s.repos.User.List(page, limit, role)
+// ErrInvalidUserFilter is returned for an unknown sort or subscription state or an inverted date range
+var ErrInvalidUserFilter = validationError("invalid_user_filter", "invalid user filter")
+
+// ListUsers returns paginated users matching filter
+func (s *AdminService) ListUsers(filter repository.UserFilter, page, limit int) ([]models.User, int64, error) {
+	switch filter.Sort {
+	case "", repository.UserSortNewest, repository.UserSortOldest, repository.UserSortName, repository.UserSortEmail:
+	default:
+		return nil, 0, ErrInvalidUserFilter
+	}
+	switch filter.Subscription {
+	case "", repository.UserSubscriptionActive, repository.UserSubscriptionCancelled,
+		repository.UserSubscriptionExpired, repository.UserSubscriptionNone:
+	default:
+		return nil, 0, ErrInvalidUserFilter
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return nil, 0, ErrInvalidUserFilter
+	}
+	return s.repos.User.List(filter, page, limit)
 }
 
 // GetUser returns a user by ID
@@ -67,24 +91,33 @@ func (s *AdminService) GetUser(userID uuid.UUID) (*models.User, error) {
 
 // UpdateUser updates a user
 func (s *AdminService) UpdateUser(userID uuid.UUID, updates map[string]interface{}) (*models.User, error) {
-	user, err := s.repos.User.GetByID(userID)
+	user, err := s.repos.OnPrimary().User.GetByID(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	if role, ok := updates["role"].(string); ok {
+	privilegesChanged := false
+	if role, ok := updates["role"].(string); ok && role != user.Role {
 		user.Role = role
+		privilegesChanged = true
 	}
-	if isActive, ok := updates["is_active"].(bool); ok {
+	if isActive, ok := updates["is_active"].(bool); ok && isActive != user.IsActive {
 		user.IsActive = isActive
+		privilegesChanged = true
 	}
 	if isVerified, ok := updates["is_verified"].(bool); ok {
 		user.IsVerified = isVerified
 	}
+	if privilegesChanged {
+		user.TokenVersion++
+	}
 
 	if err := s.repos.User.Update(user); err != nil {
 		return nil, err
 	}
+	if privilegesChanged {
+		s.tokenVersions.Invalidate(user.ID)
+	}
 
 	return user, nil
 }
@@ -94,41 +127,109 @@ func (s *AdminService) DeleteUser(userID uuid.UUID) error {
 	return s.repos.User.Delete(userID)
 }
 
+// BulkUserStatusInput represents a bulk activate/deactivate request
+type BulkUserStatusInput struct {
+	UserIDs  []uuid.UUID `json:"user_ids" binding:"required,min=1,max=500"`
+	IsActive *bool       `json:"is_active" binding:"required"`
+}
+
+// BulkResult reports per-item outcomes of a bulk action
+type BulkResult struct {
+	Succeeded []uuid.UUID          `json:"succeeded"`
+	Failed    map[uuid.UUID]string `json:"failed"`
+}
+
+// BulkSetUserStatus activates or deactivates many users; the admin's own account is skipped
+func (s *AdminService) BulkSetUserStatus(input *BulkUserStatusInput, adminID uuid.UUID) (*BulkResult, error) {
+	result := &BulkResult{Succeeded: []uuid.UUID{}, Failed: map[uuid.UUID]string{}}
+
+	ids := make([]uuid.UUID, 0, len(input.UserIDs))
+	for _, id := range input.UserIDs {
+		if id == adminID {
+			result.Failed[id] = "cannot change own account"
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	if _, err := s.repos.User.SetActiveBulk(ids, *input.IsActive); err != nil {
+		return nil, err
+	}
+	s.tokenVersions.Invalidate(ids...)
+	result.Succeeded = ids
+
+	s.logAction(adminID, "bulk_user_status", "user", uuid.Nil, nil, map[string]interface{}{
+		"user_ids":  ids,
+		"is_active": *input.IsActive,
+	})
+
+	return result, nil
+}
+
 // ListApplications returns all yandaş applications
 func (s *AdminService) ListApplications(page, limit int, status string) ([]models.YandasProfile, int64, error) {
 	return s.repos.YandasProfile.ListAllApplications(page, limit, status)
 }
 
-// ApplicationDetailResponse wraps profile with admin-only document URLs
+// ApplicationDetailResponse wraps profile with admin-only document URLs and identity check
 type ApplicationDetailResponse struct {
 	*models.YandasProfile
-	Documents map[string]*string `json:"documents"`
+	Documents map[string]*string         `json:"documents"`
+	Answers   []models.ApplicationAnswer `json:"answers"` // file answers carry signed links
+	KYC       *KYCOutcome                `json:"kyc,omitempty"`
 }
 
-// GetApplication returns a yandaş application with document URLs (admin only)
+// GetApplication returns a yandaş application with signed links to its
+// documents (admin only)
 func (s *AdminService) GetApplication(applicationID uuid.UUID) (*ApplicationDetailResponse, error) {
 	profile, err := s.repos.YandasProfile.GetByID(applicationID)
 	if err != nil {
 		return nil, err
 	}
+	if screening, err := s.repos.DocumentScreening.GetByProfileID(profile.ID); err == nil {
+		profile.Screening = screening
+	}
+	answers, err := s.repos.ApplicationForm.ListAnswers(profile.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range answers {
+		if answers[i].Type == QuestionFile {
+			answers[i].Value = *s.documents.Sign(&answers[i].Value)
+		}
+	}
 	return &ApplicationDetailResponse{
 		YandasProfile: profile,
 		Documents: map[string]*string{
-			"kimlik_on":      profile.KimlikOnURL,
-			"kimlik_arka":    profile.KimlikArkaURL,
-			"ehliyet_on":     profile.EhliyetOnURL,
-			"ehliyet_arka":   profile.EhliyetArkaURL,
-			"adli_sicil_pdf": profile.AdliSicilPDFURL,
+			"kimlik_on":      s.documents.Sign(profile.KimlikOnURL),
+			"kimlik_arka":    s.documents.Sign(profile.KimlikArkaURL),
+			"ehliyet_on":     s.documents.Sign(profile.EhliyetOnURL),
+			"ehliyet_arka":   s.documents.Sign(profile.EhliyetArkaURL),
+			"adli_sicil_pdf": s.documents.Sign(profile.AdliSicilPDFURL),
+			"selfie":         s.documents.Sign(profile.SelfieURL),
 		},
+		Answers: answers,
+		KYC:     kycOutcome(profile),
 	}, nil
 }
 
 // ApproveApplication approves a yandaş application
 func (s *AdminService) ApproveApplication(applicationID uuid.UUID, adminID uuid.UUID) error {
-	profile, err := s.repos.YandasProfile.GetByID(applicationID)
+	profile, err := s.repos.OnPrimary().YandasProfile.GetByID(applicationID)
+	if err != nil {
+		return err
+	}
+	// The categories' rules may have changed since the application was made
+	missing, err := missingDocuments(s.repos.OnPrimary(), profile)
 	if err != nil {
 		return err
 	}
+	if len(missing) > 0 {
+		return missingDocumentsError(missing)
+	}
 
 	now := time.Now()
 	profile.ApprovalStatus = "approved"
@@ -136,28 +237,71 @@ func (s *AdminService) ApproveApplication(applicationID uuid.UUID, adminID uuid.
 	profile.ApprovedAt = &now
 	profile.IsAvailable = true
 
-	if err := s.repos.YandasProfile.Update(profile); err != nil {
-		return err
-	}
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.YandasProfile.Update(profile); err != nil {
+			return err
+		}
 
-	// Update user role
-	user, err := s.repos.User.GetByID(profile.UserID)
-	if err == nil {
+		// Update user role
+		user, err := tx.User.GetByID(profile.UserID)
+		if err != nil {
+			return err
+		}
 		user.Role = "yandas"
-		s.repos.User.Update(user)
+		user.TokenVersion++
+		return tx.User.Update(user)
+	})
+	if err != nil {
+		return err
 	}
+	s.tokenVersions.Invalidate(profile.UserID)
 
 	// Log action
 	s.logAction(adminID, "approve_application", "yandas_profile", applicationID, nil, map[string]interface{}{
 		"status": "approved",
 	})
 
+	s.webhooks.Dispatch(WebhookApplicationApproved, map[string]interface{}{
+		"yandas_id":   profile.ID,
+		"user_id":     profile.UserID,
+		"approved_at": profile.ApprovedAt,
+	})
+
 	return nil
 }
 
+// BulkApproveInput represents a bulk application approval request
+type BulkApproveInput struct {
+	ApplicationIDs []uuid.UUID `json:"application_ids" binding:"required,min=1,max=100"`
+}
+
+// BulkApproveApplications approves each pending application independently so one failure doesn't block the rest
+func (s *AdminService) BulkApproveApplications(input *BulkApproveInput, adminID uuid.UUID) *BulkResult {
+	result := &BulkResult{Succeeded: []uuid.UUID{}, Failed: map[uuid.UUID]string{}}
+
+	for _, id := range input.ApplicationIDs {
+		profile, err := s.repos.YandasProfile.GetByID(id)
+		if err != nil {
+			result.Failed[id] = "application not found"
+			continue
+		}
+		if profile.ApprovalStatus != "pending" {
+			result.Failed[id] = "application is not pending"
+			continue
+		}
+		if err := s.ApproveApplication(id, adminID); err != nil {
+			result.Failed[id] = err.Error()
+			continue
+		}
+		result.Succeeded = append(result.Succeeded, id)
+	}
+
+	return result
+}
+
 // RejectApplication rejects a yandaş application
 func (s *AdminService) RejectApplication(applicationID uuid.UUID, adminID uuid.UUID, reason string) error {
-	profile, err := s.repos.YandasProfile.GetByID(applicationID)
+	profile, err := s.repos.OnPrimary().YandasProfile.GetByID(applicationID)
 	if err != nil {
 		return err
 	}
@@ -178,9 +322,37 @@ func (s *AdminService) RejectApplication(applicationID uuid.UUID, adminID uuid.U
 	return nil
 }
 
-// ListOrders returns all orders (admin view)
-func (s *AdminService) ListOrders(page, limit int, status string) ([]models.Order, int64, error) {
-	return s.repos.Order.ListAll(page, limit, status)
+// ErrInvalidOrderFilter is returned for an unknown sort or an inverted range
+var ErrInvalidOrderFilter = validationError("invalid_order_filter", "invalid order filter")
+
+// ListOrders returns orders matching filter (admin view) along with the
+// revenue of the completed ones across every page
+func (s *AdminService) ListOrders(filter repository.OrderFilter, page, limit int) ([]models.Order, int64, *RevenueSummary, error) {
+	switch filter.Sort {
+	case "", repository.OrderSortNewest, repository.OrderSortOldest, repository.OrderSortAmountDesc,
+		repository.OrderSortAmountAsc, repository.OrderSortScheduled:
+	default:
+		return nil, 0, nil, ErrInvalidOrderFilter
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return nil, 0, nil, ErrInvalidOrderFilter
+	}
+	if filter.ScheduledFrom != nil && filter.ScheduledTo != nil && !filter.ScheduledFrom.Before(*filter.ScheduledTo) {
+		return nil, 0, nil, ErrInvalidOrderFilter
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return nil, 0, nil, ErrInvalidOrderFilter
+	}
+
+	orders, total, err := s.repos.Order.ListAll(filter, page, limit)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	revenue, err := s.repos.Order.SumRevenue(filter)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return orders, total, summarizeRevenue(revenue), nil
 }
 
 // GetOrder returns an order
@@ -188,19 +360,155 @@ func (s *AdminService) GetOrder(orderID uuid.UUID) (*models.Order, error) {
 	return s.repos.Order.GetByID(orderID)
 }
 
+// ErrInvalidReviewFilter is returned for an unknown sort or an inverted range
+var ErrInvalidReviewFilter = validationError("invalid_review_filter", "invalid review filter")
+
+// ListReviews returns reviews matching filter, hidden ones included
+func (s *AdminService) ListReviews(filter repository.ReviewFilter, page, limit int) ([]models.Review, int64, error) {
+	switch filter.Sort {
+	case "", repository.ReviewSortNewest, repository.ReviewSortOldest,
+		repository.ReviewSortRatingAsc, repository.ReviewSortRatingDesc:
+	default:
+		return nil, 0, ErrInvalidReviewFilter
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return nil, 0, ErrInvalidReviewFilter
+	}
+	if filter.MinRating != nil && filter.MaxRating != nil && *filter.MinRating > *filter.MaxRating {
+		return nil, 0, ErrInvalidReviewFilter
+	}
+	return s.repos.Review.ListAll(filter, page, limit)
+}
+
+// SetReviewHidden hides a review from the public listing and the yandaş's
+// rating, or shows it again, and recalculates the rating
+func (s *AdminService) SetReviewHidden(reviewID uuid.UUID, hidden bool, adminID uuid.UUID) error {
+	review, err := s.repos.OnPrimary().Review.GetByID(reviewID)
+	if err != nil {
+		return ErrReviewNotFound
+	}
+	if review.IsHidden == hidden {
+		return nil
+	}
+
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.Review.SetHidden(reviewID, hidden, adminID); err != nil {
+			return err
+		}
+		return updateRevieweeRating(tx, review)
+	})
+	if err != nil {
+		return err
+	}
+	s.ratings.Invalidate(review.RevieweeID)
+
+	action := "unhide_review"
+	if hidden {
+		action = "hide_review"
+	}
+	s.logAction(adminID, action, "review", reviewID, map[string]interface{}{
+		"is_hidden": review.IsHidden,
+	}, map[string]interface{}{
+		"is_hidden": hidden,
+	})
+
+	return nil
+}
+
+// DeleteReview removes an abusive review for good and recalculates the
+// yandaş's rating. The audit log keeps a copy of what was removed.
+func (s *AdminService) DeleteReview(reviewID uuid.UUID, adminID uuid.UUID) error {
+	review, err := s.repos.OnPrimary().Review.GetByID(reviewID)
+	if err != nil {
+		return ErrReviewNotFound
+	}
+
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.Review.Delete(reviewID); err != nil {
+			return err
+		}
+		return updateRevieweeRating(tx, review)
+	})
+	if err != nil {
+		return err
+	}
+	s.ratings.Invalidate(review.RevieweeID)
+
+	s.logAction(adminID, "delete_review", "review", reviewID, map[string]interface{}{
+		"order_id":    review.OrderID,
+		"reviewer_id": review.ReviewerID,
+		"reviewee_id": review.RevieweeID,
+		"rating":      review.Rating,
+		"comment":     review.Comment,
+		"is_hidden":   review.IsHidden,
+	}, nil)
+
+	return nil
+}
+
+// updateRevieweeRating recalculates the rating of the yandaş a review is about
+func updateRevieweeRating(tx *repository.Repositories, review *models.Review) error {
+	profile, err := tx.YandasProfile.GetByUserID(review.RevieweeID)
+	if err != nil {
+		return ErrYandasProfileNotFound
+	}
+	return tx.YandasProfile.UpdateRating(profile.ID)
+}
+
 // Category management
 func (s *AdminService) CreateCategory(category *models.Category) error {
+	if err := validateCommissionRate(category.CommissionRate); err != nil {
+		return err
+	}
 	return s.repos.Category.Create(category)
 }
 
 func (s *AdminService) UpdateCategory(category *models.Category) error {
+	if err := validateCommissionRate(category.CommissionRate); err != nil {
+		return err
+	}
 	return s.repos.Category.Update(category)
 }
 
+func validateCommissionRate(rate *float64) error {
+	if rate != nil && (*rate < 0 || *rate > 1) {
+		return validationError("invalid_commission_rate", "commission rate must be between 0 and 1")
+	}
+	return nil
+}
+
 func (s *AdminService) DeleteCategory(categoryID uuid.UUID) error {
 	return s.repos.Category.Delete(categoryID)
 }
 
+// CallRecordingResponse wraps a call log with its admin-only recording references
+type CallRecordingResponse struct {
+	*models.CallLog
+	RecordingFiles []string `json:"recording_files"`
+}
+
+// GetCallRecording returns the recording files of a call. Every access is audit-logged
+// since recordings are only meant to be reviewed as dispute evidence.
+func (s *AdminService) GetCallRecording(callID uuid.UUID, adminID uuid.UUID) (*CallRecordingResponse, error) {
+	callLog, err := s.repos.CallLog.GetByID(callID)
+	if err != nil {
+		return nil, ErrCallNotFound
+	}
+
+	if len(callLog.RecordingFiles) == 0 {
+		return nil, notFoundError("recording_not_found", "no recording available for this call")
+	}
+
+	s.logAction(adminID, "view_call_recording", "call_log", callID, nil, map[string]interface{}{
+		"files": []string(callLog.RecordingFiles),
+	})
+
+	return &CallRecordingResponse{
+		CallLog:        callLog,
+		RecordingFiles: callLog.RecordingFiles,
+	}, nil
+}
+
 // GetAuditLogs returns audit logs
 func (s *AdminService) GetAuditLogs(page, limit int, adminID *uuid.UUID, action string) ([]models.AuditLog, int64, error) {
 	return s.repos.AuditLog.List(page, limit, adminID, action)
@@ -251,12 +559,13 @@ func (s *AdminService) UpdateSupportTicket(ticketID uuid.UUID, status, priority,
 	if status != "" {
 		ticket.Status = status
 		if status == "resolved" {
-			now := time.Now()
-			ticket.ResolvedAt = &now
+			markResolved(ticket, time.Now())
 		}
 	}
-	if priority != "" {
+	if priority != "" && priority != ticket.Priority {
 		ticket.Priority = priority
+		// Targets for the new priority still count from when the ticket was opened
+		applySLA(ticket, ticket.CreatedAt)
 	}
 	if assignedTo != "" {
 		id, err := uuid.Parse(assignedTo)
@@ -269,28 +578,58 @@ func (s *AdminService) UpdateSupportTicket(ticketID uuid.UUID, status, priority,
 	return ticket, err
 }
 
-func (s *AdminService) ReplySupportTicket(ticketID, adminID uuid.UUID, content string) (*models.SupportMessage, error) {
+// ReplySupportTicket posts an agent reply, or an internal note that the ticket owner never sees
+func (s *AdminService) ReplySupportTicket(ticketID, adminID uuid.UUID, input *SupportReplyInput) (*models.SupportMessage, error) {
+	ticket, err := s.repos.Support.GetTicket(ticketID)
+	if err != nil {
+		return nil, ErrTicketNotFound
+	}
+
+	content, err := s.replyContent(ticket, input)
+	if err != nil {
+		return nil, err
+	}
+
 	message := &models.SupportMessage{
-		TicketID: ticketID,
-		SenderID: adminID,
-		Content:  content,
-		IsAdmin:  true,
+		TicketID:   ticketID,
+		SenderID:   adminID,
+		Content:    content,
+		IsAdmin:    true,
+		IsInternal: input.Internal,
 	}
 
 	if err := s.repos.Support.CreateMessage(message); err != nil {
 		return nil, err
 	}
 
+	// Notes don't answer the user, so they leave the status and SLA alone
+	if input.Internal {
+		return message, nil
+	}
+
 	// Update ticket status to pending (waiting for user response)
-	ticket, _ := s.repos.Support.GetTicket(ticketID)
-	if ticket != nil && ticket.Status == "open" {
-		ticket.Status = "pending"
+	if ticket.Status == "open" || ticket.FirstRespondedAt == nil {
+		if ticket.Status == "open" {
+			ticket.Status = "pending"
+		}
+		markFirstResponse(ticket, message.CreatedAt)
 		s.repos.Support.UpdateTicket(ticket)
 	}
 
 	return message, nil
 }
 
+// GetSupportStats returns ticket counts plus SLA compliance percentages
 func (s *AdminService) GetSupportStats() (map[string]int64, error) {
-	return s.repos.Support.GetStats()
+	stats, err := s.repos.Support.GetStats()
+	if err != nil {
+		return nil, err
+	}
+	if pct, ok := slaCompliance(stats["sla_first_response_met"], stats["sla_first_response_breached"]); ok {
+		stats["sla_first_response_compliance"] = pct
+	}
+	if pct, ok := slaCompliance(stats["sla_resolution_met"], stats["sla_resolution_breached"]); ok {
+		stats["sla_resolution_compliance"] = pct
+	}
+	return stats, nil
 }
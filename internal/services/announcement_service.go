@@ -0,0 +1,126 @@
+package services
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+var (
+	ErrAnnouncementNotFound       = notFoundError("announcement_not_found", "announcement not found")
+	ErrAnnouncementWindow         = validationError("announcement_window", "ends_at must be after starts_at")
+	ErrAnnouncementNotDismissible = conflictError("announcement_not_dismissible", "announcement cannot be dismissed")
+)
+
+// AnnouncementService manages in-app announcement banners
+type AnnouncementService struct {
+	repos *repository.Repositories
+}
+
+func NewAnnouncementService(repos *repository.Repositories) *AnnouncementService {
+	return &AnnouncementService{repos: repos}
+}
+
+// AnnouncementInput represents announcement data
+type AnnouncementInput struct {
+	Title       string     `json:"title" binding:"required,max=200"`
+	Body        string     `json:"body" binding:"required"`
+	Audience    string     `json:"audience" binding:"omitempty,oneof=all customer yandas"`
+	Severity    string     `json:"severity" binding:"omitempty,oneof=info warning critical"`
+	LinkURL     string     `json:"link_url" binding:"omitempty,url,max=500"`
+	Dismissible *bool      `json:"dismissible"` // defaults to true
+	IsActive    *bool      `json:"is_active"`   // defaults to true
+	StartsAt    *time.Time `json:"starts_at"`   // defaults to now
+	EndsAt      *time.Time `json:"ends_at"`
+}
+
+// Active returns the announcements a user with the given role should see now
+func (s *AnnouncementService) Active(userID uuid.UUID, role string) ([]models.Announcement, error) {
+	audiences := []string{"all"}
+	if role == "customer" || role == "yandas" {
+		audiences = append(audiences, role)
+	}
+	return s.repos.Announcement.ListActive(userID, audiences, time.Now())
+}
+
+// Dismiss hides an announcement for the user
+func (s *AnnouncementService) Dismiss(announcementID, userID uuid.UUID) error {
+	announcement, err := s.repos.Announcement.GetByID(announcementID)
+	if err != nil {
+		return ErrAnnouncementNotFound
+	}
+	if !announcement.Dismissible {
+		return ErrAnnouncementNotDismissible
+	}
+	return s.repos.Announcement.Dismiss(announcementID, userID)
+}
+
+func (s *AnnouncementService) List(page, limit int) ([]models.Announcement, int64, error) {
+	return s.repos.Announcement.List(page, limit)
+}
+
+func (s *AnnouncementService) Create(adminID uuid.UUID, input *AnnouncementInput) (*models.Announcement, error) {
+	announcement := &models.Announcement{CreatedBy: adminID, Dismissible: true, IsActive: true, StartsAt: time.Now()}
+	if err := applyAnnouncementInput(announcement, input); err != nil {
+		return nil, err
+	}
+	if err := s.repos.Announcement.Create(announcement); err != nil {
+		return nil, err
+	}
+	return announcement, nil
+}
+
+func (s *AnnouncementService) Update(id uuid.UUID, input *AnnouncementInput) (*models.Announcement, error) {
+	announcement, err := s.repos.Announcement.GetByID(id)
+	if err != nil {
+		return nil, ErrAnnouncementNotFound
+	}
+	if err := applyAnnouncementInput(announcement, input); err != nil {
+		return nil, err
+	}
+	if err := s.repos.Announcement.Update(announcement); err != nil {
+		return nil, err
+	}
+	return announcement, nil
+}
+
+func (s *AnnouncementService) Delete(id uuid.UUID) error {
+	if _, err := s.repos.Announcement.GetByID(id); err != nil {
+		return ErrAnnouncementNotFound
+	}
+	return s.repos.Announcement.Delete(id)
+}
+
+func applyAnnouncementInput(announcement *models.Announcement, input *AnnouncementInput) error {
+	announcement.Title = input.Title
+	announcement.Body = input.Body
+	announcement.Audience = "all"
+	if input.Audience != "" {
+		announcement.Audience = input.Audience
+	}
+	announcement.Severity = "info"
+	if input.Severity != "" {
+		announcement.Severity = input.Severity
+	}
+	announcement.LinkURL = nil
+	if input.LinkURL != "" {
+		link := input.LinkURL
+		announcement.LinkURL = &link
+	}
+	if input.Dismissible != nil {
+		announcement.Dismissible = *input.Dismissible
+	}
+	if input.IsActive != nil {
+		announcement.IsActive = *input.IsActive
+	}
+	if input.StartsAt != nil {
+		announcement.StartsAt = *input.StartsAt
+	}
+	announcement.EndsAt = input.EndsAt
+	if announcement.EndsAt != nil && !announcement.EndsAt.After(announcement.StartsAt) {
+		return ErrAnnouncementWindow
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestAnnouncementActiveAudiences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	announcements := mocks.NewMockAnnouncementRepository(ctrl)
+	svc := NewAnnouncementService(&repository.Repositories{Announcement: announcements})
+	userID := uuid.New()
+
+	announcements.EXPECT().ListActive(userID, []string{"all", "yandas"}, gomock.Any()).Return(nil, nil)
+	announcements.EXPECT().ListActive(userID, []string{"all"}, gomock.Any()).Return(nil, nil)
+
+	if _, err := svc.Active(userID, "yandas"); err != nil {
+		t.Fatal(err)
+	}
+	// Admins only see announcements meant for everyone
+	if _, err := svc.Active(userID, "admin"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAnnouncementCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	announcements := mocks.NewMockAnnouncementRepository(ctrl)
+	svc := NewAnnouncementService(&repository.Repositories{Announcement: announcements})
+
+	start := time.Date(2026, 7, 1, 22, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+	if _, err := svc.Create(uuid.New(), &AnnouncementInput{Title: "Bakım", Body: "Planlı bakım", StartsAt: &start, EndsAt: &end}); err != ErrAnnouncementWindow {
+		t.Fatalf("expected ErrAnnouncementWindow, got %v", err)
+	}
+
+	announcements.EXPECT().Create(gomock.Any()).Return(nil)
+	announcement, err := svc.Create(uuid.New(), &AnnouncementInput{Title: "Bakım", Body: "Planlı bakım", Severity: "warning"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !announcement.IsActive || !announcement.Dismissible || announcement.Audience != "all" || announcement.Severity != "warning" {
+		t.Errorf("unexpected defaults: %+v", announcement)
+	}
+}
+
+func TestAnnouncementDismissRequiresDismissible(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	announcements := mocks.NewMockAnnouncementRepository(ctrl)
+	svc := NewAnnouncementService(&repository.Repositories{Announcement: announcements})
+	id := uuid.New()
+
+	announcements.EXPECT().GetByID(id).Return(&models.Announcement{ID: id, Dismissible: false}, nil)
+	if err := svc.Dismiss(id, uuid.New()); err != ErrAnnouncementNotDismissible {
+		t.Errorf("expected ErrAnnouncementNotDismissible, got %v", err)
+	}
+}
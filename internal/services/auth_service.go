@@ -2,53 +2,66 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"log"
-	"math/rand"
+	"math/big"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
-	twilio "github.com/twilio/twilio-go"
-	verify "github.com/twilio/twilio-go/rest/verify/v2"
 	"github.com/yandas/backend/internal/config"
 	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/queue"
 	"github.com/yandas/backend/internal/repository"
 	"github.com/yandas/backend/pkg/auth"
 	"golang.org/x/crypto/bcrypt"
 )
 
 var (
-	ErrInvalidCredentials = errors.New("invalid email or password")
-	ErrUserNotFound       = errors.New("user not found")
-	ErrUserExists         = errors.New("user already exists")
-	ErrInvalidOTP         = errors.New("invalid or expired OTP")
-	ErrUserNotVerified    = errors.New("user not verified")
-	ErrUserInactive       = errors.New("user account is inactive")
+	ErrInvalidCredentials = unauthenticatedError("invalid_credentials", "invalid email or password")
+	ErrUserNotFound       = notFoundError("user_not_found", "user not found")
+	ErrUserExists         = conflictError("user_exists", "user already exists")
+	ErrInvalidOTP         = validationError("invalid_otp", "invalid or expired OTP")
+	ErrUserNotVerified    = unauthenticatedError("user_not_verified", "user not verified")
+	ErrUserInactive       = unauthenticatedError("user_inactive", "user account is inactive")
+	ErrInvalidToken       = unauthenticatedError("invalid_token", "invalid or expired token")
 )
 
 // AuthService handles authentication
 type AuthService struct {
-	repos    *repository.Repositories
-	cfg      *config.Config
-	redis    *redis.Client
-	emailSvc *EmailService
+	repos         *repository.Repositories
+	cfg           *config.Config
+	redis         *redis.Client
+	emailSvc      *EmailService
+	sms           *SMSService
+	monitoring    *MonitoringService
+	tokenVersions *TokenVersionCache
+	jobs          *queue.Queue
+	otpLimits     otpLimiter
+	settings      *SettingsService
+	events        *EventBus
+	consents      *ConsentService
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(repos *repository.Repositories, cfg *config.Config, redis *redis.Client, emailSvc *EmailService) *AuthService {
-	return &AuthService{repos: repos, cfg: cfg, redis: redis, emailSvc: emailSvc}
+func NewAuthService(repos *repository.Repositories, cfg *config.Config, redis *redis.Client, emailSvc *EmailService, smsSvc *SMSService, monitoring *MonitoringService, tokenVersions *TokenVersionCache, jobs *queue.Queue, settings *SettingsService, events *EventBus, consents *ConsentService) *AuthService {
+	return &AuthService{repos: repos, cfg: cfg, redis: redis, emailSvc: emailSvc, sms: smsSvc, monitoring: monitoring, tokenVersions: tokenVersions, jobs: jobs, otpLimits: otpLimiter{redis: redis, settings: settings}, settings: settings, events: events, consents: consents}
 }
 
 // RegisterInput represents registration data
 type RegisterInput struct {
 	Email    string `json:"email" binding:"required,email"`
-	Phone    string `json:"phone"`
+	Phone    string `json:"phone" binding:"omitempty,tr_phone"`
 	Password string `json:"password" binding:"required,min=6"`
 	FullName string `json:"full_name" binding:"required"`
 	Platform string `json:"platform"`
+	// IDs of the current consent documents (GET /legal/current), all required
+	AcceptedConsents []uuid.UUID `json:"accepted_consents"`
+
+	Client ClientInfo `json:"-"`
 }
 
 // Register creates a new user account
@@ -62,6 +75,14 @@ func (s *AuthService) Register(input *RegisterInput) (*models.User, *auth.TokenP
 		return nil, nil, ErrUserExists
 	}
 
+	documents, err := s.consents.Required()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := requireAllAccepted(documents, input.AcceptedConsents); err != nil {
+		return nil, nil, err
+	}
+
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -83,40 +104,36 @@ func (s *AuthService) Register(input *RegisterInput) (*models.User, *auth.TokenP
 		user.Phone = &input.Phone
 	}
 
-	if err := s.repos.User.Create(user); err != nil {
+	// Verification codes are sent by the UserRegistered subscribers
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.User.Create(user); err != nil {
+			return err
+		}
+		consents, err := consentRecords(user.ID, documents, input.AcceptedConsents, input.Client)
+		if err != nil {
+			return err
+		}
+		if err := tx.Consent.Accept(consents); err != nil {
+			return err
+		}
+		return s.events.Publish(tx, EventUserRegistered, user.ID, UserRegisteredEvent{
+			UserID:   user.ID,
+			Email:    input.Email,
+			Phone:    input.Phone,
+			FullName: input.FullName,
+		})
+	})
+	if err != nil {
 		return nil, nil, err
 	}
+	s.events.Flush()
 
 	// Generate tokens
-	tokens, err := auth.GenerateTokenPair(
-		user.ID.String(),
-		*user.Email,
-		user.Role,
-		input.Platform,
-		s.cfg.JWTSecret,
-		s.cfg.JWTAccessExpiry,
-		s.cfg.JWTRefreshExpiry,
-	)
+	tokens, err := s.startSession(user, input.Platform, input.Client)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Send email OTP
-	go func() {
-		if err := s.SendEmailOTP(input.Email, input.FullName); err != nil {
-			log.Printf("Email OTP gönderme hatası: %v\n", err)
-		}
-	}()
-
-	// Send SMS OTP if phone provided
-	if input.Phone != "" {
-		go func() {
-			if err := s.SendOTP(input.Phone); err != nil {
-				log.Printf("SMS OTP gönderme hatası: %v\n", err)
-			}
-		}()
-	}
-
 	return user, tokens, nil
 }
 
@@ -125,12 +142,15 @@ type LoginInput struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
 	Platform string `json:"platform"`
+
+	Client ClientInfo `json:"-"`
 }
 
 // Login authenticates a user
 func (s *AuthService) Login(input *LoginInput) (*models.User, *auth.TokenPair, error) {
 	user, err := s.repos.User.GetByEmail(input.Email)
 	if err != nil {
+		s.monitoring.Record(MetricFailedLogins)
 		return nil, nil, ErrInvalidCredentials
 	}
 
@@ -140,24 +160,12 @@ func (s *AuthService) Login(input *LoginInput) (*models.User, *auth.TokenPair, e
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password)); err != nil {
+		s.monitoring.Record(MetricFailedLogins)
 		return nil, nil, ErrInvalidCredentials
 	}
 
 	// Generate tokens
-	email := ""
-	if user.Email != nil {
-		email = *user.Email
-	}
-
-	tokens, err := auth.GenerateTokenPair(
-		user.ID.String(),
-		email,
-		user.Role,
-		input.Platform,
-		s.cfg.JWTSecret,
-		s.cfg.JWTAccessExpiry,
-		s.cfg.JWTRefreshExpiry,
-	)
+	tokens, err := s.startSession(user, input.Platform, input.Client)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -165,82 +173,54 @@ func (s *AuthService) Login(input *LoginInput) (*models.User, *auth.TokenPair, e
 	return user, tokens, nil
 }
 
-// RefreshToken generates new token pair from refresh token
-func (s *AuthService) RefreshToken(refreshToken, platform string) (*auth.TokenPair, error) {
+// RefreshToken generates new token pair from refresh token, rotating the session it belongs to
+func (s *AuthService) RefreshToken(refreshToken, platform string, client ClientInfo) (*auth.TokenPair, error) {
 	claims, err := auth.ValidateToken(refreshToken, s.cfg.JWTSecret)
-	if err != nil {
-		return nil, err
+	if err != nil || claims.Type == auth.TokenTypeAccess {
+		return nil, ErrInvalidToken
 	}
 
 	// Verify user still exists and is active
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		return nil, err
+		return nil, ErrInvalidToken
 	}
 
 	user, err := s.repos.User.GetByID(userID)
 	if err != nil {
-		return nil, ErrUserNotFound
+		return nil, ErrInvalidToken
 	}
 
 	if !user.IsActive {
 		return nil, ErrUserInactive
 	}
 
-	email := ""
-	if user.Email != nil {
-		email = *user.Email
+	// Tokens issued before sessions existed carry no sid; move them onto a fresh session
+	if claims.SessionID == "" {
+		return s.startSession(user, platform, client)
 	}
-
-	return auth.GenerateTokenPair(
-		user.ID.String(),
-		email,
-		user.Role,
-		platform,
-		s.cfg.JWTSecret,
-		s.cfg.JWTAccessExpiry,
-		s.cfg.JWTRefreshExpiry,
-	)
+	return s.rotateSession(user, claims.SessionID, refreshToken, platform, client)
 }
 
-// SendOTP sends OTP to phone number via Twilio Verify
+// SendOTP sends an OTP to a phone number through the configured SMS providers.
+// Resends are limited; an OTPLimitError says when the next one is allowed.
 func (s *AuthService) SendOTP(phone string) error {
-	// Normalize phone to E.164 format for Twilio
 	phone = normalizePhone(phone)
-
-	// Eğer Twilio yapılandırılmamışsa fallback kullan
-	if s.cfg.TwilioAccountSID == "" || s.cfg.TwilioVerifySID == "" {
-		otp := generateOTP()
-		if s.redis != nil {
-			key := fmt.Sprintf("otp:%s", phone)
-			ctx := context.Background()
-			s.redis.Set(ctx, key, otp, 5*time.Minute)
-		}
-		log.Printf("[FALLBACK] OTP for %s: %s\n", phone, otp)
-		return nil
+	if err := s.otpLimits.allowSend(OTPChannelPhone, phone); err != nil {
+		return err
 	}
-
-	// Twilio Verify ile SMS gönder
-	client := twilio.NewRestClientWithParams(twilio.ClientParams{
-		Username: s.cfg.TwilioAccountSID,
-		Password: s.cfg.TwilioAuthToken,
-	})
-
-	channel := "sms"
-	params := &verify.CreateVerificationParams{}
-	params.SetTo(phone)
-	params.SetChannel(channel)
-
-	_, err := client.VerifyV2.CreateVerification(s.cfg.TwilioVerifySID, params)
-	if err != nil {
-		log.Printf("Twilio SMS gönderme hatası: %v\n", err)
+	if err := s.sms.SendOTP(phone); err != nil {
+		s.monitoring.Record(MetricOTPFailures)
 		return fmt.Errorf("SMS gönderilemedi: %w", err)
 	}
-
-	log.Printf("✅ OTP SMS gönderildi: %s\n", phone)
 	return nil
 }
 
+// sendSMS sends a plain text message through the configured SMS providers
+func (s *AuthService) sendSMS(phone, body string) error {
+	return s.sms.Send(normalizePhone(phone), body)
+}
+
 // normalizePhone converts Turkish phone numbers to E.164 format
 func normalizePhone(phone string) string {
 	// Remove spaces, dashes, parentheses
@@ -261,55 +241,21 @@ func normalizePhone(phone string) string {
 	return "+90" + phone
 }
 
-// VerifyOTP verifies OTP code via Twilio Verify
+// VerifyOTP checks an OTP sent to a phone number. Too many wrong codes lock the
+// number out for a while, reported as an OTPLimitError.
 func (s *AuthService) VerifyOTP(phone, otp string) error {
-	originalPhone := phone
 	phone = normalizePhone(phone)
-	log.Printf("📞 VerifyOTP: giriş=%s, normalize=%s, otp=%s\n", originalPhone, phone, otp)
-
-	// Eğer Twilio yapılandırılmamışsa Redis fallback kullan
-	if s.cfg.TwilioAccountSID == "" || s.cfg.TwilioVerifySID == "" {
-		if s.redis == nil {
-			return ErrInvalidOTP
-		}
-		key := fmt.Sprintf("otp:%s", phone)
-		ctx := context.Background()
-		storedOTP, err := s.redis.Get(ctx, key).Result()
-		if err != nil || storedOTP != otp {
-			return ErrInvalidOTP
-		}
-		s.redis.Del(ctx, key)
-	} else {
-		// Twilio Verify ile doğrula
-		client := twilio.NewRestClientWithParams(twilio.ClientParams{
-			Username: s.cfg.TwilioAccountSID,
-			Password: s.cfg.TwilioAuthToken,
-		})
-
-		params := &verify.CreateVerificationCheckParams{}
-		params.SetTo(phone)
-		params.SetCode(otp)
-
-		resp, err := client.VerifyV2.CreateVerificationCheck(s.cfg.TwilioVerifySID, params)
-		if err != nil {
-			log.Printf("Twilio doğrulama hatası: %v\n", err)
-			return ErrInvalidOTP
-		}
-
-		statusStr := "nil"
-		if resp.Status != nil {
-			statusStr = *resp.Status
-		}
-		log.Printf("📞 Twilio OTP sonuç: status=%s, phone=%s\n", statusStr, phone)
-
-		if resp.Status == nil || *resp.Status != "approved" {
-			log.Printf("❌ Twilio OTP durumu: %s (beklenen: approved)\n", statusStr)
-			return ErrInvalidOTP
+	if err := s.otpLimits.allowVerify(OTPChannelPhone, phone); err != nil {
+		return err
+	}
+	if !s.sms.CheckOTP(phone, otp) {
+		if err := s.otpLimits.recordFailure(OTPChannelPhone, phone); err != nil {
+			return err
 		}
-
-		log.Printf("✅ Telefon OTP doğrulandı: %s\n", phone)
+		return ErrInvalidOTP
 	}
-
+	s.otpLimits.recordSuccess(OTPChannelPhone, phone)
+	log.Printf("✅ Telefon OTP doğrulandı: %s\n", phone)
 	return nil
 }
 
@@ -339,7 +285,7 @@ func (s *AuthService) ForgotPassword(email string) error {
 // ResetPassword resets password with token
 func (s *AuthService) ResetPassword(token, newPassword string) error {
 	if s.redis == nil {
-		return errors.New("service unavailable")
+		return unavailableError("service_unavailable", "service unavailable")
 	}
 
 	key := fmt.Sprintf("reset:%s", token)
@@ -347,7 +293,7 @@ func (s *AuthService) ResetPassword(token, newPassword string) error {
 
 	userIDStr, err := s.redis.Get(ctx, key).Result()
 	if err != nil {
-		return errors.New("invalid or expired reset token")
+		return validationError("invalid_reset_token", "invalid or expired reset token")
 	}
 
 	userID, err := uuid.Parse(userIDStr)
@@ -355,7 +301,7 @@ func (s *AuthService) ResetPassword(token, newPassword string) error {
 		return err
 	}
 
-	user, err := s.repos.User.GetByID(userID)
+	user, err := s.repos.OnPrimary().User.GetByID(userID)
 	if err != nil {
 		return ErrUserNotFound
 	}
@@ -376,13 +322,25 @@ func (s *AuthService) ResetPassword(token, newPassword string) error {
 	return nil
 }
 
-func generateOTP() string {
-	return fmt.Sprintf("%06d", rand.Intn(1000000))
+// generateOTP draws a six-digit code from crypto/rand; the code alone proves
+// control of an email address or phone number, so it must not be predictable
+func generateOTP() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
 }
 
 // SendEmailOTP generates and sends email OTP
 func (s *AuthService) SendEmailOTP(email, userName string) error {
-	otp := generateOTP()
+	if err := s.otpLimits.allowSend(OTPChannelEmail, strings.ToLower(email)); err != nil {
+		return err
+	}
+	otp, err := generateOTP()
+	if err != nil {
+		return err
+	}
 
 	// Store OTP in Redis
 	if s.redis != nil {
@@ -395,6 +353,7 @@ func (s *AuthService) SendEmailOTP(email, userName string) error {
 	if s.emailSvc != nil {
 		if err := s.emailSvc.SendOTPEmail(email, otp, userName); err != nil {
 			log.Printf("Email OTP gönderme hatası: %v\n", err)
+			s.monitoring.Record(MetricOTPFailures)
 			return err
 		}
 	} else {
@@ -409,17 +368,24 @@ func (s *AuthService) VerifyEmailOTP(email, otp string) error {
 	if s.redis == nil {
 		return ErrInvalidOTP
 	}
+	if err := s.otpLimits.allowVerify(OTPChannelEmail, strings.ToLower(email)); err != nil {
+		return err
+	}
 
 	key := fmt.Sprintf("email_otp:%s", email)
 	ctx := context.Background()
 
 	storedOTP, err := s.redis.Get(ctx, key).Result()
 	if err != nil || storedOTP != otp {
+		if err := s.otpLimits.recordFailure(OTPChannelEmail, strings.ToLower(email)); err != nil {
+			return err
+		}
 		return ErrInvalidOTP
 	}
 
 	// Delete OTP after successful verification
 	s.redis.Del(ctx, key)
+	s.otpLimits.recordSuccess(OTPChannelEmail, strings.ToLower(email))
 	return nil
 }
 
@@ -439,7 +405,10 @@ func (s *AuthService) VerifyAccount(email, emailOTP, phone, phoneOTP string) err
 	// Verify email OTP
 	if err := s.VerifyEmailOTP(email, emailOTP); err != nil {
 		log.Printf("❌ E-posta OTP doğrulama başarısız: email=%s, err=%v\n", email, err)
-		return fmt.Errorf("e-posta doğrulama kodu hatalı veya süresi dolmuş")
+		if errors.Is(err, ErrOTPRateLimited) {
+			return err
+		}
+		return validationError("invalid_email_otp", "e-posta doğrulama kodu hatalı veya süresi dolmuş")
 	}
 	log.Printf("✅ E-posta OTP doğrulandı: %s\n", email)
 
@@ -448,13 +417,16 @@ func (s *AuthService) VerifyAccount(email, emailOTP, phone, phoneOTP string) err
 		log.Printf("📱 Telefon OTP doğrulama başlatıldı: %s\n", phone)
 		if err := s.VerifyOTP(phone, phoneOTP); err != nil {
 			log.Printf("❌ Telefon OTP doğrulama başarısız: phone=%s, err=%v\n", phone, err)
-			return fmt.Errorf("telefon doğrulama kodu hatalı veya süresi dolmuş")
+			if errors.Is(err, ErrOTPRateLimited) {
+				return err
+			}
+			return validationError("invalid_phone_otp", "telefon doğrulama kodu hatalı veya süresi dolmuş")
 		}
 		log.Printf("✅ Telefon OTP doğrulandı: %s\n", phone)
 	}
 
 	// Mark user as verified
-	user, err := s.repos.User.GetByEmail(email)
+	user, err := s.repos.OnPrimary().User.GetByEmail(email)
 	if err != nil {
 		return ErrUserNotFound
 	}
@@ -465,9 +437,7 @@ func (s *AuthService) VerifyAccount(email, emailOTP, phone, phoneOTP string) err
 	}
 
 	// Send welcome email
-	if s.emailSvc != nil {
-		go s.emailSvc.SendWelcomeEmail(email, user.FullName)
-	}
+	s.jobs.Enqueue(JobSendWelcomeEmail, emailJob{Email: email, Name: user.FullName, UserID: user.ID, Type: NotificationTypeSystem})
 
 	log.Printf("✅ Hesap doğrulandı: %s\n", email)
 	return nil
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/auth"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestAuthService(t *testing.T) (*AuthService, *mocks.MockUserRepository, *mocks.MockSessionRepository) {
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUserRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	cfg := &config.Config{
+		JWTSecret:        "test-secret",
+		JWTAccessExpiry:  15 * time.Minute,
+		JWTRefreshExpiry: 24 * time.Hour,
+	}
+	return NewAuthService(&repository.Repositories{User: users, Session: sessions}, cfg, nil, nil, nil, nil, nil, nil, nil, nil, nil), users, sessions
+}
+
+func testUser(t *testing.T, password string) *models.User {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	email := "ayse@example.com"
+	return &models.User{
+		ID:           uuid.New(),
+		Email:        &email,
+		PasswordHash: string(hash),
+		Role:         "customer",
+		IsActive:     true,
+	}
+}
+
+func TestAuthServiceLogin(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     *models.User
+		lookup   error
+		password string
+		wantErr  error
+	}{
+		{name: "unknown email", lookup: errors.New("record not found"), password: "secret1", wantErr: ErrInvalidCredentials},
+		{name: "wrong password", user: testUser(t, "secret1"), password: "wrong", wantErr: ErrInvalidCredentials},
+		{name: "inactive user", user: func() *models.User { u := testUser(t, "secret1"); u.IsActive = false; return u }(), password: "secret1", wantErr: ErrUserInactive},
+		{name: "success", user: testUser(t, "secret1"), password: "secret1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, users, sessions := newTestAuthService(t)
+			users.EXPECT().GetByEmail("ayse@example.com").Return(tt.user, tt.lookup)
+			if tt.wantErr == nil {
+				sessions.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			user, tokens, err := svc.Login(&LoginInput{Email: "ayse@example.com", Password: tt.password})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if user.ID != tt.user.ID || tokens.AccessToken == "" {
+				t.Errorf("unexpected login result: %+v %+v", user, tokens)
+			}
+		})
+	}
+}
+
+func TestAuthServiceRegisterExistingUser(t *testing.T) {
+	svc, users, _ := newTestAuthService(t)
+	users.EXPECT().ExistsByEmail("ayse@example.com").Return(true)
+
+	_, _, err := svc.Register(&RegisterInput{Email: "ayse@example.com", Password: "secret1", FullName: "Ayşe"})
+	if !errors.Is(err, ErrUserExists) {
+		t.Fatalf("expected ErrUserExists, got %v", err)
+	}
+}
+
+func TestAuthServiceRefreshToken(t *testing.T) {
+	svc, users, sessions := newTestAuthService(t)
+	user := testUser(t, "secret1")
+
+	var session *models.Session
+	sessions.EXPECT().Create(gomock.Any()).DoAndReturn(func(s *models.Session) error {
+		session = s
+		return nil
+	})
+	pair, err := svc.startSession(user, "ios", ClientInfo{IP: "10.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.UserID != user.ID || session.RefreshTokenHash != hashToken(pair.RefreshToken) {
+		t.Fatalf("unexpected session: %+v", session)
+	}
+
+	users.EXPECT().GetByID(user.ID).Return(user, nil)
+	sessions.EXPECT().GetByID(session.ID).Return(session, nil)
+	sessions.EXPECT().Rotate(session.ID, session.RefreshTokenHash, gomock.Any(), "10.0.0.2", gomock.Any()).Return(true, nil)
+	if _, err := svc.RefreshToken(pair.RefreshToken, "ios", ClientInfo{IP: "10.0.0.2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A refresh token that no longer matches the stored hash was already exchanged
+	reused := *session
+	reused.RefreshTokenHash = "rotated"
+	users.EXPECT().GetByID(user.ID).Return(user, nil)
+	sessions.EXPECT().GetByID(session.ID).Return(&reused, nil)
+	if _, err := svc.RefreshToken(pair.RefreshToken, "ios", ClientInfo{}); !errors.Is(err, ErrSessionRevoked) {
+		t.Fatalf("expected ErrSessionRevoked, got %v", err)
+	}
+
+	revoked := *session
+	now := time.Now()
+	revoked.RevokedAt = &now
+	users.EXPECT().GetByID(user.ID).Return(user, nil)
+	sessions.EXPECT().GetByID(session.ID).Return(&revoked, nil)
+	if _, err := svc.RefreshToken(pair.RefreshToken, "ios", ClientInfo{}); !errors.Is(err, ErrSessionRevoked) {
+		t.Fatalf("expected ErrSessionRevoked, got %v", err)
+	}
+
+	user.IsActive = false
+	users.EXPECT().GetByID(user.ID).Return(user, nil)
+	if _, err := svc.RefreshToken(pair.RefreshToken, "ios", ClientInfo{}); !errors.Is(err, ErrUserInactive) {
+		t.Fatalf("expected ErrUserInactive, got %v", err)
+	}
+}
+
+func TestAuthServiceRefreshLegacyToken(t *testing.T) {
+	svc, users, sessions := newTestAuthService(t)
+	user := testUser(t, "secret1")
+
+	pair, err := auth.GenerateTokenPair(auth.Claims{UserID: user.ID.String(), Email: *user.Email, Role: user.Role, Platform: "ios"}, svc.cfg.JWTSecret, svc.cfg.JWTAccessExpiry, svc.cfg.JWTRefreshExpiry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users.EXPECT().GetByID(user.ID).Return(user, nil)
+	sessions.EXPECT().Create(gomock.Any()).Return(nil)
+	tokens, err := svc.RefreshToken(pair.RefreshToken, "ios", ClientInfo{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claims, err := auth.ValidateToken(tokens.AccessToken, svc.cfg.JWTSecret)
+	if err != nil || claims.SessionID == "" {
+		t.Fatalf("expected a session-bound token, got %+v %v", claims, err)
+	}
+}
+
+func TestAuthServiceRevokeSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	devices := mocks.NewMockDeviceTokenRepository(ctrl)
+	svc := NewAuthService(&repository.Repositories{Session: sessions, DeviceToken: devices}, &config.Config{}, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	owner := uuid.New()
+	session := &models.Session{ID: uuid.New(), UserID: owner}
+
+	sessions.EXPECT().GetByID(session.ID).Return(session, nil)
+	if err := svc.RevokeSession(uuid.New(), session.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound for another user's session, got %v", err)
+	}
+
+	sessions.EXPECT().GetByID(session.ID).Return(session, nil)
+	sessions.EXPECT().Revoke(session.ID).Return(nil)
+	devices.EXPECT().DeactivateForSession(session.ID).Return(nil)
+	if err := svc.RevokeSession(owner, session.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	cases := map[string]string{
+		"0532 123 45 67":   "+905321234567",
+		"532-123-4567":     "+905321234567",
+		"+44 20 7946 0958": "+442079460958",
+	}
+	for in, want := range cases {
+		if got := normalizePhone(in); got != want {
+			t.Errorf("normalizePhone(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAuthServiceIsTokenStale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUserRepository(ctrl)
+	repos := &repository.Repositories{User: users}
+	svc := NewAuthService(repos, &config.Config{}, nil, nil, nil, nil, NewTokenVersionCache(repos, nil), nil, nil, nil, nil)
+
+	user := testUser(t, "secret1")
+	user.TokenVersion = 2
+	users.EXPECT().GetByID(user.ID).Return(user, nil).Times(2)
+
+	if !svc.IsTokenStale(user.ID.String(), 1) {
+		t.Error("expected a token from before the role change to be stale")
+	}
+	if svc.IsTokenStale(user.ID.String(), 2) {
+		t.Error("expected a token with the current version to be accepted")
+	}
+	if !svc.IsTokenStale("not-a-uuid", 0) {
+		t.Error("expected a malformed user id to be rejected")
+	}
+}
+
+func TestAuthServiceRefreshRejectsBadTokens(t *testing.T) {
+	svc, _, _ := newTestAuthService(t)
+	user := testUser(t, "secret1")
+
+	expired, err := auth.GenerateTokenPair(auth.Claims{UserID: user.ID.String()}, svc.cfg.JWTSecret, -time.Minute, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := auth.GenerateTokenPair(auth.Claims{UserID: user.ID.String()}, "another-secret", time.Hour, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An access token can't be exchanged for a new pair
+	current, err := svc.generateTokens(user, "ios", uuid.NewString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, token := range []string{expired.RefreshToken, forged.RefreshToken, current.AccessToken, "not-a-token"} {
+		if _, err := svc.RefreshToken(token, "ios", ClientInfo{}); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected ErrInvalidToken, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+var (
+	ErrAssignmentNotFound = notFoundError("assignment_not_found", "assignment not found")
+	ErrAssignmentClosed   = conflictError("assignment_closed", "assignment is no longer searching")
+	ErrOfferExpired       = conflictError("offer_expired", "offer has expired")
+	ErrLocationRequired   = validationError("location_required", "latitude and longitude or address_id is required")
+)
+
+const (
+	// AutoAssignOfferWindow is how long a candidate has to accept before the next one is tried
+	AutoAssignOfferWindow = 60 * time.Second
+	// AutoAssignInterval is how often expired offers are moved on to the next candidate
+	AutoAssignInterval = 15 * time.Second
+	// Candidates tried before an assignment gives up
+	autoAssignMaxAttempts = 5
+	// How far from the customer candidates are searched
+	autoAssignRadiusKm = 25.0
+	// Expired offers moved on per run
+	autoAssignBatchSize = 100
+)
+
+// AutoAssignService finds the nearest available yandaş for urgent orders by
+// offering the job to one candidate at a time
+type AutoAssignService struct {
+	repos         *repository.Repositories
+	notifications *NotificationService
+	webhooks      *WebhookService
+	monitoring    *MonitoringService
+	chat          *ChatService
+	settings      *SettingsService
+	realtime      Broadcaster
+}
+
+func NewAutoAssignService(repos *repository.Repositories, notifications *NotificationService, webhooks *WebhookService, monitoring *MonitoringService, chat *ChatService, settings *SettingsService) *AutoAssignService {
+	return &AutoAssignService{repos: repos, notifications: notifications, webhooks: webhooks, monitoring: monitoring, chat: chat, settings: settings}
+}
+
+// SetBroadcaster lets offers reach connected yandaşlar in realtime.
+// Only the API process has connections, so the worker leaves it unset.
+func (s *AutoAssignService) SetBroadcaster(b Broadcaster) {
+	s.realtime = b
+}
+
+// AutoAssignInput represents an urgent order without a chosen yandaş
+type AutoAssignInput struct {
+	CategoryID      uuid.UUID  `json:"category_id" binding:"required"`
+	AddressID       *uuid.UUID `json:"address_id"`
+	LocationAddress string     `json:"location_address"`
+	Latitude        float64    `json:"latitude"`
+	Longitude       float64    `json:"longitude"`
+	CustomerNotes   string     `json:"customer_notes"`
+}
+
+// Start creates an assignment and offers it to the nearest candidate
+func (s *AutoAssignService) Start(customerID uuid.UUID, input *AutoAssignInput) (*models.AutoAssignment, error) {
+	if !s.settings.Bool(SettingFeatureAutoAssign) {
+		return nil, ErrFeatureDisabled
+	}
+	category, err := s.repos.Category.GetByID(input.CategoryID)
+	if err != nil || !category.IsActive {
+		return nil, ErrCategoryNotFound
+	}
+
+	assignment := &models.AutoAssignment{
+		CustomerID: customerID,
+		CategoryID: category.ID,
+		Latitude:   input.Latitude,
+		Longitude:  input.Longitude,
+		Status:     "searching",
+	}
+	if input.LocationAddress != "" {
+		assignment.LocationAddress = &input.LocationAddress
+	}
+	if input.CustomerNotes != "" {
+		assignment.CustomerNotes = &input.CustomerNotes
+	}
+
+	// A saved address overrides any free-form location
+	if input.AddressID != nil {
+		address, err := s.repos.Address.GetByID(*input.AddressID)
+		if err != nil || address.UserID != customerID {
+			return nil, ErrAddressNotFound
+		}
+		if address.Latitude == nil || address.Longitude == nil {
+			return nil, ErrLocationRequired
+		}
+		assignment.LocationAddress = &address.AddressText
+		assignment.Latitude = *address.Latitude
+		assignment.Longitude = *address.Longitude
+	}
+	if assignment.Latitude == 0 && assignment.Longitude == 0 {
+		return nil, ErrLocationRequired
+	}
+
+	if err := s.repos.Assignment.Create(assignment); err != nil {
+		return nil, err
+	}
+	assignment.Category = category
+
+	s.offerNext(assignment)
+	return assignment, nil
+}
+
+// Get returns one of the customer's assignments
+func (s *AutoAssignService) Get(id, customerID uuid.UUID) (*models.AutoAssignment, error) {
+	assignment, err := s.repos.Assignment.GetByID(id)
+	if err != nil || assignment.CustomerID != customerID {
+		return nil, ErrAssignmentNotFound
+	}
+	return assignment, nil
+}
+
+// Cancel stops the search; the candidate holding the offer can no longer accept it
+func (s *AutoAssignService) Cancel(id, customerID uuid.UUID) error {
+	assignment, err := s.repos.OnPrimary().Assignment.GetByID(id)
+	if err != nil || assignment.CustomerID != customerID {
+		return ErrAssignmentNotFound
+	}
+
+	candidateID := assignment.CandidateID
+	assignment.Status = "cancelled"
+	assignment.OfferExpiresAt = nil
+	ok, err := s.repos.Assignment.Transition(assignment, assignment.Attempts)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAssignmentClosed
+	}
+
+	if candidateID != nil {
+		if profile, err := s.repos.YandasProfile.GetByID(*candidateID); err == nil {
+			s.push(profile.UserID, "assignment_withdrawn", map[string]interface{}{"assignment_id": assignment.ID})
+		}
+	}
+	return nil
+}
+
+// Accept takes the offer for the yandaş holding it and creates the accepted
+// order. The job is for right away, so the yandaş must be free now and take
+// orders without notice, as for any order they accept.
+func (s *AutoAssignService) Accept(userID, id uuid.UUID) (*models.Order, error) {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+	assignment, err := s.repos.OnPrimary().Assignment.GetByID(id)
+	if err != nil || assignment.CandidateID == nil || *assignment.CandidateID != profile.ID {
+		return nil, ErrAssignmentNotFound
+	}
+	if assignment.Status != "searching" {
+		return nil, ErrAssignmentClosed
+	}
+	if assignment.OfferExpiresAt == nil || !assignment.OfferExpiresAt.After(time.Now()) {
+		return nil, ErrOfferExpired
+	}
+	now := time.Now()
+	if onVacation(profile, nil) {
+		return nil, ErrOnVacation
+	}
+	if err := checkLeadTime(profile, nil, now); err != nil {
+		return nil, err
+	}
+
+	service, err := s.repos.Service.GetByID(*assignment.ServiceID)
+	if err != nil {
+		return nil, ErrServiceNotFound
+	}
+	order, err := orderFromAssignment(assignment, profile, service)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMinimumOrder(profile, order.AgreedPrice, order.Currency); err != nil {
+		return nil, err
+	}
+	// The job starts now, so it must not overlap a booking under way or due soon
+	booking := *order
+	booking.ScheduledAt = &now
+	booking.Service = service
+	conflict, err := hasScheduleConflict(s.repos, profile.ID, &booking)
+	if err != nil {
+		return nil, err
+	}
+	if conflict {
+		return nil, ErrScheduleConflict
+	}
+
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.Order.Create(order); err != nil {
+			return err
+		}
+		assignment.Status = "assigned"
+		assignment.OfferExpiresAt = nil
+		assignment.OrderID = &order.ID
+		ok, err := tx.Assignment.Transition(assignment, assignment.Attempts)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrAssignmentClosed
+		}
+		if err := recordOrderEvent(tx, order.ID, OrderEventCreated, "pending", &assignment.CustomerID, nil); err != nil {
+			return err
+		}
+		if err := recordOrderEvent(tx, order.ID, OrderEventAccepted, order.Status, &userID, nil); err != nil {
+			return err
+		}
+		return s.chat.OpenOrderConversation(tx, order, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.webhooks.Dispatch(WebhookOrderCreated, orderWebhookData(order))
+	s.monitoring.Record(MetricOrdersCreated)
+
+	data := map[string]interface{}{"assignment_id": assignment.ID, "order_id": order.ID}
+	if order.ConversationID != nil {
+		data["conversation_id"] = *order.ConversationID
+	}
+	body := fmt.Sprintf("%s işinizi kabul etti.", profile.User.FullName)
+	if err := s.notifications.Send(assignment.CustomerID, "Yandaş bulundu", body, "order", data); err != nil {
+		log.Printf("[ASSIGN] failed to notify %s about assignment %s: %v", assignment.CustomerID, assignment.ID, err)
+	}
+	s.push(assignment.CustomerID, "assignment_accepted", data)
+	s.chat.PostOrderEvent(order, userID, OrderEventAccepted, userID)
+
+	return order, nil
+}
+
+// orderFromAssignment prices the candidate's service for the customer's location
+func orderFromAssignment(assignment *models.AutoAssignment, profile *models.YandasProfile, service *models.YandasService) (*models.Order, error) {
+	order := &models.Order{
+		CustomerID:      assignment.CustomerID,
+		YandasID:        profile.ID,
+		ServiceID:       service.ID,
+		Currency:        currencyCode(service.Currency),
+		LocationAddress: assignment.LocationAddress,
+		Latitude:        &assignment.Latitude,
+		Longitude:       &assignment.Longitude,
+		CustomerNotes:   assignment.CustomerNotes,
+		Status:          "accepted",
+	}
+
+	pricing := PricingInput{BasePrice: service.BasePrice}
+	if service.DurationMinutes != nil {
+		minutes := float64(*service.DurationMinutes)
+		pricing.DurationMinutes = &minutes
+	}
+	if profile.Latitude != nil && profile.Longitude != nil {
+		km := haversineKm(*profile.Latitude, *profile.Longitude, assignment.Latitude, assignment.Longitude)
+		pricing.DistanceKm = &km
+	}
+	lineItems, total, err := priceOrder(service, pricing)
+	if err != nil {
+		return nil, err
+	}
+	order.LineItems = lineItems
+	order.AgreedPrice = total
+	return order, nil
+}
+
+// Decline passes the offer on to the next candidate
+func (s *AutoAssignService) Decline(userID, id uuid.UUID) error {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return ErrYandasProfileNotFound
+	}
+	assignment, err := s.repos.OnPrimary().Assignment.GetByID(id)
+	if err != nil || assignment.CandidateID == nil || *assignment.CandidateID != profile.ID {
+		return ErrAssignmentNotFound
+	}
+	if assignment.Status != "searching" {
+		return ErrAssignmentClosed
+	}
+
+	s.offerNext(assignment)
+	return nil
+}
+
+// ExpireOffers moves offers nobody answered in time on to the next candidate;
+// the scheduler calls it every AutoAssignInterval
+func (s *AutoAssignService) ExpireOffers() {
+	assignments, err := s.repos.Assignment.ListExpiredOffers(time.Now(), autoAssignBatchSize)
+	if err != nil {
+		log.Printf("[ASSIGN] failed to load expired offers: %v", err)
+		return
+	}
+	for i := range assignments {
+		s.offerNext(&assignments[i])
+	}
+}
+
+// offerNext offers the assignment to the nearest candidate not tried yet, or
+// gives up once none is left or autoAssignMaxAttempts were tried. Losing a
+// race to an accept or another offer leaves the assignment as the winner set it.
+func (s *AutoAssignService) offerNext(assignment *models.AutoAssignment) {
+	expectAttempts := assignment.Attempts
+
+	var candidate *repository.AssignmentCandidate
+	if assignment.Attempts < autoAssignMaxAttempts {
+		candidates, err := s.repos.Assignment.NearestCandidates(assignment.CategoryID, assignment.CustomerID,
+			assignment.Latitude, assignment.Longitude, autoAssignRadiusKm, assignment.OfferedTo, 1)
+		if err != nil {
+			log.Printf("[ASSIGN] failed to find candidates for %s: %v", assignment.ID, err)
+			return
+		}
+		if len(candidates) > 0 {
+			candidate = &candidates[0]
+		}
+	}
+
+	if candidate == nil {
+		assignment.Status = "failed"
+		assignment.CandidateID = nil
+		assignment.ServiceID = nil
+		assignment.OfferExpiresAt = nil
+		if ok, err := s.repos.Assignment.Transition(assignment, expectAttempts); err != nil || !ok {
+			if err != nil {
+				log.Printf("[ASSIGN] failed to close assignment %s: %v", assignment.ID, err)
+			}
+			return
+		}
+		data := map[string]interface{}{"assignment_id": assignment.ID}
+		if err := s.notifications.Send(assignment.CustomerID, "Müsait yandaş bulunamadı", "Yakınınızda işi kabul eden bir yandaş bulunamadı. Lütfen daha sonra tekrar deneyin.", "order", data); err != nil {
+			log.Printf("[ASSIGN] failed to notify %s about assignment %s: %v", assignment.CustomerID, assignment.ID, err)
+		}
+		s.push(assignment.CustomerID, "assignment_failed", data)
+		return
+	}
+
+	expiresAt := time.Now().Add(AutoAssignOfferWindow)
+	assignment.CandidateID = &candidate.YandasID
+	assignment.ServiceID = &candidate.ServiceID
+	assignment.OfferExpiresAt = &expiresAt
+	assignment.Attempts++
+	assignment.OfferedTo = append(assignment.OfferedTo, candidate.YandasID.String())
+	ok, err := s.repos.Assignment.Transition(assignment, expectAttempts)
+	if err != nil {
+		log.Printf("[ASSIGN] failed to offer assignment %s: %v", assignment.ID, err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	data := map[string]interface{}{
+		"assignment_id": assignment.ID,
+		"service_id":    candidate.ServiceID,
+		"distance_km":   candidate.DistanceKm,
+		"expires_at":    expiresAt,
+	}
+	body := fmt.Sprintf("%.1f km uzaklıkta acil bir iş var. %d saniye içinde kabul edin.", candidate.DistanceKm, int(AutoAssignOfferWindow.Seconds()))
+	if err := s.notifications.Send(candidate.UserID, "Acil iş teklifi", body, "order", data); err != nil {
+		log.Printf("[ASSIGN] failed to notify %s about assignment %s: %v", candidate.UserID, assignment.ID, err)
+	}
+	s.push(candidate.UserID, "assignment_offer", data)
+}
+
+func (s *AutoAssignService) push(userID uuid.UUID, event string, payload interface{}) {
+	if s.realtime != nil {
+		s.realtime.BroadcastToUser(userID.String(), event, payload)
+	}
+}
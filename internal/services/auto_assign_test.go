@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/currency"
+	"gorm.io/gorm"
+)
+
+func newTestAutoAssignService(t *testing.T) (*AutoAssignService, *mocks.MockAssignmentRepository, *mocks.MockYandasProfileRepository, *recordingBroadcaster) {
+	ctrl := gomock.NewController(t)
+	assignments := mocks.NewMockAssignmentRepository(ctrl)
+	profiles := mocks.NewMockYandasProfileRepository(ctrl)
+	notifs := mocks.NewMockNotificationRepository(ctrl)
+	prefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{Assignment: assignments, YandasProfile: profiles, Notification: notifs, NotificationPreference: prefs, UnitOfWork: uow}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+
+	notifs.EXPECT().Create(gomock.Any()).Return(nil).AnyTimes()
+	prefs.EXPECT().GetByUserAndType(gomock.Any(), gomock.Any()).Return(nil, gorm.ErrRecordNotFound).AnyTimes()
+
+	realtime := &recordingBroadcaster{events: map[string][]string{}}
+	svc := NewAutoAssignService(repos, NewNotificationService(repos, nil, nil, nil, nil, nil), nil, nil, nil, nil)
+	svc.SetBroadcaster(realtime)
+	return svc, assignments, profiles, realtime
+}
+
+func TestExpiredOfferMovesToNextCandidate(t *testing.T) {
+	svc, assignments, _, realtime := newTestAutoAssignService(t)
+
+	first := uuid.New()
+	expired := time.Now().Add(-time.Second)
+	assignment := models.AutoAssignment{
+		ID: uuid.New(), CustomerID: uuid.New(), CategoryID: uuid.New(), Latitude: 41.0, Longitude: 29.0,
+		Status: "searching", CandidateID: &first, OfferExpiresAt: &expired, Attempts: 1, OfferedTo: pq.StringArray{first.String()},
+	}
+	next := repository.AssignmentCandidate{YandasID: uuid.New(), UserID: uuid.New(), ServiceID: uuid.New(), DistanceKm: 2.4}
+
+	assignments.EXPECT().ListExpiredOffers(gomock.Any(), autoAssignBatchSize).Return([]models.AutoAssignment{assignment}, nil)
+	assignments.EXPECT().NearestCandidates(assignment.CategoryID, assignment.CustomerID, 41.0, 29.0, autoAssignRadiusKm, []string{first.String()}, 1).
+		Return([]repository.AssignmentCandidate{next}, nil)
+	assignments.EXPECT().Transition(gomock.Any(), 1).DoAndReturn(func(a *models.AutoAssignment, _ int) (bool, error) {
+		if *a.CandidateID != next.YandasID || a.Attempts != 2 || len(a.OfferedTo) != 2 || !a.OfferExpiresAt.After(time.Now()) {
+			t.Errorf("unexpected offer %+v", a)
+		}
+		return true, nil
+	})
+
+	svc.ExpireOffers()
+
+	if got := realtime.events[next.UserID.String()]; len(got) != 1 || got[0] != "assignment_offer" {
+		t.Errorf("expected the next candidate offered the job, got %v", got)
+	}
+}
+
+func TestAssignmentFailsAfterMaxAttempts(t *testing.T) {
+	svc, assignments, _, realtime := newTestAutoAssignService(t)
+
+	assignment := &models.AutoAssignment{ID: uuid.New(), CustomerID: uuid.New(), Status: "searching", Attempts: autoAssignMaxAttempts}
+	assignments.EXPECT().Transition(assignment, autoAssignMaxAttempts).Return(true, nil)
+
+	svc.offerNext(assignment)
+
+	if assignment.Status != "failed" || assignment.CandidateID != nil {
+		t.Errorf("expected the assignment failed, got %+v", assignment)
+	}
+	if got := realtime.events[assignment.CustomerID.String()]; len(got) != 1 || got[0] != "assignment_failed" {
+		t.Errorf("expected the customer told, got %v", got)
+	}
+}
+
+func TestAcceptRejectsExpiredOffer(t *testing.T) {
+	svc, assignments, profiles, _ := newTestAutoAssignService(t)
+
+	userID := uuid.New()
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: userID}
+	expired := time.Now().Add(-time.Second)
+	assignment := &models.AutoAssignment{ID: uuid.New(), Status: "searching", CandidateID: &profile.ID, OfferExpiresAt: &expired}
+
+	profiles.EXPECT().GetByUserID(userID).Return(profile, nil)
+	assignments.EXPECT().GetByID(assignment.ID).Return(assignment, nil)
+
+	if _, err := svc.Accept(userID, assignment.ID); !errors.Is(err, ErrOfferExpired) {
+		t.Fatalf("expected ErrOfferExpired, got %v", err)
+	}
+}
+
+func TestAcceptChecksTheYandasCanTakeTheJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	assignments := mocks.NewMockAssignmentRepository(ctrl)
+	profiles := mocks.NewMockYandasProfileRepository(ctrl)
+	services := mocks.NewMockServiceRepository(ctrl)
+	orders := mocks.NewMockOrderRepository(ctrl)
+	svc := NewAutoAssignService(&repository.Repositories{Assignment: assignments, YandasProfile: profiles, Service: services, Order: orders}, nil, nil, nil, nil, nil)
+
+	userID := uuid.New()
+	service := &models.YandasService{ID: uuid.New(), BasePrice: 500}
+	minimum := currency.Money(1000)
+	now := time.Now()
+	until := now.Add(24 * time.Hour)
+	booked := now.Add(30 * time.Minute)
+
+	tests := []struct {
+		name    string
+		profile models.YandasProfile
+		setup   func(profileID uuid.UUID)
+		wantErr error
+	}{
+		{
+			name:    "on vacation",
+			profile: models.YandasProfile{VacationFrom: &now, VacationUntil: &until, VacationStartedAt: &now},
+			wantErr: ErrOnVacation,
+		},
+		{
+			name:    "needs notice",
+			profile: models.YandasProfile{LeadTimeMinutes: 60},
+			wantErr: ErrLeadTimeNotMet,
+		},
+		{
+			name:    "below minimum order value",
+			profile: models.YandasProfile{MinOrderValue: &minimum},
+			setup: func(uuid.UUID) {
+				services.EXPECT().GetByID(service.ID).Return(service, nil)
+			},
+			wantErr: ErrBelowMinimumOrder,
+		},
+		{
+			name: "booked now",
+			setup: func(profileID uuid.UUID) {
+				services.EXPECT().GetByID(service.ID).Return(service, nil)
+				orders.EXPECT().ListScheduledByYandas(profileID, gomock.Any(), gomock.Any(), []string{"accepted", "in_progress"}).
+					Return([]models.Order{{ID: uuid.New(), ScheduledAt: &booked, Service: service}}, nil)
+			},
+			wantErr: ErrScheduleConflict,
+		},
+	}
+	for _, tt := range tests {
+		profile := tt.profile
+		profile.ID, profile.UserID = uuid.New(), userID
+		expires := time.Now().Add(time.Minute)
+		assignment := &models.AutoAssignment{ID: uuid.New(), Status: "searching", CandidateID: &profile.ID, ServiceID: &service.ID, OfferExpiresAt: &expires}
+		profiles.EXPECT().GetByUserID(userID).Return(&profile, nil)
+		assignments.EXPECT().GetByID(assignment.ID).Return(assignment, nil)
+		if tt.setup != nil {
+			tt.setup(profile.ID)
+		}
+
+		if _, err := svc.Accept(userID, assignment.ID); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+var (
+	ErrBelowMinimumOrder = validationError("below_minimum_order", "order total is below the yandaş's minimum order value")
+	ErrLeadTimeNotMet    = validationError("lead_time_not_met", "the yandaş needs more notice; schedule the order later")
+)
+
+// BookingPreferencesInput replaces a yandaş's booking preferences; omitted fields are cleared
+type BookingPreferencesInput struct {
+	MinOrderValue    *currency.Money `json:"min_order_value" binding:"omitempty,min=0,max=10000000"` // at most 100.000 in minor units
+	MinOrderCurrency string          `json:"min_order_currency" binding:"omitempty,currency"`        // of the minimum; defaults to TRY
+	InstantBooking   bool            `json:"instant_booking"`
+	LeadTimeMinutes  int             `json:"lead_time_minutes" binding:"min=0,max=10080"` // at most a week
+}
+
+// UpdateBookingPreferences sets the minimum order value, instant booking and lead time
+func (s *YandasService) UpdateBookingPreferences(userID uuid.UUID, input *BookingPreferencesInput) (*models.YandasProfile, error) {
+	profile, err := s.repos.OnPrimary().YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+
+	profile.MinOrderValue, profile.MinOrderCurrency = input.MinOrderValue, nil
+	if profile.MinOrderValue != nil && *profile.MinOrderValue == 0 {
+		profile.MinOrderValue = nil
+	}
+	if profile.MinOrderValue != nil {
+		code := currencyCode(input.MinOrderCurrency)
+		profile.MinOrderCurrency = &code
+	}
+	profile.InstantBooking = input.InstantBooking
+	profile.LeadTimeMinutes = input.LeadTimeMinutes
+	if err := s.repos.YandasProfile.Update(profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// checkLeadTime refuses an order with less notice than the yandaş asks for.
+// An order with no date is for right away, so it gives no notice at all.
+func checkLeadTime(profile *models.YandasProfile, scheduledAt *time.Time, now time.Time) error {
+	if profile.LeadTimeMinutes <= 0 {
+		return nil
+	}
+	if scheduledAt == nil || scheduledAt.Before(now.Add(time.Duration(profile.LeadTimeMinutes)*time.Minute)) {
+		return ErrLeadTimeNotMet
+	}
+	return nil
+}
+
+// checkMinimumOrder refuses an order totalling less than the yandaş's minimum.
+// Amounts in different currencies aren't comparable, so the minimum only
+// applies to orders in its own currency.
+func checkMinimumOrder(profile *models.YandasProfile, total currency.Money, code string) error {
+	if profile.MinOrderValue == nil {
+		return nil
+	}
+	minCode := ""
+	if profile.MinOrderCurrency != nil {
+		minCode = *profile.MinOrderCurrency
+	}
+	if currencyCode(minCode) == currencyCode(code) && total < *profile.MinOrderValue {
+		return ErrBelowMinimumOrder
+	}
+	return nil
+}
+
+// instantBooking reports whether an order is accepted on the yandaş's
+// behalf: they turned instant booking on, the customer did not offer less than
+// the service's base price and, for a scheduled order, it does not overlap
+// their bookings. Otherwise it waits for their approval.
+func (s *OrderService) instantBooking(profile *models.YandasProfile, service *models.YandasService, order *models.Order, agreedPrice currency.Money) bool {
+	if !profile.InstantBooking {
+		return false
+	}
+	if agreedPrice > 0 && agreedPrice < service.BasePrice {
+		return false
+	}
+	if order.ScheduledAt == nil {
+		return true
+	}
+	booking := *order
+	booking.Service = service
+	conflict, err := hasScheduleConflict(s.repos, profile.ID, &booking)
+	if err != nil {
+		log.Printf("[ORDERS] failed to check the schedule of yandaş %s for instant booking: %v", profile.ID, err)
+		return false
+	}
+	return !conflict
+}
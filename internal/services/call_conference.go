@@ -0,0 +1,71 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+)
+
+// CallRoleMediator is a support agent or admin who joined a call to mediate a dispute
+const CallRoleMediator = "mediator"
+
+var (
+	ErrCallNotActive      = conflictError("call_not_active", "call is not active")
+	ErrAlreadyCallParty   = conflictError("already_call_party", "caller and callee are already in the call")
+	ErrNotCallParticipant = notFoundError("not_call_participant", "not a participant of this call")
+)
+
+// ActiveCalls returns answered calls that are still going on, for staff to join
+func (s *CallService) ActiveCalls(page, limit int) ([]models.CallLog, int64, error) {
+	return s.repos.CallLog.ListActive(page, limit)
+}
+
+// JoinCall adds a staff member to an ongoing call as a mediator and returns
+// the call with the participant's Agora uid
+func (s *CallService) JoinCall(callID, userID uuid.UUID) (*models.CallLog, *models.CallParticipant, error) {
+	callLog, err := s.repos.OnPrimary().CallLog.GetByID(callID)
+	if err != nil {
+		return nil, nil, ErrCallNotFound
+	}
+	if callLog.Status != "answered" || callLog.ChannelID == nil {
+		return nil, nil, ErrCallNotActive
+	}
+	if userID == callLog.CallerID || userID == callLog.CalleeID {
+		return nil, nil, ErrAlreadyCallParty
+	}
+
+	participant, err := s.repos.CallLog.AddParticipant(callID, userID, CallRoleMediator)
+	if err != nil {
+		return nil, nil, err
+	}
+	return callLog, participant, nil
+}
+
+// LeaveCall takes a mediator out of a call and returns the call so the others can be told
+func (s *CallService) LeaveCall(callID, userID uuid.UUID) (*models.CallLog, error) {
+	callLog, err := s.repos.OnPrimary().CallLog.GetByID(callID)
+	if err != nil {
+		return nil, ErrCallNotFound
+	}
+	left, err := s.repos.CallLog.LeaveParticipant(callID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !left {
+		return nil, ErrNotCallParticipant
+	}
+	return callLog, nil
+}
+
+// Participant returns a user's participation in a call they are currently in
+func (s *CallService) Participant(callID, userID uuid.UUID) (*models.CallParticipant, error) {
+	participant, err := s.repos.OnPrimary().CallLog.GetParticipant(callID, userID)
+	if err != nil {
+		return nil, ErrNotCallParticipant
+	}
+	return participant, nil
+}
+
+// Participants returns the extra participants still in a call
+func (s *CallService) Participants(callID uuid.UUID) ([]models.CallParticipant, error) {
+	return s.repos.CallLog.ListParticipants(callID)
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestCallJoinAsMediator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	calls := mocks.NewMockCallLogRepository(ctrl)
+	svc := NewCallService(&repository.Repositories{CallLog: calls}, nil, nil)
+
+	channel := "call_abc"
+	callLog := &models.CallLog{ID: uuid.New(), CallerID: uuid.New(), CalleeID: uuid.New(), Status: "ringing", ChannelID: &channel}
+	calls.EXPECT().GetByID(callLog.ID).Return(callLog, nil).AnyTimes()
+	agent := uuid.New()
+
+	if _, _, err := svc.JoinCall(callLog.ID, agent); err != ErrCallNotActive {
+		t.Fatalf("expected ErrCallNotActive for a ringing call, got %v", err)
+	}
+
+	callLog.Status = "answered"
+	if _, _, err := svc.JoinCall(callLog.ID, callLog.CalleeID); err != ErrAlreadyCallParty {
+		t.Fatalf("expected ErrAlreadyCallParty, got %v", err)
+	}
+
+	calls.EXPECT().AddParticipant(callLog.ID, agent, CallRoleMediator).
+		Return(&models.CallParticipant{CallID: callLog.ID, UserID: agent, UID: 3, Role: CallRoleMediator}, nil)
+	_, participant, err := svc.JoinCall(callLog.ID, agent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if participant.UID != 3 {
+		t.Errorf("expected the mediator to get uid 3, got %d", participant.UID)
+	}
+
+	calls.EXPECT().LeaveParticipant(callLog.ID, agent).Return(false, nil)
+	if _, err := svc.LeaveCall(callLog.ID, agent); err != ErrNotCallParticipant {
+		t.Fatalf("expected ErrNotCallParticipant, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+)
+
+// ErrUnknownDeliveryChannel is returned when a call delivery report names neither push nor websocket
+var ErrUnknownDeliveryChannel = validationError("unknown_delivery_channel", "via must be 'push' or 'websocket'")
+
+// PushIncomingCall rings the callee's devices over push alongside the WebSocket
+// event, so apps in the background still get the call, and records the outcome on the call
+func (s *CallService) PushIncomingCall(callLog *models.CallLog, caller *models.User, ttl time.Duration) {
+	data := map[string]string{
+		"type":                "incoming_call",
+		"call_id":             callLog.ID.String(),
+		"caller_id":           callLog.CallerID.String(),
+		"caller_name":         caller.FullName,
+		"call_type":           callLog.CallType,
+		"recording_requested": strconv.FormatBool(callLog.RecordingRequested),
+	}
+	if callLog.ChannelID != nil {
+		data["channel_name"] = *callLog.ChannelID
+	}
+	if caller.AvatarURL != nil {
+		data["caller_avatar"] = *caller.AvatarURL
+	}
+
+	status, err := s.notification.SendCallPush(callLog.CalleeID, data, ttl)
+	var pushErr *string
+	if err != nil {
+		msg := err.Error()
+		pushErr = &msg
+		log.Printf("[CALL] incoming call push for call=%s failed: %v", callLog.ID, err)
+	}
+	if err := s.repos.CallLog.SetPushResult(callLog.ID, status, pushErr); err != nil {
+		log.Printf("[CALL] failed to record push result for call=%s: %v", callLog.ID, err)
+	}
+}
+
+// MarkDelivered records that one of the callee's devices started ringing, and over which channel
+func (s *CallService) MarkDelivered(callID, calleeID uuid.UUID, via string) error {
+	if via != "push" && via != "websocket" {
+		return ErrUnknownDeliveryChannel
+	}
+	_, err := s.repos.CallLog.MarkDelivered(callID, calleeID, via)
+	return err
+}
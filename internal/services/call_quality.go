@@ -0,0 +1,88 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/repository"
+)
+
+var (
+	ErrCallNotFound          = notFoundError("call_not_found", "call not found")
+	ErrCallNotEnded          = conflictError("call_not_ended", "feedback can only be left on an ended call")
+	ErrCallFeedbackSubmitted = conflictError("call_feedback_submitted", "feedback already submitted for this call")
+	ErrUnknownCallIssue      = validationError("unknown_call_issue", "unknown call issue")
+)
+
+// callIssues are the problems a party can report about a call
+var callIssues = map[string]bool{
+	"echo":     true,
+	"drop":     true,
+	"no_audio": true,
+	"no_video": true,
+	"lag":      true,
+	"noise":    true,
+}
+
+// CallFeedbackInput is a party's rating of an ended call. Platform and region
+// come from the app so problems can be traced to an OS or an Agora area.
+type CallFeedbackInput struct {
+	Rating   int      `json:"rating" binding:"required,min=1,max=5"`
+	Issues   []string `json:"issues"`
+	Platform string   `json:"platform" binding:"omitempty,oneof=ios android web"`
+	Region   string   `json:"region" binding:"max=50"`
+}
+
+// SubmitFeedback stores the caller's or callee's quality feedback once the call has ended
+func (s *CallService) SubmitFeedback(callID, userID uuid.UUID, input *CallFeedbackInput) error {
+	callLog, err := s.repos.OnPrimary().CallLog.GetByID(callID)
+	if err != nil {
+		return ErrCallNotFound
+	}
+
+	party := ""
+	switch userID {
+	case callLog.CallerID:
+		party = "caller"
+	case callLog.CalleeID:
+		party = "callee"
+	default:
+		return ErrCallNotFound
+	}
+	if callLog.Status != "ended" {
+		return ErrCallNotEnded
+	}
+
+	issues := []string{}
+	seen := map[string]bool{}
+	for _, issue := range input.Issues {
+		issue = strings.ToLower(strings.TrimSpace(issue))
+		if !callIssues[issue] {
+			return ErrUnknownCallIssue
+		}
+		if !seen[issue] {
+			seen[issue] = true
+			issues = append(issues, issue)
+		}
+	}
+
+	ok, err := s.repos.CallLog.SetFeedback(callID, party, repository.CallFeedback{
+		Rating:   input.Rating,
+		Issues:   issues,
+		Platform: input.Platform,
+		Region:   strings.TrimSpace(input.Region),
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCallFeedbackSubmitted
+	}
+	return nil
+}
+
+// QualityReport aggregates call feedback for calls started in [from, to) by region and platform
+func (s *CallService) QualityReport(from, to time.Time) ([]repository.CallQualityRow, error) {
+	return s.repos.CallLog.QualityReport(from, to)
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestCallSubmitFeedback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	calls := mocks.NewMockCallLogRepository(ctrl)
+	svc := NewCallService(&repository.Repositories{CallLog: calls}, nil, nil)
+
+	callLog := &models.CallLog{ID: uuid.New(), CallerID: uuid.New(), CalleeID: uuid.New(), Status: "answered"}
+	calls.EXPECT().GetByID(callLog.ID).Return(callLog, nil).AnyTimes()
+
+	input := &CallFeedbackInput{Rating: 2, Issues: []string{"Echo", "drop", "echo"}, Platform: "android", Region: " istanbul "}
+
+	if err := svc.SubmitFeedback(callLog.ID, uuid.New(), input); err != ErrCallNotFound {
+		t.Fatalf("expected ErrCallNotFound for an outsider, got %v", err)
+	}
+	if err := svc.SubmitFeedback(callLog.ID, callLog.CallerID, input); err != ErrCallNotEnded {
+		t.Fatalf("expected ErrCallNotEnded, got %v", err)
+	}
+
+	callLog.Status = "ended"
+	if err := svc.SubmitFeedback(callLog.ID, callLog.CallerID, &CallFeedbackInput{Rating: 3, Issues: []string{"static"}}); err != ErrUnknownCallIssue {
+		t.Fatalf("expected ErrUnknownCallIssue, got %v", err)
+	}
+
+	calls.EXPECT().SetFeedback(callLog.ID, "callee", repository.CallFeedback{
+		Rating: 2, Issues: []string{"echo", "drop"}, Platform: "android", Region: "istanbul",
+	}).Return(true, nil)
+	if err := svc.SubmitFeedback(callLog.ID, callLog.CalleeID, input); err != nil {
+		t.Fatal(err)
+	}
+
+	calls.EXPECT().SetFeedback(callLog.ID, "callee", gomock.Any()).Return(false, nil)
+	if err := svc.SubmitFeedback(callLog.ID, callLog.CalleeID, input); err != ErrCallFeedbackSubmitted {
+		t.Fatalf("expected ErrCallFeedbackSubmitted, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+const (
+	// CallRingInterval is how often calls nobody picked up are marked missed
+	CallRingInterval = 5 * time.Second
+	// Unanswered calls marked missed per run
+	callRingBatchSize = 100
+)
+
+// CallService handles call history and post-call side effects
+type CallService struct {
+	repos        *repository.Repositories
+	chat         *ChatService
+	notification *NotificationService
+	realtime     Broadcaster
+}
+
+// NewCallService creates a new call service
+func NewCallService(repos *repository.Repositories, chat *ChatService, notification *NotificationService) *CallService {
+	return &CallService{repos: repos, chat: chat, notification: notification}
+}
+
+// SetBroadcaster lets missed calls stop the ringing on connected devices.
+// Only the API process has connections, so the worker leaves it unset.
+func (s *CallService) SetBroadcaster(b Broadcaster) {
+	s.realtime = b
+}
+
+// History returns the user's calls. filter can be "missed", "answered" or empty for all.
+func (s *CallService) History(userID uuid.UUID, page, limit int, filter string) ([]models.CallLog, int64, error) {
+	return s.repos.CallLog.ListByUser(userID, page, limit, filter)
+}
+
+// MarkMissed marks a still-ringing call as missed and notifies the callee.
+// It returns nil when the call was already picked up, rejected or cancelled.
+func (s *CallService) MarkMissed(callID uuid.UUID) (*models.CallLog, error) {
+	missed, err := s.repos.CallLog.MarkMissed(callID)
+	if err != nil || !missed {
+		return nil, err
+	}
+
+	callLog, err := s.repos.CallLog.GetByID(callID)
+	if err != nil {
+		return nil, err
+	}
+
+	callerName := "Bilinmeyen"
+	if callLog.Caller != nil {
+		callerName = callLog.Caller.FullName
+	}
+
+	s.notification.Send(callLog.CalleeID, "Cevapsız arama", fmt.Sprintf("%s sizi aradı", callerName), "call", map[string]interface{}{
+		"call_id":   callLog.ID.String(),
+		"caller_id": callLog.CallerID.String(),
+		"call_type": callLog.CallType,
+	})
+
+	return callLog, nil
+}
+
+// ExpireRinging marks the calls nobody picked up before their ring deadline
+// as missed and tells both sides to stop ringing; the scheduler runs it every
+// CallRingInterval
+func (s *CallService) ExpireRinging() {
+	ids, err := s.repos.CallLog.ListRingExpired(time.Now(), callRingBatchSize)
+	if err != nil {
+		log.Printf("[CALL] failed to load unanswered calls: %v", err)
+		return
+	}
+	for _, id := range ids {
+		callLog, err := s.MarkMissed(id)
+		if err != nil {
+			log.Printf("[CALL] Ring timeout error for call=%s: %v", id, err)
+			continue
+		}
+		if callLog == nil {
+			continue // answered, rejected or ended in the meantime
+		}
+
+		if s.realtime != nil {
+			payload := map[string]interface{}{"call_id": id.String()}
+			s.realtime.BroadcastToUser(callLog.CallerID.String(), "call_missed", payload)
+			s.realtime.BroadcastToUser(callLog.CalleeID.String(), "call_missed", payload)
+		}
+		if _, err := s.PostCallSummary(id); err != nil {
+			log.Printf("[CALL] Call summary error for call=%s: %v", id, err)
+		}
+	}
+}
+
+// PostCallSummary adds a system message describing a finished call to the
+// participants' conversation. It returns nil when the users have no conversation.
+func (s *CallService) PostCallSummary(callID uuid.UUID) (*models.Message, error) {
+	callLog, err := s.repos.CallLog.GetByID(callID)
+	if err != nil {
+		return nil, err
+	}
+
+	conv, err := s.repos.Conversation.FindBetween(callLog.CallerID, callLog.CalleeID)
+	if err != nil {
+		return nil, nil
+	}
+
+	return s.chat.PostSystemMessage(conv.ID, callLog.CallerID, callSummaryText(callLog))
+}
+
+func callSummaryText(callLog *models.CallLog) string {
+	kind := "sesli arama"
+	if callLog.CallType == "video" {
+		kind = "görüntülü arama"
+	}
+
+	switch {
+	case callLog.Status == "missed":
+		return "Cevapsız " + kind
+	case callLog.Status == "declined":
+		return "Reddedilen " + kind
+	case callLog.AnsweredAt == nil:
+		return "İptal edilen " + kind
+	}
+	return fmt.Sprintf("Tamamlanan %s · %02d:%02d", kind, callLog.Duration/60, callLog.Duration%60)
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestExpireRinging(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	calls := mocks.NewMockCallLogRepository(ctrl)
+	conversations := mocks.NewMockConversationRepository(ctrl)
+	notifications := mocks.NewMockNotificationRepository(ctrl)
+	prefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{
+		CallLog:                calls,
+		Conversation:           conversations,
+		Notification:           notifications,
+		NotificationPreference: prefs,
+		UnitOfWork:             uow,
+	}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	prefs.EXPECT().GetByUserAndType(gomock.Any(), gomock.Any()).Return(&models.NotificationPreference{}, nil).AnyTimes()
+	svc := NewCallService(repos, nil, NewNotificationService(repos, nil, nil, nil, nil, nil))
+	rooms := &recordingBroadcaster{events: map[string][]string{}}
+	svc.SetBroadcaster(rooms)
+
+	missed := &models.CallLog{ID: uuid.New(), CallerID: uuid.New(), CalleeID: uuid.New(), Status: "missed", Caller: &models.User{FullName: "Ayşe"}}
+	answered := uuid.New()
+	calls.EXPECT().ListRingExpired(gomock.Any(), callRingBatchSize).Return([]uuid.UUID{missed.ID, answered}, nil)
+	calls.EXPECT().MarkMissed(missed.ID).Return(true, nil)
+	calls.EXPECT().MarkMissed(answered).Return(false, nil)
+	calls.EXPECT().GetByID(missed.ID).Return(missed, nil).Times(2)
+	notifications.EXPECT().Create(gomock.Any()).DoAndReturn(func(n *models.Notification) error {
+		if n.UserID != missed.CalleeID || n.Type != "call" {
+			t.Errorf("unexpected notification %+v", n)
+		}
+		return nil
+	})
+	conversations.EXPECT().FindBetween(missed.CallerID, missed.CalleeID).Return(nil, errors.New("record not found"))
+
+	svc.ExpireRinging()
+
+	for _, user := range []uuid.UUID{missed.CallerID, missed.CalleeID} {
+		if got := rooms.events[user.String()]; len(got) != 1 || got[0] != "call_missed" {
+			t.Errorf("expected call_missed for %s, got %v", user, got)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+)
+
+// Demand levels of a category in a city
+const (
+	DemandLow    = "low"
+	DemandMedium = "medium"
+	DemandHigh   = "high"
+)
+
+// DemandInterval is how often category demand is recomputed
+const DemandInterval = 10 * time.Minute
+
+const (
+	// Open orders per available yandaş from which demand is high
+	highDemandRatio = 1.5
+	// Fewer open orders than this are never high demand, so one order in a
+	// city without yandaşlar does not light up the category
+	highDemandMinOrders = 3
+	// Open orders per available yandaş from which demand is medium
+	mediumDemandRatio = 0.5
+)
+
+// demandRatio is open orders per available yandaş; with nobody available every open order counts in full
+func demandRatio(openOrders, availableYandas int64) float64 {
+	if availableYandas == 0 {
+		return float64(openOrders)
+	}
+	return math.Round(float64(openOrders)/float64(availableYandas)*100) / 100
+}
+
+func demandLevel(openOrders int64, ratio float64) string {
+	switch {
+	case openOrders >= highDemandMinOrders && ratio >= highDemandRatio:
+		return DemandHigh
+	case ratio >= mediumDemandRatio:
+		return DemandMedium
+	default:
+		return DemandLow
+	}
+}
+
+// RefreshDemand recomputes demand for every city and category; the scheduler calls it every DemandInterval
+func (s *CategoryService) RefreshDemand() {
+	rows, err := s.repos.Demand.Counts()
+	if err != nil {
+		log.Printf("[DEMAND] failed to count demand: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for i := range rows {
+		rows[i].Ratio = demandRatio(rows[i].OpenOrders, rows[i].AvailableYandas)
+		rows[i].Level = demandLevel(rows[i].OpenOrders, rows[i].Ratio)
+		rows[i].ComputedAt = now
+	}
+	if err := s.repos.Demand.Replace(rows); err != nil {
+		log.Printf("[DEMAND] failed to store demand: %v", err)
+	}
+}
+
+// Demand returns the latest demand per category in a city, busiest first
+func (s *CategoryService) Demand(city string) ([]models.CategoryDemand, error) {
+	return s.repos.Demand.ListByCity(city)
+}
+
+// markHighDemand flags categories in high demand in city. A parent is flagged
+// when one of its sub-categories is.
+func (s *CategoryService) markHighDemand(categories []models.Category, city string) {
+	ids, err := s.repos.Demand.CategoryIDsAtLevel(city, DemandHigh)
+	if err != nil {
+		log.Printf("[DEMAND] failed to load high demand categories in %s: %v", city, err)
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	high := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		high[id] = true
+	}
+	for i := range categories {
+		category := &categories[i]
+		category.HighDemand = high[category.ID]
+		for j := range category.SubCategories {
+			sub := &category.SubCategories[j]
+			sub.HighDemand = high[sub.ID]
+			if sub.HighDemand {
+				category.HighDemand = true
+			}
+		}
+	}
+}
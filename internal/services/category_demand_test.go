@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestDemandLevel(t *testing.T) {
+	tests := []struct {
+		open, available int64
+		want            string
+	}{
+		{0, 4, DemandLow},
+		{1, 4, DemandLow},
+		{2, 4, DemandMedium},
+		{6, 4, DemandHigh},
+		{2, 0, DemandMedium}, // too few orders to be high even with nobody available
+		{3, 0, DemandHigh},
+	}
+	for _, tt := range tests {
+		if got := demandLevel(tt.open, demandRatio(tt.open, tt.available)); got != tt.want {
+			t.Errorf("%d open / %d available: got %s, want %s", tt.open, tt.available, got, tt.want)
+		}
+	}
+}
+
+func TestCategoryListFlagsHighDemand(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	categories := mocks.NewMockCategoryRepository(ctrl)
+	demand := mocks.NewMockDemandRepository(ctrl)
+	svc := NewCategoryService(&repository.Repositories{Category: categories, Demand: demand})
+
+	cleaning, moving, deepClean := uuid.New(), uuid.New(), uuid.New()
+	list := func() []models.Category {
+		return []models.Category{
+			{ID: cleaning, SubCategories: []models.Category{{ID: deepClean}}},
+			{ID: moving},
+		}
+	}
+	categories.EXPECT().List().Return(list(), nil)
+	demand.EXPECT().CategoryIDsAtLevel("Ankara", DemandHigh).Return([]uuid.UUID{deepClean}, nil)
+
+	result, err := svc.List("Ankara")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result[0].HighDemand || !result[0].SubCategories[0].HighDemand || result[1].HighDemand {
+		t.Errorf("expected the sub-category and its parent flagged, got %+v", result)
+	}
+
+	categories.EXPECT().List().Return(list(), nil)
+	result, _ = svc.List("")
+	if result[0].HighDemand {
+		t.Error("expected no flags without a city")
+	}
+}
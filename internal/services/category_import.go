@@ -0,0 +1,446 @@
+package services
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// Category import file formats
+const (
+	CategoryImportCSV  = "csv"
+	CategoryImportJSON = "json"
+)
+
+// Most rows accepted in one import
+const categoryImportMaxRows = 2000
+
+//go:embed default_categories.json
+var defaultCategories []byte
+
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ErrInvalidCategoryImport matches every CategoryImportError with errors.Is
+var ErrInvalidCategoryImport = validationError("invalid_category_import", "category import is invalid")
+
+// CategoryImportRowError points at a row that failed validation. Row is the CSV
+// line number, or the position of the object in a JSON file, counting from 1.
+type CategoryImportRowError struct {
+	Row     int    `json:"row"`
+	Slug    string `json:"slug,omitempty"`
+	Message string `json:"message"`
+}
+
+// CategoryImportError lists every invalid row of an import; nothing is applied
+type CategoryImportError struct {
+	Rows []CategoryImportRowError
+}
+
+func (e *CategoryImportError) Error() string {
+	return fmt.Sprintf("%s: %d invalid rows", ErrInvalidCategoryImport, len(e.Rows))
+}
+
+func (e *CategoryImportError) Unwrap() error {
+	return ErrInvalidCategoryImport
+}
+
+// CategoryImportRow is one category in an import file. Omitted optional fields
+// leave an existing category's value unchanged.
+type CategoryImportRow struct {
+	Slug          string              `json:"slug"`
+	ParentSlug    string              `json:"parent_slug"`
+	Name          string              `json:"name"`
+	NameEN        *string             `json:"name_en"`
+	Icon          *string             `json:"icon"`
+	Description   *string             `json:"description"`
+	SortOrder     *int                `json:"sort_order"`
+	IsActive      *bool               `json:"is_active"`
+	SubCategories []CategoryImportRow `json:"sub_categories"`
+
+	row int
+}
+
+// CategoryFieldChange is one field an import changes
+type CategoryFieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// CategoryChange describes how an import changes an existing category
+type CategoryChange struct {
+	Slug    string                         `json:"slug"`
+	Changes map[string]CategoryFieldChange `json:"changes"`
+}
+
+// CategoryImportResult is the diff of an import against the stored categories.
+// On a dry run it describes what would happen; nothing is written.
+type CategoryImportResult struct {
+	DryRun    bool             `json:"dry_run"`
+	Created   []string         `json:"created"`
+	Updated   []CategoryChange `json:"updated"`
+	Unchanged int              `json:"unchanged"`
+
+	creates []*models.Category
+	updates []*models.Category
+	parents map[string]string
+}
+
+// ParseCategoryImport reads categories from CSV with a header row (slug,
+// parent_slug, name, name_en, icon, description, sort_order, is_active) or from
+// a JSON array whose objects may nest their subcategories in sub_categories
+func ParseCategoryImport(format string, r io.Reader) ([]CategoryImportRow, error) {
+	switch format {
+	case CategoryImportCSV:
+		return parseCategoryCSV(r)
+	case CategoryImportJSON:
+		return parseCategoryJSON(r)
+	}
+	return nil, validationError("invalid_import_format", "format must be csv or json")
+}
+
+func parseCategoryCSV(r io.Reader) ([]CategoryImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, validationError("invalid_import_file", "csv file has no header row")
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		// Spreadsheet apps prepend a BOM to UTF-8 exports
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\xEF\xBB\xBF")))] = i
+	}
+	for _, required := range []string{"slug", "name"} {
+		if _, ok := columns[required]; !ok {
+			return nil, validationError("invalid_import_file", fmt.Sprintf("csv header is missing the %s column", required))
+		}
+	}
+
+	var rows []CategoryImportRow
+	invalid := &CategoryImportError{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, validationError("invalid_import_file", fmt.Sprintf("csv line %d: %v", line, err))
+		}
+		cell := func(name string) (string, bool) {
+			i, ok := columns[name]
+			if !ok || i >= len(record) || strings.TrimSpace(record[i]) == "" {
+				return "", false
+			}
+			return strings.TrimSpace(record[i]), true
+		}
+		optional := func(name string) *string {
+			if v, ok := cell(name); ok {
+				return &v
+			}
+			return nil
+		}
+
+		row := CategoryImportRow{
+			NameEN:      optional("name_en"),
+			Icon:        optional("icon"),
+			Description: optional("description"),
+			row:         line,
+		}
+		row.Slug, _ = cell("slug")
+		row.ParentSlug, _ = cell("parent_slug")
+		row.Name, _ = cell("name")
+		if v, ok := cell("sort_order"); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				invalid.Rows = append(invalid.Rows, CategoryImportRowError{Row: line, Slug: row.Slug, Message: "sort_order must be a whole number"})
+				continue
+			}
+			row.SortOrder = &n
+		}
+		if v, ok := cell("is_active"); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				invalid.Rows = append(invalid.Rows, CategoryImportRowError{Row: line, Slug: row.Slug, Message: "is_active must be true or false"})
+				continue
+			}
+			row.IsActive = &b
+		}
+		rows = append(rows, row)
+	}
+	if len(invalid.Rows) > 0 {
+		return nil, invalid
+	}
+	return rows, nil
+}
+
+func parseCategoryJSON(r io.Reader) ([]CategoryImportRow, error) {
+	var nested []CategoryImportRow
+	if err := json.NewDecoder(r).Decode(&nested); err != nil {
+		return nil, validationError("invalid_import_file", fmt.Sprintf("invalid json: %v", err))
+	}
+
+	// Flatten nested subcategories, numbering objects in file order
+	var rows []CategoryImportRow
+	var flatten func(items []CategoryImportRow, parentSlug string)
+	flatten = func(items []CategoryImportRow, parentSlug string) {
+		for _, item := range items {
+			subs := item.SubCategories
+			item.SubCategories = nil
+			if parentSlug != "" {
+				item.ParentSlug = parentSlug
+			}
+			item.row = len(rows) + 1
+			rows = append(rows, item)
+			flatten(subs, item.Slug)
+		}
+	}
+	flatten(nested, "")
+	return rows, nil
+}
+
+// planCategoryImport validates rows against each other and the stored
+// categories and works out what must be created or changed
+func planCategoryImport(existing []models.Category, rows []CategoryImportRow) (*CategoryImportResult, error) {
+	invalid := &CategoryImportError{}
+	fail := func(row CategoryImportRow, format string, args ...interface{}) {
+		invalid.Rows = append(invalid.Rows, CategoryImportRowError{Row: row.row, Slug: row.Slug, Message: fmt.Sprintf(format, args...)})
+	}
+	if len(rows) == 0 {
+		return nil, validationError("invalid_import_file", "import file has no categories")
+	}
+	if len(rows) > categoryImportMaxRows {
+		return nil, validationError("invalid_import_file", fmt.Sprintf("import file has more than %d categories", categoryImportMaxRows))
+	}
+
+	stored := map[string]*models.Category{}
+	slugByID := map[uuid.UUID]string{}
+	hasChildren := map[string]bool{}
+	for i := range existing {
+		stored[existing[i].Slug] = &existing[i]
+		slugByID[existing[i].ID] = existing[i].Slug
+	}
+	for _, category := range existing {
+		if category.ParentID != nil {
+			hasChildren[slugByID[*category.ParentID]] = true
+		}
+	}
+
+	bySlug := map[string]CategoryImportRow{}
+	for _, row := range rows {
+		switch {
+		case !categorySlugPattern.MatchString(row.Slug) || len(row.Slug) > 100:
+			fail(row, "slug must be lowercase letters, digits and single hyphens, at most 100 characters")
+			continue
+		case bySlug[row.Slug].row != 0:
+			fail(row, "slug %s already appears on row %d", row.Slug, bySlug[row.Slug].row)
+			continue
+		}
+		bySlug[row.Slug] = row
+
+		if strings.TrimSpace(row.Name) == "" || len(row.Name) > 100 {
+			fail(row, "name is required and must be at most 100 characters")
+		}
+		if row.NameEN != nil && len(*row.NameEN) > 100 {
+			fail(row, "name_en must be at most 100 characters")
+		}
+		if row.Icon != nil && len(*row.Icon) > 50 {
+			fail(row, "icon must be at most 50 characters")
+		}
+	}
+
+	// Categories are two levels deep: a parent must itself be top-level
+	parentOf := func(slug string) (string, bool) {
+		if row, ok := bySlug[slug]; ok {
+			return row.ParentSlug, true
+		}
+		if category, ok := stored[slug]; ok {
+			if category.ParentID == nil {
+				return "", true
+			}
+			return slugByID[*category.ParentID], true
+		}
+		return "", false
+	}
+	for _, row := range rows {
+		if row.ParentSlug == "" {
+			continue
+		}
+		switch grandparent, found := parentOf(row.ParentSlug); {
+		case row.ParentSlug == row.Slug:
+			fail(row, "a category cannot be its own parent")
+		case !found:
+			fail(row, "parent %s does not exist", row.ParentSlug)
+		case grandparent != "":
+			fail(row, "parent %s is itself a subcategory", row.ParentSlug)
+		case hasChildren[row.Slug]:
+			fail(row, "%s has subcategories and cannot become one", row.Slug)
+		}
+	}
+	if len(invalid.Rows) > 0 {
+		sort.Slice(invalid.Rows, func(i, j int) bool { return invalid.Rows[i].Row < invalid.Rows[j].Row })
+		return nil, invalid
+	}
+
+	result := &CategoryImportResult{Created: []string{}, Updated: []CategoryChange{}, parents: map[string]string{}}
+	for _, row := range rows {
+		result.parents[row.Slug] = row.ParentSlug
+
+		category, ok := stored[row.Slug]
+		if !ok {
+			created := &models.Category{
+				Slug:        row.Slug,
+				Name:        strings.TrimSpace(row.Name),
+				NameEN:      row.NameEN,
+				Icon:        row.Icon,
+				Description: row.Description,
+				IsActive:    row.IsActive == nil || *row.IsActive,
+			}
+			if row.SortOrder != nil {
+				created.SortOrder = *row.SortOrder
+			}
+			result.creates = append(result.creates, created)
+			result.Created = append(result.Created, row.Slug)
+			continue
+		}
+
+		updated := *category
+		changes := map[string]CategoryFieldChange{}
+		currentParent := ""
+		if category.ParentID != nil {
+			currentParent = slugByID[*category.ParentID]
+		}
+		if row.ParentSlug != currentParent {
+			changes["parent_slug"] = CategoryFieldChange{From: currentParent, To: row.ParentSlug}
+		}
+		if name := strings.TrimSpace(row.Name); name != category.Name {
+			changes["name"] = CategoryFieldChange{From: category.Name, To: name}
+			updated.Name = name
+		}
+		diffOptional := func(field string, incoming *string, current **string) {
+			if incoming != nil && (*current == nil || **current != *incoming) {
+				changes[field] = CategoryFieldChange{From: *current, To: *incoming}
+				*current = incoming
+			}
+		}
+		diffOptional("name_en", row.NameEN, &updated.NameEN)
+		diffOptional("icon", row.Icon, &updated.Icon)
+		diffOptional("description", row.Description, &updated.Description)
+		if row.SortOrder != nil && *row.SortOrder != category.SortOrder {
+			changes["sort_order"] = CategoryFieldChange{From: category.SortOrder, To: *row.SortOrder}
+			updated.SortOrder = *row.SortOrder
+		}
+		if row.IsActive != nil && *row.IsActive != category.IsActive {
+			changes["is_active"] = CategoryFieldChange{From: category.IsActive, To: *row.IsActive}
+			updated.IsActive = *row.IsActive
+		}
+
+		if len(changes) == 0 {
+			result.Unchanged++
+			continue
+		}
+		updated.SubCategories = nil
+		result.updates = append(result.updates, &updated)
+		result.Updated = append(result.Updated, CategoryChange{Slug: row.Slug, Changes: changes})
+	}
+	return result, nil
+}
+
+// applyCategoryImport writes a plan, top-level categories first so new
+// subcategories can point at parents created in the same import
+func applyCategoryImport(tx *repository.Repositories, existing []models.Category, plan *CategoryImportResult) error {
+	ids := map[string]uuid.UUID{}
+	for _, category := range existing {
+		ids[category.Slug] = category.ID
+	}
+
+	pending := append(append([]*models.Category{}, plan.creates...), plan.updates...)
+	sort.SliceStable(pending, func(i, j int) bool {
+		return plan.parents[pending[i].Slug] == "" && plan.parents[pending[j].Slug] != ""
+	})
+
+	for _, category := range pending {
+		category.ParentID = nil
+		if parent := plan.parents[category.Slug]; parent != "" {
+			id := ids[parent]
+			category.ParentID = &id
+		}
+		if category.ID == uuid.Nil {
+			if err := tx.Category.Create(category); err != nil {
+				return fmt.Errorf("create %s: %w", category.Slug, err)
+			}
+			ids[category.Slug] = category.ID
+			// The column defaults to true, so an inactive category needs a second write
+			if !category.IsActive {
+				if err := tx.Category.Update(category); err != nil {
+					return fmt.Errorf("deactivate %s: %w", category.Slug, err)
+				}
+			}
+			continue
+		}
+		if err := tx.Category.Update(category); err != nil {
+			return fmt.Errorf("update %s: %w", category.Slug, err)
+		}
+	}
+	return nil
+}
+
+// ImportCategories creates and updates categories from an import file.
+// Categories missing from the file are left alone. With dryRun the diff is
+// returned without writing anything.
+func (s *AdminService) ImportCategories(rows []CategoryImportRow, dryRun bool, adminID uuid.UUID) (*CategoryImportResult, error) {
+	existing, err := s.repos.OnPrimary().Category.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	plan, err := planCategoryImport(existing, rows)
+	if err != nil {
+		return nil, err
+	}
+	plan.DryRun = dryRun
+	if dryRun || len(plan.creates)+len(plan.updates) == 0 {
+		return plan, nil
+	}
+
+	if err := s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		return applyCategoryImport(tx, existing, plan)
+	}); err != nil {
+		return nil, err
+	}
+
+	s.logAction(adminID, "import_categories", "category", uuid.Nil, nil, map[string]interface{}{
+		"created": plan.Created,
+		"updated": plan.Updated,
+	})
+	return plan, nil
+}
+
+// EnsureDefaultCategories imports the built-in category tree into an empty
+// catalogue. Once any category exists, admins manage them through imports.
+func (s *CategoryService) EnsureDefaultCategories() error {
+	existing, err := s.repos.OnPrimary().Category.ListAll()
+	if err != nil || len(existing) > 0 {
+		return err
+	}
+	rows, err := ParseCategoryImport(CategoryImportJSON, bytes.NewReader(defaultCategories))
+	if err != nil {
+		return err
+	}
+	plan, err := planCategoryImport(nil, rows)
+	if err != nil {
+		return err
+	}
+	return s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		return applyCategoryImport(tx, nil, plan)
+	})
+}
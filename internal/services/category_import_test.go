@@ -0,0 +1,86 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+)
+
+func TestParseCategoryImportCSV(t *testing.T) {
+	file := "\xEF\xBB\xBFslug,parent_slug,name,name_en,icon,sort_order,is_active\n" +
+		"kurye,,Kurye,Courier,package,1,\n" +
+		"belge-teslimi,kurye,Belge Teslimi,,,2,false\n"
+	rows, err := ParseCategoryImport(CategoryImportCSV, strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0].NameEN == nil || *rows[0].NameEN != "Courier" || rows[0].IsActive != nil {
+		t.Errorf("unexpected first row %+v", rows[0])
+	}
+	if rows[1].ParentSlug != "kurye" || rows[1].NameEN != nil || rows[1].IsActive == nil || *rows[1].IsActive || rows[1].row != 3 {
+		t.Errorf("unexpected second row %+v", rows[1])
+	}
+
+	_, err = ParseCategoryImport(CategoryImportCSV, strings.NewReader("slug,name,sort_order\nx,X,first\n"))
+	var invalid *CategoryImportError
+	if !errors.As(err, &invalid) || invalid.Rows[0].Row != 2 {
+		t.Errorf("expected row 2 to be rejected, got %v", err)
+	}
+}
+
+func TestDefaultCategoriesAreValid(t *testing.T) {
+	rows, err := ParseCategoryImport(CategoryImportJSON, bytes.NewReader(defaultCategories))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := planCategoryImport(nil, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Created) != 20 || plan.parents["valet-hizmeti"] != "vekil-surucu" {
+		t.Errorf("expected 5 categories with 3 subcategories each, got %v", plan.Created)
+	}
+}
+
+func TestPlanCategoryImport(t *testing.T) {
+	parentID := uuid.New()
+	icon := "car"
+	existing := []models.Category{
+		{ID: parentID, Slug: "vasita", Name: "Vasıta", Icon: &icon, IsActive: true, SortOrder: 1},
+		{ID: uuid.New(), ParentID: &parentID, Slug: "arac-teslimat", Name: "Araç Teslimat", IsActive: true},
+	}
+	str := func(s string) *string { return &s }
+
+	plan, err := planCategoryImport(existing, []CategoryImportRow{
+		{Slug: "vasita", Name: "Vasıta", Icon: str("truck"), row: 1},
+		{Slug: "arac-teslimat", ParentSlug: "vasita", Name: "Araç Teslimat", row: 2},
+		{Slug: "ekspertiz", ParentSlug: "vasita", Name: "Ekspertiz", row: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Created) != 1 || plan.Created[0] != "ekspertiz" || plan.Unchanged != 1 {
+		t.Errorf("unexpected plan %+v", plan)
+	}
+	if len(plan.Updated) != 1 || plan.Updated[0].Changes["icon"].To != "truck" || len(plan.Updated[0].Changes) != 1 {
+		t.Errorf("expected only the icon to change, got %+v", plan.Updated)
+	}
+
+	_, err = planCategoryImport(existing, []CategoryImportRow{
+		{Slug: "Bad Slug", Name: "x", row: 1},
+		{Slug: "ekspertiz", ParentSlug: "arac-teslimat", Name: "Ekspertiz", row: 2},
+		{Slug: "vasita", ParentSlug: "yok", Name: "Vasıta", row: 3},
+		{Slug: "ekspertiz", Name: "Tekrar", row: 4},
+	})
+	var invalid *CategoryImportError
+	if !errors.As(err, &invalid) || len(invalid.Rows) != 4 {
+		t.Fatalf("expected four invalid rows, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/media"
+)
+
+// transcodeTimeout bounds a single voice note transcode
+const transcodeTimeout = time.Minute
+
+// ErrAudioProcessing is returned when an uploaded voice note cannot be stored or transcoded
+var ErrAudioProcessing = validationError("audio_processing", "audio could not be processed")
+
+// SendAudioMessage stores a voice note, transcoding it when a transcoder is
+// configured, and posts it as an audio message whose media_url plays it back
+func (s *ChatService) SendAudioMessage(userID, convID uuid.UUID, file *multipart.FileHeader, durationSeconds int) (*models.Message, error) {
+	if _, err := s.GetConversation(userID, convID); err != nil {
+		return nil, err
+	}
+	if err := media.ValidateAudio(file.Filename, file.Size, time.Duration(durationSeconds)*time.Second); err != nil {
+		return nil, err
+	}
+
+	url, err := s.storeAudio(userID, file)
+	if err != nil {
+		log.Printf("[CHAT] storing voice note from %s failed: %v", userID, err)
+		return nil, ErrAudioProcessing
+	}
+
+	msg := &models.Message{
+		ConversationID:  convID,
+		SenderID:        userID,
+		Content:         url,
+		MessageType:     "audio",
+		MediaURL:        &url,
+		DurationSeconds: &durationSeconds,
+	}
+	if err := s.saveMessage(msg); err != nil {
+		s.removeAudio(url)
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// storeAudio writes the upload under storagePath/audio and returns its /uploads URL
+func (s *ChatService) storeAudio(userID uuid.UUID, file *multipart.FileHeader) (string, error) {
+	dir := filepath.Join(s.storagePath, "audio")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	base := fmt.Sprintf("%s_%d", userID, time.Now().UnixNano())
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	src := filepath.Join(dir, base+ext)
+	if err := copyUpload(file, src); err != nil {
+		return "", err
+	}
+	if s.transcoder == nil {
+		return "/uploads/audio/" + base + ext, nil
+	}
+
+	// Transcode next to the original and keep only the result
+	defer os.Remove(src)
+	name := base + s.transcoder.Ext()
+	if name == base+ext {
+		name = base + "_" + s.transcoder.Name() + s.transcoder.Ext()
+	}
+	dst := filepath.Join(dir, name)
+
+	ctx, cancel := context.WithTimeout(context.Background(), transcodeTimeout)
+	defer cancel()
+	if err := s.transcoder.TranscodeAudio(ctx, src, dst); err != nil {
+		os.Remove(dst)
+		return "", err
+	}
+	return "/uploads/audio/" + name, nil
+}
+
+// removeAudio deletes a stored voice note by its /uploads URL
+func (s *ChatService) removeAudio(url string) {
+	os.Remove(filepath.Join(s.storagePath, strings.TrimPrefix(url, "/uploads/")))
+}
+
+func copyUpload(file *multipart.FileHeader, dst string) error {
+	in, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+)
+
+// LiveLocationInterval is how often expired live location shares are closed
+const LiveLocationInterval = time.Minute
+
+// MaxLiveLocationMinutes caps how long a location can be shared live
+const MaxLiveLocationMinutes = 60
+
+var (
+	ErrLiveLocationDuration = validationError("live_location_duration", "live location can be shared for 1 to 60 minutes")
+	ErrLiveLocationEnded    = conflictError("live_location_ended", "live location is not being shared")
+)
+
+// ConversationBroadcaster delivers realtime events to a conversation's WebSocket room
+type ConversationBroadcaster interface {
+	BroadcastConversationEvent(convID string, msgType string, payload interface{})
+}
+
+// SetBroadcaster lets live location updates reach the conversation room.
+// Only the API process has connections, so the worker leaves it unset.
+func (s *ChatService) SetBroadcaster(b ConversationBroadcaster) {
+	s.realtime = b
+}
+
+// LocationMessageInput is a shared location, optionally kept live for LiveMinutes
+type LocationMessageInput struct {
+	Latitude    *float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude   *float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	Label       string   `json:"label" binding:"max=255"`
+	LiveMinutes int      `json:"live_minutes"`
+}
+
+// LiveLocationInput is a new position of a live location share
+type LiveLocationInput struct {
+	Latitude  *float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"required,min=-180,max=180"`
+}
+
+// SendLocationMessage posts a location message. With LiveMinutes set, the sender
+// can keep moving it until it expires; every move is streamed to the conversation room.
+func (s *ChatService) SendLocationMessage(userID, convID uuid.UUID, input *LocationMessageInput) (*models.Message, error) {
+	if input.LiveMinutes < 0 || input.LiveMinutes > MaxLiveLocationMinutes {
+		return nil, ErrLiveLocationDuration
+	}
+	if _, err := s.GetConversation(userID, convID); err != nil {
+		return nil, err
+	}
+
+	msg := &models.Message{
+		ConversationID: convID,
+		SenderID:       userID,
+		Content:        "Konum",
+		MessageType:    "location",
+		Latitude:       input.Latitude,
+		Longitude:      input.Longitude,
+	}
+	if input.LiveMinutes > 0 {
+		until := time.Now().Add(time.Duration(input.LiveMinutes) * time.Minute)
+		msg.Content = "Canlı konum"
+		msg.LiveUntil = &until
+	}
+	if label := strings.TrimSpace(input.Label); label != "" {
+		msg.Content = label
+		msg.LocationLabel = &label
+	}
+
+	if err := s.saveMessage(msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// UpdateLiveLocation moves the sender's live location message and streams the new position
+func (s *ChatService) UpdateLiveLocation(userID, convID, messageID uuid.UUID, input *LiveLocationInput) error {
+	ok, err := s.repos.Message.UpdateLiveLocation(messageID, convID, userID, *input.Latitude, *input.Longitude, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrLiveLocationEnded
+	}
+
+	s.pushLocation(convID, "location_update", map[string]interface{}{
+		"conversation_id": convID,
+		"message_id":      messageID,
+		"user_id":         userID,
+		"latitude":        *input.Latitude,
+		"longitude":       *input.Longitude,
+	})
+	return nil
+}
+
+// StopLiveLocation ends the sender's live location share before it expires
+func (s *ChatService) StopLiveLocation(userID, convID, messageID uuid.UUID) error {
+	ok, err := s.repos.Message.EndLiveLocation(messageID, convID, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrLiveLocationEnded
+	}
+
+	s.pushLocationEnded(convID, messageID, userID)
+	return nil
+}
+
+// ExpireLiveLocations closes live shares whose time ran out; the scheduler runs it every LiveLocationInterval
+func (s *ChatService) ExpireLiveLocations() {
+	expired, err := s.repos.Message.ExpireLiveLocations(time.Now())
+	if err != nil {
+		log.Printf("[CHAT] failed to expire live locations: %v", err)
+		return
+	}
+	for _, msg := range expired {
+		s.pushLocationEnded(msg.ConversationID, msg.ID, msg.SenderID)
+	}
+}
+
+func (s *ChatService) pushLocationEnded(convID, messageID, userID uuid.UUID) {
+	s.pushLocation(convID, "location_ended", map[string]interface{}{
+		"conversation_id": convID,
+		"message_id":      messageID,
+		"user_id":         userID,
+	})
+}
+
+func (s *ChatService) pushLocation(convID uuid.UUID, msgType string, payload interface{}) {
+	if s.realtime != nil {
+		s.realtime.BroadcastConversationEvent(convID.String(), msgType, payload)
+	}
+}
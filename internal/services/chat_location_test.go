@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+type recordingRoomBroadcaster struct {
+	events map[string][]string
+}
+
+func (b *recordingRoomBroadcaster) BroadcastConversationEvent(convID string, msgType string, _ interface{}) {
+	b.events[convID] = append(b.events[convID], msgType)
+}
+
+func newTestChatService(t *testing.T) (*ChatService, *mocks.MockConversationRepository, *mocks.MockMessageRepository, *recordingRoomBroadcaster) {
+	ctrl := gomock.NewController(t)
+	conversations := mocks.NewMockConversationRepository(ctrl)
+	messages := mocks.NewMockMessageRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{Conversation: conversations, Message: messages, UnitOfWork: uow}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	svc := NewChatService(repos, nil, t.TempDir(), nil, nil)
+	ws := &recordingRoomBroadcaster{events: map[string][]string{}}
+	svc.SetBroadcaster(ws)
+	return svc, conversations, messages, ws
+}
+
+func TestSendLocationMessageLive(t *testing.T) {
+	svc, conversations, messages, _ := newTestChatService(t)
+	userID := uuid.New()
+	conv := &models.Conversation{ID: uuid.New(), CustomerID: userID, YandasID: uuid.New()}
+	lat, lng := 41.0082, 28.9784
+
+	if _, err := svc.SendLocationMessage(userID, conv.ID, &LocationMessageInput{Latitude: &lat, Longitude: &lng, LiveMinutes: 90}); err != ErrLiveLocationDuration {
+		t.Fatalf("expected ErrLiveLocationDuration, got %v", err)
+	}
+
+	conversations.EXPECT().GetByID(conv.ID).Return(conv, nil)
+	messages.EXPECT().Create(gomock.Any()).Return(nil)
+	conversations.EXPECT().UpdateLastMessage(conv.ID).Return(nil)
+
+	msg, err := svc.SendLocationMessage(userID, conv.ID, &LocationMessageInput{Latitude: &lat, Longitude: &lng, Label: " Kapı önü ", LiveMinutes: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.MessageType != "location" || msg.Content != "Kapı önü" || *msg.LocationLabel != "Kapı önü" {
+		t.Errorf("unexpected message %+v", msg)
+	}
+	if msg.LiveUntil == nil || time.Until(*msg.LiveUntil) < 14*time.Minute {
+		t.Errorf("expected a 15 minute live share, got %v", msg.LiveUntil)
+	}
+}
+
+func TestLiveLocationUpdatesAndExpiry(t *testing.T) {
+	svc, _, messages, ws := newTestChatService(t)
+	userID, convID, messageID := uuid.New(), uuid.New(), uuid.New()
+	lat, lng := 41.01, 28.98
+
+	messages.EXPECT().UpdateLiveLocation(messageID, convID, userID, lat, lng, gomock.Any()).Return(true, nil)
+	if err := svc.UpdateLiveLocation(userID, convID, messageID, &LiveLocationInput{Latitude: &lat, Longitude: &lng}); err != nil {
+		t.Fatal(err)
+	}
+
+	messages.EXPECT().UpdateLiveLocation(messageID, convID, userID, lat, lng, gomock.Any()).Return(false, nil)
+	if err := svc.UpdateLiveLocation(userID, convID, messageID, &LiveLocationInput{Latitude: &lat, Longitude: &lng}); err != ErrLiveLocationEnded {
+		t.Fatalf("expected ErrLiveLocationEnded, got %v", err)
+	}
+
+	messages.EXPECT().ExpireLiveLocations(gomock.Any()).
+		Return([]models.Message{{ID: messageID, ConversationID: convID, SenderID: userID}}, nil)
+	svc.ExpireLiveLocations()
+
+	if got := ws.events[convID.String()]; len(got) != 2 || got[0] != "location_update" || got[1] != "location_ended" {
+		t.Errorf("unexpected events %v", got)
+	}
+}
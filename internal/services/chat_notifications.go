@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/queue"
+)
+
+// Longest text message shown in a new message notification
+const messagePreviewLength = 100
+
+// After a message push, further messages in the conversation within this
+// window are summarised in a single push when it closes
+const chatBatchWindow = time.Minute
+
+// messagePreview is how a message reads in a notification
+func messagePreview(msg *models.Message) string {
+	switch msg.MessageType {
+	case "image":
+		return "📷 Fotoğraf"
+	case "audio":
+		return "🎤 Sesli mesaj"
+	case "location":
+		return "📍 " + msg.Content
+	}
+	return truncateText(msg.Content, messagePreviewLength)
+}
+
+// notifyNewMessage tells the other participant of a conversation about a
+// message. Every message gets an in-app notification; pushes for messages
+// following one another closely are held back and batched.
+func (s *NotificationService) notifyNewMessage(e MessageSentEvent) error {
+	conv, err := s.repos.Conversation.GetByID(e.ConversationID)
+	if err != nil {
+		return err
+	}
+	recipientID := conv.CustomerID
+	if e.SenderID == conv.CustomerID {
+		recipientID = conv.YandasID
+	}
+	sender, err := s.repos.User.GetByID(e.SenderID)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"conversation_id": e.ConversationID.String(),
+		"message_id":      e.MessageID.String(),
+	}
+	if s.holdForBatch(recipientID, e) {
+		return s.repos.Notification.Create(newNotification(recipientID, sender.FullName, e.Preview, "chat", data))
+	}
+	return s.Send(recipientID, sender.FullName, e.Preview, "chat", data)
+}
+
+type chatBatchJob struct {
+	RecipientID    uuid.UUID `json:"recipient_id"`
+	ConversationID uuid.UUID `json:"conversation_id"`
+	SenderID       uuid.UUID `json:"sender_id"`
+}
+
+func chatBatchKey(recipientID, convID uuid.UUID) string {
+	return fmt.Sprintf("notif_batch:%s:%s", recipientID, convID)
+}
+
+// holdForBatch reports whether the push for a message should wait for the
+// batch summary. The first message of a window is pushed right away; the
+// ones after it are counted and the summary is scheduled with the first of them.
+func (s *NotificationService) holdForBatch(recipientID uuid.UUID, e MessageSentEvent) bool {
+	if s.redis == nil || !s.preference(recipientID, "chat").BatchEnabled {
+		return false
+	}
+	ctx := context.Background()
+	key := chatBatchKey(recipientID, e.ConversationID)
+
+	// The key outlives the window so the count is still there when the summary runs
+	started, err := s.redis.SetNX(ctx, key, 0, 2*chatBatchWindow).Result()
+	if err != nil {
+		log.Printf("[NOTIFICATIONS] batching unavailable, pushing message %s: %v", e.MessageID, err)
+		return false
+	}
+	if started {
+		return false
+	}
+
+	held, err := s.redis.Incr(ctx, key).Result()
+	if err != nil {
+		log.Printf("[NOTIFICATIONS] batching unavailable, pushing message %s: %v", e.MessageID, err)
+		return false
+	}
+	if held == 1 {
+		s.redis.Expire(ctx, key, 2*chatBatchWindow)
+		job := chatBatchJob{RecipientID: recipientID, ConversationID: e.ConversationID, SenderID: e.SenderID}
+		if err := s.jobs.Enqueue(JobFlushChatBatch, job, queue.Delay(chatBatchWindow)); err != nil {
+			s.redis.Del(ctx, key)
+			return false
+		}
+	}
+	return true
+}
+
+// flushChatBatch sends the "N new messages" push for the messages held back
+// in a conversation and closes the window, so the next message pushes at once
+func (s *NotificationService) flushChatBatch(j chatBatchJob) error {
+	held, err := s.redis.GetDel(context.Background(), chatBatchKey(j.RecipientID, j.ConversationID)).Int()
+	if err == redis.Nil || held == 0 {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !s.IsChannelEnabled(j.RecipientID, "chat", NotificationChannelPush) {
+		return nil
+	}
+
+	sender, err := s.repos.User.GetByID(j.SenderID)
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf("%d yeni mesaj", held)
+	data := map[string]interface{}{
+		"conversation_id": j.ConversationID.String(),
+		"batched":         held,
+	}
+	return s.jobs.Enqueue(JobSendPush, pushJob{UserID: j.RecipientID, Title: sender.FullName, Body: body, Data: data})
+}
+
+// preference returns the user's stored preference for a notification type, or the defaults
+func (s *NotificationService) preference(userID uuid.UUID, notifType string) models.NotificationPreference {
+	pref, err := s.repos.NotificationPreference.GetByUserAndType(userID, notifType)
+	if err != nil {
+		return defaultNotificationPreference(userID, notifType)
+	}
+	return *pref
+}
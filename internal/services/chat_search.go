@@ -0,0 +1,121 @@
+package services
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+)
+
+// minSearchQueryLength keeps single letters from matching every conversation
+const minSearchQueryLength = 2
+
+var (
+	ErrSearchQueryTooShort = validationError("search_query_too_short", "search query must be at least 2 characters")
+	ErrInvalidCursor       = validationError("invalid_cursor", "invalid message cursor")
+)
+
+// ConversationSearchResult is a conversation matching a search. Message is the
+// latest matching message, nil when only the other participant's name matched.
+type ConversationSearchResult struct {
+	Conversation models.Conversation `json:"conversation"`
+	Message      *MessageMatch       `json:"message,omitempty"`
+}
+
+// MessageMatch is a message that matched a search. Snippet marks the matched
+// words with <mark> tags; Cursor opens the conversation at the message.
+type MessageMatch struct {
+	ID      uuid.UUID `json:"id"`
+	Snippet string    `json:"snippet"`
+	SentAt  time.Time `json:"sent_at"`
+	Cursor  string    `json:"cursor"`
+}
+
+// SearchConversations finds the user's conversations by message content and
+// the other participant's name
+func (s *ChatService) SearchConversations(userID uuid.UUID, query string, page, limit int) ([]ConversationSearchResult, int64, error) {
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) < minSearchQueryLength {
+		return nil, 0, ErrSearchQueryTooShort
+	}
+
+	matches, total, err := s.repos.Conversation.Search(userID, query, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]ConversationSearchResult, len(matches))
+	for i, match := range matches {
+		results[i].Conversation = match.Conversation
+		if match.MessageID != nil && match.MessageAt != nil {
+			results[i].Message = &MessageMatch{
+				ID:      *match.MessageID,
+				Snippet: deref(match.Snippet),
+				SentAt:  *match.MessageAt,
+				Cursor:  encodeMessageCursor(*match.MessageAt, *match.MessageID),
+			}
+		}
+	}
+	return results, total, nil
+}
+
+// ContentSearchable reports whether SearchConversations matches message content.
+// With encryption at rest it only matches the other participant's name, plus
+// any messages stored before encryption was turned on.
+func (s *ChatService) ContentSearchable() bool {
+	return s.repos.Message.ContentSearchable()
+}
+
+// GetMessagesFrom returns the conversation's messages from the cursor's message
+// back in time, newest first, so a search result opens at the matched message.
+// The returned cursor continues with older messages; it is empty at the start
+// of the conversation.
+func (s *ChatService) GetMessagesFrom(userID, convID uuid.UUID, cursor string, limit int) ([]models.Message, string, error) {
+	createdAt, id, err := decodeMessageCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := s.GetConversation(userID, convID); err != nil {
+		return nil, "", err
+	}
+
+	msgs, err := s.repos.Message.ListUpTo(convID, createdAt, id, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	next := ""
+	if len(msgs) > limit {
+		next = encodeMessageCursor(msgs[limit].CreatedAt, msgs[limit].ID)
+		msgs = msgs[:limit]
+	}
+	return msgs, next, nil
+}
+
+// encodeMessageCursor makes an opaque cursor of a message's position in its conversation
+func encodeMessageCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeMessageCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	messageID, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	return createdAt, messageID, nil
+}
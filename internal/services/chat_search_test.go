@@ -0,0 +1,81 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+func TestSearchConversations(t *testing.T) {
+	svc, conversations, _, _ := newTestChatService(t)
+	userID := uuid.New()
+
+	if _, _, err := svc.SearchConversations(userID, " a ", 1, 20); err != ErrSearchQueryTooShort {
+		t.Fatalf("expected ErrSearchQueryTooShort, got %v", err)
+	}
+
+	byMessage := models.Conversation{ID: uuid.New(), CustomerID: userID}
+	byName := models.Conversation{ID: uuid.New(), CustomerID: userID}
+	messageID, sentAt, snippet := uuid.New(), time.Now().Add(-time.Hour).Truncate(time.Microsecond), "yarın <mark>anahtar</mark> bırakırım"
+	conversations.EXPECT().Search(userID, "anahtar", 1, 20).Return([]repository.ConversationMatch{
+		{Conversation: byMessage, MessageID: &messageID, MessageAt: &sentAt, Snippet: &snippet},
+		{Conversation: byName},
+	}, int64(2), nil)
+
+	results, total, err := svc.SearchConversations(userID, " anahtar ", 1, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 2 || len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d of %d", len(results), total)
+	}
+	match := results[0].Message
+	if match == nil || match.ID != messageID || match.Snippet != snippet {
+		t.Fatalf("unexpected message match %+v", match)
+	}
+	if results[1].Message != nil {
+		t.Fatalf("expected a name-only match, got %+v", results[1].Message)
+	}
+
+	at, id, err := decodeMessageCursor(match.Cursor)
+	if err != nil || !at.Equal(sentAt) || id != messageID {
+		t.Fatalf("cursor decoded to %s %s (%v)", at, id, err)
+	}
+}
+
+func TestGetMessagesFrom(t *testing.T) {
+	svc, conversations, messages, _ := newTestChatService(t)
+	userID := uuid.New()
+	conv := &models.Conversation{ID: uuid.New(), CustomerID: userID, YandasID: uuid.New()}
+
+	if _, _, err := svc.GetMessagesFrom(userID, conv.ID, "not a cursor", 2); err != ErrInvalidCursor {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+
+	now := time.Now().Truncate(time.Microsecond)
+	page := []models.Message{
+		{ID: uuid.New(), CreatedAt: now},
+		{ID: uuid.New(), CreatedAt: now.Add(-time.Minute)},
+		{ID: uuid.New(), CreatedAt: now.Add(-2 * time.Minute)},
+	}
+	conversations.EXPECT().GetByID(conv.ID).Return(conv, nil).Times(2)
+	messages.EXPECT().ListUpTo(conv.ID, gomock.Any(), page[0].ID, 3).Return(page, nil)
+
+	msgs, next, err := svc.GetMessagesFrom(userID, conv.ID, encodeMessageCursor(page[0].CreatedAt, page[0].ID), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(msgs) != 2 || next != encodeMessageCursor(page[2].CreatedAt, page[2].ID) {
+		t.Fatalf("expected two messages and a cursor at the third, got %d and %q", len(msgs), next)
+	}
+
+	// The start of the conversation has no further cursor
+	messages.EXPECT().ListUpTo(conv.ID, gomock.Any(), page[2].ID, 3).Return(page[2:], nil)
+	if msgs, next, err = svc.GetMessagesFrom(userID, conv.ID, next, 2); err != nil || len(msgs) != 1 || next != "" {
+		t.Fatalf("expected the last message without a cursor, got %d, %q, %v", len(msgs), next, err)
+	}
+}
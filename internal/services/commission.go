@@ -0,0 +1,26 @@
+package services
+
+import (
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+// commissionRateFor resolves the platform's cut for an order. A category override
+// replaces the global rate; a paid plan's reduced rate applies when it is lower.
+func commissionRateFor(globalRate float64, category *models.Category, ent Entitlements) float64 {
+	rate := globalRate
+	if category != nil && category.CommissionRate != nil {
+		rate = *category.CommissionRate
+	}
+	if ent.Plan != "free" && ent.CommissionRate < rate {
+		rate = ent.CommissionRate
+	}
+	return rate
+}
+
+// splitEarnings divides an order total into the platform fee and the yandaş's
+// net earnings; the two always add up to the total
+func splitEarnings(amount currency.Money, rate float64) (fee, net currency.Money) {
+	fee = amount.Mul(rate)
+	return fee, amount - fee
+}
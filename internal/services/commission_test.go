@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/yandas/backend/internal/models"
+)
+
+func TestCommissionRateFor(t *testing.T) {
+	override := 0.05
+	high := 0.20
+
+	cases := []struct {
+		name     string
+		category *models.Category
+		ent      Entitlements
+		want     float64
+	}{
+		{"global", nil, PlanEntitlements["free"], 0.12},
+		{"category override", &models.Category{CommissionRate: &high}, PlanEntitlements["free"], 0.20},
+		{"plan beats global", nil, PlanEntitlements["yearly"], 0.08},
+		{"plan beats category", &models.Category{CommissionRate: &high}, PlanEntitlements["monthly"], 0.10},
+		{"lower category wins over plan", &models.Category{CommissionRate: &override}, PlanEntitlements["monthly"], 0.05},
+	}
+
+	for _, tc := range cases {
+		if got := commissionRateFor(0.12, tc.category, tc.ent); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSplitEarnings(t *testing.T) {
+	fee, net := splitEarnings(33333, 0.15)
+	if fee != 5000 || net != 28333 {
+		t.Errorf("got fee %v net %v", fee, net)
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// Users who accepted the current set are remembered this long
+const consentedCacheTTL = 24 * time.Hour
+
+var (
+	ErrConsentRequired   = validationError("consent_required", "the current terms and policies must be accepted")
+	ErrConsentNotCurrent = validationError("consent_not_current", "only the current version of a document can be accepted")
+)
+
+// ConsentService records who accepted which version of the legal documents.
+// Every required document must be accepted: at registration, and again by
+// everyone when a new version requiring consent is published.
+type ConsentService struct {
+	repos *repository.Repositories
+	redis *redis.Client
+	legal *LegalService
+}
+
+// NewConsentService creates a consent service; without Redis every check hits the database
+func NewConsentService(repos *repository.Repositories, redis *redis.Client, legal *LegalService) *ConsentService {
+	return &ConsentService{repos: repos, redis: redis, legal: legal}
+}
+
+// AcceptConsentInput lists the document versions a user accepts
+type AcceptConsentInput struct {
+	DocumentIDs []uuid.UUID `json:"document_ids" binding:"required,min=1"`
+
+	Client ClientInfo `json:"-"`
+}
+
+// Required returns the versions every user must have accepted. A nil service
+// requires none.
+func (s *ConsentService) Required() ([]models.LegalDocument, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return s.legal.Required()
+}
+
+// Pending returns the required documents the user has not accepted yet
+func (s *ConsentService) Pending(userID uuid.UUID) ([]models.LegalDocument, error) {
+	current, err := s.Required()
+	if err != nil || len(current) == 0 {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(current))
+	for i := range current {
+		ids[i] = current[i].ID
+	}
+	accepted, err := s.repos.Consent.AcceptedIDs(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return withoutDocuments(current, accepted), nil
+}
+
+// NeedsConsent reports whether the user still has to accept a current
+// document. Lookup failures let the user through rather than lock everyone out.
+func (s *ConsentService) NeedsConsent(userID uuid.UUID) bool {
+	current, err := s.Required()
+	if err != nil {
+		log.Printf("[CONSENT] failed to load required documents: %v", err)
+		return false
+	}
+	if len(current) == 0 {
+		return false
+	}
+
+	ctx := context.Background()
+	key := consentedKey(userID, current)
+	if s.redis != nil {
+		if n, err := s.redis.Exists(ctx, key).Result(); err == nil && n > 0 {
+			return false
+		}
+	}
+
+	pending, err := s.Pending(userID)
+	if err != nil {
+		log.Printf("[CONSENT] failed to check consents of %s: %v", userID, err)
+		return false
+	}
+	if len(pending) == 0 && s.redis != nil {
+		s.redis.Set(ctx, key, 1, consentedCacheTTL)
+	}
+	return len(pending) > 0
+}
+
+// Accept records the user accepting required documents and returns the ones
+// still pending
+func (s *ConsentService) Accept(userID uuid.UUID, input *AcceptConsentInput) ([]models.LegalDocument, error) {
+	current, err := s.Required()
+	if err != nil {
+		return nil, err
+	}
+	consents, err := consentRecords(userID, current, input.DocumentIDs, input.Client)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repos.Consent.Accept(consents); err != nil {
+		return nil, err
+	}
+
+	// The acceptances were just written; read them back from the primary
+	ids := make([]uuid.UUID, len(current))
+	for i := range current {
+		ids[i] = current[i].ID
+	}
+	accepted, err := s.repos.OnPrimary().Consent.AcceptedIDs(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	pending := withoutDocuments(current, accepted)
+	if len(pending) == 0 && s.redis != nil {
+		s.redis.Set(context.Background(), consentedKey(userID, current), 1, consentedCacheTTL)
+	}
+	return pending, nil
+}
+
+// History returns every version the user accepted
+func (s *ConsentService) History(userID uuid.UUID) ([]models.UserConsent, error) {
+	return s.repos.Consent.ListByUser(userID)
+}
+
+// consentRecords builds the acceptances of documentIDs, which must all be
+// required documents
+func consentRecords(userID uuid.UUID, current []models.LegalDocument, documentIDs []uuid.UUID, client ClientInfo) ([]models.UserConsent, error) {
+	currentIDs := make(map[uuid.UUID]bool, len(current))
+	for i := range current {
+		currentIDs[current[i].ID] = true
+	}
+	seen := make(map[uuid.UUID]bool, len(documentIDs))
+	consents := make([]models.UserConsent, 0, len(documentIDs))
+	for _, id := range documentIDs {
+		if !currentIDs[id] {
+			return nil, ErrConsentNotCurrent
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		consents = append(consents, models.UserConsent{
+			UserID:     userID,
+			DocumentID: id,
+			IPAddress:  client.IP,
+			UserAgent:  truncate(client.UserAgent, 255),
+		})
+	}
+	return consents, nil
+}
+
+// requireAllAccepted checks that documentIDs cover every required document
+func requireAllAccepted(current []models.LegalDocument, documentIDs []uuid.UUID) error {
+	if len(withoutDocuments(current, documentIDs)) > 0 {
+		return ErrConsentRequired
+	}
+	return nil
+}
+
+func withoutDocuments(documents []models.LegalDocument, ids []uuid.UUID) []models.LegalDocument {
+	drop := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		drop[id] = true
+	}
+	remaining := []models.LegalDocument{}
+	for _, d := range documents {
+		if !drop[d.ID] {
+			remaining = append(remaining, d)
+		}
+	}
+	return remaining
+}
+
+// consentedKey marks a user as having accepted exactly the current versions,
+// so publishing a new one moves every user to a new key
+func consentedKey(userID uuid.UUID, current []models.LegalDocument) string {
+	versions := make([]string, len(current))
+	for i, d := range current {
+		versions[i] = d.Kind + "." + strconv.Itoa(d.Version)
+	}
+	sort.Strings(versions)
+	return "consented:" + userID.String() + ":" + strings.Join(versions, ",")
+}
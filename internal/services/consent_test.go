@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func newTestConsentService(t *testing.T) (*ConsentService, *mocks.MockConsentRepository, *mocks.MockLegalDocumentRepository) {
+	ctrl := gomock.NewController(t)
+	consents := mocks.NewMockConsentRepository(ctrl)
+	documents := mocks.NewMockLegalDocumentRepository(ctrl)
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	repos := &repository.Repositories{Consent: consents, LegalDocument: documents}
+	return NewConsentService(repos, client, NewLegalService(repos, client)), consents, documents
+}
+
+func TestConsentAcceptOnlyRequiredVersions(t *testing.T) {
+	svc, consents, documents := newTestConsentService(t)
+	userID := uuid.New()
+	terms := models.LegalDocument{ID: uuid.New(), Kind: LegalTerms, Version: 2}
+	privacy := models.LegalDocument{ID: uuid.New(), Kind: LegalPrivacy, Version: 1}
+	documents.EXPECT().Required(DefaultLegalLocale).Return([]models.LegalDocument{terms, privacy}, nil)
+
+	// An old version is refused
+	_, err := svc.Accept(userID, &AcceptConsentInput{DocumentIDs: []uuid.UUID{uuid.New()}})
+	if !errors.Is(err, ErrConsentNotCurrent) {
+		t.Fatalf("expected ErrConsentNotCurrent, got %v", err)
+	}
+
+	consents.EXPECT().Accept(gomock.Any()).DoAndReturn(func(records []models.UserConsent) error {
+		if len(records) != 1 || records[0].DocumentID != terms.ID || records[0].IPAddress != "203.0.113.7" {
+			t.Errorf("unexpected consents %+v", records)
+		}
+		return nil
+	})
+	consents.EXPECT().AcceptedIDs(userID, gomock.Any()).Return([]uuid.UUID{terms.ID}, nil)
+
+	pending, err := svc.Accept(userID, &AcceptConsentInput{
+		DocumentIDs: []uuid.UUID{terms.ID, terms.ID},
+		Client:      ClientInfo{IP: "203.0.113.7"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].ID != privacy.ID {
+		t.Fatalf("expected the privacy policy to stay pending, got %+v", pending)
+	}
+}
+
+func TestNeedsConsentFollowsPublishedVersions(t *testing.T) {
+	svc, consents, documents := newTestConsentService(t)
+	userID := uuid.New()
+	v1 := models.LegalDocument{ID: uuid.New(), Kind: LegalTerms, Locale: DefaultLegalLocale, Version: 1, RequiresConsent: true}
+
+	documents.EXPECT().Required(DefaultLegalLocale).Return([]models.LegalDocument{v1}, nil)
+	consents.EXPECT().AcceptedIDs(userID, []uuid.UUID{v1.ID}).Return([]uuid.UUID{v1.ID}, nil).Times(1)
+	if svc.NeedsConsent(userID) {
+		t.Fatal("expected a user who accepted the current terms to go through")
+	}
+	// Answered from the cache from now on
+	if svc.NeedsConsent(userID) {
+		t.Fatal("expected the cached answer")
+	}
+
+	// Publishing a new version asks again
+	var v2 models.LegalDocument
+	documents.EXPECT().Publish(gomock.Any()).DoAndReturn(func(d *models.LegalDocument) error {
+		d.ID, d.Version = uuid.New(), 2
+		v2 = *d
+		return nil
+	})
+	if _, err := svc.legal.Publish(uuid.New(), &LegalDocumentInput{Kind: LegalTerms, Title: "Kullanım Koşulları", Content: "..."}); err != nil {
+		t.Fatal(err)
+	}
+	if !v2.RequiresConsent || v2.Locale != DefaultLegalLocale {
+		t.Fatalf("expected a Turkish version requiring consent, got %+v", v2)
+	}
+	documents.EXPECT().Required(DefaultLegalLocale).DoAndReturn(func(string) ([]models.LegalDocument, error) {
+		return []models.LegalDocument{v2}, nil
+	})
+	consents.EXPECT().AcceptedIDs(userID, gomock.Any()).Return(nil, nil)
+	if !svc.NeedsConsent(userID) {
+		t.Fatal("expected the new version to need consent")
+	}
+}
+
+func TestRequireAllAccepted(t *testing.T) {
+	terms := models.LegalDocument{ID: uuid.New(), Kind: LegalTerms}
+	kvkk := models.LegalDocument{ID: uuid.New(), Kind: LegalKVKK}
+
+	if err := requireAllAccepted(nil, nil); err != nil {
+		t.Errorf("expected nothing to accept before a document is published, got %v", err)
+	}
+	if err := requireAllAccepted([]models.LegalDocument{terms, kvkk}, []uuid.UUID{terms.ID}); !errors.Is(err, ErrConsentRequired) {
+		t.Errorf("expected ErrConsentRequired, got %v", err)
+	}
+	if err := requireAllAccepted([]models.LegalDocument{terms, kvkk}, []uuid.UUID{kvkk.ID, terms.ID}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
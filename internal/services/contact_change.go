@@ -0,0 +1,277 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/auth"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	ContactChannelEmail = "email"
+	ContactChannelPhone = "phone"
+
+	contactChangeTTL         = 15 * time.Minute
+	maxContactChangeAttempts = 5
+)
+
+var (
+	ErrIncorrectPassword   = unauthorizedError("incorrect_password", "current password is incorrect")
+	ErrNoPendingChange     = notFoundError("no_pending_change", "no pending change to confirm")
+	ErrContactUnchanged    = validationError("contact_unchanged", "new value matches the current one")
+	ErrContactAlreadyInUse = conflictError("contact_already_in_use", "already in use by another account")
+
+	ErrPhoneChangeNeedsVerification = validationError("phone_change_needs_verification", "phone number changes must be verified via POST /user/me/phone")
+)
+
+// EmailChangeInput represents an email change request
+type EmailChangeInput struct {
+	NewEmail        string `json:"new_email" binding:"required,email"`
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+// RequestEmailChange checks the user's password and sends a verification code to the
+// new address. The change is kept pending until ConfirmEmailChange succeeds.
+func (s *AuthService) RequestEmailChange(userID uuid.UUID, input *EmailChangeInput) (*models.ContactChange, error) {
+	user, err := s.repos.OnPrimary().User.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.CurrentPassword)); err != nil {
+		return nil, ErrIncorrectPassword
+	}
+
+	newEmail := strings.ToLower(strings.TrimSpace(input.NewEmail))
+	if user.Email != nil && strings.EqualFold(*user.Email, newEmail) {
+		return nil, ErrContactUnchanged
+	}
+	if s.repos.User.ExistsByEmail(newEmail) {
+		return nil, ErrContactAlreadyInUse
+	}
+
+	// Each request replaces the code and its attempts, so it is limited like any OTP send
+	if err := s.otpLimits.allowSend(OTPChannelEmail, newEmail); err != nil {
+		return nil, err
+	}
+	code, err := generateOTP()
+	if err != nil {
+		return nil, err
+	}
+	change := &models.ContactChange{
+		UserID:    user.ID,
+		Channel:   ContactChannelEmail,
+		NewValue:  newEmail,
+		CodeHash:  hashToken(code),
+		ExpiresAt: time.Now().Add(contactChangeTTL),
+		CreatedAt: time.Now(),
+	}
+	if err := s.repos.ContactChange.Save(change); err != nil {
+		return nil, err
+	}
+
+	if s.emailSvc != nil {
+		if err := s.emailSvc.SendOTPEmail(newEmail, code, user.FullName); err != nil {
+			s.monitoring.Record(MetricOTPFailures)
+			return nil, err
+		}
+	} else if s.cfg.GinMode != "release" {
+		// Codes are only logged in development, where no mail is sent
+		log.Printf("[FALLBACK] Email change code for %s: %s\n", newEmail, code)
+	} else {
+		log.Printf("[FALLBACK] Email unavailable, the change code for %s was not sent\n", newEmail)
+	}
+
+	return change, nil
+}
+
+// ConfirmEmailChange applies a pending email change once the code sent to the new
+// address is verified. Every existing session is signed out and the confirming
+// device receives a fresh token pair.
+func (s *AuthService) ConfirmEmailChange(userID uuid.UUID, code, platform string, client ClientInfo) (*models.User, *auth.TokenPair, error) {
+	change, err := s.verifyContactChange(userID, ContactChannelEmail, code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var user *models.User
+	var oldEmail string
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		u, err := tx.User.GetByID(userID)
+		if err != nil {
+			return ErrUserNotFound
+		}
+		if tx.User.ExistsByEmail(change.NewValue) {
+			return ErrContactAlreadyInUse
+		}
+
+		if u.Email != nil {
+			oldEmail = *u.Email
+		}
+		newEmail := change.NewValue
+		u.Email = &newEmail
+		// The new address was just proven to receive mail
+		u.EmailUndeliverableAt = nil
+		u.EmailUndeliverableReason = nil
+		// Access tokens issued before the change stop working at once, not when they expire
+		u.TokenVersion++
+		if err := tx.User.Update(u); err != nil {
+			return err
+		}
+		user = u
+		return tx.ContactChange.Delete(change.ID)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	s.tokenVersions.Invalidate(userID)
+
+	if oldEmail != "" {
+		s.jobs.Enqueue(JobSendSecurityEmail, emailJob{
+			Email:   oldEmail,
+			Name:    user.FullName,
+			Message: "Hesabınıza bağlı e-posta adresi " + change.NewValue + " olarak değiştirildi.",
+		})
+	}
+
+	if err := s.revokeAllSessions(userID); err != nil {
+		return nil, nil, err
+	}
+	tokens, err := s.startSession(user, platform, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// PhoneChangeInput represents a phone number change request
+type PhoneChangeInput struct {
+	NewPhone        string `json:"new_phone" binding:"required,tr_phone"`
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+// RequestPhoneChange checks the user's password and sends an OTP to the new number.
+// The change is kept pending until ConfirmPhoneChange succeeds.
+func (s *AuthService) RequestPhoneChange(userID uuid.UUID, input *PhoneChangeInput) (*models.ContactChange, error) {
+	user, err := s.repos.OnPrimary().User.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.CurrentPassword)); err != nil {
+		return nil, ErrIncorrectPassword
+	}
+
+	newPhone := normalizePhone(input.NewPhone)
+	if user.Phone != nil && normalizePhone(*user.Phone) == newPhone {
+		return nil, ErrContactUnchanged
+	}
+	if s.phoneInUse(input.NewPhone, newPhone) {
+		return nil, ErrContactAlreadyInUse
+	}
+
+	// The code itself is held by the SMS provider's verification or in Redis, so none is stored here
+	change := &models.ContactChange{
+		UserID:    user.ID,
+		Channel:   ContactChannelPhone,
+		NewValue:  newPhone,
+		ExpiresAt: time.Now().Add(contactChangeTTL),
+		CreatedAt: time.Now(),
+	}
+	// Send first, so a refused resend leaves the previous pending change in place
+	if err := s.SendOTP(newPhone); err != nil {
+		return nil, err
+	}
+	if err := s.repos.ContactChange.Save(change); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// ConfirmPhoneChange applies a pending phone change once the OTP sent to the new number
+// is verified, then tells the old number and the account email about the change.
+func (s *AuthService) ConfirmPhoneChange(userID uuid.UUID, code string) (*models.User, error) {
+	change, err := s.verifyContactChange(userID, ContactChannelPhone, code)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *models.User
+	var oldPhone string
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		u, err := tx.User.GetByID(userID)
+		if err != nil {
+			return ErrUserNotFound
+		}
+		if tx.User.ExistsByPhone(change.NewValue) {
+			return ErrContactAlreadyInUse
+		}
+
+		if u.Phone != nil {
+			oldPhone = *u.Phone
+		}
+		newPhone := change.NewValue
+		u.Phone = &newPhone
+		if err := tx.User.Update(u); err != nil {
+			return err
+		}
+		user = u
+		return tx.ContactChange.Delete(change.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := "Hesabınıza bağlı telefon numarası " + change.NewValue + " olarak değiştirildi."
+	if oldPhone != "" {
+		s.jobs.Enqueue(JobSendSMS, smsJob{
+			Phone: oldPhone,
+			Body:  "YANDAŞ: " + message + " Bu işlemi siz yapmadıysanız destek ekibimizle iletişime geçin.",
+		})
+	}
+	if user.Email != nil {
+		s.jobs.Enqueue(JobSendSecurityEmail, emailJob{Email: *user.Email, Name: user.FullName, Message: message})
+	}
+	return user, nil
+}
+
+// phoneInUse checks both the number as typed and its E.164 form, since older
+// accounts stored numbers as entered at registration
+func (s *AuthService) phoneInUse(raw, normalized string) bool {
+	if s.repos.User.ExistsByPhone(normalized) {
+		return true
+	}
+	return raw != normalized && s.repos.User.ExistsByPhone(raw)
+}
+
+// verifyContactChange checks a code against the pending change on a channel; phone
+// codes are checked with the OTP provider. Expired changes and changes with too
+// many wrong guesses are discarded.
+func (s *AuthService) verifyContactChange(userID uuid.UUID, channel, code string) (*models.ContactChange, error) {
+	change, err := s.repos.OnPrimary().ContactChange.Get(userID, channel)
+	if err != nil {
+		return nil, ErrNoPendingChange
+	}
+	if time.Now().After(change.ExpiresAt) || change.Attempts >= maxContactChangeAttempts {
+		s.repos.ContactChange.Delete(change.ID)
+		return nil, ErrInvalidOTP
+	}
+
+	valid := change.CodeHash == hashToken(code)
+	if change.CodeHash == "" {
+		err := s.VerifyOTP(change.NewValue, code)
+		if errors.Is(err, ErrOTPRateLimited) {
+			return nil, err
+		}
+		valid = err == nil
+	}
+	if !valid {
+		s.repos.ContactChange.IncrementAttempts(change.ID)
+		return nil, ErrInvalidOTP
+	}
+	return change, nil
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+type contactChangeMocks struct {
+	users    *mocks.MockUserRepository
+	changes  *mocks.MockContactChangeRepository
+	sessions *mocks.MockSessionRepository
+	devices  *mocks.MockDeviceTokenRepository
+}
+
+func newTestContactChangeService(t *testing.T) (*AuthService, *contactChangeMocks) {
+	ctrl := gomock.NewController(t)
+	m := &contactChangeMocks{
+		users:    mocks.NewMockUserRepository(ctrl),
+		changes:  mocks.NewMockContactChangeRepository(ctrl),
+		sessions: mocks.NewMockSessionRepository(ctrl),
+		devices:  mocks.NewMockDeviceTokenRepository(ctrl),
+	}
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{
+		User:          m.users,
+		ContactChange: m.changes,
+		Session:       m.sessions,
+		DeviceToken:   m.devices,
+		UnitOfWork:    uow,
+	}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	cfg := &config.Config{
+		JWTSecret:        "test-secret",
+		JWTAccessExpiry:  15 * time.Minute,
+		JWTRefreshExpiry: 24 * time.Hour,
+	}
+	return NewAuthService(repos, cfg, nil, nil, nil, nil, nil, nil, nil, nil, nil), m
+}
+
+func TestRequestEmailChange(t *testing.T) {
+	user := testUser(t, "secret1")
+
+	tests := []struct {
+		name    string
+		input   EmailChangeInput
+		setup   func(m *contactChangeMocks)
+		wantErr error
+	}{
+		{
+			name:    "wrong password",
+			input:   EmailChangeInput{NewEmail: "yeni@example.com", CurrentPassword: "wrong"},
+			wantErr: ErrIncorrectPassword,
+		},
+		{
+			name:    "same address",
+			input:   EmailChangeInput{NewEmail: "AYSE@example.com", CurrentPassword: "secret1"},
+			wantErr: ErrContactUnchanged,
+		},
+		{
+			name:  "address taken",
+			input: EmailChangeInput{NewEmail: "yeni@example.com", CurrentPassword: "secret1"},
+			setup: func(m *contactChangeMocks) {
+				m.users.EXPECT().ExistsByEmail("yeni@example.com").Return(true)
+			},
+			wantErr: ErrContactAlreadyInUse,
+		},
+		{
+			name:  "success",
+			input: EmailChangeInput{NewEmail: " Yeni@Example.com ", CurrentPassword: "secret1"},
+			setup: func(m *contactChangeMocks) {
+				m.users.EXPECT().ExistsByEmail("yeni@example.com").Return(false)
+				m.changes.EXPECT().Save(gomock.Any()).DoAndReturn(func(c *models.ContactChange) error {
+					if c.Channel != ContactChannelEmail || c.NewValue != "yeni@example.com" || c.CodeHash == "" {
+						t.Errorf("unexpected pending change: %+v", c)
+					}
+					return nil
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestContactChangeService(t)
+			m.users.EXPECT().GetByID(user.ID).Return(user, nil)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+
+			_, err := svc.RequestEmailChange(user.ID, &tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRequestEmailChangeLimitsSends(t *testing.T) {
+	svc, m := newTestContactChangeService(t)
+	mr := miniredis.RunT(t)
+	svc.otpLimits = otpLimiter{redis: redis.NewClient(&redis.Options{Addr: mr.Addr()})}
+	user := testUser(t, "secret1")
+	m.users.EXPECT().GetByID(user.ID).Return(user, nil).Times(2)
+	m.users.EXPECT().ExistsByEmail("yeni@example.com").Return(false).Times(2)
+	m.changes.EXPECT().Save(gomock.Any()).Return(nil)
+
+	input := EmailChangeInput{NewEmail: "yeni@example.com", CurrentPassword: "secret1"}
+	if _, err := svc.RequestEmailChange(user.ID, &input); err != nil {
+		t.Fatal(err)
+	}
+	// A new code right away would reset the attempts on the pending one
+	input.NewEmail = "YENI@example.com"
+	if _, err := svc.RequestEmailChange(user.ID, &input); limitReason(err) != OTPLimitCooldown {
+		t.Fatalf("expected the resend cooldown, got %v", err)
+	}
+}
+
+func TestConfirmEmailChange(t *testing.T) {
+	svc, m := newTestContactChangeService(t)
+	user := testUser(t, "secret1")
+	change := &models.ContactChange{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Channel:   ContactChannelEmail,
+		NewValue:  "yeni@example.com",
+		CodeHash:  hashToken("123456"),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	m.changes.EXPECT().Get(user.ID, ContactChannelEmail).Return(change, nil)
+	m.changes.EXPECT().IncrementAttempts(change.ID).Return(nil)
+	if _, _, err := svc.ConfirmEmailChange(user.ID, "000000", "ios", ClientInfo{}); !errors.Is(err, ErrInvalidOTP) {
+		t.Fatalf("expected ErrInvalidOTP, got %v", err)
+	}
+
+	oldSession := uuid.New()
+	m.changes.EXPECT().Get(user.ID, ContactChannelEmail).Return(change, nil)
+	m.users.EXPECT().GetByID(user.ID).Return(user, nil)
+	m.users.EXPECT().ExistsByEmail("yeni@example.com").Return(false)
+	m.users.EXPECT().Update(user).Return(nil)
+	m.changes.EXPECT().Delete(change.ID).Return(nil)
+	m.sessions.EXPECT().RevokeAllForUser(user.ID).Return([]uuid.UUID{oldSession}, nil)
+	m.devices.EXPECT().DeactivateForSession(oldSession).Return(nil)
+	m.sessions.EXPECT().Create(gomock.Any()).Return(nil)
+
+	updated, tokens, err := svc.ConfirmEmailChange(user.ID, "123456", "ios", ClientInfo{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *updated.Email != "yeni@example.com" || tokens.AccessToken == "" {
+		t.Errorf("unexpected result: %+v %+v", updated, tokens)
+	}
+	if updated.TokenVersion != 1 {
+		t.Errorf("expected the token version bumped, got %d", updated.TokenVersion)
+	}
+}
+
+func TestGenerateOTP(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 20; i++ {
+		code, err := generateOTP()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(code) != 6 || strings.Trim(code, "0123456789") != "" {
+			t.Fatalf("expected six digits, got %q", code)
+		}
+		seen[code] = true
+	}
+	if len(seen) < 2 {
+		t.Error("expected the codes to vary")
+	}
+}
+
+func TestConfirmEmailChangeTooManyAttempts(t *testing.T) {
+	svc, m := newTestContactChangeService(t)
+	userID := uuid.New()
+	change := &models.ContactChange{
+		ID:        uuid.New(),
+		UserID:    userID,
+		CodeHash:  hashToken("123456"),
+		Attempts:  maxContactChangeAttempts,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	m.changes.EXPECT().Get(userID, ContactChannelEmail).Return(change, nil)
+	m.changes.EXPECT().Delete(change.ID).Return(nil)
+	if _, _, err := svc.ConfirmEmailChange(userID, "123456", "ios", ClientInfo{}); !errors.Is(err, ErrInvalidOTP) {
+		t.Fatalf("expected ErrInvalidOTP, got %v", err)
+	}
+}
+
+func TestRequestPhoneChange(t *testing.T) {
+	user := testUser(t, "secret1")
+	current := "05321234567"
+	user.Phone = &current
+
+	tests := []struct {
+		name    string
+		phone   string
+		setup   func(m *contactChangeMocks)
+		wantErr error
+	}{
+		{name: "same number in another format", phone: "+90 532 123 45 67", wantErr: ErrContactUnchanged},
+		{
+			name:  "number taken",
+			phone: "0533 765 43 21",
+			setup: func(m *contactChangeMocks) {
+				m.users.EXPECT().ExistsByPhone("+905337654321").Return(false)
+				m.users.EXPECT().ExistsByPhone("0533 765 43 21").Return(true)
+			},
+			wantErr: ErrContactAlreadyInUse,
+		},
+		{
+			name:  "success",
+			phone: "+905337654321",
+			setup: func(m *contactChangeMocks) {
+				m.users.EXPECT().ExistsByPhone("+905337654321").Return(false)
+				m.changes.EXPECT().Save(gomock.Any()).DoAndReturn(func(c *models.ContactChange) error {
+					if c.Channel != ContactChannelPhone || c.NewValue != "+905337654321" || c.CodeHash != "" {
+						t.Errorf("unexpected pending change: %+v", c)
+					}
+					return nil
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestContactChangeService(t)
+			m.users.EXPECT().GetByID(user.ID).Return(user, nil)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+
+			_, err := svc.RequestPhoneChange(user.ID, &PhoneChangeInput{NewPhone: tt.phone, CurrentPassword: "secret1"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfirmPhoneChangeWrongCode(t *testing.T) {
+	svc, m := newTestContactChangeService(t)
+	userID := uuid.New()
+	change := &models.ContactChange{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Channel:   ContactChannelPhone,
+		NewValue:  "+905337654321",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	m.changes.EXPECT().Get(userID, ContactChannelPhone).Return(change, nil)
+	m.changes.EXPECT().IncrementAttempts(change.ID).Return(nil)
+	if _, err := svc.ConfirmPhoneChange(userID, "123456"); !errors.Is(err, ErrInvalidOTP) {
+		t.Fatalf("expected ErrInvalidOTP, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/export"
+)
+
+// Conversation review errors. Staff may only read a conversation that a
+// dispute or an open support ticket is about.
+var (
+	ErrConversationNotFound   = notFoundError("conversation_not_found", "conversation not found")
+	ErrConversationNotFlagged = unauthorizedError("conversation_not_flagged", "conversation is not under a dispute or support ticket")
+)
+
+// ConversationReview is a page of a flagged conversation's messages, with
+// the disputes and tickets that allow reading it
+type ConversationReview struct {
+	Conversation *models.Conversation          `json:"conversation"`
+	Flags        *repository.ConversationFlags `json:"flags"`
+	Messages     []models.Message              `json:"messages"`
+}
+
+// flaggedConversation loads a conversation staff may read. Chats are private,
+// so browsing is refused unless a dispute or an open ticket concerns its orders.
+func (s *AdminService) flaggedConversation(convID uuid.UUID) (*models.Conversation, *repository.ConversationFlags, error) {
+	conv, err := s.repos.Conversation.GetByID(convID)
+	if err != nil {
+		return nil, nil, ErrConversationNotFound
+	}
+	flags, err := s.repos.Conversation.GetFlags(conv)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !flags.Flagged() {
+		return nil, nil, ErrConversationNotFlagged
+	}
+	return conv, flags, nil
+}
+
+// ConversationFlags returns why staff may read a conversation, or
+// ErrConversationNotFlagged when nothing allows it
+func (s *AdminService) ConversationFlags(convID uuid.UUID) (*repository.ConversationFlags, error) {
+	_, flags, err := s.flaggedConversation(convID)
+	return flags, err
+}
+
+// ReviewConversation returns a page of a flagged conversation's messages,
+// newest first. Every read is audit-logged.
+func (s *AdminService) ReviewConversation(convID, adminID uuid.UUID, page, limit int) (*ConversationReview, int64, error) {
+	conv, flags, err := s.flaggedConversation(convID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	messages, total, err := s.repos.Message.GetByConversation(convID, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.logAction(adminID, "view_conversation", "conversation", convID, nil, map[string]interface{}{
+		"page":            page,
+		"disputed_orders": flags.DisputedOrders,
+		"support_tickets": flags.SupportTickets,
+	})
+
+	return &ConversationReview{Conversation: conv, Flags: flags, Messages: messages}, total, nil
+}
+
+// ExportConversationTranscript writes a flagged conversation's full transcript
+// to w, oldest message first, for dispute resolution. The export is
+// audit-logged before any message is written.
+func (s *AdminService) ExportConversationTranscript(convID, adminID uuid.UUID, w export.Writer) error {
+	conv, flags, err := s.flaggedConversation(convID)
+	if err != nil {
+		return err
+	}
+
+	s.logAction(adminID, "export_conversation", "conversation", convID, nil, map[string]interface{}{
+		"disputed_orders": flags.DisputedOrders,
+		"support_tickets": flags.SupportTickets,
+	})
+
+	if err := w.WriteRow([]string{"sent_at", "sender", "sender_role", "type", "content", "media_url"}); err != nil {
+		return err
+	}
+	err = s.repos.Message.StreamByConversation(convID, func(messages []models.Message) error {
+		for _, m := range messages {
+			sender, role := "", "system"
+			if m.Sender != nil {
+				sender = m.Sender.FullName
+			}
+			switch {
+			case m.MessageType == "system":
+			case m.SenderID == conv.CustomerID:
+				role = "customer"
+			case m.SenderID == conv.YandasID:
+				role = "yandas"
+			default:
+				role = "staff"
+			}
+			row := []string{formatTime(&m.CreatedAt), sender, role, m.MessageType, m.Content, deref(m.MediaURL)}
+			if err := w.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return w.Close()
+}
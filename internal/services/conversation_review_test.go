@@ -0,0 +1,107 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/export"
+)
+
+type reviewMocks struct {
+	conversations *mocks.MockConversationRepository
+	messages      *mocks.MockMessageRepository
+	audit         *mocks.MockAuditLogRepository
+}
+
+func newTestConversationReview(t *testing.T) (*AdminService, *reviewMocks) {
+	ctrl := gomock.NewController(t)
+	m := &reviewMocks{
+		conversations: mocks.NewMockConversationRepository(ctrl),
+		messages:      mocks.NewMockMessageRepository(ctrl),
+		audit:         mocks.NewMockAuditLogRepository(ctrl),
+	}
+	repos := &repository.Repositories{Conversation: m.conversations, Message: m.messages, AuditLog: m.audit}
+	return NewAdminService(repos, nil, nil, nil, nil, nil, nil), m
+}
+
+func TestReviewConversationRequiresFlag(t *testing.T) {
+	svc, m := newTestConversationReview(t)
+	conv := &models.Conversation{ID: uuid.New(), CustomerID: uuid.New(), YandasID: uuid.New()}
+
+	// Nothing concerns the conversation: no messages are read and nothing is logged
+	m.conversations.EXPECT().GetByID(conv.ID).Return(conv, nil)
+	m.conversations.EXPECT().GetFlags(conv).Return(&repository.ConversationFlags{}, nil)
+	if _, _, err := svc.ReviewConversation(conv.ID, uuid.New(), 1, 20); err != ErrConversationNotFlagged {
+		t.Fatalf("expected ErrConversationNotFlagged, got %v", err)
+	}
+
+	disputed := uuid.New()
+	adminID := uuid.New()
+	m.conversations.EXPECT().GetByID(conv.ID).Return(conv, nil)
+	m.conversations.EXPECT().GetFlags(conv).Return(&repository.ConversationFlags{DisputedOrders: []uuid.UUID{disputed}}, nil)
+	m.messages.EXPECT().GetByConversation(conv.ID, 1, 20).Return([]models.Message{{ID: uuid.New()}}, int64(1), nil)
+	m.audit.EXPECT().Create(gomock.Any()).DoAndReturn(func(entry *models.AuditLog) error {
+		if entry.AdminID != adminID || entry.Action != "view_conversation" || *entry.EntityID != conv.ID || !strings.Contains(*entry.NewValues, disputed.String()) {
+			t.Errorf("unexpected audit entry %+v", entry)
+		}
+		return nil
+	})
+
+	review, total, err := svc.ReviewConversation(conv.ID, adminID, 1, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 1 || len(review.Messages) != 1 || len(review.Flags.DisputedOrders) != 1 {
+		t.Fatalf("unexpected review %+v", review)
+	}
+}
+
+func TestExportConversationTranscript(t *testing.T) {
+	svc, m := newTestConversationReview(t)
+	customer := &models.User{ID: uuid.New(), FullName: "Ayşe Yılmaz"}
+	yandas := &models.User{ID: uuid.New(), FullName: "Mehmet Demir"}
+	conv := &models.Conversation{ID: uuid.New(), CustomerID: customer.ID, YandasID: yandas.ID}
+	sent := time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC)
+
+	m.conversations.EXPECT().GetByID(conv.ID).Return(conv, nil)
+	m.conversations.EXPECT().GetFlags(conv).Return(&repository.ConversationFlags{SupportTickets: []uuid.UUID{uuid.New()}}, nil)
+	m.audit.EXPECT().Create(gomock.Any()).DoAndReturn(func(entry *models.AuditLog) error {
+		if entry.Action != "export_conversation" {
+			t.Errorf("unexpected audit action %s", entry.Action)
+		}
+		return nil
+	})
+	m.messages.EXPECT().StreamByConversation(conv.ID, gomock.Any()).DoAndReturn(func(_ uuid.UUID, fn func([]models.Message) error) error {
+		return fn([]models.Message{
+			{SenderID: customer.ID, Sender: customer, MessageType: "text", Content: "Saat kaçta gelirsiniz?", CreatedAt: sent},
+			{SenderID: yandas.ID, Sender: yandas, MessageType: "text", Content: "10:30 gibi", CreatedAt: sent.Add(time.Minute)},
+			{SenderID: yandas.ID, Sender: yandas, MessageType: "system", Content: "#YND-1 kabul edildi.", CreatedAt: sent.Add(2 * time.Minute)},
+		})
+	})
+
+	var out bytes.Buffer
+	w, _ := export.NewCSV(&out)
+	if err := svc.ExportConversationTranscript(conv.ID, uuid.New(), w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(out.String(), "\xEF\xBB\xBF"))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("expected a header and 3 messages, got %d rows", len(rows))
+	}
+	roles := []string{rows[1][2], rows[2][2], rows[3][2]}
+	if strings.Join(roles, ",") != "customer,yandas,system" || rows[1][1] != "Ayşe Yılmaz" || rows[2][4] != "10:30 gibi" {
+		t.Fatalf("unexpected transcript %v", rows)
+	}
+}
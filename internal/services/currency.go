@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+// ExchangeRateInterval is how often exchange rates are fetched. The central
+// bank publishes once per business day, so fetching a few times a day is enough.
+const ExchangeRateInterval = 6 * time.Hour
+
+// CurrencyService lists the accepted currencies and keeps the exchange rates
+// reports are totalled with
+type CurrencyService struct {
+	repos *repository.Repositories
+	rates currency.RateProvider
+}
+
+func NewCurrencyService(repos *repository.Repositories, rates currency.RateProvider) *CurrencyService {
+	return &CurrencyService{repos: repos, rates: rates}
+}
+
+// CurrencyInfo is an accepted currency with its latest rate against the base
+// currency; the base currency and currencies without a fetched rate have none
+type CurrencyInfo struct {
+	currency.Currency
+	Rate     *float64 `json:"rate,omitempty"`
+	RateDate *string  `json:"rate_date,omitempty"`
+}
+
+// List returns the accepted currencies, the base currency first
+func (s *CurrencyService) List() ([]CurrencyInfo, error) {
+	latest, err := s.repos.ExchangeRate.Latest()
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]models.ExchangeRate, len(latest))
+	for _, rate := range latest {
+		byCode[rate.Currency] = rate
+	}
+
+	list := make([]CurrencyInfo, 0, len(currency.Supported()))
+	for _, c := range currency.Supported() {
+		info := CurrencyInfo{Currency: c}
+		if rate, ok := byCode[c.Code]; ok {
+			date := rate.Date.Format("2006-01-02")
+			info.Rate = &rate.Rate
+			info.RateDate = &date
+		}
+		list = append(list, info)
+	}
+	return list, nil
+}
+
+// RefreshRates fetches the latest exchange rates; the scheduler calls it every
+// ExchangeRateInterval. It does nothing when no rate provider is configured.
+func (s *CurrencyService) RefreshRates() {
+	if s.rates == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	latest, err := s.rates.Latest(ctx)
+	if err != nil {
+		log.Printf("[CURRENCY] failed to fetch rates from %s: %v", s.rates.Name(), err)
+		return
+	}
+	rows := make([]models.ExchangeRate, 0, len(latest.Rates))
+	for code, rate := range latest.Rates {
+		rows = append(rows, models.ExchangeRate{Currency: code, Date: latest.Date, Rate: rate, Source: latest.Source})
+	}
+	if err := s.repos.ExchangeRate.Save(rows); err != nil {
+		log.Printf("[CURRENCY] failed to save rates: %v", err)
+	}
+}
+
+// CurrencyAmount is an amount in one currency
+type CurrencyAmount struct {
+	Currency string         `json:"currency"`
+	Amount   currency.Money `json:"amount"`
+}
+
+// RevenueSummary is revenue per currency and its total in the base currency
+type RevenueSummary struct {
+	Currency   string           `json:"currency"` // the base currency of Total
+	Total      currency.Money   `json:"total"`
+	ByCurrency []CurrencyAmount `json:"by_currency"`
+}
+
+// summarizeRevenue totals per-currency revenue in the base currency
+func summarizeRevenue(totals []repository.CurrencyTotal) *RevenueSummary {
+	summary := &RevenueSummary{Currency: currency.Base, ByCurrency: make([]CurrencyAmount, 0, len(totals))}
+	for _, t := range totals {
+		summary.ByCurrency = append(summary.ByCurrency, CurrencyAmount{Currency: t.Currency, Amount: t.Amount})
+		summary.Total += t.BaseAmount
+	}
+	return summary
+}
+
+// currencyCode returns the canonical code of an accepted currency, or the
+// base currency when code is empty. Binding has already rejected other codes.
+func currencyCode(code string) string {
+	if c, ok := currency.Lookup(code); ok {
+		return c.Code
+	}
+	return currency.Base
+}
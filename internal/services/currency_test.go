@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+type fixedRates struct{ rates *currency.Rates }
+
+func (f *fixedRates) Name() string { return "fixed" }
+
+func (f *fixedRates) Latest(ctx context.Context) (*currency.Rates, error) { return f.rates, nil }
+
+func TestRefreshAndListRates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rates := mocks.NewMockExchangeRateRepository(ctrl)
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	svc := NewCurrencyService(&repository.Repositories{ExchangeRate: rates}, &fixedRates{&currency.Rates{
+		Date: day, Source: "tcmb", Rates: map[string]float64{"USD": 41.8765},
+	}})
+
+	rates.EXPECT().Save([]models.ExchangeRate{{Currency: "USD", Date: day, Rate: 41.8765, Source: "tcmb"}}).Return(nil)
+	svc.RefreshRates()
+
+	rates.EXPECT().Latest().Return([]models.ExchangeRate{{Currency: "USD", Date: day, Rate: 41.8765}}, nil)
+	list, err := svc.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != len(currency.Supported()) || list[0].Code != currency.Base || list[0].Rate != nil {
+		t.Fatalf("expected the base currency first without a rate, got %+v", list)
+	}
+	if list[1].Code != "USD" || list[1].Rate == nil || *list[1].Rate != 41.8765 || *list[1].RateDate != "2026-10-16" {
+		t.Errorf("unexpected USD entry %+v", list[1])
+	}
+
+	// Without a provider nothing is fetched
+	NewCurrencyService(&repository.Repositories{ExchangeRate: rates}, nil).RefreshRates()
+}
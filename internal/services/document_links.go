@@ -0,0 +1,80 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// documentsPrefix is where yandaş application documents are stored
+const documentsPrefix = "/uploads/documents/"
+
+// documentLinkTTL is how long a signed document link stays valid
+const documentLinkTTL = 15 * time.Minute
+
+var (
+	ErrInvalidDocumentLink = unauthorizedError("invalid_document_link", "document link is invalid")
+	ErrDocumentLinkExpired = unauthorizedError("document_link_expired", "document link has expired")
+)
+
+// DocumentLinks hands out short-lived signed links to identity documents.
+// The documents are not served from /uploads: a link names the file, when it
+// expires and an HMAC over both, so only admins who were given one can open it.
+type DocumentLinks struct {
+	secret      []byte
+	storagePath string
+	now         func() time.Time
+}
+
+// NewDocumentLinks creates a DocumentLinks signing with secret
+func NewDocumentLinks(secret, storagePath string) *DocumentLinks {
+	return &DocumentLinks{secret: []byte(secret), storagePath: storagePath, now: time.Now}
+}
+
+// Sign returns a signed link to a stored document. URLs outside the
+// documents directory are returned as they are.
+func (d *DocumentLinks) Sign(stored *string) *string {
+	if stored == nil || !strings.HasPrefix(*stored, documentsPrefix) {
+		return stored
+	}
+	name := strings.TrimPrefix(*stored, documentsPrefix)
+	expires := strconv.FormatInt(d.now().Add(documentLinkTTL).Unix(), 10)
+	link := "/api/v1/documents/" + url.PathEscape(name) + "?expires=" + expires + "&signature=" + d.signature(name, expires)
+	return &link
+}
+
+// Open checks a signed link and returns the path of its document on disk
+func (d *DocumentLinks) Open(name, expires, signature string) (string, error) {
+	if !hmac.Equal([]byte(signature), []byte(d.signature(name, expires))) {
+		return "", ErrInvalidDocumentLink
+	}
+	at, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return "", ErrInvalidDocumentLink
+	}
+	if d.now().Unix() > at {
+		return "", ErrDocumentLinkExpired
+	}
+
+	// Links are only signed for files directly in the documents directory
+	if name == "" || name == "." || name == ".." || path.Base(name) != name {
+		return "", ErrInvalidDocumentLink
+	}
+	stored := documentsPrefix + name
+	file, ok := uploadPath(d.storagePath, &stored)
+	if !ok {
+		return "", ErrInvalidDocumentLink
+	}
+	return file, nil
+}
+
+func (d *DocumentLinks) signature(name, expires string) string {
+	mac := hmac.New(sha256.New, d.secret)
+	mac.Write([]byte(name + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// openLink opens a link as the router would hand its parts to Open
+func openLink(t *testing.T, d *DocumentLinks, link string) (string, error) {
+	t.Helper()
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, err := url.PathUnescape(strings.TrimPrefix(u.Path, "/api/v1/documents/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d.Open(name, u.Query().Get("expires"), u.Query().Get("signature"))
+}
+
+func TestDocumentLinks(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	d := NewDocumentLinks("secret", "/srv/uploads")
+	d.now = func() time.Time { return now }
+
+	stored := "/uploads/documents/u1_kimlik_on.jpg"
+	link := d.Sign(&stored)
+	if link == nil || !strings.HasPrefix(*link, "/api/v1/documents/u1_kimlik_on.jpg?expires=") {
+		t.Fatalf("unexpected link %v", link)
+	}
+
+	file, err := openLink(t, d, *link)
+	if err != nil || file != filepath.Join("/srv/uploads", "documents", "u1_kimlik_on.jpg") {
+		t.Fatalf("expected the document path, got %q (%v)", file, err)
+	}
+
+	// Another secret, or another file under the same signature, is refused
+	if _, err := openLink(t, NewDocumentLinks("other", "/srv/uploads"), *link); !errors.Is(err, ErrInvalidDocumentLink) {
+		t.Fatalf("expected ErrInvalidDocumentLink, got %v", err)
+	}
+	forged := strings.Replace(*link, "u1_kimlik_on", "u2_kimlik_on", 1)
+	if _, err := openLink(t, d, forged); !errors.Is(err, ErrInvalidDocumentLink) {
+		t.Fatalf("expected ErrInvalidDocumentLink, got %v", err)
+	}
+
+	now = now.Add(documentLinkTTL + time.Second)
+	if _, err := openLink(t, d, *link); !errors.Is(err, ErrDocumentLinkExpired) {
+		t.Fatalf("expected ErrDocumentLinkExpired, got %v", err)
+	}
+}
+
+func TestDocumentLinksOnlySignDocuments(t *testing.T) {
+	d := NewDocumentLinks("secret", "/srv/uploads")
+
+	if d.Sign(nil) != nil {
+		t.Fatal("expected no link for a missing document")
+	}
+	external := "https://cdn.example.com/kimlik.jpg"
+	if link := d.Sign(&external); *link != external {
+		t.Fatalf("expected URLs outside the documents directory unchanged, got %q", *link)
+	}
+
+	// A signature does not reach outside the documents directory
+	expires := "9999999999"
+	if _, err := d.Open("..", expires, d.signature("..", expires)); !errors.Is(err, ErrInvalidDocumentLink) {
+		t.Fatalf("expected ErrInvalidDocumentLink, got %v", err)
+	}
+}
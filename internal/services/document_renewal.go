@@ -0,0 +1,262 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Documents that go stale and have to be renewed
+const DocumentCriminalRecord = "adli_sicil"
+
+// Document renewal statuses
+const (
+	RenewalRequested = "requested"
+	RenewalLapsed    = "lapsed"
+	RenewalRenewed   = "renewed"
+	RenewalWaived    = "waived"
+)
+
+const (
+	// Yandaşlar have this long to upload a renewed document before going offline
+	documentRenewalPeriod = 14 * 24 * time.Hour
+	documentRenewalBatch  = 200
+)
+
+// documentValidity is how long an uploaded document is relied on
+var documentValidity = map[string]time.Duration{
+	DocumentCriminalRecord: 365 * 24 * time.Hour,
+}
+
+var documentNames = map[string]string{
+	DocumentCriminalRecord: "Adli sicil kaydınızın",
+}
+
+// Document renewal errors
+var (
+	ErrDocumentRenewalNotFound = notFoundError("document_renewal_not_found", "document renewal not found")
+	ErrDocumentNotRenewable    = validationError("document_not_renewable", "document does not expire")
+	ErrDocumentRenewalClosed   = conflictError("document_renewal_closed", "document renewal is no longer open")
+	ErrDocumentRenewalLapsed   = conflictError("document_renewal_lapsed", "renew your expired documents to become available")
+	ErrInvalidRenewalStatus    = validationError("invalid_renewal_status", "invalid document renewal status")
+	ErrInvalidWaiver           = validationError("invalid_waiver", "waiver must end in the future")
+)
+
+// RenewableDocument reports whether document goes stale and can be renewed
+func RenewableDocument(document string) bool {
+	_, ok := documentValidity[document]
+	return ok
+}
+
+// CheckDocumentExpiry asks yandaşlar whose documents went stale to upload new
+// ones, and takes offline those who let the deadline pass
+func (s *YandasService) CheckDocumentExpiry() {
+	now := time.Now()
+	for document, validity := range documentValidity {
+		profiles, err := s.repos.DocumentRenewal.StaleProfiles(document, now.Add(-validity), documentRenewalBatch)
+		if err != nil {
+			log.Printf("[DOCUMENTS] failed to find stale %s documents: %v", document, err)
+			continue
+		}
+		for i := range profiles {
+			s.requestRenewal(&profiles[i], document, now)
+		}
+	}
+
+	renewals, err := s.repos.DocumentRenewal.ListPastDeadline(now, documentRenewalBatch)
+	if err != nil {
+		log.Printf("[DOCUMENTS] failed to find overdue renewals: %v", err)
+		return
+	}
+	for i := range renewals {
+		s.lapseRenewal(&renewals[i], now)
+	}
+}
+
+func (s *YandasService) requestRenewal(profile *models.YandasProfile, document string, now time.Time) {
+	renewal := &models.DocumentRenewal{
+		YandasProfileID: profile.ID,
+		Document:        document,
+		Status:          RenewalRequested,
+		Deadline:        now.Add(documentRenewalPeriod),
+	}
+	err := s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.DocumentRenewal.Create(renewal); err != nil {
+			return err
+		}
+		body := fmt.Sprintf("%s güncelliği doldu. Lütfen %s tarihine kadar yenisini yükleyin, aksi halde profiliniz yayından kaldırılacak.",
+			documentNames[document], renewal.Deadline.Format("02.01.2006"))
+		return s.notifications.SendTx(tx, profile.UserID, "Belgenizi yenileyin", body, "system", renewalData(renewal))
+	})
+	if err != nil {
+		log.Printf("[DOCUMENTS] failed to request %s renewal from yandaş %s: %v", document, profile.ID, err)
+		return
+	}
+	s.notifications.Flush()
+}
+
+// lapseRenewal takes a yandaş offline for a renewal not made by its deadline.
+// They cannot become available again until it is renewed or waived.
+func (s *YandasService) lapseRenewal(renewal *models.DocumentRenewal, now time.Time) {
+	renewal.Status = RenewalLapsed
+	renewal.LapsedAt = &now
+	err := s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.DocumentRenewal.Update(renewal); err != nil {
+			return err
+		}
+		if err := tx.YandasProfile.UpdateAvailability(renewal.YandasProfileID, false); err != nil {
+			return err
+		}
+		if renewal.YandasProfile == nil {
+			return nil
+		}
+		body := fmt.Sprintf("%s yenilenmediği için profiliniz yayından kaldırıldı. Yeni belgeyi yükleyerek tekrar müsait olabilirsiniz.",
+			documentNames[renewal.Document])
+		return s.notifications.SendTx(tx, renewal.YandasProfile.UserID, "Profiliniz yayından kaldırıldı", body, "system", renewalData(renewal))
+	})
+	if err != nil {
+		log.Printf("[DOCUMENTS] failed to lapse renewal %s: %v", renewal.ID, err)
+		return
+	}
+	s.notifications.Flush()
+}
+
+func renewalData(renewal *models.DocumentRenewal) map[string]interface{} {
+	return map[string]interface{}{
+		"document_renewal_id": renewal.ID.String(),
+		"document":            renewal.Document,
+		"deadline":            renewal.Deadline,
+	}
+}
+
+// ListDocumentRenewals returns the renewals the yandaş still has to make
+func (s *YandasService) ListDocumentRenewals(userID uuid.UUID) ([]models.DocumentRenewal, error) {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+	return s.repos.DocumentRenewal.ListOpenByProfile(profile.ID)
+}
+
+// RenewDocument stores a newly uploaded copy of a document for admin review
+// and closes its open renewal, which lets a lapsed yandaş become available again
+func (s *YandasService) RenewDocument(userID uuid.UUID, document, url string) (*models.YandasProfile, error) {
+	profile, err := s.repos.OnPrimary().YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+	if profile.ApprovalStatus != "approved" {
+		return nil, ErrProfileNotApproved
+	}
+
+	now := time.Now()
+	switch document {
+	case DocumentCriminalRecord:
+		profile.AdliSicilPDFURL = &url
+		profile.AdliSicilVerified = false
+		profile.AdliSicilUploadedAt = &now
+	default:
+		return nil, ErrDocumentNotRenewable
+	}
+
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.YandasProfile.Update(profile); err != nil {
+			return err
+		}
+		renewal, err := tx.DocumentRenewal.GetOpen(profile.ID, document)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		renewal.Status = RenewalRenewed
+		renewal.RenewedAt = &now
+		return tx.DocumentRenewal.Update(renewal)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// lapsedRenewal reports whether the profile was taken offline for a document it has not renewed
+func (s *YandasService) lapsedRenewal(profileID uuid.UUID) (bool, error) {
+	renewals, err := s.repos.OnPrimary().DocumentRenewal.ListOpenByProfile(profileID)
+	if err != nil {
+		return false, err
+	}
+	for _, renewal := range renewals {
+		if renewal.Status == RenewalLapsed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListDocumentRenewals returns renewals in a status, earliest deadline first
+func (s *AdminService) ListDocumentRenewals(status string, page, limit int) ([]models.DocumentRenewal, int64, error) {
+	switch status {
+	case RenewalRequested, RenewalLapsed, RenewalRenewed, RenewalWaived:
+	default:
+		return nil, 0, ErrInvalidRenewalStatus
+	}
+	return s.repos.DocumentRenewal.List(status, page, limit)
+}
+
+// WaiveRenewalInput overrides a document renewal
+type WaiveRenewalInput struct {
+	Reason string     `json:"reason" binding:"required,max=500"`
+	Until  *time.Time `json:"until"` // no renewal is requested again before this; defaults to one validity period
+}
+
+// WaiveDocumentRenewal lets an admin accept a yandaş's current document for
+// longer. A lapsed yandaş can become available again.
+func (s *AdminService) WaiveDocumentRenewal(renewalID, adminID uuid.UUID, input *WaiveRenewalInput) (*models.DocumentRenewal, error) {
+	renewal, err := s.repos.OnPrimary().DocumentRenewal.GetByID(renewalID)
+	if err != nil {
+		return nil, ErrDocumentRenewalNotFound
+	}
+	if renewal.Status != RenewalRequested && renewal.Status != RenewalLapsed {
+		return nil, ErrDocumentRenewalClosed
+	}
+
+	until := time.Now().Add(documentValidity[renewal.Document])
+	if input.Until != nil {
+		if !input.Until.After(time.Now()) {
+			return nil, ErrInvalidWaiver
+		}
+		until = *input.Until
+	}
+
+	previous := renewal.Status
+	renewal.Status = RenewalWaived
+	renewal.WaivedBy = &adminID
+	renewal.WaivedUntil = &until
+	renewal.WaiverReason = &input.Reason
+	if err := s.repos.DocumentRenewal.Update(renewal); err != nil {
+		return nil, err
+	}
+
+	s.logAction(adminID, "waive_document_renewal", "document_renewal", renewalID, map[string]interface{}{
+		"status": previous,
+	}, map[string]interface{}{
+		"status": RenewalWaived,
+		"until":  until,
+		"reason": input.Reason,
+	})
+
+	if s.notifications != nil && renewal.YandasProfile != nil {
+		body := fmt.Sprintf("Belgeniz %s tarihine kadar geçerli sayılacak.", until.Format("02.01.2006"))
+		if err := s.notifications.Send(renewal.YandasProfile.UserID, "Belge yenileme ertelendi", body, "system", renewalData(renewal)); err != nil {
+			log.Printf("[ADMIN] failed to notify yandaş about waived renewal %s: %v", renewal.ID, err)
+		}
+	}
+	return renewal, nil
+}
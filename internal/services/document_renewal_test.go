@@ -0,0 +1,159 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"gorm.io/gorm"
+)
+
+type renewalMocks struct {
+	profiles      *mocks.MockYandasProfileRepository
+	renewals      *mocks.MockDocumentRenewalRepository
+	notifications *mocks.MockNotificationRepository
+	prefs         *mocks.MockNotificationPreferenceRepository
+}
+
+func newTestRenewalRepos(t *testing.T) (*repository.Repositories, *renewalMocks) {
+	ctrl := gomock.NewController(t)
+	m := &renewalMocks{
+		profiles:      mocks.NewMockYandasProfileRepository(ctrl),
+		renewals:      mocks.NewMockDocumentRenewalRepository(ctrl),
+		notifications: mocks.NewMockNotificationRepository(ctrl),
+		prefs:         mocks.NewMockNotificationPreferenceRepository(ctrl),
+	}
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{
+		YandasProfile:          m.profiles,
+		DocumentRenewal:        m.renewals,
+		Notification:           m.notifications,
+		NotificationPreference: m.prefs,
+		UnitOfWork:             uow,
+	}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	m.prefs.EXPECT().GetByUserAndType(gomock.Any(), gomock.Any()).Return(&models.NotificationPreference{}, nil).AnyTimes()
+	return repos, m
+}
+
+func TestCheckDocumentExpiry(t *testing.T) {
+	repos, m := newTestRenewalRepos(t)
+	svc := NewYandasService(repos, &config.Config{}, nil, nil, nil, nil, nil, nil, nil, NewNotificationService(repos, nil, nil, nil, nil, nil), nil, nil, nil, nil)
+
+	stale := models.YandasProfile{ID: uuid.New(), UserID: uuid.New(), ApprovalStatus: "approved"}
+	m.renewals.EXPECT().StaleProfiles(DocumentCriminalRecord, gomock.Any(), documentRenewalBatch).
+		DoAndReturn(func(_ string, before time.Time, _ int) ([]models.YandasProfile, error) {
+			if age := time.Since(before); age < 364*24*time.Hour || age > 366*24*time.Hour {
+				t.Errorf("expected criminal records older than a year, got %s", age)
+			}
+			return []models.YandasProfile{stale}, nil
+		})
+	m.renewals.EXPECT().Create(gomock.Any()).DoAndReturn(func(r *models.DocumentRenewal) error {
+		if r.YandasProfileID != stale.ID || r.Status != RenewalRequested || time.Until(r.Deadline) < 13*24*time.Hour {
+			t.Errorf("unexpected renewal request %+v", r)
+		}
+		return nil
+	})
+
+	overdue := models.DocumentRenewal{
+		ID:              uuid.New(),
+		YandasProfileID: uuid.New(),
+		Document:        DocumentCriminalRecord,
+		Status:          RenewalRequested,
+		Deadline:        time.Now().Add(-time.Hour),
+		YandasProfile:   &models.YandasProfile{UserID: uuid.New()},
+	}
+	m.renewals.EXPECT().ListPastDeadline(gomock.Any(), documentRenewalBatch).Return([]models.DocumentRenewal{overdue}, nil)
+	m.renewals.EXPECT().Update(gomock.Any()).DoAndReturn(func(r *models.DocumentRenewal) error {
+		if r.Status != RenewalLapsed || r.LapsedAt == nil {
+			t.Errorf("expected the renewal lapsed, got %+v", r)
+		}
+		return nil
+	})
+	m.profiles.EXPECT().UpdateAvailability(overdue.YandasProfileID, false).Return(nil)
+
+	// Both yandaşlar are told
+	m.notifications.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+
+	svc.CheckDocumentExpiry()
+}
+
+func TestLapsedRenewalBlocksAvailabilityUntilRenewed(t *testing.T) {
+	repos, m := newTestRenewalRepos(t)
+	svc := NewYandasService(repos, &config.Config{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: uuid.New(), ApprovalStatus: "approved"}
+	lapsed := &models.DocumentRenewal{ID: uuid.New(), YandasProfileID: profile.ID, Document: DocumentCriminalRecord, Status: RenewalLapsed}
+	m.profiles.EXPECT().GetByUserID(profile.UserID).Return(profile, nil).AnyTimes()
+
+	m.renewals.EXPECT().ListOpenByProfile(profile.ID).Return([]models.DocumentRenewal{*lapsed}, nil)
+	if err := svc.UpdateAvailability(profile.UserID, true); !errors.Is(err, ErrDocumentRenewalLapsed) {
+		t.Fatalf("expected ErrDocumentRenewalLapsed, got %v", err)
+	}
+
+	if _, err := svc.RenewDocument(profile.UserID, "kimlik_on", "/uploads/documents/x.jpg"); !errors.Is(err, ErrDocumentNotRenewable) {
+		t.Errorf("expected ErrDocumentNotRenewable, got %v", err)
+	}
+
+	// Uploading a new criminal record closes the renewal for admin review
+	m.profiles.EXPECT().Update(profile).Return(nil)
+	m.renewals.EXPECT().GetOpen(profile.ID, DocumentCriminalRecord).Return(lapsed, nil)
+	m.renewals.EXPECT().Update(lapsed).Return(nil)
+	renewed, err := svc.RenewDocument(profile.UserID, DocumentCriminalRecord, "/uploads/documents/adli.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renewed.AdliSicilVerified || renewed.AdliSicilUploadedAt == nil || lapsed.Status != RenewalRenewed {
+		t.Errorf("unexpected renewal result %+v, %+v", renewed, lapsed)
+	}
+
+	m.renewals.EXPECT().ListOpenByProfile(profile.ID).Return(nil, nil)
+	m.profiles.EXPECT().UpdateAvailability(profile.ID, true).Return(nil)
+	if err := svc.UpdateAvailability(profile.UserID, true); err != nil {
+		t.Errorf("expected the yandaş to become available, got %v", err)
+	}
+}
+
+func TestWaiveDocumentRenewal(t *testing.T) {
+	repos, m := newTestRenewalRepos(t)
+	audit := mocks.NewMockAuditLogRepository(gomock.NewController(t))
+	repos.AuditLog = audit
+	svc := NewAdminService(repos, nil, nil, nil, nil, nil, nil)
+	adminID := uuid.New()
+
+	renewed := &models.DocumentRenewal{ID: uuid.New(), Status: RenewalRenewed}
+	m.renewals.EXPECT().GetByID(renewed.ID).Return(renewed, nil)
+	if _, err := svc.WaiveDocumentRenewal(renewed.ID, adminID, &WaiveRenewalInput{Reason: "x"}); !errors.Is(err, ErrDocumentRenewalClosed) {
+		t.Errorf("expected ErrDocumentRenewalClosed, got %v", err)
+	}
+
+	m.renewals.EXPECT().GetByID(gomock.Any()).Return(nil, gorm.ErrRecordNotFound)
+	if _, err := svc.WaiveDocumentRenewal(uuid.New(), adminID, &WaiveRenewalInput{Reason: "x"}); !errors.Is(err, ErrDocumentRenewalNotFound) {
+		t.Errorf("expected ErrDocumentRenewalNotFound, got %v", err)
+	}
+
+	lapsed := &models.DocumentRenewal{ID: uuid.New(), Document: DocumentCriminalRecord, Status: RenewalLapsed}
+	m.renewals.EXPECT().GetByID(lapsed.ID).Return(lapsed, nil).Times(2)
+	past := time.Now().Add(-time.Hour)
+	if _, err := svc.WaiveDocumentRenewal(lapsed.ID, adminID, &WaiveRenewalInput{Reason: "x", Until: &past}); !errors.Is(err, ErrInvalidWaiver) {
+		t.Errorf("expected ErrInvalidWaiver, got %v", err)
+	}
+
+	m.renewals.EXPECT().Update(lapsed).Return(nil)
+	audit.EXPECT().Create(gomock.Any()).Return(nil)
+	waived, err := svc.WaiveDocumentRenewal(lapsed.ID, adminID, &WaiveRenewalInput{Reason: "Yeni kayıt e-Devlet'te doğrulandı"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if waived.Status != RenewalWaived || *waived.WaivedBy != adminID || time.Until(*waived.WaivedUntil) < 364*24*time.Hour {
+		t.Errorf("unexpected waiver %+v", waived)
+	}
+}
@@ -1,28 +1,78 @@
 package services
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
+	"html"
 	"log"
-	"net/smtp"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/mail"
 )
 
-// EmailService handles sending emails via SMTP
+const emailTimeout = 30 * time.Second
+
+// ErrUnknownEmailProvider is returned for feedback webhooks of a provider that is not in use
+var ErrUnknownEmailProvider = notFoundError("unknown_email_provider", "unknown email provider")
+
+// EmailService sends emails through the configured provider
 type EmailService struct {
-	cfg *config.Config
+	repos      *repository.Repositories
+	cfg        *config.Config
+	provider   mail.Provider
+	monitoring *MonitoringService
 }
 
-// NewEmailService creates a new email service
-func NewEmailService(cfg *config.Config) *EmailService {
-	return &EmailService{cfg: cfg}
+// NewEmailService creates a new email service. Without a configured provider
+// emails are only logged.
+func NewEmailService(repos *repository.Repositories, cfg *config.Config) *EmailService {
+	return &EmailService{repos: repos, cfg: cfg, provider: newEmailProvider(cfg)}
+}
+
+// newEmailProvider returns the provider named by EMAIL_PROVIDER, or nil when it lacks credentials
+func newEmailProvider(cfg *config.Config) mail.Provider {
+	switch cfg.EmailProvider {
+	case "", mail.ProviderSMTP:
+		if cfg.SMTPUser == "" || cfg.SMTPPassword == "" {
+			return nil
+		}
+		return mail.NewSMTP(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword)
+	case mail.ProviderSES:
+		ses, err := mail.NewSES(context.Background(), cfg.SESRegion, cfg.EmailWebhookToken)
+		if err != nil {
+			log.Printf("[EMAIL] failed to set up SES, emails disabled: %v", err)
+			return nil
+		}
+		return ses
+	case mail.ProviderSendGrid:
+		if cfg.SendGridAPIKey == "" {
+			return nil
+		}
+		sendGrid, err := mail.NewSendGrid(cfg.SendGridAPIKey, cfg.SendGridWebhookKey)
+		if err != nil {
+			log.Printf("[EMAIL] failed to set up SendGrid, emails disabled: %v", err)
+			return nil
+		}
+		return sendGrid
+	}
+	log.Printf("[EMAIL] unknown email provider %q, emails disabled", cfg.EmailProvider)
+	return nil
+}
+
+// SetMonitoring attaches the service per-provider send counts are recorded with.
+// Monitoring sends its alerts by email, so it is created after this service.
+func (s *EmailService) SetMonitoring(monitoring *MonitoringService) {
+	s.monitoring = monitoring
 }
 
 // SendOTPEmail sends a beautiful OTP verification email
 func (s *EmailService) SendOTPEmail(to, otp, userName string) error {
-	if s.cfg.SMTPUser == "" || s.cfg.SMTPPassword == "" {
+	if s.provider == nil {
 		log.Printf("[EMAIL FALLBACK] OTP for %s: %s\n", to, otp)
 		return nil
 	}
@@ -35,7 +85,7 @@ func (s *EmailService) SendOTPEmail(to, otp, userName string) error {
 
 // SendWelcomeEmail sends a welcome email after verification
 func (s *EmailService) SendWelcomeEmail(to, userName string) error {
-	if s.cfg.SMTPUser == "" {
+	if s.provider == nil {
 		return nil
 	}
 
@@ -45,105 +95,104 @@ func (s *EmailService) SendWelcomeEmail(to, userName string) error {
 	return s.sendHTML(to, subject, body)
 }
 
-func (s *EmailService) sendHTML(to, subject, body string) error {
-	from := s.cfg.SMTPFrom
-	fromName := s.cfg.SMTPFromName
-
-	headers := make(map[string]string)
-	headers["From"] = fmt.Sprintf("%s <%s>", fromName, from)
-	headers["To"] = to
-	headers["Subject"] = subject
-	headers["MIME-Version"] = "1.0"
-	headers["Content-Type"] = "text/html; charset=UTF-8"
-
-	msg := ""
-	for k, v := range headers {
-		msg += fmt.Sprintf("%s: %s\r\n", k, v)
+// SendAlertEmail sends a plain monitoring alert to an admin
+func (s *EmailService) SendAlertEmail(to, subject, message string) error {
+	if s.provider == nil {
+		log.Printf("[EMAIL FALLBACK] Alert for %s: %s\n", to, subject)
+		return nil
 	}
-	msg += "\r\n" + body
-
-	addr := fmt.Sprintf("%s:%d", s.cfg.SMTPHost, s.cfg.SMTPPort)
 
-	auth := smtp.PlainAuth("", s.cfg.SMTPUser, s.cfg.SMTPPassword, s.cfg.SMTPHost)
-
-	// TLS config - InsecureSkipVerify needed if cert hostname doesn't match SMTP host
-	tlsConfig := &tls.Config{
-		ServerName:         s.cfg.SMTPHost,
-		InsecureSkipVerify: true,
-	}
+	body := "<p>" + html.EscapeString(message) + "</p>"
+	return s.sendHTML(to, "YANDAŞ Uyarı - "+subject, body)
+}
 
-	// Connect to SMTP server
-	conn, err := tls.Dial("tcp", addr, tlsConfig)
-	if err != nil {
-		// Try STARTTLS if direct TLS fails
-		log.Printf("Direct TLS failed, trying STARTTLS: %v", err)
-		c, dialErr := smtp.Dial(addr)
-		if dialErr != nil {
-			return fmt.Errorf("SMTP dial error: %w", dialErr)
-		}
-		defer c.Close()
-		if err := c.StartTLS(tlsConfig); err != nil {
-			log.Printf("STARTTLS failed, sending plain: %v", err)
-		}
-		if err := c.Auth(auth); err != nil {
-			return fmt.Errorf("SMTP auth error: %w", err)
-		}
-		if err := c.Mail(from); err != nil {
-			return fmt.Errorf("SMTP mail error: %w", err)
-		}
-		if err := c.Rcpt(to); err != nil {
-			return fmt.Errorf("SMTP rcpt error: %w", err)
-		}
-		w, err := c.Data()
-		if err != nil {
-			return fmt.Errorf("SMTP data error: %w", err)
-		}
-		if _, err := w.Write([]byte(msg)); err != nil {
-			return fmt.Errorf("SMTP write error: %w", err)
-		}
-		if err := w.Close(); err != nil {
-			return fmt.Errorf("SMTP close error: %w", err)
-		}
-		c.Quit()
-		log.Printf("✅ Email sent (STARTTLS) to: %s\n", to)
+// SendSecurityNoticeEmail tells a user about a sensitive change to their account
+func (s *EmailService) SendSecurityNoticeEmail(to, userName, message string) error {
+	if s.provider == nil {
+		log.Printf("[EMAIL FALLBACK] Security notice for %s: %s\n", to, message)
 		return nil
 	}
 
-	client, err := smtp.NewClient(conn, s.cfg.SMTPHost)
-	if err != nil {
-		return fmt.Errorf("SMTP client error: %w", err)
-	}
-	defer client.Close()
+	body := "<p>Merhaba " + html.EscapeString(userName) + ",</p><p>" + html.EscapeString(message) +
+		"</p><p>Bu değişikliği siz yapmadıysanız lütfen hemen destek ekibimizle iletişime geçin.</p>"
+	return s.sendHTML(to, "YANDAŞ - Hesap Güvenliği Bildirimi", body)
+}
 
-	if err = client.Auth(auth); err != nil {
-		return fmt.Errorf("SMTP auth error: %w", err)
+// SendNotificationDigest lists a user's unread notifications, with the
+// number of further ones not shown
+func (s *EmailService) SendNotificationDigest(to, userName string, notifications []models.Notification, more int) error {
+	if s.provider == nil {
+		log.Printf("[EMAIL FALLBACK] Notification digest for %s: %d notifications\n", to, len(notifications)+more)
+		return nil
 	}
 
-	if err = client.Mail(from); err != nil {
-		return fmt.Errorf("SMTP mail error: %w", err)
+	var body strings.Builder
+	body.WriteString("<p>Merhaba " + html.EscapeString(userName) + ",</p>")
+	body.WriteString(fmt.Sprintf("<p>Okunmamış %d bildiriminiz var:</p><ul>", len(notifications)+more))
+	for _, notif := range notifications {
+		body.WriteString("<li><strong>" + html.EscapeString(notif.Title) + "</strong><br>" + html.EscapeString(notif.Body) + "</li>")
+	}
+	body.WriteString("</ul>")
+	if more > 0 {
+		body.WriteString(fmt.Sprintf("<p>ve %d bildirim daha.</p>", more))
 	}
+	body.WriteString("<p>Tümünü görmek için YANDAŞ uygulamasını açın.</p>")
+	return s.sendHTML(to, "YANDAŞ - Okunmamış bildirimleriniz", body.String())
+}
 
-	if err = client.Rcpt(to); err != nil {
-		return fmt.Errorf("SMTP rcpt error: %w", err)
+// sendHTML sends through the provider, skipping addresses flagged undeliverable
+// so repeated bounces don't hurt the sender reputation
+func (s *EmailService) sendHTML(to, subject, body string) error {
+	if s.repos.User.IsEmailUndeliverable(to) {
+		log.Printf("[EMAIL] skipped undeliverable address %s", to)
+		return nil
 	}
 
-	w, err := client.Data()
+	ctx, cancel := context.WithTimeout(context.Background(), emailTimeout)
+	defer cancel()
+	_, err := s.provider.Send(ctx, &mail.Message{
+		From:     s.cfg.SMTPFrom,
+		FromName: s.cfg.SMTPFromName,
+		To:       to,
+		Subject:  subject,
+		HTML:     body,
+	})
 	if err != nil {
-		return fmt.Errorf("SMTP data error: %w", err)
+		s.monitoring.Record(emailFailureMetric(s.provider.Name()))
+		return err
 	}
+	s.monitoring.Record(emailSentMetric(s.provider.Name()))
+	log.Printf("✅ Email sent via %s to: %s\n", s.provider.Name(), to)
+	return nil
+}
 
-	_, err = w.Write([]byte(msg))
-	if err != nil {
-		return fmt.Errorf("SMTP write error: %w", err)
+// HandleEvents records bounces and complaints posted by the provider in use,
+// marking the affected addresses undeliverable
+func (s *EmailService) HandleEvents(providerName string, r *http.Request) error {
+	parser, ok := s.provider.(mail.EventParser)
+	if !ok || s.provider.Name() != providerName {
+		return ErrUnknownEmailProvider
 	}
 
-	err = w.Close()
+	events, err := parser.ParseEvents(r)
 	if err != nil {
-		return fmt.Errorf("SMTP close error: %w", err)
+		return err
+	}
+	for _, event := range events {
+		switch event.Type {
+		case mail.EventBounce:
+			s.monitoring.Record(MetricEmailBounces)
+		case mail.EventComplaint:
+			s.monitoring.Record(MetricEmailComplaints)
+		}
+		marked, err := s.repos.User.MarkEmailUndeliverable(event.Email, event.Type+": "+event.Reason)
+		if err != nil {
+			return err
+		}
+		if marked {
+			log.Printf("[EMAIL] %s marked undeliverable after a %s", event.Email, event.Type)
+		}
 	}
-
-	client.Quit()
-	log.Printf("✅ Email sent to: %s\n", to)
 	return nil
 }
 
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/mail"
+)
+
+type fakeMailProvider struct {
+	sent   []string
+	bodies []string
+	events []mail.Event
+}
+
+func (p *fakeMailProvider) Name() string { return mail.ProviderSES }
+
+func (p *fakeMailProvider) Send(_ context.Context, msg *mail.Message) (string, error) {
+	p.sent = append(p.sent, msg.To)
+	p.bodies = append(p.bodies, msg.HTML)
+	return "id", nil
+}
+
+func (p *fakeMailProvider) ParseEvents(*http.Request) ([]mail.Event, error) {
+	return p.events, nil
+}
+
+func TestEmailSkipsUndeliverableAddresses(t *testing.T) {
+	users := mocks.NewMockUserRepository(gomock.NewController(t))
+	provider := &fakeMailProvider{}
+	svc := &EmailService{repos: &repository.Repositories{User: users}, cfg: &config.Config{}, provider: provider}
+
+	users.EXPECT().IsEmailUndeliverable("bounced@example.com").Return(true)
+	users.EXPECT().IsEmailUndeliverable("ok@example.com").Return(false)
+	svc.SendWelcomeEmail("bounced@example.com", "Ayşe")
+	svc.SendWelcomeEmail("ok@example.com", "Ali")
+
+	if len(provider.sent) != 1 || provider.sent[0] != "ok@example.com" {
+		t.Errorf("unexpected recipients %v", provider.sent)
+	}
+}
+
+func TestEmailHandleEvents(t *testing.T) {
+	users := mocks.NewMockUserRepository(gomock.NewController(t))
+	provider := &fakeMailProvider{events: []mail.Event{
+		{Type: mail.EventBounce, Email: "a@example.com", Reason: "General"},
+		{Type: mail.EventComplaint, Email: "b@example.com", Reason: "abuse"},
+	}}
+	svc := &EmailService{repos: &repository.Repositories{User: users}, cfg: &config.Config{}, provider: provider}
+
+	users.EXPECT().MarkEmailUndeliverable("a@example.com", "bounce: General").Return(true, nil)
+	users.EXPECT().MarkEmailUndeliverable("b@example.com", "complaint: abuse").Return(false, nil)
+	if err := svc.HandleEvents(mail.ProviderSES, httptest.NewRequest(http.MethodPost, "/", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.HandleEvents(mail.ProviderSendGrid, httptest.NewRequest(http.MethodPost, "/", nil)); !errors.Is(err, ErrUnknownEmailProvider) {
+		t.Errorf("expected ErrUnknownEmailProvider for a provider not in use, got %v", err)
+	}
+}
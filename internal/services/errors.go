@@ -0,0 +1,72 @@
+package services
+
+import "errors"
+
+// Kinds of domain errors. Every error a client can act on wraps one of them,
+// so handlers can pick the HTTP status without knowing each error.
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrUnauthenticated = errors.New("not authenticated")
+	ErrUnauthorized    = errors.New("not allowed")
+	ErrConflict        = errors.New("conflict")
+	ErrValidation      = errors.New("invalid request")
+	ErrUnavailable     = errors.New("temporarily unavailable")
+)
+
+// DomainError is an error caused by the request rather than the system. It
+// matches its kind with errors.Is and carries a machine-readable code.
+type DomainError struct {
+	Kind    error
+	Code    string
+	Message string
+}
+
+func (e *DomainError) Error() string { return e.Message }
+
+func (e *DomainError) Unwrap() error { return e.Kind }
+
+// notFoundError reports a missing resource, or one the caller may not see
+func notFoundError(code, message string) error {
+	return &DomainError{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+// unauthenticatedError reports a caller whose identity could not be
+// established: wrong credentials, a bad token or a signed-out session
+func unauthenticatedError(code, message string) error {
+	return &DomainError{Kind: ErrUnauthenticated, Code: code, Message: message}
+}
+
+// unauthorizedError reports an action the caller is not allowed to take
+func unauthorizedError(code, message string) error {
+	return &DomainError{Kind: ErrUnauthorized, Code: code, Message: message}
+}
+
+// conflictError reports an action the resource's current state doesn't allow
+func conflictError(code, message string) error {
+	return &DomainError{Kind: ErrConflict, Code: code, Message: message}
+}
+
+// validationError reports input the service rejects
+func validationError(code, message string) error {
+	return &DomainError{Kind: ErrValidation, Code: code, Message: message}
+}
+
+// unavailableError reports a feature that is switched off or not configured
+func unavailableError(code, message string) error {
+	return &DomainError{Kind: ErrUnavailable, Code: code, Message: message}
+}
+
+// Errors shared by several services
+var (
+	ErrYandasProfileNotFound = notFoundError("yandas_profile_not_found", "yandaş profile not found")
+	ErrYandasNotFound        = notFoundError("yandas_not_found", "yandaş not found")
+	ErrYandasUnavailable     = conflictError("yandas_unavailable", "yandaş not available")
+	ErrProfileNotApproved    = unauthorizedError("profile_not_approved", "profile not approved yet")
+	ErrOrderNotFound         = notFoundError("order_not_found", "order not found")
+	ErrServiceNotFound       = notFoundError("service_not_found", "service not found")
+	ErrAddressNotFound       = notFoundError("address_not_found", "address not found")
+	ErrReviewNotFound        = notFoundError("review_not_found", "review not found")
+	ErrTicketNotFound        = notFoundError("ticket_not_found", "ticket not found")
+	ErrCategoryNotFound      = notFoundError("category_not_found", "category not found")
+	ErrNotOwner              = unauthorizedError("unauthorized", "unauthorized")
+)
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDomainErrorKinds(t *testing.T) {
+	cases := []struct {
+		err  error
+		kind error
+		code string
+	}{
+		{ErrOrderNotFound, ErrNotFound, "order_not_found"},
+		{ErrInvalidCredentials, ErrUnauthenticated, "invalid_credentials"},
+		{ErrSessionRevoked, ErrUnauthenticated, "session_revoked"},
+		{ErrIncorrectPassword, ErrUnauthorized, "incorrect_password"},
+		{ErrScheduleConflict, ErrConflict, "schedule_conflict"},
+		{ErrInvalidBudget, ErrValidation, "invalid_budget"},
+		{ErrFeatureDisabled, ErrUnavailable, "feature_disabled"},
+		{fmt.Errorf("%w: rates.x", ErrUnknownSetting), ErrValidation, "unknown_setting"},
+	}
+	for _, tc := range cases {
+		if !errors.Is(tc.err, tc.kind) {
+			t.Errorf("%v: expected kind %v", tc.err, tc.kind)
+		}
+		var domainErr *DomainError
+		if !errors.As(tc.err, &domainErr) || domainErr.Code != tc.code {
+			t.Errorf("%v: expected code %q, got %+v", tc.err, tc.code, domainErr)
+		}
+	}
+
+	if errors.Is(ErrOrderNotFound, ErrConflict) {
+		t.Error("expected a not found error not to match another kind")
+	}
+	if !errors.Is(&CategoryImportError{}, ErrValidation) {
+		t.Error("expected an invalid import to be a validation error")
+	}
+}
+
+func TestParseSettingIsValidationError(t *testing.T) {
+	def, _ := settingDefinition(SettingMaintenanceEnabled)
+	_, err := parseSetting(def, "maybe")
+	var domainErr *DomainError
+	if !errors.As(err, &domainErr) || domainErr.Kind != ErrValidation || domainErr.Code != "invalid_setting" {
+		t.Errorf("expected an invalid_setting validation error, got %v", err)
+	}
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/queue"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// Domain events
+const (
+	EventUserRegistered = "user.registered"
+	EventOrderAccepted  = "order.accepted"
+	EventMessageSent    = "message.sent"
+)
+
+// UserRegisteredEvent is published when an account is created
+type UserRegisteredEvent struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Email    string    `json:"email"`
+	Phone    string    `json:"phone,omitempty"`
+	FullName string    `json:"full_name"`
+}
+
+// OrderAcceptedEvent is published when a yandaş accepts an order
+type OrderAcceptedEvent struct {
+	OrderID      uuid.UUID `json:"order_id"`
+	CustomerID   uuid.UUID `json:"customer_id"`
+	YandasID     uuid.UUID `json:"yandas_id"`
+	YandasUserID uuid.UUID `json:"yandas_user_id"`
+}
+
+// MessageSentEvent is published when a participant sends a chat message
+type MessageSentEvent struct {
+	MessageID      uuid.UUID `json:"message_id"`
+	ConversationID uuid.UUID `json:"conversation_id"`
+	SenderID       uuid.UUID `json:"sender_id"`
+	MessageType    string    `json:"message_type"`
+	Preview        string    `json:"preview"`
+}
+
+const (
+	eventPollInterval = 5 * time.Second
+	eventLease        = time.Minute
+	eventBatchSize    = 100
+	eventBaseBackoff  = 10 * time.Second
+	eventMaxBackoff   = 10 * time.Minute
+	// Relayed events are kept this long for inspection
+	eventRetention = 7 * 24 * time.Hour
+)
+
+// eventHandler handles one event payload
+type eventHandler func(ctx context.Context, payload json.RawMessage) error
+
+// eventSubscriber is a named handler of one event type, run as its own job so
+// subscribers are retried independently of each other
+type eventSubscriber struct {
+	name    string
+	handle  eventHandler
+	options []queue.Option
+}
+
+// EventBus carries domain events from the services that cause them to the
+// subscribers that handle their side effects. Events are written to an outbox
+// table within the caller's transaction; the API process relays them to the
+// job queue, where cmd/worker runs each subscriber. Subscribers may see an
+// event more than once and must tolerate that.
+type EventBus struct {
+	repos       *repository.Repositories
+	jobs        *queue.Queue
+	subscribers map[string][]eventSubscriber
+	wake        chan struct{}
+}
+
+func NewEventBus(repos *repository.Repositories, jobs *queue.Queue) *EventBus {
+	return &EventBus{
+		repos:       repos,
+		jobs:        jobs,
+		subscribers: make(map[string][]eventSubscriber),
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// subscribeEvent registers fn, named uniquely within the event type, to handle
+// events of eventType decoded into T
+func subscribeEvent[T any](b *EventBus, eventType, name string, fn func(ctx context.Context, event T) error, opts ...queue.Option) {
+	handle := func(ctx context.Context, raw json.RawMessage) error {
+		var event T
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return fmt.Errorf("decode %s: %w", eventType, err)
+		}
+		return fn(ctx, event)
+	}
+	b.subscribers[eventType] = append(b.subscribers[eventType], eventSubscriber{name: name, handle: handle, options: opts})
+}
+
+// Publish adds an event to the outbox through tx, so it is only relayed if
+// the caller's transaction commits; call Flush once it has. It is safe to
+// call on a nil bus.
+func (b *EventBus) Publish(tx *repository.Repositories, eventType string, aggregateID uuid.UUID, payload interface{}) error {
+	if b == nil {
+		return nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	event := &models.DomainEvent{
+		Type:          eventType,
+		AggregateID:   aggregateID,
+		Payload:       string(data),
+		Status:        "pending",
+		NextAttemptAt: &now,
+	}
+	return tx.DomainEvent.Create(event)
+}
+
+// Flush wakes the relay so committed events go out without waiting for the
+// next poll. It is safe to call on a nil bus.
+func (b *EventBus) Flush() {
+	if b == nil {
+		return
+	}
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run relays due events until the process exits
+func (b *EventBus) Run() {
+	ticker := time.NewTicker(eventPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-b.wake:
+		}
+		b.relayDue()
+	}
+}
+
+func (b *EventBus) relayDue() {
+	events, err := b.repos.DomainEvent.ClaimDue(eventBatchSize, eventLease)
+	if err != nil {
+		log.Printf("[EVENTS] failed to claim events: %v", err)
+		return
+	}
+	for i := range events {
+		b.relay(&events[i])
+	}
+}
+
+// relay enqueues a job per subscriber of the event and marks it published,
+// scheduling a retry of the whole event when the queue is unavailable
+func (b *EventBus) relay(event *models.DomainEvent) {
+	event.Attempts++
+
+	var relayErr error
+	for _, sub := range b.subscribers[event.Type] {
+		job := eventJob{EventID: event.ID, Subscriber: sub.name}
+		if err := b.jobs.Enqueue(JobDeliverEvent, job, sub.options...); err != nil {
+			relayErr = err
+			break
+		}
+	}
+
+	if relayErr == nil {
+		now := time.Now()
+		event.Status = "published"
+		event.PublishedAt = &now
+		event.NextAttemptAt = nil
+		event.LastError = nil
+	} else {
+		msg := relayErr.Error()
+		next := time.Now().Add(eventBackoff(event.Attempts))
+		event.LastError = &msg
+		event.NextAttemptAt = &next
+	}
+
+	if err := b.repos.DomainEvent.Update(event); err != nil {
+		log.Printf("[EVENTS] failed to record relay of %s %s: %v", event.Type, event.ID, err)
+	}
+}
+
+// deliver runs one subscriber of a relayed event
+func (b *EventBus) deliver(ctx context.Context, job eventJob) error {
+	event, err := b.repos.DomainEvent.GetByID(job.EventID)
+	if err != nil {
+		return fmt.Errorf("load event %s: %w", job.EventID, err)
+	}
+	for _, sub := range b.subscribers[event.Type] {
+		if sub.name == job.Subscriber {
+			return sub.handle(ctx, json.RawMessage(event.Payload))
+		}
+	}
+	log.Printf("[EVENTS] no subscriber %q for %s, skipped", job.Subscriber, event.Type)
+	return nil
+}
+
+// Prune deletes relayed events past their retention
+func (b *EventBus) Prune() {
+	if err := b.repos.DomainEvent.PrunePublished(time.Now().Add(-eventRetention)); err != nil {
+		log.Printf("[EVENTS] failed to prune events: %v", err)
+	}
+}
+
+// eventBackoff doubles the wait after each failed relay, capped at eventMaxBackoff
+func eventBackoff(attempts int) time.Duration {
+	delay := eventBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= eventMaxBackoff {
+			return eventMaxBackoff
+		}
+	}
+	return delay
+}
+
+// registerEventSubscribers binds the side effects of each domain event
+func registerEventSubscribers(b *EventBus, s *Services) {
+	subscribeEvent(b, EventUserRegistered, "email_verification", func(_ context.Context, e UserRegisteredEvent) error {
+		return s.Auth.SendEmailOTP(e.Email, e.FullName)
+	}, queue.MaxAttempts(otpJobAttempts))
+	subscribeEvent(b, EventUserRegistered, "phone_verification", func(_ context.Context, e UserRegisteredEvent) error {
+		if e.Phone == "" {
+			return nil
+		}
+		return s.Auth.SendOTP(e.Phone)
+	}, queue.MaxAttempts(otpJobAttempts))
+
+	subscribeEvent(b, EventOrderAccepted, "notify_customer", func(_ context.Context, e OrderAcceptedEvent) error {
+		return s.Yandas.notifyOrderAccepted(e)
+	})
+	subscribeEvent(b, EventOrderAccepted, "webhook", func(_ context.Context, e OrderAcceptedEvent) error {
+		order, err := b.repos.Order.GetByID(e.OrderID)
+		if err != nil {
+			return err
+		}
+		s.Webhook.Dispatch(WebhookOrderAccepted, orderWebhookData(order))
+		return nil
+	})
+
+	subscribeEvent(b, EventMessageSent, "notify_recipient", func(_ context.Context, e MessageSentEvent) error {
+		return s.Notification.notifyNewMessage(e)
+	})
+}
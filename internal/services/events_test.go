@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/queue"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestEventBusRelayAndDeliver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	events := mocks.NewMockDomainEventRepository(ctrl)
+	repos := &repository.Repositories{DomainEvent: events}
+	mr := miniredis.RunT(t)
+	bus := NewEventBus(repos, queue.New(redis.NewClient(&redis.Options{Addr: mr.Addr()}), queue.NewMux()))
+
+	var delivered []string
+	for _, name := range []string{"first", "second"} {
+		name := name
+		subscribeEvent(bus, EventOrderAccepted, name, func(_ context.Context, e OrderAcceptedEvent) error {
+			delivered = append(delivered, name+":"+e.OrderID.String())
+			return nil
+		})
+	}
+
+	// Publishing writes to the outbox through the caller's transaction
+	orderID := uuid.New()
+	var stored *models.DomainEvent
+	events.EXPECT().Create(gomock.Any()).DoAndReturn(func(e *models.DomainEvent) error {
+		stored = e
+		stored.ID = uuid.New()
+		return nil
+	})
+	if err := bus.Publish(repos, EventOrderAccepted, orderID, OrderAcceptedEvent{OrderID: orderID}); err != nil {
+		t.Fatal(err)
+	}
+	if stored.Type != EventOrderAccepted || stored.AggregateID != orderID || stored.Status != "pending" {
+		t.Fatalf("unexpected outbox row %+v", stored)
+	}
+
+	// The relay enqueues one job per subscriber and marks the event published
+	events.EXPECT().ClaimDue(eventBatchSize, eventLease).Return([]models.DomainEvent{*stored}, nil)
+	events.EXPECT().Update(gomock.Any()).DoAndReturn(func(e *models.DomainEvent) error {
+		if e.Status != "published" || e.PublishedAt == nil || e.Attempts != 1 {
+			t.Errorf("expected the event published, got %+v", e)
+		}
+		return nil
+	})
+	bus.relayDue()
+	jobs, _ := mr.List("queue:pending")
+	if len(jobs) != 2 {
+		t.Fatalf("expected a job per subscriber, got %d", len(jobs))
+	}
+
+	// Each job runs only its own subscriber
+	var job queue.Job
+	if err := json.Unmarshal([]byte(jobs[0]), &job); err != nil {
+		t.Fatal(err)
+	}
+	var payload eventJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	events.EXPECT().GetByID(stored.ID).Return(stored, nil)
+	if err := bus.deliver(context.Background(), payload); err != nil {
+		t.Fatal(err)
+	}
+	if len(delivered) != 1 || delivered[0] != payload.Subscriber+":"+orderID.String() {
+		t.Errorf("unexpected deliveries %v", delivered)
+	}
+}
+
+func TestEventBackoff(t *testing.T) {
+	if eventBackoff(1) != eventBaseBackoff || eventBackoff(2) != 2*eventBaseBackoff {
+		t.Errorf("expected doubling backoff, got %v %v", eventBackoff(1), eventBackoff(2))
+	}
+	if eventBackoff(20) != eventMaxBackoff {
+		t.Errorf("expected backoff capped at %v, got %v", eventMaxBackoff, eventBackoff(20))
+	}
+	var nilBus *EventBus
+	if err := nilBus.Publish(nil, EventMessageSent, uuid.New(), nil); err != nil {
+		t.Errorf("expected a nil bus to drop events, got %v", err)
+	}
+	nilBus.Flush()
+}
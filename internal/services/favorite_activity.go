@@ -0,0 +1,159 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+// Favorite activity types
+const (
+	FavoriteActivityOnline     = "online"
+	FavoriteActivityNewService = "new_service"
+	FavoriteActivityPriceDrop  = "price_drop"
+)
+
+const (
+	// A yandaş toggling availability on and off only announces coming online this often
+	favoriteOnlineCooldown = 6 * time.Hour
+	// How far back the favorites feed reaches
+	favoriteFeedWindow = 30 * 24 * time.Hour
+)
+
+// Broadcaster delivers realtime events to a user's open WebSocket connections
+type Broadcaster interface {
+	BroadcastToUser(userID string, msgType string, payload interface{})
+}
+
+// SetBroadcaster lets favorite activity reach connected users in realtime.
+// Only the API process has connections, so the worker leaves it unset.
+func (s *FavoriteService) SetBroadcaster(b Broadcaster) {
+	s.realtime = b
+}
+
+// Feed returns recent activity of the yandaşlar a user favorited
+func (s *FavoriteService) Feed(userID uuid.UUID, page, limit int) ([]PublicFavoriteActivity, int64, error) {
+	activities, total, err := s.repos.Favorite.ListFeed(userID, time.Now().Add(-favoriteFeedWindow), page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return publicFavoriteActivities(activities), total, nil
+}
+
+// YandasOnline records a yandaş becoming available, at most once per cooldown
+func (s *FavoriteService) YandasOnline(yandasID uuid.UUID) {
+	if s == nil {
+		return
+	}
+	if last, err := s.repos.Favorite.LastActivity(yandasID, FavoriteActivityOnline); err == nil && time.Since(last.CreatedAt) < favoriteOnlineCooldown {
+		return
+	}
+	s.publish(&models.FavoriteActivity{YandasID: yandasID, Type: FavoriteActivityOnline})
+}
+
+// ServiceAdded records a new service from a yandaş
+func (s *FavoriteService) ServiceAdded(service *models.YandasService) {
+	if s == nil {
+		return
+	}
+	price := service.BasePrice
+	s.publish(&models.FavoriteActivity{
+		YandasID:  service.YandasID,
+		Type:      FavoriteActivityNewService,
+		ServiceID: &service.ID,
+		Title:     service.Title,
+		NewPrice:  &price,
+		Currency:  service.Currency,
+	})
+}
+
+// PriceChanged records a service price change; only drops are announced
+func (s *FavoriteService) PriceChanged(service *models.YandasService, oldPrice currency.Money) {
+	if s == nil || service.BasePrice >= oldPrice || !serviceBookable(service) {
+		return
+	}
+	newPrice := service.BasePrice
+	s.publish(&models.FavoriteActivity{
+		YandasID:  service.YandasID,
+		Type:      FavoriteActivityPriceDrop,
+		ServiceID: &service.ID,
+		Title:     service.Title,
+		OldPrice:  &oldPrice,
+		NewPrice:  &newPrice,
+		Currency:  service.Currency,
+	})
+}
+
+// publish stores the activity, pushes it to connected followers and queues
+// their notifications. Failures are logged; they never fail the yandaş's action.
+func (s *FavoriteService) publish(activity *models.FavoriteActivity) {
+	if err := s.repos.Favorite.CreateActivity(activity); err != nil {
+		log.Printf("[FAVORITES] failed to record %s activity for %s: %v", activity.Type, activity.YandasID, err)
+		return
+	}
+
+	if s.realtime != nil {
+		followers, err := s.repos.Favorite.GetUserIDsByYandas(activity.YandasID)
+		if err != nil {
+			log.Printf("[FAVORITES] failed to load followers of %s: %v", activity.YandasID, err)
+		}
+		for _, userID := range followers {
+			s.realtime.BroadcastToUser(userID.String(), "favorite_activity", activity)
+		}
+	}
+
+	if err := s.jobs.Enqueue(JobFavoriteActivity, activityJob{ActivityID: activity.ID}); err != nil {
+		log.Printf("[FAVORITES] failed to queue notifications for activity %s: %v", activity.ID, err)
+	}
+}
+
+// notifyFollowers is the JobFavoriteActivity handler. Each follower gets an
+// in-app notification, and a push unless they muted favorite notifications.
+func (s *FavoriteService) notifyFollowers(activityID uuid.UUID) error {
+	activity, err := s.repos.OnPrimary().Favorite.GetActivity(activityID)
+	if err != nil {
+		return err
+	}
+	followers, err := s.repos.Favorite.GetUserIDsByYandas(activity.YandasID)
+	if err != nil {
+		return err
+	}
+
+	title, body := favoriteActivityText(activity)
+	data := map[string]interface{}{
+		"activity_id": activity.ID,
+		"yandas_id":   activity.YandasID,
+		"type":        activity.Type,
+	}
+	// Not retried per user: a retry would notify everyone already reached again
+	for _, userID := range followers {
+		if err := s.notifications.Send(userID, title, body, NotificationTypeFavorite, data); err != nil {
+			log.Printf("[FAVORITES] failed to notify %s about activity %s: %v", userID, activity.ID, err)
+		}
+	}
+	return nil
+}
+
+func favoriteActivityText(activity *models.FavoriteActivity) (string, string) {
+	name := "Favori yandaşınız"
+	if activity.Yandas != nil && activity.Yandas.User.FullName != "" {
+		name = activity.Yandas.User.FullName
+	}
+
+	switch activity.Type {
+	case FavoriteActivityNewService:
+		return name + " yeni bir hizmet ekledi", activity.Title
+	case FavoriteActivityPriceDrop:
+		if activity.OldPrice != nil && activity.NewPrice != nil {
+			return name + " fiyat düşürdü", fmt.Sprintf("%s: %s yerine %s", activity.Title,
+				formatAmount(*activity.OldPrice, currencyCode(activity.Currency)), formatAmount(*activity.NewPrice, currencyCode(activity.Currency)))
+		}
+		return name + " fiyat düşürdü", activity.Title
+	default:
+		return name + " şimdi müsait", name + " şu anda yeni işler kabul ediyor."
+	}
+}
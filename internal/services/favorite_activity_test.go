@@ -0,0 +1,98 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"gorm.io/gorm"
+)
+
+type recordingBroadcaster struct {
+	events map[string][]string
+}
+
+func (b *recordingBroadcaster) BroadcastToUser(userID string, msgType string, _ interface{}) {
+	b.events[userID] = append(b.events[userID], msgType)
+}
+
+func newTestFavoriteService(t *testing.T) (*FavoriteService, *mocks.MockFavoriteRepository, *mocks.MockNotificationRepository, *mocks.MockNotificationPreferenceRepository) {
+	ctrl := gomock.NewController(t)
+	favorites := mocks.NewMockFavoriteRepository(ctrl)
+	notifications := mocks.NewMockNotificationRepository(ctrl)
+	prefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{Favorite: favorites, Notification: notifications, NotificationPreference: prefs, UnitOfWork: uow}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	return NewFavoriteService(repos, NewNotificationService(repos, nil, nil, nil, nil, nil), nil), favorites, notifications, prefs
+}
+
+func TestFavoritePriceChangeOnlyAnnouncesDrops(t *testing.T) {
+	svc, favorites, _, _ := newTestFavoriteService(t)
+	service := &models.YandasService{ID: uuid.New(), YandasID: uuid.New(), Title: "Ev temizliği", BasePrice: 400, IsActive: true, Status: ServiceStatusActive}
+
+	// No expectations: a raise or unchanged price must not record anything
+	svc.PriceChanged(service, 350)
+	svc.PriceChanged(service, 400)
+
+	favorites.EXPECT().CreateActivity(gomock.Any()).DoAndReturn(func(a *models.FavoriteActivity) error {
+		if a.Type != FavoriteActivityPriceDrop || *a.OldPrice != 500 || *a.NewPrice != 400 {
+			t.Errorf("unexpected activity %+v", a)
+		}
+		return nil
+	})
+	svc.PriceChanged(service, 500)
+}
+
+func TestFavoriteOnlineRespectsCooldown(t *testing.T) {
+	svc, favorites, _, _ := newTestFavoriteService(t)
+	yandasID := uuid.New()
+	follower := uuid.New()
+	ws := &recordingBroadcaster{events: map[string][]string{}}
+	svc.SetBroadcaster(ws)
+
+	favorites.EXPECT().LastActivity(yandasID, FavoriteActivityOnline).
+		Return(&models.FavoriteActivity{CreatedAt: time.Now().Add(-time.Hour)}, nil)
+	svc.YandasOnline(yandasID)
+
+	favorites.EXPECT().LastActivity(yandasID, FavoriteActivityOnline).Return(nil, gorm.ErrRecordNotFound)
+	favorites.EXPECT().CreateActivity(gomock.Any()).Return(nil)
+	favorites.EXPECT().GetUserIDsByYandas(yandasID).Return([]uuid.UUID{follower}, nil)
+	svc.YandasOnline(yandasID)
+
+	if got := ws.events[follower.String()]; len(got) != 1 || got[0] != "favorite_activity" {
+		t.Errorf("expected one realtime event for the follower, got %v", got)
+	}
+}
+
+func TestFavoriteNotifyFollowers(t *testing.T) {
+	svc, favorites, notifications, prefs := newTestFavoriteService(t)
+	activity := &models.FavoriteActivity{
+		ID:       uuid.New(),
+		YandasID: uuid.New(),
+		Type:     FavoriteActivityNewService,
+		Title:    "Boya badana",
+		Yandas:   &models.YandasProfile{User: models.User{FullName: "Mehmet Usta"}},
+	}
+	followers := []uuid.UUID{uuid.New(), uuid.New()}
+
+	favorites.EXPECT().GetActivity(activity.ID).Return(activity, nil)
+	favorites.EXPECT().GetUserIDsByYandas(activity.YandasID).Return(followers, nil)
+	notifications.EXPECT().Create(gomock.Any()).DoAndReturn(func(n *models.Notification) error {
+		if n.Type != "favorite" || n.Title != "Mehmet Usta yeni bir hizmet ekledi" || n.Body != "Boya badana" {
+			t.Errorf("unexpected notification %+v", n)
+		}
+		return nil
+	}).Times(2)
+	prefs.EXPECT().GetByUserAndType(gomock.Any(), "favorite").Return(nil, gorm.ErrRecordNotFound).Times(2)
+
+	if err := svc.notifyFollowers(activity.ID); err != nil {
+		t.Fatal(err)
+	}
+}
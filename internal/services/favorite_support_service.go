@@ -1,21 +1,25 @@
 package services
 
 import (
-	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/queue"
 	"github.com/yandas/backend/internal/repository"
 )
 
 // FavoriteService handles favorite operations
 type FavoriteService struct {
-	repos *repository.Repositories
+	repos         *repository.Repositories
+	notifications *NotificationService
+	jobs          *queue.Queue
+	realtime      Broadcaster
 }
 
 // NewFavoriteService creates a new favorite service
-func NewFavoriteService(repos *repository.Repositories) *FavoriteService {
-	return &FavoriteService{repos: repos}
+func NewFavoriteService(repos *repository.Repositories, notifications *NotificationService, jobs *queue.Queue) *FavoriteService {
+	return &FavoriteService{repos: repos, notifications: notifications, jobs: jobs}
 }
 
 // Toggle adds or removes a yandaş from favorites
@@ -23,7 +27,7 @@ func (s *FavoriteService) Toggle(userID, yandasID uuid.UUID) (bool, error) {
 	// Verify yandaş exists
 	_, err := s.repos.YandasProfile.GetByID(yandasID)
 	if err != nil {
-		return false, errors.New("yandaş not found")
+		return false, ErrYandasNotFound
 	}
 
 	// Check if already favorited
@@ -49,8 +53,12 @@ func (s *FavoriteService) Toggle(userID, yandasID uuid.UUID) (bool, error) {
 }
 
 // List returns user favorites
-func (s *FavoriteService) List(userID uuid.UUID, page, limit int) ([]models.Favorite, int64, error) {
-	return s.repos.Favorite.ListByUser(userID, page, limit)
+func (s *FavoriteService) List(userID uuid.UUID, page, limit int) ([]PublicFavorite, int64, error) {
+	favorites, total, err := s.repos.Favorite.ListByUser(userID, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return publicFavorites(favorites), total, nil
 }
 
 // IsFavorited checks if a yandaş is favorited by user
@@ -65,12 +73,14 @@ func (s *FavoriteService) GetFavoriteIDs(userID uuid.UUID) ([]uuid.UUID, error)
 
 // SupportService handles user-facing support operations
 type SupportService struct {
-	repos *repository.Repositories
+	repos         *repository.Repositories
+	notifications *NotificationService
+	activity      *ActivityService
 }
 
 // NewSupportService creates a new support service
-func NewSupportService(repos *repository.Repositories) *SupportService {
-	return &SupportService{repos: repos}
+func NewSupportService(repos *repository.Repositories, notifications *NotificationService, activity *ActivityService) *SupportService {
+	return &SupportService{repos: repos, notifications: notifications, activity: activity}
 }
 
 // CreateTicketInput represents support ticket creation data
@@ -103,10 +113,21 @@ func (s *SupportService) CreateTicket(userID uuid.UUID, input *CreateTicketInput
 			ticket.OrderID = &orderID
 		}
 	}
+	applySLA(ticket, time.Now())
 
-	if err := s.repos.Support.CreateTicket(ticket); err != nil {
+	err := s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.Support.CreateTicket(ticket); err != nil {
+			return err
+		}
+		return s.activity.Record(tx, ActivityTicketCreated, ticket.ID, &userID, ticket.Subject, map[string]interface{}{
+			"category": ticket.Category,
+			"priority": ticket.Priority,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.activity.Flush()
 
 	return ticket, nil
 }
@@ -118,13 +139,13 @@ func (s *SupportService) ListUserTickets(userID uuid.UUID, page, limit int) ([]m
 
 // GetUserTicket returns a specific support ticket (user-scoped)
 func (s *SupportService) GetUserTicket(userID uuid.UUID, ticketID uuid.UUID) (*models.SupportTicket, error) {
-	ticket, err := s.repos.Support.GetTicket(ticketID)
+	ticket, err := s.repos.Support.GetTicketForOwner(ticketID)
 	if err != nil {
-		return nil, errors.New("ticket not found")
+		return nil, ErrTicketNotFound
 	}
 
 	if ticket.UserID != userID {
-		return nil, errors.New("unauthorized")
+		return nil, ErrNotOwner
 	}
 
 	return ticket, nil
@@ -134,11 +155,11 @@ func (s *SupportService) GetUserTicket(userID uuid.UUID, ticketID uuid.UUID) (*m
 func (s *SupportService) ReplyTicket(userID uuid.UUID, ticketID uuid.UUID, content string) (*models.SupportMessage, error) {
 	ticket, err := s.repos.Support.GetTicket(ticketID)
 	if err != nil {
-		return nil, errors.New("ticket not found")
+		return nil, ErrTicketNotFound
 	}
 
 	if ticket.UserID != userID {
-		return nil, errors.New("unauthorized")
+		return nil, ErrNotOwner
 	}
 
 	msg := &models.SupportMessage{
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Version is the API version reported by the health endpoints
+const Version = "1.0.0"
+
+// Overall and per-dependency health states
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+)
+
+// healthCheckTimeout bounds each dependency check so a hung dependency
+// cannot hang the health endpoint
+const healthCheckTimeout = 2 * time.Second
+
+// ClientCounter reports the number of open WebSocket connections
+type ClientCounter interface {
+	ClientCount() int
+}
+
+// HealthService checks the API's dependencies
+type HealthService struct {
+	repos *repository.Repositories
+	cfg   *config.Config
+	redis *redis.Client
+	hub   ClientCounter
+}
+
+func NewHealthService(repos *repository.Repositories, cfg *config.Config, redis *redis.Client) *HealthService {
+	return &HealthService{repos: repos, cfg: cfg, redis: redis}
+}
+
+// SetHub lets the report include connected WebSocket clients.
+// Only the API process has connections, so the worker leaves it unset.
+func (s *HealthService) SetHub(hub ClientCounter) {
+	s.hub = hub
+}
+
+// DependencyHealth is the result of checking one dependency
+type DependencyHealth struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// MigrationHealth compares the applied migrations with the ones the binary ships
+type MigrationHealth struct {
+	Status  string   `json:"status"`
+	Applied int      `json:"applied"`
+	Pending []string `json:"pending"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// IntegrationHealth reports which external providers have credentials configured
+type IntegrationHealth struct {
+	SMTP           bool `json:"smtp"`
+	Twilio         bool `json:"twilio"`
+	Agora          bool `json:"agora"`
+	AgoraRecording bool `json:"agora_recording"`
+}
+
+// HealthReport is the detailed status shown on the admin dashboard
+type HealthReport struct {
+	Status           string            `json:"status"`
+	Version          string            `json:"version"`
+	CheckedAt        time.Time         `json:"checked_at"`
+	Database         DependencyHealth  `json:"database"`
+	Redis            DependencyHealth  `json:"redis"`
+	Migrations       MigrationHealth   `json:"migrations"`
+	Integrations     IntegrationHealth `json:"integrations"`
+	WebSocketClients *int              `json:"websocket_clients"`
+}
+
+// Live reports whether the API can serve requests, which needs the database
+func (s *HealthService) Live(ctx context.Context) bool {
+	return s.checkDatabase(ctx).Status == HealthOK
+}
+
+// Report checks every dependency. The API is down without the database and
+// degraded when Redis is unreachable or migrations are pending.
+func (s *HealthService) Report(ctx context.Context) *HealthReport {
+	report := &HealthReport{
+		Version:      Version,
+		CheckedAt:    time.Now(),
+		Database:     s.checkDatabase(ctx),
+		Redis:        s.checkRedis(ctx),
+		Integrations: s.integrations(),
+	}
+	if report.Database.Status == HealthOK {
+		report.Migrations = s.checkMigrations()
+	} else {
+		report.Migrations = MigrationHealth{Status: HealthDown, Pending: []string{}, Error: "database unavailable"}
+	}
+	if s.hub != nil {
+		clients := s.hub.ClientCount()
+		report.WebSocketClients = &clients
+	}
+
+	switch {
+	case report.Database.Status != HealthOK:
+		report.Status = HealthDown
+	case report.Redis.Status != HealthOK, report.Migrations.Status != HealthOK:
+		report.Status = HealthDegraded
+	default:
+		report.Status = HealthOK
+	}
+	return report
+}
+
+func (s *HealthService) checkDatabase(ctx context.Context) DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	return timedCheck(func() error { return s.repos.System.Ping(ctx) })
+}
+
+func (s *HealthService) checkRedis(ctx context.Context) DependencyHealth {
+	if s.redis == nil {
+		return DependencyHealth{Status: HealthDown, Error: "not configured"}
+	}
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	return timedCheck(func() error { return s.redis.Ping(ctx).Err() })
+}
+
+func (s *HealthService) checkMigrations() MigrationHealth {
+	statuses, err := s.repos.System.MigrationStatus()
+	if err != nil {
+		return MigrationHealth{Status: HealthDown, Pending: []string{}, Error: err.Error()}
+	}
+	health := MigrationHealth{Status: HealthOK, Pending: []string{}}
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			health.Applied++
+			continue
+		}
+		health.Pending = append(health.Pending, fmt.Sprintf("%06d_%s", status.Version, status.Name))
+	}
+	if len(health.Pending) > 0 {
+		health.Status = HealthDegraded
+	}
+	return health
+}
+
+func (s *HealthService) integrations() IntegrationHealth {
+	cfg := s.cfg
+	agora := cfg.AgoraAppID != "" && cfg.AgoraAppCertificate != ""
+	return IntegrationHealth{
+		SMTP:           cfg.SMTPUser != "" && cfg.SMTPPassword != "",
+		Twilio:         cfg.TwilioAccountSID != "" && cfg.TwilioAuthToken != "" && (cfg.TwilioFromNumber != "" || cfg.TwilioVerifySID != ""),
+		Agora:          agora,
+		AgoraRecording: agora && cfg.AgoraRecordingEnabled && cfg.AgoraCustomerID != "" && cfg.AgoraCustomerSecret != "",
+	}
+}
+
+// recentBackups is how many backups the backup status lists
+const recentBackups = 10
+
+// BackupHealth reports the database backups cmd/backup takes. It is degraded
+// when no backup has succeeded within twice the backup interval.
+type BackupHealth struct {
+	Status          string                  `json:"status"`
+	LastSucceededAt *time.Time              `json:"last_succeeded_at"`
+	Interval        string                  `json:"interval"`
+	Recent          []models.DatabaseBackup `json:"recent"`
+}
+
+// Backups reports when the database was last backed up, with the latest runs
+func (s *HealthService) Backups() (*BackupHealth, error) {
+	interval := s.cfg.BackupInterval
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	health := &BackupHealth{Status: HealthDegraded, Interval: interval.String()}
+
+	latest, err := s.repos.Backup.LatestSucceeded()
+	switch {
+	case err == nil:
+		finished := latest.StartedAt
+		if latest.FinishedAt != nil {
+			finished = *latest.FinishedAt
+		}
+		health.LastSucceededAt = &finished
+		if time.Since(latest.StartedAt) <= 2*interval {
+			health.Status = HealthOK
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	health.Recent, err = s.repos.Backup.List(recentBackups)
+	if err != nil {
+		return nil, err
+	}
+	return health, nil
+}
+
+// timedCheck runs check and reports its outcome with how long it took
+func timedCheck(check func() error) DependencyHealth {
+	start := time.Now()
+	err := check()
+	health := DependencyHealth{Status: HealthOK, LatencyMS: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		health.Status = HealthDown
+		health.Error = err.Error()
+	}
+	return health
+}
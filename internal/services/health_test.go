@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/database"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"gorm.io/gorm"
+)
+
+type fixedClientCounter int
+
+func (n fixedClientCounter) ClientCount() int { return int(n) }
+
+func TestHealthReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	system := mocks.NewMockSystemRepository(ctrl)
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	cfg := &config.Config{SMTPUser: "mailer", SMTPPassword: "secret", AgoraAppID: "app"}
+	svc := NewHealthService(&repository.Repositories{System: system}, cfg, client)
+	svc.SetHub(fixedClientCounter(3))
+
+	applied := time.Now()
+	system.EXPECT().Ping(gomock.Any()).Return(nil)
+	system.EXPECT().MigrationStatus().Return([]database.MigrationStatus{
+		{Migration: database.Migration{Version: 1, Name: "baseline"}, AppliedAt: &applied},
+		{Migration: database.Migration{Version: 2, Name: "add_slugs"}},
+	}, nil)
+
+	report := svc.Report(context.Background())
+	if report.Status != HealthDegraded {
+		t.Errorf("expected a pending migration to degrade health, got %s", report.Status)
+	}
+	if report.Database.Status != HealthOK || report.Redis.Status != HealthOK {
+		t.Errorf("expected database and redis ok, got %+v / %+v", report.Database, report.Redis)
+	}
+	if report.Migrations.Applied != 1 || len(report.Migrations.Pending) != 1 || report.Migrations.Pending[0] != "000002_add_slugs" {
+		t.Errorf("unexpected migrations %+v", report.Migrations)
+	}
+	if !report.Integrations.SMTP || report.Integrations.Twilio || report.Integrations.Agora {
+		t.Errorf("unexpected integrations %+v", report.Integrations)
+	}
+	if report.WebSocketClients == nil || *report.WebSocketClients != 3 {
+		t.Errorf("expected 3 websocket clients, got %v", report.WebSocketClients)
+	}
+}
+
+func TestHealthReportDatabaseDown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	system := mocks.NewMockSystemRepository(ctrl)
+	svc := NewHealthService(&repository.Repositories{System: system}, &config.Config{}, nil)
+
+	system.EXPECT().Ping(gomock.Any()).Return(errors.New("connection refused")).Times(2)
+
+	if svc.Live(context.Background()) {
+		t.Error("expected the API not to be live without a database")
+	}
+	report := svc.Report(context.Background())
+	if report.Status != HealthDown || report.Database.Error != "connection refused" {
+		t.Errorf("expected down with the database error, got %s %+v", report.Status, report.Database)
+	}
+	if report.WebSocketClients != nil {
+		t.Error("expected no websocket count without a hub")
+	}
+}
+
+func TestBackupHealth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	backups := mocks.NewMockBackupRepository(ctrl)
+	svc := NewHealthService(&repository.Repositories{Backup: backups}, &config.Config{BackupInterval: 24 * time.Hour}, nil)
+
+	finished := time.Now().Add(-50 * time.Hour)
+	recent := []models.DatabaseBackup{{Status: "failed"}, {Status: "succeeded", StartedAt: finished.Add(-time.Minute), FinishedAt: &finished}}
+	backups.EXPECT().LatestSucceeded().Return(&recent[1], nil)
+	backups.EXPECT().List(recentBackups).Return(recent, nil).Times(2)
+
+	health, err := svc.Backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if health.Status != HealthDegraded || health.LastSucceededAt == nil || !health.LastSucceededAt.Equal(finished) || len(health.Recent) != 2 {
+		t.Errorf("expected a stale backup to degrade health, got %+v", health)
+	}
+
+	backups.EXPECT().LatestSucceeded().Return(nil, gorm.ErrRecordNotFound)
+	health, err = svc.Backups()
+	if err != nil || health.Status != HealthDegraded || health.LastSucceededAt != nil {
+		t.Errorf("expected no backup to degrade health, got %+v, %v", health, err)
+	}
+}
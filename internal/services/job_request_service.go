@@ -0,0 +1,505 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/queue"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+var (
+	ErrJobRequestNotFound = notFoundError("job_request_not_found", "job request not found")
+	ErrJobRequestClosed   = conflictError("job_request_closed", "job request is no longer open")
+	ErrInvalidBudget      = validationError("invalid_budget", "budget_min must not exceed budget_max")
+	ErrBidNotFound        = notFoundError("bid_not_found", "bid not found")
+	ErrBidNotPending      = conflictError("bid_not_pending", "bid is no longer pending")
+	ErrBidNotAllowed      = unauthorizedError("bid_not_allowed", "job request does not match your services")
+	ErrOwnJobRequest      = unauthorizedError("own_job_request", "cannot bid on your own job request")
+)
+
+const (
+	// How long a job request takes bids unless the customer picks otherwise
+	JobRequestDefaultTTL = 48 * time.Hour
+	// JobRequestExpiryInterval is how often requests past their expiry are closed
+	JobRequestExpiryInterval = 15 * time.Minute
+	// Most yandaşlar told about a single new request
+	jobRequestBroadcastLimit = 100
+)
+
+// JobRequestService runs open job requests: customers post a job without
+// picking a yandaş, matching yandaşlar bid, and the accepted bid becomes an order
+type JobRequestService struct {
+	repos         *repository.Repositories
+	notifications *NotificationService
+	jobs          *queue.Queue
+	webhooks      *WebhookService
+	monitoring    *MonitoringService
+	chat          *ChatService
+	settings      *SettingsService
+	realtime      Broadcaster
+}
+
+func NewJobRequestService(repos *repository.Repositories, notifications *NotificationService, jobs *queue.Queue, webhooks *WebhookService, monitoring *MonitoringService, chat *ChatService, settings *SettingsService) *JobRequestService {
+	return &JobRequestService{repos: repos, notifications: notifications, jobs: jobs, webhooks: webhooks, monitoring: monitoring, chat: chat, settings: settings}
+}
+
+// SetBroadcaster lets job request events reach connected users in realtime.
+// Only the API process has connections, so the worker leaves it unset.
+func (s *JobRequestService) SetBroadcaster(b Broadcaster) {
+	s.realtime = b
+}
+
+// JobRequestInput represents job request data
+type JobRequestInput struct {
+	CategoryID      uuid.UUID       `json:"category_id" binding:"required"`
+	City            string          `json:"city" binding:"required,max=100"`
+	Title           string          `json:"title" binding:"required,max=255"`
+	Description     string          `json:"description"`
+	BudgetMin       *currency.Money `json:"budget_min" binding:"omitempty,min=0"`
+	BudgetMax       *currency.Money `json:"budget_max" binding:"omitempty,min=0"`
+	Currency        string          `json:"currency" binding:"omitempty,currency"` // of the budget and bids; defaults to TRY
+	ScheduledAt     *time.Time      `json:"scheduled_at"`
+	AddressID       *uuid.UUID      `json:"address_id"`
+	LocationAddress string          `json:"location_address"`
+	Latitude        float64         `json:"latitude"`
+	Longitude       float64         `json:"longitude"`
+	ExpiresInHours  int             `json:"expires_in_hours" binding:"omitempty,min=1,max=168"` // defaults to 48
+}
+
+// BidInput represents a yandaş's bid on a job request
+type BidInput struct {
+	ServiceID uuid.UUID      `json:"service_id" binding:"required"`
+	Amount    currency.Money `json:"amount" binding:"required,gt=0"`
+	Message   string         `json:"message" binding:"max=1000"`
+}
+
+// Create posts a job request and tells matching yandaşlar about it
+func (s *JobRequestService) Create(customerID uuid.UUID, input *JobRequestInput) (*models.JobRequest, error) {
+	if !s.settings.Bool(SettingFeatureJobRequests) {
+		return nil, ErrFeatureDisabled
+	}
+	category, err := s.repos.Category.GetByID(input.CategoryID)
+	if err != nil || !category.IsActive {
+		return nil, ErrCategoryNotFound
+	}
+	if input.BudgetMin != nil && input.BudgetMax != nil && *input.BudgetMin > *input.BudgetMax {
+		return nil, ErrInvalidBudget
+	}
+
+	ttl := JobRequestDefaultTTL
+	if input.ExpiresInHours > 0 {
+		ttl = time.Duration(input.ExpiresInHours) * time.Hour
+	}
+	request := &models.JobRequest{
+		CustomerID:  customerID,
+		CategoryID:  category.ID,
+		City:        input.City,
+		Title:       input.Title,
+		BudgetMin:   input.BudgetMin,
+		BudgetMax:   input.BudgetMax,
+		Currency:    currencyCode(input.Currency),
+		ScheduledAt: input.ScheduledAt,
+		Status:      "open",
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+	if input.Description != "" {
+		request.Description = &input.Description
+	}
+	if input.LocationAddress != "" {
+		request.LocationAddress = &input.LocationAddress
+	}
+	if input.Latitude != 0 && input.Longitude != 0 {
+		request.Latitude = &input.Latitude
+		request.Longitude = &input.Longitude
+	}
+
+	// A saved address overrides any free-form location
+	if input.AddressID != nil {
+		address, err := s.repos.Address.GetByID(*input.AddressID)
+		if err != nil || address.UserID != customerID {
+			return nil, ErrAddressNotFound
+		}
+		request.LocationAddress = &address.AddressText
+		request.Latitude = address.Latitude
+		request.Longitude = address.Longitude
+	}
+
+	if err := s.repos.JobRequest.Create(request); err != nil {
+		return nil, err
+	}
+	request.Category = category
+
+	s.broadcast(request)
+	return request, nil
+}
+
+// broadcast pushes a new request to connected matching yandaşlar and queues
+// their notifications. Failures are logged; they never fail the post.
+func (s *JobRequestService) broadcast(request *models.JobRequest) {
+	if s.realtime != nil {
+		userIDs, err := s.repos.JobRequest.MatchingYandasUserIDs(request, jobRequestBroadcastLimit)
+		if err != nil {
+			log.Printf("[JOBS] failed to load yandaşlar matching request %s: %v", request.ID, err)
+		}
+		for _, userID := range userIDs {
+			s.realtime.BroadcastToUser(userID.String(), "job_request", request)
+		}
+	}
+
+	if err := s.jobs.Enqueue(JobJobRequestPosted, jobRequestJob{JobRequestID: request.ID}); err != nil {
+		log.Printf("[JOBS] failed to queue notifications for request %s: %v", request.ID, err)
+	}
+}
+
+// notifyMatchingYandas is the JobJobRequestPosted handler
+func (s *JobRequestService) notifyMatchingYandas(requestID uuid.UUID) error {
+	request, err := s.repos.OnPrimary().JobRequest.GetByID(requestID)
+	if err != nil {
+		return err
+	}
+	if request.Status != "open" {
+		return nil
+	}
+	userIDs, err := s.repos.JobRequest.MatchingYandasUserIDs(request, jobRequestBroadcastLimit)
+	if err != nil {
+		return err
+	}
+
+	title := "Yeni iş talebi: " + request.Title
+	body := request.City
+	if request.Category != nil {
+		body = request.Category.Name + " · " + request.City
+	}
+	data := map[string]interface{}{"job_request_id": request.ID}
+	// Not retried per user: a retry would notify everyone already reached again
+	for _, userID := range userIDs {
+		if err := s.notifications.Send(userID, title, body, "order", data); err != nil {
+			log.Printf("[JOBS] failed to notify %s about request %s: %v", userID, request.ID, err)
+		}
+	}
+	return nil
+}
+
+// ListMine returns the customer's job requests with their pending bid counts
+func (s *JobRequestService) ListMine(customerID uuid.UUID, page, limit int, status string) ([]models.JobRequest, int64, error) {
+	return s.repos.JobRequest.ListByCustomer(customerID, page, limit, status)
+}
+
+// Get returns a job request. Its customer sees every bid; a yandaş sees only their own.
+func (s *JobRequestService) Get(requestID, userID uuid.UUID) (*models.JobRequest, error) {
+	request, err := s.repos.JobRequest.GetByID(requestID)
+	if err != nil {
+		return nil, ErrJobRequestNotFound
+	}
+
+	if request.CustomerID == userID {
+		bids, err := s.repos.JobRequest.ListBids(request.ID)
+		if err != nil {
+			return nil, err
+		}
+		request.Bids = bids
+		return request, nil
+	}
+
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrJobRequestNotFound
+	}
+	if bid, err := s.repos.JobRequest.GetBidByYandas(request.ID, profile.ID); err == nil {
+		request.Bids = []models.Bid{*bid}
+	}
+	return request, nil
+}
+
+// Cancel withdraws an open request; its pending bids are rejected
+func (s *JobRequestService) Cancel(requestID, customerID uuid.UUID) error {
+	request, err := s.repos.OnPrimary().JobRequest.GetByID(requestID)
+	if err != nil || request.CustomerID != customerID {
+		return ErrJobRequestNotFound
+	}
+
+	var rejected []uuid.UUID
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		closed, err := tx.JobRequest.Close(request.ID, "cancelled", nil, nil)
+		if err != nil {
+			return err
+		}
+		if !closed {
+			return ErrJobRequestClosed
+		}
+		rejected, err = tx.JobRequest.RejectPendingBids(request.ID, nil)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notifyRejected(request, rejected, "İş talebi iptal edildi")
+	return nil
+}
+
+// AcceptBid turns a bid into an accepted order and rejects the other bids
+func (s *JobRequestService) AcceptBid(requestID, bidID, customerID uuid.UUID) (*models.Order, error) {
+	request, err := s.repos.OnPrimary().JobRequest.GetByID(requestID)
+	if err != nil || request.CustomerID != customerID {
+		return nil, ErrJobRequestNotFound
+	}
+	if request.Status != "open" || !request.ExpiresAt.After(time.Now()) {
+		return nil, ErrJobRequestClosed
+	}
+
+	bid, err := s.repos.OnPrimary().JobRequest.GetBid(bidID)
+	if err != nil || bid.JobRequestID != request.ID {
+		return nil, ErrBidNotFound
+	}
+	if bid.Status != "pending" {
+		return nil, ErrBidNotPending
+	}
+	if bid.Yandas == nil || bid.Yandas.ApprovalStatus != "approved" {
+		return nil, ErrYandasUnavailable
+	}
+
+	order := orderFromBid(request, bid)
+	yandasUserID := bid.Yandas.UserID
+
+	var rejected []uuid.UUID
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.Order.Create(order); err != nil {
+			return err
+		}
+		closed, err := tx.JobRequest.Close(request.ID, "accepted", &bid.ID, &order.ID)
+		if err != nil {
+			return err
+		}
+		if !closed {
+			return ErrJobRequestClosed
+		}
+		bid.Status = "accepted"
+		if err := tx.JobRequest.UpdateBid(bid); err != nil {
+			return err
+		}
+		if rejected, err = tx.JobRequest.RejectPendingBids(request.ID, &bid.ID); err != nil {
+			return err
+		}
+		if err := recordOrderEvent(tx, order.ID, OrderEventCreated, "pending", &customerID, nil); err != nil {
+			return err
+		}
+		if err := recordOrderEvent(tx, order.ID, OrderEventAccepted, order.Status, &yandasUserID, nil); err != nil {
+			return err
+		}
+		return s.chat.OpenOrderConversation(tx, order, yandasUserID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.webhooks.Dispatch(WebhookOrderCreated, orderWebhookData(order))
+	s.monitoring.Record(MetricOrdersCreated)
+
+	data := map[string]interface{}{"job_request_id": request.ID, "bid_id": bid.ID, "order_id": order.ID}
+	if order.ConversationID != nil {
+		data["conversation_id"] = *order.ConversationID
+	}
+	if err := s.notifications.Send(yandasUserID, "Teklifiniz kabul edildi", request.Title, "order", data); err != nil {
+		log.Printf("[JOBS] failed to notify %s about accepted bid %s: %v", yandasUserID, bid.ID, err)
+	}
+	s.push(yandasUserID, "bid_accepted", data)
+	s.notifyRejected(request, rejected, "Teklifiniz seçilmedi")
+	s.chat.PostOrderEvent(order, yandasUserID, OrderEventAccepted, yandasUserID)
+
+	return order, nil
+}
+
+// orderFromBid builds the accepted order a bid turns into
+func orderFromBid(request *models.JobRequest, bid *models.Bid) *models.Order {
+	description := request.Title
+	if bid.Service != nil {
+		description = bid.Service.Title
+	}
+	return &models.Order{
+		CustomerID:      request.CustomerID,
+		YandasID:        bid.YandasID,
+		ServiceID:       bid.ServiceID,
+		AgreedPrice:     bid.Amount,
+		Currency:        request.Currency,
+		LocationAddress: request.LocationAddress,
+		Latitude:        request.Latitude,
+		Longitude:       request.Longitude,
+		ScheduledAt:     request.ScheduledAt,
+		CustomerNotes:   request.Description,
+		Status:          "accepted",
+		LineItems:       []models.OrderLineItem{{Kind: "base", Description: description, Amount: bid.Amount}},
+	}
+}
+
+// notifyRejected tells the yandaşlar whose bids were rejected that the request closed
+func (s *JobRequestService) notifyRejected(request *models.JobRequest, userIDs []uuid.UUID, title string) {
+	data := map[string]interface{}{"job_request_id": request.ID, "status": request.Status}
+	for _, userID := range userIDs {
+		if err := s.notifications.Send(userID, title, request.Title, "order", data); err != nil {
+			log.Printf("[JOBS] failed to notify %s about request %s: %v", userID, request.ID, err)
+		}
+		s.push(userID, "bid_rejected", data)
+	}
+}
+
+func (s *JobRequestService) push(userID uuid.UUID, event string, payload interface{}) {
+	if s.realtime != nil {
+		s.realtime.BroadcastToUser(userID.String(), event, payload)
+	}
+}
+
+// ListOpen returns open requests matching the yandaş's service cities and categories
+func (s *JobRequestService) ListOpen(userID uuid.UUID, page, limit int) ([]models.JobRequest, int64, error) {
+	profile, err := s.approvedProfile(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repos.JobRequest.ListOpenForYandas(profile.ID, time.Now(), page, limit)
+}
+
+// PlaceBid bids on an open request, or revises the yandaş's earlier bid on it
+func (s *JobRequestService) PlaceBid(userID, requestID uuid.UUID, input *BidInput) (*models.Bid, error) {
+	profile, err := s.approvedProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+	request, err := s.repos.OnPrimary().JobRequest.GetByID(requestID)
+	if err != nil {
+		return nil, ErrJobRequestNotFound
+	}
+	if request.Status != "open" || !request.ExpiresAt.After(time.Now()) {
+		return nil, ErrJobRequestClosed
+	}
+	if request.CustomerID == userID {
+		return nil, ErrOwnJobRequest
+	}
+
+	service, err := s.repos.Service.GetByID(input.ServiceID)
+	if err != nil || service.YandasID != profile.ID {
+		return nil, ErrServiceNotFound
+	}
+	if !serviceBookable(service) || service.CategoryID != request.CategoryID || !servesCity(profile, request.City) {
+		return nil, ErrBidNotAllowed
+	}
+
+	bid, err := s.repos.OnPrimary().JobRequest.GetBidByYandas(request.ID, profile.ID)
+	revised := err == nil
+	if revised && bid.Status != "pending" && bid.Status != "withdrawn" {
+		return nil, ErrBidNotPending
+	}
+	if !revised {
+		bid = &models.Bid{JobRequestID: request.ID, YandasID: profile.ID}
+	}
+	bid.ServiceID = service.ID
+	bid.Amount = input.Amount
+	bid.Message = nil
+	if input.Message != "" {
+		bid.Message = &input.Message
+	}
+	bid.Status = "pending"
+
+	if revised {
+		err = s.repos.JobRequest.UpdateBid(bid)
+	} else {
+		err = s.repos.JobRequest.CreateBid(bid)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	name := "Bir yandaş"
+	if profile.User.FullName != "" {
+		name = profile.User.FullName
+	}
+	data := map[string]interface{}{"job_request_id": request.ID, "bid_id": bid.ID}
+	body := fmt.Sprintf("%s %s teklif verdi.", name, formatAmount(bid.Amount, request.Currency))
+	if err := s.notifications.Send(request.CustomerID, "Yeni teklif: "+request.Title, body, "order", data); err != nil {
+		log.Printf("[JOBS] failed to notify %s about bid %s: %v", request.CustomerID, bid.ID, err)
+	}
+	s.push(request.CustomerID, "bid_received", bid)
+
+	return bid, nil
+}
+
+// WithdrawBid pulls back a pending bid
+func (s *JobRequestService) WithdrawBid(userID, bidID uuid.UUID) error {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return ErrYandasProfileNotFound
+	}
+	bid, err := s.repos.OnPrimary().JobRequest.GetBid(bidID)
+	if err != nil || bid.YandasID != profile.ID {
+		return ErrBidNotFound
+	}
+	if bid.Status != "pending" {
+		return ErrBidNotPending
+	}
+
+	bid.Status = "withdrawn"
+	if err := s.repos.JobRequest.UpdateBid(bid); err != nil {
+		return err
+	}
+
+	if request, err := s.repos.JobRequest.GetByID(bid.JobRequestID); err == nil {
+		s.push(request.CustomerID, "bid_withdrawn", map[string]interface{}{"job_request_id": request.ID, "bid_id": bid.ID})
+	}
+	return nil
+}
+
+// ListMyBids returns the yandaş's bids, newest first
+func (s *JobRequestService) ListMyBids(userID uuid.UUID, page, limit int) ([]models.Bid, int64, error) {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, 0, ErrYandasProfileNotFound
+	}
+	return s.repos.JobRequest.ListBidsByYandas(profile.ID, page, limit)
+}
+
+// ExpireStale closes requests past their expiry; the scheduler calls it every JobRequestExpiryInterval
+func (s *JobRequestService) ExpireStale() {
+	expired, err := s.repos.JobRequest.ExpireOpen(time.Now())
+	if err != nil {
+		log.Printf("[JOBS] failed to expire job requests: %v", err)
+		return
+	}
+
+	for i := range expired {
+		request := &expired[i]
+		rejected, err := s.repos.JobRequest.RejectPendingBids(request.ID, nil)
+		if err != nil {
+			log.Printf("[JOBS] failed to reject bids of expired request %s: %v", request.ID, err)
+		}
+		s.notifyRejected(request, rejected, "İş talebinin süresi doldu")
+
+		data := map[string]interface{}{"job_request_id": request.ID, "status": request.Status}
+		if err := s.notifications.Send(request.CustomerID, "İş talebinizin süresi doldu", request.Title, "order", data); err != nil {
+			log.Printf("[JOBS] failed to notify %s about expired request %s: %v", request.CustomerID, request.ID, err)
+		}
+	}
+}
+
+func (s *JobRequestService) approvedProfile(userID uuid.UUID) (*models.YandasProfile, error) {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+	if profile.ApprovalStatus != "approved" {
+		return nil, unauthorizedError("yandas_not_approved", "yandaş not approved")
+	}
+	return profile, nil
+}
+
+func servesCity(profile *models.YandasProfile, city string) bool {
+	for _, c := range profile.ServiceCities {
+		if c == city {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"gorm.io/gorm"
+)
+
+type jobRequestMocks struct {
+	requests *mocks.MockJobRequestRepository
+	profiles *mocks.MockYandasProfileRepository
+	services *mocks.MockServiceRepository
+	orders   *mocks.MockOrderRepository
+	history  *mocks.MockOrderHistoryRepository
+	notifs   *mocks.MockNotificationRepository
+	realtime *recordingBroadcaster
+}
+
+func newTestJobRequestService(t *testing.T) (*JobRequestService, *jobRequestMocks) {
+	ctrl := gomock.NewController(t)
+	m := &jobRequestMocks{
+		requests: mocks.NewMockJobRequestRepository(ctrl),
+		profiles: mocks.NewMockYandasProfileRepository(ctrl),
+		services: mocks.NewMockServiceRepository(ctrl),
+		orders:   mocks.NewMockOrderRepository(ctrl),
+		history:  mocks.NewMockOrderHistoryRepository(ctrl),
+		notifs:   mocks.NewMockNotificationRepository(ctrl),
+		realtime: &recordingBroadcaster{events: map[string][]string{}},
+	}
+	prefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{
+		JobRequest:             m.requests,
+		YandasProfile:          m.profiles,
+		Service:                m.services,
+		Order:                  m.orders,
+		OrderHistory:           m.history,
+		Notification:           m.notifs,
+		NotificationPreference: prefs,
+		UnitOfWork:             uow,
+	}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	prefs.EXPECT().GetByUserAndType(gomock.Any(), gomock.Any()).Return(nil, gorm.ErrRecordNotFound).AnyTimes()
+
+	svc := NewJobRequestService(repos, NewNotificationService(repos, nil, nil, nil, nil, nil), nil, nil, nil, nil, nil)
+	svc.SetBroadcaster(m.realtime)
+	return svc, m
+}
+
+func TestAcceptBidCreatesAcceptedOrder(t *testing.T) {
+	svc, m := newTestJobRequestService(t)
+
+	customerID, yandasUserID, loserUserID := uuid.New(), uuid.New(), uuid.New()
+	request := &models.JobRequest{ID: uuid.New(), CustomerID: customerID, Title: "Bahçe düzenleme", Currency: "TRY", Status: "open", ExpiresAt: time.Now().Add(time.Hour)}
+	bid := &models.Bid{
+		ID:           uuid.New(),
+		JobRequestID: request.ID,
+		YandasID:     uuid.New(),
+		ServiceID:    uuid.New(),
+		Amount:       750,
+		Status:       "pending",
+		Yandas:       &models.YandasProfile{UserID: yandasUserID, ApprovalStatus: "approved"},
+	}
+
+	m.requests.EXPECT().GetByID(request.ID).Return(request, nil)
+	m.requests.EXPECT().GetBid(bid.ID).Return(bid, nil)
+	m.orders.EXPECT().Create(gomock.Any()).DoAndReturn(func(o *models.Order) error {
+		o.ID = uuid.New()
+		return nil
+	})
+	m.requests.EXPECT().Close(request.ID, "accepted", &bid.ID, gomock.Any()).Return(true, nil)
+	m.requests.EXPECT().UpdateBid(bid).Return(nil)
+	m.requests.EXPECT().RejectPendingBids(request.ID, &bid.ID).Return([]uuid.UUID{loserUserID}, nil)
+	m.history.EXPECT().Record(gomock.Any()).Return(nil).Times(2)
+	m.notifs.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+
+	order, err := svc.AcceptBid(request.ID, bid.ID, customerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != "accepted" || order.AgreedPrice != 750 || order.YandasID != bid.YandasID || len(order.LineItems) != 1 {
+		t.Errorf("unexpected order %+v", order)
+	}
+	if bid.Status != "accepted" {
+		t.Errorf("expected the bid accepted, got %s", bid.Status)
+	}
+	if got := m.realtime.events[yandasUserID.String()]; len(got) != 1 || got[0] != "bid_accepted" {
+		t.Errorf("expected bid_accepted for the winner, got %v", got)
+	}
+	if got := m.realtime.events[loserUserID.String()]; len(got) != 1 || got[0] != "bid_rejected" {
+		t.Errorf("expected bid_rejected for the other bidder, got %v", got)
+	}
+}
+
+func TestAcceptBidLosesRaceToCancel(t *testing.T) {
+	svc, m := newTestJobRequestService(t)
+
+	customerID := uuid.New()
+	request := &models.JobRequest{ID: uuid.New(), CustomerID: customerID, Status: "open", ExpiresAt: time.Now().Add(time.Hour)}
+	bid := &models.Bid{ID: uuid.New(), JobRequestID: request.ID, Status: "pending", Yandas: &models.YandasProfile{ApprovalStatus: "approved"}}
+
+	m.requests.EXPECT().GetByID(request.ID).Return(request, nil)
+	m.requests.EXPECT().GetBid(bid.ID).Return(bid, nil)
+	m.orders.EXPECT().Create(gomock.Any()).Return(nil)
+	m.requests.EXPECT().Close(request.ID, "accepted", &bid.ID, gomock.Any()).Return(false, nil)
+
+	if _, err := svc.AcceptBid(request.ID, bid.ID, customerID); !errors.Is(err, ErrJobRequestClosed) {
+		t.Fatalf("expected ErrJobRequestClosed, got %v", err)
+	}
+}
+
+func TestPlaceBidRequiresMatchingService(t *testing.T) {
+	svc, m := newTestJobRequestService(t)
+
+	userID := uuid.New()
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: userID, ApprovalStatus: "approved", ServiceCities: pq.StringArray{"İzmir"}}
+	request := &models.JobRequest{ID: uuid.New(), CustomerID: uuid.New(), CategoryID: uuid.New(), City: "İzmir", Status: "open", ExpiresAt: time.Now().Add(time.Hour)}
+	service := &models.YandasService{ID: uuid.New(), YandasID: profile.ID, CategoryID: uuid.New(), IsActive: true, Status: ServiceStatusActive}
+
+	m.profiles.EXPECT().GetByUserID(userID).Return(profile, nil)
+	m.requests.EXPECT().GetByID(request.ID).Return(request, nil)
+	m.services.EXPECT().GetByID(service.ID).Return(service, nil)
+
+	_, err := svc.PlaceBid(userID, request.ID, &BidInput{ServiceID: service.ID, Amount: 300})
+	if !errors.Is(err, ErrBidNotAllowed) {
+		t.Fatalf("expected ErrBidNotAllowed for a service in another category, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/queue"
+)
+
+// Background job types, executed by cmd/worker
+const (
+	JobSendEmailOTP      = "email.otp"
+	JobSendWelcomeEmail  = "email.welcome"
+	JobSendSecurityEmail = "email.security_notice"
+	JobSendSMSOTP        = "sms.otp"
+	JobSendSMS           = "sms.send"
+	JobSendPush          = "push.send"
+	JobIssueReceipt      = "receipt.issue"
+	JobScreenApplication = "application.screen"
+	JobVerifyIdentity    = "application.kyc"
+	JobFavoriteActivity  = "favorite.activity"
+	JobJobRequestPosted  = "job_request.posted"
+	JobDeliverEvent      = "event.deliver"
+	JobFlushChatBatch    = "notification.chat_batch"
+	JobSendDigest        = "notification.digest"
+)
+
+// Codes expire within minutes, so OTP jobs give up early instead of arriving stale
+const otpJobAttempts = 3
+
+// emailJob and smsJob carry the recipient and notification type of messages
+// users can opt out of; the worker drops them if the channel is turned off
+type emailJob struct {
+	Email   string    `json:"email"`
+	Name    string    `json:"name"`
+	Message string    `json:"message,omitempty"`
+	UserID  uuid.UUID `json:"user_id,omitempty"`
+	Type    string    `json:"type,omitempty"`
+}
+
+type smsJob struct {
+	Phone  string    `json:"phone"`
+	Body   string    `json:"body,omitempty"`
+	UserID uuid.UUID `json:"user_id,omitempty"`
+	Type   string    `json:"type,omitempty"`
+}
+
+type pushJob struct {
+	UserID uuid.UUID              `json:"user_id"`
+	Title  string                 `json:"title"`
+	Body   string                 `json:"body"`
+	Data   map[string]interface{} `json:"data,omitempty"`
+}
+
+type orderJob struct {
+	OrderID uuid.UUID `json:"order_id"`
+}
+
+type profileJob struct {
+	ProfileID uuid.UUID `json:"profile_id"`
+}
+
+type activityJob struct {
+	ActivityID uuid.UUID `json:"activity_id"`
+}
+
+type jobRequestJob struct {
+	JobRequestID uuid.UUID `json:"job_request_id"`
+}
+
+type eventJob struct {
+	EventID    uuid.UUID `json:"event_id"`
+	Subscriber string    `json:"subscriber"`
+}
+
+// registerJobHandlers binds every job type to the service that executes it
+func registerJobHandlers(mux *queue.Mux, s *Services) {
+	queue.HandleJSON(mux, JobSendEmailOTP, func(_ context.Context, j emailJob) error {
+		return s.Auth.SendEmailOTP(j.Email, j.Name)
+	})
+	queue.HandleJSON(mux, JobSendWelcomeEmail, func(_ context.Context, j emailJob) error {
+		if s.Notification.optedOut(j.UserID, j.Type, NotificationChannelEmail) {
+			return nil
+		}
+		return s.Email.SendWelcomeEmail(j.Email, j.Name)
+	})
+	queue.HandleJSON(mux, JobSendSecurityEmail, func(_ context.Context, j emailJob) error {
+		if s.Notification.optedOut(j.UserID, j.Type, NotificationChannelEmail) {
+			return nil
+		}
+		return s.Email.SendSecurityNoticeEmail(j.Email, j.Name, j.Message)
+	})
+	queue.HandleJSON(mux, JobSendSMSOTP, func(_ context.Context, j smsJob) error {
+		return s.Auth.SendOTP(j.Phone)
+	})
+	queue.HandleJSON(mux, JobSendSMS, func(_ context.Context, j smsJob) error {
+		if s.Notification.optedOut(j.UserID, j.Type, NotificationChannelSMS) {
+			return nil
+		}
+		return s.Auth.sendSMS(j.Phone, j.Body)
+	})
+	queue.HandleJSON(mux, JobSendPush, func(_ context.Context, j pushJob) error {
+		return s.Notification.sendPush(j.UserID, j.Title, j.Body, j.Data)
+	})
+	queue.HandleJSON(mux, JobIssueReceipt, func(_ context.Context, j orderJob) error {
+		return s.Receipt.issue(j.OrderID)
+	})
+	queue.HandleJSON(mux, JobScreenApplication, func(_ context.Context, j profileJob) error {
+		_, err := s.Screening.ScreenApplication(j.ProfileID)
+		return err
+	})
+	queue.HandleJSON(mux, JobVerifyIdentity, func(_ context.Context, j profileJob) error {
+		_, err := s.KYC.VerifyApplication(j.ProfileID)
+		return err
+	})
+	queue.HandleJSON(mux, JobFavoriteActivity, func(_ context.Context, j activityJob) error {
+		return s.Favorite.notifyFollowers(j.ActivityID)
+	})
+	queue.HandleJSON(mux, JobJobRequestPosted, func(_ context.Context, j jobRequestJob) error {
+		return s.JobRequest.notifyMatchingYandas(j.JobRequestID)
+	})
+	queue.HandleJSON(mux, JobDeliverEvent, func(ctx context.Context, j eventJob) error {
+		return s.Events.deliver(ctx, j)
+	})
+	queue.HandleJSON(mux, JobFlushChatBatch, func(_ context.Context, j chatBatchJob) error {
+		return s.Notification.flushChatBatch(j)
+	})
+	queue.HandleJSON(mux, JobSendDigest, func(_ context.Context, j digestJob) error {
+		return s.Notification.SendDigest(j.UserID)
+	})
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/queue"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/kyc"
+)
+
+// kycTimeout bounds a single submission to the provider
+const kycTimeout = 30 * time.Second
+
+// Identity check statuses; pending, verified and rejected come from the
+// provider, skipped and failed are recorded when no check could be made
+const (
+	KYCPending  = kyc.StatusPending
+	KYCVerified = kyc.StatusVerified
+	KYCRejected = kyc.StatusRejected
+	KYCSkipped  = "skipped"
+	KYCFailed   = "failed"
+)
+
+// ErrUnknownKYCProvider is returned for result webhooks from a provider that is not configured
+var ErrUnknownKYCProvider = notFoundError("unknown_kyc_provider", "unknown KYC provider")
+
+// KYCService matches an applicant's selfie with their kimlik through a KYC
+// provider. Like OCR screening it only informs the admin review; it never
+// approves or rejects an application by itself.
+type KYCService struct {
+	repos       *repository.Repositories
+	provider    kyc.Provider
+	storagePath string
+	jobs        *queue.Queue
+}
+
+func NewKYCService(repos *repository.Repositories, provider kyc.Provider, storagePath string, jobs *queue.Queue) *KYCService {
+	return &KYCService{repos: repos, provider: provider, storagePath: storagePath, jobs: jobs}
+}
+
+// Enabled reports whether a KYC provider is configured
+func (s *KYCService) Enabled() bool {
+	return s.provider != nil
+}
+
+// KYCOutcome is the identity check of an application as shown to admins
+type KYCOutcome struct {
+	Provider  string     `json:"provider"`
+	Reference *string    `json:"reference,omitempty"`
+	Status    string     `json:"status"`
+	Score     *float64   `json:"score,omitempty"`
+	Reason    *string    `json:"reason,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
+// kycOutcome returns the identity check stored on a profile, or nil if none was made
+func kycOutcome(profile *models.YandasProfile) *KYCOutcome {
+	if profile.KYCProvider == nil || profile.KYCStatus == nil {
+		return nil
+	}
+	return &KYCOutcome{
+		Provider:  *profile.KYCProvider,
+		Reference: profile.KYCReference,
+		Status:    *profile.KYCStatus,
+		Score:     profile.KYCScore,
+		Reason:    profile.KYCReason,
+		CheckedAt: profile.KYCCheckedAt,
+	}
+}
+
+// VerifyApplication submits the applicant's selfie and kimlik to the provider
+// and stores the result on the profile. Asynchronous providers leave it
+// pending until their webhook reports back.
+func (s *KYCService) VerifyApplication(profileID uuid.UUID) (*KYCOutcome, error) {
+	if !s.Enabled() {
+		return nil, unavailableError("kyc_not_configured", "identity verification is not configured")
+	}
+
+	profile, err := s.repos.OnPrimary().YandasProfile.GetByID(profileID)
+	if err != nil {
+		return nil, notFoundError("application_not_found", "application not found")
+	}
+
+	provider := s.provider.Name()
+	now := time.Now()
+	profile.KYCProvider = &provider
+	profile.KYCReference = nil
+	profile.KYCScore = nil
+	profile.KYCReason = nil
+	profile.KYCCheckedAt = &now
+
+	selfie, hasSelfie := uploadPath(s.storagePath, profile.SelfieURL)
+	front, hasFront := uploadPath(s.storagePath, profile.KimlikOnURL)
+	if !hasSelfie || !hasFront {
+		return s.record(profile, KYCSkipped, "selfie or kimlik front missing")
+	}
+	back, _ := uploadPath(s.storagePath, profile.KimlikArkaURL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), kycTimeout)
+	defer cancel()
+
+	result, err := s.provider.Verify(ctx, &kyc.Check{
+		ApplicantID: profile.ID.String(),
+		FullName:    profile.User.FullName,
+		SelfiePath:  selfie,
+		IDFrontPath: front,
+		IDBackPath:  back,
+	})
+	if err != nil {
+		return s.record(profile, KYCFailed, err.Error())
+	}
+
+	profile.KYCReference = stringOrNil(result.Reference)
+	profile.KYCScore = result.Score
+	return s.record(profile, result.Status, result.Reason)
+}
+
+// VerifyApplicationAsync queues VerifyApplication as a background job
+func (s *KYCService) VerifyApplicationAsync(profileID uuid.UUID) {
+	if !s.Enabled() {
+		return
+	}
+	if err := s.jobs.Enqueue(JobVerifyIdentity, profileJob{ProfileID: profileID}); err != nil {
+		log.Printf("[KYC] queueing verification %s failed: %v", profileID, err)
+	}
+}
+
+// HandleResult records a result posted by the provider for a pending check
+func (s *KYCService) HandleResult(providerName string, r *http.Request) error {
+	parser, ok := s.provider.(kyc.ResultParser)
+	if !ok || s.provider.Name() != providerName {
+		return ErrUnknownKYCProvider
+	}
+
+	result, err := parser.ParseResult(r)
+	if err != nil {
+		return err
+	}
+
+	profile, err := s.repos.OnPrimary().YandasProfile.GetByKYCReference(providerName, result.Reference)
+	if err != nil {
+		log.Printf("[KYC] %s result for unknown check %s", providerName, result.Reference)
+		return nil
+	}
+	if profile.KYCStatus == nil || *profile.KYCStatus != KYCPending {
+		log.Printf("[KYC] %s result for check %s that is no longer pending", providerName, result.Reference)
+		return nil
+	}
+
+	now := time.Now()
+	profile.KYCCheckedAt = &now
+	if result.Score != nil {
+		profile.KYCScore = result.Score
+	}
+	_, err = s.record(profile, result.Status, result.Reason)
+	return err
+}
+
+func (s *KYCService) record(profile *models.YandasProfile, status, reason string) (*KYCOutcome, error) {
+	profile.KYCStatus = &status
+	profile.KYCReason = stringOrNil(reason)
+	if err := s.repos.YandasProfile.UpdateKYC(profile); err != nil {
+		return nil, err
+	}
+	return kycOutcome(profile), nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/kyc"
+)
+
+func newTestKYC(t *testing.T) (*KYCService, *mocks.MockYandasProfileRepository) {
+	ctrl := gomock.NewController(t)
+	profiles := mocks.NewMockYandasProfileRepository(ctrl)
+	svc := NewKYCService(&repository.Repositories{YandasProfile: profiles}, kyc.NewMock(), "/srv/uploads", nil)
+	return svc, profiles
+}
+
+func TestVerifyApplication(t *testing.T) {
+	svc, profiles := newTestKYC(t)
+
+	selfie, front := "/uploads/u1/selfie.jpg", "/uploads/u1/kimlik_on.jpg"
+	profile := &models.YandasProfile{ID: uuid.New(), SelfieURL: &selfie, KimlikOnURL: &front, User: models.User{FullName: "Ayşe Yılmaz"}}
+	profiles.EXPECT().GetByID(profile.ID).Return(profile, nil)
+	profiles.EXPECT().UpdateKYC(profile).Return(nil)
+
+	outcome, err := svc.VerifyApplication(profile.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if outcome.Provider != "mock" || outcome.Status != KYCPending || outcome.Reference == nil || *outcome.Reference != "mock-1" {
+		t.Fatalf("expected a pending mock check, got %+v", outcome)
+	}
+}
+
+func TestVerifyApplicationWithoutSelfie(t *testing.T) {
+	svc, profiles := newTestKYC(t)
+
+	front := "/uploads/u1/kimlik_on.jpg"
+	profile := &models.YandasProfile{ID: uuid.New(), KimlikOnURL: &front}
+	profiles.EXPECT().GetByID(profile.ID).Return(profile, nil)
+	profiles.EXPECT().UpdateKYC(profile).Return(nil)
+
+	outcome, err := svc.VerifyApplication(profile.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if outcome.Status != KYCSkipped || outcome.Reference != nil {
+		t.Fatalf("expected the check to be skipped, got %+v", outcome)
+	}
+}
+
+func TestHandleKYCResult(t *testing.T) {
+	provider, reference := "mock", "mock-7"
+	pending, verified := KYCPending, KYCVerified
+
+	tests := []struct {
+		name       string
+		provider   string
+		body       string
+		status     *string
+		wantErr    error
+		wantStatus string
+	}{
+		{name: "verified", provider: "mock", body: `{"reference":"mock-7","status":"verified","score":0.97}`, status: &pending, wantStatus: KYCVerified},
+		{name: "rejected", provider: "mock", body: `{"reference":"mock-7","status":"rejected","reason":"liveness"}`, status: &pending, wantStatus: KYCRejected},
+		{name: "already decided", provider: "mock", body: `{"reference":"mock-7","status":"rejected"}`, status: &verified},
+		{name: "unknown provider", provider: "onfido", body: `{}`, wantErr: ErrUnknownKYCProvider},
+		{name: "malformed", provider: "mock", body: `{"reference":"mock-7","status":"maybe"}`, wantErr: kyc.ErrInvalidWebhook},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, profiles := newTestKYC(t)
+
+			profile := &models.YandasProfile{ID: uuid.New(), KYCProvider: &provider, KYCReference: &reference, KYCStatus: tt.status}
+			profiles.EXPECT().GetByKYCReference(provider, reference).Return(profile, nil).MaxTimes(1)
+			if tt.wantStatus != "" {
+				profiles.EXPECT().UpdateKYC(profile).Return(nil)
+			}
+
+			err := svc.HandleResult(tt.provider, httptest.NewRequest("POST", "/api/v1/kyc/results/"+tt.provider, strings.NewReader(tt.body)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantStatus != "" && *profile.KYCStatus != tt.wantStatus {
+				t.Fatalf("expected status %s, got %s", tt.wantStatus, *profile.KYCStatus)
+			}
+		})
+	}
+}
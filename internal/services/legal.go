@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Kinds of legal documents
+const (
+	LegalTerms   = "terms"
+	LegalPrivacy = "privacy"
+	LegalKVKK    = "kvkk"
+)
+
+// DefaultLegalLocale is the locale of the binding texts users accept; other
+// locales are translations and fall back to it
+const DefaultLegalLocale = "tr"
+
+// Publishing a version drops the cached documents right away
+const legalCacheTTL = 10 * time.Minute
+
+const legalRequiredCacheKey = "legal_documents:required"
+
+// LegalLocales are the locales documents are published in
+var LegalLocales = []string{DefaultLegalLocale, "en"}
+
+var ErrLegalDocumentNotFound = notFoundError("legal_document_not_found", "legal document not found")
+
+// defaultLegalDocuments are the texts served before any version was published
+//
+//go:embed legal/*.md
+var defaultLegalDocuments embed.FS
+
+var defaultLegalTitles = map[string]string{
+	LegalTerms:   "Kullanım Koşulları",
+	LegalPrivacy: "Gizlilik Politikası",
+	LegalKVKK:    "KVKK Aydınlatma Metni",
+}
+
+// LegalService manages the versioned legal texts (terms, privacy policy, KVKK
+// notice) in each locale. Public pages read them through a Redis cache.
+type LegalService struct {
+	repos *repository.Repositories
+	redis *redis.Client
+}
+
+// NewLegalService creates a legal service; without Redis every read hits the database
+func NewLegalService(repos *repository.Repositories, redis *redis.Client) *LegalService {
+	return &LegalService{repos: repos, redis: redis}
+}
+
+// LegalDocumentInput is a new version of a document. Translations never
+// require consent; a Turkish version does unless it only corrects the last one.
+type LegalDocumentInput struct {
+	Kind            string `json:"kind" binding:"required,oneof=terms privacy kvkk"`
+	Locale          string `json:"locale" binding:"omitempty,oneof=tr en"` // defaults to tr
+	Title           string `json:"title" binding:"required,max=200"`
+	Content         string `json:"content" binding:"required"`
+	RequiresConsent *bool  `json:"requires_consent"` // defaults to true
+}
+
+func legalDocumentKey(kind, locale string) string {
+	return "legal_document:" + kind + ":" + locale
+}
+
+// Document returns the current version of a kind in locale, or in the
+// default locale when it has no translation
+func (s *LegalService) Document(kind, locale string) (*models.LegalDocument, error) {
+	if locale != DefaultLegalLocale {
+		document, err := s.cachedLatest(kind, locale)
+		if err == nil || !errors.Is(err, ErrLegalDocumentNotFound) {
+			return document, err
+		}
+	}
+	return s.cachedLatest(kind, DefaultLegalLocale)
+}
+
+func (s *LegalService) cachedLatest(kind, locale string) (*models.LegalDocument, error) {
+	ctx := context.Background()
+	key := legalDocumentKey(kind, locale)
+	if s.redis != nil {
+		if cached, err := s.redis.Get(ctx, key).Bytes(); err == nil {
+			var document models.LegalDocument
+			if json.Unmarshal(cached, &document) == nil {
+				return &document, nil
+			}
+		}
+	}
+
+	document, err := s.repos.LegalDocument.Latest(kind, locale)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrLegalDocumentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.redis != nil {
+		if data, err := json.Marshal(document); err == nil {
+			s.redis.Set(ctx, key, data, legalCacheTTL)
+		}
+	}
+	return document, nil
+}
+
+// Required returns the versions users must have accepted: per kind, the
+// newest Turkish version that requires consent. A nil service requires none.
+func (s *LegalService) Required() ([]models.LegalDocument, error) {
+	if s == nil {
+		return nil, nil
+	}
+	ctx := context.Background()
+	if s.redis != nil {
+		if cached, err := s.redis.Get(ctx, legalRequiredCacheKey).Bytes(); err == nil {
+			var documents []models.LegalDocument
+			if json.Unmarshal(cached, &documents) == nil {
+				return documents, nil
+			}
+		}
+	}
+
+	documents, err := s.repos.LegalDocument.Required(DefaultLegalLocale)
+	if err != nil {
+		return nil, err
+	}
+	if s.redis != nil {
+		if data, err := json.Marshal(documents); err == nil {
+			s.redis.Set(ctx, legalRequiredCacheKey, data, legalCacheTTL)
+		}
+	}
+	return documents, nil
+}
+
+// Publish stores the next version of a document in its locale
+func (s *LegalService) Publish(adminID uuid.UUID, input *LegalDocumentInput) (*models.LegalDocument, error) {
+	document := &models.LegalDocument{
+		Kind:            input.Kind,
+		Locale:          DefaultLegalLocale,
+		Title:           input.Title,
+		Content:         input.Content,
+		RequiresConsent: true,
+		PublishedBy:     adminID,
+	}
+	if input.Locale != "" {
+		document.Locale = input.Locale
+	}
+	if input.RequiresConsent != nil {
+		document.RequiresConsent = *input.RequiresConsent
+	}
+	if document.Locale != DefaultLegalLocale {
+		document.RequiresConsent = false
+	}
+
+	if err := s.repos.LegalDocument.Publish(document); err != nil {
+		return nil, err
+	}
+	s.invalidate(document.Kind, document.Locale)
+	return document, nil
+}
+
+// History returns published versions, newest first
+func (s *LegalService) History(kind, locale string, page, limit int) ([]models.LegalDocument, int64, error) {
+	return s.repos.LegalDocument.List(kind, locale, page, limit)
+}
+
+// GetByID returns one published version
+func (s *LegalService) GetByID(id uuid.UUID) (*models.LegalDocument, error) {
+	document, err := s.repos.LegalDocument.GetByID(id)
+	if err != nil {
+		return nil, ErrLegalDocumentNotFound
+	}
+	return document, nil
+}
+
+// EnsureDefaultDocuments publishes the built-in Turkish texts of kinds that
+// have none yet. They require no consent: nobody was asked to accept them.
+func (s *LegalService) EnsureDefaultDocuments() error {
+	kinds, err := s.repos.OnPrimary().LegalDocument.Kinds(DefaultLegalLocale)
+	if err != nil {
+		return err
+	}
+	published := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		published[kind] = true
+	}
+
+	for _, kind := range []string{LegalTerms, LegalPrivacy, LegalKVKK} {
+		if published[kind] {
+			continue
+		}
+		content, err := defaultLegalDocuments.ReadFile("legal/" + kind + ".md")
+		if err != nil {
+			return err
+		}
+		document := &models.LegalDocument{
+			Kind:        kind,
+			Locale:      DefaultLegalLocale,
+			Title:       defaultLegalTitles[kind],
+			Content:     string(content),
+			PublishedBy: uuid.Nil,
+		}
+		if err := s.repos.LegalDocument.Publish(document); err != nil {
+			return err
+		}
+		s.invalidate(kind, DefaultLegalLocale)
+	}
+	return nil
+}
+
+// invalidate drops the cached copies a new version replaces
+func (s *LegalService) invalidate(kind, locale string) {
+	if s.redis != nil {
+		s.redis.Del(context.Background(), legalDocumentKey(kind, locale), legalRequiredCacheKey)
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"gorm.io/gorm"
+)
+
+func newTestLegalService(t *testing.T) (*LegalService, *mocks.MockLegalDocumentRepository) {
+	ctrl := gomock.NewController(t)
+	documents := mocks.NewMockLegalDocumentRepository(ctrl)
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return NewLegalService(&repository.Repositories{LegalDocument: documents}, client), documents
+}
+
+func TestLegalDocumentFallsBackToTurkish(t *testing.T) {
+	svc, documents := newTestLegalService(t)
+	privacy := &models.LegalDocument{ID: uuid.New(), Kind: LegalPrivacy, Locale: DefaultLegalLocale, Version: 3}
+
+	documents.EXPECT().Latest(LegalPrivacy, "en").Return(nil, gorm.ErrRecordNotFound)
+	documents.EXPECT().Latest(LegalPrivacy, DefaultLegalLocale).Return(privacy, nil).Times(1)
+	document, err := svc.Document(LegalPrivacy, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document.ID != privacy.ID {
+		t.Fatalf("expected the Turkish version, got %+v", document)
+	}
+
+	// Served from the cache from now on
+	if document, err = svc.Document(LegalPrivacy, DefaultLegalLocale); err != nil || document.Version != 3 {
+		t.Fatalf("expected the cached version, got %+v, %v", document, err)
+	}
+
+	documents.EXPECT().Latest(LegalKVKK, DefaultLegalLocale).Return(nil, gorm.ErrRecordNotFound)
+	if _, err := svc.Document(LegalKVKK, DefaultLegalLocale); !errors.Is(err, ErrLegalDocumentNotFound) {
+		t.Fatalf("expected ErrLegalDocumentNotFound, got %v", err)
+	}
+}
+
+func TestLegalTranslationsNeverRequireConsent(t *testing.T) {
+	svc, documents := newTestLegalService(t)
+	requires := true
+
+	documents.EXPECT().Publish(gomock.Any()).Return(nil)
+	document, err := svc.Publish(uuid.New(), &LegalDocumentInput{
+		Kind: LegalTerms, Locale: "en", Title: "Terms of Service", Content: "...", RequiresConsent: &requires,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document.RequiresConsent {
+		t.Fatal("expected a translation not to require consent")
+	}
+}
+
+func TestEnsureDefaultDocumentsSeedsMissingKinds(t *testing.T) {
+	svc, documents := newTestLegalService(t)
+
+	documents.EXPECT().Kinds(DefaultLegalLocale).Return([]string{LegalTerms}, nil)
+	var seeded []models.LegalDocument
+	documents.EXPECT().Publish(gomock.Any()).DoAndReturn(func(d *models.LegalDocument) error {
+		seeded = append(seeded, *d)
+		return nil
+	}).Times(2)
+	if err := svc.EnsureDefaultDocuments(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range seeded {
+		if d.Kind == LegalTerms {
+			t.Error("expected published kinds to be left alone")
+		}
+		if d.RequiresConsent || d.Locale != DefaultLegalLocale || !strings.HasPrefix(d.Content, "# "+d.Title) {
+			t.Errorf("unexpected default document %+v", d)
+		}
+	}
+}
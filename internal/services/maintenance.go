@@ -0,0 +1,89 @@
+package services
+
+import (
+	"time"
+)
+
+// MaintenanceInterval is how often each API process checks whether a
+// scheduled maintenance window started or ended
+const MaintenanceInterval = 30 * time.Second
+
+// GlobalBroadcaster sends an event to every connected client
+type GlobalBroadcaster interface {
+	BroadcastToAll(msgType string, payload interface{})
+}
+
+// MaintenanceStatus is the maintenance state sent to clients
+type MaintenanceStatus struct {
+	Active   bool       `json:"active"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
+
+func (m *MaintenanceStatus) equal(other *MaintenanceStatus) bool {
+	sameTime := func(a, b *time.Time) bool {
+		return (a == nil && b == nil) || (a != nil && b != nil && a.Equal(*b))
+	}
+	return m.Active == other.Active && sameTime(m.StartsAt, other.StartsAt) && sameTime(m.EndsAt, other.EndsAt)
+}
+
+// SetBroadcaster lets maintenance notices reach connected clients and records
+// the current state to compare later checks against. Only the API process has
+// connections, so the worker leaves it unset.
+func (s *SettingsService) SetBroadcaster(b GlobalBroadcaster) {
+	status := s.Maintenance()
+	s.maintenanceMu.Lock()
+	s.realtime = b
+	s.lastMaintenance = &status
+	s.maintenanceMu.Unlock()
+}
+
+// Maintenance reports whether maintenance mode is on. It is on while switched
+// on by hand, or from a scheduled start until the expected end, if any.
+func (s *SettingsService) Maintenance() MaintenanceStatus {
+	return s.maintenanceAt(time.Now())
+}
+
+func (s *SettingsService) maintenanceAt(now time.Time) MaintenanceStatus {
+	var status MaintenanceStatus
+	if startsAt := s.Time(SettingMaintenanceStartsAt); !startsAt.IsZero() {
+		status.StartsAt = &startsAt
+	}
+	if endsAt := s.Time(SettingMaintenanceEndsAt); !endsAt.IsZero() {
+		status.EndsAt = &endsAt
+	}
+
+	scheduled := status.StartsAt != nil && !now.Before(*status.StartsAt) &&
+		(status.EndsAt == nil || now.Before(*status.EndsAt))
+	status.Active = s.Bool(SettingMaintenanceEnabled) || scheduled
+
+	// A window that is over is no longer worth announcing
+	if !status.Active && status.EndsAt != nil && !now.Before(*status.EndsAt) {
+		status.StartsAt, status.EndsAt = nil, nil
+	}
+	return status
+}
+
+// MaintenanceActive reports whether requests should be turned away, along
+// with the expected end of the maintenance when one is set
+func (s *SettingsService) MaintenanceActive() (bool, *time.Time) {
+	status := s.Maintenance()
+	return status.Active, status.EndsAt
+}
+
+// WatchMaintenance pushes a maintenance event to connected clients when the
+// state or schedule changed since the last check. The scheduler runs it every
+// MaintenanceInterval; settings updates run it right away.
+func (s *SettingsService) WatchMaintenance() {
+	status := s.Maintenance()
+
+	s.maintenanceMu.Lock()
+	last, realtime := s.lastMaintenance, s.realtime
+	s.lastMaintenance = &status
+	s.maintenanceMu.Unlock()
+
+	if realtime == nil || last == nil || last.equal(&status) {
+		return
+	}
+	realtime.BroadcastToAll("maintenance", status)
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+type recordingGlobalBroadcaster struct {
+	events []interface{}
+}
+
+func (b *recordingGlobalBroadcaster) BroadcastToAll(_ string, payload interface{}) {
+	b.events = append(b.events, payload)
+}
+
+func TestMaintenanceWindow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	settings := mocks.NewMockSettingRepository(ctrl)
+	svc := NewSettingsService(&repository.Repositories{Setting: settings}, &config.Config{}, nil)
+
+	var stored []models.Setting
+	settings.EXPECT().List().DoAndReturn(func() ([]models.Setting, error) { return stored, nil }).AnyTimes()
+
+	now := time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC)
+	if status := svc.maintenanceAt(now); status.Active || status.StartsAt != nil {
+		t.Errorf("expected no maintenance by default, got %+v", status)
+	}
+
+	stored = []models.Setting{
+		{Key: SettingMaintenanceStartsAt, Value: "2024-06-01T03:00:00Z"},
+		{Key: SettingMaintenanceEndsAt, Value: "2024-06-01T04:00:00Z"},
+	}
+	if status := svc.maintenanceAt(now); status.Active || status.StartsAt == nil {
+		t.Errorf("expected an upcoming window, got %+v", status)
+	}
+	if status := svc.maintenanceAt(now.Add(90 * time.Minute)); !status.Active {
+		t.Error("expected maintenance within the window")
+	}
+	if status := svc.maintenanceAt(now.Add(3 * time.Hour)); status.Active || status.StartsAt != nil {
+		t.Errorf("expected a finished window to be dropped, got %+v", status)
+	}
+
+	stored = []models.Setting{{Key: SettingMaintenanceEnabled, Value: "true"}}
+	if status := svc.maintenanceAt(now); !status.Active {
+		t.Error("expected maintenance switched on by hand")
+	}
+	if on := NewSettingsService(&repository.Repositories{Setting: settings}, &config.Config{MaintenanceMode: true}, nil); !on.Maintenance().Active {
+		t.Error("expected MAINTENANCE_MODE to switch maintenance on")
+	}
+}
+
+func TestWatchMaintenanceBroadcastsChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	settings := mocks.NewMockSettingRepository(ctrl)
+	svc := NewSettingsService(&repository.Repositories{Setting: settings}, &config.Config{}, nil)
+
+	var stored []models.Setting
+	settings.EXPECT().List().DoAndReturn(func() ([]models.Setting, error) { return stored, nil }).AnyTimes()
+
+	ws := &recordingGlobalBroadcaster{}
+	svc.SetBroadcaster(ws)
+	svc.WatchMaintenance()
+	if len(ws.events) != 0 {
+		t.Fatalf("expected no event while nothing changed, got %d", len(ws.events))
+	}
+
+	stored = []models.Setting{{Key: SettingMaintenanceEnabled, Value: "true"}}
+	svc.WatchMaintenance()
+	svc.WatchMaintenance()
+	stored = nil
+	svc.WatchMaintenance()
+
+	if len(ws.events) != 2 {
+		t.Fatalf("expected an event when maintenance starts and ends, got %d", len(ws.events))
+	}
+	if status := ws.events[0].(MaintenanceStatus); !status.Active {
+		t.Error("expected the first event to announce maintenance")
+	}
+	if status := ws.events[1].(MaintenanceStatus); status.Active {
+		t.Error("expected the second event to announce the end")
+	}
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/mail"
+)
+
+// Monitored metrics
+const (
+	MetricFailedLogins         = "failed_logins"
+	MetricOrdersCreated        = "orders_created"
+	MetricOTPFailures          = "otp_failures"
+	MetricPaymentWebhookErrors = "payment_webhook_errors"
+	MetricEmailBounces         = "email_bounces"
+	MetricEmailComplaints      = "email_complaints"
+)
+
+// emailSentMetric and emailFailureMetric name the per-provider send counters, e.g. emails_sent.ses
+func emailSentMetric(provider string) string    { return "emails_sent." + provider }
+func emailFailureMetric(provider string) string { return "email_failures." + provider }
+
+// Metrics lists every metric alert rules can watch
+var Metrics = []string{
+	MetricFailedLogins, MetricOrdersCreated, MetricOTPFailures, MetricPaymentWebhookErrors,
+	MetricEmailBounces, MetricEmailComplaints,
+	emailSentMetric(mail.ProviderSMTP), emailFailureMetric(mail.ProviderSMTP),
+	emailSentMetric(mail.ProviderSES), emailFailureMetric(mail.ProviderSES),
+	emailSentMetric(mail.ProviderSendGrid), emailFailureMetric(mail.ProviderSendGrid),
+}
+
+// Alert channels
+const (
+	AlertChannelEmail        = "email"
+	AlertChannelNotification = "notification"
+)
+
+var ErrAlertRuleNotFound = notFoundError("alert_rule_not_found", "alert rule not found")
+
+// MonitoringInterval is how often alert rules are evaluated
+const MonitoringInterval = time.Minute
+
+// metricRetention is how long per-minute counters are kept
+const metricRetention = 7 * 24 * time.Hour
+
+// DefaultAlertRules are created on first start so alerts work before anyone configures them
+var DefaultAlertRules = []models.AlertRule{
+	{Name: "Failed login spike", Metric: MetricFailedLogins, Condition: "above", Threshold: 50, WindowMinutes: 15, CooldownMinutes: 60, IsActive: true},
+	{Name: "OTP delivery failures", Metric: MetricOTPFailures, Condition: "above", Threshold: 20, WindowMinutes: 15, CooldownMinutes: 60, IsActive: true},
+	{Name: "Payment webhook errors", Metric: MetricPaymentWebhookErrors, Condition: "above", Threshold: 5, WindowMinutes: 60, CooldownMinutes: 120, IsActive: true},
+	{Name: "No new orders", Metric: MetricOrdersCreated, Condition: "below", Threshold: 1, WindowMinutes: 180, CooldownMinutes: 360, IsActive: false},
+}
+
+// MonitoringService counts business metrics and alerts admins when rules are crossed
+type MonitoringService struct {
+	repos         *repository.Repositories
+	email         *EmailService
+	notifications *NotificationService
+}
+
+func NewMonitoringService(repos *repository.Repositories, email *EmailService, notifications *NotificationService) *MonitoringService {
+	return &MonitoringService{repos: repos, email: email, notifications: notifications}
+}
+
+// Record counts one occurrence of a metric. It is safe to call on a nil service.
+func (s *MonitoringService) Record(metric string) {
+	if s == nil {
+		return
+	}
+	if err := s.repos.Monitoring.IncrementMetric(metric, time.Now().Truncate(time.Minute)); err != nil {
+		log.Printf("[MONITOR] failed to record %s: %v", metric, err)
+	}
+}
+
+// EnsureDefaultRules seeds DefaultAlertRules when no rules exist yet
+func (s *MonitoringService) EnsureDefaultRules() error {
+	if s.repos.Monitoring.CountRules() > 0 {
+		return nil
+	}
+	for _, rule := range DefaultAlertRules {
+		rule.Channels = []string{AlertChannelEmail, AlertChannelNotification}
+		if err := s.repos.Monitoring.CreateRule(&rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tick evaluates active rules and prunes old counters; the scheduler calls it every MonitoringInterval
+func (s *MonitoringService) Tick() {
+	now := time.Now()
+	s.evaluate(now)
+	if err := s.repos.Monitoring.PruneMetrics(now.Add(-metricRetention)); err != nil {
+		log.Printf("[MONITOR] failed to prune metrics: %v", err)
+	}
+}
+
+func (s *MonitoringService) evaluate(now time.Time) {
+	rules, err := s.repos.Monitoring.ListActiveRules()
+	if err != nil {
+		log.Printf("[MONITOR] failed to load rules: %v", err)
+		return
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.LastTriggeredAt != nil && now.Sub(*rule.LastTriggeredAt) < time.Duration(rule.CooldownMinutes)*time.Minute {
+			continue
+		}
+
+		value, err := s.repos.Monitoring.SumMetric(rule.Metric, now.Add(-time.Duration(rule.WindowMinutes)*time.Minute))
+		if err != nil {
+			log.Printf("[MONITOR] failed to read %s: %v", rule.Metric, err)
+			continue
+		}
+
+		if ruleCrossed(rule, value) {
+			s.trigger(rule, value, now)
+		}
+	}
+}
+
+func ruleCrossed(rule *models.AlertRule, value int64) bool {
+	if rule.Condition == "below" {
+		return value < rule.Threshold
+	}
+	return value > rule.Threshold
+}
+
+// trigger stores the alert event and notifies admins on the rule's channels
+func (s *MonitoringService) trigger(rule *models.AlertRule, value int64, now time.Time) {
+	comparison := "üzerinde"
+	if rule.Condition == "below" {
+		comparison = "altında"
+	}
+	message := fmt.Sprintf("%s: son %d dakikada %s = %d (eşik %d %s)",
+		rule.Name, rule.WindowMinutes, rule.Metric, value, rule.Threshold, comparison)
+
+	event := &models.AlertEvent{
+		RuleID:    rule.ID,
+		Metric:    rule.Metric,
+		Value:     value,
+		Threshold: rule.Threshold,
+		Condition: rule.Condition,
+		Message:   message,
+	}
+	if err := s.repos.Monitoring.CreateEvent(event); err != nil {
+		log.Printf("[MONITOR] failed to store alert: %v", err)
+	}
+
+	rule.LastTriggeredAt = &now
+	if err := s.repos.Monitoring.UpdateRule(rule); err != nil {
+		log.Printf("[MONITOR] failed to update rule %s: %v", rule.ID, err)
+	}
+
+	log.Printf("[MONITOR] alert: %s", message)
+
+	admins, _, err := s.repos.User.List(repository.UserFilter{Role: "admin"}, 1, 100)
+	if err != nil {
+		return
+	}
+	for _, channel := range rule.Channels {
+		for _, admin := range admins {
+			switch channel {
+			case AlertChannelNotification:
+				s.notifications.Send(admin.ID, "Sistem uyarısı: "+rule.Name, message, "system", map[string]interface{}{
+					"alert_event_id": event.ID.String(),
+					"metric":         rule.Metric,
+				})
+			case AlertChannelEmail:
+				if admin.Email != nil {
+					if err := s.email.SendAlertEmail(*admin.Email, rule.Name, message); err != nil {
+						log.Printf("[MONITOR] failed to email alert: %v", err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// AlertRuleInput represents alert rule data
+type AlertRuleInput struct {
+	Name            string   `json:"name" binding:"required,max=100"`
+	Metric          string   `json:"metric" binding:"required,oneof=failed_logins orders_created otp_failures payment_webhook_errors email_bounces email_complaints emails_sent.smtp email_failures.smtp emails_sent.ses email_failures.ses emails_sent.sendgrid email_failures.sendgrid"`
+	Condition       string   `json:"condition" binding:"required,oneof=above below"`
+	Threshold       int64    `json:"threshold" binding:"min=0"`
+	WindowMinutes   int      `json:"window_minutes" binding:"required,min=1,max=10080"`
+	CooldownMinutes int      `json:"cooldown_minutes" binding:"min=0,max=10080"`
+	Channels        []string `json:"channels" binding:"required,min=1,dive,oneof=email notification"`
+	IsActive        *bool    `json:"is_active"`
+}
+
+func (s *MonitoringService) ListRules() ([]models.AlertRule, error) {
+	return s.repos.Monitoring.ListRules()
+}
+
+func (s *MonitoringService) CreateRule(input *AlertRuleInput) (*models.AlertRule, error) {
+	rule := &models.AlertRule{IsActive: true}
+	applyAlertRuleInput(rule, input)
+	if err := s.repos.Monitoring.CreateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *MonitoringService) UpdateRule(id uuid.UUID, input *AlertRuleInput) (*models.AlertRule, error) {
+	rule, err := s.repos.Monitoring.GetRule(id)
+	if err != nil {
+		return nil, ErrAlertRuleNotFound
+	}
+	applyAlertRuleInput(rule, input)
+	if err := s.repos.Monitoring.UpdateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func applyAlertRuleInput(rule *models.AlertRule, input *AlertRuleInput) {
+	rule.Name = input.Name
+	rule.Metric = input.Metric
+	rule.Condition = input.Condition
+	rule.Threshold = input.Threshold
+	rule.WindowMinutes = input.WindowMinutes
+	rule.CooldownMinutes = input.CooldownMinutes
+	rule.Channels = input.Channels
+	if input.IsActive != nil {
+		rule.IsActive = *input.IsActive
+	}
+}
+
+func (s *MonitoringService) DeleteRule(id uuid.UUID) error {
+	if _, err := s.repos.Monitoring.GetRule(id); err != nil {
+		return ErrAlertRuleNotFound
+	}
+	return s.repos.Monitoring.DeleteRule(id)
+}
+
+func (s *MonitoringService) ListEvents(page, limit int, ruleID *uuid.UUID) ([]models.AlertEvent, int64, error) {
+	return s.repos.Monitoring.ListEvents(page, limit, ruleID)
+}
+
+// CurrentMetrics returns each metric's total over the last windowMinutes
+func (s *MonitoringService) CurrentMetrics(windowMinutes int) (map[string]int64, error) {
+	since := time.Now().Add(-time.Duration(windowMinutes) * time.Minute)
+	values := make(map[string]int64, len(Metrics))
+	for _, metric := range Metrics {
+		value, err := s.repos.Monitoring.SumMetric(metric, since)
+		if err != nil {
+			return nil, err
+		}
+		values[metric] = value
+	}
+	return values, nil
+}
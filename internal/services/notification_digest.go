@@ -0,0 +1,80 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+)
+
+const (
+	// Users who have not used the app for this long get the digest
+	digestInactivity = 24 * time.Hour
+	// The digest covers notifications from this far back; it runs daily
+	digestPeriod = 24 * time.Hour
+	// Most notifications listed in one digest; the rest are only counted
+	digestMaxItems   = 10
+	digestFetchLimit = 100
+)
+
+type digestJob struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// QueueDigests enqueues the daily email digest for every inactive user with
+// unread notifications from the last day
+func (s *NotificationService) QueueDigests() {
+	now := time.Now()
+	userIDs, err := s.repos.Notification.DigestRecipients(now.Add(-digestPeriod), now.Add(-digestInactivity))
+	if err != nil {
+		log.Printf("[NOTIFICATIONS] failed to find digest recipients: %v", err)
+		return
+	}
+	for _, userID := range userIDs {
+		if err := s.jobs.Enqueue(JobSendDigest, digestJob{UserID: userID}); err != nil {
+			log.Printf("[NOTIFICATIONS] failed to queue digest for %s: %v", userID, err)
+		}
+	}
+}
+
+// SendDigest emails a user their unread notifications of the last day,
+// leaving out types they excluded from the digest or turned email off for
+func (s *NotificationService) SendDigest(userID uuid.UUID) error {
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.Email == nil {
+		return nil
+	}
+	prefs, err := s.GetPreferences(userID)
+	if err != nil {
+		return err
+	}
+	included := make(map[string]bool, len(prefs))
+	for _, pref := range prefs {
+		included[pref.Type] = pref.DigestEnabled && pref.EmailEnabled
+	}
+
+	unread, err := s.repos.Notification.ListUnreadSince(userID, time.Now().Add(-digestPeriod), digestFetchLimit)
+	if err != nil {
+		return err
+	}
+	var items []models.Notification
+	for _, notif := range unread {
+		if included[notif.Type] {
+			items = append(items, notif)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	more := 0
+	if len(items) > digestMaxItems {
+		more = len(items) - digestMaxItems
+		items = items[:digestMaxItems]
+	}
+	return s.email.SendNotificationDigest(*user.Email, user.FullName, items, more)
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/queue"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"gorm.io/gorm"
+)
+
+func TestChatNotificationsAreBatched(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	conversations := mocks.NewMockConversationRepository(ctrl)
+	users := mocks.NewMockUserRepository(ctrl)
+	notifications := mocks.NewMockNotificationRepository(ctrl)
+	prefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{Conversation: conversations, User: users, Notification: notifications, NotificationPreference: prefs, UnitOfWork: uow}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	svc := NewNotificationService(repos, nil, client, nil, queue.New(client, queue.NewMux()), nil)
+
+	conv := &models.Conversation{ID: uuid.New(), CustomerID: uuid.New(), YandasID: uuid.New()}
+	sender := &models.User{ID: conv.YandasID, FullName: "Ayşe Yılmaz"}
+	conversations.EXPECT().GetByID(conv.ID).Return(conv, nil).AnyTimes()
+	users.EXPECT().GetByID(sender.ID).Return(sender, nil).AnyTimes()
+	prefs.EXPECT().GetByUserAndType(conv.CustomerID, "chat").Return(nil, gorm.ErrRecordNotFound).AnyTimes()
+	notifications.EXPECT().Create(gomock.Any()).Return(nil).Times(4)
+
+	// The first message is pushed, the next three only get in-app notifications
+	for i := 0; i < 4; i++ {
+		e := MessageSentEvent{MessageID: uuid.New(), ConversationID: conv.ID, SenderID: sender.ID, Preview: "Merhaba"}
+		if err := svc.notifyNewMessage(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if pending, _ := mr.List("queue:pending"); len(pending) != 1 {
+		t.Fatalf("expected one push for the first message, got %d", len(pending))
+	}
+	scheduled, _ := mr.ZMembers("queue:scheduled")
+	if len(scheduled) != 1 {
+		t.Fatalf("expected one summary to be scheduled, got %d", len(scheduled))
+	}
+
+	// When the window closes the held messages are summarised
+	var job queue.Job
+	if err := json.Unmarshal([]byte(scheduled[0]), &job); err != nil {
+		t.Fatal(err)
+	}
+	var batch chatBatchJob
+	if err := json.Unmarshal(job.Payload, &batch); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.flushChatBatch(batch); err != nil {
+		t.Fatal(err)
+	}
+	pending, _ := mr.List("queue:pending")
+	if len(pending) != 2 {
+		t.Fatalf("expected the summary push, got %d jobs", len(pending))
+	}
+	if err := json.Unmarshal([]byte(pending[0]), &job); err != nil {
+		t.Fatal(err)
+	}
+	var summary pushJob
+	if err := json.Unmarshal(job.Payload, &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.UserID != conv.CustomerID || summary.Title != "Ayşe Yılmaz" || summary.Body != "3 yeni mesaj" {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+
+	// Flushing closed the window: the next message is pushed at once
+	notifications.EXPECT().Create(gomock.Any()).Return(nil)
+	svc.notifyNewMessage(MessageSentEvent{MessageID: uuid.New(), ConversationID: conv.ID, SenderID: sender.ID, Preview: "Geldim"})
+	if pending, _ := mr.List("queue:pending"); len(pending) != 3 {
+		t.Errorf("expected the message pushed, got %d jobs", len(pending))
+	}
+}
+
+func TestSendDigestSkipsExcludedTypes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUserRepository(ctrl)
+	notifications := mocks.NewMockNotificationRepository(ctrl)
+	prefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+	repos := &repository.Repositories{User: users, Notification: notifications, NotificationPreference: prefs}
+	provider := &fakeMailProvider{}
+	email := &EmailService{repos: repos, cfg: &config.Config{}, provider: provider}
+	svc := NewNotificationService(repos, nil, nil, email, nil, nil)
+
+	address := "ali@example.com"
+	user := &models.User{ID: uuid.New(), Email: &address, FullName: "Ali"}
+	users.EXPECT().GetByID(user.ID).Return(user, nil)
+	users.EXPECT().IsEmailUndeliverable(address).Return(false)
+	prefs.EXPECT().ListByUser(user.ID).Return([]models.NotificationPreference{
+		{UserID: user.ID, Type: "promotion", PushEnabled: true, EmailEnabled: true, DigestEnabled: false},
+		{UserID: user.ID, Type: "favorite", PushEnabled: true, EmailEnabled: false, DigestEnabled: true},
+	}, nil)
+	notifications.EXPECT().ListUnreadSince(user.ID, gomock.Any(), digestFetchLimit).Return([]models.Notification{
+		{Title: "Siparişiniz kabul edildi", Body: "#YND-1042", Type: "order"},
+		{Title: "Kampanya", Body: "%20 indirim", Type: "promotion"},
+		{Title: "Favori yandaşınız çevrimiçi", Body: "Ayşe", Type: "favorite"},
+	}, nil)
+
+	if err := svc.SendDigest(user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(provider.bodies) != 1 {
+		t.Fatalf("expected one digest, got %d", len(provider.bodies))
+	}
+	body := provider.bodies[0]
+	if !strings.Contains(body, "Siparişiniz kabul edildi") || strings.Contains(body, "Kampanya") || strings.Contains(body, "Favori") {
+		t.Errorf("unexpected digest %s", body)
+	}
+}
+
+func TestOptedOutOfChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	prefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+	svc := NewNotificationService(&repository.Repositories{NotificationPreference: prefs}, nil, nil, nil, nil, nil)
+
+	userID := uuid.New()
+	prefs.EXPECT().GetByUserAndType(userID, NotificationTypeSystem).
+		Return(&models.NotificationPreference{PushEnabled: true, EmailEnabled: false, SMSEnabled: true}, nil).Times(2)
+	if !svc.optedOut(userID, NotificationTypeSystem, NotificationChannelEmail) {
+		t.Error("expected the user to have turned system emails off")
+	}
+	if svc.optedOut(userID, NotificationTypeSystem, NotificationChannelSMS) {
+		t.Error("expected system SMS to stay on")
+	}
+	// Security notices carry no type and are always sent
+	if svc.optedOut(userID, "", NotificationChannelEmail) || svc.optedOut(uuid.Nil, NotificationTypeSystem, NotificationChannelEmail) {
+		t.Error("expected messages without a user or type to be sent")
+	}
+}
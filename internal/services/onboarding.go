@@ -0,0 +1,335 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// Types of application questions
+const (
+	QuestionText   = "text"
+	QuestionNumber = "number"
+	QuestionYesNo  = "yes_no"
+	QuestionChoice = "choice"
+	QuestionFile   = "file" // answered with the URL of an uploaded document
+)
+
+// maxAnswerLength bounds a text answer
+const maxAnswerLength = 2000
+
+// ApplicationDocuments are the documents of the application a category can
+// require, in the order the form lists them
+var ApplicationDocuments = []string{"kimlik_on", "kimlik_arka", "ehliyet_on", "ehliyet_arka", "adli_sicil_pdf", "selfie"}
+
+var ErrApplicationQuestionNotFound = notFoundError("application_question_not_found", "application question not found")
+
+// OnboardingService manages the per-category yandaş application forms: the
+// rules on which documents a category requires and the questions it asks
+type OnboardingService struct {
+	repos *repository.Repositories
+}
+
+// NewOnboardingService creates an onboarding service
+func NewOnboardingService(repos *repository.Repositories) *OnboardingService {
+	return &OnboardingService{repos: repos}
+}
+
+// ApplicationForm is what an applicant fills in for a set of categories
+type ApplicationForm struct {
+	RequiredDocuments []DocumentRequirement        `json:"required_documents"`
+	Questions         []models.ApplicationQuestion `json:"questions"`
+}
+
+// DocumentRequirement is a document an application has to include
+type DocumentRequirement struct {
+	Document string  `json:"document"`
+	Hint     *string `json:"hint,omitempty"`
+}
+
+// DocumentRulesInput lists every document a category requires
+type DocumentRulesInput struct {
+	Rules []DocumentRuleInput `json:"rules" binding:"dive"`
+}
+
+// DocumentRuleInput makes a document mandatory for a category
+type DocumentRuleInput struct {
+	Document string  `json:"document" binding:"required"`
+	Hint     *string `json:"hint" binding:"omitempty,max=300"`
+}
+
+// ApplicationQuestionInput is an application question as admins set it up
+type ApplicationQuestionInput struct {
+	Label      string   `json:"label" binding:"required,max=200"`
+	HelpText   *string  `json:"help_text" binding:"omitempty,max=500"`
+	Type       string   `json:"type" binding:"required,oneof=text number yes_no choice file"`
+	Options    []string `json:"options"` // choice questions only
+	IsRequired bool     `json:"is_required"`
+	SortOrder  int      `json:"sort_order"`
+	IsActive   *bool    `json:"is_active"` // defaults to true
+}
+
+// ApplicationAnswerInput answers one question of the application form
+type ApplicationAnswerInput struct {
+	QuestionID uuid.UUID `json:"question_id" binding:"required"`
+	Value      string    `json:"value"`
+}
+
+// Form returns the combined application form of the categories
+func (s *OnboardingService) Form(categoryIDs []uuid.UUID) (*ApplicationForm, error) {
+	return loadApplicationForm(s.repos, categoryIDs)
+}
+
+// Questions returns every question of a category, inactive ones included
+func (s *OnboardingService) Questions(categoryID uuid.UUID) ([]models.ApplicationQuestion, error) {
+	return s.repos.ApplicationForm.ListQuestions([]uuid.UUID{categoryID}, false)
+}
+
+// CreateQuestion adds a question to a category's form
+func (s *OnboardingService) CreateQuestion(categoryID uuid.UUID, input *ApplicationQuestionInput) (*models.ApplicationQuestion, error) {
+	if _, err := s.repos.Category.GetByID(categoryID); err != nil {
+		return nil, ErrCategoryNotFound
+	}
+	question := &models.ApplicationQuestion{CategoryID: categoryID, IsActive: true}
+	if err := applyQuestionInput(question, input); err != nil {
+		return nil, err
+	}
+	if err := s.repos.ApplicationForm.CreateQuestion(question); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+// UpdateQuestion changes a question; answers already given are not affected
+func (s *OnboardingService) UpdateQuestion(id uuid.UUID, input *ApplicationQuestionInput) (*models.ApplicationQuestion, error) {
+	question, err := s.repos.OnPrimary().ApplicationForm.GetQuestion(id)
+	if err != nil {
+		return nil, ErrApplicationQuestionNotFound
+	}
+	if err := applyQuestionInput(question, input); err != nil {
+		return nil, err
+	}
+	if err := s.repos.ApplicationForm.UpdateQuestion(question); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+// DeleteQuestion removes a question from its form
+func (s *OnboardingService) DeleteQuestion(id uuid.UUID) error {
+	if _, err := s.repos.ApplicationForm.GetQuestion(id); err != nil {
+		return ErrApplicationQuestionNotFound
+	}
+	return s.repos.ApplicationForm.DeleteQuestion(id)
+}
+
+func applyQuestionInput(question *models.ApplicationQuestion, input *ApplicationQuestionInput) error {
+	var options []string
+	if input.Type == QuestionChoice {
+		for _, option := range input.Options {
+			if option = strings.TrimSpace(option); option != "" {
+				options = append(options, option)
+			}
+		}
+		if len(options) == 0 {
+			return validationError("invalid_question", "a choice question needs options")
+		}
+	}
+
+	question.Label = strings.TrimSpace(input.Label)
+	question.HelpText = input.HelpText
+	question.Type = input.Type
+	question.Options = pq.StringArray(options)
+	question.IsRequired = input.IsRequired
+	question.SortOrder = input.SortOrder
+	if input.IsActive != nil {
+		question.IsActive = *input.IsActive
+	}
+	return nil
+}
+
+// DocumentRules returns the documents a category requires
+func (s *OnboardingService) DocumentRules(categoryID uuid.UUID) ([]models.CategoryDocumentRule, error) {
+	return s.repos.DocumentRule.ListByCategories([]uuid.UUID{categoryID})
+}
+
+// SetDocumentRules replaces the documents a category requires. Applications
+// already submitted are held to the new rules when approved.
+func (s *OnboardingService) SetDocumentRules(categoryID uuid.UUID, input *DocumentRulesInput) ([]models.CategoryDocumentRule, error) {
+	if _, err := s.repos.Category.GetByID(categoryID); err != nil {
+		return nil, ErrCategoryNotFound
+	}
+	rules := []models.CategoryDocumentRule{}
+	seen := map[string]bool{}
+	for _, rule := range input.Rules {
+		if !slices.Contains(ApplicationDocuments, rule.Document) {
+			return nil, validationError("invalid_required_document", fmt.Sprintf("unknown document %q", rule.Document))
+		}
+		if seen[rule.Document] {
+			continue
+		}
+		seen[rule.Document] = true
+		rules = append(rules, models.CategoryDocumentRule{CategoryID: categoryID, Document: rule.Document, Hint: rule.Hint})
+	}
+	if err := s.repos.DocumentRule.Replace(categoryID, rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// requiredDocuments returns every document one of the categories requires,
+// with the hint of the first rule that has one
+func requiredDocuments(repos *repository.Repositories, categoryIDs []uuid.UUID) ([]DocumentRequirement, error) {
+	rules, err := repos.DocumentRule.ListByCategories(categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	hints := map[string]*string{}
+	required := map[string]bool{}
+	for _, rule := range rules {
+		required[rule.Document] = true
+		if hints[rule.Document] == nil {
+			hints[rule.Document] = rule.Hint
+		}
+	}
+	requirements := []DocumentRequirement{}
+	for _, document := range ApplicationDocuments {
+		if required[document] {
+			requirements = append(requirements, DocumentRequirement{Document: document, Hint: hints[document]})
+		}
+	}
+	return requirements, nil
+}
+
+// missingDocuments returns the documents the categories of an application
+// require that it lacks
+func missingDocuments(repos *repository.Repositories, profile *models.YandasProfile) ([]DocumentRequirement, error) {
+	required, err := requiredDocuments(repos, parseUUIDs(profile.CategoryIDs))
+	if err != nil {
+		return nil, err
+	}
+	fields := documentFields(profile)
+	missing := []DocumentRequirement{}
+	for _, requirement := range required {
+		if url := *fields[requirement.Document]; url == nil || *url == "" {
+			missing = append(missing, requirement)
+		}
+	}
+	return missing, nil
+}
+
+func missingDocumentsError(missing []DocumentRequirement) error {
+	names := make([]string, len(missing))
+	for i, requirement := range missing {
+		names[i] = requirement.Document
+	}
+	return conflictError("missing_documents", "application is missing required documents: "+strings.Join(names, ", "))
+}
+
+// documentFields returns the application documents of a profile by name
+func documentFields(profile *models.YandasProfile) map[string]**string {
+	return map[string]**string{
+		"kimlik_on":      &profile.KimlikOnURL,
+		"kimlik_arka":    &profile.KimlikArkaURL,
+		"ehliyet_on":     &profile.EhliyetOnURL,
+		"ehliyet_arka":   &profile.EhliyetArkaURL,
+		"adli_sicil_pdf": &profile.AdliSicilPDFURL,
+		"selfie":         &profile.SelfieURL,
+	}
+}
+
+// loadApplicationForm combines the forms of the categories: every document
+// one of them requires and all of their active questions
+func loadApplicationForm(repos *repository.Repositories, categoryIDs []uuid.UUID) (*ApplicationForm, error) {
+	for _, id := range categoryIDs {
+		category, err := repos.Category.GetByID(id)
+		if err != nil || !category.IsActive {
+			return nil, ErrCategoryNotFound
+		}
+	}
+
+	documents, err := requiredDocuments(repos, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := repos.ApplicationForm.ListQuestions(categoryIDs, true)
+	if err != nil {
+		return nil, err
+	}
+	return &ApplicationForm{RequiredDocuments: documents, Questions: questions}, nil
+}
+
+// Answers checks an application against the form, given the documents it
+// uploaded by name, and returns the answers to store
+func (f *ApplicationForm) Answers(documents map[string]string, inputs []ApplicationAnswerInput) ([]models.ApplicationAnswer, error) {
+	for _, requirement := range f.RequiredDocuments {
+		if documents[requirement.Document] == "" {
+			return nil, validationError("missing_document", fmt.Sprintf("%s is required", requirement.Document))
+		}
+	}
+
+	values := make(map[uuid.UUID]string, len(inputs))
+	for _, input := range inputs {
+		values[input.QuestionID] = strings.TrimSpace(input.Value)
+	}
+	answers := []models.ApplicationAnswer{}
+	for _, question := range f.Questions {
+		value, answered := values[question.ID]
+		delete(values, question.ID)
+		if !answered || value == "" {
+			if question.IsRequired {
+				return nil, validationError("missing_answer", fmt.Sprintf("%q must be answered", question.Label))
+			}
+			continue
+		}
+		if err := checkAnswer(&question, value); err != nil {
+			return nil, err
+		}
+		answers = append(answers, models.ApplicationAnswer{
+			QuestionID: question.ID,
+			CategoryID: question.CategoryID,
+			Question:   question.Label,
+			Type:       question.Type,
+			Value:      value,
+		})
+	}
+	if len(values) > 0 {
+		return nil, validationError("unknown_question", "answers must be to questions of the form")
+	}
+	return answers, nil
+}
+
+func checkAnswer(question *models.ApplicationQuestion, value string) error {
+	invalid := func(expected string) error {
+		return validationError("invalid_answer", fmt.Sprintf("%q must be %s", question.Label, expected))
+	}
+	switch question.Type {
+	case QuestionNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return invalid("a number")
+		}
+	case QuestionYesNo:
+		if value != "yes" && value != "no" {
+			return invalid("yes or no")
+		}
+	case QuestionChoice:
+		if !slices.Contains(question.Options, value) {
+			return invalid("one of the options")
+		}
+	case QuestionFile:
+		if !strings.HasPrefix(value, documentsPrefix) {
+			return invalid("an uploaded document")
+		}
+	default:
+		if len([]rune(value)) > maxAnswerLength {
+			return invalid(fmt.Sprintf("at most %d characters", maxAnswerLength))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestApplicationFormMergesDocumentRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	categories := mocks.NewMockCategoryRepository(ctrl)
+	rules := mocks.NewMockDocumentRuleRepository(ctrl)
+	forms := mocks.NewMockApplicationFormRepository(ctrl)
+	repos := &repository.Repositories{Category: categories, DocumentRule: rules, ApplicationForm: forms}
+
+	driving := &models.Category{ID: uuid.New(), IsActive: true}
+	cleaning := &models.Category{ID: uuid.New(), IsActive: true}
+	hint := "Sürücü kategorileri için ehliyet gerekir"
+	ids := []uuid.UUID{driving.ID, cleaning.ID}
+	categories.EXPECT().GetByID(driving.ID).Return(driving, nil)
+	categories.EXPECT().GetByID(cleaning.ID).Return(cleaning, nil)
+	rules.EXPECT().ListByCategories(ids).Return([]models.CategoryDocumentRule{
+		{CategoryID: driving.ID, Document: "selfie"},
+		{CategoryID: driving.ID, Document: "ehliyet_on", Hint: &hint},
+		{CategoryID: cleaning.ID, Document: "kimlik_on"},
+		{CategoryID: cleaning.ID, Document: "selfie"},
+	}, nil)
+	forms.EXPECT().ListQuestions(ids, true).Return(nil, nil)
+
+	form, err := NewOnboardingService(repos).Form(ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"kimlik_on", "ehliyet_on", "selfie"}
+	if len(form.RequiredDocuments) != len(want) {
+		t.Fatalf("expected %v, got %+v", want, form.RequiredDocuments)
+	}
+	for i := range want {
+		if form.RequiredDocuments[i].Document != want[i] {
+			t.Fatalf("expected %v, got %+v", want, form.RequiredDocuments)
+		}
+	}
+	if form.RequiredDocuments[1].Hint == nil || *form.RequiredDocuments[1].Hint != hint {
+		t.Errorf("expected the rule's hint, got %+v", form.RequiredDocuments[1])
+	}
+
+	inactive := &models.Category{ID: uuid.New()}
+	categories.EXPECT().GetByID(inactive.ID).Return(inactive, nil)
+	if _, err := NewOnboardingService(repos).Form([]uuid.UUID{inactive.ID}); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("expected ErrCategoryNotFound, got %v", err)
+	}
+}
+
+func TestMissingDocumentsFollowCurrentRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rules := mocks.NewMockDocumentRuleRepository(ctrl)
+	repos := &repository.Repositories{DocumentRule: rules}
+
+	categoryID := uuid.New()
+	kimlik := "/uploads/documents/kimlik.jpg"
+	profile := &models.YandasProfile{CategoryIDs: pq.StringArray{categoryID.String()}, KimlikOnURL: &kimlik}
+	// Ehliyet became mandatory after the application was made
+	rules.EXPECT().ListByCategories([]uuid.UUID{categoryID}).Return([]models.CategoryDocumentRule{
+		{CategoryID: categoryID, Document: "kimlik_on"},
+		{CategoryID: categoryID, Document: "ehliyet_on"},
+	}, nil)
+
+	missing, err := missingDocuments(repos, profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 1 || missing[0].Document != "ehliyet_on" {
+		t.Fatalf("expected the ehliyet to be missing, got %+v", missing)
+	}
+	if err := missingDocumentsError(missing); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected a conflict, got %v", err)
+	}
+}
+
+func TestApplicationFormAnswers(t *testing.T) {
+	experience := models.ApplicationQuestion{ID: uuid.New(), CategoryID: uuid.New(), Label: "Kaç yıllık ehliyet?", Type: QuestionNumber, IsRequired: true}
+	licence := models.ApplicationQuestion{ID: uuid.New(), CategoryID: experience.CategoryID, Label: "Ehliyet sınıfı", Type: QuestionChoice, Options: pq.StringArray{"B", "C", "D"}}
+	reference := models.ApplicationQuestion{ID: uuid.New(), CategoryID: experience.CategoryID, Label: "Referans mektubu", Type: QuestionFile}
+	form := &ApplicationForm{
+		RequiredDocuments: []DocumentRequirement{{Document: "ehliyet_on"}},
+		Questions:         []models.ApplicationQuestion{experience, licence, reference},
+	}
+	documents := map[string]string{"ehliyet_on": "/uploads/documents/ehliyet.jpg"}
+
+	cases := []struct {
+		name      string
+		documents map[string]string
+		answers   []ApplicationAnswerInput
+		code      string
+	}{
+		{"missing document", map[string]string{}, []ApplicationAnswerInput{{QuestionID: experience.ID, Value: "5"}}, "missing_document"},
+		{"missing required answer", documents, []ApplicationAnswerInput{{QuestionID: experience.ID, Value: "  "}}, "missing_answer"},
+		{"not a number", documents, []ApplicationAnswerInput{{QuestionID: experience.ID, Value: "beş"}}, "invalid_answer"},
+		{"not an option", documents, []ApplicationAnswerInput{{QuestionID: experience.ID, Value: "5"}, {QuestionID: licence.ID, Value: "A"}}, "invalid_answer"},
+		{"file outside uploads", documents, []ApplicationAnswerInput{{QuestionID: experience.ID, Value: "5"}, {QuestionID: reference.ID, Value: "https://example.com/x.pdf"}}, "invalid_answer"},
+		{"unknown question", documents, []ApplicationAnswerInput{{QuestionID: experience.ID, Value: "5"}, {QuestionID: uuid.New(), Value: "x"}}, "unknown_question"},
+	}
+	for _, tc := range cases {
+		_, err := form.Answers(tc.documents, tc.answers)
+		var domainErr *DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != tc.code {
+			t.Errorf("%s: expected %s, got %v", tc.name, tc.code, err)
+		}
+	}
+
+	answers, err := form.Answers(documents, []ApplicationAnswerInput{
+		{QuestionID: licence.ID, Value: "C"},
+		{QuestionID: experience.ID, Value: " 7 "},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(answers) != 2 || answers[0].QuestionID != experience.ID || answers[0].Value != "7" || answers[1].Question != licence.Label {
+		t.Fatalf("unexpected answers %+v", answers)
+	}
+}
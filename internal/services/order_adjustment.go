@@ -0,0 +1,225 @@
+package services
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+var (
+	ErrAdjustmentNotFound  = notFoundError("adjustment_not_found", "adjustment not found")
+	ErrAdjustmentResolved  = conflictError("adjustment_resolved", "adjustment was already answered")
+	ErrAdjustmentUnchanged = validationError("adjustment_unchanged", "new amount must differ from the current amount")
+)
+
+// AdjustmentInput is a yandaş's proposed new amount for an order
+type AdjustmentInput struct {
+	Amount currency.Money `json:"amount" binding:"min=0"`
+	Reason string         `json:"reason" binding:"required,max=200"`
+}
+
+// adjustableStatus reports whether an order's amount can still change. Once it
+// is completed the earnings are split and paid out from the final amount.
+func adjustableStatus(status string) bool {
+	switch status {
+	case "accepted", "in_progress", "pending_confirmation":
+		return true
+	}
+	return false
+}
+
+// ProposeAdjustment lets the yandaş ask to change the amount of an order they
+// have not been paid for yet. A lower amount is a partial refund. A new
+// proposal withdraws the one still awaiting the customer.
+func (s *YandasService) ProposeAdjustment(userID uuid.UUID, orderID uuid.UUID, input *AdjustmentInput) (*models.OrderAdjustment, error) {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+
+	order, err := s.repos.OnPrimary().Order.GetByID(orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+
+	if order.YandasID != profile.ID {
+		return nil, ErrNotOwner
+	}
+
+	if !adjustableStatus(order.Status) {
+		return nil, conflictError("invalid_order_status", "order amount can no longer be adjusted")
+	}
+
+	if input.Amount == order.AgreedPrice {
+		return nil, ErrAdjustmentUnchanged
+	}
+
+	adjustment := &models.OrderAdjustment{
+		OrderID:        order.ID,
+		ProposedBy:     userID,
+		PreviousAmount: order.AgreedPrice,
+		Amount:         input.Amount,
+		Reason:         input.Reason,
+		Status:         "pending",
+	}
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := lockOrder(tx, order.ID, order.Status); err != nil {
+			return err
+		}
+		if err := tx.OrderAdjustment.WithdrawPending(order.ID); err != nil {
+			return err
+		}
+		if err := tx.OrderAdjustment.Create(adjustment); err != nil {
+			return err
+		}
+		data := map[string]interface{}{"order_id": order.ID, "adjustment_id": adjustment.ID}
+		body := fmt.Sprintf("Yandaşınız sipariş tutarını %s yerine %s olarak güncellemek istiyor: %s",
+			formatAmount(adjustment.PreviousAmount, order.Currency), formatAmount(adjustment.Amount, order.Currency), adjustment.Reason)
+		return s.notifications.SendTx(tx, order.CustomerID, "Tutar değişikliği onayınızı bekliyor", body, "order", data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notifications.Flush()
+	return adjustment, nil
+}
+
+// ApproveAdjustment lets the customer accept a proposed amount. The order keeps
+// its first agreed price as the original and gets a line for the difference;
+// commission and earnings are split from the new amount on completion.
+func (s *YandasService) ApproveAdjustment(customerID uuid.UUID, orderID uuid.UUID, adjustmentID uuid.UUID) (*models.Order, error) {
+	order, adjustment, err := s.customerAdjustment(customerID, orderID, adjustmentID)
+	if err != nil {
+		return nil, err
+	}
+	if adjustment.PreviousAmount != order.AgreedPrice {
+		return nil, ErrOrderChanged
+	}
+
+	var items []models.OrderLineItem
+	// Orders priced before line items existed get their agreed price as a line
+	// first so that the lines still add up to the total
+	if len(order.LineItems) == 0 {
+		description := "Hizmet"
+		if order.Service != nil {
+			description = order.Service.Title
+		}
+		items = append(items, models.OrderLineItem{OrderID: order.ID, Kind: "base", Description: description, Amount: order.AgreedPrice})
+	}
+	items = append(items, models.OrderLineItem{
+		OrderID:     order.ID,
+		Kind:        "adjustment",
+		Description: "Tutar düzeltmesi: " + adjustment.Reason,
+		Amount:      adjustment.Amount - adjustment.PreviousAmount,
+	})
+
+	if order.OriginalPrice == nil {
+		original := order.AgreedPrice
+		order.OriginalPrice = &original
+	}
+	order.AgreedPrice = adjustment.Amount
+	note := fmt.Sprintf("%s → %s: %s", formatAmount(adjustment.PreviousAmount, order.Currency), formatAmount(adjustment.Amount, order.Currency), adjustment.Reason)
+
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := lockOrder(tx, order.ID, order.Status); err != nil {
+			return err
+		}
+		resolved, err := tx.OrderAdjustment.Resolve(adjustment.ID, "approved")
+		if err != nil {
+			return err
+		}
+		if !resolved {
+			return ErrAdjustmentResolved
+		}
+		if err := tx.Order.Update(order); err != nil {
+			return err
+		}
+		for i := range items {
+			if err := tx.Order.AddLineItem(&items[i]); err != nil {
+				return err
+			}
+		}
+		return recordOrderEvent(tx, order.ID, OrderEventAdjusted, order.Status, &customerID, &note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	order.LineItems = append(order.LineItems, items...)
+
+	if order.Yandas != nil {
+		s.chat.PostOrderEvent(order, order.Yandas.UserID, OrderEventAdjusted, customerID)
+	}
+	s.notifyAdjustmentAnswer(order, "Tutar değişikliği onaylandı", "Müşteri yeni tutarı onayladı: "+formatAmount(order.AgreedPrice, order.Currency))
+	return order, nil
+}
+
+// RejectAdjustment lets the customer turn down a proposed amount; the order
+// keeps its price
+func (s *YandasService) RejectAdjustment(customerID uuid.UUID, orderID uuid.UUID, adjustmentID uuid.UUID) error {
+	order, adjustment, err := s.customerAdjustment(customerID, orderID, adjustmentID)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := s.repos.OrderAdjustment.Resolve(adjustment.ID, "rejected")
+	if err != nil {
+		return err
+	}
+	if !resolved {
+		return ErrAdjustmentResolved
+	}
+
+	s.notifyAdjustmentAnswer(order, "Tutar değişikliği reddedildi", "Müşteri önerdiğiniz tutarı reddetti; sipariş tutarı değişmedi.")
+	return nil
+}
+
+// customerAdjustment loads a pending adjustment of one of the customer's
+// orders that can still be adjusted
+func (s *YandasService) customerAdjustment(customerID, orderID, adjustmentID uuid.UUID) (*models.Order, *models.OrderAdjustment, error) {
+	order, err := s.repos.OnPrimary().Order.GetByID(orderID)
+	if err != nil {
+		return nil, nil, ErrOrderNotFound
+	}
+
+	if order.CustomerID != customerID {
+		return nil, nil, ErrNotOwner
+	}
+
+	adjustment, err := s.repos.OnPrimary().OrderAdjustment.GetByID(adjustmentID)
+	if err != nil || adjustment.OrderID != order.ID {
+		return nil, nil, ErrAdjustmentNotFound
+	}
+	if adjustment.Status != "pending" {
+		return nil, nil, ErrAdjustmentResolved
+	}
+
+	if !adjustableStatus(order.Status) {
+		return nil, nil, conflictError("invalid_order_status", "order amount can no longer be adjusted")
+	}
+	return order, adjustment, nil
+}
+
+// notifyAdjustmentAnswer tells the yandaş how the customer answered. Failures are logged.
+func (s *YandasService) notifyAdjustmentAnswer(order *models.Order, title, body string) {
+	if s.notifications == nil || order.Yandas == nil {
+		return
+	}
+	data := map[string]interface{}{"order_id": order.ID, "event": OrderEventAdjusted}
+	if err := s.notifications.Send(order.Yandas.UserID, title, body, "order", data); err != nil {
+		log.Printf("[ORDERS] failed to notify %s of order %s adjustment: %v", order.Yandas.UserID, order.ID, err)
+	}
+}
+
+// ListAdjustments returns an order's amount adjustments oldest first for
+// either of its participants
+func (s *OrderService) ListAdjustments(orderID, userID uuid.UUID) ([]models.OrderAdjustment, error) {
+	order, err := s.Get(orderID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repos.OrderAdjustment.ListByOrder(order.ID)
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestAdjustmentChangesEarnings(t *testing.T) {
+	svc, m := newTestCompletion(t)
+	adjustments := mocks.NewMockOrderAdjustmentRepository(gomock.NewController(t))
+	svc.repos.OrderAdjustment = adjustments
+
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: uuid.New()}
+	customerID := uuid.New()
+	order := &models.Order{
+		ID: uuid.New(), CustomerID: customerID, YandasID: profile.ID, Yandas: profile,
+		Status: "in_progress", AgreedPrice: 1000, Currency: "TRY",
+		LineItems: []models.OrderLineItem{{Kind: "base", Description: "Temizlik", Amount: 1000}},
+	}
+
+	// Proposing the current amount changes nothing
+	m.profiles.EXPECT().GetByUserID(profile.UserID).Return(profile, nil)
+	m.orders.EXPECT().GetByID(order.ID).Return(order, nil)
+	if _, err := svc.ProposeAdjustment(profile.UserID, order.ID, &AdjustmentInput{Amount: 1000, Reason: "aynı"}); !errors.Is(err, ErrAdjustmentUnchanged) {
+		t.Errorf("expected ErrAdjustmentUnchanged, got %v", err)
+	}
+
+	// The yandaş proposes a partial refund; an earlier proposal is withdrawn
+	var proposed *models.OrderAdjustment
+	m.profiles.EXPECT().GetByUserID(profile.UserID).Return(profile, nil)
+	m.orders.EXPECT().GetByID(order.ID).Return(order, nil)
+	m.orders.EXPECT().GetByIDForUpdate(order.ID).Return(&models.Order{ID: order.ID, Status: "in_progress"}, nil)
+	adjustments.EXPECT().WithdrawPending(order.ID).Return(nil)
+	adjustments.EXPECT().Create(gomock.Any()).DoAndReturn(func(a *models.OrderAdjustment) error {
+		a.ID = uuid.New()
+		proposed = a
+		return nil
+	})
+	if _, err := svc.ProposeAdjustment(profile.UserID, order.ID, &AdjustmentInput{Amount: 800, Reason: "Bir oda eksik"}); err != nil {
+		t.Fatal(err)
+	}
+	if proposed.PreviousAmount != 1000 || proposed.Amount != 800 || proposed.Status != "pending" {
+		t.Errorf("unexpected adjustment %+v", proposed)
+	}
+
+	// The customer approves it: the order keeps its original price and gets a refund line
+	m.orders.EXPECT().GetByID(order.ID).Return(order, nil)
+	adjustments.EXPECT().GetByID(proposed.ID).Return(proposed, nil)
+	m.orders.EXPECT().GetByIDForUpdate(order.ID).Return(&models.Order{ID: order.ID, Status: "in_progress"}, nil)
+	adjustments.EXPECT().Resolve(proposed.ID, "approved").Return(true, nil)
+	m.orders.EXPECT().Update(order).Return(nil)
+	m.orders.EXPECT().AddLineItem(gomock.Any()).DoAndReturn(func(item *models.OrderLineItem) error {
+		if item.Kind != "adjustment" || item.Amount != -200 {
+			t.Errorf("unexpected line item %+v", item)
+		}
+		return nil
+	})
+	m.history.EXPECT().Record(gomock.Any()).DoAndReturn(func(entry *models.OrderStatusHistory) error {
+		if entry.Event != OrderEventAdjusted || entry.Note == nil {
+			t.Errorf("expected an adjusted event, got %+v", entry)
+		}
+		return nil
+	})
+	if _, err := svc.ApproveAdjustment(customerID, order.ID, proposed.ID); err != nil {
+		t.Fatal(err)
+	}
+	if order.AgreedPrice != 800 || order.OriginalPrice == nil || *order.OriginalPrice != 1000 || len(order.LineItems) != 2 {
+		t.Errorf("expected the order to be adjusted, got %+v", order)
+	}
+
+	// Confirming the completion splits the adjusted amount
+	order.Status = "pending_confirmation"
+	m.orders.EXPECT().GetByID(order.ID).Return(order, nil)
+	m.profiles.EXPECT().GetByID(profile.ID).Return(profile, nil)
+	m.orders.EXPECT().GetByIDForUpdate(order.ID).Return(&models.Order{ID: order.ID, Status: "pending_confirmation"}, nil)
+	m.orders.EXPECT().Update(order).Return(nil)
+	m.history.EXPECT().Record(gomock.Any()).Return(nil)
+	m.payouts.EXPECT().CreateEntry(gomock.Any()).DoAndReturn(func(entry *models.PayoutEntry) error {
+		if entry.GrossAmount != 800 || entry.NetAmount != 680 {
+			t.Errorf("unexpected payout entry %+v", entry)
+		}
+		return nil
+	})
+	m.profiles.EXPECT().UpdateRating(profile.ID).Return(nil)
+	if err := svc.ConfirmCompletion(customerID, order.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// A completed order's amount is final
+	m.orders.EXPECT().GetByID(order.ID).Return(order, nil)
+	adjustments.EXPECT().GetByID(proposed.ID).Return(&models.OrderAdjustment{ID: proposed.ID, OrderID: order.ID, Status: "pending"}, nil)
+	if _, err := svc.ApproveAdjustment(customerID, order.ID, proposed.ID); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected a conflict, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// orderEventText is the system message posted to the order's conversation for an event
+func orderEventText(order *models.Order, event string) string {
+	subject := "#" + order.OrderNumber
+	if order.OrderNumber == "" {
+		subject = "Sipariş"
+	}
+	if order.Service != nil && order.Service.Title != "" {
+		subject += " (" + order.Service.Title + ")"
+	}
+
+	switch event {
+	case OrderEventAccepted:
+		return subject + " kabul edildi." + orderDetails(order)
+	case OrderEventEnRoute:
+		return subject + " için yandaş yola çıktı."
+	case OrderEventStarted:
+		return subject + " için hizmet başladı."
+	case OrderEventAdjusted:
+		return fmt.Sprintf("%s tutarı %s olarak güncellendi.", subject, formatAmount(order.AgreedPrice, order.Currency))
+	case OrderEventCompletionRequested:
+		return subject + " tamamlandı olarak işaretlendi, müşteri onayı bekleniyor."
+	case OrderEventCompleted:
+		return subject + " tamamlandı."
+	case OrderEventCancelled:
+		if order.CancellationReason != nil && *order.CancellationReason != "" {
+			return fmt.Sprintf("%s iptal edildi: %s", subject, *order.CancellationReason)
+		}
+		return subject + " iptal edildi."
+	default:
+		return ""
+	}
+}
+
+// orderDetails lists when, where and for how much an order was booked, one
+// line each, for the acceptance message
+func orderDetails(order *models.Order) string {
+	details := ""
+	if order.ScheduledAt != nil {
+		details += "\nTarih: " + order.ScheduledAt.Format("02.01.2006 15:04")
+	}
+	if order.LocationAddress != nil && *order.LocationAddress != "" {
+		details += "\nAdres: " + *order.LocationAddress
+	}
+	if order.AgreedPrice > 0 {
+		details += "\nTutar: " + formatAmount(order.AgreedPrice, order.Currency)
+	}
+	return details
+}
+
+// OpenOrderConversation links an accepted order to the conversation between
+// its customer and yandaş, starting one if they have never talked, and points
+// the conversation at the order. It runs inside the acceptance transaction;
+// the acceptance message itself is posted by PostOrderEvent after the commit.
+// It is safe to call on a nil ChatService.
+func (s *ChatService) OpenOrderConversation(tx *repository.Repositories, order *models.Order, yandasUserID uuid.UUID) error {
+	if s == nil {
+		return nil
+	}
+
+	conv, err := tx.Conversation.GetByParticipants(order.CustomerID, yandasUserID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		conv = &models.Conversation{CustomerID: order.CustomerID, YandasID: yandasUserID, OrderID: &order.ID}
+		if err := tx.Conversation.Create(conv); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		if err := tx.Conversation.LinkOrder(conv.ID, order.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Order.SetConversation(order.ID, conv.ID); err != nil {
+		return err
+	}
+	order.ConversationID = &conv.ID
+	return nil
+}
+
+// PostOrderEvent adds a system message about an order's state change to the
+// order's conversation, or for orders accepted before conversations were
+// linked, the one between its customer and yandaş, and pushes it to the
+// conversation room. Orders without a conversation are skipped and failures
+// are logged; they never fail the state change.
+func (s *ChatService) PostOrderEvent(order *models.Order, yandasUserID uuid.UUID, event string, actorID uuid.UUID) {
+	if s == nil {
+		return
+	}
+	text := orderEventText(order, event)
+	if text == "" {
+		return
+	}
+
+	convID := order.ConversationID
+	if convID == nil {
+		conv, err := s.repos.Conversation.GetByParticipants(order.CustomerID, yandasUserID)
+		if err != nil {
+			return
+		}
+		convID = &conv.ID
+	}
+
+	if _, err := s.PostSystemMessage(*convID, actorID, text); err != nil {
+		log.Printf("[CHAT] failed to post %s of order %s: %v", event, order.ID, err)
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/currency"
+	"gorm.io/gorm"
+)
+
+func TestPostOrderEvent(t *testing.T) {
+	svc, conversations, messages, _ := newTestChatService(t)
+	outbox := mocks.NewMockOutboxRepository(gomock.NewController(t))
+	svc.repos.Outbox = outbox
+	svc.outbox = NewOutbox(svc.repos, nil)
+	yandasUserID := uuid.New()
+	reason := "Adres değişti"
+	order := &models.Order{
+		ID:                 uuid.New(),
+		OrderNumber:        "YND-1042",
+		CustomerID:         uuid.New(),
+		Service:            &models.YandasService{Title: "Ev temizliği"},
+		CancellationReason: &reason,
+	}
+	conv := &models.Conversation{ID: uuid.New(), CustomerID: order.CustomerID, YandasID: yandasUserID}
+
+	conversations.EXPECT().GetByParticipants(order.CustomerID, yandasUserID).Return(conv, nil)
+	messages.EXPECT().Create(gomock.Any()).DoAndReturn(func(msg *models.Message) error {
+		if msg.MessageType != "system" || msg.SenderID != order.CustomerID || msg.Content != "#YND-1042 (Ev temizliği) iptal edildi: Adres değişti" {
+			t.Errorf("unexpected message %+v", msg)
+		}
+		return nil
+	})
+	conversations.EXPECT().UpdateLastMessage(conv.ID).Return(nil)
+	// The room broadcast is queued in the same transaction
+	outbox.EXPECT().Create(gomock.Any()).DoAndReturn(func(message *models.OutboxMessage) error {
+		if message.Channel != OutboxChannelWebSocket || message.Target != "conv:"+conv.ID.String() || message.Type != "message" {
+			t.Errorf("unexpected outbox message %+v", message)
+		}
+		return nil
+	})
+	svc.PostOrderEvent(order, yandasUserID, OrderEventCancelled, order.CustomerID)
+
+	// No conversation between the two: nothing is posted
+	conversations.EXPECT().GetByParticipants(order.CustomerID, yandasUserID).Return(nil, gorm.ErrRecordNotFound)
+	svc.PostOrderEvent(order, yandasUserID, OrderEventStarted, yandasUserID)
+
+	// Services built without chat skip the message
+	var none *ChatService
+	none.PostOrderEvent(order, yandasUserID, OrderEventCompleted, yandasUserID)
+}
+
+func TestOpenOrderConversation(t *testing.T) {
+	svc, conversations, _, _ := newTestChatService(t)
+	orders := mocks.NewMockOrderRepository(gomock.NewController(t))
+	svc.repos.Order = orders
+	yandasUserID := uuid.New()
+	order := &models.Order{ID: uuid.New(), CustomerID: uuid.New()}
+
+	// First order between the two: a conversation is started for it
+	conversations.EXPECT().GetByParticipants(order.CustomerID, yandasUserID).Return(nil, gorm.ErrRecordNotFound)
+	var created *models.Conversation
+	conversations.EXPECT().Create(gomock.Any()).DoAndReturn(func(conv *models.Conversation) error {
+		if conv.OrderID == nil || *conv.OrderID != order.ID || conv.YandasID != yandasUserID {
+			t.Errorf("unexpected conversation %+v", conv)
+		}
+		conv.ID = uuid.New()
+		created = conv
+		return nil
+	})
+	orders.EXPECT().SetConversation(order.ID, gomock.Any()).Return(nil)
+	if err := svc.OpenOrderConversation(svc.repos, order, yandasUserID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.ConversationID == nil || *order.ConversationID != created.ID {
+		t.Fatalf("expected the order to link the new conversation, got %v", order.ConversationID)
+	}
+
+	// They talked before: the existing conversation moves to the new order
+	next := &models.Order{ID: uuid.New(), CustomerID: order.CustomerID}
+	conversations.EXPECT().GetByParticipants(order.CustomerID, yandasUserID).Return(created, nil)
+	conversations.EXPECT().LinkOrder(created.ID, next.ID).Return(nil)
+	orders.EXPECT().SetConversation(next.ID, created.ID).Return(nil)
+	if err := svc.OpenOrderConversation(svc.repos, next, yandasUserID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next.ConversationID == nil || *next.ConversationID != created.ID {
+		t.Fatalf("expected the order to link the existing conversation, got %v", next.ConversationID)
+	}
+}
+
+func TestOrderAcceptedText(t *testing.T) {
+	scheduled := time.Date(2026, 3, 14, 10, 30, 0, 0, time.Local)
+	address := "Moda Cd. 12, Kadıköy"
+	order := &models.Order{
+		OrderNumber:     "YND-1042",
+		Service:         &models.YandasService{Title: "Ev temizliği"},
+		ScheduledAt:     &scheduled,
+		LocationAddress: &address,
+		AgreedPrice:     currency.Money(150000),
+		Currency:        "TRY",
+	}
+
+	want := "#YND-1042 (Ev temizliği) kabul edildi.\nTarih: 14.03.2026 10:30\nAdres: Moda Cd. 12, Kadıköy\nTutar: " + formatAmount(order.AgreedPrice, "TRY")
+	if got := orderEventText(order, OrderEventAccepted); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
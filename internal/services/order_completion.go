@@ -0,0 +1,190 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+const (
+	// CompletionConfirmInterval is how often completions the customer left unconfirmed are confirmed
+	CompletionConfirmInterval = 10 * time.Minute
+	completionConfirmBatch    = 100
+)
+
+// Note on the completed event of a completion nobody confirmed in time
+const autoConfirmNote = "Müşteri onayı beklenmeden otomatik onaylandı"
+
+var ErrNotAwaitingConfirmation = conflictError("not_awaiting_confirmation", "order is not awaiting completion confirmation")
+
+// CompleteOrder marks an in-progress order done. When completions need the
+// customer's confirmation the order waits in pending_confirmation; otherwise
+// it is completed and the yandaş's earnings are released right away.
+func (s *YandasService) CompleteOrder(userID uuid.UUID, orderID uuid.UUID, notes string) error {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return ErrYandasProfileNotFound
+	}
+
+	order, err := s.repos.OnPrimary().Order.GetByID(orderID)
+	if err != nil {
+		return ErrOrderNotFound
+	}
+
+	if order.YandasID != profile.ID {
+		return ErrNotOwner
+	}
+
+	if order.Status != "in_progress" {
+		return conflictError("invalid_order_status", "order cannot be completed")
+	}
+
+	order.YandasNotes = &notes
+	if s.settings.Bool(SettingOrderConfirmation) {
+		return s.requestCompletion(profile, order, notes)
+	}
+	return s.finishOrder(profile, order, "in_progress", &profile.UserID, &notes)
+}
+
+// requestCompletion puts the order on hold until the customer confirms it
+func (s *YandasService) requestCompletion(profile *models.YandasProfile, order *models.Order, notes string) error {
+	now := time.Now()
+	order.Status = "pending_confirmation"
+	order.CompletionRequestedAt = &now
+
+	err := s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := lockOrder(tx, order.ID, "in_progress"); err != nil {
+			return err
+		}
+		if err := tx.Order.Update(order); err != nil {
+			return err
+		}
+		return recordOrderEvent(tx, order.ID, OrderEventCompletionRequested, order.Status, &profile.UserID, &notes)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.chat.PostOrderEvent(order, profile.UserID, OrderEventCompletionRequested, profile.UserID)
+	if s.notifications != nil {
+		data := map[string]interface{}{"order_id": order.ID, "event": OrderEventCompletionRequested}
+		body := "Yandaşınız siparişi tamamladı. Lütfen onaylayın; onaylamazsanız kısa süre sonra otomatik onaylanacak."
+		if err := s.notifications.Send(order.CustomerID, "Siparişiniz tamamlandı mı?", body, "order", data); err != nil {
+			log.Printf("[ORDERS] failed to ask %s to confirm order %s: %v", order.CustomerID, order.ID, err)
+		}
+	}
+	return nil
+}
+
+// ConfirmCompletion lets the customer accept the yandaş's completion, which
+// releases the earnings
+func (s *YandasService) ConfirmCompletion(customerID uuid.UUID, orderID uuid.UUID) error {
+	order, err := s.repos.OnPrimary().Order.GetByID(orderID)
+	if err != nil {
+		return ErrOrderNotFound
+	}
+
+	if order.CustomerID != customerID {
+		return ErrNotOwner
+	}
+
+	if order.Status != "pending_confirmation" {
+		return ErrNotAwaitingConfirmation
+	}
+
+	profile, err := s.repos.YandasProfile.GetByID(order.YandasID)
+	if err != nil {
+		return ErrYandasProfileNotFound
+	}
+	return s.finishOrder(profile, order, "pending_confirmation", &customerID, nil)
+}
+
+// AutoConfirmCompletions confirms completions the customer left unconfirmed
+// for longer than the auto-confirm setting; the scheduler calls it every
+// CompletionConfirmInterval
+func (s *YandasService) AutoConfirmCompletions() {
+	before := time.Now().Add(-s.settings.Duration(SettingOrderAutoConfirm))
+	due, err := s.repos.Order.ListAwaitingConfirmation(before, completionConfirmBatch)
+	if err != nil {
+		log.Printf("[ORDERS] failed to load unconfirmed completions: %v", err)
+		return
+	}
+
+	note := autoConfirmNote
+	for _, d := range due {
+		order, err := s.repos.OnPrimary().Order.GetByID(d.ID)
+		if err != nil {
+			log.Printf("[ORDERS] failed to load order %s: %v", d.ID, err)
+			continue
+		}
+		profile, err := s.repos.YandasProfile.GetByID(order.YandasID)
+		if err != nil {
+			log.Printf("[ORDERS] failed to load the yandaş of order %s: %v", order.ID, err)
+			continue
+		}
+		// The customer may have confirmed it in the meantime
+		if err := s.finishOrder(profile, order, "pending_confirmation", nil, &note); err != nil && !errors.Is(err, ErrOrderChanged) {
+			log.Printf("[ORDERS] failed to auto-confirm order %s: %v", order.ID, err)
+		}
+	}
+}
+
+// finishOrder completes an order that has status from: it splits the price
+// into commission and earnings, queues the payout and updates the yandaş's
+// totals and rating. actorID is nil when the system completes it.
+func (s *YandasService) finishOrder(profile *models.YandasProfile, order *models.Order, from string, actorID *uuid.UUID, note *string) error {
+	var category *models.Category
+	if order.Service != nil {
+		category = order.Service.Category
+	}
+	rate := commissionRateFor(s.settings.Float(SettingCommissionRate), category, s.subscriptions.Entitlements(profile.UserID))
+	fee, net := splitEarnings(order.AgreedPrice, rate)
+
+	now := time.Now()
+	order.Status = "completed"
+	order.CompletedAt = &now
+	order.CommissionRate = &rate
+	order.PlatformFee = &fee
+	order.NetEarnings = &net
+
+	err := s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := lockOrder(tx, order.ID, from); err != nil {
+			return err
+		}
+		if err := tx.Order.Update(order); err != nil {
+			return err
+		}
+		if err := recordOrderEvent(tx, order.ID, OrderEventCompleted, order.Status, actorID, note); err != nil {
+			return err
+		}
+		if err := tx.Payout.CreateEntry(&models.PayoutEntry{
+			YandasID:    profile.ID,
+			OrderID:     order.ID,
+			GrossAmount: order.AgreedPrice,
+			PlatformFee: fee,
+			NetAmount:   net,
+			Currency:    order.Currency,
+		}); err != nil {
+			return err
+		}
+		// Update yandaş rating
+		return tx.YandasProfile.UpdateRating(profile.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.webhooks.Dispatch(WebhookOrderCompleted, orderWebhookData(order))
+	s.receipts.IssueAsync(order.ID)
+	actor := profile.UserID
+	if actorID != nil {
+		actor = *actorID
+	}
+	s.chat.PostOrderEvent(order, profile.UserID, OrderEventCompleted, actor)
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"gorm.io/gorm"
+)
+
+type completionMocks struct {
+	orders   *mocks.MockOrderRepository
+	profiles *mocks.MockYandasProfileRepository
+	history  *mocks.MockOrderHistoryRepository
+	payouts  *mocks.MockPayoutRepository
+}
+
+// newTestCompletion builds a yandaş service whose completions need the customer's confirmation
+func newTestCompletion(t *testing.T) (*YandasService, *completionMocks) {
+	ctrl := gomock.NewController(t)
+	m := &completionMocks{
+		orders:   mocks.NewMockOrderRepository(ctrl),
+		profiles: mocks.NewMockYandasProfileRepository(ctrl),
+		history:  mocks.NewMockOrderHistoryRepository(ctrl),
+		payouts:  mocks.NewMockPayoutRepository(ctrl),
+	}
+	settings := mocks.NewMockSettingRepository(ctrl)
+	subscriptions := mocks.NewMockSubscriptionRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{
+		Order:         m.orders,
+		YandasProfile: m.profiles,
+		OrderHistory:  m.history,
+		Payout:        m.payouts,
+		Setting:       settings,
+		Subscription:  subscriptions,
+		UnitOfWork:    uow,
+	}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	settings.EXPECT().List().Return([]models.Setting{
+		{Key: SettingOrderConfirmation, Type: SettingTypeBool, Value: "true"},
+	}, nil).AnyTimes()
+	subscriptions.EXPECT().GetByUserID(gomock.Any()).Return(nil, gorm.ErrRecordNotFound).AnyTimes()
+
+	cfg := &config.Config{CommissionRate: 0.15}
+	svc := NewYandasService(repos, cfg, NewSubscriptionService(repos, cfg, nil, nil, nil), nil, nil, nil,
+		NewReceiptService(repos, cfg, nil, nil), nil, nil, nil, NewSettingsService(repos, cfg, nil), nil, nil, nil)
+	return svc, m
+}
+
+func TestCompletionWaitsForCustomer(t *testing.T) {
+	svc, m := newTestCompletion(t)
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: uuid.New()}
+	customerID := uuid.New()
+	order := &models.Order{ID: uuid.New(), CustomerID: customerID, YandasID: profile.ID, Status: "in_progress", AgreedPrice: 1000, Currency: "TRY"}
+
+	// The yandaş marks it done: no earnings yet
+	m.profiles.EXPECT().GetByUserID(profile.UserID).Return(profile, nil)
+	m.orders.EXPECT().GetByID(order.ID).Return(order, nil)
+	m.orders.EXPECT().GetByIDForUpdate(order.ID).Return(&models.Order{ID: order.ID, Status: "in_progress"}, nil)
+	m.orders.EXPECT().Update(gomock.Any()).DoAndReturn(func(o *models.Order) error {
+		if o.Status != "pending_confirmation" || o.CompletionRequestedAt == nil || o.CompletedAt != nil {
+			t.Errorf("expected the order to await confirmation, got %+v", o)
+		}
+		return nil
+	})
+	m.history.EXPECT().Record(gomock.Any()).DoAndReturn(func(entry *models.OrderStatusHistory) error {
+		if entry.Event != OrderEventCompletionRequested {
+			t.Errorf("unexpected event %s", entry.Event)
+		}
+		return nil
+	})
+	if err := svc.CompleteOrder(profile.UserID, order.ID, "bitti"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the customer can confirm
+	m.orders.EXPECT().GetByID(order.ID).Return(order, nil)
+	if err := svc.ConfirmCompletion(uuid.New(), order.ID); !errors.Is(err, ErrNotOwner) {
+		t.Errorf("expected ErrNotOwner, got %v", err)
+	}
+
+	// Confirming releases the earnings and updates the totals
+	m.orders.EXPECT().GetByID(order.ID).Return(order, nil)
+	m.profiles.EXPECT().GetByID(profile.ID).Return(profile, nil)
+	m.orders.EXPECT().GetByIDForUpdate(order.ID).Return(&models.Order{ID: order.ID, Status: "pending_confirmation"}, nil)
+	m.orders.EXPECT().Update(gomock.Any()).Return(nil)
+	m.history.EXPECT().Record(gomock.Any()).DoAndReturn(func(entry *models.OrderStatusHistory) error {
+		if entry.Event != OrderEventCompleted || entry.ActorID == nil || *entry.ActorID != customerID {
+			t.Errorf("expected a completed event by the customer, got %+v", entry)
+		}
+		return nil
+	})
+	m.payouts.EXPECT().CreateEntry(gomock.Any()).DoAndReturn(func(entry *models.PayoutEntry) error {
+		if entry.GrossAmount != 1000 || entry.NetAmount != 850 {
+			t.Errorf("unexpected payout entry %+v", entry)
+		}
+		return nil
+	})
+	m.profiles.EXPECT().UpdateRating(profile.ID).Return(nil)
+	if err := svc.ConfirmCompletion(customerID, order.ID); err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != "completed" || order.CompletedAt == nil {
+		t.Errorf("expected the order to be completed, got %+v", order)
+	}
+
+	// A completed order can't be confirmed again
+	m.orders.EXPECT().GetByID(order.ID).Return(order, nil)
+	if err := svc.ConfirmCompletion(customerID, order.ID); !errors.Is(err, ErrNotAwaitingConfirmation) {
+		t.Errorf("expected ErrNotAwaitingConfirmation, got %v", err)
+	}
+}
+
+func TestAutoConfirmCompletions(t *testing.T) {
+	svc, m := newTestCompletion(t)
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: uuid.New()}
+	requested := time.Now().Add(-25 * time.Hour)
+	due := &models.Order{ID: uuid.New(), YandasID: profile.ID, Status: "pending_confirmation", AgreedPrice: 400, CompletionRequestedAt: &requested}
+	confirmed := &models.Order{ID: uuid.New(), YandasID: profile.ID, Status: "pending_confirmation", AgreedPrice: 200, CompletionRequestedAt: &requested}
+
+	m.orders.EXPECT().ListAwaitingConfirmation(gomock.Any(), completionConfirmBatch).DoAndReturn(func(before time.Time, _ int) ([]models.Order, error) {
+		if age := time.Since(before); age < 24*time.Hour-time.Minute || age > 24*time.Hour+time.Minute {
+			t.Errorf("expected completions older than a day, got %v", age)
+		}
+		return []models.Order{*due, *confirmed}, nil
+	})
+	m.orders.EXPECT().GetByID(due.ID).Return(due, nil)
+	m.orders.EXPECT().GetByID(confirmed.ID).Return(confirmed, nil)
+	m.profiles.EXPECT().GetByID(profile.ID).Return(profile, nil).Times(2)
+
+	m.orders.EXPECT().GetByIDForUpdate(due.ID).Return(&models.Order{ID: due.ID, Status: "pending_confirmation"}, nil)
+	m.orders.EXPECT().Update(due).Return(nil)
+	m.history.EXPECT().Record(gomock.Any()).DoAndReturn(func(entry *models.OrderStatusHistory) error {
+		if entry.ActorID != nil || entry.Note == nil || *entry.Note != autoConfirmNote {
+			t.Errorf("expected a system completion, got %+v", entry)
+		}
+		return nil
+	})
+	m.payouts.EXPECT().CreateEntry(gomock.Any()).Return(nil)
+	m.profiles.EXPECT().UpdateRating(profile.ID).Return(nil)
+
+	// The customer confirmed this one while the batch ran
+	m.orders.EXPECT().GetByIDForUpdate(confirmed.ID).Return(&models.Order{ID: confirmed.ID, Status: "completed"}, nil)
+
+	svc.AutoConfirmCompletions()
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/routing"
+)
+
+const (
+	// An order's ETA is recomputed from location updates at most this often
+	orderETAInterval = 30 * time.Second
+	// Accepted orders scheduled further out than this get no ETA yet;
+	// the yandaş hasn't set off
+	orderETALookahead = 3 * time.Hour
+	// Time allowed for the routing provider to answer
+	orderETATimeout = 5 * time.Second
+)
+
+// OrderETA is pushed to the customer as an eta_updated event
+type OrderETA struct {
+	OrderID    string    `json:"order_id"`
+	Status     string    `json:"status"`
+	EtaAt      time.Time `json:"eta_at"`
+	Minutes    int       `json:"eta_minutes"`
+	DistanceKm float64   `json:"distance_km"`
+}
+
+// SetBroadcaster lets ETA updates reach connected customers in realtime.
+// Only the API process has connections, so the worker leaves it unset.
+func (s *YandasService) SetBroadcaster(b Broadcaster) {
+	s.realtime = b
+}
+
+// startOrderETA estimates the arrival of a just accepted or started order
+// from the yandaş's last known location
+func (s *YandasService) startOrderETA(profile *models.YandasProfile, order *models.Order) *OrderETA {
+	if profile.Latitude == nil || profile.Longitude == nil {
+		return nil
+	}
+	return s.refreshOrderETA(order, *profile.Latitude, *profile.Longitude)
+}
+
+// updateOrderETAs re-estimates the arrival of the yandaş's accepted and
+// in-progress orders after a location update
+func (s *YandasService) updateOrderETAs(profile *models.YandasProfile, lat, lng float64) {
+	for _, status := range []string{"accepted", "in_progress"} {
+		orders, _, err := s.repos.Order.ListByYandas(profile.ID, 1, 10, status)
+		if err != nil {
+			log.Printf("[ORDERS] failed to load %s orders of %s: %v", status, profile.ID, err)
+			continue
+		}
+		for i := range orders {
+			if orders[i].EtaUpdatedAt != nil && time.Since(*orders[i].EtaUpdatedAt) < orderETAInterval {
+				continue
+			}
+			s.refreshOrderETA(&orders[i], lat, lng)
+		}
+	}
+}
+
+// refreshOrderETA routes from the yandaş's position to the order location,
+// stores the estimate and pushes it to the customer. It returns nil when no
+// estimate was made. Failures are logged; they never fail the status change
+// or location update that triggered them.
+func (s *YandasService) refreshOrderETA(order *models.Order, lat, lng float64) *OrderETA {
+	if order.Latitude == nil || order.Longitude == nil {
+		return nil
+	}
+	now := time.Now()
+	if order.Status == "accepted" && order.EnRouteAt == nil && order.ScheduledAt != nil && order.ScheduledAt.Sub(now) > orderETALookahead {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), orderETATimeout)
+	defer cancel()
+	route, err := s.routing.Route(ctx, routing.Point{Lat: lat, Lng: lng}, routing.Point{Lat: *order.Latitude, Lng: *order.Longitude})
+	if err != nil {
+		log.Printf("[ORDERS] failed to estimate arrival for order %s: %v", order.ID, err)
+		return nil
+	}
+
+	eta := newOrderETA(order, route, now)
+	if err := s.repos.Order.UpdateETA(order.ID, eta.EtaAt, eta.DistanceKm); err != nil {
+		log.Printf("[ORDERS] failed to store ETA of order %s: %v", order.ID, err)
+		return nil
+	}
+	if s.realtime != nil {
+		s.realtime.BroadcastToUser(order.CustomerID.String(), "eta_updated", eta)
+	}
+	return eta
+}
+
+// newOrderETA rounds a route into the estimate shown to the customer
+func newOrderETA(order *models.Order, route *routing.Route, now time.Time) *OrderETA {
+	minutes := int(math.Ceil(route.Duration.Minutes()))
+	return &OrderETA{
+		OrderID:    order.ID.String(),
+		Status:     order.Status,
+		EtaAt:      now.Add(time.Duration(minutes) * time.Minute).Truncate(time.Second),
+		Minutes:    minutes,
+		DistanceKm: math.Round(route.DistanceKm*10) / 10,
+	}
+}
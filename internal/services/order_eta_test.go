@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/routing"
+)
+
+func TestUpdateOrderETAs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orders := mocks.NewMockOrderRepository(ctrl)
+	svc := NewYandasService(&repository.Repositories{Order: orders}, &config.Config{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, routing.NewStraightLine(), nil, nil)
+	ws := &recordingBroadcaster{events: map[string][]string{}}
+	svc.SetBroadcaster(ws)
+
+	profile := &models.YandasProfile{ID: uuid.New()}
+	lat, lng := 40.9900, 29.0290 // Kadıköy
+	recent := time.Now().Add(-10 * time.Second)
+	nextWeek := time.Now().Add(7 * 24 * time.Hour)
+
+	due := models.Order{ID: uuid.New(), CustomerID: uuid.New(), Status: "accepted", Latitude: &lat, Longitude: &lng}
+	accepted := []models.Order{
+		due,
+		{ID: uuid.New(), CustomerID: uuid.New(), Status: "accepted", Latitude: &lat, Longitude: &lng, EtaUpdatedAt: &recent},
+		{ID: uuid.New(), CustomerID: uuid.New(), Status: "accepted", Latitude: &lat, Longitude: &lng, ScheduledAt: &nextWeek},
+		{ID: uuid.New(), CustomerID: uuid.New(), Status: "accepted"},
+	}
+	orders.EXPECT().ListByYandas(profile.ID, 1, 10, "accepted").Return(accepted, int64(len(accepted)), nil)
+	orders.EXPECT().ListByYandas(profile.ID, 1, 10, "in_progress").Return(nil, int64(0), nil)
+
+	before := time.Now()
+	orders.EXPECT().UpdateETA(due.ID, gomock.Any(), gomock.Any()).DoAndReturn(func(_ uuid.UUID, etaAt time.Time, km float64) error {
+		// Taksim to Kadıköy is around 8 km by road and about a quarter of an hour
+		if km < 6 || km > 10 {
+			t.Errorf("unexpected distance %.1f km", km)
+		}
+		if etaAt.Before(before.Add(10*time.Minute)) || etaAt.After(before.Add(25*time.Minute)) {
+			t.Errorf("unexpected ETA %s", etaAt.Sub(before))
+		}
+		return nil
+	})
+
+	svc.updateOrderETAs(profile, 41.0370, 28.9850) // Taksim
+
+	if len(ws.events) != 1 || len(ws.events[due.CustomerID.String()]) != 1 || ws.events[due.CustomerID.String()][0] != "eta_updated" {
+		t.Errorf("expected one eta_updated event for the due order, got %v", ws.events)
+	}
+}
+
+func TestOnMyWay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orders := mocks.NewMockOrderRepository(ctrl)
+	profiles := mocks.NewMockYandasProfileRepository(ctrl)
+	history := mocks.NewMockOrderHistoryRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{Order: orders, YandasProfile: profiles, OrderHistory: history, UnitOfWork: uow}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	svc := NewYandasService(repos, &config.Config{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, routing.NewStraightLine(), nil, nil)
+	ws := &recordingBroadcaster{events: map[string][]string{}}
+	svc.SetBroadcaster(ws)
+
+	userID := uuid.New()
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: userID}
+	lat, lng := 40.9900, 29.0290
+	nextWeek := time.Now().Add(7 * 24 * time.Hour)
+	order := &models.Order{ID: uuid.New(), CustomerID: uuid.New(), YandasID: profile.ID, Status: "accepted", Latitude: &lat, Longitude: &lng, ScheduledAt: &nextWeek}
+	profiles.EXPECT().GetByUserID(userID).Return(profile, nil).AnyTimes()
+
+	// Pending orders can't be set off for
+	pending := *order
+	pending.Status = "pending"
+	orders.EXPECT().GetByID(order.ID).Return(&pending, nil)
+	if _, err := svc.OnMyWay(userID, order.ID, nil); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected a conflict for a pending order, got %v", err)
+	}
+
+	// Even though it's scheduled far out, setting off gets the customer an ETA
+	orders.EXPECT().GetByID(order.ID).Return(order, nil)
+	orders.EXPECT().GetByIDForUpdate(order.ID).Return(order, nil)
+	orders.EXPECT().MarkEnRoute(order.ID).Return(true, nil)
+	history.EXPECT().Record(gomock.Any()).DoAndReturn(func(entry *models.OrderStatusHistory) error {
+		if entry.Event != OrderEventEnRoute || entry.Status != "accepted" || *entry.ActorID != userID {
+			t.Errorf("unexpected history entry %+v", entry)
+		}
+		return nil
+	})
+	orders.EXPECT().UpdateETA(order.ID, gomock.Any(), gomock.Any()).Return(nil)
+	from, to := 41.0370, 28.9850
+	eta, err := svc.OnMyWay(userID, order.ID, &OnMyWayInput{Latitude: &from, Longitude: &to})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eta == nil || eta.Minutes <= 0 {
+		t.Errorf("expected an arrival estimate, got %+v", eta)
+	}
+	if got := ws.events[order.CustomerID.String()]; len(got) != 1 || got[0] != "eta_updated" {
+		t.Errorf("expected the customer to get the ETA, got %v", got)
+	}
+
+	// A second report loses the race for the en route mark
+	fresh := *order
+	fresh.EnRouteAt = nil
+	orders.EXPECT().GetByID(order.ID).Return(&fresh, nil)
+	orders.EXPECT().GetByIDForUpdate(order.ID).Return(&fresh, nil)
+	orders.EXPECT().MarkEnRoute(order.ID).Return(false, nil)
+	if _, err := svc.OnMyWay(userID, order.ID, nil); !errors.Is(err, ErrAlreadyEnRoute) {
+		t.Errorf("expected ErrAlreadyEnRoute, got %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// ErrOrderChanged is returned to the loser of two concurrent changes to an order
+var ErrOrderChanged = conflictError("order_changed", "order was changed by another request, please refresh")
+
+// lockOrder locks the order row for the rest of the transaction and fails with
+// ErrOrderChanged unless it still has the status the caller checked. Status
+// transitions call it first inside their transaction, so concurrent requests
+// on the same order run one after the other and only the first one wins.
+func lockOrder(tx *repository.Repositories, orderID uuid.UUID, status string) error {
+	order, err := tx.Order.GetByIDForUpdate(orderID)
+	if err != nil {
+		return ErrOrderNotFound
+	}
+	if order.Status != status {
+		return ErrOrderChanged
+	}
+	return nil
+}
@@ -1,65 +1,84 @@
 package services
 
 import (
-	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/config"
 	"github.com/yandas/backend/internal/models"
 	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/currency"
+	"github.com/yandas/backend/pkg/media"
 )
 
+// ErrCancelCutoffPassed is returned when an accepted order starts too soon to be cancelled
+var ErrCancelCutoffPassed = conflictError("cancel_cutoff_passed", "order is too close to its start time to be cancelled")
+
 // OrderService handles order operations
 type OrderService struct {
-	repos *repository.Repositories
-	cfg   *config.Config
+	repos      *repository.Repositories
+	cfg        *config.Config
+	webhooks   *WebhookService
+	monitoring *MonitoringService
+	chat       *ChatService
+	settings   *SettingsService
+	ratings    *ReviewStatsService
+	activity   *ActivityService
 }
 
-func NewOrderService(repos *repository.Repositories, cfg *config.Config) *OrderService {
-	return &OrderService{repos: repos, cfg: cfg}
+func NewOrderService(repos *repository.Repositories, cfg *config.Config, webhooks *WebhookService, monitoring *MonitoringService, chat *ChatService, settings *SettingsService, ratings *ReviewStatsService, activity *ActivityService) *OrderService {
+	return &OrderService{repos: repos, cfg: cfg, webhooks: webhooks, monitoring: monitoring, chat: chat, settings: settings, ratings: ratings, activity: activity}
 }
 
 // CreateOrderInput represents order creation data
 type CreateOrderInput struct {
-	YandasID        uuid.UUID  `json:"yandas_id" binding:"required"`
-	ServiceID       uuid.UUID  `json:"service_id" binding:"required"`
-	AgreedPrice     float64    `json:"agreed_price" binding:"required"`
-	LocationAddress string     `json:"location_address"`
-	Latitude        float64    `json:"latitude"`
-	Longitude       float64    `json:"longitude"`
-	ScheduledAt     *time.Time `json:"scheduled_at"`
-	CustomerNotes   string     `json:"customer_notes"`
+	YandasID        uuid.UUID      `json:"yandas_id" binding:"required"`
+	ServiceID       uuid.UUID      `json:"service_id" binding:"required"`
+	AgreedPrice     currency.Money `json:"agreed_price" binding:"min=0"` // overrides the service base price when set
+	OptionIDs       []uuid.UUID    `json:"option_ids"`
+	DurationMinutes *int           `json:"duration_minutes"`
+	AddressID       *uuid.UUID     `json:"address_id"`
+	LocationAddress string         `json:"location_address"`
+	Latitude        float64        `json:"latitude"`
+	Longitude       float64        `json:"longitude"`
+	ScheduledAt     *time.Time     `json:"scheduled_at"`
+	CustomerNotes   string         `json:"customer_notes"`
 }
 
-// Create creates a new order
+// Create creates a new order. It must meet the yandaş's lead time and minimum
+// order value; with instant booking on it is accepted right away.
 func (s *OrderService) Create(customerID uuid.UUID, input *CreateOrderInput) (*models.Order, error) {
 	// Verify yandaş exists and is approved
 	yandas, err := s.repos.YandasProfile.GetByID(input.YandasID)
 	if err != nil {
-		return nil, errors.New("yandaş not found")
+		return nil, ErrYandasNotFound
 	}
 
 	if yandas.ApprovalStatus != "approved" {
-		return nil, errors.New("yandaş not available")
+		return nil, ErrYandasUnavailable
+	}
+	if onVacation(yandas, input.ScheduledAt) {
+		return nil, ErrYandasOnVacation
+	}
+	if err := checkLeadTime(yandas, input.ScheduledAt, time.Now()); err != nil {
+		return nil, err
 	}
 
-	// Verify service exists
+	// Verify the service exists and is listed
 	service, err := s.repos.Service.GetByID(input.ServiceID)
-	if err != nil {
-		return nil, errors.New("service not found")
+	if err != nil || !serviceBookable(service) {
+		return nil, ErrServiceNotFound
 	}
 
 	if service.YandasID != input.YandasID {
-		return nil, errors.New("service does not belong to this yandaş")
+		return nil, validationError("service_mismatch", "service does not belong to this yandaş")
 	}
 
 	order := &models.Order{
 		CustomerID:      customerID,
 		YandasID:        input.YandasID,
 		ServiceID:       input.ServiceID,
-		AgreedPrice:     input.AgreedPrice,
-		Currency:        "TRY",
+		Currency:        currencyCode(service.Currency),
 		LocationAddress: &input.LocationAddress,
 		ScheduledAt:     input.ScheduledAt,
 		CustomerNotes:   &input.CustomerNotes,
@@ -73,9 +92,86 @@ func (s *OrderService) Create(customerID uuid.UUID, input *CreateOrderInput) (*m
 		order.Longitude = &input.Longitude
 	}
 
-	if err := s.repos.Order.Create(order); err != nil {
+	// A saved address overrides any free-form location
+	if input.AddressID != nil {
+		address, err := s.repos.Address.GetByID(*input.AddressID)
+		if err != nil || address.UserID != customerID {
+			return nil, ErrAddressNotFound
+		}
+		order.LocationAddress = &address.AddressText
+		order.Latitude = address.Latitude
+		order.Longitude = address.Longitude
+	}
+
+	travelKm, travelFee, err := travelQuote(yandas, order.Latitude, order.Longitude)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing := PricingInput{BasePrice: service.BasePrice, OptionIDs: input.OptionIDs, TravelKm: travelKm, TravelFee: travelFee}
+	if input.AgreedPrice > 0 {
+		pricing.BasePrice = input.AgreedPrice
+	}
+	if input.DurationMinutes != nil {
+		minutes := float64(*input.DurationMinutes)
+		pricing.DurationMinutes = &minutes
+	} else if service.DurationMinutes != nil {
+		minutes := float64(*service.DurationMinutes)
+		pricing.DurationMinutes = &minutes
+	}
+	if order.Latitude != nil && order.Longitude != nil && yandas.Latitude != nil && yandas.Longitude != nil {
+		km := haversineKm(*yandas.Latitude, *yandas.Longitude, *order.Latitude, *order.Longitude)
+		pricing.DistanceKm = &km
+	}
+
+	lineItems, total, err := priceOrder(service, pricing)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMinimumOrder(yandas, total, order.Currency); err != nil {
 		return nil, err
 	}
+	order.LineItems = lineItems
+	order.AgreedPrice = total
+
+	instant := s.instantBooking(yandas, service, order, input.AgreedPrice)
+	if instant {
+		order.Status = "accepted"
+	}
+
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.Order.Create(order); err != nil {
+			return err
+		}
+		if err := recordOrderEvent(tx, order.ID, OrderEventCreated, "pending", &customerID, nil); err != nil {
+			return err
+		}
+		if instant {
+			if err := recordOrderEvent(tx, order.ID, OrderEventAccepted, order.Status, &yandas.UserID, nil); err != nil {
+				return err
+			}
+			if err := s.chat.OpenOrderConversation(tx, order, yandas.UserID); err != nil {
+				return err
+			}
+		}
+		return s.activity.Record(tx, ActivityOrderCreated, order.ID, &customerID, "New order", map[string]interface{}{
+			"status":       order.Status,
+			"agreed_price": order.AgreedPrice,
+			"currency":     order.Currency,
+			"yandas_id":    order.YandasID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.activity.Flush()
+
+	s.webhooks.Dispatch(WebhookOrderCreated, orderWebhookData(order))
+	s.monitoring.Record(MetricOrdersCreated)
+	if instant {
+		s.webhooks.Dispatch(WebhookOrderAccepted, orderWebhookData(order))
+		s.chat.PostOrderEvent(order, yandas.UserID, OrderEventAccepted, yandas.UserID)
+	}
 
 	return order, nil
 }
@@ -84,14 +180,14 @@ func (s *OrderService) Create(customerID uuid.UUID, input *CreateOrderInput) (*m
 func (s *OrderService) Get(orderID uuid.UUID, userID uuid.UUID) (*models.Order, error) {
 	order, err := s.repos.Order.GetByID(orderID)
 	if err != nil {
-		return nil, errors.New("order not found")
+		return nil, ErrOrderNotFound
 	}
 
 	// Check authorization
 	if order.CustomerID != userID {
 		profile, _ := s.repos.YandasProfile.GetByUserID(userID)
 		if profile == nil || order.YandasID != profile.ID {
-			return nil, errors.New("unauthorized")
+			return nil, ErrNotOwner
 		}
 	}
 
@@ -105,24 +201,47 @@ func (s *OrderService) List(customerID uuid.UUID, page, limit int, status string
 
 // Cancel cancels an order
 func (s *OrderService) Cancel(orderID uuid.UUID, userID uuid.UUID, reason string) error {
-	order, err := s.repos.Order.GetByID(orderID)
+	order, err := s.repos.OnPrimary().Order.GetByID(orderID)
 	if err != nil {
-		return errors.New("order not found")
+		return ErrOrderNotFound
 	}
 
 	if order.CustomerID != userID {
-		return errors.New("unauthorized")
+		return ErrNotOwner
 	}
 
 	if order.Status != "pending" && order.Status != "accepted" {
-		return errors.New("order cannot be cancelled")
+		return conflictError("invalid_order_status", "order cannot be cancelled")
 	}
 
+	// Accepted bookings can't be dropped on the yandaş at the last minute
+	if cutoff := s.settings.Duration(SettingOrderCancelCutoff); cutoff > 0 && order.Status == "accepted" &&
+		order.ScheduledAt != nil && time.Until(*order.ScheduledAt) < cutoff {
+		return ErrCancelCutoffPassed
+	}
+
+	from := order.Status
 	order.Status = "cancelled"
 	order.CancellationReason = &reason
 	order.CancelledBy = &userID
 
-	return s.repos.Order.Update(order)
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := lockOrder(tx, orderID, from); err != nil {
+			return err
+		}
+		if err := tx.Order.Update(order); err != nil {
+			return err
+		}
+		return recordOrderEvent(tx, order.ID, OrderEventCancelled, order.Status, &userID, &reason)
+	})
+	if err != nil {
+		return err
+	}
+
+	if order.Yandas != nil {
+		s.chat.PostOrderEvent(order, order.Yandas.UserID, OrderEventCancelled, userID)
+	}
+	return nil
 }
 
 // ReviewInput represents review data
@@ -130,32 +249,37 @@ type ReviewInput struct {
 	Rating      int    `json:"rating" binding:"required,min=1,max=5"`
 	Comment     string `json:"comment"`
 	IsAnonymous bool   `json:"is_anonymous"`
+
+	// Optional criterion ratings
+	PunctualityRating   *int `json:"punctuality_rating" binding:"omitempty,min=1,max=5"`
+	CommunicationRating *int `json:"communication_rating" binding:"omitempty,min=1,max=5"`
+	QualityRating       *int `json:"quality_rating" binding:"omitempty,min=1,max=5"`
 }
 
 // Review adds a review to an order
 func (s *OrderService) Review(orderID uuid.UUID, reviewerID uuid.UUID, input *ReviewInput) (*models.Review, error) {
-	order, err := s.repos.Order.GetByID(orderID)
+	order, err := s.repos.OnPrimary().Order.GetByID(orderID)
 	if err != nil {
-		return nil, errors.New("order not found")
+		return nil, ErrOrderNotFound
 	}
 
 	if order.CustomerID != reviewerID {
-		return nil, errors.New("unauthorized")
+		return nil, ErrNotOwner
 	}
 
 	if order.Status != "completed" {
-		return nil, errors.New("order must be completed to leave a review")
+		return nil, conflictError("order_not_completed", "order must be completed to leave a review")
 	}
 
 	// Check if already reviewed
 	if s.repos.Review.ExistsByOrderID(orderID) {
-		return nil, errors.New("order already reviewed")
+		return nil, conflictError("already_reviewed", "order already reviewed")
 	}
 
 	// Get yandaş user ID
 	yandas, err := s.repos.YandasProfile.GetByID(order.YandasID)
 	if err != nil {
-		return nil, errors.New("yandaş not found")
+		return nil, ErrYandasNotFound
 	}
 
 	review := &models.Review{
@@ -165,18 +289,57 @@ func (s *OrderService) Review(orderID uuid.UUID, reviewerID uuid.UUID, input *Re
 		Rating:      input.Rating,
 		Comment:     &input.Comment,
 		IsAnonymous: input.IsAnonymous,
+
+		PunctualityRating:   input.PunctualityRating,
+		CommunicationRating: input.CommunicationRating,
+		QualityRating:       input.QualityRating,
 	}
 
-	if err := s.repos.Review.Create(review); err != nil {
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.Review.Create(review); err != nil {
+			return err
+		}
+		// Update yandaş rating
+		return tx.YandasProfile.UpdateRating(order.YandasID)
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.ratings.Invalidate(review.RevieweeID)
 
-	// Update yandaş rating
-	s.repos.YandasProfile.UpdateRating(order.YandasID)
+	s.webhooks.Dispatch(WebhookReviewCreated, map[string]interface{}{
+		"id":          review.ID,
+		"order_id":    review.OrderID,
+		"reviewee_id": review.RevieweeID,
+		"rating":      review.Rating,
+		"created_at":  review.CreatedAt,
+	})
 
 	return review, nil
 }
 
+// MarkReviewHelpful records a helpful vote from someone other than the review's author or subject
+func (s *OrderService) MarkReviewHelpful(reviewID, userID uuid.UUID) error {
+	review, err := s.repos.Review.GetByID(reviewID)
+	if err != nil || review.IsHidden {
+		return ErrReviewNotFound
+	}
+
+	if review.ReviewerID == userID || review.RevieweeID == userID {
+		return unauthorizedError("own_review", "cannot vote on your own review")
+	}
+
+	return s.repos.Review.AddHelpfulVote(reviewID, userID)
+}
+
+// UnmarkReviewHelpful withdraws a helpful vote
+func (s *OrderService) UnmarkReviewHelpful(reviewID, userID uuid.UUID) error {
+	if _, err := s.repos.Review.GetByID(reviewID); err != nil {
+		return ErrReviewNotFound
+	}
+	return s.repos.Review.RemoveHelpfulVote(reviewID, userID)
+}
+
 // CategoryService handles category operations
 type CategoryService struct {
 	repos *repository.Repositories
@@ -186,17 +349,28 @@ func NewCategoryService(repos *repository.Repositories) *CategoryService {
 	return &CategoryService{repos: repos}
 }
 
-func (s *CategoryService) List() ([]models.Category, error) {
-	return s.repos.Category.List()
+// List returns the active categories; with a city, those in high demand there are flagged
+func (s *CategoryService) List(city string) ([]models.Category, error) {
+	categories, err := s.repos.Category.List()
+	if err != nil || city == "" {
+		return categories, err
+	}
+	s.markHighDemand(categories, city)
+	return categories, nil
 }
 
 // ChatService handles chat operations
 type ChatService struct {
-	repos *repository.Repositories
+	repos       *repository.Repositories
+	transcoder  media.Transcoder
+	storagePath string
+	events      *EventBus
+	outbox      *Outbox
+	realtime    ConversationBroadcaster
 }
 
-func NewChatService(repos *repository.Repositories) *ChatService {
-	return &ChatService{repos: repos}
+func NewChatService(repos *repository.Repositories, transcoder media.Transcoder, storagePath string, events *EventBus, outbox *Outbox) *ChatService {
+	return &ChatService{repos: repos, transcoder: transcoder, storagePath: storagePath, events: events, outbox: outbox}
 }
 
 func (s *ChatService) GetConversations(userID uuid.UUID, page, limit int) ([]models.Conversation, int64, error) {
@@ -211,7 +385,7 @@ func (s *ChatService) GetConversation(userID uuid.UUID, convID uuid.UUID) (*mode
 
 	// Check authorization
 	if conv.CustomerID != userID && conv.YandasID != userID {
-		return nil, errors.New("unauthorized")
+		return nil, ErrNotOwner
 	}
 
 	return conv, nil
@@ -250,12 +424,67 @@ func (s *ChatService) SendMessage(userID uuid.UUID, convID uuid.UUID, input *Sen
 		MessageType:    msgType,
 	}
 
-	if err := s.repos.Message.Create(msg); err != nil {
+	if err := s.saveMessage(msg); err != nil {
 		return nil, err
 	}
 
-	// Update conversation last message time
-	s.repos.Conversation.UpdateLastMessage(convID)
+	return msg, nil
+}
+
+// saveMessage stores a participant's message, moves the conversation's last
+// message time, queues the room broadcast and publishes MessageSent, all in
+// one transaction
+func (s *ChatService) saveMessage(msg *models.Message) error {
+	err := s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.Message.Create(msg); err != nil {
+			return err
+		}
+		if err := tx.Conversation.UpdateLastMessage(msg.ConversationID); err != nil {
+			return err
+		}
+		if err := s.outbox.BroadcastToConversation(tx, msg.ConversationID, "message", msg); err != nil {
+			return err
+		}
+		return s.events.Publish(tx, EventMessageSent, msg.ConversationID, MessageSentEvent{
+			MessageID:      msg.ID,
+			ConversationID: msg.ConversationID,
+			SenderID:       msg.SenderID,
+			MessageType:    msg.MessageType,
+			Preview:        messagePreview(msg),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	s.outbox.Flush()
+	s.events.Flush()
+	return nil
+}
+
+// PostSystemMessage inserts a system-type message into a conversation and
+// broadcasts it to the room. Access checks are skipped since these messages
+// are generated by the platform, not typed by a participant.
+func (s *ChatService) PostSystemMessage(convID uuid.UUID, senderID uuid.UUID, content string) (*models.Message, error) {
+	msg := &models.Message{
+		ConversationID: convID,
+		SenderID:       senderID,
+		Content:        content,
+		MessageType:    "system",
+	}
+
+	err := s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.Message.Create(msg); err != nil {
+			return err
+		}
+		if err := tx.Conversation.UpdateLastMessage(convID); err != nil {
+			return err
+		}
+		return s.outbox.BroadcastToConversation(tx, convID, "message", msg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.outbox.Flush()
 
 	return msg, nil
 }
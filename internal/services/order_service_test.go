@@ -0,0 +1,482 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+type orderServiceMocks struct {
+	orders   *mocks.MockOrderRepository
+	profiles *mocks.MockYandasProfileRepository
+	services *mocks.MockServiceRepository
+	reviews  *mocks.MockReviewRepository
+	address  *mocks.MockAddressRepository
+	history  *mocks.MockOrderHistoryRepository
+	convs    *mocks.MockConversationRepository
+	messages *mocks.MockMessageRepository
+	uow      *mocks.MockUnitOfWork
+}
+
+func newTestOrderService(t *testing.T) (*OrderService, *orderServiceMocks) {
+	ctrl := gomock.NewController(t)
+	m := &orderServiceMocks{
+		orders:   mocks.NewMockOrderRepository(ctrl),
+		profiles: mocks.NewMockYandasProfileRepository(ctrl),
+		services: mocks.NewMockServiceRepository(ctrl),
+		reviews:  mocks.NewMockReviewRepository(ctrl),
+		address:  mocks.NewMockAddressRepository(ctrl),
+		history:  mocks.NewMockOrderHistoryRepository(ctrl),
+		convs:    mocks.NewMockConversationRepository(ctrl),
+		messages: mocks.NewMockMessageRepository(ctrl),
+		uow:      mocks.NewMockUnitOfWork(ctrl),
+	}
+	repos := &repository.Repositories{
+		Order:         m.orders,
+		YandasProfile: m.profiles,
+		Service:       m.services,
+		Review:        m.reviews,
+		Address:       m.address,
+		OrderHistory:  m.history,
+		Conversation:  m.convs,
+		Message:       m.messages,
+		UnitOfWork:    m.uow,
+	}
+	// Transactions run inline against the same mocks
+	m.uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	return NewOrderService(repos, &config.Config{}, nil, nil, nil, nil, nil, nil), m
+}
+
+func TestOrderServiceCreate(t *testing.T) {
+	customerID := uuid.New()
+	yandasID := uuid.New()
+	serviceID := uuid.New()
+	soon := time.Now().Add(time.Hour)
+	minimum := currency.Money(1000)
+
+	tests := []struct {
+		name    string
+		setup   func(m *orderServiceMocks)
+		input   CreateOrderInput
+		wantErr string
+	}{
+		{
+			name: "yandas not found",
+			setup: func(m *orderServiceMocks) {
+				m.profiles.EXPECT().GetByID(yandasID).Return(nil, errors.New("record not found"))
+			},
+			wantErr: "yandaş not found",
+		},
+		{
+			name: "yandas not approved",
+			setup: func(m *orderServiceMocks) {
+				m.profiles.EXPECT().GetByID(yandasID).Return(&models.YandasProfile{ID: yandasID, ApprovalStatus: "pending"}, nil)
+			},
+			wantErr: "yandaş not available",
+		},
+		{
+			name: "service belongs to another yandas",
+			setup: func(m *orderServiceMocks) {
+				m.profiles.EXPECT().GetByID(yandasID).Return(&models.YandasProfile{ID: yandasID, ApprovalStatus: "approved"}, nil)
+				m.services.EXPECT().GetByID(serviceID).Return(&models.YandasService{ID: serviceID, YandasID: uuid.New(), IsActive: true, Status: ServiceStatusActive}, nil)
+			},
+			wantErr: "service does not belong to this yandaş",
+		},
+		{
+			name: "address owned by someone else",
+			setup: func(m *orderServiceMocks) {
+				m.profiles.EXPECT().GetByID(yandasID).Return(&models.YandasProfile{ID: yandasID, ApprovalStatus: "approved"}, nil)
+				m.services.EXPECT().GetByID(serviceID).Return(&models.YandasService{ID: serviceID, YandasID: yandasID, IsActive: true, Status: ServiceStatusActive}, nil)
+				m.address.EXPECT().GetByID(gomock.Any()).Return(&models.Address{UserID: uuid.New()}, nil)
+			},
+			input:   CreateOrderInput{AddressID: &uuid.UUID{}},
+			wantErr: "address not found",
+		},
+		{
+			name: "too little notice",
+			setup: func(m *orderServiceMocks) {
+				m.profiles.EXPECT().GetByID(yandasID).Return(&models.YandasProfile{ID: yandasID, ApprovalStatus: "approved", LeadTimeMinutes: 120}, nil)
+			},
+			input:   CreateOrderInput{ScheduledAt: &soon},
+			wantErr: ErrLeadTimeNotMet.Error(),
+		},
+		{
+			name: "below minimum order value",
+			setup: func(m *orderServiceMocks) {
+				m.profiles.EXPECT().GetByID(yandasID).Return(&models.YandasProfile{ID: yandasID, ApprovalStatus: "approved", MinOrderValue: &minimum}, nil)
+				m.services.EXPECT().GetByID(serviceID).Return(&models.YandasService{ID: serviceID, YandasID: yandasID, IsActive: true, Status: ServiceStatusActive}, nil)
+			},
+			input:   CreateOrderInput{AgreedPrice: 500},
+			wantErr: ErrBelowMinimumOrder.Error(),
+		},
+		{
+			name: "success",
+			setup: func(m *orderServiceMocks) {
+				m.profiles.EXPECT().GetByID(yandasID).Return(&models.YandasProfile{ID: yandasID, ApprovalStatus: "approved"}, nil)
+				m.services.EXPECT().GetByID(serviceID).Return(&models.YandasService{ID: serviceID, YandasID: yandasID, IsActive: true, Status: ServiceStatusActive}, nil)
+				m.orders.EXPECT().Create(gomock.Any()).DoAndReturn(func(order *models.Order) error {
+					if order.CustomerID != customerID || order.Status != "pending" {
+						t.Errorf("unexpected order: %+v", order)
+					}
+					return nil
+				})
+				m.history.EXPECT().Record(gomock.Any()).DoAndReturn(func(entry *models.OrderStatusHistory) error {
+					if entry.Event != OrderEventCreated || entry.ActorID == nil || *entry.ActorID != customerID {
+						t.Errorf("unexpected history entry: %+v", entry)
+					}
+					return nil
+				})
+			},
+			input: CreateOrderInput{AgreedPrice: 500, Latitude: 41.0, Longitude: 29.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestOrderService(t)
+			tt.setup(m)
+
+			input := tt.input
+			input.YandasID = yandasID
+			input.ServiceID = serviceID
+
+			order, err := svc.Create(customerID, &input)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if order.Latitude == nil || *order.Latitude != 41.0 {
+				t.Errorf("expected latitude to be set, got %v", order.Latitude)
+			}
+		})
+	}
+}
+
+func TestOrderServiceCreateInstantBooking(t *testing.T) {
+	svc, m := newTestOrderService(t)
+	customerID := uuid.New()
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: uuid.New(), ApprovalStatus: "approved", InstantBooking: true, LeadTimeMinutes: 60}
+	service := &models.YandasService{ID: uuid.New(), YandasID: profile.ID, IsActive: true, Status: ServiceStatusActive}
+	m.profiles.EXPECT().GetByID(profile.ID).Return(profile, nil).Times(2)
+	m.services.EXPECT().GetByID(service.ID).Return(service, nil).Times(2)
+	m.orders.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+
+	// Accepted on the yandaş's behalf when the slot is free
+	scheduled := time.Now().Add(3 * time.Hour)
+	m.orders.EXPECT().ListScheduledByYandas(profile.ID, gomock.Any(), gomock.Any(), []string{"accepted", "in_progress"}).Return(nil, nil)
+	var events []string
+	m.history.EXPECT().Record(gomock.Any()).DoAndReturn(func(entry *models.OrderStatusHistory) error {
+		events = append(events, entry.Event+":"+entry.Status)
+		return nil
+	}).Times(3)
+	order, err := svc.Create(customerID, &CreateOrderInput{YandasID: profile.ID, ServiceID: service.ID, AgreedPrice: 500, ScheduledAt: &scheduled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != "accepted" || len(events) != 2 || events[0] != "created:pending" || events[1] != "accepted:accepted" {
+		t.Errorf("expected an accepted order, got %s with %v", order.Status, events)
+	}
+
+	// Left for approval when it overlaps a booking
+	m.orders.EXPECT().ListScheduledByYandas(profile.ID, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]models.Order{{ID: uuid.New(), ScheduledAt: &scheduled, Service: service}}, nil)
+	order, err = svc.Create(customerID, &CreateOrderInput{YandasID: profile.ID, ServiceID: service.ID, AgreedPrice: 500, ScheduledAt: &scheduled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != "pending" {
+		t.Errorf("expected the overlapping order to wait for approval, got %s", order.Status)
+	}
+}
+
+func TestOrderServiceCreateInstantBookingPriceOverride(t *testing.T) {
+	svc, m := newTestOrderService(t)
+	customerID := uuid.New()
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: uuid.New(), ApprovalStatus: "approved", InstantBooking: true}
+	service := &models.YandasService{ID: uuid.New(), YandasID: profile.ID, BasePrice: 1000, IsActive: true, Status: ServiceStatusActive}
+	m.profiles.EXPECT().GetByID(profile.ID).Return(profile, nil).Times(2)
+	m.services.EXPECT().GetByID(service.ID).Return(service, nil).Times(2)
+	m.orders.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+	m.history.EXPECT().Record(gomock.Any()).Return(nil).Times(3)
+
+	// A price below the service's is left for the yandaş to approve
+	order, err := svc.Create(customerID, &CreateOrderInput{YandasID: profile.ID, ServiceID: service.ID, AgreedPrice: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != "pending" {
+		t.Errorf("expected an underpriced order to wait for approval, got %s", order.Status)
+	}
+
+	order, err = svc.Create(customerID, &CreateOrderInput{YandasID: profile.ID, ServiceID: service.ID, AgreedPrice: 1500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != "accepted" {
+		t.Errorf("expected an order at or above the base price to be accepted, got %s", order.Status)
+	}
+}
+
+func TestCheckLeadTime(t *testing.T) {
+	now := time.Now()
+	profile := &models.YandasProfile{LeadTimeMinutes: 120}
+	later, soon := now.Add(3*time.Hour), now.Add(90*time.Minute)
+	if err := checkLeadTime(profile, &later, now); err != nil {
+		t.Errorf("expected enough notice, got %v", err)
+	}
+	for _, at := range []*time.Time{&soon, nil} {
+		if err := checkLeadTime(profile, at, now); !errors.Is(err, ErrLeadTimeNotMet) {
+			t.Errorf("%v: expected ErrLeadTimeNotMet, got %v", at, err)
+		}
+	}
+	if err := checkLeadTime(&models.YandasProfile{}, nil, now); err != nil {
+		t.Errorf("expected no lead time to take orders for right away, got %v", err)
+	}
+}
+
+func TestCheckMinimumOrder(t *testing.T) {
+	minimum, eur := currency.Money(1000), "EUR"
+	legacy := &models.YandasProfile{MinOrderValue: &minimum}
+	inEuros := &models.YandasProfile{MinOrderValue: &minimum, MinOrderCurrency: &eur}
+
+	tests := []struct {
+		name    string
+		profile *models.YandasProfile
+		total   currency.Money
+		code    string
+		wantErr error
+	}{
+		{"no minimum", &models.YandasProfile{}, 1, "TRY", nil},
+		{"below a TRY minimum", legacy, 500, "TRY", ErrBelowMinimumOrder},
+		{"at the minimum", legacy, 1000, "", nil},
+		{"EUR order against a TRY minimum", legacy, 500, "EUR", nil},
+		{"below a EUR minimum", inEuros, 500, "EUR", ErrBelowMinimumOrder},
+		{"TRY order against a EUR minimum", inEuros, 500, "TRY", nil},
+	}
+	for _, tt := range tests {
+		if err := checkMinimumOrder(tt.profile, tt.total, tt.code); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestOrderServiceCancel(t *testing.T) {
+	customerID := uuid.New()
+	orderID := uuid.New()
+
+	tests := []struct {
+		name    string
+		order   *models.Order
+		locked  string // status of the row once locked, when the request gets that far
+		userID  uuid.UUID
+		wantErr string
+	}{
+		{
+			name:    "other user",
+			order:   &models.Order{ID: orderID, CustomerID: customerID, Status: "pending"},
+			userID:  uuid.New(),
+			wantErr: "unauthorized",
+		},
+		{
+			name:    "already completed",
+			order:   &models.Order{ID: orderID, CustomerID: customerID, Status: "completed"},
+			userID:  customerID,
+			wantErr: "order cannot be cancelled",
+		},
+		{
+			name:    "accepted concurrently",
+			order:   &models.Order{ID: orderID, CustomerID: customerID, Status: "pending"},
+			locked:  "accepted",
+			userID:  customerID,
+			wantErr: ErrOrderChanged.Error(),
+		},
+		{
+			name:   "pending order",
+			order:  &models.Order{ID: orderID, CustomerID: customerID, Status: "pending"},
+			locked: "pending",
+			userID: customerID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newTestOrderService(t)
+			m.orders.EXPECT().GetByID(orderID).Return(tt.order, nil)
+			if tt.locked != "" {
+				m.orders.EXPECT().GetByIDForUpdate(orderID).Return(&models.Order{ID: orderID, Status: tt.locked}, nil)
+			}
+			if tt.wantErr == "" {
+				m.orders.EXPECT().Update(gomock.Any()).Return(nil)
+				m.history.EXPECT().Record(gomock.Any()).Return(nil)
+			}
+
+			err := svc.Cancel(orderID, tt.userID, "changed my mind")
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.order.Status != "cancelled" || tt.order.CancelledBy == nil || *tt.order.CancelledBy != customerID {
+				t.Errorf("order not cancelled correctly: %+v", tt.order)
+			}
+		})
+	}
+}
+
+func TestOrderServiceReview(t *testing.T) {
+	customerID := uuid.New()
+	orderID := uuid.New()
+	yandasID := uuid.New()
+	yandasUserID := uuid.New()
+
+	svc, m := newTestOrderService(t)
+	m.orders.EXPECT().GetByID(orderID).Return(&models.Order{ID: orderID, CustomerID: customerID, YandasID: yandasID, Status: "completed"}, nil)
+	m.reviews.EXPECT().ExistsByOrderID(orderID).Return(false)
+	m.profiles.EXPECT().GetByID(yandasID).Return(&models.YandasProfile{ID: yandasID, UserID: yandasUserID}, nil)
+	m.reviews.EXPECT().Create(gomock.Any()).Return(nil)
+	m.profiles.EXPECT().UpdateRating(yandasID).Return(nil)
+
+	review, err := svc.Review(orderID, customerID, &ReviewInput{Rating: 5, Comment: "Harika"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if review.RevieweeID != yandasUserID {
+		t.Errorf("expected reviewee %s, got %s", yandasUserID, review.RevieweeID)
+	}
+}
+
+func TestOrderServiceReviewRollsBackOnRatingFailure(t *testing.T) {
+	customerID := uuid.New()
+	orderID := uuid.New()
+	yandasID := uuid.New()
+
+	svc, m := newTestOrderService(t)
+	m.orders.EXPECT().GetByID(orderID).Return(&models.Order{ID: orderID, CustomerID: customerID, YandasID: yandasID, Status: "completed"}, nil)
+	m.reviews.EXPECT().ExistsByOrderID(orderID).Return(false)
+	m.profiles.EXPECT().GetByID(yandasID).Return(&models.YandasProfile{ID: yandasID, UserID: uuid.New()}, nil)
+	m.reviews.EXPECT().Create(gomock.Any()).Return(nil)
+	m.profiles.EXPECT().UpdateRating(yandasID).Return(errors.New("connection reset"))
+
+	if _, err := svc.Review(orderID, customerID, &ReviewInput{Rating: 4}); err == nil {
+		t.Fatal("expected rating failure to be returned so the transaction rolls back")
+	}
+}
+
+func TestOrderServiceRebook(t *testing.T) {
+	customerID := uuid.New()
+	yandasID := uuid.New()
+	serviceID := uuid.New()
+	optionID := uuid.New()
+	pastID := uuid.New()
+	address := "Kadıköy, İstanbul"
+
+	svc, m := newTestOrderService(t)
+	m.orders.EXPECT().GetByID(pastID).Return(&models.Order{
+		ID:              pastID,
+		CustomerID:      customerID,
+		YandasID:        yandasID,
+		ServiceID:       serviceID,
+		LocationAddress: &address,
+		LineItems: []models.OrderLineItem{
+			{Kind: "base", Amount: 200},
+			{Kind: "option", OptionID: &optionID, Amount: 50},
+		},
+	}, nil)
+	m.profiles.EXPECT().GetByID(yandasID).Return(&models.YandasProfile{ID: yandasID, ApprovalStatus: "approved"}, nil)
+	m.services.EXPECT().GetByID(serviceID).Return(&models.YandasService{
+		ID:        serviceID,
+		YandasID:  yandasID,
+		BasePrice: 250,
+		IsActive:  true,
+		Status:    ServiceStatusActive,
+		Options:   []models.ServiceOption{{ID: optionID, Name: "Ekstra", Price: 60, IsActive: true}},
+	}, nil)
+	m.orders.EXPECT().Create(gomock.Any()).Return(nil)
+	m.history.EXPECT().Record(gomock.Any()).Return(nil)
+
+	order, err := svc.Rebook(pastID, customerID, &RebookInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.AgreedPrice != 310 {
+		t.Errorf("expected current pricing 310, got %v", order.AgreedPrice)
+	}
+	if order.LocationAddress == nil || *order.LocationAddress != address {
+		t.Errorf("location not copied: %v", order.LocationAddress)
+	}
+}
+
+func TestOrderServiceRebookOtherCustomer(t *testing.T) {
+	svc, m := newTestOrderService(t)
+	m.orders.EXPECT().GetByID(gomock.Any()).Return(&models.Order{CustomerID: uuid.New()}, nil)
+
+	if _, err := svc.Rebook(uuid.New(), uuid.New(), &RebookInput{}); err == nil {
+		t.Fatal("expected error for someone else's order")
+	}
+}
+
+func TestOrderServiceTimeline(t *testing.T) {
+	customerID := uuid.New()
+	yandasUserID := uuid.New()
+	orderID := uuid.New()
+	convID := uuid.New()
+	created := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	completed := created.Add(3 * time.Hour)
+	lastMessage := created.Add(30 * time.Minute)
+
+	svc, m := newTestOrderService(t)
+	m.orders.EXPECT().GetByID(orderID).Return(&models.Order{
+		ID:          orderID,
+		CustomerID:  customerID,
+		Status:      "completed",
+		CreatedAt:   created,
+		CompletedAt: &completed,
+		Yandas:      &models.YandasProfile{UserID: yandasUserID},
+		Review:      &models.Review{Rating: 5, CreatedAt: completed.Add(time.Hour)},
+	}, nil)
+	m.history.EXPECT().ListByOrder(orderID).Return([]models.OrderStatusHistory{
+		{Event: OrderEventCreated, Status: "pending", CreatedAt: created},
+		{Event: OrderEventAccepted, Status: "accepted", CreatedAt: created.Add(10 * time.Minute)},
+		{Event: OrderEventStarted, Status: "in_progress", CreatedAt: created.Add(time.Hour)},
+		{Event: OrderEventCompleted, Status: "completed", CreatedAt: completed},
+	}, nil)
+	m.convs.EXPECT().GetByParticipants(customerID, yandasUserID).Return(&models.Conversation{ID: convID}, nil)
+	m.messages.EXPECT().CountBetween(convID, created, completed).Return(int64(4), &lastMessage, nil)
+
+	events, err := svc.Timeline(orderID, customerID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{OrderEventCreated, OrderEventAccepted, OrderEventMessages, OrderEventStarted, OrderEventCompleted, OrderEventReviewed}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, event := range events {
+		if event.Type != want[i] {
+			t.Errorf("event %d: expected %s, got %s", i, want[i], event.Type)
+		}
+	}
+	if events[2].Count == nil || *events[2].Count != 4 {
+		t.Errorf("expected 4 messages, got %v", events[2].Count)
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Order timeline event types. Messages and reviewed are derived when the
+// timeline is built; the rest are stored in the order's status history.
+const (
+	OrderEventCreated             = "created"
+	OrderEventAccepted            = "accepted"
+	OrderEventEnRoute             = "en_route"
+	OrderEventStarted             = "started"
+	OrderEventLocation            = "location"
+	OrderEventMessages            = "messages"
+	OrderEventAdjusted            = "adjusted"
+	OrderEventRescheduled         = "rescheduled"
+	OrderEventCompletionRequested = "completion_requested"
+	OrderEventCompleted           = "completed"
+	OrderEventCancelled           = "cancelled"
+	OrderEventReviewed            = "reviewed"
+)
+
+// A yandaş's location is kept on an in-progress order at most this often
+const orderLocationInterval = 2 * time.Minute
+
+// TimelineEvent is one step of an order as shown on the tracking screen
+type TimelineEvent struct {
+	Type      string     `json:"type"`
+	Status    string     `json:"status,omitempty"`
+	At        time.Time  `json:"at"`
+	ActorID   *uuid.UUID `json:"actor_id,omitempty"`
+	Note      *string    `json:"note,omitempty"`
+	Latitude  *float64   `json:"latitude,omitempty"`
+	Longitude *float64   `json:"longitude,omitempty"`
+	Count     *int64     `json:"count,omitempty"`  // messages
+	Rating    *int       `json:"rating,omitempty"` // reviewed
+}
+
+// Timeline returns an order's events oldest first for either of its participants
+func (s *OrderService) Timeline(orderID, userID uuid.UUID) ([]TimelineEvent, error) {
+	order, err := s.Get(orderID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.repos.OrderHistory.ListByOrder(order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]TimelineEvent, 0, len(history)+2)
+	for _, entry := range history {
+		events = append(events, TimelineEvent{
+			Type:      entry.Event,
+			Status:    entry.Status,
+			At:        entry.CreatedAt,
+			ActorID:   entry.ActorID,
+			Note:      entry.Note,
+			Latitude:  entry.Latitude,
+			Longitude: entry.Longitude,
+		})
+	}
+
+	if count, last := s.orderMessageCount(order); count > 0 {
+		events = append(events, TimelineEvent{Type: OrderEventMessages, At: *last, Count: &count})
+	}
+	if order.Review != nil {
+		rating := order.Review.Rating
+		events = append(events, TimelineEvent{Type: OrderEventReviewed, At: order.Review.CreatedAt, Rating: &rating})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+	return events, nil
+}
+
+// orderMessageCount counts the chat messages between the order's customer and
+// yandaş while the order was open, and when the last of them was sent
+func (s *OrderService) orderMessageCount(order *models.Order) (int64, *time.Time) {
+	if order.Yandas == nil {
+		return 0, nil
+	}
+	conv, err := s.repos.Conversation.GetByParticipants(order.CustomerID, order.Yandas.UserID)
+	if err != nil {
+		return 0, nil
+	}
+
+	end := time.Now()
+	switch {
+	case order.CompletedAt != nil:
+		end = *order.CompletedAt
+	case order.Status == "cancelled":
+		end = order.UpdatedAt
+	}
+
+	count, last, err := s.repos.Message.CountBetween(conv.ID, order.CreatedAt, end)
+	if err != nil || last == nil {
+		return 0, nil
+	}
+	return count, last
+}
+
+// recordOrderEvent appends a status change to an order's history. Pass the
+// transaction's repositories so the change and its history commit together.
+func recordOrderEvent(repos *repository.Repositories, orderID uuid.UUID, event, status string, actorID *uuid.UUID, note *string) error {
+	return repos.OrderHistory.Record(&models.OrderStatusHistory{
+		OrderID: orderID,
+		Event:   event,
+		Status:  status,
+		ActorID: actorID,
+		Note:    note,
+	})
+}
+
+// recordOrderLocations adds the yandaş's position to the history of their
+// in-progress orders. Failures are logged; they never fail the location update.
+func (s *YandasService) recordOrderLocations(profile *models.YandasProfile, lat, lng float64) {
+	orders, _, err := s.repos.Order.ListByYandas(profile.ID, 1, 10, "in_progress")
+	if err != nil {
+		log.Printf("[ORDERS] failed to load in-progress orders of %s: %v", profile.ID, err)
+		return
+	}
+
+	for _, order := range orders {
+		last, err := s.repos.OnPrimary().OrderHistory.LastEvent(order.ID, OrderEventLocation)
+		if err == nil && time.Since(last.CreatedAt) < orderLocationInterval {
+			continue
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("[ORDERS] failed to read location history of order %s: %v", order.ID, err)
+			continue
+		}
+
+		entry := &models.OrderStatusHistory{
+			OrderID:   order.ID,
+			Event:     OrderEventLocation,
+			Status:    order.Status,
+			ActorID:   &profile.UserID,
+			Latitude:  &lat,
+			Longitude: &lng,
+		}
+		if err := s.repos.OrderHistory.Record(entry); err != nil {
+			log.Printf("[ORDERS] failed to record location for order %s: %v", order.ID, err)
+		}
+	}
+}
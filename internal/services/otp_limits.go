@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// OTP channels limits are kept for
+const (
+	OTPChannelPhone = "phone"
+	OTPChannelEmail = "email"
+)
+
+// Why an OTP request was refused
+const (
+	OTPLimitCooldown = "otp_cooldown"
+	OTPLimitHourly   = "otp_hourly_limit"
+	OTPLimitLocked   = "otp_locked"
+)
+
+// Defaults of the OTP limits; admins can change them at runtime through settings
+const (
+	// Minimum time between two codes to the same target
+	otpResendCooldown = 60 * time.Second
+	// Codes a target can receive per hour
+	otpMaxSendsPerHour = 5
+	// Wrong guesses allowed per code before the target is locked out
+	otpMaxVerifyAttempts = 5
+	// How long a locked out target can neither receive nor check codes
+	otpLockout = 15 * time.Minute
+)
+
+// ErrOTPRateLimited matches every OTPLimitError with errors.Is
+var ErrOTPRateLimited = errors.New("too many OTP requests")
+
+// OTPLimitError is returned when a code may not be sent or checked yet
+type OTPLimitError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *OTPLimitError) Error() string {
+	return fmt.Sprintf("%s, retry in %d seconds", ErrOTPRateLimited, e.RetrySeconds())
+}
+
+func (e *OTPLimitError) Is(target error) bool {
+	return target == ErrOTPRateLimited
+}
+
+// RetrySeconds is RetryAfter rounded up to whole seconds
+func (e *OTPLimitError) RetrySeconds() int {
+	return int((e.RetryAfter + time.Second - 1) / time.Second)
+}
+
+// otpLimiter keeps OTP send and verification counters in Redis. Without Redis,
+// or when Redis errors, nothing is limited.
+type otpLimiter struct {
+	redis    *redis.Client
+	settings *SettingsService
+}
+
+func otpLimitKey(kind, channel, target string) string {
+	return fmt.Sprintf("otp_%s:%s:%s", kind, channel, target)
+}
+
+// ttl returns how long key has left, or zero when it does not exist
+func (l *otpLimiter) ttl(ctx context.Context, key string) time.Duration {
+	ttl, err := l.redis.TTL(ctx, key).Result()
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
+// allowSend reserves a send to target, refusing while it is locked out, within
+// the resend cooldown or over the hourly limit. A new code gets fresh attempts.
+func (l *otpLimiter) allowSend(channel, target string) error {
+	if l.redis == nil {
+		return nil
+	}
+	ctx := context.Background()
+
+	if ttl := l.ttl(ctx, otpLimitKey("lock", channel, target)); ttl > 0 {
+		return &OTPLimitError{Reason: OTPLimitLocked, RetryAfter: ttl}
+	}
+
+	// A zero cooldown turns it off; SetNX would otherwise keep the key forever
+	if cooldown := l.settings.Duration(SettingOTPResendCooldown); cooldown > 0 {
+		cooldownKey := otpLimitKey("cooldown", channel, target)
+		fresh, err := l.redis.SetNX(ctx, cooldownKey, 1, cooldown).Result()
+		if err != nil {
+			return nil
+		}
+		if !fresh {
+			return &OTPLimitError{Reason: OTPLimitCooldown, RetryAfter: l.ttl(ctx, cooldownKey)}
+		}
+	}
+
+	sendsKey := otpLimitKey("sends", channel, target)
+	sends, err := l.redis.Incr(ctx, sendsKey).Result()
+	if err != nil {
+		return nil
+	}
+	if sends == 1 {
+		l.redis.Expire(ctx, sendsKey, time.Hour)
+	}
+	if sends > int64(l.settings.Int(SettingOTPMaxSendsPerHour)) {
+		return &OTPLimitError{Reason: OTPLimitHourly, RetryAfter: l.ttl(ctx, sendsKey)}
+	}
+
+	l.redis.Del(ctx, otpLimitKey("attempts", channel, target))
+	return nil
+}
+
+// allowVerify refuses checks while target is locked out
+func (l *otpLimiter) allowVerify(channel, target string) error {
+	if l.redis == nil {
+		return nil
+	}
+	if ttl := l.ttl(context.Background(), otpLimitKey("lock", channel, target)); ttl > 0 {
+		return &OTPLimitError{Reason: OTPLimitLocked, RetryAfter: ttl}
+	}
+	return nil
+}
+
+// recordFailure counts a wrong code and locks target out once the code has
+// used up its attempts; the lockout is returned so the client can show it
+func (l *otpLimiter) recordFailure(channel, target string) error {
+	if l.redis == nil {
+		return nil
+	}
+	ctx := context.Background()
+
+	lockout := l.settings.Duration(SettingOTPLockout)
+	attemptsKey := otpLimitKey("attempts", channel, target)
+	attempts, err := l.redis.Incr(ctx, attemptsKey).Result()
+	if err != nil {
+		return nil
+	}
+	if attempts == 1 {
+		l.redis.Expire(ctx, attemptsKey, lockout)
+	}
+	if attempts < int64(l.settings.Int(SettingOTPMaxVerifyAttempts)) {
+		return nil
+	}
+
+	l.redis.Set(ctx, otpLimitKey("lock", channel, target), 1, lockout)
+	l.redis.Del(ctx, attemptsKey)
+	return &OTPLimitError{Reason: OTPLimitLocked, RetryAfter: lockout}
+}
+
+// recordSuccess clears the attempts of a code that was verified
+func (l *otpLimiter) recordSuccess(channel, target string) {
+	if l.redis == nil {
+		return
+	}
+	l.redis.Del(context.Background(), otpLimitKey("attempts", channel, target))
+}
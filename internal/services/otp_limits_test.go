@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/repository"
+)
+
+func newTestOTPAuthService(t *testing.T) (*AuthService, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return NewAuthService(&repository.Repositories{}, &config.Config{}, client, nil, nil, nil, nil, nil, nil, nil, nil), mr
+}
+
+func limitReason(err error) string {
+	var limitErr *OTPLimitError
+	if errors.As(err, &limitErr) {
+		return limitErr.Reason
+	}
+	return ""
+}
+
+func TestSendOTPLimits(t *testing.T) {
+	svc, mr := newTestOTPAuthService(t)
+
+	if err := svc.SendOTP("05321234567"); err != nil {
+		t.Fatal(err)
+	}
+	err := svc.SendOTP("+905321234567")
+	if limitReason(err) != OTPLimitCooldown || !errors.Is(err, ErrOTPRateLimited) {
+		t.Fatalf("expected the cooldown for the same number in another format, got %v", err)
+	}
+	if retry := err.(*OTPLimitError).RetrySeconds(); retry != 60 {
+		t.Errorf("expected a 60 second retry, got %d", retry)
+	}
+
+	for i := 1; i < otpMaxSendsPerHour; i++ {
+		mr.FastForward(otpResendCooldown)
+		if err := svc.SendOTP("05321234567"); err != nil {
+			t.Fatalf("send %d: %v", i+1, err)
+		}
+	}
+	mr.FastForward(otpResendCooldown)
+	err = svc.SendOTP("05321234567")
+	if limitReason(err) != OTPLimitHourly {
+		t.Fatalf("expected the hourly limit, got %v", err)
+	}
+	if retry := err.(*OTPLimitError).RetryAfter; retry <= 0 || retry > time.Hour {
+		t.Errorf("unexpected retry after %v", retry)
+	}
+}
+
+func TestVerifyOTPLockout(t *testing.T) {
+	svc, mr := newTestOTPAuthService(t)
+
+	for i := 1; i < otpMaxVerifyAttempts; i++ {
+		if err := svc.VerifyOTP("05321234567", "000000"); !errors.Is(err, ErrInvalidOTP) {
+			t.Fatalf("attempt %d: expected ErrInvalidOTP, got %v", i, err)
+		}
+	}
+	if err := svc.VerifyOTP("05321234567", "000000"); limitReason(err) != OTPLimitLocked {
+		t.Fatalf("expected a lockout on the last attempt, got %v", err)
+	}
+
+	// Locked out of both checking and requesting a new code
+	if err := svc.VerifyOTP("05321234567", "123456"); limitReason(err) != OTPLimitLocked {
+		t.Errorf("expected the lockout to hold, got %v", err)
+	}
+	if err := svc.SendOTP("05321234567"); limitReason(err) != OTPLimitLocked {
+		t.Errorf("expected sends to be locked out, got %v", err)
+	}
+
+	mr.FastForward(otpLockout)
+	if err := svc.SendOTP("05321234567"); err != nil {
+		t.Errorf("expected sends after the lockout, got %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/queue"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// Outbox delivery channels
+const (
+	OutboxChannelWebSocket = "ws"
+	OutboxChannelPush      = "push"
+)
+
+const (
+	outboxPollInterval = 5 * time.Second
+	outboxLease        = time.Minute
+	outboxBatchSize    = 100
+	outboxMaxAttempts  = 10
+	outboxBaseBackoff  = 5 * time.Second
+	outboxMaxBackoff   = 5 * time.Minute
+	// Realtime events older than this are dropped; reconnecting clients
+	// reload the screen anyway
+	outboxWebSocketTTL = 10 * time.Minute
+	// Delivered messages are kept this long for inspection
+	outboxRetention = 3 * 24 * time.Hour
+)
+
+var errNoRealtime = errors.New("no WebSocket hub in this process")
+
+// RoomBroadcaster delivers realtime events to WebSocket user, conversation and admin rooms
+type RoomBroadcaster interface {
+	BroadcastToUser(userID string, msgType string, payload interface{})
+	BroadcastConversationEvent(convID string, msgType string, payload interface{})
+	BroadcastToRoom(room string, msgType string, payload interface{})
+}
+
+// outboxPush is the payload of a push outbox message
+type outboxPush struct {
+	Title string                 `json:"title"`
+	Body  string                 `json:"body"`
+	Data  map[string]interface{} `json:"data,omitempty"`
+}
+
+// Outbox delivers WebSocket broadcasts and push notifications caused by a
+// transaction at least once: they are written in the transaction and sent by
+// a dispatcher in the API process after it commits, surviving a crash in between.
+type Outbox struct {
+	repos    *repository.Repositories
+	jobs     *queue.Queue
+	realtime RoomBroadcaster
+	wake     chan struct{}
+}
+
+func NewOutbox(repos *repository.Repositories, jobs *queue.Queue) *Outbox {
+	return &Outbox{repos: repos, jobs: jobs, wake: make(chan struct{}, 1)}
+}
+
+// SetBroadcaster gives the dispatcher the WebSocket hub. Only the API process
+// has connections, so only it runs the dispatcher.
+func (o *Outbox) SetBroadcaster(b RoomBroadcaster) {
+	o.realtime = b
+}
+
+// BroadcastToUser queues a realtime event for a user's connections through tx.
+// It is safe to call on a nil outbox.
+func (o *Outbox) BroadcastToUser(tx *repository.Repositories, userID uuid.UUID, msgType string, payload interface{}) error {
+	return o.add(tx, OutboxChannelWebSocket, "user:"+userID.String(), msgType, payload)
+}
+
+// BroadcastToConversation queues a realtime event for a conversation room through tx.
+// It is safe to call on a nil outbox.
+func (o *Outbox) BroadcastToConversation(tx *repository.Repositories, convID uuid.UUID, msgType string, payload interface{}) error {
+	return o.add(tx, OutboxChannelWebSocket, "conv:"+convID.String(), msgType, payload)
+}
+
+// BroadcastToAdmins queues a realtime event for the admin dashboards in the
+// admin:events room through tx. It is safe to call on a nil outbox.
+func (o *Outbox) BroadcastToAdmins(tx *repository.Repositories, msgType string, payload interface{}) error {
+	return o.add(tx, OutboxChannelWebSocket, ActivityRoom, msgType, payload)
+}
+
+// Push queues a push notification to a user's devices through tx. It is safe
+// to call on a nil outbox.
+func (o *Outbox) Push(tx *repository.Repositories, userID uuid.UUID, notifType, title, body string, data map[string]interface{}) error {
+	return o.add(tx, OutboxChannelPush, userID.String(), notifType, outboxPush{Title: title, Body: body, Data: data})
+}
+
+func (o *Outbox) add(tx *repository.Repositories, channel, target, msgType string, payload interface{}) error {
+	if o == nil {
+		return nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return tx.Outbox.Create(&models.OutboxMessage{
+		Channel:       channel,
+		Target:        target,
+		Type:          msgType,
+		Payload:       string(data),
+		Status:        "pending",
+		NextAttemptAt: &now,
+	})
+}
+
+// Flush wakes the dispatcher so committed messages go out without waiting
+// for the next poll. It is safe to call on a nil outbox.
+func (o *Outbox) Flush() {
+	if o == nil {
+		return
+	}
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers due messages until the process exits
+func (o *Outbox) Run() {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-o.wake:
+		}
+		o.dispatchDue()
+	}
+}
+
+func (o *Outbox) dispatchDue() {
+	messages, err := o.repos.Outbox.ClaimDue(outboxBatchSize, outboxLease)
+	if err != nil {
+		log.Printf("[OUTBOX] failed to claim messages: %v", err)
+		return
+	}
+	for i := range messages {
+		o.dispatch(&messages[i])
+	}
+}
+
+// dispatch delivers one message and records the outcome, scheduling a retry on failure
+func (o *Outbox) dispatch(message *models.OutboxMessage) {
+	message.Attempts++
+
+	err := o.deliver(message)
+	now := time.Now()
+	switch {
+	case err == nil:
+		message.Status = "delivered"
+		message.DeliveredAt = &now
+		message.NextAttemptAt = nil
+		message.LastError = nil
+	case message.Attempts >= outboxMaxAttempts:
+		msg := err.Error()
+		message.Status = "failed"
+		message.LastError = &msg
+		message.NextAttemptAt = nil
+	default:
+		msg := err.Error()
+		next := now.Add(outboxBackoff(message.Attempts))
+		message.LastError = &msg
+		message.NextAttemptAt = &next
+	}
+
+	if err := o.repos.Outbox.Update(message); err != nil {
+		log.Printf("[OUTBOX] failed to record delivery of %s: %v", message.ID, err)
+	}
+}
+
+func (o *Outbox) deliver(message *models.OutboxMessage) error {
+	switch message.Channel {
+	case OutboxChannelWebSocket:
+		if time.Since(message.CreatedAt) > outboxWebSocketTTL {
+			message.Attempts = outboxMaxAttempts
+			return errors.New("expired before delivery")
+		}
+		if o.realtime == nil {
+			return errNoRealtime
+		}
+		kind, id, _ := strings.Cut(message.Target, ":")
+		payload := json.RawMessage(message.Payload)
+		switch kind {
+		case "user":
+			o.realtime.BroadcastToUser(id, message.Type, payload)
+		case "conv":
+			o.realtime.BroadcastConversationEvent(id, message.Type, payload)
+		case "admin":
+			o.realtime.BroadcastToRoom(message.Target, message.Type, payload)
+		default:
+			message.Attempts = outboxMaxAttempts
+			return fmt.Errorf("unknown room %q", message.Target)
+		}
+		return nil
+	case OutboxChannelPush:
+		userID, err := uuid.Parse(message.Target)
+		if err != nil {
+			message.Attempts = outboxMaxAttempts
+			return fmt.Errorf("invalid push target %q", message.Target)
+		}
+		var push outboxPush
+		if err := json.Unmarshal([]byte(message.Payload), &push); err != nil {
+			message.Attempts = outboxMaxAttempts
+			return err
+		}
+		return o.jobs.Enqueue(JobSendPush, pushJob{UserID: userID, Title: push.Title, Body: push.Body, Data: push.Data})
+	}
+	message.Attempts = outboxMaxAttempts
+	return fmt.Errorf("unknown channel %q", message.Channel)
+}
+
+// Prune deletes delivered messages past their retention
+func (o *Outbox) Prune() {
+	if err := o.repos.Outbox.PruneDelivered(time.Now().Add(-outboxRetention)); err != nil {
+		log.Printf("[OUTBOX] failed to prune messages: %v", err)
+	}
+}
+
+// outboxBackoff doubles the wait after each failed delivery, capped at outboxMaxBackoff
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return delay
+}
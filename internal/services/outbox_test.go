@@ -0,0 +1,143 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/queue"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"gorm.io/gorm"
+)
+
+type recordingRooms struct {
+	events []string
+}
+
+func (r *recordingRooms) BroadcastToUser(userID string, msgType string, payload interface{}) {
+	r.events = append(r.events, "user:"+userID+" "+msgType+" "+string(payload.(json.RawMessage)))
+}
+
+func (r *recordingRooms) BroadcastConversationEvent(convID string, msgType string, payload interface{}) {
+	r.events = append(r.events, "conv:"+convID+" "+msgType+" "+string(payload.(json.RawMessage)))
+}
+
+func (r *recordingRooms) BroadcastToRoom(room string, msgType string, payload interface{}) {
+	r.events = append(r.events, room+" "+msgType+" "+string(payload.(json.RawMessage)))
+}
+
+func TestOutboxDispatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	messages := mocks.NewMockOutboxRepository(ctrl)
+	repos := &repository.Repositories{Outbox: messages}
+	mr := miniredis.RunT(t)
+	outbox := NewOutbox(repos, queue.New(redis.NewClient(&redis.Options{Addr: mr.Addr()}), queue.NewMux()))
+
+	// Messages are written through the caller's transaction
+	var stored []models.OutboxMessage
+	messages.EXPECT().Create(gomock.Any()).DoAndReturn(func(m *models.OutboxMessage) error {
+		m.ID = uuid.New()
+		m.CreatedAt = time.Now()
+		stored = append(stored, *m)
+		return nil
+	}).Times(2)
+	convID, userID := uuid.New(), uuid.New()
+	if err := outbox.BroadcastToConversation(repos, convID, "message", map[string]string{"content": "Merhaba"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Push(repos, userID, "chat", "Yeni mesaj", "Merhaba", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a hub the broadcast stays pending for a retry; the push is queued
+	messages.EXPECT().ClaimDue(outboxBatchSize, outboxLease).Return(stored, nil)
+	var updated []models.OutboxMessage
+	messages.EXPECT().Update(gomock.Any()).DoAndReturn(func(m *models.OutboxMessage) error {
+		updated = append(updated, *m)
+		return nil
+	}).Times(2)
+	outbox.dispatchDue()
+	if ws := updated[0]; ws.Status != "pending" || ws.NextAttemptAt == nil || ws.LastError == nil {
+		t.Errorf("expected the broadcast scheduled for a retry, got %+v", ws)
+	}
+	if push := updated[1]; push.Status != "delivered" || push.DeliveredAt == nil {
+		t.Errorf("expected the push delivered, got %+v", push)
+	}
+	if jobs, _ := mr.List("queue:pending"); len(jobs) != 1 {
+		t.Errorf("expected one push job, got %d", len(jobs))
+	}
+
+	// The retry reaches the conversation room with the stored payload
+	rooms := &recordingRooms{}
+	outbox.SetBroadcaster(rooms)
+	messages.EXPECT().ClaimDue(outboxBatchSize, outboxLease).Return(updated[:1], nil)
+	messages.EXPECT().Update(gomock.Any()).Return(nil)
+	outbox.dispatchDue()
+	want := "conv:" + convID.String() + ` message {"content":"Merhaba"}`
+	if len(rooms.events) != 1 || rooms.events[0] != want {
+		t.Errorf("unexpected broadcasts %v", rooms.events)
+	}
+}
+
+func TestOutboxDropsStaleBroadcasts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	messages := mocks.NewMockOutboxRepository(ctrl)
+	outbox := NewOutbox(&repository.Repositories{Outbox: messages}, nil)
+	rooms := &recordingRooms{}
+	outbox.SetBroadcaster(rooms)
+
+	stale := models.OutboxMessage{
+		ID:        uuid.New(),
+		Channel:   OutboxChannelWebSocket,
+		Target:    "user:" + uuid.NewString(),
+		Type:      "eta_updated",
+		Payload:   "{}",
+		Status:    "pending",
+		CreatedAt: time.Now().Add(-time.Hour),
+	}
+	messages.EXPECT().ClaimDue(outboxBatchSize, outboxLease).Return([]models.OutboxMessage{stale}, nil)
+	messages.EXPECT().Update(gomock.Any()).DoAndReturn(func(m *models.OutboxMessage) error {
+		if m.Status != "failed" || m.NextAttemptAt != nil {
+			t.Errorf("expected the stale broadcast dropped, got %+v", m)
+		}
+		return nil
+	})
+	outbox.dispatchDue()
+	if len(rooms.events) != 0 {
+		t.Errorf("stale broadcast was sent: %v", rooms.events)
+	}
+}
+
+func TestNotificationSendTxQueuesPush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	notifications := mocks.NewMockNotificationRepository(ctrl)
+	prefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+	messages := mocks.NewMockOutboxRepository(ctrl)
+	repos := &repository.Repositories{Notification: notifications, NotificationPreference: prefs, Outbox: messages}
+	svc := NewNotificationService(repos, nil, nil, nil, nil, NewOutbox(repos, nil))
+	userID := uuid.New()
+
+	notifications.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+	prefs.EXPECT().GetByUserAndType(userID, "order").Return(nil, gorm.ErrRecordNotFound)
+	messages.EXPECT().Create(gomock.Any()).DoAndReturn(func(m *models.OutboxMessage) error {
+		if m.Channel != OutboxChannelPush || m.Target != userID.String() || m.Type != "order" {
+			t.Errorf("unexpected outbox message %+v", m)
+		}
+		return nil
+	})
+	if err := svc.SendTx(repos, userID, "Siparişiniz kabul edildi", "#YND-1042", "order", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Users who turned push off for the type only get the in-app notification
+	prefs.EXPECT().GetByUserAndType(userID, "promotion").Return(&models.NotificationPreference{Type: "promotion"}, nil)
+	if err := svc.SendTx(repos, userID, "Kampanya", "%20 indirim", "promotion", nil); err != nil {
+		t.Fatal(err)
+	}
+}
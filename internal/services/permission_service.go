@@ -0,0 +1,194 @@
+package services
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// Permissions checked by admin routes. Admins implicitly hold all of them.
+const (
+	PermissionDashboardView       = "dashboard.view"
+	PermissionUsersView           = "users.view"
+	PermissionUsersManage         = "users.manage"
+	PermissionApplicationsManage  = "applications.manage"
+	PermissionOrdersView          = "orders.view"
+	PermissionCategoriesManage    = "categories.manage"
+	PermissionAnalyticsView       = "analytics.view"
+	PermissionAuditLogsView       = "audit_logs.view"
+	PermissionCallRecordingsView  = "calls.recordings.view"
+	PermissionConversationsReview = "conversations.review"
+	PermissionSupportManage       = "support.manage"
+	PermissionReviewsModerate     = "reviews.moderate"
+	PermissionContentModerate     = "content.moderate"
+	PermissionPayoutsManage       = "payouts.manage"
+	PermissionRolesManage         = "roles.manage"
+	PermissionWebhooksManage      = "webhooks.manage"
+	PermissionMonitoringManage    = "monitoring.manage"
+	PermissionAnnouncementsManage = "announcements.manage"
+	PermissionLegalManage         = "legal.manage"
+	PermissionSettingsManage      = "settings.manage"
+	PermissionReportsView         = "reports.view"
+	PermissionDebugCapture        = "debug.capture"
+)
+
+// AllPermissions lists every known permission
+var AllPermissions = []string{
+	PermissionDashboardView,
+	PermissionUsersView,
+	PermissionUsersManage,
+	PermissionApplicationsManage,
+	PermissionOrdersView,
+	PermissionCategoriesManage,
+	PermissionAnalyticsView,
+	PermissionAuditLogsView,
+	PermissionCallRecordingsView,
+	PermissionConversationsReview,
+	PermissionSupportManage,
+	PermissionReviewsModerate,
+	PermissionContentModerate,
+	PermissionPayoutsManage,
+	PermissionRolesManage,
+	PermissionWebhooksManage,
+	PermissionMonitoringManage,
+	PermissionAnnouncementsManage,
+	PermissionLegalManage,
+	PermissionSettingsManage,
+	PermissionReportsView,
+	PermissionDebugCapture,
+}
+
+// SystemRoles are the built-in staff roles seeded on startup
+var SystemRoles = []models.Role{
+	{
+		Name:        "support_agent",
+		Description: "Destek talepleri",
+		Permissions: []string{PermissionSupportManage, PermissionUsersView, PermissionOrdersView},
+	},
+	{
+		Name:        "moderator",
+		Description: "Değerlendirme ve içerik moderasyonu",
+		Permissions: []string{PermissionReviewsModerate, PermissionContentModerate, PermissionUsersView},
+	},
+	{
+		Name:        "finance",
+		Description: "Analitik ve ödemeler",
+		Permissions: []string{PermissionDashboardView, PermissionAnalyticsView, PermissionPayoutsManage, PermissionOrdersView, PermissionReportsView},
+	},
+}
+
+// PermissionService resolves staff permissions
+type PermissionService struct {
+	repos *repository.Repositories
+}
+
+func NewPermissionService(repos *repository.Repositories) *PermissionService {
+	return &PermissionService{repos: repos}
+}
+
+// EnsureSystemRoles creates the built-in roles and keeps their permissions current
+func (s *PermissionService) EnsureSystemRoles() error {
+	for _, role := range SystemRoles {
+		role := role
+		if err := s.repos.Role.UpsertSystemRole(&role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasPermission reports whether one of the user's roles grants the permission
+func (s *PermissionService) HasPermission(userID uuid.UUID, permission string) bool {
+	return s.repos.Role.UserHasPermission(userID, permission)
+}
+
+// UserPermissions returns the distinct permissions granted to a user
+func (s *PermissionService) UserPermissions(userID uuid.UUID, accountRole string) ([]string, error) {
+	if accountRole == "admin" {
+		return AllPermissions, nil
+	}
+
+	roles, err := s.repos.Role.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	permissions := []string{}
+	for _, role := range roles {
+		for _, p := range role.Permissions {
+			if !seen[p] {
+				seen[p] = true
+				permissions = append(permissions, p)
+			}
+		}
+	}
+	return permissions, nil
+}
+
+// ListRoles returns all roles
+func (s *PermissionService) ListRoles() ([]models.Role, error) {
+	return s.repos.Role.List()
+}
+
+// GetUserRoles returns the roles assigned to a user
+func (s *PermissionService) GetUserRoles(userID uuid.UUID) ([]models.Role, error) {
+	if _, err := s.repos.User.GetByID(userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+	return s.repos.Role.ListByUser(userID)
+}
+
+// AssignRolesInput represents a user's new role set
+type AssignRolesInput struct {
+	Roles []string `json:"roles" binding:"required"`
+}
+
+// AssignRoles replaces the user's roles with the named ones
+func (s *PermissionService) AssignRoles(userID uuid.UUID, input *AssignRolesInput, adminID uuid.UUID) ([]models.Role, error) {
+	if _, err := s.repos.User.GetByID(userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	names := []string{}
+	seen := map[string]bool{}
+	for _, name := range input.Roles {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	roleIDs := []uuid.UUID{}
+	if len(names) > 0 {
+		roles, err := s.repos.Role.GetByNames(names)
+		if err != nil {
+			return nil, err
+		}
+		if len(roles) != len(names) {
+			return nil, validationError("unknown_role", "unknown role")
+		}
+		for _, role := range roles {
+			roleIDs = append(roleIDs, role.ID)
+		}
+	}
+
+	if err := s.repos.Role.ReplaceUserRoles(userID, roleIDs, adminID); err != nil {
+		return nil, err
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{"roles": names})
+	newValues := string(data)
+	entityType := "user"
+	s.repos.AuditLog.Create(&models.AuditLog{
+		AdminID:    adminID,
+		Action:     "assign_roles",
+		EntityType: &entityType,
+		EntityID:   &userID,
+		NewValues:  &newValues,
+	})
+
+	return s.repos.Role.ListByUser(userID)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestHasPermission(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roles := mocks.NewMockRoleRepository(ctrl)
+	svc := NewPermissionService(&repository.Repositories{Role: roles})
+
+	userID := uuid.New()
+	roles.EXPECT().UserHasPermission(userID, PermissionSupportManage).Return(true)
+	roles.EXPECT().UserHasPermission(userID, PermissionPayoutsManage).Return(false)
+	if !svc.HasPermission(userID, PermissionSupportManage) {
+		t.Error("expected the granted permission to be held")
+	}
+	if svc.HasPermission(userID, PermissionPayoutsManage) {
+		t.Error("expected a permission no role grants to be refused")
+	}
+}
+
+func TestUserPermissions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roles := mocks.NewMockRoleRepository(ctrl)
+	svc := NewPermissionService(&repository.Repositories{Role: roles})
+
+	// Admins hold every permission without any role
+	got, err := svc.UserPermissions(uuid.New(), "admin")
+	if err != nil || !reflect.DeepEqual(got, AllPermissions) {
+		t.Errorf("expected every permission for an admin, got %v %v", got, err)
+	}
+
+	staff := uuid.New()
+	roles.EXPECT().ListByUser(staff).Return([]models.Role{
+		{Name: "support_agent", Permissions: []string{PermissionSupportManage, PermissionUsersView}},
+		{Name: "moderator", Permissions: []string{PermissionReviewsModerate, PermissionUsersView}},
+	}, nil)
+	got, err = svc.UserPermissions(staff, "customer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{PermissionSupportManage, PermissionUsersView, PermissionReviewsModerate}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the roles' distinct permissions %v, got %v", want, got)
+	}
+
+	nobody := uuid.New()
+	roles.EXPECT().ListByUser(nobody).Return(nil, nil)
+	if got, err := svc.UserPermissions(nobody, "customer"); err != nil || len(got) != 0 {
+		t.Errorf("expected no permissions without roles, got %v %v", got, err)
+	}
+
+	failing := uuid.New()
+	roles.EXPECT().ListByUser(failing).Return(nil, errors.New("connection refused"))
+	if _, err := svc.UserPermissions(failing, "customer"); err == nil {
+		t.Error("expected a lookup failure to be returned, not an empty permission set")
+	}
+}
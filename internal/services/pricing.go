@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+// Price tier bases
+const (
+	PriceTierDuration = "duration" // minutes
+	PriceTierDistance = "distance" // km
+)
+
+// PricingInput carries what an order's price depends on
+type PricingInput struct {
+	BasePrice       currency.Money
+	OptionIDs       []uuid.UUID
+	DurationMinutes *float64
+	DistanceKm      *float64
+	TravelKm        *float64 // from the yandaş's base, set with TravelFee
+	TravelFee       *currency.Money
+}
+
+// priceOrder builds the line items for an order and returns them with their total.
+// The service must be loaded with its active options and price tiers.
+func priceOrder(service *models.YandasService, input PricingInput) ([]models.OrderLineItem, currency.Money, error) {
+	items := []models.OrderLineItem{{
+		Kind:        "base",
+		Description: service.Title,
+		Amount:      input.BasePrice,
+	}}
+
+	options := make(map[uuid.UUID]models.ServiceOption, len(service.Options))
+	for _, option := range service.Options {
+		options[option.ID] = option
+	}
+
+	seen := map[uuid.UUID]bool{}
+	for _, id := range input.OptionIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		option, ok := options[id]
+		if !ok {
+			return nil, 0, validationError("invalid_service_option", "invalid service option")
+		}
+		optionID := option.ID
+		items = append(items, models.OrderLineItem{
+			Kind:        "option",
+			OptionID:    &optionID,
+			Description: option.Name,
+			Amount:      option.Price,
+		})
+	}
+
+	if input.DurationMinutes != nil {
+		if tier := matchPriceTier(service.PriceTiers, PriceTierDuration, *input.DurationMinutes); tier != nil && tier.Surcharge > 0 {
+			items = append(items, models.OrderLineItem{
+				Kind:        "tier",
+				Description: fmt.Sprintf("Süre ücreti (%.0f dk'ya kadar)", tier.UpTo),
+				Amount:      tier.Surcharge,
+			})
+		}
+	}
+
+	if input.DistanceKm != nil {
+		if tier := matchPriceTier(service.PriceTiers, PriceTierDistance, *input.DistanceKm); tier != nil && tier.Surcharge > 0 {
+			items = append(items, models.OrderLineItem{
+				Kind:        "tier",
+				Description: fmt.Sprintf("Mesafe ücreti (%.0f km'ye kadar)", tier.UpTo),
+				Amount:      tier.Surcharge,
+			})
+		}
+	}
+
+	if input.TravelFee != nil && *input.TravelFee > 0 && input.TravelKm != nil {
+		items = append(items, models.OrderLineItem{
+			Kind:        "travel",
+			Description: fmt.Sprintf("Yol ücreti (%.1f km)", *input.TravelKm),
+			Amount:      *input.TravelFee,
+		})
+	}
+
+	var total currency.Money
+	for _, item := range items {
+		total += item.Amount
+	}
+
+	return items, total, nil
+}
+
+// matchPriceTier returns the narrowest band covering value, or the highest band if value exceeds them all
+func matchPriceTier(tiers []models.ServicePriceTier, basis string, value float64) *models.ServicePriceTier {
+	var covering, highest *models.ServicePriceTier
+	for i := range tiers {
+		tier := &tiers[i]
+		if tier.Basis != basis {
+			continue
+		}
+		if value <= tier.UpTo && (covering == nil || tier.UpTo < covering.UpTo) {
+			covering = tier
+		}
+		if highest == nil || tier.UpTo > highest.UpTo {
+			highest = tier
+		}
+	}
+	if covering != nil {
+		return covering
+	}
+	return highest
+}
+
+// haversineKm returns the great-circle distance between two coordinates
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+func TestPriceOrder(t *testing.T) {
+	flowers := models.ServiceOption{ID: uuid.New(), Name: "Çiçek", Price: 15000}
+	service := &models.YandasService{
+		Title:   "Düğün eşliği",
+		Options: []models.ServiceOption{flowers},
+		PriceTiers: []models.ServicePriceTier{
+			{Basis: PriceTierDuration, UpTo: 60, Surcharge: 0},
+			{Basis: PriceTierDuration, UpTo: 180, Surcharge: 20000},
+			{Basis: PriceTierDistance, UpTo: 10, Surcharge: 5000},
+			{Basis: PriceTierDistance, UpTo: 30, Surcharge: 12000},
+		},
+	}
+
+	minutes := 120.0
+	km := 45.0
+	items, total, err := priceOrder(service, PricingInput{
+		BasePrice:       100000,
+		OptionIDs:       []uuid.UUID{flowers.ID, flowers.ID},
+		DurationMinutes: &minutes,
+		DistanceKm:      &km,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// base + option (once) + 180 min band + highest distance band
+	if want := currency.Money(100000 + 15000 + 20000 + 12000); total != want {
+		t.Errorf("total = %v, want %v", total, want)
+	}
+	if len(items) != 4 {
+		t.Errorf("expected 4 line items, got %d", len(items))
+	}
+
+	if _, _, err := priceOrder(service, PricingInput{OptionIDs: []uuid.UUID{uuid.New()}}); err == nil {
+		t.Error("expected unknown option to be rejected")
+	}
+}
+
+func TestHaversineKm(t *testing.T) {
+	// Taksim to Kadıköy is roughly 6 km as the crow flies
+	d := haversineKm(41.0370, 28.9850, 40.9900, 29.0290)
+	if math.Abs(d-6.4) > 1 {
+		t.Errorf("unexpected distance %.2f km", d)
+	}
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+// The public views of yandaşlar and their reviews. Public endpoints return
+// these instead of models, so a relation loaded for another purpose never
+// puts a user's email, phone or full name in a response.
+
+// PublicName shortens a full name to the first name and the last name's
+// initial: "Ayşe Nur Yılmaz" becomes "Ayşe Y."
+func PublicName(fullName string) string {
+	names := strings.Fields(fullName)
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	initial, _ := utf8.DecodeRuneInString(names[len(names)-1])
+	return names[0] + " " + string(unicode.TurkishCase.ToUpper(initial)) + "."
+}
+
+// PublicYandas is a yandaş as listings, search and favorites show them
+type PublicYandas struct {
+	ID            uuid.UUID       `json:"id"`
+	UserID        uuid.UUID       `json:"user_id"` // to start a conversation
+	Slug          *string         `json:"slug,omitempty"`
+	DisplayName   string          `json:"display_name"`
+	AvatarURL     *string         `json:"avatar_url,omitempty"`
+	RatingAvg     float64         `json:"rating_avg"`
+	TotalJobs     int             `json:"total_jobs"`
+	IsAvailable   bool            `json:"is_available"`
+	BackOn        *time.Time      `json:"back_on,omitempty"` // set while the yandaş is on vacation
+	ServiceCities pq.StringArray  `json:"service_cities"`
+	StartingPrice *currency.Money `json:"starting_price,omitempty"` // lowest active service base price
+}
+
+// PublicYandasProfile is the public profile page of a yandaş
+type PublicYandasProfile struct {
+	PublicYandas
+	Bio               *string                `json:"bio,omitempty"`
+	InstagramHandle   *string                `json:"instagram_handle,omitempty"`
+	InstagramVerified bool                   `json:"instagram_verified"`
+	ServiceRadiusKm   *float64               `json:"service_radius_km,omitempty"`
+	TravelFeePerKm    *currency.Money        `json:"travel_fee_per_km,omitempty"`
+	MinOrderValue     *currency.Money        `json:"min_order_value,omitempty"`
+	MinOrderCurrency  *string                `json:"min_order_currency,omitempty"`
+	InstantBooking    bool                   `json:"instant_booking"`
+	LeadTimeMinutes   int                    `json:"lead_time_minutes"`
+	MemberSince       time.Time              `json:"member_since"`
+	Services          []models.YandasService `json:"services"`
+}
+
+// PublicFavorite is a favorited yandaş in the user's favorites list
+type PublicFavorite struct {
+	ID        uuid.UUID     `json:"id"`
+	YandasID  uuid.UUID     `json:"yandas_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	Yandas    *PublicYandas `json:"yandas,omitempty"`
+}
+
+// PublicFavoriteActivity is a favorites feed entry; its yandaş is the public
+// view rather than the profile it was loaded with
+type PublicFavoriteActivity struct {
+	models.FavoriteActivity
+	Yandas *PublicYandas `json:"yandas,omitempty"`
+}
+
+// PublicReview is a review as shown on a yandaş's profile. Anonymous reviews
+// carry no reviewer.
+type PublicReview struct {
+	ID                  uuid.UUID  `json:"id"`
+	Rating              int        `json:"rating"`
+	Comment             *string    `json:"comment,omitempty"`
+	PunctualityRating   *int       `json:"punctuality_rating,omitempty"`
+	CommunicationRating *int       `json:"communication_rating,omitempty"`
+	QualityRating       *int       `json:"quality_rating,omitempty"`
+	IsAnonymous         bool       `json:"is_anonymous"`
+	ReviewerName        string     `json:"reviewer_name,omitempty"`
+	ReviewerAvatarURL   *string    `json:"reviewer_avatar_url,omitempty"`
+	Reply               *string    `json:"reply,omitempty"`
+	RepliedAt           *time.Time `json:"replied_at,omitempty"`
+	HelpfulCount        int        `json:"helpful_count"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// publicListItem converts a listing or search result
+func publicListItem(item *models.YandasListItem) PublicYandas {
+	return PublicYandas{
+		ID:            item.ID,
+		UserID:        item.UserID,
+		Slug:          item.Slug,
+		DisplayName:   PublicName(item.FullName),
+		AvatarURL:     item.AvatarURL,
+		RatingAvg:     item.RatingAvg,
+		TotalJobs:     item.TotalJobs,
+		IsAvailable:   item.IsAvailable,
+		BackOn:        item.BackOn,
+		ServiceCities: item.ServiceCities,
+		StartingPrice: item.StartingPrice,
+	}
+}
+
+func publicListItems(items []models.YandasListItem) []PublicYandas {
+	public := make([]PublicYandas, len(items))
+	for i := range items {
+		public[i] = publicListItem(&items[i])
+	}
+	return public
+}
+
+// publicYandas converts a profile loaded with its user; the starting price
+// comes from its services when they are loaded
+func publicYandas(profile *models.YandasProfile) PublicYandas {
+	public := PublicYandas{
+		ID:            profile.ID,
+		UserID:        profile.UserID,
+		Slug:          profile.Slug,
+		DisplayName:   PublicName(profile.User.FullName),
+		AvatarURL:     profile.User.AvatarURL,
+		RatingAvg:     profile.RatingAvg,
+		TotalJobs:     profile.TotalJobs,
+		IsAvailable:   profile.IsAvailable,
+		ServiceCities: profile.ServiceCities,
+	}
+	if profile.VacationStartedAt != nil {
+		public.BackOn = profile.VacationUntil
+	}
+	for i := range profile.Services {
+		service := &profile.Services[i]
+		if serviceBookable(service) && (public.StartingPrice == nil || service.BasePrice < *public.StartingPrice) {
+			price := service.BasePrice
+			public.StartingPrice = &price
+		}
+	}
+	return public
+}
+
+func publicYandasProfile(profile *models.YandasProfile) *PublicYandasProfile {
+	return &PublicYandasProfile{
+		PublicYandas:      publicYandas(profile),
+		Bio:               profile.Bio,
+		InstagramHandle:   profile.InstagramHandle,
+		InstagramVerified: profile.InstagramVerified,
+		ServiceRadiusKm:   profile.ServiceRadiusKm,
+		TravelFeePerKm:    profile.TravelFeePerKm,
+		MinOrderValue:     profile.MinOrderValue,
+		MinOrderCurrency:  profile.MinOrderCurrency,
+		InstantBooking:    profile.InstantBooking,
+		LeadTimeMinutes:   profile.LeadTimeMinutes,
+		MemberSince:       profile.CreatedAt,
+		Services:          profile.Services,
+	}
+}
+
+func publicFavorites(favorites []models.Favorite) []PublicFavorite {
+	public := make([]PublicFavorite, len(favorites))
+	for i, f := range favorites {
+		public[i] = PublicFavorite{ID: f.ID, YandasID: f.YandasID, CreatedAt: f.CreatedAt}
+		if f.Yandas != nil {
+			yandas := publicYandas(f.Yandas)
+			public[i].Yandas = &yandas
+		}
+	}
+	return public
+}
+
+func publicFavoriteActivities(activities []models.FavoriteActivity) []PublicFavoriteActivity {
+	public := make([]PublicFavoriteActivity, len(activities))
+	for i, a := range activities {
+		public[i].FavoriteActivity = a
+		public[i].FavoriteActivity.Yandas = nil
+		if a.Yandas != nil {
+			yandas := publicYandas(a.Yandas)
+			public[i].Yandas = &yandas
+		}
+	}
+	return public
+}
+
+func publicReviews(reviews []models.Review) []PublicReview {
+	public := make([]PublicReview, len(reviews))
+	for i, r := range reviews {
+		public[i] = PublicReview{
+			ID:                  r.ID,
+			Rating:              r.Rating,
+			Comment:             r.Comment,
+			PunctualityRating:   r.PunctualityRating,
+			CommunicationRating: r.CommunicationRating,
+			QualityRating:       r.QualityRating,
+			IsAnonymous:         r.IsAnonymous,
+			Reply:               r.Reply,
+			RepliedAt:           r.RepliedAt,
+			HelpfulCount:        r.HelpfulCount,
+			CreatedAt:           r.CreatedAt,
+		}
+		if !r.IsAnonymous && r.Reviewer != nil {
+			public[i].ReviewerName = PublicName(r.Reviewer.FullName)
+			public[i].ReviewerAvatarURL = r.Reviewer.AvatarURL
+		}
+	}
+	return public
+}
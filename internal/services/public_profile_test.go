@@ -0,0 +1,70 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+func TestPublicName(t *testing.T) {
+	cases := map[string]string{
+		"Ayşe Nur Yılmaz": "Ayşe Y.",
+		"mehmet  işçi":    "mehmet İ.",
+		"Cem":             "Cem",
+		"  ":              "",
+	}
+	for full, want := range cases {
+		if got := PublicName(full); got != want {
+			t.Errorf("PublicName(%q) = %q, want %q", full, got, want)
+		}
+	}
+}
+
+func TestPublicYandasLeavesOutContactDetails(t *testing.T) {
+	email, phone := "ayse@example.com", "+905551112233"
+	profile := &models.YandasProfile{
+		ID:     uuid.New(),
+		UserID: uuid.New(),
+		User:   models.User{FullName: "Ayşe Yılmaz", Email: &email, Phone: &phone},
+		Services: []models.YandasService{
+			{BasePrice: 50000, IsActive: true, Status: ServiceStatusActive},
+			{BasePrice: 20000, IsActive: true, Status: ServiceStatusDraft},
+			{BasePrice: 35000, IsActive: true, Status: ServiceStatusActive},
+		},
+	}
+
+	favorites := publicFavorites([]models.Favorite{{ID: uuid.New(), YandasID: profile.ID, Yandas: profile}})
+	body, err := json.Marshal(favorites)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, private := range []string{email, phone, "Yılmaz"} {
+		if strings.Contains(string(body), private) {
+			t.Errorf("favorites expose %q: %s", private, body)
+		}
+	}
+
+	yandas := favorites[0].Yandas
+	if yandas.DisplayName != "Ayşe Y." || yandas.StartingPrice == nil || *yandas.StartingPrice != currency.Money(35000) {
+		t.Errorf("unexpected public yandaş %+v", yandas)
+	}
+}
+
+func TestPublicReviewsHideAnonymousReviewers(t *testing.T) {
+	reviewer := &models.User{FullName: "Can Demir"}
+	reviews := publicReviews([]models.Review{
+		{Rating: 5, Reviewer: reviewer},
+		{Rating: 4, Reviewer: reviewer, IsAnonymous: true},
+	})
+
+	if reviews[0].ReviewerName != "Can D." {
+		t.Errorf("expected the reviewer's public name, got %q", reviews[0].ReviewerName)
+	}
+	if reviews[1].ReviewerName != "" {
+		t.Errorf("expected no reviewer on an anonymous review, got %q", reviews[1].ReviewerName)
+	}
+}
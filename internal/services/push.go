@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/pkg/push"
+)
+
+// DevicePlatformVoIP marks an iOS PushKit token; it only receives incoming call pushes
+const DevicePlatformVoIP = "ios_voip"
+
+// pushTimeout bounds delivering one push to all of a user's devices
+const pushTimeout = 15 * time.Second
+
+// Outcomes of an incoming call push, kept on the call log
+const (
+	PushStatusNone      = "none"
+	PushStatusSent      = "sent"
+	PushStatusFailed    = "failed"
+	PushStatusNoDevices = "no_devices"
+	PushStatusDisabled  = "disabled"
+)
+
+// sendPush delivers a notification to the user's devices. Failures on single
+// devices are logged so one stale token does not make the job retry for all.
+func (s *NotificationService) sendPush(userID uuid.UUID, title, body string, data map[string]interface{}) error {
+	if s.pusher == nil {
+		return nil
+	}
+	tokens, err := s.repos.DeviceToken.GetByUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	payload := make(map[string]string, len(data))
+	for k, v := range data {
+		payload[k] = fmt.Sprint(v)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+	defer cancel()
+	for _, token := range tokens {
+		if token.Platform == DevicePlatformVoIP {
+			continue
+		}
+		_, err := s.pusher.Send(ctx, &push.Message{Token: token.Token, Title: title, Body: body, Data: payload})
+		s.handlePushError(userID, token.Token, err)
+	}
+	return nil
+}
+
+// SendCallPush rings the user's devices for an incoming call with a high-priority
+// data message, or a VoIP push on iOS devices that registered one. The ttl keeps
+// a late push from ringing after the call stopped. It returns a PushStatus.
+func (s *NotificationService) SendCallPush(userID uuid.UUID, data map[string]string, ttl time.Duration) (string, error) {
+	if (s.pusher == nil && s.voip == nil) || !s.IsChannelEnabled(userID, "call", NotificationChannelPush) {
+		return PushStatusDisabled, nil
+	}
+	tokens, err := s.repos.DeviceToken.GetByUserID(userID)
+	if err != nil {
+		return PushStatusFailed, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+	defer cancel()
+
+	sent := 0
+	var lastErr error
+	for _, token := range tokens {
+		var err error
+		switch {
+		case token.Platform == DevicePlatformVoIP && s.voip != nil:
+			err = s.voip.SendVoIP(ctx, token.Token, data, ttl)
+		case token.Platform != DevicePlatformVoIP && s.pusher != nil:
+			_, err = s.pusher.Send(ctx, &push.Message{Token: token.Token, Data: data, HighPriority: true, TTL: ttl})
+		default:
+			continue
+		}
+		if err == nil {
+			sent++
+			continue
+		}
+		if s.handlePushError(userID, token.Token, err) {
+			lastErr = err
+		}
+	}
+
+	switch {
+	case sent > 0:
+		return PushStatusSent, nil
+	case lastErr != nil:
+		return PushStatusFailed, lastErr
+	default:
+		return PushStatusNoDevices, nil
+	}
+}
+
+// handlePushError drops unregistered tokens and logs other failures. It reports
+// whether err was a delivery failure, as opposed to nil or a stale token.
+func (s *NotificationService) handlePushError(userID uuid.UUID, token string, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, push.ErrInvalidToken):
+		if err := s.repos.DeviceToken.Deactivate(token); err != nil {
+			log.Printf("[PUSH] failed to deactivate token of %s: %v", userID, err)
+		}
+		return false
+	default:
+		log.Printf("[PUSH] delivery to %s failed: %v", userID, err)
+		return true
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/push"
+	"gorm.io/gorm"
+)
+
+type fakePushSender struct {
+	sent    []*push.Message
+	invalid map[string]bool
+}
+
+func (f *fakePushSender) Send(_ context.Context, msg *push.Message) (string, error) {
+	if f.invalid[msg.Token] {
+		return "", push.ErrInvalidToken
+	}
+	f.sent = append(f.sent, msg)
+	return "id", nil
+}
+
+func TestSendCallPush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tokens := mocks.NewMockDeviceTokenRepository(ctrl)
+	prefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+	svc := NewNotificationService(&repository.Repositories{DeviceToken: tokens, NotificationPreference: prefs}, nil, nil, nil, nil, nil)
+	userID := uuid.New()
+	prefs.EXPECT().GetByUserAndType(userID, "call").Return(nil, gorm.ErrRecordNotFound).AnyTimes()
+
+	// Nothing configured to deliver with
+	if status, _ := svc.SendCallPush(userID, nil, time.Minute); status != PushStatusDisabled {
+		t.Fatalf("expected %s, got %s", PushStatusDisabled, status)
+	}
+
+	sender := &fakePushSender{invalid: map[string]bool{"stale": true}}
+	svc.pusher = sender
+	tokens.EXPECT().GetByUserID(userID).Return([]models.DeviceToken{
+		{Token: "stale", Platform: "android"},
+		{Token: "pushkit", Platform: DevicePlatformVoIP},
+		{Token: "phone", Platform: "android"},
+	}, nil)
+	tokens.EXPECT().Deactivate("stale").Return(nil)
+
+	status, err := svc.SendCallPush(userID, map[string]string{"type": "incoming_call"}, 45*time.Second)
+	if err != nil || status != PushStatusSent {
+		t.Fatalf("expected %s, got %s (%v)", PushStatusSent, status, err)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].Token != "phone" || !sender.sent[0].HighPriority || sender.sent[0].TTL != 45*time.Second {
+		t.Errorf("unexpected pushes %+v", sender.sent)
+	}
+
+	// Only a VoIP token and no VoIP sender
+	tokens.EXPECT().GetByUserID(userID).Return([]models.DeviceToken{{Token: "pushkit", Platform: DevicePlatformVoIP}}, nil)
+	if status, _ := svc.SendCallPush(userID, nil, time.Minute); status != PushStatusNoDevices {
+		t.Errorf("expected %s, got %s", PushStatusNoDevices, status)
+	}
+}
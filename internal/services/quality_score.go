@@ -0,0 +1,113 @@
+package services
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// QualityInterval is how often yandaş quality scores are recomputed
+const QualityInterval = time.Hour
+
+// qualityWindow is how far back orders count towards the score
+const qualityWindow = 90 * 24 * time.Hour
+
+// Weights of the score's components; they add up to 1
+const (
+	qualityWeightRating        = 0.35
+	qualityWeightCompletion    = 0.25
+	qualityWeightResponse      = 0.15
+	qualityWeightDisputes      = 0.15
+	qualityWeightCancellations = 0.10
+)
+
+const (
+	// Figures are pulled towards the priors below as if the yandaş had this
+	// many more reviews or orders, so one order neither sinks a newcomer nor
+	// lifts them to the top
+	qualityPriorWeight        = 5
+	qualityPriorRating        = 4.0
+	qualityPriorCompletion    = 0.9
+	qualityPriorCancellations = 0.05
+	qualityPriorDisputes      = 0.02
+	// A median response of this many minutes scores half
+	qualityResponseHalfMinutes = 30.0
+	// Rates from which the dispute and cancellation components score nothing
+	qualityMaxDisputeRate      = 0.2
+	qualityMaxCancellationRate = 0.25
+)
+
+// ErrInvalidQualityFilter is returned for an unknown sort or an inverted score range
+var ErrInvalidQualityFilter = validationError("invalid_quality_filter", "invalid leaderboard filter")
+
+// RefreshQualityScores recomputes every approved yandaş's quality score; the
+// scheduler calls it every QualityInterval
+func (s *YandasService) RefreshQualityScores() {
+	now := time.Now()
+	rows, err := s.repos.Quality.Metrics(now.Add(-qualityWindow))
+	if err != nil {
+		log.Printf("[QUALITY] failed to count metrics: %v", err)
+		return
+	}
+	for i := range rows {
+		scoreQuality(&rows[i])
+		rows[i].ComputedAt = now
+	}
+	if err := s.repos.Quality.Replace(rows); err != nil {
+		log.Printf("[QUALITY] failed to store scores: %v", err)
+	}
+}
+
+// scoreQuality fills in the rates and the 0-100 score of a yandaş's counts.
+// The rates are the raw ones shown to admins; the score uses smoothed ones.
+func scoreQuality(row *models.YandasQualityScore) {
+	row.CompletionRate = ratio(row.CompletedCount, row.EndedCount)
+	row.CancellationRate = ratio(row.CancelledCount, row.OrderCount)
+	row.DisputeRate = ratio(row.DisputedCount, row.OrderCount)
+
+	rating := (qualityPriorRating*qualityPriorWeight + row.RatingAvg*float64(row.ReviewCount)) / (qualityPriorWeight + float64(row.ReviewCount))
+	completion := smoothedRate(row.CompletedCount, row.EndedCount, qualityPriorCompletion)
+	cancellations := smoothedRate(row.CancelledCount, row.OrderCount, qualityPriorCancellations)
+	disputes := smoothedRate(row.DisputedCount, row.OrderCount, qualityPriorDisputes)
+	response := 0.5
+	if row.ResponseMinutes != nil {
+		response = 1 / (1 + math.Max(0, *row.ResponseMinutes)/qualityResponseHalfMinutes)
+	}
+
+	score := qualityWeightRating*rating/5 +
+		qualityWeightCompletion*completion +
+		qualityWeightResponse*response +
+		qualityWeightDisputes*math.Max(0, 1-disputes/qualityMaxDisputeRate) +
+		qualityWeightCancellations*math.Max(0, 1-cancellations/qualityMaxCancellationRate)
+	row.Score = math.Round(score*10000) / 100
+}
+
+// ratio is count out of total rounded to four places, 0 without a total
+func ratio(count, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(count)/float64(total)*10000) / 10000
+}
+
+func smoothedRate(count, total int64, prior float64) float64 {
+	return (float64(count) + prior*qualityPriorWeight) / (float64(total) + qualityPriorWeight)
+}
+
+// Leaderboard returns yandaşlar by quality score, best first unless sorted otherwise
+func (s *AdminService) Leaderboard(filter repository.QualityFilter, page, limit int) ([]repository.LeaderboardEntry, int64, error) {
+	switch filter.Sort {
+	case "", repository.QualitySortScore, repository.QualitySortScoreAsc, repository.QualitySortRating,
+		repository.QualitySortCompletion, repository.QualitySortResponse, repository.QualitySortDisputes,
+		repository.QualitySortCancellations, repository.QualitySortOrders:
+	default:
+		return nil, 0, ErrInvalidQualityFilter
+	}
+	if filter.MinScore != nil && filter.MaxScore != nil && *filter.MinScore > *filter.MaxScore {
+		return nil, 0, ErrInvalidQualityFilter
+	}
+	return s.repos.Quality.Leaderboard(filter, page, limit)
+}
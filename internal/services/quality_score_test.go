@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func scored(row models.YandasQualityScore) models.YandasQualityScore {
+	scoreQuality(&row)
+	return row
+}
+
+func TestScoreQualityRanksReliableYandasFirst(t *testing.T) {
+	fast, slow := 10.0, 240.0
+	reliable := scored(models.YandasQualityScore{RatingAvg: 4.9, ReviewCount: 40, OrderCount: 50, EndedCount: 50, CompletedCount: 49, CancelledCount: 1, ResponseMinutes: &fast})
+	disputed := scored(models.YandasQualityScore{RatingAvg: 4.9, ReviewCount: 40, OrderCount: 50, EndedCount: 50, CompletedCount: 42, CancelledCount: 4, DisputedCount: 6, ResponseMinutes: &fast})
+	sluggish := scored(models.YandasQualityScore{RatingAvg: 4.9, ReviewCount: 40, OrderCount: 50, EndedCount: 50, CompletedCount: 49, CancelledCount: 1, ResponseMinutes: &slow})
+
+	if reliable.Score <= sluggish.Score || sluggish.Score <= disputed.Score {
+		t.Fatalf("expected reliable > sluggish > disputed, got %.2f, %.2f, %.2f", reliable.Score, sluggish.Score, disputed.Score)
+	}
+	if reliable.Score > 100 || disputed.Score < 0 {
+		t.Errorf("expected scores between 0 and 100, got %.2f and %.2f", reliable.Score, disputed.Score)
+	}
+	if disputed.DisputeRate != 0.12 || disputed.CompletionRate != 0.84 || disputed.CancellationRate != 0.08 {
+		t.Errorf("unexpected raw rates %+v", disputed)
+	}
+}
+
+func TestScoreQualitySmoothsNewcomers(t *testing.T) {
+	newcomer := scored(models.YandasQualityScore{})
+	if newcomer.CompletionRate != 0 || newcomer.Score <= 50 {
+		t.Fatalf("expected a newcomer to start from the priors, got %+v", newcomer)
+	}
+
+	// One perfect order lifts a newcomer a little, not past an established yandaş
+	perfect := scored(models.YandasQualityScore{RatingAvg: 5, ReviewCount: 1, OrderCount: 1, EndedCount: 1, CompletedCount: 1})
+	minutes := 15.0
+	established := scored(models.YandasQualityScore{RatingAvg: 4.8, ReviewCount: 60, OrderCount: 80, EndedCount: 80, CompletedCount: 78, CancelledCount: 1, ResponseMinutes: &minutes})
+	if perfect.Score <= newcomer.Score || perfect.Score >= established.Score {
+		t.Fatalf("expected newcomer %.2f < one order %.2f < established %.2f", newcomer.Score, perfect.Score, established.Score)
+	}
+
+	// One cancelled order does not sink them either
+	unlucky := scored(models.YandasQualityScore{OrderCount: 1, EndedCount: 1, CancelledCount: 1})
+	if unlucky.CancellationRate != 1 || unlucky.Score < newcomer.Score-15 {
+		t.Fatalf("expected a single cancellation to cost little, got %.2f from %.2f", unlucky.Score, newcomer.Score)
+	}
+}
+
+func TestRefreshQualityScoresStoresScores(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	quality := mocks.NewMockQualityRepository(ctrl)
+	svc := &YandasService{repos: &repository.Repositories{Quality: quality}}
+
+	quality.EXPECT().Metrics(gomock.Any()).DoAndReturn(func(since time.Time) ([]models.YandasQualityScore, error) {
+		if d := time.Since(since); d < qualityWindow-time.Minute || d > qualityWindow+time.Minute {
+			t.Errorf("expected a %s window, got %s", qualityWindow, d)
+		}
+		return []models.YandasQualityScore{{RatingAvg: 4.5, ReviewCount: 10, OrderCount: 12, EndedCount: 12, CompletedCount: 12}}, nil
+	})
+	quality.EXPECT().Replace(gomock.Any()).DoAndReturn(func(rows []models.YandasQualityScore) error {
+		if len(rows) != 1 || rows[0].Score == 0 || rows[0].CompletionRate != 1 || rows[0].ComputedAt.IsZero() {
+			t.Errorf("unexpected scores %+v", rows)
+		}
+		return nil
+	})
+	svc.RefreshQualityScores()
+}
+
+func TestLeaderboardValidatesFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	quality := mocks.NewMockQualityRepository(ctrl)
+	svc := NewAdminService(&repository.Repositories{Quality: quality}, nil, nil, nil, nil, nil, nil)
+
+	low, high := 40.0, 80.0
+	for name, filter := range map[string]repository.QualityFilter{
+		"sort":  {Sort: "newest"},
+		"range": {MinScore: &high, MaxScore: &low},
+	} {
+		if _, _, err := svc.Leaderboard(filter, 1, 20); !errors.Is(err, ErrInvalidQualityFilter) {
+			t.Errorf("%s: expected ErrInvalidQualityFilter, got %v", name, err)
+		}
+	}
+
+	filter := repository.QualityFilter{City: "Ankara", MinScore: &low, MaxScore: &high, Sort: repository.QualitySortDisputes}
+	quality.EXPECT().Leaderboard(filter, 1, 20).Return([]repository.LeaderboardEntry{{FullName: "Ayşe Y."}}, int64(1), nil)
+	entries, total, err := svc.Leaderboard(filter, 1, 20)
+	if err != nil || total != 1 || len(entries) != 1 {
+		t.Fatalf("unexpected leaderboard %v, %d, %v", entries, total, err)
+	}
+}
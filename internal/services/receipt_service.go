@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/queue"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/currency"
+	"github.com/yandas/backend/pkg/einvoice"
+	"github.com/yandas/backend/pkg/pdf"
+)
+
+// einvoiceTimeout bounds a single call to the e-Arşiv integrator
+const einvoiceTimeout = 30 * time.Second
+
+// ErrReceiptUnavailable is returned for orders that are not completed yet
+var ErrReceiptUnavailable = conflictError("receipt_unavailable", "receipt is only available for completed orders")
+
+// ReceiptService issues receipts for completed orders and renders them as PDF
+type ReceiptService struct {
+	repos    *repository.Repositories
+	cfg      *config.Config
+	provider einvoice.Provider
+	jobs     *queue.Queue
+}
+
+func NewReceiptService(repos *repository.Repositories, cfg *config.Config, provider einvoice.Provider, jobs *queue.Queue) *ReceiptService {
+	return &ReceiptService{repos: repos, cfg: cfg, provider: provider, jobs: jobs}
+}
+
+// Ensure returns the order's receipt, issuing it on first use
+func (s *ReceiptService) Ensure(order *models.Order) (*models.Receipt, error) {
+	if order.Status != "completed" {
+		return nil, ErrReceiptUnavailable
+	}
+
+	if receipt, err := s.repos.Receipt.GetByOrderID(order.ID); err == nil {
+		return receipt, nil
+	}
+
+	subtotal := order.AgreedPrice.Mul(1 / (1 + s.cfg.VATRate))
+	receipt := &models.Receipt{
+		OrderID:       order.ID,
+		ReceiptNumber: "RCP-" + order.OrderNumber,
+		Currency:      order.Currency,
+		Subtotal:      subtotal,
+		VATRate:       s.cfg.VATRate,
+		VATAmount:     order.AgreedPrice - subtotal,
+		Total:         order.AgreedPrice,
+		IssuedAt:      time.Now(),
+	}
+	if err := s.repos.Receipt.Create(receipt); err != nil {
+		return nil, err
+	}
+	if receipt.ID == uuid.Nil {
+		// Another request issued it first
+		return s.repos.Receipt.GetByOrderID(order.ID)
+	}
+
+	s.issueEInvoice(receipt, order)
+
+	return receipt, nil
+}
+
+// IssueAsync queues issuing the receipt once an order completes
+func (s *ReceiptService) IssueAsync(orderID uuid.UUID) {
+	if err := s.jobs.Enqueue(JobIssueReceipt, orderJob{OrderID: orderID}); err != nil {
+		log.Printf("[RECEIPT] failed to queue receipt for order %s: %v", orderID, err)
+	}
+}
+
+// issue is the JobIssueReceipt handler
+func (s *ReceiptService) issue(orderID uuid.UUID) error {
+	order, err := s.repos.Order.GetByID(orderID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.Ensure(order); err != nil {
+		log.Printf("[RECEIPT] failed to issue receipt for order %s: %v", orderID, err)
+		return err
+	}
+	return nil
+}
+
+// issueEInvoice registers the receipt with the e-Arşiv integrator, when one is configured
+func (s *ReceiptService) issueEInvoice(receipt *models.Receipt, order *models.Order) {
+	if s.provider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), einvoiceTimeout)
+	defer cancel()
+
+	name := s.provider.Name()
+	receipt.EInvoiceProvider = &name
+
+	result, err := s.provider.Issue(ctx, s.invoiceFor(receipt, order))
+	if err != nil {
+		msg := err.Error()
+		receipt.EInvoiceStatus = "failed"
+		receipt.EInvoiceError = &msg
+		log.Printf("[EINVOICE] %s failed for %s: %v", name, receipt.ReceiptNumber, err)
+	} else {
+		receipt.EInvoiceStatus = "issued"
+		receipt.EInvoiceUUID = &result.UUID
+		receipt.EInvoiceError = nil
+	}
+
+	if err := s.repos.Receipt.Update(receipt); err != nil {
+		log.Printf("[RECEIPT] failed to record e-invoice result for %s: %v", receipt.ReceiptNumber, err)
+	}
+}
+
+func (s *ReceiptService) invoiceFor(receipt *models.Receipt, order *models.Order) *einvoice.Invoice {
+	invoice := &einvoice.Invoice{
+		Number:      receipt.ReceiptNumber,
+		OrderNumber: order.OrderNumber,
+		IssuedAt:    receipt.IssuedAt,
+		Currency:    receipt.Currency,
+		SellerName:  s.cfg.CompanyName,
+		Subtotal:    receipt.Subtotal,
+		VATRate:     receipt.VATRate,
+		VATAmount:   receipt.VATAmount,
+		Total:       receipt.Total,
+	}
+	if order.Customer != nil {
+		invoice.BuyerName = order.Customer.FullName
+		if order.Customer.Email != nil {
+			invoice.BuyerEmail = *order.Customer.Email
+		}
+	}
+	for _, line := range receiptLines(order) {
+		invoice.Lines = append(invoice.Lines, einvoice.Line{Description: line.Description, Amount: line.Amount})
+	}
+	return invoice
+}
+
+// receiptLines returns the order's priced lines, falling back to one line for orders priced before line items existed
+func receiptLines(order *models.Order) []models.OrderLineItem {
+	if len(order.LineItems) > 0 {
+		return order.LineItems
+	}
+	description := "Hizmet"
+	if order.Service != nil {
+		description = order.Service.Title
+	}
+	return []models.OrderLineItem{{Description: description, Amount: order.AgreedPrice}}
+}
+
+// Render lays out the receipt as a one-page PDF. The yandaş's copy also shows the commission split.
+func (s *ReceiptService) Render(receipt *models.Receipt, order *models.Order, forYandas bool) []byte {
+	doc := pdf.New()
+	page := doc.AddPage()
+
+	const left, right = 50.0, 545.0
+	y := 70.0
+
+	page.Text(left, y, 18, true, "HİZMET MAKBUZU")
+	page.TextRight(right, y, 10, true, s.cfg.CompanyName)
+	if s.cfg.CompanyTaxNumber != "" {
+		page.TextRight(right, y+14, 9, false, "VKN: "+s.cfg.CompanyTaxNumber)
+	}
+	y += 40
+
+	field := func(label, value string) {
+		page.Text(left, y, 10, true, label)
+		page.Text(left+110, y, 10, false, value)
+		y += 16
+	}
+	field("Makbuz No", receipt.ReceiptNumber)
+	field("Sipariş No", order.OrderNumber)
+	field("Düzenleme Tarihi", receipt.IssuedAt.Format("02.01.2006 15:04"))
+	if order.CompletedAt != nil {
+		field("Hizmet Tarihi", order.CompletedAt.Format("02.01.2006 15:04"))
+	}
+	if order.Customer != nil {
+		field("Müşteri", order.Customer.FullName)
+	}
+	if order.Yandas != nil {
+		field("Yandaş", order.Yandas.User.FullName)
+	}
+	if order.LocationAddress != nil {
+		field("Adres", *order.LocationAddress)
+	}
+
+	y += 14
+	page.Text(left, y, 10, true, "Açıklama")
+	page.TextRight(right, y, 10, true, "Tutar")
+	y += 6
+	page.Line(left, y, right, y)
+	y += 16
+
+	for _, line := range receiptLines(order) {
+		page.Text(left, y, 10, false, line.Description)
+		page.TextRight(right, y, 10, false, formatAmount(line.Amount, receipt.Currency))
+		y += 16
+	}
+
+	y -= 6
+	page.Line(left, y, right, y)
+	y += 18
+
+	total := func(label string, amount currency.Money, bold bool) {
+		page.Text(left+250, y, 10, bold, label)
+		page.TextRight(right, y, 10, bold, formatAmount(amount, receipt.Currency))
+		y += 16
+	}
+	total("Ara Toplam (KDV hariç)", receipt.Subtotal, false)
+	total(fmt.Sprintf("KDV (%%%s)", formatPercent(receipt.VATRate)), receipt.VATAmount, false)
+	total("Genel Toplam", receipt.Total, true)
+
+	if forYandas && order.PlatformFee != nil && order.NetEarnings != nil {
+		y += 10
+		rate := 0.0
+		if order.CommissionRate != nil {
+			rate = *order.CommissionRate
+		}
+		total(fmt.Sprintf("Platform Komisyonu (%%%s)", formatPercent(rate)), *order.PlatformFee, false)
+		total("Net Kazanç", *order.NetEarnings, true)
+	}
+
+	y += 30
+	if receipt.EInvoiceStatus == "issued" && receipt.EInvoiceUUID != nil {
+		page.Text(left, y, 9, false, "e-Arşiv Fatura ETTN: "+*receipt.EInvoiceUUID)
+	} else {
+		page.Text(left, y, 9, false, "Bu belge bilgilendirme amaçlıdır, mali değeri yoktur. Fiyatlara KDV dahildir.")
+	}
+
+	return doc.Bytes()
+}
+
+// formatAmount renders 1234.50 as "1.234,50 TRY"
+func formatAmount(amount currency.Money, code string) string {
+	s := strings.TrimPrefix(amount.String(), "-")
+	whole, frac := s[:len(s)-3], s[len(s)-2:]
+
+	var grouped strings.Builder
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(d)
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+	return sign + grouped.String() + "," + frac + " " + code
+}
+
+func formatPercent(rate float64) string {
+	return strconv.FormatFloat(math.Round(rate*10000)/100, 'f', -1, 64)
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/yandas/backend/pkg/currency"
+)
+
+func TestFormatAmount(t *testing.T) {
+	cases := map[currency.Money]string{
+		0:         "0,00 TRY",
+		1250:      "12,50 TRY",
+		123450:    "1.234,50 TRY",
+		123456789: "1.234.567,89 TRY",
+		-95000:    "-950,00 TRY",
+		-5:        "-0,05 TRY",
+	}
+	for amount, want := range cases {
+		if got := formatAmount(amount, "TRY"); got != want {
+			t.Errorf("formatAmount(%v) = %q, want %q", amount, got, want)
+		}
+	}
+}
+
+func TestFormatPercent(t *testing.T) {
+	if got := formatPercent(0.2); got != "20" {
+		t.Errorf("got %q", got)
+	}
+	if got := formatPercent(0.075); got != "7.5" {
+		t.Errorf("got %q", got)
+	}
+}
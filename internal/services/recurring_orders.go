@@ -0,0 +1,287 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yandas/backend/internal/models"
+)
+
+const (
+	// RecurringOrderInterval is how often due occurrences are instantiated
+	RecurringOrderInterval = 5 * time.Minute
+	// recurringLeadTime is how far ahead of its scheduled time an occurrence becomes an order,
+	// giving the yandaş time to accept it
+	recurringLeadTime = 48 * time.Hour
+	recurringBatch    = 100
+)
+
+var ErrRecurringNotFound = notFoundError("recurring_not_found", "recurring order not found")
+
+// RebookInput overrides fields of the order being repeated
+type RebookInput struct {
+	ScheduledAt   *time.Time `json:"scheduled_at"`
+	AddressID     *uuid.UUID `json:"address_id"`
+	CustomerNotes *string    `json:"customer_notes"`
+}
+
+// Rebook creates a new order for the same yandaş, service, options and location as a past order.
+// The price is recalculated from the service's current pricing.
+func (s *OrderService) Rebook(orderID, customerID uuid.UUID, input *RebookInput) (*models.Order, error) {
+	past, err := s.repos.Order.GetByID(orderID)
+	if err != nil || past.CustomerID != customerID {
+		return nil, ErrOrderNotFound
+	}
+
+	create := &CreateOrderInput{
+		YandasID:    past.YandasID,
+		ServiceID:   past.ServiceID,
+		ScheduledAt: input.ScheduledAt,
+		AddressID:   input.AddressID,
+	}
+	for _, item := range past.LineItems {
+		if item.Kind == "option" && item.OptionID != nil {
+			create.OptionIDs = append(create.OptionIDs, *item.OptionID)
+		}
+	}
+	if past.LocationAddress != nil {
+		create.LocationAddress = *past.LocationAddress
+	}
+	if past.Latitude != nil && past.Longitude != nil {
+		create.Latitude = *past.Latitude
+		create.Longitude = *past.Longitude
+	}
+	if input.CustomerNotes != nil {
+		create.CustomerNotes = *input.CustomerNotes
+	} else if past.CustomerNotes != nil {
+		create.CustomerNotes = *past.CustomerNotes
+	}
+
+	return s.Create(customerID, create)
+}
+
+// RecurringOrderInput represents a recurring booking
+type RecurringOrderInput struct {
+	YandasID        uuid.UUID   `json:"yandas_id" binding:"required"`
+	ServiceID       uuid.UUID   `json:"service_id" binding:"required"`
+	OptionIDs       []uuid.UUID `json:"option_ids"`
+	DurationMinutes *int        `json:"duration_minutes"`
+	AddressID       *uuid.UUID  `json:"address_id"`
+	LocationAddress string      `json:"location_address"`
+	Latitude        float64     `json:"latitude"`
+	Longitude       float64     `json:"longitude"`
+	CustomerNotes   string      `json:"customer_notes"`
+	Frequency       string      `json:"frequency" binding:"required,oneof=weekly biweekly monthly"`
+	StartAt         time.Time   `json:"start_at" binding:"required"`
+	EndsAt          *time.Time  `json:"ends_at"`
+	MaxOccurrences  *int        `json:"max_occurrences" binding:"omitempty,min=1"`
+}
+
+// CreateRecurring starts a recurring series; the first order is created shortly before StartAt
+func (s *OrderService) CreateRecurring(customerID uuid.UUID, input *RecurringOrderInput) (*models.RecurringOrder, error) {
+	if !input.StartAt.After(time.Now()) {
+		return nil, validationError("invalid_start_at", "start_at must be in the future")
+	}
+	if input.EndsAt != nil && !input.EndsAt.After(input.StartAt) {
+		return nil, validationError("invalid_ends_at", "ends_at must be after start_at")
+	}
+
+	yandas, err := s.repos.YandasProfile.GetByID(input.YandasID)
+	if err != nil || yandas.ApprovalStatus != "approved" {
+		return nil, ErrYandasUnavailable
+	}
+	service, err := s.repos.Service.GetByID(input.ServiceID)
+	if err != nil || service.YandasID != input.YandasID || !serviceBookable(service) {
+		return nil, ErrServiceNotFound
+	}
+	if input.AddressID != nil {
+		address, err := s.repos.Address.GetByID(*input.AddressID)
+		if err != nil || address.UserID != customerID {
+			return nil, ErrAddressNotFound
+		}
+	}
+
+	recurring := &models.RecurringOrder{
+		CustomerID:      customerID,
+		YandasID:        input.YandasID,
+		ServiceID:       input.ServiceID,
+		DurationMinutes: input.DurationMinutes,
+		AddressID:       input.AddressID,
+		Frequency:       input.Frequency,
+		NextRunAt:       input.StartAt,
+		EndsAt:          input.EndsAt,
+		MaxOccurrences:  input.MaxOccurrences,
+		Status:          "active",
+	}
+	for _, id := range input.OptionIDs {
+		recurring.OptionIDs = append(recurring.OptionIDs, id.String())
+	}
+	if input.LocationAddress != "" {
+		recurring.LocationAddress = &input.LocationAddress
+	}
+	if input.Latitude != 0 && input.Longitude != 0 {
+		recurring.Latitude = &input.Latitude
+		recurring.Longitude = &input.Longitude
+	}
+	if input.CustomerNotes != "" {
+		recurring.CustomerNotes = &input.CustomerNotes
+	}
+
+	if err := s.repos.RecurringOrder.Create(recurring); err != nil {
+		return nil, err
+	}
+
+	return recurring, nil
+}
+
+func (s *OrderService) ListRecurring(customerID uuid.UUID, page, limit int) ([]models.RecurringOrder, int64, error) {
+	return s.repos.RecurringOrder.ListByCustomer(customerID, page, limit)
+}
+
+func (s *OrderService) GetRecurring(id, customerID uuid.UUID) (*models.RecurringOrder, error) {
+	recurring, err := s.repos.RecurringOrder.GetByID(id)
+	if err != nil || recurring.CustomerID != customerID {
+		return nil, ErrRecurringNotFound
+	}
+	return recurring, nil
+}
+
+// PauseRecurring stops creating orders until the series is resumed
+func (s *OrderService) PauseRecurring(id, customerID uuid.UUID) (*models.RecurringOrder, error) {
+	return s.setRecurringStatus(id, customerID, "active", "paused")
+}
+
+// ResumeRecurring restarts a paused series, skipping occurrences that passed while paused
+func (s *OrderService) ResumeRecurring(id, customerID uuid.UUID) (*models.RecurringOrder, error) {
+	return s.setRecurringStatus(id, customerID, "paused", "active")
+}
+
+// CancelRecurring ends the series; orders already created are not affected
+func (s *OrderService) CancelRecurring(id, customerID uuid.UUID) (*models.RecurringOrder, error) {
+	recurring, err := s.GetRecurring(id, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if recurring.Status == "cancelled" || recurring.Status == "completed" {
+		return nil, conflictError("recurring_ended", "recurring order already ended")
+	}
+	recurring.Status = "cancelled"
+	return recurring, s.repos.RecurringOrder.Update(recurring)
+}
+
+func (s *OrderService) setRecurringStatus(id, customerID uuid.UUID, from, to string) (*models.RecurringOrder, error) {
+	recurring, err := s.GetRecurring(id, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if recurring.Status != from {
+		return nil, conflictError("invalid_recurring_status", "recurring order is not "+from)
+	}
+
+	recurring.Status = to
+	if to == "active" {
+		now := time.Now()
+		for !recurring.NextRunAt.After(now) {
+			recurring.NextRunAt = nextOccurrence(recurring.NextRunAt, recurring.Frequency)
+		}
+		if recurringEnded(recurring) {
+			recurring.Status = "completed"
+		}
+	}
+
+	return recurring, s.repos.RecurringOrder.Update(recurring)
+}
+
+// ProcessRecurring turns due occurrences into orders; the scheduler calls it every RecurringOrderInterval
+func (s *OrderService) ProcessRecurring() {
+	due, err := s.repos.RecurringOrder.ListDue(time.Now().Add(recurringLeadTime), recurringBatch)
+	if err != nil {
+		log.Printf("[RECURRING] failed to load due series: %v", err)
+		return
+	}
+
+	for i := range due {
+		s.instantiate(&due[i])
+	}
+}
+
+// instantiate creates the order for a series' next occurrence. The occurrence is claimed first
+// so two API instances never book it twice; a failed booking is skipped and recorded.
+func (s *OrderService) instantiate(recurring *models.RecurringOrder) {
+	scheduledFor := recurring.NextRunAt
+	next := nextOccurrence(scheduledFor, recurring.Frequency)
+
+	claimed, err := s.repos.RecurringOrder.ClaimOccurrence(recurring.ID, scheduledFor, next)
+	if err != nil || !claimed {
+		return
+	}
+	recurring.NextRunAt = next
+	recurring.OccurrenceCount++
+
+	input := &CreateOrderInput{
+		YandasID:        recurring.YandasID,
+		ServiceID:       recurring.ServiceID,
+		DurationMinutes: recurring.DurationMinutes,
+		AddressID:       recurring.AddressID,
+		ScheduledAt:     &scheduledFor,
+	}
+	input.OptionIDs = parseUUIDs(recurring.OptionIDs)
+	if recurring.LocationAddress != nil {
+		input.LocationAddress = *recurring.LocationAddress
+	}
+	if recurring.Latitude != nil && recurring.Longitude != nil {
+		input.Latitude = *recurring.Latitude
+		input.Longitude = *recurring.Longitude
+	}
+	if recurring.CustomerNotes != nil {
+		input.CustomerNotes = *recurring.CustomerNotes
+	}
+
+	order, err := s.Create(recurring.CustomerID, input)
+	if err != nil {
+		msg := err.Error()
+		recurring.LastError = &msg
+		log.Printf("[RECURRING] occurrence %s of %s failed: %v", scheduledFor.Format(time.RFC3339), recurring.ID, err)
+	} else {
+		recurring.LastOrderID = &order.ID
+		recurring.LastError = nil
+	}
+
+	if recurringEnded(recurring) {
+		recurring.Status = "completed"
+	}
+	if err := s.repos.RecurringOrder.Update(recurring); err != nil {
+		log.Printf("[RECURRING] failed to update series %s: %v", recurring.ID, err)
+	}
+}
+
+// recurringEnded reports whether the series has no occurrences left
+func recurringEnded(recurring *models.RecurringOrder) bool {
+	if recurring.MaxOccurrences != nil && recurring.OccurrenceCount >= *recurring.MaxOccurrences {
+		return true
+	}
+	return recurring.EndsAt != nil && recurring.NextRunAt.After(*recurring.EndsAt)
+}
+
+func nextOccurrence(t time.Time, frequency string) time.Time {
+	switch frequency {
+	case "biweekly":
+		return t.AddDate(0, 0, 14)
+	case "monthly":
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 7)
+	}
+}
+
+func parseUUIDs(values pq.StringArray) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, v := range values {
+		if id, err := uuid.Parse(v); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/httpcapture"
+)
+
+const (
+	captureRulesKey    = "debug_capture:rules"
+	captureRequestsKey = "debug_capture:requests"
+)
+
+const (
+	// Captures are kept this long, and at most this many of them
+	captureRetention   = time.Hour
+	maxCapturedEntries = 1000
+	// Rules are reloaded from Redis this often, so new rules and stops reach
+	// every API process within it
+	captureRulesRefresh = 10 * time.Second
+	// A rule captures for 30 minutes unless told otherwise, and never longer than a day
+	defaultCaptureDuration = 30
+	maxCaptureDuration     = 24 * 60
+)
+
+var (
+	ErrCaptureRuleNotFound = notFoundError("capture_rule_not_found", "capture rule not found")
+	ErrCaptureTargetNeeded = validationError("capture_target_needed", "a user ID or a path is required")
+	ErrCaptureUnavailable  = unavailableError("capture_unavailable", "request capture needs Redis")
+)
+
+// CaptureRule records sampled requests of one user, one endpoint, or one
+// user's calls to one endpoint until it expires
+type CaptureRule struct {
+	ID         string     `json:"id"`
+	UserID     *uuid.UUID `json:"user_id,omitempty"`
+	PathPrefix string     `json:"path_prefix,omitempty"`
+	SampleRate float64    `json:"sample_rate"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	CreatedBy  uuid.UUID  `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// matches reports whether the rule covers a request by userID (empty when
+// anonymous) to path
+func (r *CaptureRule) matches(userID, path string, now time.Time) bool {
+	if now.After(r.ExpiresAt) {
+		return false
+	}
+	if r.UserID != nil && r.UserID.String() != userID {
+		return false
+	}
+	return r.PathPrefix == "" || strings.HasPrefix(path, r.PathPrefix)
+}
+
+// CaptureRuleInput starts a capture. SampleRate 0 captures every matching request.
+type CaptureRuleInput struct {
+	UserID          *uuid.UUID `json:"user_id"`
+	PathPrefix      string     `json:"path_prefix" binding:"omitempty,startswith=/,max=200"`
+	SampleRate      float64    `json:"sample_rate" binding:"min=0,max=1"`
+	DurationMinutes int        `json:"duration_minutes" binding:"min=0,max=1440"`
+}
+
+// CaptureFilter narrows the captured requests listed
+type CaptureFilter struct {
+	RuleID string
+	UserID string
+	Path   string
+}
+
+// RequestCaptureService lets admins record redacted request and response
+// bodies of a user or endpoint for a while, to debug issues reported from
+// the apps without a deploy. Rules and captures live only in Redis.
+type RequestCaptureService struct {
+	repos *repository.Repositories
+	redis *redis.Client
+
+	mu        sync.RWMutex
+	rules     []CaptureRule
+	loadedAt  time.Time
+	randFloat func() float64
+}
+
+func NewRequestCaptureService(repos *repository.Repositories, redis *redis.Client) *RequestCaptureService {
+	return &RequestCaptureService{repos: repos, redis: redis, randFloat: rand.Float64}
+}
+
+// activeRules returns the unexpired rules, reloading them when the local copy is stale
+func (s *RequestCaptureService) activeRules() []CaptureRule {
+	if s.redis == nil {
+		return nil
+	}
+	s.mu.RLock()
+	rules, loadedAt := s.rules, s.loadedAt
+	s.mu.RUnlock()
+	if time.Since(loadedAt) < captureRulesRefresh {
+		return rules
+	}
+
+	rules, err := s.loadRules(context.Background())
+	if err != nil {
+		log.Printf("[CAPTURE] failed to load capture rules: %v", err)
+	}
+	s.mu.Lock()
+	s.rules, s.loadedAt = rules, time.Now()
+	s.mu.Unlock()
+	return rules
+}
+
+// loadRules reads the rules from Redis, dropping expired ones
+func (s *RequestCaptureService) loadRules(ctx context.Context) ([]CaptureRule, error) {
+	stored, err := s.redis.HGetAll(ctx, captureRulesKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	rules := make([]CaptureRule, 0, len(stored))
+	for id, data := range stored {
+		var rule CaptureRule
+		if json.Unmarshal([]byte(data), &rule) != nil || now.After(rule.ExpiresAt) {
+			s.redis.HDel(ctx, captureRulesKey, id)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].CreatedAt.Before(rules[j].CreatedAt) })
+	return rules, nil
+}
+
+// Capturing reports whether any rule is active, so request bodies are only
+// buffered while someone is capturing
+func (s *RequestCaptureService) Capturing() bool {
+	return len(s.activeRules()) > 0
+}
+
+// Match returns the first rule covering the request that samples it
+func (s *RequestCaptureService) Match(userID, path string) (string, bool) {
+	now := time.Now()
+	for _, rule := range s.activeRules() {
+		if !rule.matches(userID, path, now) {
+			continue
+		}
+		if rule.SampleRate > 0 && s.randFloat() >= rule.SampleRate {
+			continue
+		}
+		return rule.ID, true
+	}
+	return "", false
+}
+
+// Record stores a captured exchange, pruning captures past their retention
+func (s *RequestCaptureService) Record(exchange *httpcapture.Exchange) {
+	if s.redis == nil {
+		return
+	}
+	exchange.ID = uuid.NewString()
+	data, err := json.Marshal(exchange)
+	if err != nil {
+		return
+	}
+	ctx := context.Background()
+	pipe := s.redis.TxPipeline()
+	pipe.ZAdd(ctx, captureRequestsKey, redis.Z{Score: float64(exchange.CapturedAt.UnixMilli()), Member: data})
+	pipe.ZRemRangeByScore(ctx, captureRequestsKey, "-inf", strconv.FormatInt(time.Now().Add(-captureRetention).UnixMilli(), 10))
+	pipe.ZRemRangeByRank(ctx, captureRequestsKey, 0, -maxCapturedEntries-1)
+	pipe.Expire(ctx, captureRequestsKey, captureRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[CAPTURE] failed to store %s %s: %v", exchange.Method, exchange.Path, err)
+	}
+}
+
+// StartCapture adds a capture rule, audit-logged since captures hold user data
+func (s *RequestCaptureService) StartCapture(input *CaptureRuleInput, adminID uuid.UUID) (*CaptureRule, error) {
+	if s.redis == nil {
+		return nil, ErrCaptureUnavailable
+	}
+	if input.UserID == nil && input.PathPrefix == "" {
+		return nil, ErrCaptureTargetNeeded
+	}
+	if input.UserID != nil {
+		if _, err := s.repos.User.GetByID(*input.UserID); err != nil {
+			return nil, ErrUserNotFound
+		}
+	}
+	duration := input.DurationMinutes
+	if duration == 0 {
+		duration = defaultCaptureDuration
+	}
+	if duration > maxCaptureDuration {
+		duration = maxCaptureDuration
+	}
+
+	now := time.Now()
+	rule := &CaptureRule{
+		ID:         uuid.NewString(),
+		UserID:     input.UserID,
+		PathPrefix: input.PathPrefix,
+		SampleRate: input.SampleRate,
+		ExpiresAt:  now.Add(time.Duration(duration) * time.Minute),
+		CreatedBy:  adminID,
+		CreatedAt:  now,
+	}
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redis.HSet(context.Background(), captureRulesKey, rule.ID, data).Err(); err != nil {
+		return nil, err
+	}
+	s.invalidateRules()
+
+	newValues := map[string]interface{}{
+		"path_prefix": rule.PathPrefix,
+		"sample_rate": rule.SampleRate,
+		"expires_at":  rule.ExpiresAt,
+	}
+	if rule.UserID != nil {
+		newValues["user_id"] = rule.UserID.String()
+	}
+	s.audit(adminID, "start_request_capture", rule.ID, newValues)
+	return rule, nil
+}
+
+// ListCaptureRules returns the active capture rules
+func (s *RequestCaptureService) ListCaptureRules() ([]CaptureRule, error) {
+	if s.redis == nil {
+		return []CaptureRule{}, nil
+	}
+	return s.loadRules(context.Background())
+}
+
+// StopCapture removes a capture rule; what it captured stays until it expires
+func (s *RequestCaptureService) StopCapture(ruleID string, adminID uuid.UUID) error {
+	if s.redis == nil {
+		return ErrCaptureRuleNotFound
+	}
+	removed, err := s.redis.HDel(context.Background(), captureRulesKey, ruleID).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrCaptureRuleNotFound
+	}
+	s.invalidateRules()
+	s.audit(adminID, "stop_request_capture", ruleID, nil)
+	return nil
+}
+
+// ListCaptured returns captured requests, newest first
+func (s *RequestCaptureService) ListCaptured(filter CaptureFilter, page, limit int) ([]httpcapture.Exchange, int64, error) {
+	exchanges := []httpcapture.Exchange{}
+	if s.redis == nil {
+		return exchanges, 0, nil
+	}
+	since := strconv.FormatInt(time.Now().Add(-captureRetention).UnixMilli(), 10)
+	stored, err := s.redis.ZRevRangeByScore(context.Background(), captureRequestsKey, &redis.ZRangeBy{Min: since, Max: "+inf"}).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	offset := (page - 1) * limit
+	for _, data := range stored {
+		var exchange httpcapture.Exchange
+		if json.Unmarshal([]byte(data), &exchange) != nil {
+			continue
+		}
+		if (filter.RuleID != "" && exchange.RuleID != filter.RuleID) ||
+			(filter.UserID != "" && exchange.UserID != filter.UserID) ||
+			(filter.Path != "" && !strings.HasPrefix(exchange.Path, filter.Path)) {
+			continue
+		}
+		if total >= int64(offset) && len(exchanges) < limit {
+			exchanges = append(exchanges, exchange)
+		}
+		total++
+	}
+	return exchanges, total, nil
+}
+
+// invalidateRules makes this process see a rule change right away; other
+// processes pick it up on their next refresh
+func (s *RequestCaptureService) invalidateRules() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *RequestCaptureService) audit(adminID uuid.UUID, action, ruleID string, newValues map[string]interface{}) {
+	entityType := "request_capture"
+	entry := &models.AuditLog{AdminID: adminID, Action: action, EntityType: &entityType}
+	if id, err := uuid.Parse(ruleID); err == nil {
+		entry.EntityID = &id
+	}
+	if newValues != nil {
+		data, _ := json.Marshal(newValues)
+		str := string(data)
+		entry.NewValues = &str
+	}
+	if err := s.repos.AuditLog.Create(entry); err != nil {
+		log.Printf("[CAPTURE] failed to audit %s: %v", action, err)
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/httpcapture"
+)
+
+func TestRequestCapture(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUserRepository(ctrl)
+	auditLogs := mocks.NewMockAuditLogRepository(ctrl)
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	svc := NewRequestCaptureService(&repository.Repositories{User: users, AuditLog: auditLogs}, client)
+
+	if svc.Capturing() {
+		t.Fatal("expected no capture without rules")
+	}
+	if _, err := svc.StartCapture(&CaptureRuleInput{}, uuid.New()); err != ErrCaptureTargetNeeded {
+		t.Errorf("expected ErrCaptureTargetNeeded, got %v", err)
+	}
+
+	userID := uuid.New()
+	users.EXPECT().GetByID(userID).Return(&models.User{ID: userID}, nil)
+	auditLogs.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+	rule, err := svc.StartCapture(&CaptureRuleInput{UserID: &userID, PathPrefix: "/api/v1/orders"}, uuid.New())
+	if err != nil {
+		t.Fatalf("StartCapture: %v", err)
+	}
+	if time.Until(rule.ExpiresAt) > 31*time.Minute {
+		t.Errorf("expected the default 30 minute capture, expires at %v", rule.ExpiresAt)
+	}
+
+	if !svc.Capturing() {
+		t.Fatal("expected capturing once a rule exists")
+	}
+	if _, ok := svc.Match(uuid.NewString(), "/api/v1/orders"); ok {
+		t.Error("expected another user's request not to match")
+	}
+	if _, ok := svc.Match(userID.String(), "/api/v1/users/me"); ok {
+		t.Error("expected another path not to match")
+	}
+	ruleID, ok := svc.Match(userID.String(), "/api/v1/orders/123")
+	if !ok || ruleID != rule.ID {
+		t.Fatalf("expected the rule to match, got %q %v", ruleID, ok)
+	}
+
+	svc.Record(&httpcapture.Exchange{RuleID: ruleID, UserID: userID.String(), Method: "GET", Path: "/api/v1/orders/123", CapturedAt: time.Now()})
+	exchanges, total, err := svc.ListCaptured(CaptureFilter{UserID: userID.String()}, 1, 20)
+	if err != nil || total != 1 || exchanges[0].Path != "/api/v1/orders/123" {
+		t.Errorf("expected the capture to be listed, got %+v %d %v", exchanges, total, err)
+	}
+
+	if err := svc.StopCapture(rule.ID, uuid.New()); err != nil {
+		t.Fatalf("StopCapture: %v", err)
+	}
+	if svc.Capturing() {
+		t.Error("expected capture to stop with its rule")
+	}
+	if err := svc.StopCapture(rule.ID, uuid.New()); err != ErrCaptureRuleNotFound {
+		t.Errorf("expected ErrCaptureRuleNotFound, got %v", err)
+	}
+}
+
+func TestRequestCaptureSampling(t *testing.T) {
+	rule := CaptureRule{ID: "r", PathPrefix: "/api/v1/auth", SampleRate: 0.25, ExpiresAt: time.Now().Add(time.Minute)}
+	svc := &RequestCaptureService{redis: &redis.Client{}, rules: []CaptureRule{rule}, loadedAt: time.Now()}
+
+	svc.randFloat = func() float64 { return 0.5 }
+	if _, ok := svc.Match("", "/api/v1/auth/login"); ok {
+		t.Error("expected a request outside the sample to be skipped")
+	}
+	svc.randFloat = func() float64 { return 0.1 }
+	if _, ok := svc.Match("", "/api/v1/auth/login"); !ok {
+		t.Error("expected a sampled request to match")
+	}
+}
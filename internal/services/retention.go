@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// Retention policies, in the order they run
+const (
+	RetentionOTPKeys      = "otp_keys"
+	RetentionDeletedUsers = "deleted_users"
+	RetentionMessages     = "messages"
+	RetentionAuditLogs    = "audit_logs"
+	RetentionCallLogs     = "call_logs"
+)
+
+const (
+	// retentionBatchSize rows are removed per statement, and at most
+	// retentionMaxBatches statements run per policy; what is left waits for
+	// the next run
+	retentionBatchSize  = 1000
+	retentionMaxBatches = 200
+	otpScanCount        = 500
+)
+
+// otpKeyPatterns match the OTP codes and OTP limit counters kept in Redis
+var otpKeyPatterns = []string{"otp:*", "email_otp:*", "otp_*"}
+
+// RetentionResult is what one policy matched and removed. Removed stays 0 in a dry run.
+type RetentionResult struct {
+	Policy   string     `json:"policy"`
+	Action   string     `json:"action"`           // delete, archive
+	Cutoff   *time.Time `json:"cutoff,omitempty"` // data from before it is past the window
+	Matched  int64      `json:"matched"`
+	Removed  int64      `json:"removed"`
+	Failed   int64      `json:"failed,omitempty"`
+	Disabled bool       `json:"disabled,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// RetentionReport is the outcome of one run of every policy
+type RetentionReport struct {
+	DryRun     bool              `json:"dry_run"`
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt time.Time         `json:"finished_at"`
+	Results    []RetentionResult `json:"results"`
+}
+
+// RetentionService purges and archives data past the retention windows set in
+// the retention.* settings. The scheduler runs it daily; admins can preview
+// a run as a dry-run report.
+type RetentionService struct {
+	repos    *repository.Repositories
+	redis    *redis.Client
+	settings *SettingsService
+}
+
+// NewRetentionService creates a retention service; without Redis the OTP key policy is skipped
+func NewRetentionService(repos *repository.Repositories, redis *redis.Client, settings *SettingsService) *RetentionService {
+	return &RetentionService{repos: repos, redis: redis, settings: settings}
+}
+
+// Run applies every policy, or with dryRun only counts what each would remove
+func (s *RetentionService) Run(dryRun bool) *RetentionReport {
+	now := time.Now()
+	report := &RetentionReport{DryRun: dryRun, StartedAt: now}
+	report.Results = []RetentionResult{
+		s.purgeOTPKeys(dryRun),
+		s.purgeDeletedUsers(now.AddDate(0, 0, -s.settings.Int(SettingRetentionUsers)), dryRun),
+		s.applyWindow(RetentionMessages, "archive", SettingRetentionMessages, now, dryRun, s.repos.Retention.CountMessages, s.repos.Retention.ArchiveMessages),
+		s.applyWindow(RetentionAuditLogs, "delete", SettingRetentionAuditLogs, now, dryRun, s.repos.Retention.CountAuditLogs, s.repos.Retention.DeleteAuditLogs),
+		s.applyWindow(RetentionCallLogs, "delete", SettingRetentionCallLogs, now, dryRun, s.repos.Retention.CountCallLogs, s.repos.Retention.DeleteCallLogs),
+	}
+	report.FinishedAt = time.Now()
+	return report
+}
+
+// RunScheduled runs the policies, as a dry run while retention.dry_run is on, and logs the outcome
+func (s *RetentionService) RunScheduled() {
+	report := s.Run(s.settings.Bool(SettingRetentionDryRun))
+	for _, r := range report.Results {
+		switch {
+		case r.Error != "":
+			log.Printf("[RETENTION] %s failed after removing %d: %s", r.Policy, r.Removed, r.Error)
+		case r.Disabled:
+		case report.DryRun:
+			log.Printf("[RETENTION] dry run: %s would %s %d", r.Policy, r.Action, r.Matched)
+		case r.Removed > 0 || r.Failed > 0:
+			log.Printf("[RETENTION] %s: %s %d of %d, %d failed", r.Policy, r.Action, r.Removed, r.Matched, r.Failed)
+		}
+	}
+}
+
+// RunNow runs the policies on an admin's request and audits it
+func (s *RetentionService) RunNow(adminID uuid.UUID, dryRun bool) *RetentionReport {
+	report := s.Run(dryRun)
+	if dryRun {
+		return report
+	}
+
+	removed := map[string]int64{}
+	for _, r := range report.Results {
+		removed[r.Policy] = r.Removed
+	}
+	entityType := "retention"
+	data, _ := json.Marshal(map[string]interface{}{"removed": removed})
+	newValues := string(data)
+	if err := s.repos.AuditLog.Create(&models.AuditLog{AdminID: adminID, Action: "run_retention", EntityType: &entityType, NewValues: &newValues}); err != nil {
+		log.Printf("[RETENTION] failed to audit run: %v", err)
+	}
+	return report
+}
+
+// purgeOTPKeys deletes OTP codes and counters Redis would never expire, such
+// as a counter whose EXPIRE failed after its INCR. Keys with a TTL are left to Redis.
+func (s *RetentionService) purgeOTPKeys(dryRun bool) RetentionResult {
+	result := RetentionResult{Policy: RetentionOTPKeys, Action: "delete"}
+	if s.redis == nil {
+		result.Disabled = true
+		return result
+	}
+
+	ctx := context.Background()
+	for _, pattern := range otpKeyPatterns {
+		iter := s.redis.Scan(ctx, 0, pattern, otpScanCount).Iterator()
+		for iter.Next(ctx) {
+			key := iter.Val()
+			// -1 means the key exists without an expiry
+			if ttl, err := s.redis.TTL(ctx, key).Result(); err != nil || ttl != -1 {
+				continue
+			}
+			result.Matched++
+			if dryRun {
+				continue
+			}
+			if err := s.redis.Del(ctx, key).Err(); err != nil {
+				result.Failed++
+				continue
+			}
+			result.Removed++
+		}
+		if err := iter.Err(); err != nil {
+			result.Error = err.Error()
+			break
+		}
+	}
+	return result
+}
+
+// purgeDeletedUsers permanently deletes accounts soft-deleted before cutoff.
+// An account still referenced by orders or other records fails and is retried next run.
+func (s *RetentionService) purgeDeletedUsers(cutoff time.Time, dryRun bool) RetentionResult {
+	result := RetentionResult{Policy: RetentionDeletedUsers, Action: "delete", Cutoff: &cutoff}
+	ids, err := s.repos.Retention.SoftDeletedUsers(cutoff)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Matched = int64(len(ids))
+	if dryRun {
+		return result
+	}
+	for _, id := range ids {
+		if err := s.repos.User.HardDelete(id); err != nil {
+			log.Printf("[RETENTION] failed to delete user %s: %v", id, err)
+			result.Failed++
+			continue
+		}
+		result.Removed++
+	}
+	return result
+}
+
+// applyWindow counts the rows older than the window in months set by key and,
+// unless dryRun, removes them in batches. A window of 0 turns the policy off.
+func (s *RetentionService) applyWindow(policy, action, key string, now time.Time, dryRun bool,
+	count func(before time.Time) (int64, error), remove func(before time.Time, limit int) (int64, error)) RetentionResult {
+	result := RetentionResult{Policy: policy, Action: action}
+	months := s.settings.Int(key)
+	if months <= 0 {
+		result.Disabled = true
+		return result
+	}
+	cutoff := now.AddDate(0, -months, 0)
+	result.Cutoff = &cutoff
+
+	matched, err := count(cutoff)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Matched = matched
+	if dryRun || matched == 0 {
+		return result
+	}
+
+	for i := 0; i < retentionMaxBatches; i++ {
+		removed, err := remove(cutoff, retentionBatchSize)
+		result.Removed += removed
+		if err != nil {
+			result.Error = err.Error()
+			break
+		}
+		if removed < retentionBatchSize {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+type retentionMocks struct {
+	retention *mocks.MockRetentionRepository
+	users     *mocks.MockUserRepository
+	audit     *mocks.MockAuditLogRepository
+}
+
+func newTestRetentionService(t *testing.T) (*RetentionService, retentionMocks, *redis.Client) {
+	ctrl := gomock.NewController(t)
+	m := retentionMocks{
+		retention: mocks.NewMockRetentionRepository(ctrl),
+		users:     mocks.NewMockUserRepository(ctrl),
+		audit:     mocks.NewMockAuditLogRepository(ctrl),
+	}
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	repos := &repository.Repositories{Retention: m.retention, User: m.users, AuditLog: m.audit}
+	return NewRetentionService(repos, client, nil), m, client
+}
+
+func resultOf(t *testing.T, report *RetentionReport, policy string) RetentionResult {
+	t.Helper()
+	for _, r := range report.Results {
+		if r.Policy == policy {
+			return r
+		}
+	}
+	t.Fatalf("no result for %s", policy)
+	return RetentionResult{}
+}
+
+func TestRetentionDryRunRemovesNothing(t *testing.T) {
+	svc, m, client := newTestRetentionService(t)
+	ctx := context.Background()
+	client.Set(ctx, "otp_sends:sms:+905551112233", 3, 0)
+	client.Set(ctx, "otp:+905551112233", "123456", time.Minute)
+
+	m.retention.EXPECT().SoftDeletedUsers(gomock.Any()).DoAndReturn(func(before time.Time) ([]uuid.UUID, error) {
+		if d := time.Since(before); d < 30*24*time.Hour-time.Minute || d > 30*24*time.Hour+time.Minute {
+			t.Errorf("expected a 30 day window, got %s", d)
+		}
+		return []uuid.UUID{uuid.New(), uuid.New()}, nil
+	})
+	m.retention.EXPECT().CountMessages(gomock.Any()).Return(int64(5000), nil)
+	m.retention.EXPECT().CountAuditLogs(gomock.Any()).Return(int64(12), nil)
+	m.retention.EXPECT().CountCallLogs(gomock.Any()).Return(int64(0), nil)
+
+	report := svc.RunNow(uuid.New(), true)
+	if !report.DryRun || len(report.Results) != 5 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if r := resultOf(t, report, RetentionOTPKeys); r.Matched != 1 || r.Removed != 0 {
+		t.Errorf("expected one stuck OTP key to be reported, got %+v", r)
+	}
+	if r := resultOf(t, report, RetentionMessages); r.Matched != 5000 || r.Removed != 0 || r.Action != "archive" {
+		t.Errorf("unexpected messages result %+v", r)
+	}
+	if r := resultOf(t, report, RetentionDeletedUsers); r.Matched != 2 || r.Removed != 0 {
+		t.Errorf("unexpected users result %+v", r)
+	}
+	if n, _ := client.Exists(ctx, "otp_sends:sms:+905551112233").Result(); n != 1 {
+		t.Error("expected a dry run to keep the key")
+	}
+}
+
+func TestRetentionRunRemovesInBatches(t *testing.T) {
+	svc, m, client := newTestRetentionService(t)
+	ctx := context.Background()
+	client.Set(ctx, "email_otp:ayse@example.com", "123456", 0)
+	client.Set(ctx, "otp_cooldown:sms:+905551112233", 1, time.Minute)
+
+	kept, purged := uuid.New(), uuid.New()
+	m.retention.EXPECT().SoftDeletedUsers(gomock.Any()).Return([]uuid.UUID{kept, purged}, nil)
+	m.users.EXPECT().HardDelete(kept).Return(errors.New("violates foreign key constraint"))
+	m.users.EXPECT().HardDelete(purged).Return(nil)
+
+	m.retention.EXPECT().CountMessages(gomock.Any()).Return(int64(2500), nil)
+	gomock.InOrder(
+		m.retention.EXPECT().ArchiveMessages(gomock.Any(), retentionBatchSize).Return(int64(retentionBatchSize), nil).Times(2),
+		m.retention.EXPECT().ArchiveMessages(gomock.Any(), retentionBatchSize).Return(int64(500), nil),
+	)
+	m.retention.EXPECT().CountAuditLogs(gomock.Any()).Return(int64(0), nil)
+	m.retention.EXPECT().CountCallLogs(gomock.Any()).Return(int64(3), nil)
+	m.retention.EXPECT().DeleteCallLogs(gomock.Any(), retentionBatchSize).Return(int64(1), errors.New("timeout"))
+	m.audit.EXPECT().Create(gomock.Any()).DoAndReturn(func(entry *models.AuditLog) error {
+		if entry.Action != "run_retention" || entry.NewValues == nil {
+			t.Errorf("unexpected audit entry %+v", entry)
+		}
+		return nil
+	})
+
+	report := svc.RunNow(uuid.New(), false)
+	if r := resultOf(t, report, RetentionOTPKeys); r.Removed != 1 {
+		t.Errorf("expected the key without a TTL to be removed, got %+v", r)
+	}
+	if n, _ := client.Exists(ctx, "email_otp:ayse@example.com", "otp_cooldown:sms:+905551112233").Result(); n != 1 {
+		t.Errorf("expected only the expiring key to remain, %d left", n)
+	}
+	if r := resultOf(t, report, RetentionDeletedUsers); r.Removed != 1 || r.Failed != 1 {
+		t.Errorf("unexpected users result %+v", r)
+	}
+	if r := resultOf(t, report, RetentionMessages); r.Removed != 2500 || r.Error != "" {
+		t.Errorf("unexpected messages result %+v", r)
+	}
+	if r := resultOf(t, report, RetentionCallLogs); r.Removed != 1 || r.Error != "timeout" {
+		t.Errorf("unexpected call logs result %+v", r)
+	}
+}
+
+func TestRetentionWindowsCanBeTurnedOff(t *testing.T) {
+	svc, m, _ := newTestRetentionService(t)
+	settings := mocks.NewMockSettingRepository(gomock.NewController(t))
+	settings.EXPECT().List().Return([]models.Setting{
+		{Key: SettingRetentionMessages, Value: "0"},
+		{Key: SettingRetentionAuditLogs, Value: "0"},
+		{Key: SettingRetentionCallLogs, Value: "0"},
+	}, nil).AnyTimes()
+	svc.settings = NewSettingsService(&repository.Repositories{Setting: settings}, &config.Config{}, nil)
+
+	m.retention.EXPECT().SoftDeletedUsers(gomock.Any()).Return(nil, nil)
+	report := svc.Run(false)
+	for _, policy := range []string{RetentionMessages, RetentionAuditLogs, RetentionCallLogs} {
+		if r := resultOf(t, report, policy); !r.Disabled || r.Cutoff != nil {
+			t.Errorf("expected %s to be off, got %+v", policy, r)
+		}
+	}
+}
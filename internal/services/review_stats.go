@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// Stats are invalidated whenever reviews change; the TTL only bounds how long
+// the month buckets of the trend can lag behind the calendar
+const reviewStatsCacheTTL = 6 * time.Hour
+
+// How many calendar months, the current one included, the rating trend covers
+const reviewTrendMonths = 6
+
+// ReviewStatsService serves the rating breakdown shown next to a yandaş's reviews
+type ReviewStatsService struct {
+	repos *repository.Repositories
+	redis *redis.Client
+}
+
+// NewReviewStatsService creates a review stats service; without Redis every request aggregates in the database
+func NewReviewStatsService(repos *repository.Repositories, redis *redis.Client) *ReviewStatsService {
+	return &ReviewStatsService{repos: repos, redis: redis}
+}
+
+func reviewStatsKey(revieweeID uuid.UUID) string {
+	return "review_stats:" + revieweeID.String()
+}
+
+// ForYandas returns the star distribution, criteria averages and monthly
+// trend of the visible reviews about a yandaş
+func (s *ReviewStatsService) ForYandas(yandasID uuid.UUID) (*repository.ReviewStats, error) {
+	profile, err := s.repos.YandasProfile.GetByID(yandasID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+
+	ctx := context.Background()
+	key := reviewStatsKey(profile.UserID)
+	if s.redis != nil {
+		if cached, err := s.redis.Get(ctx, key).Bytes(); err == nil {
+			var stats repository.ReviewStats
+			if json.Unmarshal(cached, &stats) == nil {
+				return &stats, nil
+			}
+		}
+	}
+
+	now := time.Now()
+	stats, err := s.repos.Review.Stats(profile.UserID, reviewTrendStart(now))
+	if err != nil {
+		return nil, err
+	}
+	stats.Trend = fillReviewTrend(stats.Trend, now)
+
+	if s.redis != nil {
+		if data, err := json.Marshal(stats); err == nil {
+			s.redis.Set(ctx, key, data, reviewStatsCacheTTL)
+		}
+	}
+	return stats, nil
+}
+
+// Invalidate drops the cached stats of the reviewed users after their reviews changed
+func (s *ReviewStatsService) Invalidate(revieweeIDs ...uuid.UUID) {
+	if s == nil || s.redis == nil || len(revieweeIDs) == 0 {
+		return
+	}
+	keys := make([]string, len(revieweeIDs))
+	for i, id := range revieweeIDs {
+		keys[i] = reviewStatsKey(id)
+	}
+	s.redis.Del(context.Background(), keys...)
+}
+
+// reviewTrendStart is the first day of the oldest month in the trend
+func reviewTrendStart(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month()-reviewTrendMonths+1, 1, 0, 0, 0, 0, now.Location())
+}
+
+// fillReviewTrend returns one row per trend month, oldest first, with zero
+// counts for the months without reviews
+func fillReviewTrend(rows []repository.ReviewTrendRow, now time.Time) []repository.ReviewTrendRow {
+	byMonth := make(map[string]repository.ReviewTrendRow, len(rows))
+	for _, row := range rows {
+		byMonth[row.Month] = row
+	}
+
+	trend := make([]repository.ReviewTrendRow, reviewTrendMonths)
+	start := reviewTrendStart(now)
+	for i := range trend {
+		month := start.AddDate(0, i, 0)
+		key := fmt.Sprintf("%04d-%02d", month.Year(), month.Month())
+		trend[i] = repository.ReviewTrendRow{Month: key}
+		if row, ok := byMonth[key]; ok {
+			trend[i] = row
+		}
+	}
+	return trend
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestReviewStatsCachedUntilInvalidated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	profiles := mocks.NewMockYandasProfileRepository(ctrl)
+	reviews := mocks.NewMockReviewRepository(ctrl)
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	svc := NewReviewStatsService(&repository.Repositories{YandasProfile: profiles, Review: reviews}, client)
+
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: uuid.New()}
+	profiles.EXPECT().GetByID(profile.ID).Return(profile, nil).AnyTimes()
+
+	quality := 4.5
+	reviews.EXPECT().Stats(profile.UserID, gomock.Any()).Return(&repository.ReviewStats{
+		Total:        3,
+		Average:      4,
+		Distribution: map[int]int64{1: 0, 2: 0, 3: 1, 4: 1, 5: 1},
+		Quality:      &quality,
+	}, nil).Times(2)
+
+	for i := 0; i < 2; i++ {
+		stats, err := svc.ForYandas(profile.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Total != 3 || stats.Distribution[5] != 1 || *stats.Quality != 4.5 || stats.Punctuality != nil {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+		if len(stats.Trend) != reviewTrendMonths {
+			t.Errorf("expected %d trend months, got %d", reviewTrendMonths, len(stats.Trend))
+		}
+	}
+
+	// A new review drops the cached stats
+	svc.Invalidate(profile.UserID)
+	if _, err := svc.ForYandas(profile.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFillReviewTrend(t *testing.T) {
+	now := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+	trend := fillReviewTrend([]repository.ReviewTrendRow{
+		{Month: "2025-10", Count: 2, Average: 3.5},
+		{Month: "2026-02", Count: 1, Average: 5},
+	}, now)
+
+	months := []string{"2025-09", "2025-10", "2025-11", "2025-12", "2026-01", "2026-02"}
+	if len(trend) != len(months) {
+		t.Fatalf("expected %d months, got %+v", len(months), trend)
+	}
+	for i, month := range months {
+		if trend[i].Month != month {
+			t.Errorf("month %d: expected %s, got %s", i, month, trend[i].Month)
+		}
+	}
+	if trend[1].Count != 2 || trend[1].Average != 3.5 || trend[2].Count != 0 || trend[5].Count != 1 {
+		t.Errorf("unexpected trend %+v", trend)
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// Granted room joins are cached; participants of a conversation or order never change
+const roomAccessCacheTTL = 10 * time.Minute
+
+// RoomAccessService decides which WebSocket rooms a user may join
+type RoomAccessService struct {
+	repos *repository.Repositories
+	redis *redis.Client
+}
+
+func NewRoomAccessService(repos *repository.Repositories, redis *redis.Client) *RoomAccessService {
+	return &RoomAccessService{repos: repos, redis: redis}
+}
+
+// CanJoinRoom allows a user into their own user room, into conversation and
+// order rooms they take part in, and into ActivityRoom when they may view the
+// admin dashboard. Unknown room kinds are refused.
+func (s *RoomAccessService) CanJoinRoom(userID, room string) bool {
+	kind, id, ok := strings.Cut(room, ":")
+	if !ok {
+		return false
+	}
+	if kind == "user" {
+		return id == userID
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return false
+	}
+	if room == ActivityRoom {
+		return s.canViewActivity(uid)
+	}
+	targetID, err := uuid.Parse(id)
+	if err != nil {
+		return false
+	}
+
+	ctx := context.Background()
+	cacheKey := "ws_room_access:" + userID + ":" + room
+	if s.redis != nil {
+		if n, err := s.redis.Exists(ctx, cacheKey).Result(); err == nil && n > 0 {
+			return true
+		}
+	}
+
+	var allowed bool
+	switch kind {
+	case "conv":
+		allowed = s.isConversationParticipant(uid, targetID)
+	case "order":
+		allowed = s.isOrderParticipant(uid, targetID)
+	}
+
+	// Only grants are cached so a room created right after a refused join can still be joined
+	if allowed && s.redis != nil {
+		s.redis.Set(ctx, cacheKey, 1, roomAccessCacheTTL)
+	}
+	return allowed
+}
+
+func (s *RoomAccessService) isConversationParticipant(userID, convID uuid.UUID) bool {
+	conv, err := s.repos.Conversation.GetByID(convID)
+	if err != nil {
+		return false
+	}
+	return conv.CustomerID == userID || conv.YandasID == userID
+}
+
+func (s *RoomAccessService) isOrderParticipant(userID, orderID uuid.UUID) bool {
+	order, err := s.repos.Order.GetByID(orderID)
+	if err != nil {
+		return false
+	}
+	if order.CustomerID == userID {
+		return true
+	}
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	return err == nil && profile != nil && order.YandasID == profile.ID
+}
+
+// canViewActivity is checked on every join rather than cached, so revoking the
+// permission takes effect on the next connection
+func (s *RoomAccessService) canViewActivity(userID uuid.UUID) bool {
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return false
+	}
+	return user.Role == "admin" || s.repos.Role.UserHasPermission(userID, PermissionDashboardView)
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestRoomAccessCanJoinRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	convs := mocks.NewMockConversationRepository(ctrl)
+	orders := mocks.NewMockOrderRepository(ctrl)
+	profiles := mocks.NewMockYandasProfileRepository(ctrl)
+	users := mocks.NewMockUserRepository(ctrl)
+	roles := mocks.NewMockRoleRepository(ctrl)
+	svc := NewRoomAccessService(&repository.Repositories{Conversation: convs, Order: orders, YandasProfile: profiles, User: users, Role: roles}, nil)
+
+	customer, yandasUser, stranger := uuid.New(), uuid.New(), uuid.New()
+	profileID := uuid.New()
+	conv := &models.Conversation{ID: uuid.New(), CustomerID: customer, YandasID: yandasUser}
+	order := &models.Order{ID: uuid.New(), CustomerID: customer, YandasID: profileID}
+
+	convs.EXPECT().GetByID(conv.ID).Return(conv, nil).Times(2)
+	orders.EXPECT().GetByID(order.ID).Return(order, nil).Times(2)
+	profiles.EXPECT().GetByUserID(yandasUser).Return(&models.YandasProfile{ID: profileID}, nil)
+	profiles.EXPECT().GetByUserID(stranger).Return(nil, errors.New("record not found"))
+
+	admin, staff := uuid.New(), uuid.New()
+	users.EXPECT().GetByID(admin).Return(&models.User{ID: admin, Role: "admin"}, nil)
+	users.EXPECT().GetByID(staff).Return(&models.User{ID: staff, Role: "customer"}, nil)
+	users.EXPECT().GetByID(stranger).Return(&models.User{ID: stranger, Role: "customer"}, nil)
+	roles.EXPECT().UserHasPermission(staff, PermissionDashboardView).Return(true)
+	roles.EXPECT().UserHasPermission(stranger, PermissionDashboardView).Return(false)
+
+	tests := []struct {
+		name   string
+		userID uuid.UUID
+		room   string
+		want   bool
+	}{
+		{"own user room", customer, "user:" + customer.String(), true},
+		{"someone else's user room", stranger, "user:" + customer.String(), false},
+		{"conversation participant", yandasUser, "conv:" + conv.ID.String(), true},
+		{"conversation outsider", stranger, "conv:" + conv.ID.String(), false},
+		{"order yandas", yandasUser, "order:" + order.ID.String(), true},
+		{"order outsider", stranger, "order:" + order.ID.String(), false},
+		{"malformed id", customer, "conv:not-a-uuid", false},
+		{"unknown room kind", customer, "admin:" + customer.String(), false},
+		{"activity room admin", admin, ActivityRoom, true},
+		{"activity room dashboard viewer", staff, ActivityRoom, true},
+		{"activity room without permission", stranger, ActivityRoom, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := svc.CanJoinRoom(tt.userID.String(), tt.room); got != tt.want {
+				t.Errorf("CanJoinRoom(%s) = %v, want %v", tt.room, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/queue"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/ocr"
+)
+
+// ocrTimeout bounds a single OCR run
+const ocrTimeout = 30 * time.Second
+
+// Screening statuses
+const (
+	ScreeningPassed     = "passed"
+	ScreeningMismatch   = "mismatch"
+	ScreeningUnreadable = "unreadable"
+	ScreeningSkipped    = "skipped"
+	ScreeningFailed     = "failed"
+)
+
+// ScreeningService pre-screens application documents with OCR to speed up admin review
+type ScreeningService struct {
+	repos       *repository.Repositories
+	provider    ocr.Provider
+	storagePath string
+	jobs        *queue.Queue
+}
+
+func NewScreeningService(repos *repository.Repositories, provider ocr.Provider, storagePath string, jobs *queue.Queue) *ScreeningService {
+	return &ScreeningService{repos: repos, provider: provider, storagePath: storagePath, jobs: jobs}
+}
+
+// Enabled reports whether an OCR provider is configured
+func (s *ScreeningService) Enabled() bool {
+	return s.provider != nil
+}
+
+// ScreenApplication reads the kimlik front image, compares the name with the account and stores the result.
+// It never blocks the application itself; failures are recorded for the admin to see.
+func (s *ScreeningService) ScreenApplication(profileID uuid.UUID) (*models.DocumentScreening, error) {
+	if !s.Enabled() {
+		return nil, unavailableError("ocr_not_configured", "ocr is not configured")
+	}
+
+	profile, err := s.repos.YandasProfile.GetByID(profileID)
+	if err != nil {
+		return nil, notFoundError("application_not_found", "application not found")
+	}
+
+	screening := &models.DocumentScreening{
+		YandasProfileID: profile.ID,
+		Provider:        s.provider.Name(),
+	}
+
+	path, ok := uploadPath(s.storagePath, profile.KimlikOnURL)
+	if !ok {
+		screening.Status = ScreeningSkipped
+		return screening, s.repos.DocumentScreening.Upsert(screening)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ocrTimeout)
+	defer cancel()
+
+	text, err := s.provider.ExtractText(ctx, path)
+	if err != nil {
+		msg := err.Error()
+		screening.Status = ScreeningFailed
+		if errors.Is(err, ocr.ErrUnsupportedFile) {
+			screening.Status = ScreeningSkipped
+		}
+		screening.Error = &msg
+		return screening, s.repos.DocumentScreening.Upsert(screening)
+	}
+
+	fields := ocr.ParseKimlik(text)
+	if name := fields.FullName(); name != "" {
+		screening.ExtractedName = &name
+	}
+	if fields.IDNumber != "" {
+		masked := ocr.MaskIDNumber(fields.IDNumber)
+		screening.IDNumberMasked = &masked
+		screening.IDNumberValid = true
+	}
+	screening.NameMatch = ocr.NamesMatch(profile.User.FullName, fields)
+
+	switch {
+	case screening.ExtractedName == nil && !screening.IDNumberValid:
+		screening.Status = ScreeningUnreadable
+	case screening.NameMatch && screening.IDNumberValid:
+		screening.Status = ScreeningPassed
+	default:
+		screening.Status = ScreeningMismatch
+	}
+
+	return screening, s.repos.DocumentScreening.Upsert(screening)
+}
+
+// ScreenApplicationAsync queues ScreenApplication as a background job
+func (s *ScreeningService) ScreenApplicationAsync(profileID uuid.UUID) {
+	if !s.Enabled() {
+		return
+	}
+	if err := s.jobs.Enqueue(JobScreenApplication, profileJob{ProfileID: profileID}); err != nil {
+		log.Printf("[OCR] queueing screening %s failed: %v", profileID, err)
+	}
+}
+
+// uploadPath maps an /uploads/... URL to a file under the storage path
+func uploadPath(storagePath string, url *string) (string, bool) {
+	if url == nil || !strings.HasPrefix(*url, "/uploads/") {
+		return "", false
+	}
+	rel := filepath.Clean(strings.TrimPrefix(*url, "/uploads/"))
+	if strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	return filepath.Join(storagePath, rel), true
+}
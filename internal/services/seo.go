@@ -0,0 +1,190 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// Paths of public pages on the web frontend
+const (
+	yandasPagePath   = "/yandas/"
+	categoryPagePath = "/kategori/"
+)
+
+const (
+	maxSlugLength       = 80
+	ogDescriptionLength = 160
+)
+
+// SEOService serves the data the web frontend needs for search engines and
+// link previews
+type SEOService struct {
+	repos *repository.Repositories
+	cfg   *config.Config
+}
+
+func NewSEOService(repos *repository.Repositories, cfg *config.Config) *SEOService {
+	return &SEOService{repos: repos, cfg: cfg}
+}
+
+// SitemapPage is a sitemap entry with its absolute URL
+type SitemapPage struct {
+	repository.SitemapEntry
+	URL string `json:"url"`
+}
+
+// Sitemap returns a page of public profiles and categories
+func (s *SEOService) Sitemap(page, limit int) ([]SitemapPage, int64, error) {
+	entries, total, err := s.repos.Sitemap.List(page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	pages := make([]SitemapPage, len(entries))
+	for i, entry := range entries {
+		path := categoryPagePath
+		if entry.Type == repository.SitemapYandas {
+			path = yandasPagePath
+		}
+		pages[i] = SitemapPage{SitemapEntry: entry, URL: s.pageURL(path + entry.Slug)}
+	}
+	return pages, total, nil
+}
+
+// OpenGraph is the link preview of a public page
+type OpenGraph struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	URL         string  `json:"url"`
+	Image       *string `json:"image,omitempty"`
+	Type        string  `json:"type"`
+	SiteName    string  `json:"site_name"`
+}
+
+// ProfileOpenGraph returns the link preview of a yandaş profile, looked up by ID or slug
+func (s *SEOService) ProfileOpenGraph(ref string) (*OpenGraph, error) {
+	profile, err := publicProfile(s.repos, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	name := PublicName(profile.User.FullName)
+	description := ""
+	if profile.Bio != nil {
+		description = strings.Join(strings.Fields(*profile.Bio), " ")
+	}
+	if description == "" {
+		description = name + " Yandaş'ta hizmet veriyor"
+		if len(profile.ServiceCities) > 0 {
+			description += ": " + strings.Join(profile.ServiceCities, ", ")
+		}
+		if profile.TotalJobs > 0 {
+			description += fmt.Sprintf(" · %.1f puan, %d iş", profile.RatingAvg, profile.TotalJobs)
+		}
+	}
+
+	path := yandasPagePath + profile.ID.String()
+	if profile.Slug != nil {
+		path = yandasPagePath + *profile.Slug
+	}
+
+	return &OpenGraph{
+		Title:       name + " | Yandaş",
+		Description: truncateText(description, ogDescriptionLength),
+		URL:         s.pageURL(path),
+		Image:       profile.User.AvatarURL,
+		Type:        "profile",
+		SiteName:    "Yandaş",
+	}, nil
+}
+
+func (s *SEOService) pageURL(path string) string {
+	return strings.TrimRight(s.cfg.WebURL, "/") + path
+}
+
+// publicProfile finds an approved yandaş profile by ID or slug
+func publicProfile(repos *repository.Repositories, ref string) (*models.YandasProfile, error) {
+	var profile *models.YandasProfile
+	var err error
+	if id, parseErr := uuid.Parse(ref); parseErr == nil {
+		profile, err = repos.YandasProfile.GetPublic(id)
+	} else {
+		profile, err = repos.YandasProfile.GetPublicBySlug(ref)
+	}
+	if err != nil || profile.ApprovalStatus != "approved" {
+		return nil, ErrYandasProfileNotFound
+	}
+	return profile, nil
+}
+
+// truncateText shortens text to at most max characters at a word boundary,
+// marking the cut with an ellipsis
+func truncateText(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	cut := string([]rune(text)[:max-1])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+var slugLetters = strings.NewReplacer(
+	"ç", "c", "Ç", "c", "ğ", "g", "Ğ", "g", "ı", "i", "I", "i", "İ", "i",
+	"ö", "o", "Ö", "o", "ş", "s", "Ş", "s", "ü", "u", "Ü", "u",
+	"â", "a", "Â", "a", "î", "i", "Î", "i", "û", "u", "Û", "u",
+)
+
+// slugify turns a name into a URL slug: Turkish letters transliterated,
+// lowercase a-z and 0-9 joined by single dashes. Migration 000032 backfilled
+// existing profiles with the same rules.
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(slugLetters.Replace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+		if b.Len() >= maxSlugLength {
+			break
+		}
+	}
+	slug := strings.Trim(b.String(), "-")
+	if slug == "" {
+		return "yandas"
+	}
+	return slug
+}
+
+// uniqueProfileSlug derives a slug from name that no profile uses yet,
+// numbering repeats: ayse-yilmaz, ayse-yilmaz-2, ...
+func uniqueProfileSlug(profiles repository.YandasProfileRepository, name string) (string, error) {
+	base := slugify(name)
+	for n := 1; n <= 20; n++ {
+		slug := base
+		if n > 1 {
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+		taken, err := profiles.SlugExists(slug)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+	}
+	return base + "-" + uuid.NewString()[:8], nil
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Ayşe Yılmaz":           "ayse-yilmaz",
+		"İSMAİL ÇAĞLAR":         "ismail-caglar",
+		"  Öztürk & Şahin Ltd.": "ozturk-sahin-ltd",
+		"Hâlâ Güzel 2":          "hala-guzel-2",
+		"---":                   "yandas",
+	}
+	for name, want := range cases {
+		if got := slugify(name); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestUniqueProfileSlug(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	profiles := mocks.NewMockYandasProfileRepository(ctrl)
+	profiles.EXPECT().SlugExists("ayse-yilmaz").Return(true, nil)
+	profiles.EXPECT().SlugExists("ayse-yilmaz-2").Return(false, nil)
+
+	if slug, err := uniqueProfileSlug(profiles, "Ayşe Yılmaz"); err != nil || slug != "ayse-yilmaz-2" {
+		t.Errorf("expected ayse-yilmaz-2, got %q, %v", slug, err)
+	}
+}
+
+func TestProfileOpenGraph(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	profiles := mocks.NewMockYandasProfileRepository(ctrl)
+	svc := NewSEOService(&repository.Repositories{YandasProfile: profiles}, &config.Config{WebURL: "https://yandas.app/"})
+
+	slug := "ayse-yilmaz"
+	profile := &models.YandasProfile{
+		Slug:           &slug,
+		ApprovalStatus: "approved",
+		ServiceCities:  []string{"İzmir", "Manisa"},
+		RatingAvg:      4.8,
+		TotalJobs:      12,
+		User:           models.User{FullName: "Ayşe Yılmaz"},
+	}
+	profiles.EXPECT().GetPublicBySlug(slug).Return(profile, nil)
+
+	og, err := svc.ProfileOpenGraph(slug)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if og.URL != "https://yandas.app/yandas/ayse-yilmaz" || og.Title != "Ayşe Y. | Yandaş" {
+		t.Errorf("unexpected preview %+v", og)
+	}
+	if og.Description != "Ayşe Y. Yandaş'ta hizmet veriyor: İzmir, Manisa · 4.8 puan, 12 iş" {
+		t.Errorf("unexpected description %q", og.Description)
+	}
+
+	profiles.EXPECT().GetPublicBySlug("bekleyen").Return(&models.YandasProfile{ApprovalStatus: "pending"}, nil)
+	if _, err := svc.ProfileOpenGraph("bekleyen"); !errors.Is(err, ErrYandasProfileNotFound) {
+		t.Errorf("expected an unapproved profile to be hidden, got %v", err)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	if got := truncateText("Ev temizliği ve ütü", 14); got != "Ev temizliği…" {
+		t.Errorf("got %q", got)
+	}
+	if got := truncateText("Kısa", 14); got != "Kısa" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"errors"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+var (
+	ErrOutsideServiceArea = validationError("outside_service_area", "location is outside the yandaş's service area")
+	ErrInvalidServiceArea = validationError("invalid_service_area", "a base location is required for a service radius or travel fee")
+)
+
+// ServiceAreaInput replaces a yandaş's service area; omitted fields are cleared
+type ServiceAreaInput struct {
+	BaseLatitude   *float64        `json:"base_latitude" binding:"omitempty,min=-90,max=90"`
+	BaseLongitude  *float64        `json:"base_longitude" binding:"omitempty,min=-180,max=180"`
+	RadiusKm       *float64        `json:"radius_km" binding:"omitempty,gt=0,max=1000"`
+	TravelFeePerKm *currency.Money `json:"travel_fee_per_km" binding:"omitempty,min=0,max=100000"` // at most 1.000 TRY, in kuruş
+}
+
+// UpdateServiceArea sets the base location, radius and per-km travel fee
+func (s *YandasService) UpdateServiceArea(userID uuid.UUID, input *ServiceAreaInput) (*models.YandasProfile, error) {
+	if (input.BaseLatitude == nil) != (input.BaseLongitude == nil) {
+		return nil, ErrInvalidServiceArea
+	}
+	if input.BaseLatitude == nil && (input.RadiusKm != nil || input.TravelFeePerKm != nil) {
+		return nil, ErrInvalidServiceArea
+	}
+
+	profile, err := s.repos.OnPrimary().YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+
+	profile.BaseLatitude = input.BaseLatitude
+	profile.BaseLongitude = input.BaseLongitude
+	profile.ServiceRadiusKm = input.RadiusKm
+	profile.TravelFeePerKm = input.TravelFeePerKm
+	if err := s.repos.YandasProfile.Update(profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// travelQuote returns the distance from the yandaş's base to a location and
+// the travel fee for it; both are nil when the yandaş has no base location.
+// A location beyond the radius fails with ErrOutsideServiceArea.
+func travelQuote(profile *models.YandasProfile, lat, lng *float64) (distanceKm *float64, fee *currency.Money, err error) {
+	if profile.BaseLatitude == nil || profile.BaseLongitude == nil {
+		return nil, nil, nil
+	}
+	if lat == nil || lng == nil {
+		if profile.ServiceRadiusKm != nil {
+			return nil, nil, ErrLocationRequired
+		}
+		return nil, nil, nil
+	}
+
+	km := math.Round(haversineKm(*profile.BaseLatitude, *profile.BaseLongitude, *lat, *lng)*10) / 10
+	distanceKm = &km
+	if perKm := profile.TravelFeePerKm; perKm != nil && *perKm > 0 {
+		amount := perKm.Mul(km)
+		fee = &amount
+	}
+	if profile.ServiceRadiusKm != nil && km > *profile.ServiceRadiusKm {
+		return distanceKm, fee, ErrOutsideServiceArea
+	}
+	return distanceKm, fee, nil
+}
+
+// EstimateTravel fills in the distance and travel fee of the yandaş's
+// services for a customer location, flagging them when it is out of area
+func (s *YandasService) EstimateTravel(yandasID uuid.UUID, services []models.YandasService, lat, lng float64) error {
+	profile, err := s.repos.YandasProfile.GetByID(yandasID)
+	if err != nil {
+		return err
+	}
+
+	distanceKm, fee, err := travelQuote(profile, &lat, &lng)
+	if err != nil && !errors.Is(err, ErrOutsideServiceArea) {
+		return err
+	}
+	for i := range services {
+		services[i].DistanceKm = distanceKm
+		services[i].TravelFee = fee
+		services[i].OutsideArea = err != nil
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+func TestTravelQuote(t *testing.T) {
+	baseLat, baseLng := 41.0370, 28.9850 // Taksim
+	radius, perKm := 10.0, currency.Money(500)
+	profile := &models.YandasProfile{
+		BaseLatitude:    &baseLat,
+		BaseLongitude:   &baseLng,
+		ServiceRadiusKm: &radius,
+		TravelFeePerKm:  &perKm,
+	}
+
+	if km, fee, err := travelQuote(&models.YandasProfile{}, &baseLat, &baseLng); km != nil || fee != nil || err != nil {
+		t.Errorf("expected no quote without a base location, got %v %v %v", km, fee, err)
+	}
+	if _, _, err := travelQuote(profile, nil, nil); !errors.Is(err, ErrLocationRequired) {
+		t.Errorf("expected ErrLocationRequired, got %v", err)
+	}
+
+	// Kadıköy is about 6.4 km away
+	lat, lng := 40.9900, 29.0290
+	km, fee, err := travelQuote(profile, &lat, &lng)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if km == nil || fee == nil || *fee != perKm.Mul(*km) {
+		t.Errorf("unexpected quote %v km, %v fee", km, fee)
+	}
+
+	// Bursa is well outside a 10 km radius
+	lat, lng = 40.1885, 29.0610
+	if _, _, err := travelQuote(profile, &lat, &lng); !errors.Is(err, ErrOutsideServiceArea) {
+		t.Errorf("expected ErrOutsideServiceArea, got %v", err)
+	}
+}
+
+func TestPriceOrderTravelFee(t *testing.T) {
+	km, fee := 6.4, currency.Money(3200)
+	items, total, err := priceOrder(&models.YandasService{Title: "Kurye"}, PricingInput{
+		BasePrice: 20000,
+		TravelKm:  &km,
+		TravelFee: &fee,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 23200 {
+		t.Errorf("total = %v, want 232.00", total)
+	}
+	if len(items) != 2 || items[1].Kind != "travel" {
+		t.Errorf("expected a travel line item, got %+v", items)
+	}
+}
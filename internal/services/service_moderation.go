@@ -0,0 +1,156 @@
+package services
+
+import (
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+)
+
+// Service publication statuses; only active services are listed and can be booked
+const (
+	ServiceStatusDraft    = "draft"
+	ServiceStatusPending  = "pending"
+	ServiceStatusActive   = "active"
+	ServiceStatusRejected = "rejected"
+)
+
+// Service moderation errors
+var (
+	ErrServicePublished     = conflictError("service_published", "service is already published")
+	ErrServiceNotPending    = conflictError("service_not_pending", "service is not awaiting approval")
+	ErrInvalidServiceStatus = validationError("invalid_service_status", "invalid service status")
+)
+
+// publishedStatus is where a published service goes: straight to the
+// listing, or to the moderation queue when admin approval is required
+func (s *YandasService) publishedStatus() string {
+	if s.cfg.ServiceApprovalRequired {
+		return ServiceStatusPending
+	}
+	return ServiceStatusActive
+}
+
+// takesServiceSlot reports whether a service in status counts against the
+// plan's active service limit
+func takesServiceSlot(status string) bool {
+	return status == ServiceStatusActive || status == ServiceStatusPending
+}
+
+// serviceBookable reports whether customers can order or be offered the service
+func serviceBookable(service *models.YandasService) bool {
+	return service.IsActive && service.Status == ServiceStatusActive
+}
+
+// PublishService publishes a draft, or resubmits a rejected service after
+// it was edited
+func (s *YandasService) PublishService(userID, serviceID uuid.UUID) (*models.YandasService, error) {
+	service, err := s.ownedService(userID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if service.Status != ServiceStatusDraft && service.Status != ServiceStatusRejected {
+		return nil, ErrServicePublished
+	}
+	if service.IsActive {
+		if err := s.subscriptions.RequireEntitlement(userID, EntitlementServiceSlot); err != nil {
+			return nil, err
+		}
+	}
+
+	service.Status = s.publishedStatus()
+	service.RejectionReason = nil
+	if err := s.repos.Service.Update(service); err != nil {
+		return nil, err
+	}
+	if serviceBookable(service) {
+		s.favorites.ServiceAdded(service)
+	}
+	return service, nil
+}
+
+// ListServices returns services in a publication status, oldest first;
+// pending ones make up the moderation queue
+func (s *AdminService) ListServices(status string, page, limit int) ([]models.YandasService, int64, error) {
+	switch status {
+	case ServiceStatusDraft, ServiceStatusPending, ServiceStatusActive, ServiceStatusRejected:
+	default:
+		return nil, 0, ErrInvalidServiceStatus
+	}
+	return s.repos.Service.ListByStatus(status, page, limit)
+}
+
+// ApproveService lists a service that was waiting for approval
+func (s *AdminService) ApproveService(serviceID, adminID uuid.UUID) (*models.YandasService, error) {
+	service, err := s.repos.OnPrimary().Service.GetByID(serviceID)
+	if err != nil || service.DeletedAt != nil {
+		return nil, ErrServiceNotFound
+	}
+	if service.Status != ServiceStatusPending {
+		return nil, ErrServiceNotPending
+	}
+
+	service.Status = ServiceStatusActive
+	if err := s.repos.Service.Update(service); err != nil {
+		return nil, err
+	}
+
+	s.logAction(adminID, "approve_service", "yandas_service", serviceID, map[string]interface{}{
+		"status": ServiceStatusPending,
+	}, map[string]interface{}{
+		"status": ServiceStatusActive,
+	})
+	if serviceBookable(service) {
+		s.favorites.ServiceAdded(service)
+	}
+	s.notifyServiceModeration(service, "Hizmetiniz yayında", service.Title)
+
+	return service, nil
+}
+
+// RejectService sends a service back to the yandaş with the reason; it can
+// be edited and published again
+func (s *AdminService) RejectService(serviceID, adminID uuid.UUID, reason string) (*models.YandasService, error) {
+	service, err := s.repos.OnPrimary().Service.GetByID(serviceID)
+	if err != nil || service.DeletedAt != nil {
+		return nil, ErrServiceNotFound
+	}
+	if service.Status != ServiceStatusPending {
+		return nil, ErrServiceNotPending
+	}
+
+	service.Status = ServiceStatusRejected
+	service.RejectionReason = &reason
+	if err := s.repos.Service.Update(service); err != nil {
+		return nil, err
+	}
+
+	s.logAction(adminID, "reject_service", "yandas_service", serviceID, map[string]interface{}{
+		"status": ServiceStatusPending,
+	}, map[string]interface{}{
+		"status": ServiceStatusRejected,
+		"reason": reason,
+	})
+	s.notifyServiceModeration(service, "Hizmetiniz onaylanmadı", service.Title+": "+reason)
+
+	return service, nil
+}
+
+// notifyServiceModeration tells the yandaş how their service was moderated
+func (s *AdminService) notifyServiceModeration(service *models.YandasService, title, body string) {
+	if s.notifications == nil {
+		return
+	}
+	profile, err := s.repos.YandasProfile.GetByID(service.YandasID)
+	if err != nil {
+		log.Printf("[ADMIN] failed to load yandaş %s of service %s: %v", service.YandasID, service.ID, err)
+		return
+	}
+	data := map[string]interface{}{
+		"service_id": service.ID.String(),
+		"status":     service.Status,
+	}
+	if err := s.notifications.Send(profile.UserID, title, body, "system", data); err != nil {
+		log.Printf("[ADMIN] failed to notify yandaş about service %s: %v", service.ID, err)
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestCreateServiceAsDraft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	profiles := mocks.NewMockYandasProfileRepository(ctrl)
+	services := mocks.NewMockServiceRepository(ctrl)
+	svc := NewYandasService(&repository.Repositories{YandasProfile: profiles, Service: services}, &config.Config{ServiceApprovalRequired: true}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: uuid.New(), ApprovalStatus: "approved"}
+	profiles.EXPECT().GetByUserID(profile.UserID).Return(profile, nil)
+	services.EXPECT().Create(gomock.Any()).Return(nil)
+
+	// Drafts don't take a plan slot, so no subscription check is needed
+	service, err := svc.CreateService(profile.UserID, &ServiceInput{Title: "Boya", BasePrice: 30000, Draft: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if service.Status != ServiceStatusDraft || serviceBookable(service) {
+		t.Errorf("expected an unlisted draft, got status %q", service.Status)
+	}
+	if svc.publishedStatus() != ServiceStatusPending {
+		t.Error("expected published services to wait for approval")
+	}
+}
+
+func TestServiceModeration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	services := mocks.NewMockServiceRepository(ctrl)
+	audit := mocks.NewMockAuditLogRepository(ctrl)
+	svc := NewAdminService(&repository.Repositories{Service: services, AuditLog: audit}, nil, nil, nil, nil, nil, nil)
+	adminID := uuid.New()
+
+	if _, _, err := svc.ListServices("archived", 1, 20); !errors.Is(err, ErrInvalidServiceStatus) {
+		t.Errorf("expected ErrInvalidServiceStatus, got %v", err)
+	}
+
+	draft := &models.YandasService{ID: uuid.New(), Status: ServiceStatusDraft, IsActive: true}
+	services.EXPECT().GetByID(draft.ID).Return(draft, nil)
+	if _, err := svc.ApproveService(draft.ID, adminID); !errors.Is(err, ErrServiceNotPending) {
+		t.Errorf("expected ErrServiceNotPending for a draft, got %v", err)
+	}
+
+	pending := &models.YandasService{ID: uuid.New(), Status: ServiceStatusPending, IsActive: true}
+	services.EXPECT().GetByID(pending.ID).Return(pending, nil)
+	services.EXPECT().Update(pending).Return(nil)
+	audit.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *models.AuditLog) error {
+		if log.Action != "reject_service" || *log.EntityID != pending.ID {
+			t.Errorf("unexpected audit log %+v", log)
+		}
+		return nil
+	})
+
+	rejected, err := svc.RejectService(pending.ID, adminID, "Fiyat bilgisi eksik")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rejected.Status != ServiceStatusRejected || rejected.RejectionReason == nil || *rejected.RejectionReason != "Fiyat bilgisi eksik" {
+		t.Errorf("expected a rejected service with the reason, got %+v", rejected)
+	}
+}
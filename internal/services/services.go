@@ -3,7 +3,14 @@ package services
 import (
 	"github.com/redis/go-redis/v9"
 	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/queue"
 	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/currency"
+	"github.com/yandas/backend/pkg/einvoice"
+	"github.com/yandas/backend/pkg/kyc"
+	"github.com/yandas/backend/pkg/media"
+	"github.com/yandas/backend/pkg/ocr"
+	"github.com/yandas/backend/pkg/routing"
 )
 
 // Services holds all service instances
@@ -12,6 +19,7 @@ type Services struct {
 	User         *UserService
 	Yandas       *YandasService
 	Category     *CategoryService
+	Onboarding   *OnboardingService
 	Order        *OrderService
 	Chat         *ChatService
 	Subscription *SubscriptionService
@@ -20,24 +28,108 @@ type Services struct {
 	Favorite     *FavoriteService
 	Support      *SupportService
 	Email        *EmailService
+	SMS          *SMSService
+	Call         *CallService
+	Permission   *PermissionService
+	Screening    *ScreeningService
+	KYC          *KYCService
+	Webhook      *WebhookService
+	Receipt      *ReceiptService
+	Monitoring   *MonitoringService
+	Settings     *SettingsService
+	RoomAccess   *RoomAccessService
+	Announcement *AnnouncementService
+	Legal        *LegalService
+	Consent      *ConsentService
+	JobRequest   *JobRequestService
+	AutoAssign   *AutoAssignService
+	ReviewStats  *ReviewStatsService
+	Currency     *CurrencyService
+	SEO          *SEOService
+	Tracking     *TrackingService
+	Retention    *RetentionService
+	Events       *EventBus
+	Outbox       *Outbox
+	Activity     *ActivityService
+	Health       *HealthService
+	Capture      *RequestCaptureService
+	Documents    *DocumentLinks
+
+	// Jobs enqueues background work; JobHandlers executes it in cmd/worker
+	Jobs        *queue.Queue
+	JobHandlers *queue.Mux
 }
 
 // NewServices creates all services
 func NewServices(repos *repository.Repositories, cfg *config.Config, redis *redis.Client) *Services {
-	emailSvc := NewEmailService(cfg)
+	jobHandlers := queue.NewMux()
+	jobs := queue.New(redis, jobHandlers)
+	events := NewEventBus(repos, jobs)
+	outbox := NewOutbox(repos, jobs)
+	activitySvc := NewActivityService(repos, outbox)
+	settingsSvc := NewSettingsService(repos, cfg, redis)
+	emailSvc := NewEmailService(repos, cfg)
+	smsSvc := NewSMSService(repos, cfg, redis)
+	chatSvc := NewChatService(repos, media.NewTranscoder(cfg.MediaTranscoder, cfg.FFmpegPath), cfg.StoragePath, events, outbox)
+	notificationSvc := NewNotificationService(repos, cfg, redis, emailSvc, jobs, outbox)
+	monitoringSvc := NewMonitoringService(repos, emailSvc, notificationSvc)
+	emailSvc.SetMonitoring(monitoringSvc)
+	tokenVersions := NewTokenVersionCache(repos, redis)
+	subscriptionSvc := NewSubscriptionService(repos, cfg, monitoringSvc, tokenVersions, activitySvc)
+	webhookSvc := NewWebhookService(repos)
+	receiptSvc := NewReceiptService(repos, cfg, einvoice.NewProvider(cfg.EInvoiceProvider), jobs)
+	favoriteSvc := NewFavoriteService(repos, notificationSvc, jobs)
+	reviewStatsSvc := NewReviewStatsService(repos, redis)
+	screeningSvc := NewScreeningService(repos, ocr.NewProvider(cfg.OCRProvider, cfg.TesseractPath, cfg.TesseractLang), cfg.StoragePath, jobs)
+	legalSvc := NewLegalService(repos, redis)
+	consentSvc := NewConsentService(repos, redis, legalSvc)
+	documentLinks := NewDocumentLinks(cfg.DocumentLinkSecret, cfg.StoragePath)
+	kycSvc := NewKYCService(repos, kyc.NewProvider(cfg.KYCProvider), cfg.StoragePath, jobs)
 
-	return &Services{
-		Auth:         NewAuthService(repos, cfg, redis, emailSvc),
+	svcs := &Services{
+		Auth:         NewAuthService(repos, cfg, redis, emailSvc, smsSvc, monitoringSvc, tokenVersions, jobs, settingsSvc, events, consentSvc),
 		User:         NewUserService(repos, cfg),
-		Yandas:       NewYandasService(repos, cfg),
+		Yandas:       NewYandasService(repos, cfg, subscriptionSvc, screeningSvc, kycSvc, webhookSvc, receiptSvc, favoriteSvc, chatSvc, notificationSvc, settingsSvc, routing.NewProvider(cfg.RoutingProvider, cfg.RoutingURL), events, activitySvc),
 		Category:     NewCategoryService(repos),
-		Order:        NewOrderService(repos, cfg),
-		Chat:         NewChatService(repos),
-		Subscription: NewSubscriptionService(repos, cfg),
-		Notification: NewNotificationService(repos, cfg),
-		Admin:        NewAdminService(repos),
-		Favorite:     NewFavoriteService(repos),
-		Support:      NewSupportService(repos),
+		Onboarding:   NewOnboardingService(repos),
+		Order:        NewOrderService(repos, cfg, webhookSvc, monitoringSvc, chatSvc, settingsSvc, reviewStatsSvc, activitySvc),
+		Chat:         chatSvc,
+		Subscription: subscriptionSvc,
+		Notification: notificationSvc,
+		Admin:        NewAdminService(repos, webhookSvc, tokenVersions, reviewStatsSvc, favoriteSvc, notificationSvc, documentLinks),
+		Favorite:     favoriteSvc,
+		Support:      NewSupportService(repos, notificationSvc, activitySvc),
 		Email:        emailSvc,
+		SMS:          smsSvc,
+		Call:         NewCallService(repos, chatSvc, notificationSvc),
+		Permission:   NewPermissionService(repos),
+		Screening:    screeningSvc,
+		KYC:          kycSvc,
+		Webhook:      webhookSvc,
+		Receipt:      receiptSvc,
+		Monitoring:   monitoringSvc,
+		Settings:     settingsSvc,
+		RoomAccess:   NewRoomAccessService(repos, redis),
+		Announcement: NewAnnouncementService(repos),
+		Legal:        legalSvc,
+		Consent:      consentSvc,
+		JobRequest:   NewJobRequestService(repos, notificationSvc, jobs, webhookSvc, monitoringSvc, chatSvc, settingsSvc),
+		AutoAssign:   NewAutoAssignService(repos, notificationSvc, webhookSvc, monitoringSvc, chatSvc, settingsSvc),
+		ReviewStats:  reviewStatsSvc,
+		Currency:     NewCurrencyService(repos, currency.NewRateProvider(cfg.ExchangeRateProvider, cfg.ExchangeRateURL)),
+		SEO:          NewSEOService(repos, cfg),
+		Tracking:     NewTrackingService(repos, nil),
+		Retention:    NewRetentionService(repos, redis, settingsSvc),
+		Events:       events,
+		Outbox:       outbox,
+		Activity:     activitySvc,
+		Health:       NewHealthService(repos, cfg, redis),
+		Capture:      NewRequestCaptureService(repos, redis),
+		Documents:    documentLinks,
+		Jobs:         jobs,
+		JobHandlers:  jobHandlers,
 	}
+	registerJobHandlers(jobHandlers, svcs)
+	registerEventSubscribers(events, svcs)
+	return svcs
 }
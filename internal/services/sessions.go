@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/auth"
+)
+
+var (
+	ErrSessionNotFound = notFoundError("session_not_found", "session not found")
+	ErrSessionRevoked  = unauthenticatedError("session_revoked", "session has been revoked")
+)
+
+// ClientInfo describes the device a request came from
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+func sessionRevokedKey(sessionID string) string {
+	return "session_revoked:" + sessionID
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
+
+func (s *AuthService) generateTokens(user *models.User, platform, sessionID string) (*auth.TokenPair, error) {
+	email := ""
+	if user.Email != nil {
+		email = *user.Email
+	}
+	return auth.GenerateTokenPair(auth.Claims{
+		UserID:       user.ID.String(),
+		Email:        email,
+		Role:         user.Role,
+		Platform:     platform,
+		SessionID:    sessionID,
+		TokenVersion: user.TokenVersion,
+	}, s.cfg.JWTSecret, s.cfg.JWTAccessExpiry, s.cfg.JWTRefreshExpiry)
+}
+
+// startSession records a new signed-in device and issues its first token pair
+func (s *AuthService) startSession(user *models.User, platform string, client ClientInfo) (*auth.TokenPair, error) {
+	session := &models.Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		Platform:   truncate(platform, 20),
+		UserAgent:  truncate(client.UserAgent, 255),
+		IPAddress:  truncate(client.IP, 45),
+		LastSeenAt: time.Now(),
+		ExpiresAt:  time.Now().Add(s.cfg.JWTRefreshExpiry),
+	}
+
+	tokens, err := s.generateTokens(user, platform, session.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	session.RefreshTokenHash = hashToken(tokens.RefreshToken)
+
+	if err := s.repos.Session.Create(session); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// rotateSession exchanges a session's refresh token for a new pair. A refresh
+// token that was already exchanged no longer matches the stored hash and is rejected.
+func (s *AuthService) rotateSession(user *models.User, sessionID, refreshToken, platform string, client ClientInfo) (*auth.TokenPair, error) {
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	session, err := s.repos.OnPrimary().Session.GetByID(id)
+	if err != nil || session.UserID != user.ID {
+		return nil, ErrSessionNotFound
+	}
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return nil, ErrSessionRevoked
+	}
+
+	oldHash := hashToken(refreshToken)
+	if session.RefreshTokenHash != oldHash {
+		return nil, ErrSessionRevoked
+	}
+
+	if platform == "" {
+		platform = session.Platform
+	}
+	tokens, err := s.generateTokens(user, platform, session.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	ip := client.IP
+	if ip == "" {
+		ip = session.IPAddress
+	}
+	rotated, err := s.repos.Session.Rotate(session.ID, oldHash, hashToken(tokens.RefreshToken), truncate(ip, 45), time.Now().Add(s.cfg.JWTRefreshExpiry))
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, ErrSessionRevoked
+	}
+	return tokens, nil
+}
+
+// ListSessions returns the user's active sessions, flagging the one making the request
+func (s *AuthService) ListSessions(userID uuid.UUID, currentSessionID string) ([]models.Session, error) {
+	sessions, err := s.repos.Session.ListActiveForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID.String() == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs a device out remotely and stops push notifications to it
+func (s *AuthService) RevokeSession(userID, sessionID uuid.UUID) error {
+	session, err := s.repos.OnPrimary().Session.GetByID(sessionID)
+	if err != nil || session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.repos.Session.Revoke(session.ID); err != nil {
+		return err
+	}
+	if err := s.repos.DeviceToken.DeactivateForSession(session.ID); err != nil {
+		log.Printf("Device token deactivation failed for session %s: %v\n", session.ID, err)
+	}
+
+	s.rememberRevoked(session.ID)
+	return nil
+}
+
+// revokeAllSessions signs the user out everywhere, e.g. after their login email changes
+func (s *AuthService) revokeAllSessions(userID uuid.UUID) error {
+	ids, err := s.repos.Session.RevokeAllForUser(userID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := s.repos.DeviceToken.DeactivateForSession(id); err != nil {
+			log.Printf("Device token deactivation failed for session %s: %v\n", id, err)
+		}
+		s.rememberRevoked(id)
+	}
+	return nil
+}
+
+// rememberRevoked caches a revocation for IsSessionRevoked. Tokens issued
+// before the typ claim can be a refresh token presented as an access token,
+// so the entry outlives the longest-lived token of the session.
+func (s *AuthService) rememberRevoked(sessionID uuid.UUID) {
+	if s.redis != nil {
+		s.redis.Set(context.Background(), sessionRevokedKey(sessionID.String()), 1, s.cfg.JWTRefreshExpiry)
+	}
+}
+
+// IsSessionRevoked reports whether an access token's session has been signed out.
+// Tokens without a session id predate sessions and are accepted until they expire.
+func (s *AuthService) IsSessionRevoked(sessionID string) bool {
+	if sessionID == "" {
+		return false
+	}
+	if s.redis != nil {
+		n, err := s.redis.Exists(context.Background(), sessionRevokedKey(sessionID)).Result()
+		return err == nil && n > 0
+	}
+
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return true
+	}
+	session, err := s.repos.OnPrimary().Session.GetByID(id)
+	return err != nil || session.RevokedAt != nil
+}
+
+// IsTokenStale reports whether a token was issued before the user's role or status last changed
+func (s *AuthService) IsTokenStale(userID string, tokenVersion int) bool {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return true
+	}
+	current, err := s.tokenVersions.Get(id)
+	return err != nil || tokenVersion != current
+}
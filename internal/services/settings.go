@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// Operational settings admins can change at runtime
+const (
+	SettingCommissionRate       = "commission.rate"
+	SettingRateLimitRequests    = "rate_limit.requests"
+	SettingRateLimitWindow      = "rate_limit.window"
+	SettingOrderCancelCutoff    = "orders.cancel_cutoff"
+	SettingOrderConfirmation    = "orders.completion_confirmation"
+	SettingOrderAutoConfirm     = "orders.auto_confirm_after"
+	SettingOTPResendCooldown    = "otp.resend_cooldown"
+	SettingOTPMaxSendsPerHour   = "otp.max_sends_per_hour"
+	SettingOTPMaxVerifyAttempts = "otp.max_verify_attempts"
+	SettingOTPLockout           = "otp.lockout"
+	SettingFeatureJobRequests   = "features.job_requests"
+	SettingFeatureAutoAssign    = "features.auto_assign"
+	SettingMaintenanceEnabled   = "maintenance.enabled"
+	SettingMaintenanceStartsAt  = "maintenance.starts_at"
+	SettingMaintenanceEndsAt    = "maintenance.ends_at"
+	SettingRankingQuality       = "ranking.quality_score"
+	SettingRetentionUsers       = "retention.deleted_user_days"
+	SettingRetentionMessages    = "retention.message_months"
+	SettingRetentionAuditLogs   = "retention.audit_log_months"
+	SettingRetentionCallLogs    = "retention.call_log_months"
+	SettingRetentionDryRun      = "retention.dry_run"
+)
+
+// Setting value types
+const (
+	SettingTypeFloat    = "float"
+	SettingTypeInt      = "int"
+	SettingTypeBool     = "bool"
+	SettingTypeDuration = "duration"
+	SettingTypeTime     = "time" // RFC 3339, empty for none
+)
+
+// How long the overrides are cached; updates invalidate the cache right away
+const settingsCacheTTL = 5 * time.Minute
+
+const settingsCacheKey = "settings"
+
+var (
+	ErrUnknownSetting  = validationError("unknown_setting", "unknown setting")
+	ErrFeatureDisabled = unavailableError("feature_disabled", "this feature is currently disabled")
+)
+
+// SettingDefinition describes a setting: its type, bounds and default
+type SettingDefinition struct {
+	Key         string   `json:"key"`
+	Type        string   `json:"type"`
+	Description string   `json:"description"`
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	Default     string   `json:"default"`
+}
+
+func bound(v float64) *float64 {
+	return &v
+}
+
+// settingDefinitions lists every known setting. Defaults of settings that also
+// have an environment variable are replaced with the configured value.
+var settingDefinitions = []SettingDefinition{
+	{Key: SettingCommissionRate, Type: SettingTypeFloat, Description: "Komisyon oranı (0.15 = %15), kategori veya plan geçersiz kılmadıkça", Min: bound(0), Max: bound(1), Default: "0.15"},
+	{Key: SettingRateLimitRequests, Type: SettingTypeInt, Description: "IP başına pencere içinde izin verilen istek sayısı", Min: bound(1), Max: bound(100000), Default: "100"},
+	{Key: SettingRateLimitWindow, Type: SettingTypeDuration, Description: "İstek sınırı penceresi", Min: bound(1), Max: bound(86400), Default: "60s"},
+	{Key: SettingOrderCancelCutoff, Type: SettingTypeDuration, Description: "Kabul edilmiş siparişin başlangıcına bu süreden az kala iptal edilemez (0 = sınırsız)", Min: bound(0), Max: bound(7 * 86400), Default: "0s"},
+	{Key: SettingOrderConfirmation, Type: SettingTypeBool, Description: "Tamamlanan siparişler müşteri onaylayana kadar onay bekler; kazanç onaydan sonra aktarılır", Default: "false"},
+	{Key: SettingOrderAutoConfirm, Type: SettingTypeDuration, Description: "Müşterinin onaylamadığı tamamlanma bu süreden sonra kendiliğinden onaylanır", Min: bound(3600), Max: bound(7 * 86400), Default: "24h"},
+	{Key: SettingOTPResendCooldown, Type: SettingTypeDuration, Description: "Aynı hedefe iki kod arasındaki en kısa süre", Min: bound(0), Max: bound(3600), Default: otpResendCooldown.String()},
+	{Key: SettingOTPMaxSendsPerHour, Type: SettingTypeInt, Description: "Bir hedefe saatte gönderilebilecek kod sayısı", Min: bound(1), Max: bound(100), Default: strconv.Itoa(otpMaxSendsPerHour)},
+	{Key: SettingOTPMaxVerifyAttempts, Type: SettingTypeInt, Description: "Kilitlenmeden önce kod başına yanlış deneme hakkı", Min: bound(1), Max: bound(100), Default: strconv.Itoa(otpMaxVerifyAttempts)},
+	{Key: SettingOTPLockout, Type: SettingTypeDuration, Description: "Kilitlenen hedefin kod alamayacağı ve doğrulayamayacağı süre", Min: bound(60), Max: bound(86400), Default: otpLockout.String()},
+	{Key: SettingFeatureJobRequests, Type: SettingTypeBool, Description: "Açık iş talepleri ve teklifler", Default: "true"},
+	{Key: SettingFeatureAutoAssign, Type: SettingTypeBool, Description: "Acil siparişlerin en yakın yandaşa otomatik atanması", Default: "true"},
+	{Key: SettingMaintenanceEnabled, Type: SettingTypeBool, Description: "Bakım modu: yönetim paneli dışındaki tüm istekler 503 ile yanıtlanır", Default: "false"},
+	{Key: SettingMaintenanceStartsAt, Type: SettingTypeTime, Description: "Planlı bakımın başlangıcı (RFC 3339, boş = planlı bakım yok)", Default: ""},
+	{Key: SettingMaintenanceEndsAt, Type: SettingTypeTime, Description: "Bakımın tahmini bitişi; planlı bakım bu saatte kendiliğinden sona erer", Default: ""},
+	{Key: SettingRankingQuality, Type: SettingTypeBool, Description: "Genel yandaş listesi, öncelikli listelemeden sonra kalite puanına göre sıralanır", Default: "false"},
+	{Key: SettingRetentionUsers, Type: SettingTypeInt, Description: "Silinen hesaplar bu kadar gün sonra kalıcı olarak silinir", Min: bound(1), Max: bound(365), Default: "30"},
+	{Key: SettingRetentionMessages, Type: SettingTypeInt, Description: "Bu kadar aydan eski mesajlar arşiv tablosuna taşınır (0 = taşınmaz)", Min: bound(0), Max: bound(120), Default: "12"},
+	{Key: SettingRetentionAuditLogs, Type: SettingTypeInt, Description: "Bu kadar aydan eski denetim kayıtları silinir (0 = silinmez)", Min: bound(0), Max: bound(120), Default: "24"},
+	{Key: SettingRetentionCallLogs, Type: SettingTypeInt, Description: "Bu kadar aydan eski arama kayıtları silinir; kaydı olan aramalar saklanır (0 = silinmez)", Min: bound(0), Max: bound(120), Default: "12"},
+	{Key: SettingRetentionDryRun, Type: SettingTypeBool, Description: "Zamanlanmış saklama görevi hiçbir şey silmeden yalnızca rapor üretir", Default: "false"},
+}
+
+func settingDefinition(key string) (SettingDefinition, bool) {
+	for _, def := range settingDefinitions {
+		if def.Key == key {
+			return def, true
+		}
+	}
+	return SettingDefinition{}, false
+}
+
+// invalidSetting reports a value that does not fit its setting
+func invalidSetting(format string, args ...interface{}) error {
+	return validationError("invalid_setting", fmt.Sprintf(format, args...))
+}
+
+// parseSetting checks a raw value against the definition's type and bounds.
+// Duration bounds are in seconds.
+func parseSetting(def SettingDefinition, raw string) (interface{}, error) {
+	var value interface{}
+	var magnitude float64
+	switch def.Type {
+	case SettingTypeFloat:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, invalidSetting("%s must be a number", def.Key)
+		}
+		value, magnitude = v, v
+	case SettingTypeInt:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, invalidSetting("%s must be a whole number", def.Key)
+		}
+		value, magnitude = v, float64(v)
+	case SettingTypeBool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, invalidSetting("%s must be true or false", def.Key)
+		}
+		return v, nil
+	case SettingTypeDuration:
+		v, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, invalidSetting("%s must be a duration such as 90s or 15m", def.Key)
+		}
+		value, magnitude = v, v.Seconds()
+	case SettingTypeTime:
+		if raw == "" {
+			return time.Time{}, nil
+		}
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, invalidSetting("%s must be a time such as 2024-06-01T03:00:00+03:00", def.Key)
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("%s has unsupported type %s", def.Key, def.Type)
+	}
+	if def.Min != nil && magnitude < *def.Min {
+		return nil, invalidSetting("%s must be at least %v", def.Key, *def.Min)
+	}
+	if def.Max != nil && magnitude > *def.Max {
+		return nil, invalidSetting("%s must be at most %v", def.Key, *def.Max)
+	}
+	return value, nil
+}
+
+// SettingsService serves operational settings that admins can change without a
+// deploy. Overrides live in the settings table and are cached in Redis. A nil
+// *SettingsService answers every lookup with the built-in default.
+type SettingsService struct {
+	repos    *repository.Repositories
+	redis    *redis.Client
+	defaults map[string]string
+
+	// Maintenance notices pushed to this process's WebSocket clients
+	realtime        GlobalBroadcaster
+	maintenanceMu   sync.Mutex
+	lastMaintenance *MaintenanceStatus
+}
+
+// NewSettingsService creates the settings service; without Redis every lookup hits the database
+func NewSettingsService(repos *repository.Repositories, cfg *config.Config, redis *redis.Client) *SettingsService {
+	defaults := map[string]string{}
+	for _, def := range settingDefinitions {
+		defaults[def.Key] = def.Default
+	}
+	if cfg != nil {
+		defaults[SettingCommissionRate] = strconv.FormatFloat(cfg.CommissionRate, 'f', -1, 64)
+		if cfg.RateLimitRequests > 0 {
+			defaults[SettingRateLimitRequests] = strconv.Itoa(cfg.RateLimitRequests)
+		}
+		if cfg.RateLimitWindow > 0 {
+			defaults[SettingRateLimitWindow] = (time.Duration(cfg.RateLimitWindow) * time.Second).String()
+		}
+		defaults[SettingMaintenanceEnabled] = strconv.FormatBool(cfg.MaintenanceMode)
+	}
+	return &SettingsService{repos: repos, redis: redis, defaults: defaults}
+}
+
+// overrides returns the stored values by key, from the cache when possible
+func (s *SettingsService) overrides() map[string]string {
+	ctx := context.Background()
+	if s.redis != nil {
+		if cached, err := s.redis.Get(ctx, settingsCacheKey).Bytes(); err == nil {
+			values := map[string]string{}
+			if json.Unmarshal(cached, &values) == nil {
+				return values
+			}
+		}
+	}
+
+	values := map[string]string{}
+	rows, err := s.repos.Setting.List()
+	if err != nil {
+		// Fall back to defaults rather than failing the request; don't cache the miss
+		return values
+	}
+	for _, row := range rows {
+		values[row.Key] = row.Value
+	}
+	if s.redis != nil {
+		if data, err := json.Marshal(values); err == nil {
+			s.redis.Set(ctx, settingsCacheKey, data, settingsCacheTTL)
+		}
+	}
+	return values
+}
+
+// value returns the parsed current value of key. Stored values that no longer
+// parse, e.g. after a bound was tightened, are ignored in favour of the default.
+func (s *SettingsService) value(key string) interface{} {
+	def, ok := settingDefinition(key)
+	if !ok {
+		return nil
+	}
+	if s != nil {
+		if raw, ok := s.overrides()[key]; ok {
+			if v, err := parseSetting(def, raw); err == nil {
+				return v
+			}
+		}
+		def.Default = s.defaults[key]
+	}
+	v, _ := parseSetting(def, def.Default)
+	return v
+}
+
+// Float returns a float setting
+func (s *SettingsService) Float(key string) float64 {
+	v, _ := s.value(key).(float64)
+	return v
+}
+
+// Int returns an int setting
+func (s *SettingsService) Int(key string) int {
+	v, _ := s.value(key).(int)
+	return v
+}
+
+// Bool returns a bool setting
+func (s *SettingsService) Bool(key string) bool {
+	v, _ := s.value(key).(bool)
+	return v
+}
+
+// Duration returns a duration setting
+func (s *SettingsService) Duration(key string) time.Duration {
+	v, _ := s.value(key).(time.Duration)
+	return v
+}
+
+// Time returns a time setting; the zero time when it is empty
+func (s *SettingsService) Time(key string) time.Time {
+	v, _ := s.value(key).(time.Time)
+	return v
+}
+
+// RateLimit returns the per-IP request limit and its window
+func (s *SettingsService) RateLimit() (int, time.Duration) {
+	return s.Int(SettingRateLimitRequests), s.Duration(SettingRateLimitWindow)
+}
+
+// SettingView is a setting as shown to admins
+type SettingView struct {
+	SettingDefinition
+	Value      string     `json:"value"`
+	Overridden bool       `json:"overridden"`
+	UpdatedBy  *uuid.UUID `json:"updated_by,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// List returns every setting with its current value
+func (s *SettingsService) List() ([]SettingView, error) {
+	rows, err := s.repos.OnPrimary().Setting.List()
+	if err != nil {
+		return nil, err
+	}
+	stored := map[string]models.Setting{}
+	for _, row := range rows {
+		stored[row.Key] = row
+	}
+
+	views := make([]SettingView, 0, len(settingDefinitions))
+	for _, def := range settingDefinitions {
+		def.Default = s.defaults[def.Key]
+		view := SettingView{SettingDefinition: def, Value: def.Default}
+		if row, ok := stored[def.Key]; ok {
+			row := row
+			view.Value = row.Value
+			view.Overridden = true
+			view.UpdatedBy = row.UpdatedBy
+			view.UpdatedAt = &row.UpdatedAt
+		}
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+// UpdateSettingsInput maps setting keys to their new values. A null value
+// removes the override so the setting returns to its default.
+type UpdateSettingsInput struct {
+	Values map[string]*string `json:"values" binding:"required,min=1"`
+}
+
+// Update validates every value before storing any of them, then audit-logs each change
+func (s *SettingsService) Update(input *UpdateSettingsInput, adminID uuid.UUID) ([]SettingView, error) {
+	keys := make([]string, 0, len(input.Values))
+	for key, raw := range input.Values {
+		def, ok := settingDefinition(key)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+		}
+		if raw != nil {
+			if _, err := parseSetting(def, *raw); err != nil {
+				return nil, err
+			}
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	before := map[string]string{}
+	rows, err := s.repos.OnPrimary().Setting.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		before[row.Key] = row.Value
+	}
+
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		for _, key := range keys {
+			raw := input.Values[key]
+			if raw == nil {
+				if err := tx.Setting.Delete(key); err != nil {
+					return err
+				}
+				continue
+			}
+			def, _ := settingDefinition(key)
+			if err := tx.Setting.Upsert(&models.Setting{Key: key, Type: def.Type, Value: *raw, UpdatedBy: &adminID}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.redis != nil {
+		s.redis.Del(context.Background(), settingsCacheKey)
+	}
+	s.WatchMaintenance()
+
+	entityType := "setting"
+	for _, key := range keys {
+		oldValue, ok := before[key]
+		if !ok {
+			oldValue = s.defaults[key]
+		}
+		newValue := s.defaults[key]
+		if raw := input.Values[key]; raw != nil {
+			newValue = *raw
+		}
+		oldJSON, _ := json.Marshal(map[string]interface{}{"key": key, "value": oldValue})
+		newJSON, _ := json.Marshal(map[string]interface{}{"key": key, "value": newValue, "overridden": input.Values[key] != nil})
+		oldStr, newStr := string(oldJSON), string(newJSON)
+		s.repos.AuditLog.Create(&models.AuditLog{
+			AdminID:    adminID,
+			Action:     "update_setting",
+			EntityType: &entityType,
+			OldValues:  &oldStr,
+			NewValues:  &newStr,
+		})
+	}
+
+	return s.List()
+}
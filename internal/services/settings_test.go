@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestSettingsDefaultsAndOverrides(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	settings := mocks.NewMockSettingRepository(ctrl)
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	svc := NewSettingsService(&repository.Repositories{Setting: settings}, &config.Config{CommissionRate: 0.12, RateLimitWindow: 30}, client)
+
+	// Loaded once, then served from the cache
+	settings.EXPECT().List().Return([]models.Setting{
+		{Key: SettingOTPMaxSendsPerHour, Type: SettingTypeInt, Value: "3"},
+		{Key: SettingFeatureAutoAssign, Type: SettingTypeBool, Value: "false"},
+		{Key: SettingOTPResendCooldown, Type: SettingTypeDuration, Value: "not a duration"},
+	}, nil).Times(1)
+
+	if got := svc.Float(SettingCommissionRate); got != 0.12 {
+		t.Errorf("expected the configured commission rate, got %v", got)
+	}
+	if requests, window := svc.RateLimit(); requests != 100 || window != 30*time.Second {
+		t.Errorf("unexpected rate limit %d per %v", requests, window)
+	}
+	if got := svc.Int(SettingOTPMaxSendsPerHour); got != 3 {
+		t.Errorf("expected the override, got %d", got)
+	}
+	if svc.Bool(SettingFeatureAutoAssign) {
+		t.Error("expected auto-assign to be switched off")
+	}
+	if got := svc.Duration(SettingOTPResendCooldown); got != otpResendCooldown {
+		t.Errorf("expected an unparseable override to fall back to the default, got %v", got)
+	}
+
+	var unset *SettingsService
+	if got := unset.Int(SettingOTPMaxVerifyAttempts); got != otpMaxVerifyAttempts {
+		t.Errorf("expected a nil service to return defaults, got %d", got)
+	}
+}
+
+func TestSettingsUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	settings := mocks.NewMockSettingRepository(ctrl)
+	audit := mocks.NewMockAuditLogRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{Setting: settings, AuditLog: audit, UnitOfWork: uow}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	svc := NewSettingsService(repos, &config.Config{CommissionRate: 0.15}, nil)
+	adminID := uuid.New()
+
+	// Nothing is stored when any value is invalid
+	_, err := svc.Update(&UpdateSettingsInput{Values: map[string]*string{
+		SettingCommissionRate:    strPtr("0.2"),
+		SettingRateLimitRequests: strPtr("0"),
+	}}, adminID)
+	if err == nil {
+		t.Fatal("expected an out of range value to be rejected")
+	}
+	if _, err := svc.Update(&UpdateSettingsInput{Values: map[string]*string{"nope": strPtr("1")}}, adminID); !errors.Is(err, ErrUnknownSetting) {
+		t.Fatalf("expected ErrUnknownSetting, got %v", err)
+	}
+
+	settings.EXPECT().List().Return([]models.Setting{{Key: SettingOTPLockout, Value: "30m"}}, nil).AnyTimes()
+	settings.EXPECT().Upsert(gomock.Any()).DoAndReturn(func(s *models.Setting) error {
+		if s.Key != SettingCommissionRate || s.Type != SettingTypeFloat || s.Value != "0.2" || *s.UpdatedBy != adminID {
+			t.Errorf("unexpected upsert %+v", s)
+		}
+		return nil
+	})
+	settings.EXPECT().Delete(SettingOTPLockout).Return(nil)
+	audit.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *models.AuditLog) error {
+		if log.Action != "update_setting" || log.AdminID != adminID {
+			t.Errorf("unexpected audit log %+v", log)
+		}
+		return nil
+	}).Times(2)
+
+	if _, err := svc.Update(&UpdateSettingsInput{Values: map[string]*string{
+		SettingCommissionRate: strPtr("0.2"),
+		SettingOTPLockout:     nil,
+	}}, adminID); err != nil {
+		t.Fatal(err)
+	}
+}
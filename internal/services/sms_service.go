@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/sms"
+)
+
+// What an SMS was sent for, recorded on its deliveries
+const (
+	SMSPurposeOTP  = "otp"
+	SMSPurposeText = "text"
+)
+
+const (
+	smsTimeout = 15 * time.Second
+	// How long a locally generated OTP stays valid
+	smsOTPTTL = 5 * time.Minute
+)
+
+var (
+	ErrSMSFailed          = errors.New("no SMS provider could send the message")
+	ErrUnknownSMSProvider = notFoundError("unknown_sms_provider", "unknown SMS provider")
+)
+
+// SMSService sends text messages through the configured providers, failing over
+// to the next one when a provider errors, and logs every attempt with its
+// delivery status for troubleshooting
+type SMSService struct {
+	repos     *repository.Repositories
+	redis     *redis.Client
+	providers []sms.Provider // primary first
+	verifier  sms.Verifier   // the primary's hosted OTP verification, if it has one
+}
+
+// NewSMSService creates an SMS service from the SMS_PROVIDER and SMS_FALLBACK_PROVIDERS
+// settings. Providers without credentials are skipped; with none left messages are only logged.
+func NewSMSService(repos *repository.Repositories, cfg *config.Config, redis *redis.Client) *SMSService {
+	s := &SMSService{repos: repos, redis: redis}
+
+	seen := make(map[string]bool)
+	for _, name := range append([]string{cfg.SMSProvider}, cfg.SMSFallbackProviders...) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if provider := newSMSProvider(name, cfg); provider != nil {
+			s.providers = append(s.providers, provider)
+		}
+	}
+	if len(s.providers) == 0 {
+		log.Printf("[SMS] no SMS provider configured, messages will only be logged")
+		s.providers = []sms.Provider{sms.NewMock()}
+	}
+	if twilio, ok := s.providers[0].(*sms.Twilio); ok && twilio.CanVerify() {
+		s.verifier = twilio
+	}
+	return s
+}
+
+// newSMSProvider returns the provider configured by name, or nil when it is disabled or lacks credentials
+func newSMSProvider(name string, cfg *config.Config) sms.Provider {
+	switch name {
+	case "", "none":
+		return nil
+	case "twilio":
+		if cfg.TwilioAccountSID == "" || cfg.TwilioAuthToken == "" || (cfg.TwilioFromNumber == "" && cfg.TwilioVerifySID == "") {
+			return nil
+		}
+		callbackURL := ""
+		if cfg.APIURL != "" {
+			callbackURL = strings.TrimRight(cfg.APIURL, "/") + "/api/v1/sms/status/twilio"
+		}
+		return sms.NewTwilio(sms.TwilioConfig{
+			AccountSID:  cfg.TwilioAccountSID,
+			AuthToken:   cfg.TwilioAuthToken,
+			From:        cfg.TwilioFromNumber,
+			VerifySID:   cfg.TwilioVerifySID,
+			CallbackURL: callbackURL,
+		})
+	case "netgsm":
+		if cfg.NetgsmUserCode == "" || cfg.NetgsmPassword == "" || cfg.NetgsmHeader == "" {
+			return nil
+		}
+		return sms.NewNetgsm(cfg.NetgsmUserCode, cfg.NetgsmPassword, cfg.NetgsmHeader)
+	case "mock":
+		return sms.NewMock()
+	}
+	log.Printf("[SMS] unknown SMS provider %q, skipped", name)
+	return nil
+}
+
+// Send delivers a text message to an E.164 phone number
+func (s *SMSService) Send(phone, body string) error {
+	if s == nil {
+		log.Printf("[SMS] not configured, message to %s dropped", phone)
+		return nil
+	}
+	return s.send(phone, body, SMSPurposeText, 1)
+}
+
+// send tries the providers in order until one accepts the message, starting the attempt count at attempt
+func (s *SMSService) send(phone, body, purpose string, attempt int) error {
+	var lastErr error
+	for i, provider := range s.providers {
+		ctx, cancel := context.WithTimeout(context.Background(), smsTimeout)
+		messageID, err := provider.Send(ctx, phone, body)
+		cancel()
+		s.record(phone, purpose, provider.Name(), messageID, attempt+i, err)
+		if err == nil {
+			if i > 0 {
+				log.Printf("[SMS] sent to %s through fallback provider %s", phone, provider.Name())
+			}
+			return nil
+		}
+		log.Printf("[SMS] %s failed to send to %s: %v", provider.Name(), phone, err)
+		lastErr = err
+	}
+	return fmt.Errorf("%w: %v", ErrSMSFailed, lastErr)
+}
+
+// SendOTP sends a verification code to an E.164 phone number. The primary
+// provider's hosted verification is used when it has one; otherwise, or when it
+// fails, a code is generated here, kept in Redis and sent as a plain message.
+func (s *SMSService) SendOTP(phone string) error {
+	if s == nil {
+		log.Printf("[SMS] not configured, OTP for %s not sent", phone)
+		return nil
+	}
+
+	ctx := context.Background()
+	attempt := 1
+	if s.verifier != nil {
+		verifyCtx, cancel := context.WithTimeout(ctx, smsTimeout)
+		verificationID, err := s.verifier.StartVerification(verifyCtx, phone)
+		cancel()
+		s.record(phone, SMSPurposeOTP, s.providers[0].Name(), verificationID, attempt, err)
+		if err == nil {
+			// Drop a code from an earlier fallback so the new one is checked with the provider
+			if s.redis != nil {
+				s.redis.Del(ctx, otpKey(phone))
+			}
+			return nil
+		}
+		log.Printf("[SMS] hosted verification failed for %s, sending our own code: %v", phone, err)
+		attempt++
+	}
+
+	code, err := generateOTP()
+	if err != nil {
+		return err
+	}
+	if s.redis != nil {
+		if err := s.redis.Set(ctx, otpKey(phone), code, smsOTPTTL).Err(); err != nil {
+			return err
+		}
+	} else {
+		log.Printf("[SMS] Redis unavailable, the OTP sent to %s cannot be verified", phone)
+	}
+	return s.send(phone, fmt.Sprintf("YANDAŞ doğrulama kodunuz: %s", code), SMSPurposeOTP, attempt)
+}
+
+// CheckOTP reports whether code is the one last sent to phone. A locally
+// generated code is consumed on success; otherwise the hosted verification decides.
+func (s *SMSService) CheckOTP(phone, code string) bool {
+	if s == nil {
+		return false
+	}
+
+	ctx := context.Background()
+	if s.redis != nil {
+		if stored, err := s.redis.Get(ctx, otpKey(phone)).Result(); err == nil {
+			if stored != code {
+				return false
+			}
+			s.redis.Del(ctx, otpKey(phone))
+			return true
+		}
+	}
+	if s.verifier == nil {
+		return false
+	}
+
+	verifyCtx, cancel := context.WithTimeout(ctx, smsTimeout)
+	defer cancel()
+	approved, err := s.verifier.CheckVerification(verifyCtx, phone, code)
+	if err != nil {
+		log.Printf("[SMS] verification check failed for %s: %v", phone, err)
+	}
+	return approved
+}
+
+func otpKey(phone string) string {
+	return fmt.Sprintf("otp:%s", phone)
+}
+
+// record logs one send attempt; failures to log never fail the send
+func (s *SMSService) record(phone, purpose, provider, messageID string, attempt int, sendErr error) {
+	delivery := &models.SMSDelivery{
+		Phone:             phone,
+		Purpose:           purpose,
+		Provider:          provider,
+		ProviderMessageID: stringOrNil(messageID),
+		Attempt:           attempt,
+		Status:            sms.StatusSent,
+	}
+	if sendErr != nil {
+		delivery.Status = sms.StatusFailed
+		delivery.Error = stringOrNil(sendErr.Error())
+	}
+	if err := s.repos.SMSDelivery.Create(delivery); err != nil {
+		log.Printf("[SMS] failed to log delivery to %s: %v", phone, err)
+	}
+}
+
+// HandleStatus records a delivery report posted by a provider
+func (s *SMSService) HandleStatus(providerName string, r *http.Request) error {
+	var parser sms.StatusParser
+	for _, provider := range s.providers {
+		if provider.Name() == providerName {
+			parser, _ = provider.(sms.StatusParser)
+		}
+	}
+	if parser == nil {
+		return ErrUnknownSMSProvider
+	}
+
+	status, err := parser.ParseStatus(r)
+	if err != nil || status == nil {
+		return err
+	}
+	updated, err := s.repos.SMSDelivery.UpdateStatus(providerName, status.MessageID, status.Status, stringOrNil(status.ErrorCode), stringOrNil(status.Error))
+	if err != nil {
+		return err
+	}
+	if !updated {
+		log.Printf("[SMS] %s status for unknown message %s", providerName, status.MessageID)
+	}
+	return nil
+}
+
+// Deliveries lists logged send attempts, newest first, optionally for one phone number or status
+func (s *SMSService) Deliveries(page, limit int, phone, status string) ([]models.SMSDelivery, int64, error) {
+	if phone != "" {
+		phone = normalizePhone(phone)
+	}
+	return s.repos.SMSDelivery.List(page, limit, phone, status)
+}
+
+func stringOrNil(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/sms"
+)
+
+type fakeSMSProvider struct {
+	name string
+	err  error
+	sent []string
+}
+
+func (p *fakeSMSProvider) Name() string { return p.name }
+
+func (p *fakeSMSProvider) Send(_ context.Context, to, body string) (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	p.sent = append(p.sent, to)
+	return p.name + "-1", nil
+}
+
+func TestSMSSendFailsOver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	deliveries := mocks.NewMockSMSDeliveryRepository(ctrl)
+	primary := &fakeSMSProvider{name: "twilio", err: errors.New("service unavailable")}
+	fallback := &fakeSMSProvider{name: "netgsm"}
+	svc := &SMSService{
+		repos:     &repository.Repositories{SMSDelivery: deliveries},
+		providers: []sms.Provider{primary, fallback},
+	}
+
+	var logged []models.SMSDelivery
+	deliveries.EXPECT().Create(gomock.Any()).DoAndReturn(func(d *models.SMSDelivery) error {
+		logged = append(logged, *d)
+		return nil
+	}).Times(2)
+
+	if err := svc.Send("+905321234567", "Merhaba"); err != nil {
+		t.Fatal(err)
+	}
+	if len(fallback.sent) != 1 {
+		t.Fatalf("expected the fallback to send, got %v", fallback.sent)
+	}
+	if logged[0].Provider != "twilio" || logged[0].Status != sms.StatusFailed || logged[0].Attempt != 1 || logged[0].Error == nil {
+		t.Errorf("unexpected primary attempt %+v", logged[0])
+	}
+	if logged[1].Provider != "netgsm" || logged[1].Status != sms.StatusSent || logged[1].Attempt != 2 || *logged[1].ProviderMessageID != "netgsm-1" {
+		t.Errorf("unexpected fallback attempt %+v", logged[1])
+	}
+
+	// Every provider failing is an error
+	fallback.err = errors.New("limit exceeded")
+	deliveries.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+	if err := svc.Send("+905321234567", "Merhaba"); !errors.Is(err, ErrSMSFailed) {
+		t.Errorf("expected ErrSMSFailed, got %v", err)
+	}
+}
+
+func TestSMSHandleStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	deliveries := mocks.NewMockSMSDeliveryRepository(ctrl)
+	svc := &SMSService{
+		repos:     &repository.Repositories{SMSDelivery: deliveries},
+		providers: []sms.Provider{sms.NewMock(), &fakeSMSProvider{name: "netgsm"}},
+	}
+
+	errorCode := "30003"
+	deliveries.EXPECT().UpdateStatus("mock", "mock-1", sms.StatusUndelivered, &errorCode, nil).Return(true, nil)
+	body := `{"message_id":"mock-1","status":"undelivered","error_code":"30003"}`
+	if err := svc.HandleStatus("mock", httptest.NewRequest("POST", "/api/v1/sms/status/mock", strings.NewReader(body))); err != nil {
+		t.Fatal(err)
+	}
+
+	// Netgsm has no callbacks, and unconfigured providers are unknown
+	for _, name := range []string{"netgsm", "twilio"} {
+		if err := svc.HandleStatus(name, httptest.NewRequest("POST", "/", strings.NewReader(body))); !errors.Is(err, ErrUnknownSMSProvider) {
+			t.Errorf("%s: expected ErrUnknownSMSProvider, got %v", name, err)
+		}
+	}
+}
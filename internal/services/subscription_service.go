@@ -5,19 +5,25 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/yandas/backend/internal/config"
 	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/queue"
 	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/push"
 )
 
 // SubscriptionService handles subscription operations
 type SubscriptionService struct {
-	repos *repository.Repositories
-	cfg   *config.Config
+	repos         *repository.Repositories
+	cfg           *config.Config
+	monitoring    *MonitoringService
+	tokenVersions *TokenVersionCache
+	activity      *ActivityService
 }
 
-func NewSubscriptionService(repos *repository.Repositories, cfg *config.Config) *SubscriptionService {
-	return &SubscriptionService{repos: repos, cfg: cfg}
+func NewSubscriptionService(repos *repository.Repositories, cfg *config.Config, monitoring *MonitoringService, tokenVersions *TokenVersionCache, activity *ActivityService) *SubscriptionService {
+	return &SubscriptionService{repos: repos, cfg: cfg, monitoring: monitoring, tokenVersions: tokenVersions, activity: activity}
 }
 
 // Get returns user subscription
@@ -25,6 +31,97 @@ func (s *SubscriptionService) Get(userID uuid.UUID) (*models.Subscription, error
 	return s.repos.Subscription.GetByUserID(userID)
 }
 
+var (
+	ErrEntitlementRequired = unauthorizedError("entitlement_required", "your plan does not include this feature")
+	ErrServiceLimitReached = unauthorizedError("service_limit_reached", "active service limit reached for your plan")
+)
+
+// Entitlement names checked by RequireEntitlement
+const (
+	EntitlementServiceSlot       = "service_slot"
+	EntitlementPriorityListing   = "priority_listing"
+	EntitlementReducedCommission = "reduced_commission"
+)
+
+// Entitlements describes what a plan grants
+type Entitlements struct {
+	Plan              string  `json:"plan"` // free, monthly, yearly
+	MaxActiveServices int64   `json:"max_active_services"`
+	PriorityListing   bool    `json:"priority_listing"`
+	CommissionRate    float64 `json:"commission_rate"`
+}
+
+// PlanEntitlements maps plan types to what they grant. Users without an active subscription get "free".
+// Keep priority listing in sync with the ordering in YandasProfileRepository.ListPublic.
+var PlanEntitlements = map[string]Entitlements{
+	"free":    {Plan: "free", MaxActiveServices: 3, PriorityListing: false, CommissionRate: 0.15},
+	"monthly": {Plan: "monthly", MaxActiveServices: 20, PriorityListing: true, CommissionRate: 0.10},
+	"yearly":  {Plan: "yearly", MaxActiveServices: 20, PriorityListing: true, CommissionRate: 0.08},
+}
+
+// SubscriptionStatus combines a user's subscription with its entitlements
+type SubscriptionStatus struct {
+	*models.Subscription
+	Entitlements Entitlements `json:"entitlements"`
+}
+
+// Status returns the user's subscription, if any, and the entitlements it grants
+func (s *SubscriptionService) Status(userID uuid.UUID) *SubscriptionStatus {
+	sub, err := s.repos.Subscription.GetByUserID(userID)
+	if err != nil || !isSubscriptionCurrent(sub) {
+		sub = nil
+	}
+	return &SubscriptionStatus{Subscription: sub, Entitlements: entitlementsFor(sub)}
+}
+
+// Entitlements returns what the user's current plan grants
+func (s *SubscriptionService) Entitlements(userID uuid.UUID) Entitlements {
+	return s.Status(userID).Entitlements
+}
+
+// RequireEntitlement returns an error unless the user's plan grants the entitlement
+func (s *SubscriptionService) RequireEntitlement(userID uuid.UUID, entitlement string) error {
+	ent := s.Entitlements(userID)
+
+	switch entitlement {
+	case EntitlementServiceSlot:
+		profile, err := s.repos.YandasProfile.GetByUserID(userID)
+		if err != nil {
+			return ErrYandasProfileNotFound
+		}
+		if s.repos.Service.CountActiveByYandasID(profile.ID) >= ent.MaxActiveServices {
+			return ErrServiceLimitReached
+		}
+		return nil
+	case EntitlementPriorityListing:
+		if ent.PriorityListing {
+			return nil
+		}
+	case EntitlementReducedCommission:
+		if ent.CommissionRate < PlanEntitlements["free"].CommissionRate {
+			return nil
+		}
+	}
+
+	return ErrEntitlementRequired
+}
+
+func isSubscriptionCurrent(sub *models.Subscription) bool {
+	if sub == nil || sub.Status != "active" {
+		return false
+	}
+	return sub.CurrentPeriodEnd == nil || sub.CurrentPeriodEnd.After(time.Now())
+}
+
+func entitlementsFor(sub *models.Subscription) Entitlements {
+	if sub != nil {
+		if ent, ok := PlanEntitlements[sub.PlanType]; ok {
+			return ent
+		}
+	}
+	return PlanEntitlements["free"]
+}
+
 // VerifyInput represents subscription verification data from RevenueCat
 type VerifyInput struct {
 	ReceiptData  string `json:"receipt_data" binding:"required"`
@@ -51,40 +148,52 @@ func (s *SubscriptionService) Verify(userID uuid.UUID, input *VerifyInput) (*mod
 		periodEnd = now.AddDate(1, 0, 0)
 	}
 
-	// Check if subscription exists
-	existing, _ := s.repos.Subscription.GetByUserID(userID)
-	if existing != nil {
-		existing.PlanType = planType
-		existing.CurrentPeriodStart = &now
-		existing.CurrentPeriodEnd = &periodEnd
-		existing.Status = "active"
-		if err := s.repos.Subscription.Update(existing); err != nil {
-			return nil, err
+	// The user row lock serializes concurrent verifications and webhooks, so
+	// two requests can't both find no subscription and create one each
+	var sub *models.Subscription
+	created := false
+	err := s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.User.Lock(userID); err != nil {
+			return ErrUserNotFound
+		}
+		if existing, err := tx.Subscription.GetByUserID(userID); err == nil {
+			existing.PlanType = planType
+			existing.CurrentPeriodStart = &now
+			existing.CurrentPeriodEnd = &periodEnd
+			existing.Status = "active"
+			sub = existing
+			return tx.Subscription.Update(existing)
 		}
-		return existing, nil
-	}
-
-	sub := &models.Subscription{
-		UserID:             userID,
-		PlanType:           planType,
-		Status:             "active",
-		Provider:           "revenuecat",
-		CurrentPeriodStart: &now,
-		CurrentPeriodEnd:   &periodEnd,
-	}
 
-	if err := s.repos.Subscription.Create(sub); err != nil {
+		sub = &models.Subscription{
+			UserID:             userID,
+			PlanType:           planType,
+			Status:             "active",
+			Provider:           "revenuecat",
+			CurrentPeriodStart: &now,
+			CurrentPeriodEnd:   &periodEnd,
+		}
+		created = true
+		return tx.Subscription.Create(sub)
+	})
+	if err != nil {
 		return nil, err
 	}
+	if !created {
+		return sub, nil
+	}
 
 	// Update user role to yandas if not already
-	user, _ := s.repos.User.GetByID(userID)
+	user, _ := s.repos.OnPrimary().User.GetByID(userID)
 	if user != nil && user.Role == "customer" {
 		// Only if they have an approved yandas profile
 		profile, _ := s.repos.YandasProfile.GetByUserID(userID)
 		if profile != nil && profile.ApprovalStatus == "approved" {
 			user.Role = "yandas"
-			s.repos.User.Update(user)
+			user.TokenVersion++
+			if s.repos.User.Update(user) == nil {
+				s.tokenVersions.Invalidate(user.ID)
+			}
 		}
 	}
 
@@ -102,8 +211,16 @@ type WebhookPayload struct {
 	} `json:"event"`
 }
 
-// HandleWebhook handles RevenueCat webhook
+// HandleWebhook handles RevenueCat webhook; failures are counted for monitoring
 func (s *SubscriptionService) HandleWebhook(payload []byte) error {
+	err := s.handleWebhook(payload)
+	if err != nil {
+		s.monitoring.Record(MetricPaymentWebhookErrors)
+	}
+	return err
+}
+
+func (s *SubscriptionService) handleWebhook(payload []byte) error {
 	var webhook WebhookPayload
 	if err := json.Unmarshal(payload, &webhook); err != nil {
 		return err
@@ -114,35 +231,63 @@ func (s *SubscriptionService) HandleWebhook(payload []byte) error {
 		return err
 	}
 
-	sub, err := s.repos.Subscription.GetByUserID(userID)
-	if err != nil {
-		return nil // No subscription to update
-	}
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.User.Lock(userID); err != nil {
+			return nil // Unknown user, nothing to update
+		}
+		sub, err := tx.Subscription.GetByUserID(userID)
+		if err != nil {
+			return nil // No subscription to update
+		}
 
-	switch webhook.Event.Type {
-	case "INITIAL_PURCHASE", "RENEWAL":
-		expiration := time.UnixMilli(webhook.Event.ExpirationAtMs)
-		sub.Status = "active"
-		sub.CurrentPeriodEnd = &expiration
-	case "CANCELLATION":
-		now := time.Now()
-		sub.Status = "cancelled"
-		sub.CancelledAt = &now
-	case "EXPIRATION":
-		sub.Status = "expired"
-	}
+		switch webhook.Event.Type {
+		case "INITIAL_PURCHASE", "RENEWAL":
+			expiration := time.UnixMilli(webhook.Event.ExpirationAtMs)
+			sub.Status = "active"
+			sub.CurrentPeriodEnd = &expiration
+		case "CANCELLATION":
+			now := time.Now()
+			sub.Status = "cancelled"
+			sub.CancelledAt = &now
+		case "EXPIRATION":
+			sub.Status = "expired"
+		}
 
-	return s.repos.Subscription.Update(sub)
+		if err := tx.Subscription.Update(sub); err != nil {
+			return err
+		}
+		return s.activity.Record(tx, ActivityPaymentWebhook, sub.ID, &userID, "RevenueCat "+webhook.Event.Type, map[string]interface{}{
+			"event_type": webhook.Event.Type,
+			"product_id": webhook.Event.ProductID,
+			"status":     sub.Status,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	s.activity.Flush()
+	return nil
 }
 
 // NotificationService handles notification operations
 type NotificationService struct {
-	repos *repository.Repositories
-	cfg   *config.Config
+	repos  *repository.Repositories
+	cfg    *config.Config
+	redis  *redis.Client
+	email  *EmailService
+	jobs   *queue.Queue
+	outbox *Outbox
+	pusher push.Sender
+	voip   push.VoIPSender
 }
 
-func NewNotificationService(repos *repository.Repositories, cfg *config.Config) *NotificationService {
-	return &NotificationService{repos: repos, cfg: cfg}
+func NewNotificationService(repos *repository.Repositories, cfg *config.Config, redis *redis.Client, email *EmailService, jobs *queue.Queue, outbox *Outbox) *NotificationService {
+	s := &NotificationService{repos: repos, cfg: cfg, redis: redis, email: email, jobs: jobs, outbox: outbox}
+	if cfg != nil {
+		s.pusher = push.NewSender(cfg.FCMServerKey)
+		s.voip = push.NewVoIPSender(cfg.APNSVoIPProvider)
+	}
+	return s
 }
 
 func (s *NotificationService) List(userID uuid.UUID, page, limit int) ([]models.Notification, int64, error) {
@@ -159,7 +304,50 @@ func (s *NotificationService) MarkAllAsRead(userID uuid.UUID) error {
 
 // Send creates a notification and sends push
 func (s *NotificationService) Send(userID uuid.UUID, title, body, notifType string, data map[string]interface{}) error {
+	err := s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		return s.SendTx(tx, userID, title, body, notifType, data)
+	})
+	if err != nil {
+		return err
+	}
+	s.Flush()
+	return nil
+}
+
+// Flush wakes the outbox dispatcher after a transaction that called SendTx
+// commits. It is safe to call on a nil service.
+func (s *NotificationService) Flush() {
+	if s == nil {
+		return
+	}
+	s.outbox.Flush()
+}
+
+// SendTx creates the in-app notification through tx and queues its push in
+// the outbox, so both commit or roll back with the caller's changes. Callers
+// flush the outbox after commit. It is safe to call on a nil service.
+func (s *NotificationService) SendTx(tx *repository.Repositories, userID uuid.UUID, title, body, notifType string, data map[string]interface{}) error {
+	if s == nil {
+		return nil
+	}
+
 	// Create in-app notification
+	if err := tx.Notification.Create(newNotification(userID, title, body, notifType, data)); err != nil {
+		return err
+	}
+
+	// Send push notification unless the user opted out of this type
+	if !s.IsChannelEnabled(userID, notifType, NotificationChannelPush) {
+		return nil
+	}
+	if s.outbox == nil {
+		s.jobs.Enqueue(JobSendPush, pushJob{UserID: userID, Title: title, Body: body, Data: data})
+		return nil
+	}
+	return s.outbox.Push(tx, userID, notifType, title, body, data)
+}
+
+func newNotification(userID uuid.UUID, title, body, notifType string, data map[string]interface{}) *models.Notification {
 	var dataStr *string
 	if data != nil {
 		dataBytes, _ := json.Marshal(data)
@@ -167,30 +355,149 @@ func (s *NotificationService) Send(userID uuid.UUID, title, body, notifType stri
 		dataStr = &str
 	}
 
-	notif := &models.Notification{
+	return &models.Notification{
 		UserID: userID,
 		Title:  title,
 		Body:   body,
 		Type:   notifType,
 		Data:   dataStr,
 	}
+}
 
-	if err := s.repos.Notification.Create(notif); err != nil {
-		return err
+// Notification delivery channels
+const (
+	NotificationChannelPush  = "push"
+	NotificationChannelEmail = "email"
+	NotificationChannelSMS   = "sms"
+)
+
+// Notification types, each with its own preferences per channel
+const (
+	NotificationTypeOrder     = "order"
+	NotificationTypeChat      = "chat"
+	NotificationTypeCall      = "call"
+	NotificationTypeFavorite  = "favorite"
+	NotificationTypePromotion = "promotion"
+	NotificationTypeSystem    = "system"
+)
+
+// NotificationTypes lists the notification types users can configure
+var NotificationTypes = []string{
+	NotificationTypeOrder, NotificationTypeChat, NotificationTypeCall,
+	NotificationTypeFavorite, NotificationTypePromotion, NotificationTypeSystem,
+}
+
+// GetPreferences returns the user's preferences for every notification type,
+// filling in enabled defaults for types the user never changed
+func (s *NotificationService) GetPreferences(userID uuid.UUID) ([]models.NotificationPreference, error) {
+	stored, err := s.repos.NotificationPreference.ListByUser(userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Send push notification
-	go s.sendPush(userID, title, body, data)
+	byType := make(map[string]models.NotificationPreference, len(stored))
+	for _, pref := range stored {
+		byType[pref.Type] = pref
+	}
 
-	return nil
+	prefs := make([]models.NotificationPreference, 0, len(NotificationTypes))
+	for _, notifType := range NotificationTypes {
+		pref, ok := byType[notifType]
+		if !ok {
+			pref = defaultNotificationPreference(userID, notifType)
+		}
+		prefs = append(prefs, pref)
+	}
+
+	return prefs, nil
+}
+
+// NotificationPreferenceInput represents a preference change for one notification type.
+// Channels left out of the request keep their current value.
+type NotificationPreferenceInput struct {
+	Type   string `json:"type" binding:"required,oneof=order chat call favorite promotion system"`
+	Push   *bool  `json:"push"`
+	Email  *bool  `json:"email"`
+	SMS    *bool  `json:"sms"`
+	Batch  *bool  `json:"batch"`
+	Digest *bool  `json:"digest"`
+}
+
+// UpdatePreferencesInput represents a batch of preference changes
+type UpdatePreferencesInput struct {
+	Preferences []NotificationPreferenceInput `json:"preferences" binding:"required,dive"`
 }
 
-func (s *NotificationService) sendPush(userID uuid.UUID, title, body string, data map[string]interface{}) {
-	tokens, err := s.repos.DeviceToken.GetByUserID(userID)
+// UpdatePreferences applies preference changes and returns the full preference set
+func (s *NotificationService) UpdatePreferences(userID uuid.UUID, input *UpdatePreferencesInput) ([]models.NotificationPreference, error) {
+	for _, change := range input.Preferences {
+		pref, err := s.repos.NotificationPreference.GetByUserAndType(userID, change.Type)
+		if err != nil {
+			defaults := defaultNotificationPreference(userID, change.Type)
+			pref = &defaults
+		}
+
+		if change.Push != nil {
+			pref.PushEnabled = *change.Push
+		}
+		if change.Email != nil {
+			pref.EmailEnabled = *change.Email
+		}
+		if change.SMS != nil {
+			pref.SMSEnabled = *change.SMS
+		}
+		if change.Batch != nil {
+			pref.BatchEnabled = *change.Batch
+		}
+		if change.Digest != nil {
+			pref.DigestEnabled = *change.Digest
+		}
+
+		if err := s.repos.NotificationPreference.Upsert(pref); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetPreferences(userID)
+}
+
+// IsChannelEnabled reports whether a notification of the given type may be sent over a channel.
+// Types without a stored preference are enabled by default.
+func (s *NotificationService) IsChannelEnabled(userID uuid.UUID, notifType, channel string) bool {
+	pref, err := s.repos.NotificationPreference.GetByUserAndType(userID, notifType)
 	if err != nil {
-		return
+		return true
 	}
 
-	// TODO: Send via FCM
-	_ = tokens
+	switch channel {
+	case NotificationChannelPush:
+		return pref.PushEnabled
+	case NotificationChannelEmail:
+		return pref.EmailEnabled
+	case NotificationChannelSMS:
+		return pref.SMSEnabled
+	}
+	return true
+}
+
+// optedOut reports whether a user turned a channel off for the type of a
+// message queued for them. Messages without a user or type, like sign-in codes
+// and security notices, are always sent.
+func (s *NotificationService) optedOut(userID uuid.UUID, notifType, channel string) bool {
+	if userID == uuid.Nil || notifType == "" {
+		return false
+	}
+	return !s.IsChannelEnabled(userID, notifType, channel)
+}
+
+func defaultNotificationPreference(userID uuid.UUID, notifType string) models.NotificationPreference {
+	return models.NotificationPreference{
+		UserID:        userID,
+		Type:          notifType,
+		PushEnabled:   true,
+		EmailEnabled:  true,
+		SMSEnabled:    true,
+		BatchEnabled:  true,
+		DigestEnabled: true,
+	}
 }
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"gorm.io/gorm"
+)
+
+func TestRequireEntitlement(t *testing.T) {
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour)
+	monthly := &models.Subscription{PlanType: "monthly", Status: "active", CurrentPeriodEnd: &future}
+	yearly := &models.Subscription{PlanType: "yearly", Status: "active", CurrentPeriodEnd: &future}
+	lapsed := &models.Subscription{PlanType: "monthly", Status: "active", CurrentPeriodEnd: &past}
+	cancelled := &models.Subscription{PlanType: "yearly", Status: "cancelled", CurrentPeriodEnd: &future}
+
+	tests := []struct {
+		name         string
+		subscription *models.Subscription // nil: never subscribed
+		entitlement  string
+		services     int64
+		wantErr      error
+	}{
+		{"free, room for a service", nil, EntitlementServiceSlot, 2, nil},
+		{"free, at the service limit", nil, EntitlementServiceSlot, 3, ErrServiceLimitReached},
+		{"monthly, past the free limit", monthly, EntitlementServiceSlot, 3, nil},
+		{"monthly, at its limit", monthly, EntitlementServiceSlot, 20, ErrServiceLimitReached},
+		{"lapsed falls back to free", lapsed, EntitlementServiceSlot, 3, ErrServiceLimitReached},
+		{"free, priority listing", nil, EntitlementPriorityListing, 0, ErrEntitlementRequired},
+		{"monthly, priority listing", monthly, EntitlementPriorityListing, 0, nil},
+		{"cancelled, priority listing", cancelled, EntitlementPriorityListing, 0, ErrEntitlementRequired},
+		{"free, reduced commission", nil, EntitlementReducedCommission, 0, ErrEntitlementRequired},
+		{"yearly, reduced commission", yearly, EntitlementReducedCommission, 0, nil},
+		{"unknown entitlement", yearly, "white_label", 0, ErrEntitlementRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			subscriptions := mocks.NewMockSubscriptionRepository(ctrl)
+			profiles := mocks.NewMockYandasProfileRepository(ctrl)
+			services := mocks.NewMockServiceRepository(ctrl)
+			svc := NewSubscriptionService(&repository.Repositories{Subscription: subscriptions, YandasProfile: profiles, Service: services}, nil, nil, nil, nil)
+
+			userID, profileID := uuid.New(), uuid.New()
+			if tt.subscription != nil {
+				subscriptions.EXPECT().GetByUserID(userID).Return(tt.subscription, nil)
+			} else {
+				subscriptions.EXPECT().GetByUserID(userID).Return(nil, gorm.ErrRecordNotFound)
+			}
+			if tt.entitlement == EntitlementServiceSlot {
+				profiles.EXPECT().GetByUserID(userID).Return(&models.YandasProfile{ID: profileID}, nil)
+				services.EXPECT().CountActiveByYandasID(profileID).Return(tt.services)
+			}
+
+			if err := svc.RequireEntitlement(userID, tt.entitlement); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
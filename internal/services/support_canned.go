@@ -0,0 +1,102 @@
+package services
+
+import (
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+)
+
+var (
+	ErrCannedResponseNotFound = notFoundError("canned_response_not_found", "canned response not found")
+	ErrReplyContentRequired   = validationError("reply_content_required", "content or canned_response_id is required")
+)
+
+// CannedResponseInput represents canned response data
+type CannedResponseInput struct {
+	Title    string `json:"title" binding:"required,max=100"`
+	Content  string `json:"content" binding:"required"`
+	Category string `json:"category" binding:"omitempty,oneof=general order payment account technical"`
+}
+
+// SupportReplyInput is an agent's reply on a ticket. With a canned response and
+// no content the rendered template is sent; internal replies are notes only
+// agents can see.
+type SupportReplyInput struct {
+	Content          string     `json:"content"`
+	CannedResponseID *uuid.UUID `json:"canned_response_id"`
+	Internal         bool       `json:"internal"`
+}
+
+func (s *AdminService) ListCannedResponses(category string) ([]models.CannedResponse, error) {
+	return s.repos.Support.ListCannedResponses(category)
+}
+
+func (s *AdminService) CreateCannedResponse(adminID uuid.UUID, input *CannedResponseInput) (*models.CannedResponse, error) {
+	response := &models.CannedResponse{CreatedBy: adminID}
+	applyCannedResponseInput(response, input)
+	if err := s.repos.Support.CreateCannedResponse(response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *AdminService) UpdateCannedResponse(id uuid.UUID, input *CannedResponseInput) (*models.CannedResponse, error) {
+	response, err := s.repos.Support.GetCannedResponse(id)
+	if err != nil {
+		return nil, ErrCannedResponseNotFound
+	}
+	applyCannedResponseInput(response, input)
+	if err := s.repos.Support.UpdateCannedResponse(response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func applyCannedResponseInput(response *models.CannedResponse, input *CannedResponseInput) {
+	response.Title = input.Title
+	response.Content = input.Content
+	response.Category = nil
+	if input.Category != "" {
+		category := input.Category
+		response.Category = &category
+	}
+}
+
+func (s *AdminService) DeleteCannedResponse(id uuid.UUID) error {
+	if _, err := s.repos.Support.GetCannedResponse(id); err != nil {
+		return ErrCannedResponseNotFound
+	}
+	return s.repos.Support.DeleteCannedResponse(id)
+}
+
+// replyContent resolves what an agent's reply says, rendering the canned response when one is chosen
+func (s *AdminService) replyContent(ticket *models.SupportTicket, input *SupportReplyInput) (string, error) {
+	content := strings.TrimSpace(input.Content)
+	if input.CannedResponseID != nil {
+		canned, err := s.repos.Support.GetCannedResponse(*input.CannedResponseID)
+		if err != nil {
+			return "", ErrCannedResponseNotFound
+		}
+		if content == "" {
+			content = renderCannedResponse(canned.Content, ticket)
+		}
+		if err := s.repos.Support.IncrementCannedResponseUsage(canned.ID); err != nil {
+			log.Printf("[SUPPORT] failed to count use of canned response %s: %v", canned.ID, err)
+		}
+	}
+	if content == "" {
+		return "", ErrReplyContentRequired
+	}
+	return content, nil
+}
+
+// renderCannedResponse fills the {name} and {subject} placeholders for a ticket
+func renderCannedResponse(template string, ticket *models.SupportTicket) string {
+	name := ""
+	if ticket.User != nil {
+		name = ticket.User.FullName
+	}
+	return strings.NewReplacer("{name}", name, "{subject}", ticket.Subject).Replace(template)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestAdminReplyWithCannedResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	support := mocks.NewMockSupportRepository(ctrl)
+	svc := NewAdminService(&repository.Repositories{Support: support}, nil, nil, nil, nil, nil, nil)
+
+	ticket := &models.SupportTicket{ID: uuid.New(), Subject: "İade talebi", Status: "open", User: &models.User{FullName: "Ayşe Yılmaz"}}
+	canned := &models.CannedResponse{ID: uuid.New(), Content: "Merhaba {name}, \"{subject}\" talebinizi inceliyoruz."}
+
+	support.EXPECT().GetTicket(ticket.ID).Return(ticket, nil)
+	support.EXPECT().GetCannedResponse(canned.ID).Return(canned, nil)
+	support.EXPECT().IncrementCannedResponseUsage(canned.ID).Return(nil)
+	support.EXPECT().CreateMessage(gomock.Any()).Return(nil)
+	support.EXPECT().UpdateTicket(ticket).Return(nil)
+
+	message, err := svc.ReplySupportTicket(ticket.ID, uuid.New(), &SupportReplyInput{CannedResponseID: &canned.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Merhaba Ayşe Yılmaz, \"İade talebi\" talebinizi inceliyoruz."; message.Content != want {
+		t.Errorf("expected %q, got %q", want, message.Content)
+	}
+	if ticket.Status != "pending" || ticket.FirstRespondedAt == nil {
+		t.Errorf("reply should answer the ticket: %+v", ticket)
+	}
+}
+
+func TestAdminInternalNoteLeavesTicketAlone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	support := mocks.NewMockSupportRepository(ctrl)
+	svc := NewAdminService(&repository.Repositories{Support: support}, nil, nil, nil, nil, nil, nil)
+
+	ticket := &models.SupportTicket{ID: uuid.New(), Status: "open"}
+	support.EXPECT().GetTicket(ticket.ID).Return(ticket, nil)
+	support.EXPECT().CreateMessage(gomock.Any()).DoAndReturn(func(m *models.SupportMessage) error {
+		if !m.IsInternal {
+			t.Error("note should be internal")
+		}
+		return nil
+	})
+
+	if _, err := svc.ReplySupportTicket(ticket.ID, uuid.New(), &SupportReplyInput{Content: "Kullanıcı daha önce de iade aldı", Internal: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ticket.Status != "open" || ticket.FirstRespondedAt != nil {
+		t.Errorf("internal note should not change the ticket: %+v", ticket)
+	}
+
+	support.EXPECT().GetTicket(ticket.ID).Return(ticket, nil)
+	if _, err := svc.ReplySupportTicket(ticket.ID, uuid.New(), &SupportReplyInput{Content: "  "}); err != ErrReplyContentRequired {
+		t.Errorf("expected ErrReplyContentRequired, got %v", err)
+	}
+}
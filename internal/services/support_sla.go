@@ -0,0 +1,163 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// SLAPolicy is how quickly a support ticket must get its first staff reply and be resolved
+type SLAPolicy struct {
+	FirstResponse time.Duration
+	Resolution    time.Duration
+}
+
+// SupportSLAPolicies maps ticket priority to its SLA targets. Migration
+// 000005_support_sla backfilled existing tickets with the same values.
+var SupportSLAPolicies = map[string]SLAPolicy{
+	"urgent": {FirstResponse: time.Hour, Resolution: 8 * time.Hour},
+	"high":   {FirstResponse: 4 * time.Hour, Resolution: 24 * time.Hour},
+	"normal": {FirstResponse: 8 * time.Hour, Resolution: 72 * time.Hour},
+	"low":    {FirstResponse: 24 * time.Hour, Resolution: 120 * time.Hour},
+}
+
+// SupportSLAInterval is how often overdue tickets are escalated
+const SupportSLAInterval = 5 * time.Minute
+
+// Overdue tickets escalated per run
+const escalationBatchSize = 100
+
+// ticketPriorities is the escalation ladder, lowest first
+var ticketPriorities = []string{"low", "normal", "high", "urgent"}
+
+func slaPolicy(priority string) SLAPolicy {
+	if policy, ok := SupportSLAPolicies[priority]; ok {
+		return policy
+	}
+	return SupportSLAPolicies["normal"]
+}
+
+// applySLA sets the ticket's deadlines for its priority, counted from start
+func applySLA(ticket *models.SupportTicket, start time.Time) {
+	policy := slaPolicy(ticket.Priority)
+	firstResponseDue := start.Add(policy.FirstResponse)
+	resolutionDue := start.Add(policy.Resolution)
+	ticket.FirstResponseDueAt = &firstResponseDue
+	ticket.ResolutionDueAt = &resolutionDue
+}
+
+// markFirstResponse records the first staff reply and whether it missed its deadline
+func markFirstResponse(ticket *models.SupportTicket, at time.Time) {
+	if ticket.FirstRespondedAt != nil {
+		return
+	}
+	ticket.FirstRespondedAt = &at
+	if ticket.FirstResponseDueAt != nil && at.After(*ticket.FirstResponseDueAt) {
+		ticket.FirstResponseBreached = true
+	}
+}
+
+// markResolved records the resolution and whether it missed its deadline
+func markResolved(ticket *models.SupportTicket, at time.Time) {
+	ticket.ResolvedAt = &at
+	if ticket.ResolutionDueAt != nil && at.After(*ticket.ResolutionDueAt) {
+		ticket.ResolutionBreached = true
+	}
+}
+
+// nextPriority returns the priority one step up the ladder; urgent stays urgent
+func nextPriority(priority string) string {
+	for i, p := range ticketPriorities {
+		if p == priority && i+1 < len(ticketPriorities) {
+			return ticketPriorities[i+1]
+		}
+	}
+	return "urgent"
+}
+
+// EscalateOverdue escalates tickets past an SLA deadline; the scheduler calls it every SupportSLAInterval
+func (s *SupportService) EscalateOverdue() {
+	now := time.Now()
+	tickets, err := s.repos.Support.ListOverdueTickets(now, escalationBatchSize)
+	if err != nil {
+		log.Printf("[SUPPORT] failed to load overdue tickets: %v", err)
+		return
+	}
+	for i := range tickets {
+		s.escalate(&tickets[i], now)
+	}
+}
+
+// escalate bumps the ticket's priority and restarts its deadlines at the new
+// priority, so it escalates again only if it stays overdue, then alerts admins
+func (s *SupportService) escalate(ticket *models.SupportTicket, now time.Time) {
+	stage := "Çözüm"
+	if ticket.FirstRespondedAt == nil && ticket.FirstResponseDueAt != nil && now.After(*ticket.FirstResponseDueAt) {
+		stage = "İlk yanıt"
+		ticket.FirstResponseBreached = true
+	} else {
+		ticket.ResolutionBreached = true
+	}
+
+	fromLevel := ticket.EscalationLevel
+	ticket.Priority = nextPriority(ticket.Priority)
+	ticket.EscalationLevel++
+	ticket.EscalatedAt = &now
+	applySLA(ticket, now)
+
+	escalated, err := s.repos.Support.EscalateTicket(ticket, fromLevel)
+	if err != nil {
+		log.Printf("[SUPPORT] failed to escalate ticket %s: %v", ticket.ID, err)
+		return
+	}
+	if !escalated {
+		return
+	}
+
+	title := "Destek talebi yükseltildi: " + ticket.Subject
+	body := fmt.Sprintf("%s süresi aşıldı. Yeni öncelik: %s.", stage, ticket.Priority)
+	data := map[string]interface{}{
+		"ticket_id":        ticket.ID.String(),
+		"priority":         ticket.Priority,
+		"escalation_level": ticket.EscalationLevel,
+	}
+	for _, userID := range s.escalationRecipients(ticket) {
+		if err := s.notifications.Send(userID, title, body, "system", data); err != nil {
+			log.Printf("[SUPPORT] failed to notify %s about ticket %s: %v", userID, ticket.ID, err)
+		}
+	}
+}
+
+// escalationRecipients returns the admins plus the ticket's assignee
+func (s *SupportService) escalationRecipients(ticket *models.SupportTicket) []uuid.UUID {
+	admins, _, err := s.repos.User.List(repository.UserFilter{Role: "admin"}, 1, 100)
+	if err != nil {
+		log.Printf("[SUPPORT] failed to load admins: %v", err)
+	}
+
+	recipients := make([]uuid.UUID, 0, len(admins)+1)
+	for _, admin := range admins {
+		recipients = append(recipients, admin.ID)
+	}
+	if ticket.AssignedTo != nil {
+		for _, id := range recipients {
+			if id == *ticket.AssignedTo {
+				return recipients
+			}
+		}
+		recipients = append(recipients, *ticket.AssignedTo)
+	}
+	return recipients
+}
+
+// slaCompliance returns the share of measured tickets that met a target, in percent
+func slaCompliance(met, breached int64) (int64, bool) {
+	if met+breached == 0 {
+		return 0, false
+	}
+	return met * 100 / (met + breached), true
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"gorm.io/gorm"
+)
+
+func TestSupportEscalateOverdue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	support := mocks.NewMockSupportRepository(ctrl)
+	users := mocks.NewMockUserRepository(ctrl)
+	notifications := mocks.NewMockNotificationRepository(ctrl)
+	prefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{Support: support, User: users, Notification: notifications, NotificationPreference: prefs, UnitOfWork: uow}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	svc := NewSupportService(repos, NewNotificationService(repos, nil, nil, nil, nil, nil), nil)
+
+	created := time.Now().Add(-10 * time.Hour)
+	ticket := models.SupportTicket{ID: uuid.New(), Subject: "Ödeme sorunu", Priority: "normal", Status: "open", CreatedAt: created}
+	applySLA(&ticket, created)
+	admin := models.User{ID: uuid.New()}
+	assignee := uuid.New()
+	ticket.AssignedTo = &assignee
+
+	support.EXPECT().ListOverdueTickets(gomock.Any(), escalationBatchSize).Return([]models.SupportTicket{ticket}, nil)
+	support.EXPECT().EscalateTicket(gomock.Any(), 0).DoAndReturn(func(escalated *models.SupportTicket, _ int) (bool, error) {
+		if escalated.Priority != "high" || escalated.EscalationLevel != 1 || !escalated.FirstResponseBreached {
+			t.Errorf("ticket not escalated correctly: %+v", escalated)
+		}
+		if escalated.FirstResponseDueAt == nil || !escalated.FirstResponseDueAt.After(time.Now()) {
+			t.Errorf("expected a fresh first response deadline, got %v", escalated.FirstResponseDueAt)
+		}
+		return true, nil
+	})
+	users.EXPECT().List(repository.UserFilter{Role: "admin"}, 1, 100).Return([]models.User{admin}, int64(1), nil)
+	notifications.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+	prefs.EXPECT().GetByUserAndType(gomock.Any(), "system").Return(nil, gorm.ErrRecordNotFound).Times(2)
+
+	svc.EscalateOverdue()
+}
+
+func TestSupportSLAMarks(t *testing.T) {
+	created := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	ticket := &models.SupportTicket{Priority: "urgent"}
+	applySLA(ticket, created)
+
+	markFirstResponse(ticket, created.Add(30*time.Minute))
+	markFirstResponse(ticket, created.Add(5*time.Hour))
+	if ticket.FirstResponseBreached || !ticket.FirstRespondedAt.Equal(created.Add(30*time.Minute)) {
+		t.Errorf("first response should be the earliest reply and within SLA: %+v", ticket)
+	}
+
+	markResolved(ticket, created.Add(9*time.Hour))
+	if !ticket.ResolutionBreached {
+		t.Error("resolution after 9h should breach the 8h urgent target")
+	}
+	if got := nextPriority("urgent"); got != "urgent" {
+		t.Errorf("urgent should stay urgent, got %s", got)
+	}
+}
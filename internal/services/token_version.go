@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// A short TTL bounds how long a lookup racing a bump can cache the old version
+const tokenVersionCacheTTL = 10 * time.Minute
+
+// TokenVersionCache serves users' current token versions for the auth middleware.
+// Services that change a user's role or status bump User.TokenVersion and call
+// Invalidate so tokens carrying the old version are rejected on the next request.
+type TokenVersionCache struct {
+	repos *repository.Repositories
+	redis *redis.Client
+}
+
+// NewTokenVersionCache creates a token version cache; without Redis every lookup hits the database
+func NewTokenVersionCache(repos *repository.Repositories, redis *redis.Client) *TokenVersionCache {
+	return &TokenVersionCache{repos: repos, redis: redis}
+}
+
+func tokenVersionKey(userID uuid.UUID) string {
+	return "token_version:" + userID.String()
+}
+
+// Get returns the user's current token version
+func (c *TokenVersionCache) Get(userID uuid.UUID) (int, error) {
+	ctx := context.Background()
+	if c.redis != nil {
+		if cached, err := c.redis.Get(ctx, tokenVersionKey(userID)).Result(); err == nil {
+			if version, err := strconv.Atoi(cached); err == nil {
+				return version, nil
+			}
+		}
+	}
+
+	// Read from the primary so a version bumped moments ago is never cached stale from a replica
+	user, err := c.repos.OnPrimary().User.GetByID(userID)
+	if err != nil {
+		return 0, err
+	}
+	if c.redis != nil {
+		c.redis.Set(ctx, tokenVersionKey(userID), user.TokenVersion, tokenVersionCacheTTL)
+	}
+	return user.TokenVersion, nil
+}
+
+// Invalidate drops cached versions after they were bumped in the database
+func (c *TokenVersionCache) Invalidate(userIDs ...uuid.UUID) {
+	if c == nil || c.redis == nil || len(userIDs) == 0 {
+		return
+	}
+	keys := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		keys[i] = tokenVersionKey(id)
+	}
+	c.redis.Del(context.Background(), keys...)
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// Product analytics event names
+const (
+	TrackScreenView   = "screen_view"
+	TrackSearch       = "search"
+	TrackProfileView  = "profile_view"
+	TrackOrderStarted = "order_started"
+)
+
+// FunnelSteps is the search to order funnel the admin report follows
+var FunnelSteps = []string{TrackSearch, TrackProfileView, TrackOrderStarted}
+
+const (
+	// maxEventProperties bounds the encoded properties of one event
+	maxEventProperties = 4096
+	// Events stamped further ahead than this are taken as received now;
+	// events older than maxEventAge, e.g. from a long-offline app, are dropped
+	maxEventClockSkew = 5 * time.Minute
+	maxEventAge       = 7 * 24 * time.Hour
+	// trackingRetention is how long raw events are kept
+	trackingRetention = 180 * 24 * time.Hour
+)
+
+var (
+	ErrTrackingActorRequired = validationError("tracking_actor_required", "anonymous_id is required when not signed in")
+	ErrEventPropertiesTooBig = validationError("event_properties_too_large", "event properties must be at most 4 KB")
+)
+
+// EventSink receives accepted analytics events. The funnel reports read the
+// analytics_events table, so a sink that sends events elsewhere should also
+// store them.
+type EventSink interface {
+	Write(events []models.AnalyticsEvent) error
+}
+
+// databaseSink stores events in the analytics_events table
+type databaseSink struct {
+	repos *repository.Repositories
+}
+
+func (d databaseSink) Write(events []models.AnalyticsEvent) error {
+	return d.repos.Analytics.RecordEvents(events)
+}
+
+// TrackingService ingests product analytics events from the apps and reports
+// funnels over them
+type TrackingService struct {
+	repos *repository.Repositories
+	sink  EventSink
+}
+
+// NewTrackingService creates a tracking service; a nil sink stores events in the database
+func NewTrackingService(repos *repository.Repositories, sink EventSink) *TrackingService {
+	if sink == nil {
+		sink = databaseSink{repos: repos}
+	}
+	return &TrackingService{repos: repos, sink: sink}
+}
+
+// TrackEventsInput is a batch of events from one app install
+type TrackEventsInput struct {
+	AnonymousID string            `json:"anonymous_id" binding:"omitempty,max=64"` // the app's install ID, kept across sign-in
+	SessionID   string            `json:"session_id" binding:"omitempty,max=64"`
+	Platform    string            `json:"platform" binding:"omitempty,oneof=ios android web"`
+	AppVersion  string            `json:"app_version" binding:"omitempty,max=20"`
+	Events      []TrackEventInput `json:"events" binding:"required,min=1,max=100,dive"`
+}
+
+// TrackEventInput is one event; occurred_at defaults to when it was received
+type TrackEventInput struct {
+	Name       string                 `json:"name" binding:"required,oneof=screen_view search profile_view order_started"`
+	Screen     string                 `json:"screen" binding:"omitempty,max=100"`
+	YandasID   *uuid.UUID             `json:"yandas_id"`
+	Properties map[string]interface{} `json:"properties"`
+	OccurredAt *time.Time             `json:"occurred_at"`
+}
+
+// Track stores a batch of events and returns how many were accepted. Events
+// are attributed to the install when it sends its ID, so a funnel started
+// before sign-in continues after it; userID is nil for signed-out requests.
+func (s *TrackingService) Track(userID *uuid.UUID, input *TrackEventsInput) (int, error) {
+	actor := input.AnonymousID
+	if actor == "" && userID != nil {
+		actor = userID.String()
+	}
+	if actor == "" {
+		return 0, ErrTrackingActorRequired
+	}
+
+	now := time.Now()
+	events := make([]models.AnalyticsEvent, 0, len(input.Events))
+	for _, e := range input.Events {
+		occurredAt := now
+		if e.OccurredAt != nil && !e.OccurredAt.After(now.Add(maxEventClockSkew)) {
+			occurredAt = *e.OccurredAt
+		}
+		if now.Sub(occurredAt) > maxEventAge {
+			continue
+		}
+
+		event := models.AnalyticsEvent{
+			Name:       e.Name,
+			Actor:      actor,
+			UserID:     userID,
+			SessionID:  input.SessionID,
+			Platform:   input.Platform,
+			AppVersion: input.AppVersion,
+			Screen:     e.Screen,
+			YandasID:   e.YandasID,
+			OccurredAt: occurredAt,
+		}
+		if len(e.Properties) > 0 {
+			data, err := json.Marshal(e.Properties)
+			if err != nil || len(data) > maxEventProperties {
+				return 0, ErrEventPropertiesTooBig
+			}
+			properties := string(data)
+			event.Properties = &properties
+		}
+		events = append(events, event)
+	}
+
+	if len(events) == 0 {
+		return 0, nil
+	}
+	if err := s.sink.Write(events); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+// FunnelReportStep is one step of a funnel with its conversion from the
+// previous step and from the first
+type FunnelReportStep struct {
+	repository.FunnelStep
+	StepConversion    float64 `json:"step_conversion"`
+	OverallConversion float64 `json:"overall_conversion"`
+}
+
+// FunnelReport is the search to order funnel over a date range
+type FunnelReport struct {
+	From     string             `json:"from"` // YYYY-MM-DD
+	To       string             `json:"to"`   // YYYY-MM-DD, inclusive
+	Platform string             `json:"platform,omitempty"`
+	Steps    []FunnelReportStep `json:"steps"`
+}
+
+// Funnel reports the actors who searched in [from, to) and went on to view a
+// profile and start an order; platform narrows it to one app when set
+func (s *TrackingService) Funnel(from, to time.Time, platform string) (*FunnelReport, error) {
+	rows, err := s.repos.Analytics.Funnel(FunnelSteps, from, to, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &FunnelReport{
+		From:     from.Format("2006-01-02"),
+		To:       to.AddDate(0, 0, -1).Format("2006-01-02"),
+		Platform: platform,
+		Steps:    make([]FunnelReportStep, 0, len(rows)),
+	}
+	for i, row := range rows {
+		step := FunnelReportStep{FunnelStep: row}
+		if i == 0 {
+			if row.Actors > 0 {
+				step.StepConversion, step.OverallConversion = 1, 1
+			}
+		} else {
+			step.StepConversion = ratio(row.Actors, rows[i-1].Actors)
+			step.OverallConversion = ratio(row.Actors, rows[0].Actors)
+		}
+		report.Steps = append(report.Steps, step)
+	}
+	return report, nil
+}
+
+// Prune deletes raw events past trackingRetention; the scheduler calls it daily
+func (s *TrackingService) Prune() {
+	removed, err := s.repos.Analytics.PruneEvents(time.Now().Add(-trackingRetention))
+	if err != nil {
+		log.Printf("[TRACKING] failed to prune events: %v", err)
+		return
+	}
+	if removed > 0 {
+		log.Printf("[TRACKING] pruned %d events", removed)
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func newTestTrackingService(t *testing.T) (*TrackingService, *mocks.MockAnalyticsRepository) {
+	ctrl := gomock.NewController(t)
+	analytics := mocks.NewMockAnalyticsRepository(ctrl)
+	return NewTrackingService(&repository.Repositories{Analytics: analytics}, nil), analytics
+}
+
+func TestTrackAttributesEventsToTheInstall(t *testing.T) {
+	svc, analytics := newTestTrackingService(t)
+	userID := uuid.New()
+	yandasID := uuid.New()
+	now := time.Now()
+	earlier, stale, future := now.Add(-time.Hour), now.Add(-8*24*time.Hour), now.Add(time.Hour)
+
+	analytics.EXPECT().RecordEvents(gomock.Any()).DoAndReturn(func(events []models.AnalyticsEvent) error {
+		if len(events) != 3 {
+			t.Fatalf("expected the stale event to be dropped, got %d events", len(events))
+		}
+		for _, e := range events {
+			if e.Actor != "install-1" || e.UserID == nil || *e.UserID != userID || e.Platform != "ios" {
+				t.Errorf("unexpected attribution %+v", e)
+			}
+		}
+		if !events[0].OccurredAt.Equal(earlier) || events[1].OccurredAt.After(now.Add(time.Minute)) {
+			t.Errorf("unexpected times %s and %s", events[0].OccurredAt, events[1].OccurredAt)
+		}
+		if events[2].YandasID == nil || *events[2].YandasID != yandasID || events[2].Properties == nil || !strings.Contains(*events[2].Properties, `"source":"search"`) {
+			t.Errorf("unexpected profile view %+v", events[2])
+		}
+		return nil
+	})
+
+	accepted, err := svc.Track(&userID, &TrackEventsInput{
+		AnonymousID: "install-1",
+		Platform:    "ios",
+		Events: []TrackEventInput{
+			{Name: TrackSearch, OccurredAt: &earlier},
+			{Name: TrackScreenView, Screen: "home", OccurredAt: &future},
+			{Name: TrackScreenView, OccurredAt: &stale},
+			{Name: TrackProfileView, YandasID: &yandasID, Properties: map[string]interface{}{"source": "search"}},
+		},
+	})
+	if err != nil || accepted != 3 {
+		t.Fatalf("expected 3 accepted events, got %d, %v", accepted, err)
+	}
+}
+
+func TestTrackNeedsAnActor(t *testing.T) {
+	svc, analytics := newTestTrackingService(t)
+
+	if _, err := svc.Track(nil, &TrackEventsInput{Events: []TrackEventInput{{Name: TrackSearch}}}); !errors.Is(err, ErrTrackingActorRequired) {
+		t.Fatalf("expected ErrTrackingActorRequired, got %v", err)
+	}
+
+	big := map[string]interface{}{"query": strings.Repeat("a", maxEventProperties)}
+	if _, err := svc.Track(nil, &TrackEventsInput{AnonymousID: "install-2", Events: []TrackEventInput{{Name: TrackSearch, Properties: big}}}); !errors.Is(err, ErrEventPropertiesTooBig) {
+		t.Fatalf("expected ErrEventPropertiesTooBig, got %v", err)
+	}
+
+	// Signed in without an install ID, the user is the actor
+	userID := uuid.New()
+	analytics.EXPECT().RecordEvents(gomock.Any()).DoAndReturn(func(events []models.AnalyticsEvent) error {
+		if events[0].Actor != userID.String() {
+			t.Errorf("expected the user as actor, got %q", events[0].Actor)
+		}
+		return nil
+	})
+	if _, err := svc.Track(&userID, &TrackEventsInput{Events: []TrackEventInput{{Name: TrackOrderStarted}}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFunnelConversions(t *testing.T) {
+	svc, analytics := newTestTrackingService(t)
+	from := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 30)
+
+	analytics.EXPECT().Funnel(FunnelSteps, from, to, "android").Return([]repository.FunnelStep{
+		{Step: 1, Name: TrackSearch, Actors: 400},
+		{Step: 2, Name: TrackProfileView, Actors: 100},
+		{Step: 3, Name: TrackOrderStarted, Actors: 20},
+	}, nil)
+
+	report, err := svc.Funnel(from, to, "android")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.From != "2026-09-01" || report.To != "2026-09-30" || len(report.Steps) != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if s := report.Steps[0]; s.StepConversion != 1 || s.OverallConversion != 1 {
+		t.Errorf("unexpected first step %+v", s)
+	}
+	if s := report.Steps[2]; s.StepConversion != 0.2 || s.OverallConversion != 0.05 {
+		t.Errorf("unexpected last step %+v", s)
+	}
+}
@@ -1,8 +1,6 @@
 package services
 
 import (
-	"errors"
-
 	"github.com/google/uuid"
 	"github.com/yandas/backend/internal/config"
 	"github.com/yandas/backend/internal/models"
@@ -29,29 +27,27 @@ func (s *UserService) GetProfile(userID uuid.UUID) (*models.User, error) {
 // UpdateProfileInput represents profile update data
 type UpdateProfileInput struct {
 	FullName string `json:"full_name"`
-	Phone    string `json:"phone"`
+	Phone    string `json:"phone" binding:"omitempty,tr_phone"`
+	Version  *int   `json:"version"` // the version last read; the update is refused if it changed since
 }
 
 // UpdateProfile updates user profile
 func (s *UserService) UpdateProfile(userID uuid.UUID, input *UpdateProfileInput) (*models.User, error) {
-	user, err := s.repos.User.GetByID(userID)
+	user, err := s.repos.OnPrimary().User.GetByID(userID)
 	if err != nil {
 		return nil, err
 	}
 
+	if input.Version != nil {
+		user.Version = *input.Version
+	}
 	if input.FullName != "" {
 		user.FullName = input.FullName
 	}
 
-	if input.Phone != "" {
-		// Check if phone is already taken
-		if s.repos.User.ExistsByPhone(input.Phone) {
-			existing, _ := s.repos.User.GetByPhone(input.Phone)
-			if existing != nil && existing.ID != userID {
-				return nil, errors.New("phone number already in use")
-			}
-		}
-		user.Phone = &input.Phone
+	// Phone numbers change only through the OTP-verified phone change flow
+	if input.Phone != "" && (user.Phone == nil || *user.Phone != input.Phone) {
+		return nil, ErrPhoneChangeNeedsVerification
 	}
 
 	if err := s.repos.User.Update(user); err != nil {
@@ -63,7 +59,7 @@ func (s *UserService) UpdateProfile(userID uuid.UUID, input *UpdateProfileInput)
 
 // UpdateAvatar updates user avatar
 func (s *UserService) UpdateAvatar(userID uuid.UUID, avatarURL string) error {
-	user, err := s.repos.User.GetByID(userID)
+	user, err := s.repos.OnPrimary().User.GetByID(userID)
 	if err != nil {
 		return err
 	}
@@ -80,14 +76,14 @@ type ChangePasswordInput struct {
 
 // ChangePassword changes user password
 func (s *UserService) ChangePassword(userID uuid.UUID, input *ChangePasswordInput) error {
-	user, err := s.repos.User.GetByID(userID)
+	user, err := s.repos.OnPrimary().User.GetByID(userID)
 	if err != nil {
 		return err
 	}
 
 	// Verify current password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.CurrentPassword)); err != nil {
-		return errors.New("current password is incorrect")
+		return ErrIncorrectPassword
 	}
 
 	// Hash new password
@@ -109,13 +105,129 @@ func (s *UserService) DeleteAccount(userID uuid.UUID) error {
 	return s.repos.User.HardDelete(userID)
 }
 
-// RegisterDeviceToken registers a device token for push notifications
-func (s *UserService) RegisterDeviceToken(userID uuid.UUID, token, platform string) error {
+// RegisterDeviceToken registers a device token for push notifications, tied to the
+// session it was registered from so revoking the session silences the device
+func (s *UserService) RegisterDeviceToken(userID uuid.UUID, sessionID *uuid.UUID, token, platform string) error {
 	deviceToken := &models.DeviceToken{
-		UserID:   userID,
-		Token:    token,
-		Platform: platform,
-		IsActive: true,
+		UserID:    userID,
+		Token:     token,
+		Platform:  platform,
+		SessionID: sessionID,
+		IsActive:  true,
 	}
 	return s.repos.DeviceToken.Create(deviceToken)
 }
+
+// AddressInput represents saved address data
+type AddressInput struct {
+	Label       string   `json:"label" binding:"required,max=50"`
+	AddressText string   `json:"address_text" binding:"required"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+	City        string   `json:"city"`
+	IsDefault   bool     `json:"is_default"`
+}
+
+// ListAddresses returns the user's saved addresses, default first
+func (s *UserService) ListAddresses(userID uuid.UUID) ([]models.Address, error) {
+	return s.repos.Address.ListByUser(userID)
+}
+
+// GetAddress returns a saved address owned by the user
+func (s *UserService) GetAddress(userID, addressID uuid.UUID) (*models.Address, error) {
+	address, err := s.repos.Address.GetByID(addressID)
+	if err != nil || address.UserID != userID {
+		return nil, ErrAddressNotFound
+	}
+	return address, nil
+}
+
+// CreateAddress saves a new address; the first address becomes the default
+func (s *UserService) CreateAddress(userID uuid.UUID, input *AddressInput) (*models.Address, error) {
+	address := &models.Address{
+		UserID:      userID,
+		Label:       input.Label,
+		AddressText: input.AddressText,
+		Latitude:    input.Latitude,
+		Longitude:   input.Longitude,
+		City:        input.City,
+	}
+
+	makeDefault := input.IsDefault || s.repos.Address.CountByUser(userID) == 0
+
+	if err := s.repos.Address.Create(address); err != nil {
+		return nil, err
+	}
+
+	if makeDefault {
+		if err := s.repos.Address.SetDefault(userID, address.ID); err != nil {
+			return nil, err
+		}
+		address.IsDefault = true
+	}
+
+	return address, nil
+}
+
+// UpdateAddress updates a saved address
+func (s *UserService) UpdateAddress(userID, addressID uuid.UUID, input *AddressInput) (*models.Address, error) {
+	address, err := s.GetAddress(userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	address.Label = input.Label
+	address.AddressText = input.AddressText
+	address.Latitude = input.Latitude
+	address.Longitude = input.Longitude
+	address.City = input.City
+
+	if err := s.repos.Address.Update(address); err != nil {
+		return nil, err
+	}
+
+	if input.IsDefault && !address.IsDefault {
+		if err := s.repos.Address.SetDefault(userID, address.ID); err != nil {
+			return nil, err
+		}
+		address.IsDefault = true
+	}
+
+	return address, nil
+}
+
+// SetDefaultAddress marks an address as the user's default
+func (s *UserService) SetDefaultAddress(userID, addressID uuid.UUID) (*models.Address, error) {
+	address, err := s.GetAddress(userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repos.Address.SetDefault(userID, address.ID); err != nil {
+		return nil, err
+	}
+	address.IsDefault = true
+
+	return address, nil
+}
+
+// DeleteAddress removes a saved address, promoting the newest remaining one if it was the default
+func (s *UserService) DeleteAddress(userID, addressID uuid.UUID) error {
+	address, err := s.GetAddress(userID, addressID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repos.Address.Delete(address.ID); err != nil {
+		return err
+	}
+
+	if address.IsDefault {
+		remaining, err := s.repos.Address.ListByUser(userID)
+		if err == nil && len(remaining) > 0 {
+			s.repos.Address.SetDefault(userID, remaining[0].ID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+)
+
+// Vacation order policies: what happens to pending orders that fall in a vacation
+const (
+	VacationReject     = "reject"
+	VacationReschedule = "reschedule"
+)
+
+const (
+	// VacationInterval is how often the scheduler begins and ends vacations
+	VacationInterval = 5 * time.Minute
+	maxVacation      = 180 * 24 * time.Hour
+	vacationBatch    = 200
+	// vacationOrderLimit bounds the pending orders settled for one vacation
+	vacationOrderLimit = 500
+)
+
+// Vacation errors
+var (
+	ErrInvalidVacation          = validationError("invalid_vacation", "vacation must end in the future, after it starts and within 180 days")
+	ErrVacationOverlapsBookings = conflictError("vacation_overlaps_bookings", "accepted bookings fall within the vacation")
+	ErrVacationNotFound         = notFoundError("vacation_not_found", "no vacation planned")
+	ErrOnVacation               = conflictError("on_vacation", "end your vacation first")
+	ErrYandasOnVacation         = conflictError("yandas_on_vacation", "yandaş is on vacation")
+)
+
+// VacationInput plans a vacation. From defaults to now and OrderPolicy to reject.
+type VacationInput struct {
+	From        *time.Time `json:"from"`
+	Until       time.Time  `json:"until" binding:"required"`
+	OrderPolicy string     `json:"order_policy" binding:"omitempty,oneof=reject reschedule"`
+}
+
+// onVacation reports whether an order for the yandaş at scheduledAt (or as
+// soon as possible, when nil) falls in their vacation
+func onVacation(profile *models.YandasProfile, scheduledAt *time.Time) bool {
+	if profile.VacationFrom == nil || profile.VacationUntil == nil {
+		return false
+	}
+	if profile.VacationStartedAt != nil && (scheduledAt == nil || scheduledAt.Before(*profile.VacationUntil)) {
+		return true
+	}
+	return scheduledAt != nil && !scheduledAt.Before(*profile.VacationFrom) && scheduledAt.Before(*profile.VacationUntil)
+}
+
+// SetVacation plans a vacation, or moves the end of the one planned or under
+// way. A vacation starting now begins at once; a later one is begun by
+// ProcessVacations. Pending orders that fall in it are rejected or moved past
+// it according to the order policy.
+func (s *YandasService) SetVacation(userID uuid.UUID, input *VacationInput) (*models.YandasProfile, error) {
+	profile, err := s.repos.OnPrimary().YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+	if profile.ApprovalStatus != "approved" {
+		return nil, ErrProfileNotApproved
+	}
+
+	now := time.Now()
+	from := now
+	switch {
+	case profile.VacationStartedAt != nil:
+		from = *profile.VacationFrom // a vacation under way keeps its start
+	case input.From != nil && input.From.After(now):
+		from = *input.From
+	}
+	until := input.Until
+	if !until.After(from) || !until.After(now) || until.Sub(from) > maxVacation {
+		return nil, ErrInvalidVacation
+	}
+	policy := input.OrderPolicy
+	if policy == "" {
+		policy = VacationReject
+	}
+
+	booked, err := s.repos.Order.ListScheduledByYandas(profile.ID, from, until, []string{"accepted", "in_progress"})
+	if err != nil {
+		return nil, err
+	}
+	if len(booked) > 0 {
+		return nil, ErrVacationOverlapsBookings
+	}
+
+	if err := s.repos.YandasProfile.SetVacation(profile.ID, from, until, policy); err != nil {
+		return nil, err
+	}
+	profile.VacationFrom, profile.VacationUntil, profile.VacationPolicy = &from, &until, &policy
+
+	if profile.VacationStartedAt == nil && !from.After(now) {
+		if err := s.startVacation(profile, now); err != nil {
+			return nil, err
+		}
+		return profile, nil
+	}
+	s.settleVacationOrders(profile)
+	return profile, nil
+}
+
+// EndVacation cancels a planned vacation or ends the one under way early,
+// making the yandaş available again
+func (s *YandasService) EndVacation(userID uuid.UUID) (*models.YandasProfile, error) {
+	profile, err := s.repos.OnPrimary().YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+	if profile.VacationFrom == nil {
+		return nil, ErrVacationNotFound
+	}
+	if err := s.finishVacation(profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// ProcessVacations begins the vacations whose start has come and ends those
+// past their end; the scheduler calls it every VacationInterval
+func (s *YandasService) ProcessVacations() {
+	now := time.Now()
+	profiles, err := s.repos.YandasProfile.ListVacationsDue(now, vacationBatch)
+	if err != nil {
+		log.Printf("[VACATION] failed to find vacations due: %v", err)
+		return
+	}
+	for i := range profiles {
+		profile := &profiles[i]
+		if !profile.VacationUntil.After(now) {
+			err = s.finishVacation(profile)
+		} else {
+			err = s.startVacation(profile, now)
+		}
+		if err != nil {
+			log.Printf("[VACATION] failed to update the vacation of yandaş %s: %v", profile.ID, err)
+		}
+	}
+}
+
+// startVacation takes the yandaş offline and settles their pending orders
+func (s *YandasService) startVacation(profile *models.YandasProfile, now time.Time) error {
+	started, err := s.repos.YandasProfile.StartVacation(profile.ID, now)
+	if err != nil {
+		return err
+	}
+	if !started {
+		return nil
+	}
+	profile.VacationStartedAt = &now
+	profile.IsAvailable = false
+	s.settleVacationOrders(profile)
+	return nil
+}
+
+// finishVacation clears the vacation and, if it had begun, makes the yandaş
+// available again unless their approval or documents no longer allow it
+func (s *YandasService) finishVacation(profile *models.YandasProfile) error {
+	available := profile.VacationStartedAt != nil && profile.ApprovalStatus == "approved"
+	if available {
+		lapsed, err := s.lapsedRenewal(profile.ID)
+		if err != nil {
+			return err
+		}
+		available = !lapsed
+	}
+
+	err := s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.YandasProfile.ClearVacation(profile.ID); err != nil {
+			return err
+		}
+		if !available {
+			return nil
+		}
+		return tx.YandasProfile.UpdateAvailability(profile.ID, true)
+	})
+	if err != nil {
+		return err
+	}
+
+	profile.VacationFrom, profile.VacationUntil, profile.VacationPolicy, profile.VacationStartedAt = nil, nil, nil, nil
+	if available {
+		profile.IsAvailable = true
+		s.favorites.YandasOnline(profile.ID)
+	}
+	return nil
+}
+
+// settleVacationOrders rejects or reschedules the pending orders that fall in
+// the vacation. Orders with no date are rejected once the vacation begins, as
+// their customers wanted them as soon as possible. Failures are logged and the
+// order is left for the yandaş to answer.
+func (s *YandasService) settleVacationOrders(profile *models.YandasProfile) {
+	orders, _, err := s.repos.OnPrimary().Order.ListByYandas(profile.ID, 1, vacationOrderLimit, "pending")
+	if err != nil {
+		log.Printf("[VACATION] failed to load pending orders of yandaş %s: %v", profile.ID, err)
+		return
+	}
+
+	for i := range orders {
+		order := &orders[i]
+		if !onVacation(profile, order.ScheduledAt) {
+			continue
+		}
+		if order.ScheduledAt != nil && profile.VacationPolicy != nil && *profile.VacationPolicy == VacationReschedule {
+			err = s.rescheduleForVacation(profile, order)
+		} else {
+			err = s.rejectForVacation(profile, order)
+		}
+		if err != nil {
+			log.Printf("[VACATION] failed to settle order %s of yandaş %s: %v", order.ID, profile.ID, err)
+		}
+	}
+	s.notifications.Flush()
+}
+
+func (s *YandasService) rejectForVacation(profile *models.YandasProfile, order *models.Order) error {
+	backOn := profile.VacationUntil.Format("02.01.2006")
+	reason := "Yandaş tatilde, " + backOn + " tarihinde dönecek"
+	order.Status = "cancelled"
+	order.CancellationReason = &reason
+	order.CancelledBy = &profile.UserID
+
+	return s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := lockOrder(tx, order.ID, "pending"); err != nil {
+			return err
+		}
+		if err := tx.Order.Update(order); err != nil {
+			return err
+		}
+		if err := recordOrderEvent(tx, order.ID, OrderEventCancelled, order.Status, &profile.UserID, &reason); err != nil {
+			return err
+		}
+		body := fmt.Sprintf("Yandaşınız %s tarihine kadar tatilde olduğu için siparişiniz iptal edildi.", backOn)
+		return s.notifications.SendTx(tx, order.CustomerID, "Siparişiniz iptal edildi", body, "order",
+			map[string]interface{}{"order_id": order.ID, "event": OrderEventCancelled, "back_on": profile.VacationUntil})
+	})
+}
+
+func (s *YandasService) rescheduleForVacation(profile *models.YandasProfile, order *models.Order) error {
+	next := vacationReschedule(*order.ScheduledAt, *profile.VacationUntil)
+	note := "Yandaş tatilde, sipariş " + next.Format("02.01.2006") + " tarihine ertelendi"
+	order.ScheduledAt = &next
+
+	return s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := lockOrder(tx, order.ID, "pending"); err != nil {
+			return err
+		}
+		if err := tx.Order.Update(order); err != nil {
+			return err
+		}
+		if err := recordOrderEvent(tx, order.ID, OrderEventRescheduled, order.Status, &profile.UserID, &note); err != nil {
+			return err
+		}
+		body := fmt.Sprintf("Yandaşınız tatilde olduğu için siparişiniz %s tarihine ertelendi.", next.Format("02.01.2006"))
+		return s.notifications.SendTx(tx, order.CustomerID, "Siparişiniz ertelendi", body, "order",
+			map[string]interface{}{"order_id": order.ID, "event": OrderEventRescheduled, "scheduled_at": next})
+	})
+}
+
+// vacationReschedule moves a booking by whole days to the first day it falls
+// on at or after the vacation's end, keeping its time of day
+func vacationReschedule(scheduledAt, until time.Time) time.Time {
+	days := int(math.Ceil(until.Sub(scheduledAt).Hours() / 24))
+	return scheduledAt.AddDate(0, 0, days)
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+type vacationMocks struct {
+	profiles      *mocks.MockYandasProfileRepository
+	orders        *mocks.MockOrderRepository
+	history       *mocks.MockOrderHistoryRepository
+	renewals      *mocks.MockDocumentRenewalRepository
+	notifications *mocks.MockNotificationRepository
+}
+
+func newTestVacationService(t *testing.T) (*YandasService, *vacationMocks) {
+	ctrl := gomock.NewController(t)
+	m := &vacationMocks{
+		profiles:      mocks.NewMockYandasProfileRepository(ctrl),
+		orders:        mocks.NewMockOrderRepository(ctrl),
+		history:       mocks.NewMockOrderHistoryRepository(ctrl),
+		renewals:      mocks.NewMockDocumentRenewalRepository(ctrl),
+		notifications: mocks.NewMockNotificationRepository(ctrl),
+	}
+	prefs := mocks.NewMockNotificationPreferenceRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	repos := &repository.Repositories{
+		YandasProfile:          m.profiles,
+		Order:                  m.orders,
+		OrderHistory:           m.history,
+		DocumentRenewal:        m.renewals,
+		Notification:           m.notifications,
+		NotificationPreference: prefs,
+		UnitOfWork:             uow,
+	}
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(tx *repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	prefs.EXPECT().GetByUserAndType(gomock.Any(), gomock.Any()).Return(&models.NotificationPreference{}, nil).AnyTimes()
+	notifications := NewNotificationService(repos, nil, nil, nil, nil, nil)
+	return NewYandasService(repos, &config.Config{}, nil, nil, nil, nil, nil, nil, nil, notifications, nil, nil, nil, nil), m
+}
+
+func TestSetVacationSettlesPendingOrders(t *testing.T) {
+	svc, m := newTestVacationService(t)
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: uuid.New(), ApprovalStatus: "approved", IsAvailable: true}
+	m.profiles.EXPECT().GetByUserID(profile.UserID).Return(profile, nil)
+
+	until := time.Now().Add(7 * 24 * time.Hour)
+	during := time.Now().Add(2*24*time.Hour + time.Hour)
+	after := until.Add(24 * time.Hour)
+	asap := models.Order{ID: uuid.New(), CustomerID: uuid.New(), YandasID: profile.ID, Status: "pending"}
+	scheduled := models.Order{ID: uuid.New(), CustomerID: uuid.New(), YandasID: profile.ID, Status: "pending", ScheduledAt: &during}
+	later := models.Order{ID: uuid.New(), CustomerID: uuid.New(), YandasID: profile.ID, Status: "pending", ScheduledAt: &after}
+
+	m.orders.EXPECT().ListScheduledByYandas(profile.ID, gomock.Any(), until, []string{"accepted", "in_progress"}).Return(nil, nil)
+	m.profiles.EXPECT().SetVacation(profile.ID, gomock.Any(), until, VacationReschedule).Return(nil)
+	m.profiles.EXPECT().StartVacation(profile.ID, gomock.Any()).Return(true, nil)
+	m.orders.EXPECT().ListByYandas(profile.ID, 1, vacationOrderLimit, "pending").Return([]models.Order{asap, scheduled, later}, int64(3), nil)
+
+	// The undated order is rejected, the one in the vacation moved past it and the later one kept
+	m.orders.EXPECT().GetByIDForUpdate(gomock.Any()).Return(&models.Order{Status: "pending"}, nil).Times(2)
+	updated := map[uuid.UUID]*models.Order{}
+	m.orders.EXPECT().Update(gomock.Any()).DoAndReturn(func(o *models.Order) error {
+		updated[o.ID] = o
+		return nil
+	}).Times(2)
+	var events []string
+	m.history.EXPECT().Record(gomock.Any()).DoAndReturn(func(h *models.OrderStatusHistory) error {
+		events = append(events, h.Event)
+		return nil
+	}).Times(2)
+	m.notifications.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+
+	got, err := svc.SetVacation(profile.UserID, &VacationInput{Until: until, OrderPolicy: VacationReschedule})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.VacationStartedAt == nil || got.IsAvailable {
+		t.Errorf("expected the vacation under way, got %+v", got)
+	}
+	if o := updated[asap.ID]; o == nil || o.Status != "cancelled" || o.CancelledBy == nil || *o.CancelledBy != profile.UserID {
+		t.Errorf("expected the undated order rejected, got %+v", o)
+	}
+	if o := updated[scheduled.ID]; o == nil || o.Status != "pending" || !o.ScheduledAt.Equal(during.AddDate(0, 0, 5)) {
+		t.Errorf("expected the order moved to the same time on the first day back, got %+v", o)
+	}
+	if _, ok := updated[later.ID]; ok {
+		t.Error("expected the order after the vacation to be kept")
+	}
+	if len(events) != 2 || events[0] != OrderEventCancelled || events[1] != OrderEventRescheduled {
+		t.Errorf("unexpected order events %v", events)
+	}
+}
+
+func TestSetVacationChecks(t *testing.T) {
+	svc, m := newTestVacationService(t)
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: uuid.New(), ApprovalStatus: "approved"}
+	m.profiles.EXPECT().GetByUserID(profile.UserID).Return(profile, nil).AnyTimes()
+
+	from := time.Now().Add(24 * time.Hour)
+	for _, input := range []VacationInput{
+		{Until: time.Now().Add(-time.Hour)},
+		{From: &from, Until: from.Add(-time.Minute)},
+		{Until: time.Now().Add(maxVacation + time.Hour)},
+	} {
+		if _, err := svc.SetVacation(profile.UserID, &input); !errors.Is(err, ErrInvalidVacation) {
+			t.Errorf("%+v: expected ErrInvalidVacation, got %v", input, err)
+		}
+	}
+
+	booked := from.Add(time.Hour)
+	m.orders.EXPECT().ListScheduledByYandas(profile.ID, from, gomock.Any(), gomock.Any()).
+		Return([]models.Order{{ID: uuid.New(), ScheduledAt: &booked, Status: "accepted"}}, nil)
+	if _, err := svc.SetVacation(profile.UserID, &VacationInput{From: &from, Until: from.Add(48 * time.Hour)}); !errors.Is(err, ErrVacationOverlapsBookings) {
+		t.Errorf("expected ErrVacationOverlapsBookings, got %v", err)
+	}
+
+	// A planned vacation only settles the orders that fall in it
+	m.orders.EXPECT().ListScheduledByYandas(profile.ID, from, gomock.Any(), gomock.Any()).Return(nil, nil)
+	m.profiles.EXPECT().SetVacation(profile.ID, from, gomock.Any(), VacationReject).Return(nil)
+	m.orders.EXPECT().ListByYandas(profile.ID, 1, vacationOrderLimit, "pending").
+		Return([]models.Order{{ID: uuid.New(), Status: "pending"}}, int64(1), nil)
+	got, err := svc.SetVacation(profile.UserID, &VacationInput{From: &from, Until: from.Add(48 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.VacationStartedAt != nil || *got.VacationPolicy != VacationReject {
+		t.Errorf("expected a planned vacation rejecting orders, got %+v", got)
+	}
+
+	started := time.Now()
+	profile.VacationStartedAt = &started
+	if err := svc.UpdateAvailability(profile.UserID, true); !errors.Is(err, ErrOnVacation) {
+		t.Errorf("expected ErrOnVacation, got %v", err)
+	}
+}
+
+func TestProcessVacations(t *testing.T) {
+	svc, m := newTestVacationService(t)
+	now := time.Now()
+	from, until := now.Add(-7*24*time.Hour), now.Add(-time.Minute)
+	returning := models.YandasProfile{ID: uuid.New(), ApprovalStatus: "approved", VacationFrom: &from, VacationUntil: &until, VacationStartedAt: &from}
+	start, end := now.Add(-time.Minute), now.Add(3*24*time.Hour)
+	leaving := models.YandasProfile{ID: uuid.New(), ApprovalStatus: "approved", IsAvailable: true, VacationFrom: &start, VacationUntil: &end}
+	m.profiles.EXPECT().ListVacationsDue(gomock.Any(), vacationBatch).Return([]models.YandasProfile{returning, leaving}, nil)
+
+	m.renewals.EXPECT().ListOpenByProfile(returning.ID).Return(nil, nil)
+	m.profiles.EXPECT().ClearVacation(returning.ID).Return(nil)
+	m.profiles.EXPECT().UpdateAvailability(returning.ID, true).Return(nil)
+
+	m.profiles.EXPECT().StartVacation(leaving.ID, gomock.Any()).Return(true, nil)
+	m.orders.EXPECT().ListByYandas(leaving.ID, 1, vacationOrderLimit, "pending").Return(nil, int64(0), nil)
+
+	svc.ProcessVacations()
+}
+
+func TestOnVacation(t *testing.T) {
+	now := time.Now()
+	from, until := now.Add(24*time.Hour), now.Add(5*24*time.Hour)
+	inside, before, after := now.Add(2*24*time.Hour), now.Add(time.Hour), now.Add(6*24*time.Hour)
+	planned := &models.YandasProfile{VacationFrom: &from, VacationUntil: &until}
+	underWay := &models.YandasProfile{VacationFrom: &now, VacationUntil: &until, VacationStartedAt: &now}
+
+	tests := []struct {
+		name        string
+		profile     *models.YandasProfile
+		scheduledAt *time.Time
+		want        bool
+	}{
+		{"no vacation", &models.YandasProfile{}, nil, false},
+		{"planned, as soon as possible", planned, nil, false},
+		{"planned, before it", planned, &before, false},
+		{"planned, inside it", planned, &inside, true},
+		{"under way, as soon as possible", underWay, nil, true},
+		{"under way, inside it", underWay, &inside, true},
+		{"under way, after it", underWay, &after, false},
+	}
+	for _, tt := range tests {
+		if got := onVacation(tt.profile, tt.scheduledAt); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	scheduled := time.Date(2026, time.July, 10, 14, 30, 0, 0, time.UTC)
+	back := time.Date(2026, time.July, 20, 9, 0, 0, 0, time.UTC)
+	if got := vacationReschedule(scheduled, back); !got.Equal(time.Date(2026, time.July, 20, 14, 30, 0, 0, time.UTC)) {
+		t.Errorf("expected the booking on the day back at the same time, got %s", got)
+	}
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/webhook"
+)
+
+// Outbound webhook events
+const (
+	WebhookOrderCreated        = "order.created"
+	WebhookOrderAccepted       = "order.accepted"
+	WebhookOrderCompleted      = "order.completed"
+	WebhookApplicationApproved = "application.approved"
+	WebhookReviewCreated       = "review.created"
+)
+
+// WebhookEvents lists the events endpoints can subscribe to
+var WebhookEvents = []string{
+	WebhookOrderCreated,
+	WebhookOrderAccepted,
+	WebhookOrderCompleted,
+	WebhookApplicationApproved,
+	WebhookReviewCreated,
+}
+
+var (
+	ErrWebhookEndpointNotFound = notFoundError("webhook_endpoint_not_found", "webhook endpoint not found")
+	ErrWebhookURLNotAllowed    = validationError("webhook_url_not_allowed", "webhook URL must be a public http(s) address")
+)
+
+const (
+	webhookMaxAttempts  = 8
+	webhookBaseBackoff  = 30 * time.Second
+	webhookMaxBackoff   = 6 * time.Hour
+	webhookPollInterval = 15 * time.Second
+	webhookLease        = 2 * time.Minute
+	webhookBatchSize    = 50
+	webhookTimeout      = 10 * time.Second
+)
+
+// WebhookService fans events out to registered endpoints and retries failed deliveries
+type WebhookService struct {
+	repos  *repository.Repositories
+	sender *webhook.Sender
+	wake   chan struct{}
+}
+
+func NewWebhookService(repos *repository.Repositories) *WebhookService {
+	return &WebhookService{
+		repos:  repos,
+		sender: webhook.NewSender(webhookTimeout),
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// webhookEnvelope is the JSON body receivers get
+type webhookEnvelope struct {
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// Dispatch queues an event for every active endpoint subscribed to it.
+// It is safe to call on a nil service so callers don't need to guard it.
+func (s *WebhookService) Dispatch(event string, data interface{}) {
+	if s == nil {
+		return
+	}
+
+	endpoints, err := s.repos.Webhook.ListActiveEndpointsForEvent(event)
+	if err != nil || len(endpoints) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(webhookEnvelope{Event: event, CreatedAt: time.Now(), Data: data})
+	if err != nil {
+		log.Printf("[WEBHOOK] failed to encode %s: %v", event, err)
+		return
+	}
+
+	now := time.Now()
+	for _, endpoint := range endpoints {
+		delivery := &models.WebhookDelivery{
+			EndpointID:    endpoint.ID,
+			Event:         event,
+			Payload:       string(body),
+			Status:        "pending",
+			NextAttemptAt: &now,
+		}
+		if err := s.repos.Webhook.CreateDelivery(delivery); err != nil {
+			log.Printf("[WEBHOOK] failed to queue %s for %s: %v", event, endpoint.ID, err)
+		}
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers due webhooks until the process exits
+func (s *WebhookService) Run() {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		s.processDue()
+	}
+}
+
+func (s *WebhookService) processDue() {
+	deliveries, err := s.repos.Webhook.ClaimDueDeliveries(webhookBatchSize, webhookLease)
+	if err != nil {
+		log.Printf("[WEBHOOK] failed to claim deliveries: %v", err)
+		return
+	}
+	for i := range deliveries {
+		s.attempt(&deliveries[i])
+	}
+}
+
+// attempt sends one delivery and records the outcome, scheduling a retry on failure
+func (s *WebhookService) attempt(delivery *models.WebhookDelivery) {
+	delivery.Attempts++
+
+	endpoint, err := s.repos.Webhook.GetEndpoint(delivery.EndpointID)
+	if err != nil || !endpoint.IsActive {
+		msg := "endpoint removed or disabled"
+		delivery.Status = "failed"
+		delivery.LastError = &msg
+		delivery.NextAttemptAt = nil
+		s.repos.Webhook.UpdateDelivery(delivery)
+		return
+	}
+
+	result, err := s.sender.Send(endpoint.URL, endpoint.Secret, delivery.Event, delivery.ID.String(), []byte(delivery.Payload))
+	if result != nil {
+		delivery.LastStatusCode = &result.StatusCode
+		delivery.LastResponse = &result.Body
+	}
+
+	if err == nil {
+		now := time.Now()
+		delivery.Status = "succeeded"
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = nil
+	} else {
+		msg := err.Error()
+		delivery.LastError = &msg
+		if delivery.Attempts >= webhookMaxAttempts {
+			delivery.Status = "failed"
+			delivery.NextAttemptAt = nil
+		} else {
+			next := time.Now().Add(webhookBackoff(delivery.Attempts))
+			delivery.NextAttemptAt = &next
+		}
+	}
+
+	if err := s.repos.Webhook.UpdateDelivery(delivery); err != nil {
+		log.Printf("[WEBHOOK] failed to record delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// webhookBackoff doubles the wait after each failed attempt, capped at webhookMaxBackoff
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+	return delay
+}
+
+// WebhookEndpointInput represents endpoint registration data
+type WebhookEndpointInput struct {
+	URL         string   `json:"url" binding:"required,url"`
+	Events      []string `json:"events" binding:"required,min=1,dive,oneof=order.created order.accepted order.completed application.approved review.created"`
+	Description string   `json:"description"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+// WebhookEndpointCreated includes the signing secret, which is only returned once
+type WebhookEndpointCreated struct {
+	*models.WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+func (s *WebhookService) ListEndpoints() ([]models.WebhookEndpoint, error) {
+	return s.repos.Webhook.ListEndpoints()
+}
+
+// CreateEndpoint registers a receiver and generates its signing secret
+func (s *WebhookService) CreateEndpoint(input *WebhookEndpointInput, adminID uuid.UUID) (*WebhookEndpointCreated, error) {
+	if err := webhook.ValidateURL(input.URL); err != nil {
+		return nil, ErrWebhookURLNotAllowed
+	}
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		URL:       input.URL,
+		Secret:    secret,
+		Events:    input.Events,
+		IsActive:  true,
+		CreatedBy: adminID,
+	}
+	if input.Description != "" {
+		endpoint.Description = &input.Description
+	}
+
+	if err := s.repos.Webhook.CreateEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+
+	return &WebhookEndpointCreated{WebhookEndpoint: endpoint, Secret: secret}, nil
+}
+
+// UpdateEndpoint changes an endpoint's URL, events or active flag; the secret is kept
+func (s *WebhookService) UpdateEndpoint(id uuid.UUID, input *WebhookEndpointInput) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.repos.Webhook.GetEndpoint(id)
+	if err != nil {
+		return nil, ErrWebhookEndpointNotFound
+	}
+	if err := webhook.ValidateURL(input.URL); err != nil {
+		return nil, ErrWebhookURLNotAllowed
+	}
+
+	endpoint.URL = input.URL
+	endpoint.Events = input.Events
+	if input.Description != "" {
+		endpoint.Description = &input.Description
+	}
+	if input.IsActive != nil {
+		endpoint.IsActive = *input.IsActive
+	}
+
+	if err := s.repos.Webhook.UpdateEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+func (s *WebhookService) DeleteEndpoint(id uuid.UUID) error {
+	if _, err := s.repos.Webhook.GetEndpoint(id); err != nil {
+		return ErrWebhookEndpointNotFound
+	}
+	return s.repos.Webhook.DeleteEndpoint(id)
+}
+
+func (s *WebhookService) ListDeliveries(endpointID uuid.UUID, page, limit int, status string) ([]models.WebhookDelivery, int64, error) {
+	return s.repos.Webhook.ListDeliveries(endpointID, page, limit, status)
+}
+
+// RetryDelivery requeues a delivery for immediate sending
+func (s *WebhookService) RetryDelivery(id uuid.UUID) (*models.WebhookDelivery, error) {
+	delivery, err := s.repos.Webhook.GetDelivery(id)
+	if err != nil {
+		return nil, notFoundError("delivery_not_found", "delivery not found")
+	}
+
+	now := time.Now()
+	delivery.Status = "pending"
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+
+	if err := s.repos.Webhook.UpdateDelivery(delivery); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return delivery, nil
+}
+
+// orderWebhookData is the order shape sent to integrators, without customer contact details
+func orderWebhookData(order *models.Order) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           order.ID,
+		"order_number": order.OrderNumber,
+		"status":       order.Status,
+		"customer_id":  order.CustomerID,
+		"yandas_id":    order.YandasID,
+		"service_id":   order.ServiceID,
+		"agreed_price": order.AgreedPrice,
+		"currency":     order.Currency,
+		"scheduled_at": order.ScheduledAt,
+		"completed_at": order.CompletedAt,
+		"created_at":   order.CreatedAt,
+	}
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+const (
+	// Days the nightly rollup recounts, so late writes to a closed day are still counted
+	analyticsRollupDays = 2
+	// Raw profile views older than this are pruned once counted
+	profileViewRetention = 90 * 24 * time.Hour
+)
+
+// AnalyticsCounts is a yandaş's funnel over some period
+type AnalyticsCounts struct {
+	ProfileViews       int64          `json:"profile_views"`
+	FavoriteAdds       int64          `json:"favorite_adds"`
+	ConversationStarts int64          `json:"conversation_starts"`
+	OrdersCreated      int64          `json:"orders_created"`
+	OrdersCompleted    int64          `json:"orders_completed"`
+	Revenue            currency.Money `json:"revenue"`
+}
+
+func (c *AnalyticsCounts) add(stat *models.YandasDailyStat) {
+	c.ProfileViews += stat.ProfileViews
+	c.FavoriteAdds += stat.FavoriteAdds
+	c.ConversationStarts += stat.ConversationStarts
+	c.OrdersCreated += stat.OrdersCreated
+	c.OrdersCompleted += stat.OrdersCompleted
+	c.Revenue += stat.Revenue
+}
+
+// AnalyticsDay is the funnel of a single day
+type AnalyticsDay struct {
+	Date string `json:"date"`
+	AnalyticsCounts
+}
+
+// AnalyticsConversion holds the funnel's conversion rates in percent; nil when the base is zero
+type AnalyticsConversion struct {
+	ViewToFavorite     *float64 `json:"view_to_favorite"`
+	ViewToConversation *float64 `json:"view_to_conversation"`
+	ViewToOrder        *float64 `json:"view_to_order"`
+	OrderCompletion    *float64 `json:"order_completion"`
+}
+
+// YandasAnalytics is a yandaş's funnel over a date range. Days are counted by
+// the nightly rollup, so today shows up the following night.
+type YandasAnalytics struct {
+	From       string              `json:"from"`
+	To         string              `json:"to"`
+	Totals     AnalyticsCounts     `json:"totals"`
+	Conversion AnalyticsConversion `json:"conversion"`
+	Days       []AnalyticsDay      `json:"days"`
+}
+
+// recordProfileView counts a view of a public profile. Failures are logged;
+// they never fail the profile request.
+func (s *YandasService) recordProfileView(yandasID uuid.UUID) {
+	if err := s.repos.Analytics.RecordProfileView(yandasID); err != nil {
+		log.Printf("[ANALYTICS] failed to record profile view of %s: %v", yandasID, err)
+	}
+}
+
+// Analytics returns the yandaş's funnel for the days in [from, to), one entry per day
+func (s *YandasService) Analytics(userID uuid.UUID, from, to time.Time) (*YandasAnalytics, error) {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+
+	stats, err := s.repos.Analytics.ListDaily(profile.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	byDate := make(map[string]*models.YandasDailyStat, len(stats))
+	for i := range stats {
+		byDate[stats[i].Date.Format("2006-01-02")] = &stats[i]
+	}
+
+	result := &YandasAnalytics{
+		From: from.Format("2006-01-02"),
+		To:   to.AddDate(0, 0, -1).Format("2006-01-02"),
+		Days: []AnalyticsDay{},
+	}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		entry := AnalyticsDay{Date: day.Format("2006-01-02")}
+		if stat, ok := byDate[entry.Date]; ok {
+			entry.add(stat)
+			result.Totals.add(stat)
+		}
+		result.Days = append(result.Days, entry)
+	}
+
+	totals := result.Totals
+	result.Conversion = AnalyticsConversion{
+		ViewToFavorite:     conversionRate(totals.FavoriteAdds, totals.ProfileViews),
+		ViewToConversation: conversionRate(totals.ConversationStarts, totals.ProfileViews),
+		ViewToOrder:        conversionRate(totals.OrdersCreated, totals.ProfileViews),
+		OrderCompletion:    conversionRate(totals.OrdersCompleted, totals.OrdersCreated),
+	}
+	return result, nil
+}
+
+// conversionRate returns part as a percentage of base, rounded to two decimals
+func conversionRate(part, base int64) *float64 {
+	if base == 0 {
+		return nil
+	}
+	rate := float64(part*10000/base) / 100
+	return &rate
+}
+
+// RollupAnalytics recounts the last analyticsRollupDays full days into the
+// daily stats and prunes old raw views; the scheduler runs it nightly
+func (s *YandasService) RollupAnalytics() {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for i := analyticsRollupDays; i >= 1; i-- {
+		from := today.AddDate(0, 0, -i)
+		if err := s.repos.Analytics.RollupDay(from, from.AddDate(0, 0, 1)); err != nil {
+			log.Printf("[ANALYTICS] failed to roll up %s: %v", from.Format("2006-01-02"), err)
+		}
+	}
+
+	pruned, err := s.repos.Analytics.PruneProfileViews(today.Add(-profileViewRetention))
+	if err != nil {
+		log.Printf("[ANALYTICS] failed to prune profile views: %v", err)
+		return
+	}
+	if pruned > 0 {
+		log.Printf("[ANALYTICS] pruned %d profile views", pruned)
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+)
+
+func TestYandasAnalyticsFillsDaysAndConversion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	profiles := mocks.NewMockYandasProfileRepository(ctrl)
+	analytics := mocks.NewMockAnalyticsRepository(ctrl)
+	svc := NewYandasService(&repository.Repositories{YandasProfile: profiles, Analytics: analytics}, &config.Config{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	userID := uuid.New()
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: userID}
+	from := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 3)
+
+	profiles.EXPECT().GetByUserID(userID).Return(profile, nil)
+	analytics.EXPECT().ListDaily(profile.ID, from, to).Return([]models.YandasDailyStat{
+		{YandasID: profile.ID, Date: from, ProfileViews: 40, FavoriteAdds: 2, ConversationStarts: 4, OrdersCreated: 2, OrdersCompleted: 1, Revenue: 250},
+		{YandasID: profile.ID, Date: from.AddDate(0, 0, 2), ProfileViews: 20, ConversationStarts: 1, OrdersCreated: 1},
+	}, nil)
+
+	result, err := svc.Analytics(userID, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.From != "2026-04-01" || result.To != "2026-04-03" {
+		t.Errorf("expected range 2026-04-01..2026-04-03, got %s..%s", result.From, result.To)
+	}
+	if len(result.Days) != 3 || result.Days[1].Date != "2026-04-02" || result.Days[1].ProfileViews != 0 {
+		t.Fatalf("expected three days with an empty middle day, got %+v", result.Days)
+	}
+	if result.Totals.ProfileViews != 60 || result.Totals.OrdersCreated != 3 || result.Totals.Revenue != 250 {
+		t.Errorf("unexpected totals %+v", result.Totals)
+	}
+	if rate := result.Conversion.ViewToOrder; rate == nil || *rate != 5 {
+		t.Errorf("expected 5%% view to order, got %v", rate)
+	}
+	if rate := result.Conversion.OrderCompletion; rate == nil || *rate != 33.33 {
+		t.Errorf("expected 33.33%% completion, got %v", rate)
+	}
+}
+
+func TestConversionRateWithoutBase(t *testing.T) {
+	if rate := conversionRate(3, 0); rate != nil {
+		t.Errorf("expected nil rate for a zero base, got %v", *rate)
+	}
+}
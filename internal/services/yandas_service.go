@@ -1,24 +1,54 @@
 package services
 
 import (
-	"errors"
+	"fmt"
+	"log"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/yandas/backend/internal/config"
 	"github.com/yandas/backend/internal/models"
 	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/pkg/currency"
+	"github.com/yandas/backend/pkg/routing"
+)
+
+var (
+	ErrScheduleConflict      = conflictError("schedule_conflict", "order overlaps with another booking")
+	ErrServiceOptionNotFound = notFoundError("service_option_not_found", "option not found")
+)
+
+const (
+	// defaultBookingDuration is assumed when a service has no duration set
+	defaultBookingDuration = 60 * time.Minute
+	// maxBookingDuration bounds how far back to look for overlapping bookings
+	maxBookingDuration = 24 * time.Hour
 )
 
 // YandasService handles yandaş operations
 type YandasService struct {
-	repos *repository.Repositories
-	cfg   *config.Config
+	repos         *repository.Repositories
+	cfg           *config.Config
+	subscriptions *SubscriptionService
+	screening     *ScreeningService
+	kyc           *KYCService
+	webhooks      *WebhookService
+	receipts      *ReceiptService
+	favorites     *FavoriteService
+	chat          *ChatService
+	notifications *NotificationService
+	settings      *SettingsService
+	routing       routing.Provider
+	events        *EventBus
+	realtime      Broadcaster
+	activity      *ActivityService
 }
 
 // NewYandasService creates a new yandaş service
-func NewYandasService(repos *repository.Repositories, cfg *config.Config) *YandasService {
-	return &YandasService{repos: repos, cfg: cfg}
+func NewYandasService(repos *repository.Repositories, cfg *config.Config, subscriptions *SubscriptionService, screening *ScreeningService, kyc *KYCService, webhooks *WebhookService, receipts *ReceiptService, favorites *FavoriteService, chat *ChatService, notifications *NotificationService, settings *SettingsService, router routing.Provider, events *EventBus, activity *ActivityService) *YandasService {
+	return &YandasService{repos: repos, cfg: cfg, subscriptions: subscriptions, screening: screening, kyc: kyc, webhooks: webhooks, receipts: receipts, favorites: favorites, chat: chat, notifications: notifications, settings: settings, routing: router, events: events, activity: activity}
 }
 
 // ApplicationInput represents yandaş application data
@@ -30,8 +60,23 @@ type ApplicationInput struct {
 	EhliyetOnURL    string   `json:"ehliyet_on_url"`     // License Front
 	EhliyetArkaURL  string   `json:"ehliyet_arka_url"`   // License Back
 	AdliSicilPDFURL string   `json:"adli_sicil_pdf_url"` // Criminal Record PDF
+	SelfieURL       string   `json:"selfie_url"`         // Selfie for the identity check
 	ServiceCities   []string `json:"service_cities" binding:"required"`
-	CategoryIDs     []string `json:"category_ids"` // For multiple categories (optional for now)
+	CategoryIDs     []string `json:"category_ids"` // the categories applied for; their application forms must be filled in
+
+	Answers []ApplicationAnswerInput `json:"answers"`
+}
+
+// documents returns the uploaded documents by name
+func (input *ApplicationInput) documents() map[string]string {
+	return map[string]string{
+		"kimlik_on":      input.KimlikOnURL,
+		"kimlik_arka":    input.KimlikArkaURL,
+		"ehliyet_on":     input.EhliyetOnURL,
+		"ehliyet_arka":   input.EhliyetArkaURL,
+		"adli_sicil_pdf": input.AdliSicilPDFURL,
+		"selfie":         input.SelfieURL,
+	}
 }
 
 // Apply creates a yandaş application
@@ -39,14 +84,47 @@ func (s *YandasService) Apply(userID uuid.UUID, input *ApplicationInput) (*model
 	// Check if already applied
 	existing, _ := s.repos.YandasProfile.GetByUserID(userID)
 	if existing != nil {
-		return nil, errors.New("you have already applied to become a yandaş")
+		return nil, conflictError("already_applied", "you have already applied to become a yandaş")
+	}
+
+	// The categories' forms decide which documents and answers are required
+	var categoryIDs []uuid.UUID
+	var appliedFor pq.StringArray
+	for _, raw := range input.CategoryIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, ErrCategoryNotFound
+		}
+		if !slices.Contains(categoryIDs, id) {
+			categoryIDs = append(categoryIDs, id)
+			appliedFor = append(appliedFor, id.String())
+		}
+	}
+	form, err := loadApplicationForm(s.repos, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := form.Answers(input.documents(), input.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	slug, err := uniqueProfileSlug(s.repos.YandasProfile, user.FullName)
+	if err != nil {
+		return nil, err
 	}
 
 	profile := &models.YandasProfile{
 		UserID:          userID,
+		Slug:            &slug,
 		Bio:             &input.Bio,
 		InstagramHandle: &input.InstagramHandle,
 		ServiceCities:   input.ServiceCities,
+		CategoryIDs:     appliedFor,
 		ApprovalStatus:  "pending",
 	}
 
@@ -64,38 +142,120 @@ func (s *YandasService) Apply(userID uuid.UUID, input *ApplicationInput) (*model
 		profile.EhliyetArkaURL = &input.EhliyetArkaURL
 	}
 	if input.AdliSicilPDFURL != "" {
+		now := time.Now()
 		profile.AdliSicilPDFURL = &input.AdliSicilPDFURL
+		profile.AdliSicilUploadedAt = &now
+	}
+	if input.SelfieURL != "" {
+		profile.SelfieURL = &input.SelfieURL
 	}
 
-	if err := s.repos.YandasProfile.Create(profile); err != nil {
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := tx.YandasProfile.Create(profile); err != nil {
+			return err
+		}
+		for i := range answers {
+			answers[i].YandasProfileID = profile.ID
+		}
+		if err := tx.ApplicationForm.CreateAnswers(answers); err != nil {
+			return err
+		}
+		return s.activity.Record(tx, ActivityApplicationSubmitted, profile.ID, &userID, "New yandaş application", map[string]interface{}{
+			"approval_status": profile.ApprovalStatus,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.activity.Flush()
+
+	// OCR pre-screen of the kimlik and identity check for the admin review queue
+	s.screening.ScreenApplicationAsync(profile.ID)
+	s.kyc.VerifyApplicationAsync(profile.ID)
 
 	return profile, nil
 }
 
+// ApplicationStatusResponse is an application with the documents its
+// categories require that are still missing
+type ApplicationStatusResponse struct {
+	*models.YandasProfile
+	MissingDocuments []DocumentRequirement `json:"missing_documents"`
+}
+
 // GetApplicationStatus returns application status
-func (s *YandasService) GetApplicationStatus(userID uuid.UUID) (*models.YandasProfile, error) {
-	return s.repos.YandasProfile.GetByUserID(userID)
+func (s *YandasService) GetApplicationStatus(userID uuid.UUID) (*ApplicationStatusResponse, error) {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	missing := []DocumentRequirement{}
+	if profile.ApprovalStatus == "pending" {
+		if missing, err = missingDocuments(s.repos, profile); err != nil {
+			return nil, err
+		}
+	}
+	return &ApplicationStatusResponse{YandasProfile: profile, MissingDocuments: missing}, nil
+}
+
+// UploadApplicationDocument adds or replaces a document of a pending
+// application, e.g. one a category started requiring after it was made
+func (s *YandasService) UploadApplicationDocument(userID uuid.UUID, document, url string) (*ApplicationStatusResponse, error) {
+	profile, err := s.repos.OnPrimary().YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+	if profile.ApprovalStatus != "pending" {
+		return nil, conflictError("application_not_pending", "documents can only be added to a pending application")
+	}
+	field, ok := documentFields(profile)[document]
+	if !ok {
+		return nil, validationError("invalid_document", fmt.Sprintf("unknown document %q", document))
+	}
+
+	*field = &url
+	if document == "adli_sicil_pdf" {
+		now := time.Now()
+		profile.AdliSicilUploadedAt = &now
+	}
+	if err := s.repos.YandasProfile.Update(profile); err != nil {
+		return nil, err
+	}
+
+	// The identity checks run again on a new kimlik or selfie
+	if document == "kimlik_on" || document == "kimlik_arka" || document == "selfie" {
+		s.screening.ScreenApplicationAsync(profile.ID)
+		s.kyc.VerifyApplicationAsync(profile.ID)
+	}
+
+	missing, err := missingDocuments(s.repos.OnPrimary(), profile)
+	if err != nil {
+		return nil, err
+	}
+	return &ApplicationStatusResponse{YandasProfile: profile, MissingDocuments: missing}, nil
 }
 
 // UpdateProfileInput represents yandaş profile update data
 type UpdateYandasProfileInput struct {
 	Bio           string   `json:"bio"`
 	ServiceCities []string `json:"service_cities"`
+	Version       *int     `json:"version"` // the version last read; the update is refused if it changed since
 }
 
 // UpdateProfile updates yandaş profile
 func (s *YandasService) UpdateProfile(userID uuid.UUID, input *UpdateYandasProfileInput) (*models.YandasProfile, error) {
-	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	profile, err := s.repos.OnPrimary().YandasProfile.GetByUserID(userID)
 	if err != nil {
-		return nil, errors.New("yandaş profile not found")
+		return nil, ErrYandasProfileNotFound
 	}
 
 	if profile.ApprovalStatus != "approved" {
-		return nil, errors.New("profile not approved yet")
+		return nil, ErrProfileNotApproved
 	}
 
+	if input.Version != nil {
+		profile.Version = *input.Version
+	}
 	if input.Bio != "" {
 		profile.Bio = &input.Bio
 	}
@@ -113,81 +273,191 @@ func (s *YandasService) UpdateProfile(userID uuid.UUID, input *UpdateYandasProfi
 
 // UpdateAvailability updates availability status
 func (s *YandasService) UpdateAvailability(userID uuid.UUID, available bool) error {
-	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	profile, err := s.repos.OnPrimary().YandasProfile.GetByUserID(userID)
 	if err != nil {
-		return errors.New("yandaş profile not found")
+		return ErrYandasProfileNotFound
 	}
 
 	if profile.ApprovalStatus != "approved" {
-		return errors.New("profile not approved yet")
+		return ErrProfileNotApproved
+	}
+	if available && profile.VacationStartedAt != nil {
+		return ErrOnVacation
+	}
+	if available {
+		lapsed, err := s.lapsedRenewal(profile.ID)
+		if err != nil {
+			return err
+		}
+		if lapsed {
+			return ErrDocumentRenewalLapsed
+		}
 	}
 
-	return s.repos.YandasProfile.UpdateAvailability(profile.ID, available)
+	if err := s.repos.YandasProfile.UpdateAvailability(profile.ID, available); err != nil {
+		return err
+	}
+	if available && !profile.IsAvailable {
+		s.favorites.YandasOnline(profile.ID)
+	}
+	return nil
 }
 
 // UpdateLocation updates current location
 func (s *YandasService) UpdateLocation(userID uuid.UUID, lat, lng float64) error {
 	profile, err := s.repos.YandasProfile.GetByUserID(userID)
 	if err != nil {
-		return errors.New("yandaş profile not found")
+		return ErrYandasProfileNotFound
 	}
 
-	return s.repos.YandasProfile.UpdateLocation(profile.ID, lat, lng)
+	if err := s.repos.YandasProfile.UpdateLocation(profile.ID, lat, lng); err != nil {
+		return err
+	}
+	s.recordOrderLocations(profile, lat, lng)
+	s.updateOrderETAs(profile, lat, lng)
+	return nil
 }
 
-// ListPublic returns available yandaşlar
-func (s *YandasService) ListPublic(page, limit int, category, city string) ([]models.YandasProfile, int64, error) {
-	return s.repos.YandasProfile.ListPublic(page, limit, category, city)
+// ListPublic returns available yandaşlar, ranked by quality score when the
+// ranking.quality_score setting is on
+func (s *YandasService) ListPublic(page, limit int, category, city string) ([]PublicYandas, int64, error) {
+	items, total, err := s.repos.YandasProfile.ListPublic(page, limit, category, city, s.settings.Bool(SettingRankingQuality))
+	if err != nil {
+		return nil, 0, err
+	}
+	return publicListItems(items), total, nil
 }
 
-// GetPublic returns a public yandaş profile
-func (s *YandasService) GetPublic(id uuid.UUID) (*models.YandasProfile, error) {
-	profile, err := s.repos.YandasProfile.GetByID(id)
+// GetPublic returns a public yandaş profile, looked up by ID or slug, with
+// its active services, add-ons and price tiers
+func (s *YandasService) GetPublic(ref string) (*PublicYandasProfile, error) {
+	profile, err := publicProfile(s.repos, ref)
 	if err != nil {
 		return nil, err
 	}
 
-	if profile.ApprovalStatus != "approved" {
-		return nil, errors.New("profile not found")
+	services, err := s.repos.Service.GetByYandasID(profile.ID)
+	if err != nil {
+		return nil, err
 	}
+	profile.Services = services
 
-	return profile, nil
+	s.recordProfileView(profile.ID)
+	return publicYandasProfile(profile), nil
 }
 
-// GetServices returns yandaş services
+// GetServices returns a yandaş's listed services
 func (s *YandasService) GetServices(yandasID uuid.UUID) ([]models.YandasService, error) {
 	return s.repos.Service.GetByYandasID(yandasID)
 }
 
-// GetReviews returns yandaş reviews
-func (s *YandasService) GetReviews(yandasID uuid.UUID, page, limit int) ([]models.Review, int64, error) {
+// GetMyServices returns the user's own services including drafts and ones
+// awaiting approval, with includeInactive also the archived ones
+func (s *YandasService) GetMyServices(userID uuid.UUID, includeInactive bool) ([]models.YandasService, error) {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+	return s.repos.Service.ListByYandas(profile.ID, includeInactive)
+}
+
+// ErrInvalidServiceFilter is returned for an unknown sort or an inverted range
+var ErrInvalidServiceFilter = validationError("invalid_service_filter", "invalid service filter")
+
+// ListServices browses the public service catalogue
+func (s *YandasService) ListServices(filter repository.ServiceFilter, page, limit int) ([]models.ServiceListItem, int64, error) {
+	switch filter.Sort {
+	case "", repository.ServiceSortRating, repository.ServiceSortPriceAsc, repository.ServiceSortPriceDesc:
+	default:
+		return nil, 0, ErrInvalidServiceFilter
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return nil, 0, ErrInvalidServiceFilter
+	}
+	if filter.MinDuration != nil && filter.MaxDuration != nil && *filter.MinDuration > *filter.MaxDuration {
+		return nil, 0, ErrInvalidServiceFilter
+	}
+	items, total, err := s.repos.Service.ListPublic(filter, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range items {
+		items[i].YandasName = PublicName(items[i].YandasName)
+	}
+	return items, total, nil
+}
+
+// GetReviews returns yandaş reviews sorted by "recent" or "helpful"
+func (s *YandasService) GetReviews(yandasID uuid.UUID, page, limit int, sort string) ([]PublicReview, int64, error) {
 	profile, err := s.repos.YandasProfile.GetByID(yandasID)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	return s.repos.Review.ListByReviewee(profile.UserID, page, limit)
+	reviews, total, err := s.repos.Review.ListByReviewee(profile.UserID, page, limit, sort)
+	if err != nil {
+		return nil, 0, err
+	}
+	return publicReviews(reviews), total, nil
+}
+
+// ReplyInput represents a yandaş reply to a review
+type ReplyInput struct {
+	Reply string `json:"reply" binding:"required,max=1000"`
+}
+
+// ReplyToReview posts the yandaş's single public reply to a review about them
+func (s *YandasService) ReplyToReview(userID, reviewID uuid.UUID, input *ReplyInput) (*models.Review, error) {
+	review, err := s.repos.Review.GetByID(reviewID)
+	if err != nil {
+		return nil, ErrReviewNotFound
+	}
+
+	if review.RevieweeID != userID {
+		return nil, ErrNotOwner
+	}
+
+	replied, err := s.repos.Review.SetReply(reviewID, input.Reply)
+	if err != nil {
+		return nil, err
+	}
+	if !replied {
+		return nil, conflictError("already_replied", "review already has a reply")
+	}
+
+	return s.repos.Review.GetByID(reviewID)
 }
 
 // ServiceInput represents service creation data
 type ServiceInput struct {
-	CategoryID      uuid.UUID `json:"category_id" binding:"required"`
-	Title           string    `json:"title" binding:"required"`
-	Description     string    `json:"description"`
-	BasePrice       float64   `json:"base_price" binding:"required"`
-	DurationMinutes int       `json:"duration_minutes"`
-	Includes        []string  `json:"includes"`
+	CategoryID      uuid.UUID      `json:"category_id" binding:"required"`
+	Title           string         `json:"title" binding:"required"`
+	Description     string         `json:"description"`
+	BasePrice       currency.Money `json:"base_price" binding:"required"`
+	Currency        string         `json:"currency" binding:"omitempty,currency"` // defaults to TRY on create, unchanged on update
+	DurationMinutes int            `json:"duration_minutes"`
+	Includes        []string       `json:"includes"`
+	Version         *int           `json:"version"` // on update, the version last read; the update is refused if it changed since
+	Draft           bool           `json:"draft"`   // on create, save without publishing
 }
 
 // CreateService creates a new service
 func (s *YandasService) CreateService(userID uuid.UUID, input *ServiceInput) (*models.YandasService, error) {
 	profile, err := s.repos.YandasProfile.GetByUserID(userID)
 	if err != nil {
-		return nil, errors.New("yandaş profile not found")
+		return nil, ErrYandasProfileNotFound
 	}
 
 	if profile.ApprovalStatus != "approved" {
-		return nil, errors.New("profile not approved yet")
+		return nil, ErrProfileNotApproved
+	}
+
+	status := ServiceStatusDraft
+	if !input.Draft {
+		if err := s.subscriptions.RequireEntitlement(userID, EntitlementServiceSlot); err != nil {
+			return nil, err
+		}
+		status = s.publishedStatus()
 	}
 
 	service := &models.YandasService{
@@ -196,14 +466,19 @@ func (s *YandasService) CreateService(userID uuid.UUID, input *ServiceInput) (*m
 		Title:           input.Title,
 		Description:     &input.Description,
 		BasePrice:       input.BasePrice,
+		Currency:        currencyCode(input.Currency),
 		DurationMinutes: &input.DurationMinutes,
 		Includes:        input.Includes,
 		IsActive:        true,
+		Status:          status,
 	}
 
 	if err := s.repos.Service.Create(service); err != nil {
 		return nil, err
 	}
+	if service.Status == ServiceStatusActive {
+		s.favorites.ServiceAdded(service)
+	}
 
 	return service, nil
 }
@@ -212,164 +487,552 @@ func (s *YandasService) CreateService(userID uuid.UUID, input *ServiceInput) (*m
 func (s *YandasService) UpdateService(userID uuid.UUID, serviceID uuid.UUID, input *ServiceInput) (*models.YandasService, error) {
 	profile, err := s.repos.YandasProfile.GetByUserID(userID)
 	if err != nil {
-		return nil, errors.New("yandaş profile not found")
+		return nil, ErrYandasProfileNotFound
 	}
 
-	service, err := s.repos.Service.GetByID(serviceID)
-	if err != nil {
-		return nil, errors.New("service not found")
+	service, err := s.repos.OnPrimary().Service.GetByID(serviceID)
+	if err != nil || service.DeletedAt != nil {
+		return nil, ErrServiceNotFound
 	}
 
 	if service.YandasID != profile.ID {
-		return nil, errors.New("unauthorized")
+		return nil, ErrNotOwner
 	}
 
+	if input.Version != nil {
+		service.Version = *input.Version
+	}
+	oldPrice, oldCurrency := service.BasePrice, service.Currency
 	service.Title = input.Title
 	service.Description = &input.Description
 	service.BasePrice = input.BasePrice
+	if input.Currency != "" {
+		service.Currency = currencyCode(input.Currency)
+	}
 	service.DurationMinutes = &input.DurationMinutes
 	service.Includes = input.Includes
 
 	if err := s.repos.Service.Update(service); err != nil {
 		return nil, err
 	}
+	// Prices in different currencies can't be compared
+	if service.Currency == oldCurrency {
+		s.favorites.PriceChanged(service, oldPrice)
+	}
 
 	return service, nil
 }
 
-// DeleteService deletes a service
+// Service archive errors
+var (
+	ErrServiceInUse       = conflictError("service_in_use", "service has orders in progress")
+	ErrServiceArchived    = conflictError("service_archived", "service is already archived")
+	ErrServiceNotArchived = conflictError("service_not_archived", "service is not archived")
+)
+
+// DeleteService removes a service for good. Services with orders that are
+// not yet completed or cancelled can only be archived.
 func (s *YandasService) DeleteService(userID uuid.UUID, serviceID uuid.UUID) error {
+	if _, err := s.ownedService(userID, serviceID); err != nil {
+		return err
+	}
+
+	active, err := s.repos.Order.CountActiveByService(serviceID)
+	if err != nil {
+		return err
+	}
+	if active > 0 {
+		return ErrServiceInUse
+	}
+
+	return s.repos.Service.Delete(serviceID)
+}
+
+// ArchiveService hides a service from customers until it is unarchived;
+// orders already placed for it carry on
+func (s *YandasService) ArchiveService(userID, serviceID uuid.UUID) (*models.YandasService, error) {
+	service, err := s.ownedService(userID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !service.IsActive {
+		return nil, ErrServiceArchived
+	}
+
+	now := time.Now()
+	service.IsActive = false
+	service.ArchivedAt = &now
+	if err := s.repos.Service.Update(service); err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// UnarchiveService lists an archived service again; unless it is a draft or
+// was rejected it takes up a service slot of the user's plan like a new one
+func (s *YandasService) UnarchiveService(userID, serviceID uuid.UUID) (*models.YandasService, error) {
+	service, err := s.ownedService(userID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if service.IsActive {
+		return nil, ErrServiceNotArchived
+	}
+	if takesServiceSlot(service.Status) {
+		if err := s.subscriptions.RequireEntitlement(userID, EntitlementServiceSlot); err != nil {
+			return nil, err
+		}
+	}
+
+	service.IsActive = true
+	service.ArchivedAt = nil
+	if err := s.repos.Service.Update(service); err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// ownedService loads a service and checks that it belongs to the user's
+// yandaş profile; deleted services are not found
+func (s *YandasService) ownedService(userID, serviceID uuid.UUID) (*models.YandasService, error) {
 	profile, err := s.repos.YandasProfile.GetByUserID(userID)
 	if err != nil {
-		return errors.New("yandaş profile not found")
+		return nil, ErrYandasProfileNotFound
 	}
 
 	service, err := s.repos.Service.GetByID(serviceID)
-	if err != nil {
-		return errors.New("service not found")
+	if err != nil || service.DeletedAt != nil {
+		return nil, ErrServiceNotFound
 	}
 
 	if service.YandasID != profile.ID {
-		return errors.New("unauthorized")
+		return nil, ErrNotOwner
 	}
 
-	return s.repos.Service.Delete(serviceID)
+	return service, nil
+}
+
+// ServiceOptionInput represents an add-on for a service
+type ServiceOptionInput struct {
+	Name     string         `json:"name" binding:"required,max=255"`
+	Price    currency.Money `json:"price" binding:"min=0"`
+	IsActive *bool          `json:"is_active"`
+}
+
+// AddServiceOption adds a priced add-on to a service
+func (s *YandasService) AddServiceOption(userID, serviceID uuid.UUID, input *ServiceOptionInput) (*models.ServiceOption, error) {
+	if _, err := s.ownedService(userID, serviceID); err != nil {
+		return nil, err
+	}
+
+	option := &models.ServiceOption{
+		ServiceID: serviceID,
+		Name:      input.Name,
+		Price:     input.Price,
+		IsActive:  true,
+	}
+
+	if err := s.repos.Service.CreateOption(option); err != nil {
+		return nil, err
+	}
+
+	return option, nil
+}
+
+// UpdateServiceOption updates an add-on
+func (s *YandasService) UpdateServiceOption(userID, serviceID, optionID uuid.UUID, input *ServiceOptionInput) (*models.ServiceOption, error) {
+	if _, err := s.ownedService(userID, serviceID); err != nil {
+		return nil, err
+	}
+
+	option, err := s.repos.Service.GetOption(optionID)
+	if err != nil || option.ServiceID != serviceID {
+		return nil, ErrServiceOptionNotFound
+	}
+
+	option.Name = input.Name
+	option.Price = input.Price
+	if input.IsActive != nil {
+		option.IsActive = *input.IsActive
+	}
+
+	if err := s.repos.Service.UpdateOption(option); err != nil {
+		return nil, err
+	}
+
+	return option, nil
+}
+
+// DeleteServiceOption deactivates an add-on; past orders keep their line items
+func (s *YandasService) DeleteServiceOption(userID, serviceID, optionID uuid.UUID) error {
+	if _, err := s.ownedService(userID, serviceID); err != nil {
+		return err
+	}
+
+	option, err := s.repos.Service.GetOption(optionID)
+	if err != nil || option.ServiceID != serviceID {
+		return ErrServiceOptionNotFound
+	}
+
+	option.IsActive = false
+	return s.repos.Service.UpdateOption(option)
+}
+
+// PriceTierInput represents one surcharge band
+type PriceTierInput struct {
+	Basis     string         `json:"basis" binding:"required,oneof=duration distance"`
+	UpTo      float64        `json:"up_to" binding:"gt=0"`
+	Surcharge currency.Money `json:"surcharge" binding:"min=0"`
+}
+
+// SetPriceTiersInput replaces a service's tiers
+type SetPriceTiersInput struct {
+	Tiers []PriceTierInput `json:"tiers" binding:"dive"`
+}
+
+// SetPriceTiers replaces the service's duration and distance surcharge bands
+func (s *YandasService) SetPriceTiers(userID, serviceID uuid.UUID, input *SetPriceTiersInput) ([]models.ServicePriceTier, error) {
+	if _, err := s.ownedService(userID, serviceID); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	tiers := make([]models.ServicePriceTier, 0, len(input.Tiers))
+	for _, t := range input.Tiers {
+		key := fmt.Sprintf("%s:%v", t.Basis, t.UpTo)
+		if seen[key] {
+			return nil, validationError("duplicate_price_tier", "duplicate price tier")
+		}
+		seen[key] = true
+
+		tiers = append(tiers, models.ServicePriceTier{
+			ServiceID: serviceID,
+			Basis:     t.Basis,
+			UpTo:      t.UpTo,
+			Surcharge: t.Surcharge,
+		})
+	}
+
+	if err := s.repos.Service.ReplacePriceTiers(serviceID, tiers); err != nil {
+		return nil, err
+	}
+
+	return tiers, nil
 }
 
 // GetOrders returns yandaş orders
 func (s *YandasService) GetOrders(userID uuid.UUID, page, limit int, status string) ([]models.Order, int64, error) {
 	profile, err := s.repos.YandasProfile.GetByUserID(userID)
 	if err != nil {
-		return nil, 0, errors.New("yandaş profile not found")
+		return nil, 0, ErrYandasProfileNotFound
 	}
 
 	return s.repos.Order.ListByYandas(profile.ID, page, limit, status)
 }
 
-// AcceptOrder accepts an order
-func (s *YandasService) AcceptOrder(userID uuid.UUID, orderID uuid.UUID) error {
+// AcceptOrder accepts an order and opens its conversation with the customer
+func (s *YandasService) AcceptOrder(userID uuid.UUID, orderID uuid.UUID) (*models.Order, error) {
 	profile, err := s.repos.YandasProfile.GetByUserID(userID)
 	if err != nil {
-		return errors.New("yandaş profile not found")
+		return nil, ErrYandasProfileNotFound
 	}
 
-	order, err := s.repos.Order.GetByID(orderID)
+	order, err := s.repos.OnPrimary().Order.GetByID(orderID)
 	if err != nil {
-		return errors.New("order not found")
+		return nil, ErrOrderNotFound
 	}
 
 	if order.YandasID != profile.ID {
-		return errors.New("unauthorized")
+		return nil, ErrNotOwner
 	}
 
 	if order.Status != "pending" {
-		return errors.New("order cannot be accepted")
+		return nil, conflictError("invalid_order_status", "order cannot be accepted")
+	}
+
+	if onVacation(profile, order.ScheduledAt) {
+		return nil, ErrOnVacation
+	}
+
+	if order.ScheduledAt != nil {
+		conflict, err := hasScheduleConflict(s.repos, profile.ID, order)
+		if err != nil {
+			return nil, err
+		}
+		if conflict {
+			return nil, ErrScheduleConflict
+		}
 	}
 
-	return s.repos.Order.UpdateStatus(orderID, "accepted")
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := lockOrder(tx, orderID, "pending"); err != nil {
+			return err
+		}
+		if err := tx.Order.UpdateStatus(orderID, "accepted"); err != nil {
+			return err
+		}
+		if err := recordOrderEvent(tx, orderID, OrderEventAccepted, "accepted", &profile.UserID, nil); err != nil {
+			return err
+		}
+		if err := s.chat.OpenOrderConversation(tx, order, profile.UserID); err != nil {
+			return err
+		}
+		return s.events.Publish(tx, EventOrderAccepted, orderID, OrderAcceptedEvent{
+			OrderID:      orderID,
+			CustomerID:   order.CustomerID,
+			YandasID:     profile.ID,
+			YandasUserID: profile.UserID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.events.Flush()
+
+	s.chat.PostOrderEvent(order, profile.UserID, OrderEventAccepted, profile.UserID)
+	order.Status = "accepted"
+	s.startOrderETA(profile, order)
+	return order, nil
+}
+
+// notifyOrderAccepted tells the customer their order was accepted
+func (s *YandasService) notifyOrderAccepted(e OrderAcceptedEvent) error {
+	order, err := s.repos.Order.GetByID(e.OrderID)
+	if err != nil {
+		return err
+	}
+	body := "Siparişiniz kabul edildi."
+	if order.Service != nil {
+		body = order.Service.Title + " siparişiniz kabul edildi."
+	}
+	data := map[string]interface{}{"order_id": order.ID, "event": OrderEventAccepted}
+	if order.ConversationID != nil {
+		data["conversation_id"] = *order.ConversationID
+	}
+	return s.notifications.Send(e.CustomerID, "Siparişiniz kabul edildi", body, "order", data)
+}
+
+// hasScheduleConflict reports whether a scheduled order overlaps any accepted or in-progress booking
+func hasScheduleConflict(repos *repository.Repositories, yandasID uuid.UUID, order *models.Order) (bool, error) {
+	start, end := bookingWindow(order)
+
+	booked, err := repos.Order.ListScheduledByYandas(yandasID, start.Add(-maxBookingDuration), end, []string{"accepted", "in_progress"})
+	if err != nil {
+		return false, err
+	}
+
+	for i := range booked {
+		if booked[i].ID == order.ID {
+			continue
+		}
+		otherStart, otherEnd := bookingWindow(&booked[i])
+		if otherStart.Before(end) && start.Before(otherEnd) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// bookingWindow returns the start and end of a scheduled order based on its service duration
+func bookingWindow(order *models.Order) (time.Time, time.Time) {
+	start := *order.ScheduledAt
+	duration := defaultBookingDuration
+	if order.Service.DurationMinutes != nil && *order.Service.DurationMinutes > 0 {
+		duration = time.Duration(*order.Service.DurationMinutes) * time.Minute
+	}
+	return start, start.Add(duration)
+}
+
+// CalendarDay groups a yandaş's bookings by day
+type CalendarDay struct {
+	Date     string         `json:"date"`
+	Bookings []models.Order `json:"bookings"`
+}
+
+// GetCalendar returns pending, accepted and in-progress bookings in [from, to) grouped by day
+func (s *YandasService) GetCalendar(userID uuid.UUID, from, to time.Time) ([]CalendarDay, error) {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, ErrYandasProfileNotFound
+	}
+
+	orders, err := s.repos.Order.ListScheduledByYandas(profile.ID, from, to, []string{"pending", "accepted", "in_progress"})
+	if err != nil {
+		return nil, err
+	}
+
+	days := []CalendarDay{}
+	for _, order := range orders {
+		date := order.ScheduledAt.Format("2006-01-02")
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, CalendarDay{Date: date})
+		}
+		days[len(days)-1].Bookings = append(days[len(days)-1].Bookings, order)
+	}
+
+	return days, nil
 }
 
 // RejectOrder rejects an order
 func (s *YandasService) RejectOrder(userID uuid.UUID, orderID uuid.UUID, reason string) error {
 	profile, err := s.repos.YandasProfile.GetByUserID(userID)
 	if err != nil {
-		return errors.New("yandaş profile not found")
+		return ErrYandasProfileNotFound
 	}
 
-	order, err := s.repos.Order.GetByID(orderID)
+	order, err := s.repos.OnPrimary().Order.GetByID(orderID)
 	if err != nil {
-		return errors.New("order not found")
+		return ErrOrderNotFound
 	}
 
 	if order.YandasID != profile.ID {
-		return errors.New("unauthorized")
+		return ErrNotOwner
 	}
 
 	if order.Status != "pending" {
-		return errors.New("order cannot be rejected")
+		return conflictError("invalid_order_status", "order cannot be rejected")
 	}
 
 	order.Status = "cancelled"
 	order.CancellationReason = &reason
 	order.CancelledBy = &profile.UserID
-	return s.repos.Order.Update(order)
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := lockOrder(tx, orderID, "pending"); err != nil {
+			return err
+		}
+		if err := tx.Order.Update(order); err != nil {
+			return err
+		}
+		return recordOrderEvent(tx, order.ID, OrderEventCancelled, order.Status, &profile.UserID, &reason)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.chat.PostOrderEvent(order, profile.UserID, OrderEventCancelled, profile.UserID)
+	return nil
 }
 
-// StartOrder starts an order
-func (s *YandasService) StartOrder(userID uuid.UUID, orderID uuid.UUID) error {
+// ErrAlreadyEnRoute is returned when the yandaş already reported setting off for an order
+var ErrAlreadyEnRoute = conflictError("already_en_route", "already on the way to this order")
+
+// OnMyWayInput optionally carries the yandaş's current position for the arrival estimate
+type OnMyWayInput struct {
+	Latitude  *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,min=-180,max=180"`
+}
+
+// OnMyWay records that the yandaş set off for an accepted order and tells the
+// customer when they should arrive. The estimate uses the given position or,
+// without one, the yandaş's last known location.
+func (s *YandasService) OnMyWay(userID uuid.UUID, orderID uuid.UUID, input *OnMyWayInput) (*OrderETA, error) {
 	profile, err := s.repos.YandasProfile.GetByUserID(userID)
 	if err != nil {
-		return errors.New("yandaş profile not found")
+		return nil, ErrYandasProfileNotFound
 	}
 
-	order, err := s.repos.Order.GetByID(orderID)
+	order, err := s.repos.OnPrimary().Order.GetByID(orderID)
 	if err != nil {
-		return errors.New("order not found")
+		return nil, ErrOrderNotFound
 	}
 
 	if order.YandasID != profile.ID {
-		return errors.New("unauthorized")
+		return nil, ErrNotOwner
 	}
 
 	if order.Status != "accepted" {
-		return errors.New("order cannot be started")
+		return nil, conflictError("invalid_order_status", "only accepted orders can be set off for")
+	}
+	if order.EnRouteAt != nil {
+		return nil, ErrAlreadyEnRoute
+	}
+
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := lockOrder(tx, orderID, "accepted"); err != nil {
+			return err
+		}
+		marked, err := tx.Order.MarkEnRoute(orderID)
+		if err != nil {
+			return err
+		}
+		if !marked {
+			return ErrAlreadyEnRoute
+		}
+		return recordOrderEvent(tx, orderID, OrderEventEnRoute, "accepted", &profile.UserID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	order.EnRouteAt = &now
+	var eta *OrderETA
+	if input != nil && input.Latitude != nil && input.Longitude != nil {
+		eta = s.refreshOrderETA(order, *input.Latitude, *input.Longitude)
+	} else {
+		eta = s.startOrderETA(profile, order)
 	}
 
-	return s.repos.Order.UpdateStatus(orderID, "in_progress")
+	s.chat.PostOrderEvent(order, profile.UserID, OrderEventEnRoute, profile.UserID)
+	s.notifyEnRoute(order, eta)
+	return eta, nil
 }
 
-// CompleteOrder completes an order
-func (s *YandasService) CompleteOrder(userID uuid.UUID, orderID uuid.UUID, notes string) error {
+// notifyEnRoute tells the customer their yandaş set off, with the arrival
+// estimate when there is one. Failures are logged.
+func (s *YandasService) notifyEnRoute(order *models.Order, eta *OrderETA) {
+	if s.notifications == nil {
+		return
+	}
+	body := "Yandaşınız yola çıktı."
+	data := map[string]interface{}{"order_id": order.ID, "event": OrderEventEnRoute}
+	if eta != nil {
+		body = fmt.Sprintf("Yandaşınız yola çıktı, tahmini varış %d dakika.", eta.Minutes)
+		data["eta_at"] = eta.EtaAt
+		data["eta_minutes"] = eta.Minutes
+	}
+	if err := s.notifications.Send(order.CustomerID, "Yandaşınız yolda", body, "order", data); err != nil {
+		log.Printf("[ORDERS] failed to notify %s that order %s is en route: %v", order.CustomerID, order.ID, err)
+	}
+}
+
+// StartOrder starts an order
+func (s *YandasService) StartOrder(userID uuid.UUID, orderID uuid.UUID) error {
 	profile, err := s.repos.YandasProfile.GetByUserID(userID)
 	if err != nil {
-		return errors.New("yandaş profile not found")
+		return ErrYandasProfileNotFound
 	}
 
-	order, err := s.repos.Order.GetByID(orderID)
+	order, err := s.repos.OnPrimary().Order.GetByID(orderID)
 	if err != nil {
-		return errors.New("order not found")
+		return ErrOrderNotFound
 	}
 
 	if order.YandasID != profile.ID {
-		return errors.New("unauthorized")
+		return ErrNotOwner
 	}
 
-	if order.Status != "in_progress" {
-		return errors.New("order cannot be completed")
+	if order.Status != "accepted" {
+		return conflictError("invalid_order_status", "order cannot be started")
 	}
 
-	now := time.Now()
-	order.Status = "completed"
-	order.CompletedAt = &now
-	order.YandasNotes = &notes
-
-	if err := s.repos.Order.Update(order); err != nil {
+	err = s.repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := lockOrder(tx, orderID, "accepted"); err != nil {
+			return err
+		}
+		if err := tx.Order.UpdateStatus(orderID, "in_progress"); err != nil {
+			return err
+		}
+		return recordOrderEvent(tx, orderID, OrderEventStarted, "in_progress", &profile.UserID, nil)
+	})
+	if err != nil {
 		return err
 	}
 
-	// Update yandaş rating
-	s.repos.YandasProfile.UpdateRating(profile.ID)
-
+	s.chat.PostOrderEvent(order, profile.UserID, OrderEventStarted, profile.UserID)
+	order.Status = "in_progress"
+	s.startOrderETA(profile, order)
 	return nil
 }
 
@@ -377,7 +1040,7 @@ func (s *YandasService) CompleteOrder(userID uuid.UUID, orderID uuid.UUID, notes
 func (s *YandasService) GetStats(userID uuid.UUID) (map[string]interface{}, error) {
 	profile, err := s.repos.YandasProfile.GetByUserID(userID)
 	if err != nil {
-		return nil, errors.New("yandaş profile not found")
+		return nil, ErrYandasProfileNotFound
 	}
 
 	stats, err := s.repos.Order.GetStats(profile.ID)
@@ -391,7 +1054,45 @@ func (s *YandasService) GetStats(userID uuid.UUID) (map[string]interface{}, erro
 	return stats, nil
 }
 
+// EarningsSummary shows what the yandaş has earned and what is still to be paid out
+type EarningsSummary struct {
+	Unpaid  []repository.PayoutBalance `json:"unpaid"`
+	Entries []models.PayoutEntry       `json:"entries"`
+}
+
+// GetEarnings returns unpaid balances and a page of the yandaş's earnings ledger
+func (s *YandasService) GetEarnings(userID uuid.UUID, page, limit int) (*EarningsSummary, int64, error) {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, 0, ErrYandasProfileNotFound
+	}
+
+	balances, err := s.repos.Payout.UnpaidBalances(&profile.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	entries, total, err := s.repos.Payout.ListEntries(profile.ID, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &EarningsSummary{Unpaid: balances, Entries: entries}, total, nil
+}
+
+// GetPayouts returns payouts made to the yandaş
+func (s *YandasService) GetPayouts(userID uuid.UUID, page, limit int) ([]models.Payout, int64, error) {
+	profile, err := s.repos.YandasProfile.GetByUserID(userID)
+	if err != nil {
+		return nil, 0, ErrYandasProfileNotFound
+	}
+	return s.repos.Payout.List(page, limit, "", &profile.ID)
+}
+
 // Search searches yandaş profiles by query
-func (s *YandasService) Search(query string, page, limit int) ([]models.YandasProfile, int64, error) {
-	return s.repos.YandasProfile.Search(query, page, limit)
+func (s *YandasService) Search(query string, page, limit int) ([]PublicYandas, int64, error) {
+	items, total, err := s.repos.YandasProfile.Search(query, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return publicListItems(items), total, nil
 }
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/models"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/repository/mocks"
+	"github.com/yandas/backend/pkg/currency"
+)
+
+func TestListServicesValidatesFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	services := mocks.NewMockServiceRepository(ctrl)
+	svc := NewYandasService(&repository.Repositories{Service: services}, &config.Config{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	low, high := currency.Money(10000), currency.Money(50000)
+	short, long := 30, 120
+
+	invalid := map[string]repository.ServiceFilter{
+		"unknown sort":    {Sort: "newest"},
+		"inverted price":  {MinPrice: &high, MaxPrice: &low},
+		"inverted length": {MinDuration: &long, MaxDuration: &short},
+	}
+	for name, filter := range invalid {
+		if _, _, err := svc.ListServices(filter, 1, 20); !errors.Is(err, ErrInvalidServiceFilter) {
+			t.Errorf("%s: expected ErrInvalidServiceFilter, got %v", name, err)
+		}
+	}
+
+	filter := repository.ServiceFilter{CategorySlug: "temizlik", MinPrice: &low, MaxPrice: &high, Sort: repository.ServiceSortPriceAsc}
+	services.EXPECT().ListPublic(filter, 2, 20).Return([]models.ServiceListItem{{Title: "Ev temizliği"}}, int64(21), nil)
+
+	items, total, err := svc.ListServices(filter, 2, 20)
+	if err != nil || total != 21 || len(items) != 1 {
+		t.Fatalf("expected the repository page, got %d items total=%d err=%v", len(items), total, err)
+	}
+}
+
+func TestUpdateServiceSendsClientVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	profiles := mocks.NewMockYandasProfileRepository(ctrl)
+	services := mocks.NewMockServiceRepository(ctrl)
+	svc := NewYandasService(&repository.Repositories{YandasProfile: profiles, Service: services}, &config.Config{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: uuid.New()}
+	stored := &models.YandasService{ID: uuid.New(), YandasID: profile.ID, BasePrice: 300, Version: 4}
+	profiles.EXPECT().GetByUserID(profile.UserID).Return(profile, nil)
+	services.EXPECT().GetByID(stored.ID).Return(stored, nil)
+	services.EXPECT().Update(gomock.Any()).DoAndReturn(func(service *models.YandasService) error {
+		if service.Version != 3 {
+			t.Errorf("expected the update to be checked against the client's version 3, got %d", service.Version)
+		}
+		return repository.ErrStaleVersion
+	})
+
+	version := 3
+	_, err := svc.UpdateService(profile.UserID, stored.ID, &ServiceInput{Title: "Boya", BasePrice: 300, Version: &version})
+	if !errors.Is(err, repository.ErrStaleVersion) {
+		t.Errorf("expected ErrStaleVersion, got %v", err)
+	}
+}
+
+func TestArchiveAndDeleteService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	profiles := mocks.NewMockYandasProfileRepository(ctrl)
+	services := mocks.NewMockServiceRepository(ctrl)
+	orders := mocks.NewMockOrderRepository(ctrl)
+	svc := NewYandasService(&repository.Repositories{YandasProfile: profiles, Service: services, Order: orders}, &config.Config{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	profile := &models.YandasProfile{ID: uuid.New(), UserID: uuid.New()}
+	stored := &models.YandasService{ID: uuid.New(), YandasID: profile.ID, BasePrice: 30000, IsActive: true}
+	profiles.EXPECT().GetByUserID(profile.UserID).Return(profile, nil).AnyTimes()
+	services.EXPECT().GetByID(stored.ID).Return(stored, nil).AnyTimes()
+
+	orders.EXPECT().CountActiveByService(stored.ID).Return(int64(1), nil)
+	if err := svc.DeleteService(profile.UserID, stored.ID); !errors.Is(err, ErrServiceInUse) {
+		t.Errorf("expected ErrServiceInUse, got %v", err)
+	}
+
+	services.EXPECT().Update(stored).Return(nil)
+	archived, err := svc.ArchiveService(profile.UserID, stored.ID)
+	if err != nil || archived.IsActive || archived.ArchivedAt == nil {
+		t.Fatalf("expected the service to be archived, got %+v, %v", archived, err)
+	}
+	if _, err := svc.ArchiveService(profile.UserID, stored.ID); !errors.Is(err, ErrServiceArchived) {
+		t.Errorf("expected ErrServiceArchived, got %v", err)
+	}
+
+	orders.EXPECT().CountActiveByService(stored.ID).Return(int64(0), nil)
+	services.EXPECT().Delete(stored.ID).Return(nil)
+	if err := svc.DeleteService(profile.UserID, stored.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deleted := time.Now()
+	stored.DeletedAt = &deleted
+	if _, err := svc.UnarchiveService(profile.UserID, stored.ID); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("expected a deleted service to be gone, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+// Package testutil holds fixtures shared by integration tests and the local
+// seeding tools.
+package testutil
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yandas/backend/internal/models"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// DefaultPassword is the password of every user created by a Factory
+const DefaultPassword = "Test1234!"
+
+// Factory inserts valid rows with sensible defaults; each helper takes
+// optional overrides that run before the insert
+type Factory struct {
+	DB *gorm.DB
+
+	seq          atomic.Int64
+	passwordHash string
+}
+
+// NewFactory returns a factory writing to db
+func NewFactory(db *gorm.DB) *Factory {
+	return &Factory{DB: db}
+}
+
+func (f *Factory) next() int64 {
+	return f.seq.Add(1)
+}
+
+func (f *Factory) hash() (string, error) {
+	if f.passwordHash == "" {
+		// MinCost keeps fixtures fast; login only compares hashes
+		hash, err := bcrypt.GenerateFromPassword([]byte(DefaultPassword), bcrypt.MinCost)
+		if err != nil {
+			return "", err
+		}
+		f.passwordHash = string(hash)
+	}
+	return f.passwordHash, nil
+}
+
+// User creates a verified, active customer
+func (f *Factory) User(overrides ...func(*models.User)) (*models.User, error) {
+	hash, err := f.hash()
+	if err != nil {
+		return nil, err
+	}
+	n := f.next()
+	email := fmt.Sprintf("user%d@test.com", n)
+	phone := fmt.Sprintf("555%07d", n)
+	user := &models.User{
+		Email:        &email,
+		Phone:        &phone,
+		PasswordHash: hash,
+		FullName:     fmt.Sprintf("Test Kullanıcı %d", n),
+		Role:         "customer",
+		IsVerified:   true,
+		IsActive:     true,
+	}
+	for _, override := range overrides {
+		override(user)
+	}
+	if err := f.DB.Create(user).Error; err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Yandas creates a user with the yandas role and an approved, available profile
+func (f *Factory) Yandas(overrides ...func(*models.YandasProfile)) (*models.YandasProfile, error) {
+	user, err := f.User(func(u *models.User) { u.Role = "yandas" })
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	profile := &models.YandasProfile{
+		UserID:         user.ID,
+		ApprovalStatus: "approved",
+		ApprovedAt:     &now,
+		IsAvailable:    true,
+		ServiceCities:  pq.StringArray{"İstanbul"},
+	}
+	for _, override := range overrides {
+		override(profile)
+	}
+	if err := f.DB.Create(profile).Error; err != nil {
+		return nil, err
+	}
+	// gorm skips zero values that have a column default
+	if !profile.IsAvailable {
+		if err := f.DB.Model(profile).Update("is_available", false).Error; err != nil {
+			return nil, err
+		}
+	}
+	profile.User = *user
+	return profile, nil
+}
+
+// Category creates an active top-level category
+func (f *Factory) Category(overrides ...func(*models.Category)) (*models.Category, error) {
+	n := f.next()
+	category := &models.Category{
+		Slug:      fmt.Sprintf("kategori-%d", n),
+		Name:      fmt.Sprintf("Kategori %d", n),
+		IsActive:  true,
+		SortOrder: int(n),
+	}
+	for _, override := range overrides {
+		override(category)
+	}
+	if err := f.DB.Create(category).Error; err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// Service creates an active service for the yandaş, with a fresh category
+// unless the overrides set one
+func (f *Factory) Service(yandas *models.YandasProfile, overrides ...func(*models.YandasService)) (*models.YandasService, error) {
+	minutes := 60
+	service := &models.YandasService{
+		YandasID:        yandas.ID,
+		Title:           "Test Hizmeti",
+		BasePrice:       50000,
+		Currency:        "TRY",
+		DurationMinutes: &minutes,
+		IsActive:        true,
+		Status:          "active",
+	}
+	for _, override := range overrides {
+		override(service)
+	}
+	if service.CategoryID == uuid.Nil {
+		category, err := f.Category()
+		if err != nil {
+			return nil, err
+		}
+		service.CategoryID = category.ID
+	}
+	if err := f.DB.Create(service).Error; err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// Order creates a pending order for the service at its base price
+func (f *Factory) Order(customer *models.User, service *models.YandasService, overrides ...func(*models.Order)) (*models.Order, error) {
+	order := &models.Order{
+		OrderNumber: fmt.Sprintf("TST%d", f.next()),
+		CustomerID:  customer.ID,
+		YandasID:    service.YandasID,
+		ServiceID:   service.ID,
+		Status:      "pending",
+		AgreedPrice: service.BasePrice,
+		Currency:    "TRY",
+	}
+	for _, override := range overrides {
+		override(order)
+	}
+	if err := f.DB.Create(order).Error; err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Truncate empties every table except the migration bookkeeping so each test
+// starts from a clean database
+func Truncate(db *gorm.DB) error {
+	var tables []string
+	err := db.Raw(`SELECT tablename FROM pg_tables
+		WHERE schemaname = current_schema() AND tablename <> 'schema_migrations'`).Scan(&tables).Error
+	if err != nil || len(tables) == 0 {
+		return err
+	}
+	quoted := make([]string, len(tables))
+	for i, table := range tables {
+		quoted[i] = pq.QuoteIdentifier(table)
+	}
+	return db.Exec("TRUNCATE " + strings.Join(quoted, ", ") + " RESTART IDENTITY CASCADE").Error
+}
@@ -0,0 +1,256 @@
+//go:build integration
+
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/redis/go-redis/v9"
+	"github.com/yandas/backend/internal/config"
+	"github.com/yandas/backend/internal/database"
+	"github.com/yandas/backend/internal/handlers"
+	"github.com/yandas/backend/internal/repository"
+	"github.com/yandas/backend/internal/server"
+	"github.com/yandas/backend/internal/services"
+	"github.com/yandas/backend/internal/websocket"
+	"github.com/yandas/backend/pkg/envelope"
+	"gorm.io/gorm"
+)
+
+// Environment is a migrated Postgres and a Redis running in Docker, shared by
+// every test of a package
+type Environment struct {
+	Config *config.Config
+	DB     *gorm.DB
+	Redis  *redis.Client
+
+	pool      *dockertest.Pool
+	resources []*dockertest.Resource
+}
+
+// Run starts the containers, runs the package's tests and tears everything
+// down; call it from TestMain. Tests are skipped when Docker is unreachable.
+func Run(m *testing.M, env **Environment) int {
+	started, err := start()
+	if err != nil {
+		log.Printf("skipping integration tests: %v", err)
+		return 0
+	}
+	defer started.stop()
+	*env = started
+	return m.Run()
+}
+
+func start() (*Environment, error) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.Client.Ping(); err != nil {
+		return nil, fmt.Errorf("docker is not available: %w", err)
+	}
+	pool.MaxWait = 2 * time.Minute
+
+	env := &Environment{pool: pool}
+	hostConfig := func(c *docker.HostConfig) {
+		c.AutoRemove = true
+		c.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	}
+
+	postgres, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "postgres",
+		Tag:        "16-alpine",
+		Env:        []string{"POSTGRES_USER=yandas", "POSTGRES_PASSWORD=yandas", "POSTGRES_DB=yandas"},
+	}, hostConfig)
+	if err != nil {
+		return nil, err
+	}
+	env.resources = append(env.resources, postgres)
+
+	redisResource, err := pool.RunWithOptions(&dockertest.RunOptions{Repository: "redis", Tag: "7-alpine"}, hostConfig)
+	if err != nil {
+		env.stop()
+		return nil, err
+	}
+	env.resources = append(env.resources, redisResource)
+
+	cfg := config.Load()
+	cfg.GinMode = gin.TestMode
+	cfg.DBHost = "localhost"
+	cfg.DatabaseURL = fmt.Sprintf("postgres://yandas:yandas@%s/yandas?sslmode=disable", postgres.GetHostPort("5432/tcp"))
+	cfg.RedisURL = "redis://" + redisResource.GetHostPort("6379/tcp")
+	cfg.StoragePath = os.TempDir()
+	env.Config = cfg
+
+	if err := pool.Retry(func() error {
+		db, err := database.Connect(cfg)
+		if err != nil {
+			return err
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		if err := sqlDB.Ping(); err != nil {
+			return err
+		}
+		env.DB = db
+		return nil
+	}); err != nil {
+		env.stop()
+		return nil, err
+	}
+	if err := pool.Retry(func() error {
+		client, err := database.ConnectRedis(cfg)
+		if err != nil {
+			return err
+		}
+		env.Redis = client
+		return nil
+	}); err != nil {
+		env.stop()
+		return nil, err
+	}
+
+	if err := database.Migrate(env.DB); err != nil {
+		env.stop()
+		return nil, err
+	}
+	return env, nil
+}
+
+func (e *Environment) stop() {
+	for _, resource := range e.resources {
+		if err := e.pool.Purge(resource); err != nil {
+			log.Printf("failed to remove container: %v", err)
+		}
+	}
+}
+
+// App is the full API wired against the environment
+type App struct {
+	T        *testing.T
+	Services *services.Services
+	Factory  *Factory
+	Router   *gin.Engine
+}
+
+// NewApp empties the database and Redis and builds the router the way the API
+// server does
+func (e *Environment) NewApp(t *testing.T) *App {
+	t.Helper()
+	if err := Truncate(e.DB); err != nil {
+		t.Fatalf("failed to truncate tables: %v", err)
+	}
+	if err := e.Redis.FlushDB(context.Background()).Err(); err != nil {
+		t.Fatalf("failed to flush redis: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	masterKey, err := envelope.NewMasterKey(e.Config.MessageKeyProvider, e.Config.MessageKeyID, e.Config.MessageMasterKey)
+	if err != nil {
+		t.Fatalf("failed to load message master key: %v", err)
+	}
+	repos := repository.NewRepositories(e.DB, repository.NewMessageKeys(masterKey))
+	svcs := services.NewServices(repos, e.Config, e.Redis)
+	if err := svcs.Permission.EnsureSystemRoles(); err != nil {
+		t.Fatalf("failed to seed roles: %v", err)
+	}
+
+	wsHub := websocket.NewHub(websocket.NewEventStore(e.Redis), svcs.RoomAccess)
+	svcs.Favorite.SetBroadcaster(wsHub)
+	svcs.JobRequest.SetBroadcaster(wsHub)
+	svcs.AutoAssign.SetBroadcaster(wsHub)
+	svcs.Chat.SetBroadcaster(wsHub)
+	svcs.Yandas.SetBroadcaster(wsHub)
+	svcs.Settings.SetBroadcaster(wsHub)
+	svcs.Health.SetHub(wsHub)
+	svcs.Outbox.SetBroadcaster(wsHub)
+	svcs.Call.SetBroadcaster(wsHub)
+
+	h := handlers.NewHandlers(svcs, e.Config, wsHub, e.DB)
+	return &App{
+		T:        t,
+		Services: svcs,
+		Factory:  NewFactory(e.DB),
+		Router:   server.NewRouter(e.Config, svcs, h, wsHub, e.Redis),
+	}
+}
+
+// Response is a recorded API response with the standard envelope decoded
+type Response struct {
+	Status  int
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+}
+
+// Decode unmarshals the response data into v
+func (r *Response) Decode(t *testing.T, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(r.Data, v); err != nil {
+		t.Fatalf("failed to decode %s: %v", r.Data, err)
+	}
+}
+
+// Do sends a JSON request to the router; token may be empty
+func (a *App) Do(method, path, token string, body interface{}) *Response {
+	a.T.Helper()
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			a.T.Fatal(err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Platform", "test")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	a.Router.ServeHTTP(rec, req)
+
+	res := &Response{Status: rec.Code}
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), res); err != nil {
+			a.T.Fatalf("%s %s returned a non-JSON body: %s", method, path, rec.Body.String())
+		}
+	}
+	return res
+}
+
+// Login signs the user in with the factory password and returns the access token
+func (a *App) Login(email string) string {
+	a.T.Helper()
+	res := a.Do(http.MethodPost, "/api/v1/auth/login", "", map[string]string{
+		"email":    email,
+		"password": DefaultPassword,
+	})
+	if res.Status != http.StatusOK {
+		a.T.Fatalf("login as %s failed with %d: %s", email, res.Status, res.Error)
+	}
+	var data struct {
+		Tokens struct {
+			AccessToken string `json:"access_token"`
+		} `json:"tokens"`
+	}
+	res.Decode(a.T, &data)
+	return data.Tokens.AccessToken
+}
@@ -0,0 +1,200 @@
+// Package validation turns request binding failures into machine-readable
+// per-field errors and registers the custom validators used in binding tags:
+//
+//	tr_phone  a Turkish mobile number (5xx xxx xx xx with +90, 90 or 0 in front)
+//	tckn      a TC kimlik number with valid check digits
+//	currency  a currency code the platform accepts, in any case
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/yandas/backend/pkg/currency"
+	"github.com/yandas/backend/pkg/ocr"
+)
+
+// Error codes returned to clients
+const (
+	CodeRequired      = "required"
+	CodeInvalidFormat = "invalid_format"
+	CodeInvalidType   = "invalid_type"
+	CodeInvalidChoice = "invalid_choice"
+	CodeTooShort      = "too_short"
+	CodeTooLong       = "too_long"
+	CodeTooSmall      = "too_small"
+	CodeTooLarge      = "too_large"
+	CodeInvalidLength = "invalid_length"
+	CodeInvalid       = "invalid"
+	CodeInvalidBody   = "invalid_body"
+)
+
+// CodeValidationFailed is the top-level code of a response carrying field errors
+const CodeValidationFailed = "validation_failed"
+
+// FieldError is one problem with a request field. Field is the JSON path,
+// e.g. "price_tiers[1].up_to", and empty when the body as a whole is unreadable.
+type FieldError struct {
+	Field string `json:"field,omitempty"`
+	Code  string `json:"code"`
+	Param string `json:"param,omitempty"` // the limit or choices of the failed rule
+}
+
+var formatTags = map[string]bool{
+	"email": true, "url": true, "uri": true, "uuid": true, "uuid4": true, "datetime": true,
+	"e164": true, "numeric": true, "alphanum": true, "hexcolor": true,
+	"tr_phone": true, "tckn": true,
+}
+
+var register sync.Once
+
+// Register installs the custom validators and makes field errors use JSON
+// names. It is safe to call more than once.
+func Register() {
+	register.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				name = strings.SplitN(field.Tag.Get("form"), ",", 2)[0]
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+		v.RegisterValidation("tr_phone", func(fl validator.FieldLevel) bool {
+			return IsTurkishMobile(fl.Field().String())
+		})
+		v.RegisterValidation("tckn", func(fl validator.FieldLevel) bool {
+			return ocr.ValidTCKN(fl.Field().String())
+		})
+		v.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+			return currency.IsSupported(fl.Field().String())
+		})
+	})
+}
+
+// IsTurkishMobile reports whether phone is a Turkish mobile number. Spaces,
+// dashes and parentheses are ignored.
+func IsTurkishMobile(phone string) bool {
+	phone = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(phone)
+	for _, prefix := range []string{"+90", "90", "0"} {
+		if len(phone) == 10+len(prefix) && strings.HasPrefix(phone, prefix) {
+			phone = phone[len(prefix):]
+			break
+		}
+	}
+	if len(phone) != 10 || phone[0] != '5' {
+		return false
+	}
+	for _, r := range phone {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Errors converts a binding error into field errors
+func Errors(err error) []FieldError {
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		out := make([]FieldError, 0, len(fieldErrs))
+		for _, fe := range fieldErrs {
+			out = append(out, FieldError{Field: fieldPath(fe.Namespace()), Code: code(fe), Param: fe.Param()})
+		}
+		return out
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []FieldError{{Field: typeErr.Field, Code: CodeInvalidType, Param: typeErr.Type.String()}}
+	}
+	var timeErr *time.ParseError
+	if errors.As(err, &timeErr) {
+		return []FieldError{{Code: CodeInvalidFormat, Param: "RFC3339"}}
+	}
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return []FieldError{{Code: CodeInvalidType}}
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return []FieldError{{Code: CodeInvalidBody}}
+	}
+	return []FieldError{{Code: CodeInvalid}}
+}
+
+// Message summarizes field errors in one line for clients that only read the error string
+func Message(errs []FieldError) string {
+	parts := make([]string, 0, len(errs))
+	for _, fe := range errs {
+		switch {
+		case fe.Field == "" && fe.Code == CodeInvalidBody:
+			parts = append(parts, "request body is not valid JSON")
+		case fe.Field == "":
+			parts = append(parts, "request is invalid: "+fe.Code)
+		case fe.Param != "" && fe.Code != CodeInvalidFormat:
+			parts = append(parts, fe.Field+": "+fe.Code+" ("+fe.Param+")")
+		default:
+			parts = append(parts, fe.Field+": "+fe.Code)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// fieldPath drops the struct name from a namespace such as "RegisterInput.email"
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+// code maps a failed validation tag to an error code
+func code(fe validator.FieldError) string {
+	tag := fe.Tag()
+	if formatTags[tag] {
+		return CodeInvalidFormat
+	}
+
+	sized := false
+	switch fe.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		sized = true
+	}
+	switch tag {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return CodeRequired
+	case "oneof", "currency":
+		return CodeInvalidChoice
+	case "len":
+		return CodeInvalidLength
+	case "min", "gte", "gt":
+		if sized {
+			return CodeTooShort
+		}
+		return CodeTooSmall
+	case "max", "lte", "lt":
+		if sized {
+			return CodeTooLong
+		}
+		return CodeTooLarge
+	}
+	return CodeInvalid
+}
@@ -0,0 +1,82 @@
+package validation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+type tierInput struct {
+	UpTo float64 `json:"up_to" binding:"gt=0"`
+}
+
+type signupInput struct {
+	Email    string      `json:"email" binding:"required,email"`
+	Phone    string      `json:"phone" binding:"omitempty,tr_phone"`
+	Password string      `json:"password" binding:"required,min=6"`
+	Kimlik   string      `json:"kimlik" binding:"omitempty,tckn"`
+	Role     string      `json:"role" binding:"omitempty,oneof=customer yandas"`
+	Currency string      `json:"currency" binding:"omitempty,currency"`
+	Tiers    []tierInput `json:"tiers" binding:"dive"`
+}
+
+func bind(t *testing.T, body string) error {
+	t.Helper()
+	Register()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	var input signupInput
+	return binding.JSON.Bind(req, &input)
+}
+
+func TestErrors(t *testing.T) {
+	err := bind(t, `{"email":"nope","phone":"+1 555 0100","password":"123","kimlik":"10000000147","role":"admin","currency":"XYZ","tiers":[{"up_to":5},{"up_to":0}]}`)
+	want := []FieldError{
+		{Field: "email", Code: CodeInvalidFormat},
+		{Field: "phone", Code: CodeInvalidFormat},
+		{Field: "password", Code: CodeTooShort, Param: "6"},
+		{Field: "kimlik", Code: CodeInvalidFormat},
+		{Field: "role", Code: CodeInvalidChoice, Param: "customer yandas"},
+		{Field: "currency", Code: CodeInvalidChoice},
+		{Field: "tiers[1].up_to", Code: CodeTooSmall, Param: "0"},
+	}
+	if got := Errors(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Errors() = %+v, want %+v", got, want)
+	}
+
+	if got := Errors(bind(t, `{"phone":"0532 123 45 67","password":"secret1"}`)); !reflect.DeepEqual(got, []FieldError{{Field: "email", Code: CodeRequired}}) {
+		t.Errorf("expected only the missing email, got %+v", got)
+	}
+	if got := Errors(bind(t, `{"email":"a@b.co","password":12}`)); len(got) != 1 || got[0].Field != "password" || got[0].Code != CodeInvalidType {
+		t.Errorf("expected a type error on password, got %+v", got)
+	}
+	if got := Errors(bind(t, `{"email":`)); len(got) != 1 || got[0].Code != CodeInvalidBody {
+		t.Errorf("expected an unreadable body, got %+v", got)
+	}
+	if err := bind(t, `{"email":"a@b.co","password":"secret1","kimlik":"10000000146","currency":"eur"}`); err != nil {
+		t.Errorf("expected a valid request, got %v", err)
+	}
+}
+
+func TestIsTurkishMobile(t *testing.T) {
+	cases := map[string]bool{
+		"+905321234567":    true,
+		"905321234567":     true,
+		"05321234567":      true,
+		"5321234567":       true,
+		"0 (532) 123-4567": true,
+		"02121234567":      false, // landline
+		"+15550100123":     false,
+		"053212345678":     false,
+		"05321234a67":      false,
+	}
+	for phone, want := range cases {
+		if got := IsTurkishMobile(phone); got != want {
+			t.Errorf("IsTurkishMobile(%q) = %v, want %v", phone, got, want)
+		}
+	}
+}
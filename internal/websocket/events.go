@@ -0,0 +1,168 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// Number of recent events kept per user room for replay after a reconnect.
+	eventBufferSize = 100
+	// Buffered events and sequence counters expire after this much inactivity.
+	eventBufferTTL = 24 * time.Hour
+)
+
+// EventStore assigns per-room sequence numbers and keeps the last events of each
+// room so reconnecting clients can resume where they left off.
+type EventStore interface {
+	// Append sets msg.Seq and buffers the encoded message, which it returns.
+	Append(room string, msg *Message) ([]byte, error)
+	// Since returns buffered events with a sequence above after, oldest first.
+	// complete is false when events after it were already dropped from the buffer.
+	Since(room string, after int64) (events [][]byte, complete bool, err error)
+}
+
+// NewEventStore returns a Redis-backed store, or an in-process one when Redis is unavailable
+func NewEventStore(client *redis.Client) EventStore {
+	if client == nil {
+		return NewMemoryEventStore()
+	}
+	return &redisEventStore{client: client}
+}
+
+type redisEventStore struct {
+	client *redis.Client
+}
+
+func (s *redisEventStore) Append(room string, msg *Message) ([]byte, error) {
+	ctx := context.Background()
+	seqKey, listKey := "ws:seq:"+room, "ws:events:"+room
+
+	seq, err := s.client.Incr(ctx, seqKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	msg.Seq = seq
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.RPush(ctx, listKey, data)
+	pipe.LTrim(ctx, listKey, -eventBufferSize, -1)
+	pipe.Expire(ctx, listKey, eventBufferTTL)
+	pipe.Expire(ctx, seqKey, eventBufferTTL)
+	_, err = pipe.Exec(ctx)
+	return data, err
+}
+
+func (s *redisEventStore) Since(room string, after int64) ([][]byte, bool, error) {
+	ctx := context.Background()
+	current, err := s.client.Get(ctx, "ws:seq:"+room).Int64()
+	if err != nil && err != redis.Nil {
+		return nil, false, err
+	}
+	raw, err := s.client.LRange(ctx, "ws:events:"+room, 0, -1).Result()
+	if err != nil {
+		return nil, false, err
+	}
+
+	entries := make([]bufferedEvent, 0, len(raw))
+	for _, r := range raw {
+		var head struct {
+			Seq int64 `json:"seq"`
+		}
+		if json.Unmarshal([]byte(r), &head) == nil {
+			entries = append(entries, bufferedEvent{seq: head.Seq, data: []byte(r)})
+		}
+	}
+	// Concurrent appends can land in the list slightly out of order
+	sort.Slice(entries, func(i, j int) bool { return entries[i].seq < entries[j].seq })
+	events, complete := eventsAfter(entries, current, after)
+	return events, complete, nil
+}
+
+type bufferedEvent struct {
+	seq  int64
+	data []byte
+}
+
+// eventsAfter picks the events following after from a seq-ordered buffer. A client
+// ahead of the counter (it expired and restarted) or behind the oldest buffered
+// event cannot be caught up from the buffer.
+func eventsAfter(entries []bufferedEvent, current, after int64) ([][]byte, bool) {
+	if after > current {
+		return nil, false
+	}
+	if after == current {
+		return nil, true
+	}
+
+	complete := len(entries) > 0 && entries[0].seq <= after+1
+	var events [][]byte
+	for _, e := range entries {
+		if e.seq > after {
+			events = append(events, e.data)
+		}
+	}
+	return events, complete
+}
+
+// MemoryEventStore buffers events in process memory. It is used when Redis is not
+// configured and only replays events sent through the same server instance.
+type MemoryEventStore struct {
+	mu    sync.Mutex
+	rooms map[string]*memoryRoom
+}
+
+type memoryRoom struct {
+	seq     int64
+	entries []bufferedEvent
+}
+
+// NewMemoryEventStore creates an empty in-process event store
+func NewMemoryEventStore() *MemoryEventStore {
+	return &MemoryEventStore{rooms: make(map[string]*memoryRoom)}
+}
+
+func (s *MemoryEventStore) Append(room string, msg *Message) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.rooms[room]
+	if r == nil {
+		r = &memoryRoom{}
+		s.rooms[room] = r
+	}
+	r.seq++
+	msg.Seq = r.seq
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	r.entries = append(r.entries, bufferedEvent{seq: r.seq, data: data})
+	if len(r.entries) > eventBufferSize {
+		r.entries = r.entries[len(r.entries)-eventBufferSize:]
+	}
+	return data, nil
+}
+
+func (s *MemoryEventStore) Since(room string, after int64) ([][]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.rooms[room]
+	if r == nil {
+		events, complete := eventsAfter(nil, 0, after)
+		return events, complete, nil
+	}
+	events, complete := eventsAfter(r.entries, r.seq, after)
+	return events, complete, nil
+}
@@ -0,0 +1,103 @@
+package websocket
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMemoryEventStoreResume(t *testing.T) {
+	store := NewMemoryEventStore()
+	for i := 0; i < 3; i++ {
+		msg := &Message{Type: "order_update", Room: "user:1"}
+		if _, err := store.Append("user:1", msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Seq != int64(i+1) {
+			t.Fatalf("expected seq %d, got %d", i+1, msg.Seq)
+		}
+	}
+
+	events, complete, err := store.Since("user:1", 1)
+	if err != nil || !complete || len(events) != 2 {
+		t.Fatalf("expected 2 complete events, got %d complete=%v err=%v", len(events), complete, err)
+	}
+	var first Message
+	if err := json.Unmarshal(events[0], &first); err != nil || first.Seq != 2 {
+		t.Fatalf("expected seq 2 first, got %+v %v", first, err)
+	}
+
+	if events, complete, _ := store.Since("user:1", 3); len(events) != 0 || !complete {
+		t.Errorf("expected an up-to-date client to get nothing, got %d complete=%v", len(events), complete)
+	}
+	if _, complete, _ := store.Since("user:1", 10); complete {
+		t.Error("expected a client ahead of the counter to resync")
+	}
+}
+
+func TestMemoryEventStoreDropsOldEvents(t *testing.T) {
+	store := NewMemoryEventStore()
+	for i := 0; i < eventBufferSize+5; i++ {
+		store.Append("user:1", &Message{Type: "notification"})
+	}
+
+	events, complete, _ := store.Since("user:1", 2)
+	if complete {
+		t.Error("expected resync when the buffer no longer reaches back to the client's seq")
+	}
+	if len(events) != eventBufferSize {
+		t.Errorf("expected the %d buffered events, got %d", eventBufferSize, len(events))
+	}
+
+	if _, complete, _ := store.Since("user:1", 5); !complete {
+		t.Error("expected a client at the edge of the buffer to be caught up")
+	}
+}
+
+func TestClientAckResend(t *testing.T) {
+	c := &Client{Protocol: ProtocolV2, pending: make(map[int64]*pendingAck)}
+	c.expectAck(7, []byte("incoming_call"))
+	c.pending[7].sentAt = c.pending[7].sentAt.Add(-ackTimeout)
+
+	if due := c.dueForResend(); len(due) != 1 {
+		t.Fatalf("expected 1 overdue event, got %d", len(due))
+	}
+	c.acknowledge(7)
+	if len(c.pending) != 0 {
+		t.Error("expected ack to clear the pending event")
+	}
+
+	v1 := &Client{pending: make(map[int64]*pendingAck)}
+	v1.expectAck(8, []byte("incoming_call"))
+	if len(v1.pending) != 0 {
+		t.Error("expected v1 clients not to be tracked for acks")
+	}
+}
+
+func TestBroadcastStripsSequenceForV1Clients(t *testing.T) {
+	hub := NewHub(NewMemoryEventStore(), nil)
+	v1 := &Client{Send: make(chan []byte, 1), Rooms: make(map[string]bool), pending: make(map[int64]*pendingAck)}
+	v2 := &Client{Protocol: ProtocolV2, Send: make(chan []byte, 1), Rooms: make(map[string]bool), pending: make(map[int64]*pendingAck)}
+	hub.JoinRoom(v1, "user:1")
+	hub.JoinRoom(v2, "user:1")
+
+	msg := &Message{Type: "incoming_call", Room: "user:1", Payload: map[string]string{"call_id": "c1"}, AckRequired: true}
+	data, err := hub.store.Append(msg.Room, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg.data = data
+	hub.broadcastMessage(msg)
+
+	var got Message
+	if err := json.Unmarshal(<-v2.Send, &got); err != nil || got.Seq != 1 || !got.AckRequired {
+		t.Errorf("expected a sequenced event for the v2 client, got %+v (%v)", got, err)
+	}
+	if len(v2.pending) != 1 {
+		t.Error("expected the v2 client to be tracked for the ack")
+	}
+	raw := string(<-v1.Send)
+	if strings.Contains(raw, `"seq"`) || strings.Contains(raw, `"ack_required"`) || !strings.Contains(raw, `"call_id":"c1"`) {
+		t.Errorf("expected a plain v1 event, got %s", raw)
+	}
+}
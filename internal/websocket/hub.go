@@ -11,9 +11,14 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+// ProtocolV2 is the subprotocol of clients that understand sequence numbers,
+// resume and acks. Other clients keep receiving plain v1 messages.
+const ProtocolV2 = "yandas.v2"
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
+	Subprotocols:    []string{ProtocolV2},
 	CheckOrigin: func(r *http.Request) bool {
 		return true
 	},
@@ -26,16 +31,42 @@ const (
 	pongWait = 60 * time.Second
 	// Send pings to peer with this period. Must be less than pongWait.
 	pingPeriod = 30 * time.Second
+	// Unacknowledged critical events are resent after this long.
+	ackTimeout = 5 * time.Second
+	// Critical events are given up on after this many sends.
+	maxAckAttempts = 3
 )
 
+// criticalEvents must be acknowledged by v2 clients and are resent until they are
+var criticalEvents = map[string]bool{
+	"incoming_call": true,
+	"call_answered": true,
+	"call_rejected": true,
+	"call_ended":    true,
+}
+
 type Client struct {
-	ID     string
-	UserID string
-	Hub    *Hub
-	Conn   *websocket.Conn
-	Send   chan []byte
-	Rooms  map[string]bool
-	mu     sync.Mutex
+	ID       string
+	UserID   string
+	Protocol string
+	Hub      *Hub
+	Conn     *websocket.Conn
+	Send     chan []byte
+	Rooms    map[string]bool
+	pending  map[int64]*pendingAck
+	mu       sync.Mutex
+}
+
+// pendingAck is a critical event sent to a v2 client and not yet acknowledged
+type pendingAck struct {
+	data     []byte
+	sentAt   time.Time
+	attempts int
+}
+
+// RoomAuthorizer decides whether a user may join a room
+type RoomAuthorizer interface {
+	CanJoinRoom(userID, room string) bool
 }
 
 type Hub struct {
@@ -44,22 +75,33 @@ type Hub struct {
 	unregister chan *Client
 	broadcast  chan *Message
 	rooms      map[string]map[*Client]bool
+	store      EventStore
+	authorizer RoomAuthorizer
 	mu         sync.RWMutex
 }
 
 type Message struct {
-	Type    string      `json:"type"`
-	Room    string      `json:"room,omitempty"`
-	Payload interface{} `json:"payload"`
+	Type        string      `json:"type"`
+	Room        string      `json:"room,omitempty"`
+	Seq         int64       `json:"seq,omitempty"`
+	AckRequired bool        `json:"ack_required,omitempty"`
+	Payload     interface{} `json:"payload"`
+
+	// data is the encoded message when it was already serialized for the event buffer
+	data []byte
 }
 
-func NewHub() *Hub {
+// NewHub creates a hub; events sent to user rooms are sequenced and buffered in store,
+// and client join requests are checked with authorizer
+func NewHub(store EventStore, authorizer RoomAuthorizer) *Hub {
 	return &Hub{
 		clients:    make(map[*Client]bool),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		broadcast:  make(chan *Message),
 		rooms:      make(map[string]map[*Client]bool),
+		store:      store,
+		authorizer: authorizer,
 	}
 }
 
@@ -90,7 +132,15 @@ func (h *Hub) Run() {
 }
 
 func (h *Hub) broadcastMessage(msg *Message) {
-	data, _ := json.Marshal(msg)
+	data := msg.data
+	if data == nil {
+		data, _ = json.Marshal(msg)
+	}
+	// v1 clients get the event without the sequence number and ack flag
+	legacy := data
+	if msg.Seq != 0 || msg.AckRequired {
+		legacy, _ = json.Marshal(&Message{Type: msg.Type, Room: msg.Room, Payload: msg.Payload})
+	}
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
@@ -99,8 +149,15 @@ func (h *Hub) broadcastMessage(msg *Message) {
 			log.Printf("[WS] Broadcasting type=%s to room=%s, %d clients", msg.Type, msg.Room, len(clients))
 			for client := range clients {
 				log.Printf("[WS]   -> Sending to client UserID=%s", client.UserID)
+				out := legacy
+				if client.Protocol == ProtocolV2 {
+					out = data
+					if msg.AckRequired {
+						client.expectAck(msg.Seq, data)
+					}
+				}
 				select {
-				case client.Send <- data:
+				case client.Send <- out:
 				default:
 					close(client.Send)
 					delete(h.clients, client)
@@ -115,8 +172,12 @@ func (h *Hub) broadcastMessage(msg *Message) {
 	} else {
 		log.Printf("[WS] Broadcasting type=%s to ALL %d clients (no room)", msg.Type, len(h.clients))
 		for client := range h.clients {
+			out := legacy
+			if client.Protocol == ProtocolV2 {
+				out = data
+			}
 			select {
-			case client.Send <- data:
+			case client.Send <- out:
 			default:
 				close(client.Send)
 				delete(h.clients, client)
@@ -125,6 +186,13 @@ func (h *Hub) broadcastMessage(msg *Message) {
 	}
 }
 
+// ClientCount returns the number of connected clients
+func (h *Hub) ClientCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
 func (h *Hub) JoinRoom(client *Client, room string) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -135,13 +203,48 @@ func (h *Hub) JoinRoom(client *Client, room string) {
 	client.Rooms[room] = true
 }
 
+// inRoom reports whether the client has joined room
+func (h *Hub) inRoom(client *Client, room string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return client.Rooms[room]
+}
+
 func (h *Hub) BroadcastToConversation(convID string, payload interface{}) {
 	h.broadcast <- &Message{Type: "message", Room: "conv:" + convID, Payload: payload}
 }
 
+// BroadcastConversationEvent sends an event other than a new message to a conversation room
+func (h *Hub) BroadcastConversationEvent(convID string, msgType string, payload interface{}) {
+	h.broadcast <- &Message{Type: msgType, Room: "conv:" + convID, Payload: payload}
+}
+
+// BroadcastToUser sends an event to all of a user's connections. User room events
+// are sequenced and buffered so v2 clients can resume after a reconnect.
 func (h *Hub) BroadcastToUser(userID string, msgType string, payload interface{}) {
 	log.Printf("[WS] BroadcastToUser called: userID=%s, type=%s", userID, msgType)
-	h.broadcast <- &Message{Type: msgType, Room: "user:" + userID, Payload: payload}
+	msg := &Message{Type: msgType, Room: "user:" + userID, Payload: payload, AckRequired: criticalEvents[msgType]}
+	if h.store != nil {
+		data, err := h.store.Append(msg.Room, msg)
+		if err != nil {
+			log.Printf("[WS] Event buffer append failed for room=%s: %v", msg.Room, err)
+		} else {
+			msg.data = data
+		}
+	}
+	h.broadcast <- msg
+}
+
+// BroadcastToRoom sends an event to every client that joined room, such as
+// the admin:events room. Such events are not buffered for replay.
+func (h *Hub) BroadcastToRoom(room string, msgType string, payload interface{}) {
+	h.broadcast <- &Message{Type: msgType, Room: room, Payload: payload}
+}
+
+// BroadcastToAll sends an event to every client connected to this process.
+// Such events are not buffered for replay.
+func (h *Hub) BroadcastToAll(msgType string, payload interface{}) {
+	h.broadcast <- &Message{Type: msgType, Payload: payload}
 }
 
 func HandleConnection(hub *Hub, c *gin.Context) {
@@ -155,12 +258,14 @@ func HandleConnection(hub *Hub, c *gin.Context) {
 	log.Printf("[WS] New connection: UserID=%s, RemoteAddr=%s", userID.(string), c.Request.RemoteAddr)
 
 	client := &Client{
-		ID:     c.Request.RemoteAddr,
-		UserID: userID.(string),
-		Hub:    hub,
-		Conn:   conn,
-		Send:   make(chan []byte, 256),
-		Rooms:  make(map[string]bool),
+		ID:       c.Request.RemoteAddr,
+		UserID:   userID.(string),
+		Protocol: conn.Subprotocol(),
+		Hub:      hub,
+		Conn:     conn,
+		Send:     make(chan []byte, 256),
+		Rooms:    make(map[string]bool),
+		pending:  make(map[int64]*pendingAck),
 	}
 
 	hub.register <- client
@@ -223,16 +328,35 @@ func (c *Client) readPump() {
 				case c.Send <- pong:
 				default:
 				}
+			case "ack":
+				// v2: client confirms a critical event
+				if payload, ok := msg.Payload.(map[string]interface{}); ok {
+					if seq, ok := payload["seq"].(float64); ok {
+						c.acknowledge(int64(seq))
+					}
+				}
+			case "resume":
+				// v2: client reconnected and asks for events after the last one it saw
+				if payload, ok := msg.Payload.(map[string]interface{}); ok {
+					lastSeq, _ := payload["last_seq"].(float64)
+					c.resume(int64(lastSeq))
+				}
 			case "join":
 				if room, ok := msg.Payload.(string); ok {
+					if c.Hub.authorizer != nil && !c.Hub.authorizer.CanJoinRoom(c.UserID, room) {
+						log.Printf("[WS] UserID=%s denied room: %s", c.UserID, room)
+						denied, _ := json.Marshal(Message{Type: "join_denied", Payload: map[string]string{"room": room}})
+						c.send(denied)
+						continue
+					}
 					c.Hub.JoinRoom(c, room)
 					log.Printf("[WS] UserID=%s joined room: %s", c.UserID, room)
 				}
 			case "typing":
-				// Forward typing indicator to conversation room
+				// Forward typing indicator to a conversation room the client has joined
 				if payload, ok := msg.Payload.(map[string]interface{}); ok {
 					convID, _ := payload["conversation_id"].(string)
-					if convID != "" {
+					if convID != "" && c.Hub.inRoom(c, "conv:"+convID) {
 						c.Hub.broadcast <- &Message{
 							Type: "typing",
 							Room: "conv:" + convID,
@@ -245,10 +369,10 @@ func (c *Client) readPump() {
 					}
 				}
 			case "read":
-				// Forward read receipt to conversation room
+				// Forward read receipt to a conversation room the client has joined
 				if payload, ok := msg.Payload.(map[string]interface{}); ok {
 					convID, _ := payload["conversation_id"].(string)
-					if convID != "" {
+					if convID != "" && c.Hub.inRoom(c, "conv:"+convID) {
 						c.Hub.broadcast <- &Message{
 							Type: "read",
 							Room: "conv:" + convID,
@@ -264,10 +388,88 @@ func (c *Client) readPump() {
 	}
 }
 
+// send queues data without blocking the caller when the client is slow
+func (c *Client) send(data []byte) {
+	select {
+	case c.Send <- data:
+	default:
+	}
+}
+
+// expectAck records a critical event that a v2 client has to acknowledge
+func (c *Client) expectAck(seq int64, data []byte) {
+	if c.Protocol != ProtocolV2 || seq == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending[seq] = &pendingAck{data: data, sentAt: time.Now(), attempts: 1}
+}
+
+func (c *Client) acknowledge(seq int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, seq)
+}
+
+// dueForResend returns critical events whose ack is overdue, dropping the ones
+// that already used up their attempts
+func (c *Client) dueForResend() [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var due [][]byte
+	for seq, p := range c.pending {
+		if time.Since(p.sentAt) < ackTimeout {
+			continue
+		}
+		if p.attempts >= maxAckAttempts {
+			log.Printf("[WS] Giving up on seq=%d for UserID=%s after %d attempts", seq, c.UserID, p.attempts)
+			delete(c.pending, seq)
+			continue
+		}
+		p.attempts++
+		p.sentAt = time.Now()
+		due = append(due, p.data)
+	}
+	return due
+}
+
+// resume replays buffered user room events after lastSeq. When the buffer no longer
+// reaches back that far the client is told to resync over the REST API.
+func (c *Client) resume(lastSeq int64) {
+	if c.Hub.store == nil {
+		return
+	}
+	events, complete, err := c.Hub.store.Since("user:"+c.UserID, lastSeq)
+	if err != nil {
+		log.Printf("[WS] Resume failed for UserID=%s: %v", c.UserID, err)
+		complete = false
+	}
+	if !complete {
+		data, _ := json.Marshal(Message{Type: "resync_required", Payload: map[string]int64{"last_seq": lastSeq}})
+		c.send(data)
+	}
+
+	log.Printf("[WS] Replaying %d events after seq=%d for UserID=%s", len(events), lastSeq, c.UserID)
+	for _, data := range events {
+		var head struct {
+			Seq         int64 `json:"seq"`
+			AckRequired bool  `json:"ack_required"`
+		}
+		if json.Unmarshal(data, &head) == nil && head.AckRequired {
+			c.expectAck(head.Seq, data)
+		}
+		c.send(data)
+	}
+}
+
 func (c *Client) writePump() {
 	ticker := time.NewTicker(pingPeriod)
+	ackTicker := time.NewTicker(ackTimeout)
 	defer func() {
 		ticker.Stop()
+		ackTicker.Stop()
 		c.Conn.Close()
 	}()
 
@@ -284,6 +486,14 @@ func (c *Client) writePump() {
 				log.Printf("[WS] Write error for UserID=%s: %v", c.UserID, err)
 				return
 			}
+		case <-ackTicker.C:
+			for _, data := range c.dueForResend() {
+				c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+				if err := c.Conn.WriteMessage(websocket.TextMessage, data); err != nil {
+					log.Printf("[WS] Resend failed for UserID=%s: %v", c.UserID, err)
+					return
+				}
+			}
 		case <-ticker.C:
 			// Send WebSocket-level ping to keep connection alive
 			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
@@ -0,0 +1,38 @@
+-- Drops every table from the baseline, dependents first.
+
+DROP TABLE IF EXISTS "call_logs" CASCADE;
+DROP TABLE IF EXISTS "favorites" CASCADE;
+DROP TABLE IF EXISTS "support_messages" CASCADE;
+DROP TABLE IF EXISTS "support_tickets" CASCADE;
+DROP TABLE IF EXISTS "notification_preferences" CASCADE;
+DROP TABLE IF EXISTS "notifications" CASCADE;
+DROP TABLE IF EXISTS "user_roles" CASCADE;
+DROP TABLE IF EXISTS "roles" CASCADE;
+DROP TABLE IF EXISTS "webhook_deliveries" CASCADE;
+DROP TABLE IF EXISTS "webhook_endpoints" CASCADE;
+DROP TABLE IF EXISTS "metric_counters" CASCADE;
+DROP TABLE IF EXISTS "alert_events" CASCADE;
+DROP TABLE IF EXISTS "alert_rules" CASCADE;
+DROP TABLE IF EXISTS "audit_logs" CASCADE;
+DROP TABLE IF EXISTS "contact_changes" CASCADE;
+DROP TABLE IF EXISTS "sessions" CASCADE;
+DROP TABLE IF EXISTS "device_tokens" CASCADE;
+DROP TABLE IF EXISTS "subscriptions" CASCADE;
+DROP TABLE IF EXISTS "messages" CASCADE;
+DROP TABLE IF EXISTS "conversations" CASCADE;
+DROP TABLE IF EXISTS "review_votes" CASCADE;
+DROP TABLE IF EXISTS "reviews" CASCADE;
+DROP TABLE IF EXISTS "payouts" CASCADE;
+DROP TABLE IF EXISTS "payout_entries" CASCADE;
+DROP TABLE IF EXISTS "receipts" CASCADE;
+DROP TABLE IF EXISTS "recurring_orders" CASCADE;
+DROP TABLE IF EXISTS "order_line_items" CASCADE;
+DROP TABLE IF EXISTS "orders" CASCADE;
+DROP TABLE IF EXISTS "service_price_tiers" CASCADE;
+DROP TABLE IF EXISTS "service_options" CASCADE;
+DROP TABLE IF EXISTS "yandas_services" CASCADE;
+DROP TABLE IF EXISTS "categories" CASCADE;
+DROP TABLE IF EXISTS "document_screenings" CASCADE;
+DROP TABLE IF EXISTS "yandas_profiles" CASCADE;
+DROP TABLE IF EXISTS "addresses" CASCADE;
+DROP TABLE IF EXISTS "users" CASCADE;
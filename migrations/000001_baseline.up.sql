@@ -0,0 +1,113 @@
+-- Baseline schema, generated from internal/models with `go run ./cmd/migrate schema`.
+-- Databases created by AutoMigrate before migrations existed are marked as
+-- already at this version instead of running it.
+
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+CREATE EXTENSION IF NOT EXISTS "pgcrypto";
+
+CREATE TABLE "users" ("id" uuid DEFAULT gen_random_uuid(),"email" varchar(255),"phone" varchar(20),"password_hash" varchar(255) NOT NULL,"full_name" varchar(255) NOT NULL,"avatar_url" text,"role" varchar(20) DEFAULT 'customer',"is_verified" boolean DEFAULT false,"is_active" boolean DEFAULT true,"token_version" bigint NOT NULL DEFAULT 0,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_phone" ON "users" ("phone");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE "addresses" ("id" uuid DEFAULT gen_random_uuid(),"user_id" uuid NOT NULL,"label" varchar(50) NOT NULL,"address_text" text NOT NULL,"latitude" decimal(10,8),"longitude" decimal(11,8),"city" varchar(100),"is_default" boolean DEFAULT false,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_addresses_user_id" ON "addresses" ("user_id");
+
+CREATE TABLE "yandas_profiles" ("id" uuid DEFAULT gen_random_uuid(),"user_id" uuid NOT NULL,"bio" text,"instagram_handle" varchar(100),"instagram_verified" boolean DEFAULT false,"kimlik_on_url" text,"kimlik_arka_url" text,"ehliyet_on_url" text,"ehliyet_arka_url" text,"adli_sicil_pdf_url" text,"kimlik_on_verified" boolean DEFAULT false,"kimlik_arka_verified" boolean DEFAULT false,"ehliyet_on_verified" boolean DEFAULT false,"ehliyet_arka_verified" boolean DEFAULT false,"adli_sicil_verified" boolean DEFAULT false,"approval_status" varchar(20) DEFAULT 'pending',"approved_by" uuid,"approved_at" timestamptz,"rejection_reason" text,"rating_avg" decimal(3,2) DEFAULT 0,"total_jobs" bigint DEFAULT 0,"is_available" boolean DEFAULT false,"latitude" decimal(10,8),"longitude" decimal(11,8),"service_cities" text[],"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_users_yandas_profile" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_yandas_profiles_user_id" ON "yandas_profiles" ("user_id");
+
+CREATE TABLE "document_screenings" ("id" uuid DEFAULT gen_random_uuid(),"yandas_profile_id" uuid NOT NULL,"provider" varchar(30) NOT NULL,"status" varchar(20) NOT NULL,"extracted_name" varchar(255),"id_number_masked" varchar(20),"id_number_valid" boolean NOT NULL,"name_match" boolean NOT NULL,"error" text,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_yandas_profiles_screening" FOREIGN KEY ("yandas_profile_id") REFERENCES "yandas_profiles"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_document_screenings_yandas_profile_id" ON "document_screenings" ("yandas_profile_id");
+
+CREATE TABLE "categories" ("id" uuid DEFAULT gen_random_uuid(),"parent_id" uuid,"name" varchar(100) NOT NULL,"name_en" varchar(100),"slug" varchar(100) NOT NULL,"icon" varchar(50),"description" text,"is_active" boolean DEFAULT true,"sort_order" bigint DEFAULT 0,"commission_rate" decimal(5,4),PRIMARY KEY ("id"),CONSTRAINT "fk_categories_sub_categories" FOREIGN KEY ("parent_id") REFERENCES "categories"("id"));
+CREATE INDEX IF NOT EXISTS "idx_categories_parent_id" ON "categories" ("parent_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_categories_slug" ON "categories" ("slug");
+
+CREATE TABLE "yandas_services" ("id" uuid DEFAULT gen_random_uuid(),"yandas_id" uuid NOT NULL,"category_id" uuid,"title" varchar(255) NOT NULL,"description" text,"base_price" decimal(10,2) NOT NULL,"currency" varchar(3) DEFAULT 'TRY',"duration_minutes" bigint,"includes" text[],"is_active" boolean DEFAULT true,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_yandas_profiles_services" FOREIGN KEY ("yandas_id") REFERENCES "yandas_profiles"("id"),CONSTRAINT "fk_yandas_services_category" FOREIGN KEY ("category_id") REFERENCES "categories"("id"));
+
+CREATE TABLE "service_options" ("id" uuid DEFAULT gen_random_uuid(),"service_id" uuid NOT NULL,"name" varchar(255) NOT NULL,"price" decimal(10,2) NOT NULL,"is_active" boolean DEFAULT true,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_yandas_services_options" FOREIGN KEY ("service_id") REFERENCES "yandas_services"("id"));
+CREATE INDEX IF NOT EXISTS "idx_service_options_service_id" ON "service_options" ("service_id");
+
+CREATE TABLE "service_price_tiers" ("id" uuid DEFAULT gen_random_uuid(),"service_id" uuid NOT NULL,"basis" varchar(20) NOT NULL,"up_to" decimal(10,2) NOT NULL,"surcharge" decimal(10,2) NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_yandas_services_price_tiers" FOREIGN KEY ("service_id") REFERENCES "yandas_services"("id"));
+CREATE INDEX IF NOT EXISTS "idx_service_price_tiers_service_id" ON "service_price_tiers" ("service_id");
+
+CREATE TABLE "orders" ("id" uuid DEFAULT gen_random_uuid(),"order_number" varchar(20) NOT NULL,"customer_id" uuid NOT NULL,"yandas_id" uuid NOT NULL,"service_id" uuid,"status" varchar(30) DEFAULT 'pending',"agreed_price" decimal(10,2) NOT NULL,"currency" varchar(3) DEFAULT 'TRY',"location_address" text,"latitude" decimal(10,8),"longitude" decimal(11,8),"scheduled_at" timestamptz,"started_at" timestamptz,"completed_at" timestamptz,"customer_notes" text,"yandas_notes" text,"cancellation_reason" text,"cancelled_by" uuid,"commission_rate" decimal(5,4),"platform_fee" decimal(10,2),"net_earnings" decimal(10,2),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_orders_customer" FOREIGN KEY ("customer_id") REFERENCES "users"("id"),CONSTRAINT "fk_orders_yandas" FOREIGN KEY ("yandas_id") REFERENCES "yandas_profiles"("id"),CONSTRAINT "fk_orders_service" FOREIGN KEY ("service_id") REFERENCES "yandas_services"("id"));
+CREATE INDEX IF NOT EXISTS "idx_orders_deleted_at" ON "orders" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_orders_order_number" ON "orders" ("order_number");
+
+CREATE TABLE "order_line_items" ("id" uuid DEFAULT gen_random_uuid(),"order_id" uuid NOT NULL,"kind" varchar(20) NOT NULL,"option_id" uuid,"description" varchar(255) NOT NULL,"amount" decimal(10,2) NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_orders_line_items" FOREIGN KEY ("order_id") REFERENCES "orders"("id"));
+CREATE INDEX IF NOT EXISTS "idx_order_line_items_order_id" ON "order_line_items" ("order_id");
+
+CREATE TABLE "recurring_orders" ("id" uuid DEFAULT gen_random_uuid(),"customer_id" uuid NOT NULL,"yandas_id" uuid NOT NULL,"service_id" uuid NOT NULL,"option_ids" text[],"duration_minutes" bigint,"address_id" uuid,"location_address" text,"latitude" decimal(10,8),"longitude" decimal(11,8),"customer_notes" text,"frequency" varchar(20) NOT NULL,"next_run_at" timestamptz NOT NULL,"ends_at" timestamptz,"max_occurrences" bigint,"occurrence_count" bigint NOT NULL,"status" varchar(20) DEFAULT 'active',"last_order_id" uuid,"last_error" text,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_recurring_orders_yandas" FOREIGN KEY ("yandas_id") REFERENCES "yandas_profiles"("id"),CONSTRAINT "fk_recurring_orders_service" FOREIGN KEY ("service_id") REFERENCES "yandas_services"("id"));
+CREATE INDEX IF NOT EXISTS "idx_recurring_orders_status" ON "recurring_orders" ("status");
+CREATE INDEX IF NOT EXISTS "idx_recurring_orders_next_run_at" ON "recurring_orders" ("next_run_at");
+CREATE INDEX IF NOT EXISTS "idx_recurring_orders_customer_id" ON "recurring_orders" ("customer_id");
+
+CREATE TABLE "receipts" ("id" uuid DEFAULT gen_random_uuid(),"order_id" uuid NOT NULL,"receipt_number" varchar(30) NOT NULL,"currency" varchar(3) DEFAULT 'TRY',"subtotal" decimal(10,2) NOT NULL,"vat_rate" decimal(5,4) NOT NULL,"vat_amount" decimal(10,2) NOT NULL,"total" decimal(10,2) NOT NULL,"e_invoice_provider" varchar(50),"e_invoice_status" varchar(20) DEFAULT 'not_sent',"e_invoice_uuid" varchar(64),"e_invoice_error" text,"issued_at" timestamptz NOT NULL,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_receipts_order_id" ON "receipts" ("order_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_receipts_receipt_number" ON "receipts" ("receipt_number");
+
+CREATE TABLE "payout_entries" ("id" uuid DEFAULT gen_random_uuid(),"yandas_id" uuid NOT NULL,"order_id" uuid NOT NULL,"payout_id" uuid,"gross_amount" decimal(10,2) NOT NULL,"platform_fee" decimal(10,2) NOT NULL,"net_amount" decimal(10,2) NOT NULL,"currency" varchar(3) DEFAULT 'TRY',"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_payouts_entries" FOREIGN KEY ("payout_id") REFERENCES "payouts"("id"));
+CREATE INDEX IF NOT EXISTS "idx_payout_entries_payout_id" ON "payout_entries" ("payout_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_payout_entries_order_id" ON "payout_entries" ("order_id");
+CREATE INDEX IF NOT EXISTS "idx_payout_entries_yandas_id" ON "payout_entries" ("yandas_id");
+
+CREATE TABLE "payouts" ("id" uuid DEFAULT gen_random_uuid(),"yandas_id" uuid NOT NULL,"amount" decimal(12,2) NOT NULL,"currency" varchar(3) DEFAULT 'TRY',"status" varchar(20) DEFAULT 'pending',"reference" varchar(100),"notes" text,"created_by" uuid NOT NULL,"transferred_by" uuid,"transferred_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_payouts_yandas" FOREIGN KEY ("yandas_id") REFERENCES "yandas_profiles"("id"));
+CREATE INDEX IF NOT EXISTS "idx_payouts_yandas_id" ON "payouts" ("yandas_id");
+
+CREATE TABLE "reviews" ("id" uuid DEFAULT gen_random_uuid(),"order_id" uuid NOT NULL,"reviewer_id" uuid NOT NULL,"reviewee_id" uuid NOT NULL,"rating" bigint NOT NULL,"comment" text,"is_anonymous" boolean DEFAULT false,"reply" text,"replied_at" timestamptz,"helpful_count" bigint DEFAULT 0,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_reviews_reviewer" FOREIGN KEY ("reviewer_id") REFERENCES "users"("id"),CONSTRAINT "fk_orders_review" FOREIGN KEY ("order_id") REFERENCES "orders"("id"),CONSTRAINT "chk_reviews_rating" CHECK (rating >= 1 AND rating <= 5));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_reviews_order_id" ON "reviews" ("order_id");
+
+CREATE TABLE "review_votes" ("review_id" uuid,"user_id" uuid,"created_at" timestamptz,PRIMARY KEY ("review_id","user_id"));
+
+CREATE TABLE "conversations" ("id" uuid DEFAULT gen_random_uuid(),"order_id" uuid,"customer_id" uuid NOT NULL,"yandas_id" uuid NOT NULL,"last_message_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_conversations_yandas" FOREIGN KEY ("yandas_id") REFERENCES "users"("id"),CONSTRAINT "fk_conversations_customer" FOREIGN KEY ("customer_id") REFERENCES "users"("id"));
+
+CREATE TABLE "messages" ("id" uuid DEFAULT gen_random_uuid(),"conversation_id" uuid NOT NULL,"sender_id" uuid NOT NULL,"content" text NOT NULL,"message_type" varchar(20) DEFAULT 'text',"is_read" boolean DEFAULT false,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_messages_sender" FOREIGN KEY ("sender_id") REFERENCES "users"("id"),CONSTRAINT "fk_conversations_messages" FOREIGN KEY ("conversation_id") REFERENCES "conversations"("id"));
+
+CREATE TABLE "subscriptions" ("id" uuid DEFAULT gen_random_uuid(),"user_id" uuid NOT NULL,"plan_type" varchar(20) NOT NULL,"status" varchar(20) DEFAULT 'active',"provider" varchar(20) NOT NULL,"provider_subscription_id" varchar(255),"current_period_start" timestamptz,"current_period_end" timestamptz,"cancelled_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_users_subscription" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+
+CREATE TABLE "device_tokens" ("id" uuid DEFAULT gen_random_uuid(),"user_id" uuid NOT NULL,"token" text NOT NULL,"platform" varchar(10) NOT NULL,"session_id" uuid,"is_active" boolean DEFAULT true,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_users_device_tokens" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_device_tokens_session_id" ON "device_tokens" ("session_id");
+
+CREATE TABLE "sessions" ("id" uuid,"user_id" uuid NOT NULL,"platform" varchar(20),"user_agent" varchar(255),"ip_address" varchar(45),"refresh_token_hash" varchar(64) NOT NULL,"last_seen_at" timestamptz,"expires_at" timestamptz NOT NULL,"revoked_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_sessions_user_id" ON "sessions" ("user_id");
+
+CREATE TABLE "contact_changes" ("id" uuid DEFAULT gen_random_uuid(),"user_id" uuid NOT NULL,"channel" varchar(10) NOT NULL,"new_value" varchar(255) NOT NULL,"code_hash" varchar(64),"attempts" bigint DEFAULT 0,"expires_at" timestamptz NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_contact_change_user_channel" ON "contact_changes" ("user_id","channel");
+
+CREATE TABLE "audit_logs" ("id" uuid DEFAULT gen_random_uuid(),"admin_id" uuid NOT NULL,"action" varchar(100) NOT NULL,"entity_type" varchar(50),"entity_id" uuid,"old_values" jsonb,"new_values" jsonb,"ip_address" varchar(45),"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_audit_logs_admin" FOREIGN KEY ("admin_id") REFERENCES "users"("id"));
+
+CREATE TABLE "alert_rules" ("id" uuid DEFAULT gen_random_uuid(),"name" varchar(100) NOT NULL,"metric" varchar(50) NOT NULL,"condition" varchar(10) NOT NULL,"threshold" bigint NOT NULL,"window_minutes" bigint NOT NULL,"cooldown_minutes" bigint NOT NULL,"channels" text[],"is_active" boolean NOT NULL,"last_triggered_at" timestamptz,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_alert_rules_metric" ON "alert_rules" ("metric");
+
+CREATE TABLE "alert_events" ("id" uuid DEFAULT gen_random_uuid(),"rule_id" uuid NOT NULL,"metric" varchar(50) NOT NULL,"value" bigint NOT NULL,"threshold" bigint NOT NULL,"condition" varchar(10) NOT NULL,"message" text NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_alert_events_rule" FOREIGN KEY ("rule_id") REFERENCES "alert_rules"("id"));
+CREATE INDEX IF NOT EXISTS "idx_alert_events_created_at" ON "alert_events" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_alert_events_rule_id" ON "alert_events" ("rule_id");
+
+CREATE TABLE "metric_counters" ("metric" varchar(50),"bucket" timestamptz,"count" bigint NOT NULL,PRIMARY KEY ("metric","bucket"));
+CREATE INDEX IF NOT EXISTS "idx_metric_counters_bucket" ON "metric_counters" ("bucket");
+
+CREATE TABLE "webhook_endpoints" ("id" uuid DEFAULT gen_random_uuid(),"url" text NOT NULL,"secret" varchar(100) NOT NULL,"events" text[],"description" text,"is_active" boolean DEFAULT true,"created_by" uuid NOT NULL,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+
+CREATE TABLE "webhook_deliveries" ("id" uuid DEFAULT gen_random_uuid(),"endpoint_id" uuid NOT NULL,"event" varchar(50) NOT NULL,"payload" jsonb NOT NULL,"status" varchar(20) DEFAULT 'pending',"attempts" bigint DEFAULT 0,"next_attempt_at" timestamptz,"last_status_code" bigint,"last_error" text,"last_response" text,"delivered_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_next_attempt_at" ON "webhook_deliveries" ("next_attempt_at");
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_status" ON "webhook_deliveries" ("status");
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_endpoint_id" ON "webhook_deliveries" ("endpoint_id");
+
+CREATE TABLE "roles" ("id" uuid DEFAULT gen_random_uuid(),"name" varchar(50) NOT NULL,"description" text,"permissions" text[],"is_system" boolean NOT NULL,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_roles_name" ON "roles" ("name");
+
+CREATE TABLE "user_roles" ("user_id" uuid,"role_id" uuid,"granted_by" uuid,"created_at" timestamptz,PRIMARY KEY ("user_id","role_id"),CONSTRAINT "fk_user_roles_role" FOREIGN KEY ("role_id") REFERENCES "roles"("id"));
+
+CREATE TABLE "notifications" ("id" uuid DEFAULT gen_random_uuid(),"user_id" uuid NOT NULL,"title" varchar(255) NOT NULL,"body" text NOT NULL,"type" varchar(50),"data" jsonb,"is_read" boolean DEFAULT false,"created_at" timestamptz,PRIMARY KEY ("id"));
+
+CREATE TABLE "notification_preferences" ("id" uuid DEFAULT gen_random_uuid(),"user_id" uuid NOT NULL,"type" varchar(50) NOT NULL,"push_enabled" boolean NOT NULL,"email_enabled" boolean NOT NULL,"sms_enabled" boolean NOT NULL,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_notification_prefs_user_type" ON "notification_preferences" ("user_id","type");
+
+CREATE TABLE "support_tickets" ("id" uuid DEFAULT gen_random_uuid(),"user_id" uuid NOT NULL,"assigned_to" uuid,"subject" varchar(255) NOT NULL,"description" text NOT NULL,"category" varchar(50) DEFAULT 'general',"priority" varchar(20) DEFAULT 'normal',"status" varchar(20) DEFAULT 'open',"order_id" uuid,"resolved_at" timestamptz,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_support_tickets_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),CONSTRAINT "fk_support_tickets_assignee" FOREIGN KEY ("assigned_to") REFERENCES "users"("id"));
+
+CREATE TABLE "support_messages" ("id" uuid DEFAULT gen_random_uuid(),"ticket_id" uuid NOT NULL,"sender_id" uuid NOT NULL,"content" text NOT NULL,"is_admin" boolean DEFAULT false,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_support_messages_sender" FOREIGN KEY ("sender_id") REFERENCES "users"("id"),CONSTRAINT "fk_support_tickets_messages" FOREIGN KEY ("ticket_id") REFERENCES "support_tickets"("id"));
+
+CREATE TABLE "favorites" ("id" uuid DEFAULT gen_random_uuid(),"user_id" uuid NOT NULL,"yandas_id" uuid NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_favorites_yandas" FOREIGN KEY ("yandas_id") REFERENCES "yandas_profiles"("id"),CONSTRAINT "fk_favorites_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+
+CREATE TABLE "call_logs" ("id" uuid DEFAULT gen_random_uuid(),"caller_id" uuid NOT NULL,"callee_id" uuid NOT NULL,"order_id" uuid,"call_type" varchar(20) NOT NULL,"status" varchar(20) NOT NULL,"duration" bigint DEFAULT 0,"channel_id" varchar(255),"started_at" timestamptz,"answered_at" timestamptz,"ended_at" timestamptz,"recording_requested" boolean DEFAULT false,"caller_consent" boolean DEFAULT false,"callee_consent" boolean DEFAULT false,"recording_status" varchar(20) DEFAULT 'none',"recording_resource_id" text,"recording_s_id" varchar(255),"recording_files" text[],PRIMARY KEY ("id"),CONSTRAINT "fk_call_logs_caller" FOREIGN KEY ("caller_id") REFERENCES "users"("id"),CONSTRAINT "fk_call_logs_callee" FOREIGN KEY ("callee_id") REFERENCES "users"("id"));
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "idx_yandas_profiles_service_cities";
+DROP INDEX IF EXISTS "idx_notifications_user_read";
+DROP INDEX IF EXISTS "idx_messages_conversation_created";
+DROP INDEX IF EXISTS "idx_orders_customer_status";
+DROP INDEX IF EXISTS "idx_orders_yandas_status_created";
@@ -0,0 +1,26 @@
+-- Composite indexes for the hottest list queries. Each index matches the
+-- equality filters first and the sort column last, so Postgres can read the
+-- page straight off the index instead of sorting every matching row.
+--
+-- To compare plans, run the queries below with EXPLAIN (ANALYZE, BUFFERS)
+-- before and after applying this migration.
+--
+-- Plain CREATE INDEX locks writes on the table while it builds. On a large
+-- production table, create the index by hand with CONCURRENTLY first; the
+-- IF NOT EXISTS then turns this migration into a no-op.
+
+-- Yandaş order inbox: WHERE yandas_id = ? [AND status = ?] ORDER BY created_at DESC
+CREATE INDEX IF NOT EXISTS "idx_orders_yandas_status_created" ON "orders" ("yandas_id","status","created_at");
+
+-- Customer order history: WHERE customer_id = ? [AND status = ?]
+CREATE INDEX IF NOT EXISTS "idx_orders_customer_status" ON "orders" ("customer_id","status");
+
+-- Chat history and unread counts: WHERE conversation_id = ? ORDER BY created_at DESC
+CREATE INDEX IF NOT EXISTS "idx_messages_conversation_created" ON "messages" ("conversation_id","created_at");
+
+-- Notification badge: WHERE user_id = ? AND is_read = false
+CREATE INDEX IF NOT EXISTS "idx_notifications_user_read" ON "notifications" ("user_id","is_read");
+
+-- City filter on public listings: WHERE service_cities @> ARRAY[?].
+-- GIN indexes only serve the array operators, not "? = ANY(service_cities)".
+CREATE INDEX IF NOT EXISTS "idx_yandas_profiles_service_cities" ON "yandas_profiles" USING gin("service_cities");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_favorites_yandas_id";
+DROP TABLE IF EXISTS "favorite_activities";
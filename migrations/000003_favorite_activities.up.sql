@@ -0,0 +1,6 @@
+-- Activity of favorited yandaşlar (coming online, new services, price drops)
+CREATE TABLE IF NOT EXISTS "favorite_activities" ("id" uuid DEFAULT gen_random_uuid(),"yandas_id" uuid NOT NULL,"type" varchar(20) NOT NULL,"service_id" uuid,"title" varchar(255),"old_price" decimal(10,2),"new_price" decimal(10,2),"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_favorite_activities_yandas" FOREIGN KEY ("yandas_id") REFERENCES "yandas_profiles"("id"));
+CREATE INDEX IF NOT EXISTS "idx_favorite_activities_yandas_created" ON "favorite_activities" ("yandas_id","created_at");
+
+-- Fan-out to the users who favorited a yandaş
+CREATE INDEX IF NOT EXISTS "idx_favorites_yandas_id" ON "favorites" ("yandas_id");
@@ -0,0 +1,17 @@
+-- Status changes of each order, read by the order timeline
+CREATE TABLE IF NOT EXISTS "order_status_history" ("id" uuid DEFAULT gen_random_uuid(),"order_id" uuid NOT NULL,"event" varchar(30) NOT NULL,"status" varchar(30) NOT NULL,"actor_id" uuid,"note" text,"latitude" decimal(10,8),"longitude" decimal(11,8),"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_order_status_history_order_created" ON "order_status_history" ("order_id","created_at");
+
+-- Backfill from the timestamps existing orders carry. When an order was
+-- accepted was never stored, so older orders have no accepted event.
+INSERT INTO "order_status_history" ("order_id","event","status","actor_id","created_at")
+SELECT "id", 'created', 'pending', "customer_id", "created_at" FROM "orders";
+
+INSERT INTO "order_status_history" ("order_id","event","status","created_at")
+SELECT "id", 'started', 'in_progress', "started_at" FROM "orders" WHERE "started_at" IS NOT NULL;
+
+INSERT INTO "order_status_history" ("order_id","event","status","note","created_at")
+SELECT "id", 'completed', 'completed', "yandas_notes", "completed_at" FROM "orders" WHERE "completed_at" IS NOT NULL;
+
+INSERT INTO "order_status_history" ("order_id","event","status","actor_id","note","created_at")
+SELECT "id", 'cancelled', 'cancelled', "cancelled_by", "cancellation_reason", "updated_at" FROM "orders" WHERE "status" = 'cancelled';
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS "idx_support_tickets_resolution_due_at";
+DROP INDEX IF EXISTS "idx_support_tickets_first_response_due_at";
+ALTER TABLE "support_tickets" DROP COLUMN IF EXISTS "escalated_at";
+ALTER TABLE "support_tickets" DROP COLUMN IF EXISTS "escalation_level";
+ALTER TABLE "support_tickets" DROP COLUMN IF EXISTS "resolution_breached";
+ALTER TABLE "support_tickets" DROP COLUMN IF EXISTS "first_response_breached";
+ALTER TABLE "support_tickets" DROP COLUMN IF EXISTS "first_responded_at";
+ALTER TABLE "support_tickets" DROP COLUMN IF EXISTS "resolution_due_at";
+ALTER TABLE "support_tickets" DROP COLUMN IF EXISTS "first_response_due_at";
@@ -0,0 +1,31 @@
+-- SLA deadlines, breach flags and escalation state for support tickets
+ALTER TABLE "support_tickets" ADD COLUMN IF NOT EXISTS "first_response_due_at" timestamptz;
+ALTER TABLE "support_tickets" ADD COLUMN IF NOT EXISTS "resolution_due_at" timestamptz;
+ALTER TABLE "support_tickets" ADD COLUMN IF NOT EXISTS "first_responded_at" timestamptz;
+ALTER TABLE "support_tickets" ADD COLUMN IF NOT EXISTS "first_response_breached" boolean DEFAULT false;
+ALTER TABLE "support_tickets" ADD COLUMN IF NOT EXISTS "resolution_breached" boolean DEFAULT false;
+ALTER TABLE "support_tickets" ADD COLUMN IF NOT EXISTS "escalation_level" bigint DEFAULT 0;
+ALTER TABLE "support_tickets" ADD COLUMN IF NOT EXISTS "escalated_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_support_tickets_first_response_due_at" ON "support_tickets" ("first_response_due_at");
+CREATE INDEX IF NOT EXISTS "idx_support_tickets_resolution_due_at" ON "support_tickets" ("resolution_due_at");
+
+-- Backfill with the targets in services.SupportSLAPolicies
+UPDATE "support_tickets" SET
+	"first_response_due_at" = "created_at" + CASE "priority"
+		WHEN 'urgent' THEN interval '1 hour'
+		WHEN 'high' THEN interval '4 hours'
+		WHEN 'low' THEN interval '24 hours'
+		ELSE interval '8 hours' END,
+	"resolution_due_at" = "created_at" + CASE "priority"
+		WHEN 'urgent' THEN interval '8 hours'
+		WHEN 'high' THEN interval '24 hours'
+		WHEN 'low' THEN interval '120 hours'
+		ELSE interval '72 hours' END;
+
+UPDATE "support_tickets" t SET "first_responded_at" = m."first_at"
+FROM (SELECT "ticket_id", MIN("created_at") AS "first_at" FROM "support_messages" WHERE "is_admin" GROUP BY "ticket_id") m
+WHERE m."ticket_id" = t."id";
+
+UPDATE "support_tickets" SET
+	"first_response_breached" = "first_responded_at" IS NOT NULL AND "first_responded_at" > "first_response_due_at",
+	"resolution_breached" = "resolved_at" IS NOT NULL AND "resolved_at" > "resolution_due_at";
@@ -0,0 +1,2 @@
+ALTER TABLE "support_messages" DROP COLUMN IF EXISTS "is_internal";
+DROP TABLE IF EXISTS "canned_responses";
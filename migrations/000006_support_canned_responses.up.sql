@@ -0,0 +1,6 @@
+-- Reply templates for support agents
+CREATE TABLE IF NOT EXISTS "canned_responses" ("id" uuid DEFAULT gen_random_uuid(),"title" varchar(100) NOT NULL,"content" text NOT NULL,"category" varchar(50),"created_by" uuid NOT NULL,"usage_count" bigint DEFAULT 0,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_canned_responses_category" ON "canned_responses" ("category");
+
+-- Agent-only notes on tickets
+ALTER TABLE "support_messages" ADD COLUMN IF NOT EXISTS "is_internal" boolean DEFAULT false;
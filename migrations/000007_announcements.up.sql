@@ -0,0 +1,5 @@
+-- In-app announcement banners and who dismissed them
+CREATE TABLE IF NOT EXISTS "announcements" ("id" uuid DEFAULT gen_random_uuid(),"title" varchar(200) NOT NULL,"body" text NOT NULL,"audience" varchar(20) DEFAULT 'all',"severity" varchar(20) DEFAULT 'info',"link_url" varchar(500),"dismissible" boolean NOT NULL,"is_active" boolean NOT NULL,"starts_at" timestamptz NOT NULL,"ends_at" timestamptz,"created_by" uuid NOT NULL,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_announcements_active_window" ON "announcements" ("is_active","starts_at");
+CREATE TABLE IF NOT EXISTS "announcement_dismissals" ("announcement_id" uuid,"user_id" uuid,"created_at" timestamptz,PRIMARY KEY ("announcement_id","user_id"));
+CREATE INDEX IF NOT EXISTS "idx_announcement_dismissals_user_id" ON "announcement_dismissals" ("user_id");
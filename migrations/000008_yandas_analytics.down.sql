@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "yandas_daily_stats";
+DROP TABLE IF EXISTS "profile_views";
@@ -0,0 +1,7 @@
+-- Raw profile views, pruned once counted
+CREATE TABLE IF NOT EXISTS "profile_views" ("id" uuid DEFAULT gen_random_uuid(),"yandas_id" uuid NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_profile_views_yandas_created" ON "profile_views" ("yandas_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_profile_views_created_at" ON "profile_views" ("created_at");
+
+-- Per-day yandaş funnel filled by the nightly analytics rollup
+CREATE TABLE IF NOT EXISTS "yandas_daily_stats" ("yandas_id" uuid,"date" date,"profile_views" bigint NOT NULL DEFAULT 0,"favorite_adds" bigint NOT NULL DEFAULT 0,"conversation_starts" bigint NOT NULL DEFAULT 0,"orders_created" bigint NOT NULL DEFAULT 0,"orders_completed" bigint NOT NULL DEFAULT 0,"revenue" decimal(12,2) NOT NULL DEFAULT 0,"updated_at" timestamptz,PRIMARY KEY ("yandas_id","date"));
@@ -0,0 +1,2 @@
+-- Open orders against available yandaşlar per city and category, recomputed on a schedule
+CREATE TABLE IF NOT EXISTS "category_demands" ("city" varchar(100),"category_id" uuid,"open_orders" bigint NOT NULL DEFAULT 0,"available_yandas" bigint NOT NULL DEFAULT 0,"ratio" decimal(8,2) NOT NULL DEFAULT 0,"level" varchar(10) NOT NULL,"computed_at" timestamptz NOT NULL,PRIMARY KEY ("city","category_id"),CONSTRAINT "fk_category_demands_category" FOREIGN KEY ("category_id") REFERENCES "categories"("id"));
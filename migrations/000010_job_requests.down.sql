@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "bids";
+DROP TABLE IF EXISTS "job_requests";
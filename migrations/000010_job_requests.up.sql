@@ -0,0 +1,7 @@
+-- Open job requests customers post without picking a yandaş, and the yandaşlar's bids on them
+CREATE TABLE IF NOT EXISTS "job_requests" ("id" uuid DEFAULT gen_random_uuid(),"customer_id" uuid NOT NULL,"category_id" uuid NOT NULL,"city" varchar(100) NOT NULL,"title" varchar(255) NOT NULL,"description" text,"budget_min" decimal(10,2),"budget_max" decimal(10,2),"currency" varchar(3) DEFAULT 'TRY',"scheduled_at" timestamptz,"location_address" text,"latitude" decimal(10,8),"longitude" decimal(11,8),"status" varchar(20) DEFAULT 'open',"expires_at" timestamptz NOT NULL,"accepted_bid_id" uuid,"order_id" uuid,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_job_requests_customer" FOREIGN KEY ("customer_id") REFERENCES "users"("id"),CONSTRAINT "fk_job_requests_category" FOREIGN KEY ("category_id") REFERENCES "categories"("id"));
+CREATE INDEX IF NOT EXISTS "idx_job_requests_open" ON "job_requests" ("status","city","category_id");
+CREATE INDEX IF NOT EXISTS "idx_job_requests_customer_id" ON "job_requests" ("customer_id");
+CREATE TABLE IF NOT EXISTS "bids" ("id" uuid DEFAULT gen_random_uuid(),"job_request_id" uuid NOT NULL,"yandas_id" uuid NOT NULL,"service_id" uuid NOT NULL,"amount" decimal(10,2) NOT NULL,"message" text,"status" varchar(20) DEFAULT 'pending',"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_bids_yandas" FOREIGN KEY ("yandas_id") REFERENCES "yandas_profiles"("id"),CONSTRAINT "fk_bids_service" FOREIGN KEY ("service_id") REFERENCES "yandas_services"("id"),CONSTRAINT "fk_job_requests_bids" FOREIGN KEY ("job_request_id") REFERENCES "job_requests"("id"));
+CREATE INDEX IF NOT EXISTS "idx_bids_yandas_id" ON "bids" ("yandas_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_bids_request_yandas" ON "bids" ("job_request_id","yandas_id");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "auto_assignments";
+DROP INDEX IF EXISTS "idx_yandas_profiles_location";
@@ -0,0 +1,7 @@
+-- Location index for finding the nearest available yandaşlar
+CREATE INDEX IF NOT EXISTS "idx_yandas_profiles_location" ON "yandas_profiles" ("latitude","longitude");
+
+-- Urgent orders offered to the nearest yandaşlar one at a time
+CREATE TABLE IF NOT EXISTS "auto_assignments" ("id" uuid DEFAULT gen_random_uuid(),"customer_id" uuid NOT NULL,"category_id" uuid NOT NULL,"location_address" text,"latitude" decimal(10,8) NOT NULL,"longitude" decimal(11,8) NOT NULL,"customer_notes" text,"status" varchar(20) DEFAULT 'searching',"candidate_id" uuid,"service_id" uuid,"offer_expires_at" timestamptz,"attempts" bigint NOT NULL,"offered_to" text[],"order_id" uuid,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_auto_assignments_category" FOREIGN KEY ("category_id") REFERENCES "categories"("id"));
+CREATE INDEX IF NOT EXISTS "idx_auto_assignments_offers" ON "auto_assignments" ("status","offer_expires_at");
+CREATE INDEX IF NOT EXISTS "idx_auto_assignments_customer_id" ON "auto_assignments" ("customer_id");
@@ -0,0 +1,2 @@
+ALTER TABLE "messages" DROP COLUMN IF EXISTS "duration_seconds";
+ALTER TABLE "messages" DROP COLUMN IF EXISTS "media_url";
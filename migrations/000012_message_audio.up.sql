@@ -0,0 +1,3 @@
+-- Playback URL and duration of voice note messages
+ALTER TABLE "messages" ADD COLUMN IF NOT EXISTS "media_url" varchar(500);
+ALTER TABLE "messages" ADD COLUMN IF NOT EXISTS "duration_seconds" bigint;
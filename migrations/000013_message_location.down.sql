@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "idx_messages_live_until";
+ALTER TABLE "messages" DROP COLUMN IF EXISTS "live_ended_at";
+ALTER TABLE "messages" DROP COLUMN IF EXISTS "live_until";
+ALTER TABLE "messages" DROP COLUMN IF EXISTS "location_label";
+ALTER TABLE "messages" DROP COLUMN IF EXISTS "longitude";
+ALTER TABLE "messages" DROP COLUMN IF EXISTS "latitude";
@@ -0,0 +1,7 @@
+-- Structured location messages and live location sharing
+ALTER TABLE "messages" ADD COLUMN IF NOT EXISTS "latitude" decimal(10,8);
+ALTER TABLE "messages" ADD COLUMN IF NOT EXISTS "longitude" decimal(11,8);
+ALTER TABLE "messages" ADD COLUMN IF NOT EXISTS "location_label" varchar(255);
+ALTER TABLE "messages" ADD COLUMN IF NOT EXISTS "live_until" timestamptz;
+ALTER TABLE "messages" ADD COLUMN IF NOT EXISTS "live_ended_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_messages_live_until" ON "messages" ("live_until");
@@ -0,0 +1,8 @@
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "callee_region";
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "callee_platform";
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "callee_issues";
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "callee_rating";
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "caller_region";
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "caller_platform";
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "caller_issues";
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "caller_rating";
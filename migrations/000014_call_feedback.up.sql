@@ -0,0 +1,9 @@
+-- Per-party call quality feedback for troubleshooting Agora issues
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "caller_rating" bigint;
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "caller_issues" text[];
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "caller_platform" varchar(20);
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "caller_region" varchar(50);
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "callee_rating" bigint;
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "callee_issues" text[];
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "callee_platform" varchar(20);
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "callee_region" varchar(50);
@@ -0,0 +1,4 @@
+-- Extra call participants such as support agents mediating a dispute
+CREATE TABLE IF NOT EXISTS "call_participants" ("id" uuid DEFAULT gen_random_uuid(),"call_id" uuid NOT NULL,"user_id" uuid NOT NULL,"uid" bigint NOT NULL,"role" varchar(20) NOT NULL,"joined_at" timestamptz NOT NULL,"left_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_call_participants_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),CONSTRAINT "fk_call_logs_participants" FOREIGN KEY ("call_id") REFERENCES "call_logs"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_call_participants_call_uid" ON "call_participants" ("call_id","uid");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_call_participants_call_user" ON "call_participants" ("call_id","user_id");
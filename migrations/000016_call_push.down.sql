@@ -0,0 +1,5 @@
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "delivered_via";
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "delivered_at";
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "push_error";
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "push_sent_at";
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "push_status";
@@ -0,0 +1,6 @@
+-- Delivery tracking of incoming call pushes
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "push_status" varchar(20) DEFAULT 'none';
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "push_sent_at" timestamptz;
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "push_error" text;
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "delivered_at" timestamptz;
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "delivered_via" varchar(20);
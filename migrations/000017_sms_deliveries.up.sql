@@ -0,0 +1,6 @@
+-- Per-provider log of outgoing SMS and their delivery reports
+CREATE TABLE IF NOT EXISTS "sms_deliveries" ("id" uuid DEFAULT gen_random_uuid(),"phone" varchar(20) NOT NULL,"purpose" varchar(20) NOT NULL,"provider" varchar(20) NOT NULL,"provider_message_id" varchar(64),"attempt" bigint NOT NULL DEFAULT 1,"status" varchar(20) NOT NULL,"error_code" varchar(20),"error" text,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_sms_deliveries_phone" ON "sms_deliveries" ("phone");
+CREATE INDEX IF NOT EXISTS "idx_sms_deliveries_created_at" ON "sms_deliveries" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_sms_deliveries_status" ON "sms_deliveries" ("status");
+CREATE INDEX IF NOT EXISTS "idx_sms_deliveries_provider_message" ON "sms_deliveries" ("provider","provider_message_id");
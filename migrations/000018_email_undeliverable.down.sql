@@ -0,0 +1,2 @@
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email_undeliverable_reason";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email_undeliverable_at";
@@ -0,0 +1,3 @@
+-- Flag email addresses that hard-bounced or reported spam
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "email_undeliverable_at" timestamptz;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "email_undeliverable_reason" varchar(255);
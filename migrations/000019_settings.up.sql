@@ -0,0 +1,2 @@
+-- Runtime overrides of operational settings, edited from the admin panel
+CREATE TABLE IF NOT EXISTS "settings" ("key" varchar(100),"type" varchar(20) NOT NULL,"value" text NOT NULL,"updated_by" uuid,"updated_at" timestamptz,PRIMARY KEY ("key"));
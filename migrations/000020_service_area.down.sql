@@ -0,0 +1,4 @@
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "travel_fee_per_km";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "service_radius_km";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "base_longitude";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "base_latitude";
@@ -0,0 +1,5 @@
+-- Base location, service radius and per-km travel fee of each yandaş
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "base_latitude" decimal(10,8);
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "base_longitude" decimal(11,8);
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "service_radius_km" decimal(6,2);
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "travel_fee_per_km" decimal(10,2);
@@ -0,0 +1,3 @@
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "eta_updated_at";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "eta_distance_km";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "eta_at";
@@ -0,0 +1,4 @@
+-- Estimated arrival of the yandaş on accepted and in-progress orders
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "eta_at" timestamptz;
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "eta_distance_km" decimal(8,2);
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "eta_updated_at" timestamptz;
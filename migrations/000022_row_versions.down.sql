@@ -0,0 +1,3 @@
+ALTER TABLE "yandas_services" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "version";
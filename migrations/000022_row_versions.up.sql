@@ -0,0 +1,4 @@
+-- Row versions for optimistic concurrency on profile and service edits
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "yandas_services" ADD COLUMN IF NOT EXISTS "version" integer NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+ALTER TABLE "reviews" DROP COLUMN IF EXISTS "hidden_by";
+ALTER TABLE "reviews" DROP COLUMN IF EXISTS "hidden_at";
+ALTER TABLE "reviews" DROP COLUMN IF EXISTS "is_hidden";
@@ -0,0 +1,4 @@
+-- Moderators can hide reviews from public listings and the rating average
+ALTER TABLE "reviews" ADD COLUMN IF NOT EXISTS "is_hidden" boolean NOT NULL DEFAULT false;
+ALTER TABLE "reviews" ADD COLUMN IF NOT EXISTS "hidden_at" timestamptz;
+ALTER TABLE "reviews" ADD COLUMN IF NOT EXISTS "hidden_by" uuid;
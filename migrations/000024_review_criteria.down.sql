@@ -0,0 +1,3 @@
+ALTER TABLE "reviews" DROP COLUMN IF EXISTS "quality_rating";
+ALTER TABLE "reviews" DROP COLUMN IF EXISTS "communication_rating";
+ALTER TABLE "reviews" DROP COLUMN IF EXISTS "punctuality_rating";
@@ -0,0 +1,4 @@
+-- Optional punctuality, communication and quality ratings on reviews
+ALTER TABLE "reviews" ADD COLUMN IF NOT EXISTS "punctuality_rating" bigint CONSTRAINT "chk_reviews_punctuality_rating" CHECK (punctuality_rating >= 1 AND punctuality_rating <= 5);
+ALTER TABLE "reviews" ADD COLUMN IF NOT EXISTS "communication_rating" bigint CONSTRAINT "chk_reviews_communication_rating" CHECK (communication_rating >= 1 AND communication_rating <= 5);
+ALTER TABLE "reviews" ADD COLUMN IF NOT EXISTS "quality_rating" bigint CONSTRAINT "chk_reviews_quality_rating" CHECK (quality_rating >= 1 AND quality_rating <= 5);
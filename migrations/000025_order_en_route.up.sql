@@ -0,0 +1,2 @@
+-- When the yandaş of an accepted order set off
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "en_route_at" timestamptz;
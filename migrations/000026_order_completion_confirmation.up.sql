@@ -0,0 +1,2 @@
+-- Completions awaiting the customer's confirmation
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "completion_requested_at" timestamptz;
@@ -0,0 +1,2 @@
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "original_price";
+DROP TABLE IF EXISTS "order_adjustments";
@@ -0,0 +1,4 @@
+-- Amount adjustments proposed by the yandaş and approved by the customer
+CREATE TABLE IF NOT EXISTS "order_adjustments" ("id" uuid DEFAULT gen_random_uuid(),"order_id" uuid NOT NULL,"proposed_by" uuid NOT NULL,"previous_amount" decimal(10,2) NOT NULL,"amount" decimal(10,2) NOT NULL,"reason" text NOT NULL,"status" varchar(20) NOT NULL DEFAULT 'pending',"responded_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_order_adjustments_order_id" ON "order_adjustments" ("order_id");
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "original_price" decimal(10,2);
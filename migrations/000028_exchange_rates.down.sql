@@ -0,0 +1,2 @@
+ALTER TABLE "favorite_activities" DROP COLUMN IF EXISTS "currency";
+DROP TABLE IF EXISTS "exchange_rates";
@@ -0,0 +1,3 @@
+-- Daily exchange rates against the base currency for reporting, and the currency of favorite activity prices
+CREATE TABLE IF NOT EXISTS "exchange_rates" ("currency" varchar(3),"date" date,"rate" decimal(18,6) NOT NULL,"source" varchar(20) NOT NULL,"updated_at" timestamptz,PRIMARY KEY ("currency","date"));
+ALTER TABLE "favorite_activities" ADD COLUMN IF NOT EXISTS "currency" varchar(3) DEFAULT 'TRY';
@@ -0,0 +1,24 @@
+ALTER TABLE "yandas_profiles" ALTER COLUMN "travel_fee_per_km" TYPE decimal(10,2) USING "travel_fee_per_km" / 100.0;
+ALTER TABLE "yandas_services" ALTER COLUMN "base_price" TYPE decimal(10,2) USING "base_price" / 100.0;
+ALTER TABLE "service_options" ALTER COLUMN "price" TYPE decimal(10,2) USING "price" / 100.0;
+ALTER TABLE "service_price_tiers" ALTER COLUMN "surcharge" TYPE decimal(10,2) USING "surcharge" / 100.0;
+ALTER TABLE "orders" ALTER COLUMN "agreed_price" TYPE decimal(10,2) USING "agreed_price" / 100.0;
+ALTER TABLE "orders" ALTER COLUMN "original_price" TYPE decimal(10,2) USING "original_price" / 100.0;
+ALTER TABLE "orders" ALTER COLUMN "platform_fee" TYPE decimal(10,2) USING "platform_fee" / 100.0;
+ALTER TABLE "orders" ALTER COLUMN "net_earnings" TYPE decimal(10,2) USING "net_earnings" / 100.0;
+ALTER TABLE "job_requests" ALTER COLUMN "budget_min" TYPE decimal(10,2) USING "budget_min" / 100.0;
+ALTER TABLE "job_requests" ALTER COLUMN "budget_max" TYPE decimal(10,2) USING "budget_max" / 100.0;
+ALTER TABLE "bids" ALTER COLUMN "amount" TYPE decimal(10,2) USING "amount" / 100.0;
+ALTER TABLE "order_line_items" ALTER COLUMN "amount" TYPE decimal(10,2) USING "amount" / 100.0;
+ALTER TABLE "order_adjustments" ALTER COLUMN "previous_amount" TYPE decimal(10,2) USING "previous_amount" / 100.0;
+ALTER TABLE "order_adjustments" ALTER COLUMN "amount" TYPE decimal(10,2) USING "amount" / 100.0;
+ALTER TABLE "receipts" ALTER COLUMN "subtotal" TYPE decimal(10,2) USING "subtotal" / 100.0;
+ALTER TABLE "receipts" ALTER COLUMN "vat_amount" TYPE decimal(10,2) USING "vat_amount" / 100.0;
+ALTER TABLE "receipts" ALTER COLUMN "total" TYPE decimal(10,2) USING "total" / 100.0;
+ALTER TABLE "payout_entries" ALTER COLUMN "gross_amount" TYPE decimal(10,2) USING "gross_amount" / 100.0;
+ALTER TABLE "payout_entries" ALTER COLUMN "platform_fee" TYPE decimal(10,2) USING "platform_fee" / 100.0;
+ALTER TABLE "payout_entries" ALTER COLUMN "net_amount" TYPE decimal(10,2) USING "net_amount" / 100.0;
+ALTER TABLE "payouts" ALTER COLUMN "amount" TYPE decimal(12,2) USING "amount" / 100.0;
+ALTER TABLE "favorite_activities" ALTER COLUMN "old_price" TYPE decimal(10,2) USING "old_price" / 100.0;
+ALTER TABLE "favorite_activities" ALTER COLUMN "new_price" TYPE decimal(10,2) USING "new_price" / 100.0;
+ALTER TABLE "yandas_daily_stats" ALTER COLUMN "revenue" DROP DEFAULT, ALTER COLUMN "revenue" TYPE decimal(12,2) USING "revenue" / 100.0, ALTER COLUMN "revenue" SET DEFAULT 0;
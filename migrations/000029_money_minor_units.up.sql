@@ -0,0 +1,25 @@
+-- Store money as integer minor units (kuruş) instead of decimals
+ALTER TABLE "yandas_profiles" ALTER COLUMN "travel_fee_per_km" TYPE bigint USING ROUND("travel_fee_per_km" * 100)::bigint;
+ALTER TABLE "yandas_services" ALTER COLUMN "base_price" TYPE bigint USING ROUND("base_price" * 100)::bigint;
+ALTER TABLE "service_options" ALTER COLUMN "price" TYPE bigint USING ROUND("price" * 100)::bigint;
+ALTER TABLE "service_price_tiers" ALTER COLUMN "surcharge" TYPE bigint USING ROUND("surcharge" * 100)::bigint;
+ALTER TABLE "orders" ALTER COLUMN "agreed_price" TYPE bigint USING ROUND("agreed_price" * 100)::bigint;
+ALTER TABLE "orders" ALTER COLUMN "original_price" TYPE bigint USING ROUND("original_price" * 100)::bigint;
+ALTER TABLE "orders" ALTER COLUMN "platform_fee" TYPE bigint USING ROUND("platform_fee" * 100)::bigint;
+ALTER TABLE "orders" ALTER COLUMN "net_earnings" TYPE bigint USING ROUND("net_earnings" * 100)::bigint;
+ALTER TABLE "job_requests" ALTER COLUMN "budget_min" TYPE bigint USING ROUND("budget_min" * 100)::bigint;
+ALTER TABLE "job_requests" ALTER COLUMN "budget_max" TYPE bigint USING ROUND("budget_max" * 100)::bigint;
+ALTER TABLE "bids" ALTER COLUMN "amount" TYPE bigint USING ROUND("amount" * 100)::bigint;
+ALTER TABLE "order_line_items" ALTER COLUMN "amount" TYPE bigint USING ROUND("amount" * 100)::bigint;
+ALTER TABLE "order_adjustments" ALTER COLUMN "previous_amount" TYPE bigint USING ROUND("previous_amount" * 100)::bigint;
+ALTER TABLE "order_adjustments" ALTER COLUMN "amount" TYPE bigint USING ROUND("amount" * 100)::bigint;
+ALTER TABLE "receipts" ALTER COLUMN "subtotal" TYPE bigint USING ROUND("subtotal" * 100)::bigint;
+ALTER TABLE "receipts" ALTER COLUMN "vat_amount" TYPE bigint USING ROUND("vat_amount" * 100)::bigint;
+ALTER TABLE "receipts" ALTER COLUMN "total" TYPE bigint USING ROUND("total" * 100)::bigint;
+ALTER TABLE "payout_entries" ALTER COLUMN "gross_amount" TYPE bigint USING ROUND("gross_amount" * 100)::bigint;
+ALTER TABLE "payout_entries" ALTER COLUMN "platform_fee" TYPE bigint USING ROUND("platform_fee" * 100)::bigint;
+ALTER TABLE "payout_entries" ALTER COLUMN "net_amount" TYPE bigint USING ROUND("net_amount" * 100)::bigint;
+ALTER TABLE "payouts" ALTER COLUMN "amount" TYPE bigint USING ROUND("amount" * 100)::bigint;
+ALTER TABLE "favorite_activities" ALTER COLUMN "old_price" TYPE bigint USING ROUND("old_price" * 100)::bigint;
+ALTER TABLE "favorite_activities" ALTER COLUMN "new_price" TYPE bigint USING ROUND("new_price" * 100)::bigint;
+ALTER TABLE "yandas_daily_stats" ALTER COLUMN "revenue" DROP DEFAULT, ALTER COLUMN "revenue" TYPE bigint USING ROUND("revenue" * 100)::bigint, ALTER COLUMN "revenue" SET DEFAULT 0;
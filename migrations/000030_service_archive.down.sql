@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_yandas_services_deleted_at";
+ALTER TABLE "yandas_services" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "yandas_services" DROP COLUMN IF EXISTS "archived_at";
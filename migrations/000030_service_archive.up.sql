@@ -0,0 +1,6 @@
+-- Archived and deleted yandaş services; both stay inactive, only archived ones can be restored
+ALTER TABLE "yandas_services" ADD COLUMN IF NOT EXISTS "archived_at" timestamptz;
+ALTER TABLE "yandas_services" ADD COLUMN IF NOT EXISTS "deleted_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_yandas_services_deleted_at" ON "yandas_services" ("deleted_at");
+-- Services deactivated before this migration count as archived
+UPDATE "yandas_services" SET "archived_at" = NOW() WHERE "is_active" = false AND "archived_at" IS NULL;
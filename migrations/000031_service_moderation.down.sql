@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_yandas_services_status";
+ALTER TABLE "yandas_services" DROP COLUMN IF EXISTS "rejection_reason";
+ALTER TABLE "yandas_services" DROP COLUMN IF EXISTS "status";
@@ -0,0 +1,4 @@
+-- Publication status of yandaş services: drafts, services awaiting admin approval and rejected ones are not listed
+ALTER TABLE "yandas_services" ADD COLUMN IF NOT EXISTS "status" varchar(20) NOT NULL DEFAULT 'active';
+ALTER TABLE "yandas_services" ADD COLUMN IF NOT EXISTS "rejection_reason" text;
+CREATE INDEX IF NOT EXISTS "idx_yandas_services_status" ON "yandas_services" ("status");
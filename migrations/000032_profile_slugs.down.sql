@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_yandas_profiles_slug";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "slug";
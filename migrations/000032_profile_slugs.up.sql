@@ -0,0 +1,23 @@
+-- Human-readable URL slugs for public yandaş profiles, derived from the user's name
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "slug" varchar(120);
+
+-- Backfill: transliterate Turkish letters, keep a-z and 0-9 and join the rest
+-- with dashes; when several profiles share a name all but the oldest get part
+-- of their ID appended
+WITH names AS (
+	SELECT p."id", p."created_at",
+		COALESCE(NULLIF(TRIM(BOTH '-' FROM LEFT(REGEXP_REPLACE(
+			LOWER(TRANSLATE(u."full_name", 'çÇğĞıIİöÖşŞüÜâÂîÎûÛ', 'ccggiiioossuuaaiiuu')),
+			'[^a-z0-9]+', '-', 'g'), 80)), ''), 'yandas') AS "base"
+	FROM "yandas_profiles" p
+	JOIN "users" u ON u."id" = p."user_id"
+	WHERE p."slug" IS NULL
+), ranked AS (
+	SELECT "id", "base", ROW_NUMBER() OVER (PARTITION BY "base" ORDER BY "created_at", "id") AS "n"
+	FROM names
+)
+UPDATE "yandas_profiles" p SET "slug" = CASE WHEN r."n" = 1 THEN r."base" ELSE r."base" || '-' || LEFT(p."id"::text, 8) END
+FROM ranked r
+WHERE r."id" = p."id";
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_yandas_profiles_slug" ON "yandas_profiles" ("slug");
@@ -0,0 +1,6 @@
+-- Outbox of domain events relayed to their subscribers after the writing transaction commits
+CREATE TABLE IF NOT EXISTS "domain_events" ("id" uuid DEFAULT gen_random_uuid(),"type" varchar(50) NOT NULL,"aggregate_id" uuid NOT NULL,"payload" jsonb NOT NULL,"status" varchar(20) NOT NULL DEFAULT 'pending',"attempts" bigint DEFAULT 0,"next_attempt_at" timestamptz,"last_error" text,"published_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_domain_events_next_attempt_at" ON "domain_events" ("next_attempt_at");
+CREATE INDEX IF NOT EXISTS "idx_domain_events_status" ON "domain_events" ("status");
+CREATE INDEX IF NOT EXISTS "idx_domain_events_aggregate_id" ON "domain_events" ("aggregate_id");
+CREATE INDEX IF NOT EXISTS "idx_domain_events_type" ON "domain_events" ("type");
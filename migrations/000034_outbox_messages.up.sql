@@ -0,0 +1,4 @@
+-- Outbox of WebSocket broadcasts and push notifications delivered after the writing transaction commits
+CREATE TABLE IF NOT EXISTS "outbox_messages" ("id" uuid DEFAULT gen_random_uuid(),"channel" varchar(10) NOT NULL,"target" varchar(100) NOT NULL,"type" varchar(50) NOT NULL,"payload" jsonb NOT NULL,"status" varchar(20) NOT NULL DEFAULT 'pending',"attempts" bigint DEFAULT 0,"next_attempt_at" timestamptz,"last_error" text,"delivered_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_outbox_messages_next_attempt_at" ON "outbox_messages" ("next_attempt_at");
+CREATE INDEX IF NOT EXISTS "idx_outbox_messages_status" ON "outbox_messages" ("status");
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_notifications_unread_created";
+ALTER TABLE "notification_preferences" DROP COLUMN IF EXISTS "digest_enabled";
+ALTER TABLE "notification_preferences" DROP COLUMN IF EXISTS "batch_enabled";
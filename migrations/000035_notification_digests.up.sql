@@ -0,0 +1,4 @@
+-- Per type opt-ins for batched pushes and the daily email digest of unread notifications
+ALTER TABLE "notification_preferences" ADD COLUMN IF NOT EXISTS "batch_enabled" boolean NOT NULL DEFAULT true;
+ALTER TABLE "notification_preferences" ADD COLUMN IF NOT EXISTS "digest_enabled" boolean NOT NULL DEFAULT true;
+CREATE INDEX IF NOT EXISTS "idx_notifications_unread_created" ON "notifications" ("created_at") WHERE is_read = false;
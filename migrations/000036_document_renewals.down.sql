@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "document_renewals";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "adli_sicil_uploaded_at";
@@ -0,0 +1,6 @@
+-- Yearly renewal of criminal records: upload time on the profile and the renewal requests sent to yandaşlar
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "adli_sicil_uploaded_at" timestamptz;
+UPDATE "yandas_profiles" SET "adli_sicil_uploaded_at" = "created_at" WHERE "adli_sicil_pdf_url" IS NOT NULL AND "adli_sicil_uploaded_at" IS NULL;
+CREATE TABLE IF NOT EXISTS "document_renewals" ("id" uuid DEFAULT gen_random_uuid(),"yandas_profile_id" uuid NOT NULL,"document" varchar(30) NOT NULL,"status" varchar(20) NOT NULL,"deadline" timestamptz NOT NULL,"renewed_at" timestamptz,"lapsed_at" timestamptz,"waived_by" uuid,"waived_until" timestamptz,"waiver_reason" text,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_document_renewals_yandas_profile" FOREIGN KEY ("yandas_profile_id") REFERENCES "yandas_profiles"("id"));
+CREATE INDEX IF NOT EXISTS "idx_document_renewals_status_deadline" ON "document_renewals" ("status","deadline");
+CREATE INDEX IF NOT EXISTS "idx_document_renewals_yandas_profile_id" ON "document_renewals" ("yandas_profile_id");
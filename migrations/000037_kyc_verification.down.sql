@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS "idx_yandas_profiles_kyc_reference";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "kyc_checked_at";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "kyc_reason";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "kyc_score";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "kyc_status";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "kyc_reference";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "kyc_provider";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "selfie_url";
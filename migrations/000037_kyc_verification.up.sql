@@ -0,0 +1,9 @@
+-- Selfie and ID liveness check of yandaş applicants through a KYC provider
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "selfie_url" text;
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "kyc_provider" varchar(30);
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "kyc_reference" varchar(100);
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "kyc_status" varchar(20);
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "kyc_score" decimal(5,4);
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "kyc_reason" text;
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "kyc_checked_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_yandas_profiles_kyc_reference" ON "yandas_profiles" ("kyc_reference");
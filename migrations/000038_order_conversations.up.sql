@@ -0,0 +1,11 @@
+-- Orders keep the conversation opened between customer and yandaş when they were accepted
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "conversation_id" uuid;
+
+-- Backfill: accepted orders link to the conversation their customer and yandaş already have
+UPDATE "orders" o SET "conversation_id" = c."id"
+FROM "yandas_profiles" p, "conversations" c
+WHERE p."id" = o."yandas_id"
+	AND c."customer_id" = o."customer_id"
+	AND c."yandas_id" = p."user_id"
+	AND o."status" <> 'pending'
+	AND o."conversation_id" IS NULL;
@@ -0,0 +1,2 @@
+-- Full-text search over chat messages for conversation search
+CREATE INDEX IF NOT EXISTS "idx_messages_content_search" ON "messages" USING gin (to_tsvector('simple', "content"));
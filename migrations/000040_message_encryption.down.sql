@@ -0,0 +1,4 @@
+-- Dropping the data keys makes encrypted messages unreadable; only roll back before any were sealed
+ALTER TABLE "messages" DROP COLUMN IF EXISTS "encrypted";
+ALTER TABLE "conversations" DROP COLUMN IF EXISTS "data_key_id";
+ALTER TABLE "conversations" DROP COLUMN IF EXISTS "data_key";
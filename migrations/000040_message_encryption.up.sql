@@ -0,0 +1,6 @@
+-- Conversations keep their message data key, wrapped by the master key
+ALTER TABLE "conversations" ADD COLUMN IF NOT EXISTS "data_key" bytea;
+ALTER TABLE "conversations" ADD COLUMN IF NOT EXISTS "data_key_id" varchar(100);
+
+-- Messages written before encryption was enabled stay plaintext until cmd/encrypt_messages seals them
+ALTER TABLE "messages" ADD COLUMN IF NOT EXISTS "encrypted" boolean NOT NULL DEFAULT false;
@@ -0,0 +1,11 @@
+-- Values are not decrypted here: only roll back if PII_KEYS was never set
+CREATE INDEX IF NOT EXISTS "idx_sms_deliveries_phone" ON "sms_deliveries" ("phone");
+DROP INDEX IF EXISTS "idx_sms_deliveries_phone_hash";
+ALTER TABLE "sms_deliveries" DROP COLUMN IF EXISTS "phone_hash";
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_phone" ON "users" ("phone");
+DROP INDEX IF EXISTS "idx_users_phone_hash";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "phone_hash";
+
+ALTER TABLE "sms_deliveries" ALTER COLUMN "phone" TYPE varchar(20);
+ALTER TABLE "users" ALTER COLUMN "phone" TYPE varchar(20);
@@ -0,0 +1,14 @@
+-- Encrypted phone numbers are longer than the numbers themselves
+ALTER TABLE "users" ALTER COLUMN "phone" TYPE varchar(255);
+ALTER TABLE "sms_deliveries" ALTER COLUMN "phone" TYPE varchar(255);
+
+-- Ciphertext is randomized, so uniqueness and lookups move to a blind index,
+-- an HMAC of the number filled in by the application (cmd/encrypt_pii for
+-- existing rows)
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "phone_hash" varchar(64);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_phone_hash" ON "users" ("phone_hash");
+DROP INDEX IF EXISTS "idx_users_phone";
+
+ALTER TABLE "sms_deliveries" ADD COLUMN IF NOT EXISTS "phone_hash" varchar(64);
+CREATE INDEX IF NOT EXISTS "idx_sms_deliveries_phone_hash" ON "sms_deliveries" ("phone_hash");
+DROP INDEX IF EXISTS "idx_sms_deliveries_phone";
@@ -0,0 +1,5 @@
+-- Versioned legal documents and who accepted which version, when and from where
+CREATE TABLE IF NOT EXISTS "consent_documents" ("id" uuid DEFAULT gen_random_uuid(),"kind" varchar(20) NOT NULL,"version" bigint NOT NULL,"title" varchar(200) NOT NULL,"content" text NOT NULL,"published_by" uuid NOT NULL,"published_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_consent_documents_kind_version" ON "consent_documents" ("kind","version");
+CREATE TABLE IF NOT EXISTS "user_consents" ("id" uuid DEFAULT gen_random_uuid(),"user_id" uuid NOT NULL,"document_id" uuid NOT NULL,"ip_address" varchar(45),"user_agent" varchar(255),"accepted_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_user_consents_document" FOREIGN KEY ("document_id") REFERENCES "consent_documents"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_consents_user_document" ON "user_consents" ("user_id","document_id");
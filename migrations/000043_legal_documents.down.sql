@@ -0,0 +1,8 @@
+-- Translations have no place in the old table
+DELETE FROM "user_consents" WHERE "document_id" IN (SELECT "id" FROM "legal_documents" WHERE "locale" <> 'tr');
+DELETE FROM "legal_documents" WHERE "locale" <> 'tr';
+DROP INDEX IF EXISTS "idx_legal_documents_kind_locale_version";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_consent_documents_kind_version" ON "legal_documents" ("kind","version");
+ALTER TABLE "legal_documents" DROP COLUMN IF EXISTS "requires_consent";
+ALTER TABLE "legal_documents" DROP COLUMN IF EXISTS "locale";
+ALTER TABLE "legal_documents" RENAME TO "consent_documents";
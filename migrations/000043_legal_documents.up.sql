@@ -0,0 +1,7 @@
+-- Consent documents become the legal content itself: one row per version and
+-- locale. Existing versions are the Turkish ones users accepted.
+ALTER TABLE "consent_documents" RENAME TO "legal_documents";
+ALTER TABLE "legal_documents" ADD COLUMN IF NOT EXISTS "locale" varchar(10) NOT NULL DEFAULT 'tr';
+ALTER TABLE "legal_documents" ADD COLUMN IF NOT EXISTS "requires_consent" boolean NOT NULL DEFAULT true;
+DROP INDEX IF EXISTS "idx_consent_documents_kind_version";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_legal_documents_kind_locale_version" ON "legal_documents" ("kind","locale","version");
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "application_answers";
+DROP TABLE IF EXISTS "application_questions";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "category_ids";
+ALTER TABLE "categories" DROP COLUMN IF EXISTS "required_documents";
@@ -0,0 +1,8 @@
+-- Per-category application forms: the documents a category requires and the
+-- questions it asks, with applicants' answers kept for the admin review
+ALTER TABLE "categories" ADD COLUMN IF NOT EXISTS "required_documents" text[];
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "category_ids" text[];
+CREATE TABLE IF NOT EXISTS "application_questions" ("id" uuid DEFAULT gen_random_uuid(),"category_id" uuid NOT NULL,"label" varchar(200) NOT NULL,"help_text" varchar(500),"type" varchar(20) NOT NULL,"options" text[],"is_required" boolean NOT NULL DEFAULT false,"sort_order" bigint DEFAULT 0,"is_active" boolean DEFAULT true,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_application_questions_category_id" ON "application_questions" ("category_id");
+CREATE TABLE IF NOT EXISTS "application_answers" ("id" uuid DEFAULT gen_random_uuid(),"yandas_profile_id" uuid NOT NULL,"question_id" uuid NOT NULL,"category_id" uuid NOT NULL,"question" varchar(200) NOT NULL,"type" varchar(20) NOT NULL,"value" text NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_application_answers_profile_question" ON "application_answers" ("yandas_profile_id","question_id");
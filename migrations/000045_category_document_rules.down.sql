@@ -0,0 +1,5 @@
+ALTER TABLE "categories" ADD COLUMN IF NOT EXISTS "required_documents" text[];
+UPDATE "categories" SET "required_documents" = rules."documents"
+FROM (SELECT "category_id", array_agg("document") AS "documents" FROM "category_document_rules" GROUP BY "category_id") rules
+WHERE "categories"."id" = rules."category_id";
+DROP TABLE IF EXISTS "category_document_rules";
@@ -0,0 +1,8 @@
+-- The documents a category requires move from a column into rules, which
+-- also carry the hint shown to applicants missing the document
+CREATE TABLE IF NOT EXISTS "category_document_rules" ("id" uuid DEFAULT gen_random_uuid(),"category_id" uuid NOT NULL,"document" varchar(30) NOT NULL,"hint" varchar(300),"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_category_document_rules_category_document" ON "category_document_rules" ("category_id","document");
+INSERT INTO "category_document_rules" ("category_id","document","created_at")
+SELECT DISTINCT "id", unnest("required_documents"), NOW() FROM "categories" WHERE "required_documents" IS NOT NULL
+ON CONFLICT DO NOTHING;
+ALTER TABLE "categories" DROP COLUMN IF EXISTS "required_documents";
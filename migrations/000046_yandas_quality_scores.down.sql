@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "yandas_quality_scores";
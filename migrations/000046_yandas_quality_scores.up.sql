@@ -0,0 +1,3 @@
+-- Composite quality score per yandaş, recomputed on a schedule
+CREATE TABLE IF NOT EXISTS "yandas_quality_scores" ("yandas_id" uuid,"rating_avg" decimal(3,2) NOT NULL DEFAULT 0,"review_count" bigint NOT NULL DEFAULT 0,"order_count" bigint NOT NULL DEFAULT 0,"ended_count" bigint NOT NULL DEFAULT 0,"completed_count" bigint NOT NULL DEFAULT 0,"cancelled_count" bigint NOT NULL DEFAULT 0,"disputed_count" bigint NOT NULL DEFAULT 0,"response_minutes" decimal(10,2),"completion_rate" decimal(5,4) NOT NULL DEFAULT 0,"cancellation_rate" decimal(5,4) NOT NULL DEFAULT 0,"dispute_rate" decimal(5,4) NOT NULL DEFAULT 0,"score" decimal(5,2) NOT NULL DEFAULT 0,"computed_at" timestamptz NOT NULL,PRIMARY KEY ("yandas_id"));
+CREATE INDEX IF NOT EXISTS "idx_yandas_quality_scores_score" ON "yandas_quality_scores" ("score");
@@ -0,0 +1,5 @@
+-- Product analytics events sent by the apps, for funnel reports
+CREATE TABLE IF NOT EXISTS "analytics_events" ("id" uuid DEFAULT gen_random_uuid(),"name" varchar(40) NOT NULL,"actor" varchar(64) NOT NULL,"user_id" uuid,"session_id" varchar(64),"platform" varchar(20),"app_version" varchar(20),"screen" varchar(100),"yandas_id" uuid,"properties" jsonb,"occurred_at" timestamptz NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_analytics_events_name_occurred" ON "analytics_events" ("name","occurred_at");
+CREATE INDEX IF NOT EXISTS "idx_analytics_events_actor_occurred" ON "analytics_events" ("actor","occurred_at");
+CREATE INDEX IF NOT EXISTS "idx_analytics_events_created_at" ON "analytics_events" ("created_at");
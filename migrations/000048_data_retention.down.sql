@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "idx_audit_logs_created_at";
+DROP INDEX IF EXISTS "idx_messages_created_at";
+INSERT INTO "messages" ("id","conversation_id","sender_id","content","message_type","media_url","duration_seconds","latitude","longitude","location_label","live_until","live_ended_at","is_read","encrypted","created_at")
+SELECT "id","conversation_id","sender_id","content","message_type","media_url","duration_seconds","latitude","longitude","location_label","live_until","live_ended_at","is_read","encrypted","created_at" FROM "messages_archive"
+ON CONFLICT ("id") DO NOTHING;
+DROP TABLE IF EXISTS "messages_archive";
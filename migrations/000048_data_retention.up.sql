@@ -0,0 +1,5 @@
+-- Cold storage for messages past the retention window, and indexes for the retention jobs
+CREATE TABLE IF NOT EXISTS "messages_archive" ("id" uuid,"conversation_id" uuid NOT NULL,"sender_id" uuid NOT NULL,"content" text NOT NULL,"message_type" varchar(20),"media_url" varchar(500),"duration_seconds" bigint,"latitude" decimal(10,8),"longitude" decimal(11,8),"location_label" varchar(255),"live_until" timestamptz,"live_ended_at" timestamptz,"is_read" boolean,"encrypted" boolean NOT NULL DEFAULT false,"created_at" timestamptz NOT NULL,"archived_at" timestamptz NOT NULL,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_messages_archive_conversation_id" ON "messages_archive" ("conversation_id");
+CREATE INDEX IF NOT EXISTS "idx_messages_created_at" ON "messages" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_created_at" ON "audit_logs" ("created_at");
@@ -0,0 +1,3 @@
+-- pg_dump snapshots taken by cmd/backup, for restores and the backup status endpoint
+CREATE TABLE IF NOT EXISTS "database_backups" ("id" uuid DEFAULT gen_random_uuid(),"file_name" varchar(255) NOT NULL,"storage" varchar(20) NOT NULL,"location" varchar(500),"size_bytes" bigint NOT NULL DEFAULT 0,"checksum" varchar(64),"status" varchar(20) NOT NULL,"error" text,"started_at" timestamptz NOT NULL,"finished_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_database_backups_status_started" ON "database_backups" ("status","started_at");
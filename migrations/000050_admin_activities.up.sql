@@ -0,0 +1,21 @@
+-- Admin activity feed, streamed live to the admin:events WebSocket room
+CREATE TABLE IF NOT EXISTS "admin_activities" ("id" uuid DEFAULT gen_random_uuid(),"type" varchar(40) NOT NULL,"subject_id" uuid NOT NULL,"user_id" uuid,"summary" varchar(255) NOT NULL,"data" jsonb,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_admin_activities_created_at" ON "admin_activities" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_admin_activities_type_created" ON "admin_activities" ("type","created_at");
+
+-- Start the feed with the last 30 days of orders, applications and tickets;
+-- payment webhooks were not stored before
+INSERT INTO admin_activities (type, subject_id, user_id, summary, data, created_at)
+SELECT 'order_created', id, customer_id, 'New order',
+	json_build_object('status', status, 'agreed_price', agreed_price, 'currency', currency, 'yandas_id', yandas_id)::jsonb, created_at
+FROM orders WHERE created_at > now() - interval '30 days' AND deleted_at IS NULL;
+
+INSERT INTO admin_activities (type, subject_id, user_id, summary, data, created_at)
+SELECT 'application_submitted', id, user_id, 'New yandaş application',
+	json_build_object('approval_status', approval_status)::jsonb, created_at
+FROM yandas_profiles WHERE created_at > now() - interval '30 days';
+
+INSERT INTO admin_activities (type, subject_id, user_id, summary, data, created_at)
+SELECT 'ticket_created', id, user_id, left(subject, 255),
+	json_build_object('category', category, 'priority', priority)::jsonb, created_at
+FROM support_tickets WHERE created_at > now() - interval '30 days';
@@ -0,0 +1,4 @@
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "vacation_started_at";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "vacation_policy";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "vacation_until";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "vacation_from";
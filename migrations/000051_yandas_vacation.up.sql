@@ -0,0 +1,6 @@
+-- Vacation mode: a planned absence with a return date and what happens to
+-- pending orders meanwhile
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "vacation_from" timestamptz;
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "vacation_until" timestamptz;
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "vacation_policy" varchar(20);
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "vacation_started_at" timestamptz;
@@ -0,0 +1,3 @@
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "lead_time_minutes";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "instant_booking";
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "min_order_value";
@@ -0,0 +1,5 @@
+-- Per-yandaş booking preferences: a minimum order value, instant booking and
+-- the notice an order needs
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "min_order_value" bigint;
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "instant_booking" boolean DEFAULT false;
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "lead_time_minutes" bigint DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_messages_content_search";
+CREATE INDEX IF NOT EXISTS "idx_messages_content_search" ON "messages" USING gin (to_tsvector('simple', "content"));
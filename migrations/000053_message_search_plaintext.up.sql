@@ -0,0 +1,5 @@
+-- Encrypted messages can't be searched; index only the plaintext ones rather
+-- than the ciphertext of sealed messages
+DROP INDEX IF EXISTS "idx_messages_content_search";
+CREATE INDEX IF NOT EXISTS "idx_messages_content_search" ON "messages" USING gin (to_tsvector('simple', "content"))
+	WHERE "message_type" = 'text' AND NOT "encrypted";
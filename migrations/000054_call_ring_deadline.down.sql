@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_call_logs_ringing";
+ALTER TABLE "call_logs" DROP COLUMN IF EXISTS "ring_expires_at";
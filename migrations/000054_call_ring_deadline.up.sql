@@ -0,0 +1,4 @@
+-- Ring deadlines are kept with the call so the scheduler can mark unanswered
+-- calls missed even if the API instance that started them restarts
+ALTER TABLE "call_logs" ADD COLUMN IF NOT EXISTS "ring_expires_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_call_logs_ringing" ON "call_logs" ("status","ring_expires_at");
@@ -0,0 +1 @@
+ALTER TABLE "yandas_profiles" DROP COLUMN IF EXISTS "min_order_currency";
@@ -0,0 +1,3 @@
+-- The minimum order value is kept with its currency; existing minimums were set in TRY
+ALTER TABLE "yandas_profiles" ADD COLUMN IF NOT EXISTS "min_order_currency" varchar(3);
+UPDATE "yandas_profiles" SET "min_order_currency" = 'TRY' WHERE "min_order_value" IS NOT NULL;
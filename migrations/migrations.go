@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned SQL migrations applied by
+// database.Migrate and cmd/migrate. Files are named
+// <version>_<name>.up.sql / <version>_<name>.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,169 @@
+package agora
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const recordingBaseURL = "https://api.agora.io/v1/apps"
+
+// RecordingMode is the Agora cloud recording mode used for call evidence
+const RecordingMode = "mix"
+
+// StorageConfig describes the third-party bucket Agora uploads recordings to
+type StorageConfig struct {
+	Vendor         int // 1 = AWS S3
+	Region         int // vendor-specific region index
+	Bucket         string
+	AccessKey      string
+	SecretKey      string
+	FileNamePrefix []string
+}
+
+// RecordingSession identifies a running cloud recording
+type RecordingSession struct {
+	ResourceID string `json:"resource_id"`
+	SID        string `json:"sid"`
+}
+
+// RecordingClient talks to the Agora Cloud Recording RESTful API
+type RecordingClient struct {
+	appID          string
+	customerID     string
+	customerSecret string
+	storage        StorageConfig
+	httpClient     *http.Client
+}
+
+// NewRecordingClient creates a cloud recording client
+func NewRecordingClient(appID, customerID, customerSecret string, storage StorageConfig) *RecordingClient {
+	return &RecordingClient{
+		appID:          appID,
+		customerID:     customerID,
+		customerSecret: customerSecret,
+		storage:        storage,
+		httpClient:     &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Enabled reports whether the client has the credentials needed to record
+func (c *RecordingClient) Enabled() bool {
+	return c.appID != "" && c.customerID != "" && c.customerSecret != "" && c.storage.Bucket != ""
+}
+
+// Start acquires a recording resource and starts recording the channel with the given recorder uid
+func (c *RecordingClient) Start(channelName string, uid uint32, token string) (*RecordingSession, error) {
+	if !c.Enabled() {
+		return nil, fmt.Errorf("agora cloud recording is not configured")
+	}
+
+	uidStr := strconv.FormatUint(uint64(uid), 10)
+
+	var acquired struct {
+		ResourceID string `json:"resourceId"`
+	}
+	err := c.post("/cloud_recording/acquire", map[string]interface{}{
+		"cname": channelName,
+		"uid":   uidStr,
+		"clientRequest": map[string]interface{}{
+			"resourceExpiredHour": 24,
+			"scene":               0,
+		},
+	}, &acquired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire recording resource: %w", err)
+	}
+
+	var started struct {
+		ResourceID string `json:"resourceId"`
+		SID        string `json:"sid"`
+	}
+	path := fmt.Sprintf("/cloud_recording/resourceid/%s/mode/%s/start", acquired.ResourceID, RecordingMode)
+	err = c.post(path, map[string]interface{}{
+		"cname": channelName,
+		"uid":   uidStr,
+		"clientRequest": map[string]interface{}{
+			"token": token,
+			"recordingConfig": map[string]interface{}{
+				"channelType": 0,
+				"streamTypes": 2,
+				"maxIdleTime": 30,
+			},
+			"storageConfig": map[string]interface{}{
+				"vendor":         c.storage.Vendor,
+				"region":         c.storage.Region,
+				"bucket":         c.storage.Bucket,
+				"accessKey":      c.storage.AccessKey,
+				"secretKey":      c.storage.SecretKey,
+				"fileNamePrefix": c.storage.FileNamePrefix,
+			},
+		},
+	}, &started)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start recording: %w", err)
+	}
+
+	return &RecordingSession{ResourceID: started.ResourceID, SID: started.SID}, nil
+}
+
+// Stop stops a running recording and returns the uploaded file names
+func (c *RecordingClient) Stop(channelName string, uid uint32, session RecordingSession) ([]string, error) {
+	var stopped struct {
+		ServerResponse struct {
+			FileList []struct {
+				FileName string `json:"fileName"`
+			} `json:"fileList"`
+			UploadingStatus string `json:"uploadingStatus"`
+		} `json:"serverResponse"`
+	}
+	path := fmt.Sprintf("/cloud_recording/resourceid/%s/sid/%s/mode/%s/stop", session.ResourceID, session.SID, RecordingMode)
+	err := c.post(path, map[string]interface{}{
+		"cname":         channelName,
+		"uid":           strconv.FormatUint(uint64(uid), 10),
+		"clientRequest": map[string]interface{}{},
+	}, &stopped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop recording: %w", err)
+	}
+
+	files := make([]string, 0, len(stopped.ServerResponse.FileList))
+	for _, f := range stopped.ServerResponse.FileList {
+		files = append(files, f.FileName)
+	}
+	return files, nil
+}
+
+func (c *RecordingClient) post(path string, body interface{}, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, recordingBaseURL+"/"+c.appID+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.customerID, c.customerSecret)
+	req.Header.Set("Content-Type", "application/json;charset=utf-8")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("agora returned %d: %s", resp.StatusCode, string(data))
+	}
+
+	return json.Unmarshal(data, out)
+}
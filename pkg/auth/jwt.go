@@ -5,17 +5,38 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Token types, carried in the typ claim so a refresh token can't be used as an access token
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
 )
 
 // Claims represents JWT claims
 type Claims struct {
-	UserID   string `json:"user_id"`
-	Email    string `json:"email"`
-	Role     string `json:"role"`
-	Platform string `json:"platform"`
+	UserID       string `json:"user_id"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	Platform     string `json:"platform"`
+	SessionID    string `json:"sid,omitempty"`
+	TokenVersion int    `json:"tv"`
+	// Type is empty on tokens issued before it was added, which are accepted as either
+	Type string `json:"typ,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// IsRefresh reports whether the token may only be exchanged for a new pair
+func (c *Claims) IsRefresh() bool {
+	return c.Type == TokenTypeRefresh
+}
+
+// IsAccess reports whether the token may authenticate API requests
+func (c *Claims) IsAccess() bool {
+	return c.Type == "" || c.Type == TokenTypeAccess
+}
+
 // TokenPair holds access and refresh tokens
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
@@ -23,42 +44,15 @@ type TokenPair struct {
 	ExpiresIn    int64  `json:"expires_in"`
 }
 
-// GenerateTokenPair generates access and refresh tokens
-func GenerateTokenPair(userID, email, role, platform, secret string, accessExpiry, refreshExpiry time.Duration) (*TokenPair, error) {
-	// Generate access token
-	accessClaims := &Claims{
-		UserID:   userID,
-		Email:    email,
-		Role:     role,
-		Platform: platform,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "yandas-api",
-		},
-	}
-
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString([]byte(secret))
+// GenerateTokenPair generates access and refresh tokens carrying the identity in base.
+// Registered claims are filled in here.
+func GenerateTokenPair(base Claims, secret string, accessExpiry, refreshExpiry time.Duration) (*TokenPair, error) {
+	accessTokenString, err := signToken(base, secret, TokenTypeAccess, accessExpiry)
 	if err != nil {
 		return nil, err
 	}
 
-	// Generate refresh token
-	refreshClaims := &Claims{
-		UserID:   userID,
-		Email:    email,
-		Role:     role,
-		Platform: platform,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(refreshExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "yandas-api",
-		},
-	}
-
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(secret))
+	refreshTokenString, err := signToken(base, secret, TokenTypeRefresh, refreshExpiry)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +64,20 @@ func GenerateTokenPair(userID, email, role, platform, secret string, accessExpir
 	}, nil
 }
 
+func signToken(claims Claims, secret, typ string, expiry time.Duration) (string, error) {
+	claims.Type = typ
+	// A random jti keeps tokens signed within the same second distinct, so a
+	// rotated refresh token never equals the one it replaces
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		Issuer:    "yandas-api",
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &claims)
+	return token.SignedString([]byte(secret))
+}
+
 // ValidateToken validates a JWT token and returns claims
 func ValidateToken(tokenString, secret string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
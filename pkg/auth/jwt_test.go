@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenPairTypes(t *testing.T) {
+	pair, err := GenerateTokenPair(Claims{UserID: "u1", SessionID: "s1"}, "secret", time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	access, err := ValidateToken(pair.AccessToken, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !access.IsAccess() || access.IsRefresh() {
+		t.Errorf("expected an access token, got typ %q", access.Type)
+	}
+
+	refresh, err := ValidateToken(pair.RefreshToken, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refresh.IsAccess() || !refresh.IsRefresh() {
+		t.Errorf("expected a refresh token, got typ %q", refresh.Type)
+	}
+
+	if legacy := (&Claims{}); !legacy.IsAccess() {
+		t.Error("expected a token without typ to be accepted as an access token")
+	}
+}
+
+func TestTokensAreUnique(t *testing.T) {
+	base := Claims{UserID: "u1", SessionID: "s1"}
+	first, err := GenerateTokenPair(base, "secret", time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := GenerateTokenPair(base, "secret", time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.RefreshToken == second.RefreshToken || first.AccessToken == second.AccessToken {
+		t.Error("expected tokens issued in the same second to differ")
+	}
+
+	claims, err := ValidateToken(first.RefreshToken, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.ID == "" {
+		t.Error("expected a jti claim")
+	}
+}
@@ -0,0 +1,181 @@
+// Package crypto encrypts personal data in database columns. Values are sealed
+// with AES-256-GCM under a named key, and the key's name is stored with the
+// value, so keys can be rotated: new values use the current key while older
+// ones keep opening with theirs until they are re-encrypted. Ciphertext is
+// randomized, so exact lookups go through a blind index, a keyed HMAC of the
+// value stored next to it.
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/yandas/backend/pkg/envelope"
+)
+
+// DefaultIndexKey is the blind index key used until one is configured. Like
+// the default JWT secret it is only fit for development.
+const DefaultIndexKey = "default-index-key-change-in-production"
+
+// prefix marks encrypted values: enc:<key id>:<base64 ciphertext>. Values
+// without it are plaintext written before encryption was turned on.
+const prefix = "enc:"
+
+var (
+	ErrUnknownKey = errors.New("crypto: value encrypted with an unknown key")
+	ErrMalformed  = errors.New("crypto: malformed encrypted value")
+)
+
+// Key is a named column encryption key of envelope.DataKeySize bytes
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// Keyring holds the column encryption keys and the blind index key. The
+// first key encrypts; the others only decrypt values not yet rotated. A
+// keyring without keys stores values in plaintext.
+type Keyring struct {
+	current string
+	keys    map[string][]byte
+	index   []byte
+}
+
+// NewKeyring returns a keyring encrypting with the first of keys
+func NewKeyring(keys []Key, indexKey []byte) (*Keyring, error) {
+	if len(indexKey) == 0 {
+		return nil, errors.New("crypto: blind index key is empty")
+	}
+	k := &Keyring{keys: make(map[string][]byte, len(keys)), index: indexKey}
+	for _, key := range keys {
+		if key.ID == "" || strings.Contains(key.ID, ":") {
+			return nil, fmt.Errorf("crypto: invalid key ID %q", key.ID)
+		}
+		if len(key.Secret) != envelope.DataKeySize {
+			return nil, fmt.Errorf("crypto: key %s must be %d bytes, got %d", key.ID, envelope.DataKeySize, len(key.Secret))
+		}
+		if _, ok := k.keys[key.ID]; ok {
+			return nil, fmt.Errorf("crypto: duplicate key ID %q", key.ID)
+		}
+		k.keys[key.ID] = key.Secret
+		if k.current == "" {
+			k.current = key.ID
+		}
+	}
+	return k, nil
+}
+
+// ParseKeyring reads keys in the config format "id:base64,id:base64", newest
+// first. An empty list disables encryption but keeps the blind index.
+func ParseKeyring(keys, indexKey string) (*Keyring, error) {
+	var parsed []Key
+	for _, entry := range strings.Split(keys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("crypto: key %q is not id:base64", entry)
+		}
+		raw, err := base64.StdEncoding.DecodeString(secret)
+		if err != nil {
+			return nil, fmt.Errorf("crypto: key %s is not base64: %w", id, err)
+		}
+		parsed = append(parsed, Key{ID: id, Secret: raw})
+	}
+	return NewKeyring(parsed, []byte(indexKey))
+}
+
+// Encrypt seals plaintext with the current key. Empty values and keyrings
+// without keys return plaintext unchanged.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if k.current == "" || plaintext == "" {
+		return plaintext, nil
+	}
+	sealed, err := envelope.Seal(k.keys[k.current], []byte(plaintext), []byte(k.current))
+	if err != nil {
+		return "", err
+	}
+	return prefix + k.current + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value Encrypt returned, under whichever key sealed it.
+// Plaintext values are returned as they are.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, prefix) {
+		return value, nil
+	}
+	id, encoded, ok := strings.Cut(value[len(prefix):], ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+	key, ok := k.keys[id]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrMalformed
+	}
+	plaintext, err := envelope.Open(key, sealed, []byte(id))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether a stored value is plaintext or sealed with a
+// key other than the current one
+func (k *Keyring) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	if !strings.HasPrefix(value, prefix) {
+		return k.current != ""
+	}
+	return k.current == "" || !strings.HasPrefix(value[len(prefix):], k.current+":")
+}
+
+// BlindIndex returns the lookup hash of a value. It does not depend on the
+// encryption keys, so rotating them leaves the index intact; changing the
+// index key means recomputing every index column.
+func (k *Keyring) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, k.index)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+var active atomic.Pointer[Keyring]
+
+func init() {
+	k, _ := NewKeyring(nil, []byte(DefaultIndexKey))
+	active.Store(k)
+}
+
+// Use makes k the keyring of encrypted columns and blind indexes. Call it at
+// startup, before the database is used.
+func Use(k *Keyring) {
+	active.Store(k)
+}
+
+// Active returns the keyring in use
+func Active() *Keyring {
+	return active.Load()
+}
+
+// BlindIndex returns the lookup hash of a value under the keyring in use, or
+// nil for a nil value
+func BlindIndex(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	index := Active().BlindIndex(*value)
+	return &index
+}
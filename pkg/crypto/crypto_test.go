@@ -0,0 +1,130 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+func testKey(id string, fill byte) Key {
+	return Key{ID: id, Secret: bytes.Repeat([]byte{fill}, 32)}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	old, _ := NewKeyring([]Key{testKey("v1", 1)}, []byte("index"))
+	sealed, err := old.Encrypt("+905551112233")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealed, "enc:v1:") || strings.Contains(sealed, "5551112233") {
+		t.Fatalf("unexpected ciphertext %q", sealed)
+	}
+
+	// A new key in front encrypts; values under the old one still open
+	rotated, _ := NewKeyring([]Key{testKey("v2", 2), testKey("v1", 1)}, []byte("index"))
+	if plaintext, err := rotated.Decrypt(sealed); err != nil || plaintext != "+905551112233" {
+		t.Fatalf("old value did not open after rotation: %q (%v)", plaintext, err)
+	}
+	if !rotated.NeedsRotation(sealed) || !rotated.NeedsRotation("+905551112233") {
+		t.Fatal("expected old and plaintext values to need rotation")
+	}
+	resealed, _ := rotated.Encrypt("+905551112233")
+	if rotated.NeedsRotation(resealed) {
+		t.Fatalf("value under the current key needs no rotation: %q", resealed)
+	}
+
+	// Once the old key is dropped its values no longer open
+	current, _ := NewKeyring([]Key{testKey("v2", 2)}, []byte("index"))
+	if _, err := current.Decrypt(sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestKeyringWithoutKeysStoresPlaintext(t *testing.T) {
+	k, _ := NewKeyring(nil, []byte("index"))
+	if value, _ := k.Encrypt("/uploads/u1/kimlik_on.jpg"); value != "/uploads/u1/kimlik_on.jpg" {
+		t.Fatalf("expected plaintext, got %q", value)
+	}
+	if k.NeedsRotation("/uploads/u1/kimlik_on.jpg") {
+		t.Fatal("plaintext needs no rotation while encryption is off")
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	a, _ := NewKeyring([]Key{testKey("v1", 1)}, []byte("index"))
+	b, _ := NewKeyring([]Key{testKey("v2", 2)}, []byte("index"))
+	other, _ := NewKeyring(nil, []byte("another index"))
+
+	if a.BlindIndex("+905551112233") != b.BlindIndex("+905551112233") {
+		t.Fatal("rotating encryption keys must not change the blind index")
+	}
+	if a.BlindIndex("+905551112233") == other.BlindIndex("+905551112233") {
+		t.Fatal("expected the index key to change the blind index")
+	}
+	if a.BlindIndex("+905551112233") == a.BlindIndex("+905551112234") {
+		t.Fatal("expected different numbers to index differently")
+	}
+}
+
+func TestParseKeyring(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))
+	k, err := ParseKeyring("v2:"+secret+", v1:"+secret, "index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sealed, _ := k.Encrypt("x"); !strings.HasPrefix(sealed, "enc:v2:") {
+		t.Fatalf("expected the first key to encrypt, got %q", sealed)
+	}
+
+	for _, keys := range []string{"v1", "v1:short", "v1:" + secret + ",v1:" + secret} {
+		if _, err := ParseKeyring(keys, "index"); err == nil {
+			t.Errorf("expected %q to be refused", keys)
+		}
+	}
+	if _, err := ParseKeyring("", ""); err == nil {
+		t.Error("expected an empty index key to be refused")
+	}
+}
+
+type person struct {
+	Phone *string `gorm:"serializer:encrypted"`
+}
+
+func TestSerializer(t *testing.T) {
+	k, _ := NewKeyring([]Key{testKey("v1", 1)}, []byte("index"))
+	previous := Active()
+	Use(k)
+	defer Use(previous)
+
+	s, err := schema.Parse(&person{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	field := s.LookUpField("Phone")
+	ctx := context.Background()
+
+	phone := "+905551112233"
+	stored, err := Serializer{}.Value(ctx, field, reflect.ValueOf(&person{}), &phone)
+	if err != nil || !strings.HasPrefix(stored.(string), "enc:v1:") {
+		t.Fatalf("expected a sealed value, got %v (%v)", stored, err)
+	}
+
+	var p person
+	if err := (Serializer{}).Scan(ctx, field, reflect.ValueOf(&p).Elem(), stored); err != nil {
+		t.Fatal(err)
+	}
+	if p.Phone == nil || *p.Phone != phone {
+		t.Fatalf("expected the phone back, got %v", p.Phone)
+	}
+
+	if err := (Serializer{}).Scan(ctx, field, reflect.ValueOf(&p).Elem(), nil); err != nil || p.Phone != nil {
+		t.Fatalf("expected NULL to scan as nil, got %v (%v)", p.Phone, err)
+	}
+}
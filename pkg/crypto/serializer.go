@@ -0,0 +1,64 @@
+package crypto
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is the gorm serializer of encrypted columns. Tag string or
+// *string fields with `gorm:"serializer:encrypted"` to have them sealed on
+// write and opened on read with the keyring in use.
+const SerializerName = "encrypted"
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+// Serializer encrypts and decrypts string columns with the active keyring.
+// It only sees values gorm writes from structs: updates from maps and raw SQL
+// bypass it and must encrypt themselves.
+type Serializer struct{}
+
+// Scan implements schema.SerializerInterface
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType).Elem()
+	if dbValue != nil {
+		var stored string
+		switch v := dbValue.(type) {
+		case string:
+			stored = v
+		case []byte:
+			stored = string(v)
+		default:
+			return fmt.Errorf("crypto: cannot decrypt %T into %s", dbValue, field.Name)
+		}
+		plaintext, err := Active().Decrypt(stored)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.Name, err)
+		}
+		if field.FieldType.Kind() == reflect.Ptr {
+			fieldValue.Set(reflect.ValueOf(&plaintext))
+		} else {
+			fieldValue.SetString(plaintext)
+		}
+	}
+	field.ReflectValueOf(ctx, dst).Set(fieldValue)
+	return nil
+}
+
+// Value implements schema.SerializerValuerInterface
+func (Serializer) Value(_ context.Context, field *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	switch v := fieldValue.(type) {
+	case string:
+		return Active().Encrypt(v)
+	case *string:
+		if v == nil {
+			return nil, nil
+		}
+		return Active().Encrypt(*v)
+	}
+	return nil, fmt.Errorf("crypto: cannot encrypt %T in %s, only string and *string", fieldValue, field.Name)
+}
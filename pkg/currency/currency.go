@@ -0,0 +1,46 @@
+// Package currency lists the currencies the platform accepts, holds amounts
+// as integer minor units (kuruş, cents) and fetches exchange rates for
+// reporting. Reports are totalled in the base currency.
+package currency
+
+import "strings"
+
+// Base is the currency new prices default to and reports are totalled in
+const Base = "TRY"
+
+// Currency describes an accepted ISO 4217 currency
+type Currency struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Exponent int    `json:"exponent"` // digits after the decimal point; 2 means 1 unit = 100 minor units
+}
+
+var supported = []Currency{
+	{Code: "TRY", Name: "Türk Lirası", Symbol: "₺", Exponent: 2},
+	{Code: "USD", Name: "ABD Doları", Symbol: "$", Exponent: 2},
+	{Code: "EUR", Name: "Euro", Symbol: "€", Exponent: 2},
+	{Code: "GBP", Name: "İngiliz Sterlini", Symbol: "£", Exponent: 2},
+}
+
+// Supported returns the accepted currencies, the base currency first
+func Supported() []Currency {
+	return append([]Currency(nil), supported...)
+}
+
+// Lookup returns the accepted currency with code, ignoring case
+func Lookup(code string) (Currency, bool) {
+	code = strings.ToUpper(code)
+	for _, c := range supported {
+		if c.Code == code {
+			return c, true
+		}
+	}
+	return Currency{}, false
+}
+
+// IsSupported reports whether code is an accepted currency
+func IsSupported(code string) bool {
+	_, ok := Lookup(code)
+	return ok
+}
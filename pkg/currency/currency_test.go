@@ -0,0 +1,48 @@
+package currency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsSupported(t *testing.T) {
+	if IsSupported("XYZ") || !IsSupported("gbp") {
+		t.Error("unexpected supported currencies")
+	}
+}
+
+const tcmbSheet = `<?xml version="1.0" encoding="UTF-8"?>
+<Tarih_Date Tarih="16.10.2026" Date="10/16/2026" Bulten_No="2026/197">
+	<Currency CrossOrder="0" Kod="USD" CurrencyCode="USD">
+		<Unit>1</Unit><Isim>ABD DOLARI</Isim><ForexBuying>41.8012</ForexBuying><ForexSelling>41.8765</ForexSelling>
+	</Currency>
+	<Currency CrossOrder="9" Kod="EUR" CurrencyCode="EUR">
+		<Unit>1</Unit><Isim>EURO</Isim><ForexBuying>48.6100</ForexBuying><ForexSelling>48.6976</ForexSelling>
+	</Currency>
+	<Currency CrossOrder="5" Kod="JPY" CurrencyCode="JPY">
+		<Unit>100</Unit><Isim>JAPON YENİ</Isim><ForexBuying>27.6543</ForexBuying><ForexSelling>27.8374</ForexSelling>
+	</Currency>
+</Tarih_Date>`
+
+func TestTCMB(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(tcmbSheet))
+	}))
+	defer srv.Close()
+
+	rates, err := NewRateProvider("tcmb", srv.URL).Latest(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates.Date.Format("2006-01-02") != "2026-10-16" {
+		t.Errorf("unexpected date %v", rates.Date)
+	}
+	if rates.Rates["USD"] != 41.8765 || rates.Rates["EUR"] != 48.6976 {
+		t.Errorf("unexpected rates %v", rates.Rates)
+	}
+	if _, ok := rates.Rates["JPY"]; ok {
+		t.Error("expected currencies that are not accepted to be skipped")
+	}
+}
@@ -0,0 +1,104 @@
+package currency
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in minor units (kuruş, cents) of its currency. Every
+// accepted currency has two decimals. Amounts are added up as integers so
+// sums never drift; only multiplying by a rate rounds, to the nearest minor
+// unit. In JSON it is a number with two decimals, e.g. 1234.50.
+type Money int64
+
+// ErrInvalidMoney is returned for text that is not a decimal amount with at most two decimals
+var ErrInvalidMoney = errors.New("invalid amount")
+
+// FromFloat converts a decimal amount to Money, rounding to the nearest minor unit
+func FromFloat(amount float64) Money {
+	return Money(math.Round(amount * 100))
+}
+
+// Float returns the amount in major units
+func (m Money) Float() float64 {
+	return float64(m) / 100
+}
+
+// Mul multiplies the amount by f, e.g. a commission or VAT rate, rounding to
+// the nearest minor unit
+func (m Money) Mul(f float64) Money {
+	return Money(math.Round(float64(m) * f))
+}
+
+// String renders the amount with two decimals, e.g. -12.05
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	frac := strconv.FormatInt(v%100, 10)
+	if len(frac) == 1 {
+		frac = "0" + frac
+	}
+	return sign + strconv.FormatInt(v/100, 10) + "." + frac
+}
+
+// ParseMoney reads a decimal amount such as "1234.5" exactly
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || len(frac) > 2 || !digits(whole) || !digits(frac) {
+		return 0, ErrInvalidMoney
+	}
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/100-1 {
+		return 0, ErrInvalidMoney
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+	cents, _ := strconv.ParseInt(frac, 10, 64)
+	m := Money(units*100 + cents)
+	if neg {
+		m = -m
+	}
+	return m, nil
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalJSON writes the amount as a number with two decimals
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads a number or a numeric string. Amounts with more than
+// two decimals are rounded to the nearest minor unit.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" {
+		return nil
+	}
+	if parsed, err := ParseMoney(s); err == nil {
+		*m = parsed
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return ErrInvalidMoney
+	}
+	*m = FromFloat(f)
+	return nil
+}
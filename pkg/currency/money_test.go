@@ -0,0 +1,64 @@
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMoneyString(t *testing.T) {
+	cases := map[Money]string{0: "0.00", 5: "0.05", 1250: "12.50", -1205: "-12.05", 123456789: "1234567.89"}
+	for m, want := range cases {
+		if got := m.String(); got != want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(m), got, want)
+		}
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	cases := map[string]Money{"0": 0, "12.5": 1250, "12.05": 1205, " 1234.50 ": 123450, "-0.1": -10}
+	for s, want := range cases {
+		if got, err := ParseMoney(s); err != nil || got != want {
+			t.Errorf("ParseMoney(%q) = %d, %v; want %d", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", ".5", "1.234", "1,5", "abc", "1e3", "99999999999999999999"} {
+		if _, err := ParseMoney(s); !errors.Is(err, ErrInvalidMoney) {
+			t.Errorf("ParseMoney(%q): expected ErrInvalidMoney, got %v", s, err)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var v struct {
+		A Money  `json:"a"`
+		B Money  `json:"b"`
+		C *Money `json:"c"`
+	}
+	if err := json.Unmarshal([]byte(`{"a": 0.1, "b": "19.999", "c": 1e2}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.A != 10 || v.B != 2000 || v.C == nil || *v.C != 10000 {
+		t.Errorf("unexpected amounts %d %d %v", v.A, v.B, v.C)
+	}
+	out, _ := json.Marshal(v)
+	if string(out) != `{"a":0.10,"b":20.00,"c":100.00}` {
+		t.Errorf("unexpected JSON %s", out)
+	}
+	if err := json.Unmarshal([]byte(`{"a": true}`), &v); err == nil {
+		t.Error("expected a non-numeric amount to be rejected")
+	}
+}
+
+func TestMoneySumsExactly(t *testing.T) {
+	var total Money
+	for i := 0; i < 10; i++ {
+		total += FromFloat(0.1)
+	}
+	if total != 100 {
+		t.Errorf("expected 1.00, got %s", total)
+	}
+	if fee := Money(33333).Mul(0.15); fee != 5000 {
+		t.Errorf("expected a 50.00 fee, got %s", fee)
+	}
+}
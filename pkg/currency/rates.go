@@ -0,0 +1,33 @@
+package currency
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Rates is how much of the base currency one unit of each currency buys on a day
+type Rates struct {
+	Date   time.Time
+	Source string
+	Rates  map[string]float64
+}
+
+// RateProvider fetches the latest exchange rates against the base currency
+type RateProvider interface {
+	Name() string
+	Latest(ctx context.Context) (*Rates, error)
+}
+
+// NewRateProvider returns the provider configured by name, or nil when rates
+// are not fetched and reports count every currency at par
+func NewRateProvider(name, url string) RateProvider {
+	switch name {
+	case "", "none":
+		return nil
+	case "tcmb":
+		return NewTCMB(url)
+	}
+	log.Printf("[CURRENCY] unknown rate provider %q, exchange rates disabled", name)
+	return nil
+}
@@ -0,0 +1,92 @@
+package currency
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TCMBURL is the Central Bank of the Republic of Türkiye's daily rate sheet
+const TCMBURL = "https://www.tcmb.gov.tr/kurlar/today.xml"
+
+// TCMB reads the indicative rates the Turkish central bank publishes each
+// business day. They are in lira, so the base currency must be TRY.
+type TCMB struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewTCMB creates a provider reading the rate sheet at url, or TCMBURL when empty
+func NewTCMB(url string) *TCMB {
+	if url == "" {
+		url = TCMBURL
+	}
+	return &TCMB{url: url, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name identifies the provider
+func (t *TCMB) Name() string { return "tcmb" }
+
+// Latest returns the forex selling rate of each accepted currency
+func (t *TCMB) Latest(ctx context.Context) (*Rates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tcmb: unexpected %d response", resp.StatusCode)
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 256*1024))
+	if err != nil {
+		return nil, err
+	}
+	return parseTCMB(raw)
+}
+
+func parseTCMB(raw []byte) (*Rates, error) {
+	var sheet struct {
+		Date       string `xml:"Date,attr"` // MM/DD/YYYY
+		Currencies []struct {
+			Code         string `xml:"CurrencyCode,attr"`
+			Unit         string `xml:"Unit"`
+			ForexSelling string `xml:"ForexSelling"`
+		} `xml:"Currency"`
+	}
+	if err := xml.Unmarshal(raw, &sheet); err != nil {
+		return nil, fmt.Errorf("tcmb: %w", err)
+	}
+	date, err := time.Parse("01/02/2006", sheet.Date)
+	if err != nil {
+		return nil, fmt.Errorf("tcmb: unexpected date %q", sheet.Date)
+	}
+
+	rates := &Rates{Date: date, Source: "tcmb", Rates: map[string]float64{}}
+	for _, c := range sheet.Currencies {
+		if c.Code == Base || !IsSupported(c.Code) {
+			continue
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(c.ForexSelling), 64)
+		if err != nil || rate <= 0 {
+			continue
+		}
+		// Some currencies are quoted per 100 units
+		if unit, err := strconv.ParseFloat(strings.TrimSpace(c.Unit), 64); err == nil && unit > 0 {
+			rate /= unit
+		}
+		rates.Rates[c.Code] = rate
+	}
+	if len(rates.Rates) == 0 {
+		return nil, fmt.Errorf("tcmb: no rates for accepted currencies")
+	}
+	return rates, nil
+}
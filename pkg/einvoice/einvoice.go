@@ -0,0 +1,58 @@
+// Package einvoice defines the interface for Turkish e-Arşiv / e-Fatura
+// integrators (GİB-authorized providers). Receipts are generated locally;
+// a provider, when configured, registers them as official invoices.
+package einvoice
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yandas/backend/pkg/currency"
+)
+
+// Line is one invoiced item; Amount includes VAT
+type Line struct {
+	Description string
+	Amount      currency.Money
+}
+
+// Invoice carries what an integrator needs to issue an e-Arşiv invoice
+type Invoice struct {
+	Number      string
+	OrderNumber string
+	IssuedAt    time.Time
+	Currency    string
+	BuyerName   string
+	BuyerEmail  string
+	SellerName  string
+	Lines       []Line
+	Subtotal    currency.Money // excluding VAT
+	VATRate     float64
+	VATAmount   currency.Money
+	Total       currency.Money
+}
+
+// Result identifies the invoice on the provider side
+type Result struct {
+	UUID   string // ETTN assigned by the integrator
+	Status string
+	URL    string
+}
+
+// Provider issues invoices with an integrator
+type Provider interface {
+	Name() string
+	Issue(ctx context.Context, invoice *Invoice) (*Result, error)
+}
+
+// NewProvider returns the provider configured by name, or nil when e-invoicing is disabled.
+// No integrator is bundled yet; add one here when a contract is in place.
+func NewProvider(name string) Provider {
+	switch name {
+	case "", "none":
+		return nil
+	}
+	log.Printf("[EINVOICE] unknown provider %q, e-invoicing disabled", name)
+	return nil
+}
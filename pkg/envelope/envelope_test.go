@@ -0,0 +1,60 @@
+package envelope
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	key, _ := GenerateDataKey()
+	sealed, err := Seal(key, []byte("Kapı şifresi 4512"), []byte("conv-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := Open(key, sealed, []byte("conv-1"))
+	if err != nil || string(plaintext) != "Kapı şifresi 4512" {
+		t.Fatalf("unexpected plaintext %q (%v)", plaintext, err)
+	}
+
+	// Ciphertext moved to another conversation does not open
+	if _, err := Open(key, sealed, []byte("conv-2")); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected ErrDecrypt for another aad, got %v", err)
+	}
+	other, _ := GenerateDataKey()
+	if _, err := Open(other, sealed, []byte("conv-1")); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected ErrDecrypt for another key, got %v", err)
+	}
+}
+
+func TestLocalKeyWrap(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString(make([]byte, DataKeySize))
+	master, err := NewMasterKey("local", "local-1", secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataKey, _ := GenerateDataKey()
+	wrapped, err := master.Wrap(context.Background(), dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unwrapped, err := master.Unwrap(context.Background(), wrapped)
+	if err != nil || string(unwrapped) != string(dataKey) {
+		t.Fatalf("data key did not survive wrapping (%v)", err)
+	}
+}
+
+func TestNewMasterKey(t *testing.T) {
+	if key, err := NewMasterKey("none", "", ""); key != nil || err != nil {
+		t.Fatalf("expected encryption disabled, got %v, %v", key, err)
+	}
+	if _, err := NewMasterKey("local", "local-1", base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Fatal("expected a short master key to be refused")
+	}
+	if _, err := NewMasterKey("vault", "k", ""); err == nil {
+		t.Fatal("expected an unknown provider to be refused")
+	}
+}
@@ -0,0 +1,95 @@
+// Package export writes tabular data as CSV or XLSX directly to a stream
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Supported formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ErrUnsupportedFormat is returned for formats other than csv and xlsx
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// Writer receives rows one at a time; Close must be called to flush the output
+type Writer interface {
+	WriteRow(cells []string) error
+	Close() error
+}
+
+// NewWriter returns a writer for the given format
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV, "":
+		return NewCSV(w)
+	case FormatXLSX:
+		return NewXLSX(w)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// ContentType returns the MIME type for a format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+// NewCSV writes UTF-8 CSV with a BOM so spreadsheet apps keep Turkish characters intact
+func NewCSV(w io.Writer) (Writer, error) {
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (c *csvWriter) WriteRow(cells []string) error {
+	safe := make([]string, len(cells))
+	for i, cell := range cells {
+		safe[i] = neutralizeFormula(cell)
+	}
+	return c.w.Write(safe)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// neutralizeFormula prefixes values that spreadsheet apps would evaluate as
+// formulas. A sign followed only by a number, such as a negative amount or a
+// +90 phone number, is left as it is.
+func neutralizeFormula(cell string) string {
+	if cell == "" {
+		return cell
+	}
+	switch cell[0] {
+	case '=', '@', '\t', '\r':
+		return "'" + cell
+	case '+', '-':
+		if !signedNumber(cell[1:]) {
+			return "'" + cell
+		}
+	}
+	return cell
+}
+
+// signedNumber reports whether what follows a sign is a plain number: digits
+// with the separators used in amounts and phone numbers
+func signedNumber(rest string) bool {
+	if rest == "" || rest[0] < '0' || rest[0] > '9' {
+		return false
+	}
+	return strings.Trim(rest, "0123456789., ") == ""
+}
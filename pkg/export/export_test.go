@@ -0,0 +1,65 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCSVNeutralizesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewCSV(&buf)
+	w.WriteRow([]string{"=SUM(A1)", "Ayşe", "-5"})
+	w.Close()
+
+	out := strings.TrimPrefix(buf.String(), "\xEF\xBB\xBF")
+	if out != "'=SUM(A1),Ayşe,-5\n" {
+		t.Errorf("unexpected csv %q", out)
+	}
+
+	tests := map[string]string{
+		"+905551234567":      "+905551234567",
+		"+90 555 123 45 67":  "+90 555 123 45 67",
+		"-12.50":             "-12.50",
+		"-1+1":               "'-1+1",
+		"+cmd|' /C calc'!A0": "'+cmd|' /C calc'!A0",
+		"-":                  "'-",
+		"@SUM(A1)":           "'@SUM(A1)",
+		"\t=1":               "'\t=1",
+	}
+	for cell, want := range tests {
+		if got := neutralizeFormula(cell); got != want {
+			t.Errorf("%q: got %q, want %q", cell, got, want)
+		}
+	}
+}
+
+func TestXLSXIsReadableZip(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewXLSX(&buf)
+	w.WriteRow([]string{"id", "name"})
+	w.WriteRow([]string{"1", "<Çağrı & Co>"})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range zr.File {
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, _ := f.Open()
+		body, _ := io.ReadAll(rc)
+		rc.Close()
+		if !strings.Contains(string(body), `<row r="2">`) || !strings.Contains(string(body), "&lt;Çağrı &amp; Co&gt;") {
+			t.Errorf("unexpected sheet %s", body)
+		}
+		return
+	}
+	t.Fatal("sheet1.xml missing")
+}
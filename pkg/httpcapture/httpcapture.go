@@ -0,0 +1,170 @@
+// Package httpcapture records HTTP requests and responses for debugging with
+// credentials and other secrets redacted.
+package httpcapture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MaxBodySize is how much of each body is kept; the rest is cut off
+const MaxBodySize = 16 << 10
+
+// Redacted replaces secret values
+const Redacted = "[REDACTED]"
+
+// Exchange is one captured request and its response
+type Exchange struct {
+	ID              string              `json:"id"`
+	RuleID          string              `json:"rule_id"`
+	UserID          string              `json:"user_id,omitempty"`
+	Method          string              `json:"method"`
+	Path            string              `json:"path"`
+	Query           string              `json:"query,omitempty"`
+	ClientIP        string              `json:"client_ip"`
+	Status          int                 `json:"status"`
+	LatencyMS       float64             `json:"latency_ms"`
+	RequestHeaders  map[string][]string `json:"request_headers"`
+	RequestBody     string              `json:"request_body,omitempty"`
+	ResponseHeaders map[string][]string `json:"response_headers"`
+	ResponseBody    string              `json:"response_body,omitempty"`
+	CapturedAt      time.Time           `json:"captured_at"`
+}
+
+// secretHeaders are never recorded
+var secretHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+	"X-Webhook-Signature": true,
+	"X-Twilio-Signature":  true,
+}
+
+// secretFields are name fragments of JSON fields and query parameters whose
+// values are redacted; secretNames must match the whole name
+var (
+	secretFields = []string{"password", "token", "secret", "otp", "iban", "card_number", "signature", "api_key", "apikey"}
+	secretNames  = map[string]bool{"pin": true, "cvv": true, "cvc": true}
+)
+
+func isSecretField(name string) bool {
+	name = strings.ToLower(name)
+	if secretNames[name] {
+		return true
+	}
+	for _, fragment := range secretFields {
+		if strings.Contains(name, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// isVerificationCode reports whether a "code" field holds a numeric
+// verification code rather than an error code, which stays readable
+func isVerificationCode(name string, value interface{}) bool {
+	code, ok := value.(string)
+	if !ok || strings.ToLower(name) != "code" || code == "" {
+		return false
+	}
+	return strings.Trim(code, "0123456789") == ""
+}
+
+// Headers copies headers with secret ones redacted
+func Headers(header http.Header) map[string][]string {
+	out := make(map[string][]string, len(header))
+	for name, values := range header {
+		if secretHeaders[http.CanonicalHeaderKey(name)] {
+			out[name] = []string{Redacted}
+			continue
+		}
+		out[name] = append([]string(nil), values...)
+	}
+	return out
+}
+
+// Query redacts secret parameters of a raw query string, such as the token
+// WebSocket clients pass
+func Query(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return Redacted
+	}
+	for name, value := range values {
+		if isSecretField(name) || isVerificationCode(name, value[0]) {
+			values[name] = []string{Redacted}
+		}
+	}
+	return values.Encode()
+}
+
+// Body returns a printable, redacted copy of a body. JSON and form fields
+// with secret names are redacted; other non-text content is summarized.
+// Bodies longer than MaxBodySize are expected to be cut at MaxBodySize+1 bytes.
+func Body(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	size := fmt.Sprintf("%d bytes", len(body))
+	truncated := len(body) > MaxBodySize
+	if truncated {
+		size = fmt.Sprintf("over %d bytes", MaxBodySize)
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if truncated {
+			// A cut-off document cannot be parsed to find its secrets
+			return fmt.Sprintf("[%s of JSON, too large to redact]", size)
+		}
+		var doc interface{}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return fmt.Sprintf("[%s of invalid JSON]", size)
+		}
+		redacted, _ := json.Marshal(redactJSON(doc))
+		return string(redacted)
+	case mediaType == "application/x-www-form-urlencoded":
+		if truncated {
+			return fmt.Sprintf("[%s of form data, too large to redact]", size)
+		}
+		return Query(string(body))
+	case strings.HasPrefix(mediaType, "text/"):
+		if truncated {
+			return string(bytes.ToValidUTF8(body[:MaxBodySize], nil)) + "…"
+		}
+		return string(body)
+	}
+	if mediaType == "" {
+		mediaType = "unknown content"
+	}
+	return fmt.Sprintf("[%s of %s]", size, mediaType)
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSecretField(key) || isVerificationCode(key, value) {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redactJSON(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactJSON(value)
+		}
+	}
+	return v
+}
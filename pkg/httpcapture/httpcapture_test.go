@@ -0,0 +1,53 @@
+package httpcapture
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHeadersRedactsCredentials(t *testing.T) {
+	headers := Headers(http.Header{
+		"Authorization": {"Bearer abc"},
+		"Cookie":        {"session=1"},
+		"Content-Type":  {"application/json"},
+	})
+	if headers["Authorization"][0] != Redacted || headers["Cookie"][0] != Redacted {
+		t.Errorf("expected credentials redacted, got %v", headers)
+	}
+	if headers["Content-Type"][0] != "application/json" {
+		t.Errorf("expected other headers kept, got %v", headers)
+	}
+}
+
+func TestQueryRedactsSecrets(t *testing.T) {
+	got := Query("token=abc&page=2")
+	if strings.Contains(got, "abc") || !strings.Contains(got, "page=2") {
+		t.Errorf("unexpected query %q", got)
+	}
+}
+
+func TestBodyRedactsJSONSecrets(t *testing.T) {
+	body := `{"phone":"+905551112233","password":"hunter2","code":"123456","tokens":{"refresh_token":"r"},"items":[{"api_key":"k"}],"error":{"code":"service_in_use"}}`
+	got := Body("application/json; charset=utf-8", []byte(body))
+	for _, secret := range []string{"hunter2", "123456", `"r"`, `"k"`} {
+		if strings.Contains(got, secret) {
+			t.Errorf("expected %s redacted in %s", secret, got)
+		}
+	}
+	for _, kept := range []string{"+905551112233", "service_in_use"} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("expected %s kept in %s", kept, got)
+		}
+	}
+}
+
+func TestBodySummarizesUnreadableContent(t *testing.T) {
+	if got := Body("image/jpeg", []byte{0xff, 0xd8, 0xff}); got != "[3 bytes of image/jpeg]" {
+		t.Errorf("unexpected summary %q", got)
+	}
+	large := []byte(`{"password":"` + strings.Repeat("x", MaxBodySize) + `"}`)[:MaxBodySize+1]
+	if got := Body("application/json", large); strings.Contains(got, "xxx") {
+		t.Errorf("expected an oversized JSON body to be withheld, got %d bytes", len(got))
+	}
+}
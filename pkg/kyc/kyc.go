@@ -0,0 +1,64 @@
+// Package kyc checks an applicant's identity through interchangeable
+// providers: a selfie is matched against the photo on their ID card, with a
+// liveness check on the selfie. Providers either answer at once or report
+// the result later through a webhook.
+package kyc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// Verification statuses, normalized across providers
+const (
+	StatusPending  = "pending"
+	StatusVerified = "verified"
+	StatusRejected = "rejected"
+)
+
+// ErrInvalidWebhook is returned for result webhooks that fail authentication or cannot be parsed
+var ErrInvalidWebhook = errors.New("invalid kyc webhook")
+
+// Check is what a provider needs to verify an applicant. Paths point at local files.
+type Check struct {
+	ApplicantID string
+	FullName    string
+	SelfiePath  string
+	IDFrontPath string
+	IDBackPath  string
+}
+
+// Result is the outcome of a check. Reference identifies the check on the
+// provider side; a pending result is completed later through the webhook.
+type Result struct {
+	Reference string
+	Status    string
+	Score     *float64 // face match confidence from 0 to 1, if the provider reports one
+	Reason    string
+}
+
+// Provider submits identity checks
+type Provider interface {
+	Name() string
+	Verify(ctx context.Context, check *Check) (*Result, error)
+}
+
+// ResultParser is implemented by providers that report results through a webhook
+type ResultParser interface {
+	ParseResult(r *http.Request) (*Result, error)
+}
+
+// NewProvider returns the provider configured by name, or nil when identity checks are disabled.
+// No vendor is bundled yet; add one here when a contract is in place.
+func NewProvider(name string) Provider {
+	switch name {
+	case "", "none":
+		return nil
+	case "mock":
+		return NewMock()
+	}
+	log.Printf("[KYC] unknown provider %q, identity checks disabled", name)
+	return nil
+}
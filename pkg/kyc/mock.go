@@ -0,0 +1,50 @@
+package kyc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync/atomic"
+)
+
+// Mock accepts every check as pending and leaves the result to the webhook.
+// It is meant for development only: its webhook is not authenticated.
+type Mock struct {
+	submitted atomic.Int64
+}
+
+// NewMock creates a mock provider
+func NewMock() *Mock {
+	return &Mock{}
+}
+
+// Name identifies the provider
+func (m *Mock) Name() string { return "mock" }
+
+// Verify logs the check and returns a pending result with a sequential reference
+func (m *Mock) Verify(_ context.Context, check *Check) (*Result, error) {
+	log.Printf("[KYC] mock check for applicant %s", check.ApplicantID)
+	return &Result{Reference: fmt.Sprintf("mock-%d", m.submitted.Add(1)), Status: StatusPending}, nil
+}
+
+// ParseResult reads a JSON result of the form {"reference", "status", "score", "reason"},
+// so the webhook can be exercised by hand in development
+func (m *Mock) ParseResult(r *http.Request) (*Result, error) {
+	var report struct {
+		Reference string   `json:"reference"`
+		Status    string   `json:"status"`
+		Score     *float64 `json:"score"`
+		Reason    string   `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil || report.Reference == "" {
+		return nil, ErrInvalidWebhook
+	}
+	switch report.Status {
+	case StatusVerified, StatusRejected:
+	default:
+		return nil, ErrInvalidWebhook
+	}
+	return &Result{Reference: report.Reference, Status: report.Status, Score: report.Score, Reason: report.Reason}, nil
+}
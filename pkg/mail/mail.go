@@ -0,0 +1,54 @@
+// Package mail sends email through interchangeable providers: SMTP, AWS SES
+// and SendGrid. SES and SendGrid also report bounces and complaints back
+// through a webhook.
+package mail
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Provider names
+const (
+	ProviderSMTP     = "smtp"
+	ProviderSES      = "ses"
+	ProviderSendGrid = "sendgrid"
+)
+
+// Feedback event types
+const (
+	EventBounce    = "bounce"
+	EventComplaint = "complaint"
+)
+
+// ErrInvalidWebhook is returned for feedback webhooks that fail authentication or cannot be parsed
+var ErrInvalidWebhook = errors.New("invalid email webhook")
+
+// Message is an HTML email to a single recipient
+type Message struct {
+	From     string
+	FromName string
+	To       string
+	Subject  string
+	HTML     string
+}
+
+// Provider sends a message and returns the provider's message id, if it assigns one
+type Provider interface {
+	Name() string
+	Send(ctx context.Context, msg *Message) (string, error)
+}
+
+// Event is a permanent delivery problem reported for an address. Temporary
+// bounces are not reported, since the provider retries those itself.
+type Event struct {
+	Type   string
+	Email  string
+	Reason string
+}
+
+// EventParser is implemented by providers that post bounces and complaints to a webhook
+type EventParser interface {
+	ParseEvents(r *http.Request) ([]Event, error)
+}
@@ -0,0 +1,115 @@
+package mail
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSendGridSend(t *testing.T) {
+	var got sendGridRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("X-Message-Id", "abc123")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sg, err := NewSendGrid("key", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sg.endpoint = server.URL
+
+	id, err := sg.Send(context.Background(), &Message{From: "yandas@example.com", FromName: "YANDAŞ", To: "ayse@example.com", Subject: "Merhaba", HTML: "<p>Merhaba</p>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "abc123" {
+		t.Errorf("unexpected message id %q", id)
+	}
+	if got.Personalizations[0].To[0].Email != "ayse@example.com" || got.From.Name != "YANDAŞ" || got.Content[0].Type != "text/html" {
+		t.Errorf("unexpected request %+v", got)
+	}
+}
+
+func TestSendGridParseEvents(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	sg, err := NewSendGrid("key", base64.StdEncoding.EncodeToString(der))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := `[{"email":"a@example.com","event":"bounce","type":"bounce","reason":"550 no such user"},` +
+		`{"email":"b@example.com","event":"bounce","type":"blocked"},` +
+		`{"email":"c@example.com","event":"spamreport"},` +
+		`{"email":"d@example.com","event":"delivered"}]`
+	request := func(payload string) *http.Request {
+		digest := sha256.Sum256([]byte("1700000000" + payload))
+		signature, _ := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/email/events/sendgrid", strings.NewReader(body))
+		r.Header.Set("X-Twilio-Email-Event-Webhook-Timestamp", "1700000000")
+		r.Header.Set("X-Twilio-Email-Event-Webhook-Signature", base64.StdEncoding.EncodeToString(signature))
+		return r
+	}
+
+	events, err := sg.ParseEvents(request(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Event{
+		{Type: EventBounce, Email: "a@example.com", Reason: "550 no such user"},
+		{Type: EventComplaint, Email: "c@example.com", Reason: "spamreport"},
+	}
+	if len(events) != len(want) || events[0] != want[0] || events[1] != want[1] {
+		t.Errorf("expected %+v, got %+v", want, events)
+	}
+
+	// Signed over a different body
+	if _, err := sg.ParseEvents(request("[]")); err != ErrInvalidWebhook {
+		t.Errorf("expected ErrInvalidWebhook, got %v", err)
+	}
+}
+
+func TestSESParseEvents(t *testing.T) {
+	ses := &SES{webhookToken: "secret"}
+	message, _ := json.Marshal(map[string]interface{}{
+		"notificationType": "Bounce",
+		"bounce": map[string]interface{}{
+			"bounceType":        "Permanent",
+			"bounceSubType":     "General",
+			"bouncedRecipients": []map[string]string{{"emailAddress": "a@example.com"}},
+		},
+	})
+	envelope, _ := json.Marshal(snsEnvelope{Type: "Notification", Message: string(message)})
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/email/events/ses?token=secret", strings.NewReader(string(envelope)))
+	events, err := ses.ParseEvents(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0] != (Event{Type: EventBounce, Email: "a@example.com", Reason: "General"}) {
+		t.Errorf("unexpected events %+v", events)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/api/v1/email/events/ses?token=wrong", strings.NewReader(string(envelope)))
+	if _, err := ses.ParseEvents(r); err != ErrInvalidWebhook {
+		t.Errorf("expected ErrInvalidWebhook, got %v", err)
+	}
+}
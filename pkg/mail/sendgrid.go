@@ -0,0 +1,148 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const sendGridSendURL = "https://api.sendgrid.com/v3/mail/send"
+
+// SendGrid sends messages through the SendGrid v3 API. Bounces and spam
+// reports arrive through the signed Event Webhook.
+type SendGrid struct {
+	apiKey     string
+	webhookKey *ecdsa.PublicKey
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewSendGrid creates a SendGrid provider. webhookKey is the Event Webhook's
+// base64 verification key; without it webhook events are rejected.
+func NewSendGrid(apiKey, webhookKey string) (*SendGrid, error) {
+	sg := &SendGrid{
+		apiKey:     apiKey,
+		endpoint:   sendGridSendURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	if webhookKey != "" {
+		der, err := base64.StdEncoding.DecodeString(webhookKey)
+		if err != nil {
+			return nil, fmt.Errorf("sendgrid: invalid webhook verification key: %w", err)
+		}
+		key, err := x509.ParsePKIXPublicKey(der)
+		if err != nil {
+			return nil, fmt.Errorf("sendgrid: invalid webhook verification key: %w", err)
+		}
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("sendgrid: webhook verification key is not an ECDSA key")
+		}
+		sg.webhookKey = ecKey
+	}
+	return sg, nil
+}
+
+// Name identifies the provider
+func (sg *SendGrid) Name() string { return ProviderSendGrid }
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridRequest struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+}
+
+// Send delivers a message and returns the SendGrid message id
+func (sg *SendGrid) Send(ctx context.Context, msg *Message) (string, error) {
+	body, err := json.Marshal(sendGridRequest{
+		Personalizations: []sendGridPersonalization{{To: []sendGridAddress{{Email: msg.To}}}},
+		From:             sendGridAddress{Email: msg.From, Name: msg.FromName},
+		Subject:          msg.Subject,
+		Content:          []sendGridContent{{Type: "text/html", Value: msg.HTML}},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sg.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+sg.apiKey)
+
+	resp, err := sg.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("sendgrid: status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return resp.Header.Get("X-Message-Id"), nil
+}
+
+type sendGridEvent struct {
+	Email  string `json:"email"`
+	Event  string `json:"event"`
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// ParseEvents reads a signed Event Webhook batch. Blocked messages are
+// temporary bounces and yield no event.
+func (sg *SendGrid) ParseEvents(r *http.Request) ([]Event, error) {
+	if sg.webhookKey == nil {
+		return nil, ErrInvalidWebhook
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, ErrInvalidWebhook
+	}
+	signature, err := base64.StdEncoding.DecodeString(r.Header.Get("X-Twilio-Email-Event-Webhook-Signature"))
+	if err != nil {
+		return nil, ErrInvalidWebhook
+	}
+	digest := sha256.Sum256(append([]byte(r.Header.Get("X-Twilio-Email-Event-Webhook-Timestamp")), body...))
+	if !ecdsa.VerifyASN1(sg.webhookKey, digest[:], signature) {
+		return nil, ErrInvalidWebhook
+	}
+
+	var batch []sendGridEvent
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil, ErrInvalidWebhook
+	}
+	var events []Event
+	for _, e := range batch {
+		switch {
+		case e.Event == "bounce" && e.Type != "blocked":
+			events = append(events, Event{Type: EventBounce, Email: e.Email, Reason: e.Reason})
+		case e.Event == "spamreport":
+			events = append(events, Event{Type: EventComplaint, Email: e.Email, Reason: "spamreport"})
+		}
+	}
+	return events, nil
+}
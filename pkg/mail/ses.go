@@ -0,0 +1,156 @@
+package mail
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	netmail "net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// SES sends messages through Amazon SES. Credentials come from the standard
+// AWS chain (environment, shared config or instance role). Bounces and
+// complaints arrive through an SNS topic subscribed to the webhook URL, which
+// must carry the webhook token as its token query parameter.
+type SES struct {
+	client       *sesv2.Client
+	webhookToken string
+	httpClient   *http.Client
+}
+
+// NewSES creates an SES provider for a region
+func NewSES(ctx context.Context, region, webhookToken string) (*SES, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, err
+	}
+	return &SES{
+		client:       sesv2.NewFromConfig(cfg),
+		webhookToken: webhookToken,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name identifies the provider
+func (s *SES) Name() string { return ProviderSES }
+
+// Send delivers a message and returns the SES message id
+func (s *SES) Send(ctx context.Context, msg *Message) (string, error) {
+	from := netmail.Address{Name: msg.FromName, Address: msg.From}
+	out, err := s.client.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(from.String()),
+		Destination:      &types.Destination{ToAddresses: []string{msg.To}},
+		Content: &types.EmailContent{
+			Simple: &types.Message{
+				Subject: &types.Content{Data: aws.String(msg.Subject), Charset: aws.String("UTF-8")},
+				Body: &types.Body{
+					Html: &types.Content{Data: aws.String(msg.HTML), Charset: aws.String("UTF-8")},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.MessageId), nil
+}
+
+type snsEnvelope struct {
+	Type         string `json:"Type"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+type sesRecipient struct {
+	EmailAddress   string `json:"emailAddress"`
+	DiagnosticCode string `json:"diagnosticCode"`
+}
+
+// sesNotification covers both identity notifications (notificationType) and
+// configuration set events (eventType)
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Bounce           *struct {
+		BounceType        string         `json:"bounceType"`
+		BounceSubType     string         `json:"bounceSubType"`
+		BouncedRecipients []sesRecipient `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint *struct {
+		ComplaintFeedbackType string         `json:"complaintFeedbackType"`
+		ComplainedRecipients  []sesRecipient `json:"complainedRecipients"`
+	} `json:"complaint"`
+}
+
+// ParseEvents reads an SNS delivery of SES feedback. Subscription confirmations
+// are confirmed on the spot and yield no events.
+func (s *SES) ParseEvents(r *http.Request) ([]Event, error) {
+	token := r.URL.Query().Get("token")
+	if s.webhookToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.webhookToken)) != 1 {
+		return nil, ErrInvalidWebhook
+	}
+
+	var envelope snsEnvelope
+	if err := json.NewDecoder(r.Body).Decode(&envelope); err != nil {
+		return nil, ErrInvalidWebhook
+	}
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		return nil, s.confirmSubscription(r.Context(), envelope.SubscribeURL)
+	case "Notification":
+	default:
+		return nil, nil
+	}
+
+	var notification sesNotification
+	if err := json.Unmarshal([]byte(envelope.Message), &notification); err != nil {
+		return nil, ErrInvalidWebhook
+	}
+
+	var events []Event
+	switch {
+	case notification.Bounce != nil && notification.Bounce.BounceType == "Permanent":
+		for _, recipient := range notification.Bounce.BouncedRecipients {
+			reason := notification.Bounce.BounceSubType
+			if recipient.DiagnosticCode != "" {
+				reason = recipient.DiagnosticCode
+			}
+			events = append(events, Event{Type: EventBounce, Email: recipient.EmailAddress, Reason: reason})
+		}
+	case notification.Complaint != nil:
+		for _, recipient := range notification.Complaint.ComplainedRecipients {
+			events = append(events, Event{Type: EventComplaint, Email: recipient.EmailAddress, Reason: notification.Complaint.ComplaintFeedbackType})
+		}
+	}
+	return events, nil
+}
+
+// confirmSubscription visits the SubscribeURL of a new SNS subscription, which must point at AWS
+func (s *SES) confirmSubscription(ctx context.Context, subscribeURL string) error {
+	u, err := url.Parse(subscribeURL)
+	if err != nil || u.Scheme != "https" || !strings.HasSuffix(u.Hostname(), ".amazonaws.com") {
+		return ErrInvalidWebhook
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ses: subscription confirmation returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net/smtp"
+)
+
+// SMTP sends messages through an SMTP server, over direct TLS or STARTTLS
+type SMTP struct {
+	host     string
+	port     int
+	user     string
+	password string
+}
+
+// NewSMTP creates an SMTP provider
+func NewSMTP(host string, port int, user, password string) *SMTP {
+	return &SMTP{host: host, port: port, user: user, password: password}
+}
+
+// Name identifies the provider
+func (s *SMTP) Name() string { return ProviderSMTP }
+
+// Send delivers a message. SMTP assigns no message id, so it is always empty.
+func (s *SMTP) Send(_ context.Context, msg *Message) (string, error) {
+	headers := make(map[string]string)
+	headers["From"] = fmt.Sprintf("%s <%s>", msg.FromName, msg.From)
+	headers["To"] = msg.To
+	headers["Subject"] = msg.Subject
+	headers["MIME-Version"] = "1.0"
+	headers["Content-Type"] = "text/html; charset=UTF-8"
+
+	data := ""
+	for k, v := range headers {
+		data += fmt.Sprintf("%s: %s\r\n", k, v)
+	}
+	data += "\r\n" + msg.HTML
+
+	addr := fmt.Sprintf("%s:%d", s.host, s.port)
+
+	auth := smtp.PlainAuth("", s.user, s.password, s.host)
+
+	// TLS config - InsecureSkipVerify needed if cert hostname doesn't match SMTP host
+	tlsConfig := &tls.Config{
+		ServerName:         s.host,
+		InsecureSkipVerify: true,
+	}
+
+	// Connect to SMTP server
+	conn, err := tls.Dial("tcp", addr, tlsConfig)
+	if err != nil {
+		// Try STARTTLS if direct TLS fails
+		log.Printf("Direct TLS failed, trying STARTTLS: %v", err)
+		c, dialErr := smtp.Dial(addr)
+		if dialErr != nil {
+			return "", fmt.Errorf("SMTP dial error: %w", dialErr)
+		}
+		defer c.Close()
+		if err := c.StartTLS(tlsConfig); err != nil {
+			log.Printf("STARTTLS failed, sending plain: %v", err)
+		}
+		return "", s.deliver(c, auth, msg.From, msg.To, data)
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		return "", fmt.Errorf("SMTP client error: %w", err)
+	}
+	defer client.Close()
+
+	return "", s.deliver(client, auth, msg.From, msg.To, data)
+}
+
+func (s *SMTP) deliver(c *smtp.Client, auth smtp.Auth, from, to, data string) error {
+	if err := c.Auth(auth); err != nil {
+		return fmt.Errorf("SMTP auth error: %w", err)
+	}
+	if err := c.Mail(from); err != nil {
+		return fmt.Errorf("SMTP mail error: %w", err)
+	}
+	if err := c.Rcpt(to); err != nil {
+		return fmt.Errorf("SMTP rcpt error: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP data error: %w", err)
+	}
+	if _, err := w.Write([]byte(data)); err != nil {
+		return fmt.Errorf("SMTP write error: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP close error: %w", err)
+	}
+	c.Quit()
+	return nil
+}
@@ -0,0 +1,51 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// FFmpeg runs the local ffmpeg CLI
+type FFmpeg struct {
+	binary string
+}
+
+// NewFFmpeg creates a transcoder for the ffmpeg binary at path (looked up in PATH if relative)
+func NewFFmpeg(path string) *FFmpeg {
+	if path == "" {
+		path = "ffmpeg"
+	}
+	return &FFmpeg{binary: path}
+}
+
+func (f *FFmpeg) Name() string {
+	return "ffmpeg"
+}
+
+func (f *FFmpeg) Ext() string {
+	return ".m4a"
+}
+
+// TranscodeAudio re-encodes src as mono 64 kbps AAC in dst, cut at MaxAudioDuration
+// so a client misreporting the duration cannot store a longer note
+func (f *FFmpeg) TranscodeAudio(ctx context.Context, src, dst string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, f.binary,
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-i", src,
+		"-vn", "-ac", "1", "-c:a", "aac", "-b:a", "64k",
+		"-t", strconv.Itoa(int(MaxAudioDuration.Seconds())),
+		"-movflags", "+faststart",
+		dst,
+	)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
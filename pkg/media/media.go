@@ -0,0 +1,58 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Limits on voice notes, checked before anything is written to storage
+const (
+	MaxAudioBytes    = 5 << 20
+	MaxAudioDuration = 5 * time.Minute
+)
+
+var (
+	ErrUnsupportedAudio = errors.New("unsupported audio format")
+	ErrAudioTooLarge    = errors.New("audio file is too large")
+	ErrAudioDuration    = errors.New("audio duration must be between 1 second and 5 minutes")
+)
+
+// audioFormats are the containers accepted from the apps; m4a is what both record by default
+var audioFormats = map[string]bool{
+	".m4a": true, ".aac": true, ".mp3": true, ".ogg": true, ".opus": true, ".webm": true, ".wav": true,
+}
+
+// ValidateAudio checks an uploaded voice note's file name, size and the duration reported by the client
+func ValidateAudio(filename string, size int64, duration time.Duration) error {
+	if !audioFormats[strings.ToLower(filepath.Ext(filename))] {
+		return ErrUnsupportedAudio
+	}
+	if size <= 0 || size > MaxAudioBytes {
+		return ErrAudioTooLarge
+	}
+	if duration < time.Second || duration > MaxAudioDuration {
+		return ErrAudioDuration
+	}
+	return nil
+}
+
+// Transcoder converts uploaded audio into the format served for playback
+type Transcoder interface {
+	Name() string
+	// Ext is the extension of the files TranscodeAudio writes
+	Ext() string
+	TranscodeAudio(ctx context.Context, src, dst string) error
+}
+
+// NewTranscoder returns the transcoder configured by name, or nil when uploads are stored as-is
+func NewTranscoder(name, ffmpegPath string) Transcoder {
+	switch name {
+	case "ffmpeg":
+		return NewFFmpeg(ffmpegPath)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,41 @@
+package media
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateAudio(t *testing.T) {
+	cases := []struct {
+		name     string
+		filename string
+		size     int64
+		duration time.Duration
+		want     error
+	}{
+		{"m4a", "note.m4a", 120 << 10, 12 * time.Second, nil},
+		{"upper case extension", "NOTE.OGG", 1 << 10, time.Second, nil},
+		{"image", "photo.jpg", 1 << 10, 5 * time.Second, ErrUnsupportedAudio},
+		{"no extension", "note", 1 << 10, 5 * time.Second, ErrUnsupportedAudio},
+		{"empty", "note.mp3", 0, 5 * time.Second, ErrAudioTooLarge},
+		{"too large", "note.mp3", MaxAudioBytes + 1, 5 * time.Second, ErrAudioTooLarge},
+		{"too short", "note.wav", 1 << 10, 500 * time.Millisecond, ErrAudioDuration},
+		{"too long", "note.wav", 1 << 10, MaxAudioDuration + time.Second, ErrAudioDuration},
+	}
+	for _, tc := range cases {
+		if err := ValidateAudio(tc.filename, tc.size, tc.duration); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestNewTranscoder(t *testing.T) {
+	if NewTranscoder("none", "") != nil {
+		t.Error("expected no transcoder when disabled")
+	}
+	tc := NewTranscoder("ffmpeg", "")
+	if tc == nil || tc.Name() != "ffmpeg" || tc.Ext() != ".m4a" {
+		t.Errorf("unexpected transcoder %#v", tc)
+	}
+}
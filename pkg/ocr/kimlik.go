@@ -0,0 +1,136 @@
+package ocr
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// KimlikFields are the fields read from the front of a Turkish ID card
+type KimlikFields struct {
+	IDNumber   string
+	GivenNames string
+	Surname    string
+}
+
+// FullName returns the given names followed by the surname
+func (k KimlikFields) FullName() string {
+	return strings.TrimSpace(k.GivenNames + " " + k.Surname)
+}
+
+var idNumberPattern = regexp.MustCompile(`\b[1-9][0-9]{10}\b`)
+
+// ParseKimlik extracts the ID number and name from OCR text of a kimlik front side.
+// The card prints each label ("Soyadı / Surname", "Adı / Given Name(s)") on the line above its value.
+func ParseKimlik(text string) KimlikFields {
+	var fields KimlikFields
+
+	for _, candidate := range idNumberPattern.FindAllString(text, -1) {
+		if ValidTCKN(candidate) {
+			fields.IDNumber = candidate
+			break
+		}
+	}
+
+	lines := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	for i := 0; i < len(lines)-1; i++ {
+		label := map[string]bool{}
+		for _, token := range strings.Fields(foldTurkish(lines[i])) {
+			label[token] = true
+		}
+		switch {
+		case label["soyadi"] || label["surname"]:
+			if fields.Surname == "" {
+				fields.Surname = lines[i+1]
+			}
+		case label["adi"] || label["given"]:
+			if fields.GivenNames == "" {
+				fields.GivenNames = lines[i+1]
+			}
+		}
+	}
+
+	return fields
+}
+
+// ValidTCKN checks the T.C. Kimlik No checksum digits
+func ValidTCKN(id string) bool {
+	if len(id) != 11 || id[0] == '0' {
+		return false
+	}
+
+	digits := make([]int, 11)
+	for i, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+		digits[i] = int(r - '0')
+	}
+
+	odd := digits[0] + digits[2] + digits[4] + digits[6] + digits[8]
+	even := digits[1] + digits[3] + digits[5] + digits[7]
+	if ((odd*7-even)%10+10)%10 != digits[9] {
+		return false
+	}
+
+	sum := 0
+	for _, d := range digits[:10] {
+		sum += d
+	}
+	return sum%10 == digits[10]
+}
+
+// NamesMatch reports whether an extracted name plausibly belongs to the account holder:
+// the surname must match and at least one given name must appear in the account name.
+func NamesMatch(accountName string, fields KimlikFields) bool {
+	account := map[string]bool{}
+	for _, token := range strings.Fields(foldTurkish(accountName)) {
+		account[token] = true
+	}
+
+	surname := strings.Fields(foldTurkish(fields.Surname))
+	given := strings.Fields(foldTurkish(fields.GivenNames))
+	if len(surname) == 0 || len(given) == 0 {
+		return false
+	}
+
+	for _, token := range surname {
+		if !account[token] {
+			return false
+		}
+	}
+	for _, token := range given {
+		if account[token] {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskIDNumber keeps only the last four digits
+func MaskIDNumber(id string) string {
+	if len(id) <= 4 {
+		return id
+	}
+	return strings.Repeat("*", len(id)-4) + id[len(id)-4:]
+}
+
+var turkishFold = strings.NewReplacer("ç", "c", "ğ", "g", "ı", "i", "ö", "o", "ş", "s", "ü", "u", "̇", "")
+
+// foldTurkish lowercases with Turkish casing rules and strips diacritics and punctuation
+func foldTurkish(s string) string {
+	s = strings.ToLowerSpecial(unicode.TurkishCase, s)
+	s = turkishFold.Replace(s)
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) {
+			return r
+		}
+		return ' '
+	}, s)
+}
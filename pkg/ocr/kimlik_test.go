@@ -0,0 +1,56 @@
+package ocr
+
+import "testing"
+
+const sampleKimlik = `TÜRKİYE CUMHURİYETİ KİMLİK KARTI
+REPUBLIC OF TURKEY IDENTITY CARD
+T.C. Kimlik No / TR Identity No
+10000000146
+Soyadı / Surname
+YILDIRIM
+Adı / Given Name(s)
+AYŞE İPEK
+Doğum Tarihi / Date of Birth
+01.01.1990`
+
+func TestParseKimlik(t *testing.T) {
+	fields := ParseKimlik(sampleKimlik)
+
+	if fields.IDNumber != "10000000146" {
+		t.Errorf("IDNumber = %q", fields.IDNumber)
+	}
+	if fields.Surname != "YILDIRIM" || fields.GivenNames != "AYŞE İPEK" {
+		t.Errorf("unexpected name %q %q", fields.GivenNames, fields.Surname)
+	}
+}
+
+func TestValidTCKN(t *testing.T) {
+	cases := map[string]bool{
+		"10000000146": true,
+		"10000000147": false,
+		"01234567890": false,
+		"1234567890":  false,
+	}
+	for id, want := range cases {
+		if got := ValidTCKN(id); got != want {
+			t.Errorf("ValidTCKN(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestNamesMatch(t *testing.T) {
+	fields := KimlikFields{GivenNames: "AYŞE İPEK", Surname: "YILDIRIM"}
+
+	cases := map[string]bool{
+		"Ayşe Yıldırım":      true,
+		"ayse ipek yildirim": true,
+		"İpek Yıldırım":      true,
+		"Ayşe Kaya":          false,
+		"Mehmet Yıldırım":    false,
+	}
+	for name, want := range cases {
+		if got := NamesMatch(name, fields); got != want {
+			t.Errorf("NamesMatch(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package ocr
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnsupportedFile is returned when a provider cannot read the given file type
+var ErrUnsupportedFile = errors.New("unsupported file type")
+
+// Provider extracts raw text from a document image
+type Provider interface {
+	Name() string
+	ExtractText(ctx context.Context, imagePath string) (string, error)
+}
+
+// NewProvider returns the provider configured by name, or nil when OCR is disabled
+func NewProvider(name, tesseractPath, tesseractLang string) Provider {
+	switch name {
+	case "tesseract":
+		return NewTesseract(tesseractPath, tesseractLang)
+	default:
+		return nil
+	}
+}
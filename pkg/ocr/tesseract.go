@@ -0,0 +1,51 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Tesseract runs the local tesseract CLI
+type Tesseract struct {
+	binary string
+	lang   string
+}
+
+// NewTesseract creates a provider for the tesseract binary at path (looked up in PATH if relative)
+func NewTesseract(path, lang string) *Tesseract {
+	if path == "" {
+		path = "tesseract"
+	}
+	if lang == "" {
+		lang = "tur"
+	}
+	return &Tesseract{binary: path, lang: lang}
+}
+
+func (t *Tesseract) Name() string {
+	return "tesseract"
+}
+
+// ExtractText returns the text tesseract recognises in an image file
+func (t *Tesseract) ExtractText(ctx context.Context, imagePath string) (string, error) {
+	switch strings.ToLower(filepath.Ext(imagePath)) {
+	case ".jpg", ".jpeg", ".png", ".tif", ".tiff", ".bmp", ".webp":
+	default:
+		return "", ErrUnsupportedFile
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.binary, imagePath, "stdout", "-l", t.lang)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tesseract: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,176 @@
+// Package pdf writes simple text-and-line PDF documents using the built-in
+// Helvetica fonts, so no font files need to ship with the binary. Text is
+// encoded as Windows-1254 so Turkish characters render correctly.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in points
+const (
+	PageWidth  = 595.0
+	PageHeight = 842.0
+)
+
+// turkishEncoding maps the Windows-1254 code points that differ from WinAnsi to Turkish glyphs
+const turkishEncoding = "<< /Type /Encoding /BaseEncoding /WinAnsiEncoding " +
+	"/Differences [208 /Gbreve 221 /Idotaccent 222 /Scedilla 240 /gbreve 253 /dotlessi 254 /scedilla] >>"
+
+// Document is an in-memory PDF made of pages
+type Document struct {
+	pages []*Page
+}
+
+// Page collects drawing operations; coordinates are points from the top-left corner
+type Page struct {
+	content bytes.Buffer
+}
+
+// New creates an empty document
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage appends a blank A4 page
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Text draws s with its baseline at (x, y)
+func (p *Page) Text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, PageHeight-y, escape(encode(s)))
+}
+
+// TextRight draws s so that it ends at x
+func (p *Page) TextRight(x, y, size float64, bold bool, s string) {
+	p.Text(x-TextWidth(s, size), y, size, bold, s)
+}
+
+// Line draws a thin line between two points
+func (p *Page) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// TextWidth approximates the rendered width of s in Helvetica at the given size
+func TextWidth(s string, size float64) float64 {
+	var units int
+	for _, b := range encode(s) {
+		if b >= 32 && b <= 126 {
+			units += helveticaWidths[b-32]
+		} else {
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// WriteTo serializes the document
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	// Objects 1-4 are fixed; each page then takes a page object and a content stream
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding " + turkishEncoding + " >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding " + turkishEncoding + " >>")
+
+	for i, p := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, 6+i*2))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+// Bytes renders the document into memory
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	d.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// encode converts s to Windows-1254; characters outside it become '?'
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80:
+			out = append(out, byte(r))
+		case r == 'Ğ':
+			out = append(out, 0xD0)
+		case r == 'İ':
+			out = append(out, 0xDD)
+		case r == 'Ş':
+			out = append(out, 0xDE)
+		case r == 'ğ':
+			out = append(out, 0xF0)
+		case r == 'ı':
+			out = append(out, 0xFD)
+		case r == 'ş':
+			out = append(out, 0xFE)
+		case r == '€':
+			out = append(out, 0x80)
+		case r >= 0xA0 && r <= 0xFF && !strings.ContainsRune("ÐÝÞðýþ", r):
+			out = append(out, byte(r))
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+func escape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch c {
+		case '(', ')', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\n', '\r':
+			sb.WriteByte(' ')
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// helveticaWidths are glyph widths for ASCII 32-126 from the Helvetica AFM
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
@@ -0,0 +1,35 @@
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEncodeTurkish(t *testing.T) {
+	got := encode("Işık ğüş İÇ")
+	want := []byte{'I', 0xFE, 0xFD, 'k', ' ', 0xF0, 0xFC, 0xFE, ' ', 0xDD, 0xC7}
+	if !bytes.Equal(got, want) {
+		t.Errorf("encode = % x, want % x", got, want)
+	}
+}
+
+func TestDocumentStructure(t *testing.T) {
+	doc := New()
+	page := doc.AddPage()
+	page.Text(40, 60, 12, true, "Makbuz (test)")
+	page.Line(40, 70, 555, 70)
+
+	out := string(doc.Bytes())
+	for _, want := range []string{"%PDF-1.4", "/Count 1", `(Makbuz \(test\)) Tj`, "xref\n0 7\n", "%%EOF"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	if w := TextWidth("100", 10); w != 16.68 {
+		t.Errorf("TextWidth = %v", w)
+	}
+}
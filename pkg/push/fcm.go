@@ -0,0 +1,104 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const fcmSendURL = "https://fcm.googleapis.com/fcm/send"
+
+// FCM sends messages through the Firebase Cloud Messaging HTTP API with a server key
+type FCM struct {
+	serverKey  string
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewFCM creates an FCM sender
+func NewFCM(serverKey string) *FCM {
+	return &FCM{
+		serverKey:  serverKey,
+		endpoint:   fcmSendURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmRequest struct {
+	To               string            `json:"to"`
+	Priority         string            `json:"priority,omitempty"`
+	TimeToLive       *int              `json:"time_to_live,omitempty"`
+	ContentAvailable bool              `json:"content_available,omitempty"`
+	Notification     *fcmNotification  `json:"notification,omitempty"`
+	Data             map[string]string `json:"data,omitempty"`
+}
+
+type fcmResponse struct {
+	Failure int `json:"failure"`
+	Results []struct {
+		MessageID string `json:"message_id"`
+		Error     string `json:"error"`
+	} `json:"results"`
+}
+
+// Send delivers a message to one device
+func (f *FCM) Send(ctx context.Context, msg *Message) (string, error) {
+	req := fcmRequest{To: msg.Token, Data: msg.Data}
+	if msg.Title != "" || msg.Body != "" {
+		req.Notification = &fcmNotification{Title: msg.Title, Body: msg.Body}
+	} else {
+		// Wake the iOS app for data-only messages
+		req.ContentAvailable = true
+	}
+	if msg.HighPriority {
+		req.Priority = "high"
+	}
+	if msg.TTL > 0 {
+		ttl := int(msg.TTL.Seconds())
+		req.TimeToLive = &ttl
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, f.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "key="+f.serverKey)
+
+	resp, err := f.httpClient.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fcm: unexpected status %d", resp.StatusCode)
+	}
+
+	var result fcmResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("fcm: %w", err)
+	}
+	if len(result.Results) == 0 {
+		return "", fmt.Errorf("fcm: empty response")
+	}
+	switch sent := result.Results[0]; sent.Error {
+	case "":
+		return sent.MessageID, nil
+	case "NotRegistered", "InvalidRegistration":
+		return "", ErrInvalidToken
+	default:
+		return "", fmt.Errorf("fcm: %s", sent.Error)
+	}
+}
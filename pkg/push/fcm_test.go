@@ -0,0 +1,58 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFCMSendDataMessage(t *testing.T) {
+	var got fcmRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "key=secret" {
+			t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"success":1,"failure":0,"results":[{"message_id":"0:123"}]}`))
+	}))
+	defer server.Close()
+
+	fcm := NewFCM("secret")
+	fcm.endpoint = server.URL
+
+	id, err := fcm.Send(context.Background(), &Message{
+		Token:        "device",
+		Data:         map[string]string{"type": "incoming_call"},
+		HighPriority: true,
+		TTL:          45 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "0:123" {
+		t.Errorf("unexpected message id %q", id)
+	}
+	if got.To != "device" || got.Priority != "high" || got.Notification != nil || !got.ContentAvailable {
+		t.Errorf("unexpected request %+v", got)
+	}
+	if got.TimeToLive == nil || *got.TimeToLive != 45 || got.Data["type"] != "incoming_call" {
+		t.Errorf("unexpected ttl or data in %+v", got)
+	}
+}
+
+func TestFCMSendUnregisteredToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success":0,"failure":1,"results":[{"error":"NotRegistered"}]}`))
+	}))
+	defer server.Close()
+
+	fcm := NewFCM("secret")
+	fcm.endpoint = server.URL
+
+	if _, err := fcm.Send(context.Background(), &Message{Token: "stale", Title: "Merhaba", Body: "Test"}); err != ErrInvalidToken {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+}
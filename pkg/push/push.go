@@ -0,0 +1,54 @@
+// Package push delivers push notifications to devices. FCM covers Android, web
+// and regular iOS notifications; incoming calls on iOS need PushKit VoIP pushes,
+// which go through a separate VoIPSender.
+package push
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// ErrInvalidToken is returned when the device token is no longer registered and should be dropped
+var ErrInvalidToken = errors.New("device token is not registered")
+
+// Message is a push to a single device. Without Title and Body it is sent as a
+// data-only message that the app handles itself, e.g. to ring for a call.
+type Message struct {
+	Token        string
+	Title        string
+	Body         string
+	Data         map[string]string
+	HighPriority bool
+	TTL          time.Duration // zero keeps the provider default
+}
+
+// Sender delivers messages and returns the provider's message id
+type Sender interface {
+	Send(ctx context.Context, msg *Message) (string, error)
+}
+
+// NewSender returns an FCM sender, or nil when no server key is configured
+func NewSender(fcmServerKey string) Sender {
+	if fcmServerKey == "" {
+		return nil
+	}
+	return NewFCM(fcmServerKey)
+}
+
+// VoIPSender delivers PushKit VoIP pushes to iOS devices for incoming calls
+type VoIPSender interface {
+	SendVoIP(ctx context.Context, token string, payload map[string]string, ttl time.Duration) error
+}
+
+// NewVoIPSender returns the VoIP sender configured by name, or nil when VoIP pushes are disabled.
+// No APNs client is bundled yet; add one here once the VoIP certificate is issued.
+func NewVoIPSender(name string) VoIPSender {
+	switch name {
+	case "", "none":
+		return nil
+	}
+	log.Printf("[PUSH] unknown VoIP provider %q, VoIP pushes disabled", name)
+	return nil
+}
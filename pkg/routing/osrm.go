@@ -0,0 +1,69 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OSRM asks an OSRM server (self-hosted or router.project-osrm.org) for
+// driving routes
+type OSRM struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewOSRM creates an OSRM provider for the server at baseURL
+func NewOSRM(baseURL string) *OSRM {
+	return &OSRM{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Name identifies the provider
+func (o *OSRM) Name() string { return "osrm" }
+
+// Route returns the fastest driving route between the points
+func (o *OSRM) Route(ctx context.Context, from, to Point) (*Route, error) {
+	// OSRM takes coordinates as longitude,latitude
+	url := fmt.Sprintf("%s/route/v1/driving/%f,%f;%f,%f?overview=false", o.baseURL, from.Lng, from.Lat, to.Lng, to.Lat)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Routes  []struct {
+			Distance float64 `json:"distance"` // metres
+			Duration float64 `json:"duration"` // seconds
+		} `json:"routes"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, fmt.Errorf("osrm: unexpected %d response", resp.StatusCode)
+	}
+	if body.Code != "Ok" || len(body.Routes) == 0 {
+		return nil, fmt.Errorf("osrm: %s %s", body.Code, body.Message)
+	}
+
+	return &Route{
+		DistanceKm: body.Routes[0].Distance / 1000,
+		Duration:   time.Duration(body.Routes[0].Duration * float64(time.Second)),
+	}, nil
+}
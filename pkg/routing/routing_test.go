@@ -0,0 +1,57 @@
+package routing
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var (
+	taksim  = Point{Lat: 41.0370, Lng: 28.9850}
+	kadikoy = Point{Lat: 40.9900, Lng: 29.0290}
+)
+
+func TestStraightLine(t *testing.T) {
+	route, err := NewStraightLine().Route(context.Background(), taksim, kadikoy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(route.DistanceKm-6.4*detourFactor) > 1 {
+		t.Errorf("unexpected distance %.2f km", route.DistanceKm)
+	}
+	if route.Duration < 10*time.Minute || route.Duration > 25*time.Minute {
+		t.Errorf("unexpected duration %s", route.Duration)
+	}
+}
+
+func TestOSRMFallsBack(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/route/v1/driving/28.985000,41.037000;29.029000,40.990000" {
+			w.Write([]byte(`{"code":"NoRoute","message":"Impossible route"}`))
+			return
+		}
+		w.Write([]byte(`{"code":"Ok","routes":[{"distance":9200,"duration":1260}]}`))
+	}))
+	defer srv.Close()
+
+	provider := NewProvider("osrm", srv.URL)
+	route, err := provider.Route(context.Background(), taksim, kadikoy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if route.DistanceKm != 9.2 || route.Duration != 21*time.Minute {
+		t.Errorf("unexpected route %+v", route)
+	}
+
+	// A route OSRM can't find is estimated in a straight line instead
+	route, err = provider.Route(context.Background(), kadikoy, taksim)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if route.DistanceKm == 9.2 {
+		t.Error("expected the straight-line estimate")
+	}
+}
@@ -0,0 +1,54 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync/atomic"
+)
+
+// Mock logs messages instead of sending them. It is the provider used in
+// development; setting Err makes every send fail, to exercise failover.
+type Mock struct {
+	Err  error
+	sent atomic.Int64
+}
+
+// NewMock creates a mock provider
+func NewMock() *Mock {
+	return &Mock{}
+}
+
+// Name identifies the provider
+func (m *Mock) Name() string { return "mock" }
+
+// Send logs the message and returns a sequential message id
+func (m *Mock) Send(_ context.Context, to, body string) (string, error) {
+	if m.Err != nil {
+		return "", m.Err
+	}
+	log.Printf("[SMS] mock message to %s: %s", to, body)
+	return fmt.Sprintf("mock-%d", m.sent.Add(1)), nil
+}
+
+// ParseStatus reads a JSON report of the form {"message_id", "status", "error_code", "error"},
+// so delivery tracking can be exercised by hand in development
+func (m *Mock) ParseStatus(r *http.Request) (*Status, error) {
+	var report struct {
+		MessageID string `json:"message_id"`
+		Status    string `json:"status"`
+		ErrorCode string `json:"error_code"`
+		Error     string `json:"error"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil || report.MessageID == "" {
+		return nil, ErrInvalidCallback
+	}
+	switch report.Status {
+	case StatusSent, StatusDelivered, StatusUndelivered, StatusFailed:
+	default:
+		return nil, ErrInvalidCallback
+	}
+	return &Status{MessageID: report.MessageID, Status: report.Status, ErrorCode: report.ErrorCode, Error: report.Error}, nil
+}
@@ -0,0 +1,96 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const netgsmSendURL = "https://api.netgsm.com.tr/sms/send/get"
+
+// netgsmErrors maps Netgsm's numeric error responses to readable messages
+var netgsmErrors = map[string]string{
+	"20": "message text is invalid or too long",
+	"30": "invalid credentials or API access not allowed from this IP",
+	"40": "sender header is not registered",
+	"50": "recipient is not permitted for IYS-controlled sending",
+	"51": "IYS brand code is missing",
+	"70": "invalid request parameters",
+	"80": "sending limit exceeded",
+	"85": "duplicate message limit exceeded",
+}
+
+// Netgsm sends messages through the Netgsm HTTP API. Netgsm has no delivery
+// status callback; reports are only available by polling, which is not wired up.
+type Netgsm struct {
+	userCode   string
+	password   string
+	header     string
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewNetgsm creates a Netgsm provider; header is the registered sender name
+func NewNetgsm(userCode, password, header string) *Netgsm {
+	return &Netgsm{
+		userCode:   userCode,
+		password:   password,
+		header:     header,
+		endpoint:   netgsmSendURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name identifies the provider
+func (n *Netgsm) Name() string { return "netgsm" }
+
+// Send delivers a text message and returns Netgsm's bulk id
+func (n *Netgsm) Send(ctx context.Context, to, body string) (string, error) {
+	form := url.Values{
+		"usercode":  {n.userCode},
+		"password":  {n.password},
+		"gsmno":     {strings.TrimPrefix(to, "+")},
+		"message":   {body},
+		"msgheader": {n.header},
+		"dil":       {"TR"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("netgsm: unexpected status %d", resp.StatusCode)
+	}
+
+	// Success is "00 <bulkid>" (or "01"/"02" when the send date was adjusted)
+	fields := strings.Fields(string(raw))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("netgsm: empty response")
+	}
+	switch fields[0] {
+	case "00", "01", "02":
+		if len(fields) < 2 {
+			return "", fmt.Errorf("netgsm: response without bulk id")
+		}
+		return fields[1], nil
+	}
+	if msg, ok := netgsmErrors[fields[0]]; ok {
+		return "", fmt.Errorf("netgsm: %s (code %s)", msg, fields[0])
+	}
+	return "", fmt.Errorf("netgsm: unexpected response %q", fields[0])
+}
@@ -0,0 +1,47 @@
+// Package sms sends text messages through interchangeable providers. Twilio
+// and Netgsm are bundled, plus a mock that only logs for development.
+package sms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Delivery statuses, normalized across providers
+const (
+	StatusSent        = "sent"
+	StatusDelivered   = "delivered"
+	StatusUndelivered = "undelivered"
+	StatusFailed      = "failed"
+)
+
+// ErrInvalidCallback is returned for status callbacks that fail authentication or cannot be parsed
+var ErrInvalidCallback = errors.New("invalid status callback")
+
+// Provider sends a text message and returns the provider's message id
+type Provider interface {
+	Name() string
+	Send(ctx context.Context, to, body string) (string, error)
+}
+
+// Verifier is implemented by providers that generate and check one-time codes
+// themselves, such as Twilio Verify
+type Verifier interface {
+	StartVerification(ctx context.Context, to string) (string, error)
+	CheckVerification(ctx context.Context, to, code string) (bool, error)
+}
+
+// Status is a delivery report for a previously sent message
+type Status struct {
+	MessageID string
+	Status    string
+	ErrorCode string
+	Error     string
+}
+
+// StatusParser is implemented by providers that report delivery status through a
+// callback. A nil Status means the report carries nothing worth recording.
+type StatusParser interface {
+	ParseStatus(r *http.Request) (*Status, error)
+}
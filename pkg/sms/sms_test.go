@@ -0,0 +1,91 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestNetgsmSend(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantID   string
+		wantErr  string
+	}{
+		{name: "sent", response: "00 1234567890", wantID: "1234567890"},
+		{name: "bad header", response: "40", wantErr: "sender header"},
+		{name: "unknown code", response: "99", wantErr: "unexpected response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				if r.PostForm.Get("gsmno") != "905321234567" || r.PostForm.Get("msgheader") != "YANDAS" {
+					t.Errorf("unexpected form %v", r.PostForm)
+				}
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			netgsm := NewNetgsm("user", "pass", "YANDAS")
+			netgsm.endpoint = server.URL
+
+			id, err := netgsm.Send(context.Background(), "+905321234567", "Kodunuz: 123456")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || id != tt.wantID {
+				t.Fatalf("expected id %q, got %q (%v)", tt.wantID, id, err)
+			}
+		})
+	}
+}
+
+func twilioSignature(token, callbackURL string, form url.Values) string {
+	mac := hmac.New(sha1.New, []byte(token))
+	mac.Write([]byte(callbackURL))
+	// url.Values.Encode sorts by key, which is the order Twilio signs in
+	for _, pair := range strings.Split(form.Encode(), "&") {
+		key, value, _ := strings.Cut(pair, "=")
+		key, _ = url.QueryUnescape(key)
+		value, _ = url.QueryUnescape(value)
+		mac.Write([]byte(key + value))
+	}
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestTwilioParseStatus(t *testing.T) {
+	const callbackURL = "https://api.yandas.app/api/v1/sms/status/twilio"
+	twilio := NewTwilio(TwilioConfig{AuthToken: "token", CallbackURL: callbackURL})
+	form := url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"undelivered"}, "ErrorCode": {"30003"}}
+
+	request := func(signature string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, callbackURL, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("X-Twilio-Signature", signature)
+		return r
+	}
+
+	status, err := twilio.ParseStatus(request(twilioSignature("token", callbackURL, form)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.MessageID != "SM123" || status.Status != StatusUndelivered || status.ErrorCode != "30003" {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	if _, err := twilio.ParseStatus(request(twilioSignature("other", callbackURL, form))); err != ErrInvalidCallback {
+		t.Errorf("expected ErrInvalidCallback for a forged signature, got %v", err)
+	}
+}
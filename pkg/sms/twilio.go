@@ -0,0 +1,134 @@
+package sms
+
+import (
+	"context"
+	"net/http"
+
+	twilio "github.com/twilio/twilio-go"
+	twilioclient "github.com/twilio/twilio-go/client"
+	twilioapi "github.com/twilio/twilio-go/rest/api/v2010"
+	verify "github.com/twilio/twilio-go/rest/verify/v2"
+)
+
+// TwilioConfig configures the Twilio provider. VerifySID is optional; without
+// it codes are generated locally and sent as plain messages.
+type TwilioConfig struct {
+	AccountSID  string
+	AuthToken   string
+	From        string
+	VerifySID   string
+	CallbackURL string // where Twilio posts delivery status; empty disables callbacks
+}
+
+// Twilio sends messages through Twilio Messaging and codes through Twilio Verify
+type Twilio struct {
+	cfg    TwilioConfig
+	client *twilio.RestClient
+}
+
+// NewTwilio creates a Twilio provider
+func NewTwilio(cfg TwilioConfig) *Twilio {
+	return &Twilio{
+		cfg: cfg,
+		client: twilio.NewRestClientWithParams(twilio.ClientParams{
+			Username: cfg.AccountSID,
+			Password: cfg.AuthToken,
+		}),
+	}
+}
+
+// Name identifies the provider
+func (t *Twilio) Name() string { return "twilio" }
+
+// Send delivers a text message
+func (t *Twilio) Send(_ context.Context, to, body string) (string, error) {
+	params := &twilioapi.CreateMessageParams{}
+	params.SetTo(to)
+	params.SetFrom(t.cfg.From)
+	params.SetBody(body)
+	if t.cfg.CallbackURL != "" {
+		params.SetStatusCallback(t.cfg.CallbackURL)
+	}
+
+	resp, err := t.client.Api.CreateMessage(params)
+	if err != nil {
+		return "", err
+	}
+	if resp.Sid == nil {
+		return "", nil
+	}
+	return *resp.Sid, nil
+}
+
+// CanVerify reports whether a Verify service is configured
+func (t *Twilio) CanVerify() bool { return t.cfg.VerifySID != "" }
+
+// StartVerification sends a code generated by Twilio Verify
+func (t *Twilio) StartVerification(_ context.Context, to string) (string, error) {
+	params := &verify.CreateVerificationParams{}
+	params.SetTo(to)
+	params.SetChannel("sms")
+
+	resp, err := t.client.VerifyV2.CreateVerification(t.cfg.VerifySID, params)
+	if err != nil {
+		return "", err
+	}
+	if resp.Sid == nil {
+		return "", nil
+	}
+	return *resp.Sid, nil
+}
+
+// CheckVerification checks a code against Twilio Verify
+func (t *Twilio) CheckVerification(_ context.Context, to, code string) (bool, error) {
+	params := &verify.CreateVerificationCheckParams{}
+	params.SetTo(to)
+	params.SetCode(code)
+
+	resp, err := t.client.VerifyV2.CreateVerificationCheck(t.cfg.VerifySID, params)
+	if err != nil {
+		return false, err
+	}
+	return resp.Status != nil && *resp.Status == "approved", nil
+}
+
+// ParseStatus reads a Twilio status callback after checking its signature
+func (t *Twilio) ParseStatus(r *http.Request) (*Status, error) {
+	if t.cfg.CallbackURL == "" {
+		return nil, ErrInvalidCallback
+	}
+	if err := r.ParseForm(); err != nil {
+		return nil, ErrInvalidCallback
+	}
+	params := make(map[string]string, len(r.PostForm))
+	for key, values := range r.PostForm {
+		params[key] = values[0]
+	}
+	validator := twilioclient.NewRequestValidator(t.cfg.AuthToken)
+	if !validator.Validate(t.cfg.CallbackURL, params, r.Header.Get("X-Twilio-Signature")) {
+		return nil, ErrInvalidCallback
+	}
+
+	status := &Status{
+		MessageID: params["MessageSid"],
+		ErrorCode: params["ErrorCode"],
+		Error:     params["ErrorMessage"],
+	}
+	switch params["MessageStatus"] {
+	case "delivered", "read":
+		status.Status = StatusDelivered
+	case "undelivered":
+		status.Status = StatusUndelivered
+	case "failed":
+		status.Status = StatusFailed
+	case "sent":
+		status.Status = StatusSent
+	default:
+		// queued, sending and accepted say nothing new
+		return nil, nil
+	}
+	if status.MessageID == "" {
+		return nil, ErrInvalidCallback
+	}
+	return status, nil
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Local keeps objects as files under a directory
+type Local struct {
+	dir string
+}
+
+// NewLocal creates a store in dir, creating the directory when missing
+func NewLocal(dir string) (*Local, error) {
+	if dir == "" {
+		return nil, errors.New("local storage needs a directory")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &Local{dir: dir}, nil
+}
+
+// Name identifies the store
+func (l *Local) Name() string { return StoreLocal }
+
+// Path returns the file an object is kept in
+func (l *Local) Path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid key %q", key)
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(clean)), nil
+}
+
+// URL returns the object's file path, or the key when it is invalid
+func (l *Local) URL(key string) string {
+	if path, err := l.Path(key); err == nil {
+		return path
+	}
+	return key
+}
+
+// Put writes r to a temporary file and renames it into place, so a reader
+// never sees a partly written object
+func (l *Local) Put(_ context.Context, key string, r io.ReadSeeker) error {
+	path, err := l.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens the object's file
+func (l *Local) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.Path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// List walks the directory for files under prefix, skipping unfinished uploads
+func (l *Local) List(_ context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	err := filepath.WalkDir(l.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(l.dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{Key: key, Size: info.Size(), ModifiedAt: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].ModifiedAt.Before(objects[j].ModifiedAt) })
+	return objects, nil
+}
+
+// Delete removes the object's file; a missing object is not an error
+func (l *Local) Delete(_ context.Context, key string) error {
+	path, err := l.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
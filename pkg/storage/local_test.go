@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocalRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Put(ctx, "backups/a.dump", strings.NewReader("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(ctx, "other/b.txt", strings.NewReader("second")); err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := store.List(ctx, "backups/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 1 || objects[0].Key != "backups/a.dump" || objects[0].Size != 5 {
+		t.Fatalf("unexpected objects %+v", objects)
+	}
+
+	r, err := store.Get(ctx, "backups/a.dump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "first" {
+		t.Errorf("expected first, got %q", data)
+	}
+
+	if err := store.Delete(ctx, "backups/a.dump"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, "backups/a.dump"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestLocalKeysStayInsideTheDirectory(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := store.Path("../../etc/passwd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(path, dir) {
+		t.Errorf("expected %s to be inside %s", path, dir)
+	}
+	if _, err := store.Path("/"); err == nil {
+		t.Error("expected an empty key to be rejected")
+	}
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3 keeps objects in an S3 bucket, under an optional key prefix
+type S3 struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3 creates a store for a bucket
+func NewS3(ctx context.Context, opts Options) (*S3, error) {
+	if opts.Bucket == "" {
+		return nil, errors.New("s3 storage needs a bucket")
+	}
+	loadOpts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(opts.Region)}
+	if opts.AccessKey != "" {
+		loadOpts = append(loadOpts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(opts.AccessKey, opts.SecretKey, "")))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.Trim(opts.Prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &S3{client: s3.NewFromConfig(cfg), bucket: opts.Bucket, prefix: prefix}, nil
+}
+
+// Name identifies the store
+func (s *S3) Name() string { return StoreS3 }
+
+// Put uploads r in a single request, which S3 allows up to 5 GB
+func (s *S3) Put(ctx context.Context, key string, r io.ReadSeeker) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+		Body:   r,
+	})
+	return err
+}
+
+// Get downloads the object
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	})
+	var missing *types.NoSuchKey
+	if errors.As(err, &missing) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+// List pages through the objects under prefix
+func (s *S3) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.prefix + prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			objects = append(objects, Object{
+				Key:        strings.TrimPrefix(aws.ToString(obj.Key), s.prefix),
+				Size:       aws.ToInt64(obj.Size),
+				ModifiedAt: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].ModifiedAt.Before(objects[j].ModifiedAt) })
+	return objects, nil
+}
+
+// URL returns the object's s3:// URL
+func (s *S3) URL(key string) string {
+	return "s3://" + s.bucket + "/" + s.prefix + key
+}
+
+// Delete removes the object
+func (s *S3) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	})
+	return err
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Store names
+const (
+	StoreLocal = "local"
+	StoreS3    = "s3"
+)
+
+// ErrNotFound is returned when no object exists under a key
+var ErrNotFound = errors.New("object not found")
+
+// Object describes a stored object
+type Object struct {
+	Key        string
+	Size       int64
+	ModifiedAt time.Time
+}
+
+// Store keeps files under slash-separated keys
+type Store interface {
+	Name() string
+	// Put stores r under key, replacing what was there
+	Put(ctx context.Context, key string, r io.ReadSeeker) error
+	// Get opens the object under key; the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// List returns the objects whose key starts with prefix, oldest first
+	List(ctx context.Context, prefix string) ([]Object, error)
+	Delete(ctx context.Context, key string) error
+	// URL identifies where an object is kept, for logs and records
+	URL(key string) string
+}
+
+// Options configure the store NewStore creates. Without an access key the S3
+// store uses the standard AWS credential chain.
+type Options struct {
+	Path      string // local directory
+	Bucket    string
+	Region    string
+	Prefix    string // prepended to every S3 key
+	AccessKey string
+	SecretKey string
+}
+
+// NewStore returns the store configured by name
+func NewStore(ctx context.Context, name string, opts Options) (Store, error) {
+	switch name {
+	case StoreLocal, "":
+		return NewLocal(opts.Path)
+	case StoreS3:
+		return NewS3(ctx, opts)
+	default:
+		return nil, fmt.Errorf("unknown storage %q", name)
+	}
+}
@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// Errors for receiver URLs that must not be called. Without these checks an
+// endpoint could point deliveries at services on the internal network.
+var (
+	ErrInvalidURL     = errors.New("webhook URL must be an absolute http or https URL")
+	ErrPrivateAddress = errors.New("webhook URL resolves to a loopback, private or link-local address")
+)
+
+// resolveTimeout bounds the DNS lookup when a receiver URL is saved
+const resolveTimeout = 5 * time.Second
+
+// ValidateURL checks that a receiver URL is http(s) and that its host only
+// resolves to public addresses. The sender checks the address again on every
+// connection, as DNS can change after the URL is saved.
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidURL
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("resolving webhook host: %w", err)
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return ErrPrivateAddress
+		}
+	}
+	return nil
+}
+
+// publicIP reports whether ip may receive deliveries
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast()
+}
+
+// refusePrivate is a dialer Control hook rejecting connections to addresses
+// that aren't public, including ones reached through redirects
+func refusePrivate(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-Yandas-Event"
+	HeaderDelivery  = "X-Yandas-Delivery"
+	HeaderTimestamp = "X-Yandas-Timestamp"
+	HeaderSignature = "X-Yandas-Signature"
+)
+
+// maxResponseBody caps how much of a receiver's response is kept for the delivery log
+const maxResponseBody = 1024
+
+// GenerateSecret returns a new random signing secret
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// Sign returns the signature header value for a payload: sha256=HMAC(secret, "<timestamp>.<body>")
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature produced by Sign; receivers can use it as a reference implementation
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Result describes the outcome of one delivery attempt
+type Result struct {
+	StatusCode int
+	Body       string
+}
+
+// Sender posts signed payloads to receiver URLs
+type Sender struct {
+	httpClient *http.Client
+}
+
+// NewSender creates a sender with the given per-request timeout. It refuses to
+// connect to loopback, private and link-local addresses.
+func NewSender(timeout time.Duration) *Sender {
+	return newSender(timeout, refusePrivate)
+}
+
+func newSender(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) *Sender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would connect on our behalf, out of reach of the address check
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: timeout, Control: control}).DialContext
+	return &Sender{httpClient: &http.Client{Timeout: timeout, Transport: transport}}
+}
+
+// Send posts body to url. A non-2xx response is returned as an error together with the result.
+func (s *Sender) Send(url, secret, event, deliveryID string, body []byte) (*Result, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Yandas-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, deliveryID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	result := &Result{StatusCode: resp.StatusCode, Body: string(respBody)}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return result, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+
+	return result, nil
+}
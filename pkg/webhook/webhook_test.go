@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"event":"order.created"}`)
+	sig := Sign("whsec_test", 1700000000, body)
+
+	if !Verify("whsec_test", 1700000000, body, sig) {
+		t.Fatal("expected signature to verify")
+	}
+	if Verify("whsec_other", 1700000000, body, sig) {
+		t.Error("signature verified with wrong secret")
+	}
+	if Verify("whsec_test", 1700000001, body, sig) {
+		t.Error("signature verified with wrong timestamp")
+	}
+}
+
+func TestSendSignsRequest(t *testing.T) {
+	var verified bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		verified = Verify("whsec_test", ts, body, r.Header.Get(HeaderSignature)) &&
+			r.Header.Get(HeaderEvent) == "order.created"
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	result, err := newSender(time.Second, nil).Send(server.URL, "whsec_test", "order.created", "d1", []byte(`{}`))
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.StatusCode != http.StatusNoContent || !verified {
+		t.Errorf("status %d, verified %v", result.StatusCode, verified)
+	}
+}
+
+func TestSendNon2xxIsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	result, err := newSender(time.Second, nil).Send(server.URL, "s", "order.created", "d1", []byte(`{}`))
+	if err == nil || result == nil || result.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected error with result, got %v %+v", err, result)
+	}
+}
+
+func TestSendRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected no request to reach a loopback receiver")
+	}))
+	defer server.Close()
+
+	if _, err := NewSender(time.Second).Send(server.URL, "s", "order.created", "d1", []byte(`{}`)); !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("expected ErrPrivateAddress, got %v", err)
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	for _, rawURL := range []string{"ftp://example.com/hook", "/hooks", "https://"} {
+		if err := ValidateURL(rawURL); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("%s: expected ErrInvalidURL, got %v", rawURL, err)
+		}
+	}
+	for _, rawURL := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"https://10.0.0.5/hook",
+		"https://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://[fe80::1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		if err := ValidateURL(rawURL); !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("%s: expected ErrPrivateAddress, got %v", rawURL, err)
+		}
+	}
+	if err := ValidateURL("https://93.184.215.14/hook"); err != nil {
+		t.Errorf("expected a public address to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build tools
+
+// Package tools pins code generators used via go:generate.
+package tools
+
+import (
+	_ "github.com/golang/mock/mockgen"
+)